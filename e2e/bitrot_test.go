@@ -68,7 +68,7 @@ func testBitrotOnReplicaVolume(t *testing.T, tc *testCluster) {
 
 	testbitrot(t)
 
-	r.Nil(client.VolumeDelete(volumeName))
+	r.Nil(client.VolumeDelete(volumeName, api.VolumeDeleteReq{Purge: true}))
 }
 
 func testBitrotOnDistVolume(t *testing.T, tc *testCluster) {
@@ -109,7 +109,7 @@ func testBitrotOnDistVolume(t *testing.T, tc *testCluster) {
 	r.Nil(err)
 	testbitrot(t)
 
-	r.Nil(client.VolumeDelete(volumeName))
+	r.Nil(client.VolumeDelete(volumeName, api.VolumeDeleteReq{Purge: true}))
 
 }
 
@@ -202,8 +202,8 @@ func TestBrickMux(t *testing.T) {
 	r.Nil(client.VolumeStop(volname2))
 	r.Nil(client.VolumeStop(volname1))
 
-	r.Nil(client.VolumeDelete(volname2))
-	r.Nil(client.VolumeDelete(volname1))
+	r.Nil(client.VolumeDelete(volname2, api.VolumeDeleteReq{Purge: true}))
+	r.Nil(client.VolumeDelete(volname1, api.VolumeDeleteReq{Purge: true}))
 
 	for i := 6; i <= 36; i++ {
 		brickPath := testTempDir(t, "brick")
@@ -307,7 +307,7 @@ func TestBrickMux(t *testing.T) {
 
 	for i := 1; i <= 10; i++ {
 		r.Nil(client.VolumeStop(volname1 + strconv.Itoa(i)))
-		r.Nil(client.VolumeDelete(volname1 + strconv.Itoa(i)))
+		r.Nil(client.VolumeDelete(volname1+strconv.Itoa(i), api.VolumeDeleteReq{Purge: true}))
 	}
 
 	// Turn on brick mux max-bricks-per-process cluster option
@@ -606,7 +606,7 @@ func TestBrickMux(t *testing.T) {
 
 	for i := 1; i <= 10; i++ {
 		r.Nil(client.VolumeStop(volname1 + strconv.Itoa(i)))
-		r.Nil(client.VolumeDelete(volname1 + strconv.Itoa(i)))
+		r.Nil(client.VolumeDelete(volname1+strconv.Itoa(i), api.VolumeDeleteReq{Purge: true}))
 	}
 
 	// Create two volumes with different options, so that bricks from these
@@ -702,10 +702,10 @@ func TestBrickMux(t *testing.T) {
 	r.Equal(len(portMap), 2)
 
 	r.Nil(client.VolumeStop(volname1))
-	r.Nil(client.VolumeDelete(volname1))
+	r.Nil(client.VolumeDelete(volname1, api.VolumeDeleteReq{Purge: true}))
 
 	r.Nil(client.VolumeStop(volname2))
-	r.Nil(client.VolumeDelete(volname2))
+	r.Nil(client.VolumeDelete(volname2, api.VolumeDeleteReq{Purge: true}))
 
 	r.Nil(gd.Stop())
 }
@@ -141,10 +141,10 @@ func TestGeorepCreateDelete(t *testing.T) {
 	r.Nil(err)
 
 	// delete volume
-	err = client.VolumeDelete(volname)
+	err = client.VolumeDelete(volname, api.VolumeDeleteReq{Purge: true})
 	r.Nil(err)
 
 	// delete volume
-	err = client.VolumeDelete(volname2)
+	err = client.VolumeDelete(volname2, api.VolumeDeleteReq{Purge: true})
 	r.Nil(err)
 }
@@ -127,7 +127,7 @@ func testSelfHeal(t *testing.T, tc *testCluster) {
 		}
 	}
 
-	r.Nil(client.VolumeStop(vol1.Name), "Volume stop failed")
+	r.Nil(client.VolumeStopForce(vol1.Name), "Volume stop failed")
 
 	optionReq.Options = map[string]string{"cluster/replicate.entry-self-heal": "on",
 		"cluster/replicate.metadata-self-heal": "on",
@@ -167,7 +167,7 @@ func testSelfHeal(t *testing.T, tc *testCluster) {
 	r.Nil(client.SelfHeal(vol1.Name, "full"))
 
 	// Stop Volume
-	r.Nil(client.VolumeStop(vol1.Name), "Volume stop failed")
+	r.Nil(client.VolumeStopForce(vol1.Name), "Volume stop failed")
 
 	optionReq.Options = map[string]string{"cluster/replicate.self-heal-daemon": "off"}
 	optionReq.AllowAdvanced = true
@@ -176,7 +176,7 @@ func testSelfHeal(t *testing.T, tc *testCluster) {
 	r.False(isProcessRunning(pidpath), "glustershd is still running")
 
 	// delete volume
-	r.Nil(client.VolumeDelete(vol1.Name))
+	r.Nil(client.VolumeDelete(vol1.Name, api.VolumeDeleteReq{Purge: true}))
 }
 
 func testGranularEntryHeal(t *testing.T, tc *testCluster) {
@@ -272,7 +272,7 @@ func testGranularEntryHeal(t *testing.T, tc *testCluster) {
 	r.Nil(client.VolumeSet(volname, optionReq))
 
 	// Stop Volume
-	r.Nil(client.VolumeStop(volname), "Volume stop failed")
+	r.Nil(client.VolumeStopForce(volname), "Volume stop failed")
 	r.Nil(client.VolumeStart(volname, false), "volume start failed")
 
 	optionReq.Options = map[string]string{"cluster/replicate.granular-entry-heal": "enable"}
@@ -285,7 +285,7 @@ func testGranularEntryHeal(t *testing.T, tc *testCluster) {
 	// Stop Volume
 	r.Nil(client.VolumeStop(volname), "Volume stop failed")
 	// delete volume
-	r.Nil(client.VolumeDelete(volname))
+	r.Nil(client.VolumeDelete(volname, api.VolumeDeleteReq{Purge: true}))
 }
 
 func testSplitBrainOperation(t *testing.T, tc *testCluster) {
@@ -401,7 +401,7 @@ func testSplitBrainOperation(t *testing.T, tc *testCluster) {
 	defer f2.Close()
 
 	// Stop Volume
-	r.Nil(client.VolumeStop(volname), "Volume stop failed")
+	r.Nil(client.VolumeStopForce(volname), "Volume stop failed")
 	// Start Volume
 	r.Nil(client.VolumeStart(volname, false), "Volume start failed")
 
@@ -435,5 +435,5 @@ func testSplitBrainOperation(t *testing.T, tc *testCluster) {
 	// Stop Volume
 	r.Nil(client.VolumeStop(volname), "Volume stop failed")
 	// delete volume
-	r.Nil(client.VolumeDelete(volname))
+	r.Nil(client.VolumeDelete(volname, api.VolumeDeleteReq{Purge: true}))
 }
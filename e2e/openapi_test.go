@@ -0,0 +1,105 @@
+package e2e
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/openapi"
+
+	"github.com/stretchr/testify/require"
+)
+
+// pythonClientSmoke is run with the Python client generated from the
+// OpenAPI document of the cluster
+const pythonClientSmoke = `
+import sys
+from glusterd2client import Client, APIError
+
+client = Client(sys.argv[1], secret=sys.argv[2])
+client.glusterd2_service_status()
+assert len(client.get_peers()) == 1
+try:
+    client.volume_info("no-such-volume")
+    sys.exit("volume_info did not fail")
+except APIError as e:
+    assert e.status == 404, e.status
+    assert e.has_reason("VOLUME_NOT_FOUND"), e.reasons
+`
+
+func getJSON(url string, v interface{}) error {
+	resp, err := http.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+func TestOpenAPI(t *testing.T) {
+	r := require.New(t)
+
+	tc, err := setupCluster(t, "./config/1.toml")
+	r.NoError(err)
+	defer teardownCluster(tc)
+
+	g1 := tc.gds[0]
+	baseURL := "http://" + g1.ClientAddress
+
+	var endpoints api.ListEndpointsResp
+	r.NoError(getJSON(baseURL+"/endpoints", &endpoints))
+	var doc openapi.Document
+	r.NoError(getJSON(baseURL+"/openapi.json", &doc))
+
+	// Every endpoint has its operation, with the types of its request and
+	// response
+	ops := make(map[string]openapi.Operation)
+	for _, pathOps := range doc.Paths {
+		for _, op := range pathOps {
+			ops[op.OperationID] = op
+		}
+	}
+	for _, e := range endpoints {
+		op, ok := ops[openapi.OperationID(e.Name)]
+		r.True(ok, "no operation for endpoint %s", e.Name)
+		if e.RequestType != "" {
+			r.NotNil(op.RequestBody, "no request of endpoint %s", e.Name)
+		}
+		if e.ResponseType != "" {
+			r.NotEmpty(op.Responses["2XX"].Content, "no response of endpoint %s", e.Name)
+		}
+	}
+
+	// The Python client generated from the document works against the
+	// cluster
+	python, err := exec.LookPath("python3")
+	if err != nil {
+		t.Log("python3 not found, skipping the python client test")
+		return
+	}
+
+	dir := testTempDir(t, "openapi")
+	defer os.RemoveAll(dir)
+
+	data, err := json.Marshal(doc)
+	r.NoError(err)
+	spec := filepath.Join(dir, "openapi.json")
+	r.NoError(ioutil.WriteFile(spec, data, 0644))
+
+	clientDir, err := filepath.Abs("../extras/python-client")
+	r.NoError(err)
+	out, err := exec.Command(python, filepath.Join(clientDir, "generate.py"), "--spec", spec).CombinedOutput()
+	r.NoError(err, string(out))
+
+	secret, err := getAuthSecret(g1.LocalStateDir)
+	r.NoError(err)
+	cmd := exec.Command(python, "-c", pythonClientSmoke, baseURL, secret)
+	cmd.Env = append(os.Environ(), "PYTHONPATH="+clientDir)
+	out, err = cmd.CombinedOutput()
+	r.NoError(err, string(out))
+}
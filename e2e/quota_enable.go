@@ -67,7 +67,7 @@ func TestQuota(t *testing.T) {
 	// test Quota on dist-rep volume
 	t.Run("Quota-enable", tc.wrap(testQuotaEnable))
 
-	r.Nil(client.VolumeDelete(volumeName))
+	r.Nil(client.VolumeDelete(volumeName, api.VolumeDeleteReq{Purge: true}))
 }
 
 func testQuotaEnable(t *testing.T, tc *testCluster) {
@@ -125,7 +125,7 @@ func testQuotaEnable(t *testing.T, tc *testCluster) {
 	// Stop Volume
 	r.Nil(client.VolumeStop(volname), "Volume stop failed")
 	// delete volume
-	err = client.VolumeDelete(volname)
+	err = client.VolumeDelete(volname, api.VolumeDeleteReq{Purge: true})
 	r.Nil(err)
 
 }
@@ -51,7 +51,7 @@ func testReplaceBrickLoop(t *testing.T) {
 
 	err = client.VolumeStop(smartvolname)
 	r.Nil(err)
-	r.Nil(client.VolumeDelete(smartvolname))
+	r.Nil(client.VolumeDelete(smartvolname, api.VolumeDeleteReq{Purge: true}))
 
 	g4, err := spawnGlusterd(t, "./config/4.toml", true)
 	r.Nil(err)
@@ -91,7 +91,7 @@ func testReplaceBrickLoop(t *testing.T) {
 
 	err = client.VolumeStop(smartvolname)
 	r.Nil(err)
-	err = (client.VolumeDelete(smartvolname))
+	err = (client.VolumeDelete(smartvolname, api.VolumeDeleteReq{Purge: true}))
 	r.Nil(err)
 
 }
@@ -130,7 +130,7 @@ func testSmartVolumeDistributeLoop(t *testing.T) {
 	r.Nil(brickSizeTest(volinfo.Subvols[1].Bricks[0].Path, 16, 21))
 	r.Nil(brickSizeTest(volinfo.Subvols[2].Bricks[0].Path, 16, 21))
 
-	r.Nil(client.VolumeDelete(smartvolname))
+	r.Nil(client.VolumeDelete(smartvolname, api.VolumeDeleteReq{Purge: true}))
 }
 
 func testSmartVolumeReplicate2Loop(t *testing.T) {
@@ -153,7 +153,7 @@ func testSmartVolumeReplicate2Loop(t *testing.T) {
 	r.Nil(brickSizeTest(volinfo.Subvols[0].Bricks[0].Path, 16, 21))
 	r.Nil(brickSizeTest(volinfo.Subvols[0].Bricks[1].Path, 16, 21))
 
-	r.Nil(client.VolumeDelete(smartvolname))
+	r.Nil(client.VolumeDelete(smartvolname, api.VolumeDeleteReq{Purge: true}))
 }
 
 func testSmartVolumeReplicate3Loop(t *testing.T) {
@@ -177,7 +177,7 @@ func testSmartVolumeReplicate3Loop(t *testing.T) {
 	r.Nil(brickSizeTest(volinfo.Subvols[0].Bricks[1].Path, 16, 21))
 	r.Nil(brickSizeTest(volinfo.Subvols[0].Bricks[2].Path, 16, 21))
 
-	r.Nil(client.VolumeDelete(smartvolname))
+	r.Nil(client.VolumeDelete(smartvolname, api.VolumeDeleteReq{Purge: true}))
 }
 
 func testSmartVolumeArbiterLoop(t *testing.T) {
@@ -206,7 +206,7 @@ func testSmartVolumeArbiterLoop(t *testing.T) {
 	// TODO: Change this after arbiter calculation fix
 	r.Nil(brickSizeTest(volinfo.Subvols[0].Bricks[2].Path, 16, 21))
 
-	r.Nil(client.VolumeDelete(smartvolname))
+	r.Nil(client.VolumeDelete(smartvolname, api.VolumeDeleteReq{Purge: true}))
 }
 
 func testSmartVolumeDisperseLoop(t *testing.T) {
@@ -243,7 +243,7 @@ func testSmartVolumeDisperseLoop(t *testing.T) {
 	r.Nil(brickSizeTest(volinfo.Subvols[0].Bricks[1].Path, 16, 21))
 	r.Nil(brickSizeTest(volinfo.Subvols[0].Bricks[2].Path, 16, 21))
 
-	r.Nil(client.VolumeDelete(smartvolname))
+	r.Nil(client.VolumeDelete(smartvolname, api.VolumeDeleteReq{Purge: true}))
 }
 
 func testSmartVolumeDistributeReplicateLoop(t *testing.T) {
@@ -275,7 +275,7 @@ func testSmartVolumeDistributeReplicateLoop(t *testing.T) {
 	r.Nil(brickSizeTest(volinfo.Subvols[1].Bricks[1].Path, 16, 21))
 	r.Nil(brickSizeTest(volinfo.Subvols[1].Bricks[2].Path, 16, 21))
 
-	r.Nil(client.VolumeDelete(smartvolname))
+	r.Nil(client.VolumeDelete(smartvolname, api.VolumeDeleteReq{Purge: true}))
 }
 
 func testSmartVolumeDistributeDisperseLoop(t *testing.T) {
@@ -307,7 +307,7 @@ func testSmartVolumeDistributeDisperseLoop(t *testing.T) {
 	r.Nil(brickSizeTest(volinfo.Subvols[1].Bricks[1].Path, 16, 21))
 	r.Nil(brickSizeTest(volinfo.Subvols[1].Bricks[2].Path, 16, 21))
 
-	r.Nil(client.VolumeDelete(smartvolname))
+	r.Nil(client.VolumeDelete(smartvolname, api.VolumeDeleteReq{Purge: true}))
 }
 
 func testSmartVolumeAutoDistributeReplicateLoop(t *testing.T) {
@@ -343,7 +343,7 @@ func testSmartVolumeAutoDistributeReplicateLoop(t *testing.T) {
 	r.Len(volinfo.Subvols[0].Bricks, 3)
 	r.Len(volinfo.Subvols[1].Bricks, 3)
 
-	r.Nil(client.VolumeDelete(smartvolname))
+	r.Nil(client.VolumeDelete(smartvolname, api.VolumeDeleteReq{Purge: true}))
 
 	// Max-brick-size is more than request size
 	createReq = api.VolCreateReq{
@@ -361,7 +361,7 @@ func testSmartVolumeAutoDistributeReplicateLoop(t *testing.T) {
 	r.Equal("Replicate", volinfo.Type.String())
 	r.Len(volinfo.Subvols[0].Bricks, 3)
 
-	r.Nil(client.VolumeDelete(smartvolname))
+	r.Nil(client.VolumeDelete(smartvolname, api.VolumeDeleteReq{Purge: true}))
 }
 
 func testSmartVolumeAutoDistributeDisperseLoop(t *testing.T) {
@@ -385,7 +385,7 @@ func testSmartVolumeAutoDistributeDisperseLoop(t *testing.T) {
 	r.Len(volinfo.Subvols[0].Bricks, 3)
 	r.Len(volinfo.Subvols[1].Bricks, 3)
 
-	r.Nil(client.VolumeDelete(smartvolname))
+	r.Nil(client.VolumeDelete(smartvolname, api.VolumeDeleteReq{Purge: true}))
 }
 
 func editDeviceLoop(t *testing.T) {
@@ -458,7 +458,7 @@ func editDeviceLoop(t *testing.T) {
 	_, err = client.VolumeCreate(createReq)
 	r.Nil(err)
 
-	r.Nil(client.VolumeDelete(smartvolname))
+	r.Nil(client.VolumeDelete(smartvolname, api.VolumeDeleteReq{Purge: true}))
 }
 
 // TestSmartVolumeLoop creates a volume and starts it, runs further tests on it and
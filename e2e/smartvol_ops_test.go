@@ -93,7 +93,7 @@ func testReplaceBrick(t *testing.T) {
 
 	err = client.VolumeStop(smartvolname)
 	r.Nil(err)
-	r.Nil(client.VolumeDelete(smartvolname))
+	r.Nil(client.VolumeDelete(smartvolname, api.VolumeDeleteReq{Purge: true}))
 
 	g4, err := spawnGlusterd(t, "./config/4.toml", true)
 	r.Nil(err)
@@ -133,7 +133,7 @@ func testReplaceBrick(t *testing.T) {
 
 	err = client.VolumeStop(smartvolname)
 	r.Nil(err)
-	err = (client.VolumeDelete(smartvolname))
+	err = (client.VolumeDelete(smartvolname, api.VolumeDeleteReq{Purge: true}))
 	r.Nil(err)
 
 }
@@ -191,7 +191,7 @@ func testSmartVolumeDistribute(t *testing.T) {
 	_, err = client.VolumeExpand(smartvolname, expandReq)
 	r.NotNil(err)
 
-	r.Nil(client.VolumeDelete(smartvolname))
+	r.Nil(client.VolumeDelete(smartvolname, api.VolumeDeleteReq{Purge: true}))
 	checkZeroLvs(r)
 }
 
@@ -234,7 +234,7 @@ func testSmartVolumeReplicate2(t *testing.T) {
 	_, err = client.VolumeExpand(smartvolname, expandReq)
 	r.NotNil(err)
 
-	r.Nil(client.VolumeDelete(smartvolname))
+	r.Nil(client.VolumeDelete(smartvolname, api.VolumeDeleteReq{Purge: true}))
 	checkZeroLvs(r)
 }
 
@@ -279,7 +279,7 @@ func testSmartVolumeReplicate3(t *testing.T) {
 	_, err = client.VolumeExpand(smartvolname, expandReq)
 	r.NotNil(err)
 
-	r.Nil(client.VolumeDelete(smartvolname))
+	r.Nil(client.VolumeDelete(smartvolname, api.VolumeDeleteReq{Purge: true}))
 	checkZeroLvs(r)
 }
 
@@ -308,7 +308,7 @@ func testSmartVolumeArbiter(t *testing.T) {
 	// TODO: Change this after arbiter calculation fix
 	r.Nil(brickSizeTest(volinfo.Subvols[0].Bricks[2].Path, 16, 21))
 
-	r.Nil(client.VolumeDelete(smartvolname))
+	r.Nil(client.VolumeDelete(smartvolname, api.VolumeDeleteReq{Purge: true}))
 	checkZeroLvs(r)
 }
 
@@ -365,7 +365,7 @@ func testSmartVolumeDisperse(t *testing.T) {
 	_, err = client.VolumeExpand(smartvolname, expandReq)
 	r.NotNil(err)
 
-	r.Nil(client.VolumeDelete(smartvolname))
+	r.Nil(client.VolumeDelete(smartvolname, api.VolumeDeleteReq{Purge: true}))
 	checkZeroLvs(r)
 }
 
@@ -397,7 +397,7 @@ func testSmartVolumeDistributeReplicate(t *testing.T) {
 	r.Nil(brickSizeTest(volinfo.Subvols[1].Bricks[1].Path, 16, 21))
 	r.Nil(brickSizeTest(volinfo.Subvols[1].Bricks[2].Path, 16, 21))
 
-	r.Nil(client.VolumeDelete(smartvolname))
+	r.Nil(client.VolumeDelete(smartvolname, api.VolumeDeleteReq{Purge: true}))
 	checkZeroLvs(r)
 }
 
@@ -429,7 +429,7 @@ func testSmartVolumeDistributeDisperse(t *testing.T) {
 	r.Nil(brickSizeTest(volinfo.Subvols[1].Bricks[1].Path, 16, 21))
 	r.Nil(brickSizeTest(volinfo.Subvols[1].Bricks[2].Path, 16, 21))
 
-	r.Nil(client.VolumeDelete(smartvolname))
+	r.Nil(client.VolumeDelete(smartvolname, api.VolumeDeleteReq{Purge: true}))
 	checkZeroLvs(r)
 }
 
@@ -464,7 +464,7 @@ func testSmartVolumeAutoDistributeReplicate(t *testing.T) {
 	r.Len(volinfo.Subvols[0].Bricks, 3)
 	r.Len(volinfo.Subvols[1].Bricks, 3)
 
-	r.Nil(client.VolumeDelete(smartvolname))
+	r.Nil(client.VolumeDelete(smartvolname, api.VolumeDeleteReq{Purge: true}))
 	checkZeroLvs(r)
 
 	// Max-brick-size is more than request size
@@ -482,7 +482,7 @@ func testSmartVolumeAutoDistributeReplicate(t *testing.T) {
 	r.Equal("Replicate", volinfo.Type.String())
 	r.Len(volinfo.Subvols[0].Bricks, 3)
 
-	r.Nil(client.VolumeDelete(smartvolname))
+	r.Nil(client.VolumeDelete(smartvolname, api.VolumeDeleteReq{Purge: true}))
 	checkZeroLvs(r)
 }
 
@@ -506,7 +506,7 @@ func testSmartVolumeAutoDistributeDisperse(t *testing.T) {
 	r.Len(volinfo.Subvols[0].Bricks, 3)
 	r.Len(volinfo.Subvols[1].Bricks, 3)
 
-	r.Nil(client.VolumeDelete(smartvolname))
+	r.Nil(client.VolumeDelete(smartvolname, api.VolumeDeleteReq{Purge: true}))
 	checkZeroLvs(r)
 }
 
@@ -560,7 +560,7 @@ func testSmartVolumeWhenCloneExists(t *testing.T) {
 
 	r.Nil(client.VolumeStop(smartvolname))
 
-	r.Nil(client.VolumeDelete(smartvolname))
+	r.Nil(client.VolumeDelete(smartvolname, api.VolumeDeleteReq{Purge: true}))
 
 	nlv, err = numberOfLvs("gluster-dev-gluster_loop1")
 	r.Nil(err)
@@ -568,7 +568,7 @@ func testSmartVolumeWhenCloneExists(t *testing.T) {
 	r.Equal(2, nlv)
 
 	// Delete Clone Volume
-	r.Nil(client.VolumeDelete(smartvolname + "-c1"))
+	r.Nil(client.VolumeDelete(smartvolname+"-c1", api.VolumeDeleteReq{Purge: true}))
 
 	checkZeroLvs(r)
 }
@@ -642,7 +642,7 @@ func editDevice(t *testing.T) {
 	_, err = client.VolumeCreate(createReq)
 	r.Nil(err)
 
-	r.Nil(client.VolumeDelete(smartvolname))
+	r.Nil(client.VolumeDelete(smartvolname, api.VolumeDeleteReq{Purge: true}))
 }
 
 // TestSmartVolume creates a volume and starts it, runs further tests on it and
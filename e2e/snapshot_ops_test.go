@@ -60,7 +60,7 @@ func TestSnapshot(t *testing.T) {
 	}
 
 	defer func() {
-		client.VolumeDelete(snapTestName)
+		client.VolumeDelete(snapTestName, api.VolumeDeleteReq{Purge: true})
 		r.Nil(err)
 	}()
 
@@ -366,14 +366,14 @@ func testSnapshotValidation(t *testing.T) {
 
 	r.Nil(client.VolumeStop(clonename), "volume stop failed")
 
-	err = client.VolumeDelete(clonename)
+	err = client.VolumeDelete(clonename, api.VolumeDeleteReq{Purge: true})
 	r.NotNil(err, "Volume delete succeeded when snapshot is existing for the volume")
 }
 
 func testCloneDelete(t *testing.T) {
 	r := require.New(t)
 
-	r.Nil(client.VolumeDelete(clonename))
+	r.Nil(client.VolumeDelete(clonename, api.VolumeDeleteReq{Purge: true}))
 
 	volumes, err := client.Volumes("")
 	r.Nil(err)
@@ -441,11 +441,11 @@ func testSnapshotOnSmartVol(t *testing.T, tc *testCluster) {
 
 	r.Nil(client.VolumeStart(smartvolname, true))
 
-	r.Nil(client.VolumeDelete(clonename))
+	r.Nil(client.VolumeDelete(clonename, api.VolumeDeleteReq{Purge: true}))
 
 	r.Nil(client.VolumeStop(smartvolname))
 
-	r.Nil(client.VolumeDelete(smartvolname))
+	r.Nil(client.VolumeDelete(smartvolname, api.VolumeDeleteReq{Purge: true}))
 
 	//At this point all snapshot and volumes are deleted.
 	//So the lvcount should be zero
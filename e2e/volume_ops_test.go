@@ -55,6 +55,7 @@ func TestVolume(t *testing.T) {
 	t.Run("Start", testVolumeStart)
 	t.Run("Mount", tc.wrap(testVolumeMount))
 	t.Run("Status", testVolumeStatus)
+	t.Run("Size", testVolumeSize)
 	t.Run("Statedump", testVolumeStatedump)
 	t.Run("Stop", testVolumeStop)
 	t.Run("List", testVolumeList)
@@ -191,7 +192,7 @@ func testVolumeCreateWithFlags(t *testing.T, tc *testCluster) {
 	r.Nil(err)
 
 	//delete volume
-	r.Nil(client.VolumeDelete(volumeName))
+	r.Nil(client.VolumeDelete(volumeName, api.VolumeDeleteReq{Purge: true}))
 
 	createReqBrick.Name = volumeName
 	//set reuse-brick flag
@@ -201,14 +202,14 @@ func testVolumeCreateWithFlags(t *testing.T, tc *testCluster) {
 	_, err = client.VolumeCreate(createReqBrick)
 	r.Nil(err)
 
-	r.Nil(client.VolumeDelete(volumeName))
+	r.Nil(client.VolumeDelete(volumeName, api.VolumeDeleteReq{Purge: true}))
 
 	//recreate deleted volume
 	_, err = client.VolumeCreate(createReqBrick)
 	r.Nil(err)
 
 	//delete volume
-	r.Nil(client.VolumeDelete(volumeName))
+	r.Nil(client.VolumeDelete(volumeName, api.VolumeDeleteReq{Purge: true}))
 
 }
 
@@ -316,7 +317,7 @@ func testVolumeExpand(t *testing.T, tc *testCluster) {
 	}
 
 	//delete volume
-	r.Nil(client.VolumeDelete("TestVolumeExpand"))
+	r.Nil(client.VolumeDelete("TestVolumeExpand", api.VolumeDeleteReq{Purge: true}))
 
 	req := api.VolCreateReq{
 		Name: "TestVolumeExpandArbiter",
@@ -366,12 +367,49 @@ func testVolumeExpand(t *testing.T, tc *testCluster) {
 		r.Equal(subvol.Bricks[len(subvol.Bricks)-1].Type.String(), "Arbiter")
 	}
 
-	r.Nil(client.VolumeDelete("TestVolumeExpandArbiter"))
+	r.Nil(client.VolumeDelete("TestVolumeExpandArbiter", api.VolumeDeleteReq{Purge: true}))
 }
 
 func testVolumeDelete(t *testing.T) {
 	r := require.New(t)
-	r.Nil(client.VolumeDelete(volname))
+
+	vol, err := client.Volumes(volname)
+	r.Nil(err)
+	volID := vol[0].ID.String()
+
+	// deleted volumes are retained in the trash
+	r.Nil(client.VolumeDelete(volname, api.VolumeDeleteReq{}))
+	_, err = client.Volumes(volname)
+	r.NotNil(err)
+	trashed, err := client.VolumeTrashList()
+	r.Nil(err)
+	r.Len(trashed, 1)
+	r.Equal(volname, trashed[0].Volume.Name)
+	r.True(trashed[0].PurgeAt.After(trashed[0].DeletedAt))
+
+	undeleted, err := client.VolumeUndelete(volID)
+	r.Nil(err)
+	r.Equal(volname, undeleted.Name)
+	r.Equal(api.VolStopped, undeleted.State)
+	trashed, err = client.VolumeTrashList()
+	r.Nil(err)
+	r.Len(trashed, 0)
+
+	// the metadata of the bricks is removed on purging the volume
+	r.Nil(client.VolumeDelete(volname, api.VolumeDeleteReq{BrickData: api.BrickDataMetadata}))
+	r.Nil(client.VolumePurge(volID))
+	trashed, err = client.VolumeTrashList()
+	r.Nil(err)
+	r.Len(trashed, 0)
+	r.NotNil(client.VolumePurge(volID))
+	for _, subvol := range vol[0].Subvols {
+		for _, b := range subvol.Bricks {
+			_, err := os.Stat(filepath.Join(b.Path, ".glusterfs"))
+			r.True(os.IsNotExist(err))
+		}
+	}
+
+	r.NotNil(client.VolumeDelete(volname, api.VolumeDeleteReq{BrickData: "shred"}))
 }
 
 func testVolumeStart(t *testing.T) {
@@ -493,6 +531,15 @@ func testVolumeStatus(t *testing.T) {
 	r.Nil(err)
 }
 
+func testVolumeSize(t *testing.T) {
+	r := require.New(t)
+
+	size, err := client.VolumeSize(volname)
+	r.Nil(err)
+	r.NotZero(size.Capacity)
+	r.Equal(size.Bricks, size.BricksReported)
+}
+
 func testVolumeStatedump(t *testing.T) {
 	r := require.New(t)
 
@@ -618,7 +665,7 @@ func TestVolumeOptions(t *testing.T) {
 		resetOptionReq.Force = true
 		r.Nil(client.VolumeReset(volname, resetOptionReq))
 
-		err = client.VolumeDelete(volname)
+		err = client.VolumeDelete(volname, api.VolumeDeleteReq{Purge: true})
 		r.Nil(err)
 	}
 
@@ -646,7 +693,7 @@ func TestVolumeOptions(t *testing.T) {
 	_, err = client.VolumeGet(volname, "cluster/replicate.eager-lock")
 	r.Nil(err)
 
-	err = client.VolumeDelete(volname)
+	err = client.VolumeDelete(volname, api.VolumeDeleteReq{Purge: true})
 	r.Nil(err)
 
 	// invalid option test cases
@@ -658,7 +705,7 @@ func TestVolumeOptions(t *testing.T) {
 		_, err = client.VolumeGet(volname, invalidKey)
 		r.NotNil(err)
 
-		err = client.VolumeDelete(volname)
+		err = client.VolumeDelete(volname, api.VolumeDeleteReq{Purge: true})
 		r.Nil(err)
 
 		createReq.Options = map[string]string{invalidKey: "on"}
@@ -699,7 +746,7 @@ func TestVolumeOptions(t *testing.T) {
 	optionReq.Options = map[string]string{notSettableKey: "on"}
 	r.NotNil(client.VolumeSet(volname, optionReq))
 
-	r.Nil(client.VolumeDelete(volname))
+	r.Nil(client.VolumeDelete(volname, api.VolumeDeleteReq{Purge: true}))
 
 	// group option test cases
 	groupOpKeys := []string{"tls"}
@@ -713,7 +760,7 @@ func TestVolumeOptions(t *testing.T) {
 		resetOptionReq.Options = []string{"tls"}
 		r.Nil(client.VolumeReset(volname, resetOptionReq))
 
-		err = client.VolumeDelete(volname)
+		err = client.VolumeDelete(volname, api.VolumeDeleteReq{Purge: true})
 		r.Nil(err)
 	}
 	for _, validKey := range groupOpKeys {
@@ -722,7 +769,7 @@ func TestVolumeOptions(t *testing.T) {
 		_, err = client.VolumeCreate(createReq)
 		r.Nil(err)
 
-		err = client.VolumeDelete(volname)
+		err = client.VolumeDelete(volname, api.VolumeDeleteReq{Purge: true})
 		r.Nil(err)
 	}
 
@@ -761,8 +808,35 @@ func TestVolumeOptions(t *testing.T) {
 	_, err = client.OptionGroupList()
 	r.Nil(err)
 
+	group, err := client.OptionGroupGet("profile.test2")
+	r.Nil(err)
+	r.Len(group.Options, 2)
+	r.False(group.Builtin)
+
 	r.Nil(client.OptionGroupDelete("profile.test2"))
 
+	_, err = client.OptionGroupGet("profile.test2")
+	r.NotNil(err)
+
+	// the builtin groups can be overridden, and reverted
+	builtin, err := client.OptionGroupGet("profile.virt")
+	r.Nil(err)
+	r.True(builtin.Builtin)
+	r.False(builtin.Overridden)
+	r.NotNil(client.OptionGroupDelete("profile.virt"))
+
+	optionGroupReq.Name = "profile.virt"
+	r.Nil(client.OptionGroupCreate(optionGroupReq))
+	group, err = client.OptionGroupGet("profile.virt")
+	r.Nil(err)
+	r.True(group.Overridden)
+	r.Len(group.Options, 2)
+
+	r.Nil(client.OptionGroupDelete("profile.virt"))
+	group, err = client.OptionGroupGet("profile.virt")
+	r.Nil(err)
+	r.False(group.Overridden)
+	r.Equal(builtin.Options, group.Options)
 }
 
 func testDisperse(t *testing.T, tc *testCluster) {
@@ -807,7 +881,7 @@ func testDisperseMount(t *testing.T, tc *testCluster) {
 func testDisperseDelete(t *testing.T) {
 	r := require.New(t)
 	r.Nil(client.VolumeStop(disperseVolName), "disperse volume stop failed")
-	r.Nil(client.VolumeDelete(disperseVolName), "disperse volume delete failed")
+	r.Nil(client.VolumeDelete(disperseVolName, api.VolumeDeleteReq{Purge: true}), "disperse volume delete failed")
 }
 
 func validateVolumeEdit(volinfo api.VolumeGetResp, editMetadataReq api.VolEditReq, resp api.VolumeEditResp) error {
@@ -964,8 +1038,8 @@ func testShdOnVolumeStartAndStop(t *testing.T, tc *testCluster) {
 	r.Nil(client.VolumeStop(vol1.Name))
 	r.False(isProcessRunning(pidpath), "glustershd is running")
 
-	r.Nil(client.VolumeDelete(vol1.Name))
-	r.Nil(client.VolumeDelete(vol2.Name))
+	r.Nil(client.VolumeDelete(vol1.Name, api.VolumeDeleteReq{Purge: true}))
+	r.Nil(client.VolumeDelete(vol2.Name, api.VolumeDeleteReq{Purge: true}))
 }
 
 func testArbiterVolumeCreate(t *testing.T, tc *testCluster) {
@@ -1017,7 +1091,7 @@ func testArbiterVolumeCreate(t *testing.T, tc *testCluster) {
 
 	r.Nil(client.VolumeStop(volumeName))
 
-	r.Nil(client.VolumeDelete(volumeName))
+	r.Nil(client.VolumeDelete(volumeName, api.VolumeDeleteReq{Purge: true}))
 }
 
 func testVolumeProfileInfo(t *testing.T, tc *testCluster) {
@@ -1100,6 +1174,6 @@ func testVolumeProfileInfo(t *testing.T, tc *testCluster) {
 
 	r.Nil(client.VolumeStop(volname))
 
-	r.Nil(client.VolumeDelete(volname))
+	r.Nil(client.VolumeDelete(volname, api.VolumeDeleteReq{Purge: true}))
 
 }
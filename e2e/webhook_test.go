@@ -83,7 +83,7 @@ func testAddWebhook(t *testing.T, tc *testCluster) {
 	_, err := client.VolumeCreate(createReq)
 	r.Nil(err)
 
-	r.Nil(client.VolumeDelete(volumeName))
+	r.Nil(client.VolumeDelete(volumeName, api.VolumeDeleteReq{Purge: true}))
 }
 
 func testGetWebhook(t *testing.T) {
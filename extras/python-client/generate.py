@@ -0,0 +1,146 @@
+#!/usr/bin/env python3
+"""Generates the operations of the Python client of glusterd2 from the
+OpenAPI document of its REST API
+
+    $ ./generate.py --url http://127.0.0.1:24007
+    $ ./generate.py --spec openapi.json --out glusterd2client/operations.py
+
+glusterd2 serves the document at /openapi.json, built from its routes, so a
+client generated from a running glusterd2 matches its API.
+"""
+
+import argparse
+import json
+import keyword
+import os
+import re
+import sys
+import urllib.request
+
+HEADER = '''"""Operations of the glusterd2 REST API
+
+This file is generated by generate.py from the OpenAPI document of
+glusterd2 %(version)s. DO NOT EDIT.
+"""
+
+
+class Operations(object):
+    """Methods of the operations of the API, sending the requests with
+    self.request"""
+'''
+
+METHODS = ("get", "put", "post", "delete", "patch")
+
+
+def snake_case(operation_id):
+    """VolumeCreate -> volume_create"""
+    name = re.sub(r"([a-z0-9])([A-Z])", r"\1_\2", operation_id)
+    name = re.sub(r"([A-Z]+)([A-Z][a-z])", r"\1_\2", name).lower()
+    if keyword.iskeyword(name):
+        name += "_"
+    return name
+
+
+def schema_name(schema):
+    """Returns the name of the type of a schema, for the docstrings"""
+    if schema is None:
+        return None
+    if "$ref" in schema:
+        return schema["$ref"].rsplit("/", 1)[-1]
+    if schema.get("type") == "array":
+        return "[]" + (schema_name(schema.get("items")) or "any")
+    return schema.get("type", "any")
+
+
+def json_schema(content):
+    if not content:
+        return None
+    return content.get("application/json", {}).get("schema")
+
+
+def operation_method(path, method, op):
+    """Returns the source of the method of an operation"""
+    name = snake_case(op["operationId"])
+    params = [p["name"] for p in op.get("parameters", [])
+              if p.get("in") == "path"]
+    args = ["self"] + [p + "_" if keyword.iskeyword(p) else p
+                       for p in params]
+
+    request = json_schema(op.get("requestBody", {}).get("content"))
+    if request is not None:
+        args.append("body=None")
+
+    doc = ["%s %s" % (method.upper(), path)]
+    if op.get("summary"):
+        doc.insert(0, op["summary"])
+    if request is not None:
+        doc.append("Request: %s" % schema_name(request))
+    response = json_schema(op.get("responses", {}).get("2XX", {})
+                           .get("content"))
+    if response is not None:
+        doc.append("Response: %s" % schema_name(response))
+
+    pairs = ", ".join("(%r, %s)" % (p, a)
+                      for p, a in zip(params, args[1:len(params) + 1]))
+    lines = ["", "    def %s(%s):" % (name, ", ".join(args))]
+    if len(doc) == 1:
+        lines.append('        """%s"""' % doc[0])
+    else:
+        lines.append('        """%s' % doc[0])
+        lines.append("")
+        lines.extend("        " + d for d in doc[1:])
+        lines.append('        """')
+    lines.append("        return self.request(%r, %r, params=[%s]%s)" % (
+        method.upper(), path, pairs,
+        ", body=body" if request is not None else ""))
+    return "\n".join(lines) + "\n"
+
+
+def generate(spec):
+    """Returns the source of operations.py for the OpenAPI document"""
+    out = [HEADER % {"version": spec.get("info", {}).get("version") or
+                     "(unknown version)"}]
+    methods = {}
+    for path in sorted(spec.get("paths", {})):
+        for method in METHODS:
+            op = spec["paths"][path].get(method)
+            if op is None:
+                continue
+            name = snake_case(op["operationId"])
+            if name in methods:
+                raise ValueError("operations %s %s and %s share the name %s" %
+                                 (method.upper(), path, methods[name], name))
+            methods[name] = "%s %s" % (method.upper(), path)
+            out.append(operation_method(path, method, op))
+    return "".join(out)
+
+
+def main():
+    parser = argparse.ArgumentParser(description=__doc__.splitlines()[0])
+    source = parser.add_mutually_exclusive_group(required=True)
+    source.add_argument("--url", help="URL of a running glusterd2")
+    source.add_argument("--spec", help="file of the OpenAPI document")
+    parser.add_argument("--out", default=os.path.join(
+        os.path.dirname(os.path.abspath(__file__)),
+        "glusterd2client", "operations.py"),
+        help="file the operations are written to")
+    args = parser.parse_args()
+
+    if args.url:
+        with urllib.request.urlopen(args.url.rstrip("/") +
+                                    "/openapi.json") as resp:
+            spec = json.loads(resp.read().decode())
+    else:
+        with open(args.spec) as f:
+            spec = json.load(f)
+
+    try:
+        source = generate(spec)
+    except ValueError as e:
+        sys.exit(str(e))
+    with open(args.out, "w") as f:
+        f.write(source)
+
+
+if __name__ == "__main__":
+    main()
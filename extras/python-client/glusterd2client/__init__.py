@@ -0,0 +1,19 @@
+"""Python client of the glusterd2 REST API
+
+    from glusterd2client import Client, APIError
+
+    client = Client("http://gluster1:24007",
+                    secret_file="/var/lib/glusterd2/auth")
+    try:
+        client.volume_start("testvol", body={})
+    except APIError as e:
+        if not e.has_reason("VOLUME_ALREADY_STARTED"):
+            raise
+"""
+
+from glusterd2client.client import APIError, BaseClient  # noqa: F401
+from glusterd2client.operations import Operations
+
+
+class Client(Operations, BaseClient):
+    """Client of the glusterd2 REST API, with a method per operation"""
@@ -0,0 +1,127 @@
+"""HTTP client of the glusterd2 REST API
+
+The methods of the operations of the API are generated from its OpenAPI
+document into operations.py by generate.py.
+"""
+
+import base64
+import hashlib
+import hmac
+import json
+import ssl
+import time
+import urllib.error
+import urllib.parse
+import urllib.request
+
+# Lifetime of the tokens authenticating the requests, in seconds
+TOKEN_EXPIRY = 120
+
+
+class APIError(Exception):
+    """Error response of glusterd2
+
+    errors is the list of errors of the response, each with its code,
+    message, machine-readable reason and fields.
+    """
+
+    def __init__(self, status, errors, body=None):
+        self.status = status
+        self.errors = errors
+        self.body = body
+        messages = [e.get("message", "") for e in errors] or [body or ""]
+        super().__init__("Request failed. Status: %d, %s" %
+                         (status, "; ".join(messages)))
+
+    @property
+    def reasons(self):
+        """Reasons of the errors of the response"""
+        return [e.get("reason") for e in self.errors]
+
+    def has_reason(self, reason):
+        """Returns True if any of the errors of the response has reason"""
+        return reason in self.reasons
+
+
+def _b64(data):
+    return base64.urlsafe_b64encode(data).rstrip(b"=")
+
+
+class BaseClient(object):
+    """Sends the requests to glusterd2, authenticated with a token signed
+    with the secret of the user, as the Go client does"""
+
+    def __init__(self, url, user="glustercli", secret=None, secret_file=None,
+                 timeout=30, cafile=None, insecure=False):
+        self.url = url.rstrip("/")
+        self.user = user
+        self.secret = secret
+        if secret is None and secret_file is not None:
+            with open(secret_file) as f:
+                self.secret = f.read().strip()
+        self.timeout = timeout
+
+        self._context = None
+        if self.url.startswith("https"):
+            self._context = ssl.create_default_context(cafile=cafile)
+            if insecure:
+                self._context.check_hostname = False
+                self._context.verify_mode = ssl.CERT_NONE
+
+    def token(self, method, path):
+        """Returns the token authenticating a request"""
+        header = {"alg": "HS256", "typ": "JWT"}
+        claims = {
+            "iss": self.user,
+            "exp": int(time.time()) + TOKEN_EXPIRY,
+            "qsh": hashlib.sha256(
+                (method + "&" + path).encode()).hexdigest(),
+        }
+        signing_input = b".".join([
+            _b64(json.dumps(header, separators=(",", ":")).encode()),
+            _b64(json.dumps(claims, separators=(",", ":")).encode()),
+        ])
+        signature = hmac.new(self.secret.encode(), signing_input,
+                             hashlib.sha256).digest()
+        return (signing_input + b"." + _b64(signature)).decode()
+
+    def request(self, method, path, params=(), body=None):
+        """Sends a request and returns its decoded response
+
+        path is the path of the operation, whose variables are replaced by
+        params, in order. APIError is raised for the error responses.
+        """
+        # The token is signed for the path as decoded by glusterd2
+        url_path = path
+        for name, value in params:
+            path = path.replace("{%s}" % name, str(value))
+            url_path = url_path.replace(
+                "{%s}" % name, urllib.parse.quote(str(value), safe="/"))
+
+        data = None
+        headers = {"Accept": "application/json"}
+        if body is not None:
+            data = json.dumps(body).encode()
+            headers["Content-Type"] = "application/json"
+        if self.user and self.secret:
+            headers["Authorization"] = "bearer " + self.token(method, path)
+
+        req = urllib.request.Request(
+            self.url + url_path, data=data, headers=headers,
+            method=method)
+        try:
+            with urllib.request.urlopen(req, timeout=self.timeout,
+                                        context=self._context) as resp:
+                content = resp.read()
+        except urllib.error.HTTPError as e:
+            content = e.read().decode(errors="replace")
+            try:
+                errors = json.loads(content).get("errors", [])
+            except ValueError:
+                # Not a response of glusterd2, but of a proxy
+                errors = []
+            raise APIError(e.code, errors, content)
+
+        if not content:
+            return None
+        return json.loads(content.decode())
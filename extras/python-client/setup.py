@@ -0,0 +1,24 @@
+"""Python client of the glusterd2 REST API
+
+The operations are generated from the OpenAPI document of glusterd2 before
+packaging, see README.md.
+"""
+
+import os
+
+from setuptools import setup
+
+here = os.path.dirname(os.path.abspath(__file__))
+if not os.path.exists(os.path.join(here, "glusterd2client", "operations.py")):
+    raise SystemExit("glusterd2client/operations.py is missing, "
+                     "run generate.py first")
+
+setup(
+    name="glusterd2client",
+    version=os.environ.get("GD2_VERSION", "0.0.0"),
+    description="Python client of the glusterd2 REST API",
+    url="https://github.com/gluster/glusterd2",
+    license="GPLv2 or LGPLv3+",
+    packages=["glusterd2client"],
+    python_requires=">=3.4",
+)
@@ -0,0 +1,176 @@
+"""Tests of the generated client, against a fake glusterd2"""
+
+import base64
+import hashlib
+import hmac
+import http.server
+import importlib
+import json
+import os
+import sys
+import tempfile
+import threading
+import unittest
+
+HERE = os.path.dirname(os.path.abspath(__file__))
+sys.path.insert(0, os.path.dirname(HERE))
+
+import generate  # noqa: E402
+
+SPEC = {
+    "openapi": "3.0.0",
+    "info": {"title": "GlusterD2 REST API", "version": "v4.1.0"},
+    "paths": {
+        "/v1/volumes/{volname}": {
+            "get": {
+                "operationId": "VolumeInfo",
+                "parameters": [{"name": "volname", "in": "path",
+                                "required": True,
+                                "schema": {"type": "string"}}],
+                "responses": {"2XX": {"description": "success", "content": {
+                    "application/json": {"schema": {
+                        "$ref": "#/components/schemas/api.VolumeGetResp"}}}}},
+            },
+        },
+        "/v1/volumes/{volname}/start": {
+            "post": {
+                "operationId": "VolumeStart",
+                "parameters": [{"name": "volname", "in": "path",
+                                "required": True,
+                                "schema": {"type": "string"}}],
+                "requestBody": {"content": {"application/json": {"schema": {
+                    "$ref": "#/components/schemas/api.VolumeStartReq"}}}},
+                "responses": {"2XX": {"description": "success"}},
+            },
+        },
+        "/v1/cluster/locks/{lockid}": {
+            "delete": {
+                "operationId": "ForceReleaseClusterLock",
+                "parameters": [{"name": "lockid", "in": "path",
+                                "required": True,
+                                "schema": {"type": "string"}}],
+                "responses": {"2XX": {"description": "success"}},
+            },
+        },
+    },
+}
+
+SECRET = "secret"
+
+
+class FakeGlusterd2(http.server.BaseHTTPRequestHandler):
+    requests = []
+
+    def log_message(self, *args):
+        pass
+
+    def handle_one(self):
+        length = int(self.headers.get("Content-Length") or 0)
+        body = self.rfile.read(length) if length else None
+        self.requests.append((self.command, self.path,
+                              self.headers.get("Authorization"), body))
+
+        if self.path == "/v1/volumes/missing":
+            status, resp = 404, {"errors": [{
+                "code": 1, "message": "volume not found",
+                "reason": "VOLUME_NOT_FOUND"}]}
+        elif self.command == "GET":
+            status, resp = 200, {"name": self.path.rsplit("/", 1)[-1]}
+        else:
+            status, resp = 200, None
+
+        self.send_response(status)
+        self.end_headers()
+        if resp is not None:
+            self.wfile.write(json.dumps(resp).encode())
+
+    do_GET = do_POST = do_DELETE = handle_one
+
+
+def verify_token(token, method, path):
+    signing_input, signature = token.rsplit(".", 1)
+    expected = hmac.new(SECRET.encode(), signing_input.encode(),
+                        hashlib.sha256).digest()
+    padded = signature + "=" * (-len(signature) % 4)
+    if base64.urlsafe_b64decode(padded) != expected:
+        return False
+    claims = signing_input.split(".")[1]
+    claims = json.loads(base64.urlsafe_b64decode(
+        claims + "=" * (-len(claims) % 4)).decode())
+    qsh = hashlib.sha256((method + "&" + path).encode()).hexdigest()
+    return claims["iss"] == "glustercli" and claims["qsh"] == qsh
+
+
+class ClientTest(unittest.TestCase):
+
+    @classmethod
+    def setUpClass(cls):
+        cls.tmp = tempfile.TemporaryDirectory()
+        pkg = os.path.join(cls.tmp.name, "glusterd2client")
+        os.mkdir(pkg)
+        src = os.path.join(os.path.dirname(HERE), "glusterd2client")
+        for f in ("__init__.py", "client.py"):
+            with open(os.path.join(src, f)) as i, \
+                    open(os.path.join(pkg, f), "w") as o:
+                o.write(i.read())
+        with open(os.path.join(pkg, "operations.py"), "w") as f:
+            f.write(generate.generate(SPEC))
+
+        sys.path.insert(0, cls.tmp.name)
+        for m in list(sys.modules):
+            if m.startswith("glusterd2client"):
+                del sys.modules[m]
+        cls.client_mod = importlib.import_module("glusterd2client")
+
+        cls.server = http.server.HTTPServer(("127.0.0.1", 0), FakeGlusterd2)
+        threading.Thread(target=cls.server.serve_forever, daemon=True).start()
+
+    @classmethod
+    def tearDownClass(cls):
+        cls.server.shutdown()
+        sys.path.remove(cls.tmp.name)
+        cls.tmp.cleanup()
+
+    def setUp(self):
+        FakeGlusterd2.requests = []
+        self.client = self.client_mod.Client(
+            "http://127.0.0.1:%d" % self.server.server_port, secret=SECRET)
+
+    def test_snake_case(self):
+        self.assertEqual("volume_create", generate.snake_case("VolumeCreate"))
+        self.assertEqual("list_endpoints",
+                         generate.snake_case("ListEndpoints"))
+        self.assertEqual("csi_volume_get", generate.snake_case("CSIVolumeGet"))
+
+    def test_operations(self):
+        self.assertEqual({"name": "testvol"},
+                         self.client.volume_info("testvol"))
+        self.assertIsNone(self.client.volume_start("testvol", body={}))
+        self.client.force_release_cluster_lock("vol/1")
+
+        reqs = FakeGlusterd2.requests
+        self.assertEqual(("GET", "/v1/volumes/testvol"), reqs[0][:2])
+        self.assertEqual(("POST", "/v1/volumes/testvol/start"), reqs[1][:2])
+        self.assertEqual(b"{}", reqs[1][3])
+        self.assertEqual(("DELETE", "/v1/cluster/locks/vol/1"), reqs[2][:2])
+        for method, path, auth, _ in reqs:
+            self.assertTrue(auth.startswith("bearer "))
+            self.assertTrue(verify_token(auth[len("bearer "):], method, path))
+
+    def test_errors(self):
+        with self.assertRaises(self.client_mod.APIError) as cm:
+            self.client.volume_info("missing")
+        self.assertEqual(404, cm.exception.status)
+        self.assertTrue(cm.exception.has_reason("VOLUME_NOT_FOUND"))
+        self.assertIn("volume not found", str(cm.exception))
+
+    def test_duplicate_names(self):
+        spec = json.loads(json.dumps(SPEC))
+        spec["paths"]["/v1/volumes/{volname}"]["get"]["operationId"] = \
+            "VolumeStart"
+        with self.assertRaises(ValueError):
+            generate.generate(spec)
+
+
+if __name__ == "__main__":
+    unittest.main()
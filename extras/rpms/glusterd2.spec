@@ -103,6 +103,8 @@ popd
 %{gd2make} DESTDIR=%{buildroot} install
 # Install systemd unit
 install -D -p -m 0644 extras/systemd/%{name}.service %{buildroot}%{_unitdir}/%{name}.service
+%{_unitdir}/%{name}.socket
+install -D -p -m 0644 extras/systemd/%{name}.socket %{buildroot}%{_unitdir}/%{name}.socket
 # Create /var/lib/glusterd2
 install -d -m 0755 %{buildroot}%{_sharedstatedir}/%{name}
 # Setup logdir
@@ -113,10 +115,10 @@ install -D -p -m 0644 %{SOURCE1} %{buildroot}%{_sysconfdir}/logrotate.d/%{name}
 install -d -m 0755 %{buildroot}%{_sysconfdir}/sysconfig/%{name}
 
 %post
-%systemd_post %{name}.service
+%systemd_post %{name}.service %{name}.socket
 
 %preun
-%systemd_preun %{name}.service
+%systemd_preun %{name}.service %{name}.socket
 
 %files
 %{_sbindir}/%{name}
@@ -60,7 +60,7 @@ var bitrotEnableCmd = &cobra.Command{
 			}
 			failure(fmt.Sprintf("Failed to enable bitrot for volume %s\n", volname), err, 1)
 		}
-		fmt.Printf("Bitrot enabled successfully for volume %s\n", volname)
+		printMessagef("Bitrot enabled successfully for volume %s\n", volname)
 	},
 }
 
@@ -78,7 +78,7 @@ var bitrotDisableCmd = &cobra.Command{
 			}
 			failure(fmt.Sprintf("Failed to disable bitrot for volume %s\n", volname), err, 1)
 		}
-		fmt.Printf("Bitrot disabled successfully for volume '%s'\n", volname)
+		printMessagef("Bitrot disabled successfully for volume '%s'\n", volname)
 	},
 }
 
@@ -104,7 +104,7 @@ var bitrotScrubThrottleCmd = &cobra.Command{
 			}
 			failure(fmt.Sprintf("Failed to set bitrot scrub throttle to %s for volume %s", args[1], volname), err, 1)
 		}
-		fmt.Printf("Bitrot scrub throttle set successfully to %s for volume %s\n", args[1], volname)
+		printMessagef("Bitrot scrub throttle set successfully to %s for volume %s\n", args[1], volname)
 	},
 }
 
@@ -130,7 +130,7 @@ var bitrotScrubFrequencyCmd = &cobra.Command{
 			}
 			failure(fmt.Sprintf("Failed to set bitrot scrub frequency to %s for volume %s", args[1], volname), err, 1)
 		}
-		fmt.Printf("Bitrot scrub frequency is set successfully to %s for volume %s\n", args[1], volname)
+		printMessagef("Bitrot scrub frequency is set successfully to %s for volume %s\n", args[1], volname)
 	},
 }
 
@@ -159,7 +159,7 @@ var bitrotScrubCmd = &cobra.Command{
 				}
 				failure(fmt.Sprintf("Failed to %s bitrot scrub for volume %s", args[1], volname), err, 1)
 			}
-			fmt.Printf("Bitrot scrub %s is successful for volume %s\n", args[1], volname)
+			printMessagef("Bitrot scrub %s is successful for volume %s\n", args[1], volname)
 
 		case scrubStatus:
 			scrubStatus, err := client.BitrotScrubStatus(volname)
@@ -170,6 +170,9 @@ var bitrotScrubCmd = &cobra.Command{
 				}
 				failure(fmt.Sprintf("Failed to get bitrot scrub status for volume %s\n", volname), err, 1)
 			}
+			if printStructured(scrubStatus) {
+				return
+			}
 			fmt.Println()
 			fmt.Printf("Volume: %s\n", scrubStatus.Volume)
 			fmt.Printf("Scrub state: %s\n", scrubStatus.State)
@@ -215,7 +218,7 @@ var bitrotScrubCmd = &cobra.Command{
 				}
 				failure(fmt.Sprintf("Failed to start bitrot scrub on demand for volume %s\n", volname), err, 1)
 			}
-			fmt.Printf("Bitrot scrub on demand started successfully for volume %s\n", volname)
+			printMessagef("Bitrot scrub on demand started successfully for volume %s\n", volname)
 		default:
 			failure(fmt.Sprintf(
 				"Invalid scrub value: %s\nUsage: glustercli bitrot scrub <volname> {pause|resume|status|ondemand}",
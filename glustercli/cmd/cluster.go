@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/gluster/glusterd2/pkg/api"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+const (
+	helpClusterCmd        = "Gluster Cluster Management"
+	helpClusterDoctorCmd  = "Check the cluster for common problems"
+	helpClusterDoctorLong = `Check the cluster for common problems and suggest the actions to fix them.
+
+The checks are:
+  peers                     peers which do not respond or are not connected to the store
+  clock-skew                clocks of the peers which are out of sync
+  store-quorum              store members which are unhealthy, and loss of store quorum
+  orphaned-brick-processes  brick processes which do not belong to any started volume
+  stale-pmap-entries        port map entries of bricks which are not running
+  volfile-checksums         volfiles which differ between the peers or from the volume configuration
+  pending-heals             bricks of replicate and disperse volumes having entries pending heal
+
+The command exits with status 1 if any check reports an error.`
+	errClusterDoctorFailed = "Failed to run cluster diagnostics"
+
+	helpClusterTopologyCmd  = "Show the topology of the cluster"
+	helpClusterTopologyLong = `Show the topology of the cluster as a graph of the peers, their devices and
+daemons, the bricks, and the subvolumes and volumes made of the bricks.
+
+The graph is printed in the DOT language of Graphviz, to be rendered with
+for example:
+
+  glustercli cluster topology | dot -Tsvg > topology.svg
+
+With --output json or yaml, the nodes and edges of the graph are printed.`
+	errClusterTopologyFailed = "Failed to get the topology of the cluster"
+
+	helpClusterLeadersCmd   = "Show the peers leading the singleton services of the cluster"
+	errClusterLeadersFailed = "Failed to get the leaders of the services"
+
+	helpClusterLeaderFailoverCmd  = "Move a singleton service of the cluster to another peer"
+	helpClusterLeaderFailoverLong = `Make the peer leading the singleton service resign, for another peer to take
+the service over. The service stays on the same peer if no other peer
+contests its election.`
+	errClusterLeaderFailoverFailed = "Failed to move the service to another peer"
+)
+
+func init() {
+	clusterCmd.AddCommand(clusterDoctorCmd)
+	clusterCmd.AddCommand(clusterTopologyCmd)
+	clusterCmd.AddCommand(clusterLeadersCmd)
+	clusterCmd.AddCommand(clusterLeaderFailoverCmd)
+}
+
+var clusterCmd = &cobra.Command{
+	Use:   "cluster",
+	Short: helpClusterCmd,
+}
+
+var clusterDoctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: helpClusterDoctorCmd,
+	Long:  helpClusterDoctorLong,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		resp, err := client.Diagnostics()
+		if err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).Error("cluster diagnostics failed")
+			}
+			failure(errClusterDoctorFailed, err, 1)
+		}
+
+		if !printStructured(resp) {
+			printDiagnostics(resp)
+		}
+		if resp.Status == api.DiagnosticError {
+			os.Exit(1)
+		}
+	},
+}
+
+var clusterTopologyCmd = &cobra.Command{
+	Use:   "topology",
+	Short: helpClusterTopologyCmd,
+	Long:  helpClusterTopologyLong,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		resp, err := client.ClusterTopology()
+		if err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).Error("failed to get the topology of the cluster")
+			}
+			failure(errClusterTopologyFailed, err, 1)
+		}
+
+		if !printStructured(resp) {
+			fmt.Print(resp.DOT())
+		}
+	},
+}
+
+var clusterLeadersCmd = &cobra.Command{
+	Use:   "leaders",
+	Short: helpClusterLeadersCmd,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		resp, err := client.Leaders()
+		if err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).Error("failed to get the leaders of the services")
+			}
+			failure(errClusterLeadersFailed, err, 1)
+		}
+
+		if printStructured(resp) {
+			return
+		}
+		table := newTable()
+		table.SetHeader([]string{"Service", "Peer ID", "Peer"})
+		for _, l := range resp {
+			table.Append([]string{l.Service, l.PeerID, l.PeerName})
+		}
+		table.Render()
+	},
+}
+
+var clusterLeaderFailoverCmd = &cobra.Command{
+	Use:   "leader-failover <service>",
+	Short: helpClusterLeaderFailoverCmd,
+	Long:  helpClusterLeaderFailoverLong,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		resp, err := client.LeaderFailover(args[0])
+		if err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).WithField("service", args[0]).Error("failed to move the service to another peer")
+			}
+			failure(errClusterLeaderFailoverFailed, err, 1)
+		}
+		fmt.Printf("Peer %s resigned from leading the service %s\n", resp.PeerName, resp.Service)
+	},
+}
+
+func printDiagnostics(resp api.DiagnosticsResp) {
+	table := newTable()
+	table.SetHeader([]string{"Check", "Status", "Findings"})
+	for _, c := range resp.Checks {
+		table.Append([]string{c.Name, c.Status, fmt.Sprintf("%d", len(c.Findings))})
+	}
+	table.Render()
+
+	for _, c := range resp.Checks {
+		for _, f := range c.Findings {
+			fmt.Printf("\n[%s] %s: %s\n", f.Severity, c.Name, f.Message)
+			if f.Action != "" {
+				fmt.Printf("    Action: %s\n", f.Action)
+			}
+		}
+	}
+	if resp.Status == api.DiagnosticOK {
+		fmt.Println("\nNo problems found")
+	}
+}
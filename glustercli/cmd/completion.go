@@ -0,0 +1,380 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	helpCompletionCmd  = "Generate shell completion script for bash, zsh or fish"
+	helpCompletionLong = `Generate shell completion script for bash, zsh or fish.
+
+Names of volumes and peers are completed by querying glusterd2, and names of
+profiles from the glustercli configuration.
+
+To load completions in the current shell:
+
+  bash: source <(glustercli completion bash)
+  zsh:  source <(glustercli completion zsh)
+  fish: glustercli completion fish | source
+`
+
+	// names of the objects completed by querying glusterd2
+	completeVolumes = "volumes"
+	completePeers   = "peers"
+	// names of the profiles in the glustercli configuration
+	completeProfiles = "profiles"
+)
+
+// argCompletions lists, for the commands accepting names of volumes or peers
+// as arguments, the kind of object to be completed at each argument position.
+// An empty string leaves the argument at that position uncompleted.
+var argCompletions = map[string][]string{
+	"bitrot enable":           {completeVolumes},
+	"bitrot disable":          {completeVolumes},
+	"bitrot scrub-throttle":   {completeVolumes},
+	"bitrot scrub-freq":       {completeVolumes},
+	"bitrot scrub":            {completeVolumes},
+	"device add":              {completePeers},
+	"device info":             {completePeers},
+	"geo-replication create":  {completeVolumes},
+	"geo-replication start":   {completeVolumes},
+	"geo-replication stop":    {completeVolumes},
+	"geo-replication pause":   {completeVolumes},
+	"geo-replication resume":  {completeVolumes},
+	"geo-replication delete":  {completeVolumes},
+	"geo-replication status":  {completeVolumes},
+	"geo-replication get":     {completeVolumes},
+	"geo-replication set":     {completeVolumes},
+	"geo-replication reset":   {completeVolumes},
+	"peer remove":             {completePeers},
+	"profile remove":          {completeProfiles},
+	"profile set":             {completeProfiles},
+	"profile use":             {completeProfiles},
+	"snapshot create":         {"", completeVolumes},
+	"snapshot delete all":     {completeVolumes},
+	"snapshot list":           {completeVolumes},
+	"volume add-brick":        {completeVolumes},
+	"volume delete":           {completeVolumes},
+	"volume edit-metadata":    {completeVolumes},
+	"volume get":              {completeVolumes},
+	"volume heal info":        {completeVolumes},
+	"volume heal index":       {completeVolumes},
+	"volume heal full":        {completeVolumes},
+	"volume heal split-brain": {completeVolumes},
+	"volume info":             {completeVolumes},
+	"volume profile info":     {completeVolumes},
+	"volume reset":            {completeVolumes},
+	"volume set":              {completeVolumes},
+	"volume size":             {completeVolumes},
+	"volume start":            {completeVolumes},
+	"volume statedump":        {completeVolumes},
+	"volume status":           {completeVolumes},
+	"volume stop":             {completeVolumes},
+}
+
+func init() {
+	completionCmd.AddCommand(completionNamesCmd)
+}
+
+var completionCmd = &cobra.Command{
+	Use:       "completion {bash|zsh|fish}",
+	Short:     helpCompletionCmd,
+	Long:      helpCompletionLong,
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: []string{"bash", "zsh", "fish"},
+	// The completion script is generated without contacting glusterd2
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {},
+	Run: func(cmd *cobra.Command, args []string) {
+		var err error
+		root := cmd.Root()
+		switch args[0] {
+		case "bash":
+			err = root.GenBashCompletion(os.Stdout)
+		case "zsh":
+			err = genZshCompletion(root, os.Stdout)
+		case "fish":
+			err = genFishCompletion(root, os.Stdout)
+		default:
+			err = fmt.Errorf("unsupported shell %q, expected one of bash, zsh or fish", args[0])
+		}
+		if err != nil {
+			failure("Failed to generate completion script", err, 1)
+		}
+	},
+}
+
+// completionNamesCmd prints the names of volumes, peers or profiles, one per
+// line, for use by the completion scripts. Peers are printed as the ID and
+// name separated by a tab.
+var completionNamesCmd = &cobra.Command{
+	Use:       "names {volumes|peers|profiles}",
+	Hidden:    true,
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: []string{completeVolumes, completePeers, completeProfiles},
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		if len(args) > 0 && args[0] == completeProfiles {
+			return
+		}
+		GlobalFlag.Init()
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		switch args[0] {
+		case completeProfiles:
+			conf, _ := mustLoadCLIConfig()
+			names := make([]string, 0, len(conf.Profiles))
+			for name := range conf.Profiles {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				fmt.Println(name)
+			}
+		case completeVolumes:
+			vols, err := client.Volumes("")
+			if err != nil {
+				os.Exit(1)
+			}
+			for _, vol := range vols {
+				fmt.Println(vol.Name)
+			}
+		case completePeers:
+			peers, err := client.Peers()
+			if err != nil {
+				os.Exit(1)
+			}
+			for _, peer := range peers {
+				fmt.Printf("%s\t%s\n", peer.ID, peer.Name)
+			}
+		}
+	},
+}
+
+// commandPath returns the path of the command without the root command
+func commandPath(cmd *cobra.Command) string {
+	return strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" ")
+}
+
+// bashCompletionFunc returns the custom bash function called by the
+// completion script generated by cobra, when there are no subcommands or
+// flags to complete. It completes the names of volumes, peers and profiles.
+func bashCompletionFunc(root *cobra.Command) string {
+	name := root.Name()
+	cases := make(map[string][]string)
+	for path, kinds := range argCompletions {
+		for pos, kind := range kinds {
+			if kind == "" {
+				continue
+			}
+			// cobra names the bash functions of the commands after
+			// their path, joined by underscores
+			fn := name + "_" + strings.Replace(path, " ", "_", -1)
+			cases[kind] = append(cases[kind], fmt.Sprintf("%s_%d", fn, pos))
+		}
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, `__%[1]s_get_names()
+{
+    local endpoints=() i
+    for ((i = 1; i < ${#words[@]}; i++)); do
+        case "${words[i]}" in
+            --endpoints=*|--profile=*) endpoints+=("${words[i]}") ;;
+            --endpoints|--profile) endpoints+=("${words[i]}=${words[i+1]}") ;;
+        esac
+    done
+    local out
+    if out=$(%[1]s "${endpoints[@]}" --timeout=5 completion names "$1" 2>/dev/null); then
+        COMPREPLY=( $(compgen -W "$(cut -f1 <<< "${out}")" -- "$cur") )
+    fi
+}
+
+__%[1]s_custom_func()
+{
+    case "${last_command}_${#nouns[@]}" in
+`, name)
+	for _, kind := range []string{completeVolumes, completePeers, completeProfiles} {
+		sort.Strings(cases[kind])
+		fmt.Fprintf(&b, "        %s)\n            __%s_get_names %s\n            return\n            ;;\n",
+			strings.Join(cases[kind], "|"), name, kind)
+	}
+	b.WriteString("        *)\n            ;;\n    esac\n}\n")
+	return b.String()
+}
+
+// genZshCompletion writes a zsh completion script, which loads the bash
+// completion script through zsh's bash completion emulation
+func genZshCompletion(root *cobra.Command, w io.Writer) error {
+	if _, err := fmt.Fprintf(w, "#compdef %s\n\nautoload -U +X bashcompinit && bashcompinit\n\n", root.Name()); err != nil {
+		return err
+	}
+	return root.GenBashCompletion(w)
+}
+
+// fishQuote quotes s as a single argument to a fish command
+func fishQuote(s string) string {
+	return "'" + strings.Replace(strings.Replace(s, `\`, `\\`, -1), "'", `\'`, -1) + "'"
+}
+
+// genFishFlags writes the completions of the flags in fs, offered when the
+// command line is at the command path, or anywhere if path is empty
+func genFishFlags(w io.Writer, name, path string, fs *pflag.FlagSet) {
+	fs.VisitAll(func(f *pflag.Flag) {
+		if f.Hidden || f.Deprecated != "" {
+			return
+		}
+		line := "complete -c " + name
+		if path != "" {
+			line += " -n " + fishQuote("__"+name+"_in "+path)
+		}
+		line += " -l " + f.Name
+		if f.Shorthand != "" {
+			line += " -s " + f.Shorthand
+		}
+		if f.Value.Type() != "bool" {
+			line += " -r"
+		}
+		fmt.Fprintf(w, "%s -d %s\n", line, fishQuote(f.Usage))
+	})
+}
+
+// valueFlags returns the flags of the command tree which take a value, in
+// the form expected on the command line
+func valueFlags(root *cobra.Command) []string {
+	seen := make(map[string]bool)
+	var visit func(*cobra.Command)
+	add := func(f *pflag.Flag) {
+		if f.Value.Type() == "bool" {
+			return
+		}
+		seen["--"+f.Name] = true
+		if f.Shorthand != "" {
+			seen["-"+f.Shorthand] = true
+		}
+	}
+	visit = func(cmd *cobra.Command) {
+		cmd.LocalFlags().VisitAll(add)
+		for _, c := range cmd.Commands() {
+			visit(c)
+		}
+	}
+	root.PersistentFlags().VisitAll(add)
+	visit(root)
+
+	flags := make([]string, 0, len(seen))
+	for f := range seen {
+		flags = append(flags, f)
+	}
+	sort.Strings(flags)
+	return flags
+}
+
+// genFishCompletion writes a fish completion script for the command tree
+func genFishCompletion(root *cobra.Command, w io.Writer) error {
+	name := root.Name()
+	var b bytes.Buffer
+
+	fmt.Fprintf(&b, `# fish completion for %[1]s
+
+# __%[1]s_args prints the words of the command line which are not flags or
+# values of flags
+function __%[1]s_args
+    set -l words (commandline -opc)
+    set -e words[1]
+    set -l skip 0
+    for w in $words
+        if test $skip -eq 1
+            set skip 0
+            continue
+        end
+        switch $w
+            case %[2]s
+                set skip 1
+            case '-*'
+            case '*'
+                echo $w
+        end
+    end
+end
+
+# __%[1]s_at tests if the command line is at the command given by the
+# arguments after the first, followed by as many arguments as the first
+function __%[1]s_at
+    set -l n $argv[1]
+    set -e argv[1]
+    set -l args (__%[1]s_args)
+    test (count $args) -eq (math (count $argv) + $n); or return 1
+    for i in (seq (count $argv))
+        test "$args[$i]" = "$argv[$i]"; or return 1
+    end
+end
+
+# __%[1]s_in tests if the command line is within the command given by the
+# arguments
+function __%[1]s_in
+    set -l args (__%[1]s_args)
+    test (count $args) -ge (count $argv); or return 1
+    for i in (seq (count $argv))
+        test "$args[$i]" = "$argv[$i]"; or return 1
+    end
+end
+
+function __%[1]s_names
+    set -l endpoints
+    set -l words (commandline -opc)
+    for i in (seq (count $words))
+        switch $words[$i]
+            case '--endpoints=*' '--profile=*'
+                set endpoints $endpoints $words[$i]
+            case --endpoints --profile
+                set endpoints $endpoints $words[$i]=$words[(math $i + 1)]
+        end
+    end
+    %[1]s $endpoints --timeout=5 completion names $argv[1] 2>/dev/null
+end
+
+complete -c %[1]s -f
+`, name, strings.Join(valueFlags(root), " "))
+
+	genFishFlags(&b, name, "", root.PersistentFlags())
+
+	var visit func(*cobra.Command)
+	visit = func(cmd *cobra.Command) {
+		path := ""
+		if cmd != root {
+			path = commandPath(cmd)
+			genFishFlags(&b, name, path, cmd.LocalNonPersistentFlags())
+		}
+		for _, c := range cmd.Commands() {
+			if !c.IsAvailableCommand() {
+				continue
+			}
+			fmt.Fprintf(&b, "complete -c %s -n %s -a %s -d %s\n", name,
+				fishQuote(strings.TrimSpace("__"+name+"_at 0 "+path)), c.Name(), fishQuote(c.Short))
+			visit(c)
+		}
+		if len(cmd.ValidArgs) > 0 && path != "" {
+			fmt.Fprintf(&b, "complete -c %s -n %s -a %s\n", name,
+				fishQuote("__"+name+"_at 0 "+path), fishQuote(strings.Join(cmd.ValidArgs, " ")))
+		}
+		for pos, kind := range argCompletions[path] {
+			if kind == "" {
+				continue
+			}
+			fmt.Fprintf(&b, "complete -c %s -n %s -a %s\n", name,
+				fishQuote(fmt.Sprintf("__%s_at %d %s", name, pos, path)),
+				fishQuote(fmt.Sprintf("(__%s_names %s)", name, kind)))
+		}
+	}
+	visit(root)
+
+	_, err := b.WriteTo(w)
+	return err
+}
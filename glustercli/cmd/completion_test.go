@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestArgCompletions checks if the commands whose arguments are completed
+// exist
+func TestArgCompletions(t *testing.T) {
+	root := NewGlustercliCmd()
+	for path := range argCompletions {
+		cmd, _, err := root.Find(strings.Fields(path))
+		assert.Nil(t, err, path)
+		assert.Equal(t, path, commandPath(cmd))
+	}
+}
+
+func TestBashCompletionFunc(t *testing.T) {
+	f := bashCompletionFunc(NewGlustercliCmd())
+	assert.Contains(t, f, "__glustercli_custom_func()")
+	assert.Contains(t, f, "glustercli_volume_start_0")
+	assert.Contains(t, f, "glustercli_snapshot_create_1")
+	assert.NotContains(t, f, "glustercli_snapshot_create_0")
+	assert.Contains(t, f, "glustercli_peer_remove_0")
+	assert.Contains(t, f, "glustercli_profile_use_0")
+}
+
+func TestGenFishCompletion(t *testing.T) {
+	var b bytes.Buffer
+	assert.Nil(t, genFishCompletion(NewGlustercliCmd(), &b))
+	out := b.String()
+	assert.Contains(t, out, "complete -c glustercli -n '__glustercli_at 0 volume' -a start")
+	assert.Contains(t, out, "complete -c glustercli -n '__glustercli_at 0 volume start' -a '(__glustercli_names volumes)'")
+	assert.Contains(t, out, "complete -c glustercli -l output -s o -r")
+	assert.NotContains(t, out, " -a names ")
+}
@@ -2,11 +2,9 @@ package cmd
 
 import (
 	"fmt"
-	"os"
 
 	"github.com/gluster/glusterd2/plugins/device/api"
 
-	"github.com/olekukonko/tablewriter"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
@@ -48,6 +46,8 @@ var deviceInfoCmd = &cobra.Command{
 				}
 				failure("Failed to get peer list", err, 1)
 			}
+			// devices of all the peers keyed by peer ID
+			devices := make(map[string][]api.Info)
 			for _, peer := range peers {
 				peerID := peer.ID.String()
 				deviceList, err := client.DeviceList(peerID, "")
@@ -60,8 +60,13 @@ var deviceInfoCmd = &cobra.Command{
 				if len(deviceList) == 0 {
 					continue
 				}
+				if isStructuredOutput() {
+					devices[peerID] = deviceList
+					continue
+				}
 				deviceListDisplay(peerID, peer.Name, deviceList)
 			}
+			printStructured(devices)
 			return
 		}
 
@@ -80,6 +85,9 @@ var deviceInfoCmd = &cobra.Command{
 			}
 			failure("Device list failed", err, 1)
 		}
+		if printStructured(deviceList) {
+			return
+		}
 		if len(deviceList) == 0 {
 			fmt.Println("No devices are associated with given peer")
 			return
@@ -92,7 +100,7 @@ func deviceListDisplay(peerID, peerName string, deviceList []api.Info) {
 	fmt.Println()
 	fmt.Println("Peer Name:", peerName)
 	fmt.Println("Peer ID:", peerID)
-	table := tablewriter.NewWriter(os.Stdout)
+	table := newTable()
 	table.SetHeader([]string{"Device", "State", "Total Size", "Free Size", "Used Size", "Used %"})
 	for _, d := range deviceList {
 		var usedPer float64
@@ -114,7 +122,7 @@ var deviceAddCmd = &cobra.Command{
 		peerid := args[0]
 		devname := args[1]
 
-		_, err := client.DeviceAdd(peerid, devname, flagDeviceAddProvisioner)
+		resp, err := client.DeviceAdd(peerid, devname, flagDeviceAddProvisioner)
 
 		if err != nil {
 			if GlobalFlag.Verbose {
@@ -125,6 +133,9 @@ var deviceAddCmd = &cobra.Command{
 			}
 			failure("Device add failed", err, 1)
 		}
+		if printStructured(resp) {
+			return
+		}
 		fmt.Println("Device add successful")
 	},
 }
@@ -3,6 +3,10 @@ package cmd
 import (
 	"fmt"
 	"strings"
+	"time"
+
+	"github.com/gluster/glusterd2/pkg/api"
+	eventsapi "github.com/gluster/glusterd2/plugins/events/api"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -13,12 +17,44 @@ const (
 	helpEventsWebhookAddCmd    = ""
 	helpEventsWebhookDeleteCmd = ""
 	helpEventsWebhookListCmd   = ""
+	helpEventsSMTPAddCmd       = "Add a SMTP sink which mails events to the given recipients"
+	helpEventsSMTPDeleteCmd    = "Delete a SMTP sink"
+	helpEventsSMTPListCmd      = "List SMTP sinks"
+	helpEventsSyslogAddCmd     = "Add a syslog sink which forwards events to syslog"
+	helpEventsSyslogDeleteCmd  = "Delete a syslog sink"
+	helpEventsSyslogListCmd    = "List syslog sinks"
+	helpEventsThresholdAddCmd  = "Add an alerting threshold"
+	helpEventsThresholdDelCmd  = "Delete an alerting threshold"
+	helpEventsThresholdListCmd = "List alerting thresholds"
+	helpEventsAlertsCmd        = "List currently firing alerts"
 )
 
 var (
 	// Create Command Flags
 	flagWebhookAddCmdToken  string
 	flagWebhookAddCmdSecret string
+
+	flagSMTPAddCmdUser         string
+	flagSMTPAddCmdPasswordFile string
+	flagSMTPAddCmdFrom         string
+	flagSMTPAddCmdSubject      string
+	flagSMTPAddCmdTemplate     string
+	flagSMTPAddCmdEvents       []string
+	flagSMTPAddCmdSeverity     []string
+
+	flagSyslogAddCmdNetwork  string
+	flagSyslogAddCmdAddress  string
+	flagSyslogAddCmdTag      string
+	flagSyslogAddCmdFacility string
+	flagSyslogAddCmdEvents   []string
+	flagSyslogAddCmdSeverity []string
+
+	flagThresholdAddCmdKey        string
+	flagThresholdAddCmdOperator   string
+	flagThresholdAddCmdValue      float64
+	flagThresholdAddCmdClearEvent string
+	flagThresholdAddCmdScope      string
+	flagThresholdAddCmdSeverity   string
 )
 
 func init() {
@@ -30,6 +66,46 @@ func init() {
 	eventsCmd.AddCommand(eventsWebhookDeleteCmd)
 
 	eventsCmd.AddCommand(eventsWebhookListCmd)
+
+	eventsSMTPAddCmd.Flags().StringVar(&flagSMTPAddCmdUser, "user", "", "SMTP username")
+	eventsSMTPAddCmd.Flags().StringVar(&flagSMTPAddCmdPasswordFile, "password-file", "", "File holding the SMTP password on the peers")
+	eventsSMTPAddCmd.Flags().StringVar(&flagSMTPAddCmdFrom, "from", "", "Sender address")
+	eventsSMTPAddCmd.Flags().StringVar(&flagSMTPAddCmdSubject, "subject", "", "Subject template")
+	eventsSMTPAddCmd.Flags().StringVar(&flagSMTPAddCmdTemplate, "template", "", "Body template")
+	eventsSMTPAddCmd.Flags().StringSliceVar(&flagSMTPAddCmdEvents, "events", nil, "Events to be mailed (default all)")
+	eventsSMTPAddCmd.Flags().StringSliceVar(&flagSMTPAddCmdSeverity, "severities", nil, "Severities of events to be mailed (default all)")
+	eventsCmd.AddCommand(eventsSMTPAddCmd)
+	eventsCmd.AddCommand(eventsSMTPDeleteCmd)
+	eventsCmd.AddCommand(eventsSMTPListCmd)
+
+	eventsSyslogAddCmd.Flags().StringVar(&flagSyslogAddCmdNetwork, "network", "", "Network of remote syslog server (udp or tcp)")
+	eventsSyslogAddCmd.Flags().StringVar(&flagSyslogAddCmdAddress, "address", "", "Address of remote syslog server (default local syslog)")
+	eventsSyslogAddCmd.Flags().StringVar(&flagSyslogAddCmdTag, "tag", "", "Syslog tag")
+	eventsSyslogAddCmd.Flags().StringVar(&flagSyslogAddCmdFacility, "facility", "", "Syslog facility")
+	eventsSyslogAddCmd.Flags().StringSliceVar(&flagSyslogAddCmdEvents, "events", nil, "Events to be forwarded (default all)")
+	eventsSyslogAddCmd.Flags().StringSliceVar(&flagSyslogAddCmdSeverity, "severities", nil, "Severities of events to be forwarded (default all)")
+	eventsCmd.AddCommand(eventsSyslogAddCmd)
+	eventsCmd.AddCommand(eventsSyslogDeleteCmd)
+	eventsCmd.AddCommand(eventsSyslogListCmd)
+
+	eventsThresholdAddCmd.Flags().StringVar(&flagThresholdAddCmdKey, "key", "", "Event data key holding the value to compare")
+	eventsThresholdAddCmd.Flags().StringVar(&flagThresholdAddCmdOperator, "operator", ">", "Comparison operator (>, >=, <, <=, ==, !=)")
+	eventsThresholdAddCmd.Flags().Float64Var(&flagThresholdAddCmdValue, "value", 0, "Value to compare with")
+	eventsThresholdAddCmd.Flags().StringVar(&flagThresholdAddCmdClearEvent, "clear-event", "", "Event which resolves the alert")
+	eventsThresholdAddCmd.Flags().StringVar(&flagThresholdAddCmdScope, "scope", "", "Event data key to track separate alerts for, e.g. volume.name")
+	eventsThresholdAddCmd.Flags().StringVar(&flagThresholdAddCmdSeverity, "severity", string(api.SeverityWarning), "Severity of the alert (info, warning or critical)")
+	eventsCmd.AddCommand(eventsThresholdAddCmd)
+	eventsCmd.AddCommand(eventsThresholdDelCmd)
+	eventsCmd.AddCommand(eventsThresholdListCmd)
+	eventsCmd.AddCommand(eventsAlertsCmd)
+}
+
+func toSeverities(severities []string) []api.EventSeverity {
+	var out []api.EventSeverity
+	for _, s := range severities {
+		out = append(out, api.EventSeverity(s))
+	}
+	return out
 }
 
 var eventsCmd = &cobra.Command{
@@ -50,7 +126,7 @@ var eventsWebhookAddCmd = &cobra.Command{
 			}
 			failure("Failed to add Webhook", err, 1)
 		}
-		fmt.Printf("Webhook %s added successfully\n", url)
+		printMessagef("Webhook %s added successfully\n", url)
 	},
 }
 
@@ -67,7 +143,7 @@ var eventsWebhookDeleteCmd = &cobra.Command{
 			}
 			failure("Failed to delete Webhook", err, 1)
 		}
-		fmt.Printf("Webhook %s deleted successfully\n", url)
+		printMessagef("Webhook %s deleted successfully\n", url)
 	},
 }
 
@@ -84,8 +160,243 @@ var eventsWebhookListCmd = &cobra.Command{
 			failure("Failed to get list of registered Webhooks", err, 1)
 		}
 
+		if printStructured(webhooks) {
+			return
+		}
 		if len(webhooks) > 0 {
 			fmt.Printf("Webhooks:\n%s\n", strings.Join(webhooks, "\n"))
 		}
 	},
 }
+
+var eventsSMTPAddCmd = &cobra.Command{
+	Use:   "smtp-add [flags] <NAME> <SERVER:PORT> <TO> [<TO>]...",
+	Short: helpEventsSMTPAddCmd,
+	Args:  cobra.MinimumNArgs(3),
+	Run: func(cmd *cobra.Command, args []string) {
+		req := eventsapi.SMTPSink{
+			Name:         args[0],
+			Server:       args[1],
+			To:           args[2:],
+			Username:     flagSMTPAddCmdUser,
+			PasswordFile: flagSMTPAddCmdPasswordFile,
+			From:         flagSMTPAddCmdFrom,
+			Subject:      flagSMTPAddCmdSubject,
+			Template:     flagSMTPAddCmdTemplate,
+			Events:       flagSMTPAddCmdEvents,
+			Severities:   toSeverities(flagSMTPAddCmdSeverity),
+		}
+		if err := client.SMTPSinkAdd(req); err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).WithField("name", req.Name).Error("failed to add SMTP sink")
+			}
+			failure("Failed to add SMTP sink", err, 1)
+		}
+		printMessagef("SMTP sink %s added successfully\n", req.Name)
+	},
+}
+
+var eventsSMTPDeleteCmd = &cobra.Command{
+	Use:   "smtp-del <NAME>",
+	Short: helpEventsSMTPDeleteCmd,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name := args[0]
+		if err := client.SMTPSinkDelete(name); err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).WithField("name", name).Error("failed to delete SMTP sink")
+			}
+			failure("Failed to delete SMTP sink", err, 1)
+		}
+		printMessagef("SMTP sink %s deleted successfully\n", name)
+	},
+}
+
+var eventsSMTPListCmd = &cobra.Command{
+	Use:   "smtp-list",
+	Short: helpEventsSMTPListCmd,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		sinks, err := client.SMTPSinks()
+		if err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).Error("failed to list SMTP sinks")
+			}
+			failure("Failed to get list of SMTP sinks", err, 1)
+		}
+
+		if printStructured(sinks) {
+			return
+		}
+
+		table := newTable()
+		table.SetHeader([]string{"Name", "Server", "From", "To", "Events"})
+		for _, s := range sinks {
+			table.Append([]string{s.Name, s.Server, s.From, strings.Join(s.To, "\n"), strings.Join(s.Events, "\n")})
+		}
+		table.Render()
+	},
+}
+
+var eventsSyslogAddCmd = &cobra.Command{
+	Use:   "syslog-add [flags] <NAME>",
+	Short: helpEventsSyslogAddCmd,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		req := eventsapi.SyslogSink{
+			Name:       args[0],
+			Network:    flagSyslogAddCmdNetwork,
+			Address:    flagSyslogAddCmdAddress,
+			Tag:        flagSyslogAddCmdTag,
+			Facility:   flagSyslogAddCmdFacility,
+			Events:     flagSyslogAddCmdEvents,
+			Severities: toSeverities(flagSyslogAddCmdSeverity),
+		}
+		if err := client.SyslogSinkAdd(req); err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).WithField("name", req.Name).Error("failed to add syslog sink")
+			}
+			failure("Failed to add syslog sink", err, 1)
+		}
+		printMessagef("Syslog sink %s added successfully\n", req.Name)
+	},
+}
+
+var eventsSyslogDeleteCmd = &cobra.Command{
+	Use:   "syslog-del <NAME>",
+	Short: helpEventsSyslogDeleteCmd,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name := args[0]
+		if err := client.SyslogSinkDelete(name); err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).WithField("name", name).Error("failed to delete syslog sink")
+			}
+			failure("Failed to delete syslog sink", err, 1)
+		}
+		printMessagef("Syslog sink %s deleted successfully\n", name)
+	},
+}
+
+var eventsSyslogListCmd = &cobra.Command{
+	Use:   "syslog-list",
+	Short: helpEventsSyslogListCmd,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		sinks, err := client.SyslogSinks()
+		if err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).Error("failed to list syslog sinks")
+			}
+			failure("Failed to get list of syslog sinks", err, 1)
+		}
+
+		if printStructured(sinks) {
+			return
+		}
+
+		table := newTable()
+		table.SetHeader([]string{"Name", "Network", "Address", "Tag", "Facility", "Events"})
+		for _, s := range sinks {
+			table.Append([]string{s.Name, s.Network, s.Address, s.Tag, s.Facility, strings.Join(s.Events, "\n")})
+		}
+		table.Render()
+	},
+}
+
+var eventsThresholdAddCmd = &cobra.Command{
+	Use:   "threshold-add [flags] <NAME> <EVENT>",
+	Short: helpEventsThresholdAddCmd,
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		req := eventsapi.Threshold{
+			Name:       args[0],
+			Event:      args[1],
+			Key:        flagThresholdAddCmdKey,
+			Operator:   flagThresholdAddCmdOperator,
+			Value:      flagThresholdAddCmdValue,
+			ClearEvent: flagThresholdAddCmdClearEvent,
+			Scope:      flagThresholdAddCmdScope,
+			Severity:   api.EventSeverity(flagThresholdAddCmdSeverity),
+		}
+		if err := client.ThresholdAdd(req); err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).WithField("name", req.Name).Error("failed to add threshold")
+			}
+			failure("Failed to add threshold", err, 1)
+		}
+		printMessagef("Threshold %s added successfully\n", req.Name)
+	},
+}
+
+var eventsThresholdDelCmd = &cobra.Command{
+	Use:   "threshold-del <NAME>",
+	Short: helpEventsThresholdDelCmd,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name := args[0]
+		if err := client.ThresholdDelete(name); err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).WithField("name", name).Error("failed to delete threshold")
+			}
+			failure("Failed to delete threshold", err, 1)
+		}
+		printMessagef("Threshold %s deleted successfully\n", name)
+	},
+}
+
+var eventsThresholdListCmd = &cobra.Command{
+	Use:   "threshold-list",
+	Short: helpEventsThresholdListCmd,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		thresholds, err := client.Thresholds()
+		if err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).Error("failed to list thresholds")
+			}
+			failure("Failed to get list of thresholds", err, 1)
+		}
+
+		if printStructured(thresholds) {
+			return
+		}
+
+		table := newTable()
+		table.SetHeader([]string{"Name", "Event", "Condition", "Clear Event", "Scope", "Severity"})
+		for _, t := range thresholds {
+			var condition string
+			if t.Key != "" {
+				condition = fmt.Sprintf("%s %s %v", t.Key, t.Operator, t.Value)
+			}
+			table.Append([]string{t.Name, t.Event, condition, t.ClearEvent, t.Scope, string(t.Severity)})
+		}
+		table.Render()
+	},
+}
+
+var eventsAlertsCmd = &cobra.Command{
+	Use:   "alerts",
+	Short: helpEventsAlertsCmd,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		alerts, err := client.Alerts()
+		if err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).Error("failed to list alerts")
+			}
+			failure("Failed to get list of alerts", err, 1)
+		}
+
+		if printStructured(alerts) {
+			return
+		}
+
+		table := newTable()
+		table.SetHeader([]string{"Threshold", "Scope", "Severity", "Event", "Value", "Since"})
+		for _, a := range alerts {
+			table.Append([]string{a.Threshold, a.Scope, string(a.Severity), a.Event, a.Value, a.Since.Format(time.RFC3339)})
+		}
+		table.Render()
+	},
+}
@@ -55,6 +55,7 @@ const (
 var (
 	flagGeorepCmdForce        bool
 	flagGeorepShowAllConfig   bool
+	flagGeorepStatusDetail    bool
 	flagGeorepRemoteEndpoints string
 	flagRemoteUser            string
 	flagRemoteSecret          string
@@ -97,6 +98,7 @@ func init() {
 	georepCmd.AddCommand(georepResumeCmd)
 
 	// Geo-rep Status
+	georepStatusCmd.Flags().BoolVar(&flagGeorepStatusDetail, "detail", false, "Show the status of each worker")
 	georepCmd.AddCommand(georepStatusCmd)
 
 	// Geo-rep Config
@@ -220,7 +222,7 @@ var georepCreateCmd = &cobra.Command{
 			failure(errGeorepSessionCreationFailed+errGeorepSSHKeysGenerate, err, 1)
 		}
 
-		_, err = client.GeorepCreate(masterdata.id, remotevoldata.id, georepapi.GeorepCreateReq{
+		session, err := client.GeorepCreate(masterdata.id, remotevoldata.id, georepapi.GeorepCreateReq{
 			MasterVol:   volname,
 			RemoteUser:  remoteuser,
 			RemoteHosts: remotevoldata.nodes,
@@ -246,6 +248,9 @@ var georepCreateCmd = &cobra.Command{
 			failure(errGeorepSSHKeysPush, err, 1)
 		}
 
+		if printStructured(session) {
+			return
+		}
 		fmt.Println("Geo-replication session created successfully")
 	},
 }
@@ -281,15 +286,16 @@ func handleGeorepAction(args []string, action georepAction) {
 	if err != nil {
 		failure(fmt.Sprintf("Geo-replication %s failed.\n", action.String()), err, 1)
 	}
+	var session georepapi.GeorepSession
 	switch action {
 	case georepStart:
-		_, err = client.GeorepStart(masterVolID, remoteVolID, flagGeorepCmdForce)
+		session, err = client.GeorepStart(masterVolID, remoteVolID, flagGeorepCmdForce)
 	case georepStop:
-		_, err = client.GeorepStop(masterVolID, remoteVolID, flagGeorepCmdForce)
+		session, err = client.GeorepStop(masterVolID, remoteVolID, flagGeorepCmdForce)
 	case georepPause:
-		_, err = client.GeorepPause(masterVolID, remoteVolID, flagGeorepCmdForce)
+		session, err = client.GeorepPause(masterVolID, remoteVolID, flagGeorepCmdForce)
 	case georepResume:
-		_, err = client.GeorepResume(masterVolID, remoteVolID, flagGeorepCmdForce)
+		session, err = client.GeorepResume(masterVolID, remoteVolID, flagGeorepCmdForce)
 	case georepDelete:
 		err = client.GeorepDelete(masterVolID, remoteVolID, flagGeorepCmdForce)
 	}
@@ -300,7 +306,10 @@ func handleGeorepAction(args []string, action georepAction) {
 		}
 		failure(fmt.Sprintf("Geo-replication %s failed", action.String()), err, 1)
 	}
-	fmt.Println("Geo-replication session", action.String(), "successful")
+	if action != georepDelete && printStructured(session) {
+		return
+	}
+	printMessage("Geo-replication session", action.String(), "successful")
 }
 
 var georepStartCmd = &cobra.Command{
@@ -449,6 +458,19 @@ func getVolIDs(pargs []string) (string, string, error) {
 	return masterVolID, remoteVolID, nil
 }
 
+// georepSessionStatus returns the status of the session, along with the
+// status of each of its workers if asked for
+func georepSessionStatus(masterVolID, remoteVolID string) (georepapi.GeorepSession, error) {
+	if flagGeorepStatusDetail {
+		return client.GeorepStatusDetail(masterVolID, remoteVolID)
+	}
+	sessions, err := client.GeorepStatus(masterVolID, remoteVolID)
+	if err != nil {
+		return georepapi.GeorepSession{}, err
+	}
+	return sessions[0], nil
+}
+
 var georepStatusCmd = &cobra.Command{
 	Use:   "status [<master-volume> [[<remote-user>@]<remote-host>::<remote-volume>]]",
 	Short: helpGeorepStatusCmd,
@@ -474,17 +496,22 @@ var georepStatusCmd = &cobra.Command{
 				if remoteVolID != "" && s.RemoteID.String() != remoteVolID {
 					continue
 				}
-				sessionDetail, err := client.GeorepStatus(s.MasterID.String(), s.RemoteID.String())
+				sessionDetail, err := georepSessionStatus(s.MasterID.String(), s.RemoteID.String())
 				if err != nil {
 					failure(errGeorepStatusCommandFailed, err, 1)
 				}
-				sessions = append(sessions, sessionDetail[0])
+				sessions = append(sessions, sessionDetail)
 			}
 		} else {
-			sessions, err = client.GeorepStatus(masterVolID, remoteVolID)
+			session, err := georepSessionStatus(masterVolID, remoteVolID)
 			if err != nil {
 				failure(errGeorepStatusCommandFailed, err, 1)
 			}
+			sessions = georepapi.GeorepSessionList{session}
+		}
+
+		if printStructured(sessions) {
+			return
 		}
 
 		for _, session := range sessions {
@@ -496,10 +523,14 @@ var georepStatusCmd = &cobra.Command{
 				session.RemoteVol,
 				session.Status,
 			)
+			if s := session.Summary; s != nil {
+				fmt.Printf("WORKERS: %d  ACTIVE: %d  PASSIVE: %d  FAULTY: %d  INITIALIZING: %d  UNKNOWN: %d  MAX LAG: %ds\n",
+					s.Workers, s.Active, s.Passive, s.Faulty, s.Initializing, s.Unknown, s.MaxLagSeconds)
+			}
 
 			// Status Detail
 			if len(session.Workers) > 0 {
-				table := tablewriter.NewWriter(os.Stdout)
+				table := newTable()
 				table.SetHeader([]string{"Master Brick", "Status", "Crawl Status", "Remote Node", "Last Synced", "Checkpoint Time", "Checkpoint Completion Time"})
 				for _, worker := range session.Workers {
 					table.Append([]string{
@@ -536,7 +567,18 @@ var georepGetCmd = &cobra.Command{
 			failure("Error getting Options", err, 1)
 		}
 
-		table := tablewriter.NewWriter(os.Stdout)
+		if isStructuredOutput() {
+			var selected []georepapi.GeorepOption
+			for _, opt := range opts {
+				if opt.Modified || flagGeorepShowAllConfig {
+					selected = append(selected, opt)
+				}
+			}
+			printStructured(selected)
+			return
+		}
+
+		table := newTable()
 		table.SetAlignment(tablewriter.ALIGN_LEFT)
 		if len(opts) > 0 {
 			table.SetHeader([]string{"Name", "Value", "Default Value"})
@@ -564,7 +606,7 @@ var georepGetCmd = &cobra.Command{
 
 		// Other Configs
 		if flagGeorepShowAllConfig {
-			table = tablewriter.NewWriter(os.Stdout)
+			table = newTable()
 			table.SetAlignment(tablewriter.ALIGN_LEFT)
 			table.SetHeader([]string{"Name", "Value"})
 			numOpts = 0
@@ -609,7 +651,7 @@ var georepSetCmd = &cobra.Command{
 		if err != nil {
 			failure("Geo-replication session config set failed", err, 1)
 		}
-		fmt.Println("Geo-replication session config set successfully")
+		printMessage("Geo-replication session config set successfully")
 	},
 }
 
@@ -628,6 +670,6 @@ var georepResetCmd = &cobra.Command{
 		if err != nil {
 			failure("Geo-replication session config reset failed", err, 1)
 		}
-		fmt.Println("Geo-replication session config reset successfully")
+		printMessage("Geo-replication session config reset successfully")
 	},
 }
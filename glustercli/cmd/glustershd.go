@@ -67,6 +67,9 @@ var selfHealInfoCmd = &cobra.Command{
 			}
 			failure(fmt.Sprintf("Failed to get heal info for volume %s\n", volname), err, 1)
 		}
+		if printStructured(selfHealInfo) {
+			return
+		}
 		for index := range selfHealInfo {
 			fmt.Printf("Brick: %s\n", selfHealInfo[index].Name)
 			fmt.Printf("Status: %s\n", selfHealInfo[index].Status)
@@ -108,7 +111,7 @@ var selfHealIndexCmd = &cobra.Command{
 		if err != nil {
 			failure(fmt.Sprintf("Failed to run heal for volume %s\n", volname), err, 1)
 		}
-		fmt.Println("Heal on volume has been successfully launched. Use heal info to check status")
+		printMessage("Heal on volume has been successfully launched. Use heal info to check status")
 	},
 }
 
@@ -124,7 +127,7 @@ var selfHealFullCmd = &cobra.Command{
 		if err != nil {
 			failure(fmt.Sprintf("Failed to run heal for volume %s\n", volname), err, 1)
 		}
-		fmt.Println("Heal on volume has been successfully launched. Use heal info to check status")
+		printMessage("Heal on volume has been successfully launched. Use heal info to check status")
 	},
 }
 
@@ -171,6 +174,6 @@ var selfHealSplitBrainCmd = &cobra.Command{
 			}
 			failure(fmt.Sprintf("Failed to resolve split-brain for volume %s\n", volname), err, 1)
 		}
-		fmt.Printf("Split Brain Resolution successful on volume %s \n", volname)
+		printMessagef("Split Brain Resolution successful on volume %s \n", volname)
 	},
 }
@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gluster/glusterd2/pkg/api"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+const (
+	helpHookCmd        = "Gluster Hooks"
+	helpHookAddCmd     = "Register a script or a webhook run at a point of the lifecycle of the volumes"
+	helpHookDeleteCmd  = "Delete a hook"
+	helpHookListCmd    = "List hooks"
+	helpHookEnableCmd  = "Enable a hook"
+	helpHookDisableCmd = "Disable a hook"
+	helpHookHistoryCmd = "Show the latest runs of a hook"
+)
+
+var (
+	flagHookAddCmdScript   string
+	flagHookAddCmdURL      string
+	flagHookAddCmdTimeout  int
+	flagHookAddCmdDisabled bool
+)
+
+func init() {
+	hookAddCmd.Flags().StringVar(&flagHookAddCmdScript, "script", "", "Absolute path of the script to run")
+	hookAddCmd.Flags().StringVar(&flagHookAddCmdURL, "url", "", "URL of the webhook to post to")
+	hookAddCmd.Flags().IntVar(&flagHookAddCmdTimeout, "timeout", 0, "Time given to the hook to run, in seconds (default 30)")
+	hookAddCmd.Flags().BoolVar(&flagHookAddCmdDisabled, "disabled", false, "Register the hook disabled")
+	hookCmd.AddCommand(hookAddCmd)
+	hookCmd.AddCommand(hookDeleteCmd)
+	hookCmd.AddCommand(hookListCmd)
+	hookCmd.AddCommand(hookEnableCmd)
+	hookCmd.AddCommand(hookDisableCmd)
+	hookCmd.AddCommand(hookHistoryCmd)
+}
+
+var hookCmd = &cobra.Command{
+	Use:   "hook",
+	Short: helpHookCmd,
+}
+
+var hookAddCmd = &cobra.Command{
+	Use:   "add [flags] <NAME> <post-volume-start|pre-volume-stop|post-add-brick>",
+	Short: helpHookAddCmd,
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		req := api.HookCreateReq{
+			Name:     args[0],
+			Point:    api.HookPoint(args[1]),
+			Script:   flagHookAddCmdScript,
+			URL:      flagHookAddCmdURL,
+			Timeout:  flagHookAddCmdTimeout,
+			Disabled: flagHookAddCmdDisabled,
+		}
+		if _, err := client.HookCreate(req); err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).WithField("name", req.Name).Error("failed to add hook")
+			}
+			failure("Failed to add hook", err, 1)
+		}
+		printMessagef("Hook %s added successfully\n", req.Name)
+	},
+}
+
+var hookDeleteCmd = &cobra.Command{
+	Use:   "delete <NAME>",
+	Short: helpHookDeleteCmd,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name := args[0]
+		if err := client.HookDelete(name); err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).WithField("name", name).Error("failed to delete hook")
+			}
+			failure("Failed to delete hook", err, 1)
+		}
+		printMessagef("Hook %s deleted successfully\n", name)
+	},
+}
+
+var hookListCmd = &cobra.Command{
+	Use:   "list",
+	Short: helpHookListCmd,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		hooks, err := client.Hooks()
+		if err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).Error("failed to list hooks")
+			}
+			failure("Failed to get list of hooks", err, 1)
+		}
+
+		if printStructured(hooks) {
+			return
+		}
+
+		table := newTable()
+		table.SetHeader([]string{"Name", "Point", "Script/URL", "Timeout", "Enabled"})
+		for _, h := range hooks {
+			target := h.Script
+			if target == "" {
+				target = h.URL
+			}
+			table.Append([]string{h.Name, string(h.Point), target, strconv.Itoa(h.Timeout) + "s", strconv.FormatBool(h.Enabled)})
+		}
+		table.Render()
+	},
+}
+
+func hookSetEnabledCmd(use, short string, enabled bool) *cobra.Command {
+	return &cobra.Command{
+		Use:   use + " <NAME>",
+		Short: short,
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			name := args[0]
+			var err error
+			if enabled {
+				_, err = client.HookEnable(name)
+			} else {
+				_, err = client.HookDisable(name)
+			}
+			if err != nil {
+				if GlobalFlag.Verbose {
+					log.WithError(err).WithField("name", name).Errorf("failed to %s hook", use)
+				}
+				failure("Failed to "+use+" hook", err, 1)
+			}
+			printMessagef("Hook %s %sd successfully\n", name, use)
+		},
+	}
+}
+
+var hookEnableCmd = hookSetEnabledCmd("enable", helpHookEnableCmd, true)
+
+var hookDisableCmd = hookSetEnabledCmd("disable", helpHookDisableCmd, false)
+
+var hookHistoryCmd = &cobra.Command{
+	Use:   "history <NAME>",
+	Short: helpHookHistoryCmd,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name := args[0]
+		runs, err := client.HookHistory(name)
+		if err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).WithField("name", name).Error("failed to get hook history")
+			}
+			failure("Failed to get history of hook", err, 1)
+		}
+
+		if printStructured(runs) {
+			return
+		}
+
+		table := newTable()
+		table.SetHeader([]string{"Started", "Volume", "Peer", "Duration", "Success", "Error"})
+		for _, r := range runs {
+			table.Append([]string{
+				r.Started.Format(time.RFC3339),
+				r.Volume,
+				r.PeerID.String(),
+				(time.Duration(r.DurationMs) * time.Millisecond).String(),
+				strconv.FormatBool(r.Success),
+				r.Error,
+			})
+		}
+		table.Render()
+	},
+}
@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	logmgmtapi "github.com/gluster/glusterd2/plugins/logmgmt/api"
+
+	"github.com/olekukonko/tablewriter"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+const (
+	helpLoggingCmd         = "Glusterd2 Log Management"
+	helpLoggingGetCmd      = "Show log levels of glusterd2"
+	helpLoggingSetCmd      = "Set log levels of glusterd2 on all peers"
+	helpLoggingRotateCmd   = "Rotate logs of glusterd2 and the gluster daemons on all peers"
+	helpLoggingShowCmd     = "Show log entries of glusterd2 and the gluster daemons"
+	errLoggingSetFailed    = "Failed to set log levels"
+	errLoggingGetFailed    = "Failed to get log levels"
+	errLoggingRotateFailed = "Failed to rotate logs"
+	errLoggingShowFailed   = "Failed to get log entries"
+)
+
+var (
+	flagLoggingGlobal     string
+	flagLoggingSubsystems []string
+
+	flagLogsQuery logmgmtapi.LogQuery
+	flagLogsSince time.Duration
+)
+
+func init() {
+	loggingSetCmd.Flags().StringVar(&flagLoggingGlobal, "global", "", "Global log level (panic, fatal, error, warning, info or debug)")
+	loggingSetCmd.Flags().StringSliceVar(&flagLoggingSubsystems, "subsystem", nil, "Subsystem log levels as <subsystem>=<level>. Empty level resets the subsystem to global log level")
+	loggingCmd.AddCommand(loggingGetCmd)
+	loggingCmd.AddCommand(loggingSetCmd)
+	loggingCmd.AddCommand(loggingRotateCmd)
+
+	loggingShowCmd.Flags().StringVar(&flagLogsQuery.Daemon, "daemon", "", "Daemon whose logs are shown (glusterd2, brick, glustershd, gsyncd, rebalance etc.)")
+	loggingShowCmd.Flags().StringVar(&flagLogsQuery.Volume, "volume", "", "Show log entries related to the volume")
+	loggingShowCmd.Flags().StringVar(&flagLogsQuery.Brick, "brick", "", "Show log entries of the brick <host:path>")
+	loggingShowCmd.Flags().DurationVar(&flagLogsSince, "since", 0, "Show log entries logged within the duration")
+	loggingShowCmd.Flags().StringVar(&flagLogsQuery.Level, "level", "", "Show log entries at least as severe as the level")
+	loggingShowCmd.Flags().StringVar(&flagLogsQuery.Pattern, "grep", "", "Show log entries matching the regular expression")
+	loggingShowCmd.Flags().IntVar(&flagLogsQuery.Limit, "limit", 100, "Maximum number of most recent log entries shown")
+	loggingShowCmd.Flags().BoolVar(&flagLogsQuery.Peers, "peers", false, "Show log entries of all the peers")
+	loggingCmd.AddCommand(loggingShowCmd)
+}
+
+var loggingCmd = &cobra.Command{
+	Use:   "logging",
+	Short: helpLoggingCmd,
+}
+
+// printLogLevels prints the log levels as a table, or in the output format chosen
+func printLogLevels(resp logmgmtapi.LogLevelResp) {
+	if printStructured(resp) {
+		return
+	}
+	table := newTable()
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetHeader([]string{"Subsystem", "Level"})
+	table.Append([]string{"global", resp.Global})
+	for _, name := range resp.Available {
+		level, ok := resp.Subsystems[name]
+		if !ok {
+			level = resp.Global + " (global)"
+		}
+		table.Append([]string{name, level})
+	}
+	table.Render()
+}
+
+var loggingGetCmd = &cobra.Command{
+	Use:   "get",
+	Short: helpLoggingGetCmd,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		resp, err := client.LogLevels()
+		if err != nil {
+			failure(errLoggingGetFailed, err, 1)
+		}
+		printLogLevels(resp)
+	},
+}
+
+var loggingSetCmd = &cobra.Command{
+	Use:   "set [--global <level>] [--subsystem <subsystem>=<level>]...",
+	Short: helpLoggingSetCmd,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		req := logmgmtapi.LogLevelReq{
+			Global:     flagLoggingGlobal,
+			Subsystems: make(map[string]string),
+		}
+		for _, s := range flagLoggingSubsystems {
+			kv := strings.SplitN(s, "=", 2)
+			if len(kv) != 2 {
+				failure(errLoggingSetFailed, fmt.Errorf("invalid subsystem log level %q, expected <subsystem>=<level>", s), 1)
+			}
+			req.Subsystems[kv[0]] = kv[1]
+		}
+
+		resp, err := client.LogLevelSet(req)
+		if err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).WithFields(log.Fields{
+					"global":     req.Global,
+					"subsystems": req.Subsystems,
+				}).Error("log level set failed")
+			}
+			failure(errLoggingSetFailed, err, 1)
+		}
+		printLogLevels(resp)
+	},
+}
+
+var loggingRotateCmd = &cobra.Command{
+	Use:   "rotate",
+	Short: helpLoggingRotateCmd,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := client.LogRotate(); err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).Error("log rotate failed")
+			}
+			failure(errLoggingRotateFailed, err, 1)
+		}
+		printMessage("Logs rotated successfully")
+	},
+}
+
+var loggingShowCmd = &cobra.Command{
+	Use:   "show [flags]",
+	Short: helpLoggingShowCmd,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		q := flagLogsQuery
+		if flagLogsSince > 0 {
+			q.Since = time.Now().Add(-flagLogsSince)
+		}
+
+		resp, err := client.Logs(q)
+		if err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).Error("log query failed")
+			}
+			failure(errLoggingShowFailed, err, 1)
+		}
+		if printStructured(resp) {
+			return
+		}
+
+		for _, e := range resp.Entries {
+			fmt.Printf("%s %s %s [%s] %s\n", e.Time.Format(time.RFC3339Nano), e.Hostname, e.Daemon, e.Level, e.Message)
+		}
+		if resp.Truncated {
+			fmt.Fprintln(os.Stderr, "Older log entries were left out, use --limit or --since to see them")
+		}
+	},
+}
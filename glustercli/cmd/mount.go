@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+const (
+	helpMountCmd       = "Gluster Mounts managed on the peers"
+	helpMountCreateCmd = "Mount a volume on a peer, mounting it again when glusterd2 restarts"
+	helpMountListCmd   = "List the managed mounts of a peer"
+	helpMountDeleteCmd = "Unmount a managed mount of a peer"
+)
+
+var (
+	flagMountCreateCmdOptions []string
+	flagMountCreateCmdOwner   string
+)
+
+func init() {
+	mountCreateCmd.Flags().StringSliceVar(&flagMountCreateCmdOptions, "options", nil,
+		"Mount options in the format option=value,option (like ro,attribute-timeout=5)")
+	mountCreateCmd.Flags().StringVar(&flagMountCreateCmdOwner, "owner", "admin", "Who the mount is for")
+	mountCmd.AddCommand(mountCreateCmd)
+	mountCmd.AddCommand(mountListCmd)
+	mountCmd.AddCommand(mountDeleteCmd)
+}
+
+var mountCmd = &cobra.Command{
+	Use:   "mount",
+	Short: helpMountCmd,
+}
+
+// mountOptions parses the mount options given as option=value or option
+func mountOptions(opts []string) map[string]string {
+	if len(opts) == 0 {
+		return nil
+	}
+	options := make(map[string]string, len(opts))
+	for _, opt := range opts {
+		kv := strings.SplitN(opt, "=", 2)
+		if len(kv) == 2 {
+			options[kv[0]] = kv[1]
+		} else {
+			options[kv[0]] = ""
+		}
+	}
+	return options
+}
+
+var mountCreateCmd = &cobra.Command{
+	Use:   "create [flags] <PeerID> <VOLNAME> <PATH>",
+	Short: helpMountCreateCmd,
+	Args:  cobra.ExactArgs(3),
+	Run: func(cmd *cobra.Command, args []string) {
+		peerID := args[0]
+		if uuid.Parse(peerID) == nil {
+			failure("Mount create failed", errors.New("failed to parse peerID"), 1)
+		}
+		req := api.MountCreateReq{
+			Volume:  args[1],
+			Path:    args[2],
+			Options: mountOptions(flagMountCreateCmdOptions),
+			Owner:   flagMountCreateCmdOwner,
+		}
+		m, err := client.MountCreate(peerID, req)
+		if err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).WithFields(log.Fields{
+					"peerID": peerID,
+					"volume": req.Volume,
+					"path":   req.Path,
+				}).Error("failed to mount volume")
+			}
+			failure("Mount create failed", err, 1)
+		}
+		if printStructured(m) {
+			return
+		}
+		printMessagef("Volume %s mounted on %s successfully\n", m.Volume, m.Path)
+		printMessagef("Mount ID: %s\n", m.ID)
+	},
+}
+
+var mountListCmd = &cobra.Command{
+	Use:   "list <PeerID>",
+	Short: helpMountListCmd,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		peerID := args[0]
+		if uuid.Parse(peerID) == nil {
+			failure("Failed to get list of mounts", errors.New("failed to parse peerID"), 1)
+		}
+		list, err := client.Mounts(peerID)
+		if err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).WithField("peerID", peerID).Error("failed to list mounts")
+			}
+			failure("Failed to get list of mounts", err, 1)
+		}
+
+		if printStructured(list) {
+			return
+		}
+
+		table := newTable()
+		table.SetHeader([]string{"ID", "Volume", "Path", "Owner", "Online", "PID"})
+		for _, m := range list {
+			pid := ""
+			if m.PID != 0 {
+				pid = strconv.Itoa(m.PID)
+			}
+			table.Append([]string{m.ID.String(), m.Volume, m.Path, m.Owner, strconv.FormatBool(m.Online), pid})
+		}
+		table.Render()
+	},
+}
+
+var mountDeleteCmd = &cobra.Command{
+	Use:   "delete <PeerID> <MountID>",
+	Short: helpMountDeleteCmd,
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		peerID, mountID := args[0], args[1]
+		if err := client.MountDelete(peerID, mountID); err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).WithFields(log.Fields{
+					"peerID":  peerID,
+					"mountID": mountID,
+				}).Error("failed to unmount")
+			}
+			failure("Mount delete failed", err, 1)
+		}
+		printMessagef("Mount %s deleted successfully\n", mountID)
+	},
+}
@@ -0,0 +1,223 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gluster/glusterd2/pkg/api"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+const (
+	helpNamespaceCmd            = "Gluster Namespaces"
+	helpNamespaceCreateCmd      = "Create a namespace owning volumes"
+	helpNamespaceListCmd        = "List namespaces and the usage of their quotas"
+	helpNamespaceEditCmd        = "Change the quotas of a namespace"
+	helpNamespaceDeleteCmd      = "Delete a namespace without volumes"
+	helpNamespaceTokenCmd       = "API tokens scoped to a namespace"
+	helpNamespaceTokenCreateCmd = "Create an API token scoped to a namespace"
+	helpNamespaceTokenListCmd   = "List the API tokens of a namespace"
+	helpNamespaceTokenDeleteCmd = "Revoke an API token of a namespace"
+)
+
+var (
+	flagNamespaceMaxVolumes  int
+	flagNamespaceMaxCapacity string
+)
+
+func init() {
+	for _, cmd := range []*cobra.Command{namespaceCreateCmd, namespaceEditCmd} {
+		cmd.Flags().IntVar(&flagNamespaceMaxVolumes, "max-volumes", 0, "Number of volumes the namespace can have, 0 is no limit")
+		cmd.Flags().StringVar(&flagNamespaceMaxCapacity, "max-capacity", "", "Total size of the volumes the namespace can have, no limit if not given")
+	}
+	namespaceTokenCmd.AddCommand(namespaceTokenCreateCmd)
+	namespaceTokenCmd.AddCommand(namespaceTokenListCmd)
+	namespaceTokenCmd.AddCommand(namespaceTokenDeleteCmd)
+	namespaceCmd.AddCommand(namespaceCreateCmd)
+	namespaceCmd.AddCommand(namespaceListCmd)
+	namespaceCmd.AddCommand(namespaceEditCmd)
+	namespaceCmd.AddCommand(namespaceDeleteCmd)
+	namespaceCmd.AddCommand(namespaceTokenCmd)
+}
+
+var namespaceCmd = &cobra.Command{
+	Use:   "namespace",
+	Short: helpNamespaceCmd,
+}
+
+func namespaceMaxCapacity() uint64 {
+	maxCapacity, err := sizeToBytes(flagNamespaceMaxCapacity)
+	if err != nil {
+		failure("Invalid max capacity specified", err, 1)
+	}
+	return maxCapacity
+}
+
+var namespaceCreateCmd = &cobra.Command{
+	Use:   "create [flags] <NAME>",
+	Short: helpNamespaceCreateCmd,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		req := api.NamespaceCreateReq{
+			Name:        args[0],
+			MaxVolumes:  flagNamespaceMaxVolumes,
+			MaxCapacity: namespaceMaxCapacity(),
+		}
+		if _, err := client.NamespaceCreate(req); err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).WithField("name", req.Name).Error("failed to create namespace")
+			}
+			failure("Failed to create namespace", err, 1)
+		}
+		printMessagef("Namespace %s created successfully\n", req.Name)
+	},
+}
+
+func quotaString(used, limit uint64, format func(uint64) string) string {
+	if limit == 0 {
+		return format(used)
+	}
+	return format(used) + "/" + format(limit)
+}
+
+var namespaceListCmd = &cobra.Command{
+	Use:   "list",
+	Short: helpNamespaceListCmd,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		namespaces, err := client.Namespaces()
+		if err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).Error("failed to list namespaces")
+			}
+			failure("Failed to get list of namespaces", err, 1)
+		}
+
+		if printStructured(namespaces) {
+			return
+		}
+
+		count := func(n uint64) string { return strconv.FormatUint(n, 10) }
+		table := newTable()
+		table.SetHeader([]string{"Name", "Volumes", "Capacity", "Created"})
+		for _, ns := range namespaces {
+			table.Append([]string{
+				ns.Name,
+				quotaString(uint64(ns.Usage.Volumes), uint64(ns.MaxVolumes), count),
+				quotaString(ns.Usage.Capacity, ns.MaxCapacity, humanReadable),
+				ns.CreatedAt.Format(time.RFC3339),
+			})
+		}
+		table.Render()
+	},
+}
+
+var namespaceEditCmd = &cobra.Command{
+	Use:   "edit [flags] <NAME>",
+	Short: helpNamespaceEditCmd,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name := args[0]
+		req := api.NamespaceEditReq{
+			MaxVolumes:  flagNamespaceMaxVolumes,
+			MaxCapacity: namespaceMaxCapacity(),
+		}
+		if _, err := client.NamespaceEdit(name, req); err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).WithField("name", name).Error("failed to edit namespace")
+			}
+			failure("Failed to edit namespace", err, 1)
+		}
+		printMessagef("Namespace %s edited successfully\n", name)
+	},
+}
+
+var namespaceDeleteCmd = &cobra.Command{
+	Use:   "delete <NAME>",
+	Short: helpNamespaceDeleteCmd,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name := args[0]
+		if err := client.NamespaceDelete(name); err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).WithField("name", name).Error("failed to delete namespace")
+			}
+			failure("Failed to delete namespace", err, 1)
+		}
+		printMessagef("Namespace %s deleted successfully\n", name)
+	},
+}
+
+var namespaceTokenCmd = &cobra.Command{
+	Use:   "token",
+	Short: helpNamespaceTokenCmd,
+}
+
+var namespaceTokenCreateCmd = &cobra.Command{
+	Use:   "create <NAMESPACE>",
+	Short: helpNamespaceTokenCreateCmd,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name := args[0]
+		token, err := client.NamespaceTokenCreate(name)
+		if err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).WithField("namespace", name).Error("failed to create namespace token")
+			}
+			failure("Failed to create namespace token", err, 1)
+		}
+
+		if printStructured(token) {
+			return
+		}
+		fmt.Printf("Token ID: %s\n", token.ID)
+		fmt.Printf("Secret:   %s\n", token.Secret)
+		fmt.Println("The secret is not shown again, keep it safe")
+	},
+}
+
+var namespaceTokenListCmd = &cobra.Command{
+	Use:   "list <NAMESPACE>",
+	Short: helpNamespaceTokenListCmd,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name := args[0]
+		tokens, err := client.NamespaceTokens(name)
+		if err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).WithField("namespace", name).Error("failed to list namespace tokens")
+			}
+			failure("Failed to get list of namespace tokens", err, 1)
+		}
+
+		if printStructured(tokens) {
+			return
+		}
+
+		table := newTable()
+		table.SetHeader([]string{"Token ID", "Created"})
+		for _, t := range tokens {
+			table.Append([]string{t.ID, t.CreatedAt.Format(time.RFC3339)})
+		}
+		table.Render()
+	},
+}
+
+var namespaceTokenDeleteCmd = &cobra.Command{
+	Use:   "delete <NAMESPACE> <TOKEN-ID>",
+	Short: helpNamespaceTokenDeleteCmd,
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		name, id := args[0], args[1]
+		if err := client.NamespaceTokenDelete(name, id); err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).WithField("namespace", name).WithField("token", id).Error("failed to revoke namespace token")
+			}
+			failure("Failed to revoke namespace token", err, 1)
+		}
+		printMessagef("Token %s revoked successfully\n", id)
+	},
+}
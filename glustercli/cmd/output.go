@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/ghodss/yaml"
+	"github.com/olekukonko/tablewriter"
+)
+
+// Output formats supported by the --output global flag
+const (
+	outputTable = "table"
+	outputWide  = "wide"
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+)
+
+func validateOutputFormat(format string) error {
+	switch format {
+	case outputTable, outputWide, outputJSON, outputYAML:
+		return nil
+	}
+	return fmt.Errorf("invalid output format %q, expected one of %s, %s, %s or %s",
+		format, outputTable, outputWide, outputJSON, outputYAML)
+}
+
+// isStructuredOutput returns true if the output is to be machine-readable
+func isStructuredOutput() bool {
+	return GlobalFlag.Output == outputJSON || GlobalFlag.Output == outputYAML
+}
+
+// printStructured prints resp in JSON or YAML, if chosen with the --output
+// flag, and returns true. It returns false if the output is to be a table,
+// leaving the caller to render it. The JSON and YAML documents follow the
+// schema of the types in pkg/api.
+func printStructured(resp interface{}) bool {
+	var (
+		out []byte
+		err error
+	)
+	switch GlobalFlag.Output {
+	case outputJSON:
+		out, err = json.MarshalIndent(resp, "", "  ")
+		out = append(out, '\n')
+	case outputYAML:
+		// The YAML document is converted from JSON so that field names
+		// are the same in both
+		out, err = yaml.Marshal(resp)
+	default:
+		return false
+	}
+	if err != nil {
+		failure("Failed to format output", err, 1)
+	}
+	os.Stdout.Write(out)
+	return true
+}
+
+// printMessage prints a human readable message, unless the output is to be
+// machine-readable
+func printMessage(a ...interface{}) {
+	if !isStructuredOutput() {
+		fmt.Println(a...)
+	}
+}
+
+// printMessagef is like printMessage but formats the message
+func printMessagef(format string, a ...interface{}) {
+	if !isStructuredOutput() {
+		fmt.Printf(format, a...)
+	}
+}
+
+// newTable returns a table writing to stdout. Columns are not wrapped when
+// the wide output format is chosen.
+func newTable() *tablewriter.Table {
+	table := tablewriter.NewWriter(os.Stdout)
+	if GlobalFlag.Output == outputWide {
+		table.SetAutoWrapText(false)
+	}
+	return table
+}
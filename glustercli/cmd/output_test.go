@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type outputTestResp struct {
+	Name   string   `json:"name"`
+	Bricks []string `json:"bricks,omitempty"`
+}
+
+// captureStdout returns what fn writes to stdout
+func captureStdout(t *testing.T, fn func()) string {
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	fn()
+	w.Close()
+	out, err := ioutil.ReadAll(r)
+	require.NoError(t, err)
+	return string(out)
+}
+
+func withOutput(format string) func() {
+	old := GlobalFlag
+	GlobalFlag = &GlustercliOption{Output: format}
+	return func() { GlobalFlag = old }
+}
+
+func TestValidateOutputFormat(t *testing.T) {
+	for _, f := range []string{"table", "wide", "json", "yaml"} {
+		assert.Nil(t, validateOutputFormat(f), f)
+	}
+	assert.NotNil(t, validateOutputFormat("xml"))
+	assert.NotNil(t, validateOutputFormat(""))
+}
+
+func TestPrintStructured(t *testing.T) {
+	resp := outputTestResp{Name: "gv0", Bricks: []string{"host1:/bricks/b1", "host2:/bricks/b1"}}
+	tests := []struct {
+		format     string
+		structured bool
+		out        string
+	}{
+		{"json", true, `{
+  "name": "gv0",
+  "bricks": [
+    "host1:/bricks/b1",
+    "host2:/bricks/b1"
+  ]
+}
+`},
+		// The fields of the YAML document are named as in JSON
+		{"yaml", true, `bricks:
+- host1:/bricks/b1
+- host2:/bricks/b1
+name: gv0
+`},
+		// Tables are rendered by the callers
+		{"table", false, ""},
+		{"wide", false, ""},
+	}
+	for _, tt := range tests {
+		restore := withOutput(tt.format)
+		var structured bool
+		out := captureStdout(t, func() {
+			structured = printStructured(resp)
+			printMessage("volume listed")
+		})
+		restore()
+
+		assert.Equal(t, tt.structured, structured, tt.format)
+		if tt.structured {
+			// Messages would make the output invalid
+			assert.Equal(t, tt.out, out, tt.format)
+		} else {
+			assert.Equal(t, "volume listed\n", out, tt.format)
+		}
+	}
+}
+
+func TestNewTable(t *testing.T) {
+	long := "a brick path which is too long to fit in a column"
+	tests := []struct {
+		format  string
+		wrapped bool
+	}{
+		{"table", true},
+		// Columns are not wrapped in the wide output
+		{"wide", false},
+	}
+	for _, tt := range tests {
+		restore := withOutput(tt.format)
+		out := captureStdout(t, func() {
+			table := newTable()
+			table.SetHeader([]string{"Name", "Path"})
+			table.Append([]string{"b1", long})
+			table.Render()
+		})
+		restore()
+
+		assert.Contains(t, out, "b1", tt.format)
+		assert.Equal(t, tt.wrapped, !strings.Contains(out, long), tt.format)
+	}
+}
@@ -3,28 +3,49 @@ package cmd
 import (
 	"errors"
 	"fmt"
-	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gluster/glusterd2/pkg/api"
 
-	"github.com/olekukonko/tablewriter"
 	"github.com/pborman/uuid"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
 
 const (
-	helpPeerCmd       = "Gluster Peer Management"
-	helpPeerAddCmd    = "add peer specified by <HOSTNAME>"
-	helpPeerRemoveCmd = "remove peer specified by <PeerID>"
-	helpPeerStatusCmd = "list status of peers"
-	helpPeerListCmd   = "list all the nodes in the pool (including localhost)"
+	helpPeerCmd            = "Gluster Peer Management"
+	helpPeerAddCmd         = "add peer specified by <HOSTNAME>"
+	helpPeerRemoveCmd      = "remove peer specified by <PeerID>"
+	helpPeerStatusCmd      = "list status of peers"
+	helpPeerListCmd        = "list all the nodes in the pool (including localhost)"
+	helpPeerTokenCmd       = "Gluster Peer Join Token Management"
+	helpPeerTokenCreateCmd = "create a one-time token for a peer to join the cluster"
+	helpPeerJoinCmd        = "join the cluster which created the join token <TOKEN>"
+	helpPeerEvacuateCmd    = "move all the bricks off the peer specified by <PeerID>"
+	helpPeerEvacuateStatus = "show the progress of the evacuation of the peer specified by <PeerID>"
+	helpPeerPortsCmd       = "list the ports the peer specified by <PeerID> and its bricks listen on"
 )
 
 var (
 	// Peer Remove Command Flags
 	flagPeerRemoveForce bool
+
+	// Peer Token Create Command Flags
+	flagPeerTokenTTL time.Duration
+
+	// Peer Join Command Flags
+	flagPeerJoinZone string
+
+	// Peer Evacuate Command Flags
+	flagPeerEvacuateDryRun             bool
+	flagPeerEvacuateForce              bool
+	flagPeerEvacuateLimitPeers         []string
+	flagPeerEvacuateLimitZones         []string
+	flagPeerEvacuateExcludePeers       []string
+	flagPeerEvacuateExcludeZones       []string
+	flagPeerEvacuateSubvolZonesOverlap bool
 )
 
 func init() {
@@ -39,6 +60,26 @@ func init() {
 	peerListCmd.Flags().StringVar(&flagCmdFilterKey, "key", "", "Filter by metadata key")
 	peerListCmd.Flags().StringVar(&flagCmdFilterValue, "value", "", "Filter by metadata value")
 	peerCmd.AddCommand(peerListCmd)
+
+	peerTokenCreateCmd.Flags().DurationVar(&flagPeerTokenTTL, "ttl", time.Hour, "Time the token is valid for")
+	peerTokenCmd.AddCommand(peerTokenCreateCmd)
+	peerCmd.AddCommand(peerTokenCmd)
+
+	peerJoinCmd.Flags().StringVar(&flagPeerJoinZone, "zone", "", "Zone of the peer")
+	peerCmd.AddCommand(peerJoinCmd)
+
+	peerEvacuateCmd.Flags().BoolVar(&flagPeerEvacuateDryRun, "dry-run", false, "Only show the plan, without moving any brick")
+	peerEvacuateCmd.Flags().BoolVarP(&flagPeerEvacuateForce, "force", "f", false, "Move the bricks of volumes without redundancy too, losing their data")
+	peerEvacuateCmd.Flags().StringSliceVar(&flagPeerEvacuateLimitPeers, "limit-peers", nil, "Move bricks only to these Peers")
+	peerEvacuateCmd.Flags().StringSliceVar(&flagPeerEvacuateLimitZones, "limit-zones", nil, "Move bricks only to these Zones")
+	peerEvacuateCmd.Flags().StringSliceVar(&flagPeerEvacuateExcludePeers, "exclude-peers", nil, "Do not move bricks to these Peers")
+	peerEvacuateCmd.Flags().StringSliceVar(&flagPeerEvacuateExcludeZones, "exclude-zones", nil, "Do not move bricks to these Zones")
+	peerEvacuateCmd.Flags().BoolVar(&flagPeerEvacuateSubvolZonesOverlap, "subvols-zones-overlap", false, "Brick can be moved to a zone used by other Sub volumes")
+	peerCmd.AddCommand(peerEvacuateCmd)
+
+	peerCmd.AddCommand(peerEvacuateStatusCmd)
+
+	peerCmd.AddCommand(peerPortsCmd)
 }
 
 var peerCmd = &cobra.Command{
@@ -62,8 +103,11 @@ var peerAddCmd = &cobra.Command{
 			}
 			failure("Peer add failed", err, 1)
 		}
+		if printStructured(peer) {
+			return
+		}
 		fmt.Println("Peer add successful")
-		table := tablewriter.NewWriter(os.Stdout)
+		table := newTable()
 		table.SetHeader([]string{"ID", "Name", "Client Addresses", "Peer Addresses"})
 		table.Append([]string{peer.ID.String(), peer.Name, strings.Join(peer.ClientAddresses, "\n"), strings.Join(peer.PeerAddresses, "\n")})
 		table.Render()
@@ -89,7 +133,7 @@ var peerRemoveCmd = &cobra.Command{
 			}
 			failure("Peer remove failed", err, 1)
 		}
-		fmt.Println("Peer remove success")
+		printMessage("Peer remove success")
 	},
 }
 
@@ -113,11 +157,14 @@ func peerStatusHandler(cmd *cobra.Command) {
 		}
 		failure("Failed to get Peers list", err, 1)
 	}
-	table := tablewriter.NewWriter(os.Stdout)
-	table.SetHeader([]string{"ID", "Name", "Client Addresses", "Peer Addresses", "Online", "PID"})
+	if printStructured(peers) {
+		return
+	}
+	table := newTable()
+	table.SetHeader([]string{"ID", "Name", "Client Addresses", "Peer Addresses", "Online", "PID", "Liveness"})
 
 	for _, peer := range peers {
-		table.Append([]string{peer.ID.String(), peer.Name, strings.Join(peer.ClientAddresses, "\n"), strings.Join(peer.PeerAddresses, "\n"), formatBoolYesNo(peer.Online), formatPID(peer.PID)})
+		table.Append([]string{peer.ID.String(), peer.Name, strings.Join(peer.ClientAddresses, "\n"), strings.Join(peer.PeerAddresses, "\n"), formatBoolYesNo(peer.Online), formatPID(peer.PID), formatLiveness(peer.Liveness)})
 	}
 	table.Render()
 }
@@ -139,3 +186,156 @@ var peerListCmd = &cobra.Command{
 		peerStatusHandler(cmd)
 	},
 }
+
+var peerTokenCmd = &cobra.Command{
+	Use:   "token",
+	Short: helpPeerTokenCmd,
+}
+
+var peerTokenCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: helpPeerTokenCreateCmd,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		resp, err := client.PeerTokenCreate(api.PeerTokenReq{TTL: int(flagPeerTokenTTL / time.Second)})
+		if err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).Error("peer token create failed")
+			}
+			failure("Failed to create join token", err, 1)
+		}
+		if printStructured(resp) {
+			return
+		}
+		fmt.Println(resp.Token)
+		fmt.Println("Expires at:", resp.ExpiresAt.Local().Format(time.RFC1123))
+	},
+}
+
+var peerJoinCmd = &cobra.Command{
+	Use:   "join <TOKEN>",
+	Short: helpPeerJoinCmd,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		peer, err := client.PeerJoin(api.PeerJoinReq{
+			Token: args[0],
+			Zone:  flagPeerJoinZone,
+		})
+		if err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).Error("peer join failed")
+			}
+			failure("Peer join failed", err, 1)
+		}
+		if printStructured(peer) {
+			return
+		}
+		fmt.Println("Peer join successful")
+		table := newTable()
+		table.SetHeader([]string{"ID", "Name", "Client Addresses", "Peer Addresses"})
+		table.Append([]string{peer.ID.String(), peer.Name, strings.Join(peer.ClientAddresses, "\n"), strings.Join(peer.PeerAddresses, "\n")})
+		table.Render()
+	},
+}
+
+func printEvacuation(st api.PeerEvacuateStatus) {
+	fmt.Printf("State: %s (%d of %d bricks moved, %d failed)\n", st.State, st.Done, st.Total, st.Failed)
+	table := newTable()
+	table.SetHeader([]string{"Volume", "Brick", "Size", "Target Peer", "Target Device", "State", "Message"})
+	for _, m := range st.Moves {
+		var target, size string
+		if m.DstPeerID != nil {
+			target = m.DstPeerID.String()
+			size = humanReadable(m.Size)
+		}
+		table.Append([]string{m.Volume, m.SrcPath, size, target, m.DstDevice, m.State, m.Message})
+	}
+	table.Render()
+}
+
+var peerEvacuateCmd = &cobra.Command{
+	Use:   "evacuate <PeerID>",
+	Short: helpPeerEvacuateCmd,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		peerID := args[0]
+		if uuid.Parse(peerID) == nil {
+			failure("Peer evacuate failed", errors.New("failed to parse peerID"), 1)
+		}
+		st, err := client.PeerEvacuate(peerID, api.PeerEvacuateReq{
+			DryRun:             flagPeerEvacuateDryRun,
+			Force:              flagPeerEvacuateForce,
+			LimitPeers:         flagPeerEvacuateLimitPeers,
+			LimitZones:         flagPeerEvacuateLimitZones,
+			ExcludePeers:       flagPeerEvacuateExcludePeers,
+			ExcludeZones:       flagPeerEvacuateExcludeZones,
+			SubvolZonesOverlap: flagPeerEvacuateSubvolZonesOverlap,
+		})
+		if err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).WithField("peerID", peerID).Error("peer evacuate failed")
+			}
+			failure("Peer evacuate failed", err, 1)
+		}
+		if printStructured(st) {
+			return
+		}
+		printEvacuation(st)
+	},
+}
+
+var peerEvacuateStatusCmd = &cobra.Command{
+	Use:   "evacuate-status <PeerID>",
+	Short: helpPeerEvacuateStatus,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		peerID := args[0]
+		st, err := client.PeerEvacuationStatus(peerID)
+		if err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).WithField("peerID", peerID).Error("peer evacuate status failed")
+			}
+			failure("Failed to get the evacuation status", err, 1)
+		}
+		if printStructured(st) {
+			return
+		}
+		printEvacuation(st)
+	},
+}
+
+var peerPortsCmd = &cobra.Command{
+	Use:   "ports <PeerID>",
+	Short: helpPeerPortsCmd,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		peerID := args[0]
+		if uuid.Parse(peerID) == nil {
+			failure("Failed to get the ports of the peer", errors.New("failed to parse peerID"), 1)
+		}
+		resp, err := client.PeerPorts(peerID)
+		if err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).WithField("peerID", peerID).Error("peer ports failed")
+			}
+			failure("Failed to get the ports of the peer", err, 1)
+		}
+		if printStructured(resp) {
+			return
+		}
+		table := newTable()
+		table.SetHeader([]string{"Port", "Protocol", "Service", "Bricks", "PID", "Expected", "Listening"})
+		for _, p := range resp.Ports {
+			var port string
+			if p.Port != 0 {
+				port = strconv.Itoa(p.Port)
+			}
+			var bricks []string
+			for _, b := range p.Bricks {
+				bricks = append(bricks, b.Volume+":"+b.Path)
+			}
+			table.Append([]string{port, p.Protocol, p.Service, strings.Join(bricks, "\n"), formatPID(p.PID), formatBoolYesNo(p.Expected), formatBoolYesNo(p.Listening)})
+		}
+		table.Render()
+	},
+}
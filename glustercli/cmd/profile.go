@@ -0,0 +1,330 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pelletier/go-toml"
+	"github.com/spf13/cobra"
+)
+
+const (
+	helpProfileCmd  = "Manage the profiles of glusterd2 endpoints"
+	helpProfileLong = `Manage named profiles of glusterd2 endpoints, so that clusters can be
+switched between with the --profile flag instead of environment variables.
+
+A profile holds the endpoints of a cluster along with the TLS and
+authentication options to reach it. The profiles are saved in
+~/.glustercli/config, which is readable only by its owner as it may hold
+secrets.
+
+The options are taken in the following order of precedence (highest to
+lowest): flags, the profile chosen with --profile, the GD2_ENDPOINTS and
+GD2_AUTH_SECRET environment variables, the default profile.`
+	helpProfileListCmd = "List the profiles"
+	helpProfileSetCmd  = "Create or update a profile with the global flags given"
+	helpProfileSetLong = `Create or update a profile with the global flags given.
+
+The flags saved in a profile are --endpoints, --cacert, --insecure, --user,
+--secret and --secret-file. Flags which are not given keep their value in an
+existing profile. For example:
+
+  glustercli profile set prod --endpoints=https://node1:24007,https://node2:24007 \
+      --cacert=/etc/glusterd2/ca.pem --secret-file=/etc/glusterd2/prod.secret`
+	helpProfileRemoveCmd = "Remove a profile"
+	helpProfileUseCmd    = "Make a profile the default one"
+
+	cliConfigDir  = ".glustercli"
+	cliConfigFile = "config"
+)
+
+// profileFlags are the global flags which can be saved in a profile
+var profileFlags = []string{"endpoints", "cacert", "insecure", "user", "secret", "secret-file"}
+
+// cliProfile is a named set of options to reach a glusterd2 cluster
+type cliProfile struct {
+	Endpoints  []string `toml:"endpoints"`
+	Cacert     string   `toml:"cacert"`
+	Insecure   bool     `toml:"insecure"`
+	User       string   `toml:"user"`
+	Secret     string   `toml:"secret"`
+	SecretFile string   `toml:"secret-file"`
+}
+
+// cliConfig is the glustercli configuration file
+type cliConfig struct {
+	DefaultProfile string                `toml:"default-profile"`
+	Profiles       map[string]cliProfile `toml:"profiles"`
+}
+
+func cliConfigPath() (string, error) {
+	home := os.Getenv("HOME")
+	if home == "" {
+		return "", errors.New("HOME is not set")
+	}
+	return filepath.Join(home, cliConfigDir, cliConfigFile), nil
+}
+
+// loadCLIConfig reads the configuration file at path. An empty configuration
+// is returned if the file does not exist.
+func loadCLIConfig(path string) (*cliConfig, error) {
+	conf := &cliConfig{}
+	data, err := ioutil.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if len(data) > 0 {
+		if err := toml.Unmarshal(data, conf); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %s", path, err)
+		}
+	}
+	if conf.Profiles == nil {
+		conf.Profiles = make(map[string]cliProfile)
+	}
+	return conf, nil
+}
+
+// save writes the configuration to path, readable only by its owner
+func (conf *cliConfig) save(path string) error {
+	data, err := toml.Marshal(*conf)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// selectProfile returns the profile chosen with --profile, or else the
+// default profile. explicit is true if the profile was chosen with --profile.
+func (conf *cliConfig) selectProfile(name string) (profile *cliProfile, explicit bool, err error) {
+	if name != "" {
+		p, ok := conf.Profiles[name]
+		if !ok {
+			return nil, true, fmt.Errorf("profile %s does not exist", name)
+		}
+		return &p, true, nil
+	}
+	if p, ok := conf.Profiles[conf.DefaultProfile]; ok {
+		return &p, false, nil
+	}
+	return nil, false, nil
+}
+
+// applyProfile sets the global options which are not given as flags from the
+// profile. The default profile does not override the environment variables.
+func (gOpt *GlustercliOption) applyProfile(p *cliProfile, explicit bool) {
+	unset := func(flag string) bool {
+		return !gOpt.flagSet.Changed(flag)
+	}
+
+	if len(p.Endpoints) > 0 && unset("endpoints") && (explicit || os.Getenv("GD2_ENDPOINTS") == "") {
+		gOpt.Endpoints = p.Endpoints
+		gOpt.profileEndpoints = true
+	}
+	if p.Cacert != "" && unset("cacert") {
+		gOpt.Cacert = p.Cacert
+	}
+	if p.Insecure && unset("insecure") {
+		gOpt.Insecure = true
+	}
+	if p.User != "" && unset("user") {
+		gOpt.User = p.User
+	}
+	// a secret given as a flag, in either form, overrides both forms in
+	// the profile
+	if unset("secret") && unset("secret-file") && (explicit || os.Getenv("GD2_AUTH_SECRET") == "") {
+		gOpt.Secret = p.Secret
+		gOpt.SecretFile = p.SecretFile
+	}
+}
+
+// SetProfile applies the profile chosen with --profile, or the default
+// profile, to the global options.
+func (gOpt *GlustercliOption) SetProfile() {
+	path, err := cliConfigPath()
+	if err != nil {
+		if gOpt.Profile != "" {
+			failure("Failed to find the glustercli configuration", err, 1)
+		}
+		return
+	}
+	conf, err := loadCLIConfig(path)
+	if err != nil {
+		failure("Failed to read the glustercli configuration", err, 1)
+	}
+	p, explicit, err := conf.selectProfile(gOpt.Profile)
+	if err != nil {
+		failure("Failed to select profile", err, 1)
+	}
+	if p != nil {
+		gOpt.applyProfile(p, explicit)
+	}
+}
+
+// updateProfile sets the options of the profile which are given as flags
+func (gOpt *GlustercliOption) updateProfile(p *cliProfile) (changed bool) {
+	for _, flag := range profileFlags {
+		if !gOpt.flagSet.Changed(flag) {
+			continue
+		}
+		changed = true
+		switch flag {
+		case "endpoints":
+			p.Endpoints = gOpt.Endpoints
+		case "cacert":
+			p.Cacert = gOpt.Cacert
+		case "insecure":
+			p.Insecure = gOpt.Insecure
+		case "user":
+			p.User = gOpt.User
+		case "secret":
+			p.Secret = gOpt.Secret
+			p.SecretFile = ""
+		case "secret-file":
+			p.SecretFile = gOpt.SecretFile
+			p.Secret = ""
+		}
+	}
+	return changed
+}
+
+func init() {
+	profileCmd.AddCommand(profileListCmd)
+	profileCmd.AddCommand(profileSetCmd)
+	profileCmd.AddCommand(profileRemoveCmd)
+	profileCmd.AddCommand(profileUseCmd)
+}
+
+// mustLoadCLIConfig returns the configuration and the path of its file
+func mustLoadCLIConfig() (*cliConfig, string) {
+	path, err := cliConfigPath()
+	if err != nil {
+		failure("Failed to find the glustercli configuration", err, 1)
+	}
+	conf, err := loadCLIConfig(path)
+	if err != nil {
+		failure("Failed to read the glustercli configuration", err, 1)
+	}
+	return conf, path
+}
+
+func mustSaveCLIConfig(conf *cliConfig, path string) {
+	if err := conf.save(path); err != nil {
+		failure("Failed to save the glustercli configuration", err, 1)
+	}
+}
+
+var profileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: helpProfileCmd,
+	Long:  helpProfileLong,
+	// profiles are managed without connecting to glusterd2
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {},
+}
+
+var profileListCmd = &cobra.Command{
+	Use:   "list",
+	Short: helpProfileListCmd,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		conf, _ := mustLoadCLIConfig()
+		if len(conf.Profiles) == 0 {
+			fmt.Println("There are no profiles")
+			return
+		}
+		names := make([]string, 0, len(conf.Profiles))
+		for name := range conf.Profiles {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		table := newTable()
+		table.SetHeader([]string{"Name", "Default", "Endpoints", "CA Cert", "User", "Auth"})
+		for _, name := range names {
+			p := conf.Profiles[name]
+			var def, auth string
+			if name == conf.DefaultProfile {
+				def = "*"
+			}
+			switch {
+			case p.Secret != "":
+				auth = "secret"
+			case p.SecretFile != "":
+				auth = p.SecretFile
+			}
+			cacert := p.Cacert
+			if p.Insecure {
+				cacert = "(insecure)"
+			}
+			table.Append([]string{name, def, strings.Join(p.Endpoints, ","), cacert, p.User, auth})
+		}
+		table.Render()
+	},
+}
+
+var profileSetCmd = &cobra.Command{
+	Use:   "set <name>",
+	Short: helpProfileSetCmd,
+	Long:  helpProfileSetLong,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name := args[0]
+		conf, path := mustLoadCLIConfig()
+		p, exists := conf.Profiles[name]
+		if !GlobalFlag.updateProfile(&p) && exists {
+			failure("Failed to update profile", fmt.Errorf("none of the flags --%s were given", strings.Join(profileFlags, ", --")), 1)
+		}
+		conf.Profiles[name] = p
+		if len(conf.Profiles) == 1 {
+			conf.DefaultProfile = name
+		}
+		mustSaveCLIConfig(conf, path)
+		fmt.Printf("Profile %s saved\n", name)
+	},
+}
+
+var profileRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: helpProfileRemoveCmd,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name := args[0]
+		conf, path := mustLoadCLIConfig()
+		if _, ok := conf.Profiles[name]; !ok {
+			failure("Failed to remove profile", fmt.Errorf("profile %s does not exist", name), 1)
+		}
+		delete(conf.Profiles, name)
+		if conf.DefaultProfile == name {
+			conf.DefaultProfile = ""
+		}
+		mustSaveCLIConfig(conf, path)
+		fmt.Printf("Profile %s removed\n", name)
+	},
+}
+
+var profileUseCmd = &cobra.Command{
+	Use:   "use <name>",
+	Short: helpProfileUseCmd,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name := args[0]
+		conf, path := mustLoadCLIConfig()
+		if _, ok := conf.Profiles[name]; !ok {
+			failure("Failed to set the default profile", fmt.Errorf("profile %s does not exist", name), 1)
+		}
+		conf.DefaultProfile = name
+		mustSaveCLIConfig(conf, path)
+		fmt.Printf("Profile %s is now the default\n", name)
+	},
+}
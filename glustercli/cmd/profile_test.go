@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCLIConfigSaveLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "glustercli")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, cliConfigDir, cliConfigFile)
+
+	conf, err := loadCLIConfig(path)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(conf.Profiles))
+
+	conf.DefaultProfile = "prod"
+	conf.Profiles["prod"] = cliProfile{
+		Endpoints:  []string{"https://node1:24007", "https://node2:24007"},
+		Cacert:     "/etc/glusterd2/ca.pem",
+		SecretFile: "/etc/glusterd2/prod.secret",
+	}
+	conf.Profiles["test"] = cliProfile{Endpoints: []string{"http://test:24007"}, Insecure: true}
+	assert.Nil(t, conf.save(path))
+
+	info, err := os.Stat(path)
+	assert.Nil(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	loaded, err := loadCLIConfig(path)
+	assert.Nil(t, err)
+	assert.Equal(t, conf, loaded)
+}
+
+func TestSelectProfile(t *testing.T) {
+	conf := &cliConfig{
+		DefaultProfile: "prod",
+		Profiles: map[string]cliProfile{
+			"prod": {User: "prod"},
+			"test": {User: "test"},
+		},
+	}
+
+	p, explicit, err := conf.selectProfile("")
+	assert.Nil(t, err)
+	assert.False(t, explicit)
+	assert.Equal(t, "prod", p.User)
+
+	p, explicit, err = conf.selectProfile("test")
+	assert.Nil(t, err)
+	assert.True(t, explicit)
+	assert.Equal(t, "test", p.User)
+
+	_, _, err = conf.selectProfile("dev")
+	assert.NotNil(t, err)
+
+	conf.DefaultProfile = ""
+	p, _, err = conf.selectProfile("")
+	assert.Nil(t, err)
+	assert.Nil(t, p)
+}
+
+func TestApplyProfile(t *testing.T) {
+	p := &cliProfile{
+		Endpoints: []string{"https://node1:24007"},
+		User:      "admin",
+		Secret:    "s3cret",
+	}
+	newOpts := func(args ...string) *GlustercliOption {
+		opts := &GlustercliOption{}
+		opts.flagSet = pflag.NewFlagSet("glustercli", pflag.ContinueOnError)
+		opts.AddPersistentFlag(opts.flagSet)
+		assert.Nil(t, opts.flagSet.Parse(args))
+		return opts
+	}
+
+	opts := newOpts("--user=other")
+	opts.applyProfile(p, false)
+	assert.Equal(t, p.Endpoints, opts.Endpoints)
+	assert.True(t, opts.profileEndpoints)
+	assert.Equal(t, "other", opts.User)
+	assert.Equal(t, "s3cret", opts.Secret)
+
+	opts = newOpts("--secret-file=/tmp/secret")
+	opts.applyProfile(p, true)
+	assert.Equal(t, "", opts.Secret)
+	assert.Equal(t, "/tmp/secret", opts.SecretFile)
+
+	// the environment variables override the default profile, but not a
+	// profile chosen with --profile
+	os.Setenv("GD2_ENDPOINTS", "http://env:24007")
+	defer os.Unsetenv("GD2_ENDPOINTS")
+	opts = newOpts()
+	opts.applyProfile(p, false)
+	assert.False(t, opts.profileEndpoints)
+	opts.applyProfile(p, true)
+	assert.True(t, opts.profileEndpoints)
+}
@@ -41,6 +41,7 @@ func NewGlustercliCmd() *cobra.Command {
 	opts.flagSet = rootCmd.PersistentFlags()
 	opts.AddPersistentFlag(opts.flagSet)
 	addSubCommands(rootCmd)
+	rootCmd.BashCompletionFunction = bashCompletionFunc(rootCmd)
 	GlobalFlag = opts
 	return rootCmd
 }
@@ -51,10 +52,18 @@ func addSubCommands(rootCmd *cobra.Command) {
 	rootCmd.AddCommand(bitrotCmd)
 	rootCmd.AddCommand(deviceCmd)
 	rootCmd.AddCommand(eventsCmd)
+	rootCmd.AddCommand(hookCmd)
+	rootCmd.AddCommand(namespaceCmd)
+	rootCmd.AddCommand(mountCmd)
 	rootCmd.AddCommand(georepCmd)
 	rootCmd.AddCommand(snapshotCmd)
 	rootCmd.AddCommand(volumeCmd)
 	rootCmd.AddCommand(traceCmd)
+	rootCmd.AddCommand(loggingCmd)
+	rootCmd.AddCommand(clusterCmd)
+	rootCmd.AddCommand(completionCmd)
+	rootCmd.AddCommand(shellCmd)
+	rootCmd.AddCommand(profileCmd)
 }
 
 // GlustercliOption will have all global flags set during run time
@@ -63,6 +72,7 @@ type GlustercliOption struct {
 	ScriptMode bool
 	XMLOutput  bool
 	JSONOutput bool
+	Output     string
 	Insecure   bool
 	Verbose    bool
 	Cacert     string
@@ -72,6 +82,10 @@ type GlustercliOption struct {
 	SecretFile string
 	Endpoints  []string
 	Timeout    uint
+	Profile    string
+
+	// profileEndpoints is true if the endpoints were set from a profile
+	profileEndpoints bool
 }
 
 //AddPersistentFlag will initialize the Global Flags of root command.
@@ -80,6 +94,8 @@ func (gOpt *GlustercliOption) AddPersistentFlag(flagSet *pflag.FlagSet) {
 	flagSet.BoolVarP(&gOpt.ScriptMode, "script-mode", "y", false, "running in script mode")
 	flagSet.BoolVarP(&gOpt.XMLOutput, "xml", "", false, "XML Output")
 	flagSet.BoolVarP(&gOpt.JSONOutput, "json", "", false, "JSON Output")
+	flagSet.MarkDeprecated("json", "use --output=json instead")
+	flagSet.StringVarP(&gOpt.Output, "output", "o", outputTable, "Output format, one of table, wide, json or yaml")
 	flagSet.StringSliceVar(&gOpt.Endpoints, "endpoints", []string{"http://127.0.0.1:24007"}, "glusterd2 endpoints")
 	flagSet.BoolVarP(&gOpt.Verbose, "verbose", "v", false, "verbose output")
 	flagSet.UintVar(&gOpt.Timeout, "timeout", defaultTimeout,
@@ -93,6 +109,9 @@ func (gOpt *GlustercliOption) AddPersistentFlag(flagSet *pflag.FlagSet) {
 	// Log options
 	flagSet.StringVarP(&gOpt.LogLevel, logging.LevelFlag, "", defaultLogLevel, logging.LevelHelp)
 
+	// Profile of endpoints and credentials, see `glustercli profile`
+	flagSet.StringVar(&gOpt.Profile, "profile", "", "Name of the profile to take the endpoints and credentials from")
+
 	// SSL/TLS options
 	flagSet.StringVarP(&gOpt.Cacert, "cacert", "", "", "Path to CA certificate")
 	flagSet.BoolVarP(&gOpt.Insecure, "insecure", "", false,
@@ -105,6 +124,17 @@ func (gOpt *GlustercliOption) Init() {
 	if err := logging.Init("", "stdout", gOpt.LogLevel, false); err != nil {
 		fmt.Println("Error initializing log file ", err)
 	}
+	// Initialize output format
+	if gOpt.JSONOutput {
+		gOpt.Output = outputJSON
+	}
+	if err := validateOutputFormat(gOpt.Output); err != nil {
+		failure("Invalid output format", err, 1)
+	}
+
+	// Initialize options from the profile
+	gOpt.SetProfile()
+
 	//Initialize Secret
 	gOpt.SetSecret()
 
@@ -120,7 +150,9 @@ func (gOpt *GlustercliOption) Init() {
 // Secret is taken in following order of precedence (highest to lowest):
 // --secret
 // --secret-file
+// secret or secret-file of the profile chosen with --profile
 // GD2_AUTH_SECRET (environment variable)
+// secret or secret-file of the default profile
 // --secret-file (default path)
 //
 // NOTE: For simplicity, we don't distinguish between an empty
@@ -161,11 +193,13 @@ func (gOpt *GlustercliOption) SetSecret() {
 // SetEndpoints will Set the endpoints based on precedence.
 // Endpoints are taken in following order of precedence (highest to lowest):
 // --endpoints
+// endpoints of the profile chosen with --profile
 // GD2_ENDPOINTS (environment variable)
+// endpoints of the default profile
 // (default value)
 func (gOpt *GlustercliOption) SetEndpoints() {
-	// if --endpoints is set
-	if gOpt.flagSet.Changed("endpoints") {
+	// if --endpoints is set, or taken from a profile
+	if gOpt.flagSet.Changed("endpoints") || gOpt.profileEndpoints {
 		return
 	}
 
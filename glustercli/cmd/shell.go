@@ -0,0 +1,243 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	helpShellCmd  = "Run glustercli commands interactively"
+	helpShellLong = `Run glustercli commands interactively.
+
+Commands are entered without the leading "glustercli" and run with the global
+flags given to the shell. In addition, the shell understands:
+
+  history      list the commands run so far
+  !!           run the last command again
+  !<n>         run the command numbered n in the history
+  help         show the available commands
+  exit, quit   leave the shell
+
+The history is saved in ~/.glustercli_history across sessions.`
+
+	shellPrompt      = "glustercli> "
+	shellHistoryFile = ".glustercli_history"
+	shellHistorySize = 1000
+)
+
+var shellCmd = &cobra.Command{
+	Use:   "shell",
+	Short: helpShellCmd,
+	Long:  helpShellLong,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runShell(cmd.Root(), os.Stdin, os.Stdout); err != nil {
+			failure("Interactive shell failed", err, 1)
+		}
+	},
+}
+
+// splitCommandLine splits a line into words like a shell, honouring single
+// and double quotes and backslash escapes
+func splitCommandLine(line string) ([]string, error) {
+	var (
+		words   []string
+		word    []rune
+		inWord  bool
+		quote   rune
+		escaped bool
+	)
+	for _, r := range line {
+		switch {
+		case escaped:
+			word = append(word, r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped = true
+			inWord = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				word = append(word, r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t':
+			if inWord {
+				words = append(words, string(word))
+				word = word[:0]
+				inWord = false
+			}
+		default:
+			word = append(word, r)
+			inWord = true
+		}
+	}
+	if quote != 0 || escaped {
+		return nil, errors.New("unterminated quote or escape")
+	}
+	if inWord {
+		words = append(words, string(word))
+	}
+	return words, nil
+}
+
+// shellHistory is the list of commands run in the interactive shell
+type shellHistory struct {
+	path  string
+	lines []string
+}
+
+func loadShellHistory() *shellHistory {
+	h := &shellHistory{}
+	home := os.Getenv("HOME")
+	if home == "" {
+		return h
+	}
+	h.path = filepath.Join(home, shellHistoryFile)
+	f, err := os.Open(h.path)
+	if err != nil {
+		return h
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			h.lines = append(h.lines, line)
+		}
+	}
+	if len(h.lines) > shellHistorySize {
+		h.lines = h.lines[len(h.lines)-shellHistorySize:]
+	}
+	return h
+}
+
+// add appends the line to the history and to the history file
+func (h *shellHistory) add(line string) {
+	h.lines = append(h.lines, line)
+	if h.path == "" {
+		return
+	}
+	f, err := os.OpenFile(h.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	fmt.Fprintln(f, line)
+}
+
+// expand replaces !! and !<n> with the lines from the history
+func (h *shellHistory) expand(line string) (string, error) {
+	if !strings.HasPrefix(line, "!") {
+		return line, nil
+	}
+	if len(h.lines) == 0 {
+		return "", errors.New("history is empty")
+	}
+	if line == "!!" {
+		return h.lines[len(h.lines)-1], nil
+	}
+	n, err := strconv.Atoi(line[1:])
+	if err != nil || n < 1 || n > len(h.lines) {
+		return "", fmt.Errorf("%s: event not found", line)
+	}
+	return h.lines[n-1], nil
+}
+
+// changedGlobalFlags returns the global flags set on the command line of the
+// shell, to be passed on to the commands run in it
+func changedGlobalFlags(root *cobra.Command) []string {
+	var flags []string
+	root.PersistentFlags().Visit(func(f *pflag.Flag) {
+		value := f.Value.String()
+		if strings.HasSuffix(f.Value.Type(), "Slice") {
+			// slices are formatted as [a,b] and parsed from a,b
+			value = strings.TrimSuffix(strings.TrimPrefix(value, "["), "]")
+		}
+		flags = append(flags, "--"+f.Name+"="+value)
+	})
+	return flags
+}
+
+// runShell reads commands from in and runs each of them in a new glustercli
+// process, so that a failing command does not end the shell and flags do not
+// carry over from one command to the next
+func runShell(root *cobra.Command, in io.Reader, out io.Writer) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	globalFlags := changedGlobalFlags(root)
+	history := loadShellHistory()
+	reader := bufio.NewReader(in)
+
+	for {
+		fmt.Fprint(out, shellPrompt)
+		line, err := reader.ReadString('\n')
+		if err == io.EOF && line == "" {
+			fmt.Fprintln(out)
+			return nil
+		} else if err != nil && err != io.EOF {
+			return err
+		}
+
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		expanded, err := history.expand(line)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			continue
+		}
+		if expanded != line {
+			fmt.Fprintln(out, expanded)
+			line = expanded
+		}
+
+		words, err := splitCommandLine(line)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			continue
+		}
+		if words[0] == root.Name() {
+			words = words[1:]
+		}
+		if len(words) == 0 {
+			continue
+		}
+
+		switch words[0] {
+		case "exit", "quit":
+			return nil
+		case "history":
+			for i, l := range history.lines {
+				fmt.Fprintf(out, "%5d  %s\n", i+1, l)
+			}
+			continue
+		case "shell":
+			fmt.Fprintln(os.Stderr, "Already in the interactive shell")
+			continue
+		}
+		history.add(line)
+
+		c := exec.Command(exe, append(globalFlags, words...)...)
+		c.Stdin = os.Stdin
+		c.Stdout = out
+		c.Stderr = os.Stderr
+		// The exit status of the command is reported by the command itself
+		c.Run()
+	}
+}
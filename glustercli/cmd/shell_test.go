@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitCommandLine(t *testing.T) {
+	words, err := splitCommandLine(`volume set  testvol opt "a b" 'c\d' e\ f ""`)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"volume", "set", "testvol", "opt", "a b", `c\d`, "e f", ""}, words)
+
+	_, err = splitCommandLine(`volume set "testvol`)
+	assert.NotNil(t, err)
+}
+
+func TestShellHistoryExpand(t *testing.T) {
+	h := &shellHistory{}
+	_, err := h.expand("!!")
+	assert.NotNil(t, err)
+
+	h.lines = []string{"peer list", "volume list"}
+	line, err := h.expand("!!")
+	assert.Nil(t, err)
+	assert.Equal(t, "volume list", line)
+
+	line, err = h.expand("!1")
+	assert.Nil(t, err)
+	assert.Equal(t, "peer list", line)
+
+	_, err = h.expand("!3")
+	assert.NotNil(t, err)
+
+	line, err = h.expand("volume info")
+	assert.Nil(t, err)
+	assert.Equal(t, "volume info", line)
+}
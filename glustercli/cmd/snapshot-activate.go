@@ -1,8 +1,6 @@
 package cmd
 
 import (
-	"fmt"
-
 	"github.com/gluster/glusterd2/pkg/api"
 
 	log "github.com/sirupsen/logrus"
@@ -42,5 +40,5 @@ func snapshotActivateCmdRun(cmd *cobra.Command, args []string) {
 		}
 		failure("snapshot activation failed", err, 1)
 	}
-	fmt.Printf("Snapshot %s activated successfully\n", snapname)
+	printMessagef("Snapshot %s activated successfully\n", snapname)
 }
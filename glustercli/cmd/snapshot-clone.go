@@ -47,6 +47,9 @@ func snapshotCloneCmdRun(cmd *cobra.Command, args []string) {
 		}
 		failure("Failed to clone Snapshot", err, 1)
 	}
+	if printStructured(vol) {
+		return
+	}
 	fmt.Printf("New Volume %s cloned from Snapshot %s\n", vol.Name, snapname)
 	fmt.Println("Clone Volume ID: ", vol.ID)
 }
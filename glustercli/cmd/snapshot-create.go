@@ -59,6 +59,9 @@ func snapshotCreateCmdRun(cmd *cobra.Command, args []string) {
 		}
 		failure("Snapshot creation failed", err, 1)
 	}
+	if printStructured(snap) {
+		return
+	}
 	vol := snap.VolInfo
 	fmt.Printf("%s Snapshot created successfully\n", vol.Name)
 	fmt.Println("Snapshot Volume ID: ", vol.ID)
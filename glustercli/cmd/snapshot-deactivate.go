@@ -1,8 +1,6 @@
 package cmd
 
 import (
-	"fmt"
-
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
@@ -32,5 +30,5 @@ func snapshotDeactivateCmdRun(cmd *cobra.Command, args []string) {
 		}
 		failure("snapshot deactivation failed", err, 1)
 	}
-	fmt.Printf("Snapshot %s deactivated successfully\n", snapname)
+	printMessagef("Snapshot %s deactivated successfully\n", snapname)
 }
@@ -49,7 +49,7 @@ func snapshotDelete(snapname string) error {
 		}
 		return err
 	}
-	fmt.Printf("%s Snapshot deleted successfully\n", snapname)
+	printMessagef("%s Snapshot deleted successfully\n", snapname)
 	return nil
 }
 
@@ -74,16 +74,16 @@ func snapshotDeleteAllCmdRun(cmd *cobra.Command, args []string) {
 	}
 
 	if len(snaps) == 0 {
-		fmt.Printf("There are no snapshots to delete \n")
+		printMessagef("There are no snapshots to delete \n")
 		return
 	}
 
 	for _, vol := range snaps {
 		if len(vol.SnapList) == 0 {
-			fmt.Printf("There are no snapshots to delete for volume %s \n", vol.ParentName)
+			printMessagef("There are no snapshots to delete for volume %s \n", vol.ParentName)
 			continue
 		}
-		fmt.Printf("Deleting snapshots of volume %s \n", vol.ParentName)
+		printMessagef("Deleting snapshots of volume %s \n", vol.ParentName)
 		for _, snap := range vol.SnapList {
 			if err := snapshotDelete(snap.VolInfo.Name); err != nil {
 				fmt.Printf("Failed to delete snapshot %s \n", snap.VolInfo.Name)
@@ -68,6 +68,9 @@ func snapshotInfoHandler(cmd *cobra.Command) error {
 	if err != nil {
 		return err
 	}
+	if printStructured(snap) {
+		return nil
+	}
 	snapshotInfoDisplay(snap)
 	return err
 }
@@ -2,10 +2,8 @@ package cmd
 
 import (
 	"fmt"
-	"os"
 
 	"github.com/gluster/glusterd2/pkg/api"
-	"github.com/olekukonko/tablewriter"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
@@ -32,8 +30,11 @@ func snapshotListHandler(cmd *cobra.Command) error {
 	if err != nil {
 		return err
 	}
+	if printStructured(snaps) {
+		return nil
+	}
 
-	table := tablewriter.NewWriter(os.Stdout)
+	table := newTable()
 	table.SetAutoMergeCells(true)
 	table.SetRowLine(true)
 	if volname == "" {
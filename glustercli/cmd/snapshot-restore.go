@@ -38,5 +38,8 @@ func snapshotRestoreCmdRun(cmd *cobra.Command, args []string) {
 		}
 		failure("snapshot activation failed", err, 1)
 	}
+	if printStructured(vol) {
+		return
+	}
 	fmt.Printf("Snapshot %s restored successfully to volume %s\n", snapname, vol.Name)
 }
@@ -54,6 +54,10 @@ func snapshotStatusHandler(cmd *cobra.Command) error {
 		return err
 	}
 
+	if printStructured(snap) {
+		return nil
+	}
+
 	if snapname == "" {
 		// TODO Status for all snapshot
 	} else {
@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gluster/glusterd2/pkg/api"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+const (
+	helpSupportBundleCmd         = "Collect the state of the cluster for bug reports"
+	helpSupportBundleCreateCmd   = "Create a support bundle with the logs, statedumps, versions and health of all the peers"
+	helpSupportBundleListCmd     = "List the support bundles"
+	helpSupportBundleStatusCmd   = "Show the progress of the creation of a support bundle"
+	helpSupportBundleDownloadCmd = "Download the archive of a support bundle"
+	helpSupportBundleDeleteCmd   = "Delete a support bundle and its archive"
+
+	supportBundlePollInterval = 2 * time.Second
+)
+
+var (
+	flagSupportBundleNoLogs     bool
+	flagSupportBundleLogSize    int64
+	flagSupportBundleWait       bool
+	flagSupportBundleOutputFile string
+)
+
+func init() {
+	supportBundleCreateCmd.Flags().BoolVar(&flagSupportBundleNoLogs, "no-logs", false, "Leave the logs out of the support bundle")
+	supportBundleCreateCmd.Flags().Int64Var(&flagSupportBundleLogSize, "log-size", 0, "Size in bytes of the end of each log collected (default 1MiB)")
+	supportBundleCreateCmd.Flags().BoolVar(&flagSupportBundleWait, "wait", false, "Wait for the support bundle to be created, and download it")
+	supportBundleCreateCmd.Flags().StringVar(&flagSupportBundleOutputFile, "output-file", "", "File the archive is downloaded to with --wait (default support-bundle-<ID>.tar.gz)")
+	supportBundleDownloadCmd.Flags().StringVar(&flagSupportBundleOutputFile, "output-file", "", "File the archive is downloaded to (default support-bundle-<ID>.tar.gz)")
+
+	supportBundleCmd.AddCommand(supportBundleCreateCmd)
+	supportBundleCmd.AddCommand(supportBundleListCmd)
+	supportBundleCmd.AddCommand(supportBundleStatusCmd)
+	supportBundleCmd.AddCommand(supportBundleDownloadCmd)
+	supportBundleCmd.AddCommand(supportBundleDeleteCmd)
+	clusterCmd.AddCommand(supportBundleCmd)
+}
+
+var supportBundleCmd = &cobra.Command{
+	Use:   "support-bundle",
+	Short: helpSupportBundleCmd,
+}
+
+var supportBundleCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: helpSupportBundleCreateCmd,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		req := api.SupportBundleReq{
+			NoLogs:  flagSupportBundleNoLogs,
+			LogSize: flagSupportBundleLogSize,
+		}
+		b, err := client.SupportBundleCreate(req)
+		if err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).Error("failed to create the support bundle")
+			}
+			failure("Failed to create the support bundle", err, 1)
+		}
+
+		if !flagSupportBundleWait {
+			if !printStructured(b) {
+				fmt.Printf("Creating support bundle %s, check its progress with:\n\n", b.ID)
+				fmt.Printf("  glustercli cluster support-bundle status %s\n", b.ID)
+			}
+			return
+		}
+
+		for b.State == api.SupportBundleRunning {
+			printMessagef("\rCollecting... %d%%", b.Progress)
+			time.Sleep(supportBundlePollInterval)
+			if b, err = client.SupportBundleStatus(b.ID.String()); err != nil {
+				failure("Failed to get the progress of the support bundle", err, 1)
+			}
+		}
+		printMessagef("\n")
+		if b.State == api.SupportBundleFailed {
+			failure("Failed to create the support bundle", errors.New(b.Error), 1)
+		}
+		downloadSupportBundle(b.ID.String())
+	},
+}
+
+var supportBundleListCmd = &cobra.Command{
+	Use:   "list",
+	Short: helpSupportBundleListCmd,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		bundles, err := client.SupportBundleList()
+		if err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).Error("failed to list the support bundles")
+			}
+			failure("Failed to list the support bundles", err, 1)
+		}
+
+		if printStructured(bundles) {
+			return
+		}
+
+		table := newTable()
+		table.SetHeader([]string{"ID", "State", "Progress", "Peer", "Size", "Created At"})
+		for _, b := range bundles {
+			size := ""
+			if b.State == api.SupportBundleCompleted {
+				size = humanReadable(uint64(b.Size))
+			}
+			table.Append([]string{b.ID.String(), b.State, fmt.Sprintf("%d%%", b.Progress),
+				b.PeerID.String(), size, b.CreatedAt.Format(time.RFC3339)})
+		}
+		table.Render()
+	},
+}
+
+var supportBundleStatusCmd = &cobra.Command{
+	Use:   "status <ID>",
+	Short: helpSupportBundleStatusCmd,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		b, err := client.SupportBundleStatus(args[0])
+		if err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).WithField("id", args[0]).Error("failed to get the support bundle")
+			}
+			failure("Failed to get the support bundle", err, 1)
+		}
+
+		if printStructured(b) {
+			return
+		}
+
+		fmt.Printf("Support bundle %s: %s, %d%% collected\n", b.ID, b.State, b.Progress)
+		if b.Error != "" {
+			fmt.Printf("Error: %s\n", b.Error)
+		}
+		if b.State == api.SupportBundleCompleted {
+			fmt.Printf("Size: %s\n", humanReadable(uint64(b.Size)))
+		}
+
+		table := newTable()
+		table.SetHeader([]string{"Peer", "State", "Error"})
+		for _, p := range b.Peers {
+			table.Append([]string{p.Name, p.State, strings.TrimSpace(p.Error)})
+		}
+		table.Render()
+	},
+}
+
+var supportBundleDownloadCmd = &cobra.Command{
+	Use:   "download <ID>",
+	Short: helpSupportBundleDownloadCmd,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		downloadSupportBundle(args[0])
+	},
+}
+
+var supportBundleDeleteCmd = &cobra.Command{
+	Use:   "delete <ID>",
+	Short: helpSupportBundleDeleteCmd,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := client.SupportBundleDelete(args[0]); err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).WithField("id", args[0]).Error("failed to delete the support bundle")
+			}
+			failure("Failed to delete the support bundle", err, 1)
+		}
+		printMessagef("Support bundle %s deleted\n", args[0])
+	},
+}
+
+// downloadSupportBundle downloads the archive of the support bundle to the
+// output file
+func downloadSupportBundle(id string) {
+	path := flagSupportBundleOutputFile
+	if path == "" {
+		path = "support-bundle-" + id + ".tar.gz"
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		failure("Failed to download the support bundle", err, 1)
+	}
+	if err := client.SupportBundleDownload(id, f); err != nil {
+		f.Close()
+		os.Remove(path)
+		if GlobalFlag.Verbose {
+			log.WithError(err).WithField("id", id).Error("failed to download the support bundle")
+		}
+		failure("Failed to download the support bundle", err, 1)
+	}
+	if err := f.Close(); err != nil {
+		failure("Failed to download the support bundle", err, 1)
+	}
+	printMessagef("Support bundle downloaded to %s\n", path)
+}
@@ -3,9 +3,7 @@ package cmd
 import (
 	"errors"
 	"fmt"
-	"os"
 
-	"github.com/gluster/glusterd2/pkg/tracing"
 	tracemgmtapi "github.com/gluster/glusterd2/plugins/tracemgmt/api"
 
 	"github.com/olekukonko/tablewriter"
@@ -79,7 +77,7 @@ var traceEnableCmd = &cobra.Command{
 
 		validateJaegerSampler()
 
-		_, err := client.TraceEnable(tracemgmtapi.SetupTracingReq{
+		jaegerConfigInfo, err := client.TraceEnable(tracemgmtapi.SetupTracingReq{
 			JaegerEndpoint:       jaegerEndpoint,
 			JaegerAgentEndpoint:  jaegerAgentEndpoint,
 			JaegerSampler:        flagTraceJaegerSampler,
@@ -97,6 +95,9 @@ var traceEnableCmd = &cobra.Command{
 			}
 			failure(errTraceEnableReqFailed, err, 1)
 		}
+		if printStructured(jaegerConfigInfo) {
+			return
+		}
 		fmt.Println("Trace enable successful")
 	},
 }
@@ -109,16 +110,19 @@ var traceStatusCmd = &cobra.Command{
 		if err != nil {
 			failure("Error getting trace status", err, 1)
 		}
+		if printStructured(jaegerConfigInfo) {
+			return
+		}
 
-		table := tablewriter.NewWriter(os.Stdout)
+		table := newTable()
 		table.SetAlignment(tablewriter.ALIGN_LEFT)
 		table.SetHeader([]string{"Trace Option", "Value"})
 
 		table.Append([]string{"Status", jaegerConfigInfo.Status})
 		table.Append([]string{"Jaeger Endpoint", jaegerConfigInfo.JaegerEndpoint})
 		table.Append([]string{"Jaeger Agent Endpoint", jaegerConfigInfo.JaegerAgentEndpoint})
-		jaegerSampler := tracing.JaegerSamplerType(jaegerConfigInfo.JaegerSampler)
-		table.Append([]string{"Jaeger Sampler", fmt.Sprintf("%d (%s)", jaegerConfigInfo.JaegerSampler, tracing.SamplerTypeToString(jaegerSampler))})
+		jaegerSampler := tracemgmtapi.JaegerSamplerType(jaegerConfigInfo.JaegerSampler)
+		table.Append([]string{"Jaeger Sampler", fmt.Sprintf("%d (%s)", jaegerConfigInfo.JaegerSampler, tracemgmtapi.SamplerTypeToString(jaegerSampler))})
 		table.Append([]string{"Jaeger Sample Fraction", fmt.Sprintf("%0.2f", jaegerConfigInfo.JaegerSampleFraction)})
 		table.Render()
 		fmt.Println()
@@ -132,7 +136,7 @@ var traceUpdateCmd = &cobra.Command{
 
 		validateJaegerSampler()
 
-		_, err := client.TraceUpdate(tracemgmtapi.SetupTracingReq{
+		jaegerConfigInfo, err := client.TraceUpdate(tracemgmtapi.SetupTracingReq{
 			JaegerEndpoint:       flagTraceJaegerEndpoint,
 			JaegerAgentEndpoint:  flagTraceJaegerAgentEndpoint,
 			JaegerSampler:        flagTraceJaegerSampler,
@@ -150,6 +154,9 @@ var traceUpdateCmd = &cobra.Command{
 			}
 			failure(errTraceUpdateReqFailed, err, 1)
 		}
+		if printStructured(jaegerConfigInfo) {
+			return
+		}
 		fmt.Println("Trace update successful")
 	},
 }
@@ -162,6 +169,6 @@ var traceDisableCmd = &cobra.Command{
 		if err != nil {
 			failure("Trace disable failed", err, 1)
 		}
-		fmt.Println("Trace disable successful")
+		printMessage("Trace disable successful")
 	},
 }
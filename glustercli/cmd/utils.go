@@ -7,7 +7,9 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/gluster/glusterd2/pkg/api"
 	"github.com/gluster/glusterd2/pkg/utils"
 )
 
@@ -29,6 +31,34 @@ func formatPID(pid int) string {
 	return strconv.Itoa(pid)
 }
 
+func formatLiveness(l *api.PeerLiveness) string {
+	if l == nil {
+		return ""
+	}
+	s := fmt.Sprintf("%s since %s", l.State, l.Since.Format(time.RFC3339))
+	if l.ClockSkewed {
+		s += fmt.Sprintf("\nclock skewed by %.0fms", l.ClockOffsetMs)
+	}
+	return s
+}
+
+// formatResourceUsage returns the CPU time and the memory used by the cgroup
+// of a process, along with their limits
+func formatResourceUsage(u *api.ResourceUsage) (string, string) {
+	if u == nil {
+		return "", ""
+	}
+	cpu := fmt.Sprintf("%.1fs", u.CPUSeconds)
+	if u.CPULimitPercent > 0 {
+		cpu += fmt.Sprintf(" (limit %d%%)", u.CPULimitPercent)
+	}
+	memory := humanReadable(u.MemoryBytes)
+	if u.MemoryLimitBytes > 0 {
+		memory += " / " + humanReadable(u.MemoryLimitBytes)
+	}
+	return cpu, memory
+}
+
 func sizeToBytes(value string) (uint64, error) {
 	if value == "" {
 		return 0, nil
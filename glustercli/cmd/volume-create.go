@@ -2,9 +2,9 @@ package cmd
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
-	"github.com/gluster/glusterd2/glusterd2/volume"
 	"github.com/gluster/glusterd2/pkg/api"
 
 	log "github.com/sirupsen/logrus"
@@ -29,7 +29,10 @@ var (
 	flagCreateExpOpts                 bool
 	flagCreateDepOpts                 bool
 	flagCreateThinArbiter             string
+	flagCreateBrickUser               string
 	flagCreateVolumeOptions           []string
+	flagCreateNamespace               string
+	flagCreateLabels                  []string
 
 	flagCreateVolumeSize            string
 	flagCreateDistributeCount       int
@@ -43,6 +46,8 @@ var (
 	flagAverageFileSize             string
 	flagCreateMaxBrickSize          string
 	flagProvisionerType             string
+	flagCreateEncrypted             bool
+	flagCreatePreview               bool
 
 	volumeCreateCmd = &cobra.Command{
 		Use:   "create <volname> [<brick> [<brick>]...|--size <size>]",
@@ -63,7 +68,8 @@ func init() {
 	volumeCreateCmd.Flags().IntVar(&flagCreateDisperseDataCount, "disperse-data", 0, "Disperse Data Count")
 	volumeCreateCmd.Flags().IntVar(&flagCreateDisperseRedundancyCount, "redundancy", 0, "Redundancy Count")
 	volumeCreateCmd.Flags().StringVar(&flagCreateTransport, "transport", "tcp", "Transport")
-	volumeCreateCmd.Flags().BoolVar(&flagCreateForce, "force", false, "Force")
+	volumeCreateCmd.Flags().BoolVar(&flagCreateForce, "force", false, "Skip the brick checks, and clean the xattrs of reused bricks")
+	volumeCreateCmd.Flags().StringVar(&flagCreateBrickUser, "brick-user", "", "User the brick processes are run as (default is the brick-user of glusterd2)")
 	volumeCreateCmd.Flags().StringSliceVar(&flagCreateVolumeOptions, "options", nil,
 		"Volume options in the format option:value,option:value")
 
@@ -76,6 +82,8 @@ func init() {
 	volumeCreateCmd.Flags().BoolVar(&flagAllowRootDir, "allow-root-dir", false, "Allow root directory")
 	volumeCreateCmd.Flags().BoolVar(&flagAllowMountAsBrick, "allow-mount-as-brick", false, "Allow mount as bricks")
 	volumeCreateCmd.Flags().BoolVar(&flagCreateBrickDir, "create-brick-dir", false, "Create brick directory")
+	volumeCreateCmd.Flags().StringVar(&flagCreateNamespace, "namespace", "", "Namespace the volume belongs to")
+	volumeCreateCmd.Flags().StringSliceVar(&flagCreateLabels, "label", nil, "Labels of the volume, as key=value")
 
 	// Smart Volume Flags
 	volumeCreateCmd.Flags().StringVar(&flagCreateVolumeSize, "size", "", "Size of the Volume")
@@ -90,10 +98,21 @@ func init() {
 	volumeCreateCmd.Flags().StringVar(&flagAverageFileSize, "average-file-size", "1M", "Average size of the files")
 	volumeCreateCmd.Flags().StringVar(&flagCreateMaxBrickSize, "max-brick-size", "", "Max brick size for auto distribute count")
 	volumeCreateCmd.Flags().StringVar(&flagProvisionerType, "provisioner", "lvm", "Brick Provisioner Type(lvm, loop)")
+	volumeCreateCmd.Flags().BoolVar(&flagCreateEncrypted, "encrypt", false, "Encrypt the bricks at rest with dm-crypt")
+	volumeCreateCmd.Flags().BoolVar(&flagCreatePreview, "preview", false, "Only show where the bricks would be placed, without creating the Volume")
 
 	volumeCmd.AddCommand(volumeCreateCmd)
 }
 
+// createLabels returns the labels given to the volume to create
+func createLabels() map[string]string {
+	labels, err := parseLabels(flagCreateLabels)
+	if err != nil {
+		failure("Invalid labels specified", err, 1)
+	}
+	return labels
+}
+
 func smartVolumeCreate(cmd *cobra.Command, args []string) {
 	size, err := sizeToBytes(flagCreateVolumeSize)
 	if err != nil {
@@ -148,6 +167,15 @@ func smartVolumeCreate(cmd *cobra.Command, args []string) {
 		SubvolZonesOverlap:      flagCreateSubvolZoneOverlap,
 		Force:                   flagCreateForce,
 		ProvisionerType:         flagProvisionerType,
+		Encrypted:               flagCreateEncrypted,
+		BrickUser:               flagCreateBrickUser,
+		Namespace:               flagCreateNamespace,
+		Labels:                  createLabels(),
+	}
+
+	if flagCreatePreview {
+		placementPreview(req)
+		return
 	}
 
 	vol, err := client.VolumeCreate(req)
@@ -158,10 +186,48 @@ func smartVolumeCreate(cmd *cobra.Command, args []string) {
 		}
 		failure("Volume creation failed", err, 1)
 	}
+	if printStructured(vol) {
+		return
+	}
 	fmt.Printf("%s Volume created successfully\n", vol.Name)
 	fmt.Println("Volume ID: ", vol.ID)
 }
 
+func placementPreview(req api.VolCreateReq) {
+	preview, err := client.PlacementPreview(req)
+	if err != nil {
+		if GlobalFlag.Verbose {
+			log.WithError(err).WithField("volume", req.Name).Error("placement preview failed")
+		}
+		failure("Placement preview failed", err, 1)
+	}
+	if printStructured(preview) {
+		return
+	}
+
+	if preview.Feasible {
+		fmt.Printf("Volume %s can be provisioned\n", req.Name)
+		table := newTable()
+		table.SetHeader([]string{"Subvol", "Brick", "Peer ID", "Device", "Size"})
+		for i, sv := range preview.Subvols {
+			for _, b := range sv.Bricks {
+				table.Append([]string{strconv.Itoa(i + 1), b.Path, b.PeerID, b.RootDevice, humanReadable(b.Size)})
+			}
+		}
+		table.Render()
+	} else {
+		fmt.Printf("Volume %s cannot be provisioned: %s\n", req.Name, preview.Reason)
+	}
+
+	table := newTable()
+	table.SetHeader([]string{"Peer ID", "Zone", "Device", "Bricks", "Available", "Remaining"})
+	for _, d := range preview.Devices {
+		table.Append([]string{d.PeerID, d.Zone, d.Device, strconv.Itoa(d.Bricks), humanReadable(d.AvailableSize), humanReadable(d.RemainingSize)})
+	}
+	table.Render()
+	fmt.Printf("Available: %s, Remaining: %s\n", humanReadable(preview.AvailableSize), humanReadable(preview.RemainingSize))
+}
+
 func volumeCreateCmdRun(cmd *cobra.Command, args []string) {
 	if flagCreateVolumeSize != "" {
 		smartVolumeCreate(cmd, args)
@@ -295,9 +361,12 @@ func volumeCreateCmdRun(cmd *cobra.Command, args []string) {
 	}
 
 	req := api.VolCreateReq{
-		Name:    volname,
-		Subvols: subvols,
-		Force:   flagCreateForce,
+		Name:      volname,
+		Subvols:   subvols,
+		Force:     flagCreateForce,
+		BrickUser: flagCreateBrickUser,
+		Namespace: flagCreateNamespace,
+		Labels:    createLabels(),
 		VolOptionReq: api.VolOptionReq{
 			Options: options,
 			VolOptionFlags: api.VolOptionFlags{
@@ -316,7 +385,7 @@ func volumeCreateCmdRun(cmd *cobra.Command, args []string) {
 			fmt.Println("Thin arbiter can only be enabled for replica count 2")
 			return
 		}
-		if err := volume.AddThinArbiter(&req, cmd.Flag("thin-arbiter").Value.String()); err != nil {
+		if err := req.AddThinArbiter(cmd.Flag("thin-arbiter").Value.String()); err != nil {
 			fmt.Println(err)
 			return
 		}
@@ -329,6 +398,9 @@ func volumeCreateCmdRun(cmd *cobra.Command, args []string) {
 		}
 		failure("Volume creation failed", err, 1)
 	}
+	if printStructured(vol) {
+		return
+	}
 	fmt.Printf("%s Volume created successfully\n", vol.Name)
 	fmt.Println("Volume ID: ", vol.ID)
 }
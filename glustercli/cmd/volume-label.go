@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gluster/glusterd2/pkg/api"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+const helpVolumeLabelCmd = "Set or remove labels of a volume, key=value sets a label and key- removes it"
+
+func init() {
+	volumeCmd.AddCommand(volumeLabelCmd)
+}
+
+// parseLabels parses the labels given as key=value
+func parseLabels(args []string) (map[string]string, error) {
+	if len(args) == 0 {
+		return nil, nil
+	}
+	labels := make(map[string]string, len(args))
+	for _, arg := range args {
+		parts := strings.SplitN(arg, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid label %q, expected key=value", arg)
+		}
+		labels[parts[0]] = parts[1]
+	}
+	return labels, nil
+}
+
+// formatLabels returns the labels as key=value, sorted by key
+func formatLabels(labels map[string]string) string {
+	list := make([]string, 0, len(labels))
+	for key, value := range labels {
+		list = append(list, key+"="+value)
+	}
+	sort.Strings(list)
+	return strings.Join(list, ",")
+}
+
+var volumeLabelCmd = &cobra.Command{
+	Use:   "label <volname> <key>=<value>|<key>- [<key>=<value>|<key>-]...",
+	Short: helpVolumeLabelCmd,
+	Args:  cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		volname := args[0]
+		req := api.VolPatchReq{Labels: make(map[string]*string)}
+		for _, arg := range args[1:] {
+			if strings.HasSuffix(arg, "-") && !strings.Contains(arg, "=") {
+				req.Labels[strings.TrimSuffix(arg, "-")] = nil
+				continue
+			}
+			labels, err := parseLabels([]string{arg})
+			if err != nil {
+				failure("Invalid label specified", err, 1)
+			}
+			for key, value := range labels {
+				value := value
+				req.Labels[key] = &value
+			}
+		}
+
+		vol, err := client.VolumePatch(volname, req)
+		if err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).WithField("volume", volname).Error("failed to label volume")
+			}
+			failure("Failed to label volume", err, 1)
+		}
+		if printStructured(vol) {
+			return
+		}
+		fmt.Printf("Volume %s labeled: %s\n", volname, formatLabels(vol.Labels))
+	},
+}
@@ -2,11 +2,9 @@ package cmd
 
 import (
 	"fmt"
-	"os"
 
 	"github.com/gluster/glusterd2/pkg/api"
 
-	"github.com/olekukonko/tablewriter"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
@@ -67,11 +65,14 @@ var volumeProfileInfoCmd = &cobra.Command{
 			log.WithError(err).WithField("volname", volname).Error("failed to get volume profile info")
 			failure(fmt.Sprintf("Failed to get volume profile info for volume %s\n", volname), err, 1)
 		}
+		if printStructured(volumeProfileInfo) {
+			return
+		}
 		// Iterate over all bricks
 		for index := range volumeProfileInfo {
 
 			// Display Cumulative Stats
-			table := tablewriter.NewWriter(os.Stdout)
+			table := newTable()
 			fmt.Printf("Brick: %s\n\n", volumeProfileInfo[index].BrickName)
 			if volumeProfileInfo[index].CumulativeStats.Interval != "" {
 				fmt.Printf("Cumulative Stats: \n")
@@ -97,7 +98,7 @@ var volumeProfileInfoCmd = &cobra.Command{
 			fmt.Printf("\n\n")
 
 			// Display Interval Stats
-			table = tablewriter.NewWriter(os.Stdout)
+			table = newTable()
 			table.SetHeader([]string{"%-Latency", "AvgLatency", "MinLatency", "MaxLatency", "No. Of Calls", "FOP"})
 			if volumeProfileInfo[index].IntervalStats.Interval != "" {
 				fmt.Printf("Interval %s Stats: \n\n", volumeProfileInfo[index].IntervalStats.Interval)
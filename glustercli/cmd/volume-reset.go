@@ -2,7 +2,6 @@ package cmd
 
 import (
 	"errors"
-	"fmt"
 
 	"github.com/gluster/glusterd2/pkg/api"
 
@@ -47,7 +46,7 @@ var volumeResetCmd = &cobra.Command{
 			}
 			failure("Volume reset failed", err, 1)
 		}
-		fmt.Printf("Volume options reset successfully\n")
+		printMessagef("Volume options reset successfully\n")
 	},
 }
 
@@ -11,8 +11,10 @@ import (
 )
 
 const (
-	volumeSetCmdHelpShort = "Set volume options"
-	volumeSetCmdHelpLong  = "Set options on a specified gluster volume. Needs a volume name and at least one option-value pair."
+	volumeSetCmdHelpShort      = "Set volume options"
+	volumeSetCmdHelpLong       = "Set options on a specified gluster volume. Needs a volume name and at least one option-value pair."
+	volumeSetBatchCmdHelpShort = "Set volume options on many volumes"
+	volumeSetBatchCmdHelpLong  = "Set options on the gluster volumes selected by a label selector, or on all the volumes, one volume after the other. Needs at least one option-value pair."
 )
 
 var (
@@ -25,6 +27,18 @@ var (
 		Args:  volumeSetCmdArgs,
 		Run:   volumeSetCmdRun,
 	}
+
+	flagSetBatchSelector    string
+	flagSetBatchAll         bool
+	flagSetBatchStopOnError bool
+
+	volumeSetBatchCmd = &cobra.Command{
+		Use:   "set-batch [--selector <selector>|--all] <option> <value> [<option> <value>]...",
+		Short: volumeSetBatchCmdHelpShort,
+		Long:  volumeSetBatchCmdHelpLong,
+		Args:  volumeSetBatchCmdArgs,
+		Run:   volumeSetBatchCmdRun,
+	}
 )
 
 func init() {
@@ -32,6 +46,14 @@ func init() {
 	volumeSetCmd.Flags().BoolVar(&flagSetExp, "experimental", false, "Allow setting experimental options")
 	volumeSetCmd.Flags().BoolVar(&flagSetDep, "deprecated", false, "Allow setting deprecated options")
 	volumeCmd.AddCommand(volumeSetCmd)
+
+	volumeSetBatchCmd.Flags().StringVarP(&flagSetBatchSelector, "selector", "l", "", "Label selector of the volumes, like env=prod,team=db")
+	volumeSetBatchCmd.Flags().BoolVar(&flagSetBatchAll, "all", false, "Set the options on all the volumes")
+	volumeSetBatchCmd.Flags().BoolVar(&flagSetBatchStopOnError, "stop-on-error", false, "Skip the remaining volumes once a volume fails")
+	volumeSetBatchCmd.Flags().BoolVar(&flagSetAdv, "advanced", false, "Allow setting advanced options")
+	volumeSetBatchCmd.Flags().BoolVar(&flagSetExp, "experimental", false, "Allow setting experimental options")
+	volumeSetBatchCmd.Flags().BoolVar(&flagSetDep, "deprecated", false, "Allow setting deprecated options")
+	volumeCmd.AddCommand(volumeSetBatchCmd)
 }
 
 func volumeSetCmdArgs(cmd *cobra.Command, args []string) error {
@@ -58,7 +80,7 @@ func volumeSetCmdRun(cmd *cobra.Command, args []string) {
 		}
 		failure("Volume option set failed", err, 1)
 	} else {
-		fmt.Printf("Options set successfully for %s volume\n", volname)
+		printMessagef("Options set successfully for %s volume\n", volname)
 	}
 }
 
@@ -73,6 +95,11 @@ func volumeOptionJSONHandler(cmd *cobra.Command, volname string, options []strin
 	if volname == "all" {
 		err := client.ClusterOptionSet(api.ClusterOptionReq{
 			Options: vopt,
+			VolOptionFlags: api.VolOptionFlags{
+				AllowAdvanced:     flagSetAdv,
+				AllowExperimental: flagSetExp,
+				AllowDeprecated:   flagSetDep,
+			},
 		})
 		return err
 	}
@@ -88,3 +115,55 @@ func volumeOptionJSONHandler(cmd *cobra.Command, volname string, options []strin
 
 	return err
 }
+
+func volumeSetBatchCmdArgs(cmd *cobra.Command, args []string) error {
+	if len(args) < 2 {
+		return errors.New("need at least 2 arguments")
+	}
+	if len(args)%2 != 0 {
+		return errors.New("needs '<option> <value>' to be in pairs")
+	}
+	return nil
+}
+
+func volumeSetBatchCmdRun(cmd *cobra.Command, args []string) {
+	vopt := make(map[string]string)
+	for i := 0; i < len(args); i += 2 {
+		vopt[args[i]] = args[i+1]
+	}
+
+	resp, err := client.VolumeSetBatch(api.VolOptionsBatchReq{
+		Selector:    flagSetBatchSelector,
+		All:         flagSetBatchAll,
+		StopOnError: flagSetBatchStopOnError,
+		VolOptionReq: api.VolOptionReq{
+			Options: vopt,
+			VolOptionFlags: api.VolOptionFlags{
+				AllowAdvanced:     flagSetAdv,
+				AllowExperimental: flagSetExp,
+				AllowDeprecated:   flagSetDep,
+			},
+		},
+	})
+	if err != nil {
+		if GlobalFlag.Verbose {
+			log.WithError(err).WithField("selector", flagSetBatchSelector).Error("batch volume option set failed")
+		}
+		failure("Batch volume option set failed", err, 1)
+	}
+
+	if printStructured(resp) {
+		return
+	}
+
+	table := newTable()
+	table.SetHeader([]string{"Volume", "Status", "Error"})
+	for _, r := range resp.Results {
+		table.Append([]string{r.Volume, r.Status, r.Error})
+	}
+	table.Render()
+	fmt.Printf("Set: %d, Failed: %d, Skipped: %d\n", resp.Set, resp.Failed, resp.Skipped)
+	if resp.Failed > 0 {
+		failure("Failed to set options on some volumes", nil, 1)
+	}
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"errors"
+	"time"
+
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+const (
+	helpVolumeTrashCmd    = "List the deleted volumes retained in the trash"
+	helpVolumeUndeleteCmd = "Restore a deleted volume from the trash, not started"
+	helpVolumePurgeCmd    = "Delete a volume retained in the trash for good"
+)
+
+func init() {
+	volumeCmd.AddCommand(volumeTrashCmd)
+	volumeCmd.AddCommand(volumeUndeleteCmd)
+	volumeCmd.AddCommand(volumePurgeCmd)
+}
+
+var volumeTrashCmd = &cobra.Command{
+	Use:   "trash",
+	Short: helpVolumeTrashCmd,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		trashed, err := client.VolumeTrashList()
+		if err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).Error("failed to list the trash")
+			}
+			failure("Failed to list the deleted volumes", err, 1)
+		}
+
+		if printStructured(trashed) {
+			return
+		}
+
+		table := newTable()
+		table.SetHeader([]string{"ID", "Name", "Type", "Deleted At", "Purge At", "Brick Data"})
+		for _, t := range trashed {
+			table.Append([]string{t.Volume.ID.String(), t.Volume.Name, t.Volume.Type.String(),
+				t.DeletedAt.Format(time.RFC3339), t.PurgeAt.Format(time.RFC3339), t.BrickData})
+		}
+		table.Render()
+	},
+}
+
+var volumeUndeleteCmd = &cobra.Command{
+	Use:   "undelete <VOLID>",
+	Short: helpVolumeUndeleteCmd,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		volID := args[0]
+		if uuid.Parse(volID) == nil {
+			failure("Volume undelete failed", errors.New("failed to parse volume ID"), 1)
+		}
+		vol, err := client.VolumeUndelete(volID)
+		if err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).WithField("id", volID).Error("volume undelete failed")
+			}
+			failure("Volume undelete failed", err, 1)
+		}
+		printMessagef("Volume %s undeleted successfully, start it to use it\n", vol.Name)
+	},
+}
+
+var volumePurgeCmd = &cobra.Command{
+	Use:   "purge <VOLID>",
+	Short: helpVolumePurgeCmd,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		volID := args[0]
+		if uuid.Parse(volID) == nil {
+			failure("Volume purge failed", errors.New("failed to parse volume ID"), 1)
+		}
+		if !GlobalFlag.ScriptMode {
+			if ok := PromptConfirm("Are you sure you want to delete volume %s and its data for good [yes/no]? ", volID); !ok {
+				return
+			}
+		}
+		if err := client.VolumePurge(volID); err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).WithField("id", volID).Error("volume purge failed")
+			}
+			failure("Volume purge failed", err, 1)
+		}
+		printMessagef("Volume %s purged successfully\n", volID)
+	},
+}
@@ -3,7 +3,6 @@ package cmd
 import (
 	"errors"
 	"fmt"
-	"os"
 	"strconv"
 	"strings"
 
@@ -32,11 +31,16 @@ const (
 
 var (
 	// Start Command Flags
-	flagStartCmdForce bool
+	flagStartCmdForce         bool
+	flagStartCmdResetVolumeID bool
 
 	// Stop Command Flags
 	flagStopCmdForce bool
 
+	// Delete Command Flags
+	flagDeleteCmdPurge     bool
+	flagDeleteCmdBrickData string
+
 	// Expand Command Flags
 	flagExpandCmdReplicaCount    int
 	flagExpandCmdForce           bool
@@ -46,6 +50,7 @@ var (
 	// Filter Volume Info/List command flags
 	flagCmdFilterKey   string
 	flagCmdFilterValue string
+	flagCmdSelector    string
 
 	//Filter Volume Get command flags
 	flagGetAdv bool
@@ -63,13 +68,16 @@ var (
 func init() {
 	// Volume Start
 	volumeStartCmd.Flags().BoolVarP(&flagStartCmdForce, "force", "f", false, "Force")
+	volumeStartCmd.Flags().BoolVar(&flagStartCmdResetVolumeID, "reset-volume-id", false, "Take over brick paths which belong to another volume or hold data without a volume ID")
 	volumeCmd.AddCommand(volumeStartCmd)
 
 	// Volume Stop
-	volumeStopCmd.Flags().BoolVarP(&flagStopCmdForce, "force", "f", false, "Force")
+	volumeStopCmd.Flags().BoolVarP(&flagStopCmdForce, "force", "f", false, "Force stop the volume, disconnecting its clients")
 	volumeCmd.AddCommand(volumeStopCmd)
 
 	// Volume Delete
+	volumeDeleteCmd.Flags().BoolVar(&flagDeleteCmdPurge, "purge", false, "Delete the volume for good, instead of retaining it in the trash")
+	volumeDeleteCmd.Flags().StringVar(&flagDeleteCmdBrickData, "brick-data", api.BrickDataKeep, "What to do with the data of the bricks when the volume is purged: keep, metadata (remove the gluster metadata only) or wipe")
 	volumeCmd.AddCommand(volumeDeleteCmd)
 
 	volumeGetCmd.Flags().BoolVar(&flagGetAdv, "advanced", false, "Get advanced options")
@@ -81,19 +89,21 @@ func init() {
 
 	volumeInfoCmd.Flags().StringVar(&flagCmdFilterKey, "key", "", "Filter by metadata key")
 	volumeInfoCmd.Flags().StringVar(&flagCmdFilterValue, "value", "", "Filter by metadata value")
+	volumeInfoCmd.Flags().StringVarP(&flagCmdSelector, "selector", "l", "", "Filter by label selector, like env=prod,team=db")
 	volumeCmd.AddCommand(volumeInfoCmd)
 
 	volumeCmd.AddCommand(volumeStatusCmd)
 
 	volumeListCmd.Flags().StringVar(&flagCmdFilterKey, "key", "", "Filter by metadata Key")
 	volumeListCmd.Flags().StringVar(&flagCmdFilterValue, "value", "", "Filter by metadata value")
+	volumeListCmd.Flags().StringVarP(&flagCmdSelector, "selector", "l", "", "Filter by label selector, like env=prod,team=db")
 	volumeCmd.AddCommand(volumeListCmd)
 
 	// Volume Expand
 	volumeExpandCmd.Flags().IntVar(&flagExpandCmdReplicaCount, "replica", 0, "Replica Count")
 	volumeExpandCmd.Flags().IntVar(&flagExpandCmdDistributeCount, "distribute", 0, "Distribute Count")
 	volumeExpandCmd.Flags().StringVar(&flagExpandCmdSize, "size", "", "Size by which volume needs to be expanded.")
-	volumeExpandCmd.Flags().BoolVarP(&flagExpandCmdForce, "force", "f", false, "Force")
+	volumeExpandCmd.Flags().BoolVarP(&flagExpandCmdForce, "force", "f", false, "Skip the brick checks, and clean the xattrs of reused bricks")
 	volumeExpandCmd.Flags().BoolVar(&flagReuseBricks, "reuse-bricks", false, "Reuse Bricks")
 	volumeExpandCmd.Flags().BoolVar(&flagAllowRootDir, "allow-root-dir", false, "Allow Root Directory")
 	volumeExpandCmd.Flags().BoolVar(&flagAllowMountAsBrick, "allow-mount-as-brick", false, "Allow Mount as Bricks")
@@ -182,14 +192,23 @@ var volumeStartCmd = &cobra.Command{
 	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		volname := cmd.Flags().Args()[0]
-		err := client.VolumeStart(volname, flagStartCmdForce)
+		if flagStartCmdResetVolumeID && !GlobalFlag.ScriptMode {
+			if ok := PromptConfirm("Bricks of volume %s may serve the data of another volume, are you sure you want to reset their volume ID [yes/no]? ", volname); !ok {
+				return
+			}
+		}
+		req := api.VolumeStartReq{
+			ForceStartBricks: flagStartCmdForce,
+			ResetVolumeID:    flagStartCmdResetVolumeID,
+		}
+		err := client.VolumeStartWithReq(volname, req)
 		if err != nil {
 			if GlobalFlag.Verbose {
 				log.WithError(err).WithField("volume", volname).Error("volume start failed")
 			}
 			failure("volume start failed", err, 1)
 		}
-		fmt.Printf("Volume %s started successfully\n", volname)
+		printMessagef("Volume %s started successfully\n", volname)
 	},
 }
 
@@ -199,14 +218,19 @@ var volumeStopCmd = &cobra.Command{
 	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		volname := cmd.Flags().Args()[0]
-		err := client.VolumeStop(volname)
+		var err error
+		if flagStopCmdForce {
+			err = client.VolumeStopForce(volname)
+		} else {
+			err = client.VolumeStop(volname)
+		}
 		if err != nil {
 			if GlobalFlag.Verbose {
 				log.WithError(err).WithField("volume", volname).Error("volume stop failed")
 			}
 			failure("Volume stop failed", err, 1)
 		}
-		fmt.Printf("Volume %s stopped successfully\n", volname)
+		printMessagef("Volume %s stopped successfully\n", volname)
 	},
 }
 
@@ -221,14 +245,17 @@ var volumeDeleteCmd = &cobra.Command{
 				return
 			}
 		}
-		err := client.VolumeDelete(volname)
+		err := client.VolumeDelete(volname, api.VolumeDeleteReq{
+			Purge:     flagDeleteCmdPurge,
+			BrickData: flagDeleteCmdBrickData,
+		})
 		if err != nil {
 			if GlobalFlag.Verbose {
 				log.WithError(err).WithField("volume", volname).Error("volume deletion failed")
 			}
 			failure("Volume deletion failed", err, 1)
 		}
-		fmt.Printf("Volume %s deleted successfully\n", volname)
+		printMessagef("Volume %s deleted successfully\n", volname)
 	},
 }
 
@@ -247,22 +274,25 @@ var volumeGetCmd = &cobra.Command{
 			failure("Error getting volume options", err, 1)
 		}
 
-		table := tablewriter.NewWriter(os.Stdout)
-		table.SetHeader([]string{"Name", "Modified", "Value", "Default Value", "Option Level"})
-		table.SetAlignment(tablewriter.ALIGN_LEFT)
-
+		var selected api.VolumeOptionsGetResp
 		for _, opt := range opts {
 			//if modified flag is set, discard unmodified options
 			if flagGetMdf && !opt.Modified {
 				continue
 			}
-			if flagGetBsc && opt.OptionLevel == "Basic" {
-				table.Append([]string{opt.OptName, formatBoolYesNo(opt.Modified), opt.Value, opt.DefaultValue, opt.OptionLevel})
+			if (flagGetBsc && opt.OptionLevel == "Basic") || (flagGetAdv && opt.OptionLevel == "Advanced") {
+				selected = append(selected, opt)
 			}
-			if flagGetAdv && opt.OptionLevel == "Advanced" {
-				table.Append([]string{opt.OptName, formatBoolYesNo(opt.Modified), opt.Value, opt.DefaultValue, opt.OptionLevel})
+		}
+		if printStructured(selected) {
+			return
+		}
 
-			}
+		table := newTable()
+		table.SetHeader([]string{"Name", "Modified", "Value", "Default Value", "Option Level"})
+		table.SetAlignment(tablewriter.ALIGN_LEFT)
+		for _, opt := range selected {
+			table.Append([]string{opt.OptName, formatBoolYesNo(opt.Modified), opt.Value, opt.DefaultValue, opt.OptionLevel})
 		}
 		table.Render()
 
@@ -300,6 +330,15 @@ func volumeInfoDisplay(vol api.VolumeGetResp) {
 	if vol.Capacity != 0 {
 		fmt.Println("Capacity:", humanReadable(vol.Capacity))
 	}
+	if vol.BrickUser != "" {
+		fmt.Println("Brick User:", vol.BrickUser)
+	}
+	if vol.Encrypted {
+		fmt.Println("Encrypted: yes")
+	}
+	if len(vol.Labels) > 0 {
+		fmt.Println("Labels:", formatLabels(vol.Labels))
+	}
 	fmt.Println("Transport-type:", vol.Transport)
 	fmt.Println("Options:")
 	for key, value := range vol.Options {
@@ -327,19 +366,19 @@ func volumeInfoHandler2(cmd *cobra.Command, isInfo bool) error {
 		volname = cmd.Flags().Args()[0]
 	}
 	if volname == "" {
-		if flagCmdFilterKey == "" && flagCmdFilterValue == "" {
-			vols, err = client.Volumes("")
-		} else if flagCmdFilterKey != "" && flagCmdFilterValue == "" {
-			vols, err = client.Volumes("", map[string]string{"key": flagCmdFilterKey})
-		} else if flagCmdFilterKey == "" && flagCmdFilterValue != "" {
-			vols, err = client.Volumes("", map[string]string{"value": flagCmdFilterValue})
-		} else if flagCmdFilterKey != "" && flagCmdFilterValue != "" {
-			vols, err = client.Volumes("", map[string]string{"key": flagCmdFilterKey,
-				"value": flagCmdFilterValue,
-			})
+		filterParams := make(map[string]string)
+		if flagCmdFilterKey != "" {
+			filterParams["key"] = flagCmdFilterKey
 		}
+		if flagCmdFilterValue != "" {
+			filterParams["value"] = flagCmdFilterValue
+		}
+		if flagCmdSelector != "" {
+			filterParams["selector"] = flagCmdSelector
+		}
+		vols, err = client.Volumes("", filterParams)
 	} else {
-		if flagCmdFilterKey != "" || flagCmdFilterValue != "" {
+		if flagCmdFilterKey != "" || flagCmdFilterValue != "" || flagCmdSelector != "" {
 			return errors.New("invalid command. Cannot give filter arguments when providing volname")
 		}
 		vols, err = client.Volumes(volname)
@@ -349,6 +388,10 @@ func volumeInfoHandler2(cmd *cobra.Command, isInfo bool) error {
 		return err
 	}
 
+	if printStructured(vols) {
+		return nil
+	}
+
 	if len(vols) <= 0 {
 		fmt.Println("No volumes found")
 		return nil
@@ -359,7 +402,7 @@ func volumeInfoHandler2(cmd *cobra.Command, isInfo bool) error {
 			volumeInfoDisplay(vol)
 		}
 	} else {
-		table := tablewriter.NewWriter(os.Stdout)
+		table := newTable()
 		table.SetAlignment(tablewriter.ALIGN_LEFT)
 		table.SetHeader([]string{"ID", "Name", "Type", "State", "Transport", "Bricks"})
 		for _, vol := range vols {
@@ -379,7 +422,7 @@ func volumeInfoHandler2(cmd *cobra.Command, isInfo bool) error {
 }
 
 var volumeInfoCmd = &cobra.Command{
-	Use:   "info [<volname> |--key <key>|--value <value>|--key <key> --value <value>]",
+	Use:   "info [<volname> |--key <key>|--value <value>|--key <key> --value <value>|--selector <selector>]",
 	Short: helpVolumeInfoCmd,
 	Args:  cobra.RangeArgs(0, 1),
 	Run: func(cmd *cobra.Command, args []string) {
@@ -394,7 +437,7 @@ var volumeInfoCmd = &cobra.Command{
 }
 
 var volumeListCmd = &cobra.Command{
-	Use:   "list [--key <key>|--value <value>|--key <key> --value <value>]",
+	Use:   "list [--key <key>|--value <value>|--key <key> --value <value>|--selector <selector>]",
 	Short: helpVolumeListCmd,
 	Args:  cobra.RangeArgs(0, 1),
 	Run: func(cmd *cobra.Command, args []string) {
@@ -409,11 +452,12 @@ var volumeListCmd = &cobra.Command{
 }
 
 func volumeStatusDisplay(vol api.BricksStatusResp) {
-	table := tablewriter.NewWriter(os.Stdout)
-	table.SetHeader([]string{"Brick ID", "Host", "Path", "Online", "Port", "Pid"})
+	table := newTable()
+	table.SetHeader([]string{"Brick ID", "Host", "Path", "Online", "Port", "Pid", "CPU", "Memory"})
 	for _, b := range vol {
+		cpu, memory := formatResourceUsage(b.Resources)
 		table.Append([]string{b.Info.ID.String(), b.Info.Hostname, b.Info.Path,
-			strconv.FormatBool(b.Online), strconv.Itoa(b.Port), strconv.Itoa(b.Pid)})
+			strconv.FormatBool(b.Online), strconv.Itoa(b.Port), strconv.Itoa(b.Pid), cpu, memory})
 	}
 	table.Render()
 }
@@ -428,26 +472,35 @@ func volumeStatusHandler(cmd *cobra.Command) error {
 	if volname == "" {
 		var volList api.VolumeListResp
 		volList, err = client.Volumes("")
-		if len(volList) <= 0 {
+		if err != nil {
+			return err
+		}
+		if len(volList) <= 0 && !isStructuredOutput() {
 			fmt.Println("No volumes found")
 			return nil
 		}
+		// bricks status of all the volumes keyed by volume name
+		statuses := make(map[string]api.BricksStatusResp)
 		for _, volume := range volList {
 			vol, err = client.BricksStatus(volume.Name)
-			fmt.Println("Volume :", volume.Name)
-			if err == nil {
-				volumeStatusDisplay(vol)
-			} else {
+			if err != nil {
 				if GlobalFlag.Verbose {
 					log.WithError(err).Error("error getting volume status")
 				}
 				failure("Error getting volume status", err, 1)
 			}
+			if isStructuredOutput() {
+				statuses[volume.Name] = vol
+				continue
+			}
+			fmt.Println("Volume :", volume.Name)
+			volumeStatusDisplay(vol)
 		}
+		printStructured(statuses)
 	} else {
 		vol, err = client.BricksStatus(volname)
-		fmt.Println("Volume :", volname)
-		if err == nil {
+		if err == nil && !printStructured(vol) {
+			fmt.Println("Volume :", volname)
 			volumeStatusDisplay(vol)
 		}
 	}
@@ -475,18 +528,26 @@ var volumeSizeCmd = &cobra.Command{
 	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		volname := cmd.Flags().Args()[0]
-		vol, err := client.VolumeStatus(volname)
+		size, err := client.VolumeSize(volname)
 		if err != nil {
 			if GlobalFlag.Verbose {
 				log.WithError(err).WithField("volume", volname).Error("error getting volume size")
 			}
 			failure("Error getting volume size", err, 1)
 		}
+		if printStructured(size) {
+			return
+		}
 		fmt.Println("Volume:", volname)
-		fmt.Println("Capacity:", humanReadable(vol.Size.Capacity))
-		fmt.Println("Used:", humanReadable(vol.Size.Used))
-		fmt.Println("Free:", humanReadable(vol.Size.Free))
-
+		fmt.Println("Capacity:", humanReadable(size.Capacity))
+		fmt.Println("Used:", humanReadable(size.Used))
+		fmt.Println("Free:", humanReadable(size.Free))
+		fmt.Printf("Utilization: %.2f%%\n", size.Utilization)
+		fmt.Printf("Inodes: %d (Used: %d, Free: %d)\n", size.Inodes, size.InodesUsed, size.InodesFree)
+		fmt.Printf("Inode Utilization: %.2f%%\n", size.InodeUtilization)
+		if size.BricksReported < size.Bricks {
+			fmt.Printf("Bricks Reported: %d of %d\n", size.BricksReported, size.Bricks)
+		}
 	},
 }
 
@@ -535,6 +596,9 @@ var volumeExpandCmd = &cobra.Command{
 			}
 			failure("Addition of brick failed", err, 1)
 		}
+		if printStructured(vol) {
+			return
+		}
 		fmt.Printf("%s Volume expanded successfully\n", vol.Name)
 	},
 }
@@ -551,13 +615,16 @@ var volumeEditCmd = &cobra.Command{
 			Metadata:       metadata,
 			DeleteMetadata: flagCmdDeleteMetadata,
 		}
-		_, err := client.EditVolume(volname, editMetadataReq)
+		vol, err := client.EditVolume(volname, editMetadataReq)
 		if err != nil {
 			if GlobalFlag.Verbose {
 				log.WithError(err).WithField("volume", volname).Error("failed to edit metadata")
 			}
 			failure("Failed to edit metadata", err, 1)
 		}
+		if printStructured(vol) {
+			return
+		}
 		fmt.Printf("Metadata edit successful\n")
 	},
 }
@@ -0,0 +1,254 @@
+// Package adaptivethrottle backs off the rebalance and self-heal crawls of a
+// volume while the clients of the volume see a high latency on its bricks,
+// and restores their rates once the latency is back to normal, so that
+// maintenance does not starve production I/O.
+//
+// Every peer samples the latency of the operations served by its bricks from
+// the io-stats xlator and saves it in the store. The first online peer of
+// each volume aggregates the latency of all the bricks of the volume and
+// throttles or restores the crawls by changing the volume options they read.
+package adaptivethrottle
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/events"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/options"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/glusterd2/transactionv2"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/glusterd2/xlator"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+	flag "github.com/spf13/pflag"
+	config "github.com/spf13/viper"
+)
+
+const (
+	intervalOpt = "adaptive-throttle-interval"
+
+	// Keys of the volume options of adaptive throttling, namespaced by a
+	// pseudo xlator
+	xlatorID       = "adaptive-throttle"
+	enableKey      = xlatorID + ".enable"
+	latencyHighKey = xlatorID + ".latency-high-ms"
+	latencyLowKey  = xlatorID + ".latency-low-ms"
+
+	defaultLatencyHigh = 50
+	defaultLatencyLow  = 20
+
+	// EventThrottled is broadcast when the crawls of a volume are backed
+	// off
+	EventThrottled = "volume.throttle.backoff"
+	// EventRestored is broadcast when the crawls of a volume are back to
+	// the rates set by the admin
+	EventRestored = "volume.throttle.restored"
+)
+
+var (
+	stopChan chan struct{}
+	stopOnce sync.Once
+)
+
+func init() {
+	// The options are consumed only by this package, they never reach a
+	// volfile
+	xlator.RegisterPseudoXlator(&xlator.Xlator{
+		ID: xlatorID,
+		Options: []*options.Option{
+			{
+				Key:          []string{"enable"},
+				Type:         options.OptionTypeBool,
+				DefaultValue: "off",
+				Description:  "Back off the rebalance and self-heal crawls of the volume while its bricks serve the clients with a high latency",
+				Flags:        options.OptionFlagSettable,
+				Level:        options.OptionStatusBasic,
+			},
+			{
+				Key:          []string{"latency-high-ms"},
+				Type:         options.OptionTypeInt,
+				Min:          1,
+				DefaultValue: strconv.Itoa(defaultLatencyHigh),
+				Description:  "Average latency of the bricks, in milliseconds, above which the crawls of the volume are backed off",
+				Flags:        options.OptionFlagSettable,
+				Level:        options.OptionStatusBasic,
+			},
+			{
+				Key:          []string{"latency-low-ms"},
+				Type:         options.OptionTypeInt,
+				Min:          0,
+				DefaultValue: strconv.Itoa(defaultLatencyLow),
+				Description:  "Average latency of the bricks, in milliseconds, below which the crawls of the volume are restored",
+				Flags:        options.OptionFlagSettable,
+				Level:        options.OptionStatusBasic,
+			},
+		},
+	})
+}
+
+// InitFlags intializes the command line options for adaptive throttling
+func InitFlags() {
+	flag.Duration(intervalOpt, 30*time.Second, "Interval at which the latency of the bricks is sampled, to throttle the rebalance and self-heal crawls of the volumes with adaptive throttling enabled. Set to 0 to disable.")
+}
+
+// Start starts sampling the latency of the local bricks periodically
+func Start() {
+	interval := config.GetDuration(intervalOpt)
+	if interval <= 0 {
+		log.Info("adaptive throttling disabled")
+		return
+	}
+
+	stopChan = make(chan struct{})
+	go transaction.UntilStop(collect, interval, stopChan)
+}
+
+// Stop stops sampling the latency of the local bricks
+func Stop() {
+	if stopChan == nil {
+		return
+	}
+	stopOnce.Do(func() {
+		close(stopChan)
+	})
+}
+
+// isEnabled tells if adaptive throttling is enabled on the volume
+func isEnabled(v *volume.Volinfo) bool {
+	enabled, err := options.StringToBoolean(v.Options[enableKey])
+	return err == nil && enabled
+}
+
+// thresholds returns the latencies above which the crawls of the volume are
+// backed off and below which they are restored. The low threshold is never
+// above the high one.
+func thresholds(v *volume.Volinfo) (high, low time.Duration) {
+	highMs, lowMs := defaultLatencyHigh, defaultLatencyLow
+	if n, err := strconv.Atoi(v.Options[latencyHighKey]); err == nil && n > 0 {
+		highMs = n
+	}
+	if n, err := strconv.Atoi(v.Options[latencyLowKey]); err == nil && n >= 0 {
+		lowMs = n
+	}
+	if lowMs > highMs {
+		lowMs = highMs
+	}
+	return time.Duration(highMs) * time.Millisecond, time.Duration(lowMs) * time.Millisecond
+}
+
+// decide tells if the crawls of a volume are to be throttled, given whether
+// they are now and the latency of its bricks. Between the thresholds the
+// crawls are left as they are, for a latency close to one of them not to
+// flap.
+func decide(throttled bool, latency, high, low time.Duration) bool {
+	switch {
+	case latency > high:
+		return true
+	case latency <= low:
+		return false
+	}
+	return throttled
+}
+
+func collect() {
+	volumes, err := volume.GetVolumes(context.TODO())
+	if err != nil {
+		log.WithError(err).Error("adaptivethrottle: failed to get volumes")
+		return
+	}
+
+	names := make(map[string]bool, len(volumes))
+	for _, v := range volumes {
+		names[v.Name] = true
+		enabled := v.State == volume.VolStarted && isEnabled(v)
+		if enabled {
+			sampleLocalBricks(v)
+		}
+		if isAggregator(v) {
+			adjust(v, enabled)
+		}
+	}
+	forgetDeleted(names)
+}
+
+// isAggregator returns true if this node is the first online node among the
+// nodes hosting the bricks of the volume, so that only one node throttles a
+// volume
+func isAggregator(v *volume.Volinfo) bool {
+	for _, node := range v.Nodes() {
+		if uuid.Equal(node, gdctx.MyUUID) {
+			return true
+		}
+		if _, alive := store.Store.IsNodeAlive(node); alive {
+			return false
+		}
+	}
+	return false
+}
+
+// adjust throttles or restores the crawls of the volume as required by the
+// latency of its bricks. The crawls of a volume with adaptive throttling
+// disabled are restored.
+func adjust(v *volume.Volinfo, enabled bool) {
+	logger := log.WithField("volume", v.Name)
+
+	st, err := getState(v.Name)
+	if err != nil {
+		logger.WithError(err).Error("adaptivethrottle: failed to get throttle state")
+		return
+	}
+	throttled := st != nil
+
+	var latency time.Duration
+	want := false
+	if enabled {
+		samples, err := getSamples(v.Name)
+		if err != nil {
+			logger.WithError(err).Error("adaptivethrottle: failed to get latency samples")
+			return
+		}
+		var ok bool
+		latency, ok = aggregate(samples, time.Now(), 3*config.GetDuration(intervalOpt))
+		if !ok {
+			// No brick was sampled lately, leave the crawls as they are
+			return
+		}
+		high, low := thresholds(v)
+		want = decide(throttled, latency, high, low)
+	}
+	if want == throttled {
+		return
+	}
+
+	if want {
+		if err := throttle(v); err != nil {
+			logger.WithError(err).Error("adaptivethrottle: failed to back off the crawls")
+			return
+		}
+		logger.WithField("latency", latency).Info("backed off the rebalance and self-heal crawls")
+		events.Broadcast(newEvent(EventThrottled, v, latency))
+		return
+	}
+
+	if err := restore(v, st); err != nil {
+		logger.WithError(err).Error("adaptivethrottle: failed to restore the crawls")
+		return
+	}
+	logger.WithField("latency", latency).Info("restored the rebalance and self-heal crawls")
+	events.Broadcast(newEvent(EventRestored, v, latency))
+}
+
+func newEvent(name string, v *volume.Volinfo, latency time.Duration) *api.Event {
+	data := map[string]string{
+		"volume.name":       v.Name,
+		"volume.id":         v.ID.String(),
+		"volume.latency_ms": strconv.FormatFloat(float64(latency)/float64(time.Millisecond), 'f', 2, 64),
+	}
+	return events.New(name, data, true)
+}
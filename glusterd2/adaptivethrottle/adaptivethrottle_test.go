@@ -0,0 +1,103 @@
+package adaptivethrottle
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/volume"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThresholds(t *testing.T) {
+	v := &volume.Volinfo{Options: map[string]string{}}
+	high, low := thresholds(v)
+	assert.Equal(t, 50*time.Millisecond, high)
+	assert.Equal(t, 20*time.Millisecond, low)
+
+	v.Options[latencyHighKey] = "10"
+	v.Options[latencyLowKey] = "30"
+	high, low = thresholds(v)
+	assert.Equal(t, 10*time.Millisecond, high)
+	assert.Equal(t, 10*time.Millisecond, low)
+}
+
+func TestDecide(t *testing.T) {
+	high, low := 50*time.Millisecond, 20*time.Millisecond
+	assert.True(t, decide(false, 60*time.Millisecond, high, low))
+	assert.False(t, decide(true, 10*time.Millisecond, high, low))
+
+	// between the thresholds the crawls are left as they are
+	assert.True(t, decide(true, 30*time.Millisecond, high, low))
+	assert.False(t, decide(false, 30*time.Millisecond, high, low))
+}
+
+func TestParseCounters(t *testing.T) {
+	info := map[string]string{
+		"cumulative":       "-1",
+		"-1-27-hits":       "10",
+		"-1-27-avglatency": "100",
+		"-1-29-hits":       "30",
+		"-1-29-avglatency": "200",
+		"-1-duration":      "60",
+		"interval":         "5",
+		"5-27-hits":        "1000",
+	}
+	c := parseCounters(info)
+	assert.Equal(t, uint64(40), c.hits)
+	assert.Equal(t, float64(7000), c.latency)
+
+	assert.Equal(t, counters{}, parseCounters(map[string]string{}))
+}
+
+func TestDelta(t *testing.T) {
+	d, ok := delta(counters{10, 1000}, counters{30, 5000})
+	assert.True(t, ok)
+	assert.Equal(t, counters{20, 4000}, d)
+
+	// a restarted brick has its counters reset
+	_, ok = delta(counters{30, 5000}, counters{5, 500})
+	assert.False(t, ok)
+}
+
+func TestAggregate(t *testing.T) {
+	now := time.Now()
+	samples := []sample{
+		{MeasuredAt: now, Hits: 10, Latency: 10 * 1000},
+		{MeasuredAt: now.Add(-time.Second), Hits: 30, Latency: 30 * 5000},
+		{MeasuredAt: now.Add(-time.Hour), Hits: 1000, Latency: 1000 * 100000},
+	}
+	latency, ok := aggregate(samples, now, time.Minute)
+	assert.True(t, ok)
+	assert.Equal(t, 4*time.Millisecond, latency)
+
+	latency, ok = aggregate([]sample{{MeasuredAt: now}}, now, time.Minute)
+	assert.True(t, ok)
+	assert.Equal(t, time.Duration(0), latency)
+
+	_, ok = aggregate(samples[2:], now, time.Minute)
+	assert.False(t, ok)
+}
+
+func TestBackoffRestore(t *testing.T) {
+	v := &volume.Volinfo{
+		Options: map[string]string{afrShdThreadsKey: "8"},
+		Subvols: []volume.Subvol{{Type: volume.SubvolReplicate}},
+	}
+	st := backoff(v, time.Now())
+	assert.Equal(t, rebalThrottleLazy, v.Options[rebalThrottleKey])
+	assert.Equal(t, shdThreadsThrottled, v.Options[afrShdThreadsKey])
+	assert.NotContains(t, v.Options, ecShdThreadsKey)
+
+	assert.True(t, restoreOptions(v, st))
+	assert.Equal(t, "8", v.Options[afrShdThreadsKey])
+	assert.NotContains(t, v.Options, rebalThrottleKey)
+
+	// options changed by the admin while throttled are left as they are
+	st = backoff(v, time.Now())
+	v.Options[rebalThrottleKey] = "aggressive"
+	assert.True(t, restoreOptions(v, st))
+	assert.Equal(t, "aggressive", v.Options[rebalThrottleKey])
+
+	assert.False(t, restoreOptions(v, st))
+}
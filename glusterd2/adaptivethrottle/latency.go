@@ -0,0 +1,219 @@
+package adaptivethrottle
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/daemon"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/servers/sunrpc/dict"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+
+	"github.com/coreos/etcd/clientv3"
+	log "github.com/sirupsen/logrus"
+)
+
+// samplesPrefix is where each peer saves the latency it sampled on its
+// bricks of a volume, under <volname>/<peer-id>
+const samplesPrefix = "throttle/samples/"
+
+// counters are the cumulative io-stats counters of a brick, summed over all
+// the fops
+type counters struct {
+	hits uint64
+	// latency is the sum of the latencies of the fops, in microseconds
+	latency float64
+}
+
+// sample is the latency of the fops served by the local bricks of a volume
+// between two samplings
+type sample struct {
+	MeasuredAt time.Time `json:"measured-at"`
+	Hits       uint64    `json:"hits"`
+	// Latency is the sum of the latencies of the fops, in microseconds
+	Latency float64 `json:"latency"`
+}
+
+var (
+	lastMu sync.Mutex
+	// last are the counters of the local bricks at the previous sampling,
+	// by brick ID
+	last = make(map[string]counters)
+)
+
+// parseCounters sums the cumulative counters of all the fops in the profile
+// info of a brick. The keys of the cumulative stats are of the form
+// <prefix>-<fop>-<stat>, the prefix being the value of the "cumulative" key.
+func parseCounters(info map[string]string) counters {
+	var c counters
+	prefix := info["cumulative"]
+	if prefix == "" {
+		return c
+	}
+	prefix += "-"
+
+	hits := make(map[string]uint64)
+	avg := make(map[string]float64)
+	for key, value := range info {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		s := strings.Split(strings.TrimPrefix(key, prefix), "-")
+		if len(s) != 2 {
+			continue
+		}
+		switch s[1] {
+		case "hits":
+			hits[s[0]], _ = strconv.ParseUint(value, 10, 64)
+		case "avglatency":
+			avg[s[0]], _ = strconv.ParseFloat(value, 64)
+		}
+	}
+	for fop, n := range hits {
+		c.hits += n
+		c.latency += float64(n) * avg[fop]
+	}
+	return c
+}
+
+// delta returns the fops served between the previous and the current
+// counters of a brick. A brick restarted since has its counters reset, and
+// is not accounted for until the next sampling.
+func delta(prev, cur counters) (counters, bool) {
+	if cur.hits < prev.hits || cur.latency < prev.latency {
+		return counters{}, false
+	}
+	return counters{hits: cur.hits - prev.hits, latency: cur.latency - prev.latency}, true
+}
+
+// brickCounters fetches the cumulative io-stats counters of a local brick,
+// without clearing them
+func brickCounters(v *volume.Volinfo, b brick.Brickinfo) (counters, error) {
+	brickDaemon, err := brick.NewGlusterfsd(b)
+	if err != nil {
+		return counters{}, err
+	}
+	client, err := daemon.GetRPCClient(brickDaemon)
+	if err != nil {
+		return counters{}, err
+	}
+
+	reqDict := map[string]string{
+		"peek":    "1",
+		"op":      "3",
+		"info-op": "3",
+		"volname": v.Name,
+		"vol-id":  v.ID.String(),
+	}
+	req := &brick.GfBrickOpReq{
+		Name: b.Path,
+		Op:   int(brick.OpBrickXlatorInfo),
+	}
+	if req.Input, err = dict.Serialize(reqDict); err != nil {
+		return counters{}, err
+	}
+	var rsp brick.GfBrickOpRsp
+	if err := client.Call("Brick.OpBrickXlatorInfo", req, &rsp); err != nil {
+		return counters{}, err
+	}
+	if rsp.OpRet != 0 {
+		return counters{}, fmt.Errorf("failed to get the profile info of the brick: %s", rsp.OpErrstr)
+	}
+	info, err := dict.Unserialize(rsp.Output)
+	if err != nil {
+		return counters{}, err
+	}
+	return parseCounters(info), nil
+}
+
+// sampleLocalBricks samples the latency of the local bricks of the volume
+// and saves it in the store. Bricks which are down or sampled for the first
+// time are left out.
+func sampleLocalBricks(v *volume.Volinfo) {
+	s := sample{MeasuredAt: time.Now()}
+	sampled := false
+
+	lastMu.Lock()
+	for _, b := range v.GetLocalBricks() {
+		cur, err := brickCounters(v, b)
+		if err != nil {
+			log.WithError(err).WithField("brick", b.String()).Debug("adaptivethrottle: failed to sample brick latency")
+			delete(last, b.ID.String())
+			continue
+		}
+		prev, seen := last[b.ID.String()]
+		last[b.ID.String()] = cur
+		if !seen {
+			continue
+		}
+		if d, ok := delta(prev, cur); ok {
+			s.Hits += d.hits
+			s.Latency += d.latency
+			sampled = true
+		}
+	}
+	lastMu.Unlock()
+
+	if !sampled {
+		return
+	}
+	data, err := json.Marshal(s)
+	if err != nil {
+		log.WithError(err).Error("adaptivethrottle: failed to marshal latency sample")
+		return
+	}
+	key := samplesPrefix + v.Name + "/" + gdctx.MyUUID.String()
+	if _, err := store.Put(context.TODO(), key, string(data)); err != nil {
+		log.WithError(err).WithField("volume", v.Name).Error("adaptivethrottle: failed to save latency sample")
+	}
+}
+
+// getSamples returns the latency samples saved by the peers for the volume
+func getSamples(volname string) ([]sample, error) {
+	resp, err := store.Get(context.TODO(), samplesPrefix+volname+"/", clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	samples := make([]sample, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var s sample
+		if err := json.Unmarshal(kv.Value, &s); err != nil {
+			log.WithError(err).WithField("key", string(kv.Key)).Error("adaptivethrottle: failed to unmarshal latency sample")
+			continue
+		}
+		samples = append(samples, s)
+	}
+	return samples, nil
+}
+
+// aggregate returns the average latency of the fops served by all the bricks
+// of a volume, weighted by their number of fops, out of the samples taken
+// within maxAge. It returns false if no sample is recent enough.
+func aggregate(samples []sample, now time.Time, maxAge time.Duration) (time.Duration, bool) {
+	var hits uint64
+	var latency float64
+	found := false
+	for _, s := range samples {
+		if now.Sub(s.MeasuredAt) > maxAge {
+			continue
+		}
+		found = true
+		hits += s.Hits
+		latency += s.Latency
+	}
+	if !found {
+		return 0, false
+	}
+	if hits == 0 {
+		return 0, true
+	}
+	return time.Duration(latency/float64(hits)) * time.Microsecond, true
+}
@@ -0,0 +1,263 @@
+package adaptivethrottle
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	"github.com/gluster/glusterd2/glusterd2/servers/sunrpc"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	transactionv2 "github.com/gluster/glusterd2/glusterd2/transactionv2"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// statePrefix is where the state of the throttled volumes is saved,
+	// under their name
+	statePrefix = "throttle/state/"
+
+	// Keys of the volume options setting the rates of the crawls, and the
+	// values they are backed off to
+	rebalThrottleKey    = "distribute.rebal-throttle"
+	afrShdThreadsKey    = "replicate.shd-max-threads"
+	ecShdThreadsKey     = "disperse.shd-max-threads"
+	rebalThrottleLazy   = "lazy"
+	shdThreadsThrottled = "1"
+)
+
+// state is the state of a volume whose crawls are backed off
+type state struct {
+	Since time.Time `json:"since"`
+	// Options are the options set to back off the crawls
+	Options map[string]string `json:"options"`
+	// Saved are the values of the options before, nil for the options
+	// which were not set
+	Saved map[string]*string `json:"saved"`
+}
+
+// crawlOptions returns the options backing off the crawls of the volume:
+// rebalance migrates files lazily and the self-heal daemon heals with a
+// single thread
+func crawlOptions(v *volume.Volinfo) map[string]string {
+	opts := map[string]string{rebalThrottleKey: rebalThrottleLazy}
+	for _, sv := range v.Subvols {
+		switch sv.Type {
+		case volume.SubvolReplicate:
+			opts[afrShdThreadsKey] = shdThreadsThrottled
+		case volume.SubvolDisperse:
+			opts[ecShdThreadsKey] = shdThreadsThrottled
+		}
+	}
+	return opts
+}
+
+// backoff sets the options backing off the crawls on the volume, returning
+// the state to restore them from
+func backoff(v *volume.Volinfo, now time.Time) *state {
+	st := &state{
+		Since:   now,
+		Options: crawlOptions(v),
+		Saved:   make(map[string]*string),
+	}
+	for k, value := range st.Options {
+		if old, ok := v.Options[k]; ok {
+			st.Saved[k] = &old
+		} else {
+			st.Saved[k] = nil
+		}
+		v.Options[k] = value
+	}
+	return st
+}
+
+// restoreOptions sets the options of the volume back to their values before
+// the crawls were backed off. The options changed since by the admin are
+// left as they are. It returns false if no option was changed.
+func restoreOptions(v *volume.Volinfo, st *state) bool {
+	changed := false
+	for k, value := range st.Options {
+		if v.Options[k] != value {
+			continue
+		}
+		if saved := st.Saved[k]; saved != nil {
+			v.Options[k] = *saved
+		} else {
+			delete(v.Options, k)
+		}
+		changed = true
+	}
+	return changed
+}
+
+func getState(volname string) (*state, error) {
+	resp, err := store.Get(context.TODO(), statePrefix+volname)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Count != 1 {
+		return nil, nil
+	}
+
+	var st state
+	if err := json.Unmarshal(resp.Kvs[0].Value, &st); err != nil {
+		return nil, err
+	}
+	return &st, nil
+}
+
+func putState(volname string, st *state) error {
+	data, err := json.Marshal(st)
+	if err != nil {
+		return err
+	}
+	_, err = store.Put(context.TODO(), statePrefix+volname, string(data))
+	return err
+}
+
+func deleteState(volname string) error {
+	_, err := store.Delete(context.TODO(), statePrefix+volname)
+	return err
+}
+
+// forgetDeleted removes the state and the latency samples of the volumes
+// which were deleted
+func forgetDeleted(names map[string]bool) {
+	for _, prefix := range []string{statePrefix, samplesPrefix} {
+		resp, err := store.Get(context.TODO(), prefix, clientv3.WithPrefix(), clientv3.WithKeysOnly())
+		if err != nil {
+			log.WithError(err).Error("adaptivethrottle: failed to list throttle state")
+			return
+		}
+		for _, kv := range resp.Kvs {
+			volname := strings.SplitN(strings.TrimPrefix(string(kv.Key), prefix), "/", 2)[0]
+			if names[volname] {
+				continue
+			}
+			if _, err := store.Delete(context.TODO(), string(kv.Key)); err != nil {
+				log.WithError(err).WithField("key", string(kv.Key)).Error("adaptivethrottle: failed to delete throttle state")
+			}
+		}
+	}
+}
+
+// throttle backs off the crawls of the volume. The state is saved first, so
+// that the options are restored even if this peer goes down right after
+// setting them.
+func throttle(v *volume.Volinfo) error {
+	return updateVolume(v.Name, func(volinfo *volume.Volinfo) (bool, error) {
+		st := backoff(volinfo, time.Now())
+		return true, putState(volinfo.Name, st)
+	}, func(volname string) {
+		if err := deleteState(volname); err != nil {
+			log.WithError(err).WithField("volume", volname).Error("adaptivethrottle: failed to delete throttle state")
+		}
+	})
+}
+
+// restore restores the crawls of the volume to the rates set by the admin
+func restore(v *volume.Volinfo, st *state) error {
+	err := updateVolume(v.Name, func(volinfo *volume.Volinfo) (bool, error) {
+		return restoreOptions(volinfo, st), nil
+	}, nil)
+	if err != nil {
+		return err
+	}
+	return deleteState(v.Name)
+}
+
+// updateVolume changes the options of the volume with update, under the lock
+// of the volume, saves it and notifies its clients. onFailure is called if
+// the volume could not be saved.
+func updateVolume(volname string, update func(*volume.Volinfo) (bool, error), onFailure func(string)) error {
+	ctx := gdctx.WithReqLogger(context.Background(), log.StandardLogger())
+	txn, err := transactionv2.NewTxnWithLocks(ctx, volname)
+	if err != nil {
+		return err
+	}
+	defer txn.Done()
+
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		return err
+	}
+	if err := txn.Ctx.Set("oldvolinfo", volinfo); err != nil {
+		return err
+	}
+	changed, err := update(volinfo)
+	if err != nil || !changed {
+		return err
+	}
+	if err := txn.Ctx.Set("volinfo", volinfo); err != nil {
+		return err
+	}
+
+	allNodes, err := peer.GetPeerIDs()
+	if err != nil {
+		return err
+	}
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc:   "adaptive-throttle.StoreVolume",
+			UndoFunc: "adaptive-throttle.StoreVolume.Undo",
+			Nodes:    []uuid.UUID{gdctx.MyUUID},
+			Sync:     true,
+		},
+		{
+			DoFunc: "adaptive-throttle.NotifyVolfileChange",
+			Nodes:  allNodes,
+		},
+	}
+	if err := txn.Do(); err != nil {
+		if onFailure != nil {
+			onFailure(volname)
+		}
+		return err
+	}
+	return nil
+}
+
+func storeVolInfo(c transaction.TxnCtx, key string) error {
+	var volinfo volume.Volinfo
+	if err := c.Get(key, &volinfo); err != nil {
+		return err
+	}
+	return volume.AddOrUpdateVolumeFunc(&volinfo)
+}
+
+func txnStoreVolume(c transaction.TxnCtx) error {
+	return storeVolInfo(c, "volinfo")
+}
+
+func txnUndoStoreVolume(c transaction.TxnCtx) error {
+	return storeVolInfo(c, "oldvolinfo")
+}
+
+func txnNotifyVolfileChange(c transaction.TxnCtx) error {
+	var volinfo volume.Volinfo
+	if err := c.Get("volinfo", &volinfo); err != nil {
+		return err
+	}
+	if volinfo.State != volume.VolStarted {
+		return nil
+	}
+
+	// Failing to notify clients does not fail the transaction, the
+	// clients fetch the new volfiles on reconnecting
+	sunrpc.VolfileChangeNotify(c, volinfo.Name)
+	return nil
+}
+
+// RegisterStepFuncs registers the step functions of adaptive throttling
+func RegisterStepFuncs() {
+	transaction.RegisterStepFunc(txnStoreVolume, "adaptive-throttle.StoreVolume")
+	transaction.RegisterStepFunc(txnUndoStoreVolume, "adaptive-throttle.StoreVolume.Undo")
+	transaction.RegisterStepFunc(txnNotifyVolfileChange, "adaptive-throttle.NotifyVolfileChange")
+}
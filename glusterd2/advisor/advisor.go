@@ -0,0 +1,281 @@
+// Package advisor measures how effective the caches of the clients and of
+// the bricks of a volume are, out of their latest statedumps, and recommends
+// the options improving the performance of the volume for small files.
+package advisor
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/options"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/glusterd2/xlator"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// SmallFileGroup is the option group of the small-file performance
+	// mode
+	SmallFileGroup = "small-file"
+
+	// maxDumpAge is the age of the statedumps past which they are no
+	// longer considered
+	maxDumpAge = time.Hour
+
+	// minSamples is the number of lookups counted by the clients under
+	// which their counters are not significant enough to advise on
+	minSamples = 1000
+
+	// minHitRate is the hit rate of md-cache under which a longer timeout
+	// is recommended
+	minHitRate = 50
+
+	// lruFullRatio is the ratio of the inode LRU limit of a brick over
+	// which a larger limit is recommended
+	lruFullRatio = 0.9
+
+	// recommendedTimeout is the timeout of the caches, in seconds,
+	// recommended when the clients are notified of the changes
+	recommendedTimeout = 600
+)
+
+// Volume options read by the advisor
+const (
+	mdCacheKey        = "performance/md-cache.md-cache"
+	mdCacheTimeoutKey = "performance/md-cache.md-cache-timeout"
+	mdCacheInvalidKey = "performance/md-cache.cache-invalidation"
+	upcallInvalidKey  = "features/upcall.cache-invalidation"
+	upcallTimeoutKey  = "features/upcall.cache-invalidation-timeout"
+	nlCacheKey        = "performance/nl-cache.nl-cache"
+	nlCacheTimeoutKey = "performance/nl-cache.nl-cache-timeout"
+	openBehindKey     = "performance/open-behind.open-behind"
+	inodeLruLimitKey  = "protocol/server.inode-lru-limit"
+)
+
+// defaults are the defaults of the options read by the advisor, used when
+// their xlator is not loaded. The xlators of the client graph are enabled by
+// default.
+var defaults = map[string]string{
+	mdCacheKey:        "on",
+	mdCacheTimeoutKey: "1",
+	mdCacheInvalidKey: "off",
+	upcallInvalidKey:  "off",
+	nlCacheKey:        "on",
+	openBehindKey:     "on",
+}
+
+// Stats are the counters of the caches of a volume, out of the statedumps of
+// its clients and bricks
+type Stats struct {
+	MdCache api.MdCacheStats      `json:"md-cache"`
+	Bricks  []api.BrickInodeStats `json:"bricks"`
+}
+
+// Add adds the counters of o to s
+func (s *Stats) Add(o *Stats) {
+	s.MdCache.Clients += o.MdCache.Clients
+	s.MdCache.StatHits += o.MdCache.StatHits
+	s.MdCache.StatMisses += o.MdCache.StatMisses
+	s.MdCache.XattrHits += o.MdCache.XattrHits
+	s.MdCache.XattrMisses += o.MdCache.XattrMisses
+	s.MdCache.NegativeLookups += o.MdCache.NegativeLookups
+	s.MdCache.Invalidations += o.MdCache.Invalidations
+	s.Bricks = append(s.Bricks, o.Bricks...)
+}
+
+// HitRate returns the percentage of the stat lookups served by md-cache, or
+// -1 when no lookup was counted
+func (s *Stats) HitRate() float64 {
+	total := s.MdCache.StatHits + s.MdCache.StatMisses
+	if total == 0 {
+		return -1
+	}
+	return float64(s.MdCache.StatHits) * 100 / float64(total)
+}
+
+// counter returns the counter of the statedump section
+func counter(section map[string]string, key string) uint64 {
+	n, _ := strconv.ParseUint(section[key], 10, 64)
+	return n
+}
+
+// addMdCache adds the counters of the md-cache of the volume found in the
+// statedump of a client
+func (s *Stats) addMdCache(volname string, dump map[string]map[string]string) {
+	section, ok := dump[fmt.Sprintf(mdCacheSectionTmpl, volname)]
+	if !ok {
+		return
+	}
+	s.MdCache.Clients++
+	s.MdCache.StatHits += counter(section, "stat_hit_count")
+	s.MdCache.StatMisses += counter(section, "stat_miss_count")
+	s.MdCache.XattrHits += counter(section, "xattr_hit_count")
+	s.MdCache.XattrMisses += counter(section, "xattr_miss_count")
+	s.MdCache.NegativeLookups += counter(section, "negative_lookup_count")
+	s.MdCache.Invalidations += counter(section, "stat_invalidations_received") +
+		counter(section, "xattr_invalidations_received")
+}
+
+// brickInodeStats returns the state of the inode table of the brick found in
+// its statedump
+func brickInodeStats(path string, dump map[string]map[string]string) (api.BrickInodeStats, bool) {
+	for _, section := range dump {
+		for k, v := range section {
+			if !strings.HasSuffix(k, brickInodeKeyInfix+path+brickLruLimitSuffix) {
+				continue
+			}
+			prefix := strings.TrimSuffix(k, brickLruLimitSuffix)
+			limit, _ := strconv.ParseUint(v, 10, 64)
+			return api.BrickInodeStats{
+				Brick:      path,
+				LruLimit:   limit,
+				LruSize:    counter(section, prefix+".lru_size"),
+				ActiveSize: counter(section, prefix+".active_size"),
+			}, true
+		}
+	}
+	return api.BrickInodeStats{}, false
+}
+
+// brickDumpPrefix returns the prefix of the names of the statedumps of the
+// brick, its path without the leading slash and with the other slashes
+// replaced by dashes
+func brickDumpPrefix(path string) string {
+	return strings.Replace(strings.TrimPrefix(path, "/"), "/", "-", -1)
+}
+
+// Collect returns the counters of the caches of the volume out of the latest
+// statedumps of its clients and bricks on this peer
+func Collect(v *volume.Volinfo) (*Stats, error) {
+	since := time.Now().Add(-maxDumpAge)
+	stats := &Stats{Bricks: []api.BrickInodeStats{}}
+
+	dumps, err := latestDumps(statedumpDir, clientDumpPrefix, since)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range dumps {
+		dump, err := readStatedump(d.path)
+		if err != nil {
+			log.WithError(err).WithField("file", d.path).Warn("failed to read the statedump of the client")
+			continue
+		}
+		stats.addMdCache(v.Name, dump)
+	}
+
+	for _, b := range v.GetLocalBricks() {
+		dumps, err := latestDumps(statedumpDir, brickDumpPrefix(b.Path), since)
+		if err != nil {
+			return nil, err
+		}
+		var latest *dumpFile
+		for i := range dumps {
+			if latest == nil || latest.time.Before(dumps[i].time) {
+				latest = &dumps[i]
+			}
+		}
+		if latest == nil {
+			continue
+		}
+		dump, err := readStatedump(latest.path)
+		if err != nil {
+			log.WithError(err).WithField("file", latest.path).Warn("failed to read the statedump of the brick")
+			continue
+		}
+		if bs, ok := brickInodeStats(b.Path, dump); ok {
+			bs.Peer = gdctx.MyUUID
+			stats.Bricks = append(stats.Bricks, bs)
+		}
+	}
+	return stats, nil
+}
+
+// option returns the value of the option set on the volume for its clients or
+// bricks, or its default value
+func option(opts map[string]string, key string) string {
+	for _, k := range []string{"client." + key, "brick." + key, key} {
+		if value, ok := opts[k]; ok {
+			return value
+		}
+	}
+	if o, err := xlator.FindOption(key); err == nil && o.DefaultValue != "" {
+		return o.DefaultValue
+	}
+	return defaults[key]
+}
+
+func isOn(opts map[string]string, key string) bool {
+	on, err := options.StringToBoolean(option(opts, key))
+	return err == nil && on
+}
+
+// Advise returns the recommendations for the volume with the given options,
+// given the counters of its caches
+func Advise(opts map[string]string, stats *Stats) []api.VolumeRecommendation {
+	recs := []api.VolumeRecommendation{}
+	rtimeout := strconv.Itoa(recommendedTimeout)
+
+	if !isOn(opts, mdCacheKey) || !isOn(opts, openBehindKey) {
+		recs = append(recs, api.VolumeRecommendation{
+			Reason: "md-cache or open-behind is disabled, the clients look up the attributes of the files on the bricks at every access",
+			Group:  SmallFileGroup,
+		})
+		return recs
+	}
+
+	invalidation := isOn(opts, mdCacheInvalidKey) && isOn(opts, upcallInvalidKey)
+	if !invalidation {
+		recs = append(recs, api.VolumeRecommendation{
+			Reason: "the clients are not notified of the changes made by the other clients, their caches have to expire quickly",
+			Options: map[string]string{
+				upcallInvalidKey:  "on",
+				upcallTimeoutKey:  rtimeout,
+				mdCacheInvalidKey: "on",
+				mdCacheTimeoutKey: rtimeout,
+			},
+		})
+	}
+
+	lookups := stats.MdCache.StatHits + stats.MdCache.StatMisses
+	timeout, _ := strconv.Atoi(option(opts, mdCacheTimeoutKey))
+	if invalidation && lookups >= minSamples && stats.HitRate() < minHitRate && timeout < recommendedTimeout {
+		recs = append(recs, api.VolumeRecommendation{
+			Reason: fmt.Sprintf("md-cache serves %.0f%% of the lookups of the clients, its entries expire after %ds", stats.HitRate(), timeout),
+			Options: map[string]string{
+				mdCacheTimeoutKey: rtimeout,
+			},
+		})
+	}
+
+	if stats.MdCache.NegativeLookups >= minSamples && !isOn(opts, nlCacheKey) {
+		recs = append(recs, api.VolumeRecommendation{
+			Reason: fmt.Sprintf("the clients looked up %d files which do not exist", stats.MdCache.NegativeLookups),
+			Options: map[string]string{
+				nlCacheKey:        "on",
+				nlCacheTimeoutKey: rtimeout,
+			},
+		})
+	}
+
+	var limit uint64
+	for _, b := range stats.Bricks {
+		if b.LruLimit > 0 && float64(b.LruSize) >= float64(b.LruLimit)*lruFullRatio && b.LruLimit*2 > limit {
+			limit = b.LruLimit * 2
+		}
+	}
+	if limit > 0 {
+		recs = append(recs, api.VolumeRecommendation{
+			Reason: "the inode tables of bricks are full, the inodes of the files are dropped and looked up again",
+			Options: map[string]string{
+				inodeLruLimitKey: strconv.FormatUint(limit, 10),
+			},
+		})
+	}
+
+	return recs
+}
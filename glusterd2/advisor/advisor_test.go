@@ -0,0 +1,144 @@
+package advisor
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const clientDump = `DUMP-START-TIME: 2019-03-12 10:15:01.121212
+
+[mallinfo]
+mallinfo_arena=1000
+
+[performance/md-cache.testvol-md-cache]
+stat_hit_count=300
+stat_miss_count=700
+xattr_hit_count=10
+xattr_miss_count=5
+nameless_lookup_count=0
+negative_lookup_count=1200
+stat_invalidations_received=4
+xattr_invalidations_received=1
+`
+
+const brickDump = `[protocol/server.testvol-server]
+conn.0.bound_xl./bricks/b1.hashsize=16384
+conn.0.bound_xl./bricks/b1.name=/bricks/b1/inode
+conn.0.bound_xl./bricks/b1.lru_limit=16384
+conn.0.bound_xl./bricks/b1.active_size=12
+conn.0.bound_xl./bricks/b1.lru_size=16000
+`
+
+func TestParseStatedump(t *testing.T) {
+	sections, err := parseStatedump(strings.NewReader(clientDump))
+	require.NoError(t, err)
+	assert.Equal(t, "1000", sections["mallinfo"]["mallinfo_arena"])
+
+	var s Stats
+	s.addMdCache("testvol", sections)
+	s.addMdCache("othervol", sections)
+	assert.Equal(t, api.MdCacheStats{
+		Clients:         1,
+		StatHits:        300,
+		StatMisses:      700,
+		XattrHits:       10,
+		XattrMisses:     5,
+		NegativeLookups: 1200,
+		Invalidations:   5,
+	}, s.MdCache)
+	assert.Equal(t, float64(30), s.HitRate())
+
+	sections, err = parseStatedump(strings.NewReader(brickDump))
+	require.NoError(t, err)
+	bs, ok := brickInodeStats("/bricks/b1", sections)
+	if assert.True(t, ok) {
+		assert.Equal(t, uint64(16384), bs.LruLimit)
+		assert.Equal(t, uint64(16000), bs.LruSize)
+		assert.Equal(t, uint64(12), bs.ActiveSize)
+	}
+	_, ok = brickInodeStats("/bricks/b2", sections)
+	assert.False(t, ok)
+}
+
+func TestLatestDumps(t *testing.T) {
+	dir, err := ioutil.TempDir("", "advisor")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	now := time.Now().Unix()
+	for _, name := range []string{
+		"glusterdump.100.dump." + strconv.FormatInt(now-60, 10),
+		"glusterdump.100.dump." + strconv.FormatInt(now, 10),
+		"glusterdump.200.dump." + strconv.FormatInt(now-2*3600, 10),
+		"bricks-b1.300.dump." + strconv.FormatInt(now, 10),
+		"glusterd2.400.dump." + strconv.FormatInt(now, 10),
+	} {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), nil, 0644))
+	}
+
+	dumps, err := latestDumps(dir, clientDumpPrefix, time.Now().Add(-maxDumpAge))
+	require.NoError(t, err)
+	if assert.Len(t, dumps, 1) {
+		assert.Equal(t, 100, dumps[0].pid)
+		assert.Equal(t, now, dumps[0].time.Unix())
+	}
+
+	dumps, err = latestDumps(dir, brickDumpPrefix("/bricks/b1"), time.Time{})
+	require.NoError(t, err)
+	assert.Len(t, dumps, 1)
+
+	dumps, err = latestDumps(filepath.Join(dir, "missing"), clientDumpPrefix, time.Time{})
+	assert.NoError(t, err)
+	assert.Empty(t, dumps)
+}
+
+func TestAdvise(t *testing.T) {
+	stats := &Stats{}
+
+	recs := Advise(map[string]string{mdCacheKey: "off"}, stats)
+	if assert.Len(t, recs, 1) {
+		assert.Equal(t, SmallFileGroup, recs[0].Group)
+	}
+
+	recs = Advise(map[string]string{}, stats)
+	if assert.Len(t, recs, 1) {
+		assert.Equal(t, "on", recs[0].Options[upcallInvalidKey])
+	}
+
+	opts := map[string]string{
+		upcallInvalidKey:  "on",
+		mdCacheInvalidKey: "on",
+		mdCacheTimeoutKey: "60",
+		nlCacheKey:        "off",
+	}
+	assert.Empty(t, Advise(opts, stats))
+
+	stats.MdCache.StatHits = 300
+	stats.MdCache.StatMisses = 700
+	stats.MdCache.NegativeLookups = 1200
+	stats.Bricks = []api.BrickInodeStats{
+		{Brick: "/bricks/b1", LruLimit: 16384, LruSize: 16000},
+		{Brick: "/bricks/b2", LruLimit: 16384, LruSize: 100},
+	}
+	recs = Advise(opts, stats)
+	if assert.Len(t, recs, 3) {
+		assert.Equal(t, "600", recs[0].Options[mdCacheTimeoutKey])
+		assert.Equal(t, "on", recs[1].Options[nlCacheKey])
+		assert.Equal(t, "32768", recs[2].Options[inodeLruLimitKey])
+	}
+
+	opts[mdCacheTimeoutKey] = "600"
+	opts["client."+nlCacheKey] = "on"
+	stats.Bricks = nil
+	assert.Empty(t, Advise(opts, stats))
+}
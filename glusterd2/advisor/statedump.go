@@ -0,0 +1,107 @@
+package advisor
+
+import (
+	"bufio"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// statedumpDir is where the glusterfs processes write their statedumps
+var statedumpDir = "/var/run/gluster"
+
+// Prefixes and keys of the statedumps read by the advisor
+const (
+	clientDumpPrefix    = "glusterdump"
+	mdCacheSectionTmpl  = "performance/md-cache.%s-md-cache"
+	brickInodeKeyInfix  = ".bound_xl."
+	brickLruLimitSuffix = ".lru_limit"
+)
+
+// dumpNameRe matches the names of the statedump files,
+// <prefix>.<pid>.dump.<unix time>
+var dumpNameRe = regexp.MustCompile(`^(.+)\.([0-9]+)\.dump\.([0-9]+)$`)
+
+// dumpFile is a statedump file of a process
+type dumpFile struct {
+	path string
+	pid  int
+	time time.Time
+}
+
+// latestDumps returns the latest statedump of each process in the directory
+// whose file name starts with the prefix, taken after since
+func latestDumps(dir, prefix string, since time.Time) ([]dumpFile, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	latest := make(map[int]dumpFile)
+	for _, e := range entries {
+		m := dumpNameRe.FindStringSubmatch(e.Name())
+		if m == nil || m[1] != prefix || e.IsDir() {
+			continue
+		}
+		pid, _ := strconv.Atoi(m[2])
+		sec, _ := strconv.ParseInt(m[3], 10, 64)
+		t := time.Unix(sec, 0)
+		if t.Before(since) {
+			continue
+		}
+		if d, ok := latest[pid]; !ok || d.time.Before(t) {
+			latest[pid] = dumpFile{path: filepath.Join(dir, e.Name()), pid: pid, time: t}
+		}
+	}
+
+	dumps := make([]dumpFile, 0, len(latest))
+	for _, d := range latest {
+		dumps = append(dumps, d)
+	}
+	return dumps, nil
+}
+
+// parseStatedump returns the keys of a statedump by section. The sections
+// start with a "[name]" line and hold "key=value" lines.
+func parseStatedump(r io.Reader) (map[string]map[string]string, error) {
+	sections := make(map[string]map[string]string)
+	var cur map[string]string
+
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			name := strings.TrimSuffix(strings.TrimPrefix(line, "["), "]")
+			if cur = sections[name]; cur == nil {
+				cur = make(map[string]string)
+				sections[name] = cur
+			}
+			continue
+		}
+		if cur == nil {
+			continue
+		}
+		if i := strings.Index(line, "="); i > 0 {
+			cur[line[:i]] = line[i+1:]
+		}
+	}
+	return sections, s.Err()
+}
+
+// readStatedump parses the statedump file
+func readStatedump(path string) (map[string]map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return parseStatedump(f)
+}
@@ -0,0 +1,184 @@
+// Package barrier pauses the disruptive operations on the bricks of a
+// volume, through the barrier translator, for the bricks to be in a
+// consistent state while snapshotted.
+package barrier
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/daemon"
+	"github.com/gluster/glusterd2/glusterd2/servers/sunrpc/dict"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// DefaultTimeout is how long the barrier is kept when no timeout is given.
+// It is the default timeout of the barrier translator.
+const DefaultTimeout = 120 * time.Second
+
+// releases are the timers releasing the barrier of the volumes, by volume
+// name, on the local bricks
+var releases = struct {
+	sync.Mutex
+	timers map[string]*time.Timer
+}{timers: make(map[string]*time.Timer)}
+
+// controller enables and disables the barrier of the volumes on the local
+// bricks, sending the barrier requests to the bricks using send
+type controller struct {
+	send      func(b brick.Brickinfo, enable bool) error
+	getVolume func(volname string) (*volume.Volinfo, error)
+}
+
+// local is the controller sending the barrier RPCs to the brick processes
+var local = &controller{
+	send:      rpcBarrier,
+	getVolume: volume.GetVolume,
+}
+
+// rpcBarrier enables or disables the barrier on the brick
+func rpcBarrier(b brick.Brickinfo, enable bool) error {
+	option := "disable"
+	if enable {
+		option = "enable"
+	}
+
+	brickDaemon, err := brick.NewGlusterfsd(b)
+	if err != nil {
+		return err
+	}
+	client, err := daemon.GetRPCClient(brickDaemon)
+	if err != nil {
+		return err
+	}
+
+	req := &brick.GfBrickOpReq{
+		Name: b.Path,
+		Op:   int(brick.OpBrickBarrier),
+	}
+	req.Input, err = dict.Serialize(map[string]string{
+		"barrier": option,
+		"volname": b.VolumeName,
+	})
+	if err != nil {
+		return err
+	}
+
+	var rsp brick.GfBrickOpRsp
+	if err := client.Call("Brick.OpBrickBarrier", req, &rsp); err != nil {
+		return err
+	}
+	if rsp.OpRet != 0 {
+		return fmt.Errorf("barrier RPC failed: %s", rsp.OpErrstr)
+	}
+	return nil
+}
+
+// Enable enables the barrier on the local bricks of the volume. The barrier
+// is released after timeout unless disabled before, so that a failed caller
+// does not leave the volume paused. Bricks already barriered on failing are
+// released.
+func Enable(volinfo *volume.Volinfo, timeout time.Duration, logger log.FieldLogger) error {
+	return local.enable(volinfo, timeout, logger)
+}
+
+func (c *controller) enable(volinfo *volume.Volinfo, timeout time.Duration, logger log.FieldLogger) error {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	var enabled []brick.Brickinfo
+	for _, b := range volinfo.GetLocalBricks() {
+		if err := c.send(b, true); err != nil {
+			logger.WithError(err).WithField("brick", b.String()).Error("failed to enable barrier")
+			for _, e := range enabled {
+				if err := c.send(e, false); err != nil {
+					logger.WithError(err).WithField("brick", e.String()).Error("failed to disable barrier")
+				}
+			}
+			return err
+		}
+		enabled = append(enabled, b)
+	}
+
+	volname := volinfo.Name
+	releases.Lock()
+	if t, ok := releases.timers[volname]; ok {
+		t.Stop()
+	}
+	var t *time.Timer
+	t = time.AfterFunc(timeout, func() {
+		// The barrier may have been disabled, or enabled again, since
+		releases.Lock()
+		current := releases.timers[volname] == t
+		releases.Unlock()
+		if current {
+			c.release(volname, timeout)
+		}
+	})
+	releases.timers[volname] = t
+	releases.Unlock()
+
+	logger.WithFields(log.Fields{
+		"volume":  volname,
+		"timeout": timeout,
+	}).Info("barrier enabled")
+	return nil
+}
+
+// Disable disables the barrier on the local bricks of the volume, releasing
+// the operations paused
+func Disable(volinfo *volume.Volinfo, logger log.FieldLogger) error {
+	return local.disable(volinfo, logger)
+}
+
+func (c *controller) disable(volinfo *volume.Volinfo, logger log.FieldLogger) error {
+	releases.Lock()
+	if t, ok := releases.timers[volinfo.Name]; ok {
+		t.Stop()
+		delete(releases.timers, volinfo.Name)
+	}
+	releases.Unlock()
+
+	var failed error
+	for _, b := range volinfo.GetLocalBricks() {
+		if err := c.send(b, false); err != nil {
+			logger.WithError(err).WithField("brick", b.String()).Error("failed to disable barrier")
+			failed = err
+		}
+	}
+	if failed != nil {
+		return failed
+	}
+
+	logger.WithField("volume", volinfo.Name).Info("barrier disabled")
+	return nil
+}
+
+// IsEnabled tells if the barrier of the volume is enabled on the local bricks
+func IsEnabled(volname string) bool {
+	releases.Lock()
+	defer releases.Unlock()
+	_, ok := releases.timers[volname]
+	return ok
+}
+
+// release disables the barrier of the volume on its timeout
+func (c *controller) release(volname string, timeout time.Duration) {
+	logger := log.WithFields(log.Fields{
+		"volume":  volname,
+		"timeout": timeout,
+	})
+	logger.Warn("barrier timed out, releasing it")
+
+	volinfo, err := c.getVolume(volname)
+	if err != nil {
+		logger.WithError(err).Error("failed to get volume, barrier not released")
+		return
+	}
+	c.disable(volinfo, logger)
+}
@@ -0,0 +1,135 @@
+package barrier
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBricks records the barrier state of the bricks, failing to enable it
+// on the brick of the given path
+type fakeBricks struct {
+	sync.Mutex
+	enabled map[string]bool
+	fail    string
+}
+
+func (f *fakeBricks) send(b brick.Brickinfo, enable bool) error {
+	f.Lock()
+	defer f.Unlock()
+	if enable && b.Path == f.fail {
+		return errors.New("barrier RPC failed")
+	}
+	f.enabled[b.Path] = enable
+	return nil
+}
+
+func (f *fakeBricks) isEnabled(path string) bool {
+	f.Lock()
+	defer f.Unlock()
+	return f.enabled[path]
+}
+
+func testVolume(name string, paths ...string) *volume.Volinfo {
+	v := &volume.Volinfo{Name: name, Subvols: []volume.Subvol{{}}}
+	for _, p := range paths {
+		v.Subvols[0].Bricks = append(v.Subvols[0].Bricks, brick.Brickinfo{PeerID: gdctx.MyUUID, Path: p, VolumeName: name})
+	}
+	// The bricks of the other peers are barriered by their peer
+	v.Subvols[0].Bricks = append(v.Subvols[0].Bricks, brick.Brickinfo{PeerID: uuid.NewRandom(), Path: "/remote", VolumeName: name})
+	return v
+}
+
+func TestEnableDisable(t *testing.T) {
+	bricks := &fakeBricks{enabled: make(map[string]bool)}
+	c := &controller{send: bricks.send}
+	v := testVolume("gv0", "/b1", "/b2")
+
+	require.NoError(t, c.enable(v, time.Minute, log.StandardLogger()))
+	assert.True(t, IsEnabled("gv0"))
+	assert.True(t, bricks.isEnabled("/b1"))
+	assert.True(t, bricks.isEnabled("/b2"))
+	_, remote := bricks.enabled["/remote"]
+	assert.False(t, remote)
+
+	require.NoError(t, c.disable(v, log.StandardLogger()))
+	assert.False(t, IsEnabled("gv0"))
+	assert.False(t, bricks.isEnabled("/b1"))
+	assert.False(t, bricks.isEnabled("/b2"))
+}
+
+func TestEnableFailed(t *testing.T) {
+	bricks := &fakeBricks{enabled: make(map[string]bool), fail: "/b2"}
+	c := &controller{send: bricks.send}
+	v := testVolume("gv1", "/b1", "/b2")
+
+	// The bricks already barriered are released
+	assert.Error(t, c.enable(v, time.Minute, log.StandardLogger()))
+	assert.False(t, IsEnabled("gv1"))
+	assert.False(t, bricks.isEnabled("/b1"))
+}
+
+func TestEnableTimeout(t *testing.T) {
+	bricks := &fakeBricks{enabled: make(map[string]bool)}
+	v := testVolume("gv2", "/b1")
+	c := &controller{
+		send: bricks.send,
+		getVolume: func(name string) (*volume.Volinfo, error) {
+			return v, nil
+		},
+	}
+
+	// The barrier is released on its timeout
+	require.NoError(t, c.enable(v, 50*time.Millisecond, log.StandardLogger()))
+	assert.True(t, bricks.isEnabled("/b1"))
+	for i := 0; i < 200 && (IsEnabled("gv2") || bricks.isEnabled("/b1")); i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.False(t, IsEnabled("gv2"))
+	assert.False(t, bricks.isEnabled("/b1"))
+
+	// Enabling the barrier again extends it, the first timeout does not
+	// release it
+	require.NoError(t, c.enable(v, 50*time.Millisecond, log.StandardLogger()))
+	require.NoError(t, c.enable(v, time.Minute, log.StandardLogger()))
+	time.Sleep(200 * time.Millisecond)
+	assert.True(t, IsEnabled("gv2"))
+	assert.True(t, bricks.isEnabled("/b1"))
+	require.NoError(t, c.disable(v, log.StandardLogger()))
+}
+
+func TestSteps(t *testing.T) {
+	c := transaction.NewSimulation(1).Ctx()
+	nodes := []uuid.UUID{uuid.NewRandom()}
+
+	step, err := EnableStep(c, "gv0", 30*time.Second, nodes)
+	require.NoError(t, err)
+	assert.Equal(t, "barrier.Enable", step.DoFunc)
+	assert.Equal(t, "barrier.Disable", step.UndoFunc)
+	assert.Equal(t, nodes, step.Nodes)
+
+	var volname string
+	var timeout time.Duration
+	require.NoError(t, c.Get(volnameTxnKey, &volname))
+	require.NoError(t, c.Get(timeoutTxnKey, &timeout))
+	assert.Equal(t, "gv0", volname)
+	assert.Equal(t, 30*time.Second, timeout)
+
+	step, err = DisableStep(c, "gv1", nodes)
+	require.NoError(t, err)
+	assert.Equal(t, "barrier.Disable", step.DoFunc)
+	assert.Empty(t, step.UndoFunc)
+	require.NoError(t, c.Get(volnameTxnKey, &volname))
+	assert.Equal(t, "gv1", volname)
+}
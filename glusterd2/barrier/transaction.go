@@ -0,0 +1,81 @@
+package barrier
+
+import (
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+
+	"github.com/pborman/uuid"
+)
+
+const (
+	volnameTxnKey = "barrier.volname"
+	timeoutTxnKey = "barrier.timeout"
+)
+
+// EnableStep returns a step having each of the given nodes enable the
+// barrier on its bricks of the volume, for at most timeout. The step is
+// undone by disabling the barrier.
+func EnableStep(c transaction.TxnCtx, volname string, timeout time.Duration, nodes []uuid.UUID) (*transaction.Step, error) {
+	if err := c.Set(volnameTxnKey, volname); err != nil {
+		return nil, err
+	}
+	if err := c.Set(timeoutTxnKey, timeout); err != nil {
+		return nil, err
+	}
+	return &transaction.Step{
+		DoFunc:   "barrier.Enable",
+		UndoFunc: "barrier.Disable",
+		Nodes:    nodes,
+	}, nil
+}
+
+// DisableStep returns a step having each of the given nodes disable the
+// barrier on its bricks of the volume
+func DisableStep(c transaction.TxnCtx, volname string, nodes []uuid.UUID) (*transaction.Step, error) {
+	if err := c.Set(volnameTxnKey, volname); err != nil {
+		return nil, err
+	}
+	return &transaction.Step{
+		DoFunc: "barrier.Disable",
+		Nodes:  nodes,
+	}, nil
+}
+
+func txnEnable(c transaction.TxnCtx) error {
+	var volname string
+	if err := c.Get(volnameTxnKey, &volname); err != nil {
+		return err
+	}
+	var timeout time.Duration
+	if err := c.Get(timeoutTxnKey, &timeout); err != nil {
+		return err
+	}
+
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		return err
+	}
+	return Enable(volinfo, timeout, c.Logger())
+}
+
+func txnDisable(c transaction.TxnCtx) error {
+	var volname string
+	if err := c.Get(volnameTxnKey, &volname); err != nil {
+		return err
+	}
+
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		return err
+	}
+	return Disable(volinfo, c.Logger())
+}
+
+// RegisterStepFuncs registers the step functions enabling and disabling the
+// barrier
+func RegisterStepFuncs() {
+	transaction.RegisterStepFunc(txnEnable, "barrier.Enable")
+	transaction.RegisterStepFunc(txnDisable, "barrier.Disable")
+}
@@ -14,6 +14,8 @@ import (
 	"github.com/cespare/xxhash"
 	"github.com/gluster/glusterd2/glusterd2/daemon"
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/pmap"
+	"github.com/gluster/glusterd2/glusterd2/simulate"
 	"github.com/gluster/glusterd2/pkg/api"
 	log "github.com/sirupsen/logrus"
 
@@ -28,6 +30,12 @@ func brickPathWithoutSlashes(brickPath string) string {
 	return strings.Trim(strings.Replace(brickPath, "/", "-", -1), "-")
 }
 
+// LogFile returns the path to the log file of the brick process serving the
+// brick at brickPath
+func LogFile(brickPath string) string {
+	return path.Join(config.GetString("logdir"), "glusterfs", "bricks", fmt.Sprintf("%s.log", brickPathWithoutSlashes(brickPath)))
+}
+
 // GetVolfileID returns Volfile ID of glusterfsd process
 func GetVolfileID(volname string, brickPath string) string {
 	return volname + "." + gdctx.MyUUID.String() + "." + brickPathWithoutSlashes(brickPath)
@@ -43,6 +51,9 @@ type Glusterfsd struct {
 
 	// For internal use
 	brickinfo Brickinfo
+	// port is the port the brick process listens on, picked by the
+	// brick process itself if 0
+	port int
 }
 
 // Name returns human-friendly name of the brick process. This is used for logging.
@@ -62,7 +73,7 @@ func (b *Glusterfsd) Args() []string {
 		return b.args
 	}
 
-	logFile := path.Join(config.GetString("logdir"), "glusterfs", "bricks", fmt.Sprintf("%s.log", brickPathWithoutSlashes(b.brickinfo.Path)))
+	logFile := LogFile(b.brickinfo.Path)
 
 	volfileID := GetVolfileID(b.brickinfo.VolumeName, b.brickinfo.Path)
 
@@ -79,6 +90,9 @@ func (b *Glusterfsd) Args() []string {
 	b.args = append(b.args, "-S", b.SocketFile())
 	b.args = append(b.args, "--brick-name", b.brickinfo.Path)
 	b.args = append(b.args, "-l", logFile)
+	if b.port != 0 {
+		b.args = append(b.args, "--brick-port", strconv.Itoa(b.port))
+	}
 	b.args = append(b.args,
 		"--xlator-option",
 		fmt.Sprintf("*-posix.glusterd-uuid=%s", gdctx.MyUUID))
@@ -137,10 +151,6 @@ func (b *Glusterfsd) ID() string {
 	return b.brickinfo.Path
 }
 
-// BrickStartMaxRetries represents maximum no. of attempts that will be made
-// to start brick processes in case of port clashes.
-const BrickStartMaxRetries = 3
-
 // Until https://review.gluster.org/#/c/16200/ gets into a release.
 // And this is fully safe too as no other well-known errno exists after 132
 
@@ -167,35 +177,61 @@ func errorContainsErrno(err error, errno syscall.Errno) bool {
 //StartBrick starts glusterfsd process
 func (b Brickinfo) StartBrick(logger log.FieldLogger) error {
 
-	for i := 0; i < BrickStartMaxRetries; i++ {
+	if err := VerifyVolumeID(&b); err != nil {
+		logger.WithError(err).WithField("brick", b.String()).Error("refusing to start brick")
+		return err
+	}
+
+	if simulate.Enabled() {
+		return b.startSimulated(logger)
+	}
 
-		// creating a new instance everytime ensures that the call to
-		// brickDaemon.Args() will get us a new port each time
+	var skip []int
+	for i := 0; ; i++ {
+		// Each attempt is on a port not tried before
+		port, err := pmap.AllocPort(skip...)
+		if err != nil {
+			return err
+		}
 		brickDaemon, err := NewGlusterfsd(b)
 		if err != nil {
+			pmap.ReleasePort(port)
 			return err
 		}
+		brickDaemon.port = port
 
 		err = daemon.Start(brickDaemon, true, logger)
-		if err != nil {
-			if errorContainsErrno(err, syscall.EADDRINUSE) || errorContainsErrno(err, anotherEADDRINUSE) {
-				// Retry iff brick failed to start because of port being in use.
-				// Allow the previous instance to cleanup and exit
-				time.Sleep(1 * time.Second)
-			} else {
-				return err
+		if err == nil {
+			// A brick started again, on a replaced or repaired
+			// disk, is no longer degraded
+			if err := ClearDegraded(b); err != nil {
+				logger.WithError(err).WithField("brick", b.String()).Warn("failed to clear the degraded state of the brick")
 			}
-		} else {
-			break
+			return nil
 		}
-	}
+		pmap.ReleasePort(port)
 
-	return nil
+		// Retry iff brick failed to start because of port being in use,
+		// by a brick process which crashed or another service
+		inUse := errorContainsErrno(err, syscall.EADDRINUSE) || errorContainsErrno(err, anotherEADDRINUSE)
+		if !inUse || i >= portRetries() {
+			return err
+		}
+		logger.WithFields(log.Fields{
+			"brick": b.String(),
+			"port":  port,
+		}).Warn("brick port in use, starting the brick on another port")
+		skip = append(skip, port)
+	}
 }
 
 //TerminateBrick will stop glusterfsd process
 func (b Brickinfo) TerminateBrick() error {
 
+	if simulate.Enabled() {
+		return b.stopSimulated(log.StandardLogger())
+	}
+
 	brickDaemon, err := NewGlusterfsd(b)
 	if err != nil {
 		return err
@@ -241,6 +277,10 @@ func (b Brickinfo) TerminateBrick() error {
 //StopBrick will stop glusterfsd process
 func (b Brickinfo) StopBrick(logger log.FieldLogger) error {
 
+	if simulate.Enabled() {
+		return b.stopSimulated(logger)
+	}
+
 	brickDaemon, err := NewGlusterfsd(b)
 	if err != nil {
 		return err
@@ -267,6 +307,7 @@ func CreateBrickInfo(b *Brickinfo) api.BrickInfo {
 		PeerID:     b.PeerID,
 		Hostname:   b.Hostname,
 		Type:       api.BrickType(b.Type),
+		Encrypted:  b.Encrypted,
 	}
 }
 
@@ -294,8 +335,24 @@ func CreateBrickStatusRsp(brickStatuses []Brickstatus) []*api.BrickStatus {
 			MountOpts: status.MountOpts,
 			Device:    status.Device,
 			Size:      CreateBrickSizeInfo(&status.Size),
+			Resources: status.Resources,
+		}
+		if status.Degraded != nil {
+			s.Degraded = true
+			s.HealthCheckError = status.Degraded.Error
+			s.IOErrors = status.Degraded.IOErrors
 		}
 		brickStatusesRsp = append(brickStatusesRsp, s)
 	}
 	return brickStatusesRsp
 }
+
+func init() {
+	daemon.RegisterPolicy("glusterfsd", daemon.Policy{
+		Restart:             daemon.RestartAlways,
+		MaxRestarts:         5,
+		RestartWindow:       10 * time.Minute,
+		HealthCheck:         daemon.SocketHealthCheck,
+		HealthCheckFailures: 3,
+	})
+}
@@ -0,0 +1,44 @@
+package brick
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// entries pending heal are indexed in this directory of the brick
+	xattropIndexDir = ".glusterfs/indices/xattrop"
+	// the entries in the index are hard links to the base file, whose name
+	// has this prefix
+	xattropBasePrefix = "xattrop-"
+)
+
+// CountPendingHeals returns the number of entries pending heal on the brick
+func CountPendingHeals(brickPath string) (int, error) {
+	dir, err := os.Open(filepath.Join(brickPath, xattropIndexDir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	defer dir.Close()
+
+	count := 0
+	for {
+		names, err := dir.Readdirnames(1024)
+		for _, name := range names {
+			if !strings.HasPrefix(name, xattropBasePrefix) {
+				count++
+			}
+		}
+		if err == io.EOF {
+			return count, nil
+		}
+		if err != nil {
+			return count, err
+		}
+	}
+}
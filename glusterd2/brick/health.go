@@ -0,0 +1,80 @@
+package brick
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/store"
+
+	"github.com/coreos/etcd/clientv3"
+)
+
+// degradedPrefix is where the bricks whose posix health-check failed, or
+// which exceeded their I/O error budget, are saved in the store, by brick ID
+const degradedPrefix = "bricks/degraded/"
+
+// Degraded is a brick whose posix health-check failed, or which exceeded
+// its I/O error budget, and which can no longer be relied upon to serve its
+// data
+type Degraded struct {
+	Brick  Brickinfo `json:"brick"`
+	Error  string    `json:"error"`
+	Since  time.Time `json:"since"`
+	Killed bool      `json:"killed"`
+	// IOErrors is the number of I/O errors the brick reported within the
+	// window of its budget, 0 for a failed health-check
+	IOErrors int `json:"io-errors,omitempty"`
+}
+
+// MarkDegraded saves the brick as degraded in the store
+func MarkDegraded(d *Degraded) error {
+	data, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+	_, err = store.Put(context.TODO(), degradedPrefix+d.Brick.ID.String(), string(data))
+	return err
+}
+
+// ClearDegraded removes the degraded mark of the brick, if any
+func ClearDegraded(b Brickinfo) error {
+	_, err := store.Delete(context.TODO(), degradedPrefix+b.ID.String())
+	return err
+}
+
+// GetDegraded returns the degraded mark of the brick, nil if the brick is
+// healthy
+func GetDegraded(b Brickinfo) (*Degraded, error) {
+	resp, err := store.Get(context.TODO(), degradedPrefix+b.ID.String())
+	if err != nil {
+		return nil, err
+	}
+	if resp.Count != 1 {
+		return nil, nil
+	}
+
+	var d Degraded
+	if err := json.Unmarshal(resp.Kvs[0].Value, &d); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// GetDegradedBricks returns all the degraded bricks of the cluster
+func GetDegradedBricks() ([]*Degraded, error) {
+	resp, err := store.Get(context.TODO(), degradedPrefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	bricks := make([]*Degraded, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var d Degraded
+		if err := json.Unmarshal(kv.Value, &d); err != nil {
+			return nil, err
+		}
+		bricks = append(bricks, &d)
+	}
+	return bricks, nil
+}
@@ -0,0 +1,29 @@
+package brick
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateBrickStatusRspDegraded(t *testing.T) {
+	statuses := []Brickstatus{
+		{Info: Brickinfo{Path: "/bricks/b1"}, Online: true},
+		{Info: Brickinfo{Path: "/bricks/b2"}, Degraded: &Degraded{Error: "write failed: Input/output error", Killed: true}},
+		{Info: Brickinfo{Path: "/bricks/b3"}, Online: true, Degraded: &Degraded{Error: "too many I/O errors", IOErrors: 12}},
+	}
+
+	rsp := CreateBrickStatusRsp(statuses)
+	require.Len(t, rsp, 3)
+
+	assert.False(t, rsp[0].Degraded)
+	assert.Empty(t, rsp[0].HealthCheckError)
+
+	assert.True(t, rsp[1].Degraded)
+	assert.Equal(t, "write failed: Input/output error", rsp[1].HealthCheckError)
+	assert.Zero(t, rsp[1].IOErrors)
+
+	assert.True(t, rsp[2].Degraded)
+	assert.Equal(t, 12, rsp[2].IOErrors)
+}
@@ -0,0 +1,138 @@
+package brick
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	config "github.com/spf13/viper"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	relabelOpt = "selinux-relabel-bricks"
+
+	selinuxXattr = "security.selinux"
+	// SELinuxType is the SELinux type of the brick paths, allowing the
+	// brick processes to serve them
+	SELinuxType = "glusterd_brick_t"
+	// defaultLabel is the SELinux context given to the brick paths which
+	// have none
+	defaultLabel = "system_u:object_r:" + SELinuxType + ":s0"
+)
+
+// selinuxEnforce is the file telling if SELinux is enabled, and enforcing
+var selinuxEnforce = "/sys/fs/selinux/enforce"
+
+// LabelError is returned for a brick path with the wrong SELinux context
+type LabelError struct {
+	Path  string
+	Label string
+}
+
+func (e *LabelError) Error() string {
+	return fmt.Sprintf("brick path %s has the SELinux context %s, not of type %s, relabel it with: %s",
+		e.Path, e.Label, SELinuxType, strings.Join(relabelCommand(e.Path), " "))
+}
+
+func relabelCommand(path string) []string {
+	return []string{"chcon", "-R", "-t", SELinuxType, path}
+}
+
+// selinuxEnabled tells if SELinux is enabled on this peer
+func selinuxEnabled() bool {
+	_, err := os.Stat(selinuxEnforce)
+	return err == nil
+}
+
+// getLabel returns the SELinux context of the path, empty if it has none
+func getLabel(path string) (string, error) {
+	buf := make([]byte, 256)
+	sz, err := unix.Lgetxattr(path, selinuxXattr, buf)
+	if err == unix.ENODATA {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+	return string(bytes.TrimRight(buf[:sz], "\x00")), nil
+}
+
+// labelType returns the type of the SELinux context
+func labelType(label string) string {
+	fields := strings.SplitN(label, ":", 4)
+	if len(fields) < 3 {
+		return ""
+	}
+	return fields[2]
+}
+
+// withType returns the SELinux context with its type replaced by the type of
+// the brick paths
+func withType(label string) string {
+	fields := strings.SplitN(label, ":", 4)
+	if len(fields) < 3 {
+		return defaultLabel
+	}
+	fields[2] = SELinuxType
+	return strings.Join(fields, ":")
+}
+
+// SetLabel gives the brick directory, and its .glusterfs directory if it
+// exists, the SELinux type of the brick paths. The files created in them
+// then inherit it.
+func (b *Brickinfo) SetLabel() error {
+	if !selinuxEnabled() {
+		return nil
+	}
+	for _, p := range []string{b.Path, filepath.Join(b.Path, ".glusterfs")} {
+		label, err := getLabel(p)
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		if labelType(label) == SELinuxType {
+			continue
+		}
+		if err := unix.Lsetxattr(p, selinuxXattr, []byte(withType(label)), 0); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CheckLabel checks that the brick directory has the SELinux type of the
+// brick paths. A mislabeled brick is relabeled if selinux-relabel-bricks is
+// set, and a *LabelError is returned otherwise. A brick directory which does
+// not exist, like the one of a brick not mounted yet, is not checked.
+func (b *Brickinfo) CheckLabel(logger log.FieldLogger) error {
+	if !selinuxEnabled() {
+		return nil
+	}
+	label, err := getLabel(b.Path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if labelType(label) == SELinuxType {
+		return nil
+	}
+	if !config.GetBool(relabelOpt) {
+		return &LabelError{Path: b.Path, Label: label}
+	}
+
+	logger.WithFields(log.Fields{
+		"brick": b.String(),
+		"label": label,
+	}).Warn("relabeling the mislabeled brick path")
+	cmd := relabelCommand(b.Path)
+	if out, err := exec.Command(cmd[0], cmd[1:]...).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to relabel brick path %s: %s: %s", b.Path, err, bytes.TrimSpace(out))
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package brick
+
+import (
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLabelType(t *testing.T) {
+	assert.Equal(t, "default_t", labelType("system_u:object_r:default_t:s0"))
+	assert.Equal(t, "", labelType(""))
+
+	assert.Equal(t, "system_u:object_r:glusterd_brick_t:s0", withType("system_u:object_r:default_t:s0"))
+	assert.Equal(t, "unconfined_u:object_r:glusterd_brick_t:s0:c1,c2", withType("unconfined_u:object_r:user_home_t:s0:c1,c2"))
+	assert.Equal(t, defaultLabel, withType(""))
+}
+
+func TestLabelError(t *testing.T) {
+	err := &LabelError{Path: "/bricks/b1", Label: "system_u:object_r:default_t:s0"}
+	assert.Contains(t, err.Error(), "chcon -R -t glusterd_brick_t /bricks/b1")
+}
+
+func TestLabelSELinuxDisabled(t *testing.T) {
+	defer func(enforce string) { selinuxEnforce = enforce }(selinuxEnforce)
+	selinuxEnforce = "/nonexistent/enforce"
+
+	b := &Brickinfo{Path: "/nonexistent/brick"}
+	assert.NoError(t, b.SetLabel())
+	assert.NoError(t, b.CheckLabel(log.StandardLogger()))
+}
@@ -0,0 +1,37 @@
+package brick
+
+import (
+	"github.com/gluster/glusterd2/glusterd2/pmap"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// startSimulated serves the brick on a port of the pmap registry, without
+// starting a brick process. The bricks of a glusterd2 hosting virtual peers
+// are not started, as the bricks of the virtual peers share its filesystem.
+func (b Brickinfo) startSimulated(logger log.FieldLogger) error {
+	if _, err := pmap.RegistrySearch(b.Path); err == nil {
+		return nil
+	}
+
+	port, err := pmap.AllocPort()
+	if err != nil {
+		return err
+	}
+	pmap.RegistryExtend(b.Path, port, 0)
+
+	logger.WithFields(log.Fields{
+		"brick": b.String(),
+		"port":  port,
+	}).Info("simulated brick started")
+	return nil
+}
+
+// stopSimulated removes the brick started by startSimulated from the pmap
+// registry
+func (b Brickinfo) stopSimulated(logger log.FieldLogger) error {
+	pmap.RegistryRemove(b.Path)
+
+	logger.WithField("brick", b.String()).Info("simulated brick stopped")
+	return nil
+}
@@ -0,0 +1,132 @@
+package brick
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/pmap"
+	"github.com/gluster/glusterd2/pkg/errors"
+
+	log "github.com/sirupsen/logrus"
+	config "github.com/spf13/viper"
+)
+
+const (
+	startWorkersOpt  = "brick-start-workers"
+	signInTimeoutOpt = "brick-signin-timeout"
+	portRetriesOpt   = "brick-port-retries"
+
+	defaultStartWorkers  = 8
+	defaultSignInTimeout = 30 * time.Second
+	defaultPortRetries   = 3
+
+	signInPollInterval = 100 * time.Millisecond
+)
+
+// registrySearch looks up the port of a brick signed in with the port mapper
+var registrySearch = pmap.RegistrySearch
+
+// StartResult is the outcome of starting a brick
+type StartResult struct {
+	Brick Brickinfo
+	// Duration is the time taken to start the brick and for it to sign in
+	// with the port mapper
+	Duration time.Duration
+	SignedIn bool
+	Err      error
+}
+
+// startWorkers returns the number of brick processes started at once
+func startWorkers() int {
+	if n := config.GetInt(startWorkersOpt); n > 0 {
+		return n
+	}
+	return 1
+}
+
+// portRetries returns the number of times a brick process is started again
+// on another port, when its port is in use
+func portRetries() int {
+	n := config.GetInt(portRetriesOpt)
+	if n < 0 {
+		return 0
+	}
+	return n
+}
+
+// runParallel runs f for each of the n items, on at most workers items at
+// once
+func runParallel(n, workers int, f func(i int)) {
+	if workers > n {
+		workers = n
+	}
+
+	items := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range items {
+				f(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		items <- i
+	}
+	close(items)
+	wg.Wait()
+}
+
+// waitSignIn waits for the brick to sign in with the port mapper, which it
+// does once it is ready to serve the clients. It returns false if the brick
+// did not sign in before the timeout.
+func waitSignIn(path string, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for {
+		if _, err := registrySearch(path); err == nil {
+			return true
+		}
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(signInPollInterval)
+	}
+}
+
+// StartBricks starts the bricks in parallel, starting at most
+// brick-start-workers brick processes at once, and waits for each brick to
+// sign in with the port mapper for up to brick-signin-timeout. A brick not
+// signed in by then is left starting, and is reported as such. The results
+// are in the order of the bricks.
+func StartBricks(bricks []Brickinfo, logger log.FieldLogger) []StartResult {
+	timeout := config.GetDuration(signInTimeoutOpt)
+
+	results := make([]StartResult, len(bricks))
+	runParallel(len(bricks), startWorkers(), func(i int) {
+		b := bricks[i]
+		start := time.Now()
+
+		err := b.StartBrick(logger)
+		if err == errors.ErrProcessAlreadyRunning {
+			err = nil
+		}
+		results[i] = StartResult{Brick: b, Err: err}
+		if err != nil {
+			results[i].Duration = time.Since(start)
+			return
+		}
+
+		results[i].SignedIn = waitSignIn(b.Path, timeout)
+		results[i].Duration = time.Since(start)
+		if !results[i].SignedIn {
+			logger.WithFields(log.Fields{
+				"brick":   b.String(),
+				"timeout": timeout,
+			}).Warn("brick did not sign in with the port mapper in time")
+		}
+	})
+
+	return results
+}
@@ -0,0 +1,59 @@
+package brick
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunParallel(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		running int
+		most    int
+		done    = make([]bool, 10)
+	)
+	runParallel(len(done), 3, func(i int) {
+		mu.Lock()
+		running++
+		if running > most {
+			most = running
+		}
+		mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		mu.Lock()
+		running--
+		done[i] = true
+		mu.Unlock()
+	})
+
+	assert.True(t, most <= 3)
+	for i := range done {
+		assert.True(t, done[i])
+	}
+
+	// No items, nothing run
+	runParallel(0, 3, func(i int) {
+		t.Fatal("nothing to run")
+	})
+}
+
+func TestWaitSignIn(t *testing.T) {
+	defer func(f func(string) (int, error)) { registrySearch = f }(registrySearch)
+
+	signInAt := time.Now().Add(3 * signInPollInterval)
+	registrySearch = func(path string) (int, error) {
+		if path == "/bricks/b1" && time.Now().After(signInAt) {
+			return 49152, nil
+		}
+		return -1, errors.New("not found")
+	}
+
+	assert.True(t, waitSignIn("/bricks/b1", time.Second))
+	assert.False(t, waitSignIn("/bricks/b2", 2*signInPollInterval))
+}
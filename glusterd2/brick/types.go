@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/gluster/glusterd2/pkg/api"
+
 	"github.com/pborman/uuid"
 	"golang.org/x/sys/unix"
 )
@@ -39,6 +41,8 @@ type DeviceInfo struct {
 	VgName     string
 	RootDevice string
 	TotalSize  uint64
+	// Encrypted tells if the brick is encrypted at rest with dm-crypt
+	Encrypted bool
 }
 
 // Brickinfo is the static information about the brick
@@ -53,6 +57,8 @@ type Brickinfo struct {
 	Type           Type
 	Decommissioned bool
 	PType          ProvisionType
+	// User is the user the brick process is run as, root if empty
+	User string
 	MountInfo
 	DeviceInfo
 }
@@ -74,6 +80,8 @@ type Brickstatus struct {
 	MountOpts string
 	Device    string
 	Size      SizeInfo
+	Degraded  *Degraded
+	Resources *api.ResourceUsage
 }
 
 const (
@@ -134,6 +142,10 @@ func (b *Brickinfo) Validate(check InitChecks, allLocalBricks []Brickinfo) error
 		return err
 	}
 
+	if _, err = ValidateUser(b.User); err != nil {
+		return err
+	}
+
 	if _, err = os.Stat(b.Path); os.IsNotExist(err) {
 		if check.CreateBrickDir {
 			if err = os.MkdirAll(b.Path, 0775); err != nil {
@@ -160,7 +172,7 @@ func (b *Brickinfo) Validate(check InitChecks, allLocalBricks []Brickinfo) error
 
 	if check.IsOnRoot {
 		if err = validateIsOnRootDevice(&brickStat); err != nil {
-			return err
+			return fmt.Errorf("brick path %s: %s", b.Path, err)
 		}
 	}
 
@@ -174,7 +186,10 @@ func (b *Brickinfo) Validate(check InitChecks, allLocalBricks []Brickinfo) error
 		}
 	}
 
-	// mandatory check that cannot be skipped forcefully
+	// mandatory checks that cannot be skipped forcefully
+	if err = validateNotNested(b, allLocalBricks); err != nil {
+		return err
+	}
 	return isBrickInActiveUse(b.Path, allLocalBricks)
 }
 
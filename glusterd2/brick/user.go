@@ -0,0 +1,118 @@
+package brick
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+
+	flag "github.com/spf13/pflag"
+	config "github.com/spf13/viper"
+	"golang.org/x/sys/unix"
+)
+
+const userOpt = "brick-user"
+
+// capabilities are the capabilities kept by brick processes run as a user
+// other than root. The brick process stores the ownership, the permissions
+// and the trusted xattrs of the files of the clients, and binds its port.
+var capabilities = []uintptr{
+	unix.CAP_CHOWN,
+	unix.CAP_DAC_OVERRIDE,
+	unix.CAP_DAC_READ_SEARCH,
+	unix.CAP_FOWNER,
+	unix.CAP_FSETID,
+	unix.CAP_MKNOD,
+	unix.CAP_NET_BIND_SERVICE,
+	unix.CAP_SETGID,
+	unix.CAP_SETUID,
+	unix.CAP_SYS_ADMIN,
+	unix.CAP_SYS_RESOURCE,
+}
+
+// InitFlags intializes the command line options for the bricks
+func InitFlags() {
+	flag.String(userOpt, "", "User the brick processes of new volumes are run as, with the capabilities they need. Leave empty to run them as root.")
+	flag.Bool(relabelOpt, false, "Relabel the brick paths with the wrong SELinux context when starting volumes, instead of failing to start them.")
+	flag.Int(startWorkersOpt, defaultStartWorkers, "Maximum number of brick processes of a volume started at once on this peer.")
+	flag.Duration(signInTimeoutOpt, defaultSignInTimeout, "Time to wait for a brick started to sign in with the port mapper, before reporting it as not signed in.")
+	flag.Int(portRetriesOpt, defaultPortRetries, "Number of times a brick process which fails to listen on its port, in use by another process, is started again on the next free port.")
+}
+
+// NormalizeUser returns the user a brick process is run as, with root being
+// the empty string
+func NormalizeUser(name string) string {
+	if name == "root" {
+		return ""
+	}
+	return name
+}
+
+// DefaultUser returns the user the brick processes of new volumes are run as,
+// an empty string being root
+func DefaultUser() string {
+	return NormalizeUser(config.GetString(userOpt))
+}
+
+// ValidateUser checks that the user the brick processes are to be run as
+// exists on this peer, and returns it normalized
+func ValidateUser(name string) (string, error) {
+	name = NormalizeUser(name)
+	if name == "" {
+		return name, nil
+	}
+	if _, _, err := lookupUser(name); err != nil {
+		return "", fmt.Errorf("brick user %s: %s", name, err)
+	}
+	return name, nil
+}
+
+func lookupUser(name string) (int, int, error) {
+	u, err := user.Lookup(name)
+	if err != nil {
+		return 0, 0, err
+	}
+	uid, err := strconv.Atoi(u.Uid)
+	if err != nil {
+		return 0, 0, err
+	}
+	gid, err := strconv.Atoi(u.Gid)
+	if err != nil {
+		return 0, 0, err
+	}
+	return uid, gid, nil
+}
+
+// Chown gives the brick directory, and its .glusterfs directory if it
+// exists, to the user the brick process is run as
+func (b *Brickinfo) Chown() error {
+	if b.User == "" {
+		return nil
+	}
+	uid, gid, err := lookupUser(b.User)
+	if err != nil {
+		return err
+	}
+	for _, p := range []string{b.Path, filepath.Join(b.Path, ".glusterfs")} {
+		if err := os.Lchown(p, uid, gid); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// User returns the user the brick process is run as, an empty string being
+// the user of glusterd2
+func (b *Glusterfsd) User() string {
+	return b.brickinfo.User
+}
+
+// Capabilities returns the capabilities the brick process keeps when it is
+// run as another user
+func (b *Glusterfsd) Capabilities() []uintptr {
+	if b.brickinfo.User == "" {
+		return nil
+	}
+	return capabilities
+}
@@ -0,0 +1,35 @@
+package brick
+
+import (
+	"os/user"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+)
+
+func TestValidateUser(t *testing.T) {
+	assert.Equal(t, "", NormalizeUser("root"))
+	assert.Equal(t, "gluster", NormalizeUser("gluster"))
+
+	name, err := ValidateUser("root")
+	require.NoError(t, err)
+	assert.Equal(t, "", name)
+
+	current, err := user.Current()
+	require.NoError(t, err)
+	name, err = ValidateUser(current.Username)
+	require.NoError(t, err)
+	assert.Equal(t, NormalizeUser(current.Username), name)
+
+	_, err = ValidateUser("no-such-user-gd2")
+	assert.Error(t, err)
+}
+
+func TestGlusterfsdCapabilities(t *testing.T) {
+	assert.Nil(t, (&Glusterfsd{}).Capabilities())
+	b := &Glusterfsd{brickinfo: Brickinfo{User: "gluster"}}
+	assert.Equal(t, "gluster", b.User())
+	assert.Contains(t, b.Capabilities(), uintptr(unix.CAP_SYS_ADMIN))
+}
@@ -21,12 +21,19 @@ const (
 	volumeIDXattrSize = 16
 )
 
+// glusterXattrPrefixes are the prefixes of the xattrs set by gluster on the
+// root of the bricks
+var glusterXattrPrefixes = []string{"trusted.gfid", "trusted.glusterfs.", "trusted.afr.", "trusted.ec."}
+
 // InitChecks is a set of checks to be run on a brick
 type InitChecks struct {
 	WasInUse       bool
 	IsMount        bool
 	IsOnRoot       bool
 	CreateBrickDir bool
+	// CleanXattrs removes the gluster xattrs left on the brick by a
+	// previous volume before the brick is initialized
+	CleanXattrs bool
 }
 
 // PrepareChecks initializes InitChecks based on req
@@ -34,9 +41,11 @@ func PrepareChecks(force bool, req map[string]bool) *InitChecks {
 	c := &InitChecks{}
 
 	if force {
-		// skip all checks if force is set to true
+		// skip all checks if force is set to true, and reuse the
+		// bricks of previous volumes
 
 		c.CreateBrickDir = true
+		c.CleanXattrs = true
 		return c
 	}
 
@@ -112,25 +121,48 @@ func validateXattrSupport(brickPath string) error {
 	return unix.Setxattr(brickPath, testXattrKey, []byte("payload"), 0)
 }
 
-// validateBrickWasUsed checks if the path was ever used a brick for a volume
-// by checking if the path has certain glusterfs specific xattrs
+// validateBrickWasUsed checks if the path, or a directory above it, was ever
+// used as a brick of a volume, by checking for the xattrs set by glusterfs
+// on the bricks
 func validateBrickWasUsed(brickPath string) error {
 	keys := []string{gfidXattrKey, volumeIDXattrKey}
-	for path := brickPath; path != "/"; path = filepath.Dir(path) {
+	for path := filepath.Clean(brickPath); path != "/"; path = filepath.Dir(path) {
 		for _, key := range keys {
-			size, err := unix.Getxattr(path, key, nil)
-			if err != nil {
-				continue
-			} else if size > 0 {
-				return fmt.Errorf("xattr %s already present on %s", key, path)
-			} else {
-				return nil
+			if size, err := unix.Getxattr(path, key, nil); err == nil && size > 0 {
+				return fmt.Errorf("xattr %s present on %s: %s", key, path, errors.ErrBrickPathWasUsed)
 			}
 		}
 	}
 	return nil
 }
 
+// isSubdir tells if path is inside dir. Both paths are clean.
+func isSubdir(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+	return rel != "." && rel != ".." && !strings.HasPrefix(rel, "../")
+}
+
+// validateNotNested checks that the brick is not inside another brick of
+// this peer, and that no other brick is inside it. Bricks inside each other
+// would see the data of each other.
+func validateNotNested(b *Brickinfo, allLocalBricks []Brickinfo) error {
+	brickPath := filepath.Clean(b.Path)
+	for _, other := range allLocalBricks {
+		if uuid.Equal(other.ID, b.ID) {
+			continue
+		}
+		otherPath := filepath.Clean(other.Path)
+		if isSubdir(brickPath, otherPath) || isSubdir(otherPath, brickPath) {
+			return fmt.Errorf("brick path %s, brick %s of volume %s: %s",
+				b.Path, other.Path, other.VolumeName, errors.ErrBrickPathNested)
+		}
+	}
+	return nil
+}
+
 // isBrickInActiveUse checks if the path belongs to another active brick
 // belonging to an active volume currently present in this cluster.
 func isBrickInActiveUse(brickPath string, allLocalBricks []Brickinfo) error {
@@ -147,14 +179,36 @@ func isBrickInActiveUse(brickPath string, allLocalBricks []Brickinfo) error {
 
 	for _, b := range allLocalBricks {
 		if uuid.Equal(volumeID, b.VolumeID) {
-			return fmt.Errorf("Brick path %s is already in use by a volume (name=%s;id=%s)",
-				brickPath, b.VolumeName, b.VolumeID)
-		}
-		if strings.HasPrefix(brickPath, b.Path) {
-			return fmt.Errorf("path %s is a subdirectory of another existing brick with path %s belonging to volume (name=%s;id=%s)",
-				brickPath, b.Path, b.VolumeName, b.VolumeID)
+			return fmt.Errorf("brick path %s, volume (name=%s;id=%s): %s",
+				brickPath, b.VolumeName, b.VolumeID, errors.ErrBrickPathAlreadyInUse)
 		}
 	}
 
 	return nil
 }
+
+// CleanXattrs removes the gluster xattrs left on the brick path by a previous
+// volume. The contents of the brick are left as they are.
+func CleanXattrs(brickPath string) error {
+	size, err := unix.Listxattr(brickPath, nil)
+	if err != nil || size == 0 {
+		return err
+	}
+	buf := make([]byte, size)
+	if size, err = unix.Listxattr(brickPath, buf); err != nil {
+		return err
+	}
+
+	for _, key := range strings.Split(strings.TrimRight(string(buf[:size]), "\x00"), "\x00") {
+		for _, prefix := range glusterXattrPrefixes {
+			if !strings.HasPrefix(key, prefix) {
+				continue
+			}
+			if err := unix.Removexattr(brickPath, key); err != nil && err != unix.ENODATA {
+				return err
+			}
+			break
+		}
+	}
+	return nil
+}
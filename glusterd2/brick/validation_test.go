@@ -0,0 +1,36 @@
+package brick
+
+import (
+	"testing"
+
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsSubdir(t *testing.T) {
+	assert.True(t, isSubdir("/bricks/b1/sub", "/bricks/b1"))
+	assert.True(t, isSubdir("/bricks/b1/a/b", "/bricks"))
+	assert.False(t, isSubdir("/bricks/b1", "/bricks/b1"))
+	assert.False(t, isSubdir("/bricks/b10", "/bricks/b1"))
+	assert.False(t, isSubdir("/bricks", "/bricks/b1"))
+	assert.False(t, isSubdir("/data/b1", "/bricks"))
+}
+
+func TestValidateNotNested(t *testing.T) {
+	existing := []Brickinfo{
+		{ID: uuid.NewRandom(), Path: "/bricks/b1", VolumeName: "vol1"},
+		{ID: uuid.NewRandom(), Path: "/bricks/b2/", VolumeName: "vol1"},
+	}
+
+	b := &Brickinfo{ID: uuid.NewRandom(), Path: "/bricks/b10"}
+	assert.Nil(t, validateNotNested(b, existing))
+
+	b.Path = "/bricks/b2/sub"
+	assert.NotNil(t, validateNotNested(b, existing))
+
+	b.Path = "/bricks"
+	assert.NotNil(t, validateNotNested(b, existing))
+
+	// a brick is not nested in itself
+	assert.Nil(t, validateNotNested(&existing[0], existing))
+}
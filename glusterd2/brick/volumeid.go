@@ -0,0 +1,61 @@
+package brick
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/pborman/uuid"
+	"golang.org/x/sys/unix"
+)
+
+// VerifyVolumeID checks that the brick path holds the data of the volume of
+// the brick, as told by the volume ID xattr set on it when the brick was
+// created, so that a brick never serves the data of another volume after a
+// disk swap or a mix up of mounts. A brick path without the xattr is stamped
+// with the volume ID if it is empty, and refused otherwise.
+func VerifyVolumeID(b *Brickinfo) error {
+	data := make([]byte, volumeIDXattrSize)
+	size, err := unix.Getxattr(b.Path, volumeIDXattrKey, data)
+	if err == unix.ENODATA {
+		empty, err := isEmptyDir(b.Path)
+		if err != nil {
+			return err
+		}
+		if !empty {
+			return fmt.Errorf("brick path %s of volume %s: %s",
+				b.Path, b.VolumeName, errors.ErrBrickVolumeIDMissing)
+		}
+		return unix.Setxattr(b.Path, volumeIDXattrKey, []byte(b.VolumeID), unix.XATTR_CREATE)
+	}
+	if err != nil {
+		return err
+	}
+
+	if found := uuid.UUID(data[:size]); !uuid.Equal(found, b.VolumeID) {
+		return fmt.Errorf("brick path %s has volume ID %s, expected %s of volume %s: %s",
+			b.Path, found, b.VolumeID, b.VolumeName, errors.ErrBrickVolumeIDMismatch)
+	}
+	return nil
+}
+
+// ResetVolumeID sets the volume ID xattr of the brick path to the volume of
+// the brick, whatever volume the path belonged to
+func ResetVolumeID(b *Brickinfo) error {
+	return unix.Setxattr(b.Path, volumeIDXattrKey, []byte(b.VolumeID), 0)
+}
+
+func isEmptyDir(path string) (bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	if _, err := f.Readdirnames(1); err != io.EOF {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,51 @@
+package brick
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+)
+
+func TestVerifyVolumeID(t *testing.T) {
+	dir, err := ioutil.TempDir("", "brick")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	// trusted xattrs need privileges
+	if err := unix.Setxattr(dir, testXattrKey, []byte("true"), 0); err != nil {
+		t.Skip("trusted xattrs not supported:", err)
+	}
+	require.Nil(t, unix.Removexattr(dir, testXattrKey))
+
+	b := &Brickinfo{Path: dir, VolumeName: "vol1", VolumeID: uuid.NewRandom()}
+
+	// An empty brick path is stamped
+	require.Nil(t, VerifyVolumeID(b))
+	require.Nil(t, VerifyVolumeID(b))
+
+	other := &Brickinfo{Path: dir, VolumeName: "vol2", VolumeID: uuid.NewRandom()}
+	err = VerifyVolumeID(other)
+	require.NotNil(t, err)
+	assert.True(t, strings.HasSuffix(err.Error(), errors.ErrBrickVolumeIDMismatch.Error()))
+	assert.Contains(t, err.Error(), b.VolumeID.String())
+
+	require.Nil(t, ResetVolumeID(other))
+	assert.Nil(t, VerifyVolumeID(other))
+	assert.NotNil(t, VerifyVolumeID(b))
+
+	// A brick path with data is not stamped
+	require.Nil(t, unix.Removexattr(dir, volumeIDXattrKey))
+	require.Nil(t, ioutil.WriteFile(filepath.Join(dir, "file"), []byte("data"), 0644))
+	err = VerifyVolumeID(b)
+	require.NotNil(t, err)
+	assert.True(t, strings.HasSuffix(err.Error(), errors.ErrBrickVolumeIDMissing.Error()))
+}
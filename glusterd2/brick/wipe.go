@@ -0,0 +1,46 @@
+package brick
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// metadataDir is the directory of the brick where glusterfs keeps the
+// hardlinks of the files by GFID, and its other internal metadata
+const metadataDir = ".glusterfs"
+
+// CleanMetadata removes the metadata kept by glusterfs on the brick: its
+// internal directory and the gluster xattrs of the brick root and of all the
+// files on it. The data of the files is left as it is.
+func CleanMetadata(brickPath string) error {
+	if err := os.RemoveAll(filepath.Join(brickPath, metadataDir)); err != nil {
+		return err
+	}
+
+	return filepath.Walk(brickPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		// Listing the xattrs of a symlink would follow it
+		if info.Mode()&os.ModeSymlink != 0 {
+			return nil
+		}
+		return CleanXattrs(path)
+	})
+}
+
+// WipeData removes all the contents of the brick, and the gluster xattrs of
+// its root. The brick directory itself is left, as it may be a mount point.
+func WipeData(brickPath string) error {
+	entries, err := ioutil.ReadDir(brickPath)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if err := os.RemoveAll(filepath.Join(brickPath, e.Name())); err != nil {
+			return err
+		}
+	}
+	return CleanXattrs(brickPath)
+}
@@ -0,0 +1,43 @@
+package brick
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestBrick(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "brick")
+	require.Nil(t, err)
+	require.Nil(t, os.MkdirAll(filepath.Join(dir, metadataDir, "00", "00"), 0755))
+	require.Nil(t, os.MkdirAll(filepath.Join(dir, "dir"), 0755))
+	require.Nil(t, ioutil.WriteFile(filepath.Join(dir, "dir", "file"), []byte("data"), 0644))
+	require.Nil(t, os.Symlink("dir/file", filepath.Join(dir, "link")))
+	return dir
+}
+
+func TestCleanMetadata(t *testing.T) {
+	dir := newTestBrick(t)
+	defer os.RemoveAll(dir)
+
+	require.Nil(t, CleanMetadata(dir))
+	_, err := os.Stat(filepath.Join(dir, metadataDir))
+	assert.True(t, os.IsNotExist(err))
+	data, err := ioutil.ReadFile(filepath.Join(dir, "link"))
+	assert.Nil(t, err)
+	assert.Equal(t, "data", string(data))
+}
+
+func TestWipeData(t *testing.T) {
+	dir := newTestBrick(t)
+	defer os.RemoveAll(dir)
+
+	require.Nil(t, WipeData(dir))
+	entries, err := ioutil.ReadDir(dir)
+	assert.Nil(t, err)
+	assert.Empty(t, entries)
+}
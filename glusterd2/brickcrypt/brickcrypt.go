@@ -0,0 +1,106 @@
+// Package brickcrypt encrypts the bricks provisioned by glusterd2 at rest,
+// layering LUKS (dm-crypt) over their logical volumes. The keys of the
+// bricks are saved in the store, sealed with a master key never leaving the
+// peer of the bricks, or in an external KMS.
+package brickcrypt
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/pkg/cryptutils"
+
+	flag "github.com/spf13/pflag"
+)
+
+const kmsCommandOpt = "brick-kms-command"
+
+// InitFlags intializes the command line options for brick encryption
+func InitFlags() {
+	flag.String(kmsCommandOpt, "", "Command saving and fetching the encryption keys of the bricks in an external KMS, invoked as '<command> get|put|delete <key-id>'. The keys are saved in the store when not set.")
+}
+
+// keyID returns the ID of the key of the brick on the logical volume
+func keyID(vgName, lvName string) string {
+	return gdctx.MyUUID.String() + "/" + vgName + "/" + lvName
+}
+
+// MapperName returns the name the encrypted brick on the logical volume is
+// mapped under once unlocked
+func MapperName(vgName, lvName string) string {
+	return "gd2crypt_" + vgName + "_" + lvName
+}
+
+// DevicePath returns the path of the encrypted brick on the logical volume
+// once unlocked, which its file system is on
+func DevicePath(vgName, lvName string) string {
+	return cryptutils.MapperPath(MapperName(vgName, lvName))
+}
+
+func lvPath(vgName, lvName string) string {
+	return "/dev/" + vgName + "/" + lvName
+}
+
+// Provision formats the logical volume with LUKS, encrypted with a new key,
+// and unlocks it
+func Provision(vgName, lvName string) error {
+	raw := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, raw); err != nil {
+		return err
+	}
+	// The key is kept as text, to be passed along by KMS commands
+	key := []byte(hex.EncodeToString(raw))
+
+	id := keyID(vgName, lvName)
+	if err := putKey(id, key); err != nil {
+		return err
+	}
+	if err := cryptutils.Format(lvPath(vgName, lvName), key); err != nil {
+		deleteKey(id)
+		return err
+	}
+	return cryptutils.Open(lvPath(vgName, lvName), MapperName(vgName, lvName), key)
+}
+
+// Unlock unlocks the encrypted brick on the logical volume, if not already
+func Unlock(vgName, lvName string) error {
+	name := MapperName(vgName, lvName)
+	if cryptutils.IsOpen(name) {
+		return nil
+	}
+	key, err := getKey(keyID(vgName, lvName))
+	if err != nil {
+		return err
+	}
+	return cryptutils.Open(lvPath(vgName, lvName), name, key)
+}
+
+// Lock locks the encrypted brick on the logical volume, if unlocked. Its
+// file system must be unmounted.
+func Lock(vgName, lvName string) error {
+	name := MapperName(vgName, lvName)
+	if !cryptutils.IsOpen(name) {
+		return nil
+	}
+	return cryptutils.Close(name)
+}
+
+// Resize resizes the unlocked brick to its extended logical volume
+func Resize(vgName, lvName string) error {
+	key, err := getKey(keyID(vgName, lvName))
+	if err != nil {
+		return err
+	}
+	return cryptutils.Resize(MapperName(vgName, lvName), key)
+}
+
+// Remove locks the encrypted brick on the logical volume and deletes its
+// key, before the logical volume is removed
+func Remove(vgName, lvName string) error {
+	if err := Lock(vgName, lvName); err != nil {
+		return err
+	}
+	return deleteKey(keyID(vgName, lvName))
+}
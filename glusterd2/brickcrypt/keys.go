@@ -0,0 +1,162 @@
+package brickcrypt
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"sync"
+
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/pkg/utils"
+
+	config "github.com/spf13/viper"
+)
+
+const (
+	// keysPrefix is where the keys of the bricks are saved in the store,
+	// sealed with the master key of the peer of the brick
+	keysPrefix = "brickkeys/"
+
+	masterKeyFile = "brick-master.key"
+	keySize       = 32
+)
+
+var (
+	errKeyNotFound = errors.New("encryption key of the brick not found")
+	errKeyCorrupt  = errors.New("encryption key of the brick could not be unsealed")
+
+	masterKeyMu sync.Mutex
+)
+
+// masterKey returns the master key of this peer, generating it on first use.
+// The master key never leaves the peer, the keys of its bricks in the store
+// are hence of no use without it.
+func masterKey() ([]byte, error) {
+	masterKeyMu.Lock()
+	defer masterKeyMu.Unlock()
+
+	keyFile := path.Join(config.GetString("localstatedir"), masterKeyFile)
+	key, err := ioutil.ReadFile(keyFile)
+	if err == nil {
+		if len(key) != keySize {
+			return nil, errors.New("invalid brick master key in " + keyFile)
+		}
+		return key, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	key = make([]byte, keySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(keyFile, key, 0600); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func newGCM() (cipher.AEAD, error) {
+	key, err := masterKey()
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal encrypts the key of the brick with the master key, binding it to the
+// ID of the key
+func seal(id string, key []byte) (string, error) {
+	gcm, err := newGCM()
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	sealed := gcm.Seal(nonce, nonce, key, []byte(id))
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// unseal decrypts the key of the brick sealed by seal
+func unseal(id string, value string) ([]byte, error) {
+	sealed, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return nil, errKeyCorrupt
+	}
+	gcm, err := newGCM()
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, errKeyCorrupt
+	}
+	nonce, sealed := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	key, err := gcm.Open(nil, nonce, sealed, []byte(id))
+	if err != nil {
+		return nil, errKeyCorrupt
+	}
+	return key, nil
+}
+
+// putKey saves the key of the brick, in the external KMS if one is
+// configured, and in the store otherwise
+func putKey(id string, key []byte) error {
+	if kms := config.GetString(kmsCommandOpt); kms != "" {
+		return utils.ExecuteCommandRunInput(key, kms, "put", id)
+	}
+
+	value, err := seal(id, key)
+	if err != nil {
+		return err
+	}
+	_, err = store.Put(context.TODO(), keysPrefix+id, value)
+	return err
+}
+
+// getKey returns the key of the brick
+func getKey(id string) ([]byte, error) {
+	if kms := config.GetString(kmsCommandOpt); kms != "" {
+		key, err := utils.ExecuteCommandOutput(kms, "get", id)
+		if err != nil {
+			return nil, err
+		}
+		if key = bytes.TrimSpace(key); len(key) == 0 {
+			return nil, errKeyNotFound
+		}
+		return key, nil
+	}
+
+	resp, err := store.Get(context.TODO(), keysPrefix+id)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Count != 1 {
+		return nil, errKeyNotFound
+	}
+	return unseal(id, string(resp.Kvs[0].Value))
+}
+
+// deleteKey deletes the key of the brick
+func deleteKey(id string) error {
+	if kms := config.GetString(kmsCommandOpt); kms != "" {
+		return utils.ExecuteCommandRun(kms, "delete", id)
+	}
+
+	_, err := store.Delete(context.TODO(), keysPrefix+id)
+	return err
+}
@@ -0,0 +1,35 @@
+package brickcrypt
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	config "github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSealUnseal(t *testing.T) {
+	dir, err := ioutil.TempDir("", "brickcrypt")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	config.Set("localstatedir", dir)
+	defer config.Set("localstatedir", "")
+
+	key := []byte("0123456789abcdef")
+	sealed, err := seal("peer/vg/lv", key)
+	require.NoError(t, err)
+	assert.NotContains(t, sealed, string(key))
+
+	unsealed, err := unseal("peer/vg/lv", sealed)
+	require.NoError(t, err)
+	assert.Equal(t, key, unsealed)
+
+	// The sealed key is bound to its ID
+	_, err = unseal("peer/vg/other", sealed)
+	assert.Equal(t, errKeyCorrupt, err)
+
+	_, err = unseal("peer/vg/lv", "not sealed")
+	assert.Equal(t, errKeyCorrupt, err)
+}
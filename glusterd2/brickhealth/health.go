@@ -0,0 +1,163 @@
+// Package brickhealth handles the failures of the posix health-check of the
+// bricks, so that a brick on a dying disk is taken out instead of hanging
+// the clients.
+package brickhealth
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/brickmux"
+	"github.com/gluster/glusterd2/glusterd2/daemon"
+	"github.com/gluster/glusterd2/glusterd2/events"
+	"github.com/gluster/glusterd2/glusterd2/options"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// EventHealthCheckFailed is broadcast when the posix health-check of
+	// a brick fails
+	EventHealthCheckFailed = "posix_health_check_failed"
+
+	healthCheckKillKey = "cluster.brick-health-check-kill"
+)
+
+// Keys of the dict sent by the bricks along with a health-check failure
+const (
+	keyBrickPath = "brick-path"
+	keyVolfileID = "volfile-id"
+	keyOp        = "op"
+	keyError     = "error"
+)
+
+// findLocalBrick returns the brick of this peer with the given path
+func findLocalBrick(path, volfileID string) (*brick.Brickinfo, error) {
+	volumes, err := volume.GetVolumes(context.TODO())
+	if err != nil {
+		return nil, err
+	}
+	if b := localBrick(volumes, path, volfileID); b != nil {
+		return b, nil
+	}
+	return nil, fmt.Errorf("no local brick with path %s", path)
+}
+
+// localBrick returns the brick of this peer with the given path among the
+// volumes, nil if there is none. The volfile ID, when sent by the brick,
+// tells apart the bricks of stopped volumes sharing the path.
+func localBrick(volumes []*volume.Volinfo, path, volfileID string) *brick.Brickinfo {
+	for _, v := range volumes {
+		for _, b := range v.GetLocalBricks() {
+			if b.Path != path {
+				continue
+			}
+			if volfileID != "" && b.VolfileID != volfileID {
+				continue
+			}
+			return &b
+		}
+	}
+	return nil
+}
+
+// healthCheckError returns the error of the health-check failure reported
+// by the brick, along with the operation which failed, if sent
+func healthCheckError(reqDict map[string]string) string {
+	if op := reqDict[keyOp]; op != "" {
+		return fmt.Sprintf("%s failed: %s", op, reqDict[keyError])
+	}
+	return reqDict[keyError]
+}
+
+// killEnabled tells if bricks failing their health-check are to be killed,
+// as set by the cluster option read using getClusterOption
+func killEnabled(getClusterOption func(string) (string, error)) bool {
+	value, err := getClusterOption(healthCheckKillKey)
+	if err != nil {
+		return true
+	}
+	kill, err := options.StringToBoolean(value)
+	if err != nil {
+		return true
+	}
+	return kill
+}
+
+// killBrick stops the brick, detaching it only from its process if other
+// bricks are multiplexed into it. The brick is not restarted along with
+// glusterd2.
+func killBrick(b brick.Brickinfo, logger log.FieldLogger) error {
+	bmuxEnabled, err := brickmux.Enabled()
+	if err != nil {
+		return err
+	}
+
+	if bmuxEnabled && !brickmux.IsLastBrickInProc(b) {
+		if err := brickmux.Demultiplex(b); err != nil {
+			return err
+		}
+		brickDaemon, err := brick.NewGlusterfsd(b)
+		if err != nil {
+			return err
+		}
+		return daemon.DelDaemon(brickDaemon)
+	}
+
+	return b.StopBrick(logger)
+}
+
+// HandleHealthCheckFailure marks the brick whose posix health-check failed
+// as degraded and broadcasts a critical event. Unless disabled by the
+// cluster.brick-health-check-kill cluster option, the brick is killed so
+// that replicas take over the clients.
+func HandleHealthCheckFailure(reqDict map[string]string) error {
+	path := reqDict[keyBrickPath]
+	if path == "" {
+		return fmt.Errorf("%s not set", keyBrickPath)
+	}
+
+	b, err := findLocalBrick(path, reqDict[keyVolfileID])
+	if err != nil {
+		return err
+	}
+	logger := log.WithFields(log.Fields{
+		"volume": b.VolumeName,
+		"brick":  b.String(),
+	})
+
+	// A brick may report its failure more than once before being killed
+	if d, err := brick.GetDegraded(*b); err == nil && d != nil {
+		logger.Debug("brick already marked degraded")
+		return nil
+	}
+
+	d := &brick.Degraded{
+		Brick: *b,
+		Error: healthCheckError(reqDict),
+		Since: time.Now(),
+	}
+	logger.WithField("error", d.Error).Error("posix health-check of the brick failed")
+
+	if killEnabled(options.GetClusterOption) {
+		if err := killBrick(*b, logger); err != nil {
+			logger.WithError(err).Error("failed to kill the brick")
+		} else {
+			d.Killed = true
+			logger.Warn("killed the brick failing its health-check")
+		}
+	}
+
+	if err := brick.MarkDegraded(d); err != nil {
+		logger.WithError(err).Error("failed to mark the brick degraded")
+	}
+
+	data := b.StringMap()
+	data["error"] = d.Error
+	data["killed"] = fmt.Sprintf("%t", d.Killed)
+	events.Broadcast(events.New(EventHealthCheckFailed, data, true))
+	return nil
+}
@@ -0,0 +1,84 @@
+package brickhealth
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalBrick(t *testing.T) {
+	other := uuid.NewRandom()
+	volumes := []*volume.Volinfo{
+		{Name: "gv0", Subvols: []volume.Subvol{{Bricks: []brick.Brickinfo{
+			{PeerID: other, Path: "/bricks/b1", VolfileID: "gv0.other.bricks-b1"},
+			{PeerID: gdctx.MyUUID, Path: "/bricks/b2", VolfileID: "gv0.me.bricks-b2"},
+		}}}},
+		// A stopped volume sharing the path of a brick
+		{Name: "gv1", Subvols: []volume.Subvol{{Bricks: []brick.Brickinfo{
+			{PeerID: gdctx.MyUUID, Path: "/bricks/b2", VolfileID: "gv1.me.bricks-b2"},
+		}}}},
+	}
+
+	tests := []struct {
+		path      string
+		volfileID string
+		volume    string
+	}{
+		{"/bricks/b2", "", "gv0"},
+		{"/bricks/b2", "gv1.me.bricks-b2", "gv1"},
+		{"/bricks/b2", "gv2.me.bricks-b2", ""},
+		// The bricks of the other peers are not found
+		{"/bricks/b1", "", ""},
+		{"/bricks/b3", "", ""},
+	}
+	for _, tc := range tests {
+		b := localBrick(volumes, tc.path, tc.volfileID)
+		if tc.volume == "" {
+			assert.Nil(t, b, tc.path+" "+tc.volfileID)
+			continue
+		}
+		require.NotNil(t, b, tc.path+" "+tc.volfileID)
+		assert.Equal(t, tc.path, b.Path)
+		assert.True(t, uuid.Equal(gdctx.MyUUID, b.PeerID))
+	}
+}
+
+func TestHealthCheckError(t *testing.T) {
+	assert.Equal(t, "write failed: Input/output error",
+		healthCheckError(map[string]string{keyOp: "write", keyError: "Input/output error"}))
+	assert.Equal(t, "Input/output error", healthCheckError(map[string]string{keyError: "Input/output error"}))
+}
+
+func TestKillEnabled(t *testing.T) {
+	tests := []struct {
+		value string
+		err   error
+		kill  bool
+	}{
+		{"on", nil, true},
+		{"off", nil, false},
+		{"false", nil, false},
+		// The bricks are killed unless the option is turned off
+		{"maybe", nil, true},
+		{"", errors.New("store down"), true},
+	}
+	for _, tc := range tests {
+		value, err := tc.value, tc.err
+		getClusterOption := func(key string) (string, error) {
+			assert.Equal(t, healthCheckKillKey, key)
+			return value, err
+		}
+		assert.Equal(t, tc.kill, killEnabled(getClusterOption), tc.value)
+	}
+}
+
+func TestHandleHealthCheckFailureNoPath(t *testing.T) {
+	assert.Error(t, HandleHealthCheckFailure(map[string]string{keyError: "Input/output error"}))
+}
@@ -0,0 +1,261 @@
+package brickhealth
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/brickmux"
+	"github.com/gluster/glusterd2/glusterd2/events"
+	"github.com/gluster/glusterd2/glusterd2/options"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// EventIOErrorBudgetExceeded is broadcast when a brick reports more
+	// I/O errors than its budget within the window of the budget
+	EventIOErrorBudgetExceeded = "brick_io_error_budget_exceeded"
+
+	ioErrorBudgetKey = "cluster.brick-io-error-budget"
+	ioErrorWindowKey = "cluster.brick-io-error-window"
+	ioErrorStopKey   = "cluster.brick-io-error-stop"
+
+	// keyCount is the key of the number of I/O errors sent by the bricks
+	// since their previous report
+	keyCount = "count"
+)
+
+// ioErrorReport is a number of I/O errors reported by a brick at a time
+type ioErrorReport struct {
+	at    time.Time
+	count int
+}
+
+// ioErrorWindow is the I/O errors reported by a brick within the window of
+// its budget
+type ioErrorWindow struct {
+	reports []ioErrorReport
+}
+
+// add records count errors reported at now, forgets the errors reported
+// before the window, and returns the number of errors left
+func (w *ioErrorWindow) add(now time.Time, count int, window time.Duration) int {
+	w.reports = append(w.reports, ioErrorReport{at: now, count: count})
+
+	start := now.Add(-window)
+	i := 0
+	for i < len(w.reports) && !w.reports[i].at.After(start) {
+		i++
+	}
+	w.reports = w.reports[i:]
+
+	total := 0
+	for _, r := range w.reports {
+		total += r.count
+	}
+	return total
+}
+
+// ioErrors are the I/O errors reported by the local bricks, by brick ID
+var ioErrors = struct {
+	sync.Mutex
+	windows map[string]*ioErrorWindow
+}{
+	windows: make(map[string]*ioErrorWindow),
+}
+
+// recordIOErrors records count errors reported by the brick, and returns the
+// number of errors it reported within the window
+func recordIOErrors(b brick.Brickinfo, count int, window time.Duration) int {
+	ioErrors.Lock()
+	defer ioErrors.Unlock()
+
+	w, ok := ioErrors.windows[b.ID.String()]
+	if !ok {
+		w = &ioErrorWindow{}
+		ioErrors.windows[b.ID.String()] = w
+	}
+	return w.add(time.Now(), count, window)
+}
+
+// resetIOErrors forgets the I/O errors reported by the brick
+func resetIOErrors(b brick.Brickinfo) {
+	ioErrors.Lock()
+	defer ioErrors.Unlock()
+	delete(ioErrors.windows, b.ID.String())
+}
+
+// clusterOptionInt returns the integer value of a cluster option, its default
+// if it can not be read
+func clusterOptionInt(key string) int {
+	value, err := options.GetClusterOption(key)
+	if err == nil {
+		if n, err := strconv.Atoi(value); err == nil {
+			return n
+		}
+	}
+	n, _ := strconv.Atoi(options.ClusterOptMap[key].DefaultValue)
+	return n
+}
+
+// stopEnabled tells if bricks exceeding their I/O error budget are to be
+// stopped
+func stopEnabled() bool {
+	value, err := options.GetClusterOption(ioErrorStopKey)
+	if err != nil {
+		return false
+	}
+	stop, err := options.StringToBoolean(value)
+	if err != nil {
+		return false
+	}
+	return stop
+}
+
+// HandleIOErrors counts the I/O errors reported by a brick. A brick
+// reporting more errors than cluster.brick-io-error-budget within
+// cluster.brick-io-error-window seconds is marked degraded and a critical
+// event is broadcast. If cluster.brick-io-error-stop is on, the brick is
+// stopped too, so that the clients read from the other bricks of its replica
+// or disperse set.
+func HandleIOErrors(reqDict map[string]string) error {
+	path := reqDict[keyBrickPath]
+	if path == "" {
+		return fmt.Errorf("%s not set", keyBrickPath)
+	}
+	count := 1
+	if v := reqDict[keyCount]; v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return fmt.Errorf("invalid %s: %s", keyCount, v)
+		}
+		count = n
+	}
+
+	budget := clusterOptionInt(ioErrorBudgetKey)
+	if budget <= 0 {
+		// No budget, the errors are not counted
+		return nil
+	}
+	window := time.Duration(clusterOptionInt(ioErrorWindowKey)) * time.Second
+
+	b, err := findLocalBrick(path, reqDict[keyVolfileID])
+	if err != nil {
+		return err
+	}
+	logger := log.WithFields(log.Fields{
+		"volume": b.VolumeName,
+		"brick":  b.String(),
+	})
+
+	total := recordIOErrors(*b, count, window)
+	if total <= budget {
+		logger.WithField("io-errors", total).Debug("brick reported I/O errors")
+		return nil
+	}
+
+	// The brick keeps reporting its errors until it is stopped
+	if d, err := brick.GetDegraded(*b); err == nil && d != nil {
+		return nil
+	}
+
+	d := &brick.Degraded{
+		Brick:    *b,
+		Error:    fmt.Sprintf("%d I/O errors in %s, budget is %d", total, window, budget),
+		Since:    time.Now(),
+		IOErrors: total,
+	}
+	if op := reqDict[keyOp]; op != "" {
+		d.Error = fmt.Sprintf("%s, last %s failed: %s", d.Error, op, reqDict[keyError])
+	}
+	logger.WithField("error", d.Error).Error("brick exceeded its I/O error budget")
+
+	if stopEnabled() {
+		if err := killBrick(*b, logger); err != nil {
+			logger.WithError(err).Error("failed to stop the brick")
+		} else {
+			d.Killed = true
+			logger.Warn("stopped the brick exceeding its I/O error budget")
+		}
+	}
+
+	if err := brick.MarkDegraded(d); err != nil {
+		logger.WithError(err).Error("failed to mark the brick degraded")
+	}
+
+	data := b.StringMap()
+	data["error"] = d.Error
+	data["io-errors"] = strconv.Itoa(total)
+	data["killed"] = fmt.Sprintf("%t", d.Killed)
+	events.Broadcast(events.New(EventIOErrorBudgetExceeded, data, true))
+	return nil
+}
+
+// Enable clears the degraded mark of the local brick and forgets its I/O
+// errors. The brick is started again if it was stopped and its volume is
+// started.
+func Enable(b brick.Brickinfo, logger log.FieldLogger) error {
+	resetIOErrors(b)
+
+	d, err := brick.GetDegraded(b)
+	if err != nil {
+		return err
+	}
+	if d == nil {
+		return nil
+	}
+
+	if d.Killed {
+		volinfo, err := volume.GetVolume(b.VolumeName)
+		if err != nil {
+			return err
+		}
+		if volinfo.State == volume.VolStarted {
+			if err := startBrick(b, volinfo, logger); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := brick.ClearDegraded(b); err != nil {
+		return err
+	}
+	logger.WithField("brick", b.String()).Info("re-enabled degraded brick")
+	return nil
+}
+
+// startBrick starts the brick, multiplexing it into a running brick process
+// if brick multiplexing is enabled
+func startBrick(b brick.Brickinfo, volinfo *volume.Volinfo, logger log.FieldLogger) error {
+	bmuxEnabled, err := brickmux.Enabled()
+	if err != nil {
+		return err
+	}
+
+	if bmuxEnabled {
+		if err := brick.VerifyVolumeID(&b); err != nil {
+			return err
+		}
+		volumes, err := volume.GetVolumes(context.TODO())
+		if err != nil {
+			return err
+		}
+		err = brickmux.Multiplex(b, volinfo, volumes, logger)
+		switch err {
+		case nil:
+			return nil
+		case brickmux.ErrNoCompat:
+			// fallback to starting a separate process
+			logger.WithField("brick", b.String()).Warn(err)
+		default:
+			return err
+		}
+	}
+
+	return b.StartBrick(logger)
+}
@@ -0,0 +1,22 @@
+package brickhealth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIOErrorWindow(t *testing.T) {
+	var w ioErrorWindow
+	now := time.Now()
+	window := time.Minute
+
+	assert.Equal(t, 3, w.add(now, 3, window))
+	assert.Equal(t, 5, w.add(now.Add(30*time.Second), 2, window))
+	// The errors reported a minute before are forgotten
+	assert.Equal(t, 3, w.add(now.Add(time.Minute), 1, window))
+	assert.Len(t, w.reports, 2)
+	assert.Equal(t, 0, w.add(now.Add(5*time.Minute), 0, window))
+	assert.Len(t, w.reports, 1)
+}
@@ -0,0 +1,34 @@
+package brickhealth
+
+import (
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+
+	"github.com/pborman/uuid"
+)
+
+const brickTxnKey = "brickhealth.brick"
+
+// EnableStep returns a step having the peer of the brick re-enable it
+func EnableStep(c transaction.TxnCtx, b brick.Brickinfo) (*transaction.Step, error) {
+	if err := c.Set(brickTxnKey, b); err != nil {
+		return nil, err
+	}
+	return &transaction.Step{
+		DoFunc: "brickhealth.Enable",
+		Nodes:  []uuid.UUID{b.PeerID},
+	}, nil
+}
+
+func txnEnable(c transaction.TxnCtx) error {
+	var b brick.Brickinfo
+	if err := c.Get(brickTxnKey, &b); err != nil {
+		return err
+	}
+	return Enable(b, c.Logger())
+}
+
+// RegisterStepFuncs registers the step function re-enabling a degraded brick
+func RegisterStepFuncs() {
+	transaction.RegisterStepFunc(txnEnable, "brickhealth.Enable")
+}
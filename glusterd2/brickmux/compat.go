@@ -94,8 +94,9 @@ func findCompatibleBrick(b *brick.Brickinfo, brickVolinfo *volume.Volinfo, volum
 				// if volume isn't started, we can't multiplex.
 				continue
 			}
-			// compare volume options of volumes
-			if reflect.DeepEqual(v.Options, brickVolinfo.Options) {
+			// compare volume options of volumes, and the user their
+			// bricks are run as
+			if v.BrickUser == brickVolinfo.BrickUser && reflect.DeepEqual(v.Options, brickVolinfo.Options) {
 				targetVolume = v
 				if maxBricksPerProcess > 0 {
 					targetBrick = validateBmuxTarget(b, targetVolume, maxBricksPerProcess)
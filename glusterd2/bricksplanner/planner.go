@@ -6,6 +6,7 @@ import (
 	"path"
 	"strconv"
 
+	"github.com/gluster/glusterd2/glusterd2/brickcrypt"
 	"github.com/gluster/glusterd2/glusterd2/volume"
 	"github.com/gluster/glusterd2/pkg/api"
 	"github.com/gluster/glusterd2/pkg/lvmutils"
@@ -19,6 +20,15 @@ const (
 	defaultMaxLoopBrickSize = 100 * gutils.GiB
 )
 
+var (
+	// ErrNoDevices is returned when no devices are available for the
+	// bricks
+	ErrNoDevices = errors.New("no devices registered or available for allocating bricks")
+	// ErrNoSpace is returned when the devices available do not have the
+	// space for all the bricks
+	ErrNoSpace = errors.New("no space available or all the devices are not registered")
+)
+
 func handleReplicaSubvolReq(req *api.VolCreateReq) error {
 	if req.ReplicaCount < 2 {
 		return nil
@@ -173,6 +183,7 @@ func getBricksLayout(req *api.VolCreateReq) ([]api.SubvolReq, error) {
 				TotalSize:      tpsize + tpmsize,
 				FsType:         "xfs",
 				MntOpts:        mntopts,
+				Encrypted:      req.Encrypted,
 			})
 		}
 
@@ -190,18 +201,26 @@ func getBricksLayout(req *api.VolCreateReq) ([]api.SubvolReq, error) {
 
 // PlanBricks creates the brick layout with chosen device and size information
 func PlanBricks(req *api.VolCreateReq) error {
+	_, err := PreviewBricks(req)
+	return err
+}
+
+// PreviewBricks creates the brick layout as PlanBricks does, and returns the
+// volume groups available for the bricks with the space left on them once
+// the bricks are created. Nothing is provisioned.
+func PreviewBricks(req *api.VolCreateReq) ([]Vg, error) {
 	availableVgs, err := GetAvailableVgs(req)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if len(availableVgs) == 0 {
-		return errors.New("no devices registered or available for allocating bricks")
+		return nil, ErrNoDevices
 	}
 
 	subvols, err := getBricksLayout(req)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	zones := make(map[string]struct{})
@@ -219,7 +238,8 @@ func PlanBricks(req *api.VolCreateReq) error {
 		// with device with expected space available.
 		numBricksAllocated := 0
 		for bidx, b := range sv.Bricks {
-			for _, vg := range availableVgs {
+			for vgIdx := range availableVgs {
+				vg := &availableVgs[vgIdx]
 				_, zoneUsed := zones[vg.Zone]
 				if vg.AvailableSize >= b.TotalSize && !zoneUsed && !vg.Used {
 					subvols[idx].Bricks[bidx].PeerID = vg.PeerID
@@ -228,6 +248,8 @@ func PlanBricks(req *api.VolCreateReq) error {
 					subvols[idx].Bricks[bidx].DevicePath = "/dev/" + vg.Name + "/" + b.LvName
 					if req.ProvisionerType == api.ProvisionerTypeLoop {
 						subvols[idx].Bricks[bidx].DevicePath = vg.Device + "/" + b.TpName + "/" + b.LvName + ".img"
+					} else if b.Encrypted {
+						subvols[idx].Bricks[bidx].DevicePath = brickcrypt.DevicePath(vg.Name, b.LvName)
 					}
 
 					zones[vg.Zone] = struct{}{}
@@ -249,7 +271,8 @@ func PlanBricks(req *api.VolCreateReq) error {
 		// but enough space is available in the devices
 		for bidx := numBricksAllocated; bidx < len(sv.Bricks); bidx++ {
 			b := sv.Bricks[bidx]
-			for _, vg := range availableVgs {
+			for vgIdx := range availableVgs {
+				vg := &availableVgs[vgIdx]
 				_, zoneUsed := zones[vg.Zone]
 				if vg.AvailableSize >= b.TotalSize && !zoneUsed {
 					subvols[idx].Bricks[bidx].PeerID = vg.PeerID
@@ -258,6 +281,8 @@ func PlanBricks(req *api.VolCreateReq) error {
 					subvols[idx].Bricks[bidx].DevicePath = "/dev/" + vg.Name + "/" + b.LvName
 					if req.ProvisionerType == api.ProvisionerTypeLoop {
 						subvols[idx].Bricks[bidx].DevicePath = vg.Device + "/" + b.TpName + "/" + b.LvName + ".img"
+					} else if b.Encrypted {
+						subvols[idx].Bricks[bidx].DevicePath = brickcrypt.DevicePath(vg.Name, b.LvName)
 					}
 
 					zones[vg.Zone] = struct{}{}
@@ -271,10 +296,10 @@ func PlanBricks(req *api.VolCreateReq) error {
 
 		// If the devices are not available as it is required for Volume.
 		if len(sv.Bricks) != numBricksAllocated {
-			return errors.New("no space available or all the devices are not registered")
+			return nil, ErrNoSpace
 		}
 	}
 
 	req.Subvols = subvols
-	return nil
+	return availableVgs, nil
 }
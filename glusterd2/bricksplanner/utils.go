@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/brickcrypt"
 	"github.com/gluster/glusterd2/glusterd2/peer"
 	"github.com/gluster/glusterd2/glusterd2/store"
 	"github.com/gluster/glusterd2/glusterd2/volume"
@@ -146,6 +147,9 @@ func GetNewBrick(availableVgs []Vg, brickInfo brick.Brickstatus, vol *volume.Vol
 			}
 			if vol.ProvisionerType == api.ProvisionerTypeLoop {
 				newBrick.DevicePath = vg.Device + "/" + newBrick.TpName + "/" + newBrick.LvName + ".img"
+			} else if brickInfo.Info.Encrypted {
+				newBrick.Encrypted = true
+				newBrick.DevicePath = brickcrypt.DevicePath(vg.Name, lvName)
 			}
 			vg.Used = true
 			break
@@ -0,0 +1,355 @@
+// Package ca issues the certificates of the peers used for TLS, with a CA
+// built into the cluster or an external one, and renews them before they
+// expire.
+package ca
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/datatls"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/options"
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+	"github.com/gluster/glusterd2/pkg/utils"
+
+	"github.com/coreos/etcd/clientv3"
+	config "github.com/spf13/viper"
+)
+
+// Modes of the CA, as set by the cluster.ca option
+const (
+	ModeOff      = "off"
+	ModeBuiltin  = "builtin"
+	ModeExternal = "external"
+)
+
+const (
+	modeKey     = "cluster.ca"
+	validityKey = "cluster.ca-cert-validity-days"
+
+	// caCertKey and caKeyKey are where the certificate and private key
+	// of the built-in CA are saved in the store
+	caCertKey = "ca/cert"
+	caKeyKey  = "ca/key"
+
+	caValidity = 10 * 365 * 24 * time.Hour
+)
+
+var errNoCA = errors.New("the built-in CA is not created")
+
+func validateMode(option, value string) error {
+	switch value {
+	case ModeOff, ModeBuiltin, ModeExternal:
+		return nil
+	}
+	return gderrors.ErrInvalidCAMode
+}
+
+func validateValidity(option, value string) error {
+	days, err := strconv.Atoi(value)
+	if err != nil || days < 1 {
+		return gderrors.ErrInvalidIntValue
+	}
+	return nil
+}
+
+func init() {
+	options.RegisterClusterOpValidationFunc(modeKey, validateMode)
+	options.RegisterClusterOpValidationFunc(validityKey, validateValidity)
+}
+
+// Mode returns the mode of the CA of the cluster
+func Mode() (string, error) {
+	return options.GetClusterOption(modeKey)
+}
+
+func validity() time.Duration {
+	days := 90
+	if value, err := options.GetClusterOption(validityKey); err == nil {
+		if d, err := strconv.Atoi(value); err == nil && d > 0 {
+			days = d
+		}
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+func serialNumber() (*big.Int, error) {
+	return rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+}
+
+func encodeCert(der []byte) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func encodeKey(key *ecdsa.PrivateKey) ([]byte, error) {
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), nil
+}
+
+// newCA returns the PEM encoded certificate and private key of a new CA
+func newCA(now time.Time) ([]byte, []byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	serial, err := serialNumber()
+	if err != nil {
+		return nil, nil, err
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			Organization: []string{"Gluster"},
+			CommonName:   "glusterd2 CA " + gdctx.MyClusterID.String(),
+		},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(caValidity),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyPEM, err := encodeKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return encodeCert(der), keyPEM, nil
+}
+
+// ensureCA creates the built-in CA, unless created already, and adds it to
+// the CA bundle distributed to the peers
+func ensureCA() error {
+	cert, _, err := loadCA()
+	if err == errNoCA {
+		var certPEM, keyPEM []byte
+		if certPEM, keyPEM, err = newCA(time.Now()); err != nil {
+			return err
+		}
+
+		// Only one of the peers creating the CA at once succeeds
+		_, err = store.Txn(context.TODO()).
+			If(clientv3.Compare(clientv3.CreateRevision(caCertKey), "=", 0)).
+			Then(clientv3.OpPut(caCertKey, string(certPEM)), clientv3.OpPut(caKeyKey, string(keyPEM))).
+			Commit()
+		if err != nil {
+			return err
+		}
+		cert, _, err = loadCA()
+	}
+	if err != nil {
+		return err
+	}
+	return datatls.AddCA(string(encodeCert(cert.Raw)))
+}
+
+// loadCA returns the certificate and private key of the built-in CA
+func loadCA() (*x509.Certificate, *ecdsa.PrivateKey, error) {
+	resp, err := store.Get(context.TODO(), caCertKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	if resp.Count != 1 {
+		return nil, nil, errNoCA
+	}
+	block, _ := pem.Decode(resp.Kvs[0].Value)
+	if block == nil {
+		return nil, nil, errors.New("invalid certificate of the built-in CA")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp, err = store.Get(context.TODO(), caKeyKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	if resp.Count != 1 {
+		return nil, nil, errNoCA
+	}
+	block, _ = pem.Decode(resp.Kvs[0].Value)
+	if block == nil {
+		return nil, nil, errors.New("invalid private key of the built-in CA")
+	}
+	key, err := x509.ParseECPrivateKey(block.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cert, key, nil
+}
+
+// CACert returns the certificate of the built-in CA, with its PEM encoding
+func CACert() (*x509.Certificate, string, error) {
+	cert, _, err := loadCA()
+	if err != nil {
+		return nil, "", err
+	}
+	return cert, string(encodeCert(cert.Raw)), nil
+}
+
+// certTemplate returns the template of the certificate of this peer, named
+// after its ID and valid for its addresses
+func certTemplate(now time.Time) (*x509.Certificate, error) {
+	serial, err := serialNumber()
+	if err != nil {
+		return nil, err
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			Organization: []string{"Gluster"},
+			CommonName:   gdctx.MyUUID.String(),
+		},
+		NotBefore:   now.Add(-time.Hour),
+		NotAfter:    now.Add(validity()),
+		KeyUsage:    x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		DNSNames:    []string{gdctx.HostName},
+	}
+
+	if p, err := peer.GetPeer(gdctx.MyUUID.String()); err == nil {
+		for _, addr := range append(p.PeerAddresses, p.ClientAddresses...) {
+			host, _, err := net.SplitHostPort(addr)
+			if err != nil {
+				host = addr
+			}
+			if ip := net.ParseIP(host); ip != nil {
+				tmpl.IPAddresses = append(tmpl.IPAddresses, ip)
+			} else if host != "" && host != gdctx.HostName {
+				tmpl.DNSNames = append(tmpl.DNSNames, host)
+			}
+		}
+	}
+	return tmpl, nil
+}
+
+// signBuiltin issues the certificate of this peer with the built-in CA
+func signBuiltin(key *ecdsa.PrivateKey, now time.Time) ([]byte, error) {
+	caCert, caKey, err := loadCA()
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := certTemplate(now)
+	if err != nil {
+		return nil, err
+	}
+	return signCert(tmpl, caCert, caKey, &key.PublicKey)
+}
+
+// signCert returns the PEM encoded certificate of the template signed by
+// the CA, expiring no later than the CA
+func signCert(tmpl, caCert *x509.Certificate, caKey *ecdsa.PrivateKey, pub *ecdsa.PublicKey) ([]byte, error) {
+	if tmpl.NotAfter.After(caCert.NotAfter) {
+		tmpl.NotAfter = caCert.NotAfter
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, caCert, pub, caKey)
+	if err != nil {
+		return nil, err
+	}
+	return encodeCert(der), nil
+}
+
+// signExternal has the external CA issue the certificate of this peer, by
+// passing a CSR to the sign command
+func signExternal(key *ecdsa.PrivateKey, now time.Time) ([]byte, error) {
+	command := config.GetString(signCommandOpt)
+	if command == "" {
+		return nil, gderrors.ErrCASignCommandNotSet
+	}
+	tmpl, err := certTemplate(now)
+	if err != nil {
+		return nil, err
+	}
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject:     tmpl.Subject,
+		DNSNames:    tmpl.DNSNames,
+		IPAddresses: tmpl.IPAddresses,
+	}, key)
+	if err != nil {
+		return nil, err
+	}
+	csrPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csr})
+
+	out, err := utils.ExecuteCommandOutputInput(csrPEM, command)
+	if err != nil {
+		return nil, err
+	}
+	if block, _ := pem.Decode(out); block == nil || block.Type != "CERTIFICATE" {
+		return nil, errors.New("no certificate output by the CA sign command")
+	}
+	return bytes.TrimSpace(out), nil
+}
+
+// writeFile writes the file atomically, creating its directory if needed
+func writeFile(file string, data []byte, perm os.FileMode) error {
+	if err := os.MkdirAll(path.Dir(file), 0755); err != nil {
+		return err
+	}
+	tmp := file + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, perm); err != nil {
+		return err
+	}
+	return os.Rename(tmp, file)
+}
+
+// Issue issues a new certificate for this peer, with a new private key,
+// installing them where the bricks and clients read them from. The private
+// key never leaves the peer.
+func Issue(mode string) error {
+	now := time.Now()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+
+	var certPEM []byte
+	switch mode {
+	case ModeBuiltin:
+		if err := ensureCA(); err != nil {
+			return err
+		}
+		certPEM, err = signBuiltin(key, now)
+	case ModeExternal:
+		certPEM, err = signExternal(key, now)
+	default:
+		return gderrors.ErrCADisabled
+	}
+	if err != nil {
+		return err
+	}
+
+	keyPEM, err := encodeKey(key)
+	if err != nil {
+		return err
+	}
+	if err := datatls.SyncCA(); err != nil {
+		return err
+	}
+	if err := writeFile(datatls.KeyFile, keyPEM, 0600); err != nil {
+		return err
+	}
+	return writeFile(datatls.CertFile, certPEM, 0644)
+}
@@ -0,0 +1,68 @@
+package ca
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignCert(t *testing.T) {
+	now := time.Now()
+
+	certPEM, keyPEM, err := newCA(now)
+	require.NoError(t, err)
+
+	block, _ := pem.Decode(certPEM)
+	require.NotNil(t, block)
+	caCert, err := x509.ParseCertificate(block.Bytes)
+	require.NoError(t, err)
+	assert.True(t, caCert.IsCA)
+
+	block, _ = pem.Decode(keyPEM)
+	require.NotNil(t, block)
+	caKey, err := x509.ParseECPrivateKey(block.Bytes)
+	require.NoError(t, err)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	// The certificate must not outlive the CA
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "peer"},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     caCert.NotAfter.Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		DNSNames:     []string{"peer.example.com"},
+	}
+	signed, err := signCert(tmpl, caCert, caKey, &key.PublicKey)
+	require.NoError(t, err)
+
+	block, _ = pem.Decode(signed)
+	require.NotNil(t, block)
+	cert, err := x509.ParseCertificate(block.Bytes)
+	require.NoError(t, err)
+	assert.Equal(t, "peer", cert.Subject.CommonName)
+	assert.False(t, cert.NotAfter.After(caCert.NotAfter))
+
+	roots := x509.NewCertPool()
+	roots.AddCert(caCert)
+	for _, usage := range []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth} {
+		_, err = cert.Verify(x509.VerifyOptions{
+			Roots:       roots,
+			DNSName:     "peer.example.com",
+			CurrentTime: now,
+			KeyUsages:   []x509.ExtKeyUsage{usage},
+		})
+		assert.NoError(t, err)
+	}
+}
@@ -0,0 +1,135 @@
+package ca
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"sync"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/datatls"
+	"github.com/gluster/glusterd2/glusterd2/events"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/transactionv2"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	log "github.com/sirupsen/logrus"
+	flag "github.com/spf13/pflag"
+	config "github.com/spf13/viper"
+)
+
+const (
+	// EventCertRenewed is broadcast when this peer gets a new certificate
+	EventCertRenewed = "peer_cert_renewed"
+
+	signCommandOpt   = "ca-sign-command"
+	checkIntervalOpt = "ca-check-interval"
+)
+
+var (
+	stopChan chan struct{}
+	stopOnce sync.Once
+
+	// issueLock serializes issuing certificates on this peer
+	issueLock sync.Mutex
+)
+
+// InitFlags intializes the command line options for the CA
+func InitFlags() {
+	flag.String(signCommandOpt, "", "Command signing the certificates of this peer with the external CA. It is given a CSR on stdin and must print the certificate on stdout.")
+	flag.Duration(checkIntervalOpt, time.Hour, "Interval at which the certificate of this peer is checked, to be renewed before it expires. Set to 0 to disable.")
+}
+
+// Start starts checking the certificate of this peer periodically
+func Start() {
+	interval := config.GetDuration(checkIntervalOpt)
+	if interval <= 0 {
+		log.Info("certificate renewal disabled")
+		return
+	}
+
+	stopChan = make(chan struct{})
+	go transaction.UntilStop(check, interval, stopChan)
+}
+
+// Stop stops checking the certificate of this peer
+func Stop() {
+	if stopChan == nil {
+		return
+	}
+	stopOnce.Do(func() {
+		close(stopChan)
+	})
+}
+
+// needsRenewal tells if the certificate of this peer must be renewed, that
+// is if it is not valid, or less than a third of its validity is left
+func needsRenewal(now time.Time) bool {
+	if status := datatls.Check(now); !status.Valid {
+		return true
+	}
+
+	data, err := ioutil.ReadFile(datatls.CertFile)
+	if err != nil {
+		return true
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return true
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return true
+	}
+	return cert.NotAfter.Sub(now) < cert.NotAfter.Sub(cert.NotBefore)/3
+}
+
+// Renew issues a new certificate for this peer, if the cluster has a CA.
+// Unless forced, the current certificate is kept if not about to expire.
+func Renew(force bool) error {
+	issueLock.Lock()
+	defer issueLock.Unlock()
+
+	mode, err := Mode()
+	if err != nil {
+		return err
+	}
+	if mode == ModeOff {
+		if force {
+			return gderrors.ErrCADisabled
+		}
+		return nil
+	}
+
+	if err := datatls.SyncCA(); err != nil {
+		return err
+	}
+	if !force && !needsRenewal(time.Now()) {
+		return nil
+	}
+
+	if err := Issue(mode); err != nil {
+		return err
+	}
+
+	status := datatls.Check(time.Now())
+	log.WithFields(log.Fields{
+		"subject":  status.Subject,
+		"issuer":   status.Issuer,
+		"notafter": status.NotAfter,
+	}).Info("issued a new certificate for this peer")
+
+	data := map[string]string{
+		"peer.id":  gdctx.MyUUID.String(),
+		"subject":  status.Subject,
+		"notafter": status.NotAfter.Format(time.RFC3339),
+	}
+	events.Broadcast(events.New(EventCertRenewed, data, false))
+	return nil
+}
+
+func check() {
+	if err := Renew(false); err != nil {
+		log.WithError(err).Error("failed to renew the certificate of this peer")
+	}
+}
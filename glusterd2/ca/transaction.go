@@ -0,0 +1,38 @@
+package ca
+
+import (
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+
+	"github.com/pborman/uuid"
+)
+
+const forceTxnKey = "ca.force"
+
+// RenewStep returns a step having each of the given nodes renew its
+// certificate. Unless forced, only certificates about to expire are renewed.
+func RenewStep(c transaction.TxnCtx, force bool, nodes []uuid.UUID) (*transaction.Step, error) {
+	if err := c.Set(forceTxnKey, force); err != nil {
+		return nil, err
+	}
+	return &transaction.Step{
+		DoFunc: "ca.Renew",
+		Nodes:  nodes,
+	}, nil
+}
+
+func txnRenew(c transaction.TxnCtx) error {
+	var force bool
+	if err := c.Get(forceTxnKey, &force); err != nil {
+		return err
+	}
+	if err := Renew(force); err != nil {
+		c.Logger().WithError(err).Error("failed to renew the certificate of this peer")
+		return err
+	}
+	return nil
+}
+
+// RegisterStepFuncs registers the step function renewing the certificates
+func RegisterStepFuncs() {
+	transaction.RegisterStepFunc(txnRenew, "ca.Renew")
+}
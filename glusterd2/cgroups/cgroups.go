@@ -0,0 +1,350 @@
+// Package cgroups runs the brick processes and the self-heal daemon of this
+// peer in cgroups (v2), limiting their CPU and memory as set by the cluster
+// options, and reports their resource usage.
+package cgroups
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gluster/glusterd2/glusterd2/options"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/size"
+
+	log "github.com/sirupsen/logrus"
+	flag "github.com/spf13/pflag"
+	config "github.com/spf13/viper"
+)
+
+const (
+	rootOpt = "cgroup-root"
+
+	brickCPUKey    = "cluster.brick-cpu-limit"
+	brickMemoryKey = "cluster.brick-memory-limit"
+	shdCPUKey      = "cluster.shd-cpu-limit"
+	shdMemoryKey   = "cluster.shd-memory-limit"
+
+	// cpuPeriod is the period, in microseconds, over which the CPU limit
+	// is enforced
+	cpuPeriod = 100000
+
+	controllers = "+cpu +memory"
+)
+
+// kind is a kind of daemon run in cgroups, with the cluster options limiting
+// its resources
+type kind struct {
+	dir       string
+	cpuKey    string
+	memoryKey string
+}
+
+// kinds are the kinds of daemons run in cgroups, by daemon name
+var kinds = map[string]kind{
+	"glusterfsd": {"bricks", brickCPUKey, brickMemoryKey},
+	"glustershd": {"shd", shdCPUKey, shdMemoryKey},
+}
+
+// Limits are the resource limits of a cgroup. A limit of 0 is no limit.
+type Limits struct {
+	// CPUPercent is the CPU time, in percent of one CPU
+	CPUPercent int
+	// MemoryBytes is the memory, in bytes
+	MemoryBytes uint64
+}
+
+// InitFlags intializes the command line options for the cgroups
+func InitFlags() {
+	flag.String(rootOpt, "", "Directory of the cgroup (v2) under which the brick processes and the self-heal daemon are run, with the limits of the cluster options. Leave empty to not use cgroups.")
+}
+
+func validateCPU(option, value string) error {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return errors.New("must be a percentage of one CPU, 0 for no limit")
+	}
+	return nil
+}
+
+func validateMemory(option, value string) error {
+	if value == "0" {
+		return nil
+	}
+	if _, err := size.Parse(value); err != nil {
+		return errors.New("must be a size like 4GiB, 0 for no limit")
+	}
+	return nil
+}
+
+func init() {
+	for _, k := range kinds {
+		options.RegisterClusterOpValidationFunc(k.cpuKey, validateCPU)
+		options.RegisterClusterOpValidationFunc(k.memoryKey, validateMemory)
+	}
+}
+
+// IsLimitOption tells if the cluster option is a resource limit
+func IsLimitOption(key string) bool {
+	for _, k := range kinds {
+		if key == k.cpuKey || key == k.memoryKey {
+			return true
+		}
+	}
+	return false
+}
+
+// limits returns the limits of the kind of daemons set by the cluster
+// options. Options which are not valid are no limit.
+func (k kind) limits() Limits {
+	var l Limits
+	if value, err := options.GetClusterOption(k.cpuKey); err == nil {
+		if n, err := strconv.Atoi(value); err == nil && n > 0 {
+			l.CPUPercent = n
+		}
+	}
+	if value, err := options.GetClusterOption(k.memoryKey); err == nil && value != "0" {
+		if s, err := size.Parse(value); err == nil && s > 0 {
+			l.MemoryBytes = uint64(s.Bytes())
+		}
+	}
+	return l
+}
+
+// enabled tells if the daemons are to be run in cgroups
+func enabled() bool {
+	return config.GetString(rootOpt) != ""
+}
+
+// cgroupName returns the name of the cgroup of the daemon with the ID
+func cgroupName(id string) string {
+	return strings.Trim(strings.Replace(id, "/", "-", -1), "-")
+}
+
+// enableControllers creates the directory of a cgroup, with the cpu and
+// memory controllers enabled for its children
+func enableControllers(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(dir, "cgroup.subtree_control"), []byte(controllers), 0644)
+}
+
+// setLimits writes the limits to the cgroup
+func setLimits(dir string, l Limits) error {
+	cpu := "max " + strconv.Itoa(cpuPeriod)
+	if l.CPUPercent > 0 {
+		cpu = strconv.Itoa(l.CPUPercent*cpuPeriod/100) + " " + strconv.Itoa(cpuPeriod)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "cpu.max"), []byte(cpu), 0644); err != nil {
+		return err
+	}
+
+	memory := "max"
+	if l.MemoryBytes > 0 {
+		memory = strconv.FormatUint(l.MemoryBytes, 10)
+	}
+	return ioutil.WriteFile(filepath.Join(dir, "memory.max"), []byte(memory), 0644)
+}
+
+// Place moves the process of the daemon with the name and the ID into its
+// cgroup, with the limits of its kind. Daemons of other kinds are left
+// where they are.
+func Place(name, id string, pid int) error {
+	k, ok := kinds[name]
+	if !ok || !enabled() {
+		return nil
+	}
+
+	root := config.GetString(rootOpt)
+	if err := enableControllers(root); err != nil {
+		return err
+	}
+	kindDir := filepath.Join(root, k.dir)
+	if err := enableControllers(kindDir); err != nil {
+		return err
+	}
+	prune(kindDir)
+
+	dir := filepath.Join(kindDir, cgroupName(id))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	if err := setLimits(dir, k.limits()); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(dir, "cgroup.procs"), []byte(strconv.Itoa(pid)), 0644)
+}
+
+// Remove removes the cgroup of the daemon with the name and the ID. It
+// fails while a process is still in the cgroup.
+func Remove(name, id string) error {
+	k, ok := kinds[name]
+	if !ok || !enabled() {
+		return nil
+	}
+	err := os.Remove(filepath.Join(config.GetString(rootOpt), k.dir, cgroupName(id)))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// prune removes the cgroups left without a process, by daemons which were
+// stopped after their cgroup could be removed
+func prune(kindDir string) {
+	dirs, err := ioutil.ReadDir(kindDir)
+	if err != nil {
+		return
+	}
+	for _, d := range dirs {
+		if !d.IsDir() {
+			continue
+		}
+		dir := filepath.Join(kindDir, d.Name())
+		procs, err := ioutil.ReadFile(filepath.Join(dir, "cgroup.procs"))
+		if err != nil || len(bytes.TrimSpace(procs)) != 0 {
+			continue
+		}
+		if err := os.Remove(dir); err != nil {
+			log.WithError(err).WithField("cgroup", dir).Debug("failed to remove empty cgroup")
+		}
+	}
+}
+
+// ApplyLimits writes the limits set by the cluster options to the cgroups
+// of the daemons running on this peer
+func ApplyLimits() error {
+	if !enabled() {
+		return nil
+	}
+
+	root := config.GetString(rootOpt)
+	for _, k := range kinds {
+		kindDir := filepath.Join(root, k.dir)
+		dirs, err := ioutil.ReadDir(kindDir)
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return err
+		}
+
+		l := k.limits()
+		for _, d := range dirs {
+			if !d.IsDir() {
+				continue
+			}
+			if err := setLimits(filepath.Join(kindDir, d.Name()), l); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// readUint reads a value of a cgroup file, 0 being returned for "max"
+func readUint(path string) uint64 {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+	n, _ := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+	return n
+}
+
+// parseCPUStat returns the CPU time used, in microseconds, given the
+// contents of cpu.stat
+func parseCPUStat(data []byte) uint64 {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[0] == "usage_usec" {
+			n, _ := strconv.ParseUint(fields[1], 10, 64)
+			return n
+		}
+	}
+	return 0
+}
+
+// parseCPUMax returns the CPU limit in percent of one CPU, 0 for no limit,
+// given the contents of cpu.max
+func parseCPUMax(data []byte) int {
+	fields := strings.Fields(string(data))
+	if len(fields) != 2 || fields[0] == "max" {
+		return 0
+	}
+	quota, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return 0
+	}
+	period, err := strconv.Atoi(fields[1])
+	if err != nil || period == 0 {
+		return 0
+	}
+	return quota * 100 / period
+}
+
+// hasProcess tells if the process is in the cgroup, given the contents of
+// its cgroup.procs
+func hasProcess(procs []byte, pid int) bool {
+	p := strconv.Itoa(pid)
+	for _, line := range strings.Fields(string(procs)) {
+		if line == p {
+			return true
+		}
+	}
+	return false
+}
+
+// find returns the directory of the cgroup of glusterd2 the process is in
+func find(pid int) (string, bool) {
+	root := config.GetString(rootOpt)
+	for _, k := range kinds {
+		kindDir := filepath.Join(root, k.dir)
+		dirs, err := ioutil.ReadDir(kindDir)
+		if err != nil {
+			continue
+		}
+		for _, d := range dirs {
+			if !d.IsDir() {
+				continue
+			}
+			dir := filepath.Join(kindDir, d.Name())
+			procs, err := ioutil.ReadFile(filepath.Join(dir, "cgroup.procs"))
+			if err == nil && hasProcess(procs, pid) {
+				return dir, true
+			}
+		}
+	}
+	return "", false
+}
+
+// Usage returns the resource usage and limits of the cgroup of the process,
+// or nil if it is not run in a cgroup of glusterd2
+func Usage(pid int) *api.ResourceUsage {
+	if !enabled() {
+		return nil
+	}
+	dir, ok := find(pid)
+	if !ok {
+		return nil
+	}
+
+	u := &api.ResourceUsage{
+		Cgroup:           dir,
+		MemoryBytes:      readUint(filepath.Join(dir, "memory.current")),
+		MemoryLimitBytes: readUint(filepath.Join(dir, "memory.max")),
+	}
+	if stat, err := ioutil.ReadFile(filepath.Join(dir, "cpu.stat")); err == nil {
+		u.CPUSeconds = float64(parseCPUStat(stat)) / 1e6
+	}
+	if max, err := ioutil.ReadFile(filepath.Join(dir, "cpu.max")); err == nil {
+		u.CPULimitPercent = parseCPUMax(max)
+	}
+	return u
+}
@@ -0,0 +1,58 @@
+package cgroups
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCgroupName(t *testing.T) {
+	assert.Equal(t, "bricks-b1", cgroupName("/bricks/b1"))
+	assert.Equal(t, "glustershd", cgroupName("glustershd"))
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, validateCPU(brickCPUKey, "0"))
+	assert.NoError(t, validateCPU(brickCPUKey, "250"))
+	assert.Error(t, validateCPU(brickCPUKey, "-1"))
+	assert.Error(t, validateCPU(brickCPUKey, "half"))
+
+	assert.NoError(t, validateMemory(brickMemoryKey, "0"))
+	assert.NoError(t, validateMemory(brickMemoryKey, "4GiB"))
+	assert.Error(t, validateMemory(brickMemoryKey, "lots"))
+}
+
+func TestSetLimits(t *testing.T) {
+	dir, err := ioutil.TempDir("", "TestSetLimits")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	require.NoError(t, setLimits(dir, Limits{CPUPercent: 150, MemoryBytes: 1 << 30}))
+	cpu, err := ioutil.ReadFile(filepath.Join(dir, "cpu.max"))
+	require.NoError(t, err)
+	assert.Equal(t, "150000 100000", string(cpu))
+	assert.Equal(t, 150, parseCPUMax(cpu))
+	assert.Equal(t, uint64(1<<30), readUint(filepath.Join(dir, "memory.max")))
+
+	require.NoError(t, setLimits(dir, Limits{}))
+	cpu, err = ioutil.ReadFile(filepath.Join(dir, "cpu.max"))
+	require.NoError(t, err)
+	assert.Equal(t, 0, parseCPUMax(cpu))
+	assert.Equal(t, uint64(0), readUint(filepath.Join(dir, "memory.max")))
+}
+
+func TestParseCPUStat(t *testing.T) {
+	stat := []byte("usage_usec 2500000\nuser_usec 2000000\nsystem_usec 500000\n")
+	assert.Equal(t, uint64(2500000), parseCPUStat(stat))
+	assert.Equal(t, uint64(0), parseCPUStat([]byte("")))
+}
+
+func TestHasProcess(t *testing.T) {
+	assert.True(t, hasProcess([]byte("12\n345\n"), 345))
+	assert.False(t, hasProcess([]byte("12\n345\n"), 34))
+	assert.False(t, hasProcess(nil, 12))
+}
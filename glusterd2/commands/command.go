@@ -2,11 +2,23 @@
 package commands
 
 import (
+	"github.com/gluster/glusterd2/glusterd2/commands/daemon"
+	"github.com/gluster/glusterd2/glusterd2/commands/debug"
+	"github.com/gluster/glusterd2/glusterd2/commands/diagnostics"
+	"github.com/gluster/glusterd2/glusterd2/commands/hooks"
+	"github.com/gluster/glusterd2/glusterd2/commands/leaders"
+	"github.com/gluster/glusterd2/glusterd2/commands/migrate"
+	"github.com/gluster/glusterd2/glusterd2/commands/mounts"
+	"github.com/gluster/glusterd2/glusterd2/commands/namespaces"
 	"github.com/gluster/glusterd2/glusterd2/commands/options"
 	"github.com/gluster/glusterd2/glusterd2/commands/peers"
+	"github.com/gluster/glusterd2/glusterd2/commands/plugins"
 	"github.com/gluster/glusterd2/glusterd2/commands/snapshot"
+	"github.com/gluster/glusterd2/glusterd2/commands/templates"
+	"github.com/gluster/glusterd2/glusterd2/commands/upgrade"
 	"github.com/gluster/glusterd2/glusterd2/commands/version"
 	"github.com/gluster/glusterd2/glusterd2/commands/volumes"
+	"github.com/gluster/glusterd2/glusterd2/commands/xlators"
 	"github.com/gluster/glusterd2/glusterd2/servers/rest/route"
 )
 
@@ -25,4 +37,16 @@ var Commands = []Command{
 	&snapshotcommands.Command{},
 	&peercommands.Command{},
 	&optionscommands.Command{},
+	&debugcommands.Command{},
+	&daemoncommands.Command{},
+	&diagnosticscommands.Command{},
+	&templatecommands.Command{},
+	&hookcommands.Command{},
+	&namespacecommands.Command{},
+	&mountcommands.Command{},
+	&xlatorcommands.Command{},
+	&upgradecommands.Command{},
+	&migratecommands.Command{},
+	&plugincommands.Command{},
+	&leadercommands.Command{},
 }
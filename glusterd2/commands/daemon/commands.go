@@ -0,0 +1,42 @@
+// Package daemoncommands implements the statedump of glusterd2 itself,
+// which dumps the internal state of the peer, and the listing of the daemons
+// managed by the peer
+package daemoncommands
+
+import (
+	"github.com/gluster/glusterd2/glusterd2/middleware"
+	"github.com/gluster/glusterd2/glusterd2/servers/rest/route"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/utils"
+)
+
+// Command is a holding struct used to implement the GlusterD Command interface
+type Command struct {
+}
+
+// Routes returns command routes. Required for the Command interface.
+func (c *Command) Routes() route.Routes {
+	return route.Routes{
+		route.Route{
+			Name:         "DaemonStatedump",
+			Method:       "GET",
+			Pattern:      "/daemon/statedump",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.DaemonStatedumpResp)(nil)),
+			PeerLocal:    true,
+			HandlerFunc:  middleware.RequireAdmin(statedumpHandler)},
+		route.Route{
+			Name:         "DaemonList",
+			Method:       "GET",
+			Pattern:      "/daemons",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.DaemonListResp)(nil)),
+			PeerLocal:    true,
+			HandlerFunc:  daemonListHandler},
+	}
+}
+
+// RegisterStepFuncs implements a required function for the Command interface
+func (c *Command) RegisterStepFuncs() {
+	return
+}
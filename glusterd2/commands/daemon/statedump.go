@@ -0,0 +1,179 @@
+package daemoncommands
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"runtime"
+	"sort"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/daemon"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/plugin"
+	"github.com/gluster/glusterd2/glusterd2/pmap"
+	"github.com/gluster/glusterd2/glusterd2/quorum"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/servers/sunrpc"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/glusterd2/supportbundle"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	transactionv2 "github.com/gluster/glusterd2/glusterd2/transactionv2"
+	"github.com/gluster/glusterd2/glusterd2/volgen"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/pborman/uuid"
+	"github.com/thejerf/suture"
+)
+
+func init() {
+	supportbundle.RegisterPeerCollector("statedump.json", func(ctx context.Context) (interface{}, error) {
+		return statedump(ctx), nil
+	})
+}
+
+func statedumpHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, statedump(ctx))
+}
+
+// statedump returns the internal state of this peer
+func statedump(ctx context.Context) api.DaemonStatedumpResp {
+	logger := gdctx.GetReqLogger(ctx)
+
+	resp := api.DaemonStatedumpResp{
+		PeerID:         gdctx.MyUUID,
+		Time:           time.Now(),
+		Goroutines:     runtime.NumGoroutine(),
+		Caches:         dumpCaches(),
+		Transactions:   dumpTxns(),
+		SunRPCClients:  make([]api.SunRPCClient, 0),
+		SupervisorTree: dumpSupervisor(gdctx.Supervisor),
+		Daemons:        make([]api.StatedumpDaemon, 0),
+		StoreWatches:   make([]api.StoreWatch, 0),
+	}
+
+	for _, c := range sunrpc.Clients() {
+		resp.SunRPCClients = append(resp.SunRPCClients, api.SunRPCClient(c))
+	}
+
+	daemons, err := daemon.List()
+	if err != nil {
+		logger.WithError(err).Warn("failed to get the daemons managed by the peer for the statedump")
+	}
+	for _, d := range daemons {
+		running, pid := daemon.IsRunning(d)
+		sd := api.StatedumpDaemon{ID: d.ID(), Name: d.Name(), Running: running}
+		if running {
+			sd.PID = pid
+		}
+		resp.Daemons = append(resp.Daemons, sd)
+	}
+
+	for _, s := range store.Watches() {
+		resp.StoreWatches = append(resp.StoreWatches, api.StoreWatch{
+			Name:      s.Name,
+			Key:       s.Key,
+			Started:   s.Started,
+			Events:    s.Events,
+			LastEvent: s.LastEvent,
+			Ended:     s.Ended,
+			Error:     s.Err,
+		})
+	}
+	return resp
+}
+
+// dumpCaches returns the state of this node kept in memory
+func dumpCaches() api.StatedumpCaches {
+	caches := api.StatedumpCaches{
+		PortMap:       make([]api.StatedumpPortMapEntry, 0),
+		CustomXlators: make([]string, 0),
+		Plugins:       plugin.List(),
+		StepFuncs:     transaction.StepFuncNames(),
+		Quorum:        quorum.Status(),
+	}
+
+	for _, e := range pmap.RegistryEntries() {
+		caches.PortMap = append(caches.PortMap, api.StatedumpPortMapEntry(e))
+	}
+	sort.Slice(caches.PortMap, func(i, j int) bool {
+		return caches.PortMap[i].Port < caches.PortMap[j].Port
+	})
+
+	for _, cx := range volgen.CustomXlators() {
+		caches.CustomXlators = append(caches.CustomXlators, cx.ID())
+	}
+
+	sort.Strings(caches.StepFuncs)
+	return caches
+}
+
+// dumpTxns returns the transactions pending in the store, along with their
+// state on this node
+func dumpTxns() []api.StatedumpTxn {
+	txns := make([]api.StatedumpTxn, 0)
+	if transactionv2.GlobalTxnManager == nil {
+		return txns
+	}
+
+	for _, t := range transactionv2.GlobalTxnManager.GetTxns() {
+		st := api.StatedumpTxn{
+			ID:        t.ID,
+			ReqID:     t.ReqID,
+			StartTime: t.StartTime,
+			Nodes:     t.Nodes,
+			Steps:     make([]string, 0, len(t.Steps)),
+		}
+		for _, s := range t.Steps {
+			st.Steps = append(st.Steps, s.DoFunc)
+		}
+
+		for _, n := range t.Nodes {
+			if !uuid.Equal(n, gdctx.MyUUID) {
+				continue
+			}
+			st.Local = new(api.StatedumpTxnState)
+			if status, err := transactionv2.GlobalTxnManager.GetTxnStatus(t.ID, n); err == nil {
+				st.Local.State = string(status.State)
+				st.Local.Reason = status.Reason
+			}
+			if step, err := transactionv2.GlobalTxnManager.GetLastExecutedStep(t.ID, n); err == nil {
+				st.Local.LastStep = step
+			}
+			break
+		}
+
+		txns = append(txns, st)
+	}
+
+	sort.Slice(txns, func(i, j int) bool {
+		return txns[i].StartTime.Before(txns[j].StartTime)
+	})
+	return txns
+}
+
+// dumpSupervisor returns the supervisor tree rooted at the supervisor
+func dumpSupervisor(s *suture.Supervisor) *api.SupervisorNode {
+	if s == nil {
+		return nil
+	}
+
+	node := &api.SupervisorNode{Name: s.String()}
+	for _, svc := range s.Services() {
+		if child, ok := svc.(*suture.Supervisor); ok {
+			node.Children = append(node.Children, *dumpSupervisor(child))
+			continue
+		}
+		node.Children = append(node.Children, api.SupervisorNode{Name: serviceName(svc)})
+	}
+	return node
+}
+
+// serviceName returns the name of the service, or its type if it has no name
+func serviceName(svc suture.Service) string {
+	if s, ok := svc.(fmt.Stringer); ok {
+		return s.String()
+	}
+	return fmt.Sprintf("%T", svc)
+}
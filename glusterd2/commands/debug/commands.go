@@ -0,0 +1,110 @@
+// Package debugcommands implements the pprof, SunRPC clients and fault
+// injection endpoints
+package debugcommands
+
+import (
+	"net/http/pprof"
+
+	"github.com/gluster/glusterd2/glusterd2/servers/rest/route"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/utils"
+)
+
+// Command is a holding struct used to implement the GlusterD Command interface
+type Command struct {
+}
+
+// Routes returns command routes. Required for the Command interface.
+// The routes are not versioned so that the pprof endpoints are available at
+// the paths expected by the go pprof tool.
+func (c *Command) Routes() route.Routes {
+	return route.Routes{
+		route.Route{
+			Name:         "DebugSettingsGet",
+			Method:       "GET",
+			Pattern:      "/debug",
+			ResponseType: utils.GetTypeString((*api.DebugSettings)(nil)),
+			HandlerFunc:  adminOnly(debugSettingsGetHandler)},
+		route.Route{
+			Name:         "DebugSettingsSet",
+			Method:       "PUT",
+			Pattern:      "/debug",
+			RequestType:  utils.GetTypeString((*api.DebugSettings)(nil)),
+			ResponseType: utils.GetTypeString((*api.DebugSettings)(nil)),
+			HandlerFunc:  adminOnly(debugSettingsSetHandler)},
+		route.Route{
+			Name:         "DebugSunRPCClients",
+			Method:       "GET",
+			Pattern:      "/debug/sunrpc-clients",
+			ResponseType: utils.GetTypeString((*api.SunRPCClientsResp)(nil)),
+			PeerLocal:    true,
+			HandlerFunc:  debugOnly(sunrpcClientsHandler)},
+		route.Route{
+			Name:         "DebugFaultList",
+			Method:       "GET",
+			Pattern:      "/debug/faults",
+			ResponseType: utils.GetTypeString((*api.FaultListResp)(nil)),
+			PeerLocal:    true,
+			HandlerFunc:  faultsOnly(faultListHandler)},
+		route.Route{
+			Name:         "DebugFaultAdd",
+			Method:       "POST",
+			Pattern:      "/debug/faults",
+			RequestType:  utils.GetTypeString((*api.FaultReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.Fault)(nil)),
+			PeerLocal:    true,
+			HandlerFunc:  faultsOnly(faultAddHandler)},
+		route.Route{
+			Name:        "DebugFaultRemove",
+			Method:      "DELETE",
+			Pattern:     "/debug/faults/{id}",
+			PeerLocal:   true,
+			HandlerFunc: faultsOnly(faultRemoveHandler)},
+		route.Route{
+			Name:        "DebugFaultClear",
+			Method:      "DELETE",
+			Pattern:     "/debug/faults",
+			PeerLocal:   true,
+			HandlerFunc: faultsOnly(faultClearHandler)},
+		route.Route{
+			Name:        "DebugPprofIndex",
+			Method:      "GET",
+			Pattern:     "/debug/pprof/",
+			HandlerFunc: debugOnly(pprof.Index)},
+		route.Route{
+			Name:        "DebugPprofCmdline",
+			Method:      "GET",
+			Pattern:     "/debug/pprof/cmdline",
+			HandlerFunc: debugOnly(pprof.Cmdline)},
+		route.Route{
+			Name:        "DebugPprofProfile",
+			Method:      "GET",
+			Pattern:     "/debug/pprof/profile",
+			HandlerFunc: debugOnly(pprof.Profile)},
+		route.Route{
+			Name:        "DebugPprofSymbol",
+			Method:      "GET",
+			Pattern:     "/debug/pprof/symbol",
+			HandlerFunc: debugOnly(pprof.Symbol)},
+		route.Route{
+			Name:        "DebugPprofSymbolLookup",
+			Method:      "POST",
+			Pattern:     "/debug/pprof/symbol",
+			HandlerFunc: debugOnly(pprof.Symbol)},
+		route.Route{
+			Name:        "DebugPprofTrace",
+			Method:      "GET",
+			Pattern:     "/debug/pprof/trace",
+			HandlerFunc: debugOnly(pprof.Trace)},
+		route.Route{
+			Name:        "DebugPprofLookup",
+			Method:      "GET",
+			Pattern:     "/debug/pprof/{profile}",
+			HandlerFunc: debugOnly(profileHandler)},
+	}
+}
+
+// RegisterStepFuncs implements a required function for the Command interface
+func (c *Command) RegisterStepFuncs() {
+	return
+}
@@ -0,0 +1,60 @@
+package debugcommands
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/fault"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/gorilla/mux"
+)
+
+// faultsOnly allows the access to the handler only if fault injection is
+// enabled
+func faultsOnly(h http.HandlerFunc) http.HandlerFunc {
+	return debugOnly(func(w http.ResponseWriter, r *http.Request) {
+		if !fault.Enabled() {
+			restutils.SendHTTPError(r.Context(), w, http.StatusForbidden, fault.ErrDisabled)
+			return
+		}
+		h(w, r)
+	})
+}
+
+func faultListHandler(w http.ResponseWriter, r *http.Request) {
+	restutils.SendHTTPResponse(r.Context(), w, http.StatusOK, api.FaultListResp(fault.List()))
+}
+
+func faultAddHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req api.FaultReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
+		return
+	}
+
+	f, err := fault.Add(req)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusCreated, f)
+}
+
+func faultRemoveHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if err := fault.Remove(mux.Vars(r)["id"]); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusNotFound, err)
+		return
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusNoContent, nil)
+}
+
+func faultClearHandler(w http.ResponseWriter, r *http.Request) {
+	fault.Clear()
+	restutils.SendHTTPResponse(r.Context(), w, http.StatusNoContent, nil)
+}
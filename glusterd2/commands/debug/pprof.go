@@ -0,0 +1,12 @@
+package debugcommands
+
+import (
+	"net/http"
+	"net/http/pprof"
+
+	"github.com/gorilla/mux"
+)
+
+func profileHandler(w http.ResponseWriter, r *http.Request) {
+	pprof.Handler(mux.Vars(r)["profile"]).ServeHTTP(w, r)
+}
@@ -0,0 +1,82 @@
+package debugcommands
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+
+	"github.com/gluster/glusterd2/glusterd2/middleware"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	log "github.com/sirupsen/logrus"
+	config "github.com/spf13/viper"
+)
+
+var (
+	settingsMu   sync.RWMutex
+	settings     api.DebugSettings
+	settingsOnce sync.Once
+)
+
+// errDebugDisabled is returned when the debug endpoints are accessed while
+// they are disabled
+var errDebugDisabled = errors.New("debug endpoints are disabled, enable them with PUT /debug")
+
+// getSettings returns the current debug settings. The endpoints are enabled
+// at startup if the profiling option is set.
+func getSettings() api.DebugSettings {
+	settingsOnce.Do(func() {
+		settings.Enabled = config.GetBool("profiling")
+	})
+
+	settingsMu.RLock()
+	defer settingsMu.RUnlock()
+	return settings
+}
+
+func setSettings(s api.DebugSettings) {
+	// make sure the defaults are not applied after this
+	getSettings()
+
+	settingsMu.Lock()
+	defer settingsMu.Unlock()
+	settings = s
+}
+
+// adminOnly allows only the admin user to access the handler
+func adminOnly(h http.HandlerFunc) http.HandlerFunc {
+	return middleware.RequireAdmin(h)
+}
+
+// debugOnly allows only the admin user to access the handler, and only if
+// the debug endpoints are enabled
+func debugOnly(h http.HandlerFunc) http.HandlerFunc {
+	return adminOnly(func(w http.ResponseWriter, r *http.Request) {
+		if !getSettings().Enabled {
+			restutils.SendHTTPError(r.Context(), w, http.StatusForbidden, errDebugDisabled)
+			return
+		}
+		h(w, r)
+	})
+}
+
+func debugSettingsGetHandler(w http.ResponseWriter, r *http.Request) {
+	restutils.SendHTTPResponse(r.Context(), w, http.StatusOK, getSettings())
+}
+
+func debugSettingsSetHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req api.DebugSettings
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
+		return
+	}
+
+	setSettings(req)
+	log.WithField("enabled", req.Enabled).Info("debug endpoints toggled")
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, req)
+}
@@ -0,0 +1,18 @@
+package debugcommands
+
+import (
+	"net/http"
+
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/servers/sunrpc"
+	"github.com/gluster/glusterd2/pkg/api"
+)
+
+func sunrpcClientsHandler(w http.ResponseWriter, r *http.Request) {
+	clients := sunrpc.Clients()
+	resp := make(api.SunRPCClientsResp, 0, len(clients))
+	for _, c := range clients {
+		resp = append(resp, api.SunRPCClient(c))
+	}
+	restutils.SendHTTPResponse(r.Context(), w, http.StatusOK, resp)
+}
@@ -0,0 +1,71 @@
+// Package diagnosticscommands implements the cluster diagnostics command,
+// which checks the cluster for common problems, and the support bundles
+// collecting the state of the cluster for bug reports
+package diagnosticscommands
+
+import (
+	"github.com/gluster/glusterd2/glusterd2/middleware"
+	"github.com/gluster/glusterd2/glusterd2/servers/rest/route"
+	"github.com/gluster/glusterd2/glusterd2/supportbundle"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/utils"
+)
+
+// Command is a holding struct used to implement the GlusterD Command interface
+type Command struct {
+}
+
+// Routes returns command routes. Required for the Command interface.
+func (c *Command) Routes() route.Routes {
+	return route.Routes{
+		route.Route{
+			Name:         "Diagnostics",
+			Method:       "GET",
+			Pattern:      "/diagnostics",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.DiagnosticsResp)(nil)),
+			HandlerFunc:  middleware.RequireAdmin(diagnosticsHandler)},
+		route.Route{
+			Name:         "SupportBundleCreate",
+			Method:       "POST",
+			Pattern:      "/cluster/support-bundle",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.SupportBundleReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.SupportBundle)(nil)),
+			HandlerFunc:  middleware.RequireAdmin(createSupportBundleHandler)},
+		route.Route{
+			Name:         "SupportBundleList",
+			Method:       "GET",
+			Pattern:      "/cluster/support-bundle",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.SupportBundleListResp)(nil)),
+			HandlerFunc:  middleware.RequireAdmin(listSupportBundlesHandler)},
+		route.Route{
+			Name:         "SupportBundleStatus",
+			Method:       "GET",
+			Pattern:      "/cluster/support-bundle/{id}",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.SupportBundle)(nil)),
+			HandlerFunc:  middleware.RequireAdmin(getSupportBundleHandler)},
+		route.Route{
+			Name:        "SupportBundleDownload",
+			Method:      "GET",
+			Pattern:     "/cluster/support-bundle/{id}/download",
+			Version:     1,
+			HandlerFunc: middleware.RequireAdmin(downloadSupportBundleHandler)},
+		route.Route{
+			Name:        "SupportBundleDelete",
+			Method:      "DELETE",
+			Pattern:     "/cluster/support-bundle/{id}",
+			Version:     1,
+			HandlerFunc: middleware.RequireAdmin(deleteSupportBundleHandler)},
+	}
+}
+
+// RegisterStepFuncs registers transaction step functions with
+// the Global Transaction Step Registry
+func (c *Command) RegisterStepFuncs() {
+	transaction.RegisterStepFunc(txnCollectDiagnostics, "diagnostics.Collect")
+	supportbundle.RegisterStepFuncs()
+}
@@ -0,0 +1,321 @@
+package diagnosticscommands
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/pborman/uuid"
+)
+
+const (
+	// maxClockSkew is the largest difference between the clocks of the
+	// peers which is not reported
+	maxClockSkew = 2 * time.Second
+
+	storeTimeout = 5 * time.Second
+)
+
+// severities maps the statuses of checks to their order of severity
+var severities = map[string]int{
+	api.DiagnosticOK:      0,
+	api.DiagnosticWarning: 1,
+	api.DiagnosticError:   2,
+}
+
+// worse returns the more severe of the two statuses
+func worse(a, b string) string {
+	if severities[b] > severities[a] {
+		return b
+	}
+	return a
+}
+
+func newCheck(name string) api.DiagnosticCheck {
+	return api.DiagnosticCheck{Name: name, Status: api.DiagnosticOK}
+}
+
+func addFinding(check *api.DiagnosticCheck, f api.DiagnosticFinding) {
+	check.Findings = append(check.Findings, f)
+	check.Status = worse(check.Status, f.Severity)
+}
+
+// peerDiagnostics is the result of the diagnostic checks run on a peer
+type peerDiagnostics struct {
+	peer *peer.Peer
+	nodeDiagnostics
+}
+
+// addNodeFindings adds the problems found by the check on the peers
+func addNodeFindings(check *api.DiagnosticCheck, results []peerDiagnostics) {
+	for _, r := range results {
+		for _, f := range r.Findings[check.Name] {
+			addFinding(check, f)
+		}
+	}
+}
+
+// clockSkew returns how far t is outside of the interval from start to end
+func clockSkew(t, start, end time.Time) time.Duration {
+	if t.Before(start) {
+		return start.Sub(t)
+	}
+	if t.After(end) {
+		return t.Sub(end)
+	}
+	return 0
+}
+
+// checkClockSkew compares the time on the peers, taken while the checks were
+// being run, with the time on this node
+func checkClockSkew(results []peerDiagnostics, start, end time.Time) api.DiagnosticCheck {
+	check := newCheck(api.DiagnosticClockSkew)
+	for _, r := range results {
+		skew := clockSkew(r.Time, start, end)
+		if skew <= maxClockSkew {
+			continue
+		}
+		addFinding(&check, api.DiagnosticFinding{
+			Severity: api.DiagnosticWarning,
+			PeerID:   r.peer.ID,
+			Message:  fmt.Sprintf("clock of peer %s is off by at least %s from the clock of %s", r.peer.Name, skew.Round(time.Millisecond), gdctx.HostName),
+			Action:   "Synchronize the clocks of all the peers using NTP",
+		})
+	}
+	return check
+}
+
+// memberHealthy returns true if the store member responds on any of its
+// client URLs
+func memberHealthy(ctx context.Context, urls []string) bool {
+	for _, url := range urls {
+		if _, err := store.Store.Status(ctx, url); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// checkStoreQuorum checks if a quorum of the store members is healthy
+func checkStoreQuorum(ctx context.Context) api.DiagnosticCheck {
+	check := newCheck(api.DiagnosticStoreQuorum)
+
+	ctx, cancel := context.WithTimeout(ctx, storeTimeout)
+	defer cancel()
+
+	resp, err := store.Store.MemberList(ctx)
+	if err != nil {
+		addFinding(&check, api.DiagnosticFinding{
+			Severity: api.DiagnosticError,
+			Message:  fmt.Sprintf("failed to get the members of the store: %s", err),
+			Action:   "Check that a quorum of the store members is running and reachable",
+		})
+		return check
+	}
+
+	healthy := 0
+	for _, m := range resp.Members {
+		if memberHealthy(ctx, m.ClientURLs) {
+			healthy++
+			continue
+		}
+		addFinding(&check, api.DiagnosticFinding{
+			Severity: api.DiagnosticWarning,
+			Message:  fmt.Sprintf("store member %s (%s) is not healthy", m.Name, strings.Join(m.ClientURLs, ", ")),
+			Action:   "Check the store logs on the member and that it is reachable from the other members",
+		})
+	}
+
+	if quorum := len(resp.Members)/2 + 1; healthy < quorum {
+		addFinding(&check, api.DiagnosticFinding{
+			Severity: api.DiagnosticError,
+			Message:  fmt.Sprintf("only %d of %d store members are healthy, %d are needed for quorum", healthy, len(resp.Members), quorum),
+			Action:   "Bring the unhealthy store members back up, changes to the cluster fail until the quorum is regained",
+		})
+	}
+	return check
+}
+
+// checkVolfileChecksums finds the volfiles which differ between the peers.
+// The checksum of a volfile held by the most peers is taken to be the right
+// one. If no checksum is held by more peers than the others, the check cannot
+// tell which volfiles are wrong and reports all the peers having the volfile.
+func checkVolfileChecksums(results []peerDiagnostics) api.DiagnosticCheck {
+	check := newCheck(api.DiagnosticVolfileChecksum)
+	addNodeFindings(&check, results)
+
+	// map of volfile IDs to checksums to the peers having the volfile
+	// with the checksum
+	volfiles := make(map[string]map[string][]*peer.Peer)
+	for _, r := range results {
+		for volfileID, sum := range r.Volfiles {
+			if volfiles[volfileID] == nil {
+				volfiles[volfileID] = make(map[string][]*peer.Peer)
+			}
+			volfiles[volfileID][sum] = append(volfiles[volfileID][sum], r.peer)
+		}
+	}
+
+	volfileIDs := make([]string, 0, len(volfiles))
+	for volfileID := range volfiles {
+		volfileIDs = append(volfileIDs, volfileID)
+	}
+	sort.Strings(volfileIDs)
+
+	for _, volfileID := range volfileIDs {
+		sums := volfiles[volfileID]
+		if len(sums) < 2 {
+			continue
+		}
+		var majority string
+		tie := false
+		for sum, peers := range sums {
+			switch {
+			case majority == "" || len(peers) > len(sums[majority]):
+				majority = sum
+				tie = false
+			case len(peers) == len(sums[majority]):
+				tie = true
+			}
+		}
+		if tie {
+			var names []string
+			for _, peers := range sums {
+				for _, p := range peers {
+					names = append(names, p.Name)
+				}
+			}
+			sort.Strings(names)
+			addFinding(&check, api.DiagnosticFinding{
+				Severity: api.DiagnosticWarning,
+				Message:  fmt.Sprintf("volfile %s differs between peers %s, and no version of it is held by more peers than the others", volfileID, strings.Join(names, ", ")),
+				Action:   "Compare the volfile on the peers and regenerate the wrong ones by restarting the volumes or the daemon using it",
+			})
+			continue
+		}
+		for sum, peers := range sums {
+			if sum == majority {
+				continue
+			}
+			for _, p := range peers {
+				addFinding(&check, api.DiagnosticFinding{
+					Severity: api.DiagnosticWarning,
+					PeerID:   p.ID,
+					Message:  fmt.Sprintf("volfile %s on peer %s differs from the one on %d other peers", volfileID, p.Name, len(sums[majority])),
+					Action:   "Regenerate the volfile by restarting the volumes or the daemon using it",
+				})
+			}
+		}
+	}
+	return check
+}
+
+func diagnosticsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+
+	resp, err := diagnose(ctx)
+	if err != nil {
+		logger.WithError(err).Error("failed to run diagnostic checks")
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+// diagnose runs the diagnostic checks on all the peers
+func diagnose(ctx context.Context) (*api.DiagnosticsResp, error) {
+	peers, err := peer.GetPeers()
+	if err != nil {
+		return nil, err
+	}
+	nodes := make([]uuid.UUID, 0, len(peers))
+	for _, p := range peers {
+		nodes = append(nodes, p.ID)
+	}
+
+	txn := transaction.NewTxn(ctx)
+	defer txn.Done()
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc: "diagnostics.Collect",
+			Nodes:  nodes,
+		},
+	}
+	// Some nodes may not be up, which is reported as a finding
+	txn.DontCheckAlive = true
+	txn.DisableRollback = true
+
+	start := time.Now()
+	if err := txn.Do(); err != nil {
+		return nil, err
+	}
+	end := time.Now()
+
+	peersCheck := newCheck(api.DiagnosticPeers)
+	alive := store.Store.GetAliveNodes(ctx)
+	var results []peerDiagnostics
+	for _, p := range peers {
+		if _, ok := alive[p.ID.String()]; !ok {
+			addFinding(&peersCheck, api.DiagnosticFinding{
+				Severity: api.DiagnosticWarning,
+				PeerID:   p.ID,
+				Message:  fmt.Sprintf("peer %s is not connected to the store", p.Name),
+				Action:   "Check that glusterd2 is running on the peer and can reach the store",
+			})
+		}
+
+		r := peerDiagnostics{peer: p}
+		if err := txn.Ctx.GetNodeResult(p.ID, diagnosticsTxnKey, &r.nodeDiagnostics); err != nil {
+			addFinding(&peersCheck, api.DiagnosticFinding{
+				Severity: api.DiagnosticError,
+				PeerID:   p.ID,
+				Message:  fmt.Sprintf("peer %s did not respond, the checks on the peer were skipped", p.Name),
+				Action:   "Check that glusterd2 is running on the peer and is reachable from the other peers",
+			})
+			continue
+		}
+		results = append(results, r)
+	}
+	addNodeFindings(&peersCheck, results)
+
+	orphanedCheck := newCheck(api.DiagnosticOrphanedBricks)
+	addNodeFindings(&orphanedCheck, results)
+	pmapCheck := newCheck(api.DiagnosticStalePmap)
+	addNodeFindings(&pmapCheck, results)
+	healsCheck := newCheck(api.DiagnosticPendingHeals)
+	addNodeFindings(&healsCheck, results)
+
+	resp := &api.DiagnosticsResp{
+		Status: api.DiagnosticOK,
+		Checks: []api.DiagnosticCheck{
+			peersCheck,
+			checkClockSkew(results, start, end),
+			checkStoreQuorum(ctx),
+			orphanedCheck,
+			pmapCheck,
+			checkVolfileChecksums(results),
+			healsCheck,
+		},
+	}
+	for _, c := range resp.Checks {
+		resp.Status = worse(resp.Status, c.Status)
+	}
+	for _, r := range results {
+		p := r.Process
+		p.PeerID = r.peer.ID
+		p.Name = r.peer.Name
+		resp.Peers = append(resp.Peers, p)
+	}
+	return resp, nil
+}
@@ -0,0 +1,64 @@
+package diagnosticscommands
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorse(t *testing.T) {
+	assert.Equal(t, api.DiagnosticOK, worse(api.DiagnosticOK, api.DiagnosticOK))
+	assert.Equal(t, api.DiagnosticWarning, worse(api.DiagnosticOK, api.DiagnosticWarning))
+	assert.Equal(t, api.DiagnosticError, worse(api.DiagnosticError, api.DiagnosticWarning))
+}
+
+func TestClockSkew(t *testing.T) {
+	start := time.Now()
+	end := start.Add(time.Second)
+	assert.Equal(t, time.Duration(0), clockSkew(start.Add(500*time.Millisecond), start, end))
+	assert.Equal(t, 3*time.Second, clockSkew(start.Add(-3*time.Second), start, end))
+	assert.Equal(t, 2*time.Second, clockSkew(end.Add(2*time.Second), start, end))
+}
+
+func volfileResults(sums ...string) []peerDiagnostics {
+	var results []peerDiagnostics
+	for i, sum := range sums {
+		r := peerDiagnostics{peer: &peer.Peer{ID: uuid.NewRandom(), Name: fmt.Sprintf("peer%d", i+1)}}
+		r.Volfiles = map[string]string{"gluster/vol1": sum}
+		results = append(results, r)
+	}
+	return results
+}
+
+func TestCheckVolfileChecksums(t *testing.T) {
+	// The same volfile on all the peers is not reported
+	check := checkVolfileChecksums(volfileResults("a", "a", "a"))
+	assert.Equal(t, api.DiagnosticOK, check.Status)
+	assert.Empty(t, check.Findings)
+
+	// The peers differing from most of the peers are reported
+	results := volfileResults("a", "b", "a")
+	check = checkVolfileChecksums(results)
+	assert.Equal(t, api.DiagnosticWarning, check.Status)
+	require.Len(t, check.Findings, 1)
+	assert.Equal(t, results[1].peer.ID, check.Findings[0].PeerID)
+
+	// A tie cannot tell which volfiles are wrong, all the peers are
+	// reported in a finding of no particular peer
+	for _, sums := range [][]string{{"a", "b"}, {"b", "a"}, {"a", "b", "c"}, {"b", "a", "a", "b"}} {
+		check = checkVolfileChecksums(volfileResults(sums...))
+		assert.Equal(t, api.DiagnosticWarning, check.Status)
+		require.Len(t, check.Findings, 1, "checksums %v", sums)
+		assert.Nil(t, check.Findings[0].PeerID)
+		for i := range sums {
+			assert.Contains(t, check.Findings[0].Message, fmt.Sprintf("peer%d", i+1))
+		}
+	}
+}
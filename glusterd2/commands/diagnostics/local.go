@@ -0,0 +1,275 @@
+package diagnosticscommands
+
+import (
+	"context"
+	"expvar"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/daemon"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/orphanbricks"
+	"github.com/gluster/glusterd2/glusterd2/pmap"
+	"github.com/gluster/glusterd2/glusterd2/servers/sunrpc"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	transactionv2 "github.com/gluster/glusterd2/glusterd2/transactionv2"
+	"github.com/gluster/glusterd2/glusterd2/volgen"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	config "github.com/spf13/viper"
+)
+
+const diagnosticsTxnKey = "diagnostics"
+
+// nodeDiagnostics is the result of the diagnostic checks run on a node
+type nodeDiagnostics struct {
+	// Time is the time on the node when the checks were run
+	Time time.Time `json:"time"`
+	// Volfiles maps the IDs of the volfiles expected to be the same on all
+	// the nodes to their checksums
+	Volfiles map[string]string `json:"volfiles"`
+	// Findings maps the names of the checks to the problems found on the
+	// node
+	Findings map[string][]api.DiagnosticFinding `json:"findings"`
+	// Process is the state of the glusterd2 process of the node
+	Process api.PeerDiagnostics `json:"process"`
+}
+
+func (d *nodeDiagnostics) add(check, severity, volname, action, format string, args ...interface{}) {
+	d.Findings[check] = append(d.Findings[check], api.DiagnosticFinding{
+		Severity: severity,
+		PeerID:   gdctx.MyUUID,
+		Volume:   volname,
+		Message:  fmt.Sprintf(format, args...),
+		Action:   action,
+	})
+}
+
+func txnCollectDiagnostics(c transaction.TxnCtx) error {
+	d := nodeDiagnostics{
+		Time:     time.Now(),
+		Findings: make(map[string][]api.DiagnosticFinding),
+		Process:  processDiagnostics(),
+	}
+
+	volumes, err := volume.GetVolumes(context.TODO())
+	if err != nil {
+		c.Logger().WithError(err).Error("failed to get volumes")
+		d.add(api.DiagnosticPeers, api.DiagnosticError, "",
+			"Check the connectivity of the peer to the store",
+			"failed to get volumes from the store on %s, checks of the bricks on the peer were skipped: %s", gdctx.HostName, err)
+	} else {
+		// map of the paths of the local bricks of started volumes to
+		// their volumes
+		bricks := make(map[string]*volume.Volinfo)
+		for _, v := range volumes {
+			if v.State != volume.VolStarted {
+				continue
+			}
+			for _, b := range v.GetLocalBricks() {
+				bricks[b.Path] = v
+			}
+		}
+
+		checkBrickProcesses(&d)
+		checkPmapEntries(&d, bricks)
+		checkBrickVolfiles(&d, volumes)
+		checkPendingHeals(&d, volumes)
+	}
+
+	d.Volfiles = sharedVolfileChecksums()
+
+	return c.SetNodeResult(gdctx.MyUUID, diagnosticsTxnKey, d)
+}
+
+// txnsInProgress returns the number of transactions initiated on this node
+// which are in progress
+func txnsInProgress() int64 {
+	m, ok := expvar.Get("txn").(*expvar.Map)
+	if !ok {
+		return 0
+	}
+	v, ok := m.Get("initiated_txn_in_progress").(*expvar.Int)
+	if !ok {
+		return 0
+	}
+	return v.Value()
+}
+
+func toMilliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// processDiagnostics returns the state of the glusterd2 process of this node
+func processDiagnostics() api.PeerDiagnostics {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	p := api.PeerDiagnostics{
+		Goroutines:     runtime.NumGoroutine(),
+		SunRPCClients:  sunrpc.ClientsCount(),
+		TxnsInProgress: txnsInProgress(),
+		StoreLatency:   make(map[string]api.StoreLatency),
+		HeapAllocBytes: mem.HeapAlloc,
+		NumGC:          mem.NumGC,
+	}
+
+	if transactionv2.GlobalTxnManager != nil {
+		p.PendingTxns = len(transactionv2.GlobalTxnManager.GetTxns())
+	}
+
+	for op, l := range store.Latency() {
+		p.StoreLatency[op] = api.StoreLatency{
+			Count: l.Count,
+			P50:   toMilliseconds(l.P50),
+			P90:   toMilliseconds(l.P90),
+			P99:   toMilliseconds(l.P99),
+		}
+	}
+	return p
+}
+
+// checkBrickProcesses finds the glusterfsd processes started by this node
+// which do not serve a brick of a started volume or activated snapshot
+func checkBrickProcesses(d *nodeDiagnostics) {
+	orphans, err := orphanbricks.Find()
+	if err != nil {
+		d.add(api.DiagnosticOrphanedBricks, api.DiagnosticError, "",
+			"Check the connectivity of the peer to the store",
+			"failed to check the brick processes on %s: %s", gdctx.HostName, err)
+		return
+	}
+	for _, p := range orphans {
+		d.add(api.DiagnosticOrphanedBricks, api.DiagnosticWarning, "",
+			fmt.Sprintf("Stop the process with 'kill %d' on %s", p.PID, gdctx.HostName),
+			"glusterfsd process %d on %s serving brick %s does not belong to any started volume", p.PID, gdctx.HostName, p.BrickPath)
+	}
+}
+
+// checkPmapEntries finds the entries in the port map of this node which are
+// not of bricks of started volumes, or whose brick processes are not running
+func checkPmapEntries(d *nodeDiagnostics, bricks map[string]*volume.Volinfo) {
+	entries := pmap.RegistryEntries()
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Port < entries[j].Port
+	})
+
+	for _, e := range entries {
+		v, ok := bricks[e.BrickPath]
+		if !ok {
+			d.add(api.DiagnosticStalePmap, api.DiagnosticWarning, "",
+				fmt.Sprintf("Restart glusterd2 on %s to clear the port map", gdctx.HostName),
+				"port %d on %s is mapped to brick %s which does not belong to any started volume", e.Port, gdctx.HostName, e.BrickPath)
+			continue
+		}
+		if e.PID <= 0 {
+			continue
+		}
+		if _, err := daemon.GetProcess(e.PID); err != nil {
+			d.add(api.DiagnosticStalePmap, api.DiagnosticError, v.Name,
+				fmt.Sprintf("Restart the brick with 'glustercli volume start %s --force'", v.Name),
+				"port %d on %s is mapped to brick %s but its brick process %d is not running", e.Port, gdctx.HostName, e.BrickPath, e.PID)
+		}
+	}
+}
+
+func volfilesDir() string {
+	return filepath.Join(config.GetString("localstatedir"), "volfiles")
+}
+
+// checkBrickVolfiles finds the volfiles of the local bricks of started
+// volumes which differ from the volfiles generated from the volume
+// information in the store
+func checkBrickVolfiles(d *nodeDiagnostics, volumes []*volume.Volinfo) {
+	for _, v := range volumes {
+		if v.State != volume.VolStarted {
+			continue
+		}
+		action := fmt.Sprintf("Regenerate the volfiles by stopping and starting volume %s", v.Name)
+
+		tmpl, err := volgen.GetTemplateFromVolinfo(v, "brick")
+		if err != nil {
+			continue
+		}
+		for _, b := range v.GetLocalBricks() {
+			expected, err := volgen.BrickLevelVolfile(tmpl, v, b.PeerID.String(), b.Path)
+			if err != nil {
+				continue
+			}
+			volfileID := brick.GetVolfileID(v.Name, b.Path)
+			content, err := ioutil.ReadFile(filepath.Join(volfilesDir(), volfileID+".vol"))
+			if err != nil {
+				d.add(api.DiagnosticVolfileChecksum, api.DiagnosticError, v.Name, action,
+					"volfile %s of brick %s:%s cannot be read: %s", volfileID, gdctx.HostName, b.Path, err)
+				continue
+			}
+			if volgen.Checksum(string(content)) != volgen.Checksum(expected) {
+				d.add(api.DiagnosticVolfileChecksum, api.DiagnosticError, v.Name, action,
+					"volfile %s of brick %s:%s does not match the volume configuration in the store", volfileID, gdctx.HostName, b.Path)
+			}
+		}
+	}
+}
+
+// sharedVolfileChecksums returns the checksums of the volfiles of this node
+// which are expected to be the same on all the nodes, that is, all but the
+// volfiles of the bricks of this node
+func sharedVolfileChecksums() map[string]string {
+	sums := make(map[string]string)
+	dir := volfilesDir()
+	filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil || !info.Mode().IsRegular() || !strings.HasSuffix(p, ".vol") {
+			return nil
+		}
+		rel, _ := filepath.Rel(dir, p)
+		volfileID := strings.TrimSuffix(rel, ".vol")
+		if strings.Contains(volfileID, gdctx.MyUUID.String()) {
+			return nil
+		}
+		content, err := ioutil.ReadFile(p)
+		if err != nil {
+			return nil
+		}
+		sums[volfileID] = volgen.Checksum(string(content))
+		return nil
+	})
+	return sums
+}
+
+// checkPendingHeals finds the local bricks of started replicate and disperse
+// volumes which have entries pending heal
+func checkPendingHeals(d *nodeDiagnostics, volumes []*volume.Volinfo) {
+	for _, v := range volumes {
+		if v.State != volume.VolStarted {
+			continue
+		}
+		switch v.Type {
+		case volume.Replicate, volume.Disperse, volume.DistReplicate, volume.DistDisperse:
+		default:
+			continue
+		}
+		action := fmt.Sprintf("List the entries with 'glustercli volume heal info %s' and heal them with 'glustercli volume heal index %s'", v.Name, v.Name)
+
+		for _, b := range v.GetLocalBricks() {
+			count, err := brick.CountPendingHeals(b.Path)
+			if err != nil {
+				d.add(api.DiagnosticPendingHeals, api.DiagnosticWarning, v.Name, action,
+					"failed to count the entries pending heal on brick %s:%s: %s", gdctx.HostName, b.Path, err)
+				continue
+			}
+			if count > 0 {
+				d.add(api.DiagnosticPendingHeals, api.DiagnosticWarning, v.Name, action,
+					"%d entries pending heal on brick %s:%s", count, gdctx.HostName, b.Path)
+			}
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package diagnosticscommands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/servers/rest/proxy"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/supportbundle"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/gorilla/mux"
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+func init() {
+	supportbundle.RegisterClusterCollector("diagnostics.json", func(ctx context.Context) (interface{}, error) {
+		return diagnose(ctx)
+	})
+}
+
+func createSupportBundleHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+
+	var req api.SupportBundleReq
+	// request body is optional
+	if err := restutils.UnmarshalRequest(r, &req); err != nil && err != io.EOF {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrJSONParsingFailed)
+		return
+	}
+	if req.LogSize < 0 {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "log-size must not be negative")
+		return
+	}
+
+	// the support bundle is created once the request is answered
+	b, err := supportbundle.Create(gdctx.WithoutCancel(ctx), req)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	logger.WithFields(log.Fields{
+		"support-bundle": b.ID.String(),
+		"user":           gdctx.GetReqUser(ctx),
+	}).Info("creating a support bundle")
+	restutils.SendHTTPResponse(ctx, w, http.StatusAccepted, b)
+}
+
+func listSupportBundlesHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	bundles, err := supportbundle.List()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, api.SupportBundleListResp(bundles))
+}
+
+func getSupportBundleHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	b, err := supportbundle.Get(mux.Vars(r)["id"])
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, b)
+}
+
+// downloadSupportBundleHandler sends the archive of the support bundle. The
+// archive is kept on the peer which created it.
+func downloadSupportBundleHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := mux.Vars(r)["id"]
+
+	b, err := supportbundle.Get(id)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	if b.State != api.SupportBundleCompleted {
+		restutils.SendHTTPError(ctx, w, http.StatusConflict, errors.ErrSupportBundleNotReady)
+		return
+	}
+	if !uuid.Equal(b.PeerID, gdctx.MyUUID) {
+		// The archive is on the peer which created the bundle
+		proxy.Serve(w, r, b.PeerID)
+		return
+	}
+
+	f, err := os.Open(supportbundle.ArchivePath(id))
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	defer f.Close()
+
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"support-bundle-%s.tar.gz\"", id))
+	http.ServeContent(w, r, "", b.UpdatedAt, f)
+}
+
+func deleteSupportBundleHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+	id := mux.Vars(r)["id"]
+
+	if err := supportbundle.Delete(ctx, id); err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	logger.WithFields(log.Fields{
+		"support-bundle": id,
+		"user":           gdctx.GetReqUser(ctx),
+	}).Info("deleted the support bundle")
+	restutils.SendHTTPResponse(ctx, w, http.StatusNoContent, nil)
+}
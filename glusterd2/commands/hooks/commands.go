@@ -0,0 +1,75 @@
+// Package hookcommands implements the commands to register the hooks run at
+// points of the lifecycle of the volumes, to enable and disable them, and to
+// get the history of their runs
+package hookcommands
+
+import (
+	"github.com/gluster/glusterd2/glusterd2/middleware"
+	"github.com/gluster/glusterd2/glusterd2/servers/rest/route"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/utils"
+)
+
+// Command is a holding struct used to implement the GlusterD Command interface
+type Command struct {
+}
+
+// Routes returns command routes. Required for the Command interface.
+func (c *Command) Routes() route.Routes {
+	return route.Routes{
+		route.Route{
+			Name:         "HookList",
+			Method:       "GET",
+			Pattern:      "/hooks",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.HookListResp)(nil)),
+			HandlerFunc:  hookListHandler},
+		route.Route{
+			Name:         "HookCreate",
+			Method:       "POST",
+			Pattern:      "/hooks",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.HookCreateReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.HookResp)(nil)),
+			HandlerFunc:  middleware.RequireAdmin(hookCreateHandler)},
+		route.Route{
+			Name:         "HookGet",
+			Method:       "GET",
+			Pattern:      "/hooks/{name}",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.HookResp)(nil)),
+			HandlerFunc:  hookGetHandler},
+		route.Route{
+			Name:        "HookDelete",
+			Method:      "DELETE",
+			Pattern:     "/hooks/{name}",
+			Version:     1,
+			HandlerFunc: middleware.RequireAdmin(hookDeleteHandler)},
+		route.Route{
+			Name:         "HookEnable",
+			Method:       "POST",
+			Pattern:      "/hooks/{name}/enable",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.HookResp)(nil)),
+			HandlerFunc:  middleware.RequireAdmin(hookSetEnabledHandler(true))},
+		route.Route{
+			Name:         "HookDisable",
+			Method:       "POST",
+			Pattern:      "/hooks/{name}/disable",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.HookResp)(nil)),
+			HandlerFunc:  middleware.RequireAdmin(hookSetEnabledHandler(false))},
+		route.Route{
+			Name:         "HookHistory",
+			Method:       "GET",
+			Pattern:      "/hooks/{name}/history",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.HookHistoryResp)(nil)),
+			HandlerFunc:  hookHistoryHandler},
+	}
+}
+
+// RegisterStepFuncs implements a required function for the Command interface
+func (c *Command) RegisterStepFuncs() {
+	return
+}
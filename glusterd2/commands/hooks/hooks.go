@@ -0,0 +1,114 @@
+package hookcommands
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/hooks"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/gorilla/mux"
+)
+
+func hookListHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	list, err := hooks.List()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, api.HookListResp(list))
+}
+
+func hookCreateHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+
+	var req api.HookCreateReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
+		return
+	}
+
+	h := api.Hook{
+		Name:    req.Name,
+		Point:   req.Point,
+		Script:  req.Script,
+		URL:     req.URL,
+		Timeout: req.Timeout,
+		Enabled: !req.Disabled,
+	}
+	if err := hooks.Validate(&h); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := hooks.Add(&h); err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	logger.WithField("hook", h.Name).WithField("point", h.Point).Info("hook registered")
+	restutils.SendHTTPResponse(ctx, w, http.StatusCreated, api.HookResp(h))
+}
+
+func hookGetHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	h, err := hooks.Get(mux.Vars(r)["name"])
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, api.HookResp(*h))
+}
+
+func hookDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+	name := mux.Vars(r)["name"]
+
+	if err := hooks.Delete(name); err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	logger.WithField("hook", name).Info("hook deleted")
+	restutils.SendHTTPResponse(ctx, w, http.StatusNoContent, nil)
+}
+
+func hookSetEnabledHandler(enabled bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		logger := gdctx.GetReqLogger(ctx)
+		name := mux.Vars(r)["name"]
+
+		h, err := hooks.SetEnabled(name, enabled)
+		if err != nil {
+			status, err := restutils.ErrToStatusCode(err)
+			restutils.SendHTTPError(ctx, w, status, err)
+			return
+		}
+
+		logger.WithField("hook", name).WithField("enabled", enabled).Info("hook updated")
+		restutils.SendHTTPResponse(ctx, w, http.StatusOK, api.HookResp(*h))
+	}
+}
+
+func hookHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	runs, err := hooks.History(mux.Vars(r)["name"])
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, api.HookHistoryResp(runs))
+}
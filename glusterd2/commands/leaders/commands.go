@@ -0,0 +1,44 @@
+// Package leadercommands implements the commands listing the peers leading
+// the singleton services of the cluster, and moving a service to another
+// peer
+package leadercommands
+
+import (
+	"github.com/gluster/glusterd2/glusterd2/middleware"
+	"github.com/gluster/glusterd2/glusterd2/servers/rest/route"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/utils"
+)
+
+// Command is a holding struct used to implement the GlusterD Command interface
+type Command struct {
+}
+
+// Routes returns command routes. Required for the Command interface.
+func (c *Command) Routes() route.Routes {
+	return route.Routes{
+		route.Route{
+			Name:         "LeadersList",
+			Method:       "GET",
+			Pattern:      "/cluster/leaders",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.ListLeadersResp)(nil)),
+			HandlerFunc:  leadersListHandler,
+		},
+		route.Route{
+			Name:         "LeaderFailover",
+			Method:       "POST",
+			Pattern:      "/cluster/leaders/{service}/failover",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.ServiceLeader)(nil)),
+			HandlerFunc:  middleware.RequireAdmin(leaderFailoverHandler),
+		},
+	}
+}
+
+// RegisterStepFuncs registers transaction step functions with
+// the Global Transaction Step Registry
+func (c *Command) RegisterStepFuncs() {
+	transaction.RegisterStepFunc(txnResign, "leader.Resign")
+}
@@ -0,0 +1,91 @@
+package leadercommands
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/leader"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/gorilla/mux"
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// txnResign makes this peer resign from leading the service. The peer may
+// have lost the lead already, which is as good.
+func txnResign(c transaction.TxnCtx) error {
+	var name string
+	if err := c.Get("service", &name); err != nil {
+		return err
+	}
+	err := leader.Resign(name)
+	if err == errors.ErrNotLeader {
+		c.Logger().WithField("service", name).Info("peer no longer leads the service")
+		return nil
+	}
+	return err
+}
+
+func leadersListHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+
+	leaders, err := leader.Leaders(ctx)
+	if err != nil {
+		logger.WithError(err).Error("failed to get the leaders of the services")
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, api.ListLeadersResp(leaders))
+}
+
+func leaderFailoverHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+	name := mux.Vars(r)["service"]
+
+	l, err := leader.Leader(ctx, name)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	peerID := uuid.Parse(l.PeerID)
+	if peerID == nil {
+		restutils.SendHTTPError(ctx, w, http.StatusConflict, errors.ErrNoLeader)
+		return
+	}
+
+	// The leader resigns, and another peer contesting the election takes
+	// over
+	txn := transaction.NewTxn(ctx)
+	defer txn.Done()
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc: "leader.Resign",
+			Nodes:  []uuid.UUID{peerID},
+		},
+	}
+	if err := txn.Ctx.Set("service", name); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	txn.DisableRollback = true
+
+	if err := txn.Do(); err != nil {
+		logger.WithError(err).WithField("service", name).Error("failed to make the leader of the service resign")
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	logger.WithFields(log.Fields{
+		"service": name,
+		"peer":    l.PeerID,
+	}).Info("leader of the service resigned")
+	restutils.SendHTTPResponse(ctx, w, http.StatusAccepted, l)
+}
@@ -0,0 +1,35 @@
+// Package migratecommands implements the commands to import the volumes and
+// snapshots of a glusterd1 installation
+package migratecommands
+
+import (
+	"github.com/gluster/glusterd2/glusterd2/migrate"
+	"github.com/gluster/glusterd2/glusterd2/servers/rest/route"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/utils"
+)
+
+// Command is a holding struct used to implement the GlusterD Command interface
+type Command struct {
+}
+
+// Routes returns command routes. Required for the Command interface.
+func (c *Command) Routes() route.Routes {
+	return route.Routes{
+		route.Route{
+			Name:         "GD1Import",
+			Method:       "POST",
+			Pattern:      "/cluster/import",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.GD1ImportReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.GD1ImportResp)(nil)),
+			HandlerFunc:  gd1ImportHandler,
+		},
+	}
+}
+
+// RegisterStepFuncs registers transaction step functions with
+// the Global Transaction Step Registry
+func (c *Command) RegisterStepFuncs() {
+	migrate.RegisterStepFuncs()
+}
@@ -0,0 +1,253 @@
+package migratecommands
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/migrate"
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/snapshot"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/pborman/uuid"
+)
+
+// resolvePeer returns the glusterd2 peer known by any of the hostnames of a
+// glusterd1 peer, either as its name or as one of its addresses
+func resolvePeer(hostnames []string) (uuid.UUID, error) {
+	if p, err := peer.GetPeerByAddrs(hostnames); err == nil {
+		return p.ID, nil
+	}
+	for _, h := range hostnames {
+		if p, err := peer.GetPeerByName(h); err == nil {
+			return p.ID, nil
+		}
+	}
+	return nil, errors.ErrPeerNotFound
+}
+
+// selectVolumes returns the glusterd1 volumes to import and the snapshots
+// taken of them
+func selectVolumes(st *migrate.GD1State, names []string) ([]*migrate.GD1Volume, []*migrate.GD1Snapshot, error) {
+	volumes := st.Volumes
+	if len(names) > 0 {
+		byName := make(map[string]*migrate.GD1Volume)
+		for _, v := range st.Volumes {
+			byName[v.Name] = v
+		}
+		volumes = nil
+		for _, name := range names {
+			v, ok := byName[name]
+			if !ok {
+				return nil, nil, fmt.Errorf("%s: %s", errors.ErrVolNotFound, name)
+			}
+			volumes = append(volumes, v)
+		}
+	}
+
+	selected := make(map[string]bool)
+	for _, v := range volumes {
+		selected[v.Name] = true
+	}
+	var snaps []*migrate.GD1Snapshot
+	for _, s := range st.Snapshots {
+		if selected[s.ParentVolume()] {
+			snaps = append(snaps, s)
+		}
+	}
+	return volumes, snaps, nil
+}
+
+func gd1ImportHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+
+	var req api.GD1ImportReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrJSONParsingFailed)
+		return
+	}
+	if req.Path == "" {
+		req.Path = api.DefaultGD1WorkDir
+	}
+
+	st, err := migrate.ReadGD1State(req.Path)
+	if err != nil {
+		logger.WithError(err).WithField("path", req.Path).Error("failed to read glusterd1 state")
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, fmt.Errorf("%s: %s", errors.ErrInvalidGD1State, err))
+		return
+	}
+
+	gd1Volumes, gd1Snaps, err := selectVolumes(st, req.Volumes)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusNotFound, err)
+		return
+	}
+	if len(gd1Volumes) == 0 {
+		restutils.SendHTTPError(ctx, w, http.StatusNotFound, errors.ErrVolNotFound)
+		return
+	}
+
+	lockIDs := make([]string, 0, len(gd1Volumes))
+	for _, v := range gd1Volumes {
+		lockIDs = append(lockIDs, v.Name)
+	}
+	txn, err := transaction.NewTxnWithLocks(ctx, lockIDs...)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	defer txn.Done()
+
+	for _, v := range gd1Volumes {
+		if volume.Exists(v.Name) {
+			restutils.SendHTTPError(ctx, w, http.StatusConflict, fmt.Errorf("%s: %s", errors.ErrVolExists, v.Name))
+			return
+		}
+	}
+	for _, s := range gd1Snaps {
+		if snapshot.Exists(s.Name) {
+			restutils.SendHTTPError(ctx, w, http.StatusConflict, fmt.Errorf("%s: %s", errors.ErrSnapExists, s.Name))
+			return
+		}
+	}
+
+	allVolumes := make([]*migrate.GD1Volume, 0, len(gd1Volumes)+len(gd1Snaps))
+	allVolumes = append(allVolumes, gd1Volumes...)
+	for _, s := range gd1Snaps {
+		allVolumes = append(allVolumes, s.Volume)
+	}
+	peerMap := st.MapPeers(allVolumes, gdctx.MyUUID, resolvePeer)
+
+	resp := api.GD1ImportResp{
+		DryRun:   req.DryRun,
+		Volumes:  []api.GD1ImportedVolume{},
+		Peers:    peerMap.IDs,
+		Warnings: peerMap.Unmapped,
+	}
+
+	// Convert everything before storing anything, reporting all the
+	// problems at once
+	var problems []string
+	volinfos := make(map[string]*volume.Volinfo)
+	var volumes []*volume.Volinfo
+	for _, v := range gd1Volumes {
+		volinfo, skipped, err := v.ToVolinfo(peerMap)
+		if err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+		volinfos[v.Name] = volinfo
+		volumes = append(volumes, volinfo)
+		resp.Volumes = append(resp.Volumes, api.GD1ImportedVolume{
+			Name:           volinfo.Name,
+			ID:             volinfo.ID,
+			Type:           volinfo.Type.String(),
+			WasStarted:     v.WasStarted(),
+			SkippedOptions: skipped,
+		})
+	}
+
+	var snaps []*snapshot.Snapinfo
+	for _, s := range gd1Snaps {
+		snapinfo, _, err := s.ToSnapinfo(peerMap)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("snapshot %s: %s", s.Name, err))
+			continue
+		}
+		parent, ok := volinfos[snapinfo.ParentVolume]
+		if !ok {
+			continue
+		}
+		parent.SnapList = append(parent.SnapList, snapinfo.SnapVolinfo.Name)
+		snaps = append(snaps, snapinfo)
+	}
+	for i := range resp.Volumes {
+		resp.Volumes[i].Snapshots = volinfos[resp.Volumes[i].Name].SnapList
+	}
+
+	if req.DryRun {
+		resp.Warnings = append(resp.Warnings, problems...)
+		restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+		return
+	}
+	if len(problems) > 0 {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest,
+			fmt.Errorf("%s: %s", errors.ErrInvalidGD1State, strings.Join(problems, "; ")))
+		return
+	}
+
+	if err := storeImported(volumes, snaps); err != nil {
+		logger.WithError(err).Error("failed to store the imported volumes")
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	var nodes []uuid.UUID
+	seen := make(map[string]bool)
+	for _, v := range volumes {
+		for _, node := range v.Nodes() {
+			if !seen[node.String()] {
+				seen[node.String()] = true
+				nodes = append(nodes, node)
+			}
+		}
+	}
+
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc:   "migrate.GenerateBrickVolfiles",
+			UndoFunc: "migrate.DeleteBrickVolfiles",
+			Nodes:    nodes,
+		},
+	}
+	if err := txn.Ctx.Set("volumes", lockIDs); err != nil {
+		removeImported(volumes, snaps)
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	if err := txn.Do(); err != nil {
+		logger.WithError(err).Error("failed to generate the volfiles of the imported volumes")
+		removeImported(volumes, snaps)
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	logger.WithField("volumes", strings.Join(lockIDs, ",")).Info("imported glusterd1 volumes")
+	restutils.SendHTTPResponse(ctx, w, http.StatusCreated, resp)
+}
+
+// storeImported adds the imported volumes and snapshots to the store
+func storeImported(volumes []*volume.Volinfo, snaps []*snapshot.Snapinfo) error {
+	for _, v := range volumes {
+		if err := volume.AddOrUpdateVolume(v); err != nil {
+			removeImported(volumes, nil)
+			return err
+		}
+	}
+	for _, s := range snaps {
+		if err := snapshot.AddOrUpdateSnap(s); err != nil {
+			removeImported(volumes, snaps)
+			return err
+		}
+	}
+	return nil
+}
+
+// removeImported removes the imported volumes and snapshots from the store
+func removeImported(volumes []*volume.Volinfo, snaps []*snapshot.Snapinfo) {
+	for _, s := range snaps {
+		snapshot.DeleteSnapshot(s)
+	}
+	for _, v := range volumes {
+		volume.DeleteVolume(v.Name)
+	}
+}
@@ -0,0 +1,60 @@
+// Package mountcommands implements the commands to create, list and remove
+// the FUSE mounts of the volumes managed by glusterd2 on the peers
+package mountcommands
+
+import (
+	"github.com/gluster/glusterd2/glusterd2/middleware"
+	"github.com/gluster/glusterd2/glusterd2/servers/rest/route"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/utils"
+)
+
+// Command is a holding struct used to implement the GlusterD Command interface
+type Command struct {
+}
+
+// Routes returns command routes. Required for the Command interface.
+func (c *Command) Routes() route.Routes {
+	return route.Routes{
+		route.Route{
+			Name:         "MountList",
+			Method:       "GET",
+			Pattern:      "/peers/{peerid}/mounts",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.MountListResp)(nil)),
+			HandlerFunc:  mountListHandler},
+		route.Route{
+			Name:         "MountCreate",
+			Method:       "POST",
+			Pattern:      "/peers/{peerid}/mounts",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.MountCreateReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.MountResp)(nil)),
+			HandlerFunc:  middleware.RequireAdmin(mountCreateHandler)},
+		route.Route{
+			Name:        "MountDelete",
+			Method:      "DELETE",
+			Pattern:     "/peers/{peerid}/mounts/{mountid}",
+			Version:     1,
+			HandlerFunc: middleware.RequireAdmin(mountDeleteHandler)},
+	}
+}
+
+// RegisterStepFuncs registers transaction step functions with
+// Glusterd Transaction framework
+func (c *Command) RegisterStepFuncs() {
+	var sfs = []struct {
+		name string
+		sf   transaction.StepFunc
+	}{
+		{"mount-create.Mount", txnMount},
+		{"mount-create.Unmount", txnUnmount},
+		{"mount-create.Save", txnSave},
+		{"mount-delete.Unmount", txnUnmount},
+		{"mount-list.Status", txnStatus},
+	}
+	for _, sf := range sfs {
+		transaction.RegisterStepFunc(sf.sf, sf.name)
+	}
+}
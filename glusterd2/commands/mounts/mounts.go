@@ -0,0 +1,294 @@
+package mountcommands
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/mounts"
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	transactionv2 "github.com/gluster/glusterd2/glusterd2/transactionv2"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/gorilla/mux"
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// lockPrefix locks out other changes of the mounts of a peer
+	lockPrefix = "mounts-"
+
+	mountStatusTxnKey = "mountstatuses"
+)
+
+func txnMount(c transaction.TxnCtx) error {
+	var m api.Mount
+	if err := c.Get("mount", &m); err != nil {
+		return err
+	}
+	if err := mounts.Mount(&m); err != nil {
+		c.Logger().WithError(err).WithField("path", m.Path).Error("failed to mount volume")
+		return err
+	}
+	return nil
+}
+
+func txnUnmount(c transaction.TxnCtx) error {
+	var m api.Mount
+	if err := c.Get("mount", &m); err != nil {
+		return err
+	}
+	if err := mounts.Unmount(&m); err != nil {
+		c.Logger().WithError(err).WithField("path", m.Path).Error("failed to unmount volume")
+		return err
+	}
+	return nil
+}
+
+func txnSave(c transaction.TxnCtx) error {
+	var m api.Mount
+	if err := c.Get("mount", &m); err != nil {
+		return err
+	}
+	return mounts.Save(&m)
+}
+
+func txnStatus(c transaction.TxnCtx) error {
+	list, err := mounts.List(gdctx.MyUUID)
+	if err != nil {
+		return err
+	}
+
+	statuses := make(map[string]api.MountStatus, len(list))
+	for _, m := range list {
+		online, pid := mounts.Status(&m)
+		statuses[m.ID.String()] = api.MountStatus{Mount: m, Online: online, PID: pid}
+	}
+	return c.SetNodeResult(gdctx.MyUUID, mountStatusTxnKey, statuses)
+}
+
+// peerFromRequest returns the ID of the peer of the request, sending an
+// error if the peer does not exist
+func peerFromRequest(w http.ResponseWriter, r *http.Request) uuid.UUID {
+	ctx := r.Context()
+	peerID := uuid.Parse(mux.Vars(r)["peerid"])
+	if peerID == nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "invalid peer id")
+		return nil
+	}
+	if _, err := peer.GetPeer(peerID.String()); err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return nil
+	}
+	return peerID
+}
+
+func mountCreateHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+
+	peerID := peerFromRequest(w, r)
+	if peerID == nil {
+		return
+	}
+
+	var req api.MountCreateReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
+		return
+	}
+
+	m := api.Mount{
+		ID:      uuid.NewRandom(),
+		PeerID:  peerID,
+		Volume:  req.Volume,
+		Path:    req.Path,
+		Options: req.Options,
+		Owner:   req.Owner,
+		Created: time.Now(),
+	}
+	if err := mounts.Validate(&m); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	txn, err := transactionv2.NewTxnWithLocks(ctx, lockPrefix+peerID.String(), m.Volume)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	defer txn.Done()
+
+	volinfo, err := volume.GetVolume(m.Volume)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	if volinfo.State != volume.VolStarted {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrVolNotStarted)
+		return
+	}
+
+	existing, err := mounts.List(peerID)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	for _, other := range existing {
+		if other.Path == m.Path {
+			restutils.SendHTTPError(ctx, w, http.StatusConflict, gderrors.ErrMountExists)
+			return
+		}
+	}
+
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc:   "mount-create.Mount",
+			UndoFunc: "mount-create.Unmount",
+			Nodes:    []uuid.UUID{peerID},
+		},
+		{
+			DoFunc: "mount-create.Save",
+			Nodes:  []uuid.UUID{gdctx.MyUUID},
+			Sync:   true,
+		},
+	}
+	if err := txn.Ctx.Set("mount", &m); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if err := txn.Do(); err != nil {
+		logger.WithError(err).WithFields(log.Fields{
+			"volume": m.Volume,
+			"path":   m.Path,
+		}).Error("transaction to mount volume failed")
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	logger.WithFields(log.Fields{
+		"peerid": peerID.String(),
+		"volume": m.Volume,
+		"path":   m.Path,
+	}).Info("volume mounted")
+	restutils.SendHTTPResponse(ctx, w, http.StatusCreated, api.MountResp(m))
+}
+
+func mountListHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+
+	peerID := peerFromRequest(w, r)
+	if peerID == nil {
+		return
+	}
+
+	list, err := mounts.List(peerID)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	resp := make(api.MountListResp, 0, len(list))
+	if len(list) == 0 {
+		restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+		return
+	}
+
+	txn := transaction.NewTxn(ctx)
+	defer txn.Done()
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc: "mount-list.Status",
+			Nodes:  []uuid.UUID{peerID},
+		},
+	}
+	// The mounts of a peer which is down are listed offline
+	txn.DontCheckAlive = true
+	txn.DisableRollback = true
+
+	statuses := make(map[string]api.MountStatus)
+	if err := txn.Do(); err != nil {
+		logger.WithError(err).WithField("peerid", peerID.String()).Warn("failed to get the status of the mounts of the peer")
+	} else if err := txn.Ctx.GetNodeResult(peerID, mountStatusTxnKey, &statuses); err != nil {
+		logger.WithError(err).WithField("peerid", peerID.String()).Warn("failed to get the status of the mounts of the peer")
+	}
+
+	for _, m := range list {
+		st, ok := statuses[m.ID.String()]
+		if !ok {
+			st = api.MountStatus{Mount: m}
+		}
+		resp = append(resp, st)
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+func mountDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+
+	peerID := peerFromRequest(w, r)
+	if peerID == nil {
+		return
+	}
+	id := uuid.Parse(mux.Vars(r)["mountid"])
+	if id == nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "invalid mount id")
+		return
+	}
+
+	txn, err := transactionv2.NewTxnWithLocks(ctx, lockPrefix+peerID.String())
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	defer txn.Done()
+
+	m, err := mounts.Get(peerID, id)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc: "mount-delete.Unmount",
+			Nodes:  []uuid.UUID{peerID},
+		},
+	}
+	if err := txn.Ctx.Set("mount", m); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	if err := txn.Do(); err != nil {
+		logger.WithError(err).WithField("path", m.Path).Error("transaction to unmount volume failed")
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	if err := mounts.Delete(peerID, id); err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	logger.WithFields(log.Fields{
+		"peerid": peerID.String(),
+		"volume": m.Volume,
+		"path":   m.Path,
+	}).Info("volume unmounted")
+	restutils.SendHTTPResponse(ctx, w, http.StatusNoContent, nil)
+}
@@ -0,0 +1,81 @@
+// Package namespacecommands implements the commands to manage the namespaces
+// the volumes belong to, their quotas and their API tokens
+package namespacecommands
+
+import (
+	"github.com/gluster/glusterd2/glusterd2/middleware"
+	"github.com/gluster/glusterd2/glusterd2/servers/rest/route"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/utils"
+)
+
+// Command is a holding struct used to implement the GlusterD Command interface
+type Command struct {
+}
+
+// Routes returns command routes. Required for the Command interface.
+func (c *Command) Routes() route.Routes {
+	return route.Routes{
+		route.Route{
+			Name:         "NamespaceCreate",
+			Method:       "POST",
+			Pattern:      "/namespaces",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.NamespaceCreateReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.NamespaceResp)(nil)),
+			HandlerFunc:  middleware.RequireAdmin(namespaceCreateHandler)},
+		route.Route{
+			Name:         "NamespaceList",
+			Method:       "GET",
+			Pattern:      "/namespaces",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.NamespaceListResp)(nil)),
+			HandlerFunc:  middleware.RequireAdmin(namespaceListHandler)},
+		route.Route{
+			Name:         "NamespaceGet",
+			Method:       "GET",
+			Pattern:      "/namespaces/{name}",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.NamespaceResp)(nil)),
+			HandlerFunc:  middleware.RequireAdmin(namespaceGetHandler)},
+		route.Route{
+			Name:         "NamespaceEdit",
+			Method:       "POST",
+			Pattern:      "/namespaces/{name}/edit",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.NamespaceEditReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.NamespaceResp)(nil)),
+			HandlerFunc:  middleware.RequireAdmin(namespaceEditHandler)},
+		route.Route{
+			Name:        "NamespaceDelete",
+			Method:      "DELETE",
+			Pattern:     "/namespaces/{name}",
+			Version:     1,
+			HandlerFunc: middleware.RequireAdmin(namespaceDeleteHandler)},
+		route.Route{
+			Name:         "NamespaceTokenCreate",
+			Method:       "POST",
+			Pattern:      "/namespaces/{name}/tokens",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.NamespaceToken)(nil)),
+			HandlerFunc:  middleware.RequireAdmin(namespaceTokenCreateHandler)},
+		route.Route{
+			Name:         "NamespaceTokenList",
+			Method:       "GET",
+			Pattern:      "/namespaces/{name}/tokens",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.NamespaceTokenListResp)(nil)),
+			HandlerFunc:  middleware.RequireAdmin(namespaceTokenListHandler)},
+		route.Route{
+			Name:        "NamespaceTokenDelete",
+			Method:      "DELETE",
+			Pattern:     "/namespaces/{name}/tokens/{tokenid}",
+			Version:     1,
+			HandlerFunc: middleware.RequireAdmin(namespaceTokenDeleteHandler)},
+	}
+}
+
+// RegisterStepFuncs implements a required function for the Command interface
+func (c *Command) RegisterStepFuncs() {
+	return
+}
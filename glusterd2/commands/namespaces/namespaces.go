@@ -0,0 +1,192 @@
+package namespacecommands
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/namespace"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/gorilla/mux"
+)
+
+func namespaceResp(ns *api.Namespace) (*api.NamespaceResp, error) {
+	usage, err := namespace.GetUsage(ns.Name)
+	if err != nil {
+		return nil, err
+	}
+	return &api.NamespaceResp{Namespace: *ns, Usage: *usage}, nil
+}
+
+func namespaceCreateHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+
+	var req api.NamespaceCreateReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
+		return
+	}
+
+	ns := api.Namespace{
+		Name:        req.Name,
+		MaxVolumes:  req.MaxVolumes,
+		MaxCapacity: req.MaxCapacity,
+		CreatedAt:   time.Now(),
+	}
+	if err := namespace.Validate(&ns); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := namespace.Add(&ns); err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	logger.WithField("namespace", ns.Name).Info("namespace created")
+	restutils.SendHTTPResponse(ctx, w, http.StatusCreated, api.NamespaceResp{Namespace: ns})
+}
+
+func namespaceListHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	list, err := namespace.List()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	resp := make(api.NamespaceListResp, 0, len(list))
+	for i := range list {
+		ns, err := namespaceResp(&list[i])
+		if err != nil {
+			restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+			return
+		}
+		resp = append(resp, *ns)
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+func namespaceGetHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ns, err := namespace.Get(mux.Vars(r)["name"])
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	resp, err := namespaceResp(ns)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+func namespaceEditHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+	name := mux.Vars(r)["name"]
+
+	var req api.NamespaceEditReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
+		return
+	}
+	if req.MaxVolumes < 0 {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "max-volumes must not be negative")
+		return
+	}
+
+	ns, err := namespace.SetQuotas(name, req.MaxVolumes, req.MaxCapacity)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	resp, err := namespaceResp(ns)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	logger.WithField("namespace", name).WithField("max-volumes", req.MaxVolumes).
+		WithField("max-capacity", req.MaxCapacity).Info("namespace quotas changed")
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+func namespaceDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+	name := mux.Vars(r)["name"]
+
+	// Volumes cannot be provisioned in the namespace while it is deleted
+	txn, err := transaction.NewTxnWithLocks(ctx, namespace.LockID(name))
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	defer txn.Done()
+
+	if err := namespace.Delete(name); err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	logger.WithField("namespace", name).Info("namespace deleted")
+	restutils.SendHTTPResponse(ctx, w, http.StatusNoContent, nil)
+}
+
+func namespaceTokenCreateHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+	name := mux.Vars(r)["name"]
+
+	t, err := namespace.CreateToken(name)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	logger.WithField("namespace", name).WithField("token", t.ID).Info("namespace token created")
+	restutils.SendHTTPResponse(ctx, w, http.StatusCreated, t)
+}
+
+func namespaceTokenListHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	list, err := namespace.ListTokens(mux.Vars(r)["name"])
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, api.NamespaceTokenListResp(list))
+}
+
+func namespaceTokenDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+	name := mux.Vars(r)["name"]
+	id := mux.Vars(r)["tokenid"]
+
+	if err := namespace.DeleteToken(name, id); err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	logger.WithField("namespace", name).WithField("token", id).Info("namespace token revoked")
+	restutils.SendHTTPResponse(ctx, w, http.StatusNoContent, nil)
+}
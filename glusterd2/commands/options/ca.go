@@ -0,0 +1,142 @@
+package optionscommands
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/ca"
+	"github.com/gluster/glusterd2/glusterd2/datatls"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/pborman/uuid"
+)
+
+func peerIDs() ([]uuid.UUID, error) {
+	peers, err := peer.GetPeersF()
+	if err != nil {
+		return nil, err
+	}
+	nodes := make([]uuid.UUID, 0, len(peers))
+	for _, p := range peers {
+		nodes = append(nodes, p.ID)
+	}
+	return nodes, nil
+}
+
+// caStatus returns the state of the CA, with the certificates of the peers
+// reported by the nodes in the transaction
+func caStatus(c transaction.TxnCtx, nodes []uuid.UUID) (*api.CAStatusResp, error) {
+	mode, err := ca.Mode()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &api.CAStatusResp{
+		Mode:  mode,
+		Nodes: datatls.NodeStatuses(c, nodes),
+	}
+	if mode == ca.ModeBuiltin {
+		// The built-in CA is created on issuing the first certificate
+		if cert, certPEM, err := ca.CACert(); err == nil {
+			resp.CA = certPEM
+			resp.Subject = cert.Subject.String()
+			resp.NotAfter = cert.NotAfter
+		}
+	}
+	return resp, nil
+}
+
+func getCAHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+
+	nodes, err := peerIDs()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	txn := transaction.NewTxn(ctx)
+	defer txn.Done()
+	txn.Steps = []*transaction.Step{datatls.StatusStep(nodes)}
+
+	// Some nodes may not be up, which is okay.
+	txn.DontCheckAlive = true
+	txn.DisableRollback = true
+
+	if err := txn.Do(); err != nil {
+		logger.WithError(err).Error("failed to get the certificates of the peers")
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	resp, err := caStatus(txn.Ctx, nodes)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+// renewCerts has the nodes renew their certificates, and report them
+func renewCerts(ctx context.Context, force bool, nodes []uuid.UUID) (*api.CAStatusResp, error) {
+	txn := transaction.NewTxn(ctx)
+	defer txn.Done()
+
+	renew, err := ca.RenewStep(txn.Ctx, force, nodes)
+	if err != nil {
+		return nil, err
+	}
+	txn.Steps = []*transaction.Step{
+		renew,
+		datatls.StatusStep(nodes),
+	}
+	txn.DisableRollback = true
+
+	if err := txn.Do(); err != nil {
+		return nil, err
+	}
+	return caStatus(txn.Ctx, nodes)
+}
+
+func renewCAHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+
+	var req api.CARenewReq
+	// request body is optional
+	if err := restutils.UnmarshalRequest(r, &req); err != nil && err != io.EOF {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrJSONParsingFailed)
+		return
+	}
+
+	mode, err := ca.Mode()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	if mode == ca.ModeOff {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrCADisabled)
+		return
+	}
+
+	nodes, err := peerIDs()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	resp, err := renewCerts(ctx, req.Force, nodes)
+	if err != nil {
+		logger.WithError(err).Error("failed to renew the certificates of the peers")
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
@@ -2,7 +2,15 @@
 package optionscommands
 
 import (
+	"github.com/gluster/glusterd2/glusterd2/ca"
+	"github.com/gluster/glusterd2/glusterd2/drift"
+	"github.com/gluster/glusterd2/glusterd2/middleware"
+	"github.com/gluster/glusterd2/glusterd2/opversion"
+	"github.com/gluster/glusterd2/glusterd2/orphanbricks"
 	"github.com/gluster/glusterd2/glusterd2/servers/rest/route"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/utils"
 )
 
 // Command is a holding struct used to implement the GlusterD Command interface
@@ -19,6 +27,14 @@ func (c *Command) Routes() route.Routes {
 			Version:     1,
 			HandlerFunc: setClusterOptionsHandler,
 		},
+		route.Route{
+			Name:        "UpdateClusterOptions",
+			Method:      "PUT",
+			Pattern:     "/cluster/options",
+			Version:     1,
+			RequestType: utils.GetTypeString((*api.ClusterOptionReq)(nil)),
+			HandlerFunc: setClusterOptionsHandler,
+		},
 		route.Route{
 			Name:        "GetClusterOptions",
 			Method:      "GET",
@@ -26,10 +42,169 @@ func (c *Command) Routes() route.Routes {
 			Version:     1,
 			HandlerFunc: getClusterOptionsHandler,
 		},
+		route.Route{
+			Name:         "GetClusterOpVersion",
+			Method:       "GET",
+			Pattern:      "/cluster/op-version",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.OpVersionResp)(nil)),
+			HandlerFunc:  getOpVersionHandler,
+		},
+		route.Route{
+			Name:         "BumpClusterOpVersion",
+			Method:       "POST",
+			Pattern:      "/cluster/op-version",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.OpVersionBumpReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.OpVersionResp)(nil)),
+			HandlerFunc:  bumpOpVersionHandler,
+		},
+		route.Route{
+			Name:         "GetClusterQuorum",
+			Method:       "GET",
+			Pattern:      "/cluster/quorum",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.QuorumStatus)(nil)),
+			HandlerFunc:  getQuorumHandler,
+		},
+		route.Route{
+			Name:         "GetClusterTLSCA",
+			Method:       "GET",
+			Pattern:      "/cluster/tls/ca",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.TLSCAResp)(nil)),
+			HandlerFunc:  getTLSCAHandler,
+		},
+		route.Route{
+			Name:         "SetClusterTLSCA",
+			Method:       "PUT",
+			Pattern:      "/cluster/tls/ca",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.TLSCAReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.TLSCAResp)(nil)),
+			HandlerFunc:  setTLSCAHandler,
+		},
+		route.Route{
+			Name:         "GetClusterCA",
+			Method:       "GET",
+			Pattern:      "/cluster/ca",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.CAStatusResp)(nil)),
+			HandlerFunc:  getCAHandler,
+		},
+		route.Route{
+			Name:         "RenewClusterCerts",
+			Method:       "POST",
+			Pattern:      "/cluster/ca/renew",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.CARenewReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.CAStatusResp)(nil)),
+			HandlerFunc:  renewCAHandler,
+		},
+		route.Route{
+			Name:         "ListConfigSnapshots",
+			Method:       "GET",
+			Pattern:      "/cluster/config-snapshots",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.ConfigSnapshotListResp)(nil)),
+			HandlerFunc:  listConfigSnapshotsHandler,
+		},
+		route.Route{
+			Name:         "TakeConfigSnapshot",
+			Method:       "POST",
+			Pattern:      "/cluster/config-snapshots",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.ConfigSnapshotResp)(nil)),
+			HandlerFunc:  takeConfigSnapshotHandler,
+		},
+		route.Route{
+			Name:         "GetConfigSnapshot",
+			Method:       "GET",
+			Pattern:      "/cluster/config-snapshots/{id}",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.ConfigSnapshotResp)(nil)),
+			HandlerFunc:  getConfigSnapshotHandler,
+		},
+		route.Route{
+			Name:        "DeleteConfigSnapshot",
+			Method:      "DELETE",
+			Pattern:     "/cluster/config-snapshots/{id}",
+			Version:     1,
+			HandlerFunc: deleteConfigSnapshotHandler,
+		},
+		route.Route{
+			Name:         "DiffConfigSnapshot",
+			Method:       "GET",
+			Pattern:      "/cluster/config-snapshots/{id}/diff",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.ConfigDiffResp)(nil)),
+			HandlerFunc:  diffConfigSnapshotHandler,
+		},
+		route.Route{
+			Name:         "ListClusterLocks",
+			Method:       "GET",
+			Pattern:      "/cluster/locks",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.LockListResp)(nil)),
+			HandlerFunc:  listLocksHandler,
+		},
+		route.Route{
+			Name:         "ForceReleaseClusterLock",
+			Method:       "DELETE",
+			Pattern:      "/cluster/locks/{lockid:.*}",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.LockInfo)(nil)),
+			HandlerFunc:  middleware.RequireAdmin(forceUnlockHandler),
+		},
+		route.Route{
+			Name:         "ListOrphanBricks",
+			Method:       "GET",
+			Pattern:      "/cluster/orphan-bricks",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.OrphanBrickListResp)(nil)),
+			HandlerFunc:  listOrphanBricksHandler,
+		},
+		route.Route{
+			Name:         "CleanupOrphanBricks",
+			Method:       "DELETE",
+			Pattern:      "/cluster/orphan-bricks",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.OrphanBrickCleanupResp)(nil)),
+			HandlerFunc:  middleware.RequireAdmin(cleanupOrphanBricksHandler),
+		},
+		route.Route{
+			Name:         "ListDrift",
+			Method:       "GET",
+			Pattern:      "/cluster/drift",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.DriftListResp)(nil)),
+			HandlerFunc:  listDriftHandler,
+		},
+		route.Route{
+			Name:         "ReconcileDrift",
+			Method:       "POST",
+			Pattern:      "/cluster/drift/reconcile",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.DriftReconcileResp)(nil)),
+			HandlerFunc:  middleware.RequireAdmin(reconcileDriftHandler),
+		},
+		route.Route{
+			Name:         "ClusterTopology",
+			Method:       "GET",
+			Pattern:      "/cluster/topology",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.ClusterTopologyResp)(nil)),
+			HandlerFunc:  clusterTopologyHandler,
+		},
 	}
 }
 
 // RegisterStepFuncs implements a required function for the Command interface
 func (c *Command) RegisterStepFuncs() {
-	return
+	transaction.RegisterStepFunc(txnGenerateVolfiles, "cluster-options.GenerateVolfiles")
+	transaction.RegisterStepFunc(txnApplyCgroupLimits, "cluster-options.ApplyCgroupLimits")
+	opversion.RegisterStepFuncs()
+	ca.RegisterStepFuncs()
+	orphanbricks.RegisterStepFuncs()
+	drift.RegisterStepFuncs()
 }
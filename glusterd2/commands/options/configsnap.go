@@ -0,0 +1,132 @@
+package optionscommands
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gluster/glusterd2/glusterd2/configsnap"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/gorilla/mux"
+)
+
+func createConfigSnapshot(s *configsnap.Snapshot) api.ConfigSnapshot {
+	return api.ConfigSnapshot{
+		ID:        s.ID,
+		CreatedAt: s.CreatedAt,
+		Trigger:   s.Trigger,
+		Volumes:   s.Volumes(),
+	}
+}
+
+// getConfigSnapshot returns the snapshot with the ID in the request path
+func getConfigSnapshot(r *http.Request) (*configsnap.Snapshot, int, error) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	s, err := configsnap.Get(id)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		return nil, status, err
+	}
+	return s, http.StatusOK, nil
+}
+
+func listConfigSnapshotsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	snapshots, err := configsnap.List()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	resp := make(api.ConfigSnapshotListResp, 0, len(snapshots))
+	for _, s := range snapshots {
+		resp = append(resp, createConfigSnapshot(s))
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+func takeConfigSnapshotHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+
+	s, err := configsnap.Take(configsnap.TriggerManual)
+	if err != nil {
+		logger.WithError(err).Error("failed to take configuration snapshot")
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	resp := api.ConfigSnapshotResp{ConfigSnapshot: createConfigSnapshot(s), Config: s.Config}
+	restutils.SendHTTPResponse(ctx, w, http.StatusCreated, resp)
+}
+
+func getConfigSnapshotHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	s, status, err := getConfigSnapshot(r)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	resp := api.ConfigSnapshotResp{ConfigSnapshot: createConfigSnapshot(s), Config: s.Config}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+func deleteConfigSnapshotHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+	if err := configsnap.Delete(id); err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusNoContent, nil)
+}
+
+// diffConfigSnapshotHandler compares the snapshot with the one given as
+// "to", or with the current configuration. Only the paths with the prefix
+// given as "prefix" are compared, if given.
+func diffConfigSnapshotHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	from, status, err := getConfigSnapshot(r)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	resp := api.ConfigDiffResp{From: from.ID}
+	var to map[string]string
+	if v := r.URL.Query().Get("to"); v != "" {
+		if resp.To, err = strconv.ParseInt(v, 10, 64); err != nil {
+			restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+		s, err := configsnap.Get(resp.To)
+		if err != nil {
+			status, err := restutils.ErrToStatusCode(err)
+			restutils.SendHTTPError(ctx, w, status, err)
+			return
+		}
+		to = s.Config
+	} else if to, _, err = configsnap.Capture(); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	resp.Changes = configsnap.Diff(from.Config, to, r.URL.Query().Get("prefix"))
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
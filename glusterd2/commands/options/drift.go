@@ -0,0 +1,56 @@
+package optionscommands
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/drift"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+func listDriftHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	drifts, err := drift.List()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, api.DriftListResp(drifts))
+}
+
+// reconcileDriftHandler has the peers which are up correct the drifts of
+// their volumes
+func reconcileDriftHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+
+	var nodes []uuid.UUID
+	for id := range store.Store.GetAliveNodes(ctx) {
+		nodes = append(nodes, uuid.Parse(id))
+	}
+
+	txn := transaction.NewTxn(ctx)
+	defer txn.Done()
+	txn.Steps = []*transaction.Step{drift.ReconcileStep(nodes)}
+	txn.DisableRollback = true
+
+	if err := txn.Do(); err != nil {
+		logger.WithError(err).Error("failed to correct the drifts of the volumes")
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	corrected := drift.Corrected(txn.Ctx, nodes)
+	logger.WithFields(log.Fields{
+		"corrected": len(corrected),
+		"user":      gdctx.GetReqUser(ctx),
+	}).Info("corrected the drifts of the volumes")
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, api.DriftReconcileResp(corrected))
+}
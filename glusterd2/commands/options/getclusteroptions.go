@@ -5,6 +5,7 @@ import (
 
 	"github.com/gluster/glusterd2/glusterd2/options"
 	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/xlator"
 	"github.com/gluster/glusterd2/pkg/api"
 	"github.com/gluster/glusterd2/pkg/errors"
 )
@@ -46,5 +47,23 @@ func createClusterOptionsResp(c *options.ClusterOptions) []api.ClusterOptionsRes
 		})
 	}
 
+	if c == nil {
+		return resp
+	}
+
+	// Default volume options set at the cluster level
+	for k, v := range c.VolumeDefaults() {
+		var defaultValue string
+		if opt, err := xlator.FindOption(k); err == nil {
+			defaultValue = opt.DefaultValue
+		}
+		resp = append(resp, api.ClusterOptionsResp{
+			Key:          k,
+			Value:        v,
+			DefaultValue: defaultValue,
+			Modified:     true,
+		})
+	}
+
 	return resp
 }
@@ -0,0 +1,86 @@
+package optionscommands
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/events"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/gorilla/mux"
+	log "github.com/sirupsen/logrus"
+)
+
+// eventLockForceReleased is broadcast when a lock is force released, for
+// the release to be audited
+const eventLockForceReleased = "lock_force_released"
+
+func createLockInfo(l *transaction.LockInfo, now time.Time) api.LockInfo {
+	info := api.LockInfo{
+		ID:      l.ID,
+		PeerID:  l.PeerID,
+		TxnID:   l.TxnID,
+		ReqID:   l.ReqID,
+		Lease:   fmt.Sprintf("%x", int64(l.Lease)),
+		Since:   l.Since,
+		Waiters: l.Waiters,
+	}
+	if !l.Since.IsZero() {
+		info.Age = int64(now.Sub(l.Since) / time.Second)
+	}
+	return info
+}
+
+func listLocksHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	locks, err := transaction.ListLocks(ctx)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	now := time.Now()
+	resp := make(api.LockListResp, 0, len(locks))
+	for _, l := range locks {
+		resp = append(resp, createLockInfo(l, now))
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+// forceUnlockHandler releases a stale lock, whichever peer holds it. The
+// release is logged and broadcast as an event.
+func forceUnlockHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+	lockID := mux.Vars(r)["lockid"]
+
+	l, err := transaction.ForceUnlock(ctx, lockID)
+	if err != nil {
+		logger.WithError(err).WithField("lockID", lockID).Error("failed to force release lock")
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	info := createLockInfo(l, time.Now())
+	logger.WithFields(log.Fields{
+		"lockID": info.ID,
+		"holder": info.PeerID.String(),
+		"txnid":  info.TxnID.String(),
+		"lease":  info.Lease,
+		"user":   gdctx.GetReqUser(ctx),
+	}).Warn("lock force released")
+	events.Broadcast(events.New(eventLockForceReleased, map[string]string{
+		"lock.id": info.ID,
+		"holder":  info.PeerID.String(),
+		"txn.id":  info.TxnID.String(),
+		"user":    gdctx.GetReqUser(ctx),
+	}, true))
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, info)
+}
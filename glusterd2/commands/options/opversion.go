@@ -0,0 +1,98 @@
+package optionscommands
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/opversion"
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/errors"
+)
+
+func createOpVersionResp() (*api.OpVersionResp, error) {
+	current, err := opversion.Cluster()
+	if err != nil {
+		return nil, err
+	}
+	max, err := opversion.MaxSupported()
+	if err != nil {
+		return nil, err
+	}
+	peers, err := peer.GetPeers()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &api.OpVersionResp{
+		OpVersion:    current,
+		MaxOpVersion: max,
+		Peers:        make([]api.PeerOpVersion, 0, len(peers)),
+	}
+	for _, p := range peers {
+		resp.Peers = append(resp.Peers, api.PeerOpVersion{
+			ID:           p.ID,
+			Name:         p.Name,
+			Version:      p.Version,
+			MaxOpVersion: p.MaxOpVersion,
+		})
+	}
+	return resp, nil
+}
+
+func getOpVersionHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	resp, err := createOpVersionResp()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+func bumpOpVersionHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+
+	var req api.OpVersionBumpReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrJSONParsingFailed)
+		return
+	}
+	if req.OpVersion < 0 {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrInvalidOpVersion)
+		return
+	}
+
+	txn, err := transaction.NewTxnWithLocks(ctx, lockKey)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	defer txn.Done()
+
+	target := req.OpVersion
+	if target == 0 {
+		if target, err = opversion.MaxSupported(); err != nil {
+			restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	if err := opversion.Set(target); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+	logger.WithField("op-version", target).Info("cluster op-version raised")
+
+	resp, err := createOpVersionResp()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
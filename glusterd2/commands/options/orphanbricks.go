@@ -0,0 +1,56 @@
+package optionscommands
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/orphanbricks"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+func listOrphanBricksHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	orphans, err := orphanbricks.List()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, api.OrphanBrickListResp(orphans))
+}
+
+// cleanupOrphanBricksHandler has the peers which are up kill their orphaned
+// brick processes
+func cleanupOrphanBricksHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+
+	var nodes []uuid.UUID
+	for id := range store.Store.GetAliveNodes(ctx) {
+		nodes = append(nodes, uuid.Parse(id))
+	}
+
+	txn := transaction.NewTxn(ctx)
+	defer txn.Done()
+	txn.Steps = []*transaction.Step{orphanbricks.CleanupStep(nodes)}
+	txn.DisableRollback = true
+
+	if err := txn.Do(); err != nil {
+		logger.WithError(err).Error("failed to clean up the orphaned brick processes")
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	killed := orphanbricks.Killed(txn.Ctx, nodes)
+	logger.WithFields(log.Fields{
+		"killed": len(killed),
+		"user":   gdctx.GetReqUser(ctx),
+	}).Info("cleaned up the orphaned brick processes")
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, api.OrphanBrickCleanupResp(killed))
+}
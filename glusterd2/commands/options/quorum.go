@@ -0,0 +1,12 @@
+package optionscommands
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/quorum"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+)
+
+func getQuorumHandler(w http.ResponseWriter, r *http.Request) {
+	restutils.SendHTTPResponse(r.Context(), w, http.StatusOK, quorum.Status())
+}
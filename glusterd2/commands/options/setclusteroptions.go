@@ -4,9 +4,13 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/gluster/glusterd2/glusterd2/cgroups"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
 	"github.com/gluster/glusterd2/glusterd2/options"
+	"github.com/gluster/glusterd2/glusterd2/peer"
 	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
 	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/xlator"
 	"github.com/gluster/glusterd2/pkg/api"
 	"github.com/gluster/glusterd2/pkg/errors"
 )
@@ -15,8 +19,47 @@ const (
 	lockKey = "clusteroptions"
 )
 
+// normalizeVolumeOption validates a default volume option set at the cluster
+// level and returns its key in the form stored in the volume options
+func normalizeVolumeOption(k string, v string, flags api.VolOptionFlags) (string, error) {
+	o, err := xlator.FindOption(k)
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case !o.IsSettable():
+		return "", fmt.Errorf("option %s cannot be set", k)
+
+	case o.IsAdvanced() && !flags.AllowAdvanced:
+		return "", fmt.Errorf("option %s is an advanced option. To set it pass the advanced flag", k)
+
+	case o.IsExperimental() && !flags.AllowExperimental:
+		return "", fmt.Errorf("option %s is an experimental option. To set it pass the experimental flag", k)
+
+	case o.IsDeprecated() && !flags.AllowDeprecated:
+		return "", fmt.Errorf("option %s will be deprecated in future releases. To set it pass the deprecated flag", k)
+	}
+
+	if err := o.Validate(v); err != nil {
+		return "", fmt.Errorf("failed to validate value(%s) for key(%s): %s", v, k, err.Error())
+	}
+
+	graphName, xl, key := options.SplitKey(k)
+	xltr, err := xlator.Find(xl)
+	if err != nil {
+		return "", err
+	}
+	normalizedKeyName := xltr.FullName() + "." + key
+	if graphName != "" {
+		normalizedKeyName = graphName + "." + normalizedKeyName
+	}
+	return normalizedKeyName, nil
+}
+
 func setClusterOptionsHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
 
 	var req api.ClusterOptionReq
 	if err := restutils.UnmarshalRequest(r, &req); err != nil {
@@ -43,6 +86,15 @@ func setClusterOptionsHandler(w http.ResponseWriter, r *http.Request) {
 		c.Options = make(map[string]string)
 	}
 
+	oldOptions := make(map[string]string, len(c.Options))
+	for k, v := range c.Options {
+		oldOptions[k] = v
+	}
+
+	// Options other than the cluster-wide ones are default volume options,
+	// which apply to the volumes not setting them
+	volDefaultsChanged := false
+	limitsChanged := false
 	for k, v := range req.Options {
 		if opt, found := options.ClusterOptMap[k]; found {
 			if opt.ValidateFunc != nil {
@@ -53,10 +105,17 @@ func setClusterOptionsHandler(w http.ResponseWriter, r *http.Request) {
 				}
 			}
 			c.Options[k] = v
-		} else {
-			restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, fmt.Sprintf("Invalid global option: %s", k))
+			limitsChanged = limitsChanged || cgroups.IsLimitOption(k)
+			continue
+		}
+
+		key, err := normalizeVolumeOption(k, v, req.VolOptionFlags)
+		if err != nil {
+			restutils.SendHTTPError(ctx, w, http.StatusBadRequest, fmt.Sprintf("Invalid cluster option %s: %s", k, err))
 			return
 		}
+		c.Options[key] = v
+		volDefaultsChanged = true
 	}
 
 	if err := options.UpdateClusterOptions(c); err != nil {
@@ -65,5 +124,43 @@ func setClusterOptionsHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if volDefaultsChanged || limitsChanged {
+		if err := applyOnPeers(txn, volDefaultsChanged, limitsChanged); err != nil {
+			logger.WithError(err).Error("failed to apply the new cluster options on the peers")
+			c.Options = oldOptions
+			if err := options.UpdateClusterOptions(c); err != nil {
+				logger.WithError(err).Error("failed to restore cluster options")
+			}
+			status, err := restutils.ErrToStatusCode(err)
+			restutils.SendHTTPError(ctx, w, status, err)
+			return
+		}
+	}
+
 	restutils.SendHTTPResponse(ctx, w, http.StatusOK, c.Options)
 }
+
+// applyOnPeers has all the peers regenerate the brick volfiles of the
+// started volumes and notify their clients to fetch the new volfiles, when
+// the default volume options changed, and apply the resource limits to the
+// cgroups of their daemons, when the limits changed
+func applyOnPeers(txn *transaction.Txn, volDefaultsChanged, limitsChanged bool) error {
+	allNodes, err := peer.GetPeerIDs()
+	if err != nil {
+		return err
+	}
+
+	if volDefaultsChanged {
+		txn.Steps = append(txn.Steps, &transaction.Step{
+			DoFunc: "cluster-options.GenerateVolfiles",
+			Nodes:  allNodes,
+		})
+	}
+	if limitsChanged {
+		txn.Steps = append(txn.Steps, &transaction.Step{
+			DoFunc: "cluster-options.ApplyCgroupLimits",
+			Nodes:  allNodes,
+		})
+	}
+	return txn.Do()
+}
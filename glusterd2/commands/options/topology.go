@@ -0,0 +1,40 @@
+package optionscommands
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/topology"
+)
+
+// clusterTopologyHandler returns the topology of the cluster as a graph, in
+// JSON or, with format=dot, in the DOT language of Graphviz
+func clusterTopologyHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "dot" {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.New("format must be json or dot"))
+		return
+	}
+
+	topo, err := topology.Build(ctx)
+	if err != nil {
+		logger.WithError(err).Error("failed to build the topology of the cluster")
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if format != "dot" {
+		restutils.SendHTTPResponse(ctx, w, http.StatusOK, topo)
+		return
+	}
+	w.Header().Set("Content-Type", "text/vnd.graphviz; charset=UTF-8")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write([]byte(topo.DOT())); err != nil {
+		logger.WithError(err).Error("failed to send the topology of the cluster")
+	}
+}
@@ -0,0 +1,67 @@
+package optionscommands
+
+import (
+	"context"
+
+	"github.com/gluster/glusterd2/glusterd2/cgroups"
+	"github.com/gluster/glusterd2/glusterd2/servers/sunrpc"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/volgen"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+
+	log "github.com/sirupsen/logrus"
+)
+
+func txnGenerateVolfiles(c transaction.TxnCtx) error {
+	volumes, err := volume.GetVolumes(context.TODO())
+	if err != nil {
+		return err
+	}
+
+	var volnames []string
+	checksums := make(map[string]string)
+	for _, v := range volumes {
+		if v.State != volume.VolStarted {
+			continue
+		}
+
+		// Only the volfiles whose inputs changed are generated again,
+		// and saved if they changed
+		sums, err := volgen.GenerateChangedBricksVolfiles(v, v.GetLocalBricks())
+		if err != nil {
+			c.Logger().WithError(err).WithFields(log.Fields{
+				"template": "brick",
+				"volume":   v.Name,
+			}).Error("failed to generate volfile")
+			return err
+		}
+		for volfileID, sum := range sums {
+			checksums[volfileID] = sum
+		}
+		if sum, err := volgen.ClientVolfileChecksum(v); err == nil {
+			checksums[v.Name] = sum
+		} else {
+			c.Logger().WithError(err).WithFields(log.Fields{
+				"template": "client",
+				"volume":   v.Name,
+			}).Warn("failed to generate volfile, notifying all the clients of the volume")
+		}
+		volnames = append(volnames, v.Name)
+	}
+
+	// The volfiles of all the volumes may have changed, the clients are
+	// notified in batches for them not to fetch their volfiles all at
+	// once. Only the clients served volfiles which changed since are
+	// notified. Failing to notify clients does not fail the transaction,
+	// the clients fetch the new volfiles on reconnecting.
+	sunrpc.VolfilesChangeNotify(c.Logger(), volnames, checksums)
+	return nil
+}
+
+func txnApplyCgroupLimits(c transaction.TxnCtx) error {
+	if err := cgroups.ApplyLimits(); err != nil {
+		c.Logger().WithError(err).Error("failed to apply the resource limits to the cgroups")
+		return err
+	}
+	return nil
+}
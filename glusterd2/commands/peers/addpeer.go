@@ -1,19 +1,24 @@
 package peercommands
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"sort"
 	"strings"
 
+	"github.com/gluster/glusterd2/glusterd2/ca"
 	"github.com/gluster/glusterd2/glusterd2/events"
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
 	"github.com/gluster/glusterd2/glusterd2/peer"
 	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
 	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
 	"github.com/gluster/glusterd2/pkg/api"
 	"github.com/gluster/glusterd2/pkg/errors"
 	"github.com/gluster/glusterd2/pkg/utils"
+
+	"github.com/pborman/uuid"
 )
 
 const (
@@ -133,6 +138,12 @@ func addPeerHandler(w http.ResponseWriter, r *http.Request) {
 		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, "Fail to add metadata to peer")
 	}
 
+	// The peer is added even if it fails to get a certificate, which is
+	// retried by its periodic renewal
+	if err := issuePeerCert(ctx, newpeer.ID); err != nil {
+		logger.WithError(err).Error("failed to issue a certificate for the new peer")
+	}
+
 	resp := createPeerAddResp(newpeer)
 	restutils.SetLocationHeader(r, w, newpeer.ID.String())
 	restutils.SendHTTPResponse(ctx, w, http.StatusCreated, resp)
@@ -143,6 +154,26 @@ func addPeerHandler(w http.ResponseWriter, r *http.Request) {
 	events.Broadcast(newPeerEvent(eventPeerAdded, newpeer))
 }
 
+// issuePeerCert has the CA of the cluster, if any, issue the certificate of
+// the new peer
+func issuePeerCert(ctx context.Context, peerID uuid.UUID) error {
+	mode, err := ca.Mode()
+	if err != nil || mode == ca.ModeOff {
+		return err
+	}
+
+	txn := transaction.NewTxn(ctx)
+	defer txn.Done()
+
+	step, err := ca.RenewStep(txn.Ctx, false, []uuid.UUID{peerID})
+	if err != nil {
+		return err
+	}
+	txn.Steps = []*transaction.Step{step}
+	txn.DisableRollback = true
+	return txn.Do()
+}
+
 func createPeerAddResp(p *peer.Peer) *api.PeerAddResp {
 	return &api.PeerAddResp{
 		ID:              p.ID,
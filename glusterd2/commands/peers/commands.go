@@ -2,6 +2,7 @@
 package peercommands
 
 import (
+	"github.com/gluster/glusterd2/glusterd2/middleware"
 	"github.com/gluster/glusterd2/glusterd2/servers/rest/route"
 	"github.com/gluster/glusterd2/pkg/api"
 	"github.com/gluster/glusterd2/pkg/utils"
@@ -30,6 +31,14 @@ func (c *Command) Routes() route.Routes {
 			ResponseType: utils.GetTypeString((*api.PeerListResp)(nil)),
 			HandlerFunc:  getPeersHandler,
 		},
+		route.Route{
+			Name:         "GetPeerPorts",
+			Method:       "GET",
+			Pattern:      "/peers/{peerid}/ports",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.PeerPortsResp)(nil)),
+			HandlerFunc:  peerPortsHandler,
+		},
 		route.Route{
 			Name:        "DeletePeer",
 			Method:      "DELETE",
@@ -46,6 +55,33 @@ func (c *Command) Routes() route.Routes {
 			ResponseType: utils.GetTypeString((*api.PeerAddResp)(nil)),
 			HandlerFunc:  addPeerHandler,
 		},
+		route.Route{
+			Name:         "CreatePeerToken",
+			Method:       "POST",
+			Pattern:      "/peers/tokens",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.PeerTokenReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.PeerTokenResp)(nil)),
+			HandlerFunc:  middleware.RequireAdmin(createTokenHandler),
+		},
+		route.Route{
+			Name:         "AdmitPeer",
+			Method:       "POST",
+			Pattern:      "/peers/admit",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.PeerAdmitReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.PeerAdmitResp)(nil)),
+			HandlerFunc:  admitPeerHandler,
+		},
+		route.Route{
+			Name:         "JoinCluster",
+			Method:       "POST",
+			Pattern:      "/peers/join",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.PeerJoinReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.PeerJoinResp)(nil)),
+			HandlerFunc:  joinClusterHandler,
+		},
 		route.Route{
 			Name:         "EditPeer",
 			Method:       "POST",
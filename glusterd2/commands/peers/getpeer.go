@@ -3,6 +3,7 @@ package peercommands
 import (
 	"net/http"
 
+	"github.com/gluster/glusterd2/glusterd2/heartbeat"
 	"github.com/gluster/glusterd2/glusterd2/peer"
 	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
 	"github.com/gluster/glusterd2/glusterd2/store"
@@ -41,5 +42,18 @@ func createPeerGetResp(p *peer.Peer) *api.PeerGetResp {
 		Online:          online,
 		PID:             pid,
 		Metadata:        p.Metadata,
+		Version:         p.Version,
+		MaxOpVersion:    p.MaxOpVersion,
+		Liveness:        peerLiveness(p),
 	}
 }
+
+// peerLiveness returns the liveness of the peer from the heartbeats sent to
+// it by this peer, or nil for this peer and the peers not sent any yet
+func peerLiveness(p *peer.Peer) *api.PeerLiveness {
+	l, ok := heartbeat.Status(p.ID.String())
+	if !ok {
+		return nil
+	}
+	return &l
+}
@@ -44,6 +44,9 @@ func createPeerListResp(peers []*peer.Peer) *api.PeerListResp {
 			Online:          online,
 			PID:             pid,
 			Metadata:        p.Metadata,
+			Version:         p.Version,
+			MaxOpVersion:    p.MaxOpVersion,
+			Liveness:        peerLiveness(p),
 		})
 	}
 
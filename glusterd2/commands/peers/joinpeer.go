@@ -0,0 +1,131 @@
+package peercommands
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gluster/glusterd2/glusterd2/ca"
+	"github.com/gluster/glusterd2/glusterd2/datatls"
+	"github.com/gluster/glusterd2/glusterd2/events"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/errors"
+	"github.com/gluster/glusterd2/pkg/restclient"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// joinClusterHandler makes this peer join the cluster which created the
+// join token, presenting the token to the peer of the cluster named in it
+func joinClusterHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+
+	var req api.PeerJoinReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrJSONParsingFailed)
+		return
+	}
+
+	if req.MetadataSize() > maxMetadataSizeLimit {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrMetadataSizeOutOfBounds)
+		return
+	}
+
+	for key := range req.Metadata {
+		if strings.HasPrefix(key, "_") {
+			restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrRestrictedKeyFound)
+			return
+		}
+	}
+
+	token, err := parseToken(req.Token)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+	logger = logger.WithFields(log.Fields{
+		"remotecluster": token.ClusterID,
+		"url":           token.URL,
+	})
+
+	if mutex.TryLock() {
+		defer mutex.Unlock()
+	} else {
+		restutils.SendHTTPError(ctx, w, http.StatusConflict, ErrAnotherReqInProgress)
+		return
+	}
+
+	// Check before using the token, which can be used only once
+	if e := checkCanJoin(logger); e != ErrNone {
+		restutils.SendHTTPError(ctx, w, http.StatusConflict, e)
+		return
+	}
+
+	// The token is verified by the cluster. The REST service of the
+	// cluster is not authenticated, as its CA is not known yet.
+	client, err := restclient.New(token.URL, "", "", "", true)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	admit, err := client.PeerAdmit(api.PeerAdmitReq{Token: req.Token, PeerID: gdctx.MyUUID})
+	if err != nil {
+		logger.WithError(err).Error("cluster refused join token")
+		restutils.SendHTTPError(ctx, w, http.StatusUnauthorized, err)
+		return
+	}
+	if admit.ClusterID.String() != token.ClusterID {
+		logger.WithField("clusterid", admit.ClusterID.String()).Error("admitted to another cluster than the one of the join token")
+		restutils.SendHTTPError(ctx, w, http.StatusBadGateway, errors.ErrInvalidJoinToken)
+		return
+	}
+
+	if admit.CA != "" {
+		if err := datatls.WriteCA(admit.CA); err != nil {
+			logger.WithError(err).Error("failed to write the CA bundle of the cluster")
+			restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	if e := joinCluster(logger, admit.ClusterID.String(), &StoreConfig{Endpoints: admit.Endpoints}); e != ErrNone {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, e)
+		return
+	}
+
+	self, err := peer.GetPeer(gdctx.MyUUID.String())
+	if err != nil {
+		logger.WithError(err).Error("failed to get peer information")
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, "joined cluster, but could not find peer in store. Try again later.")
+		return
+	}
+
+	if req.Zone != "" {
+		self.Metadata["_zone"] = req.Zone
+	}
+	for key, value := range req.Metadata {
+		self.Metadata[key] = value
+	}
+	if err := peer.AddOrUpdatePeer(self); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, "Fail to add metadata to peer")
+		return
+	}
+
+	// The peer joins even if it fails to get a certificate, which is
+	// retried by its periodic renewal
+	if err := ca.Renew(false); err != nil {
+		logger.WithError(err).Error("failed to issue a certificate for this peer")
+	}
+
+	resp := (*api.PeerJoinResp)(createPeerAddResp(self))
+	restutils.SendHTTPResponse(ctx, w, http.StatusCreated, resp)
+
+	// Save updated store endpoints for restarts
+	store.Store.UpdateEndpoints()
+
+	events.Broadcast(newPeerEvent(eventPeerAdded, self))
+}
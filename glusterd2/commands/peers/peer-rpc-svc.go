@@ -15,6 +15,7 @@ import (
 
 	"github.com/pborman/uuid"
 	log "github.com/sirupsen/logrus"
+	config "github.com/spf13/viper"
 	"google.golang.org/grpc"
 )
 
@@ -47,34 +48,57 @@ func (p *PeerService) Join(ctx context.Context, req *JoinReq) (*JoinRsp, error)
 
 	logger.Info("handling new incoming join cluster request")
 
-	// Handling a Join request happens as follows,
-	// 	- TODO: Ensure no ongoing operations (transactions/other peer requests) are happening
-	//      - Check if peer is part of another cluster
-	// 	- Check if the peer has volumes
-	//	- Reconfigure the store with received configuration
-	// 	- Return your ID
+	if config.GetBool(joinTokenRequiredOpt) {
+		logger.Info("rejecting join request, joining requires a join token")
+		return &JoinRsp{PeerID: "", Err: int32(ErrJoinTokenRequired)}, nil
+	}
+
+	if e := joinCluster(logger, req.ClusterID, req.Config); e != ErrNone {
+		return &JoinRsp{PeerID: "", Err: int32(e)}, nil
+	}
+	return &JoinRsp{PeerID: gdctx.MyUUID.String(), Err: int32(ErrNone)}, nil
+}
 
+// checkCanJoin checks that this peer can join another cluster, that is it is
+// not part of a cluster and has no volumes
+func checkCanJoin(logger log.FieldLogger) Error {
 	// TODO: Ensure no other operations are happening
 
 	peers, err := peer.GetPeersF()
 	if err != nil {
 		logger.WithError(err).Error("failed to connect to store")
-		return &JoinRsp{PeerID: "", Err: int32(ErrFailedToConnectToStore)}, nil
+		return ErrFailedToConnectToStore
 	}
 	if len(peers) != 1 {
 		logger.Info("rejecting join, already part of a cluster")
-		return &JoinRsp{PeerID: "", Err: int32(ErrAnotherCluster)}, nil
+		return ErrAnotherCluster
 	}
 
 	volumes, err := volume.GetVolumes(context.TODO())
 	if err != nil {
 		logger.WithError(err).Error("failed to connect to store")
-		return &JoinRsp{PeerID: "", Err: int32(ErrFailedToConnectToStore)}, nil
+		return ErrFailedToConnectToStore
 	}
 
 	if len(volumes) != 0 {
 		logger.Info("rejecting join, we already have volumes")
-		return &JoinRsp{PeerID: "", Err: int32(ErrAnotherCluster)}, nil
+		return ErrAnotherCluster
+	}
+	return ErrNone
+}
+
+// joinCluster makes this peer join the cluster, reconfiguring the store with
+// the given store config. The caller must hold the join/leave mutex.
+func joinCluster(logger log.FieldLogger, clusterID string, c *StoreConfig) Error {
+	// Handling a Join request happens as follows,
+	// 	- TODO: Ensure no ongoing operations (transactions/other peer requests) are happening
+	//      - Check if peer is part of another cluster
+	// 	- Check if the peer has volumes
+	//	- Reconfigure the store with received configuration
+	// 	- Return your ID
+
+	if e := checkCanJoin(logger); e != ErrNone {
+		return e
 	}
 
 	logger.Debug("all checks passed, joining new cluster")
@@ -88,19 +112,19 @@ func (p *PeerService) Join(ctx context.Context, req *JoinReq) (*JoinRsp, error)
 			gdctx.UpdateClusterID(oldClusterID)
 		}
 	}(gdctx.MyClusterID.String())
-	if err := gdctx.UpdateClusterID(req.ClusterID); err != nil {
-		return &JoinRsp{PeerID: "", Err: int32(ErrClusterIDUpdateFailed)}, nil
+	if err := gdctx.UpdateClusterID(clusterID); err != nil {
+		return ErrClusterIDUpdateFailed
 	}
 
-	if err := ReconfigureStore(req.Config); err != nil {
+	if err := ReconfigureStore(c); err != nil {
 		logger.WithError(err).Error("reconfigure store failed, failed to join new cluster")
-		return &JoinRsp{PeerID: "", Err: int32(ErrStoreReconfigFailed)}, nil
+		return ErrStoreReconfigFailed
 	}
 	success = true
 	logger.Debug("reconfigured store to join new cluster")
 
 	logger.Info("joined new cluster")
-	return &JoinRsp{PeerID: gdctx.MyUUID.String(), Err: int32(ErrNone)}, nil
+	return ErrNone
 }
 
 // Leave makes the peer leave its current cluster, and restart as a single node cluster
@@ -0,0 +1,238 @@
+package peercommands
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	"github.com/gluster/glusterd2/glusterd2/pmap"
+	"github.com/gluster/glusterd2/glusterd2/servers/rest/proxy"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/snapshot"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/gorilla/mux"
+	"github.com/pborman/uuid"
+	config "github.com/spf13/viper"
+)
+
+const (
+	protoTCP = "tcp"
+	// tcpListen is the state of the listening sockets in /proc/net/tcp
+	tcpListen = "0A"
+)
+
+var procNetTCP = []string{"/proc/net/tcp", "/proc/net/tcp6"}
+
+func peerPortsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+
+	id := mux.Vars(r)["peerid"]
+	if uuid.Parse(id) == nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "Invalid peer id passed")
+		return
+	}
+	p, err := peer.GetPeerF(id)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusNotFound, err)
+		return
+	}
+	if !uuid.Equal(p.ID, gdctx.MyUUID) {
+		// The ports are found on the peer itself
+		proxy.Serve(w, r, p.ID)
+		return
+	}
+
+	resp, err := localPorts(ctx)
+	if err != nil {
+		logger.WithError(err).Error("failed to list the ports of the peer")
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+// parseListeningPorts adds the TCP ports listened on to ports, given the
+// contents of /proc/net/tcp or /proc/net/tcp6
+func parseListeningPorts(r io.Reader, ports map[int]bool) error {
+	s := bufio.NewScanner(r)
+	// skip the header
+	s.Scan()
+	for s.Scan() {
+		// sl local_address rem_address st ...
+		fields := strings.Fields(s.Text())
+		if len(fields) < 4 || fields[3] != tcpListen {
+			continue
+		}
+		i := strings.LastIndex(fields[1], ":")
+		if i < 0 {
+			continue
+		}
+		port, err := strconv.ParseUint(fields[1][i+1:], 16, 16)
+		if err != nil {
+			continue
+		}
+		ports[int(port)] = true
+	}
+	return s.Err()
+}
+
+// listeningPorts returns the TCP ports listened on on this peer
+func listeningPorts() map[int]bool {
+	ports := make(map[int]bool)
+	for _, name := range procNetTCP {
+		f, err := os.Open(name)
+		if err != nil {
+			continue
+		}
+		parseListeningPorts(f, ports)
+		f.Close()
+	}
+	return ports
+}
+
+// addrPort returns the port of the address, given as host:port or as a URL
+func addrPort(addr string) (int, bool) {
+	if u, err := url.Parse(addr); err == nil && u.Host != "" {
+		addr = u.Host
+	}
+	_, p, err := net.SplitHostPort(addr)
+	if err != nil {
+		return 0, false
+	}
+	port, err := strconv.Atoi(p)
+	return port, err == nil
+}
+
+// localBricks returns the bricks of this peer of the started volumes and
+// activated snapshots, mapped to the names of their volumes
+func localBricks(ctx context.Context) (map[string]string, error) {
+	volumes, err := volume.GetVolumes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	snapVolumes, err := snapshot.GetActivatedSnapshotVolumes()
+	if err != nil {
+		return nil, err
+	}
+
+	bricks := make(map[string]string)
+	for _, v := range append(volumes, snapVolumes...) {
+		if v.State != volume.VolStarted {
+			continue
+		}
+		for _, b := range v.GetLocalBricks() {
+			bricks[b.Path] = v.Name
+		}
+	}
+	return bricks, nil
+}
+
+// localPorts returns the ports glusterd2 and the bricks of this peer listen
+// on, and are expected to listen on
+func localPorts(ctx context.Context) (api.PeerPortsResp, error) {
+	resp := api.PeerPortsResp{
+		PeerID: gdctx.MyUUID,
+		Ports:  make([]api.PeerPort, 0),
+	}
+	listening := listeningPorts()
+
+	addConfigured := func(service, addr string) {
+		port, ok := addrPort(addr)
+		if !ok {
+			return
+		}
+		resp.Ports = append(resp.Ports, api.PeerPort{
+			Port:      port,
+			Protocol:  protoTCP,
+			Service:   service,
+			Address:   addr,
+			PID:       os.Getpid(),
+			Expected:  true,
+			Listening: listening[port],
+		})
+	}
+	addConfigured(api.PortServiceManagement, config.GetString("clientaddress"))
+	addConfigured(api.PortServicePeerRPC, config.GetString("peeraddress"))
+	if config.GetBool("metrics") && config.GetString("metrics-address") != "" {
+		addConfigured(api.PortServiceMetrics, config.GetString("metrics-address"))
+	}
+	if curls, purls, ok := store.Store.EmbeddedURLs(); ok {
+		for _, u := range curls {
+			addConfigured(api.PortServiceEtcdClient, u)
+		}
+		for _, u := range purls {
+			addConfigured(api.PortServiceEtcdPeer, u)
+		}
+	}
+
+	bricks, err := localBricks(ctx)
+	if err != nil {
+		return resp, err
+	}
+
+	// The bricks multiplexed into a process share its port
+	byPort := make(map[int]*api.PeerPort)
+	signedIn := make(map[string]bool)
+	for _, e := range pmap.RegistryEntries() {
+		p, ok := byPort[e.Port]
+		if !ok {
+			p = &api.PeerPort{
+				Port:      e.Port,
+				Protocol:  protoTCP,
+				Service:   api.PortServiceBrick,
+				PID:       e.PID,
+				Listening: listening[e.Port],
+			}
+			byPort[e.Port] = p
+		}
+		volname, expected := bricks[e.BrickPath]
+		p.Bricks = append(p.Bricks, api.PortBrick{Volume: volname, Path: e.BrickPath})
+		p.Expected = p.Expected || expected
+		signedIn[e.BrickPath] = true
+	}
+
+	ports := make([]int, 0, len(byPort))
+	for port := range byPort {
+		ports = append(ports, port)
+	}
+	sort.Ints(ports)
+	for _, port := range ports {
+		p := byPort[port]
+		sort.Slice(p.Bricks, func(i, j int) bool {
+			return p.Bricks[i].Path < p.Bricks[j].Path
+		})
+		resp.Ports = append(resp.Ports, *p)
+	}
+
+	// The bricks not signed in have no port yet
+	var missing []string
+	for path := range bricks {
+		if !signedIn[path] {
+			missing = append(missing, path)
+		}
+	}
+	sort.Strings(missing)
+	for _, path := range missing {
+		resp.Ports = append(resp.Ports, api.PeerPort{
+			Protocol: protoTCP,
+			Service:  api.PortServiceBrick,
+			Bricks:   []api.PortBrick{{Volume: bricks[path], Path: path}},
+			Expected: true,
+		})
+	}
+
+	return resp, nil
+}
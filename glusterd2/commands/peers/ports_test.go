@@ -0,0 +1,41 @@
+package peercommands
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseListeningPorts(t *testing.T) {
+	tcp := `  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 00000000:5DC7 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 21422 1 0000000000000000 100 0 0 10 0
+   1: 0100007F:0929 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 21430 1 0000000000000000 100 0 0 10 0
+   2: 0100007F:5DC7 0100007F:C0D8 01 00000000:00000000 00:00000000 00000000     0        0 21431 1 0000000000000000 20 4 30 10 -1
+`
+	tcp6 := `  sl  local_address                         remote_address                        st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 00000000000000000000000000000000:C000 00000000000000000000000000000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 23981 1 0000000000000000 100 0 0 10 0
+`
+	ports := make(map[int]bool)
+	require.NoError(t, parseListeningPorts(strings.NewReader(tcp), ports))
+	require.NoError(t, parseListeningPorts(strings.NewReader(tcp6), ports))
+	// The established connections are not listening
+	assert.Equal(t, map[int]bool{24007: true, 2345: true, 49152: true}, ports)
+}
+
+func TestAddrPort(t *testing.T) {
+	for addr, port := range map[string]int{
+		":24007":                 24007,
+		"192.168.56.101:24008":   24008,
+		"http://0.0.0.0:2379":    2379,
+		"https://[::1]:2380":     2380,
+		"localhost:24007":        24007,
+		"http://192.168.56.101":  0,
+		"no port in the address": 0,
+	} {
+		p, ok := addrPort(addr)
+		assert.Equal(t, port != 0, ok, addr)
+		assert.Equal(t, port, p, addr)
+	}
+}
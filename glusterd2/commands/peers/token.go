@@ -0,0 +1,285 @@
+package peercommands
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/datatls"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/pborman/uuid"
+	config "github.com/spf13/viper"
+)
+
+const (
+	joinTokenRequiredOpt = "join-token-required"
+
+	// joinTokenPrefix is where the join tokens not used yet are saved,
+	// under their ID, until they expire
+	joinTokenPrefix = "jointokens/"
+	// joinTokenSecretKey is where the secret signing the join tokens is
+	// saved
+	joinTokenSecretKey = "jointoken-secret"
+
+	defaultJoinTokenTTL = time.Hour
+	maxJoinTokenTTL     = 7 * 24 * time.Hour
+)
+
+var errMalformedToken = errors.New("malformed join token")
+
+// joinToken is what a join token carries, signed with the secret of the
+// cluster
+type joinToken struct {
+	ID        string `json:"id"`
+	ClusterID string `json:"cluster-id"`
+	// URL is the URL of the REST service of the peer which created the
+	// token, to which the joining peer presents it
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires-at"`
+}
+
+func tokenSignature(payload string, secret []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// encodeToken returns the join token signed with the secret, as
+// <payload>.<signature>, both base64 encoded
+func encodeToken(t *joinToken, secret []byte) (string, error) {
+	data, err := json.Marshal(t)
+	if err != nil {
+		return "", err
+	}
+	payload := base64.RawURLEncoding.EncodeToString(data)
+	return payload + "." + tokenSignature(payload, secret), nil
+}
+
+// parseToken returns what the join token carries, without verifying it
+func parseToken(token string) (*joinToken, error) {
+	parts := strings.Split(strings.TrimSpace(token), ".")
+	if len(parts) != 2 {
+		return nil, errMalformedToken
+	}
+	data, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, errMalformedToken
+	}
+	var t joinToken
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, errMalformedToken
+	}
+	return &t, nil
+}
+
+// verifyToken returns what the join token carries, if it is signed with the
+// secret and not expired
+func verifyToken(token string, secret []byte, now time.Time) (*joinToken, error) {
+	t, err := parseToken(token)
+	if err != nil {
+		return nil, err
+	}
+	parts := strings.Split(strings.TrimSpace(token), ".")
+	if !hmac.Equal([]byte(parts[1]), []byte(tokenSignature(parts[0], secret))) {
+		return nil, gderrors.ErrInvalidJoinToken
+	}
+	if now.After(t.ExpiresAt) {
+		return nil, gderrors.ErrInvalidJoinToken
+	}
+	return t, nil
+}
+
+// joinTokenSecret returns the secret signing the join tokens of the cluster,
+// creating it if needed
+func joinTokenSecret() ([]byte, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	encoded := base64.StdEncoding.EncodeToString(secret)
+
+	// Only one of the peers creating the secret at once succeeds
+	resp, err := store.Txn(context.TODO()).
+		If(clientv3.Compare(clientv3.CreateRevision(joinTokenSecretKey), "=", 0)).
+		Then(clientv3.OpPut(joinTokenSecretKey, encoded)).
+		Else(clientv3.OpGet(joinTokenSecretKey)).
+		Commit()
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Succeeded {
+		kvs := resp.Responses[0].GetResponseRange().Kvs
+		if len(kvs) != 1 {
+			return nil, errors.New("failed to get the join token secret")
+		}
+		encoded = string(kvs[0].Value)
+	}
+	return base64.StdEncoding.DecodeString(encoded)
+}
+
+// restURL returns the URL of the REST service of this peer
+func restURL() (string, error) {
+	self, err := peer.GetPeer(gdctx.MyUUID.String())
+	if err != nil {
+		return "", err
+	}
+	if len(self.ClientAddresses) == 0 {
+		return "", errors.New("no client address for this peer")
+	}
+	scheme := "http"
+	if config.GetString("cert-file") != "" {
+		scheme = "https"
+	}
+	return scheme + "://" + self.ClientAddresses[0], nil
+}
+
+// createJoinToken creates a join token valid for ttl. The token is saved in
+// the store until it expires, to be used only once.
+func createJoinToken(ttl time.Duration) (string, time.Time, error) {
+	secret, err := joinTokenSecret()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	url, err := restURL()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	t := &joinToken{
+		ID:        uuid.New(),
+		ClusterID: gdctx.MyClusterID.String(),
+		URL:       url,
+		ExpiresAt: time.Now().Add(ttl).UTC(),
+	}
+	token, err := encodeToken(t, secret)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	lease, err := store.Store.Grant(store.Store.Ctx(), int64(ttl/time.Second))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if _, err := store.Put(context.TODO(), joinTokenPrefix+t.ID, t.ExpiresAt.Format(time.RFC3339), clientv3.WithLease(lease.ID)); err != nil {
+		return "", time.Time{}, err
+	}
+	return token, t.ExpiresAt, nil
+}
+
+// useJoinToken verifies the join token and removes it from the store, for
+// it not to be used again
+func useJoinToken(token string) error {
+	secret, err := joinTokenSecret()
+	if err != nil {
+		return err
+	}
+	t, err := verifyToken(token, secret, time.Now())
+	if err != nil {
+		return err
+	}
+	if t.ClusterID != gdctx.MyClusterID.String() {
+		return gderrors.ErrInvalidJoinToken
+	}
+
+	key := joinTokenPrefix + t.ID
+	resp, err := store.Txn(context.TODO()).
+		If(clientv3.Compare(clientv3.CreateRevision(key), "!=", 0)).
+		Then(clientv3.OpDelete(key)).
+		Commit()
+	if err != nil {
+		return err
+	}
+	if !resp.Succeeded {
+		return gderrors.ErrInvalidJoinToken
+	}
+	return nil
+}
+
+func createTokenHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+
+	var req api.PeerTokenReq
+	// request body is optional
+	if err := restutils.UnmarshalRequest(r, &req); err != nil && err != io.EOF {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
+		return
+	}
+
+	ttl := defaultJoinTokenTTL
+	if req.TTL != 0 {
+		ttl = time.Duration(req.TTL) * time.Second
+	}
+	if ttl < time.Second || ttl > maxJoinTokenTTL {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, fmt.Sprintf("ttl must be between 1 and %d seconds", int(maxJoinTokenTTL/time.Second)))
+		return
+	}
+
+	token, expiresAt, err := createJoinToken(ttl)
+	if err != nil {
+		logger.WithError(err).Error("failed to create join token")
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusCreated, api.PeerTokenResp{Token: token, ExpiresAt: expiresAt})
+}
+
+// admitPeerHandler handles the request of a peer joining the cluster with a
+// join token. The request is authenticated by the token, not by the REST
+// authentication of the cluster which the peer does not have yet.
+func admitPeerHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+
+	var req api.PeerAdmitReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
+		return
+	}
+	logger = logger.WithField("peerid", req.PeerID.String())
+
+	if err := useJoinToken(req.Token); err != nil {
+		logger.WithError(err).Warn("rejecting peer presenting invalid join token")
+		if err == errMalformedToken {
+			err = gderrors.ErrInvalidJoinToken
+		}
+		restutils.SendHTTPError(ctx, w, http.StatusUnauthorized, err)
+		return
+	}
+
+	if p, _ := peer.GetPeer(req.PeerID.String()); p != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusConflict, fmt.Sprintf("Peer exists with given ID (ID: %s)", p.ID.String()))
+		return
+	}
+
+	ca, err := datatls.CA()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	logger.Info("admitting peer with join token")
+	resp := api.PeerAdmitResp{
+		ClusterID: gdctx.MyClusterID,
+		Endpoints: store.Store.Endpoints(),
+		CA:        ca,
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
@@ -0,0 +1,53 @@
+package peercommands
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJoinToken(t *testing.T) {
+	secret := []byte("secret")
+	now := time.Now()
+	jt := &joinToken{
+		ID:        "id",
+		ClusterID: "cluster",
+		URL:       "http://peer1:24007",
+		ExpiresAt: now.Add(time.Hour).UTC(),
+	}
+
+	token, err := encodeToken(jt, secret)
+	require.NoError(t, err)
+
+	parsed, err := parseToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, jt.URL, parsed.URL)
+
+	verified, err := verifyToken(token, secret, now)
+	require.NoError(t, err)
+	assert.Equal(t, jt.ID, verified.ID)
+	assert.Equal(t, jt.ClusterID, verified.ClusterID)
+
+	// signed with another secret
+	_, err = verifyToken(token, []byte("other"), now)
+	assert.Equal(t, gderrors.ErrInvalidJoinToken, err)
+
+	// expired
+	_, err = verifyToken(token, secret, now.Add(2*time.Hour))
+	assert.Equal(t, gderrors.ErrInvalidJoinToken, err)
+
+	// tampered with
+	other, err := encodeToken(&joinToken{ID: "id", ClusterID: "cluster", URL: "http://attacker:24007", ExpiresAt: jt.ExpiresAt}, []byte("other"))
+	require.NoError(t, err)
+	tampered := strings.Split(other, ".")[0] + "." + strings.Split(token, ".")[1]
+	_, err = verifyToken(tampered, secret, now)
+	assert.Equal(t, gderrors.ErrInvalidJoinToken, err)
+
+	_, err = parseToken("garbage")
+	assert.Equal(t, errMalformedToken, err)
+}
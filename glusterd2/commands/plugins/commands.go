@@ -0,0 +1,45 @@
+// Package plugincommands implements the commands to list the plugins of
+// glusterd2 and to enable or disable them in the cluster
+package plugincommands
+
+import (
+	"github.com/gluster/glusterd2/glusterd2/servers/rest/route"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/utils"
+)
+
+// Command is a holding struct used to implement the GlusterD Command interface
+type Command struct {
+}
+
+// Routes returns command routes. Required for the Command interface.
+func (c *Command) Routes() route.Routes {
+	return route.Routes{
+		route.Route{
+			Name:         "PluginList",
+			Method:       "GET",
+			Pattern:      "/plugins",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.PluginListResp)(nil)),
+			HandlerFunc:  pluginListHandler},
+		route.Route{
+			Name:         "PluginEnable",
+			Method:       "POST",
+			Pattern:      "/plugins/{name}/enable",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.PluginInfo)(nil)),
+			HandlerFunc:  pluginEnableHandler},
+		route.Route{
+			Name:         "PluginDisable",
+			Method:       "POST",
+			Pattern:      "/plugins/{name}/disable",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.PluginInfo)(nil)),
+			HandlerFunc:  pluginDisableHandler},
+	}
+}
+
+// RegisterStepFuncs implements a required function for the Command interface
+func (c *Command) RegisterStepFuncs() {
+	return
+}
@@ -0,0 +1,46 @@
+package plugincommands
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/plugin"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/gorilla/mux"
+)
+
+func pluginListHandler(w http.ResponseWriter, r *http.Request) {
+	restutils.SendHTTPResponse(r.Context(), w, http.StatusOK, api.PluginListResp(plugin.List()))
+}
+
+func pluginEnableHandler(w http.ResponseWriter, r *http.Request) {
+	setPluginEnabled(w, r, true)
+}
+
+func pluginDisableHandler(w http.ResponseWriter, r *http.Request) {
+	setPluginEnabled(w, r, false)
+}
+
+// setPluginEnabled saves the new state of the plugin in the store. The
+// peers, this one included, enable or disable the plugin on seeing it.
+func setPluginEnabled(w http.ResponseWriter, r *http.Request, enabled bool) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+	name := mux.Vars(r)["name"]
+
+	if err := plugin.SetEnabled(name, enabled); err != nil {
+		if err == errors.ErrPluginNotFound {
+			restutils.SendHTTPError(ctx, w, http.StatusNotFound, err)
+			return
+		}
+		logger.WithError(err).WithField("plugin", name).Error("failed to save the state of the plugin")
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	logger.WithField("plugin", name).WithField("enabled", enabled).Info("plugin state changed")
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, api.PluginInfo{Name: name, Enabled: enabled})
+}
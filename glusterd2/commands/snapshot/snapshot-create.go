@@ -10,7 +10,6 @@ TODO
 */
 
 import (
-	"bytes"
 	"errors"
 	"fmt"
 	"net/http"
@@ -18,14 +17,12 @@ import (
 	"sync"
 	"time"
 
+	"github.com/gluster/glusterd2/glusterd2/barrier"
 	"github.com/gluster/glusterd2/glusterd2/brick"
-	"github.com/gluster/glusterd2/glusterd2/daemon"
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
 	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
-	"github.com/gluster/glusterd2/glusterd2/servers/sunrpc/dict"
 	"github.com/gluster/glusterd2/glusterd2/snapshot"
 	"github.com/gluster/glusterd2/glusterd2/transaction"
-	"github.com/gluster/glusterd2/glusterd2/volgen"
 	"github.com/gluster/glusterd2/glusterd2/volume"
 	"github.com/gluster/glusterd2/glusterd2/xlator"
 	"github.com/gluster/glusterd2/pkg/api"
@@ -44,133 +41,6 @@ type txnData struct {
 	CreatedAt time.Time
 }
 
-func barrierActivateDeactivateFunc(volinfo *volume.Volinfo, option string, originUUID uuid.UUID) error {
-	var req brick.GfBrickOpReq
-	var err error
-
-	volinfo.Options["features/barrier"] = option
-	if bytes.Equal(originUUID, gdctx.MyUUID) {
-		if err = volume.AddOrUpdateVolumeFunc(volinfo); err != nil {
-			log.WithError(err).WithField(
-				"volume", volinfo.Name).Debug("failed to store volume info")
-			return err
-		}
-	}
-
-	reqDict := make(map[string]string)
-	reqDict["barrier"] = option
-	req.Op = int(brick.OpBrickBarrier)
-	req.Input, err = dict.Serialize(reqDict)
-	if err != nil {
-		log.WithError(err).WithField(
-			"volume", volinfo.Name).Error("failed to serialize dict for barrier option")
-	}
-
-	for _, b := range volinfo.GetLocalBricks() {
-		volfileID := brick.GetVolfileID(volinfo.Name, b.Path)
-		err := volgen.BrickVolfileToFile(volinfo, volfileID, "brick", b.PeerID.String(), b.Path)
-		if err != nil {
-			log.WithError(err).WithFields(log.Fields{
-				"template": "brick",
-				"volfile":  volfileID,
-			}).Error("failed to generate volfile")
-			return err
-		}
-
-		brickDaemon, err := brick.NewGlusterfsd(b)
-		if err != nil {
-			return err
-		}
-
-		client, err := daemon.GetRPCClient(brickDaemon)
-		if err != nil {
-			log.WithError(err).WithField(
-				"brick", b.String()).Error("failed to connect to brick, Aborting the barrier config operation")
-			return err
-		}
-
-		req.Name = b.Path
-
-		var rsp brick.GfBrickOpRsp
-		err = client.Call("Brick.OpBrickBarrier", req, &rsp)
-		if err != nil || rsp.OpRet != 0 {
-			log.WithError(err).WithField(
-				"brick", b.String()).Error("failed to send barrier RPC")
-			return err
-		}
-
-	}
-	return nil
-}
-func deactivateBarrier(c transaction.TxnCtx) error {
-	var barrierOp string
-	var snapInfo snapshot.Snapinfo
-	if err := c.Get("barrier-enabled", &barrierOp); err != nil {
-		return err
-	}
-
-	if barrierOp == "enable" {
-		/*
-			Barrier is already enabled, Just return success
-		*/
-		return nil
-	}
-	var originatorUUID uuid.UUID
-	if err := c.Get("originator-uuid", &originatorUUID); err != nil {
-		return err
-	}
-
-	if err := c.Get("snapinfo", &snapInfo); err != nil {
-		return err
-	}
-
-	volinfo, err := volume.GetVolume(snapInfo.ParentVolume)
-	if err != nil {
-		return err
-	}
-	c.Logger().WithFields(log.Fields{"volume": volinfo.Name}).Info("Sending Barrier request to bricks")
-
-	err = barrierActivateDeactivateFunc(volinfo, "disable", originatorUUID)
-
-	return err
-
-}
-
-func activateBarrier(c transaction.TxnCtx) error {
-	var barrierOp string
-	var snapInfo snapshot.Snapinfo
-	if err := c.Get("barrier-enabled", &barrierOp); err != nil {
-		return err
-	}
-
-	if barrierOp == "enabled" {
-		/*
-			Barrier is already enabled, Just return success
-		*/
-		return nil
-	}
-	/*
-		Do we need to do this ?
-	*/
-	var originatorUUID uuid.UUID
-	if err := c.Get("originator-uuid", &originatorUUID); err != nil {
-		return err
-	}
-
-	if err := c.Get("snapinfo", &snapInfo); err != nil {
-		return err
-	}
-
-	volinfo, err := volume.GetVolume(snapInfo.ParentVolume)
-	if err != nil {
-		return err
-	}
-	c.Logger().WithFields(log.Fields{"volume": volinfo.Name}).Info("Sending Barrier request to bricks")
-
-	err = barrierActivateDeactivateFunc(volinfo, "enable", originatorUUID)
-	return err
-
-}
 func undoBrickSnapshots(c transaction.TxnCtx) error {
 	var snapInfo snapshot.Snapinfo
 
@@ -511,6 +381,9 @@ func createSnapSubvols(newVolinfo, origVolinfo *volume.Volinfo, nodeData map[str
 		if err != nil {
 			return err
 		}
+		for i := range s.Bricks {
+			s.Bricks[i].User = newVolinfo.BrickUser
+		}
 		newVolinfo.Subvols = append(newVolinfo.Subvols, s)
 
 	}
@@ -614,6 +487,7 @@ func duplicateVolinfo(vol, v *volume.Volinfo) {
 		v.Options[key] = value
 	}
 	v.Transport = vol.Transport
+	v.BrickUser = vol.BrickUser
 	v.DistCount = vol.DistCount
 	v.Type = vol.Type
 	if vol.Capacity != 0 {
@@ -636,7 +510,6 @@ func duplicateVolinfo(vol, v *volume.Volinfo) {
 	/*
 	* Geo-replication cofig snapshot
 	* Quota config snapshot
-	* del barrier option
 	 */
 	return
 }
@@ -667,14 +540,6 @@ func validateOriginNodeSnapCreate(c transaction.TxnCtx) error {
 		return gderrors.ErrVolNotStarted
 	}
 
-	barrierOp := volinfo.Options["features/barrier"]
-	if err := c.Set("barrier-enabled", &barrierOp); err != nil {
-		return err
-	}
-	if err := c.Set("originator-uuid", &gdctx.MyUUID); err != nil {
-		return err
-	}
-
 	/*
 		TODO
 		*Geo-replication,
@@ -694,10 +559,8 @@ func registerSnapCreateStepFuncs() {
 	}{
 		{"snap-create.Validate", validateSnapCreate},
 		{"snap-create.CreateSnapinfo", createSnapinfo},
-		{"snap-create.ActivateBarrier", activateBarrier},
 		{"snap-create.TakeBrickSnapshots", takeSnapshots},
 		{"snap-create.UndoBrickSnapshots", undoBrickSnapshots},
-		{"snap-create.DeactivateBarrier", deactivateBarrier},
 		{"snap-create.StoreSnapshot", storeSnapshotCreate},
 		{"snap-create.UndoStoreSnapshotOnCreate", undoStoreSnapshotOnCreate},
 	}
@@ -762,7 +625,25 @@ func snapshotCreateHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// The LVs of encrypted bricks are under the mappings their file
+	// systems are on, which cannot be snapshotted as LVs
+	if vol.IsEncrypted() {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrSnapshotEncrypted)
+		return
+	}
+
 	txn.Nodes = vol.Nodes()
+	barrierEnable, err := barrier.EnableStep(txn.Ctx, vol.Name, barrier.DefaultTimeout, txn.Nodes)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	barrierDisable, err := barrier.DisableStep(txn.Ctx, vol.Name, txn.Nodes)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
 	txn.Steps = []*transaction.Step{
 		{
 			DoFunc: "snap-create.Validate",
@@ -773,12 +654,7 @@ func snapshotCreateHandler(w http.ResponseWriter, r *http.Request) {
 			Nodes:  []uuid.UUID{gdctx.MyUUID},
 			Sync:   true,
 		},
-		{
-			DoFunc:   "snap-create.ActivateBarrier",
-			UndoFunc: "snap-create.DeactivateBarrier",
-			Nodes:    txn.Nodes,
-		},
-
+		barrierEnable,
 		{
 			DoFunc:   "snap-create.TakeBrickSnapshots",
 			UndoFunc: "snap-create.UndoBrickSnapshots",
@@ -786,11 +662,7 @@ func snapshotCreateHandler(w http.ResponseWriter, r *http.Request) {
 			// All bricks need to be barriered before taking a snapshot
 			Sync: true,
 		},
-		{
-			DoFunc: "snap-create.DeactivateBarrier",
-			Nodes:  txn.Nodes,
-		},
-
+		barrierDisable,
 		{
 			DoFunc:   "snap-create.StoreSnapshot",
 			UndoFunc: "snap-create.UndoStoreSnapshotOnCreate",
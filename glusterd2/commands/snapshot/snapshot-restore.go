@@ -354,6 +354,11 @@ func snapshotRestoreHandler(w http.ResponseWriter, r *http.Request) {
 		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errMsg)
 		return
 	}
+	// Restoring would replace the files retained on the volume
+	if vol.IsWORM() {
+		restutils.SendHTTPError(ctx, w, http.StatusForbidden, errors.ErrVolumeWORM)
+		return
+	}
 
 	bricksAutoProvisioned := vol.IsAutoProvisioned() || vol.IsSnapshotProvisioned()
 	txn.Steps = []*transaction.Step{
@@ -0,0 +1,67 @@
+// Package templatecommands implements the commands to view and customize the
+// volfile templates, and to pin the versions of the templates used by volumes
+package templatecommands
+
+import (
+	"github.com/gluster/glusterd2/glusterd2/servers/rest/route"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/utils"
+)
+
+// Command is a holding struct used to implement the GlusterD Command interface
+type Command struct {
+}
+
+// Routes returns command routes. Required for the Command interface.
+func (c *Command) Routes() route.Routes {
+	return route.Routes{
+		route.Route{
+			Name:         "TemplateList",
+			Method:       "GET",
+			Pattern:      "/templates",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.VolfileTemplateListResp)(nil)),
+			HandlerFunc:  templateListHandler},
+		route.Route{
+			Name:         "TemplateGet",
+			Method:       "GET",
+			Pattern:      "/templates/{name}",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.VolfileTemplateResp)(nil)),
+			HandlerFunc:  templateGetHandler},
+		route.Route{
+			Name:         "TemplateVersions",
+			Method:       "GET",
+			Pattern:      "/templates/{name}/versions",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.VolfileTemplateVersionsResp)(nil)),
+			HandlerFunc:  templateVersionsHandler},
+		route.Route{
+			Name:         "TemplateSet",
+			Method:       "PUT",
+			Pattern:      "/templates/{name}",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.VolfileTemplateReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.VolfileTemplateResp)(nil)),
+			HandlerFunc:  templateSetHandler},
+		route.Route{
+			Name:         "VolumeTemplatePin",
+			Method:       "PUT",
+			Pattern:      "/volumes/{volname}/templates/{name}",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.VolumeTemplatePinReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.VolfileTemplateResp)(nil)),
+			HandlerFunc:  volumeTemplatePinHandler},
+		route.Route{
+			Name:        "VolumeTemplateUnpin",
+			Method:      "DELETE",
+			Pattern:     "/volumes/{volname}/templates/{name}",
+			Version:     1,
+			HandlerFunc: volumeTemplateUnpinHandler},
+	}
+}
+
+// RegisterStepFuncs implements a required function for the Command interface
+func (c *Command) RegisterStepFuncs() {
+	return
+}
@@ -0,0 +1,209 @@
+package templatecommands
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/volgen"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/gorilla/mux"
+)
+
+const (
+	// templates are locked by their name prefixed with this
+	lockKeyPrefix = "volfile-template."
+)
+
+func toAPIXlators(xls []volgen.Xlator) []api.VolfileXlator {
+	if len(xls) == 0 {
+		return nil
+	}
+	out := make([]api.VolfileXlator, 0, len(xls))
+	for _, xl := range xls {
+		out = append(out, api.VolfileXlator{
+			NameTmpl:        xl.NameTmpl,
+			Type:            xl.Type,
+			TypeTmpl:        xl.TypeTmpl,
+			OnlyLocalBricks: xl.OnlyLocalBricks,
+			Disabled:        xl.Disabled,
+			EnableByOption:  xl.EnableByOption,
+			Options:         xl.Options,
+			IgnoreOptions:   xl.IgnoreOptions,
+		})
+	}
+	return out
+}
+
+func fromAPIXlators(xls []api.VolfileXlator) []volgen.Xlator {
+	if len(xls) == 0 {
+		return nil
+	}
+	out := make([]volgen.Xlator, 0, len(xls))
+	for _, xl := range xls {
+		out = append(out, volgen.Xlator{
+			NameTmpl:        xl.NameTmpl,
+			Type:            xl.Type,
+			TypeTmpl:        xl.TypeTmpl,
+			OnlyLocalBricks: xl.OnlyLocalBricks,
+			Disabled:        xl.Disabled,
+			EnableByOption:  xl.EnableByOption,
+			Options:         xl.Options,
+			IgnoreOptions:   xl.IgnoreOptions,
+		})
+	}
+	return out
+}
+
+func createTemplateResp(t *volgen.StoredTemplate) api.VolfileTemplateResp {
+	return api.VolfileTemplateResp{
+		Version:   t.Version,
+		UpdatedAt: t.UpdatedAt,
+		Template: api.VolfileTemplate{
+			Name:               t.Template.Name,
+			Level:              t.Template.Level.String(),
+			Xlators:            toAPIXlators(t.Template.Xlators),
+			VolumeGraphXlators: toAPIXlators(t.Template.VolumeGraphXlators),
+			SubvolGraphXlators: toAPIXlators(t.Template.SubvolGraphXlators),
+			BrickGraphXlators:  toAPIXlators(t.Template.BrickGraphXlators),
+		},
+	}
+}
+
+func templateFromReq(req *api.VolfileTemplateReq) (*volgen.Template, error) {
+	level, err := volgen.ParseVolfileLevel(req.Level)
+	if err != nil {
+		return nil, err
+	}
+	return &volgen.Template{
+		Name:               req.Name,
+		Level:              level,
+		Xlators:            fromAPIXlators(req.Xlators),
+		VolumeGraphXlators: fromAPIXlators(req.VolumeGraphXlators),
+		SubvolGraphXlators: fromAPIXlators(req.SubvolGraphXlators),
+		BrickGraphXlators:  fromAPIXlators(req.BrickGraphXlators),
+	}, nil
+}
+
+func templateListHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+
+	resp := make(api.VolfileTemplateListResp, 0)
+	for _, name := range volgen.TemplateNames() {
+		t, err := volgen.GetLatestTemplate(name)
+		if err != nil {
+			logger.WithError(err).WithField("template", name).Error("failed to get template")
+			restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+			return
+		}
+		resp = append(resp, createTemplateResp(t))
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+func templateGetHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	name := mux.Vars(r)["name"]
+
+	var (
+		t   *volgen.StoredTemplate
+		err error
+	)
+	if v := r.URL.Query().Get("version"); v != "" {
+		version, perr := strconv.Atoi(v)
+		if perr != nil || version < 0 {
+			restutils.SendHTTPError(ctx, w, http.StatusBadRequest, fmt.Sprintf("invalid template version %q", v))
+			return
+		}
+		t, err = volgen.GetTemplateVersion(name, version)
+	} else {
+		t, err = volgen.GetLatestTemplate(name)
+	}
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, createTemplateResp(t))
+}
+
+func templateVersionsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	name := mux.Vars(r)["name"]
+
+	builtin, err := volgen.GetTemplateVersion(name, 0)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	stored, err := volgen.GetStoredTemplates(name)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	resp := api.VolfileTemplateVersionsResp{createTemplateResp(builtin)}
+	for i := range stored {
+		resp = append(resp, createTemplateResp(&stored[i]))
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+func templateSetHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+	name := mux.Vars(r)["name"]
+
+	var req api.VolfileTemplateReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
+		return
+	}
+	if req.Name == "" {
+		req.Name = name
+	}
+	if req.Name != name {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest,
+			fmt.Sprintf("name of the template %s does not match %s", req.Name, name))
+		return
+	}
+
+	tmpl, err := templateFromReq(&req)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+	if err := volgen.ValidateTemplate(tmpl); err != nil {
+		if err == gderrors.ErrInvalidVolFileTmplName {
+			restutils.SendHTTPError(ctx, w, http.StatusNotFound, err)
+			return
+		}
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	txn, err := transaction.NewTxnWithLocks(ctx, lockKeyPrefix+name)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	defer txn.Done()
+
+	t, err := volgen.SaveTemplate(tmpl)
+	if err != nil {
+		logger.WithError(err).WithField("template", name).Error("failed to save template")
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	logger.WithField("template", name).WithField("version", t.Version).Info("saved new version of template")
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, createTemplateResp(t))
+}
@@ -0,0 +1,29 @@
+package templatecommands
+
+import (
+	"testing"
+
+	"github.com/gluster/glusterd2/glusterd2/volgen"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTemplateConversion(t *testing.T) {
+	for _, name := range volgen.TemplateNames() {
+		builtin, err := volgen.GetTemplate(volgen.DefaultTemplateNamespace, name)
+		assert.Nil(t, err)
+
+		resp := createTemplateResp(&volgen.StoredTemplate{Template: *builtin})
+		assert.Equal(t, 0, resp.Version)
+		assert.Equal(t, builtin.Level.String(), resp.Template.Level)
+
+		req := api.VolfileTemplateReq(resp.Template)
+		tmpl, err := templateFromReq(&req)
+		assert.Nil(t, err)
+		assert.Equal(t, builtin, tmpl)
+	}
+
+	_, err := templateFromReq(&api.VolfileTemplateReq{Name: "brick", Level: "node"})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,110 @@
+package templatecommands
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/volgen"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/gorilla/mux"
+)
+
+// lockVolume locks the volume and returns its volinfo. The response is sent
+// on failure.
+func lockVolume(w http.ResponseWriter, r *http.Request, volname string) (*transaction.Txn, *volume.Volinfo, bool) {
+	ctx := r.Context()
+
+	txn, err := transaction.NewTxnWithLocks(ctx, volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return nil, nil, false
+	}
+
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		txn.Done()
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return nil, nil, false
+	}
+	if volinfo.Metadata == nil {
+		volinfo.Metadata = make(map[string]string)
+	}
+	return txn, volinfo, true
+}
+
+func volumeTemplatePinHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+	p := mux.Vars(r)
+	volname, name := p["volname"], p["name"]
+
+	var req api.VolumeTemplatePinReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
+		return
+	}
+
+	txn, volinfo, ok := lockVolume(w, r, volname)
+	if !ok {
+		return
+	}
+	defer txn.Done()
+
+	if ns, exists := volinfo.Metadata[volgen.TemplateMetadataKey]; exists && ns != volgen.DefaultTemplateNamespace {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest,
+			"volume uses templates of namespace "+ns+", versions can be pinned only for the default templates")
+		return
+	}
+
+	t, err := volgen.GetTemplateVersion(name, req.Version)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	volinfo.Metadata[volgen.TemplateVersionMetadataKeyPrefix+name] = strconv.Itoa(req.Version)
+	if err := volume.AddOrUpdateVolumeFunc(volinfo); err != nil {
+		logger.WithError(err).WithField("volume", volname).Error("failed to store volume info")
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	logger.WithField("volume", volname).WithField("template", name).WithField(
+		"version", req.Version).Info("pinned template version")
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, createTemplateResp(t))
+}
+
+func volumeTemplateUnpinHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+	p := mux.Vars(r)
+	volname, name := p["volname"], p["name"]
+
+	txn, volinfo, ok := lockVolume(w, r, volname)
+	if !ok {
+		return
+	}
+	defer txn.Done()
+
+	key := volgen.TemplateVersionMetadataKeyPrefix + name
+	if _, pinned := volinfo.Metadata[key]; pinned {
+		delete(volinfo.Metadata, key)
+		if err := volume.AddOrUpdateVolumeFunc(volinfo); err != nil {
+			logger.WithError(err).WithField("volume", volname).Error("failed to store volume info")
+			restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+			return
+		}
+		logger.WithField("volume", volname).WithField("template", name).Info("unpinned template version")
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusNoContent, nil)
+}
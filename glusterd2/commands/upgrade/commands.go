@@ -0,0 +1,51 @@
+// Package upgradecommands implements the commands to drive the rolling
+// upgrade of the peers
+package upgradecommands
+
+import (
+	"github.com/gluster/glusterd2/glusterd2/servers/rest/route"
+	"github.com/gluster/glusterd2/glusterd2/upgrade"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/utils"
+)
+
+// Command is a holding struct used to implement the GlusterD Command interface
+type Command struct {
+}
+
+// Routes returns command routes. Required for the Command interface.
+func (c *Command) Routes() route.Routes {
+	return route.Routes{
+		route.Route{
+			Name:         "UpgradeStart",
+			Method:       "PUT",
+			Pattern:      "/cluster/upgrade",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.UpgradeReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.UpgradeStatus)(nil)),
+			HandlerFunc:  upgradeStartHandler,
+		},
+		route.Route{
+			Name:         "UpgradeStatus",
+			Method:       "GET",
+			Pattern:      "/cluster/upgrade",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.UpgradeStatus)(nil)),
+			HandlerFunc:  upgradeStatusHandler,
+		},
+		route.Route{
+			Name:         "UpgradeAbort",
+			Method:       "DELETE",
+			Pattern:      "/cluster/upgrade",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.UpgradeStatus)(nil)),
+			HandlerFunc:  upgradeAbortHandler,
+		},
+	}
+}
+
+// RegisterStepFuncs registers transaction step functions with
+// the Global Transaction Step Registry
+func (c *Command) RegisterStepFuncs() {
+	upgrade.RegisterStepFuncs()
+}
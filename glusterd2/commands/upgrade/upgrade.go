@@ -0,0 +1,83 @@
+package upgradecommands
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/upgrade"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/errors"
+)
+
+func upgradeStartHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+
+	var req api.UpgradeReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrJSONParsingFailed)
+		return
+	}
+
+	txn, err := transaction.NewTxnWithLocks(ctx, upgrade.LockKey)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	defer txn.Done()
+
+	st, err := upgrade.Begin(&req)
+	if err != nil {
+		if err == errors.ErrUpgradeInProgress {
+			restutils.SendHTTPError(ctx, w, http.StatusConflict, err)
+			return
+		}
+		logger.WithError(err).Error("failed to start the rolling upgrade")
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	logger.WithField("target-version", req.TargetVersion).Info("rolling upgrade started")
+	restutils.SendHTTPResponse(ctx, w, http.StatusAccepted, st)
+}
+
+func upgradeStatusHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	st, err := upgrade.GetStatus()
+	if err != nil {
+		if err == errors.ErrUpgradeNotFound {
+			restutils.SendHTTPError(ctx, w, http.StatusNotFound, err)
+			return
+		}
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, st)
+}
+
+func upgradeAbortHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	txn, err := transaction.NewTxnWithLocks(ctx, upgrade.LockKey)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	defer txn.Done()
+
+	st, err := upgrade.Abort()
+	if err != nil {
+		if err == errors.ErrUpgradeNotFound {
+			restutils.SendHTTPError(ctx, w, http.StatusNotFound, err)
+			return
+		}
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, st)
+}
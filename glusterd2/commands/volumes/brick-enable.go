@@ -0,0 +1,74 @@
+package volumecommands
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/brickhealth"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/gorilla/mux"
+)
+
+// volumeBrickEnableHandler re-enables a brick marked degraded by a failed
+// health-check or by exceeding its I/O error budget. The brick is started
+// again if it was stopped.
+func volumeBrickEnableHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+	volname := mux.Vars(r)["volname"]
+
+	var req api.BrickEnableReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
+		return
+	}
+
+	txn, err := transaction.NewTxnWithLocks(ctx, volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	defer txn.Done()
+
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	var binfo *brick.Brickinfo
+	for _, b := range volinfo.GetBricks() {
+		if b.PeerID.String() == req.PeerID && b.Path == req.Path {
+			binfo = &b
+			break
+		}
+	}
+	if binfo == nil {
+		restutils.SendHTTPError(ctx, w, http.StatusNotFound, gderrors.ErrBrickNotFound)
+		return
+	}
+
+	step, err := brickhealth.EnableStep(txn.Ctx, *binfo)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	txn.Steps = []*transaction.Step{step}
+
+	if err := txn.Do(); err != nil {
+		logger.WithError(err).WithField("brick", binfo.String()).Error("transaction to enable the brick failed")
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, brick.CreateBrickInfo(binfo))
+}
@@ -51,6 +51,7 @@ func replaceVolinfo(c transaction.TxnCtx) error {
 	if err != nil {
 		return err
 	}
+	newBrickInfos[0].User = srcBrickInfo.User
 	newBrickInfo := newBrickInfos[0]
 
 	// Retaining the brick position same as old brick
@@ -1,6 +1,8 @@
 package volumecommands
 
 import (
+	"context"
+	"errors"
 	"net/http"
 
 	"github.com/gluster/glusterd2/glusterd2/brick"
@@ -139,18 +141,29 @@ LOOP:
 	// Get new brick from the available vgs
 	newBrick := bricksplanner.GetNewBrick(availableVgs, brickInfo, vol, subVolIndex, brickIndex)
 
+	if status, err := replaceBrick(ctx, vol, srcBrickInfo, subVolIndex, brickIndex, newBrick); err != nil {
+		logger.WithError(err).WithField("volume-name", volname).Error("replace brick transaction failed")
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	resp := createReplaceBrickResp(vol)
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+
+	return
+}
+
+// replaceBrick replaces the brick of the volume with the new brick, which
+// is provisioned on its peer
+func replaceBrick(ctx context.Context, vol *volume.Volinfo, srcBrickInfo brick.Brickinfo, subVolIndex, brickIndex int, newBrick api.BrickReq) (int, error) {
 	peerID := uuid.Parse(newBrick.PeerID)
 	if peerID == nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, "peer id of new brick could not be parsed")
-		return
+		return http.StatusInternalServerError, errors.New("peer id of new brick could not be parsed")
 	}
 	allPeerIDs := vol.Nodes()
 	nodes := []uuid.UUID{peerID}
-	txn, err := transaction.NewTxnWithLocks(ctx, volname)
+	txn, err := transaction.NewTxnWithLocks(ctx, vol.Name)
 	if err != nil {
-		status, err := restutils.ErrToStatusCode(err)
-		restutils.SendHTTPError(ctx, w, status, err)
-		return
+		return restutils.ErrToStatusCode(err)
 	}
 	defer txn.Done()
 
@@ -186,36 +199,25 @@ LOOP:
 	}
 
 	if err = txn.Ctx.Set("newBrick", &newBrick); err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return http.StatusInternalServerError, err
 	}
 	if err = txn.Ctx.Set("srcBrickInfo", &srcBrickInfo); err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return http.StatusInternalServerError, err
 	}
 	if err = txn.Ctx.Set("subVolIndex", &subVolIndex); err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return http.StatusInternalServerError, err
 	}
 	if err = txn.Ctx.Set("brickIndex", &brickIndex); err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return http.StatusInternalServerError, err
 	}
 	if err = txn.Ctx.Set("volinfo", &vol); err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return http.StatusInternalServerError, err
 	}
 
 	if err = txn.Do(); err != nil {
-		logger.WithError(err).WithField("volume-name", volname).Error("replace brick transaction failed")
-		status, err := restutils.ErrToStatusCode(err)
-		restutils.SendHTTPError(ctx, w, status, err)
-		return
+		return restutils.ErrToStatusCode(err)
 	}
-	resp := createReplaceBrickResp(vol)
-	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
-
-	return
+	return http.StatusOK, nil
 }
 
 // Replace brick resp
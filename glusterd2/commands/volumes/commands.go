@@ -2,7 +2,12 @@
 package volumecommands
 
 import (
+	"github.com/gluster/glusterd2/glusterd2/adaptivethrottle"
+	"github.com/gluster/glusterd2/glusterd2/barrier"
+	"github.com/gluster/glusterd2/glusterd2/brickhealth"
+	"github.com/gluster/glusterd2/glusterd2/datatls"
 	"github.com/gluster/glusterd2/glusterd2/servers/rest/route"
+	"github.com/gluster/glusterd2/glusterd2/volumetrash"
 	"github.com/gluster/glusterd2/pkg/api"
 	"github.com/gluster/glusterd2/pkg/utils"
 )
@@ -52,6 +57,14 @@ func (c *Command) Routes() route.Routes {
 			RequestType:  utils.GetTypeString((*api.VolOptionReq)(nil)),
 			ResponseType: utils.GetTypeString((*api.VolumeOptionResp)(nil)),
 			HandlerFunc:  volumeOptionsHandler},
+		route.Route{
+			Name:         "VolumeOptionsBatch",
+			Method:       "POST",
+			Pattern:      "/volumes/options:batch",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.VolOptionsBatchReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.VolumeOptionsBatchResp)(nil)),
+			HandlerFunc:  volumeOptionsBatchHandler},
 		route.Route{
 			Name:         "VolumeReset",
 			Method:       "DELETE", // Do DELETE requests have a body? Should this be query param ?
@@ -74,6 +87,13 @@ func (c *Command) Routes() route.Routes {
 			Version:     1,
 			RequestType: utils.GetTypeString((*api.OptionGroupReq)(nil)),
 			HandlerFunc: optionGroupCreateHandler},
+		route.Route{
+			Name:         "OptionGroupGet",
+			Method:       "GET",
+			Pattern:      "/volumes/options-group/{groupname}",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.OptionGroupGetResp)(nil)),
+			HandlerFunc:  optionGroupGetHandler},
 		route.Route{
 			Name:        "OptionGroupDelete",
 			Method:      "DELETE",
@@ -85,7 +105,28 @@ func (c *Command) Routes() route.Routes {
 			Method:      "DELETE",
 			Pattern:     "/volumes/{volname}",
 			Version:     1,
+			RequestType: utils.GetTypeString((*api.VolumeDeleteReq)(nil)),
 			HandlerFunc: volumeDeleteHandler},
+		route.Route{
+			Name:         "VolumeTrashList",
+			Method:       "GET",
+			Pattern:      "/trash/volumes",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.VolumeTrashListResp)(nil)),
+			HandlerFunc:  volumeTrashListHandler},
+		route.Route{
+			Name:         "VolumeUndelete",
+			Method:       "POST",
+			Pattern:      "/trash/volumes/{volid}/undelete",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.VolumeUndeleteResp)(nil)),
+			HandlerFunc:  volumeUndeleteHandler},
+		route.Route{
+			Name:        "VolumePurge",
+			Method:      "DELETE",
+			Pattern:     "/trash/volumes/{volid}",
+			Version:     1,
+			HandlerFunc: volumePurgeHandler},
 		route.Route{
 			Name:         "VolumeInfo",
 			Method:       "GET",
@@ -93,6 +134,14 @@ func (c *Command) Routes() route.Routes {
 			Version:      1,
 			ResponseType: utils.GetTypeString((*api.VolumeGetResp)(nil)),
 			HandlerFunc:  volumeInfoHandler},
+		route.Route{
+			Name:         "VolumePatch",
+			Method:       "PATCH",
+			Pattern:      "/volumes/{volname}",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.VolPatchReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.VolumeGetResp)(nil)),
+			HandlerFunc:  volumePatchHandler},
 		route.Route{
 			Name:         "VolumeBricksStatus",
 			Method:       "GET",
@@ -100,6 +149,14 @@ func (c *Command) Routes() route.Routes {
 			Version:      1,
 			ResponseType: utils.GetTypeString((*api.BricksStatusResp)(nil)),
 			HandlerFunc:  volumeBricksStatusHandler},
+		route.Route{
+			Name:         "VolumeBrickEnable",
+			Method:       "POST",
+			Pattern:      "/volumes/{volname}/bricks/enable",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.BrickEnableReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.BrickInfo)(nil)),
+			HandlerFunc:  volumeBrickEnableHandler},
 		route.Route{
 			Name:         "VolumeStatus",
 			Method:       "GET",
@@ -107,6 +164,13 @@ func (c *Command) Routes() route.Routes {
 			Version:      1,
 			ResponseType: utils.GetTypeString((*api.VolumeStatusResp)(nil)),
 			HandlerFunc:  volumeStatusHandler},
+		route.Route{
+			Name:         "VolumeSize",
+			Method:       "GET",
+			Pattern:      "/volumes/{volname}/size",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.VolumeSizeResp)(nil)),
+			HandlerFunc:  volumeSizeHandler},
 		route.Route{
 			Name:         "VolumeList",
 			Method:       "GET",
@@ -127,6 +191,7 @@ func (c *Command) Routes() route.Routes {
 			Method:       "POST",
 			Pattern:      "/volumes/{volname}/stop",
 			Version:      1,
+			RequestType:  utils.GetTypeString((*api.VolumeStopReq)(nil)),
 			ResponseType: utils.GetTypeString((*api.VolumeStopResp)(nil)),
 			HandlerFunc:  volumeStopHandler},
 		route.Route{
@@ -159,6 +224,145 @@ func (c *Command) Routes() route.Routes {
 			Version:      1,
 			ResponseType: utils.GetTypeString((*api.BrickProfileInfo)(nil)),
 			HandlerFunc:  volumeProfileHandler},
+		route.Route{
+			Name:         "VolumeClients",
+			Method:       "GET",
+			Pattern:      "/volumes/{volname}/clients",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.VolumeClientsResp)(nil)),
+			HandlerFunc:  volumeClientsHandler},
+		route.Route{
+			Name:         "VolumeClientDisconnect",
+			Method:       "POST",
+			Pattern:      "/volumes/{volname}/clients/disconnect",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.ClientDisconnectReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.ClientDisconnectResp)(nil)),
+			HandlerFunc:  volumeClientDisconnectHandler},
+		route.Route{
+			Name:         "VolumeBarrierEnable",
+			Method:       "POST",
+			Pattern:      "/volumes/{volname}/barrier/enable",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.VolumeBarrierReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.VolumeBarrierResp)(nil)),
+			HandlerFunc:  volumeBarrierEnableHandler},
+		route.Route{
+			Name:         "VolumeBarrierDisable",
+			Method:       "POST",
+			Pattern:      "/volumes/{volname}/barrier/disable",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.VolumeBarrierResp)(nil)),
+			HandlerFunc:  volumeBarrierDisableHandler},
+		route.Route{
+			Name:         "VolumeHalo",
+			Method:       "GET",
+			Pattern:      "/volumes/{volname}/halo",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.VolumeHaloResp)(nil)),
+			HandlerFunc:  volumeHaloHandler},
+		route.Route{
+			Name:         "VolumeLeases",
+			Method:       "GET",
+			Pattern:      "/volumes/{volname}/leases",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.VolumeLeasesResp)(nil)),
+			HandlerFunc:  volumeLeasesHandler},
+		route.Route{
+			Name:         "VolumeAdvisor",
+			Method:       "GET",
+			Pattern:      "/volumes/{volname}/advisor",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.VolumeAdvisorResp)(nil)),
+			HandlerFunc:  volumeAdvisorHandler},
+		route.Route{
+			Name:         "VolumeSetMode",
+			Method:       "POST",
+			Pattern:      "/volumes/{volname}/mode",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.VolumeModeReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.VolumeModeResp)(nil)),
+			HandlerFunc:  volumeSetModeHandler},
+		route.Route{
+			Name:         "VolumeTLS",
+			Method:       "GET",
+			Pattern:      "/volumes/{volname}/tls",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.VolumeTLSResp)(nil)),
+			HandlerFunc:  volumeTLSHandler},
+		route.Route{
+			Name:         "VolumeTLSEnable",
+			Method:       "POST",
+			Pattern:      "/volumes/{volname}/tls/enable",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.VolumeTLSReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.VolumeTLSResp)(nil)),
+			HandlerFunc:  volumeTLSEnableHandler},
+		route.Route{
+			Name:         "VolumeTLSDisable",
+			Method:       "POST",
+			Pattern:      "/volumes/{volname}/tls/disable",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.VolumeTLSResp)(nil)),
+			HandlerFunc:  volumeTLSDisableHandler},
+		route.Route{
+			Name:         "VolumeTLSRotate",
+			Method:       "POST",
+			Pattern:      "/volumes/{volname}/tls/rotate",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.VolumeTLSResp)(nil)),
+			HandlerFunc:  volumeTLSRotateHandler},
+		route.Route{
+			Name:         "VolumeConfigRestore",
+			Method:       "POST",
+			Pattern:      "/volumes/{volname}/config/restore",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.VolumeConfigRestoreReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.VolumeOptionResp)(nil)),
+			HandlerFunc:  volumeConfigRestoreHandler},
+		route.Route{
+			Name:         "PeerEvacuate",
+			Method:       "POST",
+			Pattern:      "/peers/{peerid}/evacuate",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.PeerEvacuateReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.PeerEvacuateStatus)(nil)),
+			HandlerFunc:  peerEvacuateHandler},
+		route.Route{
+			Name:         "PeerEvacuationStatus",
+			Method:       "GET",
+			Pattern:      "/peers/{peerid}/evacuate",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.PeerEvacuateStatus)(nil)),
+			HandlerFunc:  peerEvacuationStatusHandler},
+		route.Route{
+			Name:         "PlacementPreview",
+			Method:       "GET",
+			Pattern:      "/cluster/placement-preview",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.PlacementPreviewResp)(nil)),
+			HandlerFunc:  placementPreviewHandler},
+		route.Route{
+			Name:         "VolumeSubdirExportCreate",
+			Method:       "POST",
+			Pattern:      "/volumes/{volname}/subdirs",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.SubdirExportReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.SubdirExport)(nil)),
+			HandlerFunc:  volumeSubdirExportCreateHandler},
+		route.Route{
+			Name:         "VolumeSubdirExportList",
+			Method:       "GET",
+			Pattern:      "/volumes/{volname}/subdirs",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.SubdirExportListResp)(nil)),
+			HandlerFunc:  volumeSubdirExportListHandler},
+		route.Route{
+			Name:        "VolumeSubdirExportDelete",
+			Method:      "DELETE",
+			Pattern:     "/volumes/{volname}/subdirs/{subdir:.*}",
+			Version:     1,
+			HandlerFunc: volumeSubdirExportDeleteHandler},
 	}
 }
 
@@ -175,4 +379,14 @@ func (c *Command) RegisterStepFuncs() {
 	registerVolStatedumpFuncs()
 	registerReplaceBrickStepFuncs()
 	registerVolProfileStepFuncs()
+	registerVolSubdirStepFuncs()
+	registerVolClientsStepFuncs()
+	registerVolLeasesStepFuncs()
+	registerVolAdvisorStepFuncs()
+	registerVolSizeStepFuncs()
+	barrier.RegisterStepFuncs()
+	brickhealth.RegisterStepFuncs()
+	datatls.RegisterStepFuncs()
+	adaptivethrottle.RegisterStepFuncs()
+	volumetrash.RegisterStepFuncs()
 }
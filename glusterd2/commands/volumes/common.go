@@ -14,6 +14,7 @@ import (
 	"github.com/gluster/glusterd2/glusterd2/brick"
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
 	"github.com/gluster/glusterd2/glusterd2/options"
+	"github.com/gluster/glusterd2/glusterd2/opversion"
 	"github.com/gluster/glusterd2/glusterd2/servers/sunrpc"
 	"github.com/gluster/glusterd2/glusterd2/store"
 	"github.com/gluster/glusterd2/glusterd2/transaction"
@@ -59,12 +60,31 @@ func validateOptions(opts map[string]string, flags api.VolOptionFlags) error {
 		if err := o.Validate(v); err != nil {
 			return fmt.Errorf("failed to validate value(%s) for key(%s): %s", k, v, err.Error())
 		}
-		// TODO: Check op-version
+
+		if err := opversion.Check(opversion.OfOption(o)); err != nil {
+			return fmt.Errorf("option %s cannot be set: %s", k, err)
+		}
 	}
 
 	return nil
 }
 
+// requiredOpVersion returns the op-version the peers must support to set the
+// given options
+func requiredOpVersion(opts map[string]string) int {
+	required := opversion.Initial
+	for k := range opts {
+		o, err := xlator.FindOption(k)
+		if err != nil {
+			continue
+		}
+		if v := opversion.OfOption(o); v > required {
+			required = v
+		}
+	}
+	return required
+}
+
 func validateXlatorOptions(opts map[string]string, volinfo *volume.Volinfo) error {
 	var toreplace [][]string
 	for k, v := range opts {
@@ -115,6 +135,30 @@ func getGroupOptionsFromStore() (map[string]*api.OptionGroup, error) {
 	return groupOptions, nil
 }
 
+func putGroupOptionsInStore(groupOptions map[string]*api.OptionGroup) error {
+	groupOptionsJSON, err := json.Marshal(groupOptions)
+	if err != nil {
+		return err
+	}
+	_, err = store.Put(context.TODO(), "groupoptions", string(groupOptionsJSON))
+	return err
+}
+
+// builtinGroupOption returns a copy of the builtin group, as shipped with
+// glusterd2
+func builtinGroupOption(name string) (*api.OptionGroup, bool) {
+	g, ok := defaultGroupOptions[name]
+	if !ok {
+		return nil, false
+	}
+	return &api.OptionGroup{
+		Name:        g.Name,
+		Options:     append([]api.VolumeOption(nil), g.Options...),
+		Description: g.Description,
+		Builtin:     true,
+	}, true
+}
+
 func expandGroupOptions(opts map[string]string) (map[string]string, error) {
 
 	groupOptions, err := getGroupOptionsFromStore()
@@ -180,7 +224,9 @@ func notifyVolfileChange(c transaction.TxnCtx) error {
 		return nil
 	}
 
-	sunrpc.FetchSpecNotify(c)
+	// Failing to notify clients does not fail the transaction, the
+	// clients fetch the new volfiles on reconnecting
+	sunrpc.VolfileChangeNotify(c, volinfo.Name)
 
 	return nil
 }
@@ -205,8 +251,9 @@ func validateBricks(c transaction.TxnCtx) error {
 		return err
 	}
 
+	// The bricks of the request must not be nested in each other either
 	var allLocalBricks []brick.Brickinfo
-	for _, b := range allBricks {
+	for _, b := range append(allBricks, bricks...) {
 		if uuid.Equal(gdctx.MyUUID, b.PeerID) {
 			allLocalBricks = append(allLocalBricks, b)
 		}
@@ -253,6 +300,14 @@ func initBricks(c transaction.TxnCtx) error {
 			continue
 		}
 
+		if checks.CleanXattrs {
+			if err = brick.CleanXattrs(b.Path); err != nil {
+				log.WithError(err).WithField(
+					"path", b.Path).Error("failed to clean the xattrs of the brick")
+				return err
+			}
+		}
+
 		err = unix.Setxattr(b.Path, volumeIDXattrKey, []byte(b.VolumeID), flags)
 		if err != nil {
 			log.WithError(err).WithFields(log.Fields{
@@ -268,6 +323,19 @@ func initBricks(c transaction.TxnCtx) error {
 				"path", path).Error("MkdirAll failed")
 			return err
 		}
+
+		if err = b.Chown(); err != nil {
+			log.WithError(err).WithFields(log.Fields{
+				"path": b.Path,
+				"user": b.User}).Error("failed to give the brick to its user")
+			return err
+		}
+
+		if err = b.SetLabel(); err != nil {
+			log.WithError(err).WithField(
+				"path", b.Path).Error("failed to set the SELinux context of the brick")
+			return err
+		}
 	}
 
 	return nil
@@ -348,26 +416,52 @@ func loadDefaultGroupOptions() error {
 		if err != nil {
 			return err
 		}
+		// Groups added after the file was generated, for example by
+		// an upgrade, are taken from the builtin groups
+		for name, g := range defaultGroupOptions {
+			if _, exists := grpOpts[name]; !exists {
+				grpOpts[name] = g
+			}
+		}
 		defaultGroupOptions = grpOpts
 		return nil
 	}
 	return err
 }
 
-// InitDefaultGroupOptions loads the default group option map into the store
+// InitDefaultGroupOptions loads the default group option map into the store.
+// The groups created by the site and its overrides of the builtin groups are
+// kept, the other builtin groups are updated to the ones shipped.
 func InitDefaultGroupOptions() error {
 	err := loadDefaultGroupOptions()
 	if err != nil {
 		return err
 	}
-	groupOptions, err := json.Marshal(defaultGroupOptions)
+
+	stored, err := getGroupOptionsFromStore()
 	if err != nil {
 		return err
 	}
-	if _, err := store.Put(context.TODO(), "groupoptions", string(groupOptions)); err != nil {
-		return err
+
+	groupOptions := make(map[string]*api.OptionGroup)
+	for name := range defaultGroupOptions {
+		groupOptions[name], _ = builtinGroupOption(name)
 	}
-	return nil
+	for name, g := range stored {
+		_, builtin := defaultGroupOptions[name]
+		if builtin && !g.Overridden {
+			continue
+		}
+		if !builtin {
+			// An override of a builtin group which is no longer
+			// shipped is kept as a group of the site
+			g.Builtin = false
+			g.Overridden = false
+		}
+		groupOptions[name] = g
+	}
+
+	return putGroupOptionsInStore(groupOptions)
 }
 
 //validateVolumeFlags checks for Flags in volume create and expand
@@ -239,6 +239,37 @@ var defaultGroupOptions = map[string]*api.OptionGroup{
 		},
 		Description: "Enable TLS for the volume for both bricks and clients",
 	},
+	"cache-invalidation": {
+		Name: "cache-invalidation",
+		Options: []api.VolumeOption{
+			{Name: "features/upcall.cache-invalidation", OnValue: "on"},
+			{Name: "features/upcall.cache-invalidation-timeout", OnValue: "600"},
+			{Name: "performance/md-cache", OnValue: "on"},
+			{Name: "performance/md-cache.cache-invalidation", OnValue: "on"},
+			{Name: "performance/md-cache.md-cache-timeout", OnValue: "600"},
+			{Name: "performance/quick-read.cache-invalidation", OnValue: "on"},
+		},
+		Description: "Enable the invalidation by the bricks of the caches of the clients, NFS-Ganesha and Samba, for the changes made through other protocols",
+	},
+	"small-file": {
+		Name: "small-file",
+		Options: []api.VolumeOption{
+			{Name: "features/upcall.cache-invalidation", OnValue: "on"},
+			{Name: "features/upcall.cache-invalidation-timeout", OnValue: "600"},
+			{Name: "protocol/server.inode-lru-limit", OnValue: "200000"},
+			{Name: "performance/md-cache", OnValue: "on"},
+			{Name: "performance/md-cache.cache-invalidation", OnValue: "on"},
+			{Name: "performance/md-cache.md-cache-timeout", OnValue: "600"},
+			{Name: "performance/open-behind", OnValue: "on"},
+			{Name: "performance/quick-read", OnValue: "on"},
+			{Name: "performance/quick-read.cache-invalidation", OnValue: "on"},
+			{Name: "performance/quick-read.qr-cache-timeout", OnValue: "600"},
+			{Name: "performance/nl-cache", OnValue: "on"},
+			{Name: "performance/nl-cache.nl-cache-timeout", OnValue: "600"},
+			{Name: "cluster/distribute.lookup-optimize", OnValue: "on"},
+		},
+		Description: "Small-file performance mode: cache the attributes and the content of the small files on the clients, kept coherent by the invalidations of the bricks",
+	},
 	"profile.test": {
 		Name: "profile.test",
 		Options: []api.VolumeOption{
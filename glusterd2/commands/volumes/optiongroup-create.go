@@ -1,12 +1,9 @@
 package volumecommands
 
 import (
-	"context"
-	"encoding/json"
 	"net/http"
 
 	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
-	"github.com/gluster/glusterd2/glusterd2/store"
 	"github.com/gluster/glusterd2/pkg/api"
 	"github.com/gluster/glusterd2/pkg/errors"
 )
@@ -28,41 +25,42 @@ func optionGroupCreateHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if req.Name == "" {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrEmptyOptionGroupName)
+		return
+	}
+
 	if err := validateOptionSet(req); err != nil {
 		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
 		return
 	}
 
-	resp, err := store.Get(context.TODO(), "groupoptions")
+	groupOptions, err := getGroupOptionsFromStore()
 	if err != nil {
 		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
 		return
 	}
-
-	var groupOptions map[string]*api.OptionGroup
-	if resp.Count > 0 {
-		if err := json.Unmarshal(resp.Kvs[0].Value, &groupOptions); err != nil {
-			restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-			return
-		}
+	if groupOptions == nil {
+		groupOptions = make(map[string]*api.OptionGroup)
 	}
+
 	var optionSet []api.VolumeOption
 	for _, option := range req.Options {
 		optionSet = append(optionSet, option)
 	}
 
+	// Creating a group of the name of a builtin group overrides it for
+	// the site, until the group is deleted
+	_, builtin := defaultGroupOptions[req.Name]
 	groupOptions[req.Name] = &api.OptionGroup{
 		Name:        req.Name,
 		Options:     optionSet,
 		Description: req.Description,
+		Builtin:     builtin,
+		Overridden:  builtin,
 	}
 
-	groupOptionsJSON, err := json.Marshal(groupOptions)
-	if err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
-	}
-	if _, err := store.Put(context.TODO(), "groupoptions", string(groupOptionsJSON)); err != nil {
+	if err := putGroupOptionsInStore(groupOptions); err != nil {
 		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
 		return
 	}
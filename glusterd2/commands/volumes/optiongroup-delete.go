@@ -1,13 +1,10 @@
 package volumecommands
 
 import (
-	"context"
-	"encoding/json"
 	"net/http"
 
 	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
-	"github.com/gluster/glusterd2/glusterd2/store"
-	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/errors"
 
 	"github.com/gorilla/mux"
 )
@@ -16,39 +13,31 @@ func optionGroupDeleteHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	groupName := mux.Vars(r)["groupname"]
 
-	resp, err := store.Get(context.TODO(), "groupoptions")
+	groupOptions, err := getGroupOptionsFromStore()
 	if err != nil {
 		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
 		return
 	}
 
-	var groupOptions map[string]*api.OptionGroup
-	if resp.Count > 0 {
-		if err := json.Unmarshal(resp.Kvs[0].Value, &groupOptions); err != nil {
-			restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-			return
-		}
-
-	}
-	_, ok := groupOptions[groupName]
+	group, ok := groupOptions[groupName]
 	if !ok {
-		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "invalid group name specified")
+		restutils.SendHTTPError(ctx, w, http.StatusNotFound, errors.ErrOptionGroupNotFound)
 		return
 	}
 
-	if _, ok := defaultGroupOptions[groupName]; ok {
-		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "cannot delete builtin groups")
-		return
+	// Deleting the override of a builtin group reverts it to the group
+	// shipped with glusterd2
+	if builtin, ok := builtinGroupOption(groupName); ok {
+		if !group.Overridden {
+			restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrBuiltinOptionGroup)
+			return
+		}
+		groupOptions[groupName] = builtin
+	} else {
+		delete(groupOptions, groupName)
 	}
 
-	delete(groupOptions, groupName)
-
-	groupOptionsJSON, err := json.Marshal(groupOptions)
-	if err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
-	}
-	if _, err := store.Put(context.TODO(), "groupoptions", string(groupOptionsJSON)); err != nil {
+	if err := putGroupOptionsInStore(groupOptions); err != nil {
 		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
 		return
 	}
@@ -0,0 +1,31 @@
+package volumecommands
+
+import (
+	"net/http"
+
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/gorilla/mux"
+)
+
+func optionGroupGetHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	groupName := mux.Vars(r)["groupname"]
+
+	groupOptions, err := getGroupOptionsFromStore()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	group, ok := groupOptions[groupName]
+	if !ok {
+		restutils.SendHTTPError(ctx, w, http.StatusNotFound, errors.ErrOptionGroupNotFound)
+		return
+	}
+
+	resp := api.OptionGroupGetResp(*group)
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, &resp)
+}
@@ -0,0 +1,433 @@
+package volumecommands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/bricksplanner"
+	"github.com/gluster/glusterd2/glusterd2/events"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+	"github.com/gluster/glusterd2/pkg/lvmutils"
+
+	"github.com/gorilla/mux"
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// evacuationPrefix is where the progress of the evacuations is saved,
+	// under the ID of the evacuated peer
+	evacuationPrefix = "evacuations/"
+
+	eventPeerEvacuated        = "peer_evacuated"
+	eventPeerEvacuationFailed = "peer_evacuation_failed"
+)
+
+var (
+	evacuationsMu sync.Mutex
+	// evacuations are the peers whose bricks this peer is moving
+	evacuations = make(map[string]bool)
+)
+
+// plannedMove is the move of a brick with the new brick replacing it
+type plannedMove struct {
+	api.BrickMove
+	newBrick api.BrickReq
+}
+
+func getEvacuation(peerID uuid.UUID) (*api.PeerEvacuateStatus, error) {
+	resp, err := store.Get(context.TODO(), evacuationPrefix+peerID.String())
+	if err != nil {
+		return nil, err
+	}
+	if resp.Count != 1 {
+		return nil, gderrors.ErrEvacuationNotFound
+	}
+
+	var st api.PeerEvacuateStatus
+	if err := json.Unmarshal(resp.Kvs[0].Value, &st); err != nil {
+		return nil, err
+	}
+	return &st, nil
+}
+
+func putEvacuation(st *api.PeerEvacuateStatus) error {
+	st.UpdatedAt = time.Now()
+	data, err := json.Marshal(st)
+	if err != nil {
+		return err
+	}
+	_, err = store.Put(context.TODO(), evacuationPrefix+st.PeerID.String(), string(data))
+	return err
+}
+
+// evacuationRunning tells if the bricks are still being moved, which stops
+// if the peer moving them goes down
+func evacuationRunning(st *api.PeerEvacuateStatus) bool {
+	if st.State != api.EvacuationRunning {
+		return false
+	}
+	if uuid.Equal(st.DriverID, gdctx.MyUUID) {
+		evacuationsMu.Lock()
+		defer evacuationsMu.Unlock()
+		return evacuations[st.PeerID.String()]
+	}
+	_, alive := store.Store.IsNodeAlive(st.DriverID)
+	return alive
+}
+
+// estimatedBrickSize returns the size of a brick provisioned by glusterd2,
+// from the size of its thin pool, as the peer of the brick may be down
+func estimatedBrickSize(b brick.Brickinfo, snapshotReserveFactor float64) uint64 {
+	tpSize := b.TotalSize - lvmutils.GetPoolMetadataSize(b.TotalSize)
+	if snapshotReserveFactor < 1 {
+		snapshotReserveFactor = 1
+	}
+	return lvmutils.NormalizeSize(uint64(float64(tpSize) / snapshotReserveFactor))
+}
+
+// planEvacuation plans the moves of the bricks of the volumes off the peer.
+// zones are the zones of the peers, and vgs the volume groups available for
+// the new bricks by provisioner type. A new brick is never placed in the
+// zone of another brick of its volume, or of its subvolume if subvolume
+// zones may overlap, as the replace-brick does.
+func planEvacuation(peerID uuid.UUID, req *api.PeerEvacuateReq, volumes []*volume.Volinfo, zones map[string]string, vgs map[string][]bricksplanner.Vg) []plannedMove {
+	sort.Slice(volumes, func(i, j int) bool { return volumes[i].Name < volumes[j].Name })
+
+	// Space taken on the volume groups by the new bricks planned so far
+	used := make(map[string]uint64)
+
+	var plan []plannedMove
+	for _, v := range volumes {
+		// Zones of the new bricks planned for the volume, by subvolume
+		planned := make(map[int][]string)
+
+		for svIdx, sv := range v.Subvols {
+			for bIdx, b := range sv.Bricks {
+				if !uuid.Equal(b.PeerID, peerID) {
+					continue
+				}
+				m := plannedMove{
+					BrickMove: api.BrickMove{
+						Volume:  v.Name,
+						Subvol:  sv.Name,
+						SrcPath: b.Path,
+						State:   api.BrickMovePending,
+					},
+				}
+
+				if !b.PType.IsAutoProvisioned() {
+					m.State = api.BrickMoveSkipped
+					m.Message = "brick is not provisioned by glusterd2, replace it manually"
+					plan = append(plan, m)
+					continue
+				}
+				if sv.Type == volume.SubvolDistribute && !req.Force {
+					m.State = api.BrickMoveSkipped
+					m.Message = "subvolume has no redundancy, the data of the brick would be lost"
+					plan = append(plan, m)
+					continue
+				}
+
+				excludeZones := make(map[string]bool)
+				for i, s := range v.Subvols {
+					if req.SubvolZonesOverlap && i != svIdx {
+						continue
+					}
+					for _, sb := range s.Bricks {
+						excludeZones[zones[sb.PeerID.String()]] = true
+					}
+					for _, z := range planned[i] {
+						excludeZones[z] = true
+					}
+				}
+
+				var candidates []bricksplanner.Vg
+				for _, vg := range vgs[v.ProvisionerType] {
+					if excludeZones[vg.Zone] {
+						continue
+					}
+					taken := used[vg.PeerID+"/"+vg.Name]
+					if taken >= vg.AvailableSize {
+						continue
+					}
+					vg.AvailableSize -= taken
+					candidates = append(candidates, vg)
+				}
+				sort.SliceStable(candidates, func(i, j int) bool {
+					return candidates[i].AvailableSize > candidates[j].AvailableSize
+				})
+
+				status := brick.Brickstatus{
+					Info: b,
+					Size: brick.SizeInfo{Capacity: estimatedBrickSize(b, v.SnapshotReserveFactor)},
+				}
+				newBrick := bricksplanner.GetNewBrick(candidates, status, v, svIdx, bIdx)
+				if newBrick.PeerID == "" {
+					m.State = api.BrickMoveSkipped
+					m.Message = "no device with enough space in the allowed zones"
+					plan = append(plan, m)
+					continue
+				}
+
+				used[newBrick.PeerID+"/"+newBrick.VgName] += newBrick.TotalSize
+				planned[svIdx] = append(planned[svIdx], zones[newBrick.PeerID])
+
+				m.newBrick = newBrick
+				m.Size = newBrick.Size
+				m.DstPeerID = uuid.Parse(newBrick.PeerID)
+				m.DstDevice = newBrick.RootDevice
+				plan = append(plan, m)
+			}
+		}
+	}
+	return plan
+}
+
+// newEvacuationPlan plans the moves of all the bricks off the peer, onto
+// the devices of the other peers which are online
+func newEvacuationPlan(peerID uuid.UUID, req *api.PeerEvacuateReq) ([]plannedMove, error) {
+	volumes, err := volume.GetVolumes(context.TODO())
+	if err != nil {
+		return nil, err
+	}
+
+	peers, err := peer.GetPeers()
+	if err != nil {
+		return nil, err
+	}
+	zones := make(map[string]string)
+	for _, p := range peers {
+		zone := strings.TrimSpace(p.Metadata["_zone"])
+		if zone == "" {
+			zone = p.ID.String()
+		}
+		zones[p.ID.String()] = zone
+	}
+
+	vgs := make(map[string][]bricksplanner.Vg)
+	for _, v := range volumes {
+		if _, ok := vgs[v.ProvisionerType]; ok {
+			continue
+		}
+		vgreq := api.VolCreateReq{
+			LimitPeers:      req.LimitPeers,
+			LimitZones:      req.LimitZones,
+			ExcludePeers:    append([]string{peerID.String()}, req.ExcludePeers...),
+			ExcludeZones:    req.ExcludeZones,
+			ProvisionerType: v.ProvisionerType,
+		}
+		if vgs[v.ProvisionerType], err = bricksplanner.GetAvailableVgs(&vgreq); err != nil {
+			return nil, err
+		}
+	}
+
+	return planEvacuation(peerID, req, volumes, zones, vgs), nil
+}
+
+// evacuateBrick replaces the brick with the new brick planned
+func evacuateBrick(ctx context.Context, peerID uuid.UUID, m *plannedMove) error {
+	vol, err := volume.GetVolume(m.Volume)
+	if err != nil {
+		return err
+	}
+	for svIdx, sv := range vol.Subvols {
+		for bIdx, b := range sv.Bricks {
+			if uuid.Equal(b.PeerID, peerID) && b.Path == m.SrcPath {
+				_, err := replaceBrick(ctx, vol, b, svIdx, bIdx, m.newBrick)
+				return err
+			}
+		}
+	}
+	return errors.New("brick is no longer in the volume")
+}
+
+// runEvacuation moves the bricks one by one, saving the progress
+func runEvacuation(ctx context.Context, st *api.PeerEvacuateStatus, plan []plannedMove) {
+	logger := gdctx.GetReqLogger(ctx).WithField("peerid", st.PeerID.String())
+	defer func() {
+		evacuationsMu.Lock()
+		delete(evacuations, st.PeerID.String())
+		evacuationsMu.Unlock()
+	}()
+
+	for i := range plan {
+		m := &st.Moves[i]
+		if m.State != api.BrickMovePending {
+			continue
+		}
+		if gdctx.IsTerminating {
+			return
+		}
+
+		m.State = api.BrickMoveRunning
+		if err := putEvacuation(st); err != nil {
+			logger.WithError(err).Error("failed to save the progress of the evacuation")
+		}
+
+		if err := evacuateBrick(ctx, st.PeerID, &plan[i]); err != nil {
+			logger.WithError(err).WithFields(log.Fields{
+				"volume": m.Volume,
+				"brick":  m.SrcPath,
+			}).Error("failed to move brick off the evacuated peer")
+			m.State = api.BrickMoveFailed
+			m.Message = err.Error()
+			st.Failed++
+		} else {
+			m.State = api.BrickMoveDone
+			st.Done++
+		}
+		if err := putEvacuation(st); err != nil {
+			logger.WithError(err).Error("failed to save the progress of the evacuation")
+		}
+	}
+
+	st.State = api.EvacuationCompleted
+	event := eventPeerEvacuated
+	if st.Failed > 0 {
+		st.State = api.EvacuationFailed
+		event = eventPeerEvacuationFailed
+	}
+	if err := putEvacuation(st); err != nil {
+		logger.WithError(err).Error("failed to save the progress of the evacuation")
+	}
+	logger.WithFields(log.Fields{
+		"done":   st.Done,
+		"failed": st.Failed,
+	}).Info("evacuation of peer finished")
+
+	events.Broadcast(events.New(event, map[string]string{
+		"peer.id": st.PeerID.String(),
+		"done":    strconv.Itoa(st.Done),
+		"failed":  strconv.Itoa(st.Failed),
+	}, true))
+}
+
+// peerEvacuateHandler plans the moves of all the bricks off the peer, and
+// unless it is a dry run, starts moving them one by one. The progress is
+// returned by peerEvacuationStatusHandler.
+func peerEvacuateHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+
+	peerID := uuid.Parse(mux.Vars(r)["peerid"])
+	if peerID == nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "invalid peer id")
+		return
+	}
+	if _, err := peer.GetPeer(peerID.String()); err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	var req api.PeerEvacuateReq
+	// request body is optional
+	if err := restutils.UnmarshalRequest(r, &req); err != nil && err != io.EOF {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
+		return
+	}
+
+	// Locks out other peers starting the evacuation of the peer meanwhile
+	txn, err := transaction.NewTxnWithLocks(ctx, evacuationPrefix+peerID.String())
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	defer txn.Done()
+
+	if st, err := getEvacuation(peerID); err == nil && evacuationRunning(st) {
+		restutils.SendHTTPError(ctx, w, http.StatusConflict, gderrors.ErrEvacuationInProgress)
+		return
+	} else if err != nil && err != gderrors.ErrEvacuationNotFound {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	plan, err := newEvacuationPlan(peerID, &req)
+	if err != nil {
+		logger.WithError(err).Error("failed to plan the evacuation of the peer")
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	now := time.Now()
+	st := &api.PeerEvacuateStatus{
+		PeerID:    peerID,
+		State:     api.EvacuationPlanned,
+		StartedAt: now,
+		UpdatedAt: now,
+		Moves:     make([]api.BrickMove, 0, len(plan)),
+	}
+	for _, m := range plan {
+		if m.State == api.BrickMovePending {
+			st.Total++
+		}
+		st.Moves = append(st.Moves, m.BrickMove)
+	}
+
+	if req.DryRun {
+		restutils.SendHTTPResponse(ctx, w, http.StatusOK, st)
+		return
+	}
+
+	st.State = api.EvacuationRunning
+	st.DriverID = gdctx.MyUUID
+	evacuationsMu.Lock()
+	evacuations[peerID.String()] = true
+	evacuationsMu.Unlock()
+	if err := putEvacuation(st); err != nil {
+		evacuationsMu.Lock()
+		delete(evacuations, peerID.String())
+		evacuationsMu.Unlock()
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	logger.WithFields(log.Fields{
+		"peerid": peerID.String(),
+		"bricks": st.Total,
+	}).Info("starting evacuation of peer")
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusAccepted, st)
+
+	// The bricks are moved after the request is answered, with a context
+	// outliving it
+	runCtx := gdctx.WithReqLogger(context.Background(), logger)
+	go runEvacuation(runCtx, st, plan)
+}
+
+func peerEvacuationStatusHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	peerID := uuid.Parse(mux.Vars(r)["peerid"])
+	if peerID == nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "invalid peer id")
+		return
+	}
+
+	st, err := getEvacuation(peerID)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, st)
+}
@@ -0,0 +1,104 @@
+package volumecommands
+
+import (
+	"testing"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/bricksplanner"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPlanEvacuation validates planEvacuation()
+func TestPlanEvacuation(t *testing.T) {
+	const gib = uint64(1 << 30)
+	a, b, c, d, e := uuid.NewRandom(), uuid.NewRandom(), uuid.NewRandom(), uuid.NewRandom(), uuid.NewRandom()
+	zones := map[string]string{
+		a.String(): "z1",
+		b.String(): "z2",
+		c.String(): "z3",
+		d.String(): "z4",
+		e.String(): "z5",
+	}
+
+	autoBrick := func(peerID uuid.UUID, path string) brick.Brickinfo {
+		return brick.Brickinfo{
+			PeerID:     peerID,
+			Path:       path,
+			PType:      brick.AutoProvisioned,
+			DeviceInfo: brick.DeviceInfo{TotalSize: gib},
+		}
+	}
+	rep := &volume.Volinfo{
+		Name:                  "rep",
+		SnapshotReserveFactor: 1,
+		ProvisionerType:       api.ProvisionerTypeLvm,
+		Subvols: []volume.Subvol{{
+			Name:   "rep-replicate-0",
+			Type:   volume.SubvolReplicate,
+			Bricks: []brick.Brickinfo{autoBrick(a, "/b/rep1"), autoBrick(b, "/b/rep2"), autoBrick(c, "/b/rep3")},
+		}},
+	}
+	dist := &volume.Volinfo{
+		Name:                  "dist",
+		SnapshotReserveFactor: 1,
+		ProvisionerType:       api.ProvisionerTypeLvm,
+		Subvols: []volume.Subvol{{
+			Name:   "dist-distribute-0",
+			Type:   volume.SubvolDistribute,
+			Bricks: []brick.Brickinfo{autoBrick(a, "/b/dist1")},
+		}},
+	}
+	manual := &volume.Volinfo{
+		Name:            "manual",
+		ProvisionerType: api.ProvisionerTypeLvm,
+		Subvols: []volume.Subvol{{
+			Name:   "manual-replicate-0",
+			Type:   volume.SubvolReplicate,
+			Bricks: []brick.Brickinfo{{PeerID: a, Path: "/b/manual1"}, {PeerID: b, Path: "/b/manual2"}},
+		}},
+	}
+	vgs := map[string][]bricksplanner.Vg{
+		api.ProvisionerTypeLvm: {
+			{Name: "vg_sdb", Device: "/dev/sdb", PeerID: d.String(), Zone: "z4", AvailableSize: gib * 3 / 2},
+			{Name: "vg_sdb", Device: "/dev/sdb", PeerID: b.String(), Zone: "z2", AvailableSize: gib * 13 / 10},
+			{Name: "vg_sdb", Device: "/dev/sdb", PeerID: e.String(), Zone: "z5", AvailableSize: gib * 6 / 5},
+		},
+	}
+
+	plan := planEvacuation(a, &api.PeerEvacuateReq{}, []*volume.Volinfo{rep, manual, dist}, zones, vgs)
+	require.Len(t, plan, 3)
+
+	// The brick of the volume without redundancy and the brick not
+	// provisioned by glusterd2 are left on the peer
+	assert.Equal(t, "dist", plan[0].Volume)
+	assert.Equal(t, api.BrickMoveSkipped, plan[0].State)
+	assert.Equal(t, "manual", plan[1].Volume)
+	assert.Equal(t, api.BrickMoveSkipped, plan[1].State)
+
+	// The zone of the other bricks of the volume is not used
+	assert.Equal(t, "rep", plan[2].Volume)
+	assert.Equal(t, api.BrickMovePending, plan[2].State)
+	assert.Equal(t, d.String(), plan[2].DstPeerID.String())
+	assert.Equal(t, d.String(), plan[2].newBrick.PeerID)
+	assert.Equal(t, "/b/rep1", plan[2].newBrick.Path)
+	assert.Equal(t, estimatedBrickSize(rep.Subvols[0].Bricks[0], 1), plan[2].Size)
+
+	// With force, the brick of the volume without redundancy is moved
+	// first, taking space on the device the other brick no longer fits in
+	plan = planEvacuation(a, &api.PeerEvacuateReq{Force: true}, []*volume.Volinfo{rep, manual, dist}, zones, vgs)
+	require.Len(t, plan, 3)
+	assert.Equal(t, api.BrickMovePending, plan[0].State)
+	assert.Equal(t, d.String(), plan[0].DstPeerID.String())
+	assert.Equal(t, api.BrickMovePending, plan[2].State)
+	assert.Equal(t, e.String(), plan[2].DstPeerID.String())
+
+	// No device left for the brick
+	plan = planEvacuation(a, &api.PeerEvacuateReq{}, []*volume.Volinfo{rep}, zones, map[string][]bricksplanner.Vg{})
+	require.Len(t, plan, 1)
+	assert.Equal(t, api.BrickMoveSkipped, plan[0].State)
+}
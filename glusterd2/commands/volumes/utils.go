@@ -12,3 +12,17 @@ func createSizeInfo(size *volume.SizeInfo) api.SizeInfo {
 		Capacity: size.Capacity,
 	}
 }
+
+func createUsageInfo(u *volume.Usage) *api.UsageInfo {
+	return &api.UsageInfo{
+		Capacity:         u.Capacity,
+		Used:             u.Used,
+		Free:             u.Free,
+		Utilization:      u.Utilization(),
+		Inodes:           u.Inodes,
+		InodesUsed:       u.InodesUsed,
+		InodesFree:       u.InodesFree,
+		InodeUtilization: u.InodeUtilization(),
+		UpdatedAt:        u.UpdatedAt,
+	}
+}
@@ -0,0 +1,144 @@
+package volumecommands
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/advisor"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/options"
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/glusterd2/xlator"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/gorilla/mux"
+)
+
+const volumeAdvisorTxnKey = "volume-advisor"
+
+func registerVolAdvisorStepFuncs() {
+	transaction.RegisterStepFunc(txnVolumeAdvisor, "volume.Advisor")
+}
+
+// txnVolumeAdvisor collects the counters of the caches of the volume out of
+// the statedumps of its clients and bricks on this peer
+func txnVolumeAdvisor(c transaction.TxnCtx) error {
+	var volinfo volume.Volinfo
+	if err := c.Get("volinfo", &volinfo); err != nil {
+		return err
+	}
+
+	stats, err := advisor.Collect(&volinfo)
+	if err != nil {
+		c.Logger().WithError(err).WithField(
+			"volume", volinfo.Name).Error("failed to read the statedumps of the volume")
+		return err
+	}
+	return c.SetNodeResult(gdctx.MyUUID, volumeAdvisorTxnKey, stats)
+}
+
+// isGroupSet tells if the options of the group are set on the volume to
+// their values in the group
+func isGroupSet(v *volume.Volinfo, group *api.OptionGroup) bool {
+	for _, o := range group.Options {
+		graph, xl, key := options.SplitKey(o.Name)
+		xltr, err := xlator.Find(xl)
+		if err != nil {
+			return false
+		}
+		k := xltr.FullName() + "." + key
+		if graph != "" {
+			k = graph + "." + k
+		}
+		if v.Options[k] != o.OnValue {
+			return false
+		}
+	}
+	return true
+}
+
+// smallFileGroup returns the small-file option group, as overridden on the
+// cluster
+func smallFileGroup() *api.OptionGroup {
+	groups, err := getGroupOptionsFromStore()
+	if err == nil {
+		if group, ok := groups[advisor.SmallFileGroup]; ok {
+			return group
+		}
+	}
+	return defaultGroupOptions[advisor.SmallFileGroup]
+}
+
+func volumeAdvisorHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+	volname := mux.Vars(r)["volname"]
+
+	vol, err := volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	if vol.State != volume.VolStarted {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrVolNotStarted)
+		return
+	}
+
+	// The clients write their statedumps on their own host, which may be
+	// any peer
+	nodes, err := peer.GetPeerIDs()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	txn := transaction.NewTxn(ctx)
+	defer txn.Done()
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc: "volume.Advisor",
+			Nodes:  nodes,
+		},
+	}
+	if err := txn.Ctx.Set("volinfo", vol); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	// Some nodes may not be up, which is okay.
+	txn.DontCheckAlive = true
+	txn.DisableRollback = true
+
+	if err := txn.Do(); err != nil {
+		logger.WithError(err).WithField("volume", volname).Error("failed to collect the cache stats of the volume")
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	stats := &advisor.Stats{Bricks: []api.BrickInodeStats{}}
+	for _, node := range nodes {
+		var tmp advisor.Stats
+		if err := txn.Ctx.GetNodeResult(node, volumeAdvisorTxnKey, &tmp); err != nil {
+			// skip if we do not have information
+			continue
+		}
+		stats.Add(&tmp)
+	}
+
+	opts, err := options.EffectiveVolumeOptions(vol.Options)
+	if err != nil {
+		opts = vol.Options
+	}
+	resp := api.VolumeAdvisorResp{
+		SmallFileMode:   isGroupSet(vol, smallFileGroup()),
+		MdCache:         stats.MdCache,
+		MdCacheHitRate:  stats.HitRate(),
+		Bricks:          stats.Bricks,
+		Recommendations: advisor.Advise(opts, stats),
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
@@ -0,0 +1,127 @@
+package volumecommands
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/barrier"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/gorilla/mux"
+)
+
+// barrierTxn returns a transaction locking the started volume, along with the
+// volume
+func barrierTxn(w http.ResponseWriter, r *http.Request) (*transaction.Txn, *volume.Volinfo, bool) {
+	ctx := r.Context()
+	volname := mux.Vars(r)["volname"]
+
+	txn, err := transaction.NewTxnWithLocks(ctx, volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return nil, nil, false
+	}
+
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		txn.Done()
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return nil, nil, false
+	}
+	if volinfo.State != volume.VolStarted {
+		txn.Done()
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrVolNotStarted)
+		return nil, nil, false
+	}
+	return txn, volinfo, true
+}
+
+// barrierTimeout returns the time the barrier is to be kept for, the default
+// timeout of the barrier if none is requested
+func barrierTimeout(req *api.VolumeBarrierReq) (time.Duration, error) {
+	if req.Timeout < 0 {
+		return 0, errors.ErrInvalidBarrierTimeout
+	}
+	if req.Timeout == 0 {
+		return barrier.DefaultTimeout, nil
+	}
+	return time.Duration(req.Timeout) * time.Second, nil
+}
+
+func volumeBarrierEnableHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+
+	var req api.VolumeBarrierReq
+	// request body is optional
+	if err := restutils.UnmarshalRequest(r, &req); err != nil && err != io.EOF {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrJSONParsingFailed)
+		return
+	}
+	timeout, err := barrierTimeout(&req)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	txn, volinfo, ok := barrierTxn(w, r)
+	if !ok {
+		return
+	}
+	defer txn.Done()
+
+	step, err := barrier.EnableStep(txn.Ctx, volinfo.Name, timeout, volinfo.Nodes())
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	txn.Steps = []*transaction.Step{step}
+
+	if err := txn.Do(); err != nil {
+		logger.WithError(err).WithField("volume", volinfo.Name).Error("transaction to enable barrier failed")
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	resp := api.VolumeBarrierResp{
+		Enabled: true,
+		Timeout: int(timeout / time.Second),
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+func volumeBarrierDisableHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+
+	txn, volinfo, ok := barrierTxn(w, r)
+	if !ok {
+		return
+	}
+	defer txn.Done()
+
+	step, err := barrier.DisableStep(txn.Ctx, volinfo.Name, volinfo.Nodes())
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	txn.Steps = []*transaction.Step{step}
+
+	if err := txn.Do(); err != nil {
+		logger.WithError(err).WithField("volume", volinfo.Name).Error("transaction to disable barrier failed")
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, api.VolumeBarrierResp{Enabled: false})
+}
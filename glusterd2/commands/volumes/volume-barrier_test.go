@@ -0,0 +1,29 @@
+package volumecommands
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/barrier"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBarrierTimeout(t *testing.T) {
+	tests := []struct {
+		timeout  int
+		expected time.Duration
+		err      error
+	}{
+		{0, barrier.DefaultTimeout, nil},
+		{30, 30 * time.Second, nil},
+		{-1, 0, errors.ErrInvalidBarrierTimeout},
+	}
+	for _, tc := range tests {
+		timeout, err := barrierTimeout(&api.VolumeBarrierReq{Timeout: tc.timeout})
+		assert.Equal(t, tc.err, err)
+		assert.Equal(t, tc.expected, timeout)
+	}
+}
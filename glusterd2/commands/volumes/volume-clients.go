@@ -0,0 +1,312 @@
+package volumecommands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/daemon"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/servers/sunrpc"
+	"github.com/gluster/glusterd2/glusterd2/servers/sunrpc/dict"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/gorilla/mux"
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// gfCliStatusClients is GF_CLI_STATUS_CLIENTS, asking a brick for the
+	// clients connected to it
+	gfCliStatusClients = 2
+
+	volumeClientsTxnKey    = "volume-clients"
+	clientDisconnectTxnKey = "clients-disconnected"
+)
+
+func registerVolClientsStepFuncs() {
+	transaction.RegisterStepFunc(txnVolumeClients, "volume.Clients")
+	transaction.RegisterStepFunc(txnVolumeClientDisconnect, "volume.ClientDisconnect")
+}
+
+// brickClients asks the brick for the clients connected to it
+func brickClients(b brick.Brickinfo) ([]api.VolumeClient, error) {
+	brickDaemon, err := brick.NewGlusterfsd(b)
+	if err != nil {
+		return nil, err
+	}
+	client, err := daemon.GetRPCClient(brickDaemon)
+	if err != nil {
+		return nil, err
+	}
+
+	reqDict := map[string]string{
+		"cmd":        strconv.Itoa(gfCliStatusClients),
+		"brick-name": b.Path,
+		"volname":    b.VolumeName,
+	}
+	req := &brick.GfBrickOpReq{
+		Name: b.Path,
+		Op:   int(brick.OpBrickStatus),
+	}
+	if req.Input, err = dict.Serialize(reqDict); err != nil {
+		return nil, err
+	}
+
+	var rsp brick.GfBrickOpRsp
+	if err := client.Call("Brick.OpBrickStatus", req, &rsp); err != nil {
+		return nil, err
+	}
+	if rsp.OpRet != 0 {
+		return nil, fmt.Errorf("brick status RPC failed: %s", rsp.OpErrstr)
+	}
+
+	output, err := dict.Unserialize(rsp.Output)
+	if err != nil {
+		return nil, errors.New("error unserializing the output")
+	}
+
+	count, _ := strconv.Atoi(output["clientcount"])
+	clients := make([]api.VolumeClient, 0, count)
+	for i := 0; i < count; i++ {
+		key := fmt.Sprintf("client%d.", i)
+		c := api.VolumeClient{
+			Peer:    gdctx.MyUUID,
+			Layer:   api.ClientOfBrick,
+			Brick:   b.Path,
+			Address: output[key+"hostname"],
+			Name:    output[key+"name"],
+		}
+		c.OpVersion, _ = strconv.Atoi(output[key+"opversion"])
+		c.BytesRead, _ = strconv.ParseUint(output[key+"bytesread"], 10, 64)
+		c.BytesWritten, _ = strconv.ParseUint(output[key+"byteswrite"], 10, 64)
+		clients = append(clients, c)
+	}
+	return clients, nil
+}
+
+// txnVolumeClients lists the clients connected to the local bricks of the
+// volume, and to glusterd2 for the volume
+func txnVolumeClients(c transaction.TxnCtx) error {
+	var volinfo volume.Volinfo
+	if err := c.Get("volinfo", &volinfo); err != nil {
+		return err
+	}
+
+	clients := []api.VolumeClient{}
+	if volinfo.State == volume.VolStarted {
+		for _, b := range volinfo.GetLocalBricks() {
+			bc, err := brickClients(b)
+			if err != nil {
+				c.Logger().WithError(err).WithField(
+					"brick", b.String()).Error("failed to get the clients of the brick")
+				continue
+			}
+			clients = append(clients, bc...)
+		}
+	}
+
+	for _, s := range sunrpc.VolumeClients(volinfo.Name) {
+		clients = append(clients, api.VolumeClient{
+			Peer:           gdctx.MyUUID,
+			Layer:          api.ClientOfGlusterd,
+			Address:        s.Address,
+			Pid:            s.Pid,
+			OpVersion:      s.OpVersion,
+			MinOpVersion:   s.MinOpVersion,
+			ConnectedSince: s.ConnectedAt,
+		})
+	}
+
+	// Clients mounted before the minimum op-version of the volume was
+	// raised stay connected
+	for i := range clients {
+		clients[i].BelowMinOpVersion = !sunrpc.ClientSupported(&volinfo, clients[i].OpVersion)
+	}
+
+	return c.SetNodeResult(gdctx.MyUUID, volumeClientsTxnKey, clients)
+}
+
+// txnVolumeClientDisconnect closes the connections of the client to
+// glusterd2 for the volume
+func txnVolumeClientDisconnect(c transaction.TxnCtx) error {
+	var volname string
+	if err := c.Get("volname", &volname); err != nil {
+		return err
+	}
+	var req api.ClientDisconnectReq
+	if err := c.Get("req", &req); err != nil {
+		return err
+	}
+
+	n := sunrpc.DisconnectClients(volname, req.Host, req.Pid)
+	if n > 0 {
+		c.Logger().WithFields(log.Fields{
+			"volume": volname,
+			"host":   req.Host,
+			"pid":    req.Pid,
+			"count":  n,
+		}).Info("disconnected clients")
+	}
+	return c.SetNodeResult(gdctx.MyUUID, clientDisconnectTxnKey, n)
+}
+
+// clientNodes returns the peers serving the bricks of the volume and the
+// other peers up, which clients may be connected to
+func clientNodes(vol *volume.Volinfo) []uuid.UUID {
+	nodes := vol.Nodes()
+	seen := make(map[string]bool)
+	for _, n := range nodes {
+		seen[n.String()] = true
+	}
+	for id := range store.Store.GetAliveNodes(context.TODO()) {
+		if !seen[id] {
+			if n := uuid.Parse(id); n != nil {
+				nodes = append(nodes, n)
+			}
+		}
+	}
+	return nodes
+}
+
+// getVolumeClients returns the clients of the volume connected to its bricks
+// and to the peers up
+func getVolumeClients(ctx context.Context, vol *volume.Volinfo) (api.VolumeClientsResp, error) {
+	nodes := clientNodes(vol)
+	txn := transaction.NewTxn(ctx)
+	defer txn.Done()
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc: "volume.Clients",
+			Nodes:  nodes,
+		},
+	}
+	if err := txn.Ctx.Set("volinfo", vol); err != nil {
+		return nil, err
+	}
+
+	// Some nodes may not be up, which is okay.
+	txn.DontCheckAlive = true
+	txn.DisableRollback = true
+
+	if err := txn.Do(); err != nil {
+		return nil, err
+	}
+
+	resp := api.VolumeClientsResp{}
+	for _, node := range nodes {
+		var tmp []api.VolumeClient
+		if err := txn.Ctx.GetNodeResult(node, volumeClientsTxnKey, &tmp); err != nil {
+			// skip if we do not have information
+			continue
+		}
+		resp = append(resp, tmp...)
+	}
+	return resp, nil
+}
+
+// mountedClients returns the clients which fetched the client volfile of the
+// volume from glusterd2, ie. its mounts and libgfapi clients. The daemons
+// managed by glusterd2, like the self-heal daemon, are clients of the bricks
+// too but fetch volfiles of their own.
+func mountedClients(clients api.VolumeClientsResp) []api.VolumeClient {
+	var mounted []api.VolumeClient
+	for _, c := range clients {
+		if c.Layer == api.ClientOfGlusterd {
+			mounted = append(mounted, c)
+		}
+	}
+	return mounted
+}
+
+func volumeClientsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+	volname := mux.Vars(r)["volname"]
+
+	vol, err := volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	resp, err := getVolumeClients(ctx, vol)
+	if err != nil {
+		logger.WithError(err).WithField("volume", volname).Error("failed to get the clients of the volume")
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+func volumeClientDisconnectHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+	volname := mux.Vars(r)["volname"]
+
+	var req api.ClientDisconnectReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
+		return
+	}
+	if req.Host == "" {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "host is required")
+		return
+	}
+
+	vol, err := volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	nodes := clientNodes(vol)
+	txn := transaction.NewTxn(ctx)
+	defer txn.Done()
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc: "volume.ClientDisconnect",
+			Nodes:  nodes,
+		},
+	}
+	if err := txn.Ctx.Set("volname", volname); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	if err := txn.Ctx.Set("req", &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	txn.DontCheckAlive = true
+	txn.DisableRollback = true
+
+	if err := txn.Do(); err != nil {
+		logger.WithError(err).WithField("volume", volname).Error("failed to disconnect the client of the volume")
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	resp := api.ClientDisconnectResp{Disconnected: make(map[string]int)}
+	for _, node := range nodes {
+		var n int
+		if err := txn.Ctx.GetNodeResult(node, clientDisconnectTxnKey, &n); err != nil {
+			continue
+		}
+		resp.Disconnected[node.String()] = n
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
@@ -0,0 +1,115 @@
+package volumecommands
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/configsnap"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/glusterd2/xlator"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/gorilla/mux"
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// volumeConfigRestoreHandler restores the options of the volume to what
+// they were in a configuration snapshot. The options changed since are set
+// back, and the options set since are reset.
+func volumeConfigRestoreHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+	volname := mux.Vars(r)["volname"]
+
+	var req api.VolumeConfigRestoreReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrJSONParsingFailed)
+		return
+	}
+
+	snap, err := configsnap.Get(req.Snapshot)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	saved, err := snap.Volume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	if !uuid.Equal(volinfo.ID, saved.ID) {
+		restutils.SendHTTPError(ctx, w, http.StatusConflict, "volume in the snapshot was deleted and created again")
+		return
+	}
+
+	toSet := make(map[string]string)
+	for k, v := range saved.Options {
+		if cur, ok := volinfo.Options[k]; !ok || cur != v {
+			toSet[k] = v
+		}
+	}
+	var toReset []string
+	for k := range volinfo.Options {
+		if _, ok := saved.Options[k]; ok {
+			continue
+		}
+		op, err := xlator.FindOption(k)
+		if err != nil || op.IsNeverReset() {
+			continue
+		}
+		toReset = append(toReset, k)
+	}
+
+	logger = logger.WithFields(log.Fields{
+		"volume":   volname,
+		"snapshot": req.Snapshot,
+	})
+
+	if len(toSet) != 0 {
+		optReq := api.VolOptionReq{
+			Options: toSet,
+			VolOptionFlags: api.VolOptionFlags{
+				AllowAdvanced:     true,
+				AllowExperimental: true,
+				AllowDeprecated:   true,
+			},
+		}
+		v, status, err := setVolumeOptions(ctx, volname, optReq)
+		if err != nil {
+			logger.WithError(err).Error("failed to set the options of the snapshot")
+			restutils.SendHTTPError(ctx, w, status, err)
+			return
+		}
+		volinfo = v
+	}
+	if len(toReset) != 0 {
+		resetReq := api.VolOptionResetReq{Options: toReset, Force: true}
+		v, status, err := resetVolumeOptions(ctx, volname, resetReq)
+		if err != nil {
+			logger.WithError(err).Error("failed to reset the options set since the snapshot")
+			restutils.SendHTTPError(ctx, w, status, err)
+			return
+		}
+		volinfo = v
+	}
+
+	logger.WithFields(log.Fields{
+		"set":   len(toSet),
+		"reset": len(toReset),
+	}).Info("restored volume options from configuration snapshot")
+
+	resp := createVolumeOptionResp(volinfo)
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
@@ -13,6 +13,21 @@ import (
 	"github.com/pborman/uuid"
 )
 
+// addVolume stores the volume created
+func addVolume(c transaction.TxnCtx) error {
+	var volinfo volume.Volinfo
+	if err := c.Get("volinfo", &volinfo); err != nil {
+		return err
+	}
+
+	if err := volume.AddVolume(&volinfo); err != nil {
+		c.Logger().WithError(err).WithField(
+			"volume", volinfo.Name).Error("failed to store volume info")
+		return err
+	}
+	return nil
+}
+
 func undoStoreVolumeOnCreate(c transaction.TxnCtx) error {
 
 	var volinfo volume.Volinfo
@@ -22,6 +37,12 @@ func undoStoreVolumeOnCreate(c transaction.TxnCtx) error {
 		return err
 	}
 
+	// A volume of the same name not stored by this transaction is left
+	// alone
+	if v, err := volume.GetVolume(volinfo.Name); err != nil || !uuid.Equal(v.ID, volinfo.ID) {
+		return nil
+	}
+
 	if err := deleteVolume(c); err != nil {
 		c.Logger().WithError(err).WithField(
 			"volume", volinfo.Name).Warn("Failed to delete volinfo from store")
@@ -119,6 +140,9 @@ func populateSubvols(volinfo *volume.Volinfo, req *api.VolCreateReq) error {
 		if err != nil {
 			return err
 		}
+		for i := range s.Bricks {
+			s.Bricks[i].User = volinfo.BrickUser
+		}
 		volinfo.Subvols = append(volinfo.Subvols, s)
 	}
 
@@ -136,6 +160,9 @@ func newVolinfo(req *api.VolCreateReq) (*volume.Volinfo, error) {
 		SnapList:              []string{},
 		SnapshotReserveFactor: req.SnapshotReserveFactor,
 		ProvisionerType:       req.ProvisionerType,
+		BrickUser:             brick.NormalizeUser(req.BrickUser),
+		Namespace:             req.Namespace,
+		Labels:                req.Labels,
 		Auth: volume.VolAuth{
 			Username: uuid.NewRandom().String(),
 			Password: uuid.NewRandom().String(),
@@ -7,9 +7,12 @@ import (
 	"path/filepath"
 	"strconv"
 
+	"github.com/gluster/glusterd2/glusterd2/brick"
 	"github.com/gluster/glusterd2/glusterd2/bricksplanner"
 	"github.com/gluster/glusterd2/glusterd2/events"
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/namespace"
+	"github.com/gluster/glusterd2/glusterd2/opversion"
 	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
 	"github.com/gluster/glusterd2/glusterd2/transaction"
 	transactionv2 "github.com/gluster/glusterd2/glusterd2/transactionv2"
@@ -51,6 +54,10 @@ func validateVolCreateReq(req *api.VolCreateReq) error {
 		return errors.New("invalid Volume Size, Minimum size required is " + strconv.Itoa(minVolumeSize))
 	}
 
+	if req.Encrypted && (req.Size == 0 || req.ProvisionerType == api.ProvisionerTypeLoop) {
+		return gderrors.ErrEncryptionNotSupported
+	}
+
 	if req.Size == 0 && len(req.Subvols) <= 0 {
 		return gderrors.ErrEmptyBrickList
 	}
@@ -63,6 +70,9 @@ func validateVolCreateReq(req *api.VolCreateReq) error {
 	if req.MetadataSize() > maxMetadataSizeLimit {
 		return gderrors.ErrMetadataSizeOutOfBounds
 	}
+	if err := volume.ValidateLabels(req.Labels); err != nil {
+		return err
+	}
 
 	return validateVolumeFlags(req.Flags)
 }
@@ -91,6 +101,7 @@ func registerVolCreateStepFuncs() {
 		{"vol-create.InitBricks", initBricks},
 		{"vol-create.UndoInitBricks", undoInitBricks},
 		{"vol-create.StoreVolume", storeVolume},
+		{"vol-create.AddVolume", addVolume},
 		{"vol-create.UndoStoreVolume", undoStoreVolumeOnCreate},
 		{"vol-create.PrepareBricks", txnPrepareBricks},
 		{"vol-create.UndoPrepareBricks", txnUndoPrepareBricks},
@@ -147,6 +158,14 @@ func CreateVolume(ctx context.Context, req api.VolCreateReq) (status int, err er
 		return http.StatusBadRequest, err
 	}
 
+	// Tenants create volumes in their namespace only
+	if ns := gdctx.GetReqNamespace(ctx); ns != "" {
+		if req.Namespace != "" && req.Namespace != ns {
+			return http.StatusForbidden, errors.New("volume cannot be created in another namespace")
+		}
+		req.Namespace = ns
+	}
+
 	if containsReservedGroupProfile(req.Options) {
 		return http.StatusBadRequest, gderrors.ErrReservedGroupProfile
 	}
@@ -155,6 +174,10 @@ func CreateVolume(ctx context.Context, req api.VolCreateReq) (status int, err er
 		req.ProvisionerType = api.ProvisionerTypeLvm
 	}
 
+	if req.BrickUser == "" {
+		req.BrickUser = brick.DefaultUser()
+	}
+
 	if req.Size > 0 {
 		applyDefaults(&req)
 
@@ -182,7 +205,11 @@ func CreateVolume(ctx context.Context, req api.VolCreateReq) (status int, err er
 
 	// Include default Volume Options profile
 	if len(req.Subvols) > 0 {
-		groupProfile, exists := defaultGroupOptions["profile.default."+req.Subvols[0].Type]
+		groupOptions, err := getGroupOptionsFromStore()
+		if err != nil {
+			return http.StatusInternalServerError, err
+		}
+		groupProfile, exists := groupOptions["profile.default."+req.Subvols[0].Type]
 		if exists {
 			for _, opt := range groupProfile.Options {
 				// Apply default option only if not overridden in volume create request
@@ -199,7 +226,11 @@ func CreateVolume(ctx context.Context, req api.VolCreateReq) (status int, err er
 		return http.StatusBadRequest, err
 	}
 
-	txn, err := transactionv2.NewTxnWithLocks(ctx, req.Name)
+	lockIDs := []string{req.Name}
+	if req.Namespace != "" {
+		lockIDs = append(lockIDs, namespace.LockID(req.Namespace))
+	}
+	txn, err := transactionv2.NewTxnWithLocks(ctx, lockIDs...)
 	if err != nil {
 		return restutils.ErrToStatusCode(err)
 	}
@@ -209,7 +240,25 @@ func CreateVolume(ctx context.Context, req api.VolCreateReq) (status int, err er
 		return http.StatusBadRequest, gderrors.ErrVolExists
 	}
 
+	if req.Namespace != "" {
+		if err := namespace.CheckQuotas(req.Namespace, 1, req.Size); err != nil {
+			return restutils.ErrToStatusCode(err)
+		}
+	}
+
+	required := requiredOpVersion(req.Options)
+	for _, sv := range req.Subvols {
+		if v := opversion.OfSubvolType(sv.Type); v > required {
+			required = v
+		}
+	}
+	precheck, err := opversion.PrecheckStep(txn.Ctx, required, nodes)
+	if err != nil {
+		return http.StatusBadRequest, err
+	}
+
 	txn.Steps = []*transaction.Step{
+		precheck,
 		{
 			DoFunc:   "vol-create.PrepareBricks",
 			UndoFunc: "vol-create.UndoPrepareBricks",
@@ -232,7 +281,7 @@ func CreateVolume(ctx context.Context, req api.VolCreateReq) (status int, err er
 			Nodes:    nodes,
 		},
 		{
-			DoFunc:   "vol-create.StoreVolume",
+			DoFunc:   "vol-create.AddVolume",
 			UndoFunc: "vol-create.UndoStoreVolume",
 			Nodes:    []uuid.UUID{gdctx.MyUUID},
 			Sync:     true,
@@ -2,7 +2,9 @@ package volumecommands
 
 import (
 	"fmt"
+	"io"
 	"net/http"
+	"time"
 
 	"github.com/gluster/glusterd2/glusterd2/events"
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
@@ -10,9 +12,13 @@ import (
 	"github.com/gluster/glusterd2/glusterd2/transaction"
 	transactionv2 "github.com/gluster/glusterd2/glusterd2/transactionv2"
 	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/glusterd2/volumetrash"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
 
 	"github.com/gorilla/mux"
 	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
 
 	"go.opencensus.io/trace"
 )
@@ -31,8 +37,28 @@ func deleteVolume(c transaction.TxnCtx) error {
 	return err
 }
 
+func trashVolume(c transaction.TxnCtx) error {
+	var (
+		volinfo   volume.Volinfo
+		purgeAt   time.Time
+		brickData string
+	)
+	if err := c.Get("volinfo", &volinfo); err != nil {
+		return err
+	}
+	if err := c.Get("purge-at", &purgeAt); err != nil {
+		return err
+	}
+	if err := c.Get("brick-data", &brickData); err != nil {
+		return err
+	}
+
+	return volume.TrashVolume(&volinfo, purgeAt, brickData)
+}
+
 func registerVolDeleteStepFuncs() {
 	transaction.RegisterStepFunc(deleteVolume, "vol-delete.Store")
+	transaction.RegisterStepFunc(trashVolume, "vol-delete.Trash")
 	transaction.RegisterStepFunc(txnCleanBricks, "vol-delete.CleanBricks")
 }
 
@@ -42,6 +68,19 @@ func volumeDeleteHandler(w http.ResponseWriter, r *http.Request) {
 	logger := gdctx.GetReqLogger(ctx)
 	volname := mux.Vars(r)["volname"]
 
+	var req api.VolumeDeleteReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil && err != io.EOF {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+	if req.BrickData == "" {
+		req.BrickData = api.BrickDataKeep
+	}
+	if !volumetrash.ValidBrickData(req.BrickData) {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrInvalidBrickData)
+		return
+	}
+
 	ctx, span := trace.StartSpan(ctx, "/volumeDeleteHandler")
 	defer span.End()
 
@@ -72,18 +111,35 @@ func volumeDeleteHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	bricksAutoProvisioned := volinfo.IsAutoProvisioned() || volinfo.IsSnapshotProvisioned()
-	txn.Steps = []*transaction.Step{
-		{
-			DoFunc: "vol-delete.CleanBricks",
-			Nodes:  volinfo.Nodes(),
-			Skip:   !bricksAutoProvisioned,
-		},
-		{
+	// The volume is retained in the trash, with its bricks, for the grace
+	// period, unless purged at once
+	retention := volumetrash.Retention()
+	purge := req.Purge || retention == 0
+	purgeAt := time.Now().Add(retention)
+
+	if purge {
+		txn.Steps = append(volumetrash.PurgeSteps(volinfo, req.BrickData), &transaction.Step{
 			DoFunc: "vol-delete.Store",
 			Nodes:  []uuid.UUID{gdctx.MyUUID},
 			Sync:   true,
-		},
+		})
+	} else {
+		txn.Steps = []*transaction.Step{
+			{
+				DoFunc: "vol-delete.Trash",
+				Nodes:  []uuid.UUID{gdctx.MyUUID},
+				Sync:   true,
+			},
+		}
+	}
+
+	if err := txn.Ctx.Set("purge-at", purgeAt); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	if err := txn.Ctx.Set("brick-data", req.BrickData); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
 	}
 
 	if err := txn.Ctx.Set("volinfo", volinfo); err != nil {
@@ -103,7 +159,18 @@ func volumeDeleteHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	events.Broadcast(volume.NewEvent(volume.EventVolumeDeleted, volinfo))
+	logger.WithFields(log.Fields{
+		"volume":     volname,
+		"purged":     purge,
+		"brick-data": req.BrickData,
+	}).Info("volume deleted")
+
+	e := volume.NewEvent(volume.EventVolumeDeleted, volinfo)
+	e.Data["volume.brick_data"] = req.BrickData
+	if !purge {
+		e.Data["volume.purge_at"] = purgeAt.Format(time.RFC3339)
+	}
+	events.Broadcast(e)
 
 	restutils.SendHTTPResponse(ctx, w, http.StatusNoContent, nil)
 }
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/brickcrypt"
 	"github.com/gluster/glusterd2/glusterd2/brickmux"
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
 	"github.com/gluster/glusterd2/glusterd2/transaction"
@@ -63,6 +64,9 @@ func expandValidatePrepare(c transaction.TxnCtx) error {
 		c.Logger().WithError(err).Error("failed to create new brick entries")
 		return err
 	}
+	for i := range newBricks {
+		newBricks[i].User = volinfo.BrickUser
+	}
 
 	if err := c.Set("bricks", newBricks); err != nil {
 		return err
@@ -362,6 +366,14 @@ func expandLocalBricks(volinfo *volume.Volinfo, expansionTpSizePerBrick uint64,
 					return err
 				}
 
+				// extend the mapping of the encrypted lv
+				if b.Encrypted {
+					err = brickcrypt.Resize(b.VgName, lvName)
+					if err != nil {
+						return err
+					}
+				}
+
 				// Update current Vg free size
 				err = deviceutils.UpdateDeviceFreeSize(gdctx.MyUUID.String(), b.RootDevice)
 				if err != nil {
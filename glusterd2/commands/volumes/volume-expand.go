@@ -1,11 +1,14 @@
 package volumecommands
 
 import (
+	"context"
 	"net/http"
 	"path/filepath"
 
 	"github.com/gluster/glusterd2/glusterd2/events"
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/hooks"
+	"github.com/gluster/glusterd2/glusterd2/namespace"
 	"github.com/gluster/glusterd2/glusterd2/peer"
 	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
 	"github.com/gluster/glusterd2/glusterd2/transaction"
@@ -68,8 +71,6 @@ func checkForLvmResize(req api.VolExpandReq, volinfo *volume.Volinfo) bool {
 func volumeExpandHandler(w http.ResponseWriter, r *http.Request) {
 
 	ctx := r.Context()
-	ctx, span := trace.StartSpan(ctx, "/volumeExpandHandler")
-	defer span.End()
 	logger := gdctx.GetReqLogger(ctx)
 	volname := mux.Vars(r)["volname"]
 
@@ -79,23 +80,75 @@ func volumeExpandHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := validateVolumeExpandReq(req); err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+	oldVolinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
 		return
 	}
 
-	txn, err := transaction.NewTxnWithLocks(ctx, volname)
+	volinfo, status, err := ExpandVolume(ctx, volname, req)
 	if err != nil {
-		status, err := restutils.ErrToStatusCode(err)
 		restutils.SendHTTPError(ctx, w, status, err)
 		return
 	}
+
+	logger.WithField("volume-name", volinfo.Name).Info("volume expanded")
+	events.Broadcast(volume.NewEvent(volume.EventVolumeExpanded, volinfo))
+
+	info := volume.CreateVolumeInfoResp(volinfo)
+	if added := addedBricks(oldVolinfo, info); len(added) > 0 {
+		hooks.RunPost(api.HookPostAddBrick, info, added)
+	}
+
+	resp := createVolumeExpandResp(volinfo)
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+// volumeNamespace returns the namespace of the volume, none if the volume is
+// not found. The namespace of a volume never changes, hence it can be read
+// before locking the volume.
+func volumeNamespace(volname string) string {
+	v, err := volume.GetVolume(volname)
+	if err != nil {
+		return ""
+	}
+	return v.Namespace
+}
+
+// ExpandVolume expands a volume, either by adding bricks or, for volumes
+// whose bricks were provisioned by glusterd2, by resizing the bricks
+func ExpandVolume(ctx context.Context, volname string, req api.VolExpandReq) (*volume.Volinfo, int, error) {
+	ctx, span := trace.StartSpan(ctx, "/volumeExpandHandler")
+	defer span.End()
+	logger := gdctx.GetReqLogger(ctx)
+
+	if err := validateVolumeExpandReq(req); err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	lockIDs := []string{volname}
+	ns := volumeNamespace(volname)
+	if ns != "" {
+		lockIDs = append(lockIDs, namespace.LockID(ns))
+	}
+	txn, err := transaction.NewTxnWithLocks(ctx, lockIDs...)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		return nil, status, err
+	}
 	defer txn.Done()
 
 	volinfo, err := volume.GetVolume(volname)
 	if err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, http.StatusInternalServerError, err
+	}
+
+	if volinfo.Namespace != "" && req.Size > 0 {
+		if err := namespace.CheckQuotas(volinfo.Namespace, 0, req.Size); err != nil {
+			status, err := restutils.ErrToStatusCode(err)
+			return nil, status, err
+		}
 	}
 
 	var expansionSizePerBrick uint64
@@ -112,8 +165,7 @@ func volumeExpandHandler(w http.ResponseWriter, r *http.Request) {
 				for _, b := range req.Bricks {
 
 					if brick.PeerID.String() == b.PeerID && brick.Path == filepath.Clean(b.Path) {
-						restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrDuplicateBrickPath)
-						return
+						return nil, http.StatusBadRequest, errors.ErrDuplicateBrickPath
 					}
 				}
 
@@ -138,13 +190,11 @@ func volumeExpandHandler(w http.ResponseWriter, r *http.Request) {
 		bricksInfo := volinfo.GetBricks()
 		brickVgMapping, ok, err = deviceutils.CheckForAvailableVgSize(totalExpansionSizePerBrick, bricksInfo)
 		if !ok && err == nil {
-			restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "Space not sufficient on device")
-			return
+			return nil, http.StatusBadRequest, errors.ErrInsufficientDeviceSpace
 		}
 
 		if err != nil {
-			restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-			return
+			return nil, http.StatusInternalServerError, err
 		}
 
 	}
@@ -152,14 +202,12 @@ func volumeExpandHandler(w http.ResponseWriter, r *http.Request) {
 	nodes, err := req.Nodes()
 	if err != nil {
 		logger.WithError(err).Error("could not prepare node list")
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, http.StatusInternalServerError, err
 	}
 
 	allNodes, err := peer.GetPeerIDs()
 	if err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, http.StatusInternalServerError, err
 	}
 
 	txn.Nodes = allNodes
@@ -223,28 +271,23 @@ func volumeExpandHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := txn.Ctx.Set("req", &req); err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, http.StatusInternalServerError, err
 	}
 
 	if err := txn.Ctx.Set("volname", volname); err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, http.StatusInternalServerError, err
 	}
 
 	if err := txn.Ctx.Set("expansionTpSizePerBrick", expansionTpSizePerBrick); err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, http.StatusInternalServerError, err
 	}
 
 	if err := txn.Ctx.Set("expansionMetadataSizePerBrick", expansionMetadataSizePerBrick); err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, http.StatusInternalServerError, err
 	}
 
 	if err := txn.Ctx.Set("brickVgMapping", brickVgMapping); err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, http.StatusInternalServerError, err
 	}
 
 	// Add relevant attributes to the root span
@@ -262,21 +305,34 @@ func volumeExpandHandler(w http.ResponseWriter, r *http.Request) {
 	if err = txn.Do(); err != nil {
 		logger.WithError(err).WithField("volume-name", volname).Error("volume expand transaction failed")
 		status, err := restutils.ErrToStatusCode(err)
-		restutils.SendHTTPError(ctx, w, status, err)
-		return
+		return nil, status, err
 	}
 
 	volinfo, err = volume.GetVolume(volname)
 	if err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, http.StatusInternalServerError, err
 	}
 
-	logger.WithField("volume-name", volinfo.Name).Info("volume expanded")
-	events.Broadcast(volume.NewEvent(volume.EventVolumeExpanded, volinfo))
+	return volinfo, http.StatusOK, nil
+}
 
-	resp := createVolumeExpandResp(volinfo)
-	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+// addedBricks returns the bricks of the volume the expansion added, none if
+// the bricks were resized
+func addedBricks(old *volume.Volinfo, info *api.VolumeInfo) []api.BrickInfo {
+	existing := make(map[string]bool)
+	for _, b := range old.GetBricks() {
+		existing[b.ID.String()] = true
+	}
+
+	var added []api.BrickInfo
+	for _, s := range info.Subvols {
+		for _, b := range s.Bricks {
+			if !existing[b.ID.String()] {
+				added = append(added, b)
+			}
+		}
+	}
+	return added
 }
 
 func createVolumeExpandResp(v *volume.Volinfo) *api.VolumeExpandResp {
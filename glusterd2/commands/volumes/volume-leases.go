@@ -0,0 +1,191 @@
+package volumecommands
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/daemon"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/options"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/servers/sunrpc/dict"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/gorilla/mux"
+)
+
+const (
+	// leasesKey is the option of the leases xlator making the bricks grant
+	// leases
+	leasesKey = "features/leases.leases"
+
+	// leasesStatsPrefix prefixes the keys of the stats of the leases
+	// xlator in the xlator info of a brick
+	leasesStatsPrefix = "leases."
+
+	volumeLeasesTxnKey = "volume-leases"
+)
+
+func registerVolLeasesStepFuncs() {
+	transaction.RegisterStepFunc(txnVolumeLeases, "volume.Leases")
+}
+
+// leasesEnabled tells if the bricks of the volume grant leases
+func leasesEnabled(v *volume.Volinfo) bool {
+	opts, err := options.EffectiveVolumeOptions(v.Options)
+	if err != nil {
+		opts = v.Options
+	}
+	for _, k := range []string{"brick." + leasesKey, leasesKey} {
+		if value, ok := opts[k]; ok {
+			on, _ := options.StringToBoolean(value)
+			return on
+		}
+	}
+	return false
+}
+
+// leasesStats returns the stats of the leases xlator out of the xlator info
+// of a brick
+func leasesStats(info map[string]string) map[string]string {
+	stats := make(map[string]string)
+	for k, v := range info {
+		if strings.HasPrefix(k, leasesStatsPrefix) {
+			stats[strings.TrimPrefix(k, leasesStatsPrefix)] = v
+		}
+	}
+	return stats
+}
+
+// brickLeases asks the brick for the stats of its leases xlator, without
+// clearing its profile info
+func brickLeases(v *volume.Volinfo, b brick.Brickinfo) (map[string]string, error) {
+	brickDaemon, err := brick.NewGlusterfsd(b)
+	if err != nil {
+		return nil, err
+	}
+	client, err := daemon.GetRPCClient(brickDaemon)
+	if err != nil {
+		return nil, err
+	}
+
+	reqDict := map[string]string{
+		"peek":    "1",
+		"op":      "3",
+		"info-op": "3",
+		"volname": v.Name,
+		"vol-id":  v.ID.String(),
+	}
+	req := &brick.GfBrickOpReq{
+		Name: b.Path,
+		Op:   int(brick.OpBrickXlatorInfo),
+	}
+	if req.Input, err = dict.Serialize(reqDict); err != nil {
+		return nil, err
+	}
+	var rsp brick.GfBrickOpRsp
+	if err := client.Call("Brick.OpBrickXlatorInfo", req, &rsp); err != nil {
+		return nil, err
+	}
+	if rsp.OpRet != 0 {
+		return nil, fmt.Errorf("failed to get the xlator info of the brick: %s", rsp.OpErrstr)
+	}
+	info, err := dict.Unserialize(rsp.Output)
+	if err != nil {
+		return nil, err
+	}
+	return leasesStats(info), nil
+}
+
+// txnVolumeLeases reports the state of the leases of the local bricks of the
+// volume
+func txnVolumeLeases(c transaction.TxnCtx) error {
+	var volinfo volume.Volinfo
+	if err := c.Get("volinfo", &volinfo); err != nil {
+		return err
+	}
+
+	bricks := []api.BrickLeases{}
+	for _, b := range volinfo.GetLocalBricks() {
+		bl := api.BrickLeases{
+			Peer:  b.PeerID,
+			Brick: b.Path,
+		}
+		stats, err := brickLeases(&volinfo, b)
+		if err != nil {
+			c.Logger().WithError(err).WithField(
+				"brick", b.String()).Error("failed to get the leases of the brick")
+			bl.Error = err.Error()
+		} else {
+			bl.Stats = stats
+		}
+		bricks = append(bricks, bl)
+	}
+	return c.SetNodeResult(gdctx.MyUUID, volumeLeasesTxnKey, bricks)
+}
+
+func volumeLeasesHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+	volname := mux.Vars(r)["volname"]
+
+	vol, err := volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	resp := api.VolumeLeasesResp{
+		Enabled: leasesEnabled(vol),
+		Bricks:  []api.BrickLeases{},
+	}
+	if !resp.Enabled {
+		restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+		return
+	}
+	if vol.State != volume.VolStarted {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrVolNotStarted)
+		return
+	}
+
+	txn := transaction.NewTxn(ctx)
+	defer txn.Done()
+	nodes := vol.Nodes()
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc: "volume.Leases",
+			Nodes:  nodes,
+		},
+	}
+	if err := txn.Ctx.Set("volinfo", vol); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	// Some nodes may not be up, which is okay.
+	txn.DontCheckAlive = true
+	txn.DisableRollback = true
+
+	if err := txn.Do(); err != nil {
+		logger.WithError(err).WithField("volume", volname).Error("failed to get the leases of the volume")
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	for _, node := range nodes {
+		var tmp []api.BrickLeases
+		if err := txn.Ctx.GetNodeResult(node, volumeLeasesTxnKey, &tmp); err != nil {
+			// skip if we do not have information
+			continue
+		}
+		resp.Bricks = append(resp.Bricks, tmp...)
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
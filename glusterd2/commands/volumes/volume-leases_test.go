@@ -0,0 +1,19 @@
+package volumecommands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLeasesStats(t *testing.T) {
+	info := map[string]string{
+		"leases.granted":  "12",
+		"leases.recalled": "3",
+		"throttle.queued": "1",
+		"1-cumulative":    "x",
+	}
+	stats := leasesStats(info)
+	assert.Equal(t, map[string]string{"granted": "12", "recalled": "3"}, stats)
+	assert.Empty(t, leasesStats(map[string]string{}))
+}
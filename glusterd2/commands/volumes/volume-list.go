@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
 	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
 	"github.com/gluster/glusterd2/glusterd2/volume"
 	"github.com/gluster/glusterd2/pkg/api"
@@ -14,6 +15,11 @@ import (
 
 func volumeListHandler(w http.ResponseWriter, r *http.Request) {
 
+	if r.URL.Query().Get("watch") == "true" {
+		volumeWatchHandler(w, r)
+		return
+	}
+
 	ctx := r.Context()
 	ctx, span := trace.StartSpan(ctx, "/volumeListHandler")
 	defer span.End()
@@ -28,11 +34,29 @@ func volumeListHandler(w http.ResponseWriter, r *http.Request) {
 	if valueFound {
 		filterParams["value"] = values[0]
 	}
+	selector, err := volume.ParseSelector(r.URL.Query().Get("selector"))
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
 	volumes, err := volume.GetVolumes(ctx, filterParams)
 	if err != nil {
 		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
 		return
 	}
+	// Requests scoped to a namespace only see its volumes, the others can
+	// ask for the volumes of a namespace
+	ns := gdctx.GetReqNamespace(ctx)
+	if ns == "" {
+		ns = r.URL.Query().Get("namespace")
+	}
+	if ns != "" {
+		volumes = namespaceVolumes(volumes, ns)
+	}
+	if len(selector) > 0 {
+		volumes = volume.ApplyCustomFilters(volumes, volume.SelectorFilter(selector))
+	}
 
 	// Add the count of volumes being listed as an attribute in the span
 	span.AddAttributes(
@@ -43,6 +67,17 @@ func volumeListHandler(w http.ResponseWriter, r *http.Request) {
 	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
 }
 
+// namespaceVolumes returns the volumes belonging to the namespace
+func namespaceVolumes(volumes []*volume.Volinfo, ns string) []*volume.Volinfo {
+	var filtered []*volume.Volinfo
+	for _, v := range volumes {
+		if v.Namespace == ns {
+			filtered = append(filtered, v)
+		}
+	}
+	return filtered
+}
+
 func createVolumeListResp(ctx context.Context, volumes []*volume.Volinfo) *api.VolumeListResp {
 	_, span := trace.StartSpan(ctx, "createVolumeListResp")
 	defer span.End()
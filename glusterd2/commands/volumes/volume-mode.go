@@ -0,0 +1,58 @@
+package volumecommands
+
+import (
+	"net/http"
+
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/gorilla/mux"
+)
+
+func volumeSetModeHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	volname := mux.Vars(r)["volname"]
+
+	var req api.VolumeModeReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrJSONParsingFailed)
+		return
+	}
+
+	opts, err := volume.ModeOptions(&req)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	if volinfo.IsEnterpriseRetained() && req.Mode != api.VolumeModeWORMFileLevel {
+		restutils.SendHTTPError(ctx, w, http.StatusForbidden, errors.ErrWORMEnterpriseRetained)
+		return
+	}
+
+	// The read-only and worm xlators are not basic options, the mode is
+	// however a supported setting of the volume
+	optReq := api.VolOptionReq{
+		Options: opts,
+		VolOptionFlags: api.VolOptionFlags{
+			AllowAdvanced:     true,
+			AllowExperimental: true,
+		},
+	}
+	volinfo, status, err := setVolumeOptions(ctx, volname, optReq)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	resp := (*api.VolumeModeResp)(volume.CreateVolumeInfoResp(volinfo))
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
@@ -1,14 +1,17 @@
 package volumecommands
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
 	"github.com/gluster/glusterd2/glusterd2/options"
+	"github.com/gluster/glusterd2/glusterd2/opversion"
 	"github.com/gluster/glusterd2/glusterd2/peer"
 	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
 	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/volgen"
 	"github.com/gluster/glusterd2/glusterd2/volume"
 	"github.com/gluster/glusterd2/glusterd2/xlator"
 	"github.com/gluster/glusterd2/pkg/api"
@@ -57,6 +60,10 @@ func optionSetValidate(c transaction.TxnCtx) error {
 		volinfo.Options[k] = v
 	}
 
+	if err := volgen.CheckCustomXlators(&volinfo); err != nil {
+		return fmt.Errorf("validation failed for volume option: %s", err.Error())
+	}
+
 	err = c.Set("volinfo", volinfo)
 
 	return err
@@ -195,9 +202,6 @@ func registerVolOptionStepFuncs() {
 func volumeOptionsHandler(w http.ResponseWriter, r *http.Request) {
 
 	ctx := r.Context()
-	ctx, span := trace.StartSpan(ctx, "/volumeOptionsHandler")
-	defer span.End()
-	logger := gdctx.GetReqLogger(ctx)
 	volname := mux.Vars(r)["volname"]
 
 	var req api.VolOptionReq
@@ -211,33 +215,52 @@ func volumeOptionsHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	txn, err := transaction.NewTxnWithLocks(ctx, volname)
+	volinfo, status, err := setVolumeOptions(ctx, volname, req)
 	if err != nil {
-		status, err := restutils.ErrToStatusCode(err)
 		restutils.SendHTTPError(ctx, w, status, err)
 		return
 	}
+
+	resp := createVolumeOptionResp(volinfo)
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+// setVolumeOptions sets the options on the volume, returning the volume
+// updated
+func setVolumeOptions(ctx context.Context, volname string, req api.VolOptionReq) (*volume.Volinfo, int, error) {
+	ctx, span := trace.StartSpan(ctx, "/volumeOptionsHandler")
+	defer span.End()
+	logger := gdctx.GetReqLogger(ctx)
+
+	txn, err := transaction.NewTxnWithLocks(ctx, volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		return nil, status, err
+	}
 	defer txn.Done()
 
 	volinfo, err := volume.GetVolume(volname)
 	if err != nil {
 		status, err := restutils.ErrToStatusCode(err)
-		restutils.SendHTTPError(ctx, w, status, err)
-		return
+		return nil, status, err
 	}
 
 	//save volume information for transaction failure scenario
 	if err := txn.Ctx.Set("oldvolinfo", volinfo); err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, http.StatusInternalServerError, err
 	}
 	allNodes, err := peer.GetPeerIDs()
 	if err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, http.StatusInternalServerError, err
+	}
+
+	precheck, err := opversion.PrecheckStep(txn.Ctx, requiredOpVersion(req.Options), volinfo.Nodes())
+	if err != nil {
+		return nil, http.StatusBadRequest, err
 	}
 
 	txn.Steps = []*transaction.Step{
+		precheck,
 		{
 			DoFunc: "vol-option.Validate",
 			Nodes:  []uuid.UUID{gdctx.MyUUID},
@@ -266,13 +289,11 @@ func volumeOptionsHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := txn.Ctx.Set("req", &req); err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, http.StatusInternalServerError, err
 	}
 
 	if err := txn.Ctx.Set("volinfo", volinfo); err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, http.StatusInternalServerError, err
 	}
 
 	// Add relevant attributes to the span
@@ -290,19 +311,15 @@ func volumeOptionsHandler(w http.ResponseWriter, r *http.Request) {
 	if err := txn.Do(); err != nil {
 		logger.WithError(err).Error("volume option transaction failed")
 		status, err := restutils.ErrToStatusCode(err)
-		restutils.SendHTTPError(ctx, w, status, err)
-		return
+		return nil, status, err
 	}
 
 	volinfo, err = volume.GetVolume(volname)
 	if err != nil {
 		status, err := restutils.ErrToStatusCode(err)
-		restutils.SendHTTPError(ctx, w, status, err)
-		return
+		return nil, status, err
 	}
-
-	resp := createVolumeOptionResp(volinfo)
-	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+	return volinfo, http.StatusOK, nil
 }
 
 func createVolumeOptionResp(v *volume.Volinfo) *api.VolumeOptionResp {
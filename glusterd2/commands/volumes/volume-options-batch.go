@@ -0,0 +1,85 @@
+package volumecommands
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+)
+
+func validateVolOptionsBatchReq(req *api.VolOptionsBatchReq) (volume.Selector, error) {
+	if len(req.Options) == 0 {
+		return nil, errors.New("no options to set")
+	}
+	if containsReservedGroupProfile(req.Options) {
+		return nil, gderrors.ErrReservedGroupProfile
+	}
+
+	req.Selector = strings.TrimSpace(req.Selector)
+	if req.All && req.Selector != "" {
+		return nil, errors.New("a selector cannot be given with all")
+	}
+	if !req.All && req.Selector == "" {
+		return nil, errors.New("a selector, or all, is required")
+	}
+	return volume.ParseSelector(req.Selector)
+}
+
+// volumeOptionsBatchHandler sets the options on the volumes selected, one
+// volume after the other, each in its own transaction, so that the options
+// of many volumes are set without flooding the transaction engine
+func volumeOptionsBatchHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+
+	var req api.VolOptionsBatchReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
+		return
+	}
+
+	selector, err := validateVolOptionsBatchReq(&req)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	volumes, err := volume.GetVolumes(ctx)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	volumes = volume.ApplyCustomFilters(volumes, volume.SelectorFilter(selector))
+	sort.Slice(volumes, func(i, j int) bool { return volumes[i].Name < volumes[j].Name })
+
+	resp := api.VolumeOptionsBatchResp{Results: make([]api.VolumeBatchResult, 0, len(volumes))}
+	for _, v := range volumes {
+		result := api.VolumeBatchResult{Volume: v.Name}
+		switch {
+		case ctx.Err() != nil, req.StopOnError && resp.Failed > 0:
+			result.Status = api.BatchVolumeSkipped
+			resp.Skipped++
+		default:
+			if _, _, err := setVolumeOptions(ctx, v.Name, req.VolOptionReq); err != nil {
+				logger.WithError(err).WithField("volume", v.Name).Warn("failed to set volume options of batch")
+				result.Status = api.BatchVolumeFailed
+				result.Error = err.Error()
+				resp.Failed++
+			} else {
+				result.Status = api.BatchVolumeSet
+				resp.Set++
+			}
+		}
+		resp.Results = append(resp.Results, result)
+	}
+
+	logger.WithField("selector", req.Selector).WithField("set", resp.Set).
+		WithField("failed", resp.Failed).WithField("skipped", resp.Skipped).Info("batch volume options set")
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
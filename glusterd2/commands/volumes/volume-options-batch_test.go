@@ -0,0 +1,34 @@
+package volumecommands
+
+import (
+	"testing"
+
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateVolOptionsBatchReq(t *testing.T) {
+	opts := api.VolOptionReq{Options: map[string]string{"performance/io-cache.cache-size": "64MB"}}
+
+	s, err := validateVolOptionsBatchReq(&api.VolOptionsBatchReq{Selector: "env=prod", VolOptionReq: opts})
+	require.Nil(t, err)
+	assert.Len(t, s, 1)
+
+	s, err = validateVolOptionsBatchReq(&api.VolOptionsBatchReq{All: true, VolOptionReq: opts})
+	require.Nil(t, err)
+	assert.Len(t, s, 0)
+
+	for _, req := range []api.VolOptionsBatchReq{
+		{Selector: "env=prod"},
+		{VolOptionReq: opts},
+		{Selector: " ", VolOptionReq: opts},
+		{Selector: "env=prod", All: true, VolOptionReq: opts},
+		{Selector: "env=pr od", VolOptionReq: opts},
+		{All: true, VolOptionReq: api.VolOptionReq{Options: map[string]string{"profile.default.db": "on"}}},
+	} {
+		_, err := validateVolOptionsBatchReq(&req)
+		assert.NotNil(t, err, req)
+	}
+}
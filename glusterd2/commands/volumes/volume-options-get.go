@@ -9,6 +9,7 @@ import (
 	"github.com/gluster/glusterd2/glusterd2/volume"
 	"github.com/gluster/glusterd2/glusterd2/xlator"
 	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/errors"
 
 	"github.com/gorilla/mux"
 )
@@ -26,6 +27,12 @@ func volumeOptionsGetHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	defaults, err := clusterVolumeDefaults()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
 	optname, found := mux.Vars(r)["optname"]
 
 	if found {
@@ -38,19 +45,36 @@ func volumeOptionsGetHandler(w http.ResponseWriter, r *http.Request) {
 			restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
 			return
 		}
-		resp := createVolumeOptionGetResp(volinfo, opt, optname)
+		resp := createVolumeOptionGetResp(volinfo, defaults, opt, optname)
 		restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
 	} else {
-		resp := createVolumeOptionsGetResp(volinfo)
+		resp := createVolumeOptionsGetResp(volinfo, defaults)
 		restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
 	}
 }
 
-func createVolumeOptionGetResp(volinfo *volume.Volinfo, opt *options.Option, optname string) *api.VolumeOptionGetResp {
+// clusterVolumeDefaults returns the default volume options set at the
+// cluster level
+func clusterVolumeDefaults() (map[string]string, error) {
+	c, err := options.GetClusterOptions()
+	if err != nil {
+		if err == errors.ErrClusterOptionsNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return c.VolumeDefaults(), nil
+}
+
+// createVolumeOptionGetResp returns the effective value of the option for the
+// volume, which is the value set on the volume, else the default volume
+// option set at the cluster level, else the default value of the option
+func createVolumeOptionGetResp(volinfo *volume.Volinfo, defaults map[string]string, opt *options.Option, optname string) *api.VolumeOptionGetResp {
 	var (
 		resp     api.VolumeOptionGetResp
 		modified bool
 		optValue string
+		source   = api.OptionSourceDefault
 	)
 	optValue = opt.DefaultValue
 
@@ -58,14 +82,26 @@ func createVolumeOptionGetResp(volinfo *volume.Volinfo, opt *options.Option, opt
 		if strings.HasSuffix(key, optname) {
 			modified = true
 			optValue = value
+			source = api.OptionSourceVolume
 			break
 		}
 	}
 
+	if !modified {
+		for key, value := range defaults {
+			if strings.HasSuffix(key, optname) {
+				optValue = value
+				source = api.OptionSourceCluster
+				break
+			}
+		}
+	}
+
 	resp = api.VolumeOptionGetResp{
 		OptName:      optname,
 		Value:        optValue,
 		Modified:     modified,
+		Source:       source,
 		DefaultValue: opt.DefaultValue,
 		OptionLevel:  opt.Level.String(),
 	}
@@ -73,7 +109,7 @@ func createVolumeOptionGetResp(volinfo *volume.Volinfo, opt *options.Option, opt
 	return &resp
 }
 
-func createVolumeOptionsGetResp(volinfo *volume.Volinfo) *api.VolumeOptionsGetResp {
+func createVolumeOptionsGetResp(volinfo *volume.Volinfo, defaults map[string]string) *api.VolumeOptionsGetResp {
 	var resp api.VolumeOptionsGetResp
 
 	for _, xl := range xlator.Xlators() {
@@ -83,7 +119,7 @@ func createVolumeOptionsGetResp(volinfo *volume.Volinfo) *api.VolumeOptionsGetRe
 		for _, opt := range xl.Options {
 			for _, k := range opt.Key {
 				optName := xl.Category + "/" + xl.ID + "." + k
-				volOptRest := createVolumeOptionGetResp(volinfo, opt, optName)
+				volOptRest := createVolumeOptionGetResp(volinfo, defaults, opt, optName)
 				resp = append(resp, *volOptRest)
 			}
 		}
@@ -0,0 +1,34 @@
+package volumecommands
+
+import (
+	"testing"
+
+	"github.com/gluster/glusterd2/glusterd2/options"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateVolumeOptionGetResp(t *testing.T) {
+	opt := &options.Option{Key: []string{"self-heal-daemon"}, DefaultValue: "on"}
+	volinfo := &volume.Volinfo{Options: map[string]string{}}
+	defaults := map[string]string{"replicate.self-heal-daemon": "off"}
+
+	resp := createVolumeOptionGetResp(volinfo, nil, opt, "replicate.self-heal-daemon")
+	assert.Equal(t, "on", resp.Value)
+	assert.Equal(t, api.OptionSourceDefault, resp.Source)
+	assert.False(t, resp.Modified)
+
+	resp = createVolumeOptionGetResp(volinfo, defaults, opt, "replicate.self-heal-daemon")
+	assert.Equal(t, "off", resp.Value)
+	assert.Equal(t, api.OptionSourceCluster, resp.Source)
+	assert.False(t, resp.Modified)
+
+	volinfo.Options["replicate.self-heal-daemon"] = "enable"
+	resp = createVolumeOptionGetResp(volinfo, defaults, opt, "replicate.self-heal-daemon")
+	assert.Equal(t, "enable", resp.Value)
+	assert.Equal(t, api.OptionSourceVolume, resp.Source)
+	assert.True(t, resp.Modified)
+	assert.Equal(t, "on", resp.DefaultValue)
+}
@@ -0,0 +1,80 @@
+package volumecommands
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/gorilla/mux"
+)
+
+func volumePatchHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+	volname := mux.Vars(r)["volname"]
+
+	var req api.VolPatchReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrJSONParsingFailed)
+		return
+	}
+	for key, value := range req.Labels {
+		if err := volume.ValidateLabelKey(key); err != nil {
+			restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+		if value == nil {
+			continue
+		}
+		if err := volume.ValidateLabelValue(*value); err != nil {
+			restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	txn, err := transaction.NewTxnWithLocks(ctx, volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	defer txn.Done()
+
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	patchLabels(volinfo, req.Labels)
+
+	if err := volume.AddOrUpdateVolumeFunc(volinfo); err != nil {
+		logger.WithError(err).WithField("volume", volname).Error("failed to store volume info")
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, "failed to store volume info")
+		return
+	}
+
+	logger.WithField("volume", volname).WithField("labels", volinfo.Labels).Info("volume patched")
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, createVolumeGetResp(volinfo))
+}
+
+// patchLabels merges the labels into the labels of the volume, removing the
+// ones without a value
+func patchLabels(v *volume.Volinfo, labels map[string]*string) {
+	for key, value := range labels {
+		if value == nil {
+			delete(v.Labels, key)
+			continue
+		}
+		if v.Labels == nil {
+			v.Labels = make(map[string]string)
+		}
+		v.Labels[key] = *value
+	}
+}
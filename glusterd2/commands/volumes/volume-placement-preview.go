@@ -0,0 +1,187 @@
+package volumecommands
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gluster/glusterd2/glusterd2/bricksplanner"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/pkg/api"
+)
+
+// placementPreviewVolName is the name of the prospective volume when none
+// is given, which only shows in the paths of its bricks
+const placementPreviewVolName = "preview"
+
+// placementPreviewReq returns the prospective volume given in the query
+// parameters, named as the fields of the volume create request
+func placementPreviewReq(q url.Values) (*api.VolCreateReq, error) {
+	req := &api.VolCreateReq{
+		Name:            q.Get("name"),
+		ProvisionerType: q.Get("provisioner"),
+	}
+	if req.Name == "" {
+		req.Name = placementPreviewVolName
+	}
+
+	for key, field := range map[string]*uint64{
+		"size":              &req.Size,
+		"max-brick-size":    &req.MaxBrickSize,
+		"average-file-size": &req.AverageFileSize,
+	} {
+		if v := q.Get(key); v != "" {
+			n, err := strconv.ParseUint(v, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s: %s", key, v)
+			}
+			*field = n
+		}
+	}
+
+	for key, field := range map[string]*int{
+		"distribute":          &req.DistributeCount,
+		"replica":             &req.ReplicaCount,
+		"arbiter":             &req.ArbiterCount,
+		"disperse":            &req.DisperseCount,
+		"disperse-data":       &req.DisperseDataCount,
+		"disperse-redundancy": &req.DisperseRedundancyCount,
+	} {
+		if v := q.Get(key); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("invalid %s: %s", key, v)
+			}
+			*field = n
+		}
+	}
+
+	for key, field := range map[string]*bool{
+		"snapshot":                &req.SnapshotEnabled,
+		"subvolume-zones-overlap": &req.SubvolZonesOverlap,
+		"encrypted":               &req.Encrypted,
+	} {
+		if v := q.Get(key); v != "" {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s: %s", key, v)
+			}
+			*field = b
+		}
+	}
+
+	if v := q.Get("snapshot-reserve-factor"); v != "" {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid snapshot-reserve-factor: %s", v)
+		}
+		req.SnapshotReserveFactor = f
+	}
+
+	for key, field := range map[string]*[]string{
+		"limit-peers":   &req.LimitPeers,
+		"limit-zones":   &req.LimitZones,
+		"exclude-peers": &req.ExcludePeers,
+		"exclude-zones": &req.ExcludeZones,
+	} {
+		if v := q.Get(key); v != "" {
+			*field = strings.Split(v, ",")
+		}
+	}
+
+	return req, nil
+}
+
+// createPlacementPreviewResp returns the placement of the bricks planned,
+// and the space of the devices before and after creating them
+func createPlacementPreviewResp(req *api.VolCreateReq, before, after []bricksplanner.Vg) *api.PlacementPreviewResp {
+	resp := &api.PlacementPreviewResp{
+		Size:       req.Size,
+		SubvolType: req.SubvolType,
+		Subvols:    req.Subvols,
+		Devices:    []api.PlacementDevice{},
+	}
+
+	bricks := make(map[string]int)
+	for _, sv := range req.Subvols {
+		for _, b := range sv.Bricks {
+			bricks[b.PeerID+"/"+b.VgName]++
+		}
+	}
+	remaining := make(map[string]uint64)
+	for _, vg := range after {
+		remaining[vg.PeerID+"/"+vg.Name] = vg.AvailableSize
+	}
+
+	for _, vg := range before {
+		key := vg.PeerID + "/" + vg.Name
+		d := api.PlacementDevice{
+			PeerID:        vg.PeerID,
+			Zone:          vg.Zone,
+			Device:        vg.Device,
+			Bricks:        bricks[key],
+			AvailableSize: vg.AvailableSize,
+			RemainingSize: vg.AvailableSize,
+		}
+		if size, ok := remaining[key]; ok {
+			d.RemainingSize = size
+		}
+		resp.Devices = append(resp.Devices, d)
+		resp.AvailableSize += d.AvailableSize
+		resp.RemainingSize += d.RemainingSize
+	}
+	return resp
+}
+
+// placementPreviewHandler tells if the volume given in the query parameters
+// can be provisioned, on which devices its bricks would be placed, and the
+// space left on the devices once created. Nothing is created.
+func placementPreviewHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := placementPreviewReq(r.URL.Query())
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+	if req.Size == 0 {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "size is required")
+		return
+	}
+	if err := validateVolCreateReq(req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+	if req.ProvisionerType == "" {
+		req.ProvisionerType = api.ProvisionerTypeLvm
+	}
+	applyDefaults(req)
+	if req.SnapshotReserveFactor < 1 {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "invalid snapshot reserve factor")
+		return
+	}
+
+	before, err := bricksplanner.GetAvailableVgs(req)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	after, err := bricksplanner.PreviewBricks(req)
+	switch err {
+	case nil:
+		resp := createPlacementPreviewResp(req, before, after)
+		resp.Feasible = true
+		restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+	case bricksplanner.ErrNoDevices, bricksplanner.ErrNoSpace:
+		req.Subvols = nil
+		resp := createPlacementPreviewResp(req, before, nil)
+		resp.Reason = err.Error()
+		restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+	default:
+		// The volume itself is not valid
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+	}
+}
@@ -0,0 +1,75 @@
+package volumecommands
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/gluster/glusterd2/glusterd2/bricksplanner"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlacementPreviewReq(t *testing.T) {
+	q := url.Values{
+		"size":          {"1073741824"},
+		"replica":       {"3"},
+		"snapshot":      {"true"},
+		"limit-zones":   {"z1,z2,z3"},
+		"exclude-peers": {"p4"},
+	}
+	req, err := placementPreviewReq(q)
+	require.NoError(t, err)
+	assert.Equal(t, placementPreviewVolName, req.Name)
+	assert.Equal(t, uint64(1073741824), req.Size)
+	assert.Equal(t, 3, req.ReplicaCount)
+	assert.True(t, req.SnapshotEnabled)
+	assert.Equal(t, []string{"z1", "z2", "z3"}, req.LimitZones)
+	assert.Equal(t, []string{"p4"}, req.ExcludePeers)
+
+	for _, q := range []url.Values{
+		{"size": {"1G"}},
+		{"replica": {"-1"}},
+		{"snapshot": {"maybe"}},
+		{"snapshot-reserve-factor": {"x"}},
+	} {
+		_, err := placementPreviewReq(q)
+		assert.Error(t, err, q.Encode())
+	}
+}
+
+func TestCreatePlacementPreviewResp(t *testing.T) {
+	req := &api.VolCreateReq{
+		Size:       100,
+		SubvolType: "replicate",
+		Subvols: []api.SubvolReq{{Bricks: []api.BrickReq{
+			{PeerID: "p1", VgName: "vg1"},
+			{PeerID: "p2", VgName: "vg1"},
+		}}},
+	}
+	before := []bricksplanner.Vg{
+		{Name: "vg1", PeerID: "p1", Device: "/dev/sdb", AvailableSize: 500},
+		{Name: "vg1", PeerID: "p2", Device: "/dev/sdb", AvailableSize: 300},
+		{Name: "vg1", PeerID: "p3", Device: "/dev/sdb", AvailableSize: 200},
+	}
+	after := []bricksplanner.Vg{
+		{Name: "vg1", PeerID: "p1", AvailableSize: 400},
+		{Name: "vg1", PeerID: "p2", AvailableSize: 200},
+		{Name: "vg1", PeerID: "p3", AvailableSize: 200},
+	}
+
+	resp := createPlacementPreviewResp(req, before, after)
+	require.Len(t, resp.Devices, 3)
+	assert.Equal(t, 1, resp.Devices[0].Bricks)
+	assert.Equal(t, uint64(400), resp.Devices[0].RemainingSize)
+	assert.Equal(t, 0, resp.Devices[2].Bricks)
+	assert.Equal(t, uint64(200), resp.Devices[2].RemainingSize)
+	assert.Equal(t, uint64(1000), resp.AvailableSize)
+	assert.Equal(t, uint64(800), resp.RemainingSize)
+
+	// An infeasible volume leaves the space of the devices as it is
+	resp = createPlacementPreviewResp(&api.VolCreateReq{Size: 100}, before, nil)
+	assert.Empty(t, resp.Subvols)
+	assert.Equal(t, resp.AvailableSize, resp.RemainingSize)
+}
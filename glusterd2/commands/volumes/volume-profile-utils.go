@@ -74,6 +74,12 @@ type BrickProfileInfo struct {
 	BrickName       string   `json:"brick-name"`
 	CumulativeStats StatType `json:"cumulative-stats,omitempty"`
 	IntervalStats   StatType `json:"interval-stats,omitempty"`
+	// ThrottleStats are the stats of the throttle xlator of the brick,
+	// when enabled
+	ThrottleStats map[string]string `json:"throttle-stats,omitempty"`
+	// ShardStats are the counters of the shards stored on the brick, when
+	// the volume is sharded
+	ShardStats map[string]string `json:"shard-stats,omitempty"`
 }
 
 // StatType contains profile info of cumulative/interval stats of a brick
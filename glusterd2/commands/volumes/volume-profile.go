@@ -25,6 +25,14 @@ const (
 	intervalType
 )
 
+// throttleStatsPrefix prefixes the keys of the stats of the throttle xlator
+// in the profile info of a brick
+const throttleStatsPrefix = "throttle."
+
+// shardStatsPrefix prefixes the keys of the counters of the shards stored on
+// a brick in its profile info
+const shardStatsPrefix = "shard."
+
 func registerVolProfileStepFuncs() {
 	transaction.RegisterStepFunc(txnVolumeProfile, "volume.Profile")
 }
@@ -65,6 +73,7 @@ func volumeProfileHandler(w http.ResponseWriter, r *http.Request) {
 		{
 			DoFunc: "volume.Profile",
 			Nodes:  volinfo.Nodes(),
+			Stream: true,
 		},
 	}
 
@@ -110,6 +119,16 @@ func volumeProfileHandler(w http.ResponseWriter, r *http.Request) {
 					brickProfileInfo.CumulativeStats = populateStatsWithFop(brickProfileInfo.CumulativeStats, key, value, cumulativeType)
 				} else if strings.HasPrefix(key, nodeResult[brickResult]["interval"]) && nodeResult[brickResult]["interval"] != "" {
 					brickProfileInfo.IntervalStats = populateStatsWithFop(brickProfileInfo.IntervalStats, key, value, intervalType)
+				} else if strings.HasPrefix(key, throttleStatsPrefix) {
+					if brickProfileInfo.ThrottleStats == nil {
+						brickProfileInfo.ThrottleStats = make(map[string]string)
+					}
+					brickProfileInfo.ThrottleStats[strings.TrimPrefix(key, throttleStatsPrefix)] = value
+				} else if strings.HasPrefix(key, shardStatsPrefix) {
+					if brickProfileInfo.ShardStats == nil {
+						brickProfileInfo.ShardStats = make(map[string]string)
+					}
+					brickProfileInfo.ShardStats[strings.TrimPrefix(key, shardStatsPrefix)] = value
 				}
 			}
 
@@ -1,6 +1,8 @@
 package volumecommands
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"strings"
 
@@ -11,7 +13,7 @@ import (
 	"github.com/gluster/glusterd2/glusterd2/volume"
 	"github.com/gluster/glusterd2/glusterd2/xlator"
 	"github.com/gluster/glusterd2/pkg/api"
-	"github.com/gluster/glusterd2/pkg/errors"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
 
 	"github.com/gorilla/mux"
 	"github.com/pborman/uuid"
@@ -33,47 +35,54 @@ func registerVolOptionResetStepFuncs() {
 func volumeResetHandler(w http.ResponseWriter, r *http.Request) {
 
 	ctx := r.Context()
-	logger := gdctx.GetReqLogger(ctx)
-	var err error
 
 	var req api.VolOptionResetReq
 	if err := restutils.UnmarshalRequest(r, &req); err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrJSONParsingFailed)
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
 		return
 	}
 
 	if containsReservedGroupProfile(req.Options) {
-		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrReservedGroupProfile)
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrReservedGroupProfile)
 		return
 	}
 
 	volname := mux.Vars(r)["volname"]
-	volinfo, err := volume.GetVolume(volname)
+	volinfo, status, err := resetVolumeOptions(ctx, volname, req)
 	if err != nil {
-		status, err := restutils.ErrToStatusCode(err)
 		restutils.SendHTTPError(ctx, w, status, err)
 		return
 	}
 
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, volinfo)
+}
+
+// resetVolumeOptions resets the options of the volume
+func resetVolumeOptions(ctx context.Context, volname string, req api.VolOptionResetReq) (*volume.Volinfo, int, error) {
+	logger := gdctx.GetReqLogger(ctx)
+
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		return nil, status, err
+	}
+
 	txn, err := transaction.NewTxnWithLocks(ctx, volname)
 	if err != nil {
 		status, err := restutils.ErrToStatusCode(err)
-		restutils.SendHTTPError(ctx, w, status, err)
-		return
+		return nil, status, err
 	}
 	defer txn.Done()
 
 	// store volinfo to revert changes if transaction fails
 	oldvolinfo := volinfo
 	if err := txn.Ctx.Set("oldvolinfo", oldvolinfo); err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, http.StatusInternalServerError, err
 	}
 
 	req.Options, err = expandGroupOptionsReset(req.Options)
 	if err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, http.StatusInternalServerError, err
 	}
 
 	if req.All {
@@ -81,8 +90,12 @@ func volumeResetHandler(w http.ResponseWriter, r *http.Request) {
 		for key := range volinfo.Options {
 			op, err := xlator.FindOption(key)
 			if err != nil {
-				restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-				return
+				return nil, http.StatusInternalServerError, err
+			}
+			// Sharding stays enabled on the volumes which may
+			// hold sharded files
+			if volume.IsShardKey(key) && volinfo.HasShardedData() {
+				continue
 			}
 			if !op.IsNeverReset() {
 				req.Options = append(req.Options, key)
@@ -95,7 +108,11 @@ func volumeResetHandler(w http.ResponseWriter, r *http.Request) {
 	// reassign the default value
 	var newopts []string
 	if len(volinfo.Subvols) > 0 {
-		optGrp, exists := defaultGroupOptions["profile.default."+strings.ToLower(volinfo.Subvols[0].Type.String())]
+		groupOptions, err := getGroupOptionsFromStore()
+		if err != nil {
+			return nil, http.StatusInternalServerError, err
+		}
+		optGrp, exists := groupOptions["profile.default."+strings.ToLower(volinfo.Subvols[0].Type.String())]
 		if exists {
 		REQLOOP:
 			for _, k := range req.Options {
@@ -119,35 +136,30 @@ func volumeResetHandler(w http.ResponseWriter, r *http.Request) {
 			op, err := xlator.FindOption(k)
 			// If key exists, check for NEVER_RESET and FORCE flags
 			if err != nil {
-				restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
-				return
+				return nil, http.StatusBadRequest, err
 			}
 			if op.IsNeverReset() {
-				errMsg := "Reserved option, can't be reset"
-				restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errMsg)
-				return
+				return nil, http.StatusBadRequest, errors.New("Reserved option, can't be reset")
+			}
+			if volume.IsShardKey(k) && volinfo.HasShardedData() {
+				return nil, http.StatusBadRequest, gderrors.ErrShardedData
 			}
 			if op.IsForceRequired() {
 				if req.Force {
 					delete(volinfo.Options, k)
 				} else {
-					errMsg := "Option needs force flag to be reset"
-					restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errMsg)
-					return
+					return nil, http.StatusBadRequest, errors.New("Option needs force flag to be reset")
 				}
 			}
 			delete(volinfo.Options, k)
 		} else {
-			errMsg := "Option trying to reset is not set or invalid option"
-			restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errMsg)
-			return
+			return nil, http.StatusBadRequest, errors.New("Option trying to reset is not set or invalid option")
 		}
 	}
 
 	allNodes, err := peer.GetPeerIDs()
 	if err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, http.StatusInternalServerError, err
 	}
 
 	// doing this to reuse isActionStepRequired() which takes a map
@@ -182,21 +194,18 @@ func volumeResetHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := txn.Ctx.Set("req", &req); err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, http.StatusInternalServerError, err
 	}
 
 	if err := txn.Ctx.Set("volinfo", volinfo); err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, http.StatusInternalServerError, err
 	}
 
 	if err := txn.Do(); err != nil {
 		logger.WithError(err).Error("volume option transaction failed")
 		status, err := restutils.ErrToStatusCode(err)
-		restutils.SendHTTPError(ctx, w, status, err)
-		return
+		return nil, status, err
 	}
 
-	restutils.SendHTTPResponse(ctx, w, http.StatusOK, volinfo)
+	return volinfo, http.StatusOK, nil
 }
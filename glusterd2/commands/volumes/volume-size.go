@@ -0,0 +1,207 @@
+package volumecommands
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/gorilla/mux"
+	"github.com/pborman/uuid"
+)
+
+const (
+	volumeSizeTxnKey = "volume-size"
+
+	// brickStatfsTimeout bounds the wait for the statfs of the bricks, so
+	// that a hung brick filesystem does not hang the request
+	brickStatfsTimeout = 5 * time.Second
+
+	// volumeSizeCacheTTL is how long the size of a volume is served from the
+	// cache, sparing the bricks the statfs of frequent polls
+	volumeSizeCacheTTL = 10 * time.Second
+)
+
+var errVolumeSizeUnknown = errors.New("size of the volume unknown, no brick of some of its subvolumes answered")
+
+type volumeSizeCacheEntry struct {
+	id      uuid.UUID
+	resp    api.VolumeSizeResp
+	expires time.Time
+}
+
+var volumeSizeCache = struct {
+	sync.Mutex
+	entries map[string]volumeSizeCacheEntry
+}{entries: make(map[string]volumeSizeCacheEntry)}
+
+func registerVolSizeStepFuncs() {
+	transaction.RegisterStepFunc(txnVolumeBrickStatfs, "volume.BrickStatfs")
+}
+
+// localBrickUsages does a statfs of the bricks in parallel, and returns the
+// usages of the bricks which answered within the timeout, keyed by brick ID
+func localBrickUsages(bricks []brick.Brickinfo, statfs func(*brick.Brickinfo) (*volume.Usage, error), timeout time.Duration) map[string]*volume.Usage {
+	type result struct {
+		id    string
+		usage *volume.Usage
+	}
+
+	// Buffered so that the statfs of a hung brick does not block forever
+	// once given up on
+	results := make(chan result, len(bricks))
+	for i := range bricks {
+		go func(b *brick.Brickinfo) {
+			// The usage is nil if the statfs failed
+			u, _ := statfs(b)
+			results <- result{id: b.ID.String(), usage: u}
+		}(&bricks[i])
+	}
+
+	usages := make(map[string]*volume.Usage, len(bricks))
+	deadline := time.After(timeout)
+	for range bricks {
+		select {
+		case r := <-results:
+			if r.usage != nil {
+				usages[r.id] = r.usage
+			}
+		case <-deadline:
+			return usages
+		}
+	}
+	return usages
+}
+
+// txnVolumeBrickStatfs reports the usage of the local bricks of the volume
+func txnVolumeBrickStatfs(c transaction.TxnCtx) error {
+	var volinfo volume.Volinfo
+	if err := c.Get("volinfo", &volinfo); err != nil {
+		return err
+	}
+
+	bricks := volinfo.GetLocalBricks()
+	usages := localBrickUsages(bricks, volume.BrickUsageInfo, brickStatfsTimeout)
+	if len(usages) < len(bricks) {
+		c.Logger().WithField("volume", volinfo.Name).Warn(
+			"statfs failed or timed out on some bricks of the volume")
+	}
+	return c.SetNodeResult(gdctx.MyUUID, volumeSizeTxnKey, usages)
+}
+
+// getVolumeSize gathers the usage of the bricks of the volume from the peers
+// hosting them, and aggregates it according to the layout of the volume
+func getVolumeSize(ctx context.Context, vol *volume.Volinfo) (*api.VolumeSizeResp, error) {
+	nodes := vol.Nodes()
+	txn := transaction.NewTxn(ctx)
+	defer txn.Done()
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc: "volume.BrickStatfs",
+			Nodes:  nodes,
+		},
+	}
+	if err := txn.Ctx.Set("volinfo", vol); err != nil {
+		return nil, err
+	}
+
+	// Bricks on peers down are not accounted for
+	txn.DontCheckAlive = true
+	txn.DisableRollback = true
+
+	if err := txn.Do(); err != nil {
+		return nil, err
+	}
+
+	usages := make(map[string]*volume.Usage)
+	for _, node := range nodes {
+		var tmp map[string]*volume.Usage
+		if err := txn.Ctx.GetNodeResult(node, volumeSizeTxnKey, &tmp); err != nil {
+			continue
+		}
+		for id, u := range tmp {
+			usages[id] = u
+		}
+	}
+
+	u := volume.AggregateUsage(vol, usages)
+	if u == nil {
+		return nil, errVolumeSizeUnknown
+	}
+	return &api.VolumeSizeResp{
+		UsageInfo:      *createUsageInfo(u),
+		Bricks:         len(vol.GetBricks()),
+		BricksReported: len(usages),
+	}, nil
+}
+
+// cachedVolumeSize returns the size of the volume from the cache, unless it
+// expired or belongs to a former volume of the same name
+func cachedVolumeSize(vol *volume.Volinfo, now time.Time) (*api.VolumeSizeResp, bool) {
+	volumeSizeCache.Lock()
+	defer volumeSizeCache.Unlock()
+
+	e, ok := volumeSizeCache.entries[vol.Name]
+	if !ok || !uuid.Equal(e.id, vol.ID) || !now.Before(e.expires) {
+		return nil, false
+	}
+	resp := e.resp
+	return &resp, true
+}
+
+func cacheVolumeSize(vol *volume.Volinfo, resp *api.VolumeSizeResp, now time.Time) {
+	volumeSizeCache.Lock()
+	defer volumeSizeCache.Unlock()
+
+	// Drop the expired entries, of deleted volumes among others
+	for name, e := range volumeSizeCache.entries {
+		if !now.Before(e.expires) {
+			delete(volumeSizeCache.entries, name)
+		}
+	}
+	volumeSizeCache.entries[vol.Name] = volumeSizeCacheEntry{
+		id:      vol.ID,
+		resp:    *resp,
+		expires: now.Add(volumeSizeCacheTTL),
+	}
+}
+
+func volumeSizeHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+	volname := mux.Vars(r)["volname"]
+
+	vol, err := volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	if resp, ok := cachedVolumeSize(vol, time.Now()); ok {
+		restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+		return
+	}
+
+	resp, err := getVolumeSize(ctx, vol)
+	if err != nil {
+		logger.WithError(err).WithField("volume", volname).Error("failed to get the size of the volume")
+		status := http.StatusInternalServerError
+		if err == errVolumeSizeUnknown {
+			status = http.StatusServiceUnavailable
+		}
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	cacheVolumeSize(vol, resp, time.Now())
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
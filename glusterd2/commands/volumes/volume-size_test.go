@@ -0,0 +1,65 @@
+package volumecommands
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLocalBrickUsages validates that the bricks failing or not answering
+// in time are left out of the usages
+func TestLocalBrickUsages(t *testing.T) {
+	bricks := []brick.Brickinfo{
+		{ID: uuid.NewRandom(), Path: "/bricks/ok"},
+		{ID: uuid.NewRandom(), Path: "/bricks/failed"},
+		{ID: uuid.NewRandom(), Path: "/bricks/hung"},
+	}
+
+	hung := make(chan struct{})
+	defer close(hung)
+	statfs := func(b *brick.Brickinfo) (*volume.Usage, error) {
+		switch b.Path {
+		case "/bricks/failed":
+			return nil, errors.New("statfs failed")
+		case "/bricks/hung":
+			<-hung
+		}
+		return &volume.Usage{Capacity: 100}, nil
+	}
+
+	start := time.Now()
+	usages := localBrickUsages(bricks, statfs, 100*time.Millisecond)
+	assert.True(t, time.Since(start) < time.Second)
+	require.Len(t, usages, 1)
+	assert.Equal(t, uint64(100), usages[bricks[0].ID.String()].Capacity)
+}
+
+// TestVolumeSizeCache validates the expiry of the cached sizes, and that the
+// size of a deleted volume is not served for a new volume of the same name
+func TestVolumeSizeCache(t *testing.T) {
+	vol := &volume.Volinfo{ID: uuid.NewRandom(), Name: "sizevol"}
+	now := time.Now()
+
+	_, ok := cachedVolumeSize(vol, now)
+	assert.False(t, ok)
+
+	cacheVolumeSize(vol, &api.VolumeSizeResp{Bricks: 2}, now)
+	resp, ok := cachedVolumeSize(vol, now.Add(time.Second))
+	require.True(t, ok)
+	assert.Equal(t, 2, resp.Bricks)
+
+	_, ok = cachedVolumeSize(vol, now.Add(volumeSizeCacheTTL))
+	assert.False(t, ok)
+
+	recreated := &volume.Volinfo{ID: uuid.NewRandom(), Name: "sizevol"}
+	_, ok = cachedVolumeSize(recreated, now.Add(time.Second))
+	assert.False(t, ok)
+}
@@ -5,6 +5,7 @@ import (
 	"path"
 	"strings"
 
+	"github.com/gluster/glusterd2/glusterd2/brickcrypt"
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
 	"github.com/gluster/glusterd2/glusterd2/transaction"
 	"github.com/gluster/glusterd2/glusterd2/volume"
@@ -23,29 +24,72 @@ func txnPrepareBricks(c transaction.TxnCtx) error {
 		return err
 	}
 
+	// The available sizes of the devices are updated once all the local
+	// bricks are prepared, in a single store transaction
+	var bricks []api.BrickReq
+	sizes := make(map[string]uint64)
 	for _, sv := range req.Subvols {
 		for _, b := range sv.Bricks {
+			if b.PeerID != gdctx.MyUUID.String() {
+				continue
+			}
+
 			var err error
 			if req.ProvisionerType == api.ProvisionerTypeLoop {
-				err = PrepareBrickLoop(b, c)
+				err = prepareBrickLoop(b, c)
 			} else {
-				err = PrepareBrickLvm(b, c)
+				err = prepareBrickLvm(b, c)
 			}
 			if err != nil {
 				return err
 			}
+			bricks = append(bricks, b)
+			sizes[b.RootDevice] += b.TotalSize
+		}
+	}
+
+	if err := deviceutils.ReduceDevicesFreeSize(gdctx.MyUUID.String(), sizes); err != nil {
+		c.Logger().WithError(err).Error("failed to update available size of the devices")
+		return err
+	}
+	for _, b := range bricks {
+		if err := c.Set("freesizeSet."+b.PeerID+b.Path, true); err != nil {
+			return err
 		}
 	}
 
 	return nil
 }
 
+// reduceFreeSize updates the available size of the device of the brick
+// prepared
+func reduceFreeSize(b api.BrickReq, c transaction.TxnCtx) error {
+	err := deviceutils.ReduceDeviceFreeSize(gdctx.MyUUID.String(), b.RootDevice, b.TotalSize)
+	if err != nil {
+		c.Logger().WithError(err).WithField("vg-name", b.VgName).
+			Error("failed to update available size of a device")
+		return err
+	}
+
+	return c.Set("freesizeSet."+b.PeerID+b.Path, true)
+}
+
 // PrepareBrickLvm prepares(Creates thin pool, creates LV, mounts etc.) a single brick
 func PrepareBrickLvm(b api.BrickReq, c transaction.TxnCtx) error {
 	if b.PeerID != gdctx.MyUUID.String() {
 		return nil
 	}
 
+	if err := prepareBrickLvm(b, c); err != nil {
+		return err
+	}
+	return reduceFreeSize(b, c)
+}
+
+// prepareBrickLvm prepares a brick, without updating the available size of
+// its device
+func prepareBrickLvm(b api.BrickReq, c transaction.TxnCtx) error {
+
 	if err := c.Set("freesizeSet."+b.PeerID+b.Path, false); err != nil {
 		return err
 	}
@@ -82,6 +126,18 @@ func PrepareBrickLvm(b api.BrickReq, c transaction.TxnCtx) error {
 		return err
 	}
 
+	// Encrypt the LV, the file system is then made on its mapping
+	if b.Encrypted {
+		err = brickcrypt.Provision(b.VgName, b.LvName)
+		if err != nil {
+			c.Logger().WithError(err).WithFields(log.Fields{
+				"vg-name": b.VgName,
+				"lv-name": b.LvName,
+			}).Error("brick encryption failed")
+			return err
+		}
+	}
+
 	// Make Filesystem
 	var mkfsOpts []string
 	if b.Type == "arbiter" {
@@ -113,18 +169,6 @@ func PrepareBrickLvm(b api.BrickReq, c transaction.TxnCtx) error {
 		return err
 	}
 
-	// Update current Vg free size
-	err = deviceutils.ReduceDeviceFreeSize(gdctx.MyUUID.String(), b.RootDevice, b.TotalSize)
-	if err != nil {
-		c.Logger().WithError(err).WithField("vg-name", b.VgName).
-			Error("failed to update available size of a device")
-		return err
-	}
-
-	if err := c.Set("freesizeSet."+b.PeerID+b.Path, true); err != nil {
-		return err
-	}
-
 	return nil
 }
 
@@ -155,6 +199,16 @@ func txnUndoPrepareBricksLvm(req api.VolCreateReq, c transaction.TxnCtx) error {
 				c.Logger().WithError(err).WithField("path", mountRoot).Error("brick unmount failed")
 			}
 
+			if b.Encrypted {
+				err = brickcrypt.Remove(b.VgName, b.LvName)
+				if err != nil {
+					c.Logger().WithError(err).WithFields(log.Fields{
+						"vg-name": b.VgName,
+						"lv-name": b.LvName,
+					}).Error("brick encryption remove failed")
+				}
+			}
+
 			// Remove LV
 			err = lvmutils.RemoveLV(b.VgName, b.LvName, true)
 			if err != nil {
@@ -196,6 +250,16 @@ func PrepareBrickLoop(b api.BrickReq, c transaction.TxnCtx) error {
 		return nil
 	}
 
+	if err := prepareBrickLoop(b, c); err != nil {
+		return err
+	}
+	return reduceFreeSize(b, c)
+}
+
+// prepareBrickLoop prepares a brick, without updating the available size of
+// its device
+func prepareBrickLoop(b api.BrickReq, c transaction.TxnCtx) error {
+
 	if err := c.Set("freesizeSet."+b.PeerID+b.Path, false); err != nil {
 		return err
 	}
@@ -262,18 +326,6 @@ func PrepareBrickLoop(b api.BrickReq, c transaction.TxnCtx) error {
 		return err
 	}
 
-	// Update current Vg free size
-	err = deviceutils.ReduceDeviceFreeSize(gdctx.MyUUID.String(), b.RootDevice, b.TotalSize)
-	if err != nil {
-		c.Logger().WithError(err).WithField("vg-name", b.VgName).
-			Error("failed to update available size of a device")
-		return err
-	}
-
-	if err := c.Set("freesizeSet."+b.PeerID+b.Path, true); err != nil {
-		return err
-	}
-
 	return nil
 }
 
@@ -4,10 +4,13 @@ import (
 	"context"
 	"io"
 	"net/http"
+	"time"
 
+	"github.com/gluster/glusterd2/glusterd2/brick"
 	"github.com/gluster/glusterd2/glusterd2/brickmux"
 	"github.com/gluster/glusterd2/glusterd2/events"
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/hooks"
 	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
 	"github.com/gluster/glusterd2/glusterd2/transaction"
 	transactionv2 "github.com/gluster/glusterd2/glusterd2/transactionv2"
@@ -22,6 +25,50 @@ import (
 	"go.opencensus.io/trace"
 )
 
+const brickStartsTxnKey = "brick-starts"
+
+func validateBrickLabels(c transaction.TxnCtx) error {
+
+	var volinfo volume.Volinfo
+	if err := c.Get("volinfo", &volinfo); err != nil {
+		return err
+	}
+
+	for _, b := range volinfo.GetLocalBricks() {
+		if err := b.CheckLabel(c.Logger()); err != nil {
+			c.Logger().WithError(err).WithField(
+				"brick", b.String()).Error("brick path is mislabeled")
+			return err
+		}
+	}
+
+	return nil
+}
+
+// resetVolumeID takes over the local brick paths for the volume, whatever
+// volume they belonged to
+func resetVolumeID(c transaction.TxnCtx) error {
+
+	var volinfo volume.Volinfo
+	if err := c.Get("volinfo", &volinfo); err != nil {
+		return err
+	}
+
+	for _, b := range volinfo.GetLocalBricks() {
+		if err := brick.ResetVolumeID(&b); err != nil {
+			c.Logger().WithError(err).WithField(
+				"brick", b.String()).Error("failed to reset the volume ID of the brick")
+			return err
+		}
+		c.Logger().WithFields(log.Fields{
+			"volume": b.VolumeName,
+			"brick":  b.String(),
+		}).Warn("reset the volume ID of the brick")
+	}
+
+	return nil
+}
+
 func startAllBricks(c transaction.TxnCtx) error {
 
 	var volinfo volume.Volinfo
@@ -29,6 +76,14 @@ func startAllBricks(c transaction.TxnCtx) error {
 		return err
 	}
 
+	// Encrypted bricks not unlocked when glusterd2 started, the key
+	// being unavailable then, are unlocked and mounted now
+	if volinfo.IsEncrypted() {
+		if err := volume.MountVolumeBricks(&volinfo, false); err != nil {
+			return err
+		}
+	}
+
 	brickinfos := volinfo.GetLocalBricks()
 	err := volgen.GenerateBricksVolfiles(&volinfo, brickinfos)
 	if err != nil {
@@ -50,6 +105,13 @@ func startAllBricks(c transaction.TxnCtx) error {
 		allVolumes = volumes
 	}
 
+	// The bricks are multiplexed one at a time, as each brick multiplexed
+	// changes the process the next one is multiplexed into. The bricks
+	// started as separate processes are started in parallel.
+	var (
+		starts  []api.BrickStartTime
+		toStart []brick.Brickinfo
+	)
 	for _, b := range brickinfos {
 		c.Logger().WithFields(log.Fields{
 			"volume": b.VolumeName,
@@ -57,10 +119,23 @@ func startAllBricks(c transaction.TxnCtx) error {
 		}).Info("Starting brick")
 
 		if bmuxEnabled {
+			begin := time.Now()
+			// Multiplexed bricks are not started by StartBrick,
+			// which verifies the volume ID of the others
+			if err := brick.VerifyVolumeID(&b); err != nil {
+				return err
+			}
 			err := brickmux.Multiplex(b, &volinfo, allVolumes, c.Logger())
 			switch err {
 			case nil:
 				// successfully multiplexed
+				starts = append(starts, api.BrickStartTime{
+					PeerID:      b.PeerID.String(),
+					Path:        b.Path,
+					Duration:    int64(time.Since(begin) / time.Millisecond),
+					SignedIn:    true,
+					Multiplexed: true,
+				})
 				continue
 			case brickmux.ErrNoCompat:
 				// do nothing, fallback to starting a separate process
@@ -70,15 +145,31 @@ func startAllBricks(c transaction.TxnCtx) error {
 			}
 		}
 
-		if err := b.StartBrick(c.Logger()); err != nil {
-			if err == errors.ErrProcessAlreadyRunning {
-				continue
+		toStart = append(toStart, b)
+	}
+
+	var startErr error
+	for _, r := range brick.StartBricks(toStart, c.Logger()) {
+		if r.Err != nil {
+			c.Logger().WithError(r.Err).WithField(
+				"brick", r.Brick.String()).Error("failed to start brick")
+			if startErr == nil {
+				startErr = r.Err
 			}
-			return err
+			continue
 		}
+		starts = append(starts, api.BrickStartTime{
+			PeerID:   r.Brick.PeerID.String(),
+			Path:     r.Brick.Path,
+			Duration: int64(r.Duration / time.Millisecond),
+			SignedIn: r.SignedIn,
+		})
+	}
+	if startErr != nil {
+		return startErr
 	}
 
-	return nil
+	return c.SetNodeResult(gdctx.MyUUID, brickStartsTxnKey, starts)
 }
 
 func stopAllBricks(c transaction.TxnCtx) error {
@@ -113,6 +204,8 @@ func registerVolStartStepFuncs() {
 		name string
 		sf   transaction.StepFunc
 	}{
+		{"vol-start.ValidateBrickLabels", validateBrickLabels},
+		{"vol-start.ResetVolumeID", resetVolumeID},
 		{"vol-start.StartBricks", startAllBricks},
 		{"vol-start.StartBricksUndo", stopAllBricks},
 		{"vol-start.XlatorActionDoVolumeStart", xlatorActionDoVolumeStart},
@@ -138,20 +231,22 @@ func volumeStartHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	volinfo, status, err := StartVolume(ctx, volname, req)
+	volinfo, starts, status, err := StartVolume(ctx, volname, req)
 	if err != nil {
 		restutils.SendHTTPError(ctx, w, status, err)
 		return
 	}
 
 	events.Broadcast(volume.NewEvent(volume.EventVolumeStarted, volinfo))
+	hooks.RunPost(api.HookPostVolumeStart, volume.CreateVolumeInfoResp(volinfo), nil)
 
-	resp := createVolumeStartResp(volinfo)
+	resp := createVolumeStartResp(volinfo, starts)
 	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
 }
 
-// StartVolume starts a volume
-func StartVolume(ctx context.Context, volname string, req api.VolumeStartReq) (volInfo *volume.Volinfo, status int, err error) {
+// StartVolume starts a volume, and returns the times taken to start its
+// bricks
+func StartVolume(ctx context.Context, volname string, req api.VolumeStartReq) (volInfo *volume.Volinfo, starts []api.BrickStartTime, status int, err error) {
 	logger := gdctx.GetReqLogger(ctx)
 	ctx, span := trace.StartSpan(ctx, "/volumeStartHandler")
 	defer span.End()
@@ -159,21 +254,30 @@ func StartVolume(ctx context.Context, volname string, req api.VolumeStartReq) (v
 	txn, err := transactionv2.NewTxnWithLocks(ctx, volname)
 	if err != nil {
 		status, err := restutils.ErrToStatusCode(err)
-		return nil, status, err
+		return nil, nil, status, err
 	}
 	defer txn.Done()
 
 	volinfo, err := volume.GetVolume(volname)
 	if err != nil {
 		status, err := restutils.ErrToStatusCode(err)
-		return nil, status, err
+		return nil, nil, status, err
 	}
 
 	if volinfo.State == volume.VolStarted && !req.ForceStartBricks {
-		return nil, http.StatusBadRequest, errors.ErrVolAlreadyStarted
+		return nil, nil, http.StatusBadRequest, errors.ErrVolAlreadyStarted
 	}
 
 	txn.Steps = []*transaction.Step{
+		{
+			DoFunc: "vol-start.ValidateBrickLabels",
+			Nodes:  volinfo.Nodes(),
+		},
+		{
+			DoFunc: "vol-start.ResetVolumeID",
+			Nodes:  volinfo.Nodes(),
+			Skip:   !req.ResetVolumeID,
+		},
 		{
 			DoFunc:   "vol-start.StartBricks",
 			UndoFunc: "vol-start.StartBricksUndo",
@@ -193,13 +297,14 @@ func StartVolume(ctx context.Context, volname string, req api.VolumeStartReq) (v
 	}
 
 	if err := txn.Ctx.Set("oldvolinfo", volinfo); err != nil {
-		return nil, http.StatusInternalServerError, err
+		return nil, nil, http.StatusInternalServerError, err
 	}
 
 	volinfo.State = volume.VolStarted
+	volinfo.MarkShardedData()
 
 	if err := txn.Ctx.Set("volinfo", volinfo); err != nil {
-		return nil, http.StatusInternalServerError, err
+		return nil, nil, http.StatusInternalServerError, err
 	}
 
 	span.AddAttributes(
@@ -210,12 +315,24 @@ func StartVolume(ctx context.Context, volname string, req api.VolumeStartReq) (v
 	if err := txn.Do(); err != nil {
 		logger.WithError(err).WithField(
 			"volume", volname).Error("transaction to start volume failed")
-		return nil, http.StatusInternalServerError, err
+		return nil, nil, http.StatusInternalServerError, err
+	}
+
+	for _, node := range volinfo.Nodes() {
+		var nodeStarts []api.BrickStartTime
+		if err := txn.Ctx.GetNodeResult(node, brickStartsTxnKey, &nodeStarts); err != nil {
+			logger.WithError(err).WithField("peer", node.String()).Warn("failed to get the times taken to start the bricks of the peer")
+			continue
+		}
+		starts = append(starts, nodeStarts...)
 	}
 
-	return volinfo, http.StatusOK, nil
+	return volinfo, starts, http.StatusOK, nil
 }
 
-func createVolumeStartResp(v *volume.Volinfo) *api.VolumeStartResp {
-	return (*api.VolumeStartResp)(volume.CreateVolumeInfoResp(v))
+func createVolumeStartResp(v *volume.Volinfo, starts []api.BrickStartTime) *api.VolumeStartResp {
+	return &api.VolumeStartResp{
+		VolumeInfo:  *volume.CreateVolumeInfoResp(v),
+		BrickStarts: starts,
+	}
 }
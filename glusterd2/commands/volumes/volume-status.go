@@ -40,6 +40,14 @@ func volumeStatusHandler(w http.ResponseWriter, r *http.Request) {
 	size := createSizeInfo(s)
 
 	resp := createVolumeStatusResp(volinfo, &size)
+
+	u, err := volume.GetUsage(volinfo.Name)
+	if err != nil {
+		logger.WithError(err).WithField("volume", volinfo.Name).Warn("Failed to get volume usage")
+	} else if u != nil {
+		resp.Usage = createUsageInfo(u)
+	}
+
 	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
 }
 
@@ -1,15 +1,21 @@
 package volumecommands
 
 import (
+	"io"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gluster/glusterd2/glusterd2/brick"
 	"github.com/gluster/glusterd2/glusterd2/brickmux"
 	"github.com/gluster/glusterd2/glusterd2/daemon"
 	"github.com/gluster/glusterd2/glusterd2/events"
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/hooks"
 	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/servers/sunrpc"
 	"github.com/gluster/glusterd2/glusterd2/transaction"
 	transactionv2 "github.com/gluster/glusterd2/glusterd2/transactionv2"
 	"github.com/gluster/glusterd2/glusterd2/volgen"
@@ -23,6 +29,58 @@ import (
 	"go.opencensus.io/trace"
 )
 
+// clientsConnectedError is returned when stopping a volume which has clients
+// connected, without force. The type implements the `api.ErrorResponse`
+// interface to list the clients in the response.
+type clientsConnectedError []api.VolumeClient
+
+func (e clientsConnectedError) Error() string {
+	return errors.ErrVolClientsConnected.Error()
+}
+
+func (e clientsConnectedError) Response() api.ErrorResp {
+	var resp api.ErrorResp
+	for _, c := range e {
+		fields := map[string]string{
+			"peer-id": c.Peer.String(),
+			"address": c.Address,
+		}
+		if c.Pid != 0 {
+			fields["pid"] = strconv.Itoa(c.Pid)
+		}
+		if !c.ConnectedSince.IsZero() {
+			fields["connected-since"] = c.ConnectedSince.Format(time.RFC3339)
+		}
+		resp.Errors = append(resp.Errors, api.HTTPError{
+			Code:    int(api.ErrCodeGeneric),
+			Message: e.Error(),
+			Reason:  api.ReasonVolumeInUse,
+			Fields:  fields,
+		})
+	}
+	return resp
+}
+
+func (e clientsConnectedError) Status() int {
+	return http.StatusConflict
+}
+
+// disconnectClients closes the connections of the clients which fetched the
+// client volfiles of the volume from this node, before its bricks are stopped
+func disconnectClients(c transaction.TxnCtx) error {
+	var volinfo volume.Volinfo
+	if err := c.Get("volinfo", &volinfo); err != nil {
+		return err
+	}
+
+	n := sunrpc.DisconnectClients(volinfo.Name, "", 0)
+	c.Logger().WithFields(log.Fields{
+		"volume":      volinfo.Name,
+		"connections": n,
+	}).Info("disconnected the clients of the volume")
+	return nil
+}
+
 func stopBricks(c transaction.TxnCtx) error {
 
 	var volinfo volume.Volinfo
@@ -103,6 +161,7 @@ func registerVolStopStepFuncs() {
 		name string
 		sf   transaction.StepFunc
 	}{
+		{"vol-stop.DisconnectClients", disconnectClients},
 		{"vol-stop.StopBricks", stopBricks},
 		{"vol-stop.XlatorActionDoVolumeStop", xlatorActionDoVolumeStop},
 		{"vol-stop.XlatorActionUndoVOlumeStop", xlatorActionUndoVolumeStop},
@@ -124,6 +183,12 @@ func volumeStopHandler(w http.ResponseWriter, r *http.Request) {
 	logger := gdctx.GetReqLogger(ctx)
 	volname := mux.Vars(r)["volname"]
 
+	var req api.VolumeStopReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil && err != io.EOF {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
 	txn, err := transactionv2.NewTxnWithLocks(ctx, volname)
 	if err != nil {
 		status, err := restutils.ErrToStatusCode(err)
@@ -149,6 +214,28 @@ func volumeStopHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := hooks.RunPre(api.HookPreVolumeStop, volume.CreateVolumeInfoResp(volinfo)); err != nil {
+		status := http.StatusInternalServerError
+		if _, ok := err.(*hooks.Error); ok {
+			status = http.StatusConflict
+		}
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	clients, err := getVolumeClients(ctx, volinfo)
+	if err != nil {
+		logger.WithError(err).WithField(
+			"volume", volname).Error("failed to get the clients of the volume")
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	mounted := mountedClients(clients)
+	if len(mounted) > 0 && !req.Force {
+		restutils.SendHTTPError(ctx, w, http.StatusConflict, clientsConnectedError(mounted))
+		return
+	}
+
 	txn.Steps = []*transaction.Step{
 		{
 			DoFunc: "vol-stop.StopBricks",
@@ -172,6 +259,29 @@ func volumeStopHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if len(mounted) > 0 {
+		// Disconnect the clients first, for them not to fetch the volfiles
+		// again while the bricks are going down
+		txn.Steps = append([]*transaction.Step{
+			{
+				DoFunc: "vol-stop.DisconnectClients",
+				Nodes:  clientNodes(volinfo),
+			},
+		}, txn.Steps...)
+
+		addrs := make([]string, 0, len(mounted))
+		for _, c := range mounted {
+			addrs = append(addrs, c.Address)
+		}
+		logger.WithFields(log.Fields{
+			"volume":  volname,
+			"clients": addrs,
+		}).Warn("force stopping volume with clients connected")
+		e := volume.NewEvent(volume.EventVolumeClientsDisconnected, volinfo)
+		e.Data["volume.clients"] = strings.Join(addrs, ",")
+		events.Broadcast(e)
+	}
+
 	volinfo.State = volume.VolStopped
 
 	if err := txn.Ctx.Set("volinfo", volinfo); err != nil {
@@ -0,0 +1,38 @@
+package volumecommands
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestClientsConnectedError validates the clients listed when refusing to
+// stop a volume
+func TestClientsConnectedError(t *testing.T) {
+	peer := uuid.NewRandom()
+	clients := api.VolumeClientsResp{
+		{Peer: peer, Layer: api.ClientOfBrick, Brick: "/bricks/b1", Address: "10.0.0.1:49151"},
+		{Peer: peer, Layer: api.ClientOfGlusterd, Address: "10.0.0.2:1023", Pid: 1234},
+	}
+
+	mounted := mountedClients(clients)
+	require.Len(t, mounted, 1)
+	assert.Equal(t, "10.0.0.2:1023", mounted[0].Address)
+	assert.Empty(t, mountedClients(clients[:1]))
+
+	err := clientsConnectedError(mounted)
+	assert.Equal(t, http.StatusConflict, err.Status())
+	resp := err.Response()
+	require.Len(t, resp.Errors, 1)
+	assert.Equal(t, api.ReasonVolumeInUse, resp.Errors[0].Reason)
+	assert.Equal(t, map[string]string{
+		"peer-id": peer.String(),
+		"address": "10.0.0.2:1023",
+		"pid":     "1234",
+	}, resp.Errors[0].Fields)
+}
@@ -0,0 +1,271 @@
+package volumecommands
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/gorilla/mux"
+	"github.com/pborman/uuid"
+	config "github.com/spf13/viper"
+)
+
+// createSubdir creates the subdirectory being exported, by mounting the
+// volume locally
+func createSubdir(c transaction.TxnCtx) error {
+	var volinfo volume.Volinfo
+	if err := c.Get("volinfo", &volinfo); err != nil {
+		return err
+	}
+
+	var subdir string
+	if err := c.Get("subdir", &subdir); err != nil {
+		return err
+	}
+
+	tempDir, err := ioutil.TempDir(config.GetString("rundir"), "gd2mount")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tempDir)
+
+	if err := volume.MountVolume(volinfo.Name, tempDir, ""); err != nil {
+		return err
+	}
+	defer syscall.Unmount(tempDir, syscall.MNT_FORCE)
+
+	return os.MkdirAll(filepath.Join(tempDir, subdir), 0755)
+}
+
+func registerVolSubdirStepFuncs() {
+	transaction.RegisterStepFunc(createSubdir, "vol-subdir.CreateDir")
+}
+
+func validateSubdirAuthAllow(allow []string) error {
+	for _, client := range allow {
+		if client == "" || strings.ContainsAny(client, "(),|") {
+			return errors.ErrInvalidSubdirAuthAllow
+		}
+	}
+	return nil
+}
+
+func volumeSubdirExportCreateHandler(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+	volname := mux.Vars(r)["volname"]
+
+	var req api.SubdirExportReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrJSONParsingFailed)
+		return
+	}
+
+	if req.Path == "" {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrInvalidSubdirPath)
+		return
+	}
+	subdir := path.Clean("/" + req.Path)
+
+	if len(req.AuthAllow) == 0 {
+		req.AuthAllow = []string{"*"}
+	}
+	if err := validateSubdirAuthAllow(req.AuthAllow); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	txn, err := transaction.NewTxnWithLocks(ctx, volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	defer txn.Done()
+
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	if volinfo.IsSubdirExported(subdir) {
+		restutils.SendHTTPError(ctx, w, http.StatusConflict, errors.ErrSubdirExportExists)
+		return
+	}
+
+	// The subdirectory is created through a mount of the volume, unless
+	// the root of the volume itself is being exported
+	createDir := subdir != "/"
+	if createDir && volinfo.State != volume.VolStarted {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrVolNotStarted)
+		return
+	}
+
+	if err := txn.Ctx.Set("oldvolinfo", volinfo); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	volinfo.SetSubdirExport(subdir, req.AuthAllow)
+
+	if err := txn.Ctx.Set("volinfo", volinfo); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if err := txn.Ctx.Set("subdir", subdir); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	allNodes, err := peer.GetPeerIDs()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc: "vol-subdir.CreateDir",
+			Nodes:  []uuid.UUID{gdctx.MyUUID},
+			Skip:   !createDir,
+		},
+	}
+	txn.Steps = append(txn.Steps, subdirExportSteps(volinfo, allNodes)...)
+
+	if err := txn.Do(); err != nil {
+		logger.WithError(err).WithField("subdir", subdir).Error("subdir export transaction failed")
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	resp := api.SubdirExport{
+		Path:      subdir,
+		AuthAllow: req.AuthAllow,
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusCreated, &resp)
+}
+
+func volumeSubdirExportListHandler(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	volname := mux.Vars(r)["volname"]
+
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	exports := volinfo.SubdirExports()
+	resp := make(api.SubdirExportListResp, 0, len(exports))
+	for subdir, allow := range exports {
+		resp = append(resp, api.SubdirExport{
+			Path:      subdir,
+			AuthAllow: allow,
+		})
+	}
+	sort.Slice(resp, func(i, j int) bool {
+		return resp[i].Path < resp[j].Path
+	})
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+func volumeSubdirExportDeleteHandler(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+	volname := mux.Vars(r)["volname"]
+	subdir := path.Clean("/" + mux.Vars(r)["subdir"])
+
+	txn, err := transaction.NewTxnWithLocks(ctx, volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	defer txn.Done()
+
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	if !volinfo.IsSubdirExported(subdir) {
+		restutils.SendHTTPError(ctx, w, http.StatusNotFound, errors.ErrSubdirNotExported)
+		return
+	}
+
+	if err := txn.Ctx.Set("oldvolinfo", volinfo); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	// The subdirectory and its contents are left in place on the volume
+	volinfo.RemoveSubdirExport(subdir)
+
+	if err := txn.Ctx.Set("volinfo", volinfo); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	allNodes, err := peer.GetPeerIDs()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	txn.Steps = subdirExportSteps(volinfo, allNodes)
+
+	if err := txn.Do(); err != nil {
+		logger.WithError(err).WithField("subdir", subdir).Error("subdir unexport transaction failed")
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusNoContent, nil)
+}
+
+// subdirExportSteps returns the steps storing the updated exports of the
+// volume and regenerating the brick volfiles with the new auth.allow
+func subdirExportSteps(volinfo *volume.Volinfo, allNodes []uuid.UUID) []*transaction.Step {
+	return []*transaction.Step{
+		{
+			DoFunc:   "vol-option.UpdateVolinfo",
+			UndoFunc: "vol-option.UpdateVolinfo.Undo",
+			Nodes:    []uuid.UUID{gdctx.MyUUID},
+			Sync:     true,
+		},
+		{
+			DoFunc:   "vol-option.GenerateBrickVolfiles",
+			UndoFunc: "vol-option.GenerateBrickvolfiles.Undo",
+			Nodes:    volinfo.Nodes(),
+		},
+		{
+			DoFunc: "vol-option.NotifyVolfileChange",
+			Nodes:  allNodes,
+		},
+	}
+}
@@ -0,0 +1,209 @@
+package volumecommands
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/datatls"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/gorilla/mux"
+)
+
+// checkTLSCerts has the peers of the bricks of the volume check their
+// certificates, returning their states. It fails if any peer is down or has
+// no valid certificate.
+func checkTLSCerts(ctx context.Context, volinfo *volume.Volinfo) ([]api.TLSNodeStatus, int, error) {
+	txn := transaction.NewTxn(ctx)
+	defer txn.Done()
+	txn.Steps = []*transaction.Step{datatls.CheckStep(volinfo.Nodes())}
+	txn.DisableRollback = true
+
+	if err := txn.Do(); err != nil {
+		return datatls.NodeStatuses(txn.Ctx, volinfo.Nodes()), http.StatusBadRequest, err
+	}
+	return datatls.NodeStatuses(txn.Ctx, volinfo.Nodes()), http.StatusOK, nil
+}
+
+// reloadTLSBricks restarts the bricks of the started volume, for them to
+// load the certificates and TLS options. With TLS enabled, the certificates
+// are checked again before.
+func reloadTLSBricks(ctx context.Context, volname string) (int, error) {
+	txn, err := transaction.NewTxnWithLocks(ctx, volname)
+	if err != nil {
+		return restutils.ErrToStatusCode(err)
+	}
+	defer txn.Done()
+
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		return restutils.ErrToStatusCode(err)
+	}
+	if volinfo.State != volume.VolStarted {
+		return http.StatusOK, nil
+	}
+
+	reload, err := datatls.ReloadStep(txn.Ctx, volname, volinfo.Nodes())
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	check := datatls.CheckStep(volinfo.Nodes())
+	check.Skip = !datatls.IsEnabled(volinfo)
+	txn.Steps = []*transaction.Step{check, reload}
+	if err := txn.Do(); err != nil {
+		return http.StatusInternalServerError, err
+	}
+	return http.StatusOK, nil
+}
+
+func volumeTLSResp(volinfo *volume.Volinfo, nodes []api.TLSNodeStatus) *api.VolumeTLSResp {
+	return &api.VolumeTLSResp{
+		Enabled: datatls.IsEnabled(volinfo),
+		Allow:   volinfo.Options[datatls.SSLAllowKey],
+		Nodes:   nodes,
+	}
+}
+
+func volumeTLSHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+	volname := mux.Vars(r)["volname"]
+
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	txn := transaction.NewTxn(ctx)
+	defer txn.Done()
+	txn.Steps = []*transaction.Step{datatls.StatusStep(volinfo.Nodes())}
+
+	// Some nodes may not be up, which is okay.
+	txn.DontCheckAlive = true
+	txn.DisableRollback = true
+
+	if err := txn.Do(); err != nil {
+		logger.WithError(err).WithField("volume", volname).Error("failed to get the TLS certificates of the peers")
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	resp := volumeTLSResp(volinfo, datatls.NodeStatuses(txn.Ctx, volinfo.Nodes()))
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+// setVolumeTLS sets the TLS options of the volume, the TLS options being
+// advanced options, and restarts its bricks to apply them
+func setVolumeTLS(ctx context.Context, volname string, opts map[string]string) (*volume.Volinfo, int, error) {
+	req := api.VolOptionReq{
+		Options: opts,
+		VolOptionFlags: api.VolOptionFlags{
+			AllowAdvanced: true,
+		},
+	}
+	volinfo, status, err := setVolumeOptions(ctx, volname, req)
+	if err != nil {
+		return nil, status, err
+	}
+
+	// The bricks do not switch the transport of their connections on
+	// reconfiguring
+	if status, err := reloadTLSBricks(ctx, volname); err != nil {
+		return nil, status, err
+	}
+	return volinfo, http.StatusOK, nil
+}
+
+func volumeTLSEnableHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+	volname := mux.Vars(r)["volname"]
+
+	var req api.VolumeTLSReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil && err != io.EOF {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrJSONParsingFailed)
+		return
+	}
+
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	// The certificates must be valid on all the peers of the bricks, or
+	// the bricks would no longer accept connections
+	nodes, status, err := checkTLSCerts(ctx, volinfo)
+	if err != nil {
+		logger.WithError(err).WithField("volume", volname).Error("TLS certificates check failed")
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	volinfo, status, err = setVolumeTLS(ctx, volname, datatls.Options(true, req.Allow))
+	if err != nil {
+		logger.WithError(err).WithField("volume", volname).Error("failed to enable TLS")
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, volumeTLSResp(volinfo, nodes))
+}
+
+func volumeTLSDisableHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+	volname := mux.Vars(r)["volname"]
+
+	volinfo, status, err := setVolumeTLS(ctx, volname, datatls.Options(false, nil))
+	if err != nil {
+		logger.WithError(err).WithField("volume", volname).Error("failed to disable TLS")
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, volumeTLSResp(volinfo, nil))
+}
+
+func volumeTLSRotateHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+	volname := mux.Vars(r)["volname"]
+
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	if !datatls.IsEnabled(volinfo) {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrTLSNotEnabled)
+		return
+	}
+
+	// The new certificates are checked on all the peers before any brick
+	// is restarted
+	nodes, status, err := checkTLSCerts(ctx, volinfo)
+	if err != nil {
+		logger.WithError(err).WithField("volume", volname).Error("TLS certificates check failed")
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	if status, err := reloadTLSBricks(ctx, volname); err != nil {
+		logger.WithError(err).WithField("volume", volname).Error("failed to reload the bricks")
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, volumeTLSResp(volinfo, nodes))
+}
@@ -0,0 +1,77 @@
+package volumecommands
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/glusterd2/volumetrash"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/gorilla/mux"
+	"github.com/pborman/uuid"
+)
+
+func volumeTrashListHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	trashed, err := volume.GetTrashedVolumes()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	resp := make(api.VolumeTrashListResp, 0, len(trashed))
+	for _, t := range trashed {
+		resp = append(resp, api.TrashedVolume{
+			Volume:    *volume.CreateVolumeInfoResp(t.Volinfo),
+			DeletedAt: t.DeletedAt,
+			PurgeAt:   t.PurgeAt,
+			BrickData: t.BrickData,
+		})
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+func volumeUndeleteHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+
+	volID := uuid.Parse(mux.Vars(r)["volid"])
+	if volID == nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "invalid volume id")
+		return
+	}
+
+	volinfo, err := volumetrash.Undelete(ctx, volID)
+	if err != nil {
+		logger.WithError(err).WithField("id", volID.String()).Error("failed to undelete volume")
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	resp := (*api.VolumeUndeleteResp)(volume.CreateVolumeInfoResp(volinfo))
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+func volumePurgeHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+
+	volID := uuid.Parse(mux.Vars(r)["volid"])
+	if volID == nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "invalid volume id")
+		return
+	}
+
+	if err := volumetrash.Purge(ctx, volID); err != nil {
+		logger.WithError(err).WithField("id", volID.String()).Error("failed to purge volume")
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusNoContent, nil)
+}
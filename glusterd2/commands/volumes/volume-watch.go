@@ -0,0 +1,131 @@
+package volumecommands
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	config "github.com/spf13/viper"
+	"go.opencensus.io/trace"
+)
+
+const (
+	// defaultWatchTimeout is the time a watch waits for the volumes to
+	// change when no timeout is given
+	defaultWatchTimeout = 20 * time.Second
+
+	// watchTimeoutMargin is the time kept out of rest-write-timeout to
+	// send the response of a watch
+	watchTimeoutMargin = 5 * time.Second
+)
+
+// watchTimeout returns the time the watch request may wait for the volumes to
+// change, at most the time left by rest-write-timeout to send the response
+func watchTimeout(r *http.Request) (time.Duration, error) {
+	timeout := defaultWatchTimeout
+	if v := r.URL.Query().Get("timeout"); v != "" {
+		secs, err := strconv.Atoi(v)
+		if err != nil || secs < 0 {
+			return 0, fmt.Errorf("invalid timeout: %s", v)
+		}
+		timeout = time.Duration(secs) * time.Second
+	}
+
+	if max := config.GetDuration("rest-write-timeout") - watchTimeoutMargin; max > 0 && timeout > max {
+		timeout = max
+	}
+	return timeout, nil
+}
+
+// volumeWatchHandler answers the volume list requests with watch=true. Without
+// since, it lists all the volumes along with the revision of the store they
+// are at. With since, it waits for the volumes to change after the revision,
+// and returns the volumes changed and the names of the volumes deleted.
+func volumeWatchHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	ctx, span := trace.StartSpan(ctx, "/volumeWatchHandler")
+	defer span.End()
+
+	query := r.URL.Query()
+	for _, param := range []string{"key", "value", "selector"} {
+		if _, ok := query[param]; ok {
+			restutils.SendHTTPError(ctx, w, http.StatusBadRequest,
+				fmt.Errorf("%s can not be used with watch", param))
+			return
+		}
+	}
+
+	// Requests scoped to a namespace only see its volumes, the others can
+	// ask for the volumes of a namespace
+	ns := gdctx.GetReqNamespace(ctx)
+	if ns == "" {
+		ns = query.Get("namespace")
+	}
+
+	var since int64
+	if v := query.Get("since"); v != "" {
+		var err error
+		if since, err = strconv.ParseInt(v, 10, 64); err != nil || since < 0 {
+			restutils.SendHTTPError(ctx, w, http.StatusBadRequest, fmt.Errorf("invalid since: %s", v))
+			return
+		}
+	}
+
+	if since == 0 {
+		volumes, rev, err := volume.GetVolumesRevision(ctx)
+		if err != nil {
+			restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+			return
+		}
+		if ns != "" {
+			volumes = namespaceVolumes(volumes, ns)
+		}
+		restutils.SendHTTPResponse(ctx, w, http.StatusOK, createVolumeWatchResp(volumes, nil, rev))
+		return
+	}
+
+	timeout, err := watchTimeout(r)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+	wctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	changed, deleted, rev, err := volume.WatchVolumes(wctx, since)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	if ns != "" {
+		changed = namespaceVolumes(changed, ns)
+		deleted = namespaceVolumes(deleted, ns)
+	}
+
+	span.AddAttributes(
+		trace.StringAttribute("numVols", strconv.Itoa(len(changed)+len(deleted))),
+	)
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, createVolumeWatchResp(changed, deleted, rev))
+}
+
+func createVolumeWatchResp(changed, deleted []*volume.Volinfo, rev int64) *api.VolumeWatchResp {
+	resp := &api.VolumeWatchResp{
+		Revision: rev,
+		Volumes:  make([]api.VolumeGetResp, len(changed)),
+	}
+	for i, v := range changed {
+		resp.Volumes[i] = *(createVolumeGetResp(v))
+	}
+	for _, v := range deleted {
+		resp.Deleted = append(resp.Deleted, v.Name)
+	}
+	return resp
+}
@@ -0,0 +1,52 @@
+// Package xlatorcommands implements the commands to register the custom
+// xlators, which are not shipped with glusterfs, to be inserted into graphs
+package xlatorcommands
+
+import (
+	"github.com/gluster/glusterd2/glusterd2/servers/rest/route"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/utils"
+)
+
+// Command is a holding struct used to implement the GlusterD Command interface
+type Command struct {
+}
+
+// Routes returns command routes. Required for the Command interface.
+func (c *Command) Routes() route.Routes {
+	return route.Routes{
+		route.Route{
+			Name:         "CustomXlatorList",
+			Method:       "GET",
+			Pattern:      "/custom-xlators",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.CustomXlatorListResp)(nil)),
+			HandlerFunc:  customXlatorListHandler},
+		route.Route{
+			Name:         "CustomXlatorGet",
+			Method:       "GET",
+			Pattern:      "/custom-xlators/{name}",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.CustomXlatorResp)(nil)),
+			HandlerFunc:  customXlatorGetHandler},
+		route.Route{
+			Name:         "CustomXlatorSet",
+			Method:       "PUT",
+			Pattern:      "/custom-xlators/{name}",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.CustomXlatorReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.CustomXlatorResp)(nil)),
+			HandlerFunc:  customXlatorSetHandler},
+		route.Route{
+			Name:        "CustomXlatorDelete",
+			Method:      "DELETE",
+			Pattern:     "/custom-xlators/{name}",
+			Version:     1,
+			HandlerFunc: customXlatorDeleteHandler},
+	}
+}
+
+// RegisterStepFuncs implements a required function for the Command interface
+func (c *Command) RegisterStepFuncs() {
+	return
+}
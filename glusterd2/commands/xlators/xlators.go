@@ -0,0 +1,197 @@
+package xlatorcommands
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/options"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/volgen"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/gorilla/mux"
+)
+
+const (
+	// custom xlators are locked by their ID prefixed with this
+	lockKeyPrefix = "custom-xlator."
+)
+
+func createCustomXlatorResp(cx *volgen.CustomXlator) api.CustomXlatorResp {
+	resp := api.CustomXlatorResp{
+		Type:      cx.Type,
+		Templates: cx.Templates,
+		After:     cx.After,
+		Before:    cx.Before,
+		Requires:  cx.Requires,
+		Plugin:    cx.Plugin,
+	}
+	for _, o := range cx.Options {
+		resp.Options = append(resp.Options, api.CustomXlatorOption(o))
+	}
+	return resp
+}
+
+func customXlatorFromReq(req *api.CustomXlatorReq) *volgen.CustomXlator {
+	cx := &volgen.CustomXlator{
+		Type:      req.Type,
+		Templates: req.Templates,
+		After:     req.After,
+		Before:    req.Before,
+		Requires:  req.Requires,
+	}
+	for _, o := range req.Options {
+		cx.Options = append(cx.Options, volgen.CustomXlatorOption(o))
+	}
+	return cx
+}
+
+// enabledInVolumes returns the names of the volumes enabling the custom
+// xlator
+func enabledInVolumes(r *http.Request, id string) ([]string, error) {
+	volumes, err := volume.GetVolumes(r.Context())
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, v := range volumes {
+		for k, val := range v.Options {
+			if k != id+"."+id && !strings.HasSuffix(k, "/"+id+"."+id) {
+				continue
+			}
+			if on, err := options.StringToBoolean(val); err == nil && on {
+				names = append(names, v.Name)
+				break
+			}
+		}
+	}
+	return names, nil
+}
+
+func customXlatorListHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	resp := make(api.CustomXlatorListResp, 0)
+	for _, cx := range volgen.CustomXlators() {
+		resp = append(resp, api.CustomXlator(createCustomXlatorResp(&cx)))
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+func customXlatorGetHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	name := mux.Vars(r)["name"]
+
+	cx, err := volgen.GetCustomXlator(name)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, createCustomXlatorResp(cx))
+}
+
+func customXlatorSetHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+	name := mux.Vars(r)["name"]
+
+	var req api.CustomXlatorReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
+		return
+	}
+
+	cx := customXlatorFromReq(&req)
+	if cx.ID() != name {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest,
+			fmt.Sprintf("type of the xlator %s does not match %s", cx.Type, name))
+		return
+	}
+
+	txn, err := transaction.NewTxnWithLocks(ctx, lockKeyPrefix+name)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	defer txn.Done()
+
+	if existing, err := volgen.GetCustomXlator(name); err == nil && existing.Plugin != "" {
+		restutils.SendHTTPError(ctx, w, http.StatusConflict,
+			fmt.Sprintf("xlator %s is registered by plugin %s", name, existing.Plugin))
+		return
+	}
+	if err := volgen.ValidateCustomXlator(cx); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := volgen.SaveCustomXlator(cx); err != nil {
+		logger.WithError(err).WithField("xlator", cx.Type).Error("failed to save custom xlator")
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	// The other peers reload the custom xlators on watching the store,
+	// reload here too so that the xlator is usable once this returns
+	if err := volgen.LoadCustomXlators(); err != nil {
+		logger.WithError(err).Error("failed to reload custom xlators")
+	}
+	logger.WithField("xlator", cx.Type).Info("registered custom xlator")
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, createCustomXlatorResp(cx))
+}
+
+func customXlatorDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+	name := mux.Vars(r)["name"]
+
+	txn, err := transaction.NewTxnWithLocks(ctx, lockKeyPrefix+name)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	defer txn.Done()
+
+	cx, err := volgen.GetCustomXlator(name)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	if cx.Plugin != "" {
+		restutils.SendHTTPError(ctx, w, http.StatusConflict,
+			fmt.Sprintf("xlator %s is registered by plugin %s", name, cx.Plugin))
+		return
+	}
+
+	vols, err := enabledInVolumes(r, name)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	if len(vols) > 0 {
+		restutils.SendHTTPError(ctx, w, http.StatusConflict,
+			fmt.Sprintf("xlator %s is enabled in volumes %s", name, strings.Join(vols, ", ")))
+		return
+	}
+
+	if err := volgen.DeleteCustomXlator(name); err != nil {
+		logger.WithError(err).WithField("xlator", cx.Type).Error("failed to delete custom xlator")
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	if err := volgen.LoadCustomXlators(); err != nil {
+		logger.WithError(err).Error("failed to reload custom xlators")
+	}
+	logger.WithField("xlator", cx.Type).Info("deleted custom xlator")
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusNoContent, nil)
+}
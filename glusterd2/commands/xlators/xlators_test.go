@@ -0,0 +1,29 @@
+package xlatorcommands
+
+import (
+	"testing"
+
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCustomXlatorConversion(t *testing.T) {
+	req := api.CustomXlatorReq{
+		Type:      "features/myxl",
+		Templates: []string{"brick"},
+		After:     []string{"features/locks"},
+		Requires:  []string{"features/upcall"},
+		Options: []api.CustomXlatorOption{
+			{Key: "mode", Type: "str", ValidValues: []string{"fast", "safe"}},
+		},
+		Plugin: "ignored",
+	}
+
+	cx := customXlatorFromReq(&req)
+	assert.Equal(t, "myxl", cx.ID())
+	assert.Empty(t, cx.Plugin)
+
+	req.Plugin = ""
+	assert.Equal(t, api.CustomXlatorResp(req), createCustomXlatorResp(cx))
+}
@@ -7,8 +7,29 @@ import (
 	"path"
 	"strings"
 
+	"github.com/gluster/glusterd2/glusterd2/adaptivethrottle"
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/brickcrypt"
+	"github.com/gluster/glusterd2/glusterd2/ca"
+	"github.com/gluster/glusterd2/glusterd2/cgroups"
+	"github.com/gluster/glusterd2/glusterd2/daemon"
+	"github.com/gluster/glusterd2/glusterd2/drift"
+	"github.com/gluster/glusterd2/glusterd2/fault"
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/halo"
+	"github.com/gluster/glusterd2/glusterd2/heartbeat"
+	"github.com/gluster/glusterd2/glusterd2/logrotate"
+	"github.com/gluster/glusterd2/glusterd2/middleware"
+	"github.com/gluster/glusterd2/glusterd2/mounts"
+	"github.com/gluster/glusterd2/glusterd2/orphanbricks"
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	"github.com/gluster/glusterd2/glusterd2/servers/rest"
+	"github.com/gluster/glusterd2/glusterd2/servers/sunrpc"
+	"github.com/gluster/glusterd2/glusterd2/shutdown"
+	"github.com/gluster/glusterd2/glusterd2/simulate"
 	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/usagemonitor"
 	"github.com/gluster/glusterd2/pkg/logging"
 	"github.com/gluster/glusterd2/pkg/tracing"
 
@@ -47,13 +68,17 @@ func initFlags() {
 	flag.String(logging.DirFlag, defaultlogdir, logging.DirHelp)
 	flag.String(logging.FileFlag, defaultlogfile, logging.FileHelp)
 	flag.String(logging.LevelFlag, defaultloglevel, logging.LevelHelp)
-	flag.Bool("profiling", defaultprofiling, "Enable go profiling to collect profile data.")
+	flag.Bool("profiling", defaultprofiling, "Enable /debug endpoints for go profiling and diagnostics at startup. Can be toggled at runtime with PUT /debug.")
 
 	// TODO: Change default to false (disabled) in future.
 	flag.Bool("statedump", true, "Enable /statedump endpoint for metrics.")
+	flag.Bool("metrics", true, "Enable /metrics endpoint for Prometheus.")
+	flag.String("metrics-address", "", "Address to bind a separate unauthenticated listener for /metrics. Metrics are served by the REST service if not set.")
 
 	flag.String("clientaddress", defaultclientaddress, "Address to bind the REST service.")
 	flag.String("peeraddress", defaultpeeraddress, "Address to bind the inter glusterd2 RPC service.")
+	flag.Bool("systemd", false, "Use the socket passed by systemd for the REST and SunRPC services, and notify systemd of the state of glusterd2.")
+	flag.Bool("join-token-required", false, "Join other clusters only with a join token, rejecting the requests of their peers to join.")
 
 	// TODO: SSL/TLS is currently only implemented for REST interface
 	flag.String("cert-file", "", "Certificate used for SSL/TLS connections from clients to glusterd2.")
@@ -64,6 +89,27 @@ func initFlags() {
 
 	store.InitFlags()
 	tracing.InitFlags()
+	usagemonitor.InitFlags()
+	halo.InitFlags()
+	heartbeat.InitFlags()
+	brickcrypt.InitFlags()
+	ca.InitFlags()
+	logrotate.InitFlags()
+	orphanbricks.InitFlags()
+	drift.InitFlags()
+	mounts.InitFlags()
+	adaptivethrottle.InitFlags()
+	brick.InitFlags()
+	cgroups.InitFlags()
+	daemon.InitFlags()
+	transaction.InitFlags()
+	sunrpc.InitFlags()
+	rest.InitFlags()
+	shutdown.InitFlags()
+	peer.InitFlags()
+	simulate.InitFlags()
+	fault.InitFlags()
+	middleware.InitFlags()
 
 	flag.Parse()
 }
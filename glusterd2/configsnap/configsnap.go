@@ -0,0 +1,276 @@
+// Package configsnap takes snapshots of the configuration of the cluster
+// saved in the store, such as the volumes, the peers and the options, to
+// compare and restore it.
+package configsnap
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/coreos/etcd/clientv3"
+)
+
+// Triggers of the snapshots
+const (
+	TriggerScheduled = "scheduled"
+	TriggerManual    = "manual"
+)
+
+const (
+	// snapshotPrefix is where the snapshots are saved, under their ID
+	snapshotPrefix = "config-snapshots/"
+	// latestKey is where the ID of the latest snapshot is saved
+	latestKey = "config-snapshots-latest"
+
+	volumePrefix = "volumes/"
+
+	maxManualAttempts = 3
+)
+
+// prefixes are the store keys and prefixes of the configuration
+var prefixes = []string{
+	volumePrefix,
+	"peers/",
+	"clusteroptions",
+}
+
+// RegisterPrefix adds the store keys with the prefix to the configuration
+// saved in the snapshots. Plugins register the keys of their configuration.
+func RegisterPrefix(prefix string) {
+	prefixes = append(prefixes, prefix)
+}
+
+// Snapshot is a snapshot of the configuration of the cluster
+type Snapshot struct {
+	// ID is the revision of the store the configuration was read at,
+	// increasing with every snapshot
+	ID        int64     `json:"id"`
+	CreatedAt time.Time `json:"created-at"`
+	Trigger   string    `json:"trigger"`
+	Hash      string    `json:"hash"`
+	// Config are the values of the store keys of the configuration
+	Config map[string]string `json:"config"`
+}
+
+func snapshotKey(id int64) string {
+	// Zero padded for the keys to sort by ID
+	return fmt.Sprintf("%s%020d", snapshotPrefix, id)
+}
+
+// hash returns a hash of the configuration, telling if it changed
+func hash(config map[string]string) string {
+	keys := make([]string, 0, len(config))
+	for k := range config {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	h := sha256.New()
+	for _, k := range keys {
+		fmt.Fprintf(h, "%d:%s%d:%s", len(k), k, len(config[k]), config[k])
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Capture returns the current configuration, read at a single revision of
+// the store, and the revision
+func Capture() (map[string]string, int64, error) {
+	config := make(map[string]string)
+	var rev int64
+	for _, prefix := range prefixes {
+		opts := []clientv3.OpOption{clientv3.WithPrefix()}
+		if rev != 0 {
+			opts = append(opts, clientv3.WithRev(rev))
+		}
+		resp, err := store.Get(context.TODO(), prefix, opts...)
+		if err != nil {
+			return nil, 0, err
+		}
+		if rev == 0 {
+			rev = resp.Header.Revision
+		}
+		for _, kv := range resp.Kvs {
+			config[string(kv.Key)] = string(kv.Value)
+		}
+	}
+	return config, rev, nil
+}
+
+// latest returns the latest snapshot, nil if none, and the mod revision of
+// the key pointing at it
+func latest() (*Snapshot, int64, error) {
+	resp, err := store.Get(context.TODO(), latestKey)
+	if err != nil {
+		return nil, 0, err
+	}
+	if resp.Count != 1 {
+		return nil, 0, nil
+	}
+	id, err := strconv.ParseInt(string(resp.Kvs[0].Value), 10, 64)
+	if err != nil {
+		return nil, 0, err
+	}
+	s, err := Get(id)
+	if err == errors.ErrConfigSnapshotNotFound {
+		return nil, resp.Kvs[0].ModRevision, nil
+	}
+	return s, resp.Kvs[0].ModRevision, err
+}
+
+// Take takes a snapshot of the configuration. Unless taken manually, no
+// snapshot is taken if the configuration did not change since the latest
+// snapshot, or if another peer took one meanwhile, and nil is returned.
+func Take(trigger string) (*Snapshot, error) {
+	for i := 0; i < maxManualAttempts; i++ {
+		s, err := take(trigger)
+		if s != nil || err != nil || trigger != TriggerManual {
+			return s, err
+		}
+	}
+	return nil, errors.ErrConfigSnapshotConflict
+}
+
+func take(trigger string) (*Snapshot, error) {
+	prev, prevRev, err := latest()
+	if err != nil {
+		return nil, err
+	}
+
+	config, rev, err := Capture()
+	if err != nil {
+		return nil, err
+	}
+	s := &Snapshot{
+		ID:        rev,
+		CreatedAt: time.Now().UTC(),
+		Trigger:   trigger,
+		Hash:      hash(config),
+		Config:    config,
+	}
+	if trigger != TriggerManual && prev != nil && prev.Hash == s.Hash {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fails if another peer took a snapshot since the latest one was
+	// read, or if one was taken at the same revision
+	key := snapshotKey(s.ID)
+	resp, err := store.Txn(context.TODO()).
+		If(
+			clientv3.Compare(clientv3.ModRevision(latestKey), "=", prevRev),
+			clientv3.Compare(clientv3.CreateRevision(key), "=", 0),
+		).
+		Then(
+			clientv3.OpPut(key, string(data)),
+			clientv3.OpPut(latestKey, strconv.FormatInt(s.ID, 10)),
+		).
+		Commit()
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Succeeded {
+		return nil, nil
+	}
+	return s, nil
+}
+
+// Get returns the snapshot with the ID
+func Get(id int64) (*Snapshot, error) {
+	resp, err := store.Get(context.TODO(), snapshotKey(id))
+	if err != nil {
+		return nil, err
+	}
+	if resp.Count != 1 {
+		return nil, errors.ErrConfigSnapshotNotFound
+	}
+	var s Snapshot
+	if err := json.Unmarshal(resp.Kvs[0].Value, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// List returns all the snapshots, oldest first
+func List() ([]*Snapshot, error) {
+	resp, err := store.Get(context.TODO(), snapshotPrefix, clientv3.WithPrefix(),
+		clientv3.WithSort(clientv3.SortByKey, clientv3.SortAscend))
+	if err != nil {
+		return nil, err
+	}
+	snapshots := make([]*Snapshot, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var s Snapshot
+		if err := json.Unmarshal(kv.Value, &s); err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, &s)
+	}
+	return snapshots, nil
+}
+
+// Delete deletes the snapshot with the ID
+func Delete(id int64) error {
+	resp, err := store.Delete(context.TODO(), snapshotKey(id))
+	if err != nil {
+		return err
+	}
+	if resp.Deleted == 0 {
+		return errors.ErrConfigSnapshotNotFound
+	}
+	return nil
+}
+
+// prune deletes the oldest snapshots, keeping the given number
+func prune(keep int) error {
+	resp, err := store.Get(context.TODO(), snapshotPrefix, clientv3.WithPrefix(), clientv3.WithKeysOnly(),
+		clientv3.WithSort(clientv3.SortByKey, clientv3.SortAscend))
+	if err != nil {
+		return err
+	}
+	for i := 0; i < len(resp.Kvs)-keep; i++ {
+		if _, err := store.Delete(context.TODO(), string(resp.Kvs[i].Key)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Volumes returns the names of the volumes in the snapshot
+func (s *Snapshot) Volumes() []string {
+	volumes := []string{}
+	for key := range s.Config {
+		if strings.HasPrefix(key, volumePrefix) {
+			volumes = append(volumes, strings.TrimPrefix(key, volumePrefix))
+		}
+	}
+	sort.Strings(volumes)
+	return volumes
+}
+
+// Volume returns the volume in the snapshot
+func (s *Snapshot) Volume(volname string) (*volume.Volinfo, error) {
+	data, ok := s.Config[volumePrefix+volname]
+	if !ok {
+		return nil, errors.ErrVolNotFound
+	}
+	var v volume.Volinfo
+	if err := json.Unmarshal([]byte(data), &v); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
@@ -0,0 +1,49 @@
+package configsnap
+
+import (
+	"testing"
+
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHash(t *testing.T) {
+	a := map[string]string{"volumes/a": `{"name":"a"}`, "peers/1": `{}`}
+	b := map[string]string{"peers/1": `{}`, "volumes/a": `{"name":"a"}`}
+	assert.Equal(t, hash(a), hash(b))
+
+	b["volumes/a"] = `{"name":"b"}`
+	assert.NotEqual(t, hash(a), hash(b))
+
+	// Keys and values must not be confused
+	assert.NotEqual(t, hash(map[string]string{"ab": "c"}), hash(map[string]string{"a": "bc"}))
+}
+
+func TestDiff(t *testing.T) {
+	from := map[string]string{
+		"volumes/testvol": `{"name":"testvol","Options":{"a":"on","b":"off"},"Subvols":[{"Type":0}]}`,
+		"clusteroptions":  `not json`,
+	}
+	to := map[string]string{
+		"volumes/testvol": `{"name":"testvol","Options":{"a":"off","c":"on"},"Subvols":[{"Type":1}]}`,
+		"volumes/new":     `{"name":"new"}`,
+		"clusteroptions":  `not json`,
+	}
+
+	assert.Equal(t, []api.ConfigChange{
+		{Path: "volumes/new/name", Change: ChangeAdded, New: "new"},
+		{Path: "volumes/testvol/Options/a", Change: ChangeModified, Old: "on", New: "off"},
+		{Path: "volumes/testvol/Options/b", Change: ChangeRemoved, Old: "off"},
+		{Path: "volumes/testvol/Options/c", Change: ChangeAdded, New: "on"},
+		{Path: "volumes/testvol/Subvols/0/Type", Change: ChangeModified, Old: "0", New: "1"},
+	}, Diff(from, to, ""))
+
+	assert.Equal(t, []api.ConfigChange{
+		{Path: "volumes/testvol/Options/a", Change: ChangeModified, Old: "on", New: "off"},
+		{Path: "volumes/testvol/Options/b", Change: ChangeRemoved, Old: "off"},
+		{Path: "volumes/testvol/Options/c", Change: ChangeAdded, New: "on"},
+	}, Diff(from, to, "volumes/testvol/Options"))
+
+	assert.Empty(t, Diff(from, from, ""))
+}
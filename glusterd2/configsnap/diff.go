@@ -0,0 +1,85 @@
+package configsnap
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gluster/glusterd2/pkg/api"
+)
+
+// Changes in the diff of two configurations
+const (
+	ChangeAdded    = "added"
+	ChangeRemoved  = "removed"
+	ChangeModified = "modified"
+)
+
+// flatten adds the leaves of the JSON value to the map, under their path
+// from the root. Values which are not JSON are added as is.
+func flatten(path string, value interface{}, leaves map[string]string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for k, child := range v {
+			flatten(path+"/"+k, child, leaves)
+		}
+	case []interface{}:
+		for i, child := range v {
+			flatten(path+"/"+strconv.Itoa(i), child, leaves)
+		}
+	case string:
+		leaves[path] = v
+	case nil:
+		leaves[path] = "null"
+	default:
+		leaves[path] = fmt.Sprint(v)
+	}
+}
+
+// leaves returns the configuration as paths to the values of the JSON
+// documents saved in the store keys
+func leaves(config map[string]string) map[string]string {
+	l := make(map[string]string)
+	for key, data := range config {
+		var value interface{}
+		if err := json.Unmarshal([]byte(data), &value); err != nil {
+			l[key] = data
+			continue
+		}
+		flatten(key, value, l)
+	}
+	return l
+}
+
+// Diff returns the changes from one configuration to the other, sorted by
+// path. Only the paths with the prefix are compared, if given.
+func Diff(from, to map[string]string, prefix string) []api.ConfigChange {
+	a, b := leaves(from), leaves(to)
+
+	changes := []api.ConfigChange{}
+	for path, old := range a {
+		if !strings.HasPrefix(path, prefix) {
+			continue
+		}
+		if cur, ok := b[path]; !ok {
+			changes = append(changes, api.ConfigChange{Path: path, Change: ChangeRemoved, Old: old})
+		} else if cur != old {
+			changes = append(changes, api.ConfigChange{Path: path, Change: ChangeModified, Old: old, New: cur})
+		}
+	}
+	for path, cur := range b {
+		if !strings.HasPrefix(path, prefix) {
+			continue
+		}
+		if _, ok := a[path]; !ok {
+			changes = append(changes, api.ConfigChange{Path: path, Change: ChangeAdded, New: cur})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return changes
+}
@@ -0,0 +1,122 @@
+package configsnap
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/leader"
+	"github.com/gluster/glusterd2/glusterd2/options"
+	"github.com/gluster/glusterd2/glusterd2/transactionv2"
+	"github.com/gluster/glusterd2/pkg/errors"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	intervalKey = "cluster.config-snapshot-interval"
+	keepKey     = "cluster.config-snapshot-keep"
+
+	// checkInterval is the interval at which the peer leading the
+	// LeaderService checks if a scheduled snapshot is due
+	checkInterval = time.Minute
+
+	// LeaderService is the singleton service taking the scheduled
+	// snapshots
+	LeaderService = "config-snapshot"
+)
+
+var (
+	stopChan chan struct{}
+	stopOnce sync.Once
+
+	// lastAttempt is when this peer last attempted a scheduled snapshot,
+	// which is not taken if the configuration did not change
+	lastAttempt time.Time
+)
+
+func validateNonNegative(option, value string) error {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return errors.ErrInvalidIntValue
+	}
+	return nil
+}
+
+func init() {
+	options.RegisterClusterOpValidationFunc(intervalKey, validateNonNegative)
+	options.RegisterClusterOpValidationFunc(keepKey, validateNonNegative)
+	leader.Register(LeaderService)
+}
+
+// clusterInt returns the value of the integer cluster option, or its
+// default if not valid
+func clusterInt(key string, def int) int {
+	value, err := options.GetClusterOption(key)
+	if err != nil {
+		return def
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return def
+	}
+	return n
+}
+
+// Start starts taking the scheduled snapshots
+func Start() {
+	stopChan = make(chan struct{})
+	go transaction.UntilStop(check, checkInterval, stopChan)
+}
+
+// Stop stops taking the scheduled snapshots
+func Stop() {
+	if stopChan == nil {
+		return
+	}
+	stopOnce.Do(func() {
+		close(stopChan)
+	})
+}
+
+// check takes a snapshot if the latest one is older than the interval, and
+// deletes the oldest ones beyond the number to keep
+func check() {
+	if !leader.IsLeader(LeaderService) {
+		return
+	}
+
+	interval := time.Duration(clusterInt(intervalKey, 60)) * time.Minute
+	if interval == 0 {
+		return
+	}
+
+	prev, _, err := latest()
+	if err != nil {
+		log.WithError(err).Error("failed to get the latest configuration snapshot")
+		return
+	}
+	if prev != nil && time.Since(prev.CreatedAt) < interval {
+		return
+	}
+	if time.Since(lastAttempt) < interval {
+		return
+	}
+	lastAttempt = time.Now()
+
+	s, err := Take(TriggerScheduled)
+	if err != nil {
+		log.WithError(err).Error("failed to take configuration snapshot")
+		return
+	}
+	if s == nil {
+		return
+	}
+	log.WithField("id", s.ID).Info("took configuration snapshot")
+
+	if keep := clusterInt(keepKey, 48); keep > 0 {
+		if err := prune(keep); err != nil {
+			log.WithError(err).Error("failed to delete old configuration snapshots")
+		}
+	}
+}
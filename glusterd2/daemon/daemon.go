@@ -6,6 +6,7 @@ import (
 	"strings"
 	"syscall"
 
+	"github.com/gluster/glusterd2/glusterd2/cgroups"
 	"github.com/gluster/glusterd2/glusterd2/events"
 	"github.com/gluster/glusterd2/pkg/errors"
 
@@ -71,7 +72,16 @@ func Start(d Daemon, wait bool, logger log.FieldLogger) error {
 		}
 	}
 
+	// Done once the daemon is saved in the store, or failed to start
+	defer recordStart(d, logger)()
+
 	cmd := exec.Command(d.Path(), d.Args()...)
+	cmd.SysProcAttr, err = sysProcAttr(d)
+	if err != nil {
+		logger.WithError(err).WithField("name", d.Name()).Error("could not find the user to run the daemon as")
+		events.Broadcast(newEvent(d, daemonStartFailed, 0))
+		return err
+	}
 	err = cmd.Start()
 	if err != nil {
 		events.Broadcast(newEvent(d, daemonStartFailed, 0))
@@ -106,9 +116,12 @@ func Start(d Daemon, wait bool, logger log.FieldLogger) error {
 			"name": d.Name(),
 			"pid":  pid,
 		}).Debug("Started daemon successfully")
+		placeInCgroup(d, pid, logger)
 		events.Broadcast(newEvent(d, daemonStarted, pid))
 
 	} else {
+		placeInCgroup(d, cmd.Process.Pid, logger)
+
 		// If the process exits at some point later, do read it's
 		// exit status. This should not let it be a zombie.
 		go func() {
@@ -165,6 +178,9 @@ func Stop(d Daemon, force bool, logger log.FieldLogger) error {
 		"pid":  pid,
 	}).Debug("Stopping daemon.")
 	events.Broadcast(newEvent(d, daemonStopping, pid))
+	setState(d.ID(), StateStopping)
+	defer recordStop(d, force, logger)()
+	defer forgetState(d.ID())
 
 	err = Kill(pid, force)
 
@@ -188,9 +204,26 @@ func Stop(d Daemon, force bool, logger log.FieldLogger) error {
 		}).Warn("failed to delete daemon from store, it may be restarted on GlusterD restart")
 	}
 
+	// The cgroup of a daemon still exiting is removed when the next daemon
+	// of its kind is started
+	if err := cgroups.Remove(d.Name(), d.ID()); err != nil {
+		logger.WithError(err).WithField("name", d.Name()).Debug("failed to remove the cgroup of the daemon")
+	}
+
 	return nil
 }
 
+// placeInCgroup runs the daemon in its cgroup, for its resources to be
+// limited. The daemon is left running unlimited if this fails.
+func placeInCgroup(d Daemon, pid int, logger log.FieldLogger) {
+	if err := cgroups.Place(d.Name(), d.ID(), pid); err != nil {
+		logger.WithError(err).WithFields(log.Fields{
+			"name": d.Name(),
+			"pid":  pid,
+		}).Warn("failed to place the daemon in its cgroup, its resources are not limited")
+	}
+}
+
 // StartAllDaemons starts all previously running daemons when GlusterD restarts
 func StartAllDaemons() {
 	log.Debug("starting all daemons")
@@ -203,7 +236,7 @@ func StartAllDaemons() {
 		return
 	}
 
-	for _, d := range ds {
+	for _, d := range order(ds) {
 		if err := Start(d, true, log.StandardLogger()); err != nil {
 			log.WithError(err).WithField("name", d.Name()).Warn("failed to start daemon")
 		}
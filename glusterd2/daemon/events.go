@@ -20,6 +20,8 @@ const (
 	daemonStartingAll                = "daemon.startingall"
 	daemonStartedAll                 = "daemon.startedall"
 	daemonStartAllFailed             = "daemon.startallfailed"
+	daemonRestarted                  = "daemon.restarted"
+	daemonRestartLimit               = "daemon.restartlimitreached"
 )
 
 // newEvent returns an event of given type with daemon data filled
@@ -0,0 +1,83 @@
+package daemon
+
+import (
+	"encoding/json"
+
+	"github.com/gluster/glusterd2/glusterd2/journal"
+	"github.com/gluster/glusterd2/pkg/errors"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	journalStart = "daemon.start"
+	journalStop  = "daemon.stop"
+)
+
+// stopAction is the stop of a daemon recorded in the journal
+type stopAction struct {
+	Daemon *storedDaemon `json:"daemon"`
+	Force  bool          `json:"force"`
+}
+
+// recordStart records the start of the daemon in the journal
+func recordStart(d Daemon, logger log.FieldLogger) func() {
+	done, err := journal.Record(journalStart, d.ID(), newStoredDaemon(d))
+	if err != nil {
+		logger.WithError(err).WithField("name", d.Name()).Warn("failed to record the start of the daemon in the journal")
+	}
+	return done
+}
+
+// recordStop records the stop of the daemon in the journal
+func recordStop(d Daemon, force bool, logger log.FieldLogger) func() {
+	done, err := journal.Record(journalStop, d.ID(), stopAction{Daemon: newStoredDaemon(d), Force: force})
+	if err != nil {
+		logger.WithError(err).WithField("name", d.Name()).Warn("failed to record the stop of the daemon in the journal")
+	}
+	return done
+}
+
+// verifyStart verifies a start of a daemon interrupted by a crash. A daemon
+// found running is saved in the store, as it may not have been, for it to be
+// supervised. A daemon not running was not started, and is started again
+// only if it was saved in the store before.
+func verifyStart(e journal.Entry) error {
+	sd, err := unmarshalStoredDaemon(e.Data)
+	if err != nil {
+		return err
+	}
+
+	pid, err := ReadPidFromFile(sd.PidFile())
+	if err != nil {
+		return nil
+	}
+	if _, err := GetProcess(pid); err != nil {
+		return nil
+	}
+	log.WithFields(log.Fields{
+		"name": sd.Name(),
+		"pid":  pid,
+	}).Info("daemon found running, saving it in the store")
+	return saveDaemon(sd)
+}
+
+// verifyStop verifies a stop of a daemon interrupted by a crash, by stopping
+// the daemon again and removing it from the store
+func verifyStop(e journal.Entry) error {
+	var a stopAction
+	if err := json.Unmarshal(e.Data, &a); err != nil {
+		return err
+	}
+
+	err := Stop(a.Daemon, a.Force, log.StandardLogger())
+	if err == errors.ErrPidFileNotFound {
+		return DelDaemon(a.Daemon)
+	}
+	return err
+}
+
+func init() {
+	journal.Register(journalStart, verifyStart)
+	journal.Register(journalStop, verifyStop)
+}
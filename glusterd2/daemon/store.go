@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"path"
+	"strings"
 
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
 	"github.com/gluster/glusterd2/glusterd2/store"
@@ -81,3 +82,29 @@ func unmarshalStoredDaemon(data []byte) (*storedDaemon, error) {
 	}
 	return &sd, nil
 }
+
+// List returns the daemons managed by this node
+func List() ([]Daemon, error) {
+	return getDaemons()
+}
+
+// ListAll returns the daemons managed by all the nodes, by node ID
+func ListAll() (map[string][]Daemon, error) {
+	resp, err := store.Get(context.TODO(), daemonsPrefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	daemons := make(map[string][]Daemon)
+	for _, kv := range resp.Kvs {
+		d, err := unmarshalStoredDaemon(kv.Value)
+		if err != nil {
+			return nil, err
+		}
+		// the IDs of the daemons, such as the paths of the bricks, may
+		// contain slashes
+		nodeID := strings.SplitN(strings.TrimPrefix(string(kv.Key), daemonsPrefix), "/", 2)[0]
+		daemons[nodeID] = append(daemons[nodeID], d)
+	}
+	return daemons, nil
+}
@@ -6,10 +6,15 @@ type storedDaemon struct {
 	DName, DPath, DSocketFile, DPidFile, DID string
 
 	DArgs []string
+
+	// DUser and DCapabilities are the user and the capabilities the
+	// daemon is run as, for a daemon implementing UserDaemon
+	DUser         string    `json:",omitempty"`
+	DCapabilities []uintptr `json:",omitempty"`
 }
 
 func newStoredDaemon(d Daemon) *storedDaemon {
-	return &storedDaemon{
+	sd := &storedDaemon{
 		DName:       d.Name(),
 		DPath:       d.Path(),
 		DArgs:       d.Args(),
@@ -17,6 +22,8 @@ func newStoredDaemon(d Daemon) *storedDaemon {
 		DPidFile:    d.PidFile(),
 		DID:         d.ID(),
 	}
+	sd.DUser, sd.DCapabilities = runAs(d)
+	return sd
 }
 
 func (s *storedDaemon) Name() string {
@@ -42,3 +49,11 @@ func (s *storedDaemon) PidFile() string {
 func (s *storedDaemon) ID() string {
 	return s.DID
 }
+
+func (s *storedDaemon) User() string {
+	return s.DUser
+}
+
+func (s *storedDaemon) Capabilities() []uintptr {
+	return s.DCapabilities
+}
@@ -0,0 +1,480 @@
+package daemon
+
+import (
+	"errors"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/events"
+	"github.com/gluster/glusterd2/glusterd2/transactionv2"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	log "github.com/sirupsen/logrus"
+	flag "github.com/spf13/pflag"
+	config "github.com/spf13/viper"
+)
+
+const (
+	superviseIntervalOpt = "daemon-supervise-interval"
+
+	// maxBackoff is the longest time a daemon going down again after being
+	// restarted waits to be restarted
+	maxBackoff = 5 * time.Minute
+
+	// healthCheckTimeout is the time a daemon is given to accept a
+	// connection on its socket
+	healthCheckTimeout = 2 * time.Second
+)
+
+// RestartPolicy tells whether the daemons of a kind are restarted when
+// found down
+type RestartPolicy string
+
+const (
+	// RestartAlways restarts the daemons found down
+	RestartAlways RestartPolicy = "always"
+	// RestartNever leaves the daemons found down, for daemons which exit
+	// when done
+	RestartNever RestartPolicy = "never"
+)
+
+// Policy is how the daemons of a kind are supervised
+type Policy struct {
+	// DependsOn are the names of the kinds of daemons to be running before
+	// the daemons of this kind are started or restarted
+	DependsOn []string
+	Restart   RestartPolicy
+	// MaxRestarts is the number of restarts within RestartWindow after
+	// which a daemon is no longer restarted. 0 is no limit.
+	MaxRestarts   int
+	RestartWindow time.Duration
+	// HealthCheck, if set, checks a running daemon. A daemon failing
+	// HealthCheckFailures checks in a row is restarted.
+	HealthCheck         func(Daemon) error
+	HealthCheckFailures int
+}
+
+// defaultPolicy is the policy of the daemons of kinds without a registered
+// policy
+var defaultPolicy = Policy{
+	Restart:       RestartAlways,
+	MaxRestarts:   5,
+	RestartWindow: 10 * time.Minute,
+}
+
+var (
+	policiesLock sync.RWMutex
+	policies     = make(map[string]Policy)
+)
+
+// RegisterPolicy registers the policy of the daemons of the name, as returned
+// by their Name()
+func RegisterPolicy(name string, p Policy) {
+	policiesLock.Lock()
+	defer policiesLock.Unlock()
+	policies[name] = p
+}
+
+func policyOf(name string) Policy {
+	policiesLock.RLock()
+	defer policiesLock.RUnlock()
+	if p, ok := policies[name]; ok {
+		return p
+	}
+	return defaultPolicy
+}
+
+// SocketHealthCheck checks that the daemon accepts connections on its
+// socket
+func SocketHealthCheck(d Daemon) error {
+	if d.SocketFile() == "" {
+		return errors.New("daemon has no socket")
+	}
+	conn, err := net.DialTimeout("unix", d.SocketFile(), healthCheckTimeout)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// Supervision states of a daemon
+const (
+	StateRunning    = "running"
+	StateDown       = "down"
+	StateRestarting = "restarting"
+	StateStopping   = "stopping"
+	StateHeld       = "held"
+	StateFailed     = "failed"
+)
+
+// supervised is the supervision state of a daemon
+type supervised struct {
+	state string
+	pid   int
+	// downSince is when the daemon was first found down
+	downSince time.Time
+	// restarts are the times the daemon was restarted, within the restart
+	// window
+	restarts    []time.Time
+	lastError   string
+	healthFails int
+}
+
+var (
+	stateLock sync.Mutex
+	// states are the supervision states of the daemons, by ID
+	states = make(map[string]*supervised)
+
+	stopChan chan struct{}
+	stopOnce sync.Once
+)
+
+// InitFlags intializes the command line options for the supervision of the
+// daemons
+func InitFlags() {
+	flag.Duration(superviseIntervalOpt, 10*time.Second, "Interval at which the daemons managed by glusterd2 are checked, and restarted when down. Set to 0 to disable.")
+}
+
+func stateOf(id string) *supervised {
+	s, ok := states[id]
+	if !ok {
+		s = &supervised{state: StateRunning}
+		states[id] = s
+	}
+	return s
+}
+
+// setState sets the supervision state of the daemon with the ID
+func setState(id, state string) {
+	stateLock.Lock()
+	defer stateLock.Unlock()
+	stateOf(id).state = state
+}
+
+// forgetState drops the supervision state of the daemon with the ID, which
+// is no longer managed
+func forgetState(id string) {
+	stateLock.Lock()
+	defer stateLock.Unlock()
+	delete(states, id)
+}
+
+// Hold has the daemon, about to be stopped outside of Stop, not restarted
+// until it is started again or glusterd2 restarts
+func Hold(d Daemon) {
+	_, pid := IsRunning(d)
+
+	stateLock.Lock()
+	defer stateLock.Unlock()
+	s := stateOf(d.ID())
+	s.state = StateHeld
+	s.pid = pid
+}
+
+// StartSupervisor starts checking the daemons periodically, restarting them
+// as per their policies
+func StartSupervisor() {
+	interval := config.GetDuration(superviseIntervalOpt)
+	if interval <= 0 {
+		log.Info("supervision of the daemons disabled")
+		return
+	}
+
+	stopChan = make(chan struct{})
+	go transaction.UntilStop(supervise, interval, stopChan)
+}
+
+// StopSupervisor stops checking the daemons
+func StopSupervisor() {
+	if stopChan == nil {
+		return
+	}
+	stopOnce.Do(func() {
+		close(stopChan)
+	})
+}
+
+// depth returns the length of the longest chain of dependencies of the
+// kind of daemons. Dependency cycles are cut where found.
+func depth(name string, depths map[string]int, visiting map[string]bool) int {
+	if d, ok := depths[name]; ok {
+		return d
+	}
+	if visiting[name] {
+		return 0
+	}
+	visiting[name] = true
+	defer delete(visiting, name)
+
+	d := 0
+	for _, dep := range policyOf(name).DependsOn {
+		if n := depth(dep, depths, visiting) + 1; n > d {
+			d = n
+		}
+	}
+	depths[name] = d
+	return d
+}
+
+// order sorts the daemons so that the daemons of a kind come after the
+// daemons of the kinds they depend on
+func order(ds []Daemon) []Daemon {
+	depths := make(map[string]int)
+	for _, d := range ds {
+		depth(d.Name(), depths, make(map[string]bool))
+	}
+
+	sorted := make([]Daemon, len(ds))
+	copy(sorted, ds)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return depths[sorted[i].Name()] < depths[sorted[j].Name()]
+	})
+	return sorted
+}
+
+// backoff returns the time to wait before restarting a daemon restarted the
+// given number of times within the restart window
+func backoff(interval time.Duration, restarts int) time.Duration {
+	b := interval
+	for i := 0; i < restarts && b < maxBackoff; i++ {
+		b *= 2
+	}
+	if b > maxBackoff {
+		b = maxBackoff
+	}
+	return b
+}
+
+// recentRestarts drops the restarts older than the window
+func recentRestarts(restarts []time.Time, window time.Duration, now time.Time) []time.Time {
+	if window <= 0 {
+		return restarts
+	}
+	var recent []time.Time
+	for _, r := range restarts {
+		if now.Sub(r) < window {
+			recent = append(recent, r)
+		}
+	}
+	return recent
+}
+
+// supervise checks the daemons managed by this peer, in the order of their
+// dependencies, restarting the daemons found down or unhealthy
+func supervise() {
+	ds, err := getDaemons()
+	if err != nil {
+		log.WithError(err).Error("failed to get the daemons to supervise")
+		return
+	}
+
+	managed := make(map[string]bool, len(ds))
+	down := make(map[string]bool)
+	for _, d := range order(ds) {
+		managed[d.ID()] = true
+		if !check(d, down) {
+			down[d.Name()] = true
+		}
+	}
+
+	stateLock.Lock()
+	for id := range states {
+		if !managed[id] {
+			delete(states, id)
+		}
+	}
+	stateLock.Unlock()
+}
+
+// check checks the daemon, and restarts it if it is down or unhealthy and
+// its policy allows it. Daemons with a dependency down are not restarted.
+// It returns false if the daemon is down, and to be restarted.
+func check(d Daemon, down map[string]bool) bool {
+	policy := policyOf(d.Name())
+	logger := log.WithFields(log.Fields{"name": d.Name(), "id": d.ID()})
+	now := time.Now()
+
+	running, pid := IsRunning(d)
+
+	stateLock.Lock()
+	s := stateOf(d.ID())
+	switch {
+	case s.state == StateStopping:
+		stateLock.Unlock()
+		return true
+	case s.state == StateHeld && (!running || pid == s.pid):
+		// a held daemon is supervised again once started again
+		stateLock.Unlock()
+		return true
+	}
+	stateLock.Unlock()
+
+	if running && policy.HealthCheck != nil {
+		if err := policy.HealthCheck(d); err != nil {
+			stateLock.Lock()
+			s.healthFails++
+			s.lastError = err.Error()
+			unhealthy := policy.HealthCheckFailures > 0 && s.healthFails >= policy.HealthCheckFailures
+			stateLock.Unlock()
+			if unhealthy && policy.Restart == RestartAlways {
+				logger.WithError(err).Warn("daemon failed its health checks, restarting it")
+				if err := Kill(pid, true); err != nil {
+					logger.WithError(err).Error("failed to kill unhealthy daemon")
+				}
+				running = false
+			}
+		} else {
+			stateLock.Lock()
+			s.healthFails = 0
+			stateLock.Unlock()
+		}
+	}
+
+	if running {
+		stateLock.Lock()
+		s.state = StateRunning
+		s.pid = pid
+		s.downSince = time.Time{}
+		stateLock.Unlock()
+		return true
+	}
+
+	if shouldRestart(d, s, policy, down, now) {
+		restart(d, s, logger)
+	}
+
+	// a daemon not to be restarted does not hold back its dependents
+	if policy.Restart == RestartNever {
+		return true
+	}
+	stateLock.Lock()
+	defer stateLock.Unlock()
+	return s.state == StateRunning || s.state == StateFailed
+}
+
+// shouldRestart records the daemon as down, and tells if it is to be
+// restarted now
+func shouldRestart(d Daemon, s *supervised, policy Policy, down map[string]bool, now time.Time) bool {
+	stateLock.Lock()
+	defer stateLock.Unlock()
+
+	s.pid = 0
+	if s.state == StateRunning || s.downSince.IsZero() {
+		// a daemon is only restarted when found down twice in a row,
+		// so that a daemon being stopped is not restarted
+		s.state = StateDown
+		s.downSince = now
+		return false
+	}
+	if policy.Restart != RestartAlways || s.state == StateFailed {
+		return false
+	}
+	for _, dep := range policy.DependsOn {
+		if down[dep] {
+			return false
+		}
+	}
+
+	s.restarts = recentRestarts(s.restarts, policy.RestartWindow, now)
+	if policy.MaxRestarts > 0 && len(s.restarts) >= policy.MaxRestarts {
+		s.state = StateFailed
+		log.WithFields(log.Fields{
+			"name":     d.Name(),
+			"id":       d.ID(),
+			"restarts": len(s.restarts),
+		}).Error("daemon restarted too many times, no longer restarting it")
+		events.Broadcast(newEvent(d, daemonRestartLimit, 0))
+		return false
+	}
+	interval := config.GetDuration(superviseIntervalOpt)
+	if len(s.restarts) > 0 && now.Sub(s.restarts[len(s.restarts)-1]) < backoff(interval, len(s.restarts)) {
+		return false
+	}
+
+	s.state = StateRestarting
+	s.restarts = append(s.restarts, now)
+	return true
+}
+
+// restart starts the daemon found down again
+func restart(d Daemon, s *supervised, logger log.FieldLogger) {
+	// the daemon may have been stopped and removed in the meantime
+	if _, err := getDaemon(d.ID()); err != nil {
+		return
+	}
+
+	err := Start(d, true, logger)
+
+	stateLock.Lock()
+	defer stateLock.Unlock()
+	if err != nil && err != gderrors.ErrProcessAlreadyRunning {
+		s.state = StateDown
+		s.lastError = err.Error()
+		logger.WithError(err).Error("failed to restart daemon")
+		return
+	}
+	s.state = StateRunning
+	s.healthFails = 0
+	s.downSince = time.Time{}
+	logger.Info("restarted daemon found down")
+	events.Broadcast(newEvent(d, daemonRestarted, 0))
+}
+
+// Statuses returns the daemons managed by this peer with their supervision
+// state, grouped by kind in the order of their dependencies
+func Statuses() ([]api.DaemonKind, error) {
+	ds, err := getDaemons()
+	if err != nil {
+		return nil, err
+	}
+
+	kinds := make([]api.DaemonKind, 0)
+	index := make(map[string]int)
+	for _, d := range order(ds) {
+		i, ok := index[d.Name()]
+		if !ok {
+			policy := policyOf(d.Name())
+			k := api.DaemonKind{
+				Name:          d.Name(),
+				DependsOn:     policy.DependsOn,
+				RestartPolicy: string(policy.Restart),
+				MaxRestarts:   policy.MaxRestarts,
+				HealthChecked: policy.HealthCheck != nil,
+				Daemons:       make([]api.DaemonStatus, 0),
+			}
+			if k.DependsOn == nil {
+				k.DependsOn = make([]string, 0)
+			}
+			kinds = append(kinds, k)
+			i = len(kinds) - 1
+			index[d.Name()] = i
+		}
+
+		status := api.DaemonStatus{ID: d.ID(), State: StateDown}
+		if running, pid := IsRunning(d); running {
+			status.State = StateRunning
+			status.PID = pid
+		}
+
+		stateLock.Lock()
+		if s, ok := states[d.ID()]; ok {
+			if s.state != StateRunning {
+				status.State = s.state
+			}
+			status.Restarts = len(s.restarts)
+			if len(s.restarts) > 0 {
+				last := s.restarts[len(s.restarts)-1]
+				status.LastRestart = &last
+			}
+			status.LastError = s.lastError
+			status.HealthCheckFailures = s.healthFails
+		}
+		stateLock.Unlock()
+
+		kinds[i].Daemons = append(kinds[i].Daemons, status)
+	}
+	return kinds, nil
+}
@@ -0,0 +1,83 @@
+package daemon
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func names(ds []Daemon) []string {
+	var n []string
+	for _, d := range ds {
+		n = append(n, d.Name())
+	}
+	return n
+}
+
+func TestOrder(t *testing.T) {
+	RegisterPolicy("test-brick", Policy{Restart: RestartAlways})
+	RegisterPolicy("test-shd", Policy{DependsOn: []string{"test-brick"}})
+	RegisterPolicy("test-georep", Policy{DependsOn: []string{"test-shd", "test-brick"}})
+	// cycles are cut where found
+	RegisterPolicy("test-a", Policy{DependsOn: []string{"test-b"}})
+	RegisterPolicy("test-b", Policy{DependsOn: []string{"test-a"}})
+
+	ds := []Daemon{
+		&storedDaemon{DName: "test-georep"},
+		&storedDaemon{DName: "test-shd"},
+		&storedDaemon{DName: "test-brick", DID: "b1"},
+		&storedDaemon{DName: "test-brick", DID: "b2"},
+	}
+	sorted := order(ds)
+	assert.Equal(t, []string{"test-brick", "test-brick", "test-shd", "test-georep"}, names(sorted))
+	assert.Equal(t, "b1", sorted[0].ID())
+	// the daemons given are left as they are
+	assert.Equal(t, "test-georep", ds[0].Name())
+
+	assert.Len(t, order([]Daemon{&storedDaemon{DName: "test-a"}, &storedDaemon{DName: "test-b"}}), 2)
+}
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, 10*time.Second, backoff(10*time.Second, 0))
+	assert.Equal(t, 40*time.Second, backoff(10*time.Second, 2))
+	assert.Equal(t, maxBackoff, backoff(10*time.Second, 10))
+}
+
+func TestRecentRestarts(t *testing.T) {
+	now := time.Now()
+	restarts := []time.Time{now.Add(-time.Hour), now.Add(-time.Minute), now}
+
+	assert.Equal(t, restarts[1:], recentRestarts(restarts, 10*time.Minute, now))
+	assert.Len(t, recentRestarts(restarts, 0, now), 3)
+}
+
+func TestShouldRestart(t *testing.T) {
+	d := &storedDaemon{DName: "test-shd", DID: "shd"}
+	p := Policy{
+		DependsOn:     []string{"test-brick"},
+		Restart:       RestartAlways,
+		MaxRestarts:   1,
+		RestartWindow: time.Hour,
+	}
+	s := &supervised{state: StateRunning}
+	now := time.Now()
+
+	// a daemon is restarted once found down twice in a row
+	assert.False(t, shouldRestart(d, s, p, nil, now))
+	assert.Equal(t, StateDown, s.state)
+
+	// nor while a dependency is down
+	assert.False(t, shouldRestart(d, s, p, map[string]bool{"test-brick": true}, now))
+
+	assert.True(t, shouldRestart(d, s, p, nil, now))
+	assert.Equal(t, StateRestarting, s.state)
+	assert.Len(t, s.restarts, 1)
+
+	s.state = StateDown
+	assert.False(t, shouldRestart(d, s, p, nil, now.Add(time.Minute)))
+	assert.Equal(t, StateFailed, s.state)
+
+	never := &supervised{state: StateDown, downSince: now}
+	assert.False(t, shouldRestart(d, never, Policy{Restart: RestartNever}, nil, now))
+}
@@ -0,0 +1,76 @@
+package daemon
+
+import (
+	"os/user"
+	"strconv"
+	"syscall"
+)
+
+// UserDaemon is implemented by daemons which may be run as a user other than
+// root. Daemons not implementing it are run as the user of glusterd2.
+type UserDaemon interface {
+	// User should return the name of the user the daemon is run as, or
+	// an empty string for the user of glusterd2.
+	User() string
+
+	// Capabilities should return the capabilities the daemon keeps when
+	// it is run as another user.
+	Capabilities() []uintptr
+}
+
+// runAs returns the user and the capabilities the daemon is run as. An empty
+// user is the user of glusterd2.
+func runAs(d Daemon) (string, []uintptr) {
+	ud, ok := d.(UserDaemon)
+	if !ok {
+		return "", nil
+	}
+	return ud.User(), ud.Capabilities()
+}
+
+// credential returns the credential of the user with the name, with its
+// supplementary groups
+func credential(name string) (*syscall.Credential, error) {
+	u, err := user.Lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	uid, err := strconv.ParseUint(u.Uid, 10, 32)
+	if err != nil {
+		return nil, err
+	}
+	gid, err := strconv.ParseUint(u.Gid, 10, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	cred := &syscall.Credential{Uid: uint32(uid), Gid: uint32(gid)}
+	groups, err := u.GroupIds()
+	if err != nil {
+		return nil, err
+	}
+	for _, g := range groups {
+		if id, err := strconv.ParseUint(g, 10, 32); err == nil {
+			cred.Groups = append(cred.Groups, uint32(id))
+		}
+	}
+	return cred, nil
+}
+
+// sysProcAttr returns the attributes of the process of the daemon, running
+// it as its user with its capabilities, or nil for the daemon to be run as
+// the user of glusterd2
+func sysProcAttr(d Daemon) (*syscall.SysProcAttr, error) {
+	name, caps := runAs(d)
+	if name == "" {
+		return nil, nil
+	}
+	cred, err := credential(name)
+	if err != nil {
+		return nil, err
+	}
+	return &syscall.SysProcAttr{
+		Credential:  cred,
+		AmbientCaps: caps,
+	}, nil
+}
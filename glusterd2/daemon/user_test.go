@@ -0,0 +1,40 @@
+package daemon
+
+import (
+	"encoding/json"
+	"os/user"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoredDaemonUser(t *testing.T) {
+	d := &storedDaemon{DName: "test-brick", DUser: "gluster", DCapabilities: []uintptr{21}}
+	sd := newStoredDaemon(d)
+	assert.Equal(t, "gluster", sd.User())
+	assert.Equal(t, []uintptr{21}, sd.Capabilities())
+
+	data, err := json.Marshal(sd)
+	require.NoError(t, err)
+	sd, err = unmarshalStoredDaemon(data)
+	require.NoError(t, err)
+	assert.Equal(t, "gluster", sd.User())
+	assert.Equal(t, []uintptr{21}, sd.Capabilities())
+}
+
+func TestSysProcAttr(t *testing.T) {
+	attr, err := sysProcAttr(&storedDaemon{DName: "test-shd"})
+	require.NoError(t, err)
+	assert.Nil(t, attr)
+
+	current, err := user.Current()
+	require.NoError(t, err)
+	attr, err = sysProcAttr(&storedDaemon{DName: "test-brick", DUser: current.Username, DCapabilities: []uintptr{21}})
+	require.NoError(t, err)
+	require.NotNil(t, attr.Credential)
+	assert.Equal(t, []uintptr{21}, attr.AmbientCaps)
+
+	_, err = sysProcAttr(&storedDaemon{DName: "test-brick", DUser: "no-such-user-gd2"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,252 @@
+// Package datatls manages TLS on the data path of the volumes, between the
+// clients and the bricks: the options enabling it in the client and brick
+// graphs, the CA bundle distributed to the peers, and the checks of the
+// certificates of the peers.
+package datatls
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+)
+
+// Keys of the volume options enabling TLS in the brick and client graphs,
+// as saved in Volinfo.Options
+const (
+	ServerSSLKey = "protocol/server.transport.socket.ssl-enabled"
+	ClientSSLKey = "protocol/client.transport.socket.ssl-enabled"
+	SSLAllowKey  = "protocol/server.auth.ssl-allow"
+
+	// caKey is where the CA bundle is saved in the store
+	caKey = "tls/ca-bundle"
+)
+
+// The files the bricks and clients read their certificate, private key and
+// CA bundle from, as expected by glusterfs
+var (
+	CertFile = "/etc/ssl/glusterfs.pem"
+	KeyFile  = "/etc/ssl/glusterfs.key"
+	CAFile   = "/etc/ssl/glusterfs.ca"
+)
+
+var errNoCertificates = errors.New("no certificates found in the CA bundle")
+
+// IsEnabled tells if TLS is enabled on the data path of the volume
+func IsEnabled(v *volume.Volinfo) bool {
+	return v.Options[ServerSSLKey] == "on" && v.Options[ClientSSLKey] == "on"
+}
+
+// Options returns the volume options enabling or disabling TLS on the data
+// path of the volume, allowing the clients with the given common names
+func Options(enable bool, allow []string) map[string]string {
+	if !enable {
+		return map[string]string{
+			ServerSSLKey: "off",
+			ClientSSLKey: "off",
+		}
+	}
+
+	opts := map[string]string{
+		ServerSSLKey: "on",
+		ClientSSLKey: "on",
+		SSLAllowKey:  "*",
+	}
+	if len(allow) > 0 {
+		opts[SSLAllowKey] = strings.Join(allow, ",")
+	}
+	return opts
+}
+
+// parseBundle returns the certificates of the PEM encoded bundle
+func parseBundle(bundle []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, bundle = pem.Decode(bundle)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, errNoCertificates
+	}
+	return certs, nil
+}
+
+// CA returns the CA bundle distributed to the peers, if one was set
+func CA() (string, error) {
+	resp, err := store.Get(context.TODO(), caKey)
+	if err != nil {
+		return "", err
+	}
+	if resp.Count != 1 {
+		return "", nil
+	}
+	return string(resp.Kvs[0].Value), nil
+}
+
+// SetCA sets the CA bundle distributed to the peers, returning the subjects
+// of its certificates. The peers write it to their CA file as they check
+// their certificates.
+func SetCA(bundle string) ([]string, error) {
+	certs, err := parseBundle([]byte(bundle))
+	if err != nil {
+		return nil, err
+	}
+	if _, err := store.Put(context.TODO(), caKey, bundle); err != nil {
+		return nil, err
+	}
+	return Subjects(certs), nil
+}
+
+// AddCA adds the PEM encoded certificate of a CA to the CA bundle
+// distributed to the peers, unless already in it
+func AddCA(cert string) error {
+	certs, err := parseBundle([]byte(cert))
+	if err != nil {
+		return err
+	}
+
+	bundle, err := CA()
+	if err != nil {
+		return err
+	}
+	if bundle != "" {
+		current, err := parseBundle([]byte(bundle))
+		if err != nil {
+			return err
+		}
+		for _, c := range current {
+			if c.Equal(certs[0]) {
+				return nil
+			}
+		}
+		if !strings.HasSuffix(bundle, "\n") {
+			bundle += "\n"
+		}
+	}
+
+	_, err = store.Put(context.TODO(), caKey, bundle+cert)
+	return err
+}
+
+// Subjects returns the subjects of the certificates
+func Subjects(certs []*x509.Certificate) []string {
+	subjects := make([]string, 0, len(certs))
+	for _, c := range certs {
+		subjects = append(subjects, c.Subject.String())
+	}
+	return subjects
+}
+
+// BundleSubjects returns the subjects of the certificates of the PEM encoded
+// bundle
+func BundleSubjects(bundle string) ([]string, error) {
+	certs, err := parseBundle([]byte(bundle))
+	if err != nil {
+		return nil, err
+	}
+	return Subjects(certs), nil
+}
+
+// SyncCA writes the CA bundle distributed to the peers to the CA file, if
+// one was set
+func SyncCA() error {
+	bundle, err := CA()
+	if err != nil || bundle == "" {
+		return err
+	}
+	return WriteCA(bundle)
+}
+
+// WriteCA writes the CA bundle to the CA file, if it differs
+func WriteCA(bundle string) error {
+	if current, err := ioutil.ReadFile(CAFile); err == nil && string(current) == bundle {
+		return nil
+	}
+
+	if err := os.MkdirAll(path.Dir(CAFile), 0755); err != nil {
+		return err
+	}
+	tmp := CAFile + ".tmp"
+	if err := ioutil.WriteFile(tmp, []byte(bundle), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, CAFile)
+}
+
+// Check checks the certificate and private key of this peer, which must
+// match, be valid now and be issued by a CA of the CA bundle
+func Check(now time.Time) api.TLSNodeStatus {
+	status := api.TLSNodeStatus{PeerID: gdctx.MyUUID}
+
+	fail := func(err error) api.TLSNodeStatus {
+		status.Error = err.Error()
+		return status
+	}
+
+	pair, err := tls.LoadX509KeyPair(CertFile, KeyFile)
+	if err != nil {
+		return fail(err)
+	}
+	cert, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return fail(err)
+	}
+	status.Subject = cert.Subject.String()
+	status.Issuer = cert.Issuer.String()
+	status.NotAfter = cert.NotAfter
+
+	bundle, err := ioutil.ReadFile(CAFile)
+	if err != nil {
+		return fail(err)
+	}
+	cas, err := parseBundle(bundle)
+	if err != nil {
+		return fail(err)
+	}
+	roots := x509.NewCertPool()
+	for _, ca := range cas {
+		roots.AddCert(ca)
+	}
+	intermediates := x509.NewCertPool()
+	for _, c := range pair.Certificate[1:] {
+		if ic, err := x509.ParseCertificate(c); err == nil {
+			intermediates.AddCert(ic)
+		}
+	}
+
+	_, err = cert.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		CurrentTime:   now,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	if err != nil {
+		return fail(fmt.Errorf("certificate not trusted: %s", err))
+	}
+
+	status.Valid = true
+	return status
+}
@@ -0,0 +1,112 @@
+package datatls
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newCert(t *testing.T, cn string, isCA bool, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  isCA,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+	}
+	if parent == nil {
+		parent, parentKey = tmpl, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, &key.PublicKey, parentKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert, key, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func writeKey(t *testing.T, file string, key *ecdsa.PrivateKey) {
+	der, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), 0600))
+}
+
+func TestCheck(t *testing.T) {
+	dir, err := ioutil.TempDir("", "datatls")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	CertFile, KeyFile, CAFile = path.Join(dir, "cert.pem"), path.Join(dir, "key.pem"), path.Join(dir, "ca.pem")
+
+	ca, caKey, caPEM := newCert(t, "ca", true, nil, nil)
+	_, key, certPEM := newCert(t, "server1", false, ca, caKey)
+	require.NoError(t, ioutil.WriteFile(CertFile, certPEM, 0644))
+	writeKey(t, KeyFile, key)
+
+	// No CA bundle
+	status := Check(time.Now())
+	assert.False(t, status.Valid)
+
+	require.NoError(t, ioutil.WriteFile(CAFile, caPEM, 0644))
+	status = Check(time.Now())
+	assert.True(t, status.Valid, status.Error)
+	assert.Equal(t, "CN=server1", status.Subject)
+	assert.Equal(t, "CN=ca", status.Issuer)
+
+	// Expired
+	status = Check(time.Now().Add(2 * time.Hour))
+	assert.False(t, status.Valid)
+
+	// Issued by another CA
+	_, _, otherPEM := newCert(t, "other-ca", true, nil, nil)
+	require.NoError(t, ioutil.WriteFile(CAFile, otherPEM, 0644))
+	status = Check(time.Now())
+	assert.False(t, status.Valid)
+
+	// Key not matching the certificate
+	require.NoError(t, ioutil.WriteFile(CAFile, caPEM, 0644))
+	writeKey(t, KeyFile, caKey)
+	status = Check(time.Now())
+	assert.False(t, status.Valid)
+}
+
+func TestBundleSubjects(t *testing.T) {
+	_, _, ca1 := newCert(t, "ca1", true, nil, nil)
+	_, _, ca2 := newCert(t, "ca2", true, nil, nil)
+
+	subjects, err := BundleSubjects(string(ca1) + string(ca2))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"CN=ca1", "CN=ca2"}, subjects)
+
+	_, err = BundleSubjects("not a bundle")
+	assert.Equal(t, errNoCertificates, err)
+}
+
+func TestOptions(t *testing.T) {
+	opts := Options(true, []string{"client1", "client2"})
+	assert.Equal(t, "on", opts[ServerSSLKey])
+	assert.Equal(t, "on", opts[ClientSSLKey])
+	assert.Equal(t, "client1,client2", opts[SSLAllowKey])
+
+	assert.Equal(t, "*", Options(true, nil)[SSLAllowKey])
+
+	opts = Options(false, nil)
+	assert.Equal(t, "off", opts[ServerSSLKey])
+	_, ok := opts[SSLAllowKey]
+	assert.False(t, ok)
+}
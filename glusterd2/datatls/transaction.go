@@ -0,0 +1,144 @@
+package datatls
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/brickmux"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/pborman/uuid"
+)
+
+const (
+	statusTxnKey  = "datatls.status"
+	volnameTxnKey = "datatls.volname"
+)
+
+// CheckStep returns a step having each of the given nodes write the CA
+// bundle to its CA file and check its certificate, failing if it is not
+// valid
+func CheckStep(nodes []uuid.UUID) *transaction.Step {
+	return &transaction.Step{
+		DoFunc: "datatls.Check",
+		Nodes:  nodes,
+	}
+}
+
+// StatusStep returns a step having each of the given nodes report the state
+// of its certificate
+func StatusStep(nodes []uuid.UUID) *transaction.Step {
+	return &transaction.Step{
+		DoFunc: "datatls.Status",
+		Nodes:  nodes,
+	}
+}
+
+// ReloadStep returns a step having each of the given nodes restart its
+// bricks of the volume, for them to load the certificates again
+func ReloadStep(c transaction.TxnCtx, volname string, nodes []uuid.UUID) (*transaction.Step, error) {
+	if err := c.Set(volnameTxnKey, volname); err != nil {
+		return nil, err
+	}
+	return &transaction.Step{
+		DoFunc: "datatls.ReloadBricks",
+		Nodes:  nodes,
+	}, nil
+}
+
+// NodeStatuses returns the states of the certificates reported by the nodes
+// in the transaction, skipping the nodes which did not report
+func NodeStatuses(c transaction.TxnCtx, nodes []uuid.UUID) []api.TLSNodeStatus {
+	statuses := make([]api.TLSNodeStatus, 0, len(nodes))
+	for _, node := range nodes {
+		var status api.TLSNodeStatus
+		if err := c.GetNodeResult(node, statusTxnKey, &status); err != nil {
+			continue
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+func txnStatus(c transaction.TxnCtx) error {
+	if err := SyncCA(); err != nil {
+		c.Logger().WithError(err).Error("failed to write the CA bundle")
+	}
+	return c.SetNodeResult(gdctx.MyUUID, statusTxnKey, Check(time.Now()))
+}
+
+func txnCheck(c transaction.TxnCtx) error {
+	if err := SyncCA(); err != nil {
+		c.Logger().WithError(err).Error("failed to write the CA bundle")
+		return err
+	}
+	status := Check(time.Now())
+	if err := c.SetNodeResult(gdctx.MyUUID, statusTxnKey, status); err != nil {
+		return err
+	}
+	if !status.Valid {
+		return fmt.Errorf("invalid TLS certificate on peer %s: %s", gdctx.MyUUID, status.Error)
+	}
+	return nil
+}
+
+// txnReloadBricks restarts the local bricks of the volume. Multiplexed
+// bricks are attached again to a compatible process.
+func txnReloadBricks(c transaction.TxnCtx) error {
+	var volname string
+	if err := c.Get(volnameTxnKey, &volname); err != nil {
+		return err
+	}
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		return err
+	}
+	if volinfo.State != volume.VolStarted {
+		return nil
+	}
+
+	bmuxEnabled, err := brickmux.Enabled()
+	if err != nil {
+		return err
+	}
+	var allVolumes []*volume.Volinfo
+	if bmuxEnabled {
+		if allVolumes, err = volume.GetVolumes(context.TODO()); err != nil {
+			return err
+		}
+	}
+
+	for _, b := range volinfo.GetLocalBricks() {
+		c.Logger().WithField("brick", b.String()).Info("restarting brick to reload TLS certificates")
+		if err := b.StopBrick(c.Logger()); err != nil && err != errors.ErrPidFileNotFound {
+			return err
+		}
+
+		if bmuxEnabled {
+			err := brickmux.Multiplex(b, volinfo, allVolumes, c.Logger())
+			if err == nil {
+				continue
+			}
+			if err != brickmux.ErrNoCompat {
+				return err
+			}
+		}
+		if err := b.StartBrick(c.Logger()); err != nil && err != errors.ErrProcessAlreadyRunning {
+			return err
+		}
+	}
+	return nil
+}
+
+// RegisterStepFuncs registers the step functions checking the certificates
+// and reloading the bricks
+func RegisterStepFuncs() {
+	transaction.RegisterStepFunc(txnCheck, "datatls.Check")
+	transaction.RegisterStepFunc(txnStatus, "datatls.Status")
+	transaction.RegisterStepFunc(txnReloadBricks, "datatls.ReloadBricks")
+}
@@ -0,0 +1,424 @@
+// Package drift detects the differences between the effective state of the
+// started volumes on this peer and their state in the store, such as a brick
+// running with a stale volfile after an option set which failed on this
+// peer, and corrects them.
+package drift
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/events"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/options"
+	"github.com/gluster/glusterd2/glusterd2/pmap"
+	"github.com/gluster/glusterd2/glusterd2/servers/sunrpc"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	transactionv2 "github.com/gluster/glusterd2/glusterd2/transactionv2"
+	"github.com/gluster/glusterd2/glusterd2/volgen"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/coreos/etcd/clientv3"
+	log "github.com/sirupsen/logrus"
+	flag "github.com/spf13/pflag"
+	config "github.com/spf13/viper"
+)
+
+const (
+	// EventDriftDetected is broadcast when a drift of a volume is found
+	EventDriftDetected = "volume_drift_detected"
+	// EventDriftCorrected is broadcast when a drift of a volume is
+	// corrected
+	EventDriftCorrected = "volume_drift_corrected"
+
+	intervalOpt    = "drift-check-interval"
+	autoCorrectKey = "cluster.drift-auto-correct"
+
+	// driftsPrefix is where the drifts detected by the peers are saved in
+	// the store, by peer ID
+	driftsPrefix = "drift/"
+)
+
+// suspect is a drift found by the last scan. It is confirmed when found
+// again by the next scan, as the volfiles are regenerated and fetched again
+// by the bricks and clients while the volume operations run.
+type suspect struct {
+	api.Drift
+	confirmed bool
+}
+
+// key identifies a drift across the scans. A drift from another version of
+// the volfile is a new drift.
+func (s *suspect) key() string {
+	return s.Kind + "|" + s.VolfileID + "|" + s.Brick + "|" + s.Client + "|" + s.Expected
+}
+
+var (
+	stopChan chan struct{}
+	stopOnce sync.Once
+
+	// scanLock serializes the periodic scans and the reconciliations on
+	// demand
+	scanLock sync.Mutex
+	suspects = make(map[string]*suspect)
+)
+
+// InitFlags intializes the command line options for the detection of the
+// drifts of the volumes
+func InitFlags() {
+	flag.Duration(intervalOpt, 10*time.Minute, "Interval at which the volfiles and the bricks of the started volumes are checked against the store. Set to 0 to disable the checks.")
+}
+
+// Start checks the volumes for drifts now and periodically
+func Start() {
+	interval := config.GetDuration(intervalOpt)
+	if interval <= 0 {
+		log.Info("detection of volume drifts disabled")
+		return
+	}
+
+	stopChan = make(chan struct{})
+	go transactionv2.UntilStop(func() {
+		if _, err := scan(autoCorrectEnabled()); err != nil {
+			log.WithError(err).Error("failed to check the volumes for drifts")
+		}
+	}, interval, stopChan)
+}
+
+// Stop stops the periodic checks
+func Stop() {
+	if stopChan == nil {
+		return
+	}
+	stopOnce.Do(func() {
+		close(stopChan)
+	})
+}
+
+// autoCorrectEnabled tells if the drifts are to be corrected as soon as
+// they are confirmed
+func autoCorrectEnabled() bool {
+	value, err := options.GetClusterOption(autoCorrectKey)
+	if err != nil {
+		return false
+	}
+	correct, err := options.StringToBoolean(value)
+	if err != nil {
+		return false
+	}
+	return correct
+}
+
+// clientVolfile generates the client volfile of the volume, as served to
+// the clients
+func clientVolfile(v *volume.Volinfo) (string, error) {
+	tmpl, err := volgen.GetTemplateFromVolinfo(v, "client")
+	if err != nil {
+		return "", err
+	}
+	return volgen.VolumeLevelVolfile(tmpl, v)
+}
+
+// Find returns the drifts of the started volumes on this peer: the brick
+// volfiles saved which differ from the volfiles generated from the store,
+// the bricks which are not running, and the bricks and clients which were
+// last served an older volfile. A volume whose volfiles cannot be
+// generated is reported as failed to check, and the other volumes are
+// checked still.
+func Find() ([]api.Drift, error) {
+	volumes, err := volume.GetVolumes(context.TODO())
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		drifts []api.Drift
+		// checksums of the volfiles generated from the store, and
+		// their volumes, by volfile ID
+		expected = make(map[string]string)
+		volumeOf = make(map[string]string)
+	)
+	for _, v := range volumes {
+		if v.State != volume.VolStarted {
+			continue
+		}
+
+		content, err := clientVolfile(v)
+		if err != nil {
+			log.WithError(err).WithField("volume", v.Name).Error("failed to generate the client volfile of the volume")
+			drifts = append(drifts, api.Drift{
+				PeerID: gdctx.MyUUID,
+				Volume: v.Name,
+				Kind:   api.DriftCheckFailed,
+				Error:  err.Error(),
+			})
+			continue
+		}
+		expected[v.Name] = volgen.Checksum(content)
+		volumeOf[v.Name] = v.Name
+
+		for _, b := range v.GetLocalBricks() {
+			volfileID := brick.GetVolfileID(v.Name, b.Path)
+			d := api.Drift{
+				PeerID:    gdctx.MyUUID,
+				Volume:    v.Name,
+				Brick:     b.Path,
+				VolfileID: volfileID,
+			}
+			content, err := volgen.BrickVolfile(v, "brick", b.PeerID.String(), b.Path)
+			if err != nil {
+				log.WithError(err).WithFields(log.Fields{
+					"volume": v.Name,
+					"brick":  b.Path,
+				}).Error("failed to generate the brick volfile")
+				failed := d
+				failed.Kind = api.DriftCheckFailed
+				failed.Error = err.Error()
+				drifts = append(drifts, failed)
+				continue
+			}
+			checksum := volgen.Checksum(content)
+			expected[volfileID] = checksum
+			volumeOf[volfileID] = v.Name
+
+			saved, err := ioutil.ReadFile(volgen.VolfilePath(volfileID))
+			if err != nil || volgen.Checksum(string(saved)) != checksum {
+				stale := d
+				stale.Kind = api.DriftBrickVolfileStale
+				stale.Expected = checksum
+				if err == nil {
+					stale.Actual = volgen.Checksum(string(saved))
+				}
+				drifts = append(drifts, stale)
+			}
+			if _, err := pmap.RegistrySearch(b.Path); err != nil {
+				d.Kind = api.DriftBrickNotRunning
+				drifts = append(drifts, d)
+			}
+		}
+	}
+
+	for _, s := range sunrpc.ServedVolfiles() {
+		// clients mounting an exported subdirectory are served the
+		// client volfile of the volume
+		id := s.VolfileID
+		if volname, _, ok := volume.SplitSubdirVolfileID(id); ok {
+			id = volname
+		}
+		checksum, ok := expected[id]
+		if !ok || s.Checksum == checksum {
+			continue
+		}
+		drifts = append(drifts, api.Drift{
+			PeerID:    gdctx.MyUUID,
+			Volume:    volumeOf[id],
+			Kind:      api.DriftVolfileNotLoaded,
+			VolfileID: s.VolfileID,
+			Client:    s.Address,
+			PID:       s.Pid,
+			Expected:  checksum,
+			Actual:    s.Checksum,
+		})
+	}
+	return drifts, nil
+}
+
+func eventData(d api.Drift) map[string]string {
+	return map[string]string{
+		"peer.id":    d.PeerID.String(),
+		"volume":     d.Volume,
+		"kind":       d.Kind,
+		"brick.path": d.Brick,
+		"volfile-id": d.VolfileID,
+		"client":     d.Client,
+		"pid":        strconv.Itoa(d.PID),
+	}
+}
+
+// track updates the suspects with the drifts found, and returns the drifts
+// confirmed. A drift is confirmed when found by two scans in a row.
+func track(found []api.Drift, now time.Time) []*suspect {
+	current := make(map[string]*suspect)
+	var confirmed []*suspect
+	for _, d := range found {
+		s := &suspect{Drift: d}
+		s.Since = now
+		if prev, ok := suspects[s.key()]; ok {
+			s.Since = prev.Since
+			s.confirmed = true
+			if !prev.confirmed {
+				log.WithFields(log.Fields{
+					"volume":     d.Volume,
+					"kind":       d.Kind,
+					"brick":      d.Brick,
+					"volfile-id": d.VolfileID,
+					"client":     d.Client,
+				}).Warn("volume drifted from its state in the store")
+				events.Broadcast(events.New(EventDriftDetected, eventData(d), true))
+			}
+			confirmed = append(confirmed, s)
+		}
+		current[s.key()] = s
+	}
+	suspects = current
+	return confirmed
+}
+
+// correct corrects the drifts of the volume, under the lock of the volume
+// and against the volume read again from the store: the stale brick
+// volfiles are generated again, and the bricks and clients served an older
+// volfile are notified to fetch it again. The bricks which are not running
+// are left to the daemon supervisor. It returns the drifts corrected.
+func correct(volname string, drifts []*suspect) ([]*suspect, error) {
+	ctx := gdctx.WithReqLogger(context.Background(), log.StandardLogger())
+	txn, err := transactionv2.NewTxnWithLocks(ctx, volname)
+	if err != nil {
+		return nil, err
+	}
+	defer txn.Done()
+
+	v, err := volume.GetVolume(volname)
+	if err != nil {
+		return nil, err
+	}
+	if v.State != volume.VolStarted {
+		return nil, nil
+	}
+	bricks := make(map[string]brick.Brickinfo)
+	for _, b := range v.GetLocalBricks() {
+		bricks[b.Path] = b
+	}
+
+	logger := log.WithField("volume", volname)
+	notified := make(map[string]sunrpc.NotifyResult)
+	notify := func(volfileID string) bool {
+		result, ok := notified[volfileID]
+		if !ok {
+			result = sunrpc.VolfileNotify(logger, volfileID)
+			notified[volfileID] = result
+		}
+		return result.Failed == 0 && result.Pending == 0
+	}
+
+	var corrected []*suspect
+	for _, s := range drifts {
+		switch s.Kind {
+		case api.DriftBrickVolfileStale:
+			b, ok := bricks[s.Brick]
+			if !ok {
+				continue
+			}
+			if err := volgen.BrickVolfileToFile(v, s.VolfileID, "brick", b.PeerID.String(), b.Path); err != nil {
+				logger.WithError(err).WithField("brick", b.Path).Error("failed to generate the brick volfile again")
+				continue
+			}
+			// the brick fetches the volfile again, if running
+			notify(s.VolfileID)
+		case api.DriftVolfileNotLoaded:
+			if !notify(s.VolfileID) {
+				continue
+			}
+		default:
+			continue
+		}
+		corrected = append(corrected, s)
+	}
+	return corrected, nil
+}
+
+// scan finds the drifts of the volumes on this peer, and saves those
+// confirmed in the store. If fix is set, the confirmed drifts are
+// corrected, and returned.
+func scan(fix bool) ([]api.Drift, error) {
+	scanLock.Lock()
+	defer scanLock.Unlock()
+
+	found, err := Find()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		drifts    []api.Drift
+		corrected []api.Drift
+		byVolume  = make(map[string][]*suspect)
+	)
+	for _, s := range track(found, time.Now()) {
+		if !fix || s.Kind == api.DriftBrickNotRunning || s.Kind == api.DriftCheckFailed {
+			drifts = append(drifts, s.Drift)
+			continue
+		}
+		byVolume[s.Volume] = append(byVolume[s.Volume], s)
+	}
+
+	for volname, volDrifts := range byVolume {
+		fixed, err := correct(volname, volDrifts)
+		if err != nil {
+			log.WithError(err).WithField("volume", volname).Error("failed to correct the drifts of the volume")
+		}
+		done := make(map[*suspect]bool)
+		for _, s := range fixed {
+			done[s] = true
+			delete(suspects, s.key())
+			events.Broadcast(events.New(EventDriftCorrected, eventData(s.Drift), true))
+			corrected = append(corrected, s.Drift)
+		}
+		for _, s := range volDrifts {
+			if !done[s] {
+				drifts = append(drifts, s.Drift)
+			}
+		}
+	}
+	if len(corrected) > 0 {
+		log.WithField("corrected", len(corrected)).Warn("corrected the drifts of the volumes")
+	}
+
+	return corrected, save(drifts)
+}
+
+// Reconcile corrects the drifts of the volumes on this peer, which were
+// found by the last scan and are still found. It returns the drifts
+// corrected.
+func Reconcile() ([]api.Drift, error) {
+	return scan(true)
+}
+
+// save saves the drifts of this peer in the store
+func save(drifts []api.Drift) error {
+	key := driftsPrefix + gdctx.MyUUID.String()
+	if len(drifts) == 0 {
+		_, err := store.Delete(context.TODO(), key)
+		return err
+	}
+
+	data, err := json.Marshal(drifts)
+	if err != nil {
+		return err
+	}
+	_, err = store.Put(context.TODO(), key, string(data))
+	return err
+}
+
+// List returns the drifts detected by all the peers
+func List() ([]api.Drift, error) {
+	resp, err := store.Get(context.TODO(), driftsPrefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	drifts := make([]api.Drift, 0)
+	for _, kv := range resp.Kvs {
+		var peerDrifts []api.Drift
+		if err := json.Unmarshal(kv.Value, &peerDrifts); err != nil {
+			return nil, err
+		}
+		drifts = append(drifts, peerDrifts...)
+	}
+	return drifts, nil
+}
@@ -0,0 +1,35 @@
+package drift
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrack(t *testing.T) {
+	suspects = make(map[string]*suspect)
+	first := time.Now()
+	stale := api.Drift{Volume: "testvol", Kind: api.DriftBrickVolfileStale, Brick: "/bricks/b1",
+		VolfileID: "testvol.id.bricks-b1", Expected: "aaaa", Actual: "bbbb"}
+	client := api.Drift{Volume: "testvol", Kind: api.DriftVolfileNotLoaded, VolfileID: "testvol",
+		Client: "10.0.0.1:1023", Expected: "cccc", Actual: "dddd"}
+
+	// drifts are only suspected on the first scan
+	assert.Empty(t, track([]api.Drift{stale}, first))
+
+	second := first.Add(time.Minute)
+	confirmed := track([]api.Drift{stale, client}, second)
+	assert.Len(t, confirmed, 1)
+	assert.Equal(t, api.DriftBrickVolfileStale, confirmed[0].Kind)
+	assert.Equal(t, first, confirmed[0].Since)
+
+	// a drift from another version of the volfile is suspected anew
+	stale.Expected = "eeee"
+	confirmed = track([]api.Drift{stale, client}, second.Add(time.Minute))
+	assert.Len(t, confirmed, 1)
+	assert.Equal(t, api.DriftVolfileNotLoaded, confirmed[0].Kind)
+	assert.Len(t, suspects, 2)
+}
@@ -0,0 +1,48 @@
+package drift
+
+import (
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/pborman/uuid"
+)
+
+const correctedTxnKey = "drift.corrected"
+
+// ReconcileStep returns a step having each of the given nodes correct the
+// drifts of its volumes
+func ReconcileStep(nodes []uuid.UUID) *transaction.Step {
+	return &transaction.Step{
+		DoFunc: "drift.Reconcile",
+		Nodes:  nodes,
+	}
+}
+
+// Corrected returns the drifts corrected by the nodes in the reconcile step
+func Corrected(c transaction.TxnCtx, nodes []uuid.UUID) []api.Drift {
+	corrected := make([]api.Drift, 0)
+	for _, node := range nodes {
+		var nodeCorrected []api.Drift
+		if err := c.GetNodeResult(node, correctedTxnKey, &nodeCorrected); err != nil {
+			continue
+		}
+		corrected = append(corrected, nodeCorrected...)
+	}
+	return corrected
+}
+
+func txnReconcile(c transaction.TxnCtx) error {
+	corrected, err := Reconcile()
+	if err != nil {
+		c.Logger().WithError(err).Error("failed to correct the drifts of the volumes")
+		return err
+	}
+	return c.SetNodeResult(gdctx.MyUUID, correctedTxnKey, corrected)
+}
+
+// RegisterStepFuncs registers the step function correcting the drifts of
+// the volumes
+func RegisterStepFuncs() {
+	transaction.RegisterStepFunc(txnReconcile, "drift.Reconcile")
+}
@@ -12,10 +12,18 @@ import (
 
 // New returns a new Event with given information
 // Set global to true if event should be broadast across cluster
+// The severity of the event is set to the default severity for its name,
+// use NewWithSeverity to override it.
 func New(name string, data map[string]string, global bool) *api.Event {
+	return NewWithSeverity(name, SeverityOf(name), data, global)
+}
+
+// NewWithSeverity returns a new Event with given information and severity
+func NewWithSeverity(name string, severity api.EventSeverity, data map[string]string, global bool) *api.Event {
 	return &api.Event{
 		ID:        uuid.NewRandom(),
 		Name:      strings.ToLower(name),
+		Severity:  severity,
 		Data:      data,
 		Global:    global,
 		Origin:    gdctx.MyUUID,
@@ -72,9 +72,12 @@ func globalListener(glStop chan struct{}) {
 
 	// Watch for new events being added to store
 	wch := store.Store.Watch(store.Store.Ctx(), eventsPrefix, clientv3.WithPrefix(), clientv3.WithFilterDelete())
+	w := store.TrackWatch("global-events", eventsPrefix)
+	defer w.Stop()
 	for {
 		select {
 		case resp := <-wch:
+			w.Observe(resp)
 			if resp.Canceled {
 				return
 			}
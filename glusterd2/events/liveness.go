@@ -29,9 +29,12 @@ func (l *livenessWatcher) Watch() {
 	defer l.wg.Done()
 	wch := store.Store.Watch(store.Store.Ctx(), store.LivenessKeyPrefix,
 		clientv3.WithPrefix(), clientv3.WithKeysOnly())
+	w := store.TrackWatch("peer-liveness", store.LivenessKeyPrefix)
+	defer w.Stop()
 	for {
 		select {
 		case resp := <-wch:
+			w.Observe(resp)
 			if resp.Canceled {
 				return
 			}
@@ -0,0 +1,65 @@
+package events
+
+import (
+	"strings"
+
+	"github.com/gluster/glusterd2/pkg/api"
+)
+
+// criticalEvents are events which indicate loss of availability or data
+// integrity
+var criticalEvents = map[string]bool{
+	"brick_disconnected":             true,
+	"posix_health_check_failed":      true,
+	"brick_io_error_budget_exceeded": true,
+	"quorum_lost":                    true,
+	"afr_quorum_fail":                true,
+	"afr_subvols_down":               true,
+	"afr_split_brain":                true,
+	"ec_min_bricks_not_up":           true,
+	"bitrot_bad_file":                true,
+	"georep_faulty":                  true,
+	"daemon.startallfailed":          true,
+	eventPeerDisconnectedStore:       true,
+	"peer.offline":                   true,
+	"volume.usage.critical":          true,
+}
+
+// warningEvents are events which need attention but are not critical, in
+// addition to all the failure and disconnect events
+var warningEvents = map[string]bool{
+	"quota_crossed_soft_limit":   true,
+	"client_auth_reject":         true,
+	"peer_reject":                true,
+	"unknown_peer":               true,
+	"volume.usage.warning":       true,
+	"peer.degraded":              true,
+	"peer.clock.skewed":          true,
+	"daemon.restartlimitreached": true,
+	"mount.remounted":            true,
+	"volume.throttle.backoff":    true,
+	"volume.brick.cleaned":       true,
+}
+
+// SeverityOf returns the default severity of the event with given name
+func SeverityOf(name string) api.EventSeverity {
+	name = strings.ToLower(name)
+	switch {
+	case criticalEvents[name]:
+		return api.SeverityCritical
+	case warningEvents[name],
+		strings.Contains(name, "fail"),
+		strings.Contains(name, "disconnect"):
+		return api.SeverityWarning
+	}
+	return api.SeverityInfo
+}
+
+// ValidSeverity returns true if s is one of the known severities
+func ValidSeverity(s api.EventSeverity) bool {
+	switch s {
+	case api.SeverityInfo, api.SeverityWarning, api.SeverityCritical:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,27 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeverityOf(t *testing.T) {
+	assert.Equal(t, api.SeverityInfo, SeverityOf("volume.started"))
+	assert.Equal(t, api.SeverityWarning, SeverityOf("daemon.startfailed"))
+	assert.Equal(t, api.SeverityWarning, SeverityOf("CLIENT_DISCONNECT"))
+	assert.Equal(t, api.SeverityWarning, SeverityOf("quota_crossed_soft_limit"))
+	assert.Equal(t, api.SeverityCritical, SeverityOf("BRICK_DISCONNECTED"))
+	assert.Equal(t, api.SeverityCritical, SeverityOf(eventPeerDisconnectedStore))
+}
+
+func TestNewSetsSeverity(t *testing.T) {
+	e := New("QUORUM_LOST", nil, false)
+	assert.Equal(t, "quorum_lost", e.Name)
+	assert.Equal(t, api.SeverityCritical, e.Severity)
+
+	e = NewWithSeverity("heal.backlog", api.SeverityWarning, nil, false)
+	assert.Equal(t, api.SeverityWarning, e.Severity)
+}
@@ -0,0 +1,227 @@
+package events
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log/syslog"
+	"net"
+	"net/smtp"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/gluster/glusterd2/pkg/api"
+	eventsapi "github.com/gluster/glusterd2/plugins/events/api"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	defaultSMTPSubject  = "[gluster] [{{.Severity}}] {{.Name}}"
+	defaultSMTPTemplate = `Event: {{.Name}}
+Severity: {{.Severity}}
+ID: {{.ID}}
+Origin: {{.Origin}}
+Time: {{.Timestamp}}
+{{range $k, $v := .Data}}
+{{$k}}: {{$v}}{{end}}
+`
+	defaultSyslogTag = "glusterd2"
+)
+
+var syslogFacilities = map[string]syslog.Priority{
+	"kern":     syslog.LOG_KERN,
+	"user":     syslog.LOG_USER,
+	"mail":     syslog.LOG_MAIL,
+	"daemon":   syslog.LOG_DAEMON,
+	"auth":     syslog.LOG_AUTH,
+	"syslog":   syslog.LOG_SYSLOG,
+	"authpriv": syslog.LOG_AUTHPRIV,
+	"local0":   syslog.LOG_LOCAL0,
+	"local1":   syslog.LOG_LOCAL1,
+	"local2":   syslog.LOG_LOCAL2,
+	"local3":   syslog.LOG_LOCAL3,
+	"local4":   syslog.LOG_LOCAL4,
+	"local5":   syslog.LOG_LOCAL5,
+	"local6":   syslog.LOG_LOCAL6,
+	"local7":   syslog.LOG_LOCAL7,
+}
+
+func smtpTemplates(sink *eventsapi.SMTPSink) (*template.Template, *template.Template, error) {
+	subject, body := sink.Subject, sink.Template
+	if subject == "" {
+		subject = defaultSMTPSubject
+	}
+	if body == "" {
+		body = defaultSMTPTemplate
+	}
+
+	st, err := template.New("subject").Parse(subject)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid subject template: %s", err)
+	}
+	bt, err := template.New("body").Parse(body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid body template: %s", err)
+	}
+	return st, bt, nil
+}
+
+// validateSinkName checks that the name of a sink can be used as the last
+// element of its key in the store
+func validateSinkName(name string) error {
+	if name == "" {
+		return errors.New("sink name is required field")
+	}
+	if strings.Contains(name, "/") {
+		return errors.New("sink name must not contain '/'")
+	}
+	return nil
+}
+
+// smtpPassword reads the SMTP password of the sink from its password file
+func smtpPassword(sink *eventsapi.SMTPSink) (string, error) {
+	data, err := ioutil.ReadFile(sink.PasswordFile)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// ValidateSMTPSink checks that all the required fields of a SMTP sink are
+// set and that its templates can be parsed
+func ValidateSMTPSink(sink *eventsapi.SMTPSink) error {
+	if err := validateSinkName(sink.Name); err != nil {
+		return err
+	}
+	if _, _, err := net.SplitHostPort(sink.Server); err != nil {
+		return fmt.Errorf("invalid SMTP server address: %s", err)
+	}
+	if sink.From == "" || len(sink.To) == 0 {
+		return errors.New("sender and recipients are required fields")
+	}
+	if sink.PasswordFile != "" && (sink.Username == "" || !filepath.IsAbs(sink.PasswordFile)) {
+		return errors.New("password file must be an absolute path and requires a username")
+	}
+	if err := validateSeverities(sink.Severities); err != nil {
+		return err
+	}
+	_, _, err := smtpTemplates(sink)
+	return err
+}
+
+func validateSeverities(severities []api.EventSeverity) error {
+	for _, s := range severities {
+		if !ValidSeverity(s) {
+			return fmt.Errorf("unknown severity %s", s)
+		}
+	}
+	return nil
+}
+
+// ValidateSyslogSink checks that all the required fields of a syslog sink
+// are set and valid
+func ValidateSyslogSink(sink *eventsapi.SyslogSink) error {
+	if err := validateSinkName(sink.Name); err != nil {
+		return err
+	}
+	switch sink.Network {
+	case "":
+		if sink.Address != "" {
+			return errors.New("network is required if address is set")
+		}
+	case "udp", "tcp":
+		if _, _, err := net.SplitHostPort(sink.Address); err != nil {
+			return fmt.Errorf("invalid syslog address: %s", err)
+		}
+	default:
+		return fmt.Errorf("unsupported syslog network %s", sink.Network)
+	}
+	if _, ok := syslogFacilities[strings.ToLower(sink.Facility)]; !ok && sink.Facility != "" {
+		return fmt.Errorf("unknown syslog facility %s", sink.Facility)
+	}
+	return validateSeverities(sink.Severities)
+}
+
+// SMTPPublish mails the event to the recipients of the given SMTP sink
+func SMTPPublish(sink *eventsapi.SMTPSink, e *api.Event) error {
+	st, bt, err := smtpTemplates(sink)
+	if err != nil {
+		return err
+	}
+
+	var subject, body bytes.Buffer
+	if err := st.Execute(&subject, e); err != nil {
+		log.WithError(err).WithField("name", e.Name).Error("failed to execute subject template")
+		return err
+	}
+	if err := bt.Execute(&body, e); err != nil {
+		log.WithError(err).WithField("name", e.Name).Error("failed to execute body template")
+		return err
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", sink.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(sink.To, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", strings.TrimSpace(subject.String()))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	msg.Write(body.Bytes())
+
+	var auth smtp.Auth
+	if sink.Username != "" {
+		var password string
+		if sink.PasswordFile != "" {
+			if password, err = smtpPassword(sink); err != nil {
+				log.WithError(err).WithField("file", sink.PasswordFile).Error("failed to read SMTP password")
+				return err
+			}
+		}
+		host, _, _ := net.SplitHostPort(sink.Server)
+		auth = smtp.PlainAuth("", sink.Username, password, host)
+	}
+
+	if err := smtp.SendMail(sink.Server, auth, sink.From, sink.To, msg.Bytes()); err != nil {
+		log.WithError(err).WithField("server", sink.Server).Error("failed to send event mail")
+		return err
+	}
+	return nil
+}
+
+// SyslogPublish forwards the event to syslog as a JSON encoded message
+func SyslogPublish(sink *eventsapi.SyslogSink, e *api.Event) error {
+	message, err := json.Marshal(e)
+	if err != nil {
+		log.WithError(err).WithField("name", e.Name).Error("failed to marshal event")
+		return err
+	}
+
+	facility, ok := syslogFacilities[strings.ToLower(sink.Facility)]
+	if !ok {
+		facility = syslog.LOG_DAEMON
+	}
+	tag := sink.Tag
+	if tag == "" {
+		tag = defaultSyslogTag
+	}
+
+	w, err := syslog.Dial(sink.Network, sink.Address, facility|syslog.LOG_INFO, tag)
+	if err != nil {
+		log.WithError(err).WithField("address", sink.Address).Error("error while connecting to syslog")
+		return err
+	}
+	defer w.Close()
+
+	switch e.Severity {
+	case api.SeverityCritical:
+		return w.Crit(string(message))
+	case api.SeverityWarning:
+		return w.Warning(string(message))
+	}
+	return w.Info(string(message))
+}
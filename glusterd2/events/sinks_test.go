@@ -0,0 +1,78 @@
+package events
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	eventsapi "github.com/gluster/glusterd2/plugins/events/api"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateSMTPSink(t *testing.T) {
+	sink := &eventsapi.SMTPSink{
+		Name:   "admins",
+		Server: "mail.example.com:25",
+		From:   "gluster@example.com",
+		To:     []string{"admin@example.com"},
+	}
+	assert.Nil(t, ValidateSMTPSink(sink))
+
+	sink.Server = "mail.example.com"
+	assert.NotNil(t, ValidateSMTPSink(sink))
+
+	sink.Server = "mail.example.com:25"
+	sink.Template = "{{.Name"
+	assert.NotNil(t, ValidateSMTPSink(sink))
+
+	sink.Template = ""
+	sink.To = nil
+	assert.NotNil(t, ValidateSMTPSink(sink))
+
+	// The name is the last element of the key of the sink in the store
+	sink.To = []string{"admin@example.com"}
+	sink.Name = "ad/mins"
+	assert.NotNil(t, ValidateSMTPSink(sink))
+
+	sink.Name = "admins"
+	sink.PasswordFile = "smtp-password"
+	assert.NotNil(t, ValidateSMTPSink(sink))
+	sink.Username = "gluster"
+	assert.NotNil(t, ValidateSMTPSink(sink))
+	sink.PasswordFile = "/etc/glusterd2/smtp-password"
+	assert.Nil(t, ValidateSMTPSink(sink))
+}
+
+func TestSMTPPassword(t *testing.T) {
+	f, err := ioutil.TempFile("", "smtp-password")
+	require.NoError(t, err)
+	defer os.Remove(f.Name())
+	_, err = f.WriteString("s3cret\n")
+	require.NoError(t, err)
+	f.Close()
+
+	password, err := smtpPassword(&eventsapi.SMTPSink{PasswordFile: f.Name()})
+	require.NoError(t, err)
+	assert.Equal(t, "s3cret", password)
+
+	_, err = smtpPassword(&eventsapi.SMTPSink{PasswordFile: f.Name() + ".missing"})
+	assert.NotNil(t, err)
+}
+
+func TestValidateSyslogSink(t *testing.T) {
+	assert.Nil(t, ValidateSyslogSink(&eventsapi.SyslogSink{Name: "local"}))
+	assert.Nil(t, ValidateSyslogSink(&eventsapi.SyslogSink{
+		Name:     "remote",
+		Network:  "udp",
+		Address:  "syslog.example.com:514",
+		Facility: "local3",
+	}))
+
+	assert.NotNil(t, ValidateSyslogSink(&eventsapi.SyslogSink{}))
+	assert.NotNil(t, ValidateSyslogSink(&eventsapi.SyslogSink{Name: "a/b"}))
+	assert.NotNil(t, ValidateSyslogSink(&eventsapi.SyslogSink{Name: "s", Address: "x:514"}))
+	assert.NotNil(t, ValidateSyslogSink(&eventsapi.SyslogSink{Name: "s", Network: "unix"}))
+	assert.NotNil(t, ValidateSyslogSink(&eventsapi.SyslogSink{Name: "s", Facility: "bogus"}))
+}
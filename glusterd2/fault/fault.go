@@ -0,0 +1,248 @@
+// Package fault injects faults in the transaction steps, the store writes
+// and the RPCs to the other peers, for the rollback and recovery of
+// glusterd2 to be tested deterministically. The faults are injected only if
+// glusterd2 is started with fault injection enabled.
+package fault
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+	flag "github.com/spf13/pflag"
+	config "github.com/spf13/viper"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	enabledOpt = "fault-injection"
+
+	// The points faults are injected at
+	PointStep       = "step"
+	PointStoreWrite = "store-write"
+	PointRPC        = "rpc"
+
+	// The actions of the faults
+	ActionFail  = "fail"
+	ActionDelay = "delay"
+	ActionDrop  = "drop"
+)
+
+var (
+	// ErrInjected is returned by the steps and store writes failed by a
+	// fault
+	ErrInjected = errors.New("injected fault")
+	// ErrRPCKilled is returned by the RPCs failed by a fault. Like the
+	// RPCs to an unreachable peer, it is ignored by transactions with
+	// DontCheckAlive set.
+	ErrRPCKilled = status.Error(codes.Unavailable, "injected RPC connection kill")
+	// ErrNotFound is returned when the fault to remove does not exist
+	ErrNotFound = errors.New("fault not found")
+	// ErrDisabled is returned when faults are injected while fault
+	// injection is disabled
+	ErrDisabled = errors.New("fault injection is disabled, start glusterd2 with fault-injection set")
+)
+
+// faults are the faults injected on this node, in the order they were added
+var faults struct {
+	sync.Mutex
+	list []*api.Fault
+}
+
+// InitFlags intializes the command line options for fault injection
+func InitFlags() {
+	flag.Bool(enabledOpt, false, "Enable the injection of faults in the transactions, the store writes and the peer RPCs with the /debug/faults endpoints. For testing only.")
+}
+
+// Enabled returns true if faults may be injected
+func Enabled() bool {
+	return config.GetBool(enabledOpt)
+}
+
+// validate returns an error if the fault requested is not valid
+func validate(req *api.FaultReq) error {
+	if req.Count < 0 {
+		return errors.New("count can not be negative")
+	}
+	switch req.Action {
+	case ActionFail, ActionDrop:
+	case ActionDelay:
+		if req.DelayMs <= 0 {
+			return errors.New("delay faults need a positive delay-ms")
+		}
+	default:
+		return fmt.Errorf("unknown action %q", req.Action)
+	}
+	if req.Action == ActionDrop && req.Point != PointStoreWrite {
+		return errors.New("only store writes can be dropped")
+	}
+	if req.After && (req.Point != PointStep || req.Action != ActionFail) {
+		return errors.New("only step failures can be injected after the step")
+	}
+
+	switch req.Point {
+	case PointStep:
+		if req.Step == "" {
+			return errors.New("step faults need the name of the step")
+		}
+	case PointStoreWrite:
+		if req.Key == "" {
+			return errors.New("store-write faults need a key prefix")
+		}
+	case PointRPC:
+		if uuid.Parse(req.Peer) == nil {
+			return errors.New("rpc faults need the ID of the peer")
+		}
+	default:
+		return fmt.Errorf("unknown injection point %q", req.Point)
+	}
+	return nil
+}
+
+// Add injects a fault on this node, and returns it
+func Add(req api.FaultReq) (api.Fault, error) {
+	if !Enabled() {
+		return api.Fault{}, ErrDisabled
+	}
+	if err := validate(&req); err != nil {
+		return api.Fault{}, err
+	}
+
+	f := &api.Fault{FaultReq: req, ID: uuid.NewRandom().String()}
+
+	faults.Lock()
+	faults.list = append(faults.list, f)
+	faults.Unlock()
+
+	log.WithFields(log.Fields{
+		"id":     f.ID,
+		"point":  f.Point,
+		"action": f.Action,
+	}).Warn("fault injected")
+	return *f, nil
+}
+
+// List returns the faults injected on this node
+func List() []api.Fault {
+	faults.Lock()
+	defer faults.Unlock()
+
+	list := make([]api.Fault, 0, len(faults.list))
+	for _, f := range faults.list {
+		list = append(list, *f)
+	}
+	return list
+}
+
+// Remove removes the fault of the given ID
+func Remove(id string) error {
+	faults.Lock()
+	defer faults.Unlock()
+
+	for i, f := range faults.list {
+		if f.ID == id {
+			faults.list = append(faults.list[:i], faults.list[i+1:]...)
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+// Clear removes all the faults injected on this node
+func Clear() {
+	faults.Lock()
+	defer faults.Unlock()
+	faults.list = nil
+}
+
+// match returns the first fault of the point for which matches returns
+// true, and which was injected less than its count, counting it as injected
+func match(point string, matches func(f *api.Fault) bool) (api.Fault, bool) {
+	faults.Lock()
+	defer faults.Unlock()
+
+	for _, f := range faults.list {
+		if f.Point != point || !matches(f) {
+			continue
+		}
+		if f.Count != 0 && f.Injected >= f.Count {
+			continue
+		}
+		f.Injected++
+		return *f, true
+	}
+	return api.Fault{}, false
+}
+
+func delay(f api.Fault) {
+	time.Sleep(time.Duration(f.DelayMs) * time.Millisecond)
+}
+
+// Step injects the fault set for the step function of the given name. Delay
+// faults delay the step. The error to fail the step with before it is run,
+// or after it has run, is returned.
+func Step(name string) (before error, after error) {
+	f, ok := match(PointStep, func(f *api.Fault) bool {
+		return f.Step == name
+	})
+	if !ok {
+		return nil, nil
+	}
+
+	switch {
+	case f.Action == ActionDelay:
+		delay(f)
+		return nil, nil
+	case f.After:
+		return nil, ErrInjected
+	default:
+		return ErrInjected, nil
+	}
+}
+
+// StoreWrite injects the fault set for the writes to the key. Delay faults
+// delay the write. It returns true if the write is to be dropped, and the
+// error to fail it with.
+func StoreWrite(key string) (drop bool, err error) {
+	f, ok := match(PointStoreWrite, func(f *api.Fault) bool {
+		return strings.HasPrefix(key, f.Key)
+	})
+	if !ok {
+		return false, nil
+	}
+
+	switch f.Action {
+	case ActionDelay:
+		delay(f)
+		return false, nil
+	case ActionDrop:
+		return true, nil
+	default:
+		return false, ErrInjected
+	}
+}
+
+// RPC injects the fault set for the RPCs to the peer of the given ID. Delay
+// faults delay the RPC. ErrRPCKilled is returned if the connection to the
+// peer is to be killed.
+func RPC(peerID string) error {
+	f, ok := match(PointRPC, func(f *api.Fault) bool {
+		return uuid.Equal(uuid.Parse(f.Peer), uuid.Parse(peerID))
+	})
+	if !ok {
+		return nil
+	}
+
+	if f.Action == ActionDelay {
+		delay(f)
+		return nil
+	}
+	return ErrRPCKilled
+}
@@ -0,0 +1,99 @@
+package fault
+
+import (
+	"testing"
+
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/pborman/uuid"
+	config "github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdd(t *testing.T) {
+	defer Clear()
+
+	config.Set(enabledOpt, false)
+	_, err := Add(api.FaultReq{Point: PointStep, Action: ActionFail, Step: "vol-create.Store"})
+	assert.Equal(t, ErrDisabled, err)
+
+	config.Set(enabledOpt, true)
+	defer config.Set(enabledOpt, false)
+
+	for _, req := range []api.FaultReq{
+		{Point: "brick", Action: ActionFail},
+		{Point: PointStep, Action: "crash", Step: "vol-create.Store"},
+		{Point: PointStep, Action: ActionFail},
+		{Point: PointStep, Action: ActionDrop, Step: "vol-create.Store"},
+		{Point: PointStep, Action: ActionDelay, Step: "vol-create.Store"},
+		{Point: PointStoreWrite, Action: ActionFail},
+		{Point: PointStoreWrite, Action: ActionFail, Key: "volumes/", After: true},
+		{Point: PointRPC, Action: ActionFail, Peer: "node2"},
+		{Point: PointStep, Action: ActionFail, Step: "vol-create.Store", Count: -1},
+	} {
+		_, err := Add(req)
+		assert.Error(t, err, "%+v", req)
+	}
+
+	f, err := Add(api.FaultReq{Point: PointStep, Action: ActionFail, Step: "vol-create.Store"})
+	require.NoError(t, err)
+	assert.NotEmpty(t, f.ID)
+	assert.Len(t, List(), 1)
+
+	assert.Equal(t, ErrNotFound, Remove("nonexistent"))
+	assert.NoError(t, Remove(f.ID))
+	assert.Empty(t, List())
+}
+
+func TestInject(t *testing.T) {
+	config.Set(enabledOpt, true)
+	defer config.Set(enabledOpt, false)
+	defer Clear()
+
+	peerID := uuid.NewRandom().String()
+	for _, req := range []api.FaultReq{
+		{Point: PointStep, Action: ActionFail, Step: "vol-create.Store", Count: 1},
+		{Point: PointStep, Action: ActionFail, Step: "vol-start.StartBricks", After: true},
+		{Point: PointStoreWrite, Action: ActionDrop, Key: "volumes/"},
+		{Point: PointStoreWrite, Action: ActionFail, Key: "peers/"},
+		{Point: PointRPC, Action: ActionFail, Peer: peerID, Count: 2},
+	} {
+		_, err := Add(req)
+		require.NoError(t, err)
+	}
+
+	// The fault is injected only once
+	before, after := Step("vol-create.Store")
+	assert.Equal(t, ErrInjected, before)
+	assert.NoError(t, after)
+	before, after = Step("vol-create.Store")
+	assert.NoError(t, before)
+	assert.NoError(t, after)
+
+	before, after = Step("vol-start.StartBricks")
+	assert.NoError(t, before)
+	assert.Equal(t, ErrInjected, after)
+
+	drop, err := StoreWrite("volumes/gv0")
+	assert.True(t, drop)
+	assert.NoError(t, err)
+	drop, err = StoreWrite("peers/" + peerID)
+	assert.False(t, drop)
+	assert.Equal(t, ErrInjected, err)
+	drop, err = StoreWrite("snaps/s1")
+	assert.False(t, drop)
+	assert.NoError(t, err)
+
+	assert.Equal(t, ErrRPCKilled, RPC(peerID))
+	assert.Equal(t, ErrRPCKilled, RPC(peerID))
+	assert.NoError(t, RPC(peerID))
+	assert.NoError(t, RPC(uuid.NewRandom().String()))
+
+	injected := make(map[string]int)
+	for _, f := range List() {
+		injected[f.Point+f.Step+f.Key] = f.Injected
+	}
+	assert.Equal(t, 1, injected[PointStep+"vol-create.Store"])
+	assert.Equal(t, 2, injected[PointRPC])
+}
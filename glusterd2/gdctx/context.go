@@ -2,6 +2,7 @@ package gdctx
 
 import (
 	"context"
+	"time"
 
 	"github.com/pborman/uuid"
 	log "github.com/sirupsen/logrus"
@@ -12,6 +13,9 @@ type ctxKeyType int
 const (
 	reqIDKey ctxKeyType = iota
 	reqLoggerKey
+	reqUserKey
+	reqNamespaceKey
+	reqPreconditionKey
 )
 
 // WithReqID returns a new context with provided request id set as a value in the context.
@@ -41,3 +45,74 @@ func GetReqLogger(ctx context.Context) log.FieldLogger {
 	}
 	return reqLogger
 }
+
+// WithReqUser returns a new context with the authenticated user set as a value in the context.
+func WithReqUser(ctx context.Context, user string) context.Context {
+	return context.WithValue(ctx, reqUserKey, user)
+}
+
+// GetReqUser returns the authenticated user stored in the context provided.
+func GetReqUser(ctx context.Context) string {
+	user, ok := ctx.Value(reqUserKey).(string)
+	if !ok {
+		return ""
+	}
+	return user
+}
+
+// WithReqNamespace returns a new context with the namespace the request is
+// scoped to set as a value in the context.
+func WithReqNamespace(ctx context.Context, namespace string) context.Context {
+	return context.WithValue(ctx, reqNamespaceKey, namespace)
+}
+
+// GetReqNamespace returns the namespace the request is scoped to, empty if
+// the request is not scoped to a namespace.
+func GetReqNamespace(ctx context.Context) string {
+	namespace, ok := ctx.Value(reqNamespaceKey).(string)
+	if !ok {
+		return ""
+	}
+	return namespace
+}
+
+// Precondition is the revisions of the store one of which the resource a
+// request changes must be at, given by the If-Match header of the request
+type Precondition struct {
+	// Key is the key of the resource in the store
+	Key       string
+	Revisions []int64
+}
+
+// WithReqPrecondition returns a new context with the precondition of the
+// request set as a value in the context.
+func WithReqPrecondition(ctx context.Context, p Precondition) context.Context {
+	return context.WithValue(ctx, reqPreconditionKey, p)
+}
+
+// GetReqPrecondition returns the precondition of the request, if it has one.
+func GetReqPrecondition(ctx context.Context) (Precondition, bool) {
+	p, ok := ctx.Value(reqPreconditionKey).(Precondition)
+	return p, ok
+}
+
+// valuesContext carries the values of its parent context, but is never
+// cancelled and has no deadline
+type valuesContext struct {
+	context.Context
+}
+
+func (valuesContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (valuesContext) Done() <-chan struct{}       { return nil }
+func (valuesContext) Err() error                  { return nil }
+
+// WithoutCancel returns a context carrying the values of ctx, such as the
+// request ID, the request logger and the trace span, which is not cancelled
+// when ctx is. It is used for the work which must not be interrupted half
+// way when the client of a request goes away.
+func WithoutCancel(ctx context.Context) context.Context {
+	if ctx == nil {
+		return context.Background()
+	}
+	return valuesContext{ctx}
+}
@@ -30,3 +30,18 @@ func TestWithReqLogger(t *testing.T) {
 	assert.NotNil(t, newlog)
 
 }
+
+func TestWithoutCancel(t *testing.T) {
+	reqID := uuid.NewRandom()
+	parent, cancel := context.WithCancel(WithReqID(context.Background(), reqID))
+
+	ctx := WithoutCancel(parent)
+	cancel()
+
+	assert.Error(t, parent.Err())
+	assert.NoError(t, ctx.Err())
+	assert.Nil(t, ctx.Done())
+	_, ok := ctx.Deadline()
+	assert.False(t, ok)
+	assert.Equal(t, reqID, GetReqID(ctx))
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/gluster/glusterd2/version"
 
 	config "github.com/spf13/viper"
+	"github.com/thejerf/suture"
 )
 
 var (
@@ -34,6 +35,9 @@ var (
 	LocalAuthToken     string
 	RESTAPIAuthEnabled = false
 	IsTerminating      bool
+	// Supervisor is the root of the supervisor tree managing the
+	// services of GlusterD
+	Supervisor *suture.Supervisor
 )
 
 // SetHostnameAndIP will initialize HostIP and HostName global variables
@@ -0,0 +1,181 @@
+// Package halo supports halo replication, where the clients of a replicate
+// volume write only to the replicas within a maximum latency of them, and
+// measures the latency between the peers to report which replicas are local
+// to the clients of each zone.
+package halo
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/options"
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/glusterd2/xlator"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/pborman/uuid"
+)
+
+// Halo options of the replicate xlator
+const (
+	OptEnabled     = "halo-enabled"
+	OptMaxLatency  = "halo-max-latency"
+	OptMinReplicas = "halo-min-replicas"
+	OptMaxReplicas = "halo-max-replicas"
+)
+
+// defaults are the defaults of the halo options of the replicate xlator, used
+// when it is not loaded
+var defaults = map[string]string{
+	OptEnabled:     "off",
+	OptMaxLatency:  "5",
+	OptMinReplicas: "2",
+	OptMaxReplicas: "99999",
+}
+
+// Option returns the value of the halo option set on the volume for its
+// clients, or its default value
+func Option(v *volume.Volinfo, key string) string {
+	for _, k := range []string{"client.cluster/replicate." + key, "cluster/replicate." + key} {
+		if value, ok := v.Options[k]; ok {
+			return value
+		}
+	}
+	if o, err := xlator.FindOption("replicate." + key); err == nil {
+		return o.DefaultValue
+	}
+	return defaults[key]
+}
+
+func intOption(v *volume.Volinfo, key string) int {
+	n, err := strconv.Atoi(Option(v, key))
+	if err != nil {
+		n, _ = strconv.Atoi(defaults[key])
+	}
+	return n
+}
+
+// zoneOf returns the zone of the peer, which defaults to the peer itself
+func zoneOf(p *peer.Peer) string {
+	if zone, ok := p.Metadata["_zone"]; ok && zone != "" {
+		return zone
+	}
+	return p.ID.String()
+}
+
+// zoneLatency returns the lowest latency measured from the peers of the zone
+// to the peer, which is 0 for a peer of the zone
+func zoneLatency(zonePeers []uuid.UUID, to uuid.UUID, all map[string]latencies) (time.Duration, bool) {
+	var best time.Duration
+	found := false
+	for _, p := range zonePeers {
+		if uuid.Equal(p, to) {
+			return 0, true
+		}
+		d, ok := all[p.String()].Peers[to.String()]
+		if !ok {
+			continue
+		}
+		if !found || d < best {
+			best, found = d, true
+		}
+	}
+	return best, found
+}
+
+// selectBricks marks the bricks local to the zone, and those halo
+// replication writes to: the local ones up to max replicas, the fastest of
+// the others making up for min replicas
+func selectBricks(bricks []api.HaloBrick, maxLatency time.Duration, minReplicas, maxReplicas int) {
+	order := make([]int, len(bricks))
+	for i := range order {
+		order[i] = i
+	}
+	// Bricks whose latency is unknown come last
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := bricks[order[i]].LatencyMs, bricks[order[j]].LatencyMs
+		if a < 0 || b < 0 {
+			return b < 0 && a >= 0
+		}
+		return a < b
+	})
+
+	active := 0
+	for _, i := range order {
+		b := &bricks[i]
+		b.Local = b.LatencyMs >= 0 && time.Duration(b.LatencyMs*float64(time.Millisecond)) <= maxLatency
+		if active >= maxReplicas {
+			continue
+		}
+		if b.Local || active < minReplicas {
+			b.Active = true
+			active++
+		}
+	}
+}
+
+// Report returns the replicas of the volume local to the clients of each zone
+// of peers, as per the latencies measured between the peers
+func Report(v *volume.Volinfo) (*api.VolumeHaloResp, error) {
+	enabled, _ := options.StringToBoolean(Option(v, OptEnabled))
+	resp := &api.VolumeHaloResp{
+		Enabled:      enabled,
+		MaxLatencyMs: intOption(v, OptMaxLatency),
+		MinReplicas:  intOption(v, OptMinReplicas),
+		MaxReplicas:  intOption(v, OptMaxReplicas),
+	}
+	maxLatency := time.Duration(resp.MaxLatencyMs) * time.Millisecond
+
+	peers, err := peer.GetPeersF()
+	if err != nil {
+		return nil, err
+	}
+	all, err := getLatencies()
+	if err != nil {
+		return nil, err
+	}
+
+	zones := make(map[string][]uuid.UUID)
+	for _, p := range peers {
+		zone := zoneOf(p)
+		zones[zone] = append(zones[zone], p.ID)
+	}
+	names := make([]string, 0, len(zones))
+	for zone := range zones {
+		names = append(names, zone)
+	}
+	sort.Strings(names)
+
+	for _, zone := range names {
+		z := api.HaloZone{
+			Zone:  zone,
+			Peers: zones[zone],
+		}
+		for _, sv := range v.Subvols {
+			hs := api.HaloSubvol{Name: sv.Name}
+			for _, b := range sv.Bricks {
+				hs.Bricks = append(hs.Bricks, haloBrick(b, zones[zone], all))
+			}
+			selectBricks(hs.Bricks, maxLatency, resp.MinReplicas, resp.MaxReplicas)
+			z.Subvols = append(z.Subvols, hs)
+		}
+		resp.Zones = append(resp.Zones, z)
+	}
+	return resp, nil
+}
+
+func haloBrick(b brick.Brickinfo, zonePeers []uuid.UUID, all map[string]latencies) api.HaloBrick {
+	hb := api.HaloBrick{
+		ID:        b.ID,
+		PeerID:    b.PeerID,
+		Path:      b.Path,
+		LatencyMs: -1,
+	}
+	if d, ok := zoneLatency(zonePeers, b.PeerID, all); ok {
+		hb.LatencyMs = float64(d/time.Microsecond) / 1000
+	}
+	return hb
+}
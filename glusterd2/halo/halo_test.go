@@ -0,0 +1,56 @@
+package halo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestZoneLatency(t *testing.T) {
+	p1, p2, p3 := uuid.NewRandom(), uuid.NewRandom(), uuid.NewRandom()
+	all := map[string]latencies{
+		p1.String(): {Peers: map[string]time.Duration{p3.String(): 8 * time.Millisecond}},
+		p2.String(): {Peers: map[string]time.Duration{p3.String(): 3 * time.Millisecond}},
+	}
+
+	d, ok := zoneLatency([]uuid.UUID{p1, p2}, p1, all)
+	assert.True(t, ok)
+	assert.Equal(t, time.Duration(0), d)
+
+	d, ok = zoneLatency([]uuid.UUID{p1, p2}, p3, all)
+	assert.True(t, ok)
+	assert.Equal(t, 3*time.Millisecond, d)
+
+	_, ok = zoneLatency([]uuid.UUID{p3}, p1, all)
+	assert.False(t, ok)
+}
+
+func TestSelectBricks(t *testing.T) {
+	bricks := []api.HaloBrick{
+		{Path: "/b0", LatencyMs: 20},
+		{Path: "/b1", LatencyMs: -1},
+		{Path: "/b2", LatencyMs: 1},
+	}
+
+	selectBricks(bricks, 5*time.Millisecond, 2, 3)
+	assert.True(t, bricks[2].Local)
+	assert.True(t, bricks[2].Active)
+	// the fastest remote brick makes up for the min replicas
+	assert.False(t, bricks[0].Local)
+	assert.True(t, bricks[0].Active)
+	assert.False(t, bricks[1].Local)
+	assert.False(t, bricks[1].Active)
+
+	for i := range bricks {
+		bricks[i].Active = false
+	}
+	bricks[0].LatencyMs = 2
+	selectBricks(bricks, 5*time.Millisecond, 1, 1)
+	assert.True(t, bricks[0].Local)
+	assert.True(t, bricks[2].Active)
+	assert.False(t, bricks[0].Active)
+}
@@ -0,0 +1,147 @@
+package halo
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/glusterd2/transactionv2"
+	"github.com/gluster/glusterd2/pkg/utils"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+	flag "github.com/spf13/pflag"
+	config "github.com/spf13/viper"
+)
+
+const (
+	probeIntervalOpt = "halo-probe-interval"
+
+	// latencyPrefix is where each peer saves the latencies it measured to
+	// the other peers, under its ID
+	latencyPrefix = "halo/latency/"
+
+	probeSamples = 3
+	probeTimeout = 2 * time.Second
+)
+
+var (
+	stopChan chan struct{}
+	stopOnce sync.Once
+)
+
+// latencies are the round-trip latencies measured by a peer to the other
+// peers, by peer ID
+type latencies struct {
+	MeasuredAt time.Time                `json:"measured-at"`
+	Peers      map[string]time.Duration `json:"peers"`
+}
+
+// InitFlags intializes the command line options for the latency probing
+func InitFlags() {
+	flag.Duration(probeIntervalOpt, 30*time.Second, "Interval at which the latency to the other peers is measured, for halo replication. Set to 0 to disable.")
+}
+
+// Start starts measuring the latency to the other peers periodically
+func Start() {
+	interval := config.GetDuration(probeIntervalOpt)
+	if interval <= 0 {
+		log.Info("halo latency probing disabled")
+		return
+	}
+
+	stopChan = make(chan struct{})
+	go transaction.UntilStop(probe, interval, stopChan)
+}
+
+// Stop stops measuring the latency to the other peers
+func Stop() {
+	if stopChan == nil {
+		return
+	}
+	stopOnce.Do(func() {
+		close(stopChan)
+	})
+}
+
+// measure returns the lowest time taken to connect to the address, out of a
+// few attempts, which approximates the round-trip latency to it
+func measure(addr string) (time.Duration, error) {
+	var best time.Duration
+	for i := 0; i < probeSamples; i++ {
+		start := time.Now()
+		conn, err := peer.DialTimeout(addr, probeTimeout)
+		if err != nil {
+			return 0, err
+		}
+		d := time.Since(start)
+		conn.Close()
+		if best == 0 || d < best {
+			best = d
+		}
+	}
+	return best, nil
+}
+
+// probe measures the latency to the other peers and saves it to the store.
+// Unreachable peers are left out.
+func probe() {
+	peers, err := peer.GetPeersF()
+	if err != nil {
+		log.WithError(err).Error("halo: failed to get peers")
+		return
+	}
+
+	l := latencies{
+		MeasuredAt: time.Now(),
+		Peers:      make(map[string]time.Duration),
+	}
+	for _, p := range peers {
+		if uuid.Equal(p.ID, gdctx.MyUUID) || len(p.PeerAddresses) == 0 {
+			continue
+		}
+		addr, err := utils.FormRemotePeerAddress(p.PeerAddresses[0])
+		if err != nil {
+			continue
+		}
+		d, err := measure(addr)
+		if err != nil {
+			log.WithError(err).WithField("peer", p.ID.String()).Debug("halo: failed to measure latency to peer")
+			continue
+		}
+		l.Peers[p.ID.String()] = d
+	}
+
+	data, err := json.Marshal(l)
+	if err != nil {
+		log.WithError(err).Error("halo: failed to marshal latencies")
+		return
+	}
+	if _, err := store.Put(context.TODO(), latencyPrefix+gdctx.MyUUID.String(), string(data)); err != nil {
+		log.WithError(err).Error("halo: failed to save latencies")
+	}
+}
+
+// getLatencies returns the latencies measured by every peer, by peer ID
+func getLatencies() (map[string]latencies, error) {
+	resp, err := store.Get(context.TODO(), latencyPrefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	all := make(map[string]latencies, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var l latencies
+		if err := json.Unmarshal(kv.Value, &l); err != nil {
+			log.WithError(err).WithField("key", string(kv.Key)).Error("halo: failed to unmarshal latencies")
+			continue
+		}
+		all[string(kv.Key)[len(latencyPrefix):]] = l
+	}
+	return all, nil
+}
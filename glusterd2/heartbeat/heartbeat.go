@@ -0,0 +1,245 @@
+// Package heartbeat sends heartbeats to the other peers of the cluster over
+// the peer RPC, and tracks whether each of them is online, degraded or
+// offline. A peer going down is thus detected within seconds, independently
+// of the store and of failed transactions. The heartbeats also sample the
+// offset of the clocks of the other peers, for skewed clocks to be warned
+// about.
+package heartbeat
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/events"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	"github.com/gluster/glusterd2/glusterd2/transactionv2"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/utils"
+
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+	flag "github.com/spf13/pflag"
+	config "github.com/spf13/viper"
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	intervalOpt = "heartbeat-interval"
+	timeoutOpt  = "heartbeat-timeout"
+	missesOpt   = "heartbeat-misses"
+	skewOpt     = "clock-skew-threshold"
+
+	eventPeerOnline   = "peer.online"
+	eventPeerDegraded = "peer.degraded"
+	eventPeerOffline  = "peer.offline"
+
+	eventClockSkewed = "peer.clock.skewed"
+	eventClockSynced = "peer.clock.synced"
+)
+
+var (
+	stopChan chan struct{}
+	stopOnce sync.Once
+
+	connsLock sync.Mutex
+	// conns are the connections to the other peers, by address
+	conns = make(map[string]*grpc.ClientConn)
+)
+
+// InitFlags intializes the command line options for the heartbeats
+func InitFlags() {
+	flag.Duration(intervalOpt, 2*time.Second, "Interval at which heartbeats are sent to the other peers. Set to 0 to disable.")
+	flag.Duration(timeoutOpt, time.Second, "Time a peer is given to answer a heartbeat.")
+	flag.Int(missesOpt, 3, "Number of heartbeats missed in a row after which a peer is offline. A peer missing fewer is degraded.")
+	flag.Duration(skewOpt, time.Second, "Offset between the clocks of two peers beyond which their clocks are skewed. Set to 0 to disable the warnings.")
+}
+
+// Start starts sending heartbeats to the other peers periodically
+func Start() {
+	interval := config.GetDuration(intervalOpt)
+	if interval <= 0 {
+		log.Info("peer heartbeats disabled")
+		return
+	}
+
+	stopChan = make(chan struct{})
+	go transaction.UntilStop(beat, interval, stopChan)
+}
+
+// Stop stops sending heartbeats
+func Stop() {
+	if stopChan == nil {
+		return
+	}
+	stopOnce.Do(func() {
+		close(stopChan)
+	})
+}
+
+// getConn returns the connection to the address, which reconnects by itself
+// when the peer comes back
+func getConn(addr string) (*grpc.ClientConn, error) {
+	connsLock.Lock()
+	defer connsLock.Unlock()
+
+	if conn, ok := conns[addr]; ok {
+		return conn, nil
+	}
+	conn, err := grpc.Dial(addr,
+		grpc.WithInsecure(),
+		grpc.WithBackoffMaxDelay(config.GetDuration(intervalOpt)),
+		grpc.WithDialer(peer.DialTimeout),
+	)
+	if err != nil {
+		return nil, err
+	}
+	conns[addr] = conn
+	return conn, nil
+}
+
+// beatResult is the result of a heartbeat sent to a peer
+type beatResult struct {
+	ok bool
+	// offset is the offset of the clock of the peer from the clock of this
+	// peer, valid if sampled is true
+	offset  time.Duration
+	sampled bool
+}
+
+// clockOffset returns the offset of the remote time from the local clock,
+// given the time a heartbeat was sent and the time its answer was received.
+// The remote time is assumed to be taken halfway through the round trip.
+func clockOffset(remote, sent, received time.Time) time.Duration {
+	return remote.Sub(sent.Add(received.Sub(sent) / 2))
+}
+
+// ping sends a heartbeat to the peer, and samples the offset of its clock
+// from the time carried by the answer
+func ping(p *peer.Peer) beatResult {
+	var r beatResult
+	if len(p.PeerAddresses) == 0 {
+		return r
+	}
+	addr, err := utils.FormRemotePeerAddress(p.PeerAddresses[0])
+	if err != nil {
+		return r
+	}
+	conn, err := getConn(addr)
+	if err != nil {
+		log.WithError(err).WithField("peer", p.ID.String()).Debug("heartbeat: failed to connect to peer")
+		return r
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.GetDuration(timeoutOpt))
+	defer cancel()
+	var header metadata.MD
+	sent := time.Now()
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{}, grpc.Header(&header))
+	received := time.Now()
+	if err != nil {
+		log.WithError(err).WithField("peer", p.ID.String()).Debug("heartbeat: peer did not answer")
+		return r
+	}
+	r.ok = resp.Status == healthpb.HealthCheckResponse_SERVING
+
+	// peers running an older glusterd2 do not send their time
+	if values := header.Get(timeHeader); len(values) > 0 {
+		if ns, err := strconv.ParseInt(values[0], 10, 64); err == nil {
+			r.offset = clockOffset(time.Unix(0, ns), sent, received)
+			r.sampled = true
+		}
+	}
+	return r
+}
+
+// beat sends a heartbeat to every other peer, and updates their liveness
+func beat() {
+	all, err := peer.GetPeersF()
+	if err != nil {
+		log.WithError(err).Error("heartbeat: failed to get peers")
+		return
+	}
+
+	var wg sync.WaitGroup
+	current := make(map[string]bool, len(all))
+	for _, p := range all {
+		if uuid.Equal(p.ID, gdctx.MyUUID) {
+			continue
+		}
+		current[p.ID.String()] = true
+
+		wg.Add(1)
+		go func(p *peer.Peer) {
+			defer wg.Done()
+			record(p, ping(p), time.Now())
+		}(p)
+	}
+	wg.Wait()
+
+	forget(current)
+}
+
+// record updates the liveness of the peer with the result of a heartbeat,
+// and broadcasts the changes of its state and of the skew of its clock
+func record(p *peer.Peer, r beatResult, at time.Time) {
+	peersLock.Lock()
+	l, found := peers[p.ID.String()]
+	if !found {
+		l = new(liveness)
+		peers[p.ID.String()] = l
+	}
+	changed := l.observe(r.ok, at, config.GetInt(missesOpt))
+	skewChanged := r.sampled && l.observeClock(r.offset, config.GetDuration(skewOpt))
+	status := l.toAPI()
+	peersLock.Unlock()
+
+	if skewChanged {
+		name := eventClockSynced
+		if status.ClockSkewed {
+			name = eventClockSkewed
+			log.WithFields(log.Fields{
+				"peer":   p.ID.String(),
+				"name":   p.Name,
+				"offset": r.offset,
+			}).Warn("clock of peer is skewed")
+		}
+		data := map[string]string{
+			"peer.id":         p.ID.String(),
+			"peer.name":       p.Name,
+			"clock-offset-ms": strconv.FormatFloat(status.ClockOffsetMs, 'f', -1, 64),
+		}
+		events.Broadcast(events.New(name, data, false))
+	}
+
+	// a peer found online by the first heartbeat is not news
+	if !changed || (!found && status.State == api.PeerOnline) {
+		return
+	}
+
+	var name string
+	switch status.State {
+	case api.PeerOnline:
+		name = eventPeerOnline
+	case api.PeerDegraded:
+		name = eventPeerDegraded
+	case api.PeerOffline:
+		name = eventPeerOffline
+	}
+	log.WithFields(log.Fields{
+		"peer":  p.ID.String(),
+		"name":  p.Name,
+		"state": status.State,
+	}).Info("peer liveness changed")
+
+	data := map[string]string{
+		"peer.id":   p.ID.String(),
+		"peer.name": p.Name,
+		"since":     status.Since.Format(time.RFC3339),
+	}
+	events.Broadcast(events.New(name, data, false))
+}
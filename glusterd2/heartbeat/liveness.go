@@ -0,0 +1,127 @@
+package heartbeat
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gluster/glusterd2/pkg/api"
+)
+
+// liveness is the liveness of a peer, as seen by this peer from the
+// heartbeats it sent to it
+type liveness struct {
+	state    api.PeerLivenessState
+	since    time.Time
+	lastSeen time.Time
+	// misses is the number of heartbeats missed in a row
+	misses int
+
+	// clockOffset is the offset of the clock of the peer from the clock of
+	// this peer, sampled by the last heartbeat answered
+	clockOffset  time.Duration
+	clockSkewed  bool
+	clockSampled bool
+}
+
+// observe updates the liveness with the result of a heartbeat sent at the
+// given time. A peer is degraded once it misses a heartbeat, and offline
+// once it misses offlineMisses heartbeats in a row. It returns true if the
+// state of the peer changed.
+func (l *liveness) observe(ok bool, at time.Time, offlineMisses int) bool {
+	state := api.PeerOnline
+	if ok {
+		l.misses = 0
+		l.lastSeen = at
+	} else {
+		l.misses++
+		state = api.PeerDegraded
+		if l.misses >= offlineMisses {
+			state = api.PeerOffline
+		}
+	}
+
+	if state == l.state {
+		return false
+	}
+	l.state = state
+	l.since = at
+	return true
+}
+
+// observeClock updates the liveness with the clock offset sampled by a
+// heartbeat. The clock of the peer is skewed once the offset is beyond the
+// threshold, and back in sync once it is within half of it, for an offset
+// close to the threshold not to flap. It returns true if the clock of the
+// peer became skewed or back in sync.
+func (l *liveness) observeClock(offset, threshold time.Duration) bool {
+	l.clockOffset = offset
+	l.clockSampled = true
+	if offset < 0 {
+		offset = -offset
+	}
+
+	skewed := l.clockSkewed
+	switch {
+	case threshold <= 0:
+		skewed = false
+	case offset > threshold:
+		skewed = true
+	case offset <= threshold/2:
+		skewed = false
+	}
+
+	if skewed == l.clockSkewed {
+		return false
+	}
+	l.clockSkewed = skewed
+	return true
+}
+
+func (l *liveness) toAPI() api.PeerLiveness {
+	pl := api.PeerLiveness{
+		State:  l.state,
+		Since:  l.since,
+		Misses: l.misses,
+	}
+	if l.clockSampled {
+		pl.ClockOffsetMs = float64(l.clockOffset) / float64(time.Millisecond)
+		pl.ClockSkewed = l.clockSkewed
+	}
+	if !l.lastSeen.IsZero() {
+		lastSeen := l.lastSeen
+		pl.LastSeen = &lastSeen
+	}
+	return pl
+}
+
+var (
+	peersLock sync.RWMutex
+	// peers is the liveness of the other peers, by peer ID
+	peers = make(map[string]*liveness)
+)
+
+// Status returns the liveness of the peer with the ID, as seen by this peer.
+// It returns false if no heartbeat was sent to the peer yet.
+func Status(peerID string) (api.PeerLiveness, bool) {
+	peersLock.RLock()
+	defer peersLock.RUnlock()
+
+	l, ok := peers[peerID]
+	if !ok {
+		return api.PeerLiveness{}, false
+	}
+	return l.toAPI(), true
+}
+
+// forget drops the liveness of the peers which are not in the cluster
+// anymore
+func forget(current map[string]bool) {
+	peersLock.Lock()
+	defer peersLock.Unlock()
+
+	for id := range peers {
+		if !current[id] {
+			delete(peers, id)
+		}
+	}
+}
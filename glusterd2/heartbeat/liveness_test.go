@@ -0,0 +1,93 @@
+package heartbeat
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObserve(t *testing.T) {
+	var l liveness
+	start := time.Now()
+
+	assert.True(t, l.observe(true, start, 3))
+	assert.Equal(t, api.PeerOnline, l.state)
+	assert.Equal(t, start, l.since)
+
+	// answered heartbeats keep the peer online
+	second := start.Add(time.Second)
+	assert.False(t, l.observe(true, second, 3))
+	assert.Equal(t, start, l.since)
+	assert.Equal(t, second, l.lastSeen)
+
+	third := second.Add(time.Second)
+	assert.True(t, l.observe(false, third, 3))
+	assert.Equal(t, api.PeerDegraded, l.state)
+	assert.Equal(t, third, l.since)
+
+	assert.False(t, l.observe(false, third.Add(time.Second), 3))
+	assert.Equal(t, api.PeerDegraded, l.state)
+
+	offline := third.Add(2 * time.Second)
+	assert.True(t, l.observe(false, offline, 3))
+	assert.Equal(t, api.PeerOffline, l.state)
+	assert.Equal(t, offline, l.since)
+	assert.Equal(t, second, l.lastSeen)
+	assert.Equal(t, 3, l.misses)
+
+	back := offline.Add(time.Second)
+	assert.True(t, l.observe(true, back, 3))
+	assert.Equal(t, api.PeerOnline, l.state)
+	assert.Equal(t, 0, l.misses)
+
+	// a peer never answering is offline once it missed enough heartbeats
+	var never liveness
+	assert.True(t, never.observe(false, start, 1))
+	assert.Equal(t, api.PeerOffline, never.state)
+	assert.Nil(t, never.toAPI().LastSeen)
+}
+
+func TestForget(t *testing.T) {
+	peers = map[string]*liveness{
+		"a": {state: api.PeerOnline},
+		"b": {state: api.PeerOffline},
+	}
+	forget(map[string]bool{"a": true})
+
+	_, ok := Status("a")
+	assert.True(t, ok)
+	_, ok = Status("b")
+	assert.False(t, ok)
+}
+
+func TestObserveClock(t *testing.T) {
+	var l liveness
+	assert.Equal(t, float64(0), l.toAPI().ClockOffsetMs)
+
+	assert.False(t, l.observeClock(200*time.Millisecond, time.Second))
+	assert.Equal(t, float64(200), l.toAPI().ClockOffsetMs)
+
+	assert.True(t, l.observeClock(-2*time.Second, time.Second))
+	assert.True(t, l.toAPI().ClockSkewed)
+
+	// the clock is only back in sync within half of the threshold
+	assert.False(t, l.observeClock(800*time.Millisecond, time.Second))
+	assert.True(t, l.clockSkewed)
+	assert.True(t, l.observeClock(400*time.Millisecond, time.Second))
+	assert.False(t, l.clockSkewed)
+
+	// a threshold of 0 disables the skew
+	assert.False(t, l.observeClock(time.Hour, 0))
+}
+
+func TestClockOffset(t *testing.T) {
+	sent := time.Now()
+	received := sent.Add(100 * time.Millisecond)
+
+	assert.Equal(t, time.Duration(0), clockOffset(sent.Add(50*time.Millisecond), sent, received))
+	assert.Equal(t, 2*time.Second, clockOffset(sent.Add(2050*time.Millisecond), sent, received))
+	assert.Equal(t, -time.Second, clockOffset(sent.Add(-950*time.Millisecond), sent, received))
+}
@@ -0,0 +1,43 @@
+package heartbeat
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/servers/peerrpc"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+)
+
+// timeHeader is the header of the answers to the heartbeats carrying the
+// time of the answering peer, in nanoseconds since the epoch, for the clock
+// offset between the peers to be sampled
+const timeHeader = "gd2-time"
+
+// healthSvc answers the heartbeats of the other peers, with the standard
+// gRPC health checking service
+type healthSvc struct {
+	*health.Server
+}
+
+// Check answers a heartbeat, along with the time of this peer
+func (s *healthSvc) Check(ctx context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	now := strconv.FormatInt(time.Now().UnixNano(), 10)
+	if err := grpc.SetHeader(ctx, metadata.Pairs(timeHeader, now)); err != nil {
+		return nil, err
+	}
+	return s.Server.Check(ctx, req)
+}
+
+// RegisterService registers the health checking service with the gRPC server
+func (s *healthSvc) RegisterService(srv *grpc.Server) {
+	healthpb.RegisterHealthServer(srv, s)
+}
+
+func init() {
+	peerrpc.Register(&healthSvc{health.NewServer()})
+}
@@ -0,0 +1,193 @@
+// Package hooks runs the scripts and the webhooks registered by the admin at
+// points of the lifecycle of the volumes, with a JSON payload and a timeout,
+// and keeps the history of their runs.
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"syscall"
+
+	"github.com/gluster/glusterd2/glusterd2/configsnap"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/coreos/etcd/clientv3"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// hooksPrefix is where the hooks are saved, under their name
+	hooksPrefix = "hooks/"
+
+	// DefaultTimeout is the time given to a hook to run, in seconds, when
+	// not set
+	DefaultTimeout = 30
+	// MaxTimeout is the longest time a hook can be given to run, in
+	// seconds
+	MaxTimeout = 600
+)
+
+var validName = regexp.MustCompile(`^[a-zA-Z0-9_.-]+$`)
+
+// points are the points of the lifecycle of the volumes hooks can be bound
+// to, telling if the hooks run before the operation
+var points = map[api.HookPoint]bool{
+	api.HookPostVolumeStart: false,
+	api.HookPreVolumeStop:   true,
+	api.HookPostAddBrick:    false,
+}
+
+func init() {
+	configsnap.RegisterPrefix(hooksPrefix)
+}
+
+// checkScript checks that the script is an executable which only its owner,
+// root or the user running glusterd2, can change
+func checkScript(path string) error {
+	if !filepath.IsAbs(path) {
+		return errors.New("script must be an absolute path")
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if !fi.Mode().IsRegular() || fi.Mode()&0111 == 0 {
+		return errors.New("script must be an executable file")
+	}
+	if fi.Mode()&0022 != 0 {
+		return errors.New("script must not be writable by group or others")
+	}
+	if st, ok := fi.Sys().(*syscall.Stat_t); ok && st.Uid != 0 && int(st.Uid) != os.Getuid() {
+		return errors.New("script must be owned by root or by the user running glusterd2")
+	}
+	return nil
+}
+
+// checkURL checks that the URL is the URL of a webhook
+func checkURL(rawurl string) error {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("url must be a http or https URL")
+	}
+	return nil
+}
+
+// Validate checks the hook to be registered, and sets its defaults
+func Validate(h *api.Hook) error {
+	if !validName.MatchString(h.Name) {
+		return errors.New("name must only have letters, digits, '_', '.' and '-'")
+	}
+	if _, ok := points[h.Point]; !ok {
+		return errors.New("point must be one of post-volume-start, pre-volume-stop or post-add-brick")
+	}
+	if (h.Script == "") == (h.URL == "") {
+		return errors.New("either a script or a url is to be set")
+	}
+	if h.Timeout == 0 {
+		h.Timeout = DefaultTimeout
+	}
+	if h.Timeout < 0 || h.Timeout > MaxTimeout {
+		return errors.New("timeout must be between 1 and 600 seconds")
+	}
+	if h.Script != "" {
+		return checkScript(h.Script)
+	}
+	return checkURL(h.URL)
+}
+
+// Add registers the hook
+func Add(h *api.Hook) error {
+	data, err := json.Marshal(h)
+	if err != nil {
+		return err
+	}
+	key := hooksPrefix + h.Name
+	resp, err := store.Txn(context.TODO()).
+		If(clientv3.Compare(clientv3.CreateRevision(key), "=", 0)).
+		Then(clientv3.OpPut(key, string(data))).
+		Commit()
+	if err != nil {
+		return err
+	}
+	if !resp.Succeeded {
+		return gderrors.ErrHookExists
+	}
+	return nil
+}
+
+// Get returns the hook with the name
+func Get(name string) (*api.Hook, error) {
+	resp, err := store.Get(context.TODO(), hooksPrefix+name)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Count != 1 {
+		return nil, gderrors.ErrHookNotFound
+	}
+	var h api.Hook
+	if err := json.Unmarshal(resp.Kvs[0].Value, &h); err != nil {
+		return nil, err
+	}
+	return &h, nil
+}
+
+// List returns the hooks, sorted by name
+func List() ([]api.Hook, error) {
+	resp, err := store.Get(context.TODO(), hooksPrefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	hooks := make([]api.Hook, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var h api.Hook
+		if err := json.Unmarshal(kv.Value, &h); err != nil {
+			log.WithError(err).WithField("key", string(kv.Key)).Error("failed to unmarshal hook")
+			continue
+		}
+		hooks = append(hooks, h)
+	}
+	sort.Slice(hooks, func(i, j int) bool { return hooks[i].Name < hooks[j].Name })
+	return hooks, nil
+}
+
+// SetEnabled enables or disables the hook with the name
+func SetEnabled(name string, enabled bool) (*api.Hook, error) {
+	h, err := Get(name)
+	if err != nil {
+		return nil, err
+	}
+	h.Enabled = enabled
+	data, err := json.Marshal(h)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := store.Put(context.TODO(), hooksPrefix+name, string(data)); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// Delete deletes the hook with the name, along with the history of its runs
+func Delete(name string) error {
+	resp, err := store.Delete(context.TODO(), hooksPrefix+name)
+	if err != nil {
+		return err
+	}
+	if resp.Deleted == 0 {
+		return gderrors.ErrHookNotFound
+	}
+	_, err = store.Delete(context.TODO(), runsKey(name), clientv3.WithPrefix())
+	return err
+}
@@ -0,0 +1,123 @@
+package hooks
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeScript(t *testing.T, dir, name, body string, mode os.FileMode) string {
+	path := filepath.Join(dir, name)
+	require.NoError(t, ioutil.WriteFile(path, []byte("#!/bin/sh\n"+body), mode))
+	require.NoError(t, os.Chmod(path, mode))
+	return path
+}
+
+func TestValidate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "TestValidate")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	script := writeScript(t, dir, "ok", "exit 0\n", 0755)
+
+	h := api.Hook{Name: "notify", Point: api.HookPostVolumeStart, URL: "https://example.com/hook"}
+	require.NoError(t, Validate(&h))
+	assert.Equal(t, DefaultTimeout, h.Timeout)
+
+	assert.NoError(t, Validate(&api.Hook{Name: "s", Point: api.HookPreVolumeStop, Script: script}))
+
+	for _, h := range []api.Hook{
+		{Name: "a/b", Point: api.HookPostVolumeStart, URL: "http://example.com"},
+		{Name: "a", Point: "post-volume-delete", URL: "http://example.com"},
+		{Name: "a", Point: api.HookPostAddBrick},
+		{Name: "a", Point: api.HookPostAddBrick, URL: "http://example.com", Script: script},
+		{Name: "a", Point: api.HookPostAddBrick, URL: "ftp://example.com"},
+		{Name: "a", Point: api.HookPostAddBrick, URL: "http://example.com", Timeout: MaxTimeout + 1},
+		{Name: "a", Point: api.HookPostAddBrick, Script: "relative/script"},
+	} {
+		assert.Error(t, Validate(&h), h.Name)
+	}
+}
+
+func TestCheckScript(t *testing.T) {
+	dir, err := ioutil.TempDir("", "TestCheckScript")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	assert.NoError(t, checkScript(writeScript(t, dir, "ok", "", 0750)))
+	assert.Error(t, checkScript(writeScript(t, dir, "noexec", "", 0644)))
+	assert.Error(t, checkScript(writeScript(t, dir, "writable", "", 0777)))
+	assert.Error(t, checkScript(dir))
+	assert.Error(t, checkScript(filepath.Join(dir, "missing")))
+}
+
+func TestLimitedBuffer(t *testing.T) {
+	var b limitedBuffer
+	n, err := b.Write([]byte(strings.Repeat("a", maxOutput-1)))
+	assert.NoError(t, err)
+	assert.Equal(t, maxOutput-1, n)
+
+	n, err = b.Write([]byte("bcd"))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, n)
+	assert.Equal(t, maxOutput, b.Len())
+	assert.True(t, strings.HasSuffix(b.String(), "ab"))
+}
+
+func TestRunScript(t *testing.T) {
+	dir, err := ioutil.TempDir("", "TestRunScript")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	// the payload is given on the standard input
+	var r api.HookRun
+	echo := writeScript(t, dir, "echo", "echo $GD2_VOLUME_NAME\ncat\n", 0755)
+	require.NoError(t, runScript(echo, []string{"GD2_VOLUME_NAME=vol1"}, []byte(`{"hook":"echo"}`), time.Second, &r))
+	assert.Equal(t, "vol1\n{\"hook\":\"echo\"}", r.Output)
+
+	r = api.HookRun{}
+	fail := writeScript(t, dir, "fail", "echo failed >&2\nexit 3\n", 0755)
+	assert.Error(t, runScript(fail, nil, nil, time.Second, &r))
+	assert.Equal(t, 3, r.ExitCode)
+	assert.Equal(t, "failed\n", r.Output)
+
+	r = api.HookRun{}
+	slow := writeScript(t, dir, "slow", "sleep 10 &\nsleep 10\n", 0755)
+	start := time.Now()
+	err = runScript(slow, nil, nil, 100*time.Millisecond, &r)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "timed out")
+	assert.True(t, time.Since(start) < 5*time.Second)
+}
+
+func TestRunWebhook(t *testing.T) {
+	var got string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		got = string(body)
+		if strings.Contains(got, "reject") {
+			w.WriteHeader(http.StatusForbidden)
+		}
+		w.Write([]byte("done"))
+	}))
+	defer ts.Close()
+
+	var r api.HookRun
+	require.NoError(t, runWebhook(ts.URL, []byte(`{"hook":"ok"}`), time.Second, &r))
+	assert.Equal(t, `{"hook":"ok"}`, got)
+	assert.Equal(t, http.StatusOK, r.HTTPStatus)
+	assert.Equal(t, "done", r.Output)
+
+	r = api.HookRun{}
+	assert.Error(t, runWebhook(ts.URL, []byte(`{"hook":"reject"}`), time.Second, &r))
+	assert.Equal(t, http.StatusForbidden, r.HTTPStatus)
+}
@@ -0,0 +1,300 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os/exec"
+	"syscall"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/events"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/coreos/etcd/clientv3"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// runsPrefix is where the runs of the hooks are saved, under the name
+	// of the hook and the time of the run
+	runsPrefix = "hook-runs/"
+	// maxRuns is the number of runs kept for each hook
+	maxRuns = 20
+	// maxOutput is the length of the output of a run kept
+	maxOutput = 4096
+
+	// scriptPath is the PATH the scripts are run with
+	scriptPath = "/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin"
+
+	eventHookFailed = "hook.failed"
+)
+
+// Error is the failure of a hook run before an operation, which is then not
+// done
+type Error struct {
+	Hook  string
+	Point api.HookPoint
+	Err   error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s hook %s failed: %s", e.Point, e.Hook, e.Err)
+}
+
+func runsKey(name string) string {
+	return runsPrefix + name + "/"
+}
+
+// limitedBuffer keeps the beginning of what is written to it, up to
+// maxOutput bytes
+type limitedBuffer struct {
+	bytes.Buffer
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := maxOutput - b.Len(); room > 0 {
+		if len(p) > room {
+			b.Buffer.Write(p[:room])
+		} else {
+			b.Buffer.Write(p)
+		}
+	}
+	return len(p), nil
+}
+
+// runScript runs the script with the payload on its standard input. The
+// script, along with the processes it started, is killed once the timeout
+// expires.
+func runScript(script string, env []string, payload []byte, timeout time.Duration, run *api.HookRun) error {
+	if err := checkScript(script); err != nil {
+		return err
+	}
+
+	var out limitedBuffer
+	cmd := exec.Command(script)
+	cmd.Env = append([]string{"PATH=" + scriptPath}, env...)
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-time.After(timeout):
+		syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+		<-done
+		err = fmt.Errorf("timed out after %s", timeout)
+	}
+	run.Output = out.String()
+
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		if status, ok := exitErr.Sys().(syscall.WaitStatus); ok {
+			run.ExitCode = status.ExitStatus()
+		}
+	}
+	return err
+}
+
+// runWebhook posts the payload to the webhook, which is to answer with a
+// 2xx status within the timeout
+func runWebhook(url string, payload []byte, timeout time.Duration, run *api.HookRun) error {
+	client := &http.Client{Timeout: timeout}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var out limitedBuffer
+	io.Copy(&out, io.LimitReader(resp.Body, maxOutput))
+	run.Output = out.String()
+	run.HTTPStatus = resp.StatusCode
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook answered %s", resp.Status)
+	}
+	return nil
+}
+
+// run runs the hook with the payload, and records the run
+func run(h api.Hook, payload api.HookPayload) error {
+	payload.Hook = h.Name
+	r := api.HookRun{
+		Hook:    h.Name,
+		Point:   h.Point,
+		PeerID:  gdctx.MyUUID,
+		Started: time.Now(),
+	}
+	if payload.Volume != nil {
+		r.Volume = payload.Volume.Name
+	}
+
+	data, err := json.Marshal(payload)
+	if err == nil {
+		timeout := time.Duration(h.Timeout) * time.Second
+		if h.Script != "" {
+			env := []string{
+				"GD2_HOOK_NAME=" + h.Name,
+				"GD2_HOOK_POINT=" + string(h.Point),
+				"GD2_VOLUME_NAME=" + r.Volume,
+			}
+			err = runScript(h.Script, env, data, timeout, &r)
+		} else {
+			err = runWebhook(h.URL, data, timeout, &r)
+		}
+	}
+
+	r.DurationMs = int64(time.Since(r.Started) / time.Millisecond)
+	r.Success = err == nil
+	if err != nil {
+		r.Error = err.Error()
+		events.Broadcast(events.New(eventHookFailed, map[string]string{
+			"hook":        h.Name,
+			"point":       string(h.Point),
+			"volume.name": r.Volume,
+			"error":       r.Error,
+		}, true))
+	}
+
+	if recErr := record(r); recErr != nil {
+		log.WithError(recErr).WithField("hook", h.Name).Warn("failed to record the run of the hook")
+	}
+	return err
+}
+
+// record saves the run of a hook, dropping the oldest runs of the hook
+// beyond maxRuns
+func record(r api.HookRun) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	prefix := runsKey(r.Hook)
+	// Zero padded for the keys to sort by time
+	key := fmt.Sprintf("%s%020d", prefix, r.Started.UnixNano())
+	if _, err := store.Put(context.TODO(), key, string(data)); err != nil {
+		return err
+	}
+
+	resp, err := store.Get(context.TODO(), prefix, clientv3.WithPrefix(), clientv3.WithKeysOnly(),
+		clientv3.WithSort(clientv3.SortByKey, clientv3.SortAscend))
+	if err != nil {
+		return err
+	}
+	for i := 0; i < len(resp.Kvs)-maxRuns; i++ {
+		if _, err := store.Delete(context.TODO(), string(resp.Kvs[i].Key)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// History returns the runs of the hook with the name, the latest first
+func History(name string) ([]api.HookRun, error) {
+	if _, err := Get(name); err != nil {
+		return nil, err
+	}
+
+	resp, err := store.Get(context.TODO(), runsKey(name), clientv3.WithPrefix(),
+		clientv3.WithSort(clientv3.SortByKey, clientv3.SortDescend))
+	if err != nil {
+		return nil, err
+	}
+
+	runs := make([]api.HookRun, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var r api.HookRun
+		if err := json.Unmarshal(kv.Value, &r); err != nil {
+			log.WithError(err).WithField("key", string(kv.Key)).Error("failed to unmarshal run of hook")
+			continue
+		}
+		runs = append(runs, r)
+	}
+	return runs, nil
+}
+
+// enabledAt returns the enabled hooks bound to the point
+func enabledAt(point api.HookPoint) ([]api.Hook, error) {
+	hooks, err := List()
+	if err != nil {
+		return nil, err
+	}
+	var enabled []api.Hook
+	for _, h := range hooks {
+		if h.Enabled && h.Point == point {
+			enabled = append(enabled, h)
+		}
+	}
+	return enabled, nil
+}
+
+func newPayload(point api.HookPoint, volume *api.VolumeInfo, bricks []api.BrickInfo) api.HookPayload {
+	return api.HookPayload{
+		Point:  point,
+		PeerID: gdctx.MyUUID,
+		Time:   time.Now(),
+		Volume: volume,
+		Bricks: bricks,
+	}
+}
+
+// RunPre runs the hooks bound to the point, one after the other, before the
+// operation on the volume. The operation is not to be done if a hook fails.
+func RunPre(point api.HookPoint, volume *api.VolumeInfo) error {
+	hooks, err := enabledAt(point)
+	if err != nil {
+		return err
+	}
+
+	payload := newPayload(point, volume, nil)
+	for _, h := range hooks {
+		if err := run(h, payload); err != nil {
+			log.WithError(err).WithFields(log.Fields{
+				"hook":   h.Name,
+				"point":  point,
+				"volume": volume.Name,
+			}).Error("hook failed, not doing the operation")
+			return &Error{Hook: h.Name, Point: point, Err: err}
+		}
+	}
+	return nil
+}
+
+// RunPost runs the hooks bound to the point in the background, one after the
+// other, once the operation on the volume is done. Bricks are the bricks the
+// operation added, if any.
+func RunPost(point api.HookPoint, volume *api.VolumeInfo, bricks []api.BrickInfo) {
+	go func() {
+		hooks, err := enabledAt(point)
+		if err != nil {
+			log.WithError(err).WithField("point", point).Error("failed to get the hooks to run")
+			return
+		}
+
+		payload := newPayload(point, volume, bricks)
+		for _, h := range hooks {
+			if err := run(h, payload); err != nil {
+				log.WithError(err).WithFields(log.Fields{
+					"hook":   h.Name,
+					"point":  point,
+					"volume": volume.Name,
+				}).Warn("hook failed")
+			}
+		}
+	}()
+}
@@ -0,0 +1,186 @@
+// Package journal records the local actions of glusterd2, such as starting a
+// daemon or writing a volfile, before they are done. The actions left
+// recorded when glusterd2 crashed are verified against the state of the peer
+// when glusterd2 is started again.
+package journal
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+	config "github.com/spf13/viper"
+)
+
+const journalDir = "journal"
+
+// Entry is a local action recorded in the journal
+type Entry struct {
+	ID     string          `json:"id"`
+	Action string          `json:"action"`
+	Target string          `json:"target"`
+	Data   json.RawMessage `json:"data,omitempty"`
+	Time   time.Time       `json:"time"`
+}
+
+// Verifier verifies an action which was not done when glusterd2 crashed
+// against the state of the peer, and completes or undoes it
+type Verifier func(e Entry) error
+
+var verifiers = struct {
+	sync.RWMutex
+	m map[string]Verifier
+}{
+	m: make(map[string]Verifier),
+}
+
+// Register registers the verifier of the action
+func Register(action string, v Verifier) {
+	verifiers.Lock()
+	defer verifiers.Unlock()
+	verifiers.m[action] = v
+}
+
+func verifierOf(action string) Verifier {
+	verifiers.RLock()
+	defer verifiers.RUnlock()
+	return verifiers.m[action]
+}
+
+// dir returns the directory of the journal
+func dir() string {
+	return path.Join(config.GetString("localstatedir"), journalDir)
+}
+
+// Record records the action on the target, with the data needed to verify
+// it, before it is done. done must be called once the action is done, failed
+// or not. done is never nil, and does nothing if the action could not be
+// recorded.
+func Record(action, target string, data interface{}) (done func(), err error) {
+	e := Entry{
+		ID:     uuid.NewRandom().String(),
+		Action: action,
+		Target: target,
+		Time:   time.Now(),
+	}
+	if data != nil {
+		if e.Data, err = json.Marshal(data); err != nil {
+			return func() {}, err
+		}
+	}
+
+	b, err := json.Marshal(e)
+	if err != nil {
+		return func() {}, err
+	}
+	filename := path.Join(dir(), e.ID+".json")
+	if err := writeFile(filename, b); err != nil {
+		return func() {}, err
+	}
+
+	return func() {
+		if err := os.Remove(filename); err != nil && !os.IsNotExist(err) {
+			log.WithError(err).WithField("file", filename).Warn("failed to remove journal entry")
+		}
+	}, nil
+}
+
+// writeFile writes the file and syncs it to disk, so that it is there after
+// a crash, whole or not at all
+func writeFile(filename string, b []byte) error {
+	if err := os.MkdirAll(path.Dir(filename), 0700); err != nil {
+		return err
+	}
+
+	tmp := filename + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filename)
+}
+
+// Pending returns the actions recorded and not done, oldest first
+func Pending() ([]Entry, error) {
+	files, err := ioutil.ReadDir(dir())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []Entry
+	for _, f := range files {
+		if !strings.HasSuffix(f.Name(), ".json") {
+			// left by a crash while recording the action, which
+			// was not done then
+			os.Remove(path.Join(dir(), f.Name()))
+			continue
+		}
+		b, err := ioutil.ReadFile(path.Join(dir(), f.Name()))
+		if err != nil {
+			return nil, err
+		}
+		var e Entry
+		if err := json.Unmarshal(b, &e); err != nil {
+			log.WithError(err).WithField("file", f.Name()).Warn("ignoring invalid journal entry")
+			os.Remove(path.Join(dir(), f.Name()))
+			continue
+		}
+		entries = append(entries, e)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Time.Before(entries[j].Time)
+	})
+	return entries, nil
+}
+
+// Replay verifies the actions left recorded when glusterd2 crashed, oldest
+// first, and removes them from the journal. It is to be called when
+// glusterd2 starts, before the local daemons are started.
+func Replay() {
+	entries, err := Pending()
+	if err != nil {
+		log.WithError(err).Error("failed to read the journal, the actions interrupted by a crash are not verified")
+		return
+	}
+
+	for _, e := range entries {
+		logger := log.WithFields(log.Fields{
+			"action": e.Action,
+			"target": e.Target,
+			"time":   e.Time,
+		})
+		logger.Warn("verifying action interrupted by a crash")
+
+		if v := verifierOf(e.Action); v == nil {
+			logger.Error("no verifier for the action, it may have been left half done")
+		} else if err := v(e); err != nil {
+			logger.WithError(err).Error("failed to verify action, it may have been left half done")
+		}
+
+		if err := os.Remove(path.Join(dir(), e.ID+".json")); err != nil && !os.IsNotExist(err) {
+			logger.WithError(err).Warn("failed to remove journal entry")
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package journal
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	config "github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordReplay(t *testing.T) {
+	d, err := ioutil.TempDir("", "journal")
+	require.NoError(t, err)
+	defer os.RemoveAll(d)
+	config.Set("localstatedir", d)
+	defer config.Set("localstatedir", "")
+
+	// An action done is not left in the journal
+	done, err := Record("test.start", "b1", nil)
+	require.NoError(t, err)
+	done()
+	entries, err := Pending()
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	// Actions not done are verified on replay, and removed from the
+	// journal even if their verification fails
+	_, err = Record("test.start", "b2", map[string]string{"volume": "gv0"})
+	require.NoError(t, err)
+	_, err = Record("test.stop", "b3", nil)
+	require.NoError(t, err)
+	_, err = Record("test.unknown", "b4", nil)
+	require.NoError(t, err)
+	// left by a crash while recording
+	require.NoError(t, ioutil.WriteFile(path.Join(d, journalDir, "x.json.tmp"), []byte("{"), 0600))
+
+	entries, err = Pending()
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, "b2", entries[0].Target)
+	assert.JSONEq(t, `{"volume":"gv0"}`, string(entries[0].Data))
+
+	var verified []string
+	Register("test.start", func(e Entry) error {
+		verified = append(verified, e.Target)
+		return nil
+	})
+	Register("test.stop", func(e Entry) error {
+		verified = append(verified, e.Target)
+		return errors.New("failed")
+	})
+	Replay()
+	assert.Equal(t, []string{"b2", "b3"}, verified)
+
+	files, err := ioutil.ReadDir(path.Join(d, journalDir))
+	require.NoError(t, err)
+	assert.Empty(t, files)
+}
@@ -0,0 +1,266 @@
+// Package leader elects, among the peers, the peer running each of the
+// singleton services of the cluster, such as the cleanup of the stale
+// transactions, which must run on a single peer at a time.
+//
+// Each peer contests the election of each service registered. The peer
+// elected runs the service until it resigns, is stopped or loses its session
+// with the store, and another peer takes over. The peers are elected with the
+// etcd elections, on the key of each service.
+package leader
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/events"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/coreos/etcd/clientv3/concurrency"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// EventLeaderElected is broadcast when this peer is elected as the
+	// leader of a service
+	EventLeaderElected = "leader_elected"
+
+	// retryInterval is the interval before contesting again an election
+	// after resigning or failing to campaign
+	retryInterval = 5 * time.Second
+
+	storeTimeout = 10 * time.Second
+)
+
+// service is a singleton service of the cluster
+type service struct {
+	name string
+
+	mu      sync.Mutex
+	leading bool
+
+	// resignCh asks the peer leading the service to resign
+	resignCh chan struct{}
+}
+
+var (
+	servicesMu sync.RWMutex
+	services   = make(map[string]*service)
+
+	stopChan chan struct{}
+	stopOnce sync.Once
+)
+
+// Register registers a singleton service, run by a single peer at a time. It
+// must be called before Start.
+func Register(name string) {
+	servicesMu.Lock()
+	defer servicesMu.Unlock()
+	if _, ok := services[name]; ok {
+		return
+	}
+	services[name] = &service{
+		name:     name,
+		resignCh: make(chan struct{}, 1),
+	}
+}
+
+func getService(name string) (*service, error) {
+	servicesMu.RLock()
+	defer servicesMu.RUnlock()
+	s, ok := services[name]
+	if !ok {
+		return nil, errors.ErrServiceNotFound
+	}
+	return s, nil
+}
+
+// electionKey returns the key of the election of the service in the store
+func electionKey(name string) string {
+	return name + "-leader"
+}
+
+// IsLeader tells if this peer is the leader of the service, which it is to
+// run
+func IsLeader(name string) bool {
+	s, err := getService(name)
+	if err != nil {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.leading
+}
+
+// Resign makes this peer resign from leading the service, for another peer
+// to take over
+func Resign(name string) error {
+	s, err := getService(name)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.leading {
+		return errors.ErrNotLeader
+	}
+	select {
+	case s.resignCh <- struct{}{}:
+	default:
+		// already resigning
+	}
+	return nil
+}
+
+// Start starts contesting the elections of the services registered
+func Start() {
+	stopChan = make(chan struct{})
+
+	servicesMu.RLock()
+	defer servicesMu.RUnlock()
+	for _, s := range services {
+		go s.run()
+	}
+}
+
+// Stop stops contesting the elections, resigning from leading the services
+func Stop() {
+	if stopChan == nil {
+		return
+	}
+	stopOnce.Do(func() {
+		close(stopChan)
+	})
+}
+
+func (s *service) setLeading(leading bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.leading = leading
+	// A resign asked for a previous term is void
+	select {
+	case <-s.resignCh:
+	default:
+	}
+}
+
+// run contests the election of the service until stopped
+func (s *service) run() {
+	for {
+		s.term()
+
+		select {
+		case <-stopChan:
+			return
+		case <-time.After(retryInterval):
+		}
+	}
+}
+
+// term campaigns to be the leader of the service, and leads it until
+// resigning, being stopped or losing the session with the store
+func (s *service) term() {
+	logger := log.WithField("service", s.name)
+
+	session := store.Store.Session
+	election := concurrency.NewElection(session, electionKey(s.name))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-stopChan:
+		case <-session.Done():
+		case <-ctx.Done():
+		}
+		cancel()
+	}()
+
+	if err := election.Campaign(ctx, gdctx.MyUUID.String()); err != nil {
+		if ctx.Err() == nil {
+			logger.WithError(err).Error("failed in campaign for leader election")
+		}
+		return
+	}
+
+	s.setLeading(true)
+	logger.Info("node got elected as leader")
+	events.Broadcast(newLeaderElectedEvent(s.name))
+
+	select {
+	case <-ctx.Done():
+	case <-s.resignCh:
+		logger.Info("resigning from leading the service")
+	}
+	s.setLeading(false)
+
+	rctx, rcancel := context.WithTimeout(context.Background(), storeTimeout)
+	defer rcancel()
+	if err := election.Resign(rctx); err != nil {
+		logger.WithError(err).Warn("failed to resign from leader election")
+	}
+}
+
+// Leaders returns the peer leading each service registered
+func Leaders(ctx context.Context) ([]api.ServiceLeader, error) {
+	servicesMu.RLock()
+	names := make([]string, 0, len(services))
+	for name := range services {
+		names = append(names, name)
+	}
+	servicesMu.RUnlock()
+	sort.Strings(names)
+
+	leaders := make([]api.ServiceLeader, 0, len(names))
+	for _, name := range names {
+		l, err := getLeader(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		leaders = append(leaders, *l)
+	}
+	return leaders, nil
+}
+
+// Leader returns the peer leading the service
+func Leader(ctx context.Context, name string) (*api.ServiceLeader, error) {
+	if _, err := getService(name); err != nil {
+		return nil, err
+	}
+	return getLeader(ctx, name)
+}
+
+func getLeader(ctx context.Context, name string) (*api.ServiceLeader, error) {
+	ctx, cancel := context.WithTimeout(ctx, storeTimeout)
+	defer cancel()
+
+	l := &api.ServiceLeader{Service: name}
+	election := concurrency.NewElection(store.Store.Session, electionKey(name))
+	resp, err := election.Leader(ctx)
+	if err == concurrency.ErrElectionNoLeader {
+		return l, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	l.PeerID = string(resp.Kvs[0].Value)
+	if p, err := peer.GetPeer(l.PeerID); err == nil {
+		l.PeerName = p.Name
+	}
+	return l, nil
+}
+
+func newLeaderElectedEvent(name string) *api.Event {
+	data := map[string]string{
+		"service":   name,
+		"peer.id":   gdctx.MyUUID.String(),
+		"peer.name": gdctx.HostName,
+	}
+	return events.New(EventLeaderElected, data, true)
+}
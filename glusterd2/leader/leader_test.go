@@ -0,0 +1,35 @@
+package leader
+
+import (
+	"testing"
+
+	"github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResign(t *testing.T) {
+	Register("test-service")
+	assert.Equal(t, "test-service-leader", electionKey("test-service"))
+
+	assert.False(t, IsLeader("test-service"))
+	assert.Equal(t, errors.ErrNotLeader, Resign("test-service"))
+	assert.Equal(t, errors.ErrServiceNotFound, Resign("missing"))
+	assert.False(t, IsLeader("missing"))
+
+	s, err := getService("test-service")
+	require.NoError(t, err)
+	s.setLeading(true)
+	assert.True(t, IsLeader("test-service"))
+
+	// Resigning twice asks once
+	assert.NoError(t, Resign("test-service"))
+	assert.NoError(t, Resign("test-service"))
+	assert.Len(t, s.resignCh, 1)
+
+	// A resign asked for a previous term is void
+	s.setLeading(false)
+	assert.Len(t, s.resignCh, 0)
+	assert.False(t, IsLeader("test-service"))
+}
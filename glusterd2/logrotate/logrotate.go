@@ -0,0 +1,158 @@
+// Package logrotate rotates the log of glusterd2 and the logs of the gluster
+// daemons it manages when they grow beyond the configured size.
+package logrotate
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/transactionv2"
+	"github.com/gluster/glusterd2/pkg/logging"
+
+	log "github.com/sirupsen/logrus"
+	flag "github.com/spf13/pflag"
+	config "github.com/spf13/viper"
+)
+
+const (
+	intervalOpt = "log-rotate-interval"
+	maxSizeOpt  = "log-max-size"
+	maxFilesOpt = "log-max-files"
+	compressOpt = "log-compress"
+)
+
+var (
+	stopChan chan struct{}
+	stopOnce sync.Once
+
+	// rotateLock serializes the periodic and on demand rotations
+	rotateLock sync.Mutex
+)
+
+// InitFlags intializes the command line options for log rotation
+func InitFlags() {
+	flag.Duration(intervalOpt, 5*time.Minute, "Interval at which the size of the logs is checked for rotation. Set to 0 to disable size based rotation.")
+	flag.Int64(maxSizeOpt, 100, "Size in MB beyond which a log of glusterd2 or a daemon managed by it is rotated.")
+	flag.Int(maxFilesOpt, 5, "Number of rotated files retained per log.")
+	flag.Bool(compressOpt, true, "Compress rotated log files with gzip.")
+}
+
+// Start starts checking the size of the logs periodically
+func Start() {
+	interval := config.GetDuration(intervalOpt)
+	if interval <= 0 {
+		log.Info("size based log rotation disabled")
+		return
+	}
+
+	stopChan = make(chan struct{})
+	go transaction.UntilStop(func() {
+		Rotate(false)
+	}, interval, stopChan)
+}
+
+// Stop stops the periodic log rotation
+func Stop() {
+	if stopChan == nil {
+		return
+	}
+	stopOnce.Do(func() {
+		close(stopChan)
+	})
+}
+
+// daemonLogs returns the logs of the gluster daemons, which include the
+// bricks, self heal daemon and geo-replication workers, and the logs of the
+// embedded store
+func daemonLogs() ([]string, error) {
+	var logs []string
+	for _, dir := range []string{"glusterfs", "store"} {
+		dir = filepath.Join(config.GetString("logdir"), dir)
+		err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				if os.IsNotExist(err) {
+					return nil
+				}
+				return err
+			}
+			if info.Mode().IsRegular() && strings.HasSuffix(path, ".log") {
+				logs = append(logs, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return logs, err
+		}
+	}
+	return logs, nil
+}
+
+func needsRotation(path string, force bool) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	if force {
+		return info.Size() > 0
+	}
+	return info.Size() >= config.GetInt64(maxSizeOpt)*1024*1024
+}
+
+// Rotate rotates the logs which have grown beyond the configured size, or all
+// the logs if force is set. It returns the paths of the logs rotated.
+func Rotate(force bool) ([]string, error) {
+	rotateLock.Lock()
+	defer rotateLock.Unlock()
+
+	opts := logging.RotateOptions{
+		MaxFiles: config.GetInt(maxFilesOpt),
+		Compress: config.GetBool(compressOpt),
+	}
+
+	var (
+		rotated  []string
+		firstErr error
+	)
+
+	if path := logging.LogFile(); path != "" && needsRotation(path, force) {
+		if err := logging.Rotate(opts); err != nil {
+			log.WithError(err).WithField("log", path).Error("failed to rotate glusterd2 log")
+			firstErr = err
+		} else {
+			rotated = append(rotated, path)
+		}
+	}
+
+	logs, err := daemonLogs()
+	if err != nil {
+		log.WithError(err).Error("failed to list daemon logs")
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	// The daemons keep their logs open, so they are copied and truncated
+	// instead of being moved aside
+	opts.CopyTruncate = true
+	for _, path := range logs {
+		if !needsRotation(path, force) {
+			continue
+		}
+		if err := logging.RotateFile(path, opts); err != nil {
+			log.WithError(err).WithField("log", path).Error("failed to rotate daemon log")
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		rotated = append(rotated, path)
+	}
+
+	if len(rotated) > 0 {
+		log.WithField("logs", rotated).Info("rotated logs")
+	}
+	return rotated, firstErr
+}
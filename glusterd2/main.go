@@ -1,26 +1,48 @@
 package main
 
 import (
+	"context"
 	"os"
 	"os/signal"
 	"path"
 	"strings"
 	"time"
 
+	"github.com/gluster/glusterd2/glusterd2/adaptivethrottle"
 	"github.com/gluster/glusterd2/glusterd2/brickmux"
+	"github.com/gluster/glusterd2/glusterd2/ca"
 	"github.com/gluster/glusterd2/glusterd2/commands/volumes"
 	"github.com/gluster/glusterd2/glusterd2/conf"
+	"github.com/gluster/glusterd2/glusterd2/configsnap"
 	"github.com/gluster/glusterd2/glusterd2/daemon"
+	"github.com/gluster/glusterd2/glusterd2/drift"
 	"github.com/gluster/glusterd2/glusterd2/events"
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/halo"
+	"github.com/gluster/glusterd2/glusterd2/heartbeat"
+	"github.com/gluster/glusterd2/glusterd2/journal"
+	"github.com/gluster/glusterd2/glusterd2/leader"
+	"github.com/gluster/glusterd2/glusterd2/logrotate"
+	"github.com/gluster/glusterd2/glusterd2/mounts"
+	"github.com/gluster/glusterd2/glusterd2/orphanbricks"
 	"github.com/gluster/glusterd2/glusterd2/peer"
+	"github.com/gluster/glusterd2/glusterd2/plugin"
 	"github.com/gluster/glusterd2/glusterd2/pmap"
+	"github.com/gluster/glusterd2/glusterd2/quorum"
 	"github.com/gluster/glusterd2/glusterd2/servers"
+	"github.com/gluster/glusterd2/glusterd2/servers/rest"
+	"github.com/gluster/glusterd2/glusterd2/servers/sunrpc"
+	"github.com/gluster/glusterd2/glusterd2/shutdown"
+	"github.com/gluster/glusterd2/glusterd2/simulate"
 	"github.com/gluster/glusterd2/glusterd2/store"
+	transactionv1 "github.com/gluster/glusterd2/glusterd2/transaction"
 	"github.com/gluster/glusterd2/glusterd2/transactionv2"
 	"github.com/gluster/glusterd2/glusterd2/transactionv2/cleanuphandler"
+	"github.com/gluster/glusterd2/glusterd2/upgrade"
+	"github.com/gluster/glusterd2/glusterd2/usagemonitor"
 	gdutils "github.com/gluster/glusterd2/glusterd2/utils"
 	"github.com/gluster/glusterd2/glusterd2/volgen"
+	"github.com/gluster/glusterd2/glusterd2/volumetrash"
 	"github.com/gluster/glusterd2/glusterd2/xlator"
 	"github.com/gluster/glusterd2/pkg/errors"
 	"github.com/gluster/glusterd2/pkg/firewalld"
@@ -88,16 +110,28 @@ func main() {
 	}
 
 	transaction.StartTxnEngine()
+	// Start contesting the elections of the leaders of the singleton
+	// services
+	leader.Start()
 	cleanuphandler.StartCleanupLeader()
+	upgrade.Start()
 	// Start the events framework after store is up
 	if err := events.Start(); err != nil {
 		log.WithError(err).Fatal("Failed to start internal events framework")
 	}
+	transactionv1.EvictRemovedPeers()
 
 	if err := peer.AddSelfDetails(); err != nil {
 		log.WithError(err).Fatal("Could not add self details into etcd")
 	}
 
+	// Add the virtual peers hosted by this peer, if any, and start running
+	// the transactions on them
+	if err := simulate.Start(); err != nil {
+		log.WithError(err).Fatal("Failed to add the simulated peers")
+	}
+	transaction.StartHostedTxnEngines()
+
 	// Load the default group option map into the store
 	if err := volumecommands.InitDefaultGroupOptions(); err != nil {
 		log.WithError(err).Fatal("Failed to load the default group options")
@@ -108,8 +142,9 @@ func main() {
 		log.WithError(err).Fatal("Failed to generate local auth token")
 	}
 
-	// Create the Opencensus Jaeger exporter
-	if exporter := tracing.InitJaegerExporter(); exporter != nil {
+	// Create the Opencensus Jaeger and OTLP exporters
+	jaegerExporter := tracing.InitJaegerExporter()
+	if tracing.InitOTLPExporter() || jaegerExporter != nil {
 		defer tracing.Flush()
 	}
 
@@ -118,10 +153,25 @@ func main() {
 		log.WithError(err).Fatal("failed to load volgen templates")
 	}
 
+	// Load the custom xlators registered by users and keep them in sync
+	// with the other peers
+	if err := volgen.LoadCustomXlators(); err != nil {
+		log.WithError(err).Fatal("failed to load custom xlators")
+	}
+	go volgen.WatchCustomXlators()
+
+	// Enable the plugins as they are in the cluster and follow the
+	// plugins enabled or disabled later on
+	if err := plugin.Init(); err != nil {
+		log.WithError(err).Fatal("failed to load the state of the plugins")
+	}
+	go plugin.Watch()
+
 	// Start all servers (rest, peerrpc, sunrpc) managed by suture supervisor
 	super := initGD2Supervisor()
 	super.ServeBackground()
 	super.Add(servers.New())
+	gdctx.Supervisor = super
 
 	// Start dbus connection (optional for notifying firewalld)
 	if err := firewalld.Init(); err != nil {
@@ -133,6 +183,10 @@ func main() {
 	// Mount all Local Bricks
 	gdutils.MountLocalBricks()
 
+	// Verify the local actions interrupted by a crash, before the daemons
+	// are started
+	journal.Replay()
+
 	// Restart previously running daemons
 	daemon.StartAllDaemons()
 
@@ -141,6 +195,50 @@ func main() {
 		log.WithError(err).Fatal("bmux.Reconcile() failed")
 	}
 
+	// Start restarting the daemons found down
+	daemon.StartSupervisor()
+
+	// Start enforcing server-quorum, once the bricks are running
+	quorum.Start()
+
+	// Start collecting brick and volume utilization
+	usagemonitor.Start()
+
+	// Start sending heartbeats to the other peers
+	heartbeat.Start()
+
+	// Start measuring the latency to the other peers, for halo replication
+	halo.Start()
+
+	// Start renewing the certificate of this peer before it expires
+	ca.Start()
+
+	// Start taking snapshots of the configuration of the cluster
+	configsnap.Start()
+
+	// Start rotating logs which grow beyond the configured size
+	logrotate.Start()
+
+	// Start detecting the brick processes left serving no brick
+	orphanbricks.Start()
+
+	// Start detecting the volumes drifting from their state in the store
+	drift.Start()
+
+	// Mount the managed mounts of this peer, and mount them again when
+	// found down
+	mounts.Start()
+
+	// Start throttling the crawls of the volumes whose bricks see a high
+	// latency
+	adaptivethrottle.Start()
+
+	// Start purging the deleted volumes at the end of their grace period
+	volumetrash.Start()
+
+	// Report to systemd that glusterd2 is ready, once the store is healthy
+	go notifySystemdReady()
+
 	// Use the main goroutine as signal handling loop
 	sigCh := make(chan os.Signal)
 	signal.Notify(sigCh)
@@ -151,12 +249,9 @@ func main() {
 			fallthrough
 		case unix.SIGINT:
 			log.Info("Received SIGTERM. Stopping GlusterD")
+			notifySystemd("STOPPING=1")
 			gdctx.IsTerminating = true
-			transaction.StopTxnEngine()
-			cleanuphandler.StopCleanupLeader()
-			super.Stop()
-			events.Stop()
-			store.Close()
+			shutdown.Run(shutdownSteps(super)...)
 			_ = os.Remove(config.GetString("pidfile"))
 			log.Info("Stopped GlusterD")
 			return
@@ -185,6 +280,67 @@ func initGD2Supervisor() *suture.Supervisor {
 	return suture.New("gd2-main", suture.Spec{Log: superlogger, Timeout: 5 * time.Second})
 }
 
+// shutdownSteps returns the steps of the shutdown of glusterd2, in order: the
+// requests and transactions in progress are given the grace period to end,
+// the clients are disconnected to fail over to other peers, the bricks are
+// stopped if configured to, and the store is closed last.
+func shutdownSteps(super *suture.Supervisor) []shutdown.Step {
+	restDone := make(chan struct{})
+	return []shutdown.Step{
+		{Name: "rest", Run: func(ctx context.Context) {
+			// The requests being handled are answered once their
+			// transactions end
+			go func() {
+				defer close(restDone)
+				if err := rest.Shutdown(ctx); err != nil {
+					log.WithError(err).Warn("ReST requests still in progress were interrupted")
+				}
+			}()
+		}},
+		{Name: "transactions", Run: func(ctx context.Context) {
+			if err := transactionv1.Drain(ctx); err != nil {
+				log.WithError(err).Warn("transactions still in progress were failed")
+			}
+			<-restDone
+			transaction.StopTxnEngine()
+			cleanuphandler.StopCleanupLeader()
+		}},
+		{Name: "services", Run: func(ctx context.Context) {
+			upgrade.Stop()
+			leader.Stop()
+			daemon.StopSupervisor()
+			quorum.Stop()
+			usagemonitor.Stop()
+			heartbeat.Stop()
+			halo.Stop()
+			ca.Stop()
+			configsnap.Stop()
+			logrotate.Stop()
+			orphanbricks.Stop()
+			drift.Stop()
+			mounts.Stop()
+			adaptivethrottle.Stop()
+			volumetrash.Stop()
+		}},
+		{Name: "sunrpc", Run: func(ctx context.Context) {
+			n := sunrpc.Shutdown()
+			log.WithField("clients", n).Info("disconnected the SunRPC clients")
+		}},
+		{Name: "bricks", Run: func(ctx context.Context) {
+			if shutdown.StopBricksEnabled() {
+				shutdown.StopBricks(ctx)
+			}
+		}},
+		{Name: "servers", Run: func(ctx context.Context) {
+			super.Stop()
+		}},
+		{Name: "store", Run: func(ctx context.Context) {
+			events.Stop()
+			store.Close()
+		}},
+	}
+}
+
 func createDirectories() error {
 	dirs := []string{config.GetString("localstatedir"),
 		config.GetString("rundir"), config.GetString("logdir"),
@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"expvar"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// expvarCollector exports the transaction and store counters which are
+// already maintained as expvars
+type expvarCollector struct {
+	txns           *prometheus.Desc
+	txnsInProgress *prometheus.Desc
+	storeOps       *prometheus.Desc
+}
+
+func newExpvarCollector() *expvarCollector {
+	return &expvarCollector{
+		txns:           NewDesc("transaction", "total", "Number of transactions initiated on this node", "result"),
+		txnsInProgress: NewDesc("transaction", "in_progress", "Number of transactions initiated on this node which are in progress"),
+		storeOps:       NewDesc("store", "operations_total", "Number of store operations done by this node", "op"),
+	}
+}
+
+func (c *expvarCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.txns
+	ch <- c.txnsInProgress
+	ch <- c.storeOps
+}
+
+// expvarInt returns the value of the integer key in the named expvar map
+func expvarInt(name, key string) (float64, bool) {
+	m, ok := expvar.Get(name).(*expvar.Map)
+	if !ok {
+		return 0, false
+	}
+	v, ok := m.Get(key).(*expvar.Int)
+	if !ok {
+		return 0, false
+	}
+	return float64(v.Value()), true
+}
+
+func (c *expvarCollector) Collect(ch chan<- prometheus.Metric) {
+	for result, key := range map[string]string{
+		"success": "initiated_txn_success",
+		"failure": "initiated_txn_failure",
+	} {
+		v, _ := expvarInt("txn", key)
+		ch <- prometheus.MustNewConstMetric(c.txns, prometheus.CounterValue, v, result)
+	}
+
+	if v, ok := expvarInt("txn", "initiated_txn_in_progress"); ok {
+		ch <- prometheus.MustNewConstMetric(c.txnsInProgress, prometheus.GaugeValue, v)
+	}
+
+	for _, op := range []string{"get", "put", "delete", "txn"} {
+		v, _ := expvarInt("store", op)
+		ch <- prometheus.MustNewConstMetric(c.storeOps, prometheus.CounterValue, v, op)
+	}
+}
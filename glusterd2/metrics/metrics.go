@@ -0,0 +1,46 @@
+// Package metrics exports glusterd2 and cluster metrics in the Prometheus
+// exposition format
+package metrics
+
+import (
+	"net/http"
+	"os"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "glusterd2"
+
+var registry = prometheus.NewRegistry()
+
+func init() {
+	registry.MustRegister(prometheus.NewGoCollector())
+	registry.MustRegister(prometheus.NewProcessCollector(os.Getpid(), namespace))
+	registry.MustRegister(newVolumeCollector())
+	registry.MustRegister(newExpvarCollector())
+	registry.MustRegister(newStoreCollector())
+}
+
+// Register registers a collector, to be gathered when metrics are
+// scraped. Plugins use this to export their own metrics.
+func Register(c prometheus.Collector) {
+	registry.MustRegister(c)
+}
+
+// Handler returns a http.Handler which serves all registered metrics
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// NewDesc returns a metric description with the glusterd2 namespace
+func NewDesc(subsystem, name, help string, labels ...string) *prometheus.Desc {
+	return prometheus.NewDesc(prometheus.BuildFQName(namespace, subsystem, name), help, labels, nil)
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
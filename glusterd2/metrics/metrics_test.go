@@ -0,0 +1,80 @@
+package metrics
+
+import (
+	"expvar"
+	"testing"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/testutils"
+
+	"github.com/pborman/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setExpvar(name, key string, value int64) {
+	m, ok := expvar.Get(name).(*expvar.Map)
+	if !ok {
+		m = expvar.NewMap(name)
+	}
+	v := new(expvar.Int)
+	v.Set(value)
+	m.Set(key, v)
+}
+
+func TestExpvarCollector(t *testing.T) {
+	setExpvar("txn", "initiated_txn_success", 3)
+	setExpvar("txn", "initiated_txn_failure", 1)
+	setExpvar("txn", "initiated_txn_in_progress", 2)
+	setExpvar("store", "get", 5)
+
+	values, err := testutils.CollectMetrics(newExpvarCollector(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, 3.0, values[`glusterd2_transaction_total{result="success"}`])
+	assert.Equal(t, 1.0, values[`glusterd2_transaction_total{result="failure"}`])
+	assert.Equal(t, 2.0, values["glusterd2_transaction_in_progress"])
+	assert.Equal(t, 5.0, values[`glusterd2_store_operations_total{op="get"}`])
+	// Counters missing from the expvars are reported as zero
+	assert.Contains(t, values, `glusterd2_store_operations_total{op="txn"}`)
+}
+
+func TestStoreCollector(t *testing.T) {
+	// Only the store being down is reported without a store
+	values, err := testutils.CollectMetrics(newStoreCollector(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]float64{"glusterd2_store_up": 0}, values)
+}
+
+func TestVolumeCollector(t *testing.T) {
+	remote := brick.Brickinfo{ID: uuid.NewRandom(), PeerID: uuid.NewRandom(), Path: "/bricks/b1"}
+	volumes := []*volume.Volinfo{
+		{
+			Name:    "gv0",
+			Type:    volume.Replicate,
+			State:   volume.VolStarted,
+			Subvols: []volume.Subvol{{Bricks: []brick.Brickinfo{remote, remote}}},
+		},
+		{
+			Name:  "gv1",
+			Type:  volume.Distribute,
+			State: volume.VolStopped,
+		},
+	}
+
+	c := newVolumeCollector()
+	values, err := testutils.CollectMetrics(c, func(ch chan<- prometheus.Metric) {
+		c.collectVolumes(ch, volumes)
+	})
+	require.NoError(t, err)
+
+	// The bricks of other nodes are not reported
+	assert.Equal(t, map[string]float64{
+		"glusterd2_volumes": 2,
+		`glusterd2_volume_started{type="Replicate",volume="gv0"}`:  1,
+		`glusterd2_volume_started{type="Distribute",volume="gv1"}`: 0,
+		`glusterd2_volume_bricks{volume="gv0"}`:                    2,
+		`glusterd2_volume_bricks{volume="gv1"}`:                    0,
+	}, values)
+}
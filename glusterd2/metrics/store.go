@@ -0,0 +1,65 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	"github.com/gluster/glusterd2/glusterd2/store"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const storeHealthTimeout = 2 * time.Second
+
+// storeCollector exports the health of the store as seen by this node and
+// the liveness of peers
+type storeCollector struct {
+	storeUp      *prometheus.Desc
+	storeLatency *prometheus.Desc
+	peers        *prometheus.Desc
+	peersOnline  *prometheus.Desc
+}
+
+func newStoreCollector() *storeCollector {
+	return &storeCollector{
+		storeUp:      NewDesc("store", "up", "Whether the store is reachable from this node"),
+		storeLatency: NewDesc("store", "latency_seconds", "Time taken for a store read from this node"),
+		peers:        NewDesc("", "peers", "Number of peers in the cluster"),
+		peersOnline:  NewDesc("", "peers_online", "Number of peers which are connected to the store"),
+	}
+}
+
+func (c *storeCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.storeUp
+	ch <- c.storeLatency
+	ch <- c.peers
+	ch <- c.peersOnline
+}
+
+func (c *storeCollector) Collect(ch chan<- prometheus.Metric) {
+	if store.Store == nil {
+		ch <- prometheus.MustNewConstMetric(c.storeUp, prometheus.GaugeValue, 0)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), storeHealthTimeout)
+	defer cancel()
+
+	// Count of liveness keys is the number of peers connected to the store
+	start := time.Now()
+	resp, err := store.Store.Get(ctx, store.LivenessKeyPrefix, clientv3.WithPrefix(), clientv3.WithCountOnly())
+	latency := time.Since(start)
+
+	ch <- prometheus.MustNewConstMetric(c.storeUp, prometheus.GaugeValue, boolToFloat(err == nil))
+	if err != nil {
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(c.storeLatency, prometheus.GaugeValue, latency.Seconds())
+	ch <- prometheus.MustNewConstMetric(c.peersOnline, prometheus.GaugeValue, float64(resp.Count))
+
+	if peers, err := peer.GetPeerIDs(); err == nil {
+		ch <- prometheus.MustNewConstMetric(c.peers, prometheus.GaugeValue, float64(len(peers)))
+	}
+}
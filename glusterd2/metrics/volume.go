@@ -0,0 +1,130 @@
+package metrics
+
+import (
+	"context"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+)
+
+// volumeCollector exports the state of all volumes and the state and
+// utilization of the bricks local to this node
+type volumeCollector struct {
+	volumes       *prometheus.Desc
+	volumeStarted *prometheus.Desc
+	volumeBricks  *prometheus.Desc
+	volumeUsage   map[string]*prometheus.Desc
+	brickUp       *prometheus.Desc
+	brickCapacity *prometheus.Desc
+	brickUsed     *prometheus.Desc
+	brickFree     *prometheus.Desc
+}
+
+func newVolumeCollector() *volumeCollector {
+	brickLabels := []string{"volume", "brick", "host", "path"}
+	return &volumeCollector{
+		volumes:       NewDesc("", "volumes", "Number of volumes in the cluster"),
+		volumeStarted: NewDesc("volume", "started", "Whether the volume is started", "volume", "type"),
+		volumeBricks:  NewDesc("volume", "bricks", "Number of bricks in the volume", "volume"),
+		volumeUsage: map[string]*prometheus.Desc{
+			"capacity":    NewDesc("volume", "capacity_bytes", "Usable capacity of the volume", "volume"),
+			"used":        NewDesc("volume", "used_bytes", "Used space on the volume", "volume"),
+			"free":        NewDesc("volume", "free_bytes", "Free space on the volume", "volume"),
+			"inodes":      NewDesc("volume", "inodes", "Number of inodes of the volume", "volume"),
+			"inodes_used": NewDesc("volume", "inodes_used", "Number of used inodes of the volume", "volume"),
+			"inodes_free": NewDesc("volume", "inodes_free", "Number of free inodes of the volume", "volume"),
+		},
+		brickUp:       NewDesc("brick", "up", "Whether the brick process is running", brickLabels...),
+		brickCapacity: NewDesc("brick", "capacity_bytes", "Capacity of the brick filesystem", brickLabels...),
+		brickUsed:     NewDesc("brick", "used_bytes", "Used space on the brick filesystem", brickLabels...),
+		brickFree:     NewDesc("brick", "free_bytes", "Free space on the brick filesystem", brickLabels...),
+	}
+}
+
+func (c *volumeCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.volumes
+	ch <- c.volumeStarted
+	ch <- c.volumeBricks
+	for _, d := range c.volumeUsage {
+		ch <- d
+	}
+	ch <- c.brickUp
+	ch <- c.brickCapacity
+	ch <- c.brickUsed
+	ch <- c.brickFree
+}
+
+func (c *volumeCollector) Collect(ch chan<- prometheus.Metric) {
+	volumes, err := volume.GetVolumes(context.TODO())
+	if err != nil {
+		log.WithError(err).Error("metrics: failed to get volumes")
+		return
+	}
+
+	c.collectVolumes(ch, volumes)
+}
+
+// collectVolumes exports the state of the volumes and of their bricks
+// local to this node
+func (c *volumeCollector) collectVolumes(ch chan<- prometheus.Metric, volumes []*volume.Volinfo) {
+	ch <- prometheus.MustNewConstMetric(c.volumes, prometheus.GaugeValue, float64(len(volumes)))
+
+	for _, v := range volumes {
+		ch <- prometheus.MustNewConstMetric(c.volumeStarted, prometheus.GaugeValue,
+			boolToFloat(v.State == volume.VolStarted), v.Name, v.Type.String())
+		ch <- prometheus.MustNewConstMetric(c.volumeBricks, prometheus.GaugeValue,
+			float64(len(v.GetBricks())), v.Name)
+		c.collectUsage(ch, v)
+
+		for _, b := range v.GetLocalBricks() {
+			c.collectBrick(ch, b)
+		}
+	}
+}
+
+// collectUsage exports the utilization of the volume as last aggregated by
+// the usage monitor
+func (c *volumeCollector) collectUsage(ch chan<- prometheus.Metric, v *volume.Volinfo) {
+	u, err := volume.GetUsage(v.Name)
+	if err != nil {
+		log.WithError(err).WithField("volume", v.Name).Error("metrics: failed to get volume usage")
+		return
+	}
+	if u == nil {
+		return
+	}
+
+	values := map[string]uint64{
+		"capacity":    u.Capacity,
+		"used":        u.Used,
+		"free":        u.Free,
+		"inodes":      u.Inodes,
+		"inodes_used": u.InodesUsed,
+		"inodes_free": u.InodesFree,
+	}
+	for k, d := range c.volumeUsage {
+		ch <- prometheus.MustNewConstMetric(d, prometheus.GaugeValue, float64(values[k]), v.Name)
+	}
+}
+
+func (c *volumeCollector) collectBrick(ch chan<- prometheus.Metric, b brick.Brickinfo) {
+	labels := []string{b.VolumeName, b.ID.String(), b.Hostname, b.Path}
+
+	status, err := volume.BrickStatus(b, nil)
+	if err != nil {
+		log.WithError(err).WithField("brick", b.String()).Error("metrics: failed to get brick status")
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(c.brickUp, prometheus.GaugeValue, boolToFloat(status.Online), labels...)
+
+	// Size is not filled if statfs on the brick path failed
+	if status.Size.Capacity == 0 {
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(c.brickCapacity, prometheus.GaugeValue, float64(status.Size.Capacity), labels...)
+	ch <- prometheus.MustNewConstMetric(c.brickUsed, prometheus.GaugeValue, float64(status.Size.Used), labels...)
+	ch <- prometheus.MustNewConstMetric(c.brickFree, prometheus.GaugeValue, float64(status.Size.Free), labels...)
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/namespace"
 	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
 	"github.com/gluster/glusterd2/pkg/utils"
 
@@ -19,16 +20,25 @@ const (
 
 var (
 	requiredClaims = []string{"iss", "exp", "qsh"}
+
+	// tokenSecret returns the secret of the API token issuing a request,
+	// and the namespace the token is scoped to
+	tokenSecret = namespace.TokenSecret
 )
 
-func getAuthSecret(issuer string) string {
+// getAuthSecret returns the secret the requests of the issuer are signed
+// with, and the namespace the issuer is scoped to. The internal user is not
+// scoped to any namespace.
+func getAuthSecret(issuer string) (string, string) {
 	if issuer == internalUser {
-		return gdctx.LocalAuthToken
+		return gdctx.LocalAuthToken, ""
 	}
 
-	// TODO: Look for issuer secret in etcd if not internal user, this depends on User management feature
-
-	return ""
+	secret, ns, err := tokenSecret(issuer)
+	if err != nil {
+		return "", ""
+	}
+	return secret, ns
 }
 
 //isRestAuthRequired return false for few URL which doesn't require authentication
@@ -37,6 +47,11 @@ func isRestAuthRequired(url string) bool {
 	case "/ping":
 		fallthrough
 	case "/endpoints":
+		fallthrough
+	case "/openapi.json":
+		fallthrough
+	case "/v1/peers/admit":
+		// Authenticated by the join token of the request
 		return false
 	default:
 		return true
@@ -68,6 +83,7 @@ func Auth(next http.Handler) http.Handler {
 		}
 
 		// Verify JWT token with additional validations for Claims
+		var ns string
 		token, err := jwt.Parse(authHeaderParts[1], func(token *jwt.Token) (interface{}, error) {
 			claims, ok := token.Claims.(jwt.MapClaims)
 			if !ok {
@@ -86,7 +102,9 @@ func Auth(next http.Handler) http.Handler {
 				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 			}
 
-			secret := getAuthSecret(claims["iss"].(string))
+			issuer, _ := claims["iss"].(string)
+			var secret string
+			secret, ns = getAuthSecret(issuer)
 			if secret == "" {
 				return nil, fmt.Errorf("invalid App ID: %s", claims["iss"])
 			}
@@ -111,6 +129,24 @@ func Auth(next http.Handler) http.Handler {
 		// TODO: Filter URLs here if any role based control of APIs, this depends on User management feature
 
 		// Authentication is successful, continue serving the request
-		next.ServeHTTP(w, r)
+		issuer, _ := token.Claims.(jwt.MapClaims)["iss"].(string)
+		ctx = gdctx.WithReqUser(ctx, issuer)
+		if ns != "" {
+			ctx = gdctx.WithReqNamespace(ctx, ns)
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
+
+// RequireAdmin is a middleware which allows only the admin user to access
+// the handler. The internal user used by glustercli is the only admin user
+// for now. All the requests are allowed if REST authentication is disabled.
+func RequireAdmin(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if gdctx.RESTAPIAuthEnabled && gdctx.GetReqUser(r.Context()) != internalUser {
+			restutils.SendHTTPError(r.Context(), w, http.StatusForbidden, errors.New("only admin user is allowed to access this endpoint"))
+			return
+		}
+		next(w, r)
+	}
+}
@@ -13,22 +13,36 @@ import (
 
 	jwt "github.com/dgrijalva/jwt-go"
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/pkg/errors"
 	config "github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestGetAuthSecret(t *testing.T) {
-	secret := getAuthSecret("test")
+	defer func(f func(string) (string, string, error)) { tokenSecret = f }(tokenSecret)
+	tokenSecret = func(id string) (string, string, error) {
+		if id == "tenant-token" {
+			return "tenant-secret", "tenant", nil
+		}
+		return "", "", errors.ErrNamespaceTokenNotFound
+	}
+
+	secret, _ := getAuthSecret("test")
 	assert.Empty(t, secret)
 
+	secret, ns := getAuthSecret("tenant-token")
+	assert.Equal(t, "tenant-secret", secret)
+	assert.Equal(t, "tenant", ns)
+
 	config.Set("restauth", true)
 	config.Set("localstatedir", "")
 	err := gdctx.GenerateLocalAuthToken()
 	assert.Nil(t, err)
 	os.Remove("auth")
 
-	secret = getAuthSecret("glustercli")
+	secret, ns = getAuthSecret("glustercli")
 	assert.NotNil(t, secret)
+	assert.Empty(t, ns)
 }
 
 func getAuthToken(username string, password string, r *http.Request) {
@@ -102,3 +116,24 @@ func GetTestHandler() http.HandlerFunc {
 	}
 	return http.HandlerFunc(fn)
 }
+
+func TestRequireAdmin(t *testing.T) {
+	handler := RequireAdmin(GetTestHandler())
+
+	gdctx.RESTAPIAuthEnabled = false
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest("GET", "/", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	gdctx.RESTAPIAuthEnabled = true
+	defer func() { gdctx.RESTAPIAuthEnabled = false }()
+
+	w = httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/", nil)
+	handler(w, r.WithContext(gdctx.WithReqUser(r.Context(), "testuser")))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = httptest.NewRecorder()
+	handler(w, r.WithContext(gdctx.WithReqUser(r.Context(), "glustercli")))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+)
+
+var (
+	errBodyTooLarge = errors.New("request body is too large")
+	errBodyTimeout  = errors.New("request body was not received in time")
+)
+
+// ReadBody reads the body of the requests, up to maxBytes, before they are
+// handled. The requests with a larger body are answered with 413, and those
+// whose body is not received before the read timeout of the server with
+// 408, instead of tying up their handlers. The body is not limited if
+// maxBytes is not positive.
+func ReadBody(maxBytes int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if maxBytes <= 0 || r.Body == nil || r.Body == http.NoBody {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx := r.Context()
+			if r.ContentLength > maxBytes {
+				restutils.SendHTTPError(ctx, w, http.StatusRequestEntityTooLarge, errBodyTooLarge)
+				return
+			}
+
+			body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxBytes+1))
+			r.Body.Close()
+			if err != nil {
+				if e, ok := err.(net.Error); ok && e.Timeout() {
+					restutils.SendHTTPError(ctx, w, http.StatusRequestTimeout, errBodyTimeout)
+					return
+				}
+				restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+				return
+			}
+			if int64(len(body)) > maxBytes {
+				restutils.SendHTTPError(ctx, w, http.StatusRequestEntityTooLarge, errBodyTooLarge)
+				return
+			}
+
+			r.Body = ioutil.NopCloser(bytes.NewReader(body))
+			next.ServeHTTP(w, r)
+		})
+	}
+}
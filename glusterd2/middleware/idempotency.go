@@ -0,0 +1,275 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/coreos/etcd/clientv3"
+	flag "github.com/spf13/pflag"
+	config "github.com/spf13/viper"
+)
+
+const (
+	idempotencyTTLOpt = "idempotency-key-ttl"
+
+	// idempotencyPrefix is the prefix of the keys in the store recording
+	// the requests made with an Idempotency-Key
+	idempotencyPrefix = "idempotency/"
+
+	// maxRecordedBody is the largest response recorded for the retries of
+	// a request. The requests with a larger response are run again when
+	// retried.
+	maxRecordedBody = 1 << 20
+)
+
+// InitFlags intializes the command line options for the REST middlewares
+func InitFlags() {
+	flag.Duration(idempotencyTTLOpt, 24*time.Hour, "Time the response to a request with an Idempotency-Key is kept for its retries.")
+}
+
+// idempotencyRecord is the record of a request made with an Idempotency-Key
+type idempotencyRecord struct {
+	// Fingerprint tells the request apart from the other requests made
+	// with the same key
+	Fingerprint string      `json:"fingerprint"`
+	Done        bool        `json:"done"`
+	Status      int         `json:"status,omitempty"`
+	Header      http.Header `json:"header,omitempty"`
+	Body        []byte      `json:"body,omitempty"`
+}
+
+// idempotencyKeys records the requests made with an Idempotency-Key
+type idempotencyKeys interface {
+	reserve(ctx context.Context, key string, rec *idempotencyRecord) (*idempotencyRecord, int64, error)
+	complete(ctx context.Context, key string, rec *idempotencyRecord) error
+	release(ctx context.Context, key string) error
+}
+
+// storeKeys records the requests made with an Idempotency-Key in the store,
+// for them to be seen by all the peers
+type storeKeys struct{}
+
+// reserve records the request as in progress, if no request was recorded
+// for the key yet, and returns the revision of the store the key was created
+// at. The record of another request is returned instead if there is one. The
+// record of a request in progress is removed if the peer serving it goes
+// down.
+func (storeKeys) reserve(ctx context.Context, key string, rec *idempotencyRecord) (*idempotencyRecord, int64, error) {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	resp, err := store.Txn(ctx).
+		If(clientv3.Compare(clientv3.CreateRevision(key), "=", 0)).
+		Then(clientv3.OpPut(key, string(data), clientv3.WithLease(store.Store.Session.Lease()))).
+		Else(clientv3.OpGet(key)).
+		Commit()
+	if err != nil {
+		return nil, 0, err
+	}
+	if resp.Succeeded {
+		return nil, resp.Header.Revision, nil
+	}
+
+	kvs := resp.Responses[0].GetResponseRange().Kvs
+	if len(kvs) == 0 {
+		// The other request was just released
+		return nil, 0, gderrors.ErrIdempotencyKeyInUse
+	}
+	var old idempotencyRecord
+	if err := json.Unmarshal(kvs[0].Value, &old); err != nil {
+		return nil, 0, err
+	}
+	return &old, kvs[0].CreateRevision, nil
+}
+
+// complete records the response to the request, kept for
+// idempotency-key-ttl
+func (storeKeys) complete(ctx context.Context, key string, rec *idempotencyRecord) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+
+	ttl := int64(config.GetDuration(idempotencyTTLOpt) / time.Second)
+	if ttl <= 0 {
+		ttl = 1
+	}
+	lease, err := store.Store.Grant(ctx, ttl)
+	if err != nil {
+		return err
+	}
+	_, err = store.Put(ctx, key, string(data), clientv3.WithLease(lease.ID))
+	return err
+}
+
+// release removes the record of the request
+func (storeKeys) release(ctx context.Context, key string) error {
+	_, err := store.Delete(ctx, key)
+	return err
+}
+
+// idempotencyKey returns the key in the store of the Idempotency-Key of a
+// user. The keys of the users are apart, for a user not to get the responses
+// to the requests of another.
+func idempotencyKey(user, key string) string {
+	sum := sha256.Sum256([]byte(user + "\x00" + key))
+	return idempotencyPrefix + hex.EncodeToString(sum[:])
+}
+
+// fingerprint returns the fingerprint of the request of the method, URI and
+// body
+func fingerprint(method, uri string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method + " " + uri + "\x00"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// recordingWriter records the response it writes, for the retries of the
+// request
+type recordingWriter struct {
+	http.ResponseWriter
+	status   int
+	body     bytes.Buffer
+	overflow bool
+}
+
+func (w *recordingWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *recordingWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	if !w.overflow {
+		if w.body.Len()+len(b) > maxRecordedBody {
+			w.overflow = true
+			w.body.Reset()
+		} else {
+			w.body.Write(b)
+		}
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *recordingWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// replay sends the response recorded for the request
+func replay(w http.ResponseWriter, rec *idempotencyRecord, op int64) {
+	for k, v := range rec.Header {
+		w.Header()[k] = v
+	}
+	w.Header().Set(api.IdempotentReplayedHeader, "true")
+	w.Header().Set(api.OperationIDHeader, strconv.FormatInt(op, 10))
+	w.WriteHeader(rec.Status)
+	w.Write(rec.Body)
+}
+
+// Idempotent is a middleware which runs the requests made with an
+// Idempotency-Key once, across all the peers. The retries of a request are
+// answered with the response it got, without being run again, so that a
+// client retrying after a timeout does not create a volume twice. A retry
+// sent while the request is still in progress is refused with 409, and a
+// request reusing the key of another request with 422.
+//
+// The responses with a 5xx status are not recorded, the retries of those
+// requests are run again.
+func Idempotent(next http.HandlerFunc) http.HandlerFunc {
+	return idempotent(storeKeys{}, next)
+}
+
+func idempotent(keys idempotencyKeys, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		idemKey := r.Header.Get(api.IdempotencyKeyHeader)
+		if idemKey == "" || r.Method == http.MethodGet || r.Method == http.MethodHead {
+			next(w, r)
+			return
+		}
+
+		ctx := r.Context()
+		logger := gdctx.GetReqLogger(ctx)
+
+		var body []byte
+		if r.Body != nil {
+			var err error
+			if body, err = ioutil.ReadAll(r.Body); err != nil {
+				restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+				return
+			}
+			r.Body.Close()
+			r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		}
+
+		key := idempotencyKey(gdctx.GetReqUser(ctx), idemKey)
+		rec := &idempotencyRecord{Fingerprint: fingerprint(r.Method, r.URL.RequestURI(), body)}
+
+		old, op, err := keys.reserve(ctx, key, rec)
+		if err != nil {
+			status, err := restutils.ErrToStatusCode(err)
+			restutils.SendHTTPError(ctx, w, status, err)
+			return
+		}
+		if old != nil {
+			switch {
+			case old.Fingerprint != rec.Fingerprint:
+				restutils.SendHTTPError(ctx, w, http.StatusUnprocessableEntity, gderrors.ErrIdempotencyKeyReused)
+			case !old.Done:
+				restutils.SendHTTPError(ctx, w, http.StatusConflict, gderrors.ErrIdempotencyKeyInUse)
+			default:
+				replay(w, old, op)
+			}
+			return
+		}
+
+		w.Header().Set(api.OperationIDHeader, strconv.FormatInt(op, 10))
+		rw := &recordingWriter{ResponseWriter: w}
+		next(rw, r)
+
+		// The request is recorded even if its client went away, as it
+		// is then likely to be retried
+		ctx = gdctx.WithoutCancel(ctx)
+		if rw.status == 0 {
+			rw.status = http.StatusOK
+		}
+		if rw.status >= 500 || rw.overflow {
+			if err := keys.release(ctx, key); err != nil {
+				logger.WithError(err).Warn("failed to release the idempotency key")
+			}
+			return
+		}
+
+		rec.Done = true
+		rec.Status = rw.status
+		rec.Header = w.Header()
+		rec.Body = rw.body.Bytes()
+		if err := keys.complete(ctx, key, rec); err != nil {
+			logger.WithError(err).Warn("failed to record the response for the idempotency key")
+			if err := keys.release(ctx, key); err != nil {
+				logger.WithError(err).Warn("failed to release the idempotency key")
+			}
+		}
+	}
+}
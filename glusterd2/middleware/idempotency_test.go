@@ -0,0 +1,104 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeKeys keeps the idempotency records in memory
+type fakeKeys struct {
+	recs map[string]*idempotencyRecord
+	ops  map[string]int64
+	rev  int64
+}
+
+func (f *fakeKeys) reserve(ctx context.Context, key string, rec *idempotencyRecord) (*idempotencyRecord, int64, error) {
+	if old, ok := f.recs[key]; ok {
+		return old, f.ops[key], nil
+	}
+	f.rev++
+	f.recs[key] = rec
+	f.ops[key] = f.rev
+	return nil, f.rev, nil
+}
+
+func (f *fakeKeys) complete(ctx context.Context, key string, rec *idempotencyRecord) error {
+	f.recs[key] = rec
+	return nil
+}
+
+func (f *fakeKeys) release(ctx context.Context, key string) error {
+	delete(f.recs, key)
+	delete(f.ops, key)
+	return nil
+}
+
+func TestIdempotent(t *testing.T) {
+	keys := &fakeKeys{recs: make(map[string]*idempotencyRecord), ops: make(map[string]int64)}
+
+	runs := 0
+	status := http.StatusCreated
+	handler := idempotent(keys, func(w http.ResponseWriter, r *http.Request) {
+		runs++
+		w.Header().Set("Location", "/v1/volumes/gv0")
+		w.WriteHeader(status)
+		w.Write([]byte(`{"name":"gv0"}`))
+	})
+	serve := func(user, key, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("POST", "/v1/volumes", strings.NewReader(body))
+		if key != "" {
+			r.Header.Set(api.IdempotencyKeyHeader, key)
+		}
+		r = r.WithContext(gdctx.WithReqUser(r.Context(), user))
+		handler(w, r)
+		return w
+	}
+
+	w := serve("admin", "k1", `{"name":"gv0"}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "1", w.Header().Get(api.OperationIDHeader))
+	assert.Equal(t, 1, runs)
+
+	// A retry is answered with the response to the request
+	w = serve("admin", "k1", `{"name":"gv0"}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, `{"name":"gv0"}`, w.Body.String())
+	assert.Equal(t, "/v1/volumes/gv0", w.Header().Get("Location"))
+	assert.Equal(t, "true", w.Header().Get(api.IdempotentReplayedHeader))
+	assert.Equal(t, "1", w.Header().Get(api.OperationIDHeader))
+	assert.Equal(t, 1, runs)
+
+	// The key can not be reused for another request
+	assert.Equal(t, http.StatusUnprocessableEntity, serve("admin", "k1", `{"name":"gv1"}`).Code)
+	assert.Equal(t, 1, runs)
+
+	// The keys of the users are apart
+	assert.Equal(t, http.StatusCreated, serve("tenant", "k1", `{"name":"gv0"}`).Code)
+	assert.Equal(t, 2, runs)
+
+	// The requests without a key are run every time
+	serve("admin", "", `{"name":"gv0"}`)
+	serve("admin", "", `{"name":"gv0"}`)
+	assert.Equal(t, 4, runs)
+
+	// A retry of a request in progress is refused
+	keys.reserve(context.Background(), idempotencyKey("admin", "k2"),
+		&idempotencyRecord{Fingerprint: fingerprint("POST", "/v1/volumes", []byte("{}"))})
+	assert.Equal(t, http.StatusConflict, serve("admin", "k2", "{}").Code)
+	assert.Equal(t, 4, runs)
+
+	// The requests failing with 5xx are run again when retried
+	status = http.StatusInternalServerError
+	serve("admin", "k3", "{}")
+	serve("admin", "k3", "{}")
+	assert.Equal(t, 6, runs)
+}
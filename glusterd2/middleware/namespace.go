@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/gorilla/mux"
+)
+
+// Namespaced is a middleware which confines the requests scoped to a
+// namespace to the volumes of the namespace: they can only list and create
+// volumes, and access the volumes of their namespace through the routes of
+// the pattern given. The volumes of other namespaces are not found. The
+// requests not scoped to a namespace are not confined.
+func Namespaced(pattern string, next http.HandlerFunc) http.HandlerFunc {
+	return namespaced(pattern, volume.GetVolume, next)
+}
+
+func namespaced(pattern string, getVolume func(string) (*volume.Volinfo, error), next http.HandlerFunc) http.HandlerFunc {
+	volumeRoute := pattern == "/volumes" || strings.HasPrefix(pattern, "/volumes/{volname}")
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		ns := gdctx.GetReqNamespace(ctx)
+		if ns == "" {
+			next(w, r)
+			return
+		}
+
+		if !volumeRoute {
+			restutils.SendHTTPError(ctx, w, http.StatusForbidden,
+				errors.New("requests scoped to a namespace can only access the volumes of the namespace"))
+			return
+		}
+
+		if volname, ok := mux.Vars(r)["volname"]; ok {
+			v, err := getVolume(volname)
+			if err == gderrors.ErrVolNotFound || (err == nil && v.Namespace != ns) {
+				restutils.SendHTTPError(ctx, w, http.StatusNotFound, gderrors.ErrVolNotFound)
+				return
+			}
+			if err != nil {
+				restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+				return
+			}
+		}
+
+		next(w, r)
+	}
+}
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNamespaced(t *testing.T) {
+	getVolume := func(name string) (*volume.Volinfo, error) {
+		switch name {
+		case "tenantvol":
+			return &volume.Volinfo{Name: name, Namespace: "tenant"}, nil
+		case "othervol":
+			return &volume.Volinfo{Name: name, Namespace: "other"}, nil
+		}
+		return nil, errors.ErrVolNotFound
+	}
+
+	router := mux.NewRouter()
+	for _, pattern := range []string{"/volumes", "/volumes/{volname}", "/peers"} {
+		router.Path(pattern).Handler(namespaced(pattern, getVolume, GetTestHandler()))
+	}
+	serve := func(url, ns string) int {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", url, nil)
+		if ns != "" {
+			r = r.WithContext(gdctx.WithReqNamespace(r.Context(), ns))
+		}
+		router.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	// Requests not scoped to a namespace access everything
+	assert.Equal(t, http.StatusOK, serve("/peers", ""))
+	assert.Equal(t, http.StatusOK, serve("/volumes/othervol", ""))
+
+	assert.Equal(t, http.StatusOK, serve("/volumes", "tenant"))
+	assert.Equal(t, http.StatusOK, serve("/volumes/tenantvol", "tenant"))
+	assert.Equal(t, http.StatusNotFound, serve("/volumes/othervol", "tenant"))
+	assert.Equal(t, http.StatusNotFound, serve("/volumes/novol", "tenant"))
+	assert.Equal(t, http.StatusForbidden, serve("/peers", "tenant"))
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/pborman/uuid"
 	log "github.com/sirupsen/logrus"
+	"go.opencensus.io/trace"
 )
 
 // ReqIDGenerator is a middleware which generates a UUID for each incoming
@@ -23,8 +24,13 @@ func ReqIDGenerator(next http.Handler) http.Handler {
 		w.Header().Set("X-Gluster-Peer-Id", gdctx.MyUUID.String())
 		w.Header().Set("X-Gluster-Cluster-Id", gdctx.MyClusterID.String())
 
-		// Create request-scoped logger and set in request context
+		// Create request-scoped logger and set in request context. The
+		// trace ID is logged with the request ID when the request is
+		// traced, to find the trace of a request from its logs.
 		reqLoggerEntry := log.WithField("reqid", reqID.String())
+		if span := trace.FromContext(ctx); span != nil && span.SpanContext().IsSampled() {
+			reqLoggerEntry = reqLoggerEntry.WithField("traceid", span.SpanContext().TraceID.String())
+		}
 		ctx = gdctx.WithReqLogger(ctx, reqLoggerEntry)
 
 		next.ServeHTTP(w, r.WithContext(ctx))
@@ -0,0 +1,158 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/store"
+
+	"github.com/gorilla/mux"
+)
+
+// revisions gives the revisions of the resources in the store
+type revisions struct {
+	modRevision       func(ctx context.Context, key string) (int64, error)
+	checkPrecondition func(ctx context.Context) error
+}
+
+// resource is a kind of resource with a revision, the routes of which start
+// with pattern/{name}
+type resource struct {
+	pattern   string
+	name      string
+	keyPrefix string
+}
+
+// resources are the kinds of resources with a revision, with the prefixes of
+// their keys in the store
+var resources = []resource{
+	{pattern: "/volumes", name: "volname", keyPrefix: "volumes/"},
+	{pattern: "/peers", name: "peerid", keyPrefix: "peers/"},
+	{pattern: "/snapshots", name: "snapname", keyPrefix: "snaps/"},
+}
+
+// resourceKey returns the key in the store of the resource of a route of the
+// pattern, empty if the route is not for a resource. The resource created by
+// a request to the collection is found from the location of the response.
+func resourceKey(pattern string, vars map[string]string, location string) string {
+	for _, res := range resources {
+		prefix := res.pattern + "/{" + res.name + "}"
+		if pattern == prefix || strings.HasPrefix(pattern, prefix+"/") {
+			return res.keyPrefix + vars[res.name]
+		}
+		if pattern == res.pattern && location != "" {
+			return res.keyPrefix + path.Base(location)
+		}
+	}
+	return ""
+}
+
+// parseIfMatch returns the revisions of the If-Match header, which is a list
+// of entity tags, or * to match any revision
+func parseIfMatch(header string) (revs []int64, any bool, err error) {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" {
+			return nil, true, nil
+		}
+		rev, err := strconv.ParseInt(strings.Trim(tag, `"`), 10, 64)
+		if err != nil || rev <= 0 {
+			return nil, false, fmt.Errorf("invalid revision %s in If-Match", tag)
+		}
+		revs = append(revs, rev)
+	}
+	return revs, false, nil
+}
+
+// revisionWriter sets the revision the resource is at once the request is
+// served as the ETag of the response
+type revisionWriter struct {
+	http.ResponseWriter
+	r           *http.Request
+	pattern     string
+	modRevision func(ctx context.Context, key string) (int64, error)
+	wroteHeader bool
+}
+
+func (w *revisionWriter) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+
+	if status >= 200 && status < 300 {
+		key := resourceKey(w.pattern, mux.Vars(w.r), w.Header().Get("Location"))
+		if key != "" {
+			if rev, err := w.modRevision(w.r.Context(), key); err == nil && rev != 0 {
+				w.Header().Set("ETag", fmt.Sprintf(`"%d"`, rev))
+			}
+		}
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *revisionWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *revisionWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Revision is a middleware which gives the revision of the store the volume,
+// peer or snapshot of a route of the pattern given is at as the ETag of the
+// successful responses, and makes the changes to it conditional on the
+// revision given in the If-Match header of the request. A change to a
+// resource which was changed since, is refused with 412.
+func Revision(pattern string, next http.HandlerFunc) http.HandlerFunc {
+	return revision(pattern, revisions{
+		modRevision:       store.ModRevision,
+		checkPrecondition: store.CheckPrecondition,
+	}, next)
+}
+
+func revision(pattern string, rs revisions, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		ifMatch := r.Header.Get("If-Match")
+		if ifMatch != "" && r.Method != http.MethodGet && r.Method != http.MethodHead {
+			revs, any, err := parseIfMatch(ifMatch)
+			if err != nil {
+				restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+				return
+			}
+			if !any {
+				key := resourceKey(pattern, mux.Vars(r), "")
+				if key == "" {
+					restutils.SendHTTPError(ctx, w, http.StatusPreconditionFailed,
+						errors.New("the route has no revision to match"))
+					return
+				}
+				ctx = gdctx.WithReqPrecondition(ctx, gdctx.Precondition{Key: key, Revisions: revs})
+				r = r.WithContext(ctx)
+
+				// Checked again by the transaction, under its locks
+				if err := rs.checkPrecondition(ctx); err != nil {
+					status, err := restutils.ErrToStatusCode(err)
+					restutils.SendHTTPError(ctx, w, status, err)
+					return
+				}
+			}
+		}
+
+		next(&revisionWriter{ResponseWriter: w, r: r, pattern: pattern, modRevision: rs.modRevision}, r)
+	}
+}
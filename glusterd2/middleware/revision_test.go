@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResourceKey(t *testing.T) {
+	vars := map[string]string{"volname": "gv0", "peerid": "p1", "snapname": "s1"}
+
+	assert.Equal(t, "volumes/gv0", resourceKey("/volumes/{volname}", vars, ""))
+	assert.Equal(t, "volumes/gv0", resourceKey("/volumes/{volname}/options", vars, ""))
+	assert.Equal(t, "peers/p1", resourceKey("/peers/{peerid}", vars, ""))
+	assert.Equal(t, "snaps/s1", resourceKey("/snapshots/{snapname}/activate", vars, ""))
+	assert.Equal(t, "volumes/gv1", resourceKey("/volumes", nil, "/v1/volumes/gv1"))
+	assert.Equal(t, "", resourceKey("/volumes", nil, ""))
+	assert.Equal(t, "", resourceKey("/volumesx/{volname}", vars, ""))
+	assert.Equal(t, "", resourceKey("/cluster/options", nil, ""))
+}
+
+func TestParseIfMatch(t *testing.T) {
+	revs, any, err := parseIfMatch(`"12", "15"`)
+	require.NoError(t, err)
+	assert.False(t, any)
+	assert.Equal(t, []int64{12, 15}, revs)
+
+	_, any, err = parseIfMatch("*")
+	require.NoError(t, err)
+	assert.True(t, any)
+
+	_, _, err = parseIfMatch(`W/"12"`)
+	assert.Error(t, err)
+	_, _, err = parseIfMatch(`"0"`)
+	assert.Error(t, err)
+}
+
+func TestRevision(t *testing.T) {
+	revs := map[string]int64{"volumes/gv0": 12, "volumes/gv1": 20}
+	rs := revisions{
+		modRevision: func(ctx context.Context, key string) (int64, error) {
+			return revs[key], nil
+		},
+		checkPrecondition: func(ctx context.Context) error {
+			p, ok := gdctx.GetReqPrecondition(ctx)
+			if !ok {
+				return nil
+			}
+			for _, r := range p.Revisions {
+				if r == revs[p.Key] {
+					return nil
+				}
+			}
+			return errors.ErrRevisionMismatch
+		},
+	}
+
+	router := mux.NewRouter()
+	router.Methods("GET", "POST").Path("/volumes/{volname}").Handler(revision("/volumes/{volname}", rs, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	router.Methods("POST").Path("/volumes").Handler(revision("/volumes", rs, func(w http.ResponseWriter, r *http.Request) {
+		restutils.SetLocationHeader(r, w, "gv1")
+		w.WriteHeader(http.StatusCreated)
+	}))
+	router.Methods("POST").Path("/cluster/options").Handler(revision("/cluster/options", rs, GetTestHandler()))
+	serve := func(method, url, ifMatch string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(method, url, nil)
+		if ifMatch != "" {
+			r.Header.Set("If-Match", ifMatch)
+		}
+		router.ServeHTTP(w, r)
+		return w
+	}
+
+	w := serve("GET", "/volumes/gv0", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"12"`, w.Header().Get("ETag"))
+
+	// The revision of a new resource is given too
+	w = serve("POST", "/volumes", "")
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, `"20"`, w.Header().Get("ETag"))
+
+	assert.Equal(t, http.StatusOK, serve("POST", "/volumes/gv0", `"12"`).Code)
+	assert.Equal(t, http.StatusOK, serve("POST", "/volumes/gv0", `"11", "12"`).Code)
+	assert.Equal(t, http.StatusOK, serve("POST", "/volumes/gv0", "*").Code)
+	assert.Equal(t, http.StatusPreconditionFailed, serve("POST", "/volumes/gv0", `"11"`).Code)
+	assert.Equal(t, http.StatusBadRequest, serve("POST", "/volumes/gv0", "twelve").Code)
+	assert.Equal(t, http.StatusPreconditionFailed, serve("POST", "/cluster/options", `"12"`).Code)
+	assert.Equal(t, http.StatusOK, serve("POST", "/cluster/options", "").Code)
+}
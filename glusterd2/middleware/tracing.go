@@ -4,9 +4,22 @@ import (
 	"net/http"
 
 	"go.opencensus.io/plugin/ochttp"
+	"go.opencensus.io/trace"
 )
 
 // Tracing is a http middleware to be use for trace incoming http.Request
 func Tracing(next http.Handler) http.Handler {
 	return &ochttp.Handler{Handler: next}
 }
+
+// TraceRoute annotates the span created by Tracing with the name of the
+// route, so that the spans of a REST handler can be found irrespective of
+// the volume or peer in the URL
+func TraceRoute(name string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if span := trace.FromContext(r.Context()); span != nil {
+			span.AddAttributes(trace.StringAttribute("route", name))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
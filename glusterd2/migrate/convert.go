@@ -0,0 +1,291 @@
+package migrate
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/snapshot"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+
+	"github.com/pborman/uuid"
+)
+
+// Volume types and transport types as numbered by glusterd1
+const (
+	gd1TypeDistribute = 0
+	gd1TypeReplicate  = 2
+	gd1TypeDisperse   = 4
+
+	gd1StatusStarted = "1"
+)
+
+var gd1Transports = map[string]string{
+	"0": "tcp",
+	"1": "rdma",
+	"2": "tcp,rdma",
+}
+
+// PeerResolver returns the ID of the glusterd2 peer known by any of the given
+// hostnames or addresses
+type PeerResolver func(hostnames []string) (uuid.UUID, error)
+
+// PeerMap maps the hosts of the bricks of glusterd1 volumes to glusterd2
+// peers
+type PeerMap struct {
+	hosts map[string]uuid.UUID
+	// IDs maps the UUIDs of the glusterd1 peers to the IDs of glusterd2
+	// peers
+	IDs map[string]uuid.UUID
+	// Unmapped are the glusterd1 peers and brick hosts with no matching
+	// glusterd2 peer
+	Unmapped []string
+}
+
+// MapPeers maps the glusterd1 peers and the hosts of the bricks of the given
+// volumes to glusterd2 peers. The glusterd1 peer the state was read from, which
+// is not in its own peer list, maps to self and its bricks are mapped by their
+// hostname.
+func (st *GD1State) MapPeers(volumes []*GD1Volume, self uuid.UUID, resolve PeerResolver) *PeerMap {
+	m := &PeerMap{
+		hosts: make(map[string]uuid.UUID),
+		IDs:   make(map[string]uuid.UUID),
+	}
+	if st.UUID != "" {
+		m.IDs[st.UUID] = self
+	}
+
+	for _, p := range st.Peers {
+		id, err := resolve(p.Hostnames)
+		if err != nil {
+			m.Unmapped = append(m.Unmapped, fmt.Sprintf("peer %s (%s)", p.UUID, strings.Join(p.Hostnames, ", ")))
+			continue
+		}
+		m.IDs[p.UUID] = id
+		for _, h := range p.Hostnames {
+			m.hosts[h] = id
+		}
+	}
+
+	for _, v := range volumes {
+		for _, b := range v.Bricks {
+			if _, ok := m.hosts[b.Hostname]; ok {
+				continue
+			}
+			id, err := resolve([]string{b.Hostname})
+			if err != nil {
+				m.Unmapped = append(m.Unmapped, fmt.Sprintf("host %s of brick %s:%s", b.Hostname, b.Hostname, b.Path))
+				// Report each host once
+				m.hosts[b.Hostname] = nil
+				continue
+			}
+			m.hosts[b.Hostname] = id
+		}
+	}
+	return m
+}
+
+// peerOf returns the glusterd2 peer hosting the brick
+func (m *PeerMap) peerOf(b *GD1Brick) (uuid.UUID, error) {
+	id := m.hosts[b.Hostname]
+	if id == nil {
+		return nil, fmt.Errorf("no peer found for host %s of brick %s", b.Hostname, b.Path)
+	}
+	return id, nil
+}
+
+func atoi(info map[string]string, key string) int {
+	n, _ := strconv.Atoi(info[key])
+	return n
+}
+
+// ToVolinfo converts a glusterd1 volume to a glusterd2 one, along with the
+// options which have no glusterd2 equivalent. The volume is converted as
+// stopped.
+func (v *GD1Volume) ToVolinfo(m *PeerMap) (*volume.Volinfo, []string, error) {
+	return v.toVolinfo(m, v.Name, v.Name, brick.ManuallyProvisioned)
+}
+
+func (v *GD1Volume) toVolinfo(m *PeerMap, name string, volfileID string, ptype brick.ProvisionType) (*volume.Volinfo, []string, error) {
+	info := v.Info
+
+	id := uuid.Parse(info["volume-id"])
+	if id == nil {
+		return nil, nil, fmt.Errorf("volume %s: invalid volume-id %q", v.Name, info["volume-id"])
+	}
+	if count := atoi(info, "count"); count != len(v.Bricks) {
+		return nil, nil, fmt.Errorf("volume %s: found %d bricks, expected %d", v.Name, len(v.Bricks), count)
+	}
+	if len(v.Bricks) == 0 {
+		return nil, nil, fmt.Errorf("volume %s has no bricks", v.Name)
+	}
+
+	transport, ok := gd1Transports[info["transport-type"]]
+	if !ok {
+		transport = "tcp"
+	}
+
+	volinfo := &volume.Volinfo{
+		ID:        id,
+		Name:      name,
+		VolfileID: volfileID,
+		State:     volume.VolStopped,
+		Transport: transport,
+		Options:   make(map[string]string),
+		Metadata:  map[string]string{brick.ProvisionKey: string(ptype)},
+		GraphMap:  make(map[string]string),
+		SnapList:  []string{},
+		Auth: volume.VolAuth{
+			Username: info["username"],
+			Password: info["password"],
+		},
+	}
+	if volinfo.Auth.Username == "" {
+		volinfo.Auth = volume.VolAuth{
+			Username: uuid.NewRandom().String(),
+			Password: uuid.NewRandom().String(),
+		}
+	}
+
+	// Subvolumes are made of consecutive bricks, a plain distribute volume
+	// having a subvolume per brick
+	var subvolType volume.SubvolType
+	size := 1
+	switch gd1Type := atoi(info, "type"); gd1Type {
+	case gd1TypeDistribute:
+		subvolType = volume.SubvolDistribute
+	case gd1TypeReplicate:
+		subvolType = volume.SubvolReplicate
+		size = atoi(info, "replica_count")
+	case gd1TypeDisperse:
+		subvolType = volume.SubvolDisperse
+		size = atoi(info, "disperse_count")
+	default:
+		return nil, nil, fmt.Errorf("volume %s: type %d is not supported by glusterd2", v.Name, gd1Type)
+	}
+	if size <= 0 || len(v.Bricks)%size != 0 {
+		return nil, nil, fmt.Errorf("volume %s: %d bricks cannot form subvolumes of %d bricks", v.Name, len(v.Bricks), size)
+	}
+	arbiterCount := atoi(info, "arbiter_count")
+
+	for idx := 0; idx*size < len(v.Bricks); idx++ {
+		s := volume.Subvol{
+			ID:           uuid.NewRandom(),
+			Name:         fmt.Sprintf("%s-%s-%d", name, strings.ToLower(volume.SubvolTypeToString(subvolType)), idx),
+			Type:         subvolType,
+			ReplicaCount: 1,
+		}
+		switch subvolType {
+		case volume.SubvolReplicate:
+			// glusterd1 counts the arbiter among the replicas
+			s.ReplicaCount = size - arbiterCount
+			s.ArbiterCount = arbiterCount
+		case volume.SubvolDisperse:
+			s.DisperseCount = size
+			s.RedundancyCount = atoi(info, "redundancy_count")
+		}
+
+		for bidx, b := range v.Bricks[idx*size : (idx+1)*size] {
+			binfo, err := toBrickinfo(&b, m, volinfo, ptype)
+			if err != nil {
+				return nil, nil, err
+			}
+			// The last bricks of a replica set are its arbiters
+			if s.ArbiterCount > 0 && bidx >= size-s.ArbiterCount {
+				binfo.Type = brick.Arbiter
+			}
+			s.Bricks = append(s.Bricks, *binfo)
+		}
+		volinfo.Subvols = append(volinfo.Subvols, s)
+	}
+	volinfo.DistCount = len(volinfo.Subvols)
+	volinfo.Type = volumeType(subvolType, len(volinfo.Subvols))
+
+	skipped := convertOptions(info, volinfo.Options)
+	if arbiterCount > 0 {
+		volinfo.Options["replicate.arbiter-count"] = strconv.Itoa(arbiterCount)
+	}
+	return volinfo, skipped, nil
+}
+
+func volumeType(subvolType volume.SubvolType, numSubvols int) volume.VolType {
+	switch subvolType {
+	case volume.SubvolReplicate:
+		if numSubvols > 1 {
+			return volume.DistReplicate
+		}
+		return volume.Replicate
+	case volume.SubvolDisperse:
+		if numSubvols > 1 {
+			return volume.DistDisperse
+		}
+		return volume.Disperse
+	}
+	return volume.Distribute
+}
+
+func toBrickinfo(b *GD1Brick, m *PeerMap, volinfo *volume.Volinfo, ptype brick.ProvisionType) (*brick.Brickinfo, error) {
+	peerID, err := m.peerOf(b)
+	if err != nil {
+		return nil, err
+	}
+
+	binfo := &brick.Brickinfo{
+		ID:             uuid.NewRandom(),
+		Hostname:       b.Hostname,
+		PeerID:         peerID,
+		Path:           b.Path,
+		VolumeName:     volinfo.Name,
+		VolfileID:      volinfo.VolfileID,
+		VolumeID:       volinfo.ID,
+		Type:           brick.Brick,
+		PType:          ptype,
+		Decommissioned: b.Info["decommissioned"] == "1",
+	}
+	if ptype.IsSnapshotProvisioned() {
+		binfo.MountInfo = brick.MountInfo{
+			BrickDirSuffix: b.Info["mount_dir"],
+			DevicePath:     b.Info["device_path"],
+			FsType:         b.Info["fs_type"],
+			MntOpts:        b.Info["mnt_opts"],
+		}
+		binfo.DeviceInfo.VgName = b.Info["vg"]
+	}
+	return binfo, nil
+}
+
+// ToSnapinfo converts a glusterd1 snapshot to a glusterd2 one, along with the
+// options of its volume which have no glusterd2 equivalent. The snapshot is
+// converted as deactivated.
+func (s *GD1Snapshot) ToSnapinfo(m *PeerMap) (*snapshot.Snapinfo, []string, error) {
+	volinfo, skipped, err := s.Volume.toVolinfo(m, s.Name, "snaps/"+s.Name, brick.SnapshotProvisioned)
+	if err != nil {
+		return nil, nil, err
+	}
+	volinfo.State = volume.VolCreated
+
+	return &snapshot.Snapinfo{
+		SnapVolinfo:  *volinfo,
+		ParentVolume: s.ParentVolume(),
+		Description:  s.Description,
+		OptionChange: make(map[string]string),
+		CreatedAt:    s.CreatedAt,
+	}, skipped, nil
+}
+
+// WasStarted tells if the volume was started under glusterd1
+func (v *GD1Volume) WasStarted() bool {
+	return v.Info["status"] == gd1StatusStarted
+}
+
+// sortedKeys returns the keys of the map, sorted
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
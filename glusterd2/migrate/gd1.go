@@ -0,0 +1,252 @@
+// Package migrate imports the peers, volumes and snapshots of a glusterd1
+// installation into glusterd2, giving existing clusters a migration path.
+//
+// glusterd1 keeps its state as key=value files in its working directory,
+// /var/lib/glusterd by default:
+//
+//	glusterd.info             UUID and operating-version of the peer
+//	peers/<uuid>              uuid, state and hostname1..N of each peer
+//	vols/<vol>/info           volume type, counts, brick-N and options
+//	vols/<vol>/bricks/<brick> hostname, path and mount details of a brick
+//	snaps/<snap>/info         snap-id, desc and time-stamp of a snapshot
+//	snaps/<snap>/<snapvol>/   the snapshot volume, laid out as vols/<vol>
+package migrate
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GD1Peer is a peer known to glusterd1
+type GD1Peer struct {
+	UUID      string
+	Hostnames []string
+}
+
+// GD1Brick is a brick of a glusterd1 volume
+type GD1Brick struct {
+	Hostname string
+	Path     string
+	Info     map[string]string
+}
+
+// GD1Volume is a glusterd1 volume, or the volume of a glusterd1 snapshot
+type GD1Volume struct {
+	Name   string
+	Info   map[string]string
+	Bricks []GD1Brick
+}
+
+// GD1Snapshot is a glusterd1 snapshot
+type GD1Snapshot struct {
+	Name        string
+	ID          string
+	Description string
+	CreatedAt   time.Time
+	Volume      *GD1Volume
+}
+
+// ParentVolume returns the name of the volume the snapshot was taken of
+func (s *GD1Snapshot) ParentVolume() string {
+	return s.Volume.Info["parent_volname"]
+}
+
+// GD1State is the state of a glusterd1 installation
+type GD1State struct {
+	UUID      string
+	OpVersion int
+	Peers     []GD1Peer
+	Volumes   []*GD1Volume
+	Snapshots []*GD1Snapshot
+}
+
+// readInfoFile reads a glusterd1 key=value store file
+func readInfoFile(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info := make(map[string]string)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("%s: invalid line %q", path, line)
+		}
+		info[parts[0]] = parts[1]
+	}
+	return info, scanner.Err()
+}
+
+// subdirs returns the names of the directories in dir, sorted
+func subdirs(dir string) ([]string, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// ReadGD1State reads the state of glusterd1 from its working directory
+func ReadGD1State(workdir string) (*GD1State, error) {
+	info, err := readInfoFile(filepath.Join(workdir, "glusterd.info"))
+	if err != nil {
+		return nil, err
+	}
+	st := &GD1State{UUID: info["UUID"]}
+	if v, ok := info["operating-version"]; ok {
+		if st.OpVersion, err = strconv.Atoi(v); err != nil {
+			return nil, fmt.Errorf("invalid operating-version %q", v)
+		}
+	}
+
+	if st.Peers, err = readPeers(filepath.Join(workdir, "peers")); err != nil {
+		return nil, err
+	}
+
+	volnames, err := subdirs(filepath.Join(workdir, "vols"))
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range volnames {
+		v, err := readVolume(filepath.Join(workdir, "vols", name), name)
+		if err != nil {
+			return nil, err
+		}
+		st.Volumes = append(st.Volumes, v)
+	}
+
+	snapnames, err := subdirs(filepath.Join(workdir, "snaps"))
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range snapnames {
+		s, err := readSnapshot(filepath.Join(workdir, "snaps", name), name)
+		if err != nil {
+			return nil, err
+		}
+		st.Snapshots = append(st.Snapshots, s)
+	}
+
+	return st, nil
+}
+
+func readPeers(dir string) ([]GD1Peer, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var peers []GD1Peer
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		info, err := readInfoFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		p := GD1Peer{UUID: info["uuid"]}
+		for i := 1; ; i++ {
+			h, ok := info["hostname"+strconv.Itoa(i)]
+			if !ok {
+				break
+			}
+			p.Hostnames = append(p.Hostnames, h)
+		}
+		peers = append(peers, p)
+	}
+	return peers, nil
+}
+
+func readVolume(dir string, name string) (*GD1Volume, error) {
+	info, err := readInfoFile(filepath.Join(dir, "info"))
+	if err != nil {
+		return nil, err
+	}
+	v := &GD1Volume{Name: name, Info: info}
+
+	// Bricks are listed in the info file as brick-N=<host>:<path with '/'
+	// replaced by '-'>, which names the file holding the brick details
+	for i := 0; ; i++ {
+		brickFile, ok := info["brick-"+strconv.Itoa(i)]
+		if !ok {
+			break
+		}
+		binfo, err := readInfoFile(filepath.Join(dir, "bricks", brickFile))
+		if err != nil {
+			return nil, err
+		}
+		v.Bricks = append(v.Bricks, GD1Brick{
+			Hostname: binfo["hostname"],
+			Path:     binfo["path"],
+			Info:     binfo,
+		})
+	}
+	return v, nil
+}
+
+func readSnapshot(dir string, name string) (*GD1Snapshot, error) {
+	info, err := readInfoFile(filepath.Join(dir, "info"))
+	if err != nil {
+		return nil, err
+	}
+	s := &GD1Snapshot{
+		Name:        name,
+		ID:          info["snap-id"],
+		Description: info["desc"],
+	}
+	if ts, err := strconv.ParseInt(info["time-stamp"], 10, 64); err == nil {
+		s.CreatedAt = time.Unix(ts, 0).UTC()
+	}
+
+	// The volume of the snapshot is kept in a directory named after its
+	// volume id, next to the geo-replication config of the snapshot
+	names, err := subdirs(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, volname := range names {
+		voldir := filepath.Join(dir, volname)
+		if _, err := os.Stat(filepath.Join(voldir, "info")); err != nil {
+			continue
+		}
+		if s.Volume != nil {
+			return nil, fmt.Errorf("snapshot %s has more than one volume", name)
+		}
+		if s.Volume, err = readVolume(voldir, volname); err != nil {
+			return nil, err
+		}
+	}
+	if s.Volume == nil {
+		return nil, fmt.Errorf("snapshot %s has no volume", name)
+	}
+	return s, nil
+}
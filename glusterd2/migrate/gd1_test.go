@@ -0,0 +1,179 @@
+package migrate
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testSelfUUID = "9f3c1a2e-5a3c-4d8b-9a6e-0c1d2e3f4a5b"
+	testPeerUUID = "1b2c3d4e-5f60-4718-a9b0-c1d2e3f4a5b6"
+	testVolID    = "c0ffee00-1111-4222-8333-444455556666"
+	testSnapVol  = "5d6e7f8091a24b3c8d4e5f6a7b8c9d0e"
+)
+
+func writeFile(t *testing.T, path string, content string) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, ioutil.WriteFile(path, []byte(content), 0600))
+}
+
+// writeGD1State writes the state of glusterd1 having a replica 3 arbiter 1
+// volume over two peers, and a snapshot of it
+func writeGD1State(t *testing.T, dir string) {
+	writeFile(t, filepath.Join(dir, "glusterd.info"), "UUID="+testSelfUUID+"\noperating-version=31302\n")
+	writeFile(t, filepath.Join(dir, "peers", testPeerUUID),
+		"uuid="+testPeerUUID+"\nstate=3\nhostname1=server2\nhostname2=10.0.0.2\n")
+
+	vol := filepath.Join(dir, "vols", "gv0")
+	writeFile(t, filepath.Join(vol, "info"), `type=2
+count=3
+status=1
+replica_count=3
+arbiter_count=1
+disperse_count=0
+redundancy_count=0
+transport-type=0
+volume-id=`+testVolID+`
+username=user
+password=secret
+op-version=31302
+brick-0=server1:-bricks-b1
+brick-1=server2:-bricks-b1
+brick-2=server1:-bricks-arb
+nfs.disable=on
+`)
+	writeFile(t, filepath.Join(vol, "bricks", "server1:-bricks-b1"), "hostname=server1\npath=/bricks/b1\n")
+	writeFile(t, filepath.Join(vol, "bricks", "server2:-bricks-b1"), "hostname=10.0.0.2\npath=/bricks/b1\n")
+	writeFile(t, filepath.Join(vol, "bricks", "server1:-bricks-arb"), "hostname=server1\npath=/bricks/arb\ndecommissioned=0\n")
+
+	snap := filepath.Join(dir, "snaps", "snap1")
+	writeFile(t, filepath.Join(snap, "info"), "snap-id="+uuid.NewRandom().String()+"\ndesc=nightly\ntime-stamp=1500000000\n")
+	snapvol := filepath.Join(snap, testSnapVol)
+	writeFile(t, filepath.Join(snapvol, "info"), `type=0
+count=1
+status=0
+volume-id=`+uuid.NewRandom().String()+`
+parent_volname=gv0
+brick-0=server1:-run-gluster-snaps-b1
+`)
+	writeFile(t, filepath.Join(snapvol, "bricks", "server1:-run-gluster-snaps-b1"),
+		"hostname=server1\npath=/run/gluster/snaps/"+testSnapVol+"/brick1/b1\nmount_dir=/b1\ndevice_path=/dev/vg/snap\nfs_type=xfs\nmnt_opts=rw,nouuid\n")
+}
+
+func TestReadGD1State(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gd1")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	writeGD1State(t, dir)
+
+	st, err := ReadGD1State(dir)
+	require.NoError(t, err)
+	assert.Equal(t, testSelfUUID, st.UUID)
+	assert.Equal(t, 31302, st.OpVersion)
+
+	require.Len(t, st.Peers, 1)
+	assert.Equal(t, []string{"server2", "10.0.0.2"}, st.Peers[0].Hostnames)
+
+	require.Len(t, st.Volumes, 1)
+	assert.Equal(t, "gv0", st.Volumes[0].Name)
+	assert.True(t, st.Volumes[0].WasStarted())
+	require.Len(t, st.Volumes[0].Bricks, 3)
+	assert.Equal(t, "/bricks/arb", st.Volumes[0].Bricks[2].Path)
+
+	require.Len(t, st.Snapshots, 1)
+	assert.Equal(t, "nightly", st.Snapshots[0].Description)
+	assert.Equal(t, "gv0", st.Snapshots[0].ParentVolume())
+	assert.Equal(t, int64(1500000000), st.Snapshots[0].CreatedAt.Unix())
+
+	_, err = ReadGD1State(filepath.Join(dir, "missing"))
+	assert.Error(t, err)
+}
+
+func TestToVolinfo(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gd1")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	writeGD1State(t, dir)
+
+	st, err := ReadGD1State(dir)
+	require.NoError(t, err)
+
+	self, other := uuid.NewRandom(), uuid.NewRandom()
+	resolve := func(hostnames []string) (uuid.UUID, error) {
+		for _, h := range hostnames {
+			switch h {
+			case "server1":
+				return self, nil
+			case "server2":
+				return other, nil
+			}
+		}
+		return nil, errors.ErrPeerNotFound
+	}
+
+	m := st.MapPeers(append(st.Volumes, st.Snapshots[0].Volume), self, resolve)
+	assert.Empty(t, m.Unmapped)
+	assert.Equal(t, self, m.IDs[testSelfUUID])
+	assert.Equal(t, other, m.IDs[testPeerUUID])
+
+	v, skipped, err := st.Volumes[0].ToVolinfo(m)
+	require.NoError(t, err)
+	assert.Equal(t, testVolID, v.ID.String())
+	assert.Equal(t, volume.Replicate, v.Type)
+	assert.Equal(t, volume.VolStopped, v.State)
+	assert.Equal(t, "tcp", v.Transport)
+	assert.Equal(t, "user", v.Auth.Username)
+	assert.Equal(t, []string{"nfs.disable"}, skipped)
+	assert.Equal(t, "1", v.Options["replicate.arbiter-count"])
+
+	require.Len(t, v.Subvols, 1)
+	sv := v.Subvols[0]
+	assert.Equal(t, "gv0-replicate-0", sv.Name)
+	assert.Equal(t, 2, sv.ReplicaCount)
+	assert.Equal(t, 1, sv.ArbiterCount)
+	require.Len(t, sv.Bricks, 3)
+	assert.Equal(t, self, sv.Bricks[0].PeerID)
+	// Brick hosts are mapped by any of the hostnames of the peer
+	assert.Equal(t, other, sv.Bricks[1].PeerID)
+	assert.Equal(t, brick.Arbiter, sv.Bricks[2].Type)
+
+	s, _, err := st.Snapshots[0].ToSnapinfo(m)
+	require.NoError(t, err)
+	assert.Equal(t, "snap1", s.SnapVolinfo.Name)
+	assert.Equal(t, "snaps/snap1", s.SnapVolinfo.VolfileID)
+	assert.Equal(t, volume.VolCreated, s.SnapVolinfo.State)
+	b := s.SnapVolinfo.Subvols[0].Bricks[0]
+	assert.Equal(t, brick.SnapshotProvisioned, b.PType)
+	assert.Equal(t, "/b1", b.MountInfo.BrickDirSuffix)
+	assert.Equal(t, "/dev/vg/snap", b.MountInfo.DevicePath)
+}
+
+func TestToVolinfoUnmappedHost(t *testing.T) {
+	v := &GD1Volume{
+		Name: "gv1",
+		Info: map[string]string{
+			"type":      "0",
+			"count":     "1",
+			"volume-id": uuid.NewRandom().String(),
+		},
+		Bricks: []GD1Brick{{Hostname: "unknown", Path: "/bricks/b1"}},
+	}
+	st := &GD1State{}
+	m := st.MapPeers([]*GD1Volume{v}, uuid.NewRandom(), func([]string) (uuid.UUID, error) {
+		return nil, errors.ErrPeerNotFound
+	})
+	assert.Len(t, m.Unmapped, 1)
+
+	_, _, err := v.ToVolinfo(m)
+	assert.Error(t, err)
+}
@@ -0,0 +1,84 @@
+package migrate
+
+import (
+	"strings"
+
+	"github.com/gluster/glusterd2/glusterd2/xlator"
+)
+
+// gd1Namespaces maps the namespaces of the glusterd1 volume option keys to
+// the categories of the xlators implementing them
+var gd1Namespaces = map[string]string{
+	"cluster":     "cluster",
+	"performance": "performance",
+	"features":    "features",
+	"storage":     "storage",
+	"network":     "protocol",
+	"diagnostics": "debug",
+}
+
+// translateOption returns the glusterd2 key of a glusterd1 volume option key.
+// glusterd1 keys are either <xlator>.<option> keys glusterd2 knows as well,
+// <namespace>.<option> keys of an option of an xlator of the namespace, or
+// <namespace>.<xlator> keys enabling the xlator.
+func translateOption(key string) (string, bool) {
+	if _, err := xlator.FindOption(key); err == nil {
+		return key, true
+	}
+
+	parts := strings.SplitN(key, ".", 2)
+	if len(parts) != 2 {
+		return "", false
+	}
+	category, ok := gd1Namespaces[parts[0]]
+	if !ok {
+		return "", false
+	}
+
+	var matches []string
+	for _, xl := range xlator.Xlators() {
+		if xl.Category != category {
+			continue
+		}
+		k := xl.FullName()
+		if xl.ID != parts[1] {
+			k += "." + parts[1]
+		}
+		if _, err := xlator.FindOption(k); err == nil {
+			matches = append(matches, k)
+		}
+	}
+	// Options of the same name in several xlators of the namespace
+	// cannot be told apart
+	if len(matches) != 1 {
+		return "", false
+	}
+	return matches[0], true
+}
+
+// convertOptions sets the glusterd2 equivalents of the volume options found in
+// the info file of a glusterd1 volume, returning the options which have none
+// or whose value is not valid in glusterd2. The other keys of the info file,
+// which describe the volume, have no '.'.
+func convertOptions(info map[string]string, opts map[string]string) []string {
+	var skipped []string
+	for _, k := range sortedKeys(info) {
+		if !strings.Contains(k, ".") || strings.HasPrefix(k, "brick-") {
+			continue
+		}
+		v := info[k]
+
+		newKey, ok := translateOption(k)
+		if !ok {
+			skipped = append(skipped, k)
+			continue
+		}
+		o, _ := xlator.FindOption(newKey)
+		if err := o.Validate(v); err != nil {
+			skipped = append(skipped, k)
+			continue
+		}
+		opts[newKey] = v
+	}
+	return skipped
+}
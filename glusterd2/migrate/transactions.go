@@ -0,0 +1,64 @@
+package migrate
+
+import (
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/volgen"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+
+	log "github.com/sirupsen/logrus"
+)
+
+func txnGenerateBrickVolfiles(c transaction.TxnCtx) error {
+	var volnames []string
+	if err := c.Get("volumes", &volnames); err != nil {
+		return err
+	}
+
+	for _, name := range volnames {
+		v, err := volume.GetVolume(name)
+		if err != nil {
+			return err
+		}
+		if err := volgen.GenerateBricksVolfiles(v, v.GetLocalBricks()); err != nil {
+			c.Logger().WithError(err).WithFields(log.Fields{
+				"template": "brick",
+				"volume":   name,
+			}).Error("failed to generate volfile")
+			return err
+		}
+	}
+	return nil
+}
+
+func txnDeleteBrickVolfiles(c transaction.TxnCtx) error {
+	var volnames []string
+	if err := c.Get("volumes", &volnames); err != nil {
+		return err
+	}
+
+	for _, name := range volnames {
+		v, err := volume.GetVolume(name)
+		if err != nil {
+			continue
+		}
+		if err := volgen.DeleteBricksVolfiles(v.GetLocalBricks()); err != nil {
+			c.Logger().WithError(err).WithField("volume", name).Warn("failed to delete brick volfiles")
+		}
+	}
+	return nil
+}
+
+// RegisterStepFuncs registers the step functions used to import glusterd1
+// volumes
+func RegisterStepFuncs() {
+	var sfs = []struct {
+		name string
+		sf   transaction.StepFunc
+	}{
+		{"migrate.GenerateBrickVolfiles", txnGenerateBrickVolfiles},
+		{"migrate.DeleteBrickVolfiles", txnDeleteBrickVolfiles},
+	}
+	for _, sf := range sfs {
+		transaction.RegisterStepFunc(sf.sf, sf.name)
+	}
+}
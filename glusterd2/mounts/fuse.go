@@ -0,0 +1,153 @@
+package mounts
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/gluster/glusterd2/glusterd2/daemon"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	config "github.com/spf13/viper"
+)
+
+const glusterfsBin = "glusterfs"
+
+// mountLock serializes the mounts and unmounts of this peer
+var mountLock sync.Mutex
+
+func pathWithoutSlashes(p string) string {
+	return strings.Trim(strings.Replace(p, "/", "-", -1), "-")
+}
+
+// pidFile returns the pid file of the client process of the mount
+func pidFile(m *api.Mount) string {
+	return filepath.Join(config.GetString("rundir"), "mount-"+pathWithoutSlashes(m.Path)+".pid")
+}
+
+// logFile returns the log file of the client process of the mount
+func logFile(m *api.Mount) string {
+	return filepath.Join(config.GetString("logdir"), "glusterfs", "mnt-"+pathWithoutSlashes(m.Path)+".log")
+}
+
+// args returns the arguments of the client process of the mount
+func args(m *api.Mount) []string {
+	shost, sport, _ := net.SplitHostPort(config.GetString("clientaddress"))
+	if shost == "" {
+		shost = "127.0.0.1"
+	}
+
+	a := []string{
+		"--volfile-server", shost,
+		"--volfile-server-port", sport,
+		"--volfile-id", m.Volume,
+		"-p", pidFile(m),
+		"-l", logFile(m),
+	}
+
+	names := make([]string, 0, len(m.Options))
+	for name := range m.Options {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		opt, ok := options[name]
+		if !ok {
+			continue
+		}
+		if opt.check == nil {
+			a = append(a, opt.flag)
+		} else {
+			a = append(a, opt.flag+"="+m.Options[name])
+		}
+	}
+	return append(a, m.Path)
+}
+
+// isMountPoint tells if a file system is mounted at the path. A mount whose
+// client process died cannot be stat'ed, and is not.
+func isMountPoint(p string) bool {
+	var st, parent syscall.Stat_t
+	if err := syscall.Stat(p, &st); err != nil {
+		return false
+	}
+	if err := syscall.Stat(filepath.Dir(p), &parent); err != nil {
+		return false
+	}
+	return st.Dev != parent.Dev
+}
+
+// Status tells if the mount is online on this peer, with the PID of its
+// client process
+func Status(m *api.Mount) (bool, int) {
+	pid, err := daemon.ReadPidFromFile(pidFile(m))
+	if err != nil {
+		return false, 0
+	}
+	if _, err := daemon.GetProcess(pid); err != nil {
+		return false, 0
+	}
+	if !isMountPoint(m.Path) {
+		return false, pid
+	}
+	return true, pid
+}
+
+// Mount mounts the volume on this peer. A mount already online is left as
+// it is, and a mount whose client process died is unmounted first.
+func Mount(m *api.Mount) error {
+	mountLock.Lock()
+	defer mountLock.Unlock()
+
+	if online, _ := Status(m); online {
+		return nil
+	}
+
+	// Detach what is left of a mount whose client process died, for the
+	// path to be mounted again
+	_ = syscall.Unmount(m.Path, syscall.MNT_DETACH)
+	if err := os.MkdirAll(m.Path, 0755); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(logFile(m)), 0755); err != nil {
+		return err
+	}
+
+	// The client process daemonizes once the volume is mounted
+	out, err := exec.Command(glusterfsBin, args(m)...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to mount volume %s on %s: %s: %s", m.Volume, m.Path, err, bytes.TrimSpace(out))
+	}
+	if !isMountPoint(m.Path) {
+		return fmt.Errorf("failed to mount volume %s on %s, see %s", m.Volume, m.Path, logFile(m))
+	}
+	return nil
+}
+
+// Unmount unmounts the volume from this peer, failing if the mount is busy.
+// The client process exits once the volume is unmounted.
+func Unmount(m *api.Mount) error {
+	mountLock.Lock()
+	defer mountLock.Unlock()
+
+	if err := syscall.Unmount(m.Path, 0); err != nil && err != syscall.EINVAL && err != syscall.ENOENT {
+		if err != syscall.ENOTCONN {
+			return fmt.Errorf("failed to unmount %s: %s", m.Path, err)
+		}
+		// The client process died, detach what is left of the mount
+		_ = syscall.Unmount(m.Path, syscall.MNT_DETACH)
+	}
+
+	if pid, err := daemon.ReadPidFromFile(pidFile(m)); err == nil {
+		_ = daemon.Kill(pid, false)
+	}
+	_ = os.Remove(pidFile(m))
+	return nil
+}
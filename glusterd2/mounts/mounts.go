@@ -0,0 +1,191 @@
+// Package mounts manages the FUSE mounts of the volumes on the peers
+// themselves, used by the daemons of glusterd2 like the quota crawler and
+// geo-replication, and by admins. The mounts are saved in the store, and
+// mounted again when glusterd2 restarts or when found down.
+package mounts
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/gluster/glusterd2/glusterd2/configsnap"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// mountsPrefix is where the mounts are saved, by peer ID and mount ID
+const mountsPrefix = "mounts/"
+
+func init() {
+	configsnap.RegisterPrefix(mountsPrefix)
+}
+
+// option is a mount option, turned into an argument of the client process
+type option struct {
+	// flag is the argument given to the client process
+	flag string
+	// check checks the value of the option, nil for an option taking no
+	// value
+	check func(value string) error
+}
+
+func checkSeconds(value string) error {
+	if n, err := strconv.ParseFloat(value, 64); err != nil || n < 0 {
+		return errors.New("must be a number of seconds")
+	}
+	return nil
+}
+
+func checkCount(value string) error {
+	if n, err := strconv.Atoi(value); err != nil || n < 0 {
+		return errors.New("must be a positive number")
+	}
+	return nil
+}
+
+func checkOneOf(values ...string) func(string) error {
+	return func(value string) error {
+		for _, v := range values {
+			if value == v {
+				return nil
+			}
+		}
+		return fmt.Errorf("must be one of %v", values)
+	}
+}
+
+func checkSubdir(value string) error {
+	if !path.IsAbs(value) || path.Clean(value) != value {
+		return errors.New("must be an absolute and clean path in the volume")
+	}
+	return nil
+}
+
+// options are the mount options supported, by name
+var options = map[string]option{
+	"ro":                  {"--read-only", nil},
+	"acl":                 {"--acl", nil},
+	"attribute-timeout":   {"--attribute-timeout", checkSeconds},
+	"entry-timeout":       {"--entry-timeout", checkSeconds},
+	"negative-timeout":    {"--negative-timeout", checkSeconds},
+	"direct-io-mode":      {"--direct-io-mode", checkOneOf("enable", "disable")},
+	"use-readdirp":        {"--use-readdirp", checkOneOf("yes", "no")},
+	"log-level":           {"--log-level", checkOneOf("CRITICAL", "ERROR", "WARNING", "INFO", "DEBUG", "TRACE", "NONE")},
+	"reader-thread-count": {"--reader-thread-count", checkCount},
+	"lru-limit":           {"--lru-limit", checkCount},
+	"subdir-mount":        {"--subdir-mount", checkSubdir},
+}
+
+// Validate checks the mount to be created
+func Validate(m *api.Mount) error {
+	if m.Volume == "" {
+		return gderrors.ErrEmptyVolName
+	}
+	if !filepath.IsAbs(m.Path) || filepath.Clean(m.Path) != m.Path || m.Path == "/" {
+		return errors.New("path must be an absolute and clean path, other than /")
+	}
+	for name, value := range m.Options {
+		opt, ok := options[name]
+		if !ok {
+			return fmt.Errorf("unsupported mount option %s", name)
+		}
+		if opt.check == nil {
+			if value != "" {
+				return fmt.Errorf("mount option %s takes no value", name)
+			}
+			continue
+		}
+		if err := opt.check(value); err != nil {
+			return fmt.Errorf("mount option %s: %s", name, err)
+		}
+	}
+	return nil
+}
+
+func peerKey(peerID uuid.UUID) string {
+	return mountsPrefix + peerID.String() + "/"
+}
+
+func mountKey(peerID, id uuid.UUID) string {
+	return peerKey(peerID) + id.String()
+}
+
+// Save saves the mount, failing if the peer has another mount with the
+// same path
+func Save(m *api.Mount) error {
+	list, err := List(m.PeerID)
+	if err != nil {
+		return err
+	}
+	for _, other := range list {
+		if other.Path == m.Path && !uuid.Equal(other.ID, m.ID) {
+			return gderrors.ErrMountExists
+		}
+	}
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	_, err = store.Put(context.TODO(), mountKey(m.PeerID, m.ID), string(data))
+	return err
+}
+
+// Get returns the mount of the peer with the ID
+func Get(peerID, id uuid.UUID) (*api.Mount, error) {
+	resp, err := store.Get(context.TODO(), mountKey(peerID, id))
+	if err != nil {
+		return nil, err
+	}
+	if resp.Count != 1 {
+		return nil, gderrors.ErrMountNotFound
+	}
+	var m api.Mount
+	if err := json.Unmarshal(resp.Kvs[0].Value, &m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// List returns the mounts of the peer, sorted by path
+func List(peerID uuid.UUID) ([]api.Mount, error) {
+	resp, err := store.Get(context.TODO(), peerKey(peerID), clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	mounts := make([]api.Mount, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var m api.Mount
+		if err := json.Unmarshal(kv.Value, &m); err != nil {
+			log.WithError(err).WithField("key", string(kv.Key)).Error("failed to unmarshal mount")
+			continue
+		}
+		mounts = append(mounts, m)
+	}
+	sort.Slice(mounts, func(i, j int) bool { return mounts[i].Path < mounts[j].Path })
+	return mounts, nil
+}
+
+// Delete deletes the mount of the peer with the ID from the store
+func Delete(peerID, id uuid.UUID) error {
+	resp, err := store.Delete(context.TODO(), mountKey(peerID, id))
+	if err != nil {
+		return err
+	}
+	if resp.Deleted == 0 {
+		return gderrors.ErrMountNotFound
+	}
+	return nil
+}
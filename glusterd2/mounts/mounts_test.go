@@ -0,0 +1,62 @@
+package mounts
+
+import (
+	"testing"
+
+	"github.com/gluster/glusterd2/pkg/api"
+
+	config "github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	m := &api.Mount{Volume: "vol", Path: "/mnt/vol"}
+	assert.NoError(t, Validate(m))
+
+	m.Options = map[string]string{"ro": "", "attribute-timeout": "0.5", "subdir-mount": "/dir"}
+	assert.NoError(t, Validate(m))
+
+	for _, opts := range []map[string]string{
+		{"ro": "yes"},
+		{"attribute-timeout": "long"},
+		{"direct-io-mode": "on"},
+		{"subdir-mount": "dir"},
+		{"allow_other": ""},
+	} {
+		assert.Error(t, Validate(&api.Mount{Volume: "vol", Path: "/mnt/vol", Options: opts}), "%v", opts)
+	}
+
+	assert.Error(t, Validate(&api.Mount{Path: "/mnt/vol"}))
+	assert.Error(t, Validate(&api.Mount{Volume: "vol", Path: "mnt/vol"}))
+	assert.Error(t, Validate(&api.Mount{Volume: "vol", Path: "/mnt/../vol"}))
+	assert.Error(t, Validate(&api.Mount{Volume: "vol", Path: "/"}))
+}
+
+func TestArgs(t *testing.T) {
+	config.Set("clientaddress", ":24007")
+	config.Set("rundir", "/var/run/glusterd2")
+	config.Set("logdir", "/var/log/glusterd2")
+
+	m := &api.Mount{
+		Volume:  "vol",
+		Path:    "/mnt/vol",
+		Options: map[string]string{"ro": "", "attribute-timeout": "5"},
+	}
+	assert.Equal(t, []string{
+		"--volfile-server", "127.0.0.1",
+		"--volfile-server-port", "24007",
+		"--volfile-id", "vol",
+		"-p", "/var/run/glusterd2/mount-mnt-vol.pid",
+		"-l", "/var/log/glusterd2/glusterfs/mnt-mnt-vol.log",
+		"--attribute-timeout=5",
+		"--read-only",
+		"/mnt/vol",
+	}, args(m))
+}
+
+func TestStatusNotMounted(t *testing.T) {
+	config.Set("rundir", "/nonexistent")
+	online, pid := Status(&api.Mount{Volume: "vol", Path: "/nonexistent/mnt"})
+	assert.False(t, online)
+	assert.Equal(t, 0, pid)
+}
@@ -0,0 +1,90 @@
+package mounts
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/events"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/transactionv2"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	log "github.com/sirupsen/logrus"
+	flag "github.com/spf13/pflag"
+	config "github.com/spf13/viper"
+)
+
+const (
+	intervalOpt = "mount-check-interval"
+
+	// EventMountRemounted is broadcast when a mount found down is mounted
+	// again
+	EventMountRemounted = "mount.remounted"
+	// EventMountFailed is broadcast when a mount found down cannot be
+	// mounted again
+	EventMountFailed = "mount.failed"
+)
+
+var (
+	stopChan chan struct{}
+	stopOnce sync.Once
+)
+
+// InitFlags intializes the command line options for the managed mounts
+func InitFlags() {
+	flag.Duration(intervalOpt, 30*time.Second, "Interval at which the mounts managed by glusterd2 on this peer are checked, and mounted again if down. Set to 0 to only mount them when glusterd2 starts.")
+}
+
+func eventData(m *api.Mount) map[string]string {
+	return map[string]string{
+		"mount.id":     m.ID.String(),
+		"mount.volume": m.Volume,
+		"mount.path":   m.Path,
+	}
+}
+
+// remount mounts again the mounts of this peer which are down
+func remount() {
+	list, err := List(gdctx.MyUUID)
+	if err != nil {
+		log.WithError(err).Error("failed to get the mounts of the peer")
+		return
+	}
+
+	for i := range list {
+		m := &list[i]
+		if online, _ := Status(m); online {
+			continue
+		}
+		logger := log.WithFields(log.Fields{"volume": m.Volume, "path": m.Path})
+		if err := Mount(m); err != nil {
+			logger.WithError(err).Error("failed to mount the volume again")
+			events.Broadcast(events.New(EventMountFailed, eventData(m), false))
+			continue
+		}
+		logger.Info("mounted the volume again")
+		events.Broadcast(events.New(EventMountRemounted, eventData(m), false))
+	}
+}
+
+// Start mounts the mounts of this peer now, and periodically checks them
+func Start() {
+	interval := config.GetDuration(intervalOpt)
+	if interval <= 0 {
+		go remount()
+		return
+	}
+
+	stopChan = make(chan struct{})
+	go transaction.UntilStop(remount, interval, stopChan)
+}
+
+// Stop stops the periodic checks
+func Stop() {
+	if stopChan == nil {
+		return
+	}
+	stopOnce.Do(func() {
+		close(stopChan)
+	})
+}
@@ -0,0 +1,387 @@
+// Package namespace manages the tenants of the cluster: the namespaces the
+// volumes belong to, their quotas, and the API tokens scoped to them.
+package namespace
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/configsnap"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// namespacesPrefix is where the namespaces are saved, under their name
+	namespacesPrefix = "namespaces/"
+	// tokensPrefix is where the tokens of the namespaces are saved, under
+	// their ID, which the requests are issued by
+	tokensPrefix = "namespacetokens/"
+
+	tokenSecretSize = 32
+)
+
+var validName = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+func init() {
+	configsnap.RegisterPrefix(namespacesPrefix)
+}
+
+// token is an API token of a namespace, as saved in the store
+type token struct {
+	ID        string    `json:"id"`
+	Secret    string    `json:"secret"`
+	Namespace string    `json:"namespace"`
+	CreatedAt time.Time `json:"created-at"`
+}
+
+// Validate checks the namespace to be created
+func Validate(ns *api.Namespace) error {
+	if !validName.MatchString(ns.Name) {
+		return errors.New("name must be at most 63 lowercase letters, digits and '-', starting and ending with a letter or digit")
+	}
+	if ns.MaxVolumes < 0 {
+		return errors.New("max-volumes must not be negative")
+	}
+	return nil
+}
+
+// LockID returns the ID of the cluster lock held while provisioning volumes
+// in the namespace, so that concurrent requests cannot exceed its quotas
+func LockID(name string) string {
+	return "namespace/" + name
+}
+
+// Add creates the namespace
+func Add(ns *api.Namespace) error {
+	data, err := json.Marshal(ns)
+	if err != nil {
+		return err
+	}
+	key := namespacesPrefix + ns.Name
+	resp, err := store.Txn(context.TODO()).
+		If(clientv3.Compare(clientv3.CreateRevision(key), "=", 0)).
+		Then(clientv3.OpPut(key, string(data))).
+		Commit()
+	if err != nil {
+		return err
+	}
+	if !resp.Succeeded {
+		return gderrors.ErrNamespaceExists
+	}
+	return nil
+}
+
+// Get returns the namespace with the name
+func Get(name string) (*api.Namespace, error) {
+	resp, err := store.Get(context.TODO(), namespacesPrefix+name)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Count != 1 {
+		return nil, gderrors.ErrNamespaceNotFound
+	}
+	var ns api.Namespace
+	if err := json.Unmarshal(resp.Kvs[0].Value, &ns); err != nil {
+		return nil, err
+	}
+	return &ns, nil
+}
+
+// List returns the namespaces, sorted by name
+func List() ([]api.Namespace, error) {
+	resp, err := store.Get(context.TODO(), namespacesPrefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]api.Namespace, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var ns api.Namespace
+		if err := json.Unmarshal(kv.Value, &ns); err != nil {
+			log.WithError(err).WithField("key", string(kv.Key)).Error("Failed to unmarshal namespace")
+			continue
+		}
+		list = append(list, ns)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+// SetQuotas changes the quotas of the namespace. Lowering them below the
+// usage of the namespace only prevents it from provisioning more.
+func SetQuotas(name string, maxVolumes int, maxCapacity uint64) (*api.Namespace, error) {
+	if maxVolumes < 0 {
+		return nil, errors.New("max-volumes must not be negative")
+	}
+	ns, err := Get(name)
+	if err != nil {
+		return nil, err
+	}
+	ns.MaxVolumes = maxVolumes
+	ns.MaxCapacity = maxCapacity
+
+	data, err := json.Marshal(ns)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := store.Put(context.TODO(), namespacesPrefix+name, string(data)); err != nil {
+		return nil, err
+	}
+	return ns, nil
+}
+
+// Delete removes the namespace and its tokens. A namespace with volumes,
+// including the deleted ones not purged yet, cannot be deleted.
+func Delete(name string) error {
+	if _, err := Get(name); err != nil {
+		return err
+	}
+	usage, err := GetUsage(name)
+	if err != nil {
+		return err
+	}
+	if usage.Volumes > 0 {
+		return gderrors.ErrNamespaceNotEmpty
+	}
+
+	tokens, err := getTokens(name)
+	if err != nil {
+		return err
+	}
+	ops := []clientv3.Op{clientv3.OpDelete(namespacesPrefix + name)}
+	for _, t := range tokens {
+		ops = append(ops, clientv3.OpDelete(tokensPrefix+t.ID))
+	}
+	_, err = store.Txn(context.TODO()).Then(ops...).Commit()
+	return err
+}
+
+// GetUsage returns what the volumes of the namespace use of its quotas. The
+// deleted volumes not purged yet still hold their bricks, and count.
+func GetUsage(name string) (*api.NamespaceUsage, error) {
+	volumes, err := volume.GetVolumes(context.TODO())
+	if err != nil {
+		return nil, err
+	}
+	trashed, err := volume.GetTrashedVolumes()
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range trashed {
+		volumes = append(volumes, t.Volinfo)
+	}
+
+	var usage api.NamespaceUsage
+	for _, v := range volumes {
+		if v.Namespace != name {
+			continue
+		}
+		usage.Volumes++
+		usage.Capacity += v.Capacity
+	}
+	return &usage, nil
+}
+
+// CheckQuotas checks that the namespace can have the volumes and the
+// capacity added. The capacity of the volumes whose bricks are given by the
+// admin is not known, and does not count.
+func CheckQuotas(name string, volumes int, capacity uint64) error {
+	ns, err := Get(name)
+	if err != nil {
+		return err
+	}
+	usage, err := GetUsage(name)
+	if err != nil {
+		return err
+	}
+	return checkQuotas(ns, usage, volumes, capacity)
+}
+
+func checkQuotas(ns *api.Namespace, usage *api.NamespaceUsage, volumes int, capacity uint64) error {
+	if ns.MaxVolumes > 0 && usage.Volumes+volumes > ns.MaxVolumes {
+		return &QuotaExceededError{
+			Namespace: ns.Name,
+			Quota:     "max-volumes",
+			Limit:     uint64(ns.MaxVolumes),
+			Used:      uint64(usage.Volumes),
+			Requested: uint64(volumes),
+		}
+	}
+	if ns.MaxCapacity > 0 && usage.Capacity+capacity > ns.MaxCapacity {
+		return &QuotaExceededError{
+			Namespace: ns.Name,
+			Quota:     "max-capacity",
+			Limit:     ns.MaxCapacity,
+			Used:      usage.Capacity,
+			Requested: capacity,
+		}
+	}
+	return nil
+}
+
+// QuotaExceededError is returned when a request would exceed a quota of a
+// namespace. The type implements the `api.ErrorResponse` interface to tell
+// the quota in the response.
+type QuotaExceededError struct {
+	Namespace string
+	Quota     string
+	Limit     uint64
+	Used      uint64
+	Requested uint64
+}
+
+func (e *QuotaExceededError) Error() string {
+	return fmt.Sprintf("namespace %s, %s %d, %d used, %d requested: %s",
+		e.Namespace, e.Quota, e.Limit, e.Used, e.Requested, gderrors.ErrNamespaceQuotaExceeded)
+}
+
+// Response returns the error response telling the quota exceeded
+func (e *QuotaExceededError) Response() api.ErrorResp {
+	return api.ErrorResp{
+		Errors: []api.HTTPError{{
+			Code:    int(api.ErrCodeGeneric),
+			Message: e.Error(),
+			Reason:  api.ReasonQuotaExceeded,
+			Fields: map[string]string{
+				"namespace": e.Namespace,
+				"quota":     e.Quota,
+				"limit":     strconv.FormatUint(e.Limit, 10),
+				"used":      strconv.FormatUint(e.Used, 10),
+				"requested": strconv.FormatUint(e.Requested, 10),
+			},
+		}},
+	}
+}
+
+// Status returns the HTTP status of the error response
+func (e *QuotaExceededError) Status() int {
+	return http.StatusForbidden
+}
+
+// CreateToken creates an API token scoped to the namespace. Its secret is
+// only known to the caller.
+func CreateToken(name string) (*api.NamespaceToken, error) {
+	if _, err := Get(name); err != nil {
+		return nil, err
+	}
+
+	secret := make([]byte, tokenSecretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	t := token{
+		ID:        uuid.NewRandom().String(),
+		Secret:    base64.RawURLEncoding.EncodeToString(secret),
+		Namespace: name,
+		CreatedAt: time.Now(),
+	}
+	data, err := json.Marshal(t)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := store.Put(context.TODO(), tokensPrefix+t.ID, string(data)); err != nil {
+		return nil, err
+	}
+
+	resp := t.toAPI()
+	resp.Secret = t.Secret
+	return &resp, nil
+}
+
+// TokenSecret returns the secret of the token with the ID, and the
+// namespace it is scoped to
+func TokenSecret(id string) (secret, namespace string, err error) {
+	t, err := getToken(id)
+	if err != nil {
+		return "", "", err
+	}
+	return t.Secret, t.Namespace, nil
+}
+
+// ListTokens returns the tokens of the namespace, without their secrets
+func ListTokens(name string) ([]api.NamespaceToken, error) {
+	if _, err := Get(name); err != nil {
+		return nil, err
+	}
+	tokens, err := getTokens(name)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]api.NamespaceToken, 0, len(tokens))
+	for _, t := range tokens {
+		list = append(list, t.toAPI())
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].CreatedAt.Before(list[j].CreatedAt) })
+	return list, nil
+}
+
+// DeleteToken revokes the token of the namespace
+func DeleteToken(name, id string) error {
+	t, err := getToken(id)
+	if err != nil {
+		return err
+	}
+	if t.Namespace != name {
+		return gderrors.ErrNamespaceTokenNotFound
+	}
+	_, err = store.Delete(context.TODO(), tokensPrefix+id)
+	return err
+}
+
+func (t *token) toAPI() api.NamespaceToken {
+	return api.NamespaceToken{
+		ID:        t.ID,
+		Namespace: t.Namespace,
+		CreatedAt: t.CreatedAt,
+	}
+}
+
+func getToken(id string) (*token, error) {
+	resp, err := store.Get(context.TODO(), tokensPrefix+id)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Count != 1 {
+		return nil, gderrors.ErrNamespaceTokenNotFound
+	}
+	var t token
+	if err := json.Unmarshal(resp.Kvs[0].Value, &t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func getTokens(name string) ([]token, error) {
+	resp, err := store.Get(context.TODO(), tokensPrefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+	var tokens []token
+	for _, kv := range resp.Kvs {
+		var t token
+		if err := json.Unmarshal(kv.Value, &t); err != nil {
+			log.WithError(err).WithField("key", string(kv.Key)).Error("Failed to unmarshal namespace token")
+			continue
+		}
+		if t.Namespace == name {
+			tokens = append(tokens, t)
+		}
+	}
+	return tokens, nil
+}
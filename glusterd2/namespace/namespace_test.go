@@ -0,0 +1,45 @@
+package namespace
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	for _, name := range []string{"a", "team-a", "0x"} {
+		assert.Nil(t, Validate(&api.Namespace{Name: name}), name)
+	}
+	for _, name := range []string{"", "-a", "a-", "Team", "team_a", "team/a"} {
+		assert.NotNil(t, Validate(&api.Namespace{Name: name}), name)
+	}
+	assert.NotNil(t, Validate(&api.Namespace{Name: "a", MaxVolumes: -1}))
+}
+
+func TestCheckQuotas(t *testing.T) {
+	ns := &api.Namespace{Name: "team-a", MaxVolumes: 2, MaxCapacity: 100}
+	usage := &api.NamespaceUsage{Volumes: 1, Capacity: 60}
+
+	assert.Nil(t, checkQuotas(ns, usage, 1, 40))
+	assert.Nil(t, checkQuotas(ns, usage, 0, 0))
+
+	err := checkQuotas(ns, usage, 2, 0)
+	require.NotNil(t, err)
+	qerr, ok := err.(*QuotaExceededError)
+	require.True(t, ok)
+	assert.Equal(t, "max-volumes", qerr.Quota)
+	assert.Equal(t, http.StatusForbidden, qerr.Status())
+	assert.Equal(t, api.ReasonQuotaExceeded, qerr.Response().Errors[0].Reason)
+
+	err = checkQuotas(ns, usage, 1, 41)
+	require.NotNil(t, err)
+	assert.Equal(t, "max-capacity", err.(*QuotaExceededError).Quota)
+	assert.Equal(t, "60", err.(*QuotaExceededError).Response().Errors[0].Fields["used"])
+
+	// 0 is no limit
+	assert.Nil(t, checkQuotas(&api.Namespace{Name: "team-b"}, usage, 10, 1000))
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
 	"github.com/gluster/glusterd2/glusterd2/store"
 	"github.com/gluster/glusterd2/pkg/errors"
+	"github.com/gluster/glusterd2/version"
 
 	"strconv"
 )
@@ -27,12 +28,29 @@ type validateFunc func(string, string) error
 
 // ClusterOptMap contains list of supported cluster-wide options, default values and value types
 var ClusterOptMap = map[string]*ClusterOption{
-	"cluster.shared-storage":         {"cluster.shared-storage", "off", OptionTypeBool, nil},
-	"cluster.op-version":             {"cluster.op-version", strconv.Itoa(gdctx.OpVersion), OptionTypeInt, nil},
-	"cluster.max-op-version":         {"cluster.max-op-version", strconv.Itoa(gdctx.OpVersion), OptionTypeInt, nil},
-	"cluster.brick-multiplex":        {"cluster.brick-multiplex", "off", OptionTypeBool, nil},
-	"cluster.max-bricks-per-process": {"cluster.max-bricks-per-process", "250", OptionTypeInt, nil},
-	"cluster.localtime-logging":      {"cluster.localtime-logging", "off", OptionTypeBool, nil},
+	"cluster.shared-storage":            {"cluster.shared-storage", "off", OptionTypeBool, nil},
+	"cluster.op-version":                {"cluster.op-version", strconv.Itoa(version.MinOpVersion), OptionTypeInt, nil},
+	"cluster.max-op-version":            {"cluster.max-op-version", strconv.Itoa(gdctx.OpVersion), OptionTypeInt, nil},
+	"cluster.brick-multiplex":           {"cluster.brick-multiplex", "off", OptionTypeBool, nil},
+	"cluster.max-bricks-per-process":    {"cluster.max-bricks-per-process", "250", OptionTypeInt, nil},
+	"cluster.localtime-logging":         {"cluster.localtime-logging", "off", OptionTypeBool, nil},
+	"cluster.brick-health-check-kill":   {"cluster.brick-health-check-kill", "on", OptionTypeBool, nil},
+	"cluster.brick-io-error-budget":     {"cluster.brick-io-error-budget", "0", OptionTypeInt, nil},
+	"cluster.brick-io-error-window":     {"cluster.brick-io-error-window", "300", OptionTypeInt, nil},
+	"cluster.brick-io-error-stop":       {"cluster.brick-io-error-stop", "off", OptionTypeBool, nil},
+	"cluster.orphan-brick-kill":         {"cluster.orphan-brick-kill", "off", OptionTypeBool, nil},
+	"cluster.drift-auto-correct":        {"cluster.drift-auto-correct", "off", OptionTypeBool, nil},
+	"cluster.server-quorum-ratio":       {"cluster.server-quorum-ratio", "0", OptionTypeInt, nil},
+	"cluster.server-quorum-stop-bricks": {"cluster.server-quorum-stop-bricks", "on", OptionTypeBool, nil},
+	"cluster.ca":                        {"cluster.ca", "off", OptionTypeStr, nil},
+	"cluster.ca-cert-validity-days":     {"cluster.ca-cert-validity-days", "90", OptionTypeInt, nil},
+	"cluster.config-snapshot-interval":  {"cluster.config-snapshot-interval", "60", OptionTypeInt, nil},
+	"cluster.config-snapshot-keep":      {"cluster.config-snapshot-keep", "48", OptionTypeInt, nil},
+	"cluster.brick-cpu-limit":           {"cluster.brick-cpu-limit", "0", OptionTypeInt, nil},
+	"cluster.brick-memory-limit":        {"cluster.brick-memory-limit", "0", OptionTypeSizet, nil},
+	"cluster.shd-cpu-limit":             {"cluster.shd-cpu-limit", "0", OptionTypeInt, nil},
+	"cluster.shd-memory-limit":          {"cluster.shd-memory-limit", "0", OptionTypeSizet, nil},
+	"cluster.volume-trash-retention":    {"cluster.volume-trash-retention", "24", OptionTypeInt, nil},
 	// setting cluster options for block hosting volume
 	"block-hosting-volume-size":          {"block-hosting-volume-size", "5GiB", OptionTypeSizeList, nil},
 	"auto-create-block-hosting-volumes":  {"auto-create-block-hosting-volumes", "true", OptionTypeBool, nil},
@@ -105,3 +123,58 @@ func UpdateClusterOptions(c *ClusterOptions) error {
 	_, err = store.Put(context.TODO(), clusterOptionsKey, string(b))
 	return err
 }
+
+// VolumeDefaults returns the default volume options set at the cluster level.
+// These are the cluster options which are not cluster-wide options.
+func (c *ClusterOptions) VolumeDefaults() map[string]string {
+	defaults := make(map[string]string)
+	for k, v := range c.Options {
+		if _, found := ClusterOptMap[k]; !found {
+			defaults[k] = v
+		}
+	}
+	return defaults
+}
+
+// volumeOptionID returns the key of a normalized volume option without the
+// graph it is restricted to
+func volumeOptionID(key string) string {
+	_, xl, optName := SplitKey(key)
+	return xl + "." + optName
+}
+
+// MergeVolumeOptions returns the options applying to a volume with the given
+// options set, on top of the given cluster level defaults. A default is
+// dropped if the volume sets the same option, whatever the graph either of
+// them is restricted to.
+func MergeVolumeOptions(defaults map[string]string, volOpts map[string]string) map[string]string {
+	overridden := make(map[string]bool)
+	for k := range volOpts {
+		overridden[volumeOptionID(k)] = true
+	}
+
+	merged := make(map[string]string)
+	for k, v := range defaults {
+		if !overridden[volumeOptionID(k)] {
+			merged[k] = v
+		}
+	}
+	for k, v := range volOpts {
+		merged[k] = v
+	}
+	return merged
+}
+
+// EffectiveVolumeOptions returns the options applying to a volume with the
+// given options set, which override the default volume options set at the
+// cluster level.
+func EffectiveVolumeOptions(volOpts map[string]string) (map[string]string, error) {
+	c, err := GetClusterOptions()
+	if err != nil {
+		if err == errors.ErrClusterOptionsNotFound {
+			return MergeVolumeOptions(nil, volOpts), nil
+		}
+		return nil, err
+	}
+	return MergeVolumeOptions(c.VolumeDefaults(), volOpts), nil
+}
@@ -0,0 +1,38 @@
+package options
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVolumeDefaults(t *testing.T) {
+	c := ClusterOptions{Options: map[string]string{
+		"cluster.brick-multiplex":             "on",
+		"replicate.self-heal-daemon":          "off",
+		"client.io-stats.latency-measurement": "on",
+	}}
+	assert.Equal(t, map[string]string{
+		"replicate.self-heal-daemon":          "off",
+		"client.io-stats.latency-measurement": "on",
+	}, c.VolumeDefaults())
+}
+
+func TestMergeVolumeOptions(t *testing.T) {
+	defaults := map[string]string{
+		"replicate.self-heal-daemon":          "off",
+		"client.io-stats.latency-measurement": "on",
+		"io-stats.count-fop-hits":             "on",
+	}
+	volOpts := map[string]string{
+		"brick.io-stats.latency-measurement": "off",
+		"io-stats.count-fop-hits":            "off",
+	}
+	assert.Equal(t, map[string]string{
+		"replicate.self-heal-daemon":         "off",
+		"brick.io-stats.latency-measurement": "off",
+		"io-stats.count-fop-hits":            "off",
+	}, MergeVolumeOptions(defaults, volOpts))
+
+	assert.Equal(t, volOpts, MergeVolumeOptions(nil, volOpts))
+}
@@ -0,0 +1,159 @@
+// Package opversion implements the operating version of the cluster, which
+// gates the features that all the peers of the cluster must support.
+//
+// Each peer supports the op-versions up to its own, gdctx.OpVersion. The
+// cluster op-version is raised explicitly once all the peers support the
+// new op-version, so that features are not used while peers running older
+// releases remain in the cluster.
+package opversion
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/options"
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	"github.com/gluster/glusterd2/pkg/errors"
+	"github.com/gluster/glusterd2/version"
+)
+
+const (
+	// Initial is the op-version supported by all the releases of
+	// glusterd2. Peers which have not reported their op-version are
+	// assumed to support it.
+	Initial = version.MinOpVersion
+
+	clusterOpVersionKey = "cluster.op-version"
+)
+
+// subvolTypes maps the subvolume types to the op-version from which they
+// can be created
+var subvolTypes = map[string]int{
+	"distribute": Initial,
+	"replicate":  Initial,
+	"disperse":   Initial,
+}
+
+func init() {
+	options.RegisterClusterOpValidationFunc(clusterOpVersionKey, validateClusterOpVersion)
+}
+
+// Cluster returns the op-version the cluster operates at
+func Cluster() (int, error) {
+	val, err := options.GetClusterOption(clusterOpVersionKey)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(val)
+}
+
+// MaxSupported returns the highest op-version supported by all the peers of
+// the cluster, which is the highest the cluster op-version can be raised to
+func MaxSupported() (int, error) {
+	peers, err := peer.GetPeers()
+	if err != nil {
+		return 0, err
+	}
+
+	max := version.MaxOpVersion
+	for _, p := range peers {
+		opVersion := p.MaxOpVersion
+		if opVersion == 0 {
+			opVersion = Initial
+		}
+		if opVersion < max {
+			max = opVersion
+		}
+	}
+	return max, nil
+}
+
+// Check returns an error if the cluster op-version is lower than the given
+// one
+func Check(required int) error {
+	current, err := Cluster()
+	if err != nil {
+		return err
+	}
+	if current < required {
+		return fmt.Errorf("%s: requires %d, cluster op-version is %d",
+			errors.ErrOpVersionTooLow, required, current)
+	}
+	return nil
+}
+
+// CheckLocal returns an error if this peer does not support the given
+// op-version
+func CheckLocal(required int) error {
+	if gdctx.OpVersion < required {
+		return fmt.Errorf("%s: requires %d, peer op-version is %d",
+			errors.ErrPeerOpVersionTooLow, required, gdctx.OpVersion)
+	}
+	return nil
+}
+
+// OfOption returns the op-version from which the option can be set
+func OfOption(o *options.Option) int {
+	if len(o.OpVersion) == 0 {
+		return Initial
+	}
+	return int(o.OpVersion[0])
+}
+
+// OfSubvolType returns the op-version from which subvolumes of the given
+// type can be created
+func OfSubvolType(subvolType string) int {
+	if opVersion, ok := subvolTypes[subvolType]; ok {
+		return opVersion
+	}
+	return Initial
+}
+
+// validateClusterOpVersion validates a new cluster op-version, which can
+// neither be lowered nor be raised past the op-version supported by all the
+// peers
+func validateClusterOpVersion(key string, value string) error {
+	opVersion, err := strconv.Atoi(value)
+	if err != nil {
+		return errors.ErrInvalidOpVersion
+	}
+
+	current, err := Cluster()
+	if err != nil {
+		return err
+	}
+	if opVersion < current {
+		return fmt.Errorf("%s: cannot lower the cluster op-version from %d to %d",
+			errors.ErrInvalidOpVersion, current, opVersion)
+	}
+
+	max, err := MaxSupported()
+	if err != nil {
+		return err
+	}
+	if opVersion > max {
+		return fmt.Errorf("%s: %d is higher than %d, the highest op-version supported by all the peers",
+			errors.ErrInvalidOpVersion, opVersion, max)
+	}
+	return nil
+}
+
+// Set raises the cluster op-version to the given one, which the peers must
+// all support. It must be called with the cluster options locked.
+func Set(opVersion int) error {
+	value := strconv.Itoa(opVersion)
+	if err := validateClusterOpVersion(clusterOpVersionKey, value); err != nil {
+		return err
+	}
+
+	c, err := options.GetClusterOptions()
+	if err != nil {
+		if err != errors.ErrClusterOptionsNotFound {
+			return err
+		}
+		c = &options.ClusterOptions{Options: make(map[string]string)}
+	}
+	c.Options[clusterOpVersionKey] = value
+	return options.UpdateClusterOptions(c)
+}
@@ -0,0 +1,25 @@
+package opversion
+
+import (
+	"testing"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/options"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOfOption(t *testing.T) {
+	assert.Equal(t, Initial, OfOption(&options.Option{}))
+	assert.Equal(t, 60000, OfOption(&options.Option{OpVersion: []uint32{60000, 0}}))
+}
+
+func TestOfSubvolType(t *testing.T) {
+	assert.Equal(t, Initial, OfSubvolType("replicate"))
+	assert.Equal(t, Initial, OfSubvolType("unknown"))
+}
+
+func TestCheckLocal(t *testing.T) {
+	assert.Nil(t, CheckLocal(gdctx.OpVersion))
+	assert.NotNil(t, CheckLocal(gdctx.OpVersion+1))
+}
@@ -0,0 +1,39 @@
+package opversion
+
+import (
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+
+	"github.com/pborman/uuid"
+)
+
+const requiredOpVersionKey = "required-op-version"
+
+// PrecheckStep checks that the cluster op-version is at least the given one,
+// and returns a step having each of the given nodes check that it supports
+// it. The step is to be the first of the transaction using the features of
+// that op-version.
+func PrecheckStep(c transaction.TxnCtx, required int, nodes []uuid.UUID) (*transaction.Step, error) {
+	if err := Check(required); err != nil {
+		return nil, err
+	}
+	if err := c.Set(requiredOpVersionKey, required); err != nil {
+		return nil, err
+	}
+	return &transaction.Step{
+		DoFunc: "op-version.Check",
+		Nodes:  nodes,
+	}, nil
+}
+
+func txnCheckOpVersion(c transaction.TxnCtx) error {
+	var required int
+	if err := c.Get(requiredOpVersionKey, &required); err != nil {
+		return err
+	}
+	return CheckLocal(required)
+}
+
+// RegisterStepFuncs registers the step functions checking the op-version
+func RegisterStepFuncs() {
+	transaction.RegisterStepFunc(txnCheckOpVersion, "op-version.Check")
+}
@@ -0,0 +1,318 @@
+// Package orphanbricks detects the glusterfsd processes started by this peer
+// which no longer serve a brick of the cluster, left behind by crashes or by
+// volumes deleted while their bricks could not be stopped, and cleans them
+// up.
+package orphanbricks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/daemon"
+	"github.com/gluster/glusterd2/glusterd2/events"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/options"
+	"github.com/gluster/glusterd2/glusterd2/pmap"
+	"github.com/gluster/glusterd2/glusterd2/snapshot"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/glusterd2/transactionv2"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/coreos/etcd/clientv3"
+	log "github.com/sirupsen/logrus"
+	flag "github.com/spf13/pflag"
+	config "github.com/spf13/viper"
+)
+
+const (
+	// EventOrphanDetected is broadcast when a brick process is found
+	// orphaned
+	EventOrphanDetected = "brick_process_orphaned"
+	// EventOrphanKilled is broadcast when an orphaned brick process is
+	// killed
+	EventOrphanKilled = "brick_process_orphan_killed"
+
+	intervalOpt = "orphan-brick-scan-interval"
+	killKey     = "cluster.orphan-brick-kill"
+
+	// orphansPrefix is where the orphaned brick processes detected by the
+	// peers are saved in the store, by peer ID
+	orphansPrefix = "bricks/orphans/"
+
+	glusterfsdBin = "glusterfsd"
+)
+
+// suspect is a process found orphaned by the last scan. It is confirmed as
+// orphaned when found again by the next scan, as the bricks being started by
+// a transaction run before their volume is saved in the store.
+type suspect struct {
+	api.OrphanBrickProcess
+	confirmed bool
+}
+
+var (
+	stopChan chan struct{}
+	stopOnce sync.Once
+
+	// scanLock serializes the periodic scans and the cleanups on demand
+	scanLock sync.Mutex
+	suspects = make(map[int]*suspect)
+)
+
+// InitFlags intializes the command line options for the detection of the
+// orphaned brick processes
+func InitFlags() {
+	flag.Duration(intervalOpt, 5*time.Minute, "Interval at which the brick processes are checked for being orphaned. Set to 0 to disable the checks.")
+}
+
+// Start scans for orphaned brick processes now and periodically
+func Start() {
+	interval := config.GetDuration(intervalOpt)
+	if interval <= 0 {
+		log.Info("detection of orphaned brick processes disabled")
+		return
+	}
+
+	stopChan = make(chan struct{})
+	go transaction.UntilStop(func() {
+		if _, err := scan(killEnabled()); err != nil {
+			log.WithError(err).Error("failed to scan for orphaned brick processes")
+		}
+	}, interval, stopChan)
+}
+
+// Stop stops the periodic scans
+func Stop() {
+	if stopChan == nil {
+		return
+	}
+	stopOnce.Do(func() {
+		close(stopChan)
+	})
+}
+
+// killEnabled tells if the orphaned brick processes are to be killed as
+// soon as they are confirmed
+func killEnabled() bool {
+	value, err := options.GetClusterOption(killKey)
+	if err != nil {
+		return false
+	}
+	kill, err := options.StringToBoolean(value)
+	if err != nil {
+		return false
+	}
+	return kill
+}
+
+// parseCmdline returns the brick served by a glusterfsd process started by
+// the glusterd2 with the given ID, given the contents of
+// /proc/<pid>/cmdline of the process
+func parseCmdline(cmdline []byte, nodeID string) (api.OrphanBrickProcess, bool) {
+	var p api.OrphanBrickProcess
+
+	args := strings.Split(string(bytes.TrimRight(cmdline, "\x00")), "\x00")
+	if filepath.Base(args[0]) != glusterfsdBin {
+		return p, false
+	}
+
+	var ours bool
+	for i := 1; i < len(args)-1; i++ {
+		switch args[i] {
+		case "--brick-name":
+			p.BrickPath = args[i+1]
+		case "--volfile-id":
+			p.VolfileID = args[i+1]
+		case "--xlator-option":
+			ours = ours || args[i+1] == "*-posix.glusterd-uuid="+nodeID
+		}
+	}
+	return p, ours && p.BrickPath != ""
+}
+
+// localBricks returns the paths of the bricks of this peer of the started
+// volumes and activated snapshots
+func localBricks() (map[string]bool, error) {
+	volumes, err := volume.GetVolumes(context.TODO())
+	if err != nil {
+		return nil, err
+	}
+	snapVolumes, err := snapshot.GetActivatedSnapshotVolumes()
+	if err != nil {
+		return nil, err
+	}
+
+	bricks := make(map[string]bool)
+	for _, v := range append(volumes, snapVolumes...) {
+		if v.State != volume.VolStarted {
+			continue
+		}
+		for _, b := range v.GetLocalBricks() {
+			bricks[b.Path] = true
+		}
+	}
+	return bricks, nil
+}
+
+// Find returns the glusterfsd processes started by this peer which serve no
+// brick of a started volume or activated snapshot
+func Find() ([]api.OrphanBrickProcess, error) {
+	bricks, err := localBricks()
+	if err != nil {
+		return nil, err
+	}
+
+	// processes having bricks multiplexed into them may have been
+	// started for a brick which is no longer served
+	serving := make(map[int]bool)
+	for _, e := range pmap.RegistryEntries() {
+		if bricks[e.BrickPath] {
+			serving[e.PID] = true
+		}
+	}
+
+	var orphans []api.OrphanBrickProcess
+	cmdlines, _ := filepath.Glob("/proc/[0-9]*/cmdline")
+	for _, path := range cmdlines {
+		pid, err := strconv.Atoi(filepath.Base(filepath.Dir(path)))
+		if err != nil || serving[pid] {
+			continue
+		}
+		cmdline, err := ioutil.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		p, ok := parseCmdline(cmdline, gdctx.MyUUID.String())
+		if !ok || bricks[p.BrickPath] {
+			continue
+		}
+		p.PeerID = gdctx.MyUUID
+		p.PID = pid
+		orphans = append(orphans, p)
+	}
+	return orphans, nil
+}
+
+func eventData(p api.OrphanBrickProcess) map[string]string {
+	return map[string]string{
+		"peer.id":    p.PeerID.String(),
+		"pid":        strconv.Itoa(p.PID),
+		"brick.path": p.BrickPath,
+		"volfile-id": p.VolfileID,
+	}
+}
+
+// track updates the suspects with the processes found orphaned, and
+// returns the processes confirmed as orphaned. A process is confirmed when
+// found by two scans in a row.
+func track(found []api.OrphanBrickProcess, now time.Time) []*suspect {
+	current := make(map[int]*suspect)
+	var confirmed []*suspect
+	for _, p := range found {
+		s := &suspect{OrphanBrickProcess: p}
+		s.Since = now
+		if prev, ok := suspects[p.PID]; ok && prev.BrickPath == p.BrickPath {
+			s.Since = prev.Since
+			s.confirmed = true
+			if !prev.confirmed {
+				log.WithFields(log.Fields{
+					"pid":   p.PID,
+					"brick": p.BrickPath,
+				}).Warn("brick process is orphaned, it serves no brick of a started volume")
+				events.Broadcast(events.New(EventOrphanDetected, eventData(p), true))
+			}
+			confirmed = append(confirmed, s)
+		}
+		current[p.PID] = s
+	}
+	suspects = current
+	return confirmed
+}
+
+// scan finds the orphaned brick processes of this peer, and saves those
+// confirmed in the store. If kill is set, the confirmed processes are
+// killed, and returned.
+func scan(kill bool) ([]api.OrphanBrickProcess, error) {
+	scanLock.Lock()
+	defer scanLock.Unlock()
+
+	found, err := Find()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		orphans []api.OrphanBrickProcess
+		killed  []api.OrphanBrickProcess
+	)
+	for _, s := range track(found, time.Now()) {
+		if !kill {
+			orphans = append(orphans, s.OrphanBrickProcess)
+			continue
+		}
+
+		logger := log.WithFields(log.Fields{
+			"pid":   s.PID,
+			"brick": s.BrickPath,
+		})
+		if err := daemon.Kill(s.PID, false); err != nil {
+			logger.WithError(err).Error("failed to kill orphaned brick process")
+			orphans = append(orphans, s.OrphanBrickProcess)
+			continue
+		}
+		logger.Warn("killed orphaned brick process")
+		events.Broadcast(events.New(EventOrphanKilled, eventData(s.OrphanBrickProcess), true))
+		delete(suspects, s.PID)
+		killed = append(killed, s.OrphanBrickProcess)
+	}
+
+	return killed, save(orphans)
+}
+
+// Cleanup kills the orphaned brick processes of this peer, which were found
+// by the last scan and are still orphaned. It returns the processes killed.
+func Cleanup() ([]api.OrphanBrickProcess, error) {
+	return scan(true)
+}
+
+// save saves the orphaned brick processes of this peer in the store
+func save(orphans []api.OrphanBrickProcess) error {
+	key := orphansPrefix + gdctx.MyUUID.String()
+	if len(orphans) == 0 {
+		_, err := store.Delete(context.TODO(), key)
+		return err
+	}
+
+	data, err := json.Marshal(orphans)
+	if err != nil {
+		return err
+	}
+	_, err = store.Put(context.TODO(), key, string(data))
+	return err
+}
+
+// List returns the orphaned brick processes detected by all the peers
+func List() ([]api.OrphanBrickProcess, error) {
+	resp, err := store.Get(context.TODO(), orphansPrefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	orphans := make([]api.OrphanBrickProcess, 0)
+	for _, kv := range resp.Kvs {
+		var peerOrphans []api.OrphanBrickProcess
+		if err := json.Unmarshal(kv.Value, &peerOrphans); err != nil {
+			return nil, err
+		}
+		orphans = append(orphans, peerOrphans...)
+	}
+	return orphans, nil
+}
@@ -0,0 +1,50 @@
+package orphanbricks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCmdline(t *testing.T) {
+	nodeID := "0f1ec3f0-3b1a-4a4a-9b2a-4bd3e3b3c9a1"
+	cmdline := []byte("/usr/sbin/glusterfsd\x00--volfile-id\x00vol.id.brick\x00--brick-name\x00/bricks/b1\x00" +
+		"--xlator-option\x00*-posix.glusterd-uuid=" + nodeID + "\x00")
+
+	p, ok := parseCmdline(cmdline, nodeID)
+	assert.True(t, ok)
+	assert.Equal(t, "/bricks/b1", p.BrickPath)
+	assert.Equal(t, "vol.id.brick", p.VolfileID)
+
+	// bricks of other glusterds are not ours
+	_, ok = parseCmdline(cmdline, "c2f1b6a4-e0b4-4a8e-8c8a-1d9a0b0e7f21")
+	assert.False(t, ok)
+
+	_, ok = parseCmdline([]byte("/usr/sbin/glusterfs\x00--brick-name\x00/bricks/b1\x00"), nodeID)
+	assert.False(t, ok)
+}
+
+func TestTrack(t *testing.T) {
+	suspects = make(map[int]*suspect)
+	first := time.Now()
+	b1 := api.OrphanBrickProcess{PID: 10, BrickPath: "/bricks/b1"}
+	b2 := api.OrphanBrickProcess{PID: 20, BrickPath: "/bricks/b2"}
+
+	// processes are only suspected on the first scan
+	assert.Empty(t, track([]api.OrphanBrickProcess{b1}, first))
+
+	second := first.Add(time.Minute)
+	confirmed := track([]api.OrphanBrickProcess{b1, b2}, second)
+	assert.Len(t, confirmed, 1)
+	assert.Equal(t, 10, confirmed[0].PID)
+	assert.Equal(t, first, confirmed[0].Since)
+
+	// a PID reused by another brick process is suspected anew
+	b2.BrickPath = "/bricks/b3"
+	confirmed = track([]api.OrphanBrickProcess{b2}, second.Add(time.Minute))
+	assert.Empty(t, confirmed)
+	assert.Len(t, suspects, 1)
+}
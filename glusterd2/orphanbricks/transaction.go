@@ -0,0 +1,48 @@
+package orphanbricks
+
+import (
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/pborman/uuid"
+)
+
+const killedTxnKey = "orphanbricks.killed"
+
+// CleanupStep returns a step having each of the given nodes kill its
+// orphaned brick processes
+func CleanupStep(nodes []uuid.UUID) *transaction.Step {
+	return &transaction.Step{
+		DoFunc: "orphanbricks.Cleanup",
+		Nodes:  nodes,
+	}
+}
+
+// Killed returns the processes killed by the nodes in the cleanup step
+func Killed(c transaction.TxnCtx, nodes []uuid.UUID) []api.OrphanBrickProcess {
+	killed := make([]api.OrphanBrickProcess, 0)
+	for _, node := range nodes {
+		var nodeKilled []api.OrphanBrickProcess
+		if err := c.GetNodeResult(node, killedTxnKey, &nodeKilled); err != nil {
+			continue
+		}
+		killed = append(killed, nodeKilled...)
+	}
+	return killed
+}
+
+func txnCleanup(c transaction.TxnCtx) error {
+	killed, err := Cleanup()
+	if err != nil {
+		c.Logger().WithError(err).Error("failed to clean up the orphaned brick processes")
+		return err
+	}
+	return c.SetNodeResult(gdctx.MyUUID, killedTxnKey, killed)
+}
+
+// RegisterStepFuncs registers the step function cleaning up the orphaned
+// brick processes
+func RegisterStepFuncs() {
+	transaction.RegisterStepFunc(txnCleanup, "orphanbricks.Cleanup")
+}
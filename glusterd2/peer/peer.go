@@ -14,6 +14,11 @@ type Peer struct {
 	PeerAddresses   []string
 	ClientAddresses []string
 	Metadata        map[string]string
+	// Version is the glusterd2 version the peer runs, and MaxOpVersion the
+	// highest op-version it supports. Both are updated by the peer itself
+	// on starting, and are empty for peers which have not reported them.
+	Version      string
+	MaxOpVersion int
 }
 
 // ETCDConfig represents the structure which holds the ETCD env variables &
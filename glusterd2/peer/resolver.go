@@ -0,0 +1,155 @@
+package peer
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	flag "github.com/spf13/pflag"
+	config "github.com/spf13/viper"
+)
+
+const (
+	dnsTTLOpt = "peer-dns-ttl"
+
+	// minResolveInterval is the least time between two resolutions of a
+	// name after connections to it fail, so that a peer which is down
+	// does not flood the DNS servers
+	minResolveInterval = 5 * time.Second
+)
+
+// InitFlags intializes the command line options for the peers
+func InitFlags() {
+	flag.Duration(dnsTTLOpt, 30*time.Second, "Time the addresses a peer name resolves to are reused for, before the name is resolved again. The name is resolved sooner if the peer can not be reached at them.")
+}
+
+// resolved are the addresses a name resolved to
+type resolved struct {
+	addrs []string
+	at    time.Time
+	// stale is set when the peer could not be reached at the addresses
+	stale bool
+}
+
+// resolver resolves the names of the peers, and caches the addresses they
+// resolve to. The addresses of the peers in the store are left as they were
+// given, so that a peer whose IP address changes is still reached by its
+// name.
+type resolver struct {
+	sync.Mutex
+	names map[string]*resolved
+
+	lookup func(ctx context.Context, host string) ([]string, error)
+	ttl    func() time.Duration
+	now    func() time.Time
+}
+
+var defaultResolver = &resolver{
+	names:  make(map[string]*resolved),
+	lookup: net.DefaultResolver.LookupHost,
+	ttl: func() time.Duration {
+		return config.GetDuration(dnsTTLOpt)
+	},
+	now: time.Now,
+}
+
+// resolve returns the addresses the host resolves to. IP addresses are
+// returned as they are. The addresses last resolved are returned if the
+// name fails to resolve.
+func (r *resolver) resolve(ctx context.Context, host string) ([]string, error) {
+	if net.ParseIP(host) != nil {
+		return []string{host}, nil
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	now := r.now()
+	cached, ok := r.names[host]
+	if ok {
+		age := now.Sub(cached.at)
+		if age < r.ttl() && (!cached.stale || age < minResolveInterval) {
+			return cached.addrs, nil
+		}
+	}
+
+	addrs, err := r.lookup(ctx, host)
+	if err != nil || len(addrs) == 0 {
+		if ok {
+			log.WithError(err).WithField("host", host).Warn("failed to resolve peer name, using the addresses last resolved")
+			return cached.addrs, nil
+		}
+		if err == nil {
+			err = &net.DNSError{Err: "no addresses", Name: host}
+		}
+		return nil, err
+	}
+
+	if ok && !sameAddrs(cached.addrs, addrs) {
+		log.WithFields(log.Fields{
+			"host": host,
+			"old":  cached.addrs,
+			"new":  addrs,
+		}).Info("peer name resolves to new addresses")
+	}
+	r.names[host] = &resolved{addrs: addrs, at: now}
+	return addrs, nil
+}
+
+// failed marks the addresses the host resolved to as stale, for the name to
+// be resolved again on the next connection
+func (r *resolver) failed(host string) {
+	r.Lock()
+	defer r.Unlock()
+	if cached, ok := r.names[host]; ok {
+		cached.stale = true
+	}
+}
+
+func sameAddrs(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// DialTimeout connects to the peer address, given as host:port. A host name
+// is resolved through the cache of the names of the peers, and is resolved
+// again if the peer can not be reached at any of the addresses it resolved
+// to. The signature fits grpc.WithDialer.
+func DialTimeout(addr string, timeout time.Duration) (net.Conn, error) {
+	return defaultResolver.dial(addr, timeout)
+}
+
+func (r *resolver) dial(addr string, timeout time.Duration) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	ips, err := r.resolve(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	var d net.Dialer
+	for _, ip := range ips {
+		var conn net.Conn
+		conn, err = d.DialContext(ctx, "tcp", net.JoinHostPort(ip, port))
+		if err == nil {
+			return conn, nil
+		}
+	}
+	r.failed(host)
+	return nil, err
+}
@@ -0,0 +1,101 @@
+package peer
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolve(t *testing.T) {
+	now := time.Unix(1000, 0)
+	answer := []string{"192.168.1.10"}
+	var lookupErr error
+	lookups := 0
+
+	r := &resolver{
+		names: make(map[string]*resolved),
+		lookup: func(ctx context.Context, host string) ([]string, error) {
+			lookups++
+			return answer, lookupErr
+		},
+		ttl: func() time.Duration { return 30 * time.Second },
+		now: func() time.Time { return now },
+	}
+	ctx := context.Background()
+
+	// IP addresses are not resolved
+	addrs, err := r.resolve(ctx, "10.0.0.1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"10.0.0.1"}, addrs)
+	assert.Equal(t, 0, lookups)
+
+	addrs, err = r.resolve(ctx, "node1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"192.168.1.10"}, addrs)
+	assert.Equal(t, 1, lookups)
+
+	// The addresses are reused until the TTL elapses
+	answer = []string{"192.168.1.20"}
+	now = now.Add(10 * time.Second)
+	addrs, _ = r.resolve(ctx, "node1")
+	assert.Equal(t, []string{"192.168.1.10"}, addrs)
+	assert.Equal(t, 1, lookups)
+
+	// A failed connection has the name resolved again
+	r.failed("node1")
+	addrs, _ = r.resolve(ctx, "node1")
+	assert.Equal(t, []string{"192.168.1.20"}, addrs)
+	assert.Equal(t, 2, lookups)
+
+	// though not more often than minResolveInterval
+	answer = []string{"192.168.1.30"}
+	r.failed("node1")
+	addrs, _ = r.resolve(ctx, "node1")
+	assert.Equal(t, []string{"192.168.1.20"}, addrs)
+	now = now.Add(minResolveInterval)
+	addrs, _ = r.resolve(ctx, "node1")
+	assert.Equal(t, []string{"192.168.1.30"}, addrs)
+	assert.Equal(t, 3, lookups)
+
+	// The addresses last resolved are used if the name fails to resolve
+	lookupErr = errors.New("no DNS server")
+	now = now.Add(time.Minute)
+	addrs, err = r.resolve(ctx, "node1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"192.168.1.30"}, addrs)
+
+	_, err = r.resolve(ctx, "node2")
+	assert.Error(t, err)
+}
+
+func TestDial(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+	_, port, _ := net.SplitHostPort(l.Addr().String())
+
+	answer := []string{"127.0.0.1"}
+	r := &resolver{
+		names: make(map[string]*resolved),
+		lookup: func(ctx context.Context, host string) ([]string, error) {
+			return answer, nil
+		},
+		ttl: func() time.Duration { return time.Minute },
+		now: time.Now,
+	}
+
+	conn, err := r.dial(net.JoinHostPort("node1", port), time.Second)
+	require.NoError(t, err)
+	conn.Close()
+	assert.False(t, r.names["node1"].stale)
+
+	l.Close()
+	_, err = r.dial(net.JoinHostPort("node1", port), time.Second)
+	assert.Error(t, err)
+	assert.True(t, r.names["node1"].stale)
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
 	"github.com/gluster/glusterd2/pkg/errors"
 	"github.com/gluster/glusterd2/pkg/utils"
+	"github.com/gluster/glusterd2/version"
 
 	config "github.com/spf13/viper"
 )
@@ -47,6 +48,8 @@ func AddSelfDetails() error {
 		ID:            gdctx.MyUUID,
 		Name:          gdctx.HostName,
 		PeerAddresses: []string{config.GetString("peeraddress")},
+		Version:       version.GlusterdVersion,
+		MaxOpVersion:  gdctx.OpVersion,
 	}
 
 	p.ClientAddresses, err = normalizeAddrs()
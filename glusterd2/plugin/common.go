@@ -2,6 +2,7 @@ package plugin
 
 import (
 	"github.com/gluster/glusterd2/glusterd2/servers/rest/route"
+	"github.com/gluster/glusterd2/pkg/sunrpc"
 )
 
 // GlusterdPlugin is an interface that every Glusterd plugin will
@@ -12,3 +13,15 @@ type GlusterdPlugin interface {
 	RestRoutes() route.Routes
 	RegisterStepFuncs()
 }
+
+// SunRPCPlugin is implemented by the plugins also serving SunRPC programs
+type SunRPCPlugin interface {
+	SunRPCPrograms() []sunrpc.Program
+}
+
+// ServicePlugin is implemented by the plugins running a service in the
+// background while they are enabled
+type ServicePlugin interface {
+	Start()
+	Stop()
+}
@@ -0,0 +1,222 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/servers/sunrpc"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/coreos/etcd/clientv3"
+	log "github.com/sirupsen/logrus"
+)
+
+// pluginsPrefix is where the plugins disabled or enabled by the user are
+// saved in the store. Plugins not in the store are enabled.
+const pluginsPrefix = "plugins/"
+
+type pluginState struct {
+	Enabled bool `json:"enabled"`
+}
+
+// loadedPlugin is a plugin along with what it registered while enabled
+type loadedPlugin struct {
+	plugin    GlusterdPlugin
+	enabled   bool
+	stepFuncs []string
+}
+
+var (
+	mu      sync.RWMutex
+	plugins = make(map[string]*loadedPlugin)
+)
+
+// Init registers the transaction step functions and SunRPC programs of the
+// plugins enabled in the cluster. It must be called once the store is up and
+// before the servers are started.
+func Init() error {
+	mu.Lock()
+	for _, p := range PluginsList {
+		plugins[p.Name()] = &loadedPlugin{plugin: p}
+	}
+	mu.Unlock()
+
+	return loadStates()
+}
+
+// Watch enables and disables the plugins whenever they are enabled or
+// disabled in the cluster, until the store is closed
+func Watch() {
+	wch := store.Store.Watch(store.Store.Ctx(), pluginsPrefix, clientv3.WithPrefix())
+	w := store.TrackWatch("plugins", pluginsPrefix)
+	defer w.Stop()
+	for resp := range wch {
+		w.Observe(resp)
+		if resp.Canceled {
+			return
+		}
+		if err := loadStates(); err != nil {
+			log.WithError(err).Error("failed to reload the state of the plugins")
+		}
+	}
+}
+
+// loadStates enables or disables the plugins on this peer as they are in
+// the cluster
+func loadStates() error {
+	resp, err := store.Get(context.TODO(), pluginsPrefix, clientv3.WithPrefix())
+	if err != nil {
+		return err
+	}
+
+	states := make(map[string]bool)
+	for _, kv := range resp.Kvs {
+		var st pluginState
+		if err := json.Unmarshal(kv.Value, &st); err != nil {
+			log.WithError(err).WithField("key", string(kv.Key)).Error("failed to unmarshal plugin state")
+			continue
+		}
+		states[strings.TrimPrefix(string(kv.Key), pluginsPrefix)] = st.Enabled
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for name, lp := range plugins {
+		enabled, ok := states[name]
+		if !ok {
+			enabled = true
+		}
+		switch {
+		case enabled && !lp.enabled:
+			lp.activate()
+		case !enabled && lp.enabled:
+			lp.deactivate()
+		}
+	}
+	return nil
+}
+
+// activate registers the step functions and SunRPC programs of the plugin,
+// and starts its service. Its REST routes, registered once, are served again.
+func (lp *loadedPlugin) activate() {
+	logger := log.WithField("plugin", lp.plugin.Name())
+
+	// The step functions of the plugin are the ones it adds to the
+	// registry
+	before := make(map[string]bool)
+	for _, name := range transaction.StepFuncNames() {
+		before[name] = true
+	}
+	lp.plugin.RegisterStepFuncs()
+	lp.stepFuncs = nil
+	for _, name := range transaction.StepFuncNames() {
+		if !before[name] {
+			lp.stepFuncs = append(lp.stepFuncs, name)
+		}
+	}
+
+	if p, ok := lp.plugin.(SunRPCPlugin); ok {
+		for _, prog := range p.SunRPCPrograms() {
+			if err := sunrpc.RegisterProgram(prog); err != nil {
+				logger.WithError(err).WithField("program", prog.Name()).Error("could not register SunRPC program")
+			}
+		}
+	}
+
+	if p, ok := lp.plugin.(ServicePlugin); ok {
+		p.Start()
+	}
+
+	lp.enabled = true
+	logger.Info("plugin enabled")
+}
+
+// deactivate unregisters the step functions and SunRPC programs of the
+// plugin, and stops its service. Its REST routes are no longer served.
+func (lp *loadedPlugin) deactivate() {
+	for _, name := range lp.stepFuncs {
+		transaction.UnregisterStepFunc(name)
+	}
+	lp.stepFuncs = nil
+
+	if p, ok := lp.plugin.(SunRPCPlugin); ok {
+		for _, prog := range p.SunRPCPrograms() {
+			sunrpc.UnregisterProgram(prog)
+		}
+	}
+
+	if p, ok := lp.plugin.(ServicePlugin); ok {
+		p.Stop()
+	}
+
+	lp.enabled = false
+	log.WithField("plugin", lp.plugin.Name()).Info("plugin disabled")
+}
+
+// IsEnabled tells if the plugin is enabled on this peer
+func IsEnabled(name string) bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	lp, ok := plugins[name]
+	return ok && lp.enabled
+}
+
+// SetEnabled enables or disables the plugin in the cluster. Every peer,
+// this one included, applies the change on seeing it in the store.
+func SetEnabled(name string, enabled bool) error {
+	mu.RLock()
+	_, ok := plugins[name]
+	mu.RUnlock()
+	if !ok {
+		return errors.ErrPluginNotFound
+	}
+
+	data, err := json.Marshal(pluginState{Enabled: enabled})
+	if err != nil {
+		return err
+	}
+	_, err = store.Put(context.TODO(), pluginsPrefix+name, string(data))
+	return err
+}
+
+// List returns the plugins known to this peer, along with their state
+func List() []api.PluginInfo {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	list := make([]api.PluginInfo, 0, len(plugins))
+	for name, lp := range plugins {
+		info := api.PluginInfo{
+			Name:      name,
+			Enabled:   lp.enabled,
+			StepFuncs: append([]string{}, lp.stepFuncs...),
+		}
+		for _, r := range lp.plugin.RestRoutes() {
+			info.Routes = append(info.Routes, r.Name)
+		}
+		sort.Strings(info.StepFuncs)
+		list = append(list, info)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// Handler returns a handler serving the REST route of the plugin while the
+// plugin is enabled, and responding as for an unknown route otherwise
+func Handler(name string, h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !IsEnabled(name) {
+			restutils.SendHTTPError(r.Context(), w, http.StatusNotFound, errors.ErrPluginDisabled)
+			return
+		}
+		h(w, r)
+	}
+}
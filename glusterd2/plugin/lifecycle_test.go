@@ -0,0 +1,80 @@
+package plugin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gluster/glusterd2/glusterd2/servers/rest/route"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testPlugin struct{}
+
+func (p *testPlugin) Name() string {
+	return "testplugin"
+}
+
+func (p *testPlugin) RestRoutes() route.Routes {
+	return route.Routes{{Name: "TestPluginGet"}}
+}
+
+func (p *testPlugin) RegisterStepFuncs() {
+	transaction.RegisterStepFunc(func(transaction.TxnCtx) error { return nil }, "testplugin.Step")
+}
+
+func TestActivate(t *testing.T) {
+	lp := &loadedPlugin{plugin: &testPlugin{}}
+	mu.Lock()
+	plugins[lp.plugin.Name()] = lp
+	mu.Unlock()
+	defer func() {
+		mu.Lock()
+		delete(plugins, lp.plugin.Name())
+		mu.Unlock()
+	}()
+
+	lp.activate()
+	assert.True(t, IsEnabled("testplugin"))
+	assert.Equal(t, []string{"testplugin.Step"}, lp.stepFuncs)
+	assert.Contains(t, transaction.StepFuncNames(), "testplugin.Step")
+	assert.Contains(t, List(), api.PluginInfo{
+		Name:      "testplugin",
+		Enabled:   true,
+		StepFuncs: []string{"testplugin.Step"},
+		Routes:    []string{"TestPluginGet"},
+	})
+
+	lp.deactivate()
+	assert.False(t, IsEnabled("testplugin"))
+	assert.Empty(t, lp.stepFuncs)
+	assert.NotContains(t, transaction.StepFuncNames(), "testplugin.Step")
+}
+
+func TestHandler(t *testing.T) {
+	lp := &loadedPlugin{plugin: &testPlugin{}}
+	mu.Lock()
+	plugins[lp.plugin.Name()] = lp
+	mu.Unlock()
+	defer func() {
+		mu.Lock()
+		delete(plugins, lp.plugin.Name())
+		mu.Unlock()
+	}()
+
+	h := Handler("testplugin", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	h(w, httptest.NewRequest(http.MethodGet, "/v1/testplugin", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	lp.enabled = true
+	w = httptest.NewRecorder()
+	h(w, httptest.NewRequest(http.MethodGet, "/v1/testplugin", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
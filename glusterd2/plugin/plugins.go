@@ -5,17 +5,23 @@ package plugin
 import (
 	"github.com/gluster/glusterd2/plugins/bitrot"
 	"github.com/gluster/glusterd2/plugins/blockvolume"
+	"github.com/gluster/glusterd2/plugins/csi"
 	"github.com/gluster/glusterd2/plugins/device"
 	"github.com/gluster/glusterd2/plugins/events"
 	"github.com/gluster/glusterd2/plugins/georeplication"
+	"github.com/gluster/glusterd2/plugins/gfproxy"
 	"github.com/gluster/glusterd2/plugins/glustershd"
+	"github.com/gluster/glusterd2/plugins/logmgmt"
 	"github.com/gluster/glusterd2/plugins/quota"
 	"github.com/gluster/glusterd2/plugins/rebalance"
+	"github.com/gluster/glusterd2/plugins/samba"
 	"github.com/gluster/glusterd2/plugins/tracemgmt"
 
 	// ensure init() of non-plugins also gets executed
 	_ "github.com/gluster/glusterd2/plugins/afr"
 	_ "github.com/gluster/glusterd2/plugins/dht"
+	_ "github.com/gluster/glusterd2/plugins/shard"
+	_ "github.com/gluster/glusterd2/plugins/worm"
 )
 
 // PluginsList is a list of plugins which implements GlusterdPlugin interface
@@ -29,4 +35,8 @@ var PluginsList = []GlusterdPlugin{
 	&rebalance.Plugin{},
 	&blockvolume.BlockVolume{},
 	&tracemgmt.Plugin{},
+	&logmgmt.Plugin{},
+	&gfproxy.Plugin{},
+	&samba.Plugin{},
+	&csi.Plugin{},
 }
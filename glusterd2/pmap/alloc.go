@@ -0,0 +1,71 @@
+package pmap
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	// portBase is the first port handed to the brick processes, the start
+	// of the IANA dynamic port range
+	portBase = 49152
+
+	// reserveTimeout is the time a port handed to a brick process is kept
+	// from other brick processes, for the brick to sign in on it
+	reserveTimeout = 2 * time.Minute
+)
+
+// errNoFreePort is returned when all the ports a brick process may listen on
+// are in use
+var errNoFreePort = errors.New("no free port left for the brick process")
+
+// AllocPort reserves the first free port from portBase up, for a brick
+// process to be started on. The ports of the bricks signed in, the ports
+// reserved for the other brick processes starting, the ports in use by other
+// processes, and the ports in skip are passed over. The port is released
+// when a brick signs in on it, by ReleasePort or once reserveTimeout elapses.
+func AllocPort(skip ...int) (int, error) {
+	return registry.allocPort(skip...)
+}
+
+// ReleasePort releases the port reserved for a brick process which did not
+// start
+func ReleasePort(port int) {
+	registry.releasePort(port)
+}
+
+func (r *pmapRegistry) allocPort(skip ...int) (int, error) {
+	r.Lock()
+	defer r.Unlock()
+
+	if r.reserved == nil {
+		r.reserved = make(map[int]time.Time)
+	}
+
+	skipped := make(map[int]bool, len(skip))
+	for _, port := range skip {
+		skipped[port] = true
+	}
+
+	now := time.Now()
+	for port := portBase; port <= portMax; port++ {
+		if skipped[port] || r.Ports[port] != nil {
+			continue
+		}
+		if at, ok := r.reserved[port]; ok && now.Sub(at) < reserveTimeout {
+			continue
+		}
+		if !isPortFree(port) {
+			continue
+		}
+		r.reserved[port] = now
+		return port, nil
+	}
+	return 0, errNoFreePort
+}
+
+func (r *pmapRegistry) releasePort(port int) {
+	r.Lock()
+	defer r.Unlock()
+	delete(r.reserved, port)
+}
@@ -0,0 +1,52 @@
+package pmap
+
+import (
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAllocPort(t *testing.T) {
+
+	assert := require.New(t)
+
+	r := &pmapRegistry{
+		Ports:  make(map[int]brickSet),
+		bricks: make(map[string]int),
+		conns:  make(map[net.Conn]int),
+	}
+
+	// a brick signed in, another process, a port to skip and a brick
+	// process starting hold the first ports
+	assert.NoError(r.Update(portBase, "/tmp/brick1", nil, 0))
+	l, err := net.Listen("tcp", fmt.Sprintf(":%d", portBase+1))
+	assert.NoError(err)
+	defer l.Close()
+	r.reserved = map[int]time.Time{portBase + 3: time.Now()}
+
+	port, err := r.allocPort(portBase + 2)
+	assert.NoError(err)
+	assert.Equal(portBase+4, port)
+
+	// the port is not handed out twice until released
+	port, err = r.allocPort(portBase + 2)
+	assert.NoError(err)
+	assert.Equal(portBase+5, port)
+
+	r.releasePort(portBase + 4)
+	port, err = r.allocPort(portBase + 2)
+	assert.NoError(err)
+	assert.Equal(portBase+4, port)
+
+	// reservations expire, and end when the brick signs in
+	r.reserved[portBase+3] = time.Now().Add(-reserveTimeout)
+	assert.NoError(r.Update(portBase+4, "/tmp/brick2", nil, 0))
+	_, ok := r.reserved[portBase+4]
+	assert.False(ok)
+	port, err = r.allocPort(portBase + 2)
+	assert.NoError(err)
+	assert.Equal(portBase+3, port)
+}
@@ -21,6 +21,23 @@ func RegistryExtend(brickpath string, port int, pid int) {
 	registry.Update(port, brickpath, nil, pid)
 }
 
+// RegistryRemove removes a brick entry from the pmap registry, for the
+// bricks which are stopped without signing out
+func RegistryRemove(brickpath string) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	port, ok := registry.bricks[brickpath]
+	if !ok {
+		return
+	}
+	delete(registry.bricks, brickpath)
+	delete(registry.Ports[port], brickpath)
+	if len(registry.Ports[port]) == 0 {
+		delete(registry.Ports, port)
+	}
+}
+
 // GetBricksOnPort returns a list of bricks that are multiplexed onto a single
 // process that is listening on the port specified.
 func GetBricksOnPort(port int) []string {
@@ -40,3 +57,25 @@ func GetBricksOnPort(port int) []string {
 func GetNumOfBricksOnPort(port int) (int, error) {
 	return registry.NumOfBricksOnPort(port)
 }
+
+// RegistryEntry is a brick in the pmap registry
+type RegistryEntry struct {
+	Port      int
+	BrickPath string
+	PID       int
+}
+
+// RegistryEntries returns the bricks in the pmap registry along with the
+// ports they are served on and the PIDs of the brick processes
+func RegistryEntries() []RegistryEntry {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	var entries []RegistryEntry
+	for port, bricks := range registry.Ports {
+		for path, pid := range bricks {
+			entries = append(entries, RegistryEntry{Port: port, BrickPath: path, PID: pid})
+		}
+	}
+	return entries
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net"
 	"sync"
+	"time"
 
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
 	"github.com/gluster/glusterd2/pkg/firewalld"
@@ -39,6 +40,10 @@ type pmapRegistry struct {
 	// used to process disconnections
 	Ports map[int]brickSet `json:"ports,omitempty"`
 
+	// map from port number to the time it was reserved for a brick
+	// process starting, which has not signed in yet
+	reserved map[int]time.Time
+
 	notifyFirewalld   bool
 	firewalldReloadCh chan *dbus.Signal
 }
@@ -63,6 +68,7 @@ func (r *pmapRegistry) Update(port int, brickpath string, conn net.Conn, pid int
 	defer r.Unlock()
 
 	r.bricks[brickpath] = port
+	delete(r.reserved, port)
 
 	// It's possible that multiple bricks are multiplexed onto a
 	// single conn, the conn passed to this function may not be the
@@ -215,6 +221,7 @@ func Init() {
 		Ports:             make(map[int]brickSet),
 		bricks:            make(map[string]int),
 		conns:             make(map[net.Conn]int),
+		reserved:          make(map[int]time.Time),
 		notifyFirewalld:   true,
 		firewalldReloadCh: make(chan *dbus.Signal, 10),
 	}
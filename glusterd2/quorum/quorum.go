@@ -0,0 +1,331 @@
+// Package quorum enforces server-quorum: the ratio of the peers of the
+// cluster that must be up for the peers to serve the bricks and accept
+// changes to the cluster.
+package quorum
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/brickmux"
+	"github.com/gluster/glusterd2/glusterd2/events"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/options"
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/glusterd2/transactionv2"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/errors"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// EventQuorumLost is broadcast when this peer sees less peers up than
+	// required by server-quorum
+	EventQuorumLost = "quorum_lost"
+	// EventQuorumRegained is broadcast when server-quorum is met again
+	EventQuorumRegained = "quorum_regained"
+
+	ratioKey      = "cluster.server-quorum-ratio"
+	stopBricksKey = "cluster.server-quorum-stop-bricks"
+
+	checkInterval = 3 * time.Second
+	checkTimeout  = 2 * time.Second
+)
+
+// exemptRoutes are the routes still served without quorum, needed to
+// restore it by adding or removing peers, or to disable it
+var exemptRoutes = map[string]bool{
+	"AddPeer":              true,
+	"DeletePeer":           true,
+	"EditPeer":             true,
+	"SetClusterOptions":    true,
+	"UpdateClusterOptions": true,
+}
+
+var (
+	stopChan chan struct{}
+	stopOnce sync.Once
+)
+
+// state is the server-quorum as last seen by this peer. The ratio, peers and
+// local bricks are those last read from the store, as the store is not
+// reachable from a peer cut off from most of the cluster.
+var state = struct {
+	sync.RWMutex
+	met        bool
+	ratio      int
+	stopBricks bool
+	up         int
+	total      int
+	// bricks are the local bricks of the started volumes
+	bricks []brick.Brickinfo
+	// stopped are the bricks stopped on losing quorum
+	stopped []brick.Brickinfo
+	since   time.Time
+}{met: true, stopBricks: true, since: time.Now()}
+
+// isMet tells if up peers out of total meet the ratio, in percent. A ratio
+// of 0 disables server-quorum.
+func isMet(up, total, ratio int) bool {
+	if ratio == 0 || total == 0 {
+		return true
+	}
+	return up*100 >= ratio*total
+}
+
+func validateRatio(option, value string) error {
+	ratio, err := strconv.Atoi(value)
+	if err != nil {
+		return errors.ErrInvalidIntValue
+	}
+	if ratio < 0 || ratio > 100 {
+		return errors.ErrInvalidQuorumRatio
+	}
+	return nil
+}
+
+func init() {
+	options.RegisterClusterOpValidationFunc(ratioKey, validateRatio)
+}
+
+// Start starts checking server-quorum periodically, stopping and
+// restarting the local bricks as it is lost and regained
+func Start() {
+	stopChan = make(chan struct{})
+	go transaction.UntilStop(check, checkInterval, stopChan)
+}
+
+// Stop stops checking server-quorum
+func Stop() {
+	if stopChan == nil {
+		return
+	}
+	stopOnce.Do(func() {
+		close(stopChan)
+	})
+}
+
+// refresh reads the peers up, the ratio, the peers and the local bricks from
+// the store, keeping the previous ones if the store cannot be reached
+func refresh() {
+	ctx, cancel := context.WithTimeout(context.Background(), checkTimeout)
+	defer cancel()
+	up := len(store.Store.GetAliveNodes(ctx))
+
+	state.Lock()
+	state.up = up
+	state.Unlock()
+
+	// This peer is alive unless the store cannot be reached, in which
+	// case the reads below would only time out
+	if up == 0 {
+		return
+	}
+
+	ratio := -1
+	if value, err := options.GetClusterOption(ratioKey); err == nil {
+		if r, err := strconv.Atoi(value); err == nil {
+			ratio = r
+		}
+	}
+	stopBricks := stopBricksEnabled()
+	ids, peersErr := peer.GetPeerIDs()
+
+	var bricks []brick.Brickinfo
+	volumes, volumesErr := volume.GetVolumes(context.TODO())
+	for _, v := range volumes {
+		if v.State == volume.VolStarted {
+			bricks = append(bricks, v.GetLocalBricks()...)
+		}
+	}
+
+	state.Lock()
+	defer state.Unlock()
+	if ratio >= 0 {
+		state.ratio = ratio
+	}
+	state.stopBricks = stopBricks
+	if peersErr == nil {
+		state.total = len(ids)
+	}
+	if volumesErr == nil {
+		state.bricks = bricks
+	}
+}
+
+// check checks server-quorum, acting on it being lost or regained. Checks
+// are run one at a time.
+func check() {
+	refresh()
+
+	state.Lock()
+	met := isMet(state.up, state.total, state.ratio)
+	if met == state.met {
+		state.Unlock()
+		return
+	}
+	state.met = met
+	state.since = time.Now()
+
+	data := map[string]string{
+		"peer.id":      gdctx.MyUUID.String(),
+		"quorum.up":    strconv.Itoa(state.up),
+		"quorum.total": strconv.Itoa(state.total),
+		"quorum.ratio": strconv.Itoa(state.ratio),
+	}
+	logger := log.WithFields(log.Fields{
+		"up":    state.up,
+		"total": state.total,
+		"ratio": state.ratio,
+	})
+	bricks := state.bricks
+	stopped := state.stopped
+	stopBricksOnLoss := state.stopBricks
+	state.Unlock()
+
+	// Every peer sees quorum lost or regained by itself, the events are
+	// hence not broadcast across the cluster
+	if !met {
+		logger.Error("server-quorum lost")
+		events.Broadcast(events.New(EventQuorumLost, data, false))
+		if stopBricksOnLoss {
+			stopped = stopBricks(bricks, logger)
+			state.Lock()
+			state.stopped = stopped
+			state.Unlock()
+		}
+		return
+	}
+
+	logger.Info("server-quorum regained")
+	events.Broadcast(events.New(EventQuorumRegained, data, false))
+	startBricks(stopped, logger)
+	state.Lock()
+	state.stopped = nil
+	state.Unlock()
+}
+
+func stopBricksEnabled() bool {
+	value, err := options.GetClusterOption(stopBricksKey)
+	if err != nil {
+		return true
+	}
+	stop, err := options.StringToBoolean(value)
+	if err != nil {
+		return true
+	}
+	return stop
+}
+
+// stopBricks stops the local bricks of the started volumes, returning those
+// stopped. Multiplexed bricks share processes, killed along with their first
+// brick.
+func stopBricks(bricks []brick.Brickinfo, logger log.FieldLogger) []brick.Brickinfo {
+	var stopped []brick.Brickinfo
+	for _, b := range bricks {
+		logger.WithField("brick", b.String()).Warn("stopping brick on losing server-quorum")
+		if err := b.StopBrick(logger); err != nil && err != errors.ErrPidFileNotFound {
+			logger.WithError(err).WithField("brick", b.String()).Error("failed to stop brick")
+			continue
+		}
+		stopped = append(stopped, b)
+	}
+	return stopped
+}
+
+// startBricks starts again the bricks stopped on losing quorum, which are
+// still of started volumes
+func startBricks(stopped []brick.Brickinfo, logger log.FieldLogger) {
+	if len(stopped) == 0 {
+		return
+	}
+
+	volumes, err := volume.GetVolumes(context.TODO())
+	if err != nil {
+		logger.WithError(err).Error("failed to get volumes, bricks stopped on losing server-quorum are not restarted")
+		return
+	}
+	started := make(map[string]*volume.Volinfo)
+	for _, v := range volumes {
+		if v.State == volume.VolStarted {
+			started[v.Name] = v
+		}
+	}
+
+	bmuxEnabled, err := brickmux.Enabled()
+	if err != nil {
+		logger.WithError(err).Error("failed to get brick multiplexing option")
+		return
+	}
+
+	for _, b := range stopped {
+		v, ok := started[b.VolumeName]
+		if !ok {
+			continue
+		}
+		logger.WithField("brick", b.String()).Info("restarting brick on regaining server-quorum")
+
+		if bmuxEnabled {
+			err := brickmux.Multiplex(b, v, volumes, logger)
+			if err == nil {
+				continue
+			}
+			if err != brickmux.ErrNoCompat {
+				logger.WithError(err).WithField("brick", b.String()).Error("failed to multiplex brick")
+				continue
+			}
+		}
+		if err := b.StartBrick(logger); err != nil && err != errors.ErrProcessAlreadyRunning {
+			logger.WithError(err).WithField("brick", b.String()).Error("failed to start brick")
+		}
+	}
+}
+
+// IsMet tells if server-quorum was met when last checked
+func IsMet() bool {
+	state.RLock()
+	defer state.RUnlock()
+	return state.met
+}
+
+// Status returns server-quorum as last seen by this peer
+func Status() api.QuorumStatus {
+	state.RLock()
+	defer state.RUnlock()
+
+	s := api.QuorumStatus{
+		Enabled: state.ratio != 0,
+		Ratio:   state.ratio,
+		Met:     state.met,
+		Up:      state.up,
+		Total:   state.total,
+		Since:   state.since,
+	}
+	for _, b := range state.stopped {
+		s.StoppedBricks = append(s.StoppedBricks, b.String())
+	}
+	return s
+}
+
+// Handler returns a handler refusing the requests of the route while
+// server-quorum is not met, unless the route helps restoring quorum
+func Handler(routeName string, h http.HandlerFunc) http.HandlerFunc {
+	if exemptRoutes[routeName] {
+		return h
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !IsMet() {
+			restutils.SendHTTPError(r.Context(), w, http.StatusServiceUnavailable, errors.ErrQuorumNotMet)
+			return
+		}
+		h(w, r)
+	}
+}
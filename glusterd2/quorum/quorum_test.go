@@ -0,0 +1,30 @@
+package quorum
+
+import (
+	"testing"
+
+	"github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsMet(t *testing.T) {
+	// Disabled
+	assert.True(t, isMet(0, 3, 0))
+	// No peers known yet
+	assert.True(t, isMet(0, 0, 51))
+
+	assert.True(t, isMet(2, 3, 51))
+	assert.False(t, isMet(1, 3, 51))
+	assert.True(t, isMet(2, 4, 50))
+	assert.False(t, isMet(3, 4, 100))
+	assert.True(t, isMet(4, 4, 100))
+}
+
+func TestValidateRatio(t *testing.T) {
+	assert.NoError(t, validateRatio(ratioKey, "0"))
+	assert.NoError(t, validateRatio(ratioKey, "51"))
+	assert.Equal(t, errors.ErrInvalidIntValue, validateRatio(ratioKey, "half"))
+	assert.Equal(t, errors.ErrInvalidQuorumRatio, validateRatio(ratioKey, "101"))
+	assert.Equal(t, errors.ErrInvalidQuorumRatio, validateRatio(ratioKey, "-1"))
+}
@@ -37,6 +37,11 @@ func New() *EventListener {
 	}
 }
 
+// String returns the name of the service in the supervisor tree
+func (l *EventListener) String() string {
+	return "eventlistener"
+}
+
 // Serve will start accepting UDP messages.
 func (l *EventListener) Serve() {
 
@@ -0,0 +1,67 @@
+// Package metrics implements a standalone HTTP server serving only the
+// Prometheus /metrics endpoint, for scrapers which cannot authenticate
+// with the REST service.
+package metrics
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/metrics"
+
+	log "github.com/sirupsen/logrus"
+	config "github.com/spf13/viper"
+)
+
+// Server serves /metrics on the configured metrics-address
+type Server struct {
+	server *http.Server
+}
+
+// New returns a metrics server
+func New() *Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler())
+
+	return &Server{
+		server: &http.Server{
+			Handler:      mux,
+			ReadTimeout:  10 * time.Second,
+			WriteTimeout: 30 * time.Second,
+		},
+	}
+}
+
+// String returns the name of the service in the supervisor tree
+func (s *Server) String() string {
+	return "metrics"
+}
+
+// Serve begins serving metrics on metrics-address. If the address cannot
+// be listened on, Serve returns and the supervisor starts it again.
+func (s *Server) Serve() {
+	addr := config.GetString("metrics-address")
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		log.WithError(err).WithField("address", addr).Error("failed to create metrics listener")
+		return
+	}
+
+	log.WithField("address", l.Addr().String()).Info("started metrics server")
+	if err := s.server.Serve(l); err != nil && err != http.ErrServerClosed {
+		log.WithError(err).Error("metrics server failed")
+	}
+}
+
+// Stop stops the metrics server
+func (s *Server) Stop() {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	if err := s.server.Shutdown(ctx); err != nil {
+		log.WithError(err).Error("failed to gracefully stop metrics server")
+	}
+	log.Info("stopped metrics server")
+}
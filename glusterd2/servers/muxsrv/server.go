@@ -4,11 +4,17 @@ package muxsrv
 import (
 	"net"
 
+	"github.com/gluster/glusterd2/pkg/systemd"
+
 	"github.com/cockroachdb/cmux"
 	log "github.com/sirupsen/logrus"
 	config "github.com/spf13/viper"
 )
 
+// systemdSocketName is the FileDescriptorName= of the socket passed by
+// systemd for the multiplexed server
+const systemdSocketName = "client"
+
 // MuxSrv implements the suture.Sever for the GD2 multiplexed server
 type muxSrv struct {
 	l net.Listener
@@ -19,7 +25,7 @@ type muxSrv struct {
 func newMuxSrv() *muxSrv {
 	mux := &muxSrv{}
 
-	l, err := net.Listen("tcp", config.GetString("clientaddress"))
+	l, err := listen()
 	if err != nil {
 		log.WithError(err).Fatal("failed to create gd2-muxsrv listener")
 	}
@@ -29,6 +35,27 @@ func newMuxSrv() *muxSrv {
 	return mux
 }
 
+// listen returns the listener of the multiplexed server. The socket named
+// after systemdSocketName is used when it is passed by systemd.
+func listen() (net.Listener, error) {
+	if config.GetBool("systemd") {
+		l, err := systemd.Listener(systemdSocketName)
+		if err == nil {
+			log.WithField("address", l.Addr().String()).Info("using the socket passed by systemd")
+			return l, nil
+		}
+		if err != systemd.ErrNoListener {
+			return nil, err
+		}
+	}
+	return net.Listen("tcp", config.GetString("clientaddress"))
+}
+
+// String returns the name of the service in the supervisor tree
+func (m *muxSrv) String() string {
+	return "muxlistener"
+}
+
 // Serve starts the handlers and the multiplexed listener
 func (m *muxSrv) Serve() {
 	if err := m.m.Serve(); err != nil && err != cmux.ErrListenerClosed {
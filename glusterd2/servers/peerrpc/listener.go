@@ -26,6 +26,11 @@ func New() *Server {
 	return s
 }
 
+// String returns the name of the service in the supervisor tree
+func (s *Server) String() string {
+	return "peerrpc"
+}
+
 // Serve starts a gRPC server
 // TODO: This should be able to listen on multiple listeners
 func (s *Server) Serve() {
@@ -0,0 +1,56 @@
+package rest
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+
+	"github.com/gluster/glusterd2/glusterd2/plugin"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/openapi"
+	"github.com/gluster/glusterd2/pkg/utils"
+	"github.com/gluster/glusterd2/version"
+)
+
+// openAPIHandler serves the OpenAPI document of the routes, built from the
+// types of their requests and responses, so that it always matches what the
+// server accepts. The clients in other languages are generated from it.
+func (r *GDRest) openAPIHandler() http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		var routes []openapi.Route
+		for _, r := range AllRoutes {
+			if name, ok := pluginRoutes[r.Name]; ok && !plugin.IsEnabled(name) {
+				continue
+			}
+
+			path := r.Pattern
+			if r.Version != 0 {
+				path = fmt.Sprintf("/v%d%s", r.Version, r.Pattern)
+			}
+			routes = append(routes, openapi.Route{
+				Name:         r.Name,
+				Description:  r.Description,
+				Method:       r.Method,
+				Path:         path,
+				RequestType:  routeType(r.RequestType),
+				ResponseType: routeType(r.ResponseType),
+			})
+		}
+
+		info := openapi.Info{Title: "GlusterD2 REST API", Version: version.GlusterdVersion}
+		doc := openapi.New(info, routes, reflect.TypeOf(api.ErrorResp{}))
+		restutils.SendHTTPResponse(ctx, w, http.StatusOK, doc)
+	})
+}
+
+// routeType returns the type of the request or response of a route
+func routeType(s string) reflect.Type {
+	if s == "" {
+		return nil
+	}
+	t, _ := utils.GetType(s)
+	return t
+}
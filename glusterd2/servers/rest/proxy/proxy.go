@@ -0,0 +1,301 @@
+// Package proxy serves the REST requests for the local state of a peer, such
+// as its statedump, on any peer. The requests are run on the peer they are
+// for over the peer RPC, so that the clients may send them to any peer, like
+// through a load balancer.
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/pborman/uuid"
+)
+
+const (
+	serveStepFunc   = "rest.ServeLocal"
+	requestTxnKey   = "request"
+	responseTxnKey  = "response"
+	maxRequestBytes = 1 << 20 // 1MB, the request is saved in the store
+)
+
+var (
+	// router serves the requests run on this peer
+	router http.Handler
+
+	errRequestTooLarge = errors.New("request body is too large to be sent to another peer")
+	errUnreachable     = errors.New("peer is not reachable")
+)
+
+// request is a request sent to another peer
+type request struct {
+	Method    string      `json:"method"`
+	URI       string      `json:"uri"`
+	Header    http.Header `json:"header"`
+	Body      []byte      `json:"body"`
+	ReqID     string      `json:"reqid"`
+	User      string      `json:"user"`
+	Namespace string      `json:"namespace"`
+}
+
+// response is the response of a peer to a request sent to it
+type response struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header"`
+	Body   []byte      `json:"body"`
+}
+
+// recorder is the http.ResponseWriter of the requests run on this peer on
+// behalf of another peer
+type recorder struct {
+	status int
+	header http.Header
+	body   bytes.Buffer
+}
+
+func (rec *recorder) Header() http.Header {
+	return rec.header
+}
+
+func (rec *recorder) WriteHeader(status int) {
+	if rec.status == 0 {
+		rec.status = status
+	}
+}
+
+func (rec *recorder) Write(b []byte) (int, error) {
+	rec.WriteHeader(http.StatusOK)
+	return rec.body.Write(b)
+}
+
+// Register sets the router serving the requests sent to this peer by the
+// other peers, and registers the step function running them
+func Register(h http.Handler) {
+	router = h
+	transaction.RegisterStepFunc(txnServeLocal, serveStepFunc)
+}
+
+// txnServeLocal serves a request sent by another peer, and sets its response
+// as the result of the step
+func txnServeLocal(c transaction.TxnCtx) error {
+	var req request
+	if err := c.Get(requestTxnKey, &req); err != nil {
+		return err
+	}
+
+	r, err := http.NewRequest(req.Method, req.URI, bytes.NewReader(req.Body))
+	if err != nil {
+		return err
+	}
+	if req.Header != nil {
+		r.Header = req.Header
+	}
+	// The request is served here, it must not be sent on again
+	r.Header.Set(api.TargetPeerHeader, gdctx.MyUUID.String())
+	// The retries of the request are told apart by the peer it was sent to
+	r.Header.Del(api.IdempotencyKeyHeader)
+
+	// The request was authenticated by the peer it was sent to
+	ctx := gdctx.WithReqLogger(context.Background(), c.Logger())
+	if reqID := uuid.Parse(req.ReqID); reqID != nil {
+		ctx = gdctx.WithReqID(ctx, reqID)
+	}
+	ctx = gdctx.WithReqUser(ctx, req.User)
+	if req.Namespace != "" {
+		ctx = gdctx.WithReqNamespace(ctx, req.Namespace)
+	}
+
+	rec := &recorder{header: make(http.Header)}
+	router.ServeHTTP(rec, r.WithContext(ctx))
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+
+	return c.SetNodeResult(gdctx.MyUUID, responseTxnKey, response{
+		Status: rec.status,
+		Header: rec.header,
+		Body:   rec.body.Bytes(),
+	})
+}
+
+// forward runs the request on the peers, and returns their responses. The
+// peers which could not be reached have no response.
+func forward(r *http.Request, nodes []uuid.UUID) (map[string]*response, error) {
+	ctx := r.Context()
+
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxRequestBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > maxRequestBytes {
+		return nil, errRequestTooLarge
+	}
+
+	txn := transaction.NewTxn(ctx)
+	defer txn.Done()
+
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc: serveStepFunc,
+			Nodes:  nodes,
+			Stream: true,
+		},
+	}
+	req := request{
+		Method:    r.Method,
+		URI:       r.URL.RequestURI(),
+		Header:    r.Header,
+		Body:      body,
+		ReqID:     gdctx.GetReqID(ctx).String(),
+		User:      gdctx.GetReqUser(ctx),
+		Namespace: gdctx.GetReqNamespace(ctx),
+	}
+	if err := txn.Ctx.Set(requestTxnKey, req); err != nil {
+		return nil, err
+	}
+
+	// A request sent to all the peers is answered by the peers which are
+	// up
+	txn.DontCheckAlive = len(nodes) > 1
+	txn.DisableRollback = true
+
+	if err := txn.Do(); err != nil {
+		return nil, err
+	}
+
+	resps := make(map[string]*response, len(nodes))
+	for _, node := range nodes {
+		var resp response
+		if err := txn.Ctx.GetNodeResult(node, responseTxnKey, &resp); err != nil {
+			continue
+		}
+		resps[node.String()] = &resp
+	}
+	return resps, nil
+}
+
+// Serve runs the request on the peer, and sends its response back to the
+// client
+func Serve(w http.ResponseWriter, r *http.Request, peerID uuid.UUID) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+
+	resps, err := forward(r, []uuid.UUID{peerID})
+	if err == errRequestTooLarge {
+		restutils.SendHTTPError(ctx, w, http.StatusRequestEntityTooLarge, err)
+		return
+	}
+	if err != nil {
+		logger.WithError(err).WithField("peer", peerID.String()).Error("failed to send the request to the peer")
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	resp, ok := resps[peerID.String()]
+	if !ok {
+		restutils.SendHTTPError(ctx, w, http.StatusBadGateway, errUnreachable)
+		return
+	}
+
+	for k, v := range resp.Header {
+		w.Header()[k] = v
+	}
+	w.WriteHeader(resp.Status)
+	w.Write(resp.Body)
+}
+
+// ServeAll runs the GET request on all the peers, and sends their responses
+// back to the client as api.PeerResponses
+func ServeAll(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+
+	if r.Method != http.MethodGet {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "only GET requests may be sent to all the peers")
+		return
+	}
+
+	nodes, err := peer.GetPeerIDs()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	resps, err := forward(r, nodes)
+	if err != nil {
+		logger.WithError(err).Error("failed to send the request to the peers")
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	out := make(api.PeerResponses, len(nodes))
+	for _, node := range nodes {
+		resp, ok := resps[node.String()]
+		if !ok {
+			out[node.String()] = api.PeerResponse{Error: errUnreachable.Error()}
+			continue
+		}
+		body := json.RawMessage(resp.Body)
+		if !json.Valid(body) {
+			body, _ = json.Marshal(string(resp.Body))
+		}
+		out[node.String()] = api.PeerResponse{Status: resp.Status, Body: body}
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, out)
+}
+
+// findPeer returns the ID of the peer named by its ID or name
+func findPeer(name string) (uuid.UUID, error) {
+	if id := uuid.Parse(name); id != nil {
+		p, err := peer.GetPeerF(id.String())
+		if err != nil {
+			return nil, err
+		}
+		return p.ID, nil
+	}
+	p, err := peer.GetPeerByName(name)
+	if err != nil {
+		return nil, err
+	}
+	return p.ID, nil
+}
+
+// Local serves the requests for the local state of the peer on the peer named
+// by their api.TargetPeerHeader, or on all the peers. The requests without
+// this header are served by this peer.
+func Local(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		target := r.Header.Get(api.TargetPeerHeader)
+		if target == "" || target == gdctx.MyUUID.String() {
+			next(w, r)
+			return
+		}
+		if target == api.AllPeers {
+			ServeAll(w, r)
+			return
+		}
+
+		peerID, err := findPeer(target)
+		if err != nil {
+			status, err := restutils.ErrToStatusCode(err)
+			restutils.SendHTTPError(r.Context(), w, status, err)
+			return
+		}
+		if uuid.Equal(peerID, gdctx.MyUUID) {
+			next(w, r)
+			return
+		}
+		Serve(w, r, peerID)
+	}
+}
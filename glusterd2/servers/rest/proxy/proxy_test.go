@@ -0,0 +1,57 @@
+package proxy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecorder(t *testing.T) {
+	rec := &recorder{header: make(http.Header)}
+	rec.Header().Set("Content-Type", "application/gzip")
+	rec.Write([]byte("data"))
+	rec.WriteHeader(http.StatusNotFound)
+	assert.Equal(t, http.StatusOK, rec.status)
+	assert.Equal(t, "application/gzip", rec.header.Get("Content-Type"))
+	assert.Equal(t, "data", rec.body.String())
+
+	rec = &recorder{header: make(http.Header)}
+	rec.WriteHeader(http.StatusNoContent)
+	assert.Equal(t, http.StatusNoContent, rec.status)
+	assert.Zero(t, rec.body.Len())
+}
+
+func TestLocal(t *testing.T) {
+	gdctx.MyUUID = uuid.NewRandom()
+
+	served := 0
+	h := Local(func(w http.ResponseWriter, r *http.Request) {
+		served++
+		w.WriteHeader(http.StatusOK)
+	})
+
+	for _, target := range []string{"", gdctx.MyUUID.String()} {
+		r := httptest.NewRequest(http.MethodGet, "/v1/daemons", nil)
+		if target != "" {
+			r.Header.Set(api.TargetPeerHeader, target)
+		}
+		w := httptest.NewRecorder()
+		h(w, r)
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+	assert.Equal(t, 2, served)
+
+	// Only GET requests are sent to all the peers
+	r := httptest.NewRequest(http.MethodPost, "/v1/logging/rotate", nil)
+	r.Header.Set(api.TargetPeerHeader, api.AllPeers)
+	w := httptest.NewRecorder()
+	h(w, r)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, 2, served)
+}
@@ -10,8 +10,8 @@ import (
 	"time"
 
 	"github.com/gluster/glusterd2/glusterd2/middleware"
+	"github.com/gluster/glusterd2/glusterd2/plugin"
 	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
-	gdutils "github.com/gluster/glusterd2/glusterd2/utils"
 	"github.com/gluster/glusterd2/pkg/api"
 	"github.com/gluster/glusterd2/pkg/tlsmatcher"
 
@@ -19,15 +19,39 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/justinas/alice"
 	log "github.com/sirupsen/logrus"
+	flag "github.com/spf13/pflag"
 	config "github.com/spf13/viper"
 )
 
 const (
-	httpReadTimeout  = 10
-	httpWriteTimeout = 30
-	maxHeaderBytes   = 1 << 13 // 8KB
+	readTimeoutOpt       = "rest-read-timeout"
+	readHeaderTimeoutOpt = "rest-read-header-timeout"
+	writeTimeoutOpt      = "rest-write-timeout"
+	idleTimeoutOpt       = "rest-idle-timeout"
+	maxHeaderBytesOpt    = "rest-max-header-bytes"
+	maxBodyBytesOpt      = "rest-max-body-bytes"
+
+	defaultReadTimeout       = 10 * time.Second
+	defaultReadHeaderTimeout = 5 * time.Second
+	defaultWriteTimeout      = 30 * time.Second
+	defaultIdleTimeout       = 120 * time.Second
+	defaultMaxHeaderBytes    = 1 << 13 // 8KB
+	defaultMaxBodyBytes      = 1 << 20 // 1MB
 )
 
+// InitFlags intializes the command line options for the REST server
+func InitFlags() {
+	flag.Duration(readTimeoutOpt, defaultReadTimeout, "Time to receive a whole request, body included, past which the request is answered with 408 Request Timeout.")
+	flag.Duration(readHeaderTimeoutOpt, defaultReadHeaderTimeout, "Time to receive the headers of a request, past which the connection is closed.")
+	flag.Duration(writeTimeoutOpt, defaultWriteTimeout, "Time to handle a request and send its response, from the end of its headers.")
+	flag.Duration(idleTimeoutOpt, defaultIdleTimeout, "Time a keep-alive connection waits for the next request, past which it is closed.")
+	flag.Int(maxHeaderBytesOpt, defaultMaxHeaderBytes, "Maximum size of the headers of a request, past which the request is answered with 431 Request Header Fields Too Large.")
+	flag.Int64(maxBodyBytesOpt, defaultMaxBodyBytes, "Maximum size of the body of a request, past which the request is answered with 413 Request Entity Too Large. Set to 0 for no limit.")
+}
+
+// current is the REST server of glusterd2, shut down by Shutdown
+var current *GDRest
+
 // GDRest is the GlusterD Rest server
 type GDRest struct {
 	Routes   *mux.Router
@@ -58,9 +82,11 @@ func NewMuxed(m cmux.CMux) *GDRest {
 	rest := &GDRest{
 		Routes: mux.NewRouter(),
 		server: &http.Server{
-			ReadTimeout:    httpReadTimeout * time.Second,
-			WriteTimeout:   httpWriteTimeout * time.Second,
-			MaxHeaderBytes: maxHeaderBytes,
+			ReadTimeout:       config.GetDuration(readTimeoutOpt),
+			ReadHeaderTimeout: config.GetDuration(readHeaderTimeoutOpt),
+			WriteTimeout:      config.GetDuration(writeTimeoutOpt),
+			IdleTimeout:       config.GetDuration(idleTimeoutOpt),
+			MaxHeaderBytes:    config.GetInt(maxHeaderBytesOpt),
 		},
 		stopCh: make(chan struct{}),
 	}
@@ -85,12 +111,6 @@ func NewMuxed(m cmux.CMux) *GDRest {
 
 	rest.registerRoutes()
 
-	//Enable go profiling
-	profiling := config.GetBool("profiling")
-	if profiling {
-		gdutils.EnableProfiling(rest.Routes)
-	}
-
 	// Set chain of ordered middlewares
 	rest.server.Handler = alice.New(
 		middleware.Recover,
@@ -99,11 +119,34 @@ func NewMuxed(m cmux.CMux) *GDRest {
 		middleware.ReqIDGenerator,
 		middleware.LogRequest,
 		middleware.Auth,
+		middleware.ReadBody(config.GetInt64(maxBodyBytesOpt)),
 	).Then(rest.Routes)
 
+	current = rest
 	return rest
 }
 
+// Shutdown stops the REST server from accepting requests and waits for the
+// requests being handled to be answered. The connections still open once ctx
+// is done are closed.
+func Shutdown(ctx context.Context) error {
+	if current == nil {
+		return nil
+	}
+
+	log.Debug("shutting down glusterd ReST server")
+	err := current.server.Shutdown(ctx)
+	if err == context.DeadlineExceeded || err == context.Canceled {
+		current.server.Close()
+	}
+	return err
+}
+
+// String returns the name of the service in the supervisor tree
+func (r *GDRest) String() string {
+	return "rest"
+}
+
 // Serve begins serving client HTTP requests served by REST server
 func (r *GDRest) Serve() {
 	log.WithField("ip:port", r.listener.Addr().String()).Info("Started GlusterD ReST server")
@@ -120,7 +163,8 @@ func (r *GDRest) Serve() {
 
 // Stop intends to stop the GlusterD Rest server gracefully. But this won't
 // work because the Stop() call chain is managed by supervisor and the cmux
-// listener gets closed first.
+// listener gets closed first. The server is shut down gracefully by Shutdown
+// before the supervisor is stopped.
 func (r *GDRest) Stop() {
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
@@ -142,12 +186,16 @@ func (r *GDRest) listEndpointsHandler() http.HandlerFunc {
 		var resp api.ListEndpointsResp
 		ctx := req.Context()
 		for _, r := range AllRoutes {
+			if name, ok := pluginRoutes[r.Name]; ok && !plugin.IsEnabled(name) {
+				continue
+			}
 			resp = append(resp, api.Endpoint{
 				Name:         r.Name,
 				Method:       r.Method,
 				Path:         r.Pattern,
 				RequestType:  r.RequestType,
 				ResponseType: r.ResponseType,
+				PeerLocal:    r.PeerLocal,
 			})
 		}
 		restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
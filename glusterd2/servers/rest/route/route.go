@@ -17,6 +17,10 @@ type Route struct {
 	RequestType  string
 	ResponseType string // Success
 	HandlerFunc  http.HandlerFunc
+	// PeerLocal is set on the routes serving the local state of the peer,
+	// which any peer serves for another peer through the
+	// X-Gluster-Target-Peer header of the request
+	PeerLocal bool
 }
 
 // Routes is a table of many Route's
@@ -6,7 +6,11 @@ import (
 	"net/http"
 
 	"github.com/gluster/glusterd2/glusterd2/commands"
+	"github.com/gluster/glusterd2/glusterd2/metrics"
+	"github.com/gluster/glusterd2/glusterd2/middleware"
 	"github.com/gluster/glusterd2/glusterd2/plugin"
+	"github.com/gluster/glusterd2/glusterd2/quorum"
+	"github.com/gluster/glusterd2/glusterd2/servers/rest/proxy"
 	"github.com/gluster/glusterd2/glusterd2/servers/rest/route"
 	"github.com/gluster/glusterd2/pkg/api"
 	"github.com/gluster/glusterd2/pkg/utils"
@@ -18,6 +22,9 @@ import (
 // AllRoutes is global list of all API endpoints
 var AllRoutes route.Routes
 
+// pluginRoutes maps the names of the routes added by plugins to the plugins
+var pluginRoutes = make(map[string]string)
+
 // setRoutes adds the given routes to the GlusterD Rest server
 func (r *GDRest) setRoutes(routes route.Routes) {
 	var urlPattern string
@@ -28,6 +35,25 @@ func (r *GDRest) setRoutes(routes route.Routes) {
 		} else {
 			urlPattern = fmt.Sprintf("/v%d%s", route.Version, route.Pattern)
 		}
+		// Changes to the cluster are refused without server-quorum
+		handler := route.HandlerFunc
+		if route.Method != http.MethodGet {
+			handler = quorum.Handler(route.Name, handler)
+		}
+		// The changes to a resource can be conditional on its revision
+		handler = middleware.Revision(route.Pattern, handler)
+		// Tenants are confined to the volumes of their namespace
+		handler = middleware.Namespaced(route.Pattern, handler)
+		// The requests for the state of another peer are served by it
+		if route.PeerLocal {
+			handler = proxy.Local(handler)
+		}
+		// The retries of the changes made with an Idempotency-Key are
+		// answered with the response to the change
+		if route.Method != http.MethodGet {
+			handler = middleware.Idempotent(handler)
+		}
+
 		log.WithFields(log.Fields{
 			"name":   route.Name,
 			"path":   urlPattern,
@@ -37,7 +63,7 @@ func (r *GDRest) setRoutes(routes route.Routes) {
 			Methods(route.Method).
 			Path(urlPattern).
 			Name(route.Name).
-			Handler(route.HandlerFunc)
+			Handler(middleware.TraceRoute(route.Name, handler))
 
 		// Set our global copy of all routes
 		AllRoutes = append(AllRoutes, route)
@@ -45,6 +71,8 @@ func (r *GDRest) setRoutes(routes route.Routes) {
 }
 
 func (r *GDRest) registerRoutes() {
+	proxy.Register(r.Routes)
+
 	for _, c := range commands.Commands {
 		r.setRoutes(c.Routes())
 		//XXX: This doesn't feel like the right place to be register step
@@ -52,17 +80,21 @@ func (r *GDRest) registerRoutes() {
 		c.RegisterStepFuncs()
 	}
 
-	// Load routes and Step functions from Plugins
+	// Load routes from Plugins. Their step functions are registered by
+	// the plugin package, as they can be enabled and disabled at runtime.
 	for _, p := range plugin.PluginsList {
 		restRoutes := p.RestRoutes()
 		if restRoutes != nil {
+			for i := range restRoutes {
+				restRoutes[i].HandlerFunc = plugin.Handler(p.Name(), restRoutes[i].HandlerFunc)
+				pluginRoutes[restRoutes[i].Name] = p.Name()
+			}
 			r.setRoutes(restRoutes)
 			log.WithField("plugin", p.Name()).Debug("loaded REST routes from plugin")
 		}
-		p.RegisterStepFuncs()
 	}
 
-	// Expose /statedump and /endpoints handlers
+	// Expose /statedump, /endpoints and /openapi.json handlers
 	var moreRoutes route.Routes
 
 	if ok := config.GetBool("statedump"); ok {
@@ -70,9 +102,19 @@ func (r *GDRest) registerRoutes() {
 			Name:        "Statedump",
 			Method:      "GET",
 			Pattern:     "/statedump",
+			PeerLocal:   true,
 			HandlerFunc: expvar.Handler().(http.HandlerFunc)})
 	}
 
+	if config.GetBool("metrics") && config.GetString("metrics-address") == "" {
+		moreRoutes = append(moreRoutes, route.Route{
+			Name:        "Metrics",
+			Method:      "GET",
+			Pattern:     "/metrics",
+			PeerLocal:   true,
+			HandlerFunc: metrics.Handler().ServeHTTP})
+	}
+
 	moreRoutes = append(moreRoutes, route.Route{
 		Name:         "List Endpoints",
 		Method:       "GET",
@@ -80,6 +122,12 @@ func (r *GDRest) registerRoutes() {
 		ResponseType: utils.GetTypeString((*api.ListEndpointsResp)(nil)),
 		HandlerFunc:  r.listEndpointsHandler()})
 
+	moreRoutes = append(moreRoutes, route.Route{
+		Name:        "OpenAPI Document",
+		Method:      "GET",
+		Pattern:     "/openapi.json",
+		HandlerFunc: r.openAPIHandler()})
+
 	moreRoutes = append(moreRoutes, route.Route{
 		Name:        "Glusterd2 service status",
 		Method:      "GET",
@@ -6,13 +6,133 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
 	"github.com/gluster/glusterd2/glusterd2/transaction"
 	"github.com/gluster/glusterd2/pkg/api"
 	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
+// errReasons gives the reasons of the errors that have one more specific
+// than the one of their HTTP status
+var errReasons = map[error]api.ErrorReason{
+	gderrors.ErrVolNotFound:             api.ReasonVolumeNotFound,
+	gderrors.ErrVolExists:               api.ReasonVolumeExists,
+	gderrors.ErrTrashedVolNotFound:      api.ReasonVolumeNotFound,
+	gderrors.ErrVolNotStarted:           api.ReasonVolumeNotStarted,
+	gderrors.ErrVolAlreadyStarted:       api.ReasonVolumeAlreadyStarted,
+	gderrors.ErrVolAlreadyStopped:       api.ReasonVolumeAlreadyStopped,
+	gderrors.ErrVolClientsConnected:     api.ReasonVolumeInUse,
+	gderrors.ErrEmptyVolName:            api.ReasonInvalidVolumeName,
+	gderrors.ErrInvalidVolName:          api.ReasonInvalidVolumeName,
+	gderrors.ErrVolumeWORM:              api.ReasonVolumeWORM,
+	gderrors.ErrPeerNotFound:            api.ReasonPeerNotFound,
+	gderrors.ErrSnapNotFound:            api.ReasonSnapshotNotFound,
+	gderrors.ErrSnapExists:              api.ReasonSnapshotExists,
+	gderrors.ErrBrickPathAlreadyInUse:   api.ReasonBrickPathInUse,
+	gderrors.ErrDuplicateBrickPath:      api.ReasonBrickPathInUse,
+	gderrors.ErrBrickIsMountPoint:       api.ReasonInvalidBrickPath,
+	gderrors.ErrBrickUnderRootPartition: api.ReasonBrickOnRootFS,
+	gderrors.ErrBrickPathWasUsed:        api.ReasonBrickPathWasUsed,
+	gderrors.ErrBrickPathNested:         api.ReasonBrickPathNested,
+	gderrors.ErrBrickVolumeIDMismatch:   api.ReasonBrickVolumeIDMismatch,
+	gderrors.ErrBrickVolumeIDMissing:    api.ReasonBrickVolumeIDMismatch,
+	gderrors.ErrBrickNotDirectory:       api.ReasonInvalidBrickPath,
+	gderrors.ErrInvalidBrickPath:        api.ReasonInvalidBrickPath,
+	gderrors.ErrBrickPathTooLong:        api.ReasonInvalidBrickPath,
+	gderrors.ErrDeviceNotFound:          api.ReasonDeviceNotFound,
+	gderrors.ErrDeviceNameNotFound:      api.ReasonDeviceNotFound,
+	gderrors.ErrInsufficientDeviceSpace: api.ReasonInsufficientSpace,
+	gderrors.ErrQuorumNotMet:            api.ReasonQuorumLost,
+	gderrors.ErrUpgradeLosesQuorum:      api.ReasonQuorumLost,
+	gderrors.ErrOpVersionTooLow:         api.ReasonOpVersionTooLow,
+	gderrors.ErrPeerOpVersionTooLow:     api.ReasonOpVersionTooLow,
+	gderrors.ErrPluginNotFound:          api.ReasonPluginNotFound,
+	gderrors.ErrPluginDisabled:          api.ReasonPluginDisabled,
+	gderrors.ErrUpgradeInProgress:       api.ReasonUpgradeInProgress,
+	gderrors.ErrEvacuationInProgress:    api.ReasonEvacuationInProgress,
+	gderrors.ErrProcessNotFound:         api.ReasonProcessNotRunning,
+	gderrors.ErrProcessAlreadyRunning:   api.ReasonProcessAlreadyRunning,
+	gderrors.ErrOptionGroupNotFound:     api.ReasonOptionGroupNotFound,
+	gderrors.ErrNamespaceNotFound:       api.ReasonNamespaceNotFound,
+	gderrors.ErrNamespaceExists:         api.ReasonNamespaceExists,
+	gderrors.ErrNamespaceNotEmpty:       api.ReasonNamespaceNotEmpty,
+	gderrors.ErrNamespaceQuotaExceeded:  api.ReasonQuotaExceeded,
+	gderrors.ErrJSONParsingFailed:       api.ReasonInvalidRequest,
+	gderrors.ErrRevisionMismatch:        api.ReasonRevisionMismatch,
+	gderrors.ErrIdempotencyKeyInUse:     api.ReasonIdempotencyKeyInUse,
+	gderrors.ErrIdempotencyKeyReused:    api.ReasonIdempotencyKeyReused,
+	gderrors.ErrRevisionCompacted:       api.ReasonRevisionCompacted,
+	transaction.ErrLockTimeout:          api.ReasonLockTimeout,
+	transaction.ErrLockNotFound:         api.ReasonLockNotFound,
+}
+
+// reasonsByMsg gives the reasons of the errors by their message, for the
+// errors returned by the steps run on other peers, which only come back as
+// strings
+var reasonsByMsg = make(map[string]api.ErrorReason)
+
+func init() {
+	for err, reason := range errReasons {
+		reasonsByMsg[err.Error()] = reason
+	}
+}
+
+// msgToReason returns the reason of an error given its message. The errors
+// may be wrapped with the context they occurred in, as "<context>: <error>".
+func msgToReason(msg string) (api.ErrorReason, bool) {
+	for {
+		if reason, ok := reasonsByMsg[msg]; ok {
+			return reason, true
+		}
+		i := strings.Index(msg, ": ")
+		if i < 0 {
+			return "", false
+		}
+		msg = msg[i+2:]
+	}
+}
+
+// ErrToReason returns the reason of err given in the error responses. The
+// errors without a specific reason get the one of statusCode.
+func ErrToReason(err error, statusCode int) api.ErrorReason {
+	if err != nil {
+		if reason, ok := errReasons[err]; ok {
+			return reason
+		}
+		if reason, ok := msgToReason(err.Error()); ok {
+			return reason
+		}
+		if status.Code(err) == codes.Unavailable {
+			return api.ReasonPeerUnreachable
+		}
+	}
+	return statusToReason(statusCode)
+}
+
+func statusToReason(statusCode int) api.ErrorReason {
+	switch statusCode {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return api.ReasonInvalidRequest
+	case http.StatusUnauthorized:
+		return api.ReasonUnauthorized
+	case http.StatusForbidden:
+		return api.ReasonForbidden
+	case http.StatusNotFound:
+		return api.ReasonNotFound
+	case http.StatusConflict:
+		return api.ReasonConflict
+	case http.StatusServiceUnavailable:
+		return api.ReasonUnavailable
+	default:
+		return api.ReasonInternal
+	}
+}
+
 // UnmarshalRequest unmarshals JSON in `r` into `v`
 func UnmarshalRequest(r *http.Request, v interface{}) error {
 	defer r.Body.Close()
@@ -51,7 +171,8 @@ func SendHTTPResponse(ctx context.Context, w http.ResponseWriter, statusCode int
 
 // SendHTTPError sends an error response to the client. The caller of this
 // function can pass either the error or one or more error code(s) exported by
-// api package. Example usage:
+// api package. Every error of the response is given the reason of the error
+// passed, see ErrToReason. Example usage:
 // SendHTTPError(ctx, http.StatusBadRequest, err) // Pass error as is
 // SendHTTPError(ctx, http.StatusBadRequest, "", api.ErrorCode) // Specify error code
 // SendHTTPError(ctx, http.StatusBadRequest, "custom error") // Pass specific error string
@@ -66,17 +187,31 @@ func SendHTTPError(ctx context.Context, w http.ResponseWriter, statusCode int,
 		// interface, we don't have aything else to do
 		resp = v.Response()
 		statusCode = v.Status()
+		for i, e := range resp.Errors {
+			if e.Reason == "" {
+				resp.Errors[i].Reason = stepErrToReason(e)
+			}
+		}
 	} else {
+		var reason api.ErrorReason
+		if e, ok := err.(error); ok {
+			reason = ErrToReason(e, statusCode)
+		} else {
+			reason = statusToReason(statusCode)
+		}
+
 		errMsg := fmt.Sprint(err)
 		if errMsg != "" && errMsg != "<nil>" || len(errCodes) == 0 {
 			resp.Errors = append(resp.Errors, api.HTTPError{
 				Code:    int(api.ErrCodeGeneric),
-				Message: errMsg})
+				Message: errMsg,
+				Reason:  reason})
 		} else {
 			for _, code := range errCodes {
 				resp.Errors = append(resp.Errors, api.HTTPError{
 					Code:    int(code),
-					Message: api.ErrorCodeMap[code]})
+					Message: api.ErrorCodeMap[code],
+					Reason:  reason})
 			}
 		}
 	}
@@ -89,6 +224,18 @@ func SendHTTPError(ctx context.Context, w http.ResponseWriter, statusCode int,
 	}
 }
 
+// stepErrToReason returns the reason of the failure of a transaction step on
+// a peer, from the error the peer returned
+func stepErrToReason(e api.HTTPError) api.ErrorReason {
+	if api.ErrorCode(e.Code) != api.ErrTxnStepFailed {
+		return api.ReasonInternal
+	}
+	if reason, ok := msgToReason(e.Fields["error"]); ok {
+		return reason
+	}
+	return api.ReasonTxnStepFailed
+}
+
 // ErrToStatusCode returns error and http Status code based on  err
 func ErrToStatusCode(err error) (int, error) {
 	var statuscode int
@@ -98,10 +245,72 @@ func ErrToStatusCode(err error) (int, error) {
 		statuscode = http.StatusNotFound
 	case gderrors.ErrVolNotFound:
 		statuscode = http.StatusNotFound
+	case gderrors.ErrBrickNotFound:
+		statuscode = http.StatusNotFound
 	case gderrors.ErrSnapNotFound:
 		statuscode = http.StatusNotFound
+	case gderrors.ErrInvalidVolFileTmplName:
+		statuscode = http.StatusNotFound
+	case gderrors.ErrVolFileTmplVersionNotFound:
+		statuscode = http.StatusNotFound
+	case gderrors.ErrCustomXlatorNotFound:
+		statuscode = http.StatusNotFound
+	case gderrors.ErrConfigSnapshotNotFound:
+		statuscode = http.StatusNotFound
+	case gderrors.ErrConfigSnapshotConflict:
+		statuscode = http.StatusConflict
+	case gderrors.ErrEvacuationNotFound:
+		statuscode = http.StatusNotFound
+	case gderrors.ErrEvacuationInProgress:
+		statuscode = http.StatusConflict
+	case gderrors.ErrOptionGroupNotFound:
+		statuscode = http.StatusNotFound
+	case gderrors.ErrHookNotFound:
+		statuscode = http.StatusNotFound
+	case gderrors.ErrHookExists:
+		statuscode = http.StatusConflict
+	case gderrors.ErrMountNotFound:
+		statuscode = http.StatusNotFound
+	case gderrors.ErrMountExists:
+		statuscode = http.StatusConflict
+	case gderrors.ErrVolClientsConnected:
+		statuscode = http.StatusConflict
+	case gderrors.ErrTrashedVolNotFound:
+		statuscode = http.StatusNotFound
+	case gderrors.ErrVolExists:
+		statuscode = http.StatusConflict
+	case gderrors.ErrNamespaceNotFound:
+		statuscode = http.StatusNotFound
+	case gderrors.ErrNamespaceExists:
+		statuscode = http.StatusConflict
+	case gderrors.ErrNamespaceNotEmpty:
+		statuscode = http.StatusConflict
+	case gderrors.ErrNamespaceTokenNotFound:
+		statuscode = http.StatusNotFound
+	case gderrors.ErrSupportBundleNotFound:
+		statuscode = http.StatusNotFound
+	case gderrors.ErrSupportBundleRunning:
+		statuscode = http.StatusConflict
+	case gderrors.ErrSupportBundleNotReady:
+		statuscode = http.StatusConflict
+	case gderrors.ErrServiceNotFound:
+		statuscode = http.StatusNotFound
+	case gderrors.ErrNoLeader:
+		statuscode = http.StatusConflict
+	case gderrors.ErrShuttingDown:
+		statuscode = http.StatusServiceUnavailable
+	case gderrors.ErrRevisionMismatch:
+		statuscode = http.StatusPreconditionFailed
+	case gderrors.ErrIdempotencyKeyInUse:
+		statuscode = http.StatusConflict
+	case gderrors.ErrIdempotencyKeyReused:
+		statuscode = http.StatusUnprocessableEntity
+	case gderrors.ErrRevisionCompacted:
+		statuscode = http.StatusGone
 	case transaction.ErrLockTimeout:
 		statuscode = http.StatusConflict
+	case transaction.ErrLockNotFound:
+		statuscode = http.StatusNotFound
 	default:
 		statuscode = http.StatusInternalServerError
 	}
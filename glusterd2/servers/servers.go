@@ -3,10 +3,12 @@ package servers
 
 import (
 	"github.com/gluster/glusterd2/glusterd2/servers/eventlistener"
+	"github.com/gluster/glusterd2/glusterd2/servers/metrics"
 	"github.com/gluster/glusterd2/glusterd2/servers/muxsrv"
 	"github.com/gluster/glusterd2/glusterd2/servers/peerrpc"
 
 	log "github.com/sirupsen/logrus"
+	config "github.com/spf13/viper"
 	"github.com/thejerf/suture"
 )
 
@@ -21,5 +23,9 @@ func New() *suture.Supervisor {
 	s.Add(muxsrv.New())        // sunrpc + http
 	s.Add(eventlistener.New()) // eventlistener
 
+	if config.GetBool("metrics") && config.GetString("metrics-address") != "" {
+		s.Add(metrics.New()) // prometheus metrics
+	}
+
 	return s
 }
@@ -0,0 +1,69 @@
+package sunrpc
+
+import (
+	"expvar"
+	"sync"
+)
+
+var (
+	// metrics
+	getspecInflight = expvar.NewInt("sunrpc_getspec_inflight")
+	getspecWaiting  = expvar.NewInt("sunrpc_getspec_waiting")
+	getspecRefused  = expvar.NewInt("sunrpc_getspec_refused")
+)
+
+// admission limits the volfile requests of the clients served at once. The
+// requests over the limit wait for their turn, except those of the clients
+// fetching a volfile again when too many requests wait already: these
+// clients keep running with the volfile they have, and are notified again
+// later.
+type admission struct {
+	mu       sync.Mutex
+	cond     *sync.Cond
+	inflight int
+	waiting  int
+}
+
+func newAdmission() *admission {
+	a := &admission{}
+	a.cond = sync.NewCond(&a.mu)
+	return a
+}
+
+var getspecAdmission = newAdmission()
+
+// admit waits until the request can be served, given the maximum number of
+// requests served at once and waiting. It returns false if the request of a
+// client fetching a volfile again is refused. done must be called once an
+// admitted request is served.
+func (a *admission) admit(refetch bool, maxInflight, maxWaiting int) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if maxInflight > 0 && a.inflight >= maxInflight {
+		if refetch && maxWaiting > 0 && a.waiting >= maxWaiting {
+			getspecRefused.Add(1)
+			return false
+		}
+		a.waiting++
+		getspecWaiting.Set(int64(a.waiting))
+		for a.inflight >= maxInflight {
+			a.cond.Wait()
+		}
+		a.waiting--
+		getspecWaiting.Set(int64(a.waiting))
+	}
+	a.inflight++
+	getspecInflight.Set(int64(a.inflight))
+	return true
+}
+
+// done releases the place of a request served
+func (a *admission) done() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.inflight--
+	getspecInflight.Set(int64(a.inflight))
+	a.cond.Signal()
+}
@@ -0,0 +1,39 @@
+package sunrpc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdmission(t *testing.T) {
+	a := newAdmission()
+	assert.True(t, a.admit(false, 1, 1))
+
+	// a first fetch waits for its turn
+	admitted := make(chan bool)
+	go func() {
+		admitted <- a.admit(false, 1, 1)
+	}()
+	select {
+	case <-admitted:
+		t.Fatal("request admitted over the limit")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// a refetch is refused once too many requests wait
+	assert.False(t, a.admit(true, 1, 1))
+
+	a.done()
+	assert.True(t, <-admitted)
+	a.done()
+
+	// no limit
+	assert.True(t, a.admit(true, 0, 0))
+	assert.True(t, a.admit(true, 0, 0))
+	a.done()
+	a.done()
+	assert.Equal(t, 0, a.inflight)
+	assert.Equal(t, 0, a.waiting)
+}
@@ -2,8 +2,12 @@ package sunrpc
 
 import (
 	"bytes"
+	"errors"
 	"net"
+	"sort"
+	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/gluster/glusterd2/glusterd2/transaction"
 	"github.com/gluster/glusterd2/pkg/sunrpc"
@@ -21,8 +25,10 @@ import (
 //     1. Multiple goroutines may invoke methods on a net.Conn simultaneously.
 //     2. SunRPC ServerCodec will always send entire RPC message in a single
 //        RPC fragment/record over the socket.
-//     3. The glusterfs RPC client processes will never send a RPC reply to
-//        these RPC calls sent by glusterd2.
+//     3. The glusterfs RPC client processes never send a RPC reply to these
+//        RPC calls sent by glusterd2. Replies are only waited for when the
+//        caller asks for it, and are passed to the callback manager by the
+//        ServerCodec of the connection.
 // If any of the above pre-conditions change, this implementation should be
 // revisited.
 
@@ -31,27 +37,62 @@ import (
 // - glusterd_fetchsnap_notify()
 // - glusterd_client_statedump_submit_req()->rpcsvc_request_submit()
 
-// TODO:
-// Glusterd2 (and glusterd1) cannot yet recognize clients (as glusterfsd,
-// snapd, glusterfs etc). So these callback notifications are sent to all
-// the connected RPC clients.
-
 const (
 	glusterCbkProgram = 52743234 // GLUSTER_CBK_PROGRAM
 	glusterCbkVersion = 1        // GLUSTER_CBK_VERSION
 )
 
+// CallbackProcedure is a procedure of the GLUSTER_CBK program, called by
+// glusterd2 on the connected clients
+type CallbackProcedure uint32
+
+// rpc/rpc-lib/src/protocol-common.h:gf_cbk_procnum
+const (
+	gfCbkFetchSpec         CallbackProcedure = 1
+	gfCbkGetSnaps          CallbackProcedure = 4
+	gfCbkCacheInvalidation CallbackProcedure = 5
+	gfCbkChildUp           CallbackProcedure = 6
+	gfCbkChildDown         CallbackProcedure = 7
+	gfCbkStatedump         CallbackProcedure = 9
+)
+
+var callbackProcedureNames = map[CallbackProcedure]string{
+	gfCbkFetchSpec:         "FETCHSPEC",
+	gfCbkGetSnaps:          "GET_SNAPS",
+	gfCbkCacheInvalidation: "CACHE_INVALIDATION",
+	gfCbkChildUp:           "CHILD_UP",
+	gfCbkChildDown:         "CHILD_DOWN",
+	gfCbkStatedump:         "STATEDUMP",
+}
+
+func (p CallbackProcedure) String() string {
+	if name, ok := callbackProcedureNames[p]; ok {
+		return name
+	}
+	return "UNKNOWN"
+}
+
+// notifyTimeout is the time to wait for the callbacks to be sent to the
+// clients, and for their replies if waited for, before reporting the result
+// of a notification
+const notifyTimeout = 5 * time.Second
+
+var (
+	errCallbackTimeout      = errors.New("timed out waiting for the reply of the client")
+	errCallbackDisconnected = errors.New("client disconnected before replying")
+)
+
 var xidCounter uint32
 
 func getNewXid() uint32 {
 	return atomic.AddUint32(&xidCounter, 1)
 }
 
-func callbackClient(conn net.Conn, p sunrpc.ProcedureID, args interface{}) error {
+func callbackClient(conn net.Conn, xid uint32, p sunrpc.ProcedureID, args interface{}) error {
 	payload := new(bytes.Buffer)
 
 	call := sunrpc.RPCMsg{
-		Xid:  getNewXid(),
+		Xid:  xid,
 		Type: sunrpc.Call,
 		CBody: sunrpc.CallBody{
 			RPCVersion: sunrpc.RPCProtocolVersion,
@@ -79,86 +120,351 @@ func callbackClient(conn net.Conn, p sunrpc.ProcedureID, args interface{}) error
 	return nil
 }
 
-type fetchOp uint8
+// pendingCall is a callback waiting for the reply of the client
+type pendingCall struct {
+	conn  net.Conn
+	reply chan error
+}
 
-// rpc/rpc-lib/src/protocol-common.h:gf_cbk_procnum
-const (
-	gfCbkFetchSpec fetchOp = 1
-	gfCbkGetSnaps  fetchOp = 4
-)
-const (
-	gfCbkStatedump = 9
-)
+// callbackManager sends the callbacks to the connected clients and tracks
+// the replies of the calls waiting for one
+type callbackManager struct {
+	sync.Mutex
+	// pending are the calls waiting for a reply, by xid
+	pending map[uint32]*pendingCall
+}
 
-func fetchNotify(t transaction.TxnCtx, op fetchOp) {
-	clientsList.RLock()
-	defer clientsList.RUnlock()
+var callbacks = &callbackManager{
+	pending: make(map[uint32]*pendingCall),
+}
+
+// call sends the callback to the client. If waitReply is set, it waits for
+// the reply of the client until the timeout expires.
+func (m *callbackManager) call(conn net.Conn, p sunrpc.ProcedureID, args interface{}, waitReply bool, timeout time.Duration) error {
+	xid := getNewXid()
+	if !waitReply {
+		return callbackClient(conn, xid, p, args)
+	}
 
+	pc := &pendingCall{conn: conn, reply: make(chan error, 1)}
+	m.Lock()
+	m.pending[xid] = pc
+	m.Unlock()
+	defer func() {
+		m.Lock()
+		delete(m.pending, xid)
+		m.Unlock()
+	}()
+
+	if err := callbackClient(conn, xid, p, args); err != nil {
+		return err
+	}
+
+	select {
+	case err := <-pc.reply:
+		return err
+	case <-time.After(timeout):
+		return errCallbackTimeout
+	}
+}
+
+// handleReply passes the reply received from a client to the call waiting
+// for it. Replies to calls which timed out are dropped.
+func (m *callbackManager) handleReply(reply *sunrpc.RPCMsg) {
+	m.Lock()
+	pc, ok := m.pending[reply.Xid]
+	delete(m.pending, reply.Xid)
+	m.Unlock()
+
+	if !ok {
+		log.WithField("xid", reply.Xid).Debug("dropping reply to an unknown callback")
+		return
+	}
+	pc.reply <- sunrpc.ReplyError(reply)
+}
+
+// dropConn fails the calls waiting for the reply of a disconnected client
+func (m *callbackManager) dropConn(conn net.Conn) {
+	m.Lock()
+	defer m.Unlock()
+
+	for xid, pc := range m.pending {
+		if pc.conn == conn {
+			delete(m.pending, xid)
+			pc.reply <- errCallbackDisconnected
+		}
+	}
+}
+
+// Callback is a RPC call sent by glusterd2 to the connected clients
+type Callback struct {
+	// Procedure is the procedure of the GLUSTER_CBK program called
+	Procedure CallbackProcedure
+	// Args are the arguments of the procedure, nil if it takes none
+	Args interface{}
+	// WaitReply makes the call wait for the replies of the clients. The
+	// glusterfs processes do not reply to the callbacks, a client is
+	// counted as notified once the call is sent to it otherwise.
+	WaitReply bool
+	// Timeout is the time to wait for the calls to complete, 5 seconds if
+	// not set
+	Timeout time.Duration
+}
+
+// ClientSelector selects the connected clients a callback is sent to
+type ClientSelector func(conn net.Conn, ci *clientInfo) bool
+
+// SelectVolume selects the clients which may be using the volfiles of the
+// volume
+func SelectVolume(volname string) ClientSelector {
+	return func(_ net.Conn, ci *clientInfo) bool {
+		return ci.usesVolume(volname)
+	}
+}
+
+// SelectVolfile selects the clients which fetched the volfile with the given
+// ID
+func SelectVolfile(volfileID string) ClientSelector {
+	return func(_ net.Conn, ci *clientInfo) bool {
+		ci.Lock()
+		defer ci.Unlock()
+		_, ok := ci.volfiles[volfileID]
+		return ok
+	}
+}
+
+// SelectProcess selects the clients on the host with the given PID. The
+// clients which have not sent their PID are selected too.
+func SelectProcess(host string, pid int) ClientSelector {
+	return func(conn net.Conn, ci *clientInfo) bool {
+		h, _, _ := net.SplitHostPort(conn.RemoteAddr().String())
+		if !utils.IsAddressSame(h, host) {
+			return false
+		}
+		ci.Lock()
+		defer ci.Unlock()
+		return ci.pid == 0 || ci.pid == pid
+	}
+}
+
+// CallResult is the result of a callback sent to a client
+type CallResult struct {
+	// Address is the address of the client
+	Address string
+	// Error is the error sending the callback or returned by the client,
+	// empty if it succeeded or is pending
+	Error string
+	// Pending is set if the call had not completed when the result was
+	// reported
+	Pending bool
+}
+
+// NotifyResult is the result of notifying the connected clients
+type NotifyResult struct {
+	// Notified is the number of clients the notification was sent to
+	Notified int
+	// Failed is the number of clients the notification could not be sent
+	// to
+	Failed int
+	// Pending is the number of clients the notification was still being
+	// sent to when the result was reported
+	Pending int
+	// Calls are the results of the calls to each client, sorted by
+	// address
+	Calls []CallResult
+}
+
+// Notify sends the callback to the connected clients selected, all of them
+// if selector is nil, and returns the result of the calls once they all
+// completed or the timeout expired.
+func Notify(logger log.FieldLogger, cbk Callback, selector ClientSelector) NotifyResult {
 	p := sunrpc.ProcedureID{
 		ProgramNumber:   glusterCbkProgram,
 		ProgramVersion:  glusterCbkVersion,
-		ProcedureNumber: uint32(op),
+		ProcedureNumber: uint32(cbk.Procedure),
+	}
+	timeout := cbk.Timeout
+	if timeout == 0 {
+		timeout = notifyTimeout
 	}
 
-	for conn := range clientsList.c {
-		go func(c net.Conn) {
-			if err := callbackClient(c, p, nil); err != nil {
-				t.Logger().WithError(err).WithFields(log.Fields{
+	clientsList.RLock()
+	clients := make(map[net.Conn]*clientInfo)
+	for conn, ci := range clientsList.c {
+		if selector == nil || selector(conn, ci) {
+			clients[conn] = ci
+		}
+	}
+	clientsList.RUnlock()
+
+	var (
+		mu    sync.Mutex
+		calls = make(map[net.Conn]*CallResult, len(clients))
+		wg    sync.WaitGroup
+	)
+	for conn, ci := range clients {
+		calls[conn] = &CallResult{Address: conn.RemoteAddr().String(), Pending: true}
+
+		wg.Add(1)
+		go func(c net.Conn, ci *clientInfo) {
+			defer wg.Done()
+			err := callbacks.call(c, p, cbk.Args, cbk.WaitReply, timeout)
+			ci.recordNotify(err)
+
+			mu.Lock()
+			defer mu.Unlock()
+			calls[c].Pending = false
+			if err != nil {
+				calls[c].Error = err.Error()
+				logger.WithError(err).WithFields(log.Fields{
 					"client":    c.RemoteAddr().String(),
-					"procedure": op,
+					"procedure": cbk.Procedure,
 				}).Warn("Failed to notify RPC client")
 			}
-		}(conn)
+		}(conn, ci)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(timeout):
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	result := summarize(calls)
+	logger.WithFields(log.Fields{
+		"procedure": cbk.Procedure,
+		"notified":  result.Notified,
+		"failed":    result.Failed,
+		"pending":   result.Pending,
+	}).Info("notified RPC clients")
+	return result
+}
+
+// summarize returns the result of a notification from the results of the
+// calls to each client
+func summarize(calls map[net.Conn]*CallResult) NotifyResult {
+	var result NotifyResult
+	for _, c := range calls {
+		switch {
+		case c.Pending:
+			result.Pending++
+		case c.Error != "":
+			result.Failed++
+		default:
+			result.Notified++
+		}
+		result.Calls = append(result.Calls, *c)
 	}
+	sort.Slice(result.Calls, func(i, j int) bool {
+		return result.Calls[i].Address < result.Calls[j].Address
+	})
+	return result
 }
 
 // FetchSpecNotify notifies all clients connected to glusterd that the volfile
 // has changed and the clients should fetch the new volfile.
-func FetchSpecNotify(t transaction.TxnCtx) {
-	fetchNotify(t, gfCbkFetchSpec)
+func FetchSpecNotify(t transaction.TxnCtx) NotifyResult {
+	return Notify(t.Logger(), Callback{Procedure: gfCbkFetchSpec}, nil)
+}
+
+// VolfileChangeNotify notifies the clients connected to glusterd which may be
+// using the volfiles of the volume that they have changed, so that the
+// clients fetch the new volfiles and reload their graphs.
+func VolfileChangeNotify(t transaction.TxnCtx, volname string) NotifyResult {
+	return Notify(t.Logger().WithField("volume", volname), Callback{Procedure: gfCbkFetchSpec}, SelectVolume(volname))
+}
+
+// VolfileNotify notifies the clients connected to glusterd which fetched the
+// volfile with the given ID, so that they fetch it again
+func VolfileNotify(logger log.FieldLogger, volfileID string) NotifyResult {
+	return Notify(logger.WithField("volfile-id", volfileID), Callback{Procedure: gfCbkFetchSpec}, SelectVolfile(volfileID))
 }
 
 // FetchSnapNotify notifies all clients connected to glusterd that a snapshot
 // has been created or modified.
-func FetchSnapNotify(t transaction.TxnCtx) {
-	fetchNotify(t, gfCbkGetSnaps)
+func FetchSnapNotify(t transaction.TxnCtx) NotifyResult {
+	return Notify(t.Logger(), Callback{Procedure: gfCbkGetSnaps}, nil)
 }
 
-type gfStatedump struct {
-	Pid uint32
+// ChildUpNotify notifies the clients which may be using the volume that a
+// subvolume is up. The procedure takes no argument.
+func ChildUpNotify(logger log.FieldLogger, volname string) NotifyResult {
+	return Notify(logger.WithField("volume", volname), Callback{Procedure: gfCbkChildUp}, SelectVolume(volname))
 }
 
-// ClientStatedump sends notification to all connected RPC clients on the
-// specified host to take statedump. The clients will examine if the PID
-// it received in notification is same as it's own PID. If yes, it will
-// take it's own statedump.
-func ClientStatedump(volname string, host string, pid int, logger log.FieldLogger) {
+// ChildDownNotify notifies the clients which may be using the volume that a
+// subvolume is down. The procedure takes no argument.
+func ChildDownNotify(logger log.FieldLogger, volname string) NotifyResult {
+	return Notify(logger.WithField("volume", volname), Callback{Procedure: gfCbkChildDown}, SelectVolume(volname))
+}
 
-	clientsList.RLock()
-	defer clientsList.RUnlock()
+// gfIatt is the XDR encoding of the attributes of an inode
+// (rpc/xdr/src/glusterfs-fops.x:gf_iatt)
+type gfIatt struct {
+	IaGfid      [16]byte
+	IaIno       uint64
+	IaDev       uint64
+	Mode        uint32
+	IaNlink     uint32
+	IaUID       uint32
+	IaGID       uint32
+	IaRdev      uint64
+	IaSize      uint64
+	IaBlksize   uint32
+	IaBlocks    uint64
+	IaAtime     uint32
+	IaAtimeNsec uint32
+	IaMtime     uint32
+	IaMtimeNsec uint32
+	IaCtime     uint32
+	IaCtimeNsec uint32
+}
 
-	p := sunrpc.ProcedureID{
-		ProgramNumber:   glusterCbkProgram,
-		ProgramVersion:  glusterCbkVersion,
-		ProcedureNumber: gfCbkStatedump,
-	}
+// gfCacheInvalidation is the request of the CACHE_INVALIDATION callback
+// (rpc/xdr/src/glusterfs3-xdr.x:gfs3_cbk_cache_invalidation_req)
+type gfCacheInvalidation struct {
+	Gfid           string
+	EventType      uint32
+	Flags          uint32
+	ExpireTimeAttr uint32
+	Stat           gfIatt
+	ParentStat     gfIatt
+	OldParentStat  gfIatt
+	Xdata          []byte
+}
 
-	for conn := range clientsList.c {
-		h, _, _ := net.SplitHostPort(conn.RemoteAddr().String())
-		if utils.IsAddressSame(h, host) {
-			// TODO: Our RPC framework doesn't yet support tagging
-			// each connection with information whether it's a
-			// client or brick, or which volume the client is
-			// connected to etc. We can filter by volume name here
-			// and send RPCs to only those clients.
-			go func(c net.Conn) {
-				if err := callbackClient(c, p, &gfStatedump{uint32(pid)}); err != nil {
-					logger.WithError(err).WithFields(log.Fields{
-						"client":    c.RemoteAddr().String(),
-						"procedure": gfCbkStatedump,
-					}).Warn("Failed to notify RPC client")
-				}
-			}(conn)
-		}
+// gfUpcallCacheInvalidation is the upcall event type of the cache
+// invalidation (libglusterfs/src/upcall-utils.h:GF_UPCALL_CACHE_INVALIDATION)
+const gfUpcallCacheInvalidation = 1
+
+// CacheInvalidationNotify asks the clients which may be using the volume to
+// invalidate the cached attributes of the inode with the given GFID. flags
+// are the upcall flags of the attributes to invalidate.
+func CacheInvalidationNotify(logger log.FieldLogger, volname, gfid string, flags uint32) NotifyResult {
+	req := &gfCacheInvalidation{
+		Gfid:      gfid,
+		EventType: gfUpcallCacheInvalidation,
+		Flags:     flags,
 	}
+	return Notify(logger.WithFields(log.Fields{"volume": volname, "gfid": gfid}),
+		Callback{Procedure: gfCbkCacheInvalidation, Args: req}, SelectVolume(volname))
+}
+
+type gfStatedump struct {
+	Pid uint32
+}
+
+// ClientStatedump sends notification to the RPC clients on the specified
+// host to take statedump. The clients will examine if the PID it received in
+// notification is same as it's own PID. If yes, it will take it's own
+// statedump.
+func ClientStatedump(volname string, host string, pid int, logger log.FieldLogger) NotifyResult {
+	return Notify(logger.WithFields(log.Fields{"volume": volname, "host": host, "pid": pid}),
+		Callback{Procedure: gfCbkStatedump, Args: &gfStatedump{uint32(pid)}}, SelectProcess(host, pid))
 }
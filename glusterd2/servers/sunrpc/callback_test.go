@@ -0,0 +1,114 @@
+package sunrpc
+
+import (
+	"bytes"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/gluster/glusterd2/pkg/sunrpc"
+
+	"github.com/rasky/go-xdr/xdr2"
+	"github.com/stretchr/testify/assert"
+)
+
+var testCbkProcedure = sunrpc.ProcedureID{
+	ProgramNumber:   glusterCbkProgram,
+	ProgramVersion:  glusterCbkVersion,
+	ProcedureNumber: uint32(gfCbkStatedump),
+}
+
+// readCall reads the callback sent to the client
+func readCall(t *testing.T, conn net.Conn) sunrpc.RPCMsg {
+	record, err := sunrpc.ReadFullRecord(conn)
+	assert.Nil(t, err)
+
+	var call sunrpc.RPCMsg
+	_, err = xdr.Unmarshal(bytes.NewReader(record), &call)
+	assert.Nil(t, err)
+	return call
+}
+
+func TestCallbackReply(t *testing.T) {
+	server, client := net.Pipe()
+	defer server.Close()
+	defer client.Close()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- callbacks.call(server, testCbkProcedure, &gfStatedump{42}, true, time.Minute)
+	}()
+
+	call := readCall(t, client)
+	assert.Equal(t, sunrpc.Call, call.Type)
+	assert.Equal(t, uint32(glusterCbkProgram), call.CBody.Program)
+	assert.Equal(t, uint32(gfCbkStatedump), call.CBody.Procedure)
+
+	callbacks.handleReply(&sunrpc.RPCMsg{
+		Xid:  call.Xid,
+		Type: sunrpc.Reply,
+		RBody: sunrpc.ReplyBody{
+			Stat:   sunrpc.MsgAccepted,
+			Areply: sunrpc.AcceptedReply{Stat: sunrpc.ProcUnavail},
+		},
+	})
+	assert.Equal(t, sunrpc.ErrProcUnavail, <-errCh)
+	assert.Empty(t, callbacks.pending)
+}
+
+func TestCallbackTimeout(t *testing.T) {
+	server, client := net.Pipe()
+	defer server.Close()
+	defer client.Close()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- callbacks.call(server, testCbkProcedure, nil, true, 10*time.Millisecond)
+	}()
+
+	call := readCall(t, client)
+	assert.Equal(t, errCallbackTimeout, <-errCh)
+
+	// late replies are dropped
+	callbacks.handleReply(&sunrpc.RPCMsg{Xid: call.Xid, Type: sunrpc.Reply})
+	assert.Empty(t, callbacks.pending)
+}
+
+func TestCallbackDisconnected(t *testing.T) {
+	server, client := net.Pipe()
+	defer server.Close()
+	defer client.Close()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- callbacks.call(server, testCbkProcedure, nil, true, time.Minute)
+	}()
+
+	readCall(t, client)
+	callbacks.dropConn(server)
+	assert.Equal(t, errCallbackDisconnected, <-errCh)
+}
+
+func TestSummarize(t *testing.T) {
+	a, b := net.Pipe()
+	c, d := net.Pipe()
+	result := summarize(map[net.Conn]*CallResult{
+		a: {Address: "10.0.0.2:49151"},
+		b: {Address: "10.0.0.1:49152", Error: "broken pipe"},
+		c: {Address: "10.0.0.3:49150", Pending: true},
+		d: {Address: "10.0.0.1:49151"},
+	})
+	assert.Equal(t, 2, result.Notified)
+	assert.Equal(t, 1, result.Failed)
+	assert.Equal(t, 1, result.Pending)
+	if assert.Len(t, result.Calls, 4) {
+		assert.Equal(t, "10.0.0.1:49151", result.Calls[0].Address)
+		assert.Equal(t, "10.0.0.3:49150", result.Calls[3].Address)
+	}
+}
+
+func TestCallbackProcedureString(t *testing.T) {
+	assert.Equal(t, "FETCHSPEC", gfCbkFetchSpec.String())
+	assert.Equal(t, "CHILD_DOWN", gfCbkChildDown.String())
+	assert.Equal(t, "UNKNOWN", CallbackProcedure(42).String())
+}
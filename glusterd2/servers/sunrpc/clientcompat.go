@@ -0,0 +1,55 @@
+package sunrpc
+
+import (
+	"strconv"
+
+	"github.com/gluster/glusterd2/glusterd2/options"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/glusterd2/xlator"
+)
+
+const (
+	// Keys of the volume options of the compatibility of the clients,
+	// namespaced by a pseudo xlator
+	compatXlatorID = "client-compat"
+	// MinOpVersionKey is the volume option refusing the client volfiles of
+	// the volume to the clients which do not support its op-version
+	MinOpVersionKey = compatXlatorID + ".min-op-version"
+)
+
+func init() {
+	// The options are consumed by the volfile server, they never reach a
+	// volfile
+	xlator.RegisterPseudoXlator(&xlator.Xlator{
+		ID: compatXlatorID,
+		Options: []*options.Option{
+			{
+				Key:          []string{"min-op-version"},
+				Type:         options.OptionTypeInt,
+				Min:          0,
+				DefaultValue: "0",
+				Description:  "Lowest op-version the clients must support to mount the volume. The clients which do not advertise the op-versions they support are refused too. Set to 0 to let any client mount the volume.",
+				Flags:        options.OptionFlagSettable,
+				Level:        options.OptionStatusBasic,
+			},
+		},
+	})
+}
+
+// MinClientOpVersion returns the lowest op-version the clients must support
+// to mount the volume, 0 if any client may mount it
+func MinClientOpVersion(v *volume.Volinfo) int {
+	n, err := strconv.Atoi(v.Options[MinOpVersionKey])
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
+
+// ClientSupported returns true if a client supporting op-versions up to
+// opVersion may mount the volume. opVersion is 0 for the clients which do not
+// advertise it.
+func ClientSupported(v *volume.Volinfo, opVersion int) bool {
+	min := MinClientOpVersion(v)
+	return min == 0 || opVersion >= min
+}
@@ -0,0 +1,376 @@
+package sunrpc
+
+import (
+	"net"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/utils"
+)
+
+// clientInfo is the state tracked for a connected SunRPC client
+type clientInfo struct {
+	sync.Mutex
+	connectedAt time.Time
+	// pid and op-versions of the client process, if sent by the client
+	// along with its volfile requests. opVersion is the highest op-version
+	// the client supports, and minOpVersion the lowest.
+	pid          int
+	opVersion    int
+	minOpVersion int
+	// volfiles are the IDs of the volfiles fetched by the client, used as
+	// a set
+	volfiles map[string]struct{}
+	// served are the checksums of the volfiles last served to the client,
+	// by volfile ID
+	served map[string]string
+	// results of the callback notifications sent to the client
+	notified        int
+	notifyFailed    int
+	lastNotified    time.Time
+	lastNotifyError string
+}
+
+func newClientInfo() *clientInfo {
+	return &clientInfo{
+		connectedAt: time.Now(),
+		volfiles:    make(map[string]struct{}),
+		served:      make(map[string]string),
+	}
+}
+
+// ClientStatus is a snapshot of the state of a connected SunRPC client
+type ClientStatus struct {
+	Address         string
+	ConnectedAt     time.Time
+	Pid             int
+	OpVersion       int
+	MinOpVersion    int
+	Volfiles        []string
+	Notified        int
+	NotifyFailed    int
+	LastNotified    time.Time
+	LastNotifyError string
+}
+
+// Clients returns the state of the clients connected to the SunRPC server,
+// sorted by address
+func Clients() []ClientStatus {
+	clientsList.RLock()
+	defer clientsList.RUnlock()
+
+	clients := make([]ClientStatus, 0, len(clientsList.c))
+	for conn, ci := range clientsList.c {
+		ci.Lock()
+		cs := ClientStatus{
+			Address:         conn.RemoteAddr().String(),
+			ConnectedAt:     ci.connectedAt,
+			Pid:             ci.pid,
+			OpVersion:       ci.opVersion,
+			MinOpVersion:    ci.minOpVersion,
+			Notified:        ci.notified,
+			NotifyFailed:    ci.notifyFailed,
+			LastNotified:    ci.lastNotified,
+			LastNotifyError: ci.lastNotifyError,
+		}
+		for v := range ci.volfiles {
+			cs.Volfiles = append(cs.Volfiles, v)
+		}
+		ci.Unlock()
+		sort.Strings(cs.Volfiles)
+		clients = append(clients, cs)
+	}
+	sort.Slice(clients, func(i, j int) bool {
+		return clients[i].Address < clients[j].Address
+	})
+	return clients
+}
+
+// trackVolfile records that the client fetched the volfile
+func trackVolfile(conn net.Conn, volfileID string) {
+	clientsList.RLock()
+	defer clientsList.RUnlock()
+
+	if ci, ok := clientsList.c[conn]; ok {
+		ci.Lock()
+		ci.volfiles[volfileID] = struct{}{}
+		ci.Unlock()
+	}
+}
+
+// trackServedVolfile records the checksum of the volfile served to the
+// client
+func trackServedVolfile(conn net.Conn, volfileID, checksum string) {
+	clientsList.RLock()
+	defer clientsList.RUnlock()
+
+	if ci, ok := clientsList.c[conn]; ok {
+		ci.Lock()
+		ci.served[volfileID] = checksum
+		ci.Unlock()
+	}
+}
+
+// servedBefore returns true if the volfile was served to the client already
+func servedBefore(conn net.Conn, volfileID string) bool {
+	clientsList.RLock()
+	defer clientsList.RUnlock()
+
+	ci, ok := clientsList.c[conn]
+	if !ok {
+		return false
+	}
+	ci.Lock()
+	defer ci.Unlock()
+	_, served := ci.served[volfileID]
+	return served
+}
+
+// ServedVolfile is a volfile last served to a connected client
+type ServedVolfile struct {
+	Address   string
+	Pid       int
+	VolfileID string
+	Checksum  string
+}
+
+// ServedVolfiles returns the volfiles last served to the connected clients,
+// sorted by address and volfile ID
+func ServedVolfiles() []ServedVolfile {
+	clientsList.RLock()
+	defer clientsList.RUnlock()
+
+	var served []ServedVolfile
+	for conn, ci := range clientsList.c {
+		ci.Lock()
+		for volfileID, checksum := range ci.served {
+			served = append(served, ServedVolfile{
+				Address:   conn.RemoteAddr().String(),
+				Pid:       ci.pid,
+				VolfileID: volfileID,
+				Checksum:  checksum,
+			})
+		}
+		ci.Unlock()
+	}
+	sort.Slice(served, func(i, j int) bool {
+		if served[i].Address != served[j].Address {
+			return served[i].Address < served[j].Address
+		}
+		return served[i].VolfileID < served[j].VolfileID
+	})
+	return served
+}
+
+// processUUIDPid matches the PID in the process-uuid of glusterfs processes,
+// made of the hostname, the PID and the start time of the process
+var processUUIDPid = regexp.MustCompile(`-([0-9]+)-[0-9]{4}/[0-9]{2}/[0-9]{2}-`)
+
+// opVersions returns the lowest and highest op-versions supported by the
+// client, from the dict sent along with its volfile request. They are 0 if
+// not sent.
+func opVersions(xdata map[string]string) (int, int) {
+	minOpVersion, _ := strconv.Atoi(xdata["min-op-version"])
+	maxOpVersion, _ := strconv.Atoi(xdata["max-op-version"])
+	return minOpVersion, maxOpVersion
+}
+
+// trackProcess records the PID and op-versions of the client, from the dict
+// sent along with its volfile request
+func trackProcess(conn net.Conn, xdata map[string]string) {
+	var pid int
+	minOpVersion, opVersion := opVersions(xdata)
+	if v, ok := xdata["pid"]; ok {
+		pid, _ = strconv.Atoi(v)
+	} else if m := processUUIDPid.FindStringSubmatch(xdata["process-uuid"]); m != nil {
+		pid, _ = strconv.Atoi(m[1])
+	}
+
+	clientsList.RLock()
+	defer clientsList.RUnlock()
+
+	if ci, ok := clientsList.c[conn]; ok {
+		ci.Lock()
+		if pid != 0 {
+			ci.pid = pid
+		}
+		if opVersion != 0 {
+			ci.opVersion = opVersion
+		}
+		if minOpVersion != 0 {
+			ci.minOpVersion = minOpVersion
+		}
+		ci.Unlock()
+	}
+}
+
+// VolumeClients returns the state of the connected clients which fetched
+// the client volfiles of the volume, sorted by address
+func VolumeClients(volname string) []ClientStatus {
+	var clients []ClientStatus
+	for _, c := range Clients() {
+		for _, v := range c.Volfiles {
+			if isClientVolfile(v, volname) {
+				clients = append(clients, c)
+				break
+			}
+		}
+	}
+	return clients
+}
+
+// DisconnectClients closes the connections of the clients on the host, which
+// fetched the client volfiles of the volume. If host is empty, the clients
+// on all the hosts are disconnected. If pid is not 0, only the connections
+// of the client process with that PID are closed. It returns the number of
+// connections closed.
+func DisconnectClients(volname, host string, pid int) int {
+	clientsList.RLock()
+	var conns []net.Conn
+	for conn, ci := range clientsList.c {
+		h, _, _ := net.SplitHostPort(conn.RemoteAddr().String())
+		if host != "" && !utils.IsAddressSame(h, host) {
+			continue
+		}
+		ci.Lock()
+		match := pid == 0 || ci.pid == pid
+		if match {
+			match = false
+			for v := range ci.volfiles {
+				if isClientVolfile(v, volname) {
+					match = true
+					break
+				}
+			}
+		}
+		ci.Unlock()
+		if match {
+			conns = append(conns, conn)
+		}
+	}
+	clientsList.RUnlock()
+
+	// The connections are pruned from the clients list once closed
+	for _, conn := range conns {
+		conn.Close()
+	}
+	return len(conns)
+}
+
+// Shutdown disconnects the connected clients, and closes the connections
+// accepted from then on, for the clients to fail over to their backup
+// volfile servers while glusterd2 shuts down. It returns the number of
+// connections closed.
+func Shutdown() int {
+	atomic.StoreInt32(&shuttingDown, 1)
+
+	clientsList.RLock()
+	conns := make([]net.Conn, 0, len(clientsList.c))
+	for conn := range clientsList.c {
+		conns = append(conns, conn)
+	}
+	clientsList.RUnlock()
+
+	// The connections are pruned from the clients list once closed
+	for _, conn := range conns {
+		conn.Close()
+	}
+	return len(conns)
+}
+
+// isClientVolfile returns true if the volfile ID refers to a volfile of the
+// clients of the volume, as opposed to the volfiles of its bricks and
+// daemons
+func isClientVolfile(volfileID, volname string) bool {
+	volfileID = strings.TrimPrefix(volfileID, "/")
+	return volfileID == volname ||
+		strings.HasPrefix(volfileID, volname+"/") ||
+		volfileID == "gfproxy-client/"+volname
+}
+
+// recordNotify records the result of a callback notification sent to the
+// client
+func (ci *clientInfo) recordNotify(err error) {
+	ci.Lock()
+	defer ci.Unlock()
+
+	ci.lastNotified = time.Now()
+	if err != nil {
+		ci.notifyFailed++
+		ci.lastNotifyError = err.Error()
+		return
+	}
+	ci.notified++
+	ci.lastNotifyError = ""
+}
+
+// usesVolume returns true if the client may be using the volfiles of the
+// volume. Clients which have not fetched any volfile yet, and the daemons
+// whose volfiles span volumes such as the self-heal daemon, are assumed to
+// use every volume.
+func (ci *clientInfo) usesVolume(volname string) bool {
+	ci.Lock()
+	defer ci.Unlock()
+
+	if len(ci.volfiles) == 0 {
+		return true
+	}
+	for v := range ci.volfiles {
+		if volfileOfVolume(v, volname) {
+			return true
+		}
+	}
+	return false
+}
+
+// volfilesChanged returns true if any of the volfiles of the volumes which
+// the client may be using differs from the one served to the client, given
+// the checksums of the volfiles by volfile ID, the client volfiles by volume
+// name. The volfiles without a checksum are assumed to have changed.
+func (ci *clientInfo) volfilesChanged(volnames []string, checksums map[string]string) bool {
+	ci.Lock()
+	defer ci.Unlock()
+
+	if len(ci.volfiles) == 0 {
+		return true
+	}
+	for v := range ci.volfiles {
+		for _, volname := range volnames {
+			if !volfileOfVolume(v, volname) {
+				continue
+			}
+			id := v
+			if name, _, ok := volume.SplitSubdirVolfileID(v); ok && name == volname {
+				// the client volfile of the volume
+				id = name
+			}
+			checksum, ok := checksums[id]
+			if !ok || ci.served[v] != checksum {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// volfileOfVolume returns true if the volfile ID refers to a volfile of the
+// volume, for example "testvol" for the client volfile,
+// "testvol.host.bricks-b1" for a brick volfile, "testvol/dir1" for a client
+// mounting an exported subdirectory and "rebalance/testvol" for the rebalance
+// volfile
+func volfileOfVolume(volfileID, volname string) bool {
+	volfileID = strings.TrimPrefix(volfileID, "/")
+	if strings.HasPrefix(volfileID, "gluster/") {
+		return true
+	}
+	return volfileID == volname ||
+		strings.HasPrefix(volfileID, volname+".") ||
+		strings.HasPrefix(volfileID, volname+"/") ||
+		strings.HasSuffix(volfileID, "/"+volname)
+}
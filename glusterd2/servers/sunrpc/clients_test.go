@@ -0,0 +1,113 @@
+package sunrpc
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gluster/glusterd2/glusterd2/volume"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVolfileOfVolume(t *testing.T) {
+	assert.True(t, volfileOfVolume("testvol", "testvol"))
+	assert.True(t, volfileOfVolume("/testvol", "testvol"))
+	assert.True(t, volfileOfVolume("testvol.127.0.0.1.bricks-b1", "testvol"))
+	assert.True(t, volfileOfVolume("rebalance/testvol", "testvol"))
+	assert.True(t, volfileOfVolume("testvol/dir1", "testvol"))
+	assert.True(t, volfileOfVolume("gluster/glustershd", "testvol"))
+	assert.False(t, volfileOfVolume("testvol2", "testvol"))
+	assert.False(t, volfileOfVolume("othervol", "testvol"))
+}
+
+func TestClientInfo(t *testing.T) {
+	ci := newClientInfo()
+	assert.True(t, ci.usesVolume("testvol"))
+
+	ci.volfiles["othervol"] = struct{}{}
+	assert.False(t, ci.usesVolume("testvol"))
+	ci.volfiles["testvol"] = struct{}{}
+	assert.True(t, ci.usesVolume("testvol"))
+
+	ci.recordNotify(errors.New("broken pipe"))
+	assert.Equal(t, 1, ci.notifyFailed)
+	assert.Equal(t, "broken pipe", ci.lastNotifyError)
+	ci.recordNotify(nil)
+	assert.Equal(t, 1, ci.notified)
+	assert.Empty(t, ci.lastNotifyError)
+}
+
+func TestVolfilesChanged(t *testing.T) {
+	vols := []string{"testvol"}
+	sums := map[string]string{
+		"testvol":                     "c1",
+		"testvol.127.0.0.1.bricks-b1": "b1",
+	}
+
+	ci := newClientInfo()
+	assert.True(t, ci.volfilesChanged(vols, sums))
+
+	ci.volfiles["othervol"] = struct{}{}
+	assert.False(t, ci.volfilesChanged(vols, sums))
+
+	ci.volfiles["testvol"] = struct{}{}
+	ci.served["testvol"] = "c0"
+	assert.True(t, ci.volfilesChanged(vols, sums))
+	ci.served["testvol"] = "c1"
+	assert.False(t, ci.volfilesChanged(vols, sums))
+
+	// The clients of a subdirectory are served the client volfile
+	ci.volfiles["testvol/dir1"] = struct{}{}
+	ci.served["testvol/dir1"] = "c1"
+	assert.False(t, ci.volfilesChanged(vols, sums))
+
+	ci = newClientInfo()
+	ci.volfiles["testvol.127.0.0.1.bricks-b1"] = struct{}{}
+	ci.served["testvol.127.0.0.1.bricks-b1"] = "b1"
+	assert.False(t, ci.volfilesChanged(vols, sums))
+
+	// The volfiles without a checksum may have changed
+	ci.volfiles["rebalance/testvol"] = struct{}{}
+	ci.served["rebalance/testvol"] = "r1"
+	assert.True(t, ci.volfilesChanged(vols, sums))
+}
+
+func TestIsClientVolfile(t *testing.T) {
+	assert.True(t, isClientVolfile("testvol", "testvol"))
+	assert.True(t, isClientVolfile("/testvol/dir1", "testvol"))
+	assert.True(t, isClientVolfile("gfproxy-client/testvol", "testvol"))
+	assert.False(t, isClientVolfile("testvol.127.0.0.1.bricks-b1", "testvol"))
+	assert.False(t, isClientVolfile("rebalance/testvol", "testvol"))
+	assert.False(t, isClientVolfile("gluster/glustershd", "testvol"))
+}
+
+func TestProcessUUIDPid(t *testing.T) {
+	m := processUUIDPid.FindStringSubmatch("client-1.example.com-4242-2018/10/01-10:20:30:123456-testvol-client-0-0-0")
+	if assert.NotNil(t, m) {
+		assert.Equal(t, "4242", m[1])
+	}
+	assert.Nil(t, processUUIDPid.FindStringSubmatch("testvol"))
+}
+
+func TestOpVersions(t *testing.T) {
+	min, max := opVersions(map[string]string{"min-op-version": "1", "max-op-version": "50000"})
+	assert.Equal(t, 1, min)
+	assert.Equal(t, 50000, max)
+
+	min, max = opVersions(map[string]string{})
+	assert.Equal(t, 0, min)
+	assert.Equal(t, 0, max)
+}
+
+func TestClientSupported(t *testing.T) {
+	v := &volume.Volinfo{Options: map[string]string{}}
+	assert.True(t, ClientSupported(v, 0))
+
+	v.Options[MinOpVersionKey] = "40100"
+	assert.Equal(t, 40100, MinClientOpVersion(v))
+	assert.True(t, ClientSupported(v, 50000))
+	assert.True(t, ClientSupported(v, 40100))
+	assert.False(t, ClientSupported(v, 31302))
+	// clients which do not advertise their op-version are refused
+	assert.False(t, ClientSupported(v, 0))
+}
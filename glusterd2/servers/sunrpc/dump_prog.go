@@ -82,7 +82,7 @@ func (p *GfDump) Dump(args *GfDumpReq, reply *GfDumpRsp) error {
 	var list *GfProcDetail
 	var trav *GfProcDetail
 
-	for _, p := range programsList {
+	for _, p := range programsList() {
 		tmp := &GfProcDetail{
 			ProgName: p.Name(),
 			ProgNum:  uint64(p.Number()),
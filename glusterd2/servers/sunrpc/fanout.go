@@ -0,0 +1,166 @@
+package sunrpc
+
+import (
+	"expvar"
+	"math/rand"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/gluster/glusterd2/pkg/sunrpc"
+
+	log "github.com/sirupsen/logrus"
+	flag "github.com/spf13/pflag"
+	config "github.com/spf13/viper"
+)
+
+const (
+	notifyBatchSizeOpt     = "volfile-notify-batch-size"
+	notifyBatchIntervalOpt = "volfile-notify-batch-interval"
+	getspecMaxInflightOpt  = "getspec-max-inflight"
+	getspecMaxQueuedOpt    = "getspec-max-queued"
+)
+
+// InitFlags sets the flags limiting the volfile fetches of the clients
+func InitFlags() {
+	flag.Int(notifyBatchSizeOpt, 50, "Number of clients notified at once to fetch their volfiles again, after an option change of many volumes.")
+	flag.Duration(notifyBatchIntervalOpt, time.Second, "Mean interval between the batches of clients notified to fetch their volfiles again, jittered by half of it either way.")
+	flag.Int(getspecMaxInflightOpt, 16, "Number of volfile requests of the clients served at once. Set to 0 for no limit.")
+	flag.Int(getspecMaxQueuedOpt, 256, "Number of volfile requests waiting to be served past which the requests of the clients fetching a volfile again are refused, and the clients notified again later.")
+}
+
+var notifyQueued = expvar.NewInt("sunrpc_volfile_notify_queued")
+
+// fanout notifies the queued clients to fetch their volfiles again, a batch
+// at a time with a jittered interval before each batch, so that the clients
+// of many volumes do not all fetch their volfiles at once
+type fanout struct {
+	sync.Mutex
+	queue   []net.Conn
+	queued  map[net.Conn]struct{}
+	running bool
+}
+
+var volfileFanout = &fanout{queued: make(map[net.Conn]struct{})}
+
+// add queues the clients, the clients already queued keep their place. It
+// returns the number of clients added to the queue.
+func (f *fanout) add(conns []net.Conn) int {
+	f.Lock()
+	defer f.Unlock()
+
+	added := 0
+	for _, c := range conns {
+		if _, ok := f.queued[c]; ok {
+			continue
+		}
+		f.queued[c] = struct{}{}
+		f.queue = append(f.queue, c)
+		added++
+	}
+	notifyQueued.Set(int64(len(f.queue)))
+
+	if !f.running && len(f.queue) > 0 {
+		f.running = true
+		go f.run()
+	}
+	return added
+}
+
+// next dequeues the next batch of clients, all of them if size is not
+// positive. It returns nil once the queue is empty.
+func (f *fanout) next(size int) []net.Conn {
+	f.Lock()
+	defer f.Unlock()
+
+	if size <= 0 || size > len(f.queue) {
+		size = len(f.queue)
+	}
+	if size == 0 {
+		f.running = false
+		return nil
+	}
+	batch := append([]net.Conn(nil), f.queue[:size]...)
+	f.queue = f.queue[size:]
+	for _, c := range batch {
+		delete(f.queued, c)
+	}
+	notifyQueued.Set(int64(len(f.queue)))
+	return batch
+}
+
+// run notifies the queued clients until the queue is empty
+func (f *fanout) run() {
+	p := sunrpc.ProcedureID{
+		ProgramNumber:   glusterCbkProgram,
+		ProgramVersion:  glusterCbkVersion,
+		ProcedureNumber: uint32(gfCbkFetchSpec),
+	}
+	for {
+		time.Sleep(jitter(config.GetDuration(notifyBatchIntervalOpt)))
+		batch := f.next(config.GetInt(notifyBatchSizeOpt))
+		if batch == nil {
+			return
+		}
+
+		var wg sync.WaitGroup
+		for _, conn := range batch {
+			clientsList.RLock()
+			ci, ok := clientsList.c[conn]
+			clientsList.RUnlock()
+			if !ok {
+				// disconnected since queued, the client fetches
+				// its volfiles on reconnecting
+				continue
+			}
+
+			wg.Add(1)
+			go func(c net.Conn, ci *clientInfo) {
+				defer wg.Done()
+				err := callbacks.call(c, p, nil, false, notifyTimeout)
+				ci.recordNotify(err)
+				if err != nil {
+					log.WithError(err).WithFields(log.Fields{
+						"client":    c.RemoteAddr().String(),
+						"procedure": gfCbkFetchSpec,
+					}).Warn("Failed to notify RPC client")
+				}
+			}(conn, ci)
+		}
+		wg.Wait()
+	}
+}
+
+// jitter returns a random duration within half of d either way
+func jitter(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d)))
+}
+
+// VolfilesChangeNotify queues the clients connected to glusterd which may be
+// using the volfiles of any of the volumes, to be notified in batches that
+// the volfiles changed. It is used when the volfiles of many volumes change
+// at once, and returns the number of clients queued. checksums are the
+// checksums of the volfiles of the volumes by volfile ID, the client
+// volfiles by volume name: the clients served these volfiles already are
+// not notified.
+func VolfilesChangeNotify(logger log.FieldLogger, volnames []string, checksums map[string]string) int {
+	var conns []net.Conn
+	clientsList.RLock()
+	for conn, ci := range clientsList.c {
+		if ci.volfilesChanged(volnames, checksums) {
+			conns = append(conns, conn)
+		}
+	}
+	clientsList.RUnlock()
+
+	queued := volfileFanout.add(conns)
+	logger.WithFields(log.Fields{
+		"volumes": len(volnames),
+		"clients": len(conns),
+		"queued":  queued,
+	}).Info("queued the RPC clients to notify of the volfile changes")
+	return queued
+}
@@ -0,0 +1,41 @@
+package sunrpc
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFanoutBatches(t *testing.T) {
+	// keep the queue from being drained by run()
+	f := &fanout{queued: make(map[net.Conn]struct{}), running: true}
+
+	var conns []net.Conn
+	for i := 0; i < 5; i++ {
+		server, client := net.Pipe()
+		defer server.Close()
+		defer client.Close()
+		conns = append(conns, server)
+	}
+
+	assert.Equal(t, 3, f.add(conns[:3]))
+	// queued clients keep their place
+	assert.Equal(t, 2, f.add(conns))
+
+	assert.Equal(t, conns[:2], f.next(2))
+	assert.Equal(t, conns[2:4], f.next(2))
+	assert.Equal(t, 1, f.add(conns[:1]))
+	assert.Equal(t, []net.Conn{conns[4], conns[0]}, f.next(0))
+	assert.Nil(t, f.next(2))
+	assert.False(t, f.running)
+}
+
+func TestJitter(t *testing.T) {
+	assert.Equal(t, time.Duration(0), jitter(0))
+	for i := 0; i < 100; i++ {
+		d := jitter(time.Second)
+		assert.True(t, d >= 500*time.Millisecond && d < 1500*time.Millisecond)
+	}
+}
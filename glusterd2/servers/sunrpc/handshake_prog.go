@@ -2,6 +2,7 @@ package sunrpc
 
 import (
 	"errors"
+	"fmt"
 	"io/ioutil"
 	"net"
 	"os"
@@ -10,11 +11,15 @@ import (
 	"strings"
 	"syscall"
 
+	"github.com/gluster/glusterd2/glusterd2/brickhealth"
 	"github.com/gluster/glusterd2/glusterd2/servers/sunrpc/dict"
 	"github.com/gluster/glusterd2/glusterd2/snapshot"
+	"github.com/gluster/glusterd2/glusterd2/upcall"
 	"github.com/gluster/glusterd2/glusterd2/volgen"
 	"github.com/gluster/glusterd2/glusterd2/volume"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
 	"github.com/gluster/glusterd2/pkg/sunrpc"
+	"github.com/gluster/glusterd2/plugins/gfproxy"
 	"github.com/gluster/glusterd2/plugins/rebalance"
 
 	log "github.com/sirupsen/logrus"
@@ -34,6 +39,12 @@ const (
 )
 const (
 	gfEventNotifyDefragStatus = 0
+	// sent by bricks whose posix health-check failed
+	gfEventNotifyBrickHealthCheck = 1
+	// sent by bricks to relay a cache invalidation to the clients
+	gfEventNotifyUpcall = 2
+	// sent by bricks to report the I/O errors of their disk
+	gfEventNotifyBrickIOError = 3
 )
 
 var volfilePrefix = "volfiles/"
@@ -117,13 +128,15 @@ func (p *GfHandshake) ServerGetspec(args *GfGetspecReq, reply *GfGetspecRsp) err
 		err      error
 		addrs    []string
 		respDict map[string]string
+		reqDict  map[string]string
 		volinfo  *volume.Volinfo
 	)
 
-	_, err = dict.Unserialize(args.Xdata)
+	reqDict, err = dict.Unserialize(args.Xdata)
 	if err != nil {
 		log.WithError(err).Error("ServerGetspec(): dict.Unserialize() failed")
 	}
+	trackProcess(p.GetConn(), reqDict)
 
 	log.WithFields(log.Fields{
 		"client":     p.GetConn().RemoteAddr().String(),
@@ -132,6 +145,21 @@ func (p *GfHandshake) ServerGetspec(args *GfGetspecReq, reply *GfGetspecRsp) err
 
 	// Get Volfile from store
 	volfileID := strings.TrimPrefix(args.Key, "/")
+	refetch := servedBefore(p.GetConn(), volfileID)
+	if !getspecAdmission.admit(refetch, config.GetInt(getspecMaxInflightOpt), config.GetInt(getspecMaxQueuedOpt)) {
+		log.WithFields(log.Fields{
+			"client":     p.GetConn().RemoteAddr().String(),
+			"volfile-id": volfileID,
+		}).Info("too many volfile requests, refused the client fetching the volfile again, it is notified again later")
+		// The client keeps running with the volfile it has
+		volfileFanout.add([]net.Conn{p.GetConn()})
+		reply.OpRet = -1
+		reply.OpErrno = int(syscall.EAGAIN)
+		return nil
+	}
+	defer getspecAdmission.done()
+
+	trackVolfile(p.GetConn(), volfileID)
 	volfile := path.Join(config.GetString("localstatedir"), "volfiles", volfileID+".vol")
 	content, err := ioutil.ReadFile(volfile)
 	if err != nil && !os.IsNotExist(err) {
@@ -148,6 +176,26 @@ func (p *GfHandshake) ServerGetspec(args *GfGetspecReq, reply *GfGetspecRsp) err
 		// Reset error due to Volfile not exists
 		err = nil
 
+		// Volfiles of the gfproxy daemons and of the thin clients
+		// connecting to them
+		if volname, ok := gfproxy.VolumeOfVolfileID(volfileID); ok {
+			volinfo, err = volume.GetVolume(volname)
+			if err != nil {
+				log.WithError(err).WithField(
+					"volfile", volfileID,
+				).Error("failed to get volume info")
+				goto Out
+			}
+			reply.Spec, err = gfproxy.Volfile(volinfo, volfileID)
+			if err != nil {
+				log.WithError(err).WithField(
+					"volfile", volfileID,
+				).Error("failed to generate gfproxy volfile")
+				goto Out
+			}
+			goto Found
+		}
+
 		if strings.HasPrefix(volfileID, "snaps/") {
 			snapname := strings.Replace(volfileID, "snaps/", "", -1)
 			snapvol, err := snapshot.GetSnapshot(snapname)
@@ -158,6 +206,24 @@ func (p *GfHandshake) ServerGetspec(args *GfGetspecReq, reply *GfGetspecRsp) err
 				goto Out
 			}
 			volinfo = &snapvol.SnapVolinfo
+		} else if volname, subdir, ok := volume.SplitSubdirVolfileID(volfileID); ok {
+			// Clients mounting an exported subdirectory get the
+			// volfile of the volume, the bricks enforce the
+			// restrictions on the clients of the subdirectory
+			volinfo, err = volume.GetVolume(volname)
+			if err != nil {
+				log.WithError(err).WithField(
+					"volfile", volfileID,
+				).Error("failed to get volume info")
+				goto Out
+			}
+			if !volinfo.IsSubdirExported(subdir) {
+				err = gderrors.ErrSubdirNotExported
+				log.WithError(err).WithField(
+					"volfile", volfileID,
+				).Error("client requested the volfile of a subdirectory which is not exported")
+				goto Out
+			}
 		} else {
 			volinfo, err = volume.GetVolume(volfileID)
 			if err != nil {
@@ -168,6 +234,19 @@ func (p *GfHandshake) ServerGetspec(args *GfGetspecReq, reply *GfGetspecRsp) err
 			}
 		}
 
+		if _, opVersion := opVersions(reqDict); !ClientSupported(volinfo, opVersion) {
+			err = fmt.Errorf("client supports op-versions up to %d, volume %s requires %d",
+				opVersion, volinfo.Name, MinClientOpVersion(volinfo))
+			log.WithError(err).WithFields(log.Fields{
+				"client":     p.GetConn().RemoteAddr().String(),
+				"volfile-id": volfileID,
+			}).Warn("refused the volfile to a client below the minimum op-version of the volume")
+			// The client fails to mount with "Operation not
+			// supported"
+			reply.OpErrno = int(syscall.ENOTSUP)
+			goto Out
+		}
+
 		tmpl, err := volgen.GetTemplateFromVolinfo(volinfo, "client")
 		if err != nil {
 			log.WithError(err).WithField(
@@ -185,8 +264,10 @@ func (p *GfHandshake) ServerGetspec(args *GfGetspecReq, reply *GfGetspecRsp) err
 		}
 	}
 
+Found:
 	reply.OpRet = len(reply.Spec)
 	reply.OpErrno = 0
+	trackServedVolfile(p.GetConn(), volfileID, volgen.Checksum(reply.Spec))
 
 	if (args.Flags & gfGetspecFlagServersList) != 0 {
 
@@ -230,7 +311,6 @@ func (p *GfHandshake) ServerGetspec(args *GfGetspecReq, reply *GfGetspecRsp) err
 Out:
 	if err != nil {
 		reply.OpRet = -1
-		reply.OpErrno = 0
 	}
 
 	return nil
@@ -319,8 +399,10 @@ Out:
 	return nil
 }
 
-// GfServerEventNotifyReq is sent by the rebalance process before it terminates
-// and contains the status information in a dict
+// GfServerEventNotifyReq is sent by the rebalance process before it terminates,
+// by bricks whose posix health-check failed, by bricks reporting I/O errors
+// and by bricks relaying cache invalidations, and contains the status
+// information in a dict
 type GfServerEventNotifyReq struct {
 	Op   int
 	Dict []byte
@@ -334,6 +416,7 @@ type GfServerEventNotifyResp struct {
 }
 
 //ServerEventNotify processes the status information sent by the rebalance process
+//and the bricks
 func (p *GfHandshake) ServerEventNotify(args *GfServerEventNotifyReq, reply *GfServerEventNotifyResp) error {
 
 	var (
@@ -357,6 +440,54 @@ func (p *GfHandshake) ServerEventNotify(args *GfServerEventNotifyReq, reply *GfS
 			goto Out
 		}
 
+	case gfEventNotifyBrickHealthCheck:
+		reqDict, err := dict.Unserialize(args.Dict)
+		if err != nil {
+			log.WithError(err).Error("dict unserialize failed")
+			reply.OpRet = -1
+			reply.OpErrno = int(syscall.EINVAL)
+			goto Out
+		}
+		err = brickhealth.HandleHealthCheckFailure(reqDict)
+		if err != nil {
+			log.WithError(err).Error("failed to handle brick health-check failure")
+			reply.OpRet = -1
+			reply.OpErrno = int(syscall.EINVAL)
+			goto Out
+		}
+
+	case gfEventNotifyBrickIOError:
+		reqDict, err := dict.Unserialize(args.Dict)
+		if err != nil {
+			log.WithError(err).Error("dict unserialize failed")
+			reply.OpRet = -1
+			reply.OpErrno = int(syscall.EINVAL)
+			goto Out
+		}
+		err = brickhealth.HandleIOErrors(reqDict)
+		if err != nil {
+			log.WithError(err).Error("failed to handle brick I/O errors")
+			reply.OpRet = -1
+			reply.OpErrno = int(syscall.EINVAL)
+			goto Out
+		}
+
+	case gfEventNotifyUpcall:
+		reqDict, err := dict.Unserialize(args.Dict)
+		if err != nil {
+			log.WithError(err).Error("dict unserialize failed")
+			reply.OpRet = -1
+			reply.OpErrno = int(syscall.EINVAL)
+			goto Out
+		}
+		err = upcall.HandleEventNotify(reqDict)
+		if err != nil {
+			log.WithError(err).Error("failed to relay cache invalidation")
+			reply.OpRet = -1
+			reply.OpErrno = int(syscall.EINVAL)
+			goto Out
+		}
+
 	default:
 		log.WithError(err).Error("Unknown op received in event notify")
 		reply.OpRet = -1
@@ -0,0 +1,24 @@
+package sunrpc
+
+import "syscall"
+
+// lockFile takes an exclusive lock on the file, creating it if needed, so
+// that only one glusterd2 serves the socket file. The lock is held until
+// the returned file descriptor is passed to unlockFile.
+func lockFile(path string) (int, error) {
+	fd, err := syscall.Open(path, syscall.O_CREAT|syscall.O_WRONLY|syscall.O_CLOEXEC, 0666)
+	if err != nil {
+		return -1, err
+	}
+
+	if err := syscall.Flock(fd, syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		syscall.Close(fd)
+		return -1, err
+	}
+	return fd, nil
+}
+
+// unlockFile releases the lock taken with lockFile
+func unlockFile(fd int) error {
+	return syscall.Close(fd)
+}
@@ -9,8 +9,9 @@ import (
 	"os"
 	"path"
 	"sync"
-	"syscall"
+	"sync/atomic"
 
+	"github.com/gluster/glusterd2/glusterd2/events"
 	"github.com/gluster/glusterd2/glusterd2/pmap"
 	"github.com/gluster/glusterd2/pkg/sunrpc"
 
@@ -26,10 +27,52 @@ var (
 	clientCount = expvar.NewInt("sunrpc_clients_connected")
 )
 
-var programsList = []sunrpc.Program{
-	newGfHandshake(),
-	newGfDump(),
-	pmap.NewGfPortmap(),
+var programs = struct {
+	sync.RWMutex
+	list []sunrpc.Program
+}{
+	list: []sunrpc.Program{
+		newGfHandshake(),
+		newGfDump(),
+		pmap.NewGfPortmap(),
+	},
+}
+
+// RegisterProgram adds a program to the ones served by the SunRPC server.
+// Clients connecting from then on can call its procedures.
+func RegisterProgram(prog sunrpc.Program) error {
+	if err := registerProcedures(prog); err != nil {
+		return err
+	}
+
+	programs.Lock()
+	defer programs.Unlock()
+	programs.list = append(programs.list, prog)
+	return nil
+}
+
+// UnregisterProgram removes a program from the ones served by the SunRPC
+// server. Calls to its procedures fail from then on, for the clients
+// already connected as well.
+func UnregisterProgram(prog sunrpc.Program) {
+	programs.Lock()
+	defer programs.Unlock()
+
+	for i, p := range programs.list {
+		if p.Number() == prog.Number() && p.Version() == prog.Version() {
+			programs.list = append(programs.list[:i], programs.list[i+1:]...)
+			break
+		}
+	}
+	for _, procedure := range prog.Procedures() {
+		sunrpc.RemoveProcedure(procedure.ID)
+	}
+}
+
+func programsList() []sunrpc.Program {
+	programs.RLock()
+	defer programs.RUnlock()
+	return append([]sunrpc.Program(nil), programs.list...)
 }
 
 // SunRPC implements a suture service
@@ -40,16 +83,27 @@ type SunRPC struct {
 	unixStopCh    chan struct{}
 	notifyCloseCh chan io.ReadWriteCloser
 	lockFileFd    int
+	upcallRelay   events.HandlerID
 }
 
 // clientsList is global as it needs to be accessed by RPC procedures
 // that notify connected clients.
 var clientsList = struct {
 	sync.RWMutex
-	c map[net.Conn]struct{}
+	c map[net.Conn]*clientInfo
 }{
-	// This map is used as a set. Values are not consumed.
-	c: make(map[net.Conn]struct{}),
+	c: make(map[net.Conn]*clientInfo),
+}
+
+// shuttingDown is set once glusterd2 shuts down, for the connections
+// accepted from then on to be closed
+var shuttingDown int32
+
+// ClientsCount returns the number of clients connected to the SunRPC server
+func ClientsCount() int {
+	clientsList.RLock()
+	defer clientsList.RUnlock()
+	return len(clientsList.c)
 }
 
 // NewMuxed returns a SunRPC server configured to listen on a CMux multiplexed connection
@@ -57,15 +111,9 @@ func NewMuxed(m cmux.CMux) *SunRPC {
 
 	f := path.Join(config.GetString("rundir"), gd2SocketFile)
 	gd2LockFile := f + ".lock"
-	fd, err := syscall.Open(gd2LockFile,
-		syscall.O_CREAT|syscall.O_WRONLY|syscall.O_CLOEXEC, 0666)
-	if err != nil {
-		log.WithError(err).WithField("lockfile", gd2LockFile).Fatal("failed to open lock file")
-	}
-
-	err = syscall.Flock(fd, syscall.LOCK_EX|syscall.LOCK_NB)
+	fd, err := lockFile(gd2LockFile)
 	if err != nil {
-		log.WithError(err).WithField("socket", gd2SocketFile).Fatal("failed to get lock")
+		log.WithError(err).WithField("lockfile", gd2LockFile).Fatal("failed to get lock")
 	}
 
 	err = os.Remove(f)
@@ -90,7 +138,7 @@ func NewMuxed(m cmux.CMux) *SunRPC {
 		lockFileFd:    fd,
 	}
 
-	for _, prog := range programsList {
+	for _, prog := range programsList() {
 		err := registerProcedures(prog)
 		if err != nil {
 			log.WithError(err).WithField("program", prog.Name()).Error("could not register SunRPC program")
@@ -98,6 +146,8 @@ func NewMuxed(m cmux.CMux) *SunRPC {
 		}
 	}
 
+	srv.upcallRelay = registerUpcallRelay()
+
 	return srv
 }
 
@@ -112,6 +162,7 @@ func (s *SunRPC) pruneConn() {
 		delete(clientsList.c, conn)
 		pmap.ProcessDisconnect(conn)
 		clientsList.Unlock()
+		callbacks.dropConn(conn)
 
 		clientCount.Add(-1)
 	}
@@ -144,11 +195,15 @@ func (s *SunRPC) acceptLoop(stopCh chan struct{}, l net.Listener, wg *sync.WaitG
 		if err != nil {
 			continue
 		}
+		if atomic.LoadInt32(&shuttingDown) == 1 {
+			conn.Close()
+			continue
+		}
 
 		logger.WithField("address", conn.RemoteAddr().String()).Info("client connected")
 		clientCount.Add(1)
 		clientsList.Lock()
-		clientsList.c[conn] = struct{}{}
+		clientsList.c[conn] = newClientInfo()
 		clientsList.Unlock()
 
 		// Create one rpc.Server instance per client. This is a
@@ -157,7 +212,7 @@ func (s *SunRPC) acceptLoop(stopCh chan struct{}, l net.Listener, wg *sync.WaitG
 		// https://groups.google.com/d/msg/golang-nuts/Gt-1ikXovCA/aK8r9MAftDQJ
 		server := rpc.NewServer()
 
-		for _, p := range programsList {
+		for _, p := range programsList() {
 			if v, ok := p.(Conn); ok {
 				v.SetConn(conn)
 			}
@@ -174,7 +229,7 @@ func (s *SunRPC) acceptLoop(stopCh chan struct{}, l net.Listener, wg *sync.WaitG
 		//   1) Run the rpc server, and when the server terminates, close sessionCh to terminate goroutine#2
 		//   2) Wait on sessionCh and stopCh, close the session and return if either comes. session.Close should
 		//      terminate #1
-		session := sunrpc.NewServerCodec(conn, s.notifyCloseCh)
+		session := sunrpc.NewServerCodecWithReplies(conn, s.notifyCloseCh, callbacks.handleReply)
 		sessionCh := make(chan struct{})
 		go func() {
 			defer close(sessionCh)
@@ -193,6 +248,11 @@ func (s *SunRPC) acceptLoop(stopCh chan struct{}, l net.Listener, wg *sync.WaitG
 	}
 }
 
+// String returns the name of the service in the supervisor tree
+func (s *SunRPC) String() string {
+	return "sunrpc"
+}
+
 // Serve will start accepting Sun RPC client connections on the listener
 // provided.
 func (s *SunRPC) Serve() {
@@ -215,8 +275,9 @@ func (s *SunRPC) Serve() {
 func (s *SunRPC) Stop() {
 	close(s.tcpStopCh)
 	close(s.unixStopCh)
+	events.Unregister(s.upcallRelay)
 
 	// Close UDS listener; cmux should take care of the TCP one.
 	s.unixListener.Close()
-	syscall.Close(s.lockFileFd)
+	unlockFile(s.lockFileFd)
 }
@@ -0,0 +1,26 @@
+package sunrpc
+
+import (
+	"github.com/gluster/glusterd2/glusterd2/events"
+	"github.com/gluster/glusterd2/glusterd2/upcall"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// relayCacheInvalidation notifies the clients of the volume connected to
+// this peer of the cache invalidation sent by a brick of any peer
+func relayCacheInvalidation(e *api.Event) {
+	inv, err := upcall.ParseEvent(e)
+	if err != nil {
+		log.WithError(err).WithField("event.id", e.ID.String()).Warn("invalid cache invalidation event")
+		return
+	}
+	CacheInvalidationNotify(log.WithField("server", "sunrpc"), inv.Volume, inv.Gfid, inv.Flags)
+}
+
+// registerUpcallRelay relays the cache invalidations broadcast by the peers
+// to the clients connected to this peer
+func registerUpcallRelay() events.HandlerID {
+	return events.Register(events.NewHandler(relayCacheInvalidation, upcall.EventCacheInvalidation))
+}
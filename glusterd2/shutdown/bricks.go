@@ -0,0 +1,41 @@
+package shutdown
+
+import (
+	"context"
+	"syscall"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/daemon"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// StopBricks stops the local bricks of the started volumes. They are kept in
+// the store of daemons, so that glusterd2 starts them again when it is
+// started. Multiplexed bricks share processes, stopped along with their first
+// brick.
+func StopBricks(ctx context.Context) {
+	volumes, err := volume.GetVolumes(ctx)
+	if err != nil {
+		log.WithError(err).Error("failed to get the volumes, the bricks are left running")
+		return
+	}
+
+	for _, v := range volumes {
+		if v.State != volume.VolStarted {
+			continue
+		}
+		for _, b := range v.GetLocalBricks() {
+			logger := log.WithFields(log.Fields{"volume": v.Name, "brick": b.String()})
+			d, err := brick.NewGlusterfsd(b)
+			if err != nil {
+				logger.WithError(err).Warn("failed to stop brick")
+				continue
+			}
+			if err := daemon.Signal(d, syscall.SIGTERM, logger); err != nil {
+				logger.WithError(err).Debug("failed to stop brick")
+			}
+		}
+	}
+}
@@ -0,0 +1,79 @@
+// Package shutdown runs the shutdown of glusterd2 as an ordered sequence of
+// steps, sharing a grace period.
+package shutdown
+
+import (
+	"context"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	flag "github.com/spf13/pflag"
+	config "github.com/spf13/viper"
+)
+
+const (
+	gracePeriodOpt = "shutdown-grace-period"
+	stopBricksOpt  = "shutdown-stop-bricks"
+
+	defaultGracePeriod = 30 * time.Second
+)
+
+// forceTimeout is the time given to each step run once the grace period is
+// over, past which the next step is run
+const forceTimeout = 5 * time.Second
+
+// Step is a step of the shutdown of glusterd2
+type Step struct {
+	Name string
+	// Run runs the step. A step waiting for the work in progress to end
+	// stops waiting once ctx is done.
+	Run func(ctx context.Context)
+}
+
+// InitFlags intializes the command line options for the shutdown
+func InitFlags() {
+	flag.Duration(gracePeriodOpt, defaultGracePeriod, "Time given to the requests and transactions in progress to end when glusterd2 shuts down, past which they are failed.")
+	flag.Bool(stopBricksOpt, false, "Stop the bricks of this peer when glusterd2 shuts down, instead of leaving them running. They are started again with glusterd2.")
+}
+
+// StopBricksEnabled returns true if the bricks of this peer are stopped when
+// glusterd2 shuts down
+func StopBricksEnabled() bool {
+	return config.GetBool(stopBricksOpt)
+}
+
+// Run runs the steps in order, within shutdown-grace-period
+func Run(steps ...Step) {
+	run(config.GetDuration(gracePeriodOpt), steps)
+}
+
+// run runs the steps in order, sharing the grace period. Once the grace
+// period is over, each step is given forceTimeout, past which it is left
+// running and the next step is run.
+func run(grace time.Duration, steps []Step) {
+	ctx, cancel := context.WithTimeout(context.Background(), grace)
+	defer cancel()
+
+	for _, s := range steps {
+		logger := log.WithField("step", s.Name)
+		start := time.Now()
+
+		done := make(chan struct{})
+		go func(s Step) {
+			defer close(done)
+			s.Run(ctx)
+		}(s)
+
+		select {
+		case <-done:
+		case <-ctx.Done():
+			select {
+			case <-done:
+			case <-time.After(forceTimeout):
+				logger.Warn("shutdown step did not end in time, leaving it running")
+				continue
+			}
+		}
+		logger.WithField("duration", time.Since(start)).Debug("shutdown step done")
+	}
+}
@@ -0,0 +1,35 @@
+package shutdown
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun(t *testing.T) {
+	var ran []string
+	step := func(name string) Step {
+		return Step{Name: name, Run: func(ctx context.Context) {
+			ran = append(ran, name)
+		}}
+	}
+
+	run(time.Second, []Step{step("rest"), step("transactions"), step("store")})
+	assert.Equal(t, []string{"rest", "transactions", "store"}, ran)
+
+	// A step waiting for work in progress stops once the grace period is
+	// over, and the next steps still run
+	ran = nil
+	start := time.Now()
+	run(50*time.Millisecond, []Step{
+		{Name: "transactions", Run: func(ctx context.Context) {
+			<-ctx.Done()
+			ran = append(ran, "transactions")
+		}},
+		step("store"),
+	})
+	assert.Equal(t, []string{"transactions", "store"}, ran)
+	assert.True(t, time.Since(start) < forceTimeout)
+}
@@ -0,0 +1,140 @@
+// Package simulate hosts virtual peers in glusterd2, so that the transaction
+// engine, the placement of bricks and the REST API can be exercised on a
+// cluster of many peers with a single glusterd2, in development and in CI.
+//
+// The virtual peers are peers of the cluster as any other, with their own
+// keys in the store, but their steps are run by the glusterd2 hosting them
+// and their bricks are not started.
+package simulate
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	"github.com/gluster/glusterd2/glusterd2/store"
+
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+	flag "github.com/spf13/pflag"
+	config "github.com/spf13/viper"
+)
+
+const (
+	peersOpt = "simulated-peers"
+
+	// HostKey is the metadata key of the virtual peers, set to the ID of
+	// the peer hosting them
+	HostKey = "_simulated_by"
+)
+
+var (
+	// self is the ID of this peer, as gdctx.MyUUID is changed while
+	// steps run as the virtual peers
+	self  uuid.UUID
+	peers []uuid.UUID
+
+	// runMu serializes the steps run as the hosted peers
+	runMu sync.Mutex
+)
+
+// InitFlags intializes the command line options for the simulated peers
+func InitFlags() {
+	flag.Int(peersOpt, 0, "Number of virtual peers to host, for development and CI. The bricks of a glusterd2 hosting virtual peers are not started.")
+}
+
+// peerID returns the ID of the nth virtual peer hosted by the peer of the
+// given ID, the same on every start
+func peerID(host uuid.UUID, n int) uuid.UUID {
+	return uuid.NewSHA1(host, []byte("simulated-peer-"+strconv.Itoa(n)))
+}
+
+// Start adds the virtual peers to the cluster, or updates them, and
+// publishes their liveness along with the liveness of this peer. It is to
+// be called once the details of this peer are in the store.
+func Start() error {
+	n := config.GetInt(peersOpt)
+	if n <= 0 {
+		return nil
+	}
+
+	me, err := peer.GetPeer(gdctx.MyUUID.String())
+	if err != nil {
+		return err
+	}
+
+	ids := make([]uuid.UUID, 0, n)
+	for i := 1; i <= n; i++ {
+		p := &peer.Peer{
+			ID:              peerID(me.ID, i),
+			Name:            fmt.Sprintf("%s-sim%d", me.Name, i),
+			PeerAddresses:   me.PeerAddresses,
+			ClientAddresses: me.ClientAddresses,
+			Version:         me.Version,
+			MaxOpVersion:    me.MaxOpVersion,
+		}
+		// The metadata and zone set by the users are kept
+		if old, err := peer.GetPeer(p.ID.String()); err == nil && old.Metadata != nil {
+			p.Metadata = old.Metadata
+		} else {
+			p.Metadata = map[string]string{"_zone": p.ID.String()}
+		}
+		p.Metadata[HostKey] = me.ID.String()
+
+		if err := peer.AddOrUpdatePeer(p); err != nil {
+			return err
+		}
+		ids = append(ids, p.ID)
+	}
+
+	if err := store.Store.HostPeers(ids...); err != nil {
+		return err
+	}
+
+	self = me.ID
+	peers = ids
+	log.WithField("peers", n).Warn("hosting virtual peers, bricks will not be started")
+	return nil
+}
+
+// Enabled returns true if this glusterd2 hosts virtual peers
+func Enabled() bool {
+	return len(peers) != 0
+}
+
+// Peers returns the IDs of the virtual peers hosted by this glusterd2
+func Peers() []uuid.UUID {
+	return peers
+}
+
+// Hosts returns true if virtual peers are hosted, and the given peer is this
+// peer or one of them. The steps of such peers are run with RunAs.
+func Hosts(id uuid.UUID) bool {
+	if !Enabled() {
+		return false
+	}
+	if uuid.Equal(id, self) {
+		return true
+	}
+	for _, p := range peers {
+		if uuid.Equal(id, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// RunAs runs f as the hosted peer of the given ID, with gdctx.MyUUID set to
+// it. The functions run as the hosted peers are run one after the other, and
+// must hence not wait for functions run as another hosted peer.
+func RunAs(id uuid.UUID, f func() error) error {
+	runMu.Lock()
+	defer runMu.Unlock()
+
+	gdctx.MyUUID = id
+	defer func() { gdctx.MyUUID = self }()
+
+	return f()
+}
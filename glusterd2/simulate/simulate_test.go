@@ -0,0 +1,43 @@
+package simulate
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPeerID(t *testing.T) {
+	host := uuid.NewRandom()
+
+	assert.Equal(t, peerID(host, 1), peerID(host, 1))
+	assert.NotEqual(t, peerID(host, 1), peerID(host, 2))
+	assert.NotEqual(t, peerID(host, 1), peerID(uuid.NewRandom(), 1))
+}
+
+func TestRunAs(t *testing.T) {
+	defer func(id uuid.UUID) { gdctx.MyUUID = id }(gdctx.MyUUID)
+	defer func() { self, peers = nil, nil }()
+
+	gdctx.MyUUID = uuid.NewRandom()
+	assert.False(t, Enabled())
+	assert.False(t, Hosts(gdctx.MyUUID))
+
+	self = gdctx.MyUUID
+	peers = []uuid.UUID{peerID(self, 1), peerID(self, 2)}
+	assert.True(t, Enabled())
+	assert.True(t, Hosts(self))
+	assert.True(t, Hosts(peers[1]))
+	assert.False(t, Hosts(uuid.NewRandom()))
+
+	errFailed := errors.New("failed")
+	err := RunAs(peers[1], func() error {
+		assert.Equal(t, peers[1], gdctx.MyUUID)
+		return errFailed
+	})
+	assert.Equal(t, errFailed, err)
+	assert.Equal(t, self, gdctx.MyUUID)
+}
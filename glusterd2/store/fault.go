@@ -0,0 +1,51 @@
+package store
+
+import (
+	"github.com/gluster/glusterd2/glusterd2/fault"
+
+	"github.com/coreos/etcd/clientv3"
+	pb "github.com/coreos/etcd/etcdserver/etcdserverpb"
+)
+
+// faultTxn is a store transaction in which the faults set for the writes to
+// its keys are injected
+type faultTxn struct {
+	clientv3.Txn
+	ops []clientv3.Op
+}
+
+func (t *faultTxn) If(cs ...clientv3.Cmp) clientv3.Txn {
+	t.Txn = t.Txn.If(cs...)
+	return t
+}
+
+func (t *faultTxn) Then(ops ...clientv3.Op) clientv3.Txn {
+	t.Txn = t.Txn.Then(ops...)
+	t.ops = append(t.ops, ops...)
+	return t
+}
+
+func (t *faultTxn) Else(ops ...clientv3.Op) clientv3.Txn {
+	t.Txn = t.Txn.Else(ops...)
+	t.ops = append(t.ops, ops...)
+	return t
+}
+
+// Commit commits the transaction, unless a fault is set for the writes to
+// one of its keys. A transaction dropped by a fault succeeds without
+// changing the store.
+func (t *faultTxn) Commit() (*clientv3.TxnResponse, error) {
+	for _, op := range t.ops {
+		if !op.IsPut() && !op.IsDelete() {
+			continue
+		}
+		drop, err := fault.StoreWrite(string(op.KeyBytes()))
+		if err != nil {
+			return nil, err
+		}
+		if drop {
+			return &clientv3.TxnResponse{Header: &pb.ResponseHeader{}, Succeeded: true}, nil
+		}
+	}
+	return t.Txn.Commit()
+}
@@ -0,0 +1,84 @@
+package store
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// latencySamples is the number of most recent samples retained per
+// operation for computing latency percentiles
+const latencySamples = 1024
+
+// latencyRecorder retains the latency of the most recent store operations
+type latencyRecorder struct {
+	sync.Mutex
+	samples map[string][]time.Duration
+	next    map[string]int
+}
+
+var storeLatency = &latencyRecorder{
+	samples: make(map[string][]time.Duration),
+	next:    make(map[string]int),
+}
+
+// LatencyPercentiles represents the latency percentiles of a store operation
+type LatencyPercentiles struct {
+	Count int
+	P50   time.Duration
+	P90   time.Duration
+	P99   time.Duration
+}
+
+func (l *latencyRecorder) observe(op string, start time.Time) {
+	d := time.Since(start)
+
+	l.Lock()
+	defer l.Unlock()
+
+	if len(l.samples[op]) < latencySamples {
+		l.samples[op] = append(l.samples[op], d)
+		return
+	}
+	l.samples[op][l.next[op]] = d
+	l.next[op] = (l.next[op] + 1) % latencySamples
+}
+
+func (l *latencyRecorder) percentiles() map[string]LatencyPercentiles {
+	l.Lock()
+	defer l.Unlock()
+
+	result := make(map[string]LatencyPercentiles, len(l.samples))
+	for op, samples := range l.samples {
+		sorted := make([]time.Duration, len(samples))
+		copy(sorted, samples)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+		result[op] = LatencyPercentiles{
+			Count: len(sorted),
+			P50:   percentile(sorted, 50),
+			P90:   percentile(sorted, 90),
+			P99:   percentile(sorted, 99),
+		}
+	}
+	return result
+}
+
+// percentile returns the pth percentile of sorted samples using the nearest
+// rank method
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// Latency returns the latency percentiles of the recent store operations
+// done by this node, keyed by operation
+func Latency() map[string]LatencyPercentiles {
+	return storeLatency.percentiles()
+}
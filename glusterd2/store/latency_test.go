@@ -0,0 +1,33 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLatencyPercentiles(t *testing.T) {
+	l := &latencyRecorder{
+		samples: make(map[string][]time.Duration),
+		next:    make(map[string]int),
+	}
+	for i := 1; i <= 100; i++ {
+		l.samples["get"] = append(l.samples["get"], time.Duration(i)*time.Millisecond)
+	}
+
+	p := l.percentiles()["get"]
+	assert.Equal(t, 100, p.Count)
+	assert.Equal(t, 50*time.Millisecond, p.P50)
+	assert.Equal(t, 90*time.Millisecond, p.P90)
+	assert.Equal(t, 99*time.Millisecond, p.P99)
+
+	// Oldest samples are overwritten once the buffer is full
+	for i := 0; i < latencySamples; i++ {
+		l.observe("put", time.Now())
+	}
+	l.observe("put", time.Now().Add(-time.Hour))
+	assert.Equal(t, latencySamples, len(l.samples["put"]))
+	assert.Equal(t, 1, l.next["put"])
+	assert.True(t, l.percentiles()["put"].P99 < time.Hour)
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"path"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
@@ -103,23 +104,59 @@ func (s *GDStore) AreNodesAlive(ctx context.Context, peerIDs ...uuid.UUID) bool
 	return true
 }
 
+// hostedPeers are the virtual peers hosted by this instance, which are alive
+// as long as it is
+var hostedPeers struct {
+	sync.Mutex
+	ids []uuid.UUID
+}
+
+// HostPeers publishes the liveness of the virtual peers of the given IDs
+// along with the liveness of this instance
+func (s *GDStore) HostPeers(ids ...uuid.UUID) error {
+	hostedPeers.Lock()
+	hostedPeers.ids = append(hostedPeers.ids, ids...)
+	hostedPeers.Unlock()
+
+	return s.publishLiveness()
+}
+
+// livenessKeys returns the liveness keys of this instance and of the virtual
+// peers it hosts
+func livenessKeys() []string {
+	hostedPeers.Lock()
+	defer hostedPeers.Unlock()
+
+	keys := []string{LivenessKeyPrefix + gdctx.MyUUID.String()}
+	for _, id := range hostedPeers.ids {
+		keys = append(keys, LivenessKeyPrefix+id.String())
+	}
+	return keys
+}
+
 func (s *GDStore) publishLiveness() error {
 	// publish liveness of this instance into the store
-	key := LivenessKeyPrefix + gdctx.MyUUID.String()
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
-	_, err := s.Put(ctx, key, strconv.Itoa(os.Getpid()), clientv3.WithLease(s.Session.Lease()))
+	for _, key := range livenessKeys() {
+		_, err := s.Put(ctx, key, strconv.Itoa(os.Getpid()), clientv3.WithLease(s.Session.Lease()))
+		if err != nil {
+			return err
+		}
+	}
 
-	return err
+	return nil
 }
 
 func (s *GDStore) revokeLiveness() error {
 	// revoke liveness (to be invoked during graceful shutdowns)
-	key := LivenessKeyPrefix + gdctx.MyUUID.String()
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
+	for _, key := range livenessKeys() {
+		if _, err := s.Delete(ctx, key); err != nil {
+			return err
+		}
+	}
 
-	_, err := s.Delete(ctx, key)
-
-	return err
+	return nil
 }
@@ -0,0 +1,45 @@
+package store
+
+import (
+	"context"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/coreos/etcd/clientv3"
+)
+
+// ModRevision returns the revision of the store the key was last modified
+// at, or 0 if the key does not exist
+func ModRevision(ctx context.Context, key string) (int64, error) {
+	resp, err := Get(ctx, key, clientv3.WithKeysOnly())
+	if err != nil {
+		return 0, err
+	}
+	if len(resp.Kvs) == 0 {
+		return 0, nil
+	}
+	return resp.Kvs[0].ModRevision, nil
+}
+
+// CheckPrecondition returns errors.ErrRevisionMismatch if the request of the
+// context has a precondition, and the resource it changes is at none of the
+// revisions of the precondition. The transactions check it again once they
+// hold their locks, for no other change to slip in between.
+func CheckPrecondition(ctx context.Context) error {
+	p, ok := gdctx.GetReqPrecondition(ctx)
+	if !ok {
+		return nil
+	}
+
+	rev, err := ModRevision(ctx, p.Key)
+	if err != nil {
+		return err
+	}
+	for _, r := range p.Revisions {
+		if r == rev {
+			return nil
+		}
+	}
+	return errors.ErrRevisionMismatch
+}
@@ -10,12 +10,14 @@ import (
 	"sync"
 	"time"
 
+	"github.com/gluster/glusterd2/glusterd2/fault"
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
 	"github.com/gluster/glusterd2/pkg/elasticetcd"
 
 	"github.com/coreos/etcd/clientv3"
 	"github.com/coreos/etcd/clientv3/concurrency"
 	"github.com/coreos/etcd/clientv3/namespace"
+	pb "github.com/coreos/etcd/etcdserver/etcdserverpb"
 	log "github.com/sirupsen/logrus"
 	"go.opencensus.io/trace"
 )
@@ -178,6 +180,11 @@ func (s *GDStore) isStorehealthy() bool {
 	return err == nil
 }
 
+// Healthy returns true if the store is reachable from this node
+func Healthy() bool {
+	return Store.isStorehealthy()
+}
+
 // Close closes the store connections
 func (s *GDStore) Close() {
 	if err := s.revokeLiveness(); err != nil {
@@ -214,6 +221,15 @@ func (s *GDStore) Destroy(deleteNamespace bool) {
 	s.Close()
 }
 
+// EmbeddedURLs returns the client and peer URLs of the etcd server embedded
+// in glusterd2. ok is false if the store is a remote etcd cluster.
+func (s *GDStore) EmbeddedURLs() (curls, purls []string, ok bool) {
+	if s.conf.NoEmbed {
+		return nil, nil, false
+	}
+	return s.conf.CURLs, s.conf.PURLs, true
+}
+
 // UpdateEndpoints updates the configured endpoints and saves them
 func (s *GDStore) UpdateEndpoints() error {
 	if err := s.Sync(s.Ctx()); err != nil {
@@ -274,10 +290,12 @@ func Get(ctx context.Context, key string, opts ...clientv3.OpOption) (*clientv3.
 	} else {
 		var span *trace.Span
 		ctx, span = trace.StartSpan(ctx, "store.Get")
+		span.AddAttributes(trace.StringAttribute("key", key))
 		defer span.End()
 	}
 
 	defer storeCounters.Add("get", 1)
+	defer storeLatency.observe("get", time.Now())
 	return Store.Get(ctx, key, opts...)
 }
 
@@ -288,9 +306,21 @@ func Put(ctx context.Context, key, val string, opts ...clientv3.OpOption) (*clie
 	if ctx == context.TODO() {
 		ctx, cancel = context.WithTimeout(context.Background(), putTimeout*time.Second)
 		defer cancel()
+	} else {
+		var span *trace.Span
+		ctx, span = trace.StartSpan(ctx, "store.Put")
+		span.AddAttributes(trace.StringAttribute("key", key))
+		defer span.End()
+	}
+
+	if drop, err := fault.StoreWrite(key); err != nil {
+		return nil, err
+	} else if drop {
+		return &clientv3.PutResponse{Header: &pb.ResponseHeader{}}, nil
 	}
 
 	defer storeCounters.Add("put", 1)
+	defer storeLatency.observe("put", time.Now())
 	return Store.Put(ctx, key, val, opts...)
 }
 
@@ -301,9 +331,21 @@ func Delete(ctx context.Context, key string, opts ...clientv3.OpOption) (*client
 	if ctx == context.TODO() {
 		ctx, cancel = context.WithTimeout(context.Background(), deleteTimeout*time.Second)
 		defer cancel()
+	} else {
+		var span *trace.Span
+		ctx, span = trace.StartSpan(ctx, "store.Delete")
+		span.AddAttributes(trace.StringAttribute("key", key))
+		defer span.End()
+	}
+
+	if drop, err := fault.StoreWrite(key); err != nil {
+		return nil, err
+	} else if drop {
+		return &clientv3.DeleteResponse{Header: &pb.ResponseHeader{}}, nil
 	}
 
 	defer storeCounters.Add("delete", 1)
+	defer storeLatency.observe("delete", time.Now())
 	return Store.Delete(ctx, key, opts...)
 }
 
@@ -312,5 +354,8 @@ func Txn(ctx context.Context) clientv3.Txn {
 	// can't cancel() here as caller will have to eventually call
 	// clientv3.Txn.Commit()
 	defer storeCounters.Add("txn", 1)
+	if fault.Enabled() {
+		return &faultTxn{Txn: Store.Txn(ctx)}
+	}
 	return Store.Txn(ctx)
 }
@@ -0,0 +1,112 @@
+package store
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/coreos/etcd/clientv3"
+)
+
+// failedWatchesKept is the number of most recent watches which ended with an
+// error retained for statedumps
+const failedWatchesKept = 16
+
+// WatchStatus represents the health of a watch on the store
+type WatchStatus struct {
+	Name      string
+	Key       string
+	Started   time.Time
+	Events    int
+	LastEvent time.Time
+	Ended     time.Time
+	Err       string
+}
+
+// WatchTracker records the health of a watch on the store. The responses
+// received on the watch must be passed to Observe, and Stop must be called
+// once the watch is no longer used.
+type WatchTracker struct {
+	mu     sync.Mutex
+	status WatchStatus
+}
+
+var watches = struct {
+	sync.Mutex
+	active map[*WatchTracker]struct{}
+	failed []WatchStatus
+}{
+	active: make(map[*WatchTracker]struct{}),
+}
+
+// TrackWatch returns a WatchTracker for the watch on the key, reported under
+// the name in the statedumps of this node
+func TrackWatch(name, key string) *WatchTracker {
+	w := &WatchTracker{
+		status: WatchStatus{
+			Name:    name,
+			Key:     key,
+			Started: time.Now(),
+		},
+	}
+
+	watches.Lock()
+	watches.active[w] = struct{}{}
+	watches.Unlock()
+
+	return w
+}
+
+// Observe records a response received on the watch
+func (w *WatchTracker) Observe(resp clientv3.WatchResponse) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.status.Events += len(resp.Events)
+	w.status.LastEvent = time.Now()
+	if err := resp.Err(); err != nil {
+		w.status.Err = err.Error()
+	} else if resp.Canceled {
+		w.status.Err = "watch canceled"
+	}
+}
+
+// Stop marks the watch as ended. A watch which ended with an error is still
+// reported for a while.
+func (w *WatchTracker) Stop() {
+	w.mu.Lock()
+	w.status.Ended = time.Now()
+	status := w.status
+	w.mu.Unlock()
+
+	watches.Lock()
+	defer watches.Unlock()
+
+	delete(watches.active, w)
+	if status.Err == "" {
+		return
+	}
+	watches.failed = append(watches.failed, status)
+	if len(watches.failed) > failedWatchesKept {
+		watches.failed = watches.failed[len(watches.failed)-failedWatchesKept:]
+	}
+}
+
+// Watches returns the status of the active watches on the store, followed by
+// the recent watches which ended with an error
+func Watches() []WatchStatus {
+	watches.Lock()
+	defer watches.Unlock()
+
+	result := make([]WatchStatus, 0, len(watches.active)+len(watches.failed))
+	for w := range watches.active {
+		w.mu.Lock()
+		result = append(result, w.status)
+		w.mu.Unlock()
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Started.Before(result[j].Started)
+	})
+
+	return append(result, watches.failed...)
+}
@@ -0,0 +1,52 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/stretchr/testify/assert"
+)
+
+func findWatch(name string) (WatchStatus, bool) {
+	for _, w := range Watches() {
+		if w.Name == name {
+			return w, true
+		}
+	}
+	return WatchStatus{}, false
+}
+
+func TestWatchTracker(t *testing.T) {
+	w := TrackWatch("test-ok", "test/")
+	w.Observe(clientv3.WatchResponse{Events: make([]*clientv3.Event, 2)})
+	w.Observe(clientv3.WatchResponse{Events: make([]*clientv3.Event, 1)})
+
+	s, ok := findWatch("test-ok")
+	assert.True(t, ok)
+	assert.Equal(t, "test/", s.Key)
+	assert.Equal(t, 3, s.Events)
+	assert.Empty(t, s.Err)
+
+	// Watches ending without an error are no longer reported
+	w.Stop()
+	_, ok = findWatch("test-ok")
+	assert.False(t, ok)
+
+	// Watches ending with an error are
+	w = TrackWatch("test-canceled", "test/")
+	w.Observe(clientv3.WatchResponse{Canceled: true})
+	w.Stop()
+	s, ok = findWatch("test-canceled")
+	assert.True(t, ok)
+	assert.NotEmpty(t, s.Err)
+	assert.False(t, s.Ended.IsZero())
+
+	for i := 0; i < 2*failedWatchesKept; i++ {
+		w = TrackWatch("test-failed", "test/")
+		w.Observe(clientv3.WatchResponse{Canceled: true})
+		w.Stop()
+	}
+	_, ok = findWatch("test-canceled")
+	assert.False(t, ok)
+	assert.Len(t, Watches(), failedWatchesKept)
+}
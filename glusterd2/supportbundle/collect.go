@@ -0,0 +1,206 @@
+package supportbundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/topology"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/logging"
+	"github.com/gluster/glusterd2/pkg/utils"
+	"github.com/gluster/glusterd2/version"
+
+	config "github.com/spf13/viper"
+)
+
+const (
+	// defaultLogSize is the size of the end of each log collected
+	defaultLogSize = 1024 * 1024
+	// maxLogsSize is the largest size of all the logs collected on a peer
+	maxLogsSize = 16 * 1024 * 1024
+)
+
+func init() {
+	RegisterPeerCollector("version.json", collectVersion)
+	RegisterClusterCollector("store.json", func(ctx context.Context) (interface{}, error) {
+		return exportStore()
+	})
+	RegisterClusterCollector("topology.json", func(ctx context.Context) (interface{}, error) {
+		return topology.Build(ctx)
+	})
+	RegisterClusterCollector("topology.dot", func(ctx context.Context) (interface{}, error) {
+		t, err := topology.Build(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return []byte(t.DOT()), nil
+	})
+}
+
+// peerVersion are the versions of the software running on a peer
+type peerVersion struct {
+	Hostname         string `json:"hostname"`
+	GlusterdVersion  string `json:"glusterd-version"`
+	GitSHA           string `json:"git-sha"`
+	APIVersion       int    `json:"api-version"`
+	OpVersion        int    `json:"op-version"`
+	GoVersion        string `json:"go-version"`
+	GlusterfsVersion string `json:"glusterfs-version,omitempty"`
+}
+
+func collectVersion(ctx context.Context) (interface{}, error) {
+	v := peerVersion{
+		Hostname:        gdctx.HostName,
+		GlusterdVersion: version.GlusterdVersion,
+		GitSHA:          version.GitSHA,
+		APIVersion:      version.APIVersion,
+		OpVersion:       gdctx.OpVersion,
+		GoVersion:       runtime.Version(),
+	}
+	if out, err := utils.ExecuteCommandOutput("glusterfsd", "--version"); err == nil {
+		v.GlusterfsVersion = strings.SplitN(string(out), "\n", 2)[0]
+	}
+	return v, nil
+}
+
+// tail returns the last size bytes of the file, from the start of a line
+func tail(path string, size int64) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() <= size {
+		return ioutil.ReadAll(f)
+	}
+
+	if _, err := f.Seek(info.Size()-size, io.SeekStart); err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadAll(io.LimitReader(f, size))
+	if err != nil {
+		return nil, err
+	}
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		data = data[i+1:]
+	}
+	return data, nil
+}
+
+// logFiles returns the logs of glusterd2, of the store and of the glusterfs
+// processes on this peer, by their names in the support bundles
+func logFiles() map[string]string {
+	files := make(map[string]string)
+	dir := config.GetString("logdir")
+	filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil || !info.Mode().IsRegular() || !strings.HasSuffix(p, ".log") {
+			return nil
+		}
+		rel, _ := filepath.Rel(dir, p)
+		files["logs/"+filepath.ToSlash(rel)] = p
+		return nil
+	})
+
+	// the log of glusterd2 may be outside of the log directory
+	if p := logging.LogFile(); p != "" && !strings.HasPrefix(p, dir+string(filepath.Separator)) {
+		files["logs/"+filepath.Base(p)] = p
+	}
+	return files
+}
+
+// addLogs adds the ends of the logs of this peer to the archive, up to
+// maxLogsSize in all. It returns the logs which could not be collected.
+func addLogs(tw *tar.Writer, size int64) ([]string, error) {
+	if size <= 0 {
+		size = defaultLogSize
+	}
+
+	files := logFiles()
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sortLogs(names)
+
+	var failures []string
+	var total int64
+	for _, name := range names {
+		if total >= maxLogsSize {
+			failures = append(failures, fmt.Sprintf("%s: left out, the logs collected reached %d bytes", name, maxLogsSize))
+			continue
+		}
+		n := size
+		if n > maxLogsSize-total {
+			n = maxLogsSize - total
+		}
+		data, err := tail(files[name], n)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %s", name, err))
+			continue
+		}
+		if err := addFile(tw, name, data); err != nil {
+			return nil, err
+		}
+		total += int64(len(data))
+	}
+	return failures, nil
+}
+
+// sortLogs sorts the logs in the order they are collected: the logs at the
+// top of the log directory first, such as the log of glusterd2, then those
+// in the subdirectories
+func sortLogs(names []string) {
+	depth := func(name string) int { return strings.Count(name, "/") }
+	sort.Slice(names, func(i, j int) bool {
+		if depth(names[i]) != depth(names[j]) {
+			return depth(names[i]) < depth(names[j])
+		}
+		return names[i] < names[j]
+	})
+}
+
+// collect collects the files of this peer into a compressed archive
+func collect(ctx context.Context, req api.SupportBundleReq) ([]byte, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+
+	failures, err := addFiles(ctx, tw, "", peerCollectors)
+	if err != nil {
+		return nil, err
+	}
+	if !req.NoLogs {
+		logFailures, err := addLogs(tw, req.LogSize)
+		if err != nil {
+			return nil, err
+		}
+		failures = append(failures, logFailures...)
+	}
+	if err := addFailures(tw, "", failures); err != nil {
+		return nil, err
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,100 @@
+package supportbundle
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/gluster/glusterd2/glusterd2/store"
+
+	"github.com/coreos/etcd/clientv3"
+)
+
+// redacted replaces the secrets in the export of the store
+const redacted = "<redacted>"
+
+var (
+	// excludedPrefixes are the store keys left out of the export, being
+	// large and of no use to debug
+	excludedPrefixes = []string{
+		partsPrefix,
+		"config-snapshots/",
+	}
+
+	// secretPrefixes are the store keys the values of which are secrets
+	secretPrefixes = []string{
+		"ca/key",
+		"jointoken-secret",
+		"jointokens/",
+		"brickkeys/",
+		"namespacetokens/",
+	}
+
+	// secretField matches the names of the JSON fields, and of the
+	// options, the values of which are secrets
+	secretField = regexp.MustCompile(`(^|[-_.])(secret|password|passwd|passphrase|token|key)s?$`)
+)
+
+func hasAnyPrefix(key string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(key, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// redact replaces the values of the secret fields of the decoded JSON value
+func redact(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, fv := range t {
+			if secretField.MatchString(strings.ToLower(k)) {
+				t[k] = redacted
+				continue
+			}
+			t[k] = redact(fv)
+		}
+	case []interface{}:
+		for i := range t {
+			t[i] = redact(t[i])
+		}
+	}
+	return v
+}
+
+// sanitize returns the value of the store key with its secrets redacted.
+// JSON values are returned decoded.
+func sanitize(key string, value []byte) interface{} {
+	if hasAnyPrefix(key, secretPrefixes) {
+		return redacted
+	}
+
+	var v interface{}
+	d := json.NewDecoder(bytes.NewReader(value))
+	d.UseNumber()
+	if err := d.Decode(&v); err != nil || d.More() {
+		return string(value)
+	}
+	return redact(v)
+}
+
+// exportStore returns the keys of the store with their sanitized values
+func exportStore() (map[string]interface{}, error) {
+	resp, err := store.Get(context.TODO(), "", clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	export := make(map[string]interface{}, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		key := string(kv.Key)
+		if hasAnyPrefix(key, excludedPrefixes) {
+			continue
+		}
+		export[key] = sanitize(key, kv.Value)
+	}
+	return export, nil
+}
@@ -0,0 +1,138 @@
+package supportbundle
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/pborman/uuid"
+)
+
+const (
+	// bundlesPrefix is where the support bundles are saved, by ID
+	bundlesPrefix = "supportbundles/"
+	// partsPrefix is where the peers hand over the archives of the files
+	// they collect, in parts small enough for the store
+	partsPrefix = "supportbundle-parts/"
+	partSize    = 512 * 1024
+)
+
+// interrupted marks the support bundle as failed if this peer was creating
+// it and was restarted
+func interrupted(b *api.SupportBundle) {
+	if b.State != api.SupportBundleRunning || !uuid.Equal(b.PeerID, gdctx.MyUUID) {
+		return
+	}
+	if isRunning(b.ID.String()) {
+		return
+	}
+	b.State = api.SupportBundleFailed
+	b.Error = "interrupted by a restart of glusterd2"
+}
+
+// Get returns the support bundle
+func Get(id string) (*api.SupportBundle, error) {
+	resp, err := store.Get(context.TODO(), bundlesPrefix+id)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Count != 1 {
+		return nil, errors.ErrSupportBundleNotFound
+	}
+
+	var b api.SupportBundle
+	if err := json.Unmarshal(resp.Kvs[0].Value, &b); err != nil {
+		return nil, err
+	}
+	interrupted(&b)
+	return &b, nil
+}
+
+// List returns the support bundles, from the oldest to the latest
+func List() ([]api.SupportBundle, error) {
+	resp, err := store.Get(context.TODO(), bundlesPrefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	bundles := make([]api.SupportBundle, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var b api.SupportBundle
+		if err := json.Unmarshal(kv.Value, &b); err != nil {
+			return nil, err
+		}
+		interrupted(&b)
+		bundles = append(bundles, b)
+	}
+	sort.Slice(bundles, func(i, j int) bool {
+		return bundles[i].CreatedAt.Before(bundles[j].CreatedAt)
+	})
+	return bundles, nil
+}
+
+func putBundle(b *api.SupportBundle) error {
+	data, err := json.Marshal(b)
+	if err != nil {
+		return err
+	}
+	_, err = store.Put(context.TODO(), bundlesPrefix+b.ID.String(), string(data))
+	return err
+}
+
+func deleteBundle(id string) error {
+	_, err := store.Delete(context.TODO(), bundlesPrefix+id)
+	return err
+}
+
+func partsKey(id string, peerID uuid.UUID) string {
+	return partsPrefix + id + "/" + peerID.String() + "/"
+}
+
+// putParts saves the archive of the files collected by this peer in parts,
+// and returns the number of parts
+func putParts(id string, data []byte) (int, error) {
+	prefix := partsKey(id, gdctx.MyUUID)
+	parts := 0
+	for len(data) > 0 {
+		n := partSize
+		if n > len(data) {
+			n = len(data)
+		}
+		if _, err := store.Put(context.TODO(), fmt.Sprintf("%s%06d", prefix, parts), string(data[:n])); err != nil {
+			return 0, err
+		}
+		data = data[n:]
+		parts++
+	}
+	return parts, nil
+}
+
+// getParts returns the archive of the files collected by the peer
+func getParts(id string, peerID uuid.UUID, parts int) ([]byte, error) {
+	resp, err := store.Get(context.TODO(), partsKey(id, peerID), clientv3.WithPrefix(),
+		clientv3.WithSort(clientv3.SortByKey, clientv3.SortAscend))
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Kvs) != parts {
+		return nil, fmt.Errorf("%d of the %d parts of the files collected are in the store", len(resp.Kvs), parts)
+	}
+
+	var data []byte
+	for _, kv := range resp.Kvs {
+		data = append(data, kv.Value...)
+	}
+	return data, nil
+}
+
+func deleteParts(id string, peerID uuid.UUID) error {
+	_, err := store.Delete(context.TODO(), partsKey(id, peerID), clientv3.WithPrefix())
+	return err
+}
@@ -0,0 +1,417 @@
+// Package supportbundle creates support bundles: archives of the logs,
+// statedumps, versions and health of all the peers, and of the state of the
+// cluster in the store, to be attached to bug reports.
+//
+// The peer receiving the request drives the collection. Each peer collects
+// its files into an archive which it hands over through the store, and the
+// archives of the peers are merged into a single archive kept on the peer
+// driving the collection, where it is downloaded from.
+package supportbundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/pborman/uuid"
+	config "github.com/spf13/viper"
+)
+
+const (
+	// keep is the number of support bundles kept on a peer, the older ones
+	// are deleted
+	keep = 5
+
+	archivesDir = "support-bundles"
+)
+
+// Collector returns the data of a file of the support bundles. The data is
+// written as is if it is a []byte, and as JSON otherwise.
+type Collector func(ctx context.Context) (interface{}, error)
+
+var (
+	peerCollectors    = make(map[string]Collector)
+	clusterCollectors = make(map[string]Collector)
+
+	// runningLock protects running, the ID of the support bundle being
+	// created by this peer. A single support bundle is created at a time.
+	runningLock sync.Mutex
+	running     string
+)
+
+// RegisterPeerCollector adds the file collected by each peer to the support
+// bundles. The file is saved under the directory of the peer.
+func RegisterPeerCollector(name string, c Collector) {
+	peerCollectors[name] = c
+}
+
+// RegisterClusterCollector adds the file collected once for the cluster to
+// the support bundles
+func RegisterClusterCollector(name string, c Collector) {
+	clusterCollectors[name] = c
+}
+
+// ArchivePath returns the path of the archive of the support bundle, on the
+// peer which created it
+func ArchivePath(id string) string {
+	return filepath.Join(config.GetString("localstatedir"), archivesDir, id+".tar.gz")
+}
+
+// isRunning returns true if the support bundle is being created by this
+// peer
+func isRunning(id string) bool {
+	runningLock.Lock()
+	defer runningLock.Unlock()
+	return running == id
+}
+
+// Create starts the creation of a support bundle with the files of all the
+// peers, and returns it in the running state
+func Create(ctx context.Context, req api.SupportBundleReq) (*api.SupportBundle, error) {
+	runningLock.Lock()
+	defer runningLock.Unlock()
+	if running != "" {
+		return nil, errors.ErrSupportBundleRunning
+	}
+
+	peers, err := peer.GetPeers()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(peers, func(i, j int) bool { return peers[i].Name < peers[j].Name })
+
+	now := time.Now()
+	b := &api.SupportBundle{
+		ID:        uuid.NewRandom(),
+		State:     api.SupportBundleRunning,
+		PeerID:    gdctx.MyUUID,
+		Peers:     make([]api.SupportBundlePeer, 0, len(peers)),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	for _, p := range peers {
+		b.Peers = append(b.Peers, api.SupportBundlePeer{
+			ID:    p.ID,
+			Name:  p.Name,
+			State: api.SupportBundlePeerPending,
+		})
+	}
+	if err := putBundle(b); err != nil {
+		return nil, err
+	}
+
+	created := *b
+	created.Peers = append([]api.SupportBundlePeer(nil), b.Peers...)
+
+	running = b.ID.String()
+	go func() {
+		run(ctx, b, req)
+
+		runningLock.Lock()
+		running = ""
+		runningLock.Unlock()
+	}()
+	return &created, nil
+}
+
+// run creates the support bundle and saves its outcome
+func run(ctx context.Context, b *api.SupportBundle, req api.SupportBundleReq) {
+	logger := gdctx.GetReqLogger(ctx).WithField("support-bundle", b.ID.String())
+
+	err := build(ctx, b, req)
+	b.UpdatedAt = time.Now()
+	if err != nil {
+		logger.WithError(err).Error("failed to create the support bundle")
+		b.State = api.SupportBundleFailed
+		b.Error = err.Error()
+	} else {
+		logger.WithField("size", b.Size).Info("support bundle created")
+		b.State = api.SupportBundleCompleted
+		b.Progress = 100
+	}
+	if err := putBundle(b); err != nil {
+		logger.WithError(err).Error("failed to save the support bundle")
+	}
+
+	if err := prune(); err != nil {
+		logger.WithError(err).Warn("failed to delete the old support bundles")
+	}
+}
+
+// manifest describes the content of a support bundle
+type manifest struct {
+	ID        uuid.UUID               `json:"id"`
+	CreatedAt time.Time               `json:"created-at"`
+	Request   api.SupportBundleReq    `json:"request"`
+	Peers     []api.SupportBundlePeer `json:"peers"`
+}
+
+// build collects the files of the peers and of the cluster into the archive
+// of the support bundle, saving the progress as it goes
+func build(ctx context.Context, b *api.SupportBundle, req api.SupportBundleReq) error {
+	logger := gdctx.GetReqLogger(ctx).WithField("support-bundle", b.ID.String())
+	id := b.ID.String()
+
+	dest := ArchivePath(id)
+	if err := os.MkdirAll(filepath.Dir(dest), 0700); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(dest), "."+id)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	gz := gzip.NewWriter(tmp)
+	tw := tar.NewWriter(gz)
+	root := "support-bundle-" + id + "/"
+
+	steps := len(b.Peers) + 1
+	for i := range b.Peers {
+		p := &b.Peers[i]
+		name := p.Name
+		if name == "" {
+			name = p.ID.String()
+		}
+		if err := addPeer(ctx, tw, root+"peers/"+name+"/", id, p.ID, req); err != nil {
+			logger.WithError(err).WithField("peer", p.Name).Warn("failed to collect the files of the peer")
+			p.State = api.SupportBundlePeerFailed
+			p.Error = err.Error()
+		} else {
+			p.State = api.SupportBundlePeerCollected
+		}
+
+		b.Progress = (i + 1) * 100 / steps
+		b.UpdatedAt = time.Now()
+		if err := putBundle(b); err != nil {
+			logger.WithError(err).Warn("failed to save the progress of the support bundle")
+		}
+	}
+
+	failures, err := addFiles(ctx, tw, root+"cluster/", clusterCollectors)
+	if err != nil {
+		return err
+	}
+	if err := addFailures(tw, root+"cluster/", failures); err != nil {
+		return err
+	}
+	m := manifest{ID: b.ID, CreatedAt: b.CreatedAt, Request: req, Peers: b.Peers}
+	if err := addFile(tw, root+"manifest.json", m); err != nil {
+		return err
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), dest); err != nil {
+		return err
+	}
+
+	info, err := os.Stat(dest)
+	if err != nil {
+		return err
+	}
+	b.Size = info.Size()
+	return nil
+}
+
+// addPeer has the peer collect its files, and adds them to the archive
+// under the prefix
+func addPeer(ctx context.Context, tw *tar.Writer, prefix string, id string, peerID uuid.UUID, req api.SupportBundleReq) error {
+	defer deleteParts(id, peerID)
+
+	txn := transaction.NewTxn(ctx)
+	defer txn.Done()
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc: "support-bundle.Collect",
+			Nodes:  []uuid.UUID{peerID},
+		},
+	}
+	txn.DisableRollback = true
+
+	if err := txn.Ctx.Set(idTxnKey, id); err != nil {
+		return err
+	}
+	if err := txn.Ctx.Set(reqTxnKey, req); err != nil {
+		return err
+	}
+	if err := txn.Do(); err != nil {
+		return err
+	}
+
+	var parts int
+	if err := txn.Ctx.GetNodeResult(peerID, partsTxnKey, &parts); err != nil {
+		return err
+	}
+	data, err := getParts(id, peerID, parts)
+	if err != nil {
+		return err
+	}
+	return copyArchive(tw, prefix, bytes.NewReader(data))
+}
+
+// copyArchive adds the files of the compressed archive to the archive,
+// under the prefix
+func copyArchive(tw *tar.Writer, prefix string, r io.Reader) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		hdr.Name = prefix + hdr.Name
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := io.Copy(tw, tr); err != nil {
+			return err
+		}
+	}
+}
+
+// addFile adds the data to the archive, as is if it is a []byte and as
+// JSON otherwise
+func addFile(tw *tar.Writer, name string, data interface{}) error {
+	content, ok := data.([]byte)
+	if !ok {
+		var err error
+		if content, err = json.MarshalIndent(data, "", "  "); err != nil {
+			return err
+		}
+	}
+
+	hdr := &tar.Header{
+		Name:    name,
+		Mode:    0600,
+		Size:    int64(len(content)),
+		ModTime: time.Now(),
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err := tw.Write(content)
+	return err
+}
+
+// addFiles adds the files returned by the collectors to the archive, under
+// the prefix. It returns the failures of the collectors.
+func addFiles(ctx context.Context, tw *tar.Writer, prefix string, collectors map[string]Collector) ([]string, error) {
+	names := make([]string, 0, len(collectors))
+	for name := range collectors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var failures []string
+	for _, name := range names {
+		data, err := collectors[name](ctx)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %s", name, err))
+			continue
+		}
+		if err := addFile(tw, prefix+name, data); err != nil {
+			return nil, err
+		}
+	}
+	return failures, nil
+}
+
+// addFailures lists the files which could not be collected in errors.txt,
+// under the prefix
+func addFailures(tw *tar.Writer, prefix string, failures []string) error {
+	if len(failures) == 0 {
+		return nil
+	}
+	return addFile(tw, prefix+"errors.txt", []byte(strings.Join(failures, "\n")+"\n"))
+}
+
+// Delete deletes the support bundle and its archive
+func Delete(ctx context.Context, id string) error {
+	b, err := Get(id)
+	if err != nil {
+		return err
+	}
+	if b.State == api.SupportBundleRunning {
+		return errors.ErrSupportBundleNotReady
+	}
+
+	txn := transaction.NewTxn(ctx)
+	defer txn.Done()
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc: "support-bundle.Delete",
+			Nodes:  []uuid.UUID{b.PeerID},
+		},
+	}
+	// the support bundles of the peers which left the cluster are deleted
+	// all the same
+	txn.DontCheckAlive = true
+	if err := txn.Ctx.Set(idTxnKey, id); err != nil {
+		return err
+	}
+	if err := txn.Do(); err != nil {
+		return err
+	}
+	return deleteBundle(id)
+}
+
+// prune deletes the oldest of the support bundles created by this peer,
+// keeping the latest ones
+func prune() error {
+	bundles, err := List()
+	if err != nil {
+		return err
+	}
+
+	var own []api.SupportBundle
+	for _, b := range bundles {
+		if uuid.Equal(b.PeerID, gdctx.MyUUID) && b.State != api.SupportBundleRunning {
+			own = append(own, b)
+		}
+	}
+	for len(own) > keep {
+		id := own[0].ID.String()
+		if err := os.Remove(ArchivePath(id)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		if err := deleteBundle(id); err != nil {
+			return err
+		}
+		own = own[1:]
+	}
+	return nil
+}
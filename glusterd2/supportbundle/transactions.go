@@ -0,0 +1,61 @@
+package supportbundle
+
+import (
+	"context"
+	"os"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/pkg/api"
+)
+
+const (
+	idTxnKey    = "supportbundle.id"
+	reqTxnKey   = "supportbundle.req"
+	partsTxnKey = "supportbundle.parts"
+)
+
+// txnCollect collects the files of this peer, and hands them over through
+// the store
+func txnCollect(c transaction.TxnCtx) error {
+	var id string
+	if err := c.Get(idTxnKey, &id); err != nil {
+		return err
+	}
+	var req api.SupportBundleReq
+	if err := c.Get(reqTxnKey, &req); err != nil {
+		return err
+	}
+
+	ctx := gdctx.WithReqLogger(context.Background(), c.Logger())
+	data, err := collect(ctx, req)
+	if err != nil {
+		c.Logger().WithError(err).Error("failed to collect the files of the support bundle")
+		return err
+	}
+	parts, err := putParts(id, data)
+	if err != nil {
+		c.Logger().WithError(err).Error("failed to save the files of the support bundle")
+		return err
+	}
+	return c.SetNodeResult(gdctx.MyUUID, partsTxnKey, parts)
+}
+
+// txnDelete deletes the archive of the support bundle created by this peer
+func txnDelete(c transaction.TxnCtx) error {
+	var id string
+	if err := c.Get(idTxnKey, &id); err != nil {
+		return err
+	}
+	if err := os.Remove(ArchivePath(id)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// RegisterStepFuncs registers the step functions collecting the files of
+// the support bundles and deleting them
+func RegisterStepFuncs() {
+	transaction.RegisterStepFunc(txnCollect, "support-bundle.Collect")
+	transaction.RegisterStepFunc(txnDelete, "support-bundle.Delete")
+}
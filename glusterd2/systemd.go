@@ -0,0 +1,55 @@
+package main
+
+import (
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/pkg/systemd"
+
+	log "github.com/sirupsen/logrus"
+	config "github.com/spf13/viper"
+)
+
+// storeHealthCheckInterval is how often the store is checked while waiting
+// for it to be healthy before reporting glusterd2 ready to systemd
+const storeHealthCheckInterval = time.Second
+
+// notifySystemd sends the state to systemd, if glusterd2 is run by systemd
+// with notifications enabled
+func notifySystemd(state string) {
+	if !config.GetBool("systemd") {
+		return
+	}
+	if _, err := systemd.Notify(state); err != nil {
+		log.WithError(err).WithField("state", state).Warn("failed to notify systemd")
+	}
+}
+
+// notifySystemdReady reports glusterd2 ready to systemd once the store is
+// healthy, and then keeps the systemd watchdog fed. It must be called once
+// the servers are started and the SunRPC programs registered.
+func notifySystemdReady() {
+	if !config.GetBool("systemd") {
+		return
+	}
+
+	for !store.Healthy() {
+		notifySystemd("STATUS=Waiting for the store to be healthy")
+		time.Sleep(storeHealthCheckInterval)
+	}
+	notifySystemd("READY=1\nSTATUS=Running")
+	log.Debug("reported ready to systemd")
+
+	interval := systemd.WatchdogInterval()
+	if interval == 0 {
+		return
+	}
+
+	// notify twice within the interval so that a delayed notification
+	// does not trip the watchdog
+	ticker := time.NewTicker(interval / 2)
+	defer ticker.Stop()
+	for range ticker.C {
+		notifySystemd("WATCHDOG=1")
+	}
+}
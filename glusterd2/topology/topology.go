@@ -0,0 +1,202 @@
+// Package topology builds the topology of the cluster as a graph: the peers,
+// their devices and the bricks created on them, the subvolumes and volumes
+// made of the bricks, and the daemons placed on the peers.
+package topology
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/daemon"
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	deviceapi "github.com/gluster/glusterd2/plugins/device/api"
+	"github.com/gluster/glusterd2/plugins/device/deviceutils"
+)
+
+// glusterfsdName is the name of the brick processes, which are not
+// daemons of the topology as their bricks are
+const glusterfsdName = "glusterfsd"
+
+// Build returns the topology of the cluster, from the store
+func Build(ctx context.Context) (*api.ClusterTopologyResp, error) {
+	peers, err := peer.GetPeers()
+	if err != nil {
+		return nil, err
+	}
+	devices, err := deviceutils.GetDevices()
+	if err != nil {
+		return nil, err
+	}
+	volumes, err := volume.GetVolumes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	daemons, err := daemon.ListAll()
+	if err != nil {
+		return nil, err
+	}
+	alive := store.Store.GetAliveNodes(ctx)
+
+	return graph(peers, alive, devices, volumes, daemons), nil
+}
+
+func peerID(id string) string {
+	return "peer:" + id
+}
+
+func deviceID(peerID, device string) string {
+	return "device:" + peerID + ":" + device
+}
+
+func volumeID(volname string) string {
+	return "volume:" + volname
+}
+
+func subvolumeID(volname, subvol string) string {
+	return "subvolume:" + volname + ":" + subvol
+}
+
+type builder struct {
+	topology api.ClusterTopologyResp
+	nodes    map[string]bool
+}
+
+func (b *builder) node(id, kind, name string, attrs map[string]string) {
+	if b.nodes[id] {
+		return
+	}
+	b.nodes[id] = true
+	b.topology.Nodes = append(b.topology.Nodes, api.TopologyNode{
+		ID:    id,
+		Kind:  kind,
+		Name:  name,
+		Attrs: attrs,
+	})
+}
+
+func (b *builder) edge(from, to string) {
+	b.topology.Edges = append(b.topology.Edges, api.TopologyEdge{From: from, To: to})
+}
+
+func brickAttrs(bi brick.Brickinfo) map[string]string {
+	attrs := map[string]string{"path": bi.Path}
+	switch bi.Type {
+	case brick.Arbiter:
+		attrs["type"] = "arbiter"
+	case brick.ThinArbiter:
+		attrs["type"] = "thin-arbiter"
+	}
+	if bi.Decommissioned {
+		attrs["decommissioned"] = "true"
+	}
+	if bi.Encrypted {
+		attrs["encrypted"] = "true"
+	}
+	return attrs
+}
+
+// subvolume adds the subvolume, its bricks and its subvolumes, linked to
+// the node of the volume or subvolume it is part of
+func (b *builder) subvolume(v *volume.Volinfo, sv *volume.Subvol, parent string) {
+	id := subvolumeID(v.Name, sv.Name)
+	b.node(id, api.TopologySubvolume, sv.Name, map[string]string{
+		"type": strings.ToLower(sv.Type.String()),
+	})
+	b.edge(id, parent)
+
+	for _, bi := range sv.Bricks {
+		brickID := "brick:" + bi.ID.String()
+		b.node(brickID, api.TopologyBrick, bi.Hostname+":"+bi.Path, brickAttrs(bi))
+		b.edge(brickID, id)
+
+		// bricks of peers detached from the cluster still show up
+		pid := peerID(bi.PeerID.String())
+		b.node(pid, api.TopologyPeer, bi.Hostname, map[string]string{"online": "false"})
+		did := deviceID(bi.PeerID.String(), bi.RootDevice)
+		if bi.RootDevice != "" && b.nodes[did] {
+			b.edge(did, brickID)
+		} else {
+			b.edge(pid, brickID)
+		}
+	}
+	for i := range sv.Subvols {
+		b.subvolume(v, &sv.Subvols[i], id)
+	}
+}
+
+// graph builds the topology of the cluster. alive are the IDs of the peers
+// which are up, and daemons the daemons of the peers by peer ID.
+func graph(peers []*peer.Peer, alive map[string]int, devices []deviceapi.Info, volumes []*volume.Volinfo, daemons map[string][]daemon.Daemon) *api.ClusterTopologyResp {
+	b := &builder{nodes: make(map[string]bool)}
+
+	for _, p := range peers {
+		_, online := alive[p.ID.String()]
+		attrs := map[string]string{"online": strconv.FormatBool(online)}
+		if p.Version != "" {
+			attrs["version"] = p.Version
+		}
+		b.node(peerID(p.ID.String()), api.TopologyPeer, p.Name, attrs)
+	}
+
+	for _, d := range devices {
+		pid := peerID(d.PeerID.String())
+		if !b.nodes[pid] {
+			continue
+		}
+		id := deviceID(d.PeerID.String(), d.Device)
+		b.node(id, api.TopologyDevice, d.Device, map[string]string{
+			"state":      d.State,
+			"total-size": strconv.FormatUint(d.TotalSize, 10),
+			"free-size":  strconv.FormatUint(d.AvailableSize, 10),
+		})
+		b.edge(pid, id)
+	}
+
+	for _, v := range volumes {
+		id := volumeID(v.Name)
+		b.node(id, api.TopologyVolume, v.Name, map[string]string{
+			"type":  api.VolType(v.Type).String(),
+			"state": api.VolState(v.State).String(),
+		})
+		for i := range v.Subvols {
+			b.subvolume(v, &v.Subvols[i], id)
+		}
+	}
+
+	for nodeID, ds := range daemons {
+		pid := peerID(nodeID)
+		if !b.nodes[pid] {
+			continue
+		}
+		for _, d := range ds {
+			if d.Name() == glusterfsdName {
+				continue
+			}
+			id := "daemon:" + nodeID + ":" + d.ID()
+			b.node(id, api.TopologyDaemon, d.Name(), map[string]string{"id": d.ID()})
+			b.edge(pid, id)
+		}
+	}
+
+	t := &b.topology
+	sort.Slice(t.Nodes, func(i, j int) bool { return t.Nodes[i].ID < t.Nodes[j].ID })
+	sort.Slice(t.Edges, func(i, j int) bool {
+		if t.Edges[i].From != t.Edges[j].From {
+			return t.Edges[i].From < t.Edges[j].From
+		}
+		return t.Edges[i].To < t.Edges[j].To
+	})
+	if t.Nodes == nil {
+		t.Nodes = []api.TopologyNode{}
+	}
+	if t.Edges == nil {
+		t.Edges = []api.TopologyEdge{}
+	}
+	return t
+}
@@ -0,0 +1,92 @@
+package topology
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/daemon"
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	deviceapi "github.com/gluster/glusterd2/plugins/device/api"
+
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type testDaemon struct {
+	name, id string
+}
+
+func (d *testDaemon) Name() string       { return d.name }
+func (d *testDaemon) Path() string       { return "/usr/sbin/" + d.name }
+func (d *testDaemon) Args() []string     { return nil }
+func (d *testDaemon) SocketFile() string { return "" }
+func (d *testDaemon) PidFile() string    { return "" }
+func (d *testDaemon) ID() string         { return d.id }
+
+func hasEdge(t *api.ClusterTopologyResp, from, to string) bool {
+	for _, e := range t.Edges {
+		if e.From == from && e.To == to {
+			return true
+		}
+	}
+	return false
+}
+
+func TestGraph(t *testing.T) {
+	p1 := &peer.Peer{ID: uuid.NewRandom(), Name: "node1"}
+	p2 := &peer.Peer{ID: uuid.NewRandom(), Name: "node2"}
+	alive := map[string]int{p1.ID.String(): 1}
+	devices := []deviceapi.Info{{Device: "/dev/sdb", PeerID: p1.ID, State: "enabled"}}
+
+	b1 := brick.Brickinfo{ID: uuid.NewRandom(), PeerID: p1.ID, Hostname: "node1", Path: "/bricks/b1"}
+	b1.RootDevice = "/dev/sdb"
+	b2 := brick.Brickinfo{ID: uuid.NewRandom(), PeerID: p2.ID, Hostname: "node2", Path: "/bricks/b2"}
+	v := &volume.Volinfo{
+		Name:  "testvol",
+		Type:  volume.Replicate,
+		State: volume.VolStarted,
+		Subvols: []volume.Subvol{
+			{Name: "testvol-replicate-0", Type: volume.SubvolReplicate, Bricks: []brick.Brickinfo{b1, b2}},
+		},
+	}
+	daemons := map[string][]daemon.Daemon{
+		p1.ID.String(): {
+			&testDaemon{name: "glustershd", id: "glustershd"},
+			&testDaemon{name: "glusterfsd", id: "/bricks/b1"},
+		},
+	}
+
+	topo := graph([]*peer.Peer{p1, p2}, alive, devices, []*volume.Volinfo{v}, daemons)
+
+	kinds := make(map[string]int)
+	for _, n := range topo.Nodes {
+		kinds[n.Kind]++
+		if n.ID == peerID(p2.ID.String()) {
+			assert.Equal(t, "false", n.Attrs["online"])
+		}
+	}
+	assert.Equal(t, map[string]int{
+		api.TopologyPeer:      2,
+		api.TopologyDevice:    1,
+		api.TopologyBrick:     2,
+		api.TopologySubvolume: 1,
+		api.TopologyVolume:    1,
+		api.TopologyDaemon:    1,
+	}, kinds)
+
+	dev := deviceID(p1.ID.String(), "/dev/sdb")
+	sv := subvolumeID("testvol", "testvol-replicate-0")
+	assert.True(t, hasEdge(topo, peerID(p1.ID.String()), dev))
+	assert.True(t, hasEdge(topo, dev, "brick:"+b1.ID.String()))
+	assert.True(t, hasEdge(topo, peerID(p2.ID.String()), "brick:"+b2.ID.String()))
+	assert.True(t, hasEdge(topo, "brick:"+b1.ID.String(), sv))
+	assert.True(t, hasEdge(topo, sv, volumeID("testvol")))
+	assert.True(t, hasEdge(topo, peerID(p1.ID.String()), "daemon:"+p1.ID.String()+":glustershd"))
+
+	dot := topo.DOT()
+	assert.True(t, strings.HasPrefix(dot, "digraph cluster {"))
+	assert.Contains(t, dot, `"`+sv+`" -> "volume:testvol";`)
+}
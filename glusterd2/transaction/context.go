@@ -45,6 +45,7 @@ type TxnCtx interface {
 // Tctx represents structure for transaction context
 type Tctx struct {
 	config         *TxnCtxConfig // this will be marshalled and sent on wire
+	ctx            context.Context
 	logger         log.FieldLogger
 	readSet        map[string][]byte // cached responses from store
 	readCacheDirty bool
@@ -61,6 +62,7 @@ type TxnCtxConfig struct {
 func newCtx(config *TxnCtxConfig) *Tctx {
 	return &Tctx{
 		config:         config,
+		ctx:            context.Background(),
 		logger:         log.StandardLogger().WithFields(config.LogFields),
 		readSet:        make(map[string][]byte),
 		writeSet:       make(map[string]string),
@@ -96,7 +98,10 @@ func (c *Tctx) Set(key string, value interface{}) error {
 
 // SyncCache synchronizes the locally cached keys and values from the store
 func (c *Tctx) SyncCache() error {
-	resp, err := store.Get(context.TODO(), c.config.StorePrefix, clientv3.WithPrefix())
+	ctx, cancel := context.WithTimeout(c.ctx, etcdTxnTimeout*time.Second)
+	defer cancel()
+
+	resp, err := store.Get(ctx, c.config.StorePrefix, clientv3.WithPrefix())
 	if err != nil {
 		return err
 	}
@@ -120,7 +125,7 @@ func (c *Tctx) Commit() error {
 		putOps = append(putOps, clientv3.OpPut(key, value))
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), etcdTxnTimeout*time.Second)
+	ctx, cancel := context.WithTimeout(c.ctx, etcdTxnTimeout*time.Second)
 	txn, err := store.Txn(ctx).
 		If().
 		Then(putOps...).
@@ -147,6 +152,14 @@ func (c *Tctx) Commit() error {
 	return nil
 }
 
+// cacheResults caches the keys set by a streamed step on a remote node, to
+// serve Get()s for them, without writing them to the store
+func (c *Tctx) cacheResults(results map[string][]byte) {
+	for key, value := range results {
+		c.readSet[c.config.StorePrefix+key] = value
+	}
+}
+
 // SetNodeResult is similar to Set but prefixes the key with the node UUID
 // specified. This function can be used by nodes to store results of
 // transaction steps.
@@ -203,7 +216,10 @@ func (c *Tctx) Delete(key string) error {
 	delete(c.writeSet, storeKey)
 
 	// TODO: Optimize this by doing it as part of etcd txn in commit()
-	if _, err := store.Delete(context.TODO(), storeKey); err != nil {
+	ctx, cancel := context.WithTimeout(c.ctx, etcdTxnTimeout*time.Second)
+	defer cancel()
+
+	if _, err := store.Delete(ctx, storeKey); err != nil {
 		c.logger.WithError(err).WithField("key", storeKey).Error(
 			"failed to delete key")
 		return err
@@ -0,0 +1,83 @@
+package transaction
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gluster/glusterd2/pkg/errors"
+)
+
+// abortTimeout is the time given to the transactions failed at the end of
+// the grace period of the shutdown to undo their steps
+const abortTimeout = 5 * time.Second
+
+// drain tracks the transactions started by this peer, for the shutdown of
+// glusterd2 to wait for them
+var drain = struct {
+	sync.RWMutex
+	draining  bool
+	inFlight  sync.WaitGroup
+	abort     chan struct{}
+	abortOnce sync.Once
+}{
+	abort: make(chan struct{}),
+}
+
+// Begin records a transaction started by this peer. It fails with
+// ErrShuttingDown once glusterd2 is shutting down. end must be called once
+// the transaction is done.
+func Begin() (end func(), err error) {
+	drain.RLock()
+	defer drain.RUnlock()
+
+	if drain.draining {
+		return nil, errors.ErrShuttingDown
+	}
+	drain.inFlight.Add(1)
+	return drain.inFlight.Done, nil
+}
+
+// Aborted returns a channel closed at the end of the grace period of the
+// shutdown, when the transactions still running are to fail
+func Aborted() <-chan struct{} {
+	return drain.abort
+}
+
+// aborted returns ErrShuttingDown if the transactions are to fail
+func aborted() error {
+	select {
+	case <-drain.abort:
+		return errors.ErrShuttingDown
+	default:
+		return nil
+	}
+}
+
+// Drain stops this peer from starting transactions and waits for those in
+// flight to end. The transactions still running once ctx is done are
+// failed, and are given abortTimeout to undo their steps.
+func Drain(ctx context.Context) error {
+	drain.Lock()
+	drain.draining = true
+	drain.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		drain.inFlight.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+	}
+
+	drain.abortOnce.Do(func() { close(drain.abort) })
+	select {
+	case <-done:
+	case <-time.After(abortTimeout):
+	}
+	return ctx.Err()
+}
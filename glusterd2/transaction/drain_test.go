@@ -0,0 +1,52 @@
+package transaction
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDrain(t *testing.T) {
+	defer func() {
+		drain.draining = false
+		drain.abort = make(chan struct{})
+		drain.abortOnce = sync.Once{}
+	}()
+
+	end, err := Begin()
+	require.Nil(t, err)
+
+	// The transaction in flight ends within the grace period
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		end()
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.Nil(t, Drain(ctx))
+	assert.Nil(t, aborted())
+
+	// No transaction is started once draining
+	_, err = Begin()
+	assert.Equal(t, errors.ErrShuttingDown, err)
+
+	// The transaction still in flight at the end of the grace period is
+	// failed
+	drain.draining = false
+	end, err = Begin()
+	require.Nil(t, err)
+	go func() {
+		<-Aborted()
+		end()
+	}()
+	ctx, cancel = context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, Drain(ctx))
+	assert.Equal(t, errors.ErrShuttingDown, aborted())
+}
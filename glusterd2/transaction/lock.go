@@ -2,12 +2,19 @@ package transaction
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
 	"github.com/gluster/glusterd2/glusterd2/store"
 
+	"github.com/coreos/etcd/clientv3"
 	"github.com/coreos/etcd/clientv3/concurrency"
 	"github.com/pborman/uuid"
 	log "github.com/sirupsen/logrus"
@@ -15,6 +22,7 @@ import (
 
 const (
 	lockPrefix        = "locks/"
+	lockOwnerPrefix   = "lockowners/"
 	lockObtainTimeout = 5 * time.Second
 	lockTTL           = 10
 )
@@ -25,6 +33,8 @@ var (
 	ErrLockTimeout = errors.New("could not obtain lock: another conflicting transaction may be in progress")
 	// ErrLockExists is returned when a lock already exists within the transaction
 	ErrLockExists = errors.New("existing lock found for given lock ID")
+	// ErrLockNotFound is returned when no lock is held on the given lock ID
+	ErrLockNotFound = errors.New("lock not found")
 )
 
 // createLockStepFunc returns the registry IDs of StepFuncs which lock/unlock the given key.
@@ -153,8 +163,12 @@ func CreateLockFuncs(key string) (LockUnlockFunc, LockUnlockFunc) {
 // Locks are the collection of cluster wide transaction lock
 type Locks map[string]*concurrency.Mutex
 
-func (l Locks) lock(lockID string) error {
-	var logger = log.WithField("lockID", lockID)
+func (l Locks) lock(ctx context.Context, lockID string, owner *lockOwner) error {
+	logger := gdctx.GetReqLogger(ctx)
+	if logger == nil {
+		logger = log.StandardLogger()
+	}
+	logger = logger.WithField("lockID", lockID)
 
 	// Ensure that no prior lock exists for the given lockID in this transaction
 	if _, ok := l[lockID]; ok {
@@ -171,7 +185,8 @@ func (l Locks) lock(lockID string) error {
 
 	locker := concurrency.NewMutex(s, key)
 
-	ctx, cancel := context.WithTimeout(store.Store.Ctx(), lockObtainTimeout)
+	// Waiting for the lock is given up on if the request is cancelled
+	ctx, cancel := context.WithTimeout(ctx, lockObtainTimeout)
 	defer cancel()
 
 	err = locker.Lock(ctx)
@@ -180,6 +195,7 @@ func (l Locks) lock(lockID string) error {
 		logger.Debug("lock obtained")
 		// Attach lock to the transaction
 		l[lockID] = locker
+		putLockOwner(locker, s.Lease(), owner)
 
 	case context.DeadlineExceeded:
 		logger.Debug("timeout: failed to obtain lock")
@@ -196,11 +212,12 @@ func (l Locks) lock(lockID string) error {
 // Lock obtains a cluster wide transaction lock on the given lockID/lockIDs,
 // and attaches the obtained locks to the transaction
 func (l Locks) Lock(lockID string, lockIDs ...string) error {
-	if err := l.lock(lockID); err != nil {
+	ctx := store.Store.Ctx()
+	if err := l.lock(ctx, lockID, nil); err != nil {
 		return err
 	}
 	for _, id := range lockIDs {
-		if err := l.lock(id); err != nil {
+		if err := l.lock(ctx, id, nil); err != nil {
 			return err
 		}
 	}
@@ -211,7 +228,182 @@ func (l Locks) Lock(lockID string, lockIDs ...string) error {
 func (l Locks) UnLock(ctx context.Context) {
 	for lockID, locker := range l {
 		if err := locker.Unlock(ctx); err == nil {
+			deleteLockOwner(locker)
 			delete(l, lockID)
 		}
 	}
 }
+
+// lockOwner is the holder of a lock, stored along the lock for it to be
+// listed. It is attached to the lease of the lock, and goes away with it.
+type lockOwner struct {
+	PeerID uuid.UUID `json:"peer-id"`
+	TxnID  uuid.UUID `json:"txn-id,omitempty"`
+	ReqID  uuid.UUID `json:"req-id,omitempty"`
+	Since  time.Time `json:"since"`
+}
+
+func lockOwnerKey(lockerKey string) string {
+	return lockOwnerPrefix + strings.TrimPrefix(lockerKey, lockPrefix)
+}
+
+func putLockOwner(locker *concurrency.Mutex, lease clientv3.LeaseID, owner *lockOwner) {
+	if owner == nil {
+		owner = &lockOwner{}
+	}
+	owner.PeerID = gdctx.MyUUID
+	owner.Since = time.Now()
+
+	data, err := json.Marshal(owner)
+	if err != nil {
+		return
+	}
+	// The lock is held even if the owner could not be stored, it is
+	// then listed without it
+	if _, err := store.Put(context.TODO(), lockOwnerKey(locker.Key()), string(data), clientv3.WithLease(lease)); err != nil {
+		log.WithError(err).WithField("key", locker.Key()).Warn("failed to store lock owner")
+	}
+}
+
+func deleteLockOwner(locker *concurrency.Mutex) {
+	if _, err := store.Delete(context.TODO(), lockOwnerKey(locker.Key())); err != nil {
+		log.WithError(err).WithField("key", locker.Key()).Warn("failed to delete lock owner")
+	}
+}
+
+// LockInfo is a cluster wide lock, and the peer and transaction holding it
+type LockInfo struct {
+	ID string
+	// Key is the key of the holder of the lock in the store
+	Key   string
+	Lease clientv3.LeaseID
+	// PeerID, TxnID, ReqID and Since are not known for the locks obtained
+	// with CreateLockSteps and CreateLockFuncs, whose holder is only known
+	// by its lease
+	PeerID uuid.UUID
+	TxnID  uuid.UUID
+	ReqID  uuid.UUID
+	Since  time.Time
+	// Waiters is the number of transactions waiting for the lock
+	Waiters int
+}
+
+// parseLockKey returns the lock ID and the lease of the key of a lock
+// holder or waiter, which is of the form locks/<lock ID>/<lease in hex>
+func parseLockKey(key string) (string, clientv3.LeaseID, bool) {
+	if !strings.HasPrefix(key, lockPrefix) {
+		return "", 0, false
+	}
+	key = strings.TrimPrefix(key, lockPrefix)
+	i := strings.LastIndex(key, "/")
+	if i <= 0 {
+		return "", 0, false
+	}
+	lease, err := strconv.ParseInt(key[i+1:], 16, 64)
+	if err != nil {
+		return "", 0, false
+	}
+	return key[:i], clientv3.LeaseID(lease), true
+}
+
+// ListLocks returns the cluster wide locks held, sorted by lock ID
+func ListLocks(ctx context.Context) ([]*LockInfo, error) {
+	resp, err := store.Get(ctx, lockPrefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	// The holder of a lock is the key created first, the others are
+	// waiting for it
+	holders := make(map[string]*LockInfo)
+	revs := make(map[string]int64)
+	for _, kv := range resp.Kvs {
+		lockID, lease, ok := parseLockKey(string(kv.Key))
+		if !ok {
+			continue
+		}
+		info, ok := holders[lockID]
+		if !ok {
+			info = &LockInfo{ID: lockID}
+			holders[lockID] = info
+		} else {
+			info.Waiters++
+		}
+		if rev, ok := revs[lockID]; !ok || kv.CreateRevision < rev {
+			revs[lockID] = kv.CreateRevision
+			info.Key = string(kv.Key)
+			info.Lease = lease
+		}
+	}
+	if len(holders) == 0 {
+		return []*LockInfo{}, nil
+	}
+
+	// The peers publish their liveness with the lease of their session,
+	// which holds the locks not having an owner stored
+	peers := make(map[clientv3.LeaseID]uuid.UUID)
+	if resp, err := store.Get(ctx, store.LivenessKeyPrefix, clientv3.WithPrefix()); err == nil {
+		for _, kv := range resp.Kvs {
+			if id := uuid.Parse(path.Base(string(kv.Key))); id != nil {
+				peers[clientv3.LeaseID(kv.Lease)] = id
+			}
+		}
+	}
+
+	owners := make(map[string]*lockOwner)
+	if resp, err := store.Get(ctx, lockOwnerPrefix, clientv3.WithPrefix()); err == nil {
+		for _, kv := range resp.Kvs {
+			var owner lockOwner
+			if err := json.Unmarshal(kv.Value, &owner); err == nil {
+				owners[string(kv.Key)] = &owner
+			}
+		}
+	}
+
+	locks := make([]*LockInfo, 0, len(holders))
+	for _, info := range holders {
+		if owner, ok := owners[lockOwnerKey(info.Key)]; ok {
+			info.PeerID = owner.PeerID
+			info.TxnID = owner.TxnID
+			info.ReqID = owner.ReqID
+			info.Since = owner.Since
+		} else {
+			info.PeerID = peers[info.Lease]
+		}
+		locks = append(locks, info)
+	}
+	sort.Slice(locks, func(i, j int) bool { return locks[i].ID < locks[j].ID })
+
+	return locks, nil
+}
+
+// ForceUnlock releases the lock on the given lock ID, whichever peer holds
+// it, and returns the released lock. The next waiter, if any, obtains the
+// lock. The holder is not told, and carries on as if it still held the
+// lock: this must only be used on stale locks.
+func ForceUnlock(ctx context.Context, lockID string) (*LockInfo, error) {
+	locks, err := ListLocks(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, info := range locks {
+		if info.ID != lockID {
+			continue
+		}
+		// Only delete the key if it is still the same holder's
+		txn, err := store.Txn(ctx).
+			If(clientv3.Compare(clientv3.LeaseValue(info.Key), "=", int64(info.Lease))).
+			Then(clientv3.OpDelete(info.Key), clientv3.OpDelete(lockOwnerKey(info.Key))).
+			Commit()
+		if err != nil {
+			return nil, err
+		}
+		if !txn.Succeeded {
+			return nil, fmt.Errorf("lock %s changed hands, retry", lockID)
+		}
+		return info, nil
+	}
+
+	return nil, ErrLockNotFound
+}
@@ -0,0 +1,31 @@
+package transaction
+
+import (
+	"testing"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLockKey(t *testing.T) {
+	lockID, lease, ok := parseLockKey("locks/vol1/694d6f3bc4c1a404")
+	assert.True(t, ok)
+	assert.Equal(t, "vol1", lockID)
+	assert.Equal(t, clientv3.LeaseID(0x694d6f3bc4c1a404), lease)
+
+	// Lock IDs may have slashes
+	lockID, _, ok = parseLockKey("locks/evacuations/2b1b4e5c/694d6f3bc4c1a404")
+	assert.True(t, ok)
+	assert.Equal(t, "evacuations/2b1b4e5c", lockID)
+
+	_, _, ok = parseLockKey("locks/694d6f3bc4c1a404")
+	assert.False(t, ok)
+	_, _, ok = parseLockKey("locks/vol1/lease")
+	assert.False(t, ok)
+	_, _, ok = parseLockKey("volumes/vol1/694d6f3bc4c1a404")
+	assert.False(t, ok)
+}
+
+func TestLockOwnerKey(t *testing.T) {
+	assert.Equal(t, "lockowners/vol1/694d6f3bc4c1a404", lockOwnerKey("locks/vol1/694d6f3bc4c1a404"))
+}
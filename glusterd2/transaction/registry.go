@@ -41,3 +41,24 @@ func getStepFunc(name string) (StepFunc, bool) {
 	s, ok := sfRegistry.sfMap[name]
 	return s, ok
 }
+
+// UnregisterStepFunc removes the named StepFunc from the registry. Steps
+// running it fail with ErrStepFuncNotFound from then on.
+func UnregisterStepFunc(name string) {
+	sfRegistry.Lock()
+	defer sfRegistry.Unlock()
+
+	delete(sfRegistry.sfMap, name)
+}
+
+// StepFuncNames returns the names of the StepFuncs in the registry
+func StepFuncNames() []string {
+	sfRegistry.RLock()
+	defer sfRegistry.RUnlock()
+
+	names := make([]string, 0, len(sfRegistry.sfMap))
+	for name := range sfRegistry.sfMap {
+		names = append(names, name)
+	}
+	return names
+}
@@ -0,0 +1,19 @@
+package transaction
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnregisterStepFunc(t *testing.T) {
+	RegisterStepFunc(func(TxnCtx) error { return nil }, "test.Unregister")
+	_, ok := getStepFunc("test.Unregister")
+	assert.True(t, ok)
+	assert.Contains(t, StepFuncNames(), "test.Unregister")
+
+	UnregisterStepFunc("test.Unregister")
+	_, ok = getStepFunc("test.Unregister")
+	assert.False(t, ok)
+	assert.NotContains(t, StepFuncNames(), "test.Unregister")
+}
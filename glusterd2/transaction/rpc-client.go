@@ -4,50 +4,185 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"io"
+	"sync"
+	"time"
 
+	"github.com/gluster/glusterd2/glusterd2/events"
+	"github.com/gluster/glusterd2/glusterd2/fault"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
 	"github.com/gluster/glusterd2/glusterd2/peer"
+	"github.com/gluster/glusterd2/pkg/api"
 	"github.com/gluster/glusterd2/pkg/utils"
 
 	"github.com/pborman/uuid"
 	log "github.com/sirupsen/logrus"
+	flag "github.com/spf13/pflag"
+	config "github.com/spf13/viper"
 	"go.opencensus.io/plugin/ocgrpc"
+	"go.opencensus.io/trace"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/status"
 )
 
-// runStepOn will run the step on the specified node
-func runStepOn(origCtx context.Context, step string, node uuid.UUID, c TxnCtx) error {
-	// TODO: I'm creating connections on demand. This should be changed so that
-	// we have long term connections.
+const (
+	rpcTimeoutOpt = "peer-rpc-timeout"
+
+	// rpcConnectTimeout is the time a peer is given to be reachable,
+	// while the connection to it is retried with a backoff of at most
+	// rpcMaxBackoff. A step is only sent once the peer is reachable, and
+	// is never sent twice.
+	rpcConnectTimeout = 15 * time.Second
+	rpcMaxBackoff     = 3 * time.Second
+
+	// eventPeerRemoved is broadcast once a peer is removed from the
+	// cluster
+	eventPeerRemoved = "peer.removed"
+)
+
+// errPeerUnreachable is returned when the connection to a peer is not ready
+// within rpcConnectTimeout. It has the code of the errors of the RPCs to
+// unreachable peers, for the transactions to tell them apart.
+var errPeerUnreachable = status.Error(codes.Unavailable, "peer unreachable")
+
+// InitFlags intializes the command line options for the peer RPCs
+func InitFlags() {
+	flag.Duration(rpcTimeoutOpt, 0, "Time a transaction step run on another peer is given to complete, unless the step sets its own timeout. 0 does not bound the steps.")
+}
+
+// connPool holds the connections to the other peers, by peer ID, which are
+// reused by all the transactions. A connection reconnects by itself if the
+// peer goes away and comes back, resolving the name of the peer again if it
+// has moved to another IP address. It is closed once the peer is removed
+// from the cluster.
+type connPool struct {
+	sync.Mutex
+	conns map[string]*peerConn
+}
+
+// peerConn is a connection to a peer, along with the address connected to
+type peerConn struct {
+	remote string
+	conn   *grpc.ClientConn
+}
+
+var conns = connPool{conns: make(map[string]*peerConn)}
+
+func (p *connPool) get(peerID, remote string) (*grpc.ClientConn, error) {
+	p.Lock()
+	defer p.Unlock()
+
+	if pc, ok := p.conns[peerID]; ok {
+		if pc.remote == remote {
+			return pc.conn, nil
+		}
+		// The address of the peer changed
+		pc.conn.Close()
+		delete(p.conns, peerID)
+	}
+
+	conn, err := grpc.Dial(remote,
+		grpc.WithStatsHandler(&ocgrpc.ClientHandler{}),
+		grpc.WithInsecure(),
+		grpc.WithBackoffMaxDelay(rpcMaxBackoff),
+		grpc.WithDialer(peer.DialTimeout),
+	)
+	if err != nil {
+		return nil, err
+	}
+	p.conns[peerID] = &peerConn{remote: remote, conn: conn}
+	return conn, nil
+}
+
+// evict closes the connection to the peer, failing the RPCs in flight on it.
+// The next RPC to the peer connects again.
+func (p *connPool) evict(peerID string) {
+	p.Lock()
+	defer p.Unlock()
+
+	if pc, ok := p.conns[peerID]; ok {
+		pc.conn.Close()
+		delete(p.conns, peerID)
+	}
+}
+
+// EvictRemovedPeers closes the connections to the peers as they are removed
+// from the cluster. It is to be called once the events framework is started.
+func EvictRemovedPeers() {
+	events.Register(events.NewHandler(func(e *api.Event) {
+		conns.evict(e.Data["peer.id"])
+	}, eventPeerRemoved))
+}
+
+// stepTimeout returns the time given to the step to complete on a peer
+func stepTimeout(s *Step) time.Duration {
+	if s.Timeout != 0 {
+		return s.Timeout
+	}
+	return config.GetDuration(rpcTimeoutOpt)
+}
+
+// waitReady waits for the connection to the peer to be ready, or for
+// rpcConnectTimeout to elapse
+func waitReady(ctx context.Context, conn *grpc.ClientConn) error {
+	ctx, cancel := context.WithTimeout(ctx, rpcConnectTimeout)
+	defer cancel()
+
+	for {
+		state := conn.GetState()
+		if state == connectivity.Ready {
+			return nil
+		}
+		if state == connectivity.Shutdown || !conn.WaitForStateChange(ctx, state) {
+			return errPeerUnreachable
+		}
+	}
+}
+
+// runStepOn will run the step on the specified node. The results of a
+// streamed step are returned, by key in the transaction context.
+func runStepOn(origCtx context.Context, step string, node uuid.UUID, c TxnCtx, s *Step) (results map[string][]byte, err error) {
+	if origCtx != nil {
+		var span *trace.Span
+		origCtx, span = trace.StartSpan(origCtx, "RunStepOn/"+step)
+		span.AddAttributes(
+			trace.StringAttribute("reqID", c.GetTxnReqID()),
+			trace.StringAttribute("node", node.String()),
+		)
+		defer func() {
+			if err != nil {
+				span.SetStatus(trace.Status{Code: trace.StatusCodeUnknown, Message: err.Error()})
+			}
+			span.End()
+		}()
+	}
+
 	p, err := peer.GetPeerF(node.String())
 	if err != nil {
 		c.Logger().WithError(err).WithField("peerid", node.String()).Error("peer not found")
-		return err
+		return nil, err
 	}
 
 	logger := c.Logger().WithField("remotepeer", p.ID.String()+"("+p.Name+")")
 
-	var conn *grpc.ClientConn
-
 	remote, err := utils.FormRemotePeerAddress(p.PeerAddresses[0])
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	conn, err = grpc.Dial(remote,
-		grpc.WithStatsHandler(&ocgrpc.ClientHandler{}),
-		grpc.WithInsecure(),
-	)
-	if err == nil && conn != nil {
-		logger.WithFields(log.Fields{
-			"remote": remote,
-		}).Debug("connected to remote")
+	if err := fault.RPC(node.String()); err != nil {
+		conns.evict(p.ID.String())
+		logger.WithError(err).Warn("killed the connection to the peer")
+		return nil, err
 	}
 
-	if conn == nil {
+	conn, err := conns.get(p.ID.String(), remote)
+	if err != nil {
 		logger.WithError(err).WithField("remote", p.PeerAddresses[0]).Error("failed to grpc.Dial remote")
-		return err
+		return nil, err
 	}
-	defer conn.Close()
 
 	client := NewTxnSvcClient(conn)
 
@@ -57,22 +192,77 @@ func runStepOn(origCtx context.Context, step string, node uuid.UUID, c TxnCtx) e
 	data, err := json.Marshal(c)
 	if err != nil {
 		logger.WithError(err).Error("failed to JSON marshal transaction context")
-		return err
+		return nil, err
 	}
 	req.Context = data
 
-	var rsp *TxnStepResp
+	// The step is not interrupted if the client of the request goes away,
+	// the peer would carry on with it anyway. It is only given up on once
+	// its timeout elapses.
+	origCtx = gdctx.WithoutCancel(origCtx)
+	if timeout := stepTimeout(s); timeout > 0 {
+		var cancel context.CancelFunc
+		origCtx, cancel = context.WithTimeout(origCtx, timeout)
+		defer cancel()
+	}
+
+	if err = waitReady(origCtx, conn); err != nil {
+		logger.WithError(err).WithField("remote", remote).Error("failed to connect to remote")
+		return nil, err
+	}
+
+	return callStep(origCtx, client, req, s.Stream, logger)
+}
+
+// callStep runs the step with the RPC client. The results of a streamed step
+// are returned. Peers not serving the streaming RPC, from before it was
+// added, run the step with RunStep and commit its results to the store.
+func callStep(ctx context.Context, client TxnSvcClient, req *TxnStepReq, stream bool, logger log.FieldLogger) (map[string][]byte, error) {
+	if stream {
+		results, err := recvStepResults(ctx, client, req)
+		if grpc.Code(err) != codes.Unimplemented {
+			if err != nil {
+				logger.WithError(err).WithField("rpc", "TxnSvc.RunStepStream").Error("failed RPC call")
+			}
+			return results, err
+		}
+		logger.Debug("peer does not serve TxnSvc.RunStepStream, falling back to TxnSvc.RunStep")
+	}
 
-	rsp, err = client.RunStep(origCtx, req)
+	rsp, err := client.RunStep(ctx, req)
 	if err != nil {
 		logger.WithError(err).WithField("rpc", "TxnSvc.RunStep").Error("failed RPC call")
-		return err
+		return nil, err
 	}
 
 	if rsp.Error != "" {
 		logger.WithError(errors.New(rsp.Error)).Error("TxnSvc.Runstep failed on peer")
-		return errors.New(rsp.Error)
+		return nil, errors.New(rsp.Error)
 	}
 
-	return nil
+	return nil, nil
+}
+
+// recvStepResults runs the step with the streaming RPC, and returns the
+// results received, reassembled from their chunks
+func recvStepResults(ctx context.Context, client TxnSvcClient, req *TxnStepReq) (map[string][]byte, error) {
+	stream, err := client.RunStepStream(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(map[string][]byte)
+	for {
+		rsp, err := stream.Recv()
+		if err == io.EOF {
+			return results, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if rsp.Error != "" {
+			return nil, errors.New(rsp.Error)
+		}
+		results[rsp.Key] = append(results[rsp.Key], rsp.Value...)
+	}
 }
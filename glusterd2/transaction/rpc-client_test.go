@@ -0,0 +1,181 @@
+package transaction
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+	config "github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/status"
+)
+
+// fakeStepClient serves RunStepStream with the results and the error given
+type fakeStepClient struct {
+	TxnSvcClient
+	results []*TxnStepResult
+	err     error
+}
+
+func (c *fakeStepClient) RunStepStream(ctx context.Context, in *TxnStepReq, opts ...grpc.CallOption) (TxnSvc_RunStepStreamClient, error) {
+	return &fakeStepStreamClient{results: c.results, err: c.err}, nil
+}
+
+type fakeStepStreamClient struct {
+	grpc.ClientStream
+	results []*TxnStepResult
+	err     error
+}
+
+func (s *fakeStepStreamClient) Recv() (*TxnStepResult, error) {
+	if len(s.results) == 0 {
+		if s.err != nil {
+			return nil, s.err
+		}
+		return nil, io.EOF
+	}
+	r := s.results[0]
+	s.results = s.results[1:]
+	return r, nil
+}
+
+func TestStepTimeout(t *testing.T) {
+	defer config.Set(rpcTimeoutOpt, config.Get(rpcTimeoutOpt))
+
+	config.Set(rpcTimeoutOpt, time.Minute)
+	assert.Equal(t, time.Minute, stepTimeout(&Step{}))
+	// The timeout of the step is used instead of the option
+	assert.Equal(t, 5*time.Second, stepTimeout(&Step{Timeout: 5 * time.Second}))
+
+	config.Set(rpcTimeoutOpt, time.Duration(0))
+	assert.Equal(t, time.Duration(0), stepTimeout(&Step{}))
+}
+
+func TestRecvStepResults(t *testing.T) {
+	// The chunks of the values are reassembled
+	client := &fakeStepClient{results: []*TxnStepResult{
+		{Key: "n1/bulk", Value: []byte("ab")},
+		{Key: "n1/other", Value: []byte("x")},
+		{Key: "n1/bulk", Value: []byte("cd")},
+	}}
+	results, err := recvStepResults(context.Background(), client, &TxnStepReq{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"n1/bulk": []byte("abcd"), "n1/other": []byte("x")}, results)
+
+	// A step sending no key has no result
+	results, err = recvStepResults(context.Background(), &fakeStepClient{}, &TxnStepReq{})
+	require.NoError(t, err)
+	assert.Empty(t, results)
+
+	// The error of the step is sent in the body of the response
+	client = &fakeStepClient{results: []*TxnStepResult{{Error: "step failed"}}}
+	_, err = recvStepResults(context.Background(), client, &TxnStepReq{})
+	assert.EqualError(t, err, "step failed")
+
+	// The results are dropped if the stream breaks
+	client = &fakeStepClient{
+		results: []*TxnStepResult{{Key: "n1/bulk", Value: []byte("ab")}},
+		err:     errors.New("stream broken"),
+	}
+	results, err = recvStepResults(context.Background(), client, &TxnStepReq{})
+	assert.EqualError(t, err, "stream broken")
+	assert.Nil(t, results)
+}
+
+func TestWaitReady(t *testing.T) {
+	conn, err := grpc.Dial("127.0.0.1:1", grpc.WithInsecure())
+	require.NoError(t, err)
+	conn.Close()
+
+	// A peer not reachable is reported as unavailable, as are the RPCs
+	// failing to reach a peer
+	err = waitReady(context.Background(), conn)
+	assert.Equal(t, errPeerUnreachable, err)
+	assert.Equal(t, codes.Unavailable, grpc.Code(err))
+
+	resp := &stepResp{Step: "test.Step", Resps: []stepPeerResp{{PeerID: uuid.NewRandom(), Error: err}}, errCount: 1}
+	assert.True(t, isNodeUnreachable(resp))
+	apiResp := resp.Response()
+	require.Len(t, apiResp.Errors, 1)
+	assert.Equal(t, api.ReasonPeerUnreachable, apiResp.Errors[0].Reason)
+
+	// A step failing on a reachable peer is not
+	resp.Resps = append(resp.Resps, stepPeerResp{PeerID: uuid.NewRandom(), Error: errors.New("step failed")})
+	resp.errCount++
+	assert.False(t, isNodeUnreachable(resp))
+	apiResp = resp.Response()
+	require.Len(t, apiResp.Errors, 2)
+	assert.Empty(t, apiResp.Errors[1].Reason)
+}
+
+func TestConnPool(t *testing.T) {
+	pool := connPool{conns: make(map[string]*peerConn)}
+	peerID := uuid.New()
+
+	// The connections to a peer are reused
+	conn, err := pool.get(peerID, "127.0.0.1:1")
+	require.NoError(t, err)
+	again, err := pool.get(peerID, "127.0.0.1:1")
+	require.NoError(t, err)
+	assert.True(t, conn == again)
+
+	// A peer whose address changed is connected again
+	moved, err := pool.get(peerID, "127.0.0.1:2")
+	require.NoError(t, err)
+	assert.False(t, conn == moved)
+	assert.Equal(t, connectivity.Shutdown, conn.GetState())
+
+	// The connection to a removed peer is closed
+	pool.evict(peerID)
+	assert.Empty(t, pool.conns)
+	assert.Equal(t, connectivity.Shutdown, moved.GetState())
+}
+
+// fakeUnimplementedClient does not serve RunStepStream, as the peers from
+// before it was added
+type fakeUnimplementedClient struct {
+	TxnSvcClient
+	ran bool
+}
+
+func (c *fakeUnimplementedClient) RunStepStream(ctx context.Context, in *TxnStepReq, opts ...grpc.CallOption) (TxnSvc_RunStepStreamClient, error) {
+	return &fakeStepStreamClient{err: status.Error(codes.Unimplemented, "unknown method RunStepStream")}, nil
+}
+
+func (c *fakeUnimplementedClient) RunStep(ctx context.Context, in *TxnStepReq, opts ...grpc.CallOption) (*TxnStepResp, error) {
+	c.ran = true
+	return &TxnStepResp{}, nil
+}
+
+func TestCallStepFallback(t *testing.T) {
+	// A streamed step is run with RunStep on the peers not serving
+	// RunStepStream
+	client := new(fakeUnimplementedClient)
+	results, err := callStep(context.Background(), client, &TxnStepReq{}, true, log.StandardLogger())
+	require.NoError(t, err)
+	assert.True(t, client.ran)
+	assert.Nil(t, results)
+
+	// Other errors of the stream fail the step
+	_, err = callStep(context.Background(), &fakeStepClient{err: errors.New("stream broken")}, &TxnStepReq{}, true, log.StandardLogger())
+	assert.EqualError(t, err, "stream broken")
+}
+
+func TestSetStepResults(t *testing.T) {
+	// The results are added to contexts other than Tctx with Set
+	ctx := NewSimulation(1).Ctx()
+	require.NoError(t, setStepResults(ctx, map[string][]byte{"n1/bulk": []byte(`"abcd"`)}))
+	var bulk string
+	require.NoError(t, ctx.Get("n1/bulk", &bulk))
+	assert.Equal(t, "abcd", bulk)
+}
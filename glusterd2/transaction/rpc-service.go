@@ -4,7 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"strings"
+	"sync"
 
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
 	"github.com/gluster/glusterd2/glusterd2/servers/peerrpc"
 
 	log "github.com/sirupsen/logrus"
@@ -18,52 +21,116 @@ func init() {
 	peerrpc.Register(new(txnSvc))
 }
 
-// RunStep handles the incoming request. It executes the requested step and returns the results
-func (p *txnSvc) RunStep(rpcCtx context.Context, req *TxnStepReq) (*TxnStepResp, error) {
+// streamChunkSize is the size of the chunks the values of the keys set by a
+// streamed step are sent in, kept below the maximum size of a gRPC message
+const streamChunkSize = 1024 * 1024
+
+// txnSteps serializes the steps of a transaction run on this node, by the
+// store prefix of the transaction. A step whose RPC was cancelled by the
+// initiator may still be running when the initiator sends the undo of the
+// step, which waits for it to return.
+type txnSteps struct {
+	sync.Mutex
+	locks map[string]*txnStepLock
+}
 
-	var (
-		resp   TxnStepResp
-		f      StepFunc
-		err    error
-		ok     bool
-		logger log.FieldLogger
-	)
+type txnStepLock struct {
+	sync.Mutex
+	refs int
+}
+
+var running = txnSteps{locks: make(map[string]*txnStepLock)}
+
+// lock waits for the other steps of the transaction to return, and returns
+// the function to call once the step returns
+func (t *txnSteps) lock(txn string) func() {
+	t.Lock()
+	l, ok := t.locks[txn]
+	if !ok {
+		l = new(txnStepLock)
+		t.locks[txn] = l
+	}
+	l.refs++
+	t.Unlock()
+
+	l.Lock()
+	return func() {
+		l.Unlock()
+
+		t.Lock()
+		defer t.Unlock()
+		l.refs--
+		if l.refs == 0 {
+			delete(t.locks, txn)
+		}
+	}
+}
 
+// runStep executes the requested step, and returns its context. The keys
+// set by a step whose RPC was cancelled while it ran are dropped, as the
+// initiator considers the step failed.
+func (p *txnSvc) runStep(rpcCtx context.Context, req *TxnStepReq) (*Tctx, error) {
 	var ctx Tctx
-	if err = json.Unmarshal(req.Context, &ctx); err != nil {
+	if err := json.Unmarshal(req.Context, &ctx); err != nil {
 		log.WithError(err).Error("failed to Unmarshal transaction context")
-		goto End
+		return nil, err
 	}
 
-	logger = ctx.Logger().WithField("stepfunc", req.StepFunc)
+	logger := ctx.Logger().WithField("stepfunc", req.StepFunc)
 	logger.Debug("RunStep request received")
 
+	// The store calls of the step are traced as part of the request of
+	// the initiator, and are not interrupted if the initiator gives up
+	ctx.ctx = gdctx.WithoutCancel(rpcCtx)
+
 	if rpcCtx != nil {
 		_, span := trace.StartSpan(rpcCtx, req.StepFunc)
 		reqID := ctx.GetTxnReqID()
 		span.AddAttributes(
 			trace.StringAttribute("reqID", reqID),
+			trace.StringAttribute("node", gdctx.MyUUID.String()),
 		)
 		defer span.End()
 	}
 
-	f, ok = getStepFunc(req.StepFunc)
+	f, ok := getStepFunc(req.StepFunc)
 	if !ok {
-		err = errors.New("step function not found in registry")
-		goto End
+		return nil, errors.New("step function not found in registry")
+	}
+
+	unlock := running.lock(ctx.config.StorePrefix)
+	defer unlock()
+
+	if rpcCtx != nil && rpcCtx.Err() != nil {
+		logger.WithError(rpcCtx.Err()).Error("step cancelled by the initiator")
+		return nil, rpcCtx.Err()
 	}
 
 	logger.Debug("executing step function")
-	if err = f(&ctx); err != nil {
+	if err := f(&ctx); err != nil {
 		logger.WithError(err).Error("step function failed")
-		goto End
+		return nil, err
 	}
 
-	if err = ctx.Commit(); err != nil {
-		logger.WithError(err).Error("failed to commit txn context to store")
+	if rpcCtx != nil && rpcCtx.Err() != nil {
+		logger.WithError(rpcCtx.Err()).Error("step cancelled by the initiator while running, dropping its results")
+		return nil, rpcCtx.Err()
+	}
+
+	return &ctx, nil
+}
+
+// RunStep handles the incoming request. It executes the requested step and returns the results
+func (p *txnSvc) RunStep(rpcCtx context.Context, req *TxnStepReq) (*TxnStepResp, error) {
+	var resp TxnStepResp
+
+	ctx, err := p.runStep(rpcCtx, req)
+	if err == nil {
+		if err = ctx.Commit(); err != nil {
+			ctx.Logger().WithError(err).Error("failed to commit txn context to store")
+		}
 	}
 
-End:
 	// Ensure RPC will always send a success reply. Error is stored in
 	// body of response.
 	if err != nil {
@@ -73,6 +140,34 @@ End:
 	return &resp, nil
 }
 
+// RunStepStream handles the incoming request for a streamed step. It
+// executes the requested step and sends the keys it set back in chunks,
+// instead of committing them to the store.
+func (p *txnSvc) RunStepStream(req *TxnStepReq, stream TxnSvc_RunStepStreamServer) error {
+	ctx, err := p.runStep(stream.Context(), req)
+	if err != nil {
+		// As with RunStep, the error is stored in the body of the
+		// response
+		return stream.Send(&TxnStepResult{Error: err.Error()})
+	}
+
+	for storeKey, value := range ctx.writeSet {
+		key := strings.TrimPrefix(storeKey, ctx.config.StorePrefix)
+		for i := 0; i == 0 || i < len(value); i += streamChunkSize {
+			end := i + streamChunkSize
+			if end > len(value) {
+				end = len(value)
+			}
+			if err := stream.Send(&TxnStepResult{Key: key, Value: []byte(value[i:end])}); err != nil {
+				ctx.Logger().WithError(err).WithField("key", key).Error("failed to send step result")
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
 // RegisterService registers txnSvc with the given grpc.Server
 func (p *txnSvc) RegisterService(s *grpc.Server) {
 	RegisterTxnSvcServer(s, p)
@@ -0,0 +1,123 @@
+package transaction
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+// fakeStepStream collects the results sent by RunStepStream
+type fakeStepStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent []*TxnStepResult
+}
+
+func (s *fakeStepStream) Send(m *TxnStepResult) error {
+	s.sent = append(s.sent, m)
+	return nil
+}
+
+func (s *fakeStepStream) Context() context.Context {
+	if s.ctx != nil {
+		return s.ctx
+	}
+	return context.Background()
+}
+
+func init() {
+	RegisterStepFunc(func(c TxnCtx) error {
+		return c.SetNodeResult(gdctx.MyUUID, "bulk", strings.Repeat("x", 2*streamChunkSize+10))
+	}, "stream-test.Bulk")
+}
+
+func TestRunStepStream(t *testing.T) {
+	config := &TxnCtxConfig{
+		LogFields:   log.Fields{"txnid": uuid.New(), "reqid": uuid.New()},
+		StorePrefix: txnPrefix + uuid.New() + "/",
+	}
+	data, err := json.Marshal(config)
+	require.NoError(t, err)
+
+	// The value is sent in chunks, and reassembled into the context of
+	// the initiator
+	stream := new(fakeStepStream)
+	require.NoError(t, new(txnSvc).RunStepStream(&TxnStepReq{StepFunc: "stream-test.Bulk", Context: data}, stream))
+	require.Len(t, stream.sent, 3)
+
+	results := make(map[string][]byte)
+	for _, m := range stream.sent {
+		assert.Empty(t, m.Error)
+		assert.Equal(t, gdctx.MyUUID.String()+"/bulk", m.Key)
+		results[m.Key] = append(results[m.Key], m.Value...)
+	}
+
+	c := newCtx(config)
+	c.readCacheDirty = false
+	require.NoError(t, setStepResults(c, results))
+	assert.Empty(t, c.writeSet)
+
+	var bulk string
+	require.NoError(t, c.GetNodeResult(gdctx.MyUUID, "bulk", &bulk))
+	assert.Len(t, bulk, 2*streamChunkSize+10)
+
+	// Errors are sent in the body of the response
+	stream = new(fakeStepStream)
+	require.NoError(t, new(txnSvc).RunStepStream(&TxnStepReq{StepFunc: "stream-test.Missing", Context: data}, stream))
+	require.Len(t, stream.sent, 1)
+	assert.NotEmpty(t, stream.sent[0].Error)
+}
+
+func TestRunStepStreamCancelled(t *testing.T) {
+	config := &TxnCtxConfig{
+		LogFields:   log.Fields{"txnid": uuid.New(), "reqid": uuid.New()},
+		StorePrefix: txnPrefix + uuid.New() + "/",
+	}
+	data, err := json.Marshal(config)
+	require.NoError(t, err)
+
+	// A step cancelled by the initiator is not run, and sends no result
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	stream := &fakeStepStream{ctx: ctx}
+	require.NoError(t, new(txnSvc).RunStepStream(&TxnStepReq{StepFunc: "stream-test.Bulk", Context: data}, stream))
+	require.Len(t, stream.sent, 1)
+	assert.Equal(t, context.Canceled.Error(), stream.sent[0].Error)
+}
+
+func TestTxnStepsLock(t *testing.T) {
+	steps := txnSteps{locks: make(map[string]*txnStepLock)}
+
+	// The undo of a step waits for the step to return
+	unlock := steps.lock("txn1")
+	undone := make(chan struct{})
+	go func() {
+		defer close(undone)
+		steps.lock("txn1")()
+	}()
+
+	// The steps of other transactions do not
+	steps.lock("txn2")()
+
+	select {
+	case <-undone:
+		t.Fatal("undo ran while the step was running")
+	case <-time.After(50 * time.Millisecond):
+	}
+	unlock()
+	<-undone
+
+	steps.Lock()
+	assert.Empty(t, steps.locks)
+	steps.Unlock()
+}
@@ -0,0 +1,394 @@
+package transaction
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	// ErrSimulatedTimeout is returned by steps made to time out in a
+	// Simulation
+	ErrSimulatedTimeout = status.Error(codes.DeadlineExceeded, "simulated step timeout")
+	// ErrSimulatedCrash is returned by steps run on a crashed peer of a
+	// Simulation. Like an unreachable peer, it is ignored by transactions
+	// with DontCheckAlive set.
+	ErrSimulatedCrash = status.Error(codes.Unavailable, "simulated peer crash")
+)
+
+// SimulatedCall is a run of a step function on a peer of a Simulation
+type SimulatedCall struct {
+	Step string
+	Peer uuid.UUID
+	Undo bool
+	Err  error
+}
+
+type faultKind int
+
+const (
+	faultFail faultKind = iota
+	faultTimeout
+)
+
+type simFault struct {
+	kind faultKind
+	err  error
+}
+
+// Simulation runs the steps of transactions as Txn.Do does, but against an
+// in-memory transaction context and fake peers, so that step functions can
+// be tested without a cluster. Failures, timeouts and crashes of peers can be
+// injected to test the rollback of the transactions.
+//
+// The steps are run on the peers one after the other, with gdctx.MyUUID set
+// to the peer the step runs on. Simulations must hence not run in parallel,
+// with each other or with real transactions. The step functions still use
+// the store of glusterd2 as they would in a cluster.
+type Simulation struct {
+	// Peers are the fake peers the steps can be run on
+	Peers []uuid.UUID
+	// StepTimeout fails the steps running longer than it on a peer with
+	// ErrSimulatedTimeout, if set
+	StepTimeout time.Duration
+
+	mu         sync.Mutex
+	data       map[string][]byte
+	ctx        *simCtx
+	faults     map[string]simFault
+	crashed    map[string]bool
+	crashAfter map[string]string
+	calls      []SimulatedCall
+}
+
+// NewSimulation returns a Simulation with the given number of fake peers
+func NewSimulation(numPeers int) *Simulation {
+	s := &Simulation{
+		data:       make(map[string][]byte),
+		faults:     make(map[string]simFault),
+		crashed:    make(map[string]bool),
+		crashAfter: make(map[string]string),
+	}
+	for i := 0; i < numPeers; i++ {
+		s.Peers = append(s.Peers, uuid.NewRandom())
+	}
+	s.ctx = s.newCtx()
+	return s
+}
+
+// Ctx returns the transaction context of the initiator of the transactions,
+// used to pass inputs to the steps and to get their results
+func (s *Simulation) Ctx() TxnCtx {
+	return s.ctx
+}
+
+func faultKey(step string, peer uuid.UUID) string {
+	if peer == nil {
+		return step
+	}
+	return step + "/" + peer.String()
+}
+
+// FailStep makes the step fail with the given error on the peer, or on all
+// the peers if peer is nil, without running it
+func (s *Simulation) FailStep(step string, peer uuid.UUID, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults[faultKey(step, peer)] = simFault{kind: faultFail, err: err}
+}
+
+// TimeoutStep makes the step time out on the peer, or on all the peers if
+// peer is nil. The step is run, but its results are lost as when the
+// response of a peer does not arrive in time.
+func (s *Simulation) TimeoutStep(step string, peer uuid.UUID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults[faultKey(step, peer)] = simFault{kind: faultTimeout}
+}
+
+// ClearFaults removes the injected failures and timeouts
+func (s *Simulation) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = make(map[string]simFault)
+}
+
+// CrashPeer crashes the peer, failing the steps run on it with
+// ErrSimulatedCrash until it is restarted
+func (s *Simulation) CrashPeer(peer uuid.UUID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.crashed[peer.String()] = true
+}
+
+// CrashPeerAfter crashes the peer once the step succeeds on it, leaving it
+// unable to undo the step
+func (s *Simulation) CrashPeerAfter(peer uuid.UUID, step string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.crashAfter[peer.String()] = step
+}
+
+// RestartPeer brings a crashed peer back
+func (s *Simulation) RestartPeer(peer uuid.UUID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.crashed, peer.String())
+	delete(s.crashAfter, peer.String())
+}
+
+// Calls returns the runs of step functions on the peers, in the order they
+// happened
+func (s *Simulation) Calls() []SimulatedCall {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]SimulatedCall(nil), s.calls...)
+}
+
+// Run runs the steps of the transaction on the fake peers, honouring its
+// DontCheckAlive and DisableRollback flags. The context of the transaction is
+// set to the one returned by Ctx.
+func (s *Simulation) Run(t *Txn) error {
+	t.Ctx = s.ctx
+
+	if !t.DontCheckAlive {
+		nodes := t.Nodes
+		if len(nodes) == 0 {
+			for _, step := range t.Steps {
+				nodes = append(nodes, step.Nodes...)
+			}
+		}
+		for _, node := range nodesUnion(nodes) {
+			if s.isCrashed(node) {
+				return fmt.Errorf("node %s is probably down", node.String())
+			}
+		}
+	}
+
+	if err := s.ctx.Commit(); err != nil {
+		return err
+	}
+
+	for i, step := range t.Steps {
+		if step.Skip {
+			continue
+		}
+
+		if err := t.canceled(); err != nil {
+			if !t.DisableRollback {
+				s.undo(t, i-1)
+			}
+			return err
+		}
+
+		if err := s.runStep(step.DoFunc, step.Nodes, false); err != nil {
+			if t.DontCheckAlive && isNodeUnreachable(err) {
+				continue
+			}
+			if !t.DisableRollback {
+				s.undo(t, i)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// undo undoes the steps of the transaction from the nth backwards, as
+// Txn.undo does
+func (s *Simulation) undo(t *Txn, n int) {
+	for j := n; j >= 0; j-- {
+		if t.Steps[j].Skip || t.Steps[j].UndoFunc == "" {
+			continue
+		}
+		s.runStep(t.Steps[j].UndoFunc, t.Steps[j].Nodes, true)
+	}
+}
+
+func (s *Simulation) isCrashed(peer uuid.UUID) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.crashed[peer.String()]
+}
+
+func (s *Simulation) isPeer(peer uuid.UUID) bool {
+	for _, p := range s.Peers {
+		if uuid.Equal(p, peer) {
+			return true
+		}
+	}
+	return false
+}
+
+// runStep runs the step function on the nodes, returning the same error as
+// a step failing on some of the nodes of a real transaction
+func (s *Simulation) runStep(stepName string, nodes []uuid.UUID, undo bool) error {
+	resp := stepResp{Step: stepName}
+	for _, node := range nodes {
+		err := s.runStepOnPeer(stepName, node)
+		if err != nil {
+			resp.errCount++
+			s.ctx.Logger().WithError(err).WithFields(log.Fields{
+				"step": stepName, "node": node,
+			}).Error("Step failed on node.")
+		}
+		resp.Resps = append(resp.Resps, stepPeerResp{PeerID: node, Error: err})
+
+		s.mu.Lock()
+		s.calls = append(s.calls, SimulatedCall{Step: stepName, Peer: node, Undo: undo, Err: err})
+		s.mu.Unlock()
+	}
+
+	if resp.errCount != 0 {
+		return resp
+	}
+	return nil
+}
+
+func (s *Simulation) runStepOnPeer(stepName string, peer uuid.UUID) error {
+	if !s.isPeer(peer) {
+		return fmt.Errorf("peer %s not found", peer.String())
+	}
+	if s.isCrashed(peer) {
+		return ErrSimulatedCrash
+	}
+
+	s.mu.Lock()
+	f, ok := s.faults[faultKey(stepName, peer)]
+	if !ok {
+		f, ok = s.faults[faultKey(stepName, nil)]
+	}
+	s.mu.Unlock()
+	if ok && f.kind == faultFail {
+		return f.err
+	}
+
+	stepFunc, found := getStepFunc(stepName)
+	if !found {
+		return ErrStepFuncNotFound
+	}
+
+	myUUID := gdctx.MyUUID
+	gdctx.MyUUID = peer
+	defer func() { gdctx.MyUUID = myUUID }()
+
+	c := s.newCtx()
+	var err error
+	if s.StepTimeout > 0 {
+		errCh := make(chan error, 1)
+		go func() { errCh <- stepFunc(c) }()
+		select {
+		case err = <-errCh:
+		case <-time.After(s.StepTimeout):
+			return ErrSimulatedTimeout
+		}
+	} else {
+		err = stepFunc(c)
+	}
+	if err != nil {
+		return err
+	}
+	if ok && f.kind == faultTimeout {
+		return ErrSimulatedTimeout
+	}
+
+	if err := c.Commit(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	if s.crashAfter[peer.String()] == stepName {
+		s.crashed[peer.String()] = true
+	}
+	s.mu.Unlock()
+	return nil
+}
+
+// simCtx is the transaction context of a Simulation. As with the context of a
+// real transaction, the keys set by a step are seen by the following steps
+// only once the step succeeds.
+type simCtx struct {
+	sim      *Simulation
+	logger   log.FieldLogger
+	reqID    string
+	writeSet map[string][]byte
+}
+
+func (s *Simulation) newCtx() *simCtx {
+	return &simCtx{
+		sim:      s,
+		logger:   log.StandardLogger().WithField("simulation", true),
+		reqID:    uuid.NewRandom().String(),
+		writeSet: make(map[string][]byte),
+	}
+}
+
+func (c *simCtx) Set(key string, value interface{}) error {
+	b, err := json.Marshal(value)
+	if err != nil {
+		c.logger.WithError(err).WithField("key", key).Error("failed to marshal value")
+		return err
+	}
+	c.writeSet[key] = b
+	return nil
+}
+
+func (c *simCtx) SetNodeResult(peerID uuid.UUID, key string, value interface{}) error {
+	return c.Set(peerID.String()+"/"+key, value)
+}
+
+func (c *simCtx) Get(key string, value interface{}) error {
+	data, ok := c.writeSet[key]
+	if !ok {
+		c.sim.mu.Lock()
+		data, ok = c.sim.data[key]
+		c.sim.mu.Unlock()
+	}
+	if !ok {
+		return errors.New("key not found")
+	}
+	return json.Unmarshal(data, value)
+}
+
+func (c *simCtx) GetNodeResult(peerID uuid.UUID, key string, value interface{}) error {
+	return c.Get(peerID.String()+"/"+key, value)
+}
+
+func (c *simCtx) GetTxnReqID() string {
+	return c.reqID
+}
+
+func (c *simCtx) Delete(key string) error {
+	delete(c.writeSet, key)
+	c.sim.mu.Lock()
+	delete(c.sim.data, key)
+	c.sim.mu.Unlock()
+	return nil
+}
+
+func (c *simCtx) Logger() log.FieldLogger {
+	return c.logger
+}
+
+func (c *simCtx) Commit() error {
+	c.sim.mu.Lock()
+	defer c.sim.mu.Unlock()
+	for k, v := range c.writeSet {
+		c.sim.data[k] = v
+	}
+	c.writeSet = make(map[string][]byte)
+	return nil
+}
+
+func (c *simCtx) SyncCache() error {
+	return nil
+}
@@ -0,0 +1,139 @@
+package transaction
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	RegisterStepFunc(func(c TxnCtx) error {
+		var in string
+		if err := c.Get("in", &in); err != nil {
+			return err
+		}
+		return c.SetNodeResult(gdctx.MyUUID, "out", in+"-"+gdctx.MyUUID.String())
+	}, "sim-test.Echo")
+	RegisterStepFunc(func(c TxnCtx) error {
+		return c.SetNodeResult(gdctx.MyUUID, "undone", true)
+	}, "sim-test.Undo")
+	RegisterStepFunc(func(c TxnCtx) error {
+		return nil
+	}, "sim-test.Noop")
+	RegisterStepFunc(func(c TxnCtx) error {
+		time.Sleep(time.Second)
+		return nil
+	}, "sim-test.Slow")
+}
+
+func TestSimulationRun(t *testing.T) {
+	sim := NewSimulation(2)
+	require.NoError(t, sim.Ctx().Set("in", "hello"))
+
+	txn := &Txn{Steps: []*Step{{DoFunc: "sim-test.Echo", Nodes: sim.Peers}}}
+	require.NoError(t, sim.Run(txn))
+
+	for _, p := range sim.Peers {
+		var out string
+		require.NoError(t, txn.Ctx.GetNodeResult(p, "out", &out))
+		assert.Equal(t, "hello-"+p.String(), out)
+	}
+	assert.Len(t, sim.Calls(), 2)
+}
+
+func TestSimulationRollback(t *testing.T) {
+	sim := NewSimulation(2)
+	injected := errors.New("injected")
+	sim.FailStep("sim-test.Noop", sim.Peers[1], injected)
+
+	txn := &Txn{Steps: []*Step{
+		{DoFunc: "sim-test.Noop", UndoFunc: "sim-test.Undo", Nodes: sim.Peers[:1]},
+		{DoFunc: "sim-test.Noop", UndoFunc: "sim-test.Undo", Nodes: sim.Peers[1:]},
+	}}
+	err := sim.Run(txn)
+	require.Error(t, err)
+
+	// Both steps are undone, from the failed one backwards
+	calls := sim.Calls()
+	require.Len(t, calls, 4)
+	assert.Equal(t, injected, calls[1].Err)
+	assert.True(t, calls[2].Undo)
+	assert.True(t, uuid.Equal(sim.Peers[1], calls[2].Peer))
+	assert.True(t, calls[3].Undo)
+	assert.True(t, uuid.Equal(sim.Peers[0], calls[3].Peer))
+
+	var undone bool
+	assert.NoError(t, txn.Ctx.GetNodeResult(sim.Peers[0], "undone", &undone))
+	assert.True(t, undone)
+}
+
+func TestSimulationCrash(t *testing.T) {
+	sim := NewSimulation(2)
+	sim.CrashPeer(sim.Peers[1])
+
+	txn := &Txn{Steps: []*Step{{DoFunc: "sim-test.Noop", Nodes: sim.Peers}}}
+	assert.Error(t, sim.Run(txn))
+	assert.Empty(t, sim.Calls())
+
+	sim.RestartPeer(sim.Peers[1])
+	sim.CrashPeerAfter(sim.Peers[1], "sim-test.Noop")
+	txn = &Txn{Steps: []*Step{
+		{DoFunc: "sim-test.Noop", UndoFunc: "sim-test.Undo", Nodes: sim.Peers},
+		{DoFunc: "sim-test.Echo", Nodes: sim.Peers[1:]},
+	}}
+	assert.Error(t, sim.Run(txn))
+
+	// The crashed peer cannot undo the first step
+	calls := sim.Calls()
+	last := calls[len(calls)-1]
+	assert.True(t, last.Undo)
+	assert.Equal(t, ErrSimulatedCrash, last.Err)
+}
+
+func TestSimulationTimeout(t *testing.T) {
+	sim := NewSimulation(1)
+	sim.TimeoutStep("sim-test.Noop", nil)
+	assert.Error(t, sim.Run(&Txn{Steps: []*Step{{DoFunc: "sim-test.Noop", Nodes: sim.Peers}}}))
+
+	sim.ClearFaults()
+	sim.StepTimeout = 10 * time.Millisecond
+	err := sim.Run(&Txn{Steps: []*Step{{DoFunc: "sim-test.Slow", Nodes: sim.Peers}}})
+	assert.Error(t, err)
+	calls := sim.Calls()
+	assert.Equal(t, ErrSimulatedTimeout, calls[len(calls)-1].Err)
+}
+
+func TestSimulationCanceled(t *testing.T) {
+	sim := NewSimulation(2)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// No step is run once the request is cancelled
+	txn := &Txn{OrigCtx: ctx, Steps: []*Step{{DoFunc: "sim-test.Noop", Nodes: sim.Peers}}}
+	assert.Equal(t, context.Canceled, sim.Run(txn))
+	assert.Empty(t, sim.Calls())
+
+	// The steps done before are undone
+	ctx, cancel = context.WithCancel(context.Background())
+	RegisterStepFunc(func(c TxnCtx) error {
+		cancel()
+		return nil
+	}, "sim-test.Cancel")
+	txn = &Txn{OrigCtx: ctx, Steps: []*Step{
+		{DoFunc: "sim-test.Cancel", UndoFunc: "sim-test.Undo", Nodes: sim.Peers[:1]},
+		{DoFunc: "sim-test.Noop", Nodes: sim.Peers},
+	}}
+	assert.Equal(t, context.Canceled, sim.Run(txn))
+
+	calls := sim.Calls()
+	require.Len(t, calls, 2)
+	assert.Equal(t, "sim-test.Cancel", calls[0].Step)
+	assert.True(t, calls[1].Undo)
+}
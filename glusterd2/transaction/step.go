@@ -2,16 +2,22 @@ package transaction
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
+	"github.com/gluster/glusterd2/glusterd2/fault"
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/simulate"
 	"github.com/gluster/glusterd2/pkg/api"
 
 	"github.com/pborman/uuid"
 	log "github.com/sirupsen/logrus"
 	"go.opencensus.io/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 )
 
 // StepFunc is the function that is supposed to be run during a transaction step
@@ -22,12 +28,19 @@ type StepFunc func(TxnCtx) error
 // DoFunc and UndoFunc are names of StepFuncs registered in the registry
 // DoFunc performs does the action
 // UndoFunc undoes anything done by DoFunc
+// Timeout is the time the step is given to complete on a remote node,
+// instead of the peer-rpc-timeout option
+// Stream sends the keys set by the step on remote nodes back to the
+// initiator over the RPC, instead of committing them to the store. This is
+// meant for steps returning bulk data with SetNodeResult.
 type Step struct {
 	DoFunc   string
 	UndoFunc string
 	Nodes    []uuid.UUID
 	Skip     bool
 	Sync     bool
+	Timeout  time.Duration
+	Stream   bool
 }
 
 var (
@@ -37,13 +50,13 @@ var (
 
 // do runs the DoFunc on the nodes
 func (s *Step) do(origCtx context.Context, ctx TxnCtx) error {
-	return runStepFuncOnNodes(origCtx, s.DoFunc, ctx, s.Nodes)
+	return runStepFuncOnNodes(origCtx, s.DoFunc, ctx, s)
 }
 
 // undo runs the UndoFunc on the nodes
 func (s *Step) undo(ctx TxnCtx) error {
 	if s.UndoFunc != "" {
-		return runStepFuncOnNodes(context.TODO(), s.UndoFunc, ctx, s.Nodes)
+		return runStepFuncOnNodes(context.TODO(), s.UndoFunc, ctx, s)
 	}
 	return nil
 }
@@ -52,6 +65,8 @@ func (s *Step) undo(ctx TxnCtx) error {
 type stepPeerResp struct {
 	PeerID uuid.UUID
 	Error  error
+	// Results are the keys set by a streamed step on the peer
+	Results map[string][]byte
 }
 
 // stepResp contains response from multiple peers that run a step and the type
@@ -74,14 +89,19 @@ func (r stepResp) Response() api.ErrorResp {
 			continue
 		}
 
-		apiResp.Errors = append(apiResp.Errors, api.HTTPError{
+		apiErr := api.HTTPError{
 			Code:    int(api.ErrTxnStepFailed),
 			Message: api.ErrorCodeMap[api.ErrTxnStepFailed],
 			Fields: map[string]string{
 				"peer-id": resp.PeerID.String(),
 				"step":    r.Step,
 				"error":   resp.Error.Error()},
-		})
+		}
+		// The other reasons are given from the error by the REST server
+		if grpc.Code(resp.Error) == codes.Unavailable {
+			apiErr.Reason = api.ReasonPeerUnreachable
+		}
+		apiResp.Errors = append(apiResp.Errors, apiErr)
 	}
 
 	return apiResp
@@ -91,13 +111,14 @@ func (r stepResp) Status() int {
 	return http.StatusInternalServerError
 }
 
-func runStepFuncOnNodes(origCtx context.Context, stepName string, ctx TxnCtx, nodes []uuid.UUID) error {
+func runStepFuncOnNodes(origCtx context.Context, stepName string, ctx TxnCtx, s *Step) error {
+	nodes := s.Nodes
 
 	respCh := make(chan stepPeerResp, len(nodes))
 	defer close(respCh)
 
 	for _, node := range nodes {
-		go runStepFuncOnNode(origCtx, stepName, ctx, node, respCh)
+		go runStepFuncOnNode(origCtx, stepName, ctx, node, s, respCh)
 	}
 
 	// Ideally, we have to cancel the pending go-routines on first error
@@ -126,24 +147,56 @@ func runStepFuncOnNodes(origCtx context.Context, stepName string, ctx TxnCtx, no
 		return resp
 	}
 
+	// The results are only added to the context once the step completed
+	// on all the nodes, as the step may be setting keys in it locally
+	for _, peerResp := range resp.Resps {
+		if err := setStepResults(ctx, peerResp.Results); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// setStepResults adds the keys set by a streamed step on a remote node to
+// the context, for them to be read with Get and GetNodeResult
+func setStepResults(ctx TxnCtx, results map[string][]byte) error {
+	if c, ok := ctx.(*Tctx); ok {
+		c.cacheResults(results)
+		return nil
+	}
+
+	for key, value := range results {
+		if err := ctx.Set(key, json.RawMessage(value)); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-func runStepFuncOnNode(origCtx context.Context, stepName string, ctx TxnCtx, node uuid.UUID, respCh chan<- stepPeerResp) {
+func runStepFuncOnNode(origCtx context.Context, stepName string, ctx TxnCtx, node uuid.UUID, s *Step, respCh chan<- stepPeerResp) {
 
 	ctx.Logger().WithFields(log.Fields{
 		"step": stepName, "node": node,
 	}).Debug("Running step on node.")
 
-	var err error
-	if uuid.Equal(node, gdctx.MyUUID) {
+	var (
+		err     error
+		results map[string][]byte
+	)
+	if simulate.Hosts(node) {
+		// The virtual peers hosted by this node are not reached over RPC
+		err = simulate.RunAs(node, func() error {
+			return traceStep(RunStepFuncLocally)(origCtx, stepName, ctx)
+		})
+	} else if uuid.Equal(node, gdctx.MyUUID) {
 		err = traceStep(RunStepFuncLocally)(origCtx, stepName, ctx)
 	} else {
 		// remote node
-		err = runStepOn(origCtx, stepName, node, ctx)
+		results, err = runStepOn(origCtx, stepName, node, ctx, s)
 	}
 
-	respCh <- stepPeerResp{node, err}
+	respCh <- stepPeerResp{PeerID: node, Error: err, Results: results}
 }
 
 type runFunc func(origCtx context.Context, stepName string, ctx TxnCtx) error
@@ -155,6 +208,7 @@ func traceStep(f runFunc) runFunc {
 			reqID := ctx.GetTxnReqID()
 			span.AddAttributes(
 				trace.StringAttribute("reqID", reqID),
+				trace.StringAttribute("node", gdctx.MyUUID.String()),
 			)
 			defer span.End()
 		}
@@ -170,11 +224,19 @@ func RunStepFuncLocally(origCtx context.Context, stepName string, ctx TxnCtx) er
 		return ErrStepFuncNotFound
 	}
 
+	before, after := fault.Step(stepName)
+	if before != nil {
+		return before
+	}
+
 	if err := stepFunc(ctx); err != nil {
 		return err
 	}
 
 	// if step function executes successfully, commit the
 	// results to the store
-	return ctx.Commit()
+	if err := ctx.Commit(); err != nil {
+		return err
+	}
+	return after
 }
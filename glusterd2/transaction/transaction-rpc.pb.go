@@ -108,9 +108,65 @@ func (m *TxnStepResp) GetError() string {
 	return ""
 }
 
+type TxnStepResult struct {
+	Key                  string   `protobuf:"bytes,1,opt,name=Key,proto3" json:"Key,omitempty"`
+	Value                []byte   `protobuf:"bytes,2,opt,name=Value,proto3" json:"Value,omitempty"`
+	Error                string   `protobuf:"bytes,3,opt,name=Error,proto3" json:"Error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TxnStepResult) Reset()         { *m = TxnStepResult{} }
+func (m *TxnStepResult) String() string { return proto.CompactTextString(m) }
+func (*TxnStepResult) ProtoMessage()    {}
+func (*TxnStepResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8c3c13f7a3182c1a, []int{2}
+}
+
+func (m *TxnStepResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TxnStepResult.Unmarshal(m, b)
+}
+func (m *TxnStepResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TxnStepResult.Marshal(b, m, deterministic)
+}
+func (m *TxnStepResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TxnStepResult.Merge(m, src)
+}
+func (m *TxnStepResult) XXX_Size() int {
+	return xxx_messageInfo_TxnStepResult.Size(m)
+}
+func (m *TxnStepResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_TxnStepResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TxnStepResult proto.InternalMessageInfo
+
+func (m *TxnStepResult) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *TxnStepResult) GetValue() []byte {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+func (m *TxnStepResult) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*TxnStepReq)(nil), "transaction.TxnStepReq")
 	proto.RegisterType((*TxnStepResp)(nil), "transaction.TxnStepResp")
+	proto.RegisterType((*TxnStepResult)(nil), "transaction.TxnStepResult")
 }
 
 func init() {
@@ -118,7 +174,7 @@ func init() {
 }
 
 var fileDescriptor_8c3c13f7a3182c1a = []byte{
-	// 174 bytes of a gzipped FileDescriptorProto
+	// 233 bytes of a gzipped FileDescriptorProto
 	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xd2, 0x4e, 0xcf, 0x29, 0x2d,
 	0x2e, 0x49, 0x2d, 0x4a, 0x31, 0xd2, 0x2f, 0x29, 0x4a, 0xcc, 0x2b, 0x4e, 0x4c, 0x2e, 0xc9, 0xcc,
 	0xcf, 0x43, 0x66, 0xeb, 0x16, 0x15, 0x24, 0xeb, 0x15, 0x14, 0xe5, 0x97, 0xe4, 0x0b, 0x71, 0x23,
@@ -126,10 +182,14 @@ var fileDescriptor_8c3c13f7a3182c1a = []byte{
 	0x49, 0x71, 0x71, 0x80, 0x98, 0x6e, 0xa5, 0x79, 0xc9, 0x12, 0x8c, 0x0a, 0x8c, 0x1a, 0x9c, 0x41,
 	0x70, 0xbe, 0x90, 0x04, 0x17, 0xbb, 0x73, 0x7e, 0x5e, 0x49, 0x6a, 0x45, 0x89, 0x04, 0x93, 0x02,
 	0xa3, 0x06, 0x4f, 0x10, 0x8c, 0xab, 0xa4, 0xcc, 0xc5, 0x0d, 0x37, 0xa3, 0xb8, 0x40, 0x48, 0x84,
-	0x8b, 0xd5, 0xb5, 0xa8, 0x28, 0xbf, 0x08, 0x6a, 0x02, 0x84, 0x63, 0xe4, 0xc1, 0xc5, 0x06, 0x52,
-	0x54, 0x96, 0x2c, 0x64, 0xc7, 0xc5, 0x1e, 0x54, 0x0a, 0x56, 0x2e, 0x24, 0xae, 0x87, 0xe4, 0x16,
-	0x3d, 0x84, 0x43, 0xa4, 0x24, 0xb0, 0x4b, 0x14, 0x17, 0x28, 0x31, 0x24, 0xb1, 0x81, 0xbd, 0x61,
-	0x0c, 0x08, 0x00, 0x00, 0xff, 0xff, 0x69, 0x42, 0xfa, 0xe6, 0xf5, 0x00, 0x00, 0x00,
+	0x8b, 0xd5, 0xb5, 0xa8, 0x28, 0xbf, 0x08, 0x6a, 0x02, 0x84, 0xa3, 0xe4, 0xcb, 0xc5, 0x8b, 0x50,
+	0x54, 0x9a, 0x53, 0x22, 0x24, 0xc0, 0xc5, 0xec, 0x9d, 0x5a, 0x09, 0x55, 0x04, 0x62, 0x82, 0x34,
+	0x86, 0x25, 0xe6, 0x94, 0xa6, 0x42, 0xcd, 0x87, 0x70, 0x10, 0xc6, 0x31, 0x23, 0x19, 0x67, 0x34,
+	0x89, 0x91, 0x8b, 0x0d, 0x64, 0x5e, 0x59, 0xb2, 0x90, 0x1d, 0x17, 0x7b, 0x50, 0x29, 0xd8, 0x64,
+	0x21, 0x71, 0x3d, 0x24, 0xbf, 0xe9, 0x21, 0x3c, 0x26, 0x25, 0x81, 0x5d, 0xa2, 0xb8, 0x40, 0x89,
+	0x41, 0xc8, 0x83, 0x8b, 0x17, 0xaa, 0x3f, 0xb8, 0xa4, 0x28, 0x35, 0x31, 0x17, 0xb7, 0x29, 0x52,
+	0x38, 0x4c, 0x29, 0xcd, 0x29, 0x51, 0x62, 0x30, 0x60, 0x4c, 0x62, 0x03, 0x07, 0xb0, 0x31, 0x60,
+	0x00, 0x67, 0xb0, 0xc6, 0x9b, 0x8f, 0x01, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -145,6 +205,7 @@ const _ = grpc.SupportPackageIsVersion4
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
 type TxnSvcClient interface {
 	RunStep(ctx context.Context, in *TxnStepReq, opts ...grpc.CallOption) (*TxnStepResp, error)
+	RunStepStream(ctx context.Context, in *TxnStepReq, opts ...grpc.CallOption) (TxnSvc_RunStepStreamClient, error)
 }
 
 type txnSvcClient struct {
@@ -164,9 +225,42 @@ func (c *txnSvcClient) RunStep(ctx context.Context, in *TxnStepReq, opts ...grpc
 	return out, nil
 }
 
+func (c *txnSvcClient) RunStepStream(ctx context.Context, in *TxnStepReq, opts ...grpc.CallOption) (TxnSvc_RunStepStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_TxnSvc_serviceDesc.Streams[0], "/transaction.TxnSvc/RunStepStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &txnSvcRunStepStreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TxnSvc_RunStepStreamClient interface {
+	Recv() (*TxnStepResult, error)
+	grpc.ClientStream
+}
+
+type txnSvcRunStepStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *txnSvcRunStepStreamClient) Recv() (*TxnStepResult, error) {
+	m := new(TxnStepResult)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // TxnSvcServer is the server API for TxnSvc service.
 type TxnSvcServer interface {
 	RunStep(context.Context, *TxnStepReq) (*TxnStepResp, error)
+	RunStepStream(*TxnStepReq, TxnSvc_RunStepStreamServer) error
 }
 
 func RegisterTxnSvcServer(s *grpc.Server, srv TxnSvcServer) {
@@ -191,6 +285,27 @@ func _TxnSvc_RunStep_Handler(srv interface{}, ctx context.Context, dec func(inte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TxnSvc_RunStepStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(TxnStepReq)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TxnSvcServer).RunStepStream(m, &txnSvcRunStepStreamServer{stream})
+}
+
+type TxnSvc_RunStepStreamServer interface {
+	Send(*TxnStepResult) error
+	grpc.ServerStream
+}
+
+type txnSvcRunStepStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *txnSvcRunStepStreamServer) Send(m *TxnStepResult) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _TxnSvc_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "transaction.TxnSvc",
 	HandlerType: (*TxnSvcServer)(nil),
@@ -200,6 +315,12 @@ var _TxnSvc_serviceDesc = grpc.ServiceDesc{
 			Handler:    _TxnSvc_RunStep_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "RunStepStream",
+			Handler:       _TxnSvc_RunStepStream_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "glusterd2/transaction/transaction-rpc.proto",
 }
@@ -56,7 +56,15 @@ func NewTxn(ctx context.Context) *Txn {
 		},
 		StorePrefix: t.storePrefix,
 	}
-	t.Ctx = newCtx(config)
+	// The trace ID is logged by all the peers running the steps
+	if span := trace.FromContext(ctx); span != nil && span.SpanContext().IsSampled() {
+		config.LogFields["traceid"] = span.SpanContext().TraceID.String()
+	}
+	// The store calls of the context are traced as part of the request,
+	// and are not interrupted if the client goes away
+	c := newCtx(config)
+	c.ctx = gdctx.WithoutCancel(ctx)
+	t.Ctx = c
 
 	t.OrigCtx = ctx
 	t.Ctx.Logger().Debug("new transaction created")
@@ -71,7 +79,7 @@ func NewTxnWithLocks(ctx context.Context, lockIDs ...string) (*Txn, error) {
 		logger := t.Ctx.Logger().WithField("lockID", id)
 		logger.Debug("attempting to obtain lock")
 
-		if err := t.locks.Lock(id); err != nil {
+		if err := t.locks.lock(ctx, id, &lockOwner{TxnID: t.id, ReqID: t.reqID}); err != nil {
 			logger.WithError(err).Error("failed to obtain lock")
 			t.Done()
 			return nil, err
@@ -80,6 +88,14 @@ func NewTxnWithLocks(ctx context.Context, lockIDs ...string) (*Txn, error) {
 		logger.Debug("lock obtained")
 	}
 
+	// The resource changed by the request is checked against the
+	// revision expected by the client once no other change can be made
+	// to it
+	if err := store.CheckPrecondition(ctx); err != nil {
+		t.Done()
+		return nil, err
+	}
+
 	return t, nil
 }
 
@@ -87,9 +103,7 @@ func NewTxnWithLocks(ctx context.Context, lockIDs ...string) (*Txn, error) {
 // Done must be called after a transaction ends
 func (t *Txn) Done() {
 	// Release obtained locks
-	for _, locker := range t.locks {
-		locker.Unlock(context.Background())
-	}
+	t.locks.UnLock(context.Background())
 
 	// Wipe txn namespace
 	if _, err := store.Delete(context.TODO(), t.storePrefix, clientv3.WithPrefix()); err != nil {
@@ -121,6 +135,12 @@ func (t *Txn) checkAlive() error {
 
 // Do runs the transaction on the cluster
 func (t *Txn) Do() error {
+	end, err := Begin()
+	if err != nil {
+		return err
+	}
+	defer end()
+
 	if !t.DontCheckAlive {
 		if err := t.checkAlive(); err != nil {
 			return err
@@ -140,6 +160,18 @@ func (t *Txn) Do() error {
 			continue
 		}
 
+		// A step sent to the peers is never interrupted, but no step is
+		// started once the request is cancelled or timed out, and the
+		// steps done are undone
+		if err := t.canceled(); err != nil {
+			expTxn.Add("initiated_txn_failure", 1)
+			t.Ctx.Logger().WithError(err).Error("Request cancelled, transaction not completed")
+			if !t.DisableRollback && i > 0 {
+				t.undo(i - 1)
+			}
+			return err
+		}
+
 		if err := s.do(t.OrigCtx, t.Ctx); err != nil {
 			if t.DontCheckAlive && isNodeUnreachable(err) {
 				continue
@@ -157,6 +189,19 @@ func (t *Txn) Do() error {
 	return nil
 }
 
+// canceled returns the error of the context of the request the transaction
+// runs for, if it is done, or ErrShuttingDown if the transaction is failed
+// by the shutdown of glusterd2
+func (t *Txn) canceled() error {
+	if err := aborted(); err != nil {
+		return err
+	}
+	if t.OrigCtx == nil {
+		return nil
+	}
+	return t.OrigCtx.Err()
+}
+
 func isNodeUnreachable(err error) bool {
 	unreachable := true
 	if s, ok := err.(*stepResp); ok {
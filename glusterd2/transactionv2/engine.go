@@ -5,14 +5,20 @@ import (
 	"sync"
 
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/simulate"
 	"github.com/gluster/glusterd2/glusterd2/store"
 
 	"github.com/pborman/uuid"
 	log "github.com/sirupsen/logrus"
 )
 
-// transactionEngine is responsible for executing newly added txn
-var transactionEngine *Engine
+var (
+	// transactionEngine is responsible for executing newly added txn
+	transactionEngine *Engine
+	// hostedEngines execute the txns on the virtual peers hosted by this
+	// node
+	hostedEngines []*Engine
+)
 
 // Engine executes the given transaction across the cluster.
 // It makes use of etcd as the means of communication between nodes.
@@ -27,14 +33,20 @@ type Engine struct {
 
 // NewEngine creates a TxnEngine
 func NewEngine() *Engine {
+	return newEngine(gdctx.MyUUID)
+}
+
+// newEngine creates a TxnEngine executing the txns as the node of the given
+// ID
+func newEngine(selfNodeID uuid.UUID) *Engine {
 	engine := &Engine{
 		stop:        make(chan struct{}),
-		selfNodeID:  gdctx.MyUUID,
-		stepManager: newTracingManager(newStepManager()),
+		selfNodeID:  selfNodeID,
+		stepManager: newTracingManager(newStepManager(selfNodeID)),
 		txnManager:  NewTxnManager(store.Store.Watcher),
 	}
 
-	executor := NewExecutor()
+	executor := newExecutor(selfNodeID)
 	executor = newtracingExecutor(executor)
 	engine.executor = executor
 
@@ -157,9 +169,22 @@ func StartTxnEngine() {
 	go transactionEngine.Run()
 }
 
+// StartHostedTxnEngines starts a Txn Engine for each of the virtual peers
+// hosted by this node
+func StartHostedTxnEngines() {
+	for _, id := range simulate.Peers() {
+		engine := newEngine(id)
+		hostedEngines = append(hostedEngines, engine)
+		go engine.Run()
+	}
+}
+
 // StopTxnEngine stops the Txn Engine
 func StopTxnEngine() {
 	if transactionEngine != nil {
 		transactionEngine.Stop()
 	}
+	for _, engine := range hostedEngines {
+		engine.Stop()
+	}
 }
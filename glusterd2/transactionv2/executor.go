@@ -19,12 +19,18 @@ type Executor interface {
 
 // NewExecutor returns an Executor instance
 func NewExecutor() Executor {
+	return newExecutor(gdctx.MyUUID)
+}
+
+// newExecutor returns an Executor running the txns as the node of the given
+// ID
+func newExecutor(selfNodeID uuid.UUID) Executor {
 	e := &executorImpl{
 		txnManager: NewTxnManager(store.Store.Watcher),
-		selfNodeID: gdctx.MyUUID,
+		selfNodeID: selfNodeID,
 	}
 
-	stepManager := newStepManager()
+	stepManager := newStepManager(selfNodeID)
 	stepManager = newTracingManager(stepManager)
 	e.stepManager = stepManager
 	return e
@@ -4,7 +4,7 @@ import (
 	"context"
 	"time"
 
-	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/simulate"
 	"github.com/gluster/glusterd2/glusterd2/store"
 	"github.com/gluster/glusterd2/glusterd2/transaction"
 
@@ -24,9 +24,9 @@ type stepManager struct {
 	selfNodeID uuid.UUID
 }
 
-func newStepManager() StepManager {
+func newStepManager(selfNodeID uuid.UUID) StepManager {
 	return &stepManager{
-		selfNodeID: gdctx.MyUUID,
+		selfNodeID: selfNodeID,
 	}
 }
 
@@ -48,6 +48,11 @@ func (sm *stepManager) shouldRunStep(step *transaction.Step) bool {
 // before running the step function on node
 func (sm *stepManager) runStep(ctx context.Context, stepName string, txnCtx transaction.TxnCtx) error {
 	txnCtx.SyncCache()
+	if simulate.Hosts(sm.selfNodeID) {
+		return simulate.RunAs(sm.selfNodeID, func() error {
+			return transaction.RunStepFuncLocally(ctx, stepName, txnCtx)
+		})
+	}
 	return transaction.RunStepFuncLocally(ctx, stepName, txnCtx)
 }
 
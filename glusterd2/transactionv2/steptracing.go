@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
 	"github.com/gluster/glusterd2/glusterd2/transaction"
 
 	"go.opencensus.io/trace"
@@ -26,6 +27,7 @@ func (t *tracingManager) RunStep(ctx context.Context, step *transaction.Step, tx
 	defer func() {
 		attrs := []trace.Attribute{
 			trace.StringAttribute("reqID", txnCtx.GetTxnReqID()),
+			trace.StringAttribute("node", gdctx.MyUUID.String()),
 		}
 		if err != nil {
 			span.SetStatus(trace.Status{Code: trace.StatusCodeUnknown, Message: err.Error()})
@@ -45,6 +47,7 @@ func (t *tracingManager) RollBackStep(ctx context.Context, step *transaction.Ste
 	defer func() {
 		attrs := []trace.Attribute{
 			trace.StringAttribute("reqID", txnCtx.GetTxnReqID()),
+			trace.StringAttribute("node", gdctx.MyUUID.String()),
 		}
 		if err != nil {
 			span.SetStatus(trace.Status{Code: trace.StatusCodeUnknown, Message: err.Error()})
@@ -64,6 +67,7 @@ func (t *tracingManager) SyncStep(ctx context.Context, stepIndex int, txn *Txn)
 	defer func() {
 		attrs := []trace.Attribute{
 			trace.StringAttribute("reqID", txn.Ctx.GetTxnReqID()),
+			trace.StringAttribute("node", gdctx.MyUUID.String()),
 		}
 		if err != nil {
 			span.SetStatus(trace.Status{Code: trace.StatusCodeUnknown, Message: err.Error()})
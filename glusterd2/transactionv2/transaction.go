@@ -11,6 +11,7 @@ import (
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
 	"github.com/gluster/glusterd2/glusterd2/store"
 	"github.com/gluster/glusterd2/glusterd2/transaction"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
 
 	"github.com/coreos/etcd/clientv3"
 	"github.com/pborman/uuid"
@@ -72,8 +73,15 @@ func NewTxn(ctx context.Context) *Txn {
 func NewTxnWithLocks(ctx context.Context, lockIDs ...string) (*Txn, error) {
 	t := NewTxn(ctx)
 	t.locks = transaction.Locks{}
-	err := t.acquireClusterLocks(lockIDs...)
-	return t, err
+	if err := t.acquireClusterLocks(lockIDs...); err != nil {
+		return t, err
+	}
+	// Checked under the locks, as for the transactions of version 1
+	if err := store.CheckPrecondition(ctx); err != nil {
+		t.releaseLocks()
+		return t, err
+	}
+	return t, nil
 }
 
 func (t *Txn) acquireClusterLocks(lockIDs ...string) error {
@@ -132,6 +140,12 @@ func (t *Txn) checkAlive() error {
 
 // Do runs the transaction on the cluster
 func (t *Txn) Do() error {
+	end, err := transaction.Begin()
+	if err != nil {
+		return err
+	}
+	defer end()
+
 	var (
 		timer = time.NewTimer(txnTimeOut)
 	)
@@ -163,6 +177,9 @@ func (t *Txn) Do() error {
 	case <-timer.C:
 		t.onFailure(errTxnTimeout)
 		return errTxnTimeout
+	case <-transaction.Aborted():
+		t.onFailure(gderrors.ErrShuttingDown)
+		return gderrors.ErrShuttingDown
 	}
 
 	return nil
@@ -426,11 +426,14 @@ func (tm *txnManager) watch(stopCh <-chan struct{}, key string, respHandler func
 		ctx, cancel := context.WithCancel(context.Background())
 		defer cancel()
 		watchRespChan := tm.storeWatcher.Watch(ctx, key, opts...)
+		w := store.TrackWatch("transactions", key)
+		defer w.Stop()
 		for {
 			select {
 			case <-stopCh:
 				return
 			case watchResp := <-watchRespChan:
+				w.Observe(watchResp)
 				if watchResp.Err() != nil || watchResp.Canceled {
 					return
 				}
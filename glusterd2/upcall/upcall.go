@@ -0,0 +1,146 @@
+// Package upcall relays the cache invalidations sent by the bricks to the
+// clients of their volume connected to glusterd2 on every peer, so that the
+// caches of the clients, NFS-Ganesha and Samba stay coherent with the
+// changes made through the other protocols.
+package upcall
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/events"
+	"github.com/gluster/glusterd2/glusterd2/options"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// CacheInvalidationKey is the option of the upcall xlator making the
+	// bricks send the cache invalidations
+	CacheInvalidationKey = "features/upcall.cache-invalidation"
+
+	// EventCacheInvalidation is broadcast across the cluster when a brick
+	// sends a cache invalidation, for every peer to relay it to its
+	// clients
+	EventCacheInvalidation = "upcall.cache_invalidation"
+)
+
+// Keys of the dict sent by the bricks along with a cache invalidation
+const (
+	keyVolfileID = "volfile-id"
+	keyGfid      = "gfid"
+	keyFlags     = "flags"
+)
+
+// Keys of the data of EventCacheInvalidation
+const (
+	dataVolume = "volume.name"
+	dataGfid   = "gfid"
+	dataFlags  = "flags"
+)
+
+// Invalidation is the invalidation of the cached attributes of an inode
+type Invalidation struct {
+	Volume string
+	Gfid   string
+	// Flags are the upcall flags of the attributes to invalidate
+	Flags uint32
+}
+
+// Enabled tells if the bricks of the volume send cache invalidations
+func Enabled(v *volume.Volinfo) bool {
+	opts, err := options.EffectiveVolumeOptions(v.Options)
+	if err != nil {
+		opts = v.Options
+	}
+	for _, k := range []string{"brick." + CacheInvalidationKey, CacheInvalidationKey} {
+		if value, ok := opts[k]; ok {
+			on, _ := options.StringToBoolean(value)
+			return on
+		}
+	}
+	return false
+}
+
+// findLocalBrick returns the brick of this peer with the given volfile ID
+func findLocalBrick(volfileID string) (*brick.Brickinfo, error) {
+	volumes, err := volume.GetVolumes(context.TODO())
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range volumes {
+		for _, b := range v.GetLocalBricks() {
+			if b.VolfileID == volfileID {
+				return &b, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("no local brick with volfile ID %s", volfileID)
+}
+
+// parse returns the invalidation of the volume from the GFID and flags
+func parse(volname, gfid, flags string) (*Invalidation, error) {
+	if uuid.Parse(gfid) == nil {
+		return nil, fmt.Errorf("invalid GFID %q", gfid)
+	}
+	f, err := strconv.ParseUint(flags, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid flags %q", flags)
+	}
+	return &Invalidation{Volume: volname, Gfid: gfid, Flags: uint32(f)}, nil
+}
+
+// HandleEventNotify broadcasts across the cluster the cache invalidation
+// sent by a brick of this peer. Invalidations of the volumes with cache
+// invalidation disabled are refused.
+func HandleEventNotify(reqDict map[string]string) error {
+	volfileID := reqDict[keyVolfileID]
+	if volfileID == "" {
+		return fmt.Errorf("%s not set", keyVolfileID)
+	}
+
+	b, err := findLocalBrick(volfileID)
+	if err != nil {
+		return err
+	}
+	inv, err := parse(b.VolumeName, reqDict[keyGfid], reqDict[keyFlags])
+	if err != nil {
+		return err
+	}
+
+	v, err := volume.GetVolume(b.VolumeName)
+	if err != nil {
+		return err
+	}
+	if !Enabled(v) {
+		return fmt.Errorf("cache invalidation is not enabled on volume %s", v.Name)
+	}
+
+	log.WithFields(log.Fields{
+		"volume": inv.Volume,
+		"brick":  b.String(),
+		"gfid":   inv.Gfid,
+	}).Debug("relaying cache invalidation")
+	events.Broadcast(events.New(EventCacheInvalidation, map[string]string{
+		dataVolume: inv.Volume,
+		dataGfid:   inv.Gfid,
+		dataFlags:  strconv.FormatUint(uint64(inv.Flags), 10),
+	}, true))
+	return nil
+}
+
+// ParseEvent returns the cache invalidation broadcast by the event
+func ParseEvent(e *api.Event) (*Invalidation, error) {
+	if e.Name != EventCacheInvalidation {
+		return nil, fmt.Errorf("unexpected event %s", e.Name)
+	}
+	if e.Data[dataVolume] == "" {
+		return nil, fmt.Errorf("%s not set", dataVolume)
+	}
+	return parse(e.Data[dataVolume], e.Data[dataGfid], e.Data[dataFlags])
+}
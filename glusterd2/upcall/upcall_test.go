@@ -0,0 +1,48 @@
+package upcall
+
+import (
+	"testing"
+
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	inv, err := parse("testvol", "8c1d4a2e-7b11-4c4b-9a21-3f0d5e6c7b88", "1048")
+	if assert.Nil(t, err) {
+		assert.Equal(t, "testvol", inv.Volume)
+		assert.Equal(t, "8c1d4a2e-7b11-4c4b-9a21-3f0d5e6c7b88", inv.Gfid)
+		assert.Equal(t, uint32(1048), inv.Flags)
+	}
+
+	_, err = parse("testvol", "not-a-gfid", "1")
+	assert.NotNil(t, err)
+	_, err = parse("testvol", "8c1d4a2e-7b11-4c4b-9a21-3f0d5e6c7b88", "-1")
+	assert.NotNil(t, err)
+}
+
+func TestParseEvent(t *testing.T) {
+	e := &api.Event{
+		Name: EventCacheInvalidation,
+		Data: map[string]string{
+			dataVolume: "testvol",
+			dataGfid:   "00000000-0000-0000-0000-000000000001",
+			dataFlags:  "8",
+		},
+	}
+	inv, err := ParseEvent(e)
+	if assert.Nil(t, err) {
+		assert.Equal(t, "testvol", inv.Volume)
+		assert.Equal(t, uint32(8), inv.Flags)
+	}
+
+	e.Name = "volume.started"
+	_, err = ParseEvent(e)
+	assert.NotNil(t, err)
+
+	e.Name = EventCacheInvalidation
+	delete(e.Data, dataVolume)
+	_, err = ParseEvent(e)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,85 @@
+package upgrade
+
+import (
+	"fmt"
+
+	"github.com/gluster/glusterd2/glusterd2/volume"
+
+	"github.com/pborman/uuid"
+)
+
+// tolerance returns how many bricks of the subvolume can be down without the
+// subvolume losing quorum
+func tolerance(sv *volume.Subvol) int {
+	switch sv.Type {
+	case volume.SubvolReplicate:
+		// Client quorum needs more than half of the bricks up
+		return (len(sv.Bricks) - 1) / 2
+	case volume.SubvolDisperse:
+		return sv.RedundancyCount
+	default:
+		return 0
+	}
+}
+
+// checkPlacement returns the subvolumes of the started volumes which lose
+// quorum when any single peer is down, because the peer hosts more of their
+// bricks than they can afford to lose
+func checkPlacement(volumes []*volume.Volinfo) []string {
+	var problems []string
+	for _, v := range volumes {
+		if v.State != volume.VolStarted {
+			continue
+		}
+		for _, sv := range v.Subvols {
+			perPeer := make(map[string]int)
+			for _, b := range sv.Bricks {
+				perPeer[b.PeerID.String()]++
+			}
+			for peerID, count := range perPeer {
+				if count > tolerance(&sv) {
+					problems = append(problems, fmt.Sprintf("subvolume %s of volume %s loses quorum while peer %s is down",
+						sv.Name, v.Name, peerID))
+				}
+			}
+		}
+	}
+	return problems
+}
+
+// affectedVolumes returns the names of the started replicate and disperse
+// volumes having bricks on the peer, whose bricks are healed after the peer
+// is upgraded
+func affectedVolumes(volumes []*volume.Volinfo, peerID uuid.UUID) ([]string, []uuid.UUID) {
+	var names []string
+	nodes := make(map[string]uuid.UUID)
+	for _, v := range volumes {
+		if v.State != volume.VolStarted {
+			continue
+		}
+		switch v.Type {
+		case volume.Replicate, volume.Disperse, volume.DistReplicate, volume.DistDisperse:
+		default:
+			continue
+		}
+		hasBrick := false
+		for _, node := range v.Nodes() {
+			if uuid.Equal(node, peerID) {
+				hasBrick = true
+			}
+		}
+		if !hasBrick {
+			continue
+		}
+		names = append(names, v.Name)
+		for _, node := range v.Nodes() {
+			nodes[node.String()] = node
+		}
+	}
+
+	nodeList := make([]uuid.UUID, 0, len(nodes))
+	for _, node := range nodes {
+		nodeList = append(nodeList, node)
+	}
+	return names, nodeList
+}
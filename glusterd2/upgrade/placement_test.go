@@ -0,0 +1,66 @@
+package upgrade
+
+import (
+	"testing"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func newVolume(name string, svType volume.SubvolType, peers ...uuid.UUID) *volume.Volinfo {
+	sv := volume.Subvol{Name: name + "-0", Type: svType}
+	for _, p := range peers {
+		sv.Bricks = append(sv.Bricks, brick.Brickinfo{PeerID: p, VolumeName: name})
+	}
+	if svType == volume.SubvolDisperse {
+		sv.RedundancyCount = 1
+	}
+	return &volume.Volinfo{
+		Name:    name,
+		Type:    volume.Replicate,
+		State:   volume.VolStarted,
+		Subvols: []volume.Subvol{sv},
+	}
+}
+
+func TestCheckPlacement(t *testing.T) {
+	p1, p2, p3 := uuid.NewRandom(), uuid.NewRandom(), uuid.NewRandom()
+
+	assert.Empty(t, checkPlacement([]*volume.Volinfo{
+		newVolume("rep3", volume.SubvolReplicate, p1, p2, p3),
+		newVolume("disp", volume.SubvolDisperse, p1, p2, p3),
+	}))
+
+	// Two bricks of a replica 3 subvolume on the same peer
+	assert.Len(t, checkPlacement([]*volume.Volinfo{
+		newVolume("rep3", volume.SubvolReplicate, p1, p1, p2),
+	}), 1)
+
+	// Replica 2 loses quorum with either brick down
+	assert.Len(t, checkPlacement([]*volume.Volinfo{
+		newVolume("rep2", volume.SubvolReplicate, p1, p2),
+	}), 2)
+
+	stopped := newVolume("rep2", volume.SubvolReplicate, p1, p2)
+	stopped.State = volume.VolStopped
+	assert.Empty(t, checkPlacement([]*volume.Volinfo{stopped}))
+}
+
+func TestAffectedVolumes(t *testing.T) {
+	p1, p2, p3, p4 := uuid.NewRandom(), uuid.NewRandom(), uuid.NewRandom(), uuid.NewRandom()
+	volumes := []*volume.Volinfo{
+		newVolume("vol1", volume.SubvolReplicate, p1, p2, p3),
+		newVolume("vol2", volume.SubvolReplicate, p2, p3, p4),
+	}
+
+	names, nodes := affectedVolumes(volumes, p1)
+	assert.Equal(t, []string{"vol1"}, names)
+	assert.Len(t, nodes, 3)
+
+	names, nodes = affectedVolumes(volumes, p2)
+	assert.Equal(t, []string{"vol1", "vol2"}, names)
+	assert.Len(t, nodes, 4)
+}
@@ -0,0 +1,38 @@
+package upgrade
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/errors"
+)
+
+const upgradeStatusKey = "upgrade/status"
+
+// GetStatus returns the progress of the last rolling upgrade
+func GetStatus() (*api.UpgradeStatus, error) {
+	resp, err := store.Get(context.TODO(), upgradeStatusKey)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Count != 1 {
+		return nil, errors.ErrUpgradeNotFound
+	}
+
+	var st api.UpgradeStatus
+	if err := json.Unmarshal(resp.Kvs[0].Value, &st); err != nil {
+		return nil, err
+	}
+	return &st, nil
+}
+
+func putStatus(st *api.UpgradeStatus) error {
+	data, err := json.Marshal(st)
+	if err != nil {
+		return err
+	}
+	_, err = store.Put(context.TODO(), upgradeStatusKey, string(data))
+	return err
+}
@@ -0,0 +1,76 @@
+package upgrade
+
+import (
+	"context"
+	"syscall"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/daemon"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const pendingHealsTxnKey = "pending-heals"
+
+// txnStopBricks stops the local bricks of the started volumes. They are kept
+// in the store of daemons, so that glusterd2 restarts them when it is
+// restarted after the upgrade.
+func txnStopBricks(c transaction.TxnCtx) error {
+	volumes, err := volume.GetVolumes(context.TODO())
+	if err != nil {
+		return err
+	}
+
+	for _, v := range volumes {
+		if v.State != volume.VolStarted {
+			continue
+		}
+		for _, b := range v.GetLocalBricks() {
+			d, err := brick.NewGlusterfsd(b)
+			if err != nil {
+				return err
+			}
+			logger := c.Logger().WithFields(log.Fields{"volume": v.Name, "brick": b.String()})
+			// the brick is restarted by the upgrade, not by the supervisor
+			daemon.Hold(d)
+			if err := daemon.Signal(d, syscall.SIGTERM, logger); err != nil {
+				logger.WithError(err).Warn("failed to stop brick")
+			}
+		}
+	}
+	return nil
+}
+
+// txnCountPendingHeals counts the entries pending heal on the local bricks
+// of the given volumes
+func txnCountPendingHeals(c transaction.TxnCtx) error {
+	var volnames []string
+	if err := c.Get("volumes", &volnames); err != nil {
+		return err
+	}
+
+	count := 0
+	for _, name := range volnames {
+		v, err := volume.GetVolume(name)
+		if err != nil {
+			return err
+		}
+		for _, b := range v.GetLocalBricks() {
+			n, err := brick.CountPendingHeals(b.Path)
+			if err != nil {
+				return err
+			}
+			count += n
+		}
+	}
+	return c.SetNodeResult(gdctx.MyUUID, pendingHealsTxnKey, count)
+}
+
+// RegisterStepFuncs registers the step functions of the rolling upgrade
+func RegisterStepFuncs() {
+	transaction.RegisterStepFunc(txnStopBricks, "upgrade.StopBricks")
+	transaction.RegisterStepFunc(txnCountPendingHeals, "upgrade.CountPendingHeals")
+}
@@ -0,0 +1,344 @@
+// Package upgrade implements the rolling upgrade of the peers of the
+// cluster.
+//
+// The peers are upgraded one by one. The bricks of a peer are stopped, then
+// glusterd2 and glusterfs are upgraded and glusterd2 is restarted on the
+// peer by the administrator or by configuration management, which restarts
+// the bricks. The next peer is taken down only once the entries pending heal
+// on the volumes having bricks on the upgraded peer are healed, so that no
+// volume loses quorum.
+//
+// The progress is saved in the store. It is driven by the peer elected as
+// the upgrade leader, and another peer takes over when the leader itself is
+// restarted to be upgraded.
+package upgrade
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/leader"
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// LeaderService is the singleton service of the upgrade leader
+	LeaderService = "upgrade"
+	pollInterval  = 10 * time.Second
+
+	// LockKey is the cluster lock held while changing the progress of the
+	// upgrade
+	LockKey = "upgrade"
+)
+
+var (
+	stopCh   = make(chan struct{})
+	stopOnce sync.Once
+)
+
+func init() {
+	leader.Register(LeaderService)
+}
+
+// Start starts driving the rolling upgrades while this peer is the upgrade
+// leader
+func Start() {
+	go run()
+}
+
+// Stop stops driving the rolling upgrades
+func Stop() {
+	stopOnce.Do(func() {
+		close(stopCh)
+	})
+}
+
+func run() {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+			if !leader.IsLeader(LeaderService) {
+				continue
+			}
+			if err := advance(); err != nil {
+				log.WithError(err).Warn("failed to advance the rolling upgrade")
+			}
+		}
+	}
+}
+
+func setPeerState(p *api.PeerUpgradeStatus, state string, message string) {
+	p.State = state
+	p.Message = message
+	p.UpdatedAt = time.Now()
+}
+
+// Begin starts a rolling upgrade of the peers. It must be called with
+// LockKey locked.
+func Begin(req *api.UpgradeReq) (*api.UpgradeStatus, error) {
+	st, err := GetStatus()
+	if err != nil && err != errors.ErrUpgradeNotFound {
+		return nil, err
+	}
+	if st != nil && st.State == api.UpgradeRunning {
+		return nil, errors.ErrUpgradeInProgress
+	}
+
+	volumes, err := volume.GetVolumes(context.TODO())
+	if err != nil {
+		return nil, err
+	}
+	if problems := checkPlacement(volumes); len(problems) > 0 && !req.Force {
+		return nil, fmt.Errorf("%s: %s", errors.ErrUpgradeLosesQuorum, strings.Join(problems, "; "))
+	}
+
+	peers, err := peer.GetPeers()
+	if err != nil {
+		return nil, err
+	}
+
+	// Upgrade the peers without bricks first, which does not need waiting
+	// for heals
+	bricks := make(map[string]int)
+	for _, v := range volumes {
+		for _, node := range v.Nodes() {
+			bricks[node.String()]++
+		}
+	}
+	sort.Slice(peers, func(i, j int) bool {
+		bi, bj := bricks[peers[i].ID.String()] > 0, bricks[peers[j].ID.String()] > 0
+		if bi != bj {
+			return !bi
+		}
+		return peers[i].Name < peers[j].Name
+	})
+
+	now := time.Now()
+	st = &api.UpgradeStatus{
+		State:         api.UpgradeRunning,
+		TargetVersion: req.TargetVersion,
+		StartedAt:     now,
+		UpdatedAt:     now,
+		Peers:         make([]api.PeerUpgradeStatus, 0, len(peers)),
+	}
+	for _, p := range peers {
+		st.Peers = append(st.Peers, api.PeerUpgradeStatus{
+			ID:          p.ID,
+			Name:        p.Name,
+			State:       api.PeerUpgradePending,
+			FromVersion: p.Version,
+			UpdatedAt:   now,
+		})
+	}
+
+	if err := putStatus(st); err != nil {
+		return nil, err
+	}
+	return st, nil
+}
+
+// Abort stops the rolling upgrade in progress. Peers in maintenance remain
+// so until glusterd2 is restarted on them. It must be called with LockKey
+// locked.
+func Abort() (*api.UpgradeStatus, error) {
+	st, err := GetStatus()
+	if err != nil {
+		return nil, err
+	}
+	if st.State != api.UpgradeRunning {
+		return nil, errors.ErrUpgradeNotFound
+	}
+
+	st.State = api.UpgradeAborted
+	st.UpdatedAt = time.Now()
+	for i := range st.Peers {
+		if st.Peers[i].State == api.PeerUpgradeMaintenance {
+			st.Peers[i].Message = "restart glusterd2 on the peer to restart its bricks"
+		}
+	}
+
+	if err := putStatus(st); err != nil {
+		return nil, err
+	}
+	return st, nil
+}
+
+// advance moves the rolling upgrade in progress forward, if it can
+func advance() error {
+	ctx := gdctx.WithReqLogger(context.Background(), log.StandardLogger())
+	txn, err := transaction.NewTxnWithLocks(ctx, LockKey)
+	if err != nil {
+		return err
+	}
+	defer txn.Done()
+
+	st, err := GetStatus()
+	if err != nil {
+		if err == errors.ErrUpgradeNotFound {
+			return nil
+		}
+		return err
+	}
+	if st.State != api.UpgradeRunning {
+		return nil
+	}
+
+	completed := true
+	for i := range st.Peers {
+		p := &st.Peers[i]
+		if p.State == api.PeerUpgradeDone || p.State == api.PeerUpgradeSkipped {
+			continue
+		}
+		completed = false
+		if err := advancePeer(ctx, st, p); err != nil {
+			return err
+		}
+		break
+	}
+	if completed {
+		st.State = api.UpgradeCompleted
+		log.Info("rolling upgrade completed")
+	}
+
+	st.UpdatedAt = time.Now()
+	return putStatus(st)
+}
+
+// advancePeer moves the upgrade of the peer forward, if it can
+func advancePeer(ctx context.Context, st *api.UpgradeStatus, p *api.PeerUpgradeStatus) error {
+	info, err := peer.GetPeer(p.ID.String())
+	if err != nil {
+		if err == errors.ErrPeerNotFound {
+			setPeerState(p, api.PeerUpgradeSkipped, "peer was removed from the cluster")
+			return nil
+		}
+		return err
+	}
+	pid, alive := store.Store.IsNodeAlive(p.ID)
+	logger := log.WithField("peer", p.Name)
+
+	switch p.State {
+	case api.PeerUpgradePending:
+		if st.TargetVersion != "" && info.Version == st.TargetVersion {
+			setPeerState(p, api.PeerUpgradeSkipped, "peer already runs the target version")
+			return nil
+		}
+		if !alive {
+			p.Message = "waiting for the peer to be online"
+			return nil
+		}
+		// Bricks of the volumes of this peer may have been down too
+		pending, err := pendingHeals(ctx, p.ID)
+		if err != nil {
+			p.Message = fmt.Sprintf("failed to count the entries pending heal: %s", err)
+			return nil
+		}
+		if pending > 0 {
+			p.Message = fmt.Sprintf("waiting for %d entries pending heal to be healed", pending)
+			return nil
+		}
+
+		txn := transaction.NewTxn(ctx)
+		defer txn.Done()
+		txn.Steps = []*transaction.Step{
+			{
+				DoFunc: "upgrade.StopBricks",
+				Nodes:  []uuid.UUID{p.ID},
+			},
+		}
+		if err := txn.Do(); err != nil {
+			logger.WithError(err).Error("failed to stop the bricks of the peer")
+			st.State = api.UpgradeFailed
+			st.Message = fmt.Sprintf("failed to stop the bricks of peer %s: %s", p.Name, err)
+			p.Message = st.Message
+			return nil
+		}
+
+		p.FromVersion = info.Version
+		p.PID = pid
+		setPeerState(p, api.PeerUpgradeMaintenance, "upgrade glusterd2 and glusterfs on the peer and restart glusterd2")
+		logger.Info("peer is in maintenance, waiting for it to be upgraded")
+
+	case api.PeerUpgradeMaintenance:
+		if !alive || pid == p.PID {
+			return nil
+		}
+		if st.TargetVersion != "" && info.Version != st.TargetVersion {
+			p.Message = fmt.Sprintf("glusterd2 was restarted but runs version %s instead of %s", info.Version, st.TargetVersion)
+			return nil
+		}
+		p.Version = info.Version
+		setPeerState(p, api.PeerUpgradeHealing, "")
+		logger.WithField("version", info.Version).Info("peer was upgraded, waiting for heals")
+
+	case api.PeerUpgradeHealing:
+		pending, err := pendingHeals(ctx, p.ID)
+		if err != nil {
+			p.Message = fmt.Sprintf("failed to count the entries pending heal: %s", err)
+			return nil
+		}
+		if pending > 0 {
+			p.Message = fmt.Sprintf("waiting for %d entries pending heal to be healed", pending)
+			return nil
+		}
+		setPeerState(p, api.PeerUpgradeDone, "")
+		logger.Info("peer upgrade done")
+	}
+	return nil
+}
+
+// pendingHeals returns the number of entries pending heal on the volumes
+// having bricks on the peer
+func pendingHeals(ctx context.Context, peerID uuid.UUID) (int, error) {
+	volumes, err := volume.GetVolumes(context.TODO())
+	if err != nil {
+		return 0, err
+	}
+	names, nodes := affectedVolumes(volumes, peerID)
+	if len(names) == 0 {
+		return 0, nil
+	}
+
+	txn := transaction.NewTxn(ctx)
+	defer txn.Done()
+	txn.DisableRollback = true
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc: "upgrade.CountPendingHeals",
+			Nodes:  nodes,
+		},
+	}
+	if err := txn.Ctx.Set("volumes", names); err != nil {
+		return 0, err
+	}
+	if err := txn.Do(); err != nil {
+		return 0, err
+	}
+
+	total := 0
+	for _, node := range nodes {
+		var count int
+		if err := txn.Ctx.GetNodeResult(node, pendingHealsTxnKey, &count); err != nil {
+			return 0, err
+		}
+		total += count
+	}
+	return total, nil
+}
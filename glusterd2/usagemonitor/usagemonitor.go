@@ -0,0 +1,184 @@
+// Package usagemonitor periodically collects the space and inode utilization
+// of the local bricks and aggregates it per volume.
+package usagemonitor
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/events"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/glusterd2/transactionv2"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+	flag "github.com/spf13/pflag"
+	config "github.com/spf13/viper"
+)
+
+const (
+	intervalOpt          = "usage-interval"
+	warningThresholdOpt  = "usage-warning-threshold"
+	criticalThresholdOpt = "usage-critical-threshold"
+)
+
+// level is the utilization level of a volume with respect to the configured
+// thresholds
+type level int
+
+const (
+	levelNormal level = iota
+	levelWarning
+	levelCritical
+)
+
+var (
+	stopChan chan struct{}
+	stopOnce sync.Once
+)
+
+// InitFlags intializes the command line options for the usage monitor
+func InitFlags() {
+	flag.Duration(intervalOpt, time.Minute, "Interval at which brick utilization is collected. Set to 0 to disable.")
+	flag.Float64(warningThresholdOpt, 80, "Volume space or inode utilization percentage above which a warning event is raised.")
+	flag.Float64(criticalThresholdOpt, 90, "Volume space or inode utilization percentage above which a critical event is raised.")
+}
+
+// Start starts collecting the utilization of local bricks periodically
+func Start() {
+	interval := config.GetDuration(intervalOpt)
+	if interval <= 0 {
+		log.Info("volume usage monitor disabled")
+		return
+	}
+
+	stopChan = make(chan struct{})
+	go transaction.UntilStop(collect, interval, stopChan)
+}
+
+// Stop stops the usage monitor
+func Stop() {
+	if stopChan == nil {
+		return
+	}
+	stopOnce.Do(func() {
+		close(stopChan)
+	})
+}
+
+func collect() {
+	volumes, err := volume.GetVolumes(context.TODO())
+	if err != nil {
+		log.WithError(err).Error("usagemonitor: failed to get volumes")
+		return
+	}
+
+	for _, v := range volumes {
+		if v.State != volume.VolStarted {
+			continue
+		}
+		for _, b := range v.GetLocalBricks() {
+			u, err := volume.BrickUsageInfo(&b)
+			if err != nil {
+				log.WithError(err).WithField("brick", b.String()).Debug("usagemonitor: failed to statfs brick")
+				continue
+			}
+			if err := volume.AddOrUpdateBrickUsage(&b, u); err != nil {
+				log.WithError(err).WithField("brick", b.String()).Error("usagemonitor: failed to store brick usage")
+			}
+		}
+
+		if isAggregator(v) {
+			aggregate(v)
+		}
+	}
+}
+
+// isAggregator returns true if this node is the first online node among the
+// nodes hosting the bricks of the volume, so that only one node aggregates
+// and raises events for a volume
+func isAggregator(v *volume.Volinfo) bool {
+	for _, node := range v.Nodes() {
+		if uuid.Equal(node, gdctx.MyUUID) {
+			return true
+		}
+		if _, alive := store.Store.IsNodeAlive(node); alive {
+			return false
+		}
+	}
+	return false
+}
+
+func aggregate(v *volume.Volinfo) {
+	bricks, err := volume.GetBrickUsages(v.Name)
+	if err != nil {
+		log.WithError(err).WithField("volume", v.Name).Error("usagemonitor: failed to get brick usages")
+		return
+	}
+
+	u := volume.AggregateUsage(v, bricks)
+	if u == nil {
+		return
+	}
+
+	prev, err := volume.GetUsage(v.Name)
+	if err != nil {
+		log.WithError(err).WithField("volume", v.Name).Error("usagemonitor: failed to get volume usage")
+	}
+
+	if err := volume.AddOrUpdateUsage(v.Name, u); err != nil {
+		log.WithError(err).WithField("volume", v.Name).Error("usagemonitor: failed to store volume usage")
+		return
+	}
+
+	prevLevel := levelNormal
+	if prev != nil {
+		prevLevel = levelOf(prev)
+	}
+	if curLevel := levelOf(u); curLevel != prevLevel {
+		events.Broadcast(newUsageEvent(v, u, curLevel))
+	}
+}
+
+func levelOf(u *volume.Usage) level {
+	util := u.Utilization()
+	if iutil := u.InodeUtilization(); iutil > util {
+		util = iutil
+	}
+
+	switch {
+	case util >= config.GetFloat64(criticalThresholdOpt):
+		return levelCritical
+	case util >= config.GetFloat64(warningThresholdOpt):
+		return levelWarning
+	}
+	return levelNormal
+}
+
+func newUsageEvent(v *volume.Volinfo, u *volume.Usage, l level) *api.Event {
+	var name volume.Event
+	switch l {
+	case levelCritical:
+		name = volume.EventVolumeUsageCritical
+	case levelWarning:
+		name = volume.EventVolumeUsageWarning
+	default:
+		name = volume.EventVolumeUsageNormal
+	}
+
+	data := map[string]string{
+		"volume.name":              v.Name,
+		"volume.id":                v.ID.String(),
+		"volume.capacity":          fmt.Sprint(u.Capacity),
+		"volume.used":              fmt.Sprint(u.Used),
+		"volume.utilization":       fmt.Sprintf("%.2f", u.Utilization()),
+		"volume.inode_utilization": fmt.Sprintf("%.2f", u.InodeUtilization()),
+	}
+
+	return events.New(string(name), data, true)
+}
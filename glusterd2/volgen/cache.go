@@ -0,0 +1,96 @@
+package volgen
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"expvar"
+	"sync"
+)
+
+// maxCachedVolfiles bounds the number of volfiles cached, past which a
+// volfile is dropped from the cache for each volfile added
+const maxCachedVolfiles = 4096
+
+var (
+	// metrics
+	cacheHits   = expvar.NewInt("volgen_cache_hits")
+	cacheMisses = expvar.NewInt("volgen_cache_misses")
+)
+
+// cachedVolfile is a volfile generated, with the hash of the inputs it was
+// generated from
+type cachedVolfile struct {
+	inputs  string
+	content string
+}
+
+// volfileCache caches the volfiles generated, by the volfile they are. A
+// volfile is generated again only if its inputs, the template, the volume
+// with the options set at the cluster level and the brick, changed since it
+// was cached.
+type volfileCache struct {
+	sync.Mutex
+	entries map[string]cachedVolfile
+}
+
+var cache = &volfileCache{entries: make(map[string]cachedVolfile)}
+
+// inputsHash returns the hash of the inputs a volfile is generated from
+func inputsHash(inputs ...interface{}) (string, error) {
+	h := sha256.New()
+	enc := json.NewEncoder(h)
+	for _, in := range inputs {
+		if err := enc.Encode(in); err != nil {
+			return "", err
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// get returns the volfile cached, if it was generated from the inputs
+func (c *volfileCache) get(id, inputs string) (string, bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	e, ok := c.entries[id]
+	if !ok || e.inputs != inputs {
+		cacheMisses.Add(1)
+		return "", false
+	}
+	cacheHits.Add(1)
+	return e.content, true
+}
+
+func (c *volfileCache) put(id, inputs, content string) {
+	c.Lock()
+	defer c.Unlock()
+
+	if _, ok := c.entries[id]; !ok && len(c.entries) >= maxCachedVolfiles {
+		for k := range c.entries {
+			delete(c.entries, k)
+			break
+		}
+	}
+	c.entries[id] = cachedVolfile{inputs: inputs, content: content}
+}
+
+// generate returns the volfile with the given ID generated from the inputs,
+// from the cache or else generated with gen
+func (c *volfileCache) generate(id string, gen func() (string, error), inputs ...interface{}) (string, error) {
+	hash, err := inputsHash(inputs...)
+	if err != nil {
+		// not cacheable
+		return gen()
+	}
+	if content, ok := c.get(id, hash); ok {
+		return content, nil
+	}
+
+	content, err := gen()
+	if err != nil {
+		return "", err
+	}
+	c.put(id, hash, content)
+	return content, nil
+}
@@ -0,0 +1,56 @@
+package volgen
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVolfileCache(t *testing.T) {
+	c := &volfileCache{entries: make(map[string]cachedVolfile)}
+
+	generated := 0
+	gen := func(content string) func() (string, error) {
+		return func() (string, error) {
+			generated++
+			return content, nil
+		}
+	}
+
+	inputs := map[string]string{"performance.readdir-ahead": "on"}
+	out, err := c.generate("volume/client/testvol", gen("v1"), inputs, "client")
+	require.NoError(t, err)
+	assert.Equal(t, "v1", out)
+
+	// Same inputs, the volfile cached is returned
+	out, err = c.generate("volume/client/testvol", gen("v2"), inputs, "client")
+	require.NoError(t, err)
+	assert.Equal(t, "v1", out)
+	assert.Equal(t, 1, generated)
+
+	// Changed inputs, the volfile is generated again
+	inputs["performance.readdir-ahead"] = "off"
+	out, err = c.generate("volume/client/testvol", gen("v2"), inputs, "client")
+	require.NoError(t, err)
+	assert.Equal(t, "v2", out)
+	assert.Equal(t, 2, generated)
+	assert.Len(t, c.entries, 1)
+
+	// Failures are not cached
+	_, err = c.generate("volume/client/othervol", func() (string, error) {
+		return "", errors.New("failed")
+	}, inputs)
+	assert.Error(t, err)
+	assert.Len(t, c.entries, 1)
+}
+
+func TestVolfileCacheBound(t *testing.T) {
+	c := &volfileCache{entries: make(map[string]cachedVolfile)}
+	for i := 0; i < maxCachedVolfiles+10; i++ {
+		c.put(strconv.Itoa(i), "inputs", "content")
+	}
+	assert.Len(t, c.entries, maxCachedVolfiles)
+}
@@ -0,0 +1,40 @@
+package volgen
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+)
+
+// Checksum returns the checksum of the content of a volfile. The options of
+// the translators are generated in no particular order, so the checksum
+// does not depend on the order of the options within a translator, nor on
+// blank lines and indentation.
+func Checksum(volfile string) string {
+	var (
+		lines   []string
+		options []string
+	)
+	flush := func() {
+		sort.Strings(options)
+		lines = append(lines, options...)
+		options = options[:0]
+	}
+
+	for _, line := range strings.Split(volfile, "\n") {
+		line = strings.Join(strings.Fields(line), " ")
+		switch {
+		case line == "":
+		case strings.HasPrefix(line, "option "):
+			options = append(options, line)
+		default:
+			flush()
+			lines = append(lines, line)
+		}
+	}
+	flush()
+
+	sum := sha256.Sum256([]byte(strings.Join(lines, "\n")))
+	return hex.EncodeToString(sum[:8])
+}
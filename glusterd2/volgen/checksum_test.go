@@ -0,0 +1,51 @@
+package volgen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChecksum(t *testing.T) {
+	volfile := `volume testvol-posix
+    type storage/posix
+    option directory /bricks/b1
+    option volume-id 5a4b3c
+end-volume
+
+volume testvol-server
+    type protocol/server
+    option transport-type tcp
+    subvolumes testvol-posix
+end-volume
+`
+	// options are generated in any order
+	reordered := `volume testvol-posix
+  type storage/posix
+  option volume-id 5a4b3c
+  option directory /bricks/b1
+end-volume
+volume testvol-server
+  type protocol/server
+  option transport-type tcp
+  subvolumes testvol-posix
+end-volume`
+	assert.Equal(t, Checksum(volfile), Checksum(reordered))
+	assert.Len(t, Checksum(volfile), 16)
+
+	// an option moved to another translator changes the volfile
+	moved := `volume testvol-posix
+    type storage/posix
+    option directory /bricks/b1
+end-volume
+
+volume testvol-server
+    type protocol/server
+    option transport-type tcp
+    option volume-id 5a4b3c
+    subvolumes testvol-posix
+end-volume
+`
+	assert.NotEqual(t, Checksum(volfile), Checksum(moved))
+	assert.NotEqual(t, Checksum(volfile), Checksum(""))
+}
@@ -141,18 +141,28 @@ func VolumeVolfileToFile(volinfo *volume.Volinfo, volfileID string, tmplName str
 
 // BrickVolfileToFile generates Volume level volfile for the given template name
 func BrickVolfileToFile(volinfo *volume.Volinfo, volfileID string, tmplName string, peerid string, brickPath string) error {
-	tmpl, err := GetTemplateFromVolinfo(volinfo, tmplName)
+	volfile, err := BrickVolfile(volinfo, tmplName, peerid, brickPath)
 	if err != nil {
 		return err
 	}
 
-	volfile, err := BrickLevelVolfile(tmpl, volinfo, peerid, brickPath)
+	return SaveToFile(VolfilePath(volfileID), volfile)
+}
+
+// BrickVolfile generates the brick level volfile for the given template name
+func BrickVolfile(volinfo *volume.Volinfo, tmplName string, peerid string, brickPath string) (string, error) {
+	tmpl, err := GetTemplateFromVolinfo(volinfo, tmplName)
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	filename := path.Join(config.GetString("localstatedir"), "volfiles", volfileID+".vol")
-	return SaveToFile(filename, volfile)
+	return BrickLevelVolfile(tmpl, volinfo, peerid, brickPath)
+}
+
+// VolfilePath returns the path of the file the volfile with the given ID is
+// saved to
+func VolfilePath(volfileID string) string {
+	return path.Join(config.GetString("localstatedir"), "volfiles", volfileID+".vol")
 }
 
 type stringMapBrick struct {
@@ -236,12 +246,48 @@ func DeleteBricksVolfiles(brickinfos []brick.Brickinfo) error {
 // GenerateBricksVolfiles generates the volfiles of
 // all local bricks
 func GenerateBricksVolfiles(volinfo *volume.Volinfo, brickinfos []brick.Brickinfo) error {
+	_, err := GenerateChangedBricksVolfiles(volinfo, brickinfos)
+	return err
+}
+
+// GenerateChangedBricksVolfiles generates the volfiles of the local bricks,
+// and saves only the volfiles which differ from the ones saved already. It
+// returns the checksums of the volfiles, by volfile ID.
+func GenerateChangedBricksVolfiles(volinfo *volume.Volinfo, brickinfos []brick.Brickinfo) (map[string]string, error) {
+	checksums := make(map[string]string, len(brickinfos))
 	for _, b := range brickinfos {
 		volfileID := brick.GetVolfileID(b.VolumeName, b.Path)
-		err := BrickVolfileToFile(volinfo, volfileID, "brick", b.PeerID.String(), b.Path)
+		volfile, err := BrickVolfile(volinfo, "brick", b.PeerID.String(), b.Path)
 		if err != nil {
-			return err
+			return nil, err
+		}
+		checksum := Checksum(volfile)
+		checksums[volfileID] = checksum
+
+		filename := VolfilePath(volfileID)
+		if saved, err := ioutil.ReadFile(filename); err == nil && Checksum(string(saved)) == checksum {
+			continue
+		}
+		done := recordWriteBrickVolfile(b, volfileID)
+		err = SaveToFile(filename, volfile)
+		done()
+		if err != nil {
+			return nil, err
 		}
 	}
-	return nil
+	return checksums, nil
+}
+
+// ClientVolfileChecksum returns the checksum of the client volfile of the
+// volume, as served to its clients
+func ClientVolfileChecksum(volinfo *volume.Volinfo) (string, error) {
+	tmpl, err := GetTemplateFromVolinfo(volinfo, "client")
+	if err != nil {
+		return "", err
+	}
+	volfile, err := VolumeLevelVolfile(tmpl, volinfo)
+	if err != nil {
+		return "", err
+	}
+	return Checksum(volfile), nil
 }
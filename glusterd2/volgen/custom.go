@@ -0,0 +1,506 @@
+package volgen
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gluster/glusterd2/glusterd2/options"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/glusterd2/xlator"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+	"github.com/gluster/glusterd2/pkg/utils"
+
+	"github.com/coreos/etcd/clientv3"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	customXlatorsPrefix = "custom-xlators/"
+)
+
+// CustomXlator is an xlator which is not shipped with glusterfs, registered
+// by a plugin or a user. It is inserted into the graphs of the templates
+// listed in Templates, and is enabled for a volume by setting the option
+// <xlator>.<xlator> on it, for example `myxl.myxl on`.
+type CustomXlator struct {
+	// Type of the xlator, for example "features/myxl"
+	Type string `json:"type"`
+	// Templates are the names of the templates into whose graphs the
+	// xlator is inserted. Only brick and volume level templates are
+	// supported.
+	Templates []string `json:"templates"`
+	// After are the types of the xlators below which the xlator is
+	// placed in the graphs
+	After []string `json:"after"`
+	// Before are the types of the xlators above which the xlator is
+	// placed in the graphs
+	Before []string `json:"before"`
+	// Requires are the types of the xlators which must be enabled in the
+	// graphs along with the xlator
+	Requires []string `json:"requires"`
+	// Options are the options supported by the xlator
+	Options []CustomXlatorOption `json:"options"`
+	// Plugin is the name of the plugin which registered the xlator, empty
+	// for the xlators registered by users
+	Plugin string `json:"-"`
+}
+
+// CustomXlatorOption is an option of a custom xlator
+type CustomXlatorOption struct {
+	Key          string   `json:"key"`
+	Type         string   `json:"type"`
+	DefaultValue string   `json:"default-value"`
+	ValidValues  []string `json:"valid-values"`
+	Description  string   `json:"description"`
+}
+
+// optionTypes maps the names of the types of the options of custom xlators
+// to the option types
+var optionTypes = map[string]options.OptionType{
+	"any":     options.OptionTypeAny,
+	"str":     options.OptionTypeStr,
+	"int":     options.OptionTypeInt,
+	"size":    options.OptionTypeSizet,
+	"percent": options.OptionTypePercent,
+	"bool":    options.OptionTypeBool,
+	"path":    options.OptionTypePath,
+	"time":    options.OptionTypeTime,
+	"double":  options.OptionTypeDouble,
+}
+
+var (
+	customMu sync.RWMutex
+	// pluginXlators are the custom xlators registered by plugins,
+	// indexed by xlator ID
+	pluginXlators = make(map[string]CustomXlator)
+	// storeXlators are the custom xlators registered by users and saved
+	// in the store, indexed by xlator ID
+	storeXlators = make(map[string]CustomXlator)
+)
+
+// ID returns the ID of the xlator, which is the last part of its type
+func (cx *CustomXlator) ID() string {
+	return path.Base(cx.Type)
+}
+
+// related returns the types of the xlators the custom xlator is placed
+// relative to or requires
+func (cx *CustomXlator) related() []string {
+	var types []string
+	types = append(types, cx.After...)
+	types = append(types, cx.Before...)
+	return append(types, cx.Requires...)
+}
+
+// toXlator returns the xlator with the options of the custom xlator, along
+// with the option enabling it
+func (cx *CustomXlator) toXlator() (*xlator.Xlator, error) {
+	id := cx.ID()
+	flags := options.OptionFlagSettable
+	for _, name := range cx.Templates {
+		if tmpl, err := GetTemplate(DefaultTemplateNamespace, name); err == nil && tmpl.Level == VolfileLevelVolume {
+			flags |= options.OptionFlagClientOpt
+		}
+	}
+
+	xl := &xlator.Xlator{
+		ID:       id,
+		Category: path.Dir(cx.Type),
+		Options: []*options.Option{{
+			Key:          []string{id},
+			Type:         options.OptionTypeBool,
+			DefaultValue: "off",
+			Description:  fmt.Sprintf("Enable the %s xlator", cx.Type),
+			Flags:        flags,
+			Level:        options.OptionStatusBasic,
+		}},
+	}
+	for _, o := range cx.Options {
+		typ, ok := optionTypes[o.Type]
+		if !ok {
+			return nil, fmt.Errorf("invalid type %q of option %s", o.Type, o.Key)
+		}
+		xl.Options = append(xl.Options, &options.Option{
+			Key:          []string{o.Key},
+			Type:         typ,
+			Value:        o.ValidValues,
+			DefaultValue: o.DefaultValue,
+			Description:  o.Description,
+			Flags:        flags,
+			Level:        options.OptionStatusBasic,
+		})
+	}
+	return xl, nil
+}
+
+// CustomXlators returns the custom xlators registered by plugins and users,
+// sorted by type
+func CustomXlators() []CustomXlator {
+	customMu.RLock()
+	defer customMu.RUnlock()
+
+	cxs := make([]CustomXlator, 0, len(pluginXlators)+len(storeXlators))
+	for _, cx := range storeXlators {
+		if _, ok := pluginXlators[cx.ID()]; !ok {
+			cxs = append(cxs, cx)
+		}
+	}
+	for _, cx := range pluginXlators {
+		cxs = append(cxs, cx)
+	}
+	sort.Slice(cxs, func(i, j int) bool {
+		return cxs[i].Type < cxs[j].Type
+	})
+	return cxs
+}
+
+// GetCustomXlator returns the custom xlator with the given ID
+func GetCustomXlator(id string) (*CustomXlator, error) {
+	for _, cx := range CustomXlators() {
+		if cx.ID() == id {
+			return &cx, nil
+		}
+	}
+	return nil, gderrors.ErrCustomXlatorNotFound
+}
+
+// applyCustomXlators makes the options of the custom xlators known to the
+// xlator package, so that they can be set on volumes
+func applyCustomXlators() {
+	var xls []*xlator.Xlator
+	for _, cx := range CustomXlators() {
+		xl, err := cx.toXlator()
+		if err != nil {
+			log.WithError(err).WithField("xlator", cx.Type).Error("ignoring invalid custom xlator")
+			continue
+		}
+		xls = append(xls, xl)
+	}
+	xlator.SetCustomXlators(xls)
+}
+
+// RegisterCustomXlator registers a custom xlator of a plugin. It should be
+// called from the init function of the plugin.
+func RegisterCustomXlator(plugin string, cx CustomXlator) {
+	cx.Plugin = plugin
+	customMu.Lock()
+	pluginXlators[cx.ID()] = cx
+	customMu.Unlock()
+	applyCustomXlators()
+}
+
+// LoadCustomXlators loads the custom xlators registered by users from the
+// store
+func LoadCustomXlators() error {
+	resp, err := store.Get(context.TODO(), customXlatorsPrefix, clientv3.WithPrefix())
+	if err != nil {
+		return err
+	}
+
+	cxs := make(map[string]CustomXlator, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var cx CustomXlator
+		if err := json.Unmarshal(kv.Value, &cx); err != nil {
+			log.WithError(err).WithField("key", string(kv.Key)).Error("failed to unmarshal custom xlator")
+			continue
+		}
+		cxs[cx.ID()] = cx
+	}
+
+	customMu.Lock()
+	storeXlators = cxs
+	customMu.Unlock()
+	applyCustomXlators()
+	return nil
+}
+
+// WatchCustomXlators reloads the custom xlators registered by users whenever
+// they change in the store, until the store is closed
+func WatchCustomXlators() {
+	wch := store.Store.Watch(store.Store.Ctx(), customXlatorsPrefix, clientv3.WithPrefix())
+	w := store.TrackWatch("custom-xlators", customXlatorsPrefix)
+	defer w.Stop()
+	for resp := range wch {
+		w.Observe(resp)
+		if resp.Canceled {
+			return
+		}
+		if err := LoadCustomXlators(); err != nil {
+			log.WithError(err).Error("failed to reload custom xlators")
+		}
+	}
+}
+
+// SaveCustomXlator saves the custom xlator registered by a user in the store
+func SaveCustomXlator(cx *CustomXlator) error {
+	data, err := json.Marshal(cx)
+	if err != nil {
+		return err
+	}
+	_, err = store.Put(context.TODO(), customXlatorsPrefix+cx.ID(), string(data))
+	return err
+}
+
+// DeleteCustomXlator deletes the custom xlator registered by a user from the
+// store
+func DeleteCustomXlator(id string) error {
+	_, err := store.Delete(context.TODO(), customXlatorsPrefix+id)
+	return err
+}
+
+// customXlatorsFor returns the custom xlators to be inserted into the graph
+// of the template, which is not already in it
+func customXlatorsFor(tmpl *Template) []CustomXlator {
+	var cxs []CustomXlator
+	for _, cx := range CustomXlators() {
+		if utils.StringInSlice(tmpl.Name, cx.Templates) && xlatorIndex(tmpl.Xlators, cx.Type) < 0 {
+			cxs = append(cxs, cx)
+		}
+	}
+	return cxs
+}
+
+func xlatorIndex(xls []Xlator, xltype string) int {
+	for i, xl := range xls {
+		if xl.Type == xltype {
+			return i
+		}
+	}
+	return -1
+}
+
+// customXlatorPosition returns the index in xls at which the custom xlator
+// is to be inserted. The first and the last xlators of a graph, for example
+// protocol/server and storage/posix, are kept in their place. Without
+// constraints, the xlator is placed just above the last xlator.
+func customXlatorPosition(xls []Xlator, cx *CustomXlator) (int, error) {
+	lo, hi := 1, len(xls)-1
+	for _, t := range cx.After {
+		if i := xlatorIndex(xls, t); i >= 0 && i+1 > lo {
+			lo = i + 1
+		}
+	}
+	for _, t := range cx.Before {
+		if i := xlatorIndex(xls, t); i >= 0 && i < hi {
+			hi = i
+		}
+	}
+	if lo > hi {
+		return 0, fmt.Errorf("xlator %s cannot be placed below %s and above %s",
+			cx.Type, strings.Join(cx.After, ", "), strings.Join(cx.Before, ", "))
+	}
+	if len(cx.After) > 0 && len(cx.Before) == 0 {
+		return lo, nil
+	}
+	return hi, nil
+}
+
+// insertCustomXlators returns the list of xlators with the custom xlators
+// inserted, disabled unless enabled in the volume. A custom xlator placed
+// relative to another is inserted after it.
+func insertCustomXlators(xls []Xlator, cxs []CustomXlator) ([]Xlator, error) {
+	if len(xls) < 2 {
+		return xls, nil
+	}
+	out := make([]Xlator, len(xls))
+	copy(out, xls)
+
+	pending := cxs
+	for len(pending) > 0 {
+		var deferred []CustomXlator
+		for i := range pending {
+			cx := &pending[i]
+			if dependsOnPending(cx, pending) {
+				deferred = append(deferred, *cx)
+				continue
+			}
+			pos, err := customXlatorPosition(out, cx)
+			if err != nil {
+				return nil, err
+			}
+			xl := Xlator{
+				Type:     cx.Type,
+				Disabled: true,
+				// The option enabling the xlator is not
+				// known to the xlator itself
+				IgnoreOptions: []string{cx.ID()},
+			}
+			out = append(out[:pos], append([]Xlator{xl}, out[pos:]...)...)
+		}
+		if len(deferred) == len(pending) {
+			types := make([]string, 0, len(deferred))
+			for _, cx := range deferred {
+				types = append(types, cx.Type)
+			}
+			return nil, fmt.Errorf("xlators %s are placed relative to each other in a cycle", strings.Join(types, ", "))
+		}
+		pending = deferred
+	}
+	return out, nil
+}
+
+// dependsOnPending returns true if the custom xlator is placed relative to
+// another one yet to be inserted
+func dependsOnPending(cx *CustomXlator, pending []CustomXlator) bool {
+	for _, p := range pending {
+		if p.Type == cx.Type {
+			continue
+		}
+		if utils.StringInSlice(p.Type, cx.After) || utils.StringInSlice(p.Type, cx.Before) {
+			return true
+		}
+	}
+	return false
+}
+
+// withCustomXlators returns the template with the custom xlators inserted
+// into its graph
+func withCustomXlators(tmpl *Template) (*Template, error) {
+	if tmpl.Level == VolfileLevelCluster {
+		return tmpl, nil
+	}
+	cxs := customXlatorsFor(tmpl)
+	if len(cxs) == 0 {
+		return tmpl, nil
+	}
+
+	t := *tmpl
+	xls, err := insertCustomXlators(tmpl.Xlators, cxs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to insert custom xlators into template %s: %s", tmpl.Name, err)
+	}
+	t.Xlators = xls
+	return &t, nil
+}
+
+// checkCustomXlatorRequires checks if the xlators required by the enabled
+// custom xlators are enabled too
+func checkCustomXlatorRequires(enabled []Xlator) error {
+	cxs := CustomXlators()
+	if len(cxs) == 0 {
+		return nil
+	}
+	for _, cx := range cxs {
+		if len(cx.Requires) == 0 || xlatorIndex(enabled, cx.Type) < 0 {
+			continue
+		}
+		for _, req := range cx.Requires {
+			if xlatorIndex(enabled, req) < 0 {
+				return fmt.Errorf("xlator %s requires %s to be enabled", cx.Type, req)
+			}
+		}
+	}
+	return nil
+}
+
+// CheckCustomXlators checks if the graphs of the volume can be generated
+// with the custom xlators enabled in it
+func CheckCustomXlators(volinfo *volume.Volinfo) error {
+	names := make(map[string]bool)
+	for _, cx := range CustomXlators() {
+		for _, name := range cx.Templates {
+			names[name] = true
+		}
+	}
+	for name := range names {
+		tmpl, err := GetTemplateFromVolinfo(volinfo, name)
+		if err != nil {
+			return err
+		}
+		if _, err := tmpl.EnabledXlators(volinfo); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validXlatorType(t string) bool {
+	parts := strings.Split(t, "/")
+	return len(parts) == 2 && parts[0] != "" && parts[1] != ""
+}
+
+// ValidateCustomXlator checks if the custom xlator can be registered along
+// with the other custom xlators
+func ValidateCustomXlator(cx *CustomXlator) error {
+	if !validXlatorType(cx.Type) {
+		return fmt.Errorf("invalid xlator type %q, expected <category>/<name>", cx.Type)
+	}
+	for _, t := range cx.related() {
+		if !validXlatorType(t) {
+			return fmt.Errorf("invalid xlator type %q, expected <category>/<name>", t)
+		}
+	}
+
+	id := cx.ID()
+	seen := map[string]bool{id: true}
+	for _, o := range cx.Options {
+		if o.Key == "" {
+			return fmt.Errorf("option of xlator %s has no key", cx.Type)
+		}
+		if seen[o.Key] {
+			return fmt.Errorf("option %s of xlator %s is reserved or duplicated", o.Key, cx.Type)
+		}
+		seen[o.Key] = true
+	}
+	xl, err := cx.toXlator()
+	if err != nil {
+		return err
+	}
+	for _, o := range xl.Options {
+		if o.DefaultValue == "" {
+			continue
+		}
+		if err := o.Validate(o.DefaultValue); err != nil {
+			return fmt.Errorf("invalid default value %q of option %s: %s", o.DefaultValue, o.Key[0], err)
+		}
+	}
+
+	if len(cx.Templates) == 0 {
+		return fmt.Errorf("xlator %s is not inserted into any template", cx.Type)
+	}
+
+	// The other custom xlators, with this one replacing any registered
+	// with the same ID
+	others := make([]CustomXlator, 0)
+	for _, c := range CustomXlators() {
+		if c.ID() != id {
+			others = append(others, c)
+		}
+	}
+
+	for _, name := range cx.Templates {
+		tmpl, err := GetTemplate(DefaultTemplateNamespace, name)
+		if err != nil {
+			return fmt.Errorf("invalid template %s", name)
+		}
+		if tmpl.Level == VolfileLevelCluster {
+			return fmt.Errorf("xlators cannot be inserted into %s level template %s", tmpl.Level, name)
+		}
+		if xlatorIndex(tmpl.Xlators, cx.Type) >= 0 {
+			return fmt.Errorf("xlator %s is already in template %s", cx.Type, name)
+		}
+
+		cxs := []CustomXlator{*cx}
+		for _, c := range others {
+			if utils.StringInSlice(name, c.Templates) {
+				cxs = append(cxs, c)
+			}
+		}
+		xls, err := insertCustomXlators(tmpl.Xlators, cxs)
+		if err != nil {
+			return fmt.Errorf("template %s: %s", name, err)
+		}
+		for _, t := range cx.related() {
+			if xlatorIndex(xls, t) < 0 {
+				return fmt.Errorf("xlator %s is not in template %s", t, name)
+			}
+		}
+	}
+	return nil
+}
@@ -38,6 +38,13 @@ func LoadDefaultTemplates() error {
 		if err != nil {
 			return err
 		}
+		// Templates added after the file was generated, for example
+		// by an upgrade, are taken from the built-in templates
+		for name, tmpl := range namespaces[DefaultTemplateNamespace] {
+			if _, exists := tmpls[name]; !exists {
+				tmpls[name] = tmpl
+			}
+		}
 		namespaces[DefaultTemplateNamespace] = tmpls
 		return nil
 	}
@@ -58,6 +65,11 @@ func init() {
 				Type:     "debug/io-stats",
 				NameTmpl: "{{ brick.path }}",
 			},
+			{
+				// Limits the IOPS and bandwidth of the brick
+				Type:     "features/throttle",
+				Disabled: true,
+			},
 			{
 				Type: "features/index",
 			},
@@ -70,6 +82,21 @@ func init() {
 			{
 				Type: "features/upcall",
 			},
+			{
+				// Grants the leases of the files to the clients,
+				// once enabled with the leases option
+				Type: "features/leases",
+			},
+			{
+				Type:           "features/read-only",
+				Disabled:       true,
+				EnableByOption: true,
+			},
+			{
+				Type:           "features/worm",
+				Disabled:       true,
+				EnableByOption: true,
+			},
 			{
 				Type: "features/locks",
 			},
@@ -166,6 +193,11 @@ func init() {
 				Type:     "features/shard",
 				Disabled: true,
 			},
+			{
+				// Limits the IOPS and bandwidth of the client
+				Type:     "features/throttle",
+				Disabled: true,
+			},
 			{
 				Type: "cluster/distribute",
 			},
@@ -312,5 +344,136 @@ func init() {
 		},
 	}
 
+	// default quotad template. The quota plugin generates it only with
+	// the volumes having quota enabled, each volume being a child of
+	// quotad.
+	tmpls[utils.QuotadVolfile] = Template{
+		Name:  utils.QuotadVolfile,
+		Level: VolfileLevelCluster,
+		Xlators: []Xlator{
+			{
+				Type:     "features/quotad",
+				NameTmpl: "quotad",
+			},
+		},
+		VolumeGraphXlators: []Xlator{
+			{
+				Type:     "cluster/distribute",
+				NameTmpl: "{{ volume.name }}",
+			},
+		},
+		SubvolGraphXlators: []Xlator{
+			{
+				NameTmpl: "{{ subvol.name }}",
+				TypeTmpl: "cluster/{{ subvol.type }}",
+				Options: map[string]string{
+					"afr-pending-xattr": "{{ subvol.afr-pending-xattr }}",
+				},
+			},
+		},
+		BrickGraphXlators: []Xlator{
+			{
+				Type:     "protocol/client",
+				NameTmpl: "{{ subvol.name }}-client-{{ brick.index }}",
+			},
+		},
+	}
+
+	// default gfproxy daemon template. The gfproxy daemon runs the client
+	// side xlators of the volume and exports them to the thin clients, so
+	// the variables {{ brick.* }} refer to the gfproxy daemon itself.
+	tmpls[utils.GfProxyVolfile] = Template{
+		Name:  utils.GfProxyVolfile,
+		Level: VolfileLevelVolume,
+		Xlators: []Xlator{
+			{
+				Type: "protocol/server",
+			},
+			{
+				Type:     "debug/io-stats",
+				NameTmpl: "{{ brick.path }}",
+			},
+			{
+				Type: "performance/read-ahead",
+			},
+			{
+				Type:     "performance/io-threads",
+				Disabled: true,
+			},
+			{
+				Type: "performance/nl-cache",
+			},
+			{
+				Type: "performance/quick-read",
+			},
+			{
+				Type: "performance/open-behind",
+			},
+			{
+				Type: "performance/io-cache",
+			},
+			{
+				Type: "performance/readdir-ahead",
+			},
+			{
+				Type: "performance/write-behind",
+			},
+			{
+				Type: "performance/md-cache",
+			},
+			{
+				Type:           "features/read-only",
+				Disabled:       true,
+				EnableByOption: true,
+			},
+			{
+				Type: "features/utime",
+			},
+			{
+				Type:     "features/shard",
+				Disabled: true,
+			},
+			{
+				Type: "cluster/distribute",
+			},
+		},
+		SubvolGraphXlators: []Xlator{
+			{
+				NameTmpl: "{{ subvol.name }}",
+				TypeTmpl: "cluster/{{ subvol.type }}",
+				Options: map[string]string{
+					"afr-pending-xattr": "{{ subvol.afr-pending-xattr }}",
+				},
+			},
+		},
+		BrickGraphXlators: []Xlator{
+			{
+				Type:     "protocol/client",
+				NameTmpl: "{{ subvol.name }}-client-{{ brick.index }}",
+			},
+		},
+	}
+
+	// default template of the thin clients, which connect to a gfproxy
+	// daemon instead of the bricks
+	tmpls[utils.GfProxyClientVolfile] = Template{
+		Name:  utils.GfProxyClientVolfile,
+		Level: VolfileLevelVolume,
+		Xlators: []Xlator{
+			{
+				Type:     "debug/io-stats",
+				NameTmpl: "{{ volume.name }}",
+			},
+			{
+				Type:     "protocol/client",
+				NameTmpl: "{{ volume.name }}-gfproxy-client",
+				Options: map[string]string{
+					"remote-host":      "{{ brick.hostname }}",
+					"remote-subvolume": "{{ brick.path }}",
+				},
+			},
+		},
+	}
+
 	namespaces[DefaultTemplateNamespace] = tmpls
 }
@@ -0,0 +1,30 @@
+package volgen
+
+import (
+	"testing"
+
+	"github.com/gluster/glusterd2/pkg/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestThrottleXlator(t *testing.T) {
+	tmpls := namespaces[DefaultTemplateNamespace]
+
+	// The throttle xlator is below io-stats in the brick graph, for the
+	// stats to include the time spent throttled
+	brickTmpl := tmpls[utils.BrickVolfile]
+	i := xlatorIndex(brickTmpl.Xlators, "features/throttle")
+	require.NotEqual(t, -1, i)
+	assert.True(t, brickTmpl.Xlators[i].Disabled)
+	assert.True(t, xlatorIndex(brickTmpl.Xlators, "debug/io-stats") < i)
+
+	// and above distribute in the client graph, throttling the client as
+	// a whole
+	clientTmpl := tmpls[utils.ClientVolfile]
+	i = xlatorIndex(clientTmpl.Xlators, "features/throttle")
+	require.NotEqual(t, -1, i)
+	assert.True(t, clientTmpl.Xlators[i].Disabled)
+	assert.True(t, i < xlatorIndex(clientTmpl.Xlators, "cluster/distribute"))
+}
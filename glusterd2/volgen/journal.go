@@ -0,0 +1,66 @@
+package volgen
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/journal"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/errors"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const journalWriteBrickVolfile = "volgen.write-brick-volfile"
+
+// brickVolfileAction is the write of the volfile of a brick recorded in the
+// journal
+type brickVolfileAction struct {
+	Volume string `json:"volume"`
+	Brick  string `json:"brick"`
+}
+
+// recordWriteBrickVolfile records the write of the volfile of the brick in
+// the journal
+func recordWriteBrickVolfile(b brick.Brickinfo, volfileID string) func() {
+	done, err := journal.Record(journalWriteBrickVolfile, volfileID, brickVolfileAction{Volume: b.VolumeName, Brick: b.Path})
+	if err != nil {
+		log.WithError(err).WithField("volfile", volfileID).Warn("failed to record the write of the volfile in the journal")
+	}
+	return done
+}
+
+// verifyBrickVolfile verifies a write of the volfile of a brick interrupted
+// by a crash, which may have left the volfile half written. The volfile is
+// generated again from the volume in the store, or removed if the brick is
+// not a brick of this peer anymore.
+func verifyBrickVolfile(e journal.Entry) error {
+	var a brickVolfileAction
+	if err := json.Unmarshal(e.Data, &a); err != nil {
+		return err
+	}
+
+	v, err := volume.GetVolume(a.Volume)
+	if err != nil && err != errors.ErrVolNotFound {
+		return err
+	}
+	if v != nil {
+		for _, b := range v.GetLocalBricks() {
+			if b.Path == a.Brick {
+				_, err := GenerateChangedBricksVolfiles(v, []brick.Brickinfo{b})
+				return err
+			}
+		}
+	}
+
+	err = os.Remove(VolfilePath(e.Target))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+func init() {
+	journal.Register(journalWriteBrickVolfile, verifyBrickVolfile)
+}
@@ -0,0 +1,212 @@
+package volgen
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/glusterd2/xlator"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/coreos/etcd/clientv3"
+)
+
+const (
+	templatesPrefix = "templates/"
+)
+
+// StoredTemplate is a version of a default template customized by the user
+// and saved in the store
+type StoredTemplate struct {
+	Version   int       `json:"version"`
+	UpdatedAt time.Time `json:"updated-at"`
+	Template  Template  `json:"template"`
+}
+
+func templateKey(name string, version int) string {
+	return templatesPrefix + name + "/" + strconv.Itoa(version)
+}
+
+// GetStoredTemplates returns the versions of the template saved in the
+// store, oldest first
+func GetStoredTemplates(name string) ([]StoredTemplate, error) {
+	resp, err := store.Get(context.TODO(), templatesPrefix+name+"/", clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	tmpls := make([]StoredTemplate, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var tmpl StoredTemplate
+		if err := json.Unmarshal(kv.Value, &tmpl); err != nil {
+			return nil, err
+		}
+		tmpls = append(tmpls, tmpl)
+	}
+	sort.Slice(tmpls, func(i, j int) bool {
+		return tmpls[i].Version < tmpls[j].Version
+	})
+	return tmpls, nil
+}
+
+// GetStoredTemplate returns the given version of the template saved in the
+// store
+func GetStoredTemplate(name string, version int) (*StoredTemplate, error) {
+	resp, err := store.Get(context.TODO(), templateKey(name, version))
+	if err != nil {
+		return nil, err
+	}
+	if resp.Count != 1 {
+		return nil, gderrors.ErrVolFileTmplVersionNotFound
+	}
+
+	var tmpl StoredTemplate
+	if err := json.Unmarshal(resp.Kvs[0].Value, &tmpl); err != nil {
+		return nil, err
+	}
+	return &tmpl, nil
+}
+
+// GetLatestTemplate returns the latest version of the template, which is the
+// built-in template, version 0, if the template was never customized
+func GetLatestTemplate(name string) (*StoredTemplate, error) {
+	tmpls, err := GetStoredTemplates(name)
+	if err != nil {
+		return nil, err
+	}
+	if len(tmpls) > 0 {
+		return &tmpls[len(tmpls)-1], nil
+	}
+	return getBuiltinTemplate(name)
+}
+
+// GetTemplateVersion returns the given version of the template. Version 0 is
+// the built-in template.
+func GetTemplateVersion(name string, version int) (*StoredTemplate, error) {
+	builtin, err := getBuiltinTemplate(name)
+	if err != nil || version == 0 {
+		return builtin, err
+	}
+	return GetStoredTemplate(name, version)
+}
+
+func getBuiltinTemplate(name string) (*StoredTemplate, error) {
+	tmpl, err := GetTemplate(DefaultTemplateNamespace, name)
+	if err != nil {
+		return nil, err
+	}
+	return &StoredTemplate{Template: *tmpl}, nil
+}
+
+// SaveTemplate saves the template in the store as its next version
+func SaveTemplate(tmpl *Template) (*StoredTemplate, error) {
+	latest, err := GetLatestTemplate(tmpl.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	stored := StoredTemplate{
+		Version:   latest.Version + 1,
+		UpdatedAt: time.Now(),
+		Template:  *tmpl,
+	}
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fail instead of overwriting the version if it was saved meanwhile
+	key := templateKey(tmpl.Name, stored.Version)
+	resp, err := store.Txn(context.TODO()).
+		If(clientv3.Compare(clientv3.CreateRevision(key), "=", 0)).
+		Then(clientv3.OpPut(key, string(data))).
+		Commit()
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Succeeded {
+		return nil, fmt.Errorf("version %d of template %s was saved concurrently", stored.Version, tmpl.Name)
+	}
+	return &stored, nil
+}
+
+// pinnedTemplateVersion returns the version of the template pinned in the
+// volume metadata, if any
+func pinnedTemplateVersion(metadata map[string]string, name string) (int, bool, error) {
+	value, exists := metadata[TemplateVersionMetadataKeyPrefix+name]
+	if !exists {
+		return 0, false, nil
+	}
+	version, err := strconv.Atoi(value)
+	if err != nil || version < 0 {
+		return 0, true, fmt.Errorf("invalid version %q of template %s pinned by the volume", value, name)
+	}
+	return version, true, nil
+}
+
+// ValidateTemplate checks if the template can replace the default template
+// with the same name
+func ValidateTemplate(tmpl *Template) error {
+	builtin, err := GetTemplate(DefaultTemplateNamespace, tmpl.Name)
+	if err != nil {
+		return err
+	}
+	if tmpl.Level != builtin.Level {
+		return fmt.Errorf("level of template %s should be %s", tmpl.Name, builtin.Level)
+	}
+	if len(tmpl.Xlators) == 0 {
+		return fmt.Errorf("template %s has no xlators", tmpl.Name)
+	}
+	if tmpl.Level == VolfileLevelBrick && len(tmpl.SubvolGraphXlators)+len(tmpl.BrickGraphXlators) > 0 {
+		return fmt.Errorf("subvolume and brick graph xlators are not applicable to %s level templates", tmpl.Level)
+	}
+	if tmpl.Level != VolfileLevelCluster && len(tmpl.VolumeGraphXlators) > 0 {
+		return fmt.Errorf("volume graph xlators are not applicable to %s level templates", tmpl.Level)
+	}
+
+	// The type of an xlator can be templated only in the subvolume and
+	// brick graphs
+	for _, xls := range [][]Xlator{tmpl.Xlators, tmpl.VolumeGraphXlators} {
+		for _, xl := range xls {
+			if err := validateXlator(xl, false); err != nil {
+				return err
+			}
+		}
+	}
+	for _, xls := range [][]Xlator{tmpl.SubvolGraphXlators, tmpl.BrickGraphXlators} {
+		for _, xl := range xls {
+			if err := validateXlator(xl, true); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func validateXlator(xl Xlator, typeTmplAllowed bool) error {
+	if xl.TypeTmpl != "" {
+		if !typeTmplAllowed {
+			return fmt.Errorf("type-tmpl %q is applicable only to subvolume and brick graph xlators", xl.TypeTmpl)
+		}
+		if isVarStr(xl.TypeTmpl) {
+			return nil
+		}
+		xl.Type = xl.TypeTmpl
+	}
+	if xl.Type == "" {
+		return fmt.Errorf("xlator %q has no type", xl.NameTmpl)
+	}
+	if strings.Count(xl.Type, "/") != 1 {
+		return fmt.Errorf("invalid xlator type %q, expected <category>/<name>", xl.Type)
+	}
+	if _, err := xlator.Find(path.Base(xl.Type)); err != nil {
+		return err
+	}
+	return nil
+}
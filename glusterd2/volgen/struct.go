@@ -1,5 +1,9 @@
 package volgen
 
+import (
+	"fmt"
+)
+
 // VolfileLevel is the level in which volfile need to be generated
 type VolfileLevel uint16
 
@@ -25,6 +29,16 @@ func (vl VolfileLevel) String() string {
 	}
 }
 
+// ParseVolfileLevel returns the volfile level with the given name
+func ParseVolfileLevel(s string) (VolfileLevel, error) {
+	for _, vl := range []VolfileLevel{VolfileLevelBrick, VolfileLevelVolume, VolfileLevelCluster} {
+		if vl.String() == s {
+			return vl, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid volfile level %q, expected one of brick, volume or cluster", s)
+}
+
 // Template represents Volfile template
 type Template struct {
 	// Name of template, this can be used to identify the template
@@ -92,6 +106,10 @@ const (
 	TemplateMetadataKey = "_template"
 	// DefaultTemplateNamespace represents group of all default volfile templates
 	DefaultTemplateNamespace = "default"
+	// TemplateVersionMetadataKeyPrefix followed by a template name is the
+	// Volinfo Metadata key for pinning the version of the template used by
+	// the volume. Version 0 is the built-in template.
+	TemplateVersionMetadataKeyPrefix = "_template-version."
 )
 
 var namespaces = make(map[string]Templates)
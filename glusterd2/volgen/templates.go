@@ -1,6 +1,8 @@
 package volgen
 
 import (
+	"sort"
+
 	"github.com/gluster/glusterd2/glusterd2/brick"
 	"github.com/gluster/glusterd2/glusterd2/volume"
 	gderrors "github.com/gluster/glusterd2/pkg/errors"
@@ -45,15 +47,50 @@ func GetTemplate(namespace string, name string) (*Template, error) {
 	return &tmpl, nil
 }
 
+// TemplateNames returns the sorted names of the templates in the default
+// namespace
+func TemplateNames() []string {
+	names := make([]string, 0, len(namespaces[DefaultTemplateNamespace]))
+	for name := range namespaces[DefaultTemplateNamespace] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 // GetTemplateFromVolinfo gets template from the namespace set in volinfo
-// If template namespace is not set in volinfo, gets the template
-// from default namespace
+// If template namespace is not set in volinfo, gets the version of the
+// template pinned in volinfo, or else the latest version of the template
+// customized in the store, falling back to the default namespace
 func GetTemplateFromVolinfo(volinfo *volume.Volinfo, name string) (*Template, error) {
-	tmplNamespace, exists := volinfo.Metadata[TemplateMetadataKey]
-	if !exists {
-		tmplNamespace = DefaultTemplateNamespace
+	var metadata map[string]string
+	if volinfo != nil {
+		metadata = volinfo.Metadata
+	}
+
+	tmplNamespace, exists := metadata[TemplateMetadataKey]
+	if exists && tmplNamespace != DefaultTemplateNamespace {
+		tmpl, err := GetTemplate(tmplNamespace, name)
+		if err != nil {
+			return nil, err
+		}
+		return withCustomXlators(tmpl)
+	}
+
+	version, pinned, err := pinnedTemplateVersion(metadata, name)
+	if err != nil {
+		return nil, err
 	}
-	return GetTemplate(tmplNamespace, name)
+	var stored *StoredTemplate
+	if pinned {
+		stored, err = GetTemplateVersion(name, version)
+	} else {
+		stored, err = GetLatestTemplate(name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return withCustomXlators(&stored.Template)
 }
 
 // EnabledXlators returns list of xlators which are enabled in Volinfo or in template itself
@@ -69,6 +106,9 @@ func (tmpl *Template) EnabledXlators(volinfo *volume.Volinfo) ([]Xlator, error)
 			return []Xlator{}, err
 		}
 	}
+	if err = checkCustomXlatorRequires(xlist); err != nil {
+		return []Xlator{}, err
+	}
 	return xlist, nil
 }
 
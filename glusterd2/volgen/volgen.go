@@ -7,6 +7,7 @@ import (
 
 	"github.com/gluster/glusterd2/glusterd2/brick"
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/options"
 	"github.com/gluster/glusterd2/glusterd2/volume"
 	"github.com/gluster/glusterd2/pkg/utils"
 
@@ -179,8 +180,34 @@ func (e *Entry) Generate() (string, error) {
 	return out, nil
 }
 
-// BrickLevelVolfile generates brick level volfile
+// withClusterDefaults returns a copy of the volinfo whose options include
+// the default volume options set at the cluster level
+func withClusterDefaults(volinfo *volume.Volinfo) (*volume.Volinfo, error) {
+	opts, err := options.EffectiveVolumeOptions(volinfo.Options)
+	if err != nil {
+		return nil, err
+	}
+	v := *volinfo
+	v.Options = opts
+	return &v, nil
+}
+
+// BrickLevelVolfile generates brick level volfile. The volfile is generated
+// again only if its inputs changed since it was last generated.
 func BrickLevelVolfile(tmpl *Template, volinfo *volume.Volinfo, peerid string, brickpath string) (string, error) {
+	volinfo, err := withClusterDefaults(volinfo)
+	if err != nil {
+		return "", err
+	}
+	id := "brick/" + tmpl.Name + "/" + volinfo.ID.String() + "/" + peerid + ":" + brickpath
+	return cache.generate(id, func() (string, error) {
+		return brickLevelVolfile(tmpl, volinfo, peerid, brickpath)
+	}, tmpl, volinfo, peerid, brickpath)
+}
+
+// brickLevelVolfile generates the brick level volfile from the volinfo with
+// the cluster defaults
+func brickLevelVolfile(tmpl *Template, volinfo *volume.Volinfo, peerid string, brickpath string) (string, error) {
 	extraStringMaps := getExtraStringMaps(volinfo)
 	varStrData := utils.MergeStringMaps(volinfo.StringMap(), extraStringMaps.StringMap)
 	arbiterBrick := false
@@ -217,6 +244,15 @@ VolinfoLoop:
 		return "", err
 	}
 
+	// Restrict the exported subdirectories to their clients
+	if auth := volinfo.SubdirAuthAllow(); auth != "" {
+		for _, xl := range xlators {
+			if xl.Type == "protocol/server" {
+				xl.Options["auth.addr.{{ brick.path }}.allow"] = auth
+			}
+		}
+	}
+
 	volfile := NewVolfile(tmpl.Name)
 	entry := &volfile.RootEntry
 	for _, xl := range xlators {
@@ -345,13 +381,46 @@ func volumegraph(tmpl *Template, volinfo volume.Volinfo, entry *Entry, varStrDat
 
 // VolumeLevelVolfile generates volume level volfile
 func VolumeLevelVolfile(tmpl *Template, volinfo *volume.Volinfo) (string, error) {
+	return volumeLevelVolfile(tmpl, volinfo, nil)
+}
+
+// GfProxyVolfile generates the volfile of the gfproxy daemon of the volume,
+// or of the thin clients using it, from the given volume level template. The
+// brick variables of the template, like {{ brick.path }}, refer to the gfproxy
+// daemon on the given host, which exports its graph as the given subvolume.
+func GfProxyVolfile(tmpl *Template, volinfo *volume.Volinfo, host string, subvolume string) (string, error) {
+	proxy := brick.Brickinfo{
+		ID:         volinfo.ID,
+		Hostname:   host,
+		PeerID:     gdctx.MyUUID,
+		Path:       subvolume,
+		VolumeName: volinfo.Name,
+		VolumeID:   volinfo.ID,
+	}
+	return volumeLevelVolfile(tmpl, volinfo, proxy.StringMap())
+}
+
+// volumeLevelVolfile generates the volume level volfile. The volfile is
+// generated again only if its inputs changed since it was last generated.
+func volumeLevelVolfile(tmpl *Template, volinfo *volume.Volinfo, extraData map[string]string) (string, error) {
+	volinfo, err := withClusterDefaults(volinfo)
+	if err != nil {
+		return "", err
+	}
+	id := "volume/" + tmpl.Name + "/" + volinfo.ID.String() + "/" + extraData["brick.hostname"] + ":" + extraData["brick.path"]
+	return cache.generate(id, func() (string, error) {
+		return generateVolumeLevelVolfile(tmpl, volinfo, extraData)
+	}, tmpl, volinfo, extraData)
+}
+
+func generateVolumeLevelVolfile(tmpl *Template, volinfo *volume.Volinfo, extraData map[string]string) (string, error) {
 	// Xlators list from template
 	xlators, err := tmpl.EnabledXlators(volinfo)
 	if err != nil {
 		return "", err
 	}
 	extraStringMaps := getExtraStringMaps(volinfo)
-	varStrData := utils.MergeStringMaps(volinfo.StringMap(), extraStringMaps.StringMap)
+	varStrData := utils.MergeStringMaps(volinfo.StringMap(), extraStringMaps.StringMap, extraData)
 
 	volfile := NewVolfile(tmpl.Name)
 	entry := &volfile.RootEntry
@@ -389,6 +458,11 @@ func ClusterLevelVolfile(tmpl *Template, clusterinfo []*volume.Volinfo) (string,
 			continue
 		}
 
+		volinfo, err := withClusterDefaults(volinfo)
+		if err != nil {
+			return "", err
+		}
+
 		extraStringMaps := getExtraStringMaps(volinfo)
 		varStrData := utils.MergeStringMaps(volinfo.StringMap(), extraStringMaps.StringMap)
 		volumeXlators, err := tmpl.EnabledVolumeGraphXlators(volinfo)
@@ -19,6 +19,24 @@ const (
 	EventVolumeStopped = "volume.stopped"
 	// EventVolumeDeleted represents Volume Delete event
 	EventVolumeDeleted = "volume.deleted"
+	// EventVolumeUsageWarning represents volume utilization crossing the warning threshold
+	EventVolumeUsageWarning = "volume.usage.warning"
+	// EventVolumeUsageCritical represents volume utilization crossing the critical threshold
+	EventVolumeUsageCritical = "volume.usage.critical"
+	// EventVolumeUsageNormal represents volume utilization dropping below the warning threshold
+	EventVolumeUsageNormal = "volume.usage.normal"
+	// EventVolumeClientsDisconnected represents the clients of a volume
+	// being disconnected to force stopping it
+	EventVolumeClientsDisconnected = "volume.clients.disconnected"
+	// EventVolumeUndeleted represents a deleted Volume restored from the
+	// trash
+	EventVolumeUndeleted = "volume.undeleted"
+	// EventVolumePurged represents a deleted Volume removed for good at the
+	// end of its grace period
+	EventVolumePurged = "volume.purged"
+	// EventBrickDataCleaned represents the metadata or the data of a brick
+	// of a purged Volume being removed
+	EventBrickDataCleaned = "volume.brick.cleaned"
 )
 
 // NewEvent adds required details to event based on Volume info
@@ -50,8 +50,7 @@ func FilterThinArbiterVolumes(volumes []*Volinfo) []*Volinfo {
 func FilterShardVolumes(volumes []*Volinfo) []*Volinfo {
 	var volInfos []*Volinfo
 	for _, volume := range volumes {
-		_, exists := volume.Options["features/shard"]
-		if exists {
+		if volume.IsSharded() {
 			volInfos = append(volInfos, volume)
 		}
 	}
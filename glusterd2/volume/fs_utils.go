@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/brickcrypt"
 	gderrors "github.com/gluster/glusterd2/pkg/errors"
 	"github.com/gluster/glusterd2/pkg/utils"
 
@@ -238,6 +239,14 @@ func MountBrickDirectory(vol *Volinfo, brickinfo *brick.Brickinfo, mtab []*Mnten
 		return err
 	}
 
+	// Encrypted bricks are unlocked before their file system is mounted
+	if brickinfo.DeviceInfo.Encrypted {
+		if err := brickcrypt.Unlock(brickinfo.VgName, brickinfo.LvName); err != nil {
+			log.WithError(err).WithField("brickPath", brickinfo.String()).Error("Failed to unlock encrypted brick")
+			return err
+		}
+	}
+
 	if err := MountDirectory(mountRoot, brickinfo.MountInfo); err != nil {
 		log.WithError(err).WithFields(log.Fields{"brickPath": brickinfo.String(),
 			"mountRoot": mountRoot}).Error("Failed to mount snapshot directory")
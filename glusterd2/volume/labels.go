@@ -0,0 +1,212 @@
+package volume
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Labels follow the conventions of Kubernetes: a key is a name, optionally
+// prefixed with a DNS subdomain and '/', and a value is a name or empty.
+const (
+	maxLabelNameLength   = 63
+	maxLabelPrefixLength = 253
+)
+
+var (
+	labelNameRe   = regexp.MustCompile(`^([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9]$`)
+	labelPrefixRe = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+	setTermRe     = regexp.MustCompile(`^(\S+)\s+(in|notin)\s*\((.*)\)$`)
+)
+
+// ValidateLabelKey checks the key of a label
+func ValidateLabelKey(key string) error {
+	name := key
+	if i := strings.LastIndex(key, "/"); i >= 0 {
+		prefix := key[:i]
+		name = key[i+1:]
+		if len(prefix) > maxLabelPrefixLength || !labelPrefixRe.MatchString(prefix) {
+			return fmt.Errorf("invalid label key %q: the prefix must be a DNS subdomain", key)
+		}
+	}
+	if len(name) > maxLabelNameLength || !labelNameRe.MatchString(name) {
+		return fmt.Errorf("invalid label key %q: the name must be at most 63 alphanumerics, '-', '_' and '.', starting and ending with an alphanumeric", key)
+	}
+	return nil
+}
+
+// ValidateLabelValue checks the value of a label
+func ValidateLabelValue(value string) error {
+	if value == "" {
+		return nil
+	}
+	if len(value) > maxLabelNameLength || !labelNameRe.MatchString(value) {
+		return fmt.Errorf("invalid label value %q: must be empty or at most 63 alphanumerics, '-', '_' and '.', starting and ending with an alphanumeric", value)
+	}
+	return nil
+}
+
+// ValidateLabels checks the keys and the values of the labels
+func ValidateLabels(labels map[string]string) error {
+	for key, value := range labels {
+		if err := ValidateLabelKey(key); err != nil {
+			return err
+		}
+		if err := ValidateLabelValue(value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type selectorOp string
+
+const (
+	opEquals    selectorOp = "="
+	opNotEquals selectorOp = "!="
+	opIn        selectorOp = "in"
+	opNotIn     selectorOp = "notin"
+	opExists    selectorOp = "exists"
+	opNotExists selectorOp = "!"
+)
+
+// requirement is a term of a label selector
+type requirement struct {
+	key    string
+	op     selectorOp
+	values []string
+}
+
+func (r *requirement) matches(labels map[string]string) bool {
+	value, found := labels[r.key]
+	switch r.op {
+	case opEquals:
+		return found && value == r.values[0]
+	case opNotEquals:
+		return !found || value != r.values[0]
+	case opIn:
+		return found && contains(r.values, value)
+	case opNotIn:
+		return !found || !contains(r.values, value)
+	case opExists:
+		return found
+	case opNotExists:
+		return !found
+	}
+	return false
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Selector selects volumes by their labels. All its requirements must be
+// met. The empty selector selects all the volumes.
+type Selector []requirement
+
+// Matches tells if the labels meet the requirements of the selector
+func (s Selector) Matches(labels map[string]string) bool {
+	for i := range s {
+		if !s[i].matches(labels) {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseSelector parses a label selector in the syntax of Kubernetes: comma
+// separated requirements, each one of
+//
+//	key=value, key==value, key!=value
+//	key in (value1,value2), key notin (value1,value2)
+//	key, !key
+func ParseSelector(selector string) (Selector, error) {
+	var s Selector
+	for _, term := range splitSelector(selector) {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+		r, err := parseRequirement(term)
+		if err != nil {
+			return nil, fmt.Errorf("invalid label selector %q: %s", selector, err)
+		}
+		s = append(s, *r)
+	}
+	return s, nil
+}
+
+// splitSelector splits the selector at the commas not within parentheses
+func splitSelector(selector string) []string {
+	var terms []string
+	depth, start := 0, 0
+	for i, c := range selector {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				terms = append(terms, selector[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(terms, selector[start:])
+}
+
+func parseRequirement(term string) (*requirement, error) {
+	r := &requirement{}
+	if m := setTermRe.FindStringSubmatch(term); m != nil {
+		r.key, r.op = m[1], selectorOp(m[2])
+		for _, v := range strings.Split(m[3], ",") {
+			v = strings.TrimSpace(v)
+			if err := ValidateLabelValue(v); err != nil {
+				return nil, err
+			}
+			r.values = append(r.values, v)
+		}
+	} else if strings.HasPrefix(term, "!") {
+		r.key, r.op = strings.TrimSpace(term[1:]), opNotExists
+	} else if i := strings.Index(term, "!="); i >= 0 {
+		r.key, r.op, r.values = term[:i], opNotEquals, []string{term[i+2:]}
+	} else if i := strings.Index(term, "=="); i >= 0 {
+		r.key, r.op, r.values = term[:i], opEquals, []string{term[i+2:]}
+	} else if i := strings.Index(term, "="); i >= 0 {
+		r.key, r.op, r.values = term[:i], opEquals, []string{term[i+1:]}
+	} else {
+		r.key, r.op = term, opExists
+	}
+
+	r.key = strings.TrimSpace(r.key)
+	if err := ValidateLabelKey(r.key); err != nil {
+		return nil, err
+	}
+	if r.op == opEquals || r.op == opNotEquals {
+		r.values[0] = strings.TrimSpace(r.values[0])
+		if err := ValidateLabelValue(r.values[0]); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// SelectorFilter returns a Filter keeping the volumes selected by the
+// selector
+func SelectorFilter(s Selector) Filter {
+	return func(volumes []*Volinfo) []*Volinfo {
+		var volInfos []*Volinfo
+		for _, volume := range volumes {
+			if s.Matches(volume.Labels) {
+				volInfos = append(volInfos, volume)
+			}
+		}
+		return volInfos
+	}
+}
@@ -0,0 +1,73 @@
+package volume
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateLabels(t *testing.T) {
+	assert.Nil(t, ValidateLabels(map[string]string{
+		"env":                    "prod",
+		"app.kubernetes.io/name": "db",
+		"empty":                  "",
+		"A_b.c-1":                "X_y.z-2",
+	}))
+
+	for _, key := range []string{"", "-env", "env-", "a/", "/env", "Example.com/env", "a b", strings.Repeat("k", 64)} {
+		assert.NotNil(t, ValidateLabelKey(key), key)
+	}
+	for _, value := range []string{"-prod", "prod-", "a b", "a,b", strings.Repeat("v", 64)} {
+		assert.NotNil(t, ValidateLabelValue(value), value)
+	}
+}
+
+func TestSelector(t *testing.T) {
+	labels := map[string]string{"env": "prod", "team": "db", "tier": "gold"}
+
+	for selector, matches := range map[string]bool{
+		"":                          true,
+		"env=prod":                  true,
+		"env==prod":                 true,
+		"env=prod,team=db":          true,
+		" env = prod , team = db ":  true,
+		"env=prod,team=web":         false,
+		"env!=dev":                  true,
+		"env!=prod":                 false,
+		"owner!=bob":                true,
+		"team in (db,web)":          true,
+		"team in (web, cache)":      false,
+		"team notin (web,cache)":    true,
+		"owner notin (bob)":         true,
+		"tier":                      true,
+		"owner":                     false,
+		"!owner":                    true,
+		"!tier":                     false,
+		"env=prod,team in (db),!x":  true,
+		"team in (db,web),env=prod": true,
+	} {
+		s, err := ParseSelector(selector)
+		require.Nil(t, err, selector)
+		assert.Equal(t, matches, s.Matches(labels), selector)
+	}
+
+	for _, selector := range []string{"env=pr od", "team in (db", "=prod", "!", "env>1", "team in (-db)"} {
+		_, err := ParseSelector(selector)
+		assert.NotNil(t, err, selector)
+	}
+}
+
+func TestSelectorFilter(t *testing.T) {
+	volumes := []*Volinfo{
+		{Name: "vol1", Labels: map[string]string{"env": "prod"}},
+		{Name: "vol2", Labels: map[string]string{"env": "dev"}},
+		{Name: "vol3"},
+	}
+	s, err := ParseSelector("env=prod")
+	require.Nil(t, err)
+	selected := ApplyCustomFilters(volumes, SelectorFilter(s))
+	require.Len(t, selected, 1)
+	assert.Equal(t, "vol1", selected[0].Name)
+}
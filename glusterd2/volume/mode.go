@@ -0,0 +1,95 @@
+package volume
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gluster/glusterd2/glusterd2/options"
+	"github.com/gluster/glusterd2/pkg/api"
+)
+
+// Keys of the volume options setting the mode of a volume, as saved in
+// Volinfo.Options
+const (
+	ReadOnlyKey             = "features/read-only.read-only"
+	WORMKey                 = "features/worm.worm"
+	WORMFileLevelKey        = "features/worm.worm-file-level"
+	WORMRetentionPeriodKey  = "features/worm.default-retention-period"
+	WORMAutoCommitPeriodKey = "features/worm.auto-commit-period"
+	WORMRetentionModeKey    = "features/worm.retention-mode"
+)
+
+// isOptionOn tells if the boolean option is set to true on the volume
+func (v *Volinfo) isOptionOn(key string) bool {
+	on, err := options.StringToBoolean(v.Options[key])
+	return err == nil && on
+}
+
+// Mode returns the mode of the volume, as set by its options
+func (v *Volinfo) Mode() api.VolumeMode {
+	switch {
+	case v.isOptionOn(WORMFileLevelKey):
+		return api.VolumeModeWORMFileLevel
+	case v.isOptionOn(WORMKey):
+		return api.VolumeModeWORM
+	case v.isOptionOn(ReadOnlyKey):
+		return api.VolumeModeReadOnly
+	}
+	return api.VolumeModeReadWrite
+}
+
+// IsWORM tells if the files of the volume are retained, in either of the
+// WORM modes
+func (v *Volinfo) IsWORM() bool {
+	mode := v.Mode()
+	return mode == api.VolumeModeWORM || mode == api.VolumeModeWORMFileLevel
+}
+
+// IsEnterpriseRetained tells if the files of the volume are retained in the
+// enterprise retention mode, where retention cannot be lifted
+func (v *Volinfo) IsEnterpriseRetained() bool {
+	return v.Mode() == api.VolumeModeWORMFileLevel && v.Options[WORMRetentionModeKey] == "enterprise"
+}
+
+// ModeOptions returns the volume options setting the requested mode
+func ModeOptions(req *api.VolumeModeReq) (map[string]string, error) {
+	opts := map[string]string{
+		ReadOnlyKey:      "off",
+		WORMKey:          "off",
+		WORMFileLevelKey: "off",
+	}
+
+	if req.Mode != api.VolumeModeWORMFileLevel &&
+		(req.RetentionPeriod != 0 || req.AutoCommitPeriod != 0 || req.RetentionMode != "") {
+		return nil, errors.New("retention options apply only to the worm-file-level mode")
+	}
+
+	switch req.Mode {
+	case api.VolumeModeReadWrite:
+	case api.VolumeModeReadOnly:
+		opts[ReadOnlyKey] = "on"
+	case api.VolumeModeWORM:
+		opts[WORMKey] = "on"
+	case api.VolumeModeWORMFileLevel:
+		opts[WORMFileLevelKey] = "on"
+		if req.RetentionPeriod < 0 || req.AutoCommitPeriod < 0 {
+			return nil, errors.New("retention periods must not be negative")
+		}
+		if req.RetentionPeriod > 0 {
+			opts[WORMRetentionPeriodKey] = strconv.Itoa(req.RetentionPeriod)
+		}
+		if req.AutoCommitPeriod > 0 {
+			opts[WORMAutoCommitPeriodKey] = strconv.Itoa(req.AutoCommitPeriod)
+		}
+		switch req.RetentionMode {
+		case "":
+		case "relax", "enterprise":
+			opts[WORMRetentionModeKey] = req.RetentionMode
+		default:
+			return nil, errors.New("retention mode must be relax or enterprise")
+		}
+	default:
+		return nil, errors.New("invalid volume mode")
+	}
+	return opts, nil
+}
@@ -0,0 +1,57 @@
+package volume
+
+import (
+	"testing"
+
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMode(t *testing.T) {
+	v := &Volinfo{Options: map[string]string{}}
+	assert.Equal(t, api.VolumeModeReadWrite, v.Mode())
+	assert.False(t, v.IsWORM())
+
+	v.Options[ReadOnlyKey] = "on"
+	assert.Equal(t, api.VolumeModeReadOnly, v.Mode())
+
+	v.Options[WORMKey] = "enable"
+	assert.Equal(t, api.VolumeModeWORM, v.Mode())
+	assert.True(t, v.IsWORM())
+
+	v.Options[WORMFileLevelKey] = "on"
+	assert.Equal(t, api.VolumeModeWORMFileLevel, v.Mode())
+	assert.False(t, v.IsEnterpriseRetained())
+	v.Options[WORMRetentionModeKey] = "enterprise"
+	assert.True(t, v.IsEnterpriseRetained())
+}
+
+func TestModeOptions(t *testing.T) {
+	opts, err := ModeOptions(&api.VolumeModeReq{Mode: api.VolumeModeReadOnly})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		ReadOnlyKey:      "on",
+		WORMKey:          "off",
+		WORMFileLevelKey: "off",
+	}, opts)
+
+	opts, err = ModeOptions(&api.VolumeModeReq{
+		Mode:            api.VolumeModeWORMFileLevel,
+		RetentionPeriod: 3600,
+		RetentionMode:   "enterprise",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "on", opts[WORMFileLevelKey])
+	assert.Equal(t, "3600", opts[WORMRetentionPeriodKey])
+	assert.Equal(t, "enterprise", opts[WORMRetentionModeKey])
+	assert.NotContains(t, opts, WORMAutoCommitPeriodKey)
+
+	_, err = ModeOptions(&api.VolumeModeReq{Mode: api.VolumeModeWORM, RetentionPeriod: 60})
+	assert.Error(t, err)
+	_, err = ModeOptions(&api.VolumeModeReq{Mode: api.VolumeModeWORMFileLevel, RetentionMode: "strict"})
+	assert.Error(t, err)
+	_, err = ModeOptions(&api.VolumeModeReq{Mode: "append-only"})
+	assert.Error(t, err)
+}
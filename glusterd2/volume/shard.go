@@ -0,0 +1,54 @@
+package volume
+
+// Keys of the volume options of the shard xlator, as saved in Volinfo.Options
+const (
+	ShardKey          = "features/shard.shard"
+	ShardBlockSizeKey = "features/shard.shard-block-size"
+)
+
+// shardedDataMetadataKey is the internal volume metadata recording that the
+// volume was started with sharding enabled, and may thus hold sharded files
+const shardedDataMetadataKey = "_sharded-data"
+
+// shardKeys are the keys sharding may be enabled with, as saved in
+// Volinfo.Options
+var shardKeys = [...]string{"client." + ShardKey, ShardKey, "features/shard"}
+
+// IsShardKey tells if the volume option enables or disables sharding
+func IsShardKey(key string) bool {
+	for _, k := range shardKeys {
+		if key == k {
+			return true
+		}
+	}
+	return false
+}
+
+// IsSharded tells if the files of the volume are sharded
+func (v *Volinfo) IsSharded() bool {
+	for _, k := range shardKeys {
+		if _, ok := v.Options[k]; ok {
+			return v.isOptionOn(k)
+		}
+	}
+	return false
+}
+
+// MarkShardedData records that the volume may hold sharded files, when
+// sharding is enabled on it. It is called when the volume is started.
+func (v *Volinfo) MarkShardedData() {
+	if !v.IsSharded() {
+		return
+	}
+	if v.Metadata == nil {
+		v.Metadata = make(map[string]string)
+	}
+	v.Metadata[shardedDataMetadataKey] = "yes"
+}
+
+// HasShardedData tells if the volume may hold sharded files, which can no
+// longer be read once sharding is disabled
+func (v *Volinfo) HasShardedData() bool {
+	_, ok := v.Metadata[shardedDataMetadataKey]
+	return ok
+}
@@ -0,0 +1,39 @@
+package volume
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsSharded(t *testing.T) {
+	v := &Volinfo{Options: map[string]string{}}
+	assert.False(t, v.IsSharded())
+
+	v.Options["features/shard"] = "on"
+	assert.True(t, v.IsSharded())
+
+	v.Options[ShardKey] = "off"
+	assert.False(t, v.IsSharded())
+
+	v.Options["client."+ShardKey] = "enable"
+	assert.True(t, v.IsSharded())
+
+	assert.True(t, IsShardKey(ShardKey))
+	assert.False(t, IsShardKey(ShardBlockSizeKey))
+}
+
+func TestMarkShardedData(t *testing.T) {
+	v := &Volinfo{Options: map[string]string{}}
+	v.MarkShardedData()
+	assert.False(t, v.HasShardedData())
+
+	v.Options[ShardKey] = "on"
+	v.MarkShardedData()
+	assert.True(t, v.HasShardedData())
+
+	// the files stay sharded once sharding is disabled
+	v.Options[ShardKey] = "off"
+	v.MarkShardedData()
+	assert.True(t, v.HasShardedData())
+}
@@ -50,6 +50,33 @@ func AddOrUpdateVolume(v *Volinfo) error {
 	return nil
 }
 
+// AddVolume stores a new volume. The volume is stored in a single store
+// transaction, which also removes the utilization left by a deleted volume
+// of the same name, so that either the whole volume is stored or nothing.
+// It fails with ErrVolExists if a volume of the same name exists.
+func AddVolume(v *Volinfo) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		log.WithError(err).Error("Failed to marshal the volinfo object")
+		return err
+	}
+
+	key := volumePrefix + v.Name
+	ops := append([]clientv3.Op{clientv3.OpPut(key, string(data))}, deleteUsageOps(v.Name)...)
+	resp, err := store.Txn(context.TODO()).
+		If(clientv3.Compare(clientv3.CreateRevision(key), "=", 0)).
+		Then(ops...).
+		Commit()
+	if err != nil {
+		log.WithError(err).Error("Couldn't add volume to store")
+		return err
+	}
+	if !resp.Succeeded {
+		return gderror.ErrVolExists
+	}
+	return nil
+}
+
 // GetVolume fetches the json object from the store and unmarshalls it into
 // volinfo object
 func GetVolume(name string) (*Volinfo, error) {
@@ -74,7 +101,10 @@ func GetVolume(name string) (*Volinfo, error) {
 //DeleteVolume passes the volname to store to delete the volume object
 func DeleteVolume(name string) error {
 	_, e := store.Delete(context.TODO(), volumePrefix+name)
-	return e
+	if e != nil {
+		return e
+	}
+	return DeleteUsage(name)
 }
 
 // GetVolumesList returns a map of volume names to their UUIDs
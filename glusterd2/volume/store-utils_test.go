@@ -0,0 +1,62 @@
+package volume
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gluster/glusterd2/glusterd2/store"
+	gderror "github.com/gluster/glusterd2/pkg/errors"
+	"github.com/gluster/glusterd2/pkg/testutils"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// storeUsage stores the utilization of a deleted volume and of its brick
+func storeUsage(t *testing.T, volname string) {
+	_, err := store.Put(context.TODO(), volumeUsagePrefix+volname, "{}")
+	require.Nil(t, err)
+	_, err = store.Put(context.TODO(), brickUsagePrefix+volname+"/"+uuid.New(), "{}")
+	require.Nil(t, err)
+}
+
+// usageKeys returns the number of keys of the stored utilization of the
+// volume and of its bricks
+func usageKeys(t *testing.T, volname string) int64 {
+	resp, err := store.Get(context.TODO(), volumeUsagePrefix+volname)
+	require.Nil(t, err)
+	count := resp.Count
+	resp, err = store.Get(context.TODO(), brickUsagePrefix+volname+"/", clientv3.WithPrefix())
+	require.Nil(t, err)
+	return count + resp.Count
+}
+
+// TestAddVolume validates that a new volume is stored along with the
+// removal of the utilization left by a deleted volume of the same name, and
+// that nothing is stored nor removed if a volume of the same name exists
+func TestAddVolume(t *testing.T) {
+	defer testutils.InitStore(t)()
+
+	storeUsage(t, "gv0")
+	storeUsage(t, "gv00")
+
+	v := &Volinfo{ID: uuid.NewRandom(), Name: "gv0"}
+	require.Nil(t, AddVolume(v))
+	stored, err := GetVolume("gv0")
+	require.Nil(t, err)
+	assert.True(t, uuid.Equal(v.ID, stored.ID))
+	assert.Equal(t, int64(0), usageKeys(t, "gv0"))
+	assert.Equal(t, int64(2), usageKeys(t, "gv00"))
+
+	// The volume of the same name is not replaced, and the utilization
+	// stored since is not removed
+	storeUsage(t, "gv0")
+	other := &Volinfo{ID: uuid.NewRandom(), Name: "gv0"}
+	assert.Equal(t, gderror.ErrVolExists, AddVolume(other))
+	stored, err = GetVolume("gv0")
+	require.Nil(t, err)
+	assert.True(t, uuid.Equal(v.ID, stored.ID))
+	assert.Equal(t, int64(2), usageKeys(t, "gv0"))
+}
@@ -100,6 +100,13 @@ type Volinfo struct {
 	SnapshotReserveFactor float64
 	Capacity              uint64
 	ProvisionerType       string
+	// BrickUser is the user the brick processes of the volume are run
+	// as, root if empty
+	BrickUser string
+	// Namespace is the namespace the volume belongs to, none if empty
+	Namespace string
+	// Labels identify the volume, and select it in the volume lists
+	Labels map[string]string
 }
 
 // VolAuth represents username and password used by trusted/internal clients
@@ -183,6 +190,7 @@ func NewBrickEntries(bricks []api.BrickReq, volName, volfileID string, volID uui
 			VgName:     b.VgName,
 			RootDevice: b.RootDevice,
 			TotalSize:  b.TotalSize,
+			Encrypted:  b.Encrypted,
 		}
 
 		binfo.PType = ptype
@@ -0,0 +1,83 @@
+package volume
+
+import (
+	"path"
+	"sort"
+	"strings"
+)
+
+// subdirMetadataPrefix prefixes the internal volume metadata recording the
+// exported subdirectories. The value is the list of clients allowed to mount
+// the subdirectory, separated by "|".
+const subdirMetadataPrefix = "_subdir."
+
+// SubdirExports returns the exported subdirectories of the volume, mapped to
+// the clients allowed to mount them
+func (v *Volinfo) SubdirExports() map[string][]string {
+	exports := make(map[string][]string)
+	for k, val := range v.Metadata {
+		if strings.HasPrefix(k, subdirMetadataPrefix) {
+			exports[strings.TrimPrefix(k, subdirMetadataPrefix)] = strings.Split(val, "|")
+		}
+	}
+	return exports
+}
+
+// IsSubdirExported returns true if the subdirectory of the volume is exported
+func (v *Volinfo) IsSubdirExported(subdir string) bool {
+	_, ok := v.Metadata[subdirMetadataPrefix+path.Clean("/"+subdir)]
+	return ok
+}
+
+// SetSubdirExport exports the subdirectory of the volume to the given
+// clients, which are addresses or hostnames, possibly with wildcards
+func (v *Volinfo) SetSubdirExport(subdir string, allow []string) {
+	if v.Metadata == nil {
+		v.Metadata = make(map[string]string)
+	}
+	v.Metadata[subdirMetadataPrefix+path.Clean("/"+subdir)] = strings.Join(allow, "|")
+}
+
+// RemoveSubdirExport stops exporting the subdirectory of the volume
+func (v *Volinfo) RemoveSubdirExport(subdir string) {
+	delete(v.Metadata, subdirMetadataPrefix+path.Clean("/"+subdir))
+}
+
+// SubdirAuthAllow returns the auth.allow value of the bricks of the volume
+// restricting each exported subdirectory to its clients, in the
+// "/(*),/dir1(host1|host2)" form understood by protocol/server. The root of
+// the volume remains accessible to all the clients unless "/" itself is
+// exported. An empty string is returned if no subdirectory is exported.
+func (v *Volinfo) SubdirAuthAllow() string {
+	exports := v.SubdirExports()
+	if len(exports) == 0 {
+		return ""
+	}
+	if _, ok := exports["/"]; !ok {
+		exports["/"] = []string{"*"}
+	}
+
+	dirs := make([]string, 0, len(exports))
+	for dir := range exports {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+
+	auth := make([]string, len(dirs))
+	for i, dir := range dirs {
+		auth[i] = dir + "(" + strings.Join(exports[dir], "|") + ")"
+	}
+	return strings.Join(auth, ",")
+}
+
+// SplitSubdirVolfileID splits the volfile ID requested by a client mounting
+// a subdirectory, like "vol/dir1/dir2", into the volume name and the
+// subdirectory. ok is false if the volfile ID does not refer to a
+// subdirectory.
+func SplitSubdirVolfileID(volfileID string) (volname string, subdir string, ok bool) {
+	parts := strings.SplitN(strings.TrimPrefix(volfileID, "/"), "/", 2)
+	if len(parts) != 2 || parts[1] == "" || !IsValidName(parts[0]) {
+		return "", "", false
+	}
+	return parts[0], path.Clean("/" + parts[1]), true
+}
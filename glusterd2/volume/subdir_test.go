@@ -0,0 +1,47 @@
+package volume
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubdirAuthAllow(t *testing.T) {
+	v := &Volinfo{}
+	assert.Equal(t, "", v.SubdirAuthAllow())
+
+	v.SetSubdirExport("tenant2/", []string{"10.0.0.3"})
+	v.SetSubdirExport("/tenant1", []string{"10.0.0.1", "client1.example.com"})
+	assert.True(t, v.IsSubdirExported("tenant1"))
+	assert.False(t, v.IsSubdirExported("tenant3"))
+	assert.Equal(t, "/(*),/tenant1(10.0.0.1|client1.example.com),/tenant2(10.0.0.3)", v.SubdirAuthAllow())
+
+	v.SetSubdirExport("/", []string{"10.0.0.100"})
+	assert.Equal(t, "/(10.0.0.100),/tenant1(10.0.0.1|client1.example.com),/tenant2(10.0.0.3)", v.SubdirAuthAllow())
+
+	v.RemoveSubdirExport("/")
+	v.RemoveSubdirExport("tenant1")
+	assert.Equal(t, "/(*),/tenant2(10.0.0.3)", v.SubdirAuthAllow())
+
+	// The exports are internal metadata
+	assert.Equal(t, 0, v.MetadataSize())
+}
+
+func TestSplitSubdirVolfileID(t *testing.T) {
+	for _, tc := range []struct {
+		id      string
+		volname string
+		subdir  string
+		ok      bool
+	}{
+		{"testvol", "", "", false},
+		{"testvol/", "", "", false},
+		{"testvol/dir1", "testvol", "/dir1", true},
+		{"/testvol/dir1/dir2/", "testvol", "/dir1/dir2", true},
+	} {
+		volname, subdir, ok := SplitSubdirVolfileID(tc.id)
+		assert.Equal(t, tc.ok, ok, tc.id)
+		assert.Equal(t, tc.volname, volname, tc.id)
+		assert.Equal(t, tc.subdir, subdir, tc.id)
+	}
+}
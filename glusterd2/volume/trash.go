@@ -0,0 +1,127 @@
+package volume
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/store"
+	gderror "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// trashPrefix is where the deleted volumes are retained, under their ID,
+// as several deleted volumes may have had the same name
+const trashPrefix = "trash/volumes/"
+
+// TrashedVolume is a deleted volume whose bricks are retained until it is
+// purged, so that it can be undeleted meanwhile
+type TrashedVolume struct {
+	Volinfo   *Volinfo  `json:"volinfo"`
+	DeletedAt time.Time `json:"deleted-at"`
+	PurgeAt   time.Time `json:"purge-at"`
+	// BrickData is what is done with the data of the bricks when the
+	// volume is purged, one of the api.BrickData* values
+	BrickData string `json:"brick-data"`
+}
+
+// Expired tells if the retention period of the trashed volume is over
+func (t *TrashedVolume) Expired(now time.Time) bool {
+	return !now.Before(t.PurgeAt)
+}
+
+// TrashVolume moves the volume to the trash, hiding it from the volumes of
+// the cluster until it is purged at purgeAt
+func TrashVolume(v *Volinfo, purgeAt time.Time, brickData string) error {
+	t := TrashedVolume{
+		Volinfo:   v,
+		DeletedAt: time.Now(),
+		PurgeAt:   purgeAt,
+		BrickData: brickData,
+	}
+	data, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+
+	_, err = store.Txn(context.TODO()).
+		Then(clientv3.OpPut(trashPrefix+v.ID.String(), string(data)),
+			clientv3.OpDelete(volumePrefix+v.Name)).
+		Commit()
+	if err != nil {
+		return err
+	}
+	return DeleteUsage(v.Name)
+}
+
+// UntrashVolume moves the trashed volume back to the volumes of the cluster.
+// It fails if a volume with the same name was created since.
+func UntrashVolume(id uuid.UUID) (*Volinfo, error) {
+	t, err := GetTrashedVolume(id)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(t.Volinfo)
+	if err != nil {
+		return nil, err
+	}
+
+	key := volumePrefix + t.Volinfo.Name
+	resp, err := store.Txn(context.TODO()).
+		If(clientv3.Compare(clientv3.CreateRevision(key), "=", 0)).
+		Then(clientv3.OpPut(key, string(data)),
+			clientv3.OpDelete(trashPrefix+id.String())).
+		Commit()
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Succeeded {
+		return nil, gderror.ErrVolExists
+	}
+	return t.Volinfo, nil
+}
+
+// GetTrashedVolume returns the trashed volume with the ID
+func GetTrashedVolume(id uuid.UUID) (*TrashedVolume, error) {
+	resp, err := store.Get(context.TODO(), trashPrefix+id.String())
+	if err != nil {
+		return nil, err
+	}
+	if resp.Count != 1 {
+		return nil, gderror.ErrTrashedVolNotFound
+	}
+
+	var t TrashedVolume
+	if err := json.Unmarshal(resp.Kvs[0].Value, &t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// GetTrashedVolumes returns the volumes in the trash
+func GetTrashedVolumes() ([]*TrashedVolume, error) {
+	resp, err := store.Get(context.TODO(), trashPrefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	trashed := make([]*TrashedVolume, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var t TrashedVolume
+		if err := json.Unmarshal(kv.Value, &t); err != nil {
+			log.WithError(err).WithField("key", string(kv.Key)).Error("Failed to unmarshal trashed volume")
+			continue
+		}
+		trashed = append(trashed, &t)
+	}
+	return trashed, nil
+}
+
+// DeleteTrashedVolume removes the trashed volume from the store for good
+func DeleteTrashedVolume(id uuid.UUID) error {
+	_, err := store.Delete(context.TODO(), trashPrefix+id.String())
+	return err
+}
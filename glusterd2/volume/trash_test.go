@@ -0,0 +1,16 @@
+package volume
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrashedVolumeExpired(t *testing.T) {
+	now := time.Now()
+	tv := &TrashedVolume{DeletedAt: now.Add(-time.Hour), PurgeAt: now}
+	assert.True(t, tv.Expired(now))
+	assert.True(t, tv.Expired(now.Add(time.Second)))
+	assert.False(t, tv.Expired(now.Add(-time.Second)))
+}
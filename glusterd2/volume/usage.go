@@ -0,0 +1,226 @@
+package volume
+
+import (
+	"context"
+	"encoding/json"
+	"syscall"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/store"
+
+	"github.com/coreos/etcd/clientv3"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	brickUsagePrefix  string = "brickusage/"
+	volumeUsagePrefix string = "volumeusage/"
+)
+
+// Usage represents the space and inode utilization of a brick, a subvolume
+// or a volume
+type Usage struct {
+	Capacity   uint64
+	Used       uint64
+	Free       uint64
+	Inodes     uint64
+	InodesUsed uint64
+	InodesFree uint64
+	UpdatedAt  time.Time
+}
+
+// Utilization returns the percentage of used space
+func (u *Usage) Utilization() float64 {
+	if u.Capacity == 0 {
+		return 0
+	}
+	return float64(u.Used) * 100 / float64(u.Capacity)
+}
+
+// InodeUtilization returns the percentage of used inodes
+func (u *Usage) InodeUtilization() float64 {
+	if u.Inodes == 0 {
+		return 0
+	}
+	return float64(u.InodesUsed) * 100 / float64(u.Inodes)
+}
+
+// CreateUsage creates Usage from the result of statfs
+func CreateUsage(fstat *syscall.Statfs_t) *Usage {
+	u := Usage{UpdatedAt: time.Now()}
+	if fstat != nil {
+		u.Capacity = fstat.Blocks * uint64(fstat.Bsize)
+		u.Free = fstat.Bavail * uint64(fstat.Bsize)
+		u.Used = (fstat.Blocks - fstat.Bfree) * uint64(fstat.Bsize)
+		u.Inodes = fstat.Files
+		u.InodesFree = fstat.Ffree
+		u.InodesUsed = fstat.Files - fstat.Ffree
+	}
+	return &u
+}
+
+// BrickUsageInfo does a statfs on the brick path and returns its utilization
+func BrickUsageInfo(b *brick.Brickinfo) (*Usage, error) {
+	var fstat syscall.Statfs_t
+	if err := syscall.Statfs(b.Path, &fstat); err != nil {
+		return nil, err
+	}
+	return CreateUsage(&fstat), nil
+}
+
+// AggregateUsage computes the utilization of the volume from the utilization
+// of its bricks, keyed by brick ID. It returns nil if the utilization of any
+// of the subvolumes cannot be determined.
+func AggregateUsage(v *Volinfo, bricks map[string]*Usage) *Usage {
+	var total Usage
+	for i := range v.Subvols {
+		u := subvolUsage(&v.Subvols[i], bricks)
+		if u == nil {
+			return nil
+		}
+		// Files are distributed across subvolumes, hence the sum
+		total.Capacity += u.Capacity
+		total.Used += u.Used
+		total.Free += u.Free
+		total.Inodes += u.Inodes
+		total.InodesUsed += u.InodesUsed
+		total.InodesFree += u.InodesFree
+		if u.UpdatedAt.After(total.UpdatedAt) {
+			total.UpdatedAt = u.UpdatedAt
+		}
+	}
+	return &total
+}
+
+// subvolUsage computes the utilization of a subvolume. Bricks of a replica
+// set hold the same data, so the subvolume can hold only as much as its
+// smallest brick. Disperse subvolumes stripe data across the data bricks,
+// but every brick holds an inode for every file.
+func subvolUsage(sv *Subvol, bricks map[string]*Usage) *Usage {
+	var (
+		u     Usage
+		count uint64
+	)
+	for _, b := range sv.Bricks {
+		// Arbiter bricks hold only metadata
+		if b.Type != brick.Brick {
+			continue
+		}
+		bu, ok := bricks[b.ID.String()]
+		if !ok {
+			// Usage of an offline brick is not known, use the rest of
+			// the replica/disperse set
+			continue
+		}
+		if count == 0 || bu.Capacity < u.Capacity {
+			u.Capacity = bu.Capacity
+		}
+		if count == 0 || bu.Free < u.Free {
+			u.Free = bu.Free
+		}
+		if bu.Used > u.Used {
+			u.Used = bu.Used
+		}
+		if count == 0 || bu.Inodes < u.Inodes {
+			u.Inodes = bu.Inodes
+		}
+		if count == 0 || bu.InodesFree < u.InodesFree {
+			u.InodesFree = bu.InodesFree
+		}
+		if bu.InodesUsed > u.InodesUsed {
+			u.InodesUsed = bu.InodesUsed
+		}
+		if bu.UpdatedAt.After(u.UpdatedAt) {
+			u.UpdatedAt = bu.UpdatedAt
+		}
+		count++
+	}
+
+	if count == 0 {
+		return nil
+	}
+
+	if sv.Type == SubvolDisperse {
+		data := uint64(sv.DisperseCount - sv.RedundancyCount)
+		u.Capacity *= data
+		u.Used *= data
+		u.Free *= data
+	}
+
+	return &u
+}
+
+// AddOrUpdateBrickUsage stores the utilization of a brick
+func AddOrUpdateBrickUsage(b *brick.Brickinfo, u *Usage) error {
+	data, err := json.Marshal(u)
+	if err != nil {
+		return err
+	}
+	_, err = store.Put(context.TODO(), brickUsagePrefix+b.VolumeName+"/"+b.ID.String(), string(data))
+	return err
+}
+
+// GetBrickUsages returns the last known utilization of the bricks of a
+// volume, keyed by brick ID
+func GetBrickUsages(volname string) (map[string]*Usage, error) {
+	prefix := brickUsagePrefix + volname + "/"
+	resp, err := store.Get(context.TODO(), prefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	usages := make(map[string]*Usage, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var u Usage
+		if err := json.Unmarshal(kv.Value, &u); err != nil {
+			log.WithError(err).WithField("key", string(kv.Key)).Error("Failed to unmarshal brick usage")
+			continue
+		}
+		usages[string(kv.Key)[len(prefix):]] = &u
+	}
+	return usages, nil
+}
+
+// AddOrUpdateUsage stores the aggregated utilization of a volume
+func AddOrUpdateUsage(volname string, u *Usage) error {
+	data, err := json.Marshal(u)
+	if err != nil {
+		return err
+	}
+	_, err = store.Put(context.TODO(), volumeUsagePrefix+volname, string(data))
+	return err
+}
+
+// GetUsage returns the last aggregated utilization of a volume. It returns
+// nil if the utilization has not been computed yet.
+func GetUsage(volname string) (*Usage, error) {
+	resp, err := store.Get(context.TODO(), volumeUsagePrefix+volname)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Count != 1 {
+		return nil, nil
+	}
+
+	var u Usage
+	if err := json.Unmarshal(resp.Kvs[0].Value, &u); err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+// deleteUsageOps returns the store operations removing the stored
+// utilization of a volume and its bricks
+func deleteUsageOps(volname string) []clientv3.Op {
+	return []clientv3.Op{
+		clientv3.OpDelete(volumeUsagePrefix + volname),
+		clientv3.OpDelete(brickUsagePrefix+volname+"/", clientv3.WithPrefix()),
+	}
+}
+
+// DeleteUsage removes the stored utilization of a volume and its bricks
+func DeleteUsage(volname string) error {
+	_, err := store.Txn(context.TODO()).Then(deleteUsageOps(volname)...).Commit()
+	return err
+}
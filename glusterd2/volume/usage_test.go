@@ -0,0 +1,101 @@
+package volume
+
+import (
+	"testing"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func sampleUsageBricks(usages map[string]*Usage, sizes ...uint64) []brick.Brickinfo {
+	var bricks []brick.Brickinfo
+	for _, size := range sizes {
+		b := brick.Brickinfo{ID: uuid.NewRandom(), Type: brick.Brick}
+		usages[b.ID.String()] = &Usage{
+			Capacity:   size,
+			Used:       size / 4,
+			Free:       size - size/4,
+			Inodes:     size / 10,
+			InodesUsed: size / 100,
+			InodesFree: size/10 - size/100,
+		}
+		bricks = append(bricks, b)
+	}
+	return bricks
+}
+
+// TestAggregateUsageReplicate validates that a replica set is only as large
+// as its smallest brick and that arbiter bricks are ignored
+func TestAggregateUsageReplicate(t *testing.T) {
+	usages := make(map[string]*Usage)
+	bricks := sampleUsageBricks(usages, 1000, 2000, 10)
+	bricks[2].Type = brick.Arbiter
+
+	v := &Volinfo{
+		Subvols: []Subvol{{Type: SubvolReplicate, Bricks: bricks, ReplicaCount: 2, ArbiterCount: 1}},
+	}
+	u := AggregateUsage(v, usages)
+	assert.NotNil(t, u)
+	assert.Equal(t, uint64(1000), u.Capacity)
+	assert.Equal(t, uint64(500), u.Used)
+	assert.Equal(t, uint64(750), u.Free)
+	assert.Equal(t, uint64(100), u.Inodes)
+	assert.Equal(t, 50.0, u.Utilization())
+}
+
+// TestAggregateUsageDisperse validates that only the data bricks of a
+// disperse set count towards capacity
+func TestAggregateUsageDisperse(t *testing.T) {
+	usages := make(map[string]*Usage)
+	bricks := sampleUsageBricks(usages, 1000, 1000, 1000)
+
+	v := &Volinfo{
+		Subvols: []Subvol{{Type: SubvolDisperse, Bricks: bricks, DisperseCount: 3, RedundancyCount: 1}},
+	}
+	u := AggregateUsage(v, usages)
+	assert.NotNil(t, u)
+	assert.Equal(t, uint64(2000), u.Capacity)
+	assert.Equal(t, uint64(500), u.Used)
+	assert.Equal(t, uint64(100), u.Inodes)
+}
+
+// TestAggregateUsageDistribute validates that utilization of subvolumes is
+// summed up and that a subvolume without any known usage fails aggregation
+func TestAggregateUsageDistribute(t *testing.T) {
+	usages := make(map[string]*Usage)
+	v := &Volinfo{
+		Subvols: []Subvol{
+			{Type: SubvolDistribute, Bricks: sampleUsageBricks(usages, 1000)},
+			{Type: SubvolDistribute, Bricks: sampleUsageBricks(usages, 3000)},
+		},
+	}
+	u := AggregateUsage(v, usages)
+	assert.NotNil(t, u)
+	assert.Equal(t, uint64(4000), u.Capacity)
+	assert.Equal(t, uint64(1000), u.Used)
+	assert.Equal(t, uint64(400), u.Inodes)
+	assert.Equal(t, uint64(40), u.InodesUsed)
+
+	v.Subvols = append(v.Subvols, Subvol{
+		Type:   SubvolDistribute,
+		Bricks: []brick.Brickinfo{{ID: uuid.NewRandom(), Type: brick.Brick}},
+	})
+	assert.Nil(t, AggregateUsage(v, usages))
+}
+
+// TestDeleteUsageOps validates that the utilization of the volume and of all
+// its bricks, and only its bricks, is removed
+func TestDeleteUsageOps(t *testing.T) {
+	ops := deleteUsageOps("gv0")
+	assert.Len(t, ops, 2)
+
+	assert.True(t, ops[0].IsDelete())
+	assert.Equal(t, volumeUsagePrefix+"gv0", string(ops[0].KeyBytes()))
+	assert.Empty(t, ops[0].RangeBytes())
+
+	assert.True(t, ops[1].IsDelete())
+	assert.Equal(t, brickUsagePrefix+"gv0/", string(ops[1].KeyBytes()))
+	assert.Equal(t, brickUsagePrefix+"gv00", string(ops[1].RangeBytes()))
+}
@@ -7,11 +7,12 @@ import (
 	"path"
 	"path/filepath"
 	"regexp"
-	"strconv"
 	"strings"
 	"syscall"
 
 	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/brickcrypt"
+	"github.com/gluster/glusterd2/glusterd2/cgroups"
 	"github.com/gluster/glusterd2/glusterd2/daemon"
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
 	"github.com/gluster/glusterd2/glusterd2/pmap"
@@ -106,9 +107,16 @@ func BrickStatus(binfo brick.Brickinfo, mtabEntries []*Mntent) (brick.Brickstatu
 			s.Online = true
 			s.Pid = pidOnFile
 			s.Port, _ = pmap.RegistrySearch(binfo.Path)
+			s.Resources = cgroups.Usage(pidOnFile)
 		}
 	}
 
+	if d, err := brick.GetDegraded(binfo); err != nil {
+		log.WithError(err).WithField("brick", binfo.String()).Error("failed to get the degraded state of the brick")
+	} else {
+		s.Degraded = d
+	}
+
 	var fstat syscall.Statfs_t
 	if err := syscall.Statfs(binfo.Path, &fstat); err != nil {
 		log.WithError(err).WithField("path",
@@ -223,6 +231,11 @@ func CreateVolumeInfoResp(v *Volinfo) *api.VolumeInfo {
 		Subvols:   CreateSubvolInfo(&v.Subvols),
 		Metadata:  v.Metadata,
 		SnapList:  v.SnapList,
+		Mode:      v.Mode(),
+		Encrypted: v.IsEncrypted(),
+		BrickUser: v.BrickUser,
+		Namespace: v.Namespace,
+		Labels:    v.Labels,
 	}
 
 	// for common use cases, replica count of the volume is usually the
@@ -247,6 +260,16 @@ func (v *Volinfo) IsAutoProvisioned() bool {
 	return (v.GetProvisionType().IsAutoProvisioned())
 }
 
+// IsEncrypted tells if the bricks of the volume are encrypted at rest
+func (v *Volinfo) IsEncrypted() bool {
+	for _, b := range v.GetBricks() {
+		if !b.Encrypted {
+			return false
+		}
+	}
+	return len(v.Subvols) > 0
+}
+
 //GetProvisionType will return true the type of provision state
 func (v *Volinfo) GetProvisionType() brick.ProvisionType {
 
@@ -282,12 +305,26 @@ func CleanBricksLvm(volinfo *Volinfo) error {
 			}
 		}
 
-		parts := strings.Split(b.MountInfo.DevicePath, "/")
-		if len(parts) != 4 {
-			return errors.New("unable to parse device path")
+		var vgname, lvname string
+		if b.DeviceInfo.Encrypted {
+			// The device path is the mapping of the encrypted LV
+			vgname = b.DeviceInfo.VgName
+			lvname = b.DeviceInfo.LvName
+			if err := brickcrypt.Remove(vgname, lvname); err != nil {
+				log.WithError(err).WithFields(log.Fields{
+					"vg-name": vgname,
+					"lv-name": lvname,
+				}).Error("failed to remove brick encryption")
+				return err
+			}
+		} else {
+			parts := strings.Split(b.MountInfo.DevicePath, "/")
+			if len(parts) != 4 {
+				return errors.New("unable to parse device path")
+			}
+			vgname = parts[2]
+			lvname = parts[3]
 		}
-		vgname := parts[2]
-		lvname := parts[3]
 
 		// Remove LV
 		err = lvmutils.RemoveLV(vgname, lvname, true)
@@ -400,28 +437,3 @@ func CleanBricksLoop(volinfo *Volinfo) error {
 	}
 	return nil
 }
-
-//AddThinArbiter adds thin arbiter option to the volume create request
-func AddThinArbiter(req *api.VolCreateReq, thinArbiter string) error {
-
-	s := strings.Split(thinArbiter, ":")
-	if len(s) != 2 && len(s) != 3 {
-		return errors.New("thin arbiter brick must be of the form <host>:<brick> or <host>:<brick>:<port>")
-	}
-
-	// TODO: If required, handle this in a generic way, just like other
-	// volume set options that we're going to allow to be set during
-	// volume create.
-	req.Options["replicate.thin-arbiter"] = thinArbiter
-	req.AllowAdvanced = true
-	return nil
-}
-
-//AddShard adds shard options to the volume create request
-func AddShard(req *api.VolCreateReq, shardSize uint64) {
-
-	req.Options["features/shard"] = "on"
-	req.Options["features/shard.shard-block-size"] = strconv.FormatUint(shardSize, 10)
-	req.AllowAdvanced = true
-	return
-}
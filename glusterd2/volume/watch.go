@@ -0,0 +1,130 @@
+package volume
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/gluster/glusterd2/glusterd2/store"
+	gderror "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/coreos/etcd/clientv3"
+	log "github.com/sirupsen/logrus"
+)
+
+// GetVolumesRevision returns the volumes along with the revision of the store
+// they were read at. The changes made to the volumes after it are returned by
+// WatchVolumes.
+func GetVolumesRevision(ctx context.Context) ([]*Volinfo, int64, error) {
+	resp, err := store.Get(ctx, volumePrefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var volumes []*Volinfo
+	for _, kv := range resp.Kvs {
+		var vol Volinfo
+		if err := json.Unmarshal(kv.Value, &vol); err != nil {
+			log.WithError(err).WithField("volume", string(kv.Key)).Error("Failed to unmarshal volume")
+			continue
+		}
+		volumes = append(volumes, &vol)
+	}
+	return volumes, resp.Header.Revision, nil
+}
+
+// WatchVolumes waits for the volumes to change after the revision since, and
+// returns the volumes changed and the volumes deleted, as they were before
+// their deletion, along with the revision of the last change. If nothing
+// changed until ctx is done, no volumes and since are returned.
+// ErrRevisionCompacted is returned if the changes after since are no longer
+// kept by the store.
+func WatchVolumes(ctx context.Context, since int64) (changed, deleted []*Volinfo, rev int64, err error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	wch := store.Store.Watch(ctx, volumePrefix, clientv3.WithPrefix(),
+		clientv3.WithRev(since+1), clientv3.WithPrevKV())
+	w := store.TrackWatch("volume-list", volumePrefix)
+	defer w.Stop()
+
+	var events []*clientv3.Event
+	// Wait for the first changes, then take the changes already sent
+	// along with them
+	for wait := true; ; wait = false {
+		var resp clientv3.WatchResponse
+		var ok bool
+		if wait {
+			select {
+			case resp, ok = <-wch:
+			case <-ctx.Done():
+				return nil, nil, since, nil
+			}
+		} else {
+			select {
+			case resp, ok = <-wch:
+			default:
+				ok = false
+			}
+		}
+		if !ok {
+			break
+		}
+
+		w.Observe(resp)
+		if resp.CompactRevision != 0 {
+			return nil, nil, 0, gderror.ErrRevisionCompacted
+		}
+		if err := resp.Err(); err != nil {
+			return nil, nil, 0, err
+		}
+		events = append(events, resp.Events...)
+		if resp.Canceled {
+			break
+		}
+	}
+
+	if len(events) == 0 {
+		return nil, nil, since, nil
+	}
+	changed, deleted = volumeChanges(events)
+	return changed, deleted, events[len(events)-1].Kv.ModRevision, nil
+}
+
+// volumeChanges returns the volumes changed and the volumes deleted by the
+// events, keeping the last event of each volume
+func volumeChanges(events []*clientv3.Event) (changed, deleted []*Volinfo) {
+	var names []string
+	last := make(map[string]*clientv3.Event)
+	for _, ev := range events {
+		name := strings.TrimPrefix(string(ev.Kv.Key), volumePrefix)
+		if _, ok := last[name]; !ok {
+			names = append(names, name)
+		}
+		last[name] = ev
+	}
+
+	for _, name := range names {
+		ev := last[name]
+		var vol Volinfo
+		switch {
+		case ev.Type == clientv3.EventTypeDelete:
+			// The volume is known by its name alone when the store
+			// no longer has it as it was
+			vol.Name = name
+			if ev.PrevKv != nil {
+				if err := json.Unmarshal(ev.PrevKv.Value, &vol); err != nil {
+					vol = Volinfo{Name: name}
+				}
+			}
+			deleted = append(deleted, &vol)
+		default:
+			if err := json.Unmarshal(ev.Kv.Value, &vol); err != nil {
+				log.WithError(err).WithField("volume", name).Error("Failed to unmarshal volume")
+				continue
+			}
+			changed = append(changed, &vol)
+		}
+	}
+	return changed, deleted
+}
@@ -0,0 +1,58 @@
+package volume
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/coreos/etcd/mvcc/mvccpb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVolumeChanges(t *testing.T) {
+	kv := func(v *Volinfo, rev int64) *mvccpb.KeyValue {
+		data, err := json.Marshal(v)
+		require.NoError(t, err)
+		return &mvccpb.KeyValue{Key: []byte(volumePrefix + v.Name), Value: data, ModRevision: rev}
+	}
+	put := func(v *Volinfo, rev int64) *clientv3.Event {
+		return &clientv3.Event{Type: clientv3.EventTypePut, Kv: kv(v, rev)}
+	}
+	del := func(v *Volinfo, rev int64) *clientv3.Event {
+		return &clientv3.Event{
+			Type:   clientv3.EventTypeDelete,
+			Kv:     &mvccpb.KeyValue{Key: []byte(volumePrefix + v.Name), ModRevision: rev},
+			PrevKv: kv(v, rev-1),
+		}
+	}
+
+	gv0 := &Volinfo{Name: "gv0", State: VolCreated}
+	gv0Started := &Volinfo{Name: "gv0", State: VolStarted}
+	gv1 := &Volinfo{Name: "gv1", Namespace: "team-a"}
+	gv2 := &Volinfo{Name: "gv2"}
+
+	changed, deleted := volumeChanges([]*clientv3.Event{
+		put(gv0, 10), put(gv1, 11), put(gv0Started, 12), del(gv1, 13), put(gv2, 14),
+	})
+	require.Len(t, changed, 2)
+	assert.Equal(t, "gv0", changed[0].Name)
+	assert.Equal(t, VolStarted, changed[0].State)
+	assert.Equal(t, "gv2", changed[1].Name)
+	require.Len(t, deleted, 1)
+	assert.Equal(t, "gv1", deleted[0].Name)
+	assert.Equal(t, "team-a", deleted[0].Namespace)
+
+	// A volume deleted then created again is changed
+	changed, deleted = volumeChanges([]*clientv3.Event{del(gv2, 15), put(gv2, 16)})
+	assert.Len(t, changed, 1)
+	assert.Empty(t, deleted)
+
+	// Without its previous value a deleted volume is known by its name
+	ev := del(gv0, 17)
+	ev.PrevKv = nil
+	changed, deleted = volumeChanges([]*clientv3.Event{ev})
+	assert.Empty(t, changed)
+	require.Len(t, deleted, 1)
+	assert.Equal(t, "gv0", deleted[0].Name)
+}
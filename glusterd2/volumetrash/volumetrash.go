@@ -0,0 +1,283 @@
+// Package volumetrash retains the bricks of the deleted volumes for a grace
+// period, during which the volumes can be undeleted, and purges them once
+// the period is over.
+package volumetrash
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/events"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/leader"
+	"github.com/gluster/glusterd2/glusterd2/options"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	transactionv2 "github.com/gluster/glusterd2/glusterd2/transactionv2"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// retentionKey is the cluster option setting the grace period, in
+	// hours. 0 deletes the volumes for good at once.
+	retentionKey     = "cluster.volume-trash-retention"
+	defaultRetention = 24
+
+	// checkInterval is the interval at which the trashed volumes whose
+	// grace period is over are purged, by the peer leading the
+	// LeaderService
+	checkInterval = time.Minute
+
+	// LeaderService is the singleton service purging the trashed volumes
+	LeaderService = "volume-trash"
+)
+
+var (
+	stopChan chan struct{}
+	stopOnce sync.Once
+)
+
+func validateNonNegative(option, value string) error {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return errors.ErrInvalidIntValue
+	}
+	return nil
+}
+
+func init() {
+	options.RegisterClusterOpValidationFunc(retentionKey, validateNonNegative)
+	leader.Register(LeaderService)
+}
+
+// Retention returns the grace period of the deleted volumes
+func Retention() time.Duration {
+	hours := defaultRetention
+	if value, err := options.GetClusterOption(retentionKey); err == nil {
+		if n, err := strconv.Atoi(value); err == nil && n >= 0 {
+			hours = n
+		}
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+// lockID returns the ID of the cluster lock held while purging or undeleting
+// the trashed volume
+func lockID(id uuid.UUID) string {
+	return "trash/" + id.String()
+}
+
+// Purge deletes the trashed volume for good: the bricks provisioned by
+// glusterd2 are removed, and the volume is forgotten
+func Purge(ctx context.Context, id uuid.UUID) error {
+	txn, err := transactionv2.NewTxnWithLocks(ctx, lockID(id))
+	if err != nil {
+		return err
+	}
+	defer txn.Done()
+
+	t, err := volume.GetTrashedVolume(id)
+	if err != nil {
+		return err
+	}
+	volinfo := t.Volinfo
+
+	txn.Steps = append(PurgeSteps(volinfo, t.BrickData), &transaction.Step{
+		DoFunc: "vol-trash.Purge",
+		Nodes:  []uuid.UUID{gdctx.MyUUID},
+		Sync:   true,
+	})
+	if err := txn.Ctx.Set("volinfo", volinfo); err != nil {
+		return err
+	}
+	if err := txn.Ctx.Set("brick-data", t.BrickData); err != nil {
+		return err
+	}
+	if err := txn.Do(); err != nil {
+		return err
+	}
+
+	events.Broadcast(volume.NewEvent(volume.EventVolumePurged, volinfo))
+	return nil
+}
+
+// ValidBrickData tells if brickData is a known way to handle the data of the
+// bricks of a purged volume
+func ValidBrickData(brickData string) bool {
+	switch brickData {
+	case api.BrickDataKeep, api.BrickDataMetadata, api.BrickDataWipe:
+		return true
+	}
+	return false
+}
+
+// PurgeSteps returns the transaction steps removing the bricks of the volume
+// being purged, run before forgetting the volume. The bricks provisioned by
+// glusterd2 are removed with their devices, the data of the other bricks is
+// handled as told by brickData, set under "brick-data" in the transaction
+// context.
+func PurgeSteps(v *volume.Volinfo, brickData string) []*transaction.Step {
+	bricksAutoProvisioned := v.IsAutoProvisioned() || v.IsSnapshotProvisioned()
+	return []*transaction.Step{
+		{
+			DoFunc: "vol-trash.CleanBrickData",
+			Nodes:  v.Nodes(),
+			Skip:   bricksAutoProvisioned || brickData == "" || brickData == api.BrickDataKeep,
+		},
+		{
+			// Registered with the steps of volume delete
+			DoFunc: "vol-delete.CleanBricks",
+			Nodes:  v.Nodes(),
+			Skip:   !bricksAutoProvisioned,
+		},
+	}
+}
+
+// txnCleanBrickData removes the metadata or all the data of the local
+// bricks of the volume. Every brick cleaned is logged and raises an event,
+// as the data cannot be recovered.
+func txnCleanBrickData(c transaction.TxnCtx) error {
+	var (
+		volinfo   volume.Volinfo
+		brickData string
+	)
+	if err := c.Get("volinfo", &volinfo); err != nil {
+		return err
+	}
+	if err := c.Get("brick-data", &brickData); err != nil {
+		return err
+	}
+
+	clean := brick.CleanMetadata
+	if brickData == api.BrickDataWipe {
+		clean = brick.WipeData
+	}
+	for _, b := range volinfo.GetLocalBricks() {
+		logger := c.Logger().WithFields(log.Fields{
+			"volume":     volinfo.Name,
+			"brick":      b.String(),
+			"brick-data": brickData,
+		})
+		if err := clean(b.Path); err != nil {
+			if os.IsNotExist(err) {
+				logger.Warn("brick path not found, nothing to clean")
+				continue
+			}
+			logger.WithError(err).Error("failed to clean brick data")
+			return err
+		}
+		logger.Info("cleaned brick data")
+		events.Broadcast(newBrickCleanedEvent(&b, brickData))
+	}
+	return nil
+}
+
+func newBrickCleanedEvent(b *brick.Brickinfo, brickData string) *api.Event {
+	data := b.StringMap()
+	data["brick.data"] = brickData
+	return events.New(volume.EventBrickDataCleaned, data, true)
+}
+
+func txnPurge(c transaction.TxnCtx) error {
+	var volinfo volume.Volinfo
+	if err := c.Get("volinfo", &volinfo); err != nil {
+		return err
+	}
+	return volume.DeleteTrashedVolume(volinfo.ID)
+}
+
+// Undelete moves the trashed volume back to the volumes of the cluster, not
+// started
+func Undelete(ctx context.Context, id uuid.UUID) (*volume.Volinfo, error) {
+	t, err := volume.GetTrashedVolume(id)
+	if err != nil {
+		return nil, err
+	}
+
+	txn, err := transactionv2.NewTxnWithLocks(ctx, lockID(id), t.Volinfo.Name)
+	if err != nil {
+		return nil, err
+	}
+	defer txn.Done()
+
+	// The volume may have been purged while waiting for the locks
+	if _, err := volume.GetTrashedVolume(id); err != nil {
+		return nil, err
+	}
+	if volume.Exists(t.Volinfo.Name) {
+		return nil, errors.ErrVolExists
+	}
+
+	volinfo, err := volume.UntrashVolume(id)
+	if err != nil {
+		return nil, err
+	}
+
+	events.Broadcast(volume.NewEvent(volume.EventVolumeUndeleted, volinfo))
+	return volinfo, nil
+}
+
+// RegisterStepFuncs registers the step functions of the volume trash
+func RegisterStepFuncs() {
+	transaction.RegisterStepFunc(txnPurge, "vol-trash.Purge")
+	transaction.RegisterStepFunc(txnCleanBrickData, "vol-trash.CleanBrickData")
+}
+
+// Start starts purging the trashed volumes at the end of their grace period
+func Start() {
+	stopChan = make(chan struct{})
+	go transactionv2.UntilStop(reap, checkInterval, stopChan)
+}
+
+// Stop stops purging the trashed volumes
+func Stop() {
+	if stopChan == nil {
+		return
+	}
+	stopOnce.Do(func() {
+		close(stopChan)
+	})
+}
+
+// reap purges the trashed volumes whose grace period is over
+func reap() {
+	if !leader.IsLeader(LeaderService) {
+		return
+	}
+
+	trashed, err := volume.GetTrashedVolumes()
+	if err != nil {
+		log.WithError(err).Error("volumetrash: failed to list trashed volumes")
+		return
+	}
+
+	now := time.Now()
+	for _, t := range trashed {
+		if !t.Expired(now) {
+			continue
+		}
+		logger := log.WithFields(log.Fields{
+			"volume": t.Volinfo.Name,
+			"id":     t.Volinfo.ID.String(),
+		})
+		ctx := gdctx.WithReqLogger(context.Background(), logger)
+		switch err := Purge(ctx, t.Volinfo.ID); err {
+		case nil:
+			logger.Info("purged deleted volume")
+		case errors.ErrTrashedVolNotFound:
+			// Purged by another peer meanwhile
+		default:
+			// Retried at the next check, bricks on peers down cannot
+			// be removed
+			logger.WithError(err).Warn("volumetrash: failed to purge deleted volume")
+		}
+	}
+}
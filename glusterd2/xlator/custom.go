@@ -0,0 +1,85 @@
+package xlator
+
+import (
+	"sync"
+
+	"github.com/gluster/glusterd2/glusterd2/options"
+)
+
+var (
+	customMu sync.RWMutex
+	// customXlMap is a map of the xlators registered at runtime, indexed
+	// by xlator-id
+	customXlMap map[string]*Xlator
+	// customOptMap is a map of the options of the xlators registered at
+	// runtime, indexed by <xlator-id>.<option-key>
+	customOptMap map[string]*options.Option
+)
+
+// SetCustomXlators replaces the set of xlators registered at runtime by
+// plugins or users, which are not shipped with glusterfs. An xlator loaded
+// from the shared objects takes precedence over a registered xlator with the
+// same ID, but the options of the registered xlator are added to it.
+func SetCustomXlators(xls []*Xlator) {
+	xlm := make(map[string]*Xlator, len(xls))
+	optm := make(map[string]*options.Option)
+	for _, xl := range xls {
+		xlm[xl.ID] = xl
+		for _, opt := range xl.Options {
+			for _, k := range opt.Key {
+				optm[xl.ID+"."+k] = opt
+			}
+		}
+	}
+
+	customMu.Lock()
+	defer customMu.Unlock()
+	customXlMap = xlm
+	customOptMap = optm
+}
+
+func findCustom(id string) (*Xlator, bool) {
+	customMu.RLock()
+	defer customMu.RUnlock()
+	xl, ok := customXlMap[id]
+	if !ok {
+		xl, ok = pseudoXlMap[id]
+	}
+	return xl, ok
+}
+
+func findCustomOption(k string) (*options.Option, bool) {
+	customMu.RLock()
+	defer customMu.RUnlock()
+	opt, ok := customOptMap[k]
+	if !ok {
+		opt, ok = pseudoOptMap[k]
+	}
+	return opt, ok
+}
+
+var (
+	// pseudoXlMap is a map of the pseudo xlators registered by plugins,
+	// indexed by xlator-id. Pseudo xlators are never part of a volfile,
+	// they only namespace the volume options consumed by glusterd2 plugins
+	// or external tools, like user.smb.
+	pseudoXlMap = make(map[string]*Xlator)
+	// pseudoOptMap is a map of the options of pseudo xlators, indexed by
+	// <xlator-id>.<option-key>
+	pseudoOptMap = make(map[string]*options.Option)
+)
+
+// RegisterPseudoXlator registers an xlator which exists only to validate and
+// act upon a set of volume options. It is not loaded from a shared object and
+// is never inserted into a volfile. Plugins are expected to call this from
+// their init().
+func RegisterPseudoXlator(xl *Xlator) {
+	customMu.Lock()
+	defer customMu.Unlock()
+	pseudoXlMap[xl.ID] = xl
+	for _, opt := range xl.Options {
+		for _, k := range opt.Key {
+			pseudoOptMap[xl.ID+"."+k] = opt
+		}
+	}
+}
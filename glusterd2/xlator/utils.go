@@ -23,7 +23,9 @@ func Find(id string) (*Xlator, error) {
 
 	xl, ok := xlMap[id]
 	if !ok {
-		return nil, NotFoundError(id)
+		if xl, ok = findCustom(id); !ok {
+			return nil, NotFoundError(id)
+		}
 	}
 	return xl, nil
 }
@@ -50,7 +52,9 @@ func FindOption(k string) (*options.Option, error) {
 
 	opt, ok := optMap[xl+"."+name]
 	if !ok {
-		return nil, OptionNotFoundError(k)
+		if opt, ok = findCustomOption(xl + "." + name); !ok {
+			return nil, OptionNotFoundError(k)
+		}
 	}
 	return opt, nil
 }
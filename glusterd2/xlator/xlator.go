@@ -22,7 +22,11 @@ type Xlator struct {
 	Category string
 }
 
-// FullName returns xlator name including the category name
+// FullName returns xlator name including the category name. Pseudo xlators
+// have no category and are known only by their ID.
 func (xl *Xlator) FullName() string {
+	if xl.Category == "" {
+		return xl.ID
+	}
 	return xl.Category + "/" + xl.ID
 }
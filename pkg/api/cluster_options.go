@@ -1,8 +1,17 @@
 package api
 
-// ClusterOptionReq represents an incoming request to set cluster level options
+import (
+	"time"
+
+	"github.com/pborman/uuid"
+)
+
+// ClusterOptionReq represents an incoming request to set cluster level
+// options. Volume options are accepted as default volume options, applying
+// to the volumes which do not set them.
 type ClusterOptionReq struct {
 	Options map[string]string `json:"options"`
+	VolOptionFlags
 }
 
 // ClusterOptionsResp contains details for global options
@@ -12,3 +21,42 @@ type ClusterOptionsResp struct {
 	DefaultValue string `json:"default"`
 	Modified     bool   `json:"modified"`
 }
+
+// PeerOpVersion is the version of glusterd2 a peer runs and the highest
+// op-version it supports
+type PeerOpVersion struct {
+	ID           uuid.UUID `json:"id"`
+	Name         string    `json:"name"`
+	Version      string    `json:"version"`
+	MaxOpVersion int       `json:"max-op-version"`
+}
+
+// OpVersionResp is the response sent for a request to get the op-version of
+// the cluster. MaxOpVersion is the highest op-version supported by all the
+// peers, which the cluster op-version can be raised to.
+type OpVersionResp struct {
+	OpVersion    int             `json:"op-version"`
+	MaxOpVersion int             `json:"max-op-version"`
+	Peers        []PeerOpVersion `json:"peers"`
+}
+
+// OpVersionBumpReq represents a request to raise the cluster op-version. The
+// op-version is raised to the highest one supported by all the peers if
+// OpVersion is not set.
+type OpVersionBumpReq struct {
+	OpVersion int `json:"op-version,omitempty"`
+}
+
+// QuorumStatus is server-quorum as seen by the peer serving the request
+type QuorumStatus struct {
+	Enabled bool `json:"enabled"`
+	// Ratio is the percentage of the peers required to be up
+	Ratio int  `json:"ratio"`
+	Met   bool `json:"met"`
+	Up    int  `json:"up"`
+	Total int  `json:"total"`
+	// Since is when quorum was last lost or regained
+	Since time.Time `json:"since"`
+	// StoppedBricks are the local bricks stopped on losing quorum
+	StoppedBricks []string `json:"stopped-bricks,omitempty"`
+}
@@ -0,0 +1,51 @@
+package api
+
+import (
+	"time"
+)
+
+// ConfigSnapshot is a snapshot of the configuration of the cluster
+type ConfigSnapshot struct {
+	ID        int64     `json:"id"`
+	CreatedAt time.Time `json:"created-at"`
+	// Trigger is scheduled or manual
+	Trigger string   `json:"trigger"`
+	Volumes []string `json:"volumes"`
+}
+
+// ConfigSnapshotListResp is the response to listing the configuration
+// snapshots, oldest first
+type ConfigSnapshotListResp []ConfigSnapshot
+
+// ConfigSnapshotResp is a configuration snapshot with its content
+type ConfigSnapshotResp struct {
+	ConfigSnapshot
+	// Config are the values of the store keys of the configuration
+	Config map[string]string `json:"config"`
+}
+
+// ConfigChange is a change between two configurations
+type ConfigChange struct {
+	// Path is the store key, followed by the path to the changed value
+	// if the key holds a JSON document
+	Path string `json:"path"`
+	// Change is added, removed or modified
+	Change string `json:"change"`
+	Old    string `json:"old,omitempty"`
+	New    string `json:"new,omitempty"`
+}
+
+// ConfigDiffResp is the response to comparing a configuration snapshot with
+// another one, or with the current configuration
+type ConfigDiffResp struct {
+	From int64 `json:"from"`
+	// To is 0 for the current configuration
+	To      int64          `json:"to"`
+	Changes []ConfigChange `json:"changes"`
+}
+
+// VolumeConfigRestoreReq represents a request to restore the options of a
+// volume from a configuration snapshot
+type VolumeConfigRestoreReq struct {
+	Snapshot int64 `json:"snapshot"`
+}
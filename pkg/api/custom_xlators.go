@@ -0,0 +1,37 @@
+package api
+
+// CustomXlatorOption is an option of a custom xlator. Type is one of any,
+// str, int, size, percent, bool, path, time or double.
+type CustomXlatorOption struct {
+	Key          string   `json:"key"`
+	Type         string   `json:"type"`
+	DefaultValue string   `json:"default-value,omitempty"`
+	ValidValues  []string `json:"valid-values,omitempty"`
+	Description  string   `json:"description,omitempty"`
+}
+
+// CustomXlator is an xlator which is not shipped with glusterfs, inserted
+// into the graphs of the given templates of the volumes enabling it.
+//
+// After and Before are the types of the xlators it is placed below and
+// above in the graphs, and Requires are the types of the xlators which must
+// be enabled in the graphs along with it. Plugin is set for the xlators
+// registered by plugins, which cannot be changed through the REST API.
+type CustomXlator struct {
+	Type      string               `json:"type"`
+	Templates []string             `json:"templates"`
+	After     []string             `json:"after,omitempty"`
+	Before    []string             `json:"before,omitempty"`
+	Requires  []string             `json:"requires,omitempty"`
+	Options   []CustomXlatorOption `json:"options,omitempty"`
+	Plugin    string               `json:"plugin,omitempty"`
+}
+
+// CustomXlatorReq represents a request to register a custom xlator.
+type CustomXlatorReq CustomXlator
+
+// CustomXlatorResp is the response sent for a custom xlator request.
+type CustomXlatorResp CustomXlator
+
+// CustomXlatorListResp is the list of custom xlators.
+type CustomXlatorListResp []CustomXlator
@@ -0,0 +1,81 @@
+package api
+
+import (
+	"time"
+
+	"github.com/pborman/uuid"
+)
+
+// StatedumpPortMapEntry is a brick in the port map of a peer
+type StatedumpPortMapEntry struct {
+	Port      int    `json:"port"`
+	BrickPath string `json:"brick-path"`
+	PID       int    `json:"pid"`
+}
+
+// StatedumpCaches is the state kept in memory by a peer
+type StatedumpCaches struct {
+	PortMap       []StatedumpPortMapEntry `json:"port-map"`
+	CustomXlators []string                `json:"custom-xlators"`
+	Plugins       []PluginInfo            `json:"plugins"`
+	StepFuncs     []string                `json:"step-functions"`
+	Quorum        QuorumStatus            `json:"quorum"`
+}
+
+// StatedumpTxnState is the state of a transaction on a peer
+type StatedumpTxnState struct {
+	State    string `json:"state"`
+	Reason   string `json:"reason,omitempty"`
+	LastStep int    `json:"last-step"`
+}
+
+// StatedumpTxn is a transaction pending in the store, along with its state
+// on the peer when the peer is one of its nodes
+type StatedumpTxn struct {
+	ID        uuid.UUID          `json:"id"`
+	ReqID     uuid.UUID          `json:"req-id"`
+	StartTime time.Time          `json:"start-time"`
+	Nodes     []uuid.UUID        `json:"nodes"`
+	Steps     []string           `json:"steps"`
+	Local     *StatedumpTxnState `json:"local,omitempty"`
+}
+
+// SupervisorNode is a service in the supervisor tree of a peer, along with
+// the services it supervises when it is a supervisor
+type SupervisorNode struct {
+	Name     string           `json:"name"`
+	Children []SupervisorNode `json:"children,omitempty"`
+}
+
+// StatedumpDaemon is a daemon managed by a peer
+type StatedumpDaemon struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Running bool   `json:"running"`
+	PID     int    `json:"pid,omitempty"`
+}
+
+// StoreWatch is the health of a watch of a peer on the store
+type StoreWatch struct {
+	Name      string    `json:"name"`
+	Key       string    `json:"key"`
+	Started   time.Time `json:"started"`
+	Events    int       `json:"events"`
+	LastEvent time.Time `json:"last-event,omitempty"`
+	Ended     time.Time `json:"ended,omitempty"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// DaemonStatedumpResp is the response sent for a statedump request of
+// glusterd2 itself, with the internal state of the peer
+type DaemonStatedumpResp struct {
+	PeerID         uuid.UUID         `json:"peer-id"`
+	Time           time.Time         `json:"time"`
+	Goroutines     int               `json:"goroutines"`
+	Caches         StatedumpCaches   `json:"caches"`
+	Transactions   []StatedumpTxn    `json:"transactions"`
+	SunRPCClients  []SunRPCClient    `json:"sunrpc-clients"`
+	SupervisorTree *SupervisorNode   `json:"supervisor-tree,omitempty"`
+	Daemons        []StatedumpDaemon `json:"daemons"`
+	StoreWatches   []StoreWatch      `json:"store-watches"`
+}
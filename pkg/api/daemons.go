@@ -0,0 +1,31 @@
+package api
+
+import (
+	"time"
+)
+
+// DaemonStatus is the supervision state of a daemon managed by a peer
+type DaemonStatus struct {
+	ID                  string     `json:"id"`
+	State               string     `json:"state"`
+	PID                 int        `json:"pid,omitempty"`
+	Restarts            int        `json:"restarts"`
+	LastRestart         *time.Time `json:"last-restart,omitempty"`
+	LastError           string     `json:"last-error,omitempty"`
+	HealthCheckFailures int        `json:"health-check-failures"`
+}
+
+// DaemonKind is a kind of daemons managed by a peer, with the policy they are
+// supervised with
+type DaemonKind struct {
+	Name          string         `json:"name"`
+	DependsOn     []string       `json:"depends-on"`
+	RestartPolicy string         `json:"restart-policy"`
+	MaxRestarts   int            `json:"max-restarts"`
+	HealthChecked bool           `json:"health-checked"`
+	Daemons       []DaemonStatus `json:"daemons"`
+}
+
+// DaemonListResp is the response sent for a request to list the daemons
+// managed by a peer, by kind in the order of their dependencies
+type DaemonListResp []DaemonKind
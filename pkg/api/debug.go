@@ -0,0 +1,61 @@
+package api
+
+import "time"
+
+// DebugSettings represents the state of the /debug endpoints of a node.
+type DebugSettings struct {
+	Enabled bool `json:"enabled"`
+}
+
+// SunRPCClient is the state of a client connected to the SunRPC server of a
+// node, along with the results of the volfile change notifications sent to
+// it.
+type SunRPCClient struct {
+	Address         string    `json:"address"`
+	ConnectedAt     time.Time `json:"connected-at"`
+	Pid             int       `json:"pid,omitempty"`
+	OpVersion       int       `json:"op-version,omitempty"`
+	MinOpVersion    int       `json:"min-op-version,omitempty"`
+	Volfiles        []string  `json:"volfiles,omitempty"`
+	Notified        int       `json:"notified"`
+	NotifyFailed    int       `json:"notify-failed"`
+	LastNotified    time.Time `json:"last-notified,omitempty"`
+	LastNotifyError string    `json:"last-notify-error,omitempty"`
+}
+
+// SunRPCClientsResp is the response sent for a SunRPC clients request.
+type SunRPCClientsResp []SunRPCClient
+
+// FaultReq is the request to inject a fault on a node.
+type FaultReq struct {
+	// Point is where the fault is injected: step, store-write or rpc
+	Point string `json:"point"`
+	// Action is what the fault does: fail, delay, or drop for the store
+	// writes
+	Action string `json:"action"`
+	// Step is the name of the step function a step fault is injected in
+	Step string `json:"step,omitempty"`
+	// Peer is the ID of the peer the RPCs to which an rpc fault is
+	// injected in
+	Peer string `json:"peer,omitempty"`
+	// Key is the prefix of the keys the writes to which a store-write
+	// fault is injected in
+	Key string `json:"key,omitempty"`
+	// DelayMs is the time a delay fault delays by, in milliseconds
+	DelayMs int64 `json:"delay-ms,omitempty"`
+	// After makes a step fail once it has run, for its changes to be
+	// undone
+	After bool `json:"after,omitempty"`
+	// Count is the number of times the fault is injected, 0 for every time
+	Count int `json:"count,omitempty"`
+}
+
+// Fault is a fault injected on a node.
+type Fault struct {
+	FaultReq
+	ID       string `json:"id"`
+	Injected int    `json:"injected"`
+}
+
+// FaultListResp is the response sent for a fault list request.
+type FaultListResp []Fault
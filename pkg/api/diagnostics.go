@@ -0,0 +1,71 @@
+package api
+
+import (
+	"github.com/pborman/uuid"
+)
+
+// Status of a diagnostic check, which is the most severe of its findings
+const (
+	DiagnosticOK      = "ok"
+	DiagnosticWarning = "warning"
+	DiagnosticError   = "error"
+)
+
+// Names of the diagnostic checks run on the cluster
+const (
+	DiagnosticPeers           = "peers"
+	DiagnosticClockSkew       = "clock-skew"
+	DiagnosticStoreQuorum     = "store-quorum"
+	DiagnosticOrphanedBricks  = "orphaned-brick-processes"
+	DiagnosticStalePmap       = "stale-pmap-entries"
+	DiagnosticVolfileChecksum = "volfile-checksums"
+	DiagnosticPendingHeals    = "pending-heals"
+)
+
+// DiagnosticFinding is a problem found by a diagnostic check, along with the
+// action suggested to fix it.
+type DiagnosticFinding struct {
+	Severity string    `json:"severity"`
+	PeerID   uuid.UUID `json:"peer-id,omitempty"`
+	Volume   string    `json:"volume,omitempty"`
+	Message  string    `json:"message"`
+	Action   string    `json:"action,omitempty"`
+}
+
+// DiagnosticCheck is the result of a diagnostic check.
+type DiagnosticCheck struct {
+	Name     string              `json:"name"`
+	Status   string              `json:"status"`
+	Findings []DiagnosticFinding `json:"findings,omitempty"`
+}
+
+// StoreLatency contains the latency percentiles, in milliseconds, of the
+// recent store operations of a kind.
+type StoreLatency struct {
+	Count int     `json:"count"`
+	P50   float64 `json:"p50-ms"`
+	P90   float64 `json:"p90-ms"`
+	P99   float64 `json:"p99-ms"`
+}
+
+// PeerDiagnostics is the state of the glusterd2 process of a peer, taken
+// while the diagnostic checks were run.
+type PeerDiagnostics struct {
+	PeerID         uuid.UUID               `json:"peer-id"`
+	Name           string                  `json:"name"`
+	Goroutines     int                     `json:"goroutines"`
+	SunRPCClients  int                     `json:"sunrpc-clients"`
+	PendingTxns    int                     `json:"pending-transactions"`
+	TxnsInProgress int64                   `json:"transactions-in-progress"`
+	StoreLatency   map[string]StoreLatency `json:"store-latency"`
+	HeapAllocBytes uint64                  `json:"heap-alloc-bytes"`
+	NumGC          uint32                  `json:"num-gc"`
+}
+
+// DiagnosticsResp is the response sent for a diagnostics request. Status is
+// the most severe of the statuses of the checks.
+type DiagnosticsResp struct {
+	Status string            `json:"status"`
+	Checks []DiagnosticCheck `json:"checks"`
+	Peers  []PeerDiagnostics `json:"peers"`
+}
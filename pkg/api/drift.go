@@ -0,0 +1,52 @@
+package api
+
+import (
+	"time"
+
+	"github.com/pborman/uuid"
+)
+
+// Kinds of drift of the state of a volume on a peer from the store
+const (
+	// DriftBrickVolfileStale is a brick volfile saved on the peer which
+	// differs from the volfile generated from the store
+	DriftBrickVolfileStale = "brick-volfile-stale"
+	// DriftVolfileNotLoaded is a brick or client which was last served an
+	// older version of its volfile
+	DriftVolfileNotLoaded = "volfile-not-loaded"
+	// DriftBrickNotRunning is a brick of a started volume which is not
+	// signed in to the peer
+	DriftBrickNotRunning = "brick-not-running"
+	// DriftCheckFailed is a volume, or a brick of it, which could not be
+	// checked, as its volfiles could not be generated from the store
+	DriftCheckFailed = "check-failed"
+)
+
+// Drift is a difference between the effective state of a volume on a peer
+// and the state of the volume in the store
+type Drift struct {
+	PeerID    uuid.UUID `json:"peer-id"`
+	Volume    string    `json:"volume"`
+	Kind      string    `json:"kind"`
+	Brick     string    `json:"brick,omitempty"`
+	VolfileID string    `json:"volfile-id,omitempty"`
+	// Client and PID are the address and the PID of the process which was
+	// served an older volfile
+	Client string `json:"client,omitempty"`
+	PID    int    `json:"pid,omitempty"`
+	// Expected is the checksum of the volfile generated from the store,
+	// and Actual the checksum of the volfile saved or served
+	Expected string `json:"expected-checksum,omitempty"`
+	Actual   string `json:"actual-checksum,omitempty"`
+	// Error is why the volume could not be checked
+	Error string `json:"error,omitempty"`
+	// Since is when the drift was first found
+	Since time.Time `json:"since"`
+}
+
+// DriftListResp is the response to listing the drifts detected on the peers
+type DriftListResp []Drift
+
+// DriftReconcileResp is the response to a reconciliation of the volumes,
+// with the drifts corrected
+type DriftReconcileResp []Drift
@@ -8,6 +8,7 @@ type Endpoint struct {
 	Path         string `json:"path"`
 	RequestType  string `json:"request-type"`
 	ResponseType string `json:"response-type"`
+	PeerLocal    bool   `json:"peer-local,omitempty"`
 }
 
 // ListEndpointsResp is the response sent to client for a list endpoints request.
@@ -3,9 +3,15 @@ package api
 // HTTPError contains an error code and corresponding text which briefly
 // describes the error in short.
 type HTTPError struct {
-	Code    int               `json:"code"`
-	Message string            `json:"message"`
-	Fields  map[string]string `json:"fields,omitempty"`
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	// Reason is the machine-readable reason of the error, which does not
+	// change between releases
+	Reason ErrorReason `json:"reason,omitempty"`
+	// Fields gives the details of the error. The errors of transaction
+	// steps have the step, the ID of the peer it failed on as peer-id,
+	// and the error returned by the peer.
+	Fields map[string]string `json:"fields,omitempty"`
 }
 
 // ErrorResp is an error response which may contain one or more error responses
@@ -29,6 +35,69 @@ var ErrorCodeMap = map[ErrorCode]string{
 	ErrTxnStepFailed: "a txn step failed",
 }
 
+// ErrorReason is the machine-readable reason of an API error, for clients to
+// tell errors apart without parsing their messages. The reasons are never
+// renamed, new ones may be added.
+type ErrorReason string
+
+// Reasons of the errors of any endpoint, given when no more specific reason
+// applies
+const (
+	ReasonInternal       ErrorReason = "INTERNAL"
+	ReasonInvalidRequest ErrorReason = "INVALID_REQUEST"
+	ReasonUnauthorized   ErrorReason = "UNAUTHORIZED"
+	ReasonForbidden      ErrorReason = "FORBIDDEN"
+	ReasonNotFound       ErrorReason = "NOT_FOUND"
+	ReasonConflict       ErrorReason = "CONFLICT"
+	ReasonUnavailable    ErrorReason = "UNAVAILABLE"
+	// ReasonTxnStepFailed is the reason of a transaction step failing on
+	// a peer for a reason not listed here
+	ReasonTxnStepFailed ErrorReason = "TXN_STEP_FAILED"
+)
+
+// Reasons of specific errors
+const (
+	ReasonVolumeNotFound        ErrorReason = "VOLUME_NOT_FOUND"
+	ReasonVolumeExists          ErrorReason = "VOLUME_EXISTS"
+	ReasonVolumeNotStarted      ErrorReason = "VOLUME_NOT_STARTED"
+	ReasonVolumeAlreadyStarted  ErrorReason = "VOLUME_ALREADY_STARTED"
+	ReasonVolumeAlreadyStopped  ErrorReason = "VOLUME_ALREADY_STOPPED"
+	ReasonVolumeInUse           ErrorReason = "VOLUME_IN_USE"
+	ReasonInvalidVolumeName     ErrorReason = "INVALID_VOLUME_NAME"
+	ReasonVolumeWORM            ErrorReason = "VOLUME_WORM"
+	ReasonPeerNotFound          ErrorReason = "PEER_NOT_FOUND"
+	ReasonPeerUnreachable       ErrorReason = "PEER_UNREACHABLE"
+	ReasonSnapshotNotFound      ErrorReason = "SNAPSHOT_NOT_FOUND"
+	ReasonSnapshotExists        ErrorReason = "SNAPSHOT_EXISTS"
+	ReasonBrickPathInUse        ErrorReason = "BRICK_PATH_IN_USE"
+	ReasonInvalidBrickPath      ErrorReason = "INVALID_BRICK_PATH"
+	ReasonBrickOnRootFS         ErrorReason = "BRICK_ON_ROOT_FS"
+	ReasonBrickPathWasUsed      ErrorReason = "BRICK_PATH_WAS_USED"
+	ReasonBrickPathNested       ErrorReason = "BRICK_PATH_NESTED"
+	ReasonBrickVolumeIDMismatch ErrorReason = "BRICK_VOLUME_ID_MISMATCH"
+	ReasonDeviceNotFound        ErrorReason = "DEVICE_NOT_FOUND"
+	ReasonInsufficientSpace     ErrorReason = "INSUFFICIENT_SPACE"
+	ReasonQuorumLost            ErrorReason = "QUORUM_LOST"
+	ReasonLockTimeout           ErrorReason = "LOCK_TIMEOUT"
+	ReasonLockNotFound          ErrorReason = "LOCK_NOT_FOUND"
+	ReasonOpVersionTooLow       ErrorReason = "OP_VERSION_TOO_LOW"
+	ReasonPluginNotFound        ErrorReason = "PLUGIN_NOT_FOUND"
+	ReasonPluginDisabled        ErrorReason = "PLUGIN_DISABLED"
+	ReasonUpgradeInProgress     ErrorReason = "UPGRADE_IN_PROGRESS"
+	ReasonEvacuationInProgress  ErrorReason = "EVACUATION_IN_PROGRESS"
+	ReasonProcessNotRunning     ErrorReason = "PROCESS_NOT_RUNNING"
+	ReasonProcessAlreadyRunning ErrorReason = "PROCESS_ALREADY_RUNNING"
+	ReasonOptionGroupNotFound   ErrorReason = "OPTION_GROUP_NOT_FOUND"
+	ReasonNamespaceNotFound     ErrorReason = "NAMESPACE_NOT_FOUND"
+	ReasonNamespaceExists       ErrorReason = "NAMESPACE_EXISTS"
+	ReasonNamespaceNotEmpty     ErrorReason = "NAMESPACE_NOT_EMPTY"
+	ReasonQuotaExceeded         ErrorReason = "QUOTA_EXCEEDED"
+	ReasonRevisionMismatch      ErrorReason = "REVISION_MISMATCH"
+	ReasonIdempotencyKeyInUse   ErrorReason = "IDEMPOTENCY_KEY_IN_USE"
+	ReasonIdempotencyKeyReused  ErrorReason = "IDEMPOTENCY_KEY_REUSED"
+	ReasonRevisionCompacted     ErrorReason = "REVISION_COMPACTED"
+)
+
 // ErrorResponse is an interface that types can implement on custom errors.
 type ErrorResponse interface {
 	error
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"time"
+
+	"github.com/pborman/uuid"
+)
+
+// States of the evacuation of a peer
+const (
+	// EvacuationPlanned is the state of the plan returned by a dry run
+	EvacuationPlanned   = "planned"
+	EvacuationRunning   = "running"
+	EvacuationCompleted = "completed"
+	EvacuationFailed    = "failed"
+)
+
+// States of a brick being moved off the evacuated peer
+const (
+	BrickMovePending = "pending"
+	BrickMoveRunning = "running"
+	BrickMoveDone    = "done"
+	BrickMoveFailed  = "failed"
+	// BrickMoveSkipped is the state of the bricks which cannot be moved,
+	// and are left on the peer
+	BrickMoveSkipped = "skipped"
+)
+
+// PeerEvacuateReq represents a request to move all the bricks off a peer,
+// replacing them with bricks provisioned on the devices of other peers
+type PeerEvacuateReq struct {
+	// DryRun returns the plan without moving any brick
+	DryRun       bool     `json:"dry-run,omitempty"`
+	LimitPeers   []string `json:"limit-peers,omitempty"`
+	LimitZones   []string `json:"limit-zones,omitempty"`
+	ExcludePeers []string `json:"exclude-peers,omitempty"`
+	ExcludeZones []string `json:"exclude-zones,omitempty"`
+	// SubvolZonesOverlap allows a brick to be moved to a zone used by
+	// other subvolumes of the volume
+	SubvolZonesOverlap bool `json:"subvolume-zones-overlap,omitempty"`
+	// Force moves the bricks of the volumes without redundancy too, whose
+	// data is not healed onto the new bricks
+	Force bool `json:"force,omitempty"`
+}
+
+// BrickMove is the move of a brick off the evacuated peer
+type BrickMove struct {
+	Volume  string `json:"volume"`
+	Subvol  string `json:"subvol"`
+	SrcPath string `json:"src-path"`
+	// Size is the size of the new brick
+	Size      uint64    `json:"size,omitempty"`
+	DstPeerID uuid.UUID `json:"dst-peer-id,omitempty"`
+	DstDevice string    `json:"dst-device,omitempty"`
+	State     string    `json:"state"`
+	// Message tells why the brick is skipped or failed to move
+	Message string `json:"message,omitempty"`
+}
+
+// PeerEvacuateStatus is the plan and the progress of the evacuation of a
+// peer. The bricks are moved one by one, in the order they are listed in.
+type PeerEvacuateStatus struct {
+	PeerID uuid.UUID `json:"peer-id"`
+	State  string    `json:"state"`
+	// DriverID is the ID of the peer moving the bricks
+	DriverID  uuid.UUID   `json:"driver-id,omitempty"`
+	Total     int         `json:"total"`
+	Done      int         `json:"done"`
+	Failed    int         `json:"failed"`
+	StartedAt time.Time   `json:"started-at"`
+	UpdatedAt time.Time   `json:"updated-at"`
+	Moves     []BrickMove `json:"moves"`
+}
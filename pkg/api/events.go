@@ -6,12 +6,26 @@ import (
 	"github.com/pborman/uuid"
 )
 
+// EventSeverity is the severity of an event
+type EventSeverity string
+
+const (
+	// SeverityInfo is used for events which need no action
+	SeverityInfo EventSeverity = "info"
+	// SeverityWarning is used for events which may need attention
+	SeverityWarning EventSeverity = "warning"
+	// SeverityCritical is used for events which need immediate attention
+	SeverityCritical EventSeverity = "critical"
+)
+
 // Event represents an event in GD2
 type Event struct {
 	// ID is a unique event ID
 	ID uuid.UUID `json:"id"`
 	// Name is the the name of the event
 	Name string `json:"name"`
+	// Severity is the severity of the event
+	Severity EventSeverity `json:"severity"`
 	// Data is any additional data attached to the event.
 	Data map[string]string `json:"data,omitempty"`
 	// global should be set to true to broadcast event to the full GD2 cluster.
@@ -0,0 +1,43 @@
+package api
+
+import (
+	"github.com/pborman/uuid"
+)
+
+// HaloBrick is a brick of a replica set as seen from a zone
+type HaloBrick struct {
+	ID     uuid.UUID `json:"id"`
+	PeerID uuid.UUID `json:"peer-id"`
+	Path   string    `json:"path"`
+	// LatencyMs is the round-trip latency from the zone to the peer of the
+	// brick, in milliseconds, or -1 if not measured
+	LatencyMs float64 `json:"latency-ms"`
+	// Local tells if the latency is within halo-max-latency
+	Local bool `json:"local"`
+	// Active tells if halo replication writes to the brick from the zone
+	Active bool `json:"active"`
+}
+
+// HaloSubvol is a replica set as seen from a zone
+type HaloSubvol struct {
+	Name   string      `json:"name"`
+	Bricks []HaloBrick `json:"bricks"`
+}
+
+// HaloZone is a zone of peers, and the bricks of the volume which are local
+// to the clients in the zone
+type HaloZone struct {
+	Zone    string       `json:"zone"`
+	Peers   []uuid.UUID  `json:"peers"`
+	Subvols []HaloSubvol `json:"subvols"`
+}
+
+// VolumeHaloResp is the response sent for a request to report the halo
+// replication of a volume
+type VolumeHaloResp struct {
+	Enabled      bool       `json:"enabled"`
+	MaxLatencyMs int        `json:"max-latency-ms"`
+	MinReplicas  int        `json:"min-replicas"`
+	MaxReplicas  int        `json:"max-replicas"`
+	Zones        []HaloZone `json:"zones"`
+}
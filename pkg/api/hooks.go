@@ -0,0 +1,87 @@
+package api
+
+import (
+	"time"
+
+	"github.com/pborman/uuid"
+)
+
+// HookPoint is a point of the lifecycle of a volume hooks are bound to
+type HookPoint string
+
+// Points of the lifecycle of a volume hooks can be bound to
+const (
+	HookPostVolumeStart HookPoint = "post-volume-start"
+	HookPreVolumeStop   HookPoint = "pre-volume-stop"
+	HookPostAddBrick    HookPoint = "post-add-brick"
+)
+
+// Hook is a script or a webhook run at a point of the lifecycle of the
+// volumes
+type Hook struct {
+	Name  string    `json:"name"`
+	Point HookPoint `json:"point"`
+	// Script is the absolute path of the script run, on the peer serving
+	// the request
+	Script string `json:"script,omitempty"`
+	// URL is the URL of the webhook posted to
+	URL string `json:"url,omitempty"`
+	// Timeout is the time given to the hook to run, in seconds
+	Timeout int  `json:"timeout"`
+	Enabled bool `json:"enabled"`
+}
+
+// HookCreateReq represents a request to register a hook. Either Script or
+// URL is to be set.
+type HookCreateReq struct {
+	Name   string    `json:"name"`
+	Point  HookPoint `json:"point"`
+	Script string    `json:"script,omitempty"`
+	URL    string    `json:"url,omitempty"`
+	// Timeout is the time given to the hook to run, in seconds, 30 if not
+	// set
+	Timeout  int  `json:"timeout,omitempty"`
+	Disabled bool `json:"disabled,omitempty"`
+}
+
+// HookPayload is the JSON document a hook is run with, given on the
+// standard input of scripts and posted to webhooks
+type HookPayload struct {
+	Point  HookPoint   `json:"point"`
+	Hook   string      `json:"hook"`
+	PeerID uuid.UUID   `json:"peer-id"`
+	Time   time.Time   `json:"time"`
+	Volume *VolumeInfo `json:"volume"`
+	// Bricks are the bricks added, for post-add-brick hooks
+	Bricks []BrickInfo `json:"bricks,omitempty"`
+}
+
+// HookRun is a run of a hook
+type HookRun struct {
+	Hook       string    `json:"hook"`
+	Point      HookPoint `json:"point"`
+	Volume     string    `json:"volume"`
+	PeerID     uuid.UUID `json:"peer-id"`
+	Started    time.Time `json:"started"`
+	DurationMs int64     `json:"duration-ms"`
+	Success    bool      `json:"success"`
+	// ExitCode is the exit code of a script
+	ExitCode int `json:"exit-code,omitempty"`
+	// HTTPStatus is the status of the response of a webhook
+	HTTPStatus int `json:"http-status,omitempty"`
+	// Output is the beginning of the output of a script, or of the
+	// response of a webhook
+	Output string `json:"output,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// HookResp is the response sent for a request to get, register, enable or
+// disable a hook
+type HookResp Hook
+
+// HookListResp is the response sent for a request to list the hooks
+type HookListResp []Hook
+
+// HookHistoryResp is the response sent for a request to get the runs of a
+// hook, the latest first
+type HookHistoryResp []HookRun
@@ -0,0 +1,18 @@
+package api
+
+const (
+	// IdempotencyKeyHeader is the header of the requests changing the
+	// cluster which may be retried. A request is run once for a key, and
+	// the retries with the same key are answered with the response it got.
+	IdempotencyKeyHeader = "Idempotency-Key"
+
+	// IdempotentReplayedHeader is set to true in the responses to the
+	// retries of a request, which were not run again
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	// OperationIDHeader is the ID of the operation a request with an
+	// Idempotency-Key ran, the same in the responses to its retries. The
+	// IDs are revisions of the store, which grow with each operation across
+	// the cluster, whatever the clocks of the peers.
+	OperationIDHeader = "X-Gluster-Operation-ID"
+)
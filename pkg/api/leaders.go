@@ -0,0 +1,13 @@
+package api
+
+// ServiceLeader is the peer leading a singleton service of the cluster. The
+// peer is empty while no peer leads the service.
+type ServiceLeader struct {
+	Service  string `json:"service"`
+	PeerID   string `json:"peer-id,omitempty"`
+	PeerName string `json:"peer-name,omitempty"`
+}
+
+// ListLeadersResp is the response sent for a request listing the leaders of
+// the singleton services
+type ListLeadersResp []ServiceLeader
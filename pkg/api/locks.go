@@ -0,0 +1,29 @@
+package api
+
+import (
+	"time"
+
+	"github.com/pborman/uuid"
+)
+
+// LockInfo is a cluster wide lock held on a volume or another resource of
+// the cluster
+type LockInfo struct {
+	ID string `json:"id"`
+	// PeerID is the peer holding the lock
+	PeerID uuid.UUID `json:"peer-id,omitempty"`
+	// TxnID and ReqID are the transaction holding the lock and the request
+	// it runs for
+	TxnID uuid.UUID `json:"txn-id,omitempty"`
+	ReqID uuid.UUID `json:"req-id,omitempty"`
+	// Lease is the etcd lease the lock is held with, in hex
+	Lease string    `json:"lease"`
+	Since time.Time `json:"since,omitempty"`
+	// Age is the number of seconds the lock has been held for, if known
+	Age int64 `json:"age,omitempty"`
+	// Waiters is the number of transactions waiting for the lock
+	Waiters int `json:"waiters"`
+}
+
+// LockListResp is the response to listing the cluster wide locks held
+type LockListResp []LockInfo
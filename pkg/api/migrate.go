@@ -0,0 +1,46 @@
+package api
+
+import (
+	"github.com/pborman/uuid"
+)
+
+// DefaultGD1WorkDir is the working directory of glusterd1 the volumes and
+// snapshots are imported from by default
+const DefaultGD1WorkDir = "/var/lib/glusterd"
+
+// GD1ImportReq represents a request to import the volumes and snapshots of
+// a glusterd1 installation
+type GD1ImportReq struct {
+	// Path is the working directory of glusterd1 on this peer
+	Path string `json:"path,omitempty"`
+	// Volumes restricts the import to the given volumes, all the volumes
+	// being imported if empty
+	Volumes []string `json:"volumes,omitempty"`
+	// DryRun only reports what would be imported
+	DryRun bool `json:"dry-run,omitempty"`
+}
+
+// GD1ImportedVolume is a volume imported from glusterd1
+type GD1ImportedVolume struct {
+	Name string    `json:"name"`
+	ID   uuid.UUID `json:"id"`
+	Type string    `json:"type"`
+	// WasStarted tells if the volume was started under glusterd1. Volumes
+	// are imported stopped and are to be started with glusterd2 once the
+	// glusterd1 daemons are stopped.
+	WasStarted bool     `json:"was-started"`
+	Snapshots  []string `json:"snapshots,omitempty"`
+	// SkippedOptions are the options of the volume which have no
+	// glusterd2 equivalent and were not imported
+	SkippedOptions []string `json:"skipped-options,omitempty"`
+}
+
+// GD1ImportResp is the response of an import of glusterd1 volumes
+type GD1ImportResp struct {
+	DryRun  bool                `json:"dry-run"`
+	Volumes []GD1ImportedVolume `json:"volumes"`
+	// Peers maps the UUIDs of the glusterd1 peers to the ones of the
+	// glusterd2 peers hosting their bricks
+	Peers    map[string]uuid.UUID `json:"peers"`
+	Warnings []string             `json:"warnings,omitempty"`
+}
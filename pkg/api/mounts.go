@@ -0,0 +1,46 @@
+package api
+
+import (
+	"time"
+
+	"github.com/pborman/uuid"
+)
+
+// Mount is a FUSE mount of a volume managed by glusterd2 on a peer
+type Mount struct {
+	ID     uuid.UUID `json:"id"`
+	PeerID uuid.UUID `json:"peer-id"`
+	Volume string    `json:"volume"`
+	// Path is the absolute path of the mount point on the peer
+	Path string `json:"path"`
+	// Options are the mount options, an empty value for the options
+	// without value like ro
+	Options map[string]string `json:"options,omitempty"`
+	// Owner tells who the mount is for, like snapd, quota, georep or
+	// admin
+	Owner   string    `json:"owner,omitempty"`
+	Created time.Time `json:"created"`
+}
+
+// MountCreateReq is the request to mount a volume on a peer
+type MountCreateReq struct {
+	Volume  string            `json:"volume"`
+	Path    string            `json:"path"`
+	Options map[string]string `json:"options,omitempty"`
+	Owner   string            `json:"owner,omitempty"`
+}
+
+// MountStatus is a managed mount with its state on its peer
+type MountStatus struct {
+	Mount
+	// Online tells if the volume is mounted, with its client process
+	// running
+	Online bool `json:"online"`
+	PID    int  `json:"pid,omitempty"`
+}
+
+// MountResp is the response to a mount request
+type MountResp Mount
+
+// MountListResp is the response listing the managed mounts of a peer
+type MountListResp []MountStatus
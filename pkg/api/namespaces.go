@@ -0,0 +1,57 @@
+package api
+
+import "time"
+
+// Namespace is a tenant of the cluster, owning volumes. The quotas limit
+// what can be provisioned in the namespace, 0 is no limit.
+type Namespace struct {
+	Name string `json:"name"`
+	// MaxVolumes is the number of volumes the namespace can have
+	MaxVolumes int `json:"max-volumes,omitempty"`
+	// MaxCapacity is the total size, in bytes, of the volumes the
+	// namespace can have
+	MaxCapacity uint64    `json:"max-capacity,omitempty"`
+	CreatedAt   time.Time `json:"created-at"`
+}
+
+// NamespaceUsage is what the volumes of a namespace use of its quotas
+type NamespaceUsage struct {
+	Volumes  int    `json:"volumes"`
+	Capacity uint64 `json:"capacity"`
+}
+
+// NamespaceCreateReq represents a request to create a namespace
+type NamespaceCreateReq struct {
+	Name        string `json:"name"`
+	MaxVolumes  int    `json:"max-volumes,omitempty"`
+	MaxCapacity uint64 `json:"max-capacity,omitempty"`
+}
+
+// NamespaceEditReq represents a request to change the quotas of a namespace
+type NamespaceEditReq struct {
+	MaxVolumes  int    `json:"max-volumes"`
+	MaxCapacity uint64 `json:"max-capacity"`
+}
+
+// NamespaceResp is a namespace and the usage of its quotas
+type NamespaceResp struct {
+	Namespace
+	Usage NamespaceUsage `json:"usage"`
+}
+
+// NamespaceListResp is the response to a request listing the namespaces
+type NamespaceListResp []NamespaceResp
+
+// NamespaceToken is an API token scoped to a namespace. The requests
+// authenticated with it are issued by its ID, and signed with its secret.
+type NamespaceToken struct {
+	ID        string    `json:"id"`
+	Namespace string    `json:"namespace"`
+	CreatedAt time.Time `json:"created-at"`
+	// Secret is only returned when the token is created
+	Secret string `json:"secret,omitempty"`
+}
+
+// NamespaceTokenListResp is the response to a request listing the tokens of
+// a namespace
+type NamespaceTokenListResp []NamespaceToken
@@ -0,0 +1,27 @@
+package api
+
+import (
+	"time"
+
+	"github.com/pborman/uuid"
+)
+
+// OrphanBrickProcess is a glusterfsd process started by a peer which serves
+// no brick of a started volume or activated snapshot of the cluster, left
+// behind by a crash or by a deleted volume
+type OrphanBrickProcess struct {
+	PeerID    uuid.UUID `json:"peer-id"`
+	PID       int       `json:"pid"`
+	BrickPath string    `json:"brick-path"`
+	VolfileID string    `json:"volfile-id,omitempty"`
+	// Since is when the process was first found orphaned
+	Since time.Time `json:"since"`
+}
+
+// OrphanBrickListResp is the response to listing the orphaned brick
+// processes detected on the peers
+type OrphanBrickListResp []OrphanBrickProcess
+
+// OrphanBrickCleanupResp is the response to a cleanup of the orphaned brick
+// processes, with the processes killed
+type OrphanBrickCleanupResp []OrphanBrickProcess
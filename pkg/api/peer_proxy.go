@@ -0,0 +1,28 @@
+package api
+
+import "encoding/json"
+
+const (
+	// TargetPeerHeader is the header of the requests for the local state of
+	// a peer, such as its statedump, naming the peer the request is for by
+	// its ID or name. The request is served by that peer, whichever peer it
+	// is sent to.
+	TargetPeerHeader = "X-Gluster-Target-Peer"
+
+	// AllPeers is the value of TargetPeerHeader sending a GET request to
+	// all the peers, and returning their responses as PeerResponses
+	AllPeers = "all"
+)
+
+// PeerResponse is the response of a peer to a request sent to all the peers
+type PeerResponse struct {
+	Status int `json:"status,omitempty"`
+	// Body is the JSON response of the peer, or its response as a JSON
+	// string if it is not JSON
+	Body  json.RawMessage `json:"body,omitempty"`
+	Error string          `json:"error,omitempty"`
+}
+
+// PeerResponses are the responses of the peers to a request sent to all the
+// peers, by peer ID
+type PeerResponses map[string]PeerResponse
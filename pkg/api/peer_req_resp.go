@@ -1,6 +1,8 @@
 package api
 
 import (
+	"time"
+
 	"github.com/pborman/uuid"
 )
 
@@ -13,6 +15,41 @@ type Peer struct {
 	Online          bool              `json:"online"`
 	PID             int               `json:"pid,omitempty"`
 	Metadata        map[string]string `json:"metadata"`
+	Version         string            `json:"version,omitempty"`
+	MaxOpVersion    int               `json:"max-op-version,omitempty"`
+	// Liveness is the liveness of the peer as seen by the peer answering
+	// the request, from the heartbeats it sends to it
+	Liveness *PeerLiveness `json:"liveness,omitempty"`
+}
+
+// PeerLivenessState is the state of a peer, from the heartbeats sent to it
+type PeerLivenessState string
+
+const (
+	// PeerOnline is the state of a peer answering the heartbeats
+	PeerOnline PeerLivenessState = "online"
+	// PeerDegraded is the state of a peer which missed the last
+	// heartbeats, but not enough of them to be offline
+	PeerDegraded PeerLivenessState = "degraded"
+	// PeerOffline is the state of a peer which missed enough heartbeats in
+	// a row
+	PeerOffline PeerLivenessState = "offline"
+)
+
+// PeerLiveness is the liveness of a peer
+type PeerLiveness struct {
+	State PeerLivenessState `json:"state"`
+	// Since is when the peer entered the state
+	Since time.Time `json:"since"`
+	// LastSeen is when the peer last answered a heartbeat
+	LastSeen *time.Time `json:"last-seen,omitempty"`
+	// Misses is the number of heartbeats missed in a row
+	Misses int `json:"misses,omitempty"`
+	// ClockOffsetMs is the offset of the clock of the peer from the clock
+	// of the peer answering the request, sampled by the last heartbeat
+	ClockOffsetMs float64 `json:"clock-offset-ms"`
+	// ClockSkewed is true if the offset is beyond the clock skew threshold
+	ClockSkewed bool `json:"clock-skewed,omitempty"`
 }
 
 // PeerAddReq represents an incoming request to add a peer to the cluster
@@ -31,6 +68,47 @@ type PeerEditReq struct {
 // PeerAddResp is the success response sent to a PeerAddReq request
 type PeerAddResp Peer
 
+// PeerTokenReq represents a request to create a token for a peer to join
+// the cluster
+type PeerTokenReq struct {
+	// TTL is the time in seconds the token is valid for
+	TTL int `json:"ttl,omitempty"`
+}
+
+// PeerTokenResp is the response to creating a join token
+type PeerTokenResp struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires-at"`
+}
+
+// PeerJoinReq represents a request to a peer to join a cluster with a join
+// token created in the cluster
+type PeerJoinReq struct {
+	Token    string            `json:"token"`
+	Zone     string            `json:"zone,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// PeerJoinResp is the success response sent to a PeerJoinReq request
+type PeerJoinResp Peer
+
+// PeerAdmitReq is sent by a peer joining a cluster with a join token, to a
+// peer of the cluster
+type PeerAdmitReq struct {
+	Token  string    `json:"token"`
+	PeerID uuid.UUID `json:"peer-id"`
+}
+
+// PeerAdmitResp is the response to a PeerAdmitReq, with what the peer needs
+// to join the cluster
+type PeerAdmitResp struct {
+	ClusterID uuid.UUID `json:"cluster-id"`
+	// Endpoints are the endpoints of the store of the cluster
+	Endpoints []string `json:"endpoints"`
+	// CA is the CA bundle trusted for TLS in the cluster
+	CA string `json:"ca,omitempty"`
+}
+
 // PeerEditResp is the success response sent to a PeerEditReq request
 type PeerEditResp Peer
 
@@ -57,3 +135,50 @@ func (p *PeerAddReq) MetadataSize() int {
 func (p *PeerEditReq) MetadataSize() int {
 	return mapSize(p.Metadata)
 }
+
+// MetadataSize returns the size of the peer metadata in PeerJoinReq
+func (p *PeerJoinReq) MetadataSize() int {
+	return mapSize(p.Metadata)
+}
+
+// Services listening on the ports of a peer
+const (
+	PortServiceManagement = "management"
+	PortServicePeerRPC    = "peer-rpc"
+	PortServiceEtcdClient = "etcd-client"
+	PortServiceEtcdPeer   = "etcd-peer"
+	PortServiceMetrics    = "metrics"
+	PortServiceBrick      = "brick"
+)
+
+// PortBrick is a brick served on a port
+type PortBrick struct {
+	Volume string `json:"volume,omitempty"`
+	Path   string `json:"path"`
+}
+
+// PeerPort is a port a peer, or a daemon it manages, listens on or is
+// expected to listen on
+type PeerPort struct {
+	Port     int    `json:"port"`
+	Protocol string `json:"protocol"`
+	Service  string `json:"service"`
+	// Address is the address the service is configured to listen on
+	Address string `json:"address,omitempty"`
+	// Bricks are the bricks served on the port, several with brick
+	// multiplexing
+	Bricks []PortBrick `json:"bricks,omitempty"`
+	PID    int         `json:"pid,omitempty"`
+	// Expected is true if the port is configured, or is the port of a
+	// brick of a started volume or activated snapshot
+	Expected bool `json:"expected"`
+	// Listening is true if a socket is listening on the port on the peer
+	Listening bool `json:"listening"`
+}
+
+// PeerPortsResp is the response sent for a request listing the ports of a
+// peer
+type PeerPortsResp struct {
+	PeerID uuid.UUID  `json:"peer-id"`
+	Ports  []PeerPort `json:"ports"`
+}
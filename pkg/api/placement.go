@@ -0,0 +1,31 @@
+package api
+
+// PlacementDevice is the space of a device available for the bricks of a
+// prospective volume, before and after the bricks are created
+type PlacementDevice struct {
+	PeerID string `json:"peer-id"`
+	Zone   string `json:"zone"`
+	Device string `json:"device"`
+	// Bricks is the number of bricks of the volume placed on the device
+	Bricks        int    `json:"bricks"`
+	AvailableSize uint64 `json:"available-size"`
+	// RemainingSize is the space left on the device once the bricks are
+	// created
+	RemainingSize uint64 `json:"remaining-size"`
+}
+
+// PlacementPreviewResp tells if a prospective volume can be provisioned, and
+// where its bricks would be placed
+type PlacementPreviewResp struct {
+	Feasible bool `json:"feasible"`
+	// Reason tells why the volume cannot be provisioned
+	Reason     string      `json:"reason,omitempty"`
+	Size       uint64      `json:"size"`
+	SubvolType string      `json:"subvolume-type,omitempty"`
+	Subvols    []SubvolReq `json:"subvols,omitempty"`
+	// Devices are the devices available for the bricks, with the space
+	// left on them
+	Devices       []PlacementDevice `json:"devices"`
+	AvailableSize uint64            `json:"available-size"`
+	RemainingSize uint64            `json:"remaining-size"`
+}
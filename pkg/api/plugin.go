@@ -0,0 +1,15 @@
+package api
+
+// PluginInfo contains the state of a glusterd2 plugin
+type PluginInfo struct {
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+	// Routes are the names of the REST routes of the plugin
+	Routes []string `json:"routes,omitempty"`
+	// StepFuncs are the transaction step functions registered by the
+	// plugin while enabled
+	StepFuncs []string `json:"step-funcs,omitempty"`
+}
+
+// PluginListResp is the response sent for a plugin list request
+type PluginListResp []PluginInfo
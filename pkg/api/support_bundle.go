@@ -0,0 +1,60 @@
+package api
+
+import (
+	"time"
+
+	"github.com/pborman/uuid"
+)
+
+// States of a support bundle
+const (
+	SupportBundleRunning   = "running"
+	SupportBundleCompleted = "completed"
+	SupportBundleFailed    = "failed"
+)
+
+// States of the collection of a support bundle on a peer
+const (
+	SupportBundlePeerPending   = "pending"
+	SupportBundlePeerCollected = "collected"
+	SupportBundlePeerFailed    = "failed"
+)
+
+// SupportBundleReq represents a request to create a support bundle
+type SupportBundleReq struct {
+	// NoLogs leaves the logs out of the bundle
+	NoLogs bool `json:"no-logs,omitempty"`
+	// LogSize is the size in bytes of the end of each log collected, 1MiB
+	// by default
+	LogSize int64 `json:"log-size,omitempty"`
+}
+
+// SupportBundlePeer is the progress of the collection of a support bundle
+// on a peer
+type SupportBundlePeer struct {
+	ID    uuid.UUID `json:"id"`
+	Name  string    `json:"name"`
+	State string    `json:"state"`
+	Error string    `json:"error,omitempty"`
+}
+
+// SupportBundle is a support bundle, an archive of the logs, statedumps,
+// configuration, versions and health of the peers, to be attached to bug
+// reports
+type SupportBundle struct {
+	ID    uuid.UUID `json:"id"`
+	State string    `json:"state"`
+	// PeerID is the peer the archive is created on, and downloaded from
+	PeerID uuid.UUID `json:"peer-id"`
+	// Progress is the percentage of the collection done
+	Progress int                 `json:"progress"`
+	Peers    []SupportBundlePeer `json:"peers"`
+	// Size is the size in bytes of the archive, once completed
+	Size      int64     `json:"size,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	CreatedAt time.Time `json:"created-at"`
+	UpdatedAt time.Time `json:"updated-at"`
+}
+
+// SupportBundleListResp is the response to listing the support bundles
+type SupportBundleListResp []SupportBundle
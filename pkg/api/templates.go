@@ -0,0 +1,53 @@
+package api
+
+import (
+	"time"
+)
+
+// VolfileXlator is an xlator in a volfile template.
+type VolfileXlator struct {
+	NameTmpl        string            `json:"name-tmpl,omitempty"`
+	Type            string            `json:"type,omitempty"`
+	TypeTmpl        string            `json:"type-tmpl,omitempty"`
+	OnlyLocalBricks bool              `json:"only-local-bricks,omitempty"`
+	Disabled        bool              `json:"disabled,omitempty"`
+	EnableByOption  bool              `json:"enable-by-option,omitempty"`
+	Options         map[string]string `json:"options,omitempty"`
+	IgnoreOptions   []string          `json:"ignore-options,omitempty"`
+}
+
+// VolfileTemplate is the graph of xlators from which volfiles are
+// generated. Level is one of brick, volume or cluster.
+type VolfileTemplate struct {
+	Name               string          `json:"name"`
+	Level              string          `json:"level"`
+	Xlators            []VolfileXlator `json:"xlators"`
+	VolumeGraphXlators []VolfileXlator `json:"volume-graph-xlators,omitempty"`
+	SubvolGraphXlators []VolfileXlator `json:"subvol-graph-xlators,omitempty"`
+	BrickGraphXlators  []VolfileXlator `json:"brick-graph-xlators,omitempty"`
+}
+
+// VolfileTemplateReq represents a request to save a new version of a
+// volfile template.
+type VolfileTemplateReq VolfileTemplate
+
+// VolfileTemplateResp is a version of a volfile template. Version 0 is the
+// built-in template.
+type VolfileTemplateResp struct {
+	Version   int             `json:"version"`
+	UpdatedAt time.Time       `json:"updated-at"`
+	Template  VolfileTemplate `json:"template"`
+}
+
+// VolfileTemplateListResp is the latest version of each volfile template.
+type VolfileTemplateListResp []VolfileTemplateResp
+
+// VolfileTemplateVersionsResp is the list of versions of a volfile
+// template, oldest first.
+type VolfileTemplateVersionsResp []VolfileTemplateResp
+
+// VolumeTemplatePinReq represents a request to pin the version of a volfile
+// template used by a volume.
+type VolumeTemplatePinReq struct {
+	Version int `json:"version"`
+}
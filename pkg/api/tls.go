@@ -0,0 +1,66 @@
+package api
+
+import (
+	"time"
+
+	"github.com/pborman/uuid"
+)
+
+// TLSCAReq represents a request to set the CA bundle trusted for TLS on
+// the data path of the volumes
+type TLSCAReq struct {
+	// CA is the PEM encoded bundle of the certificates of the CAs
+	CA string `json:"ca"`
+}
+
+// TLSCAResp is the response to getting or setting the CA bundle
+type TLSCAResp struct {
+	CA string `json:"ca"`
+	// Subjects are the subjects of the certificates of the bundle
+	Subjects []string `json:"subjects"`
+}
+
+// VolumeTLSReq represents a request to enable TLS on the data path of a
+// volume
+type VolumeTLSReq struct {
+	// Allow are the common names of the certificates of the clients
+	// allowed to connect to the bricks. All clients with a trusted
+	// certificate are allowed if empty.
+	Allow []string `json:"allow,omitempty"`
+}
+
+// TLSNodeStatus is the state of the certificate of a peer used on the data
+// path of the volumes
+type TLSNodeStatus struct {
+	PeerID   uuid.UUID `json:"peer-id"`
+	Subject  string    `json:"subject,omitempty"`
+	Issuer   string    `json:"issuer,omitempty"`
+	NotAfter time.Time `json:"not-after,omitempty"`
+	Valid    bool      `json:"valid"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// VolumeTLSResp is the state of TLS on the data path of a volume
+type VolumeTLSResp struct {
+	Enabled bool            `json:"enabled"`
+	Allow   string          `json:"allow,omitempty"`
+	Nodes   []TLSNodeStatus `json:"nodes"`
+}
+
+// CARenewReq represents a request to renew the certificates of the peers
+type CARenewReq struct {
+	// Force renews the certificates even if not about to expire
+	Force bool `json:"force,omitempty"`
+}
+
+// CAStatusResp is the state of the CA of the cluster, and of the
+// certificates it issued to the peers
+type CAStatusResp struct {
+	// Mode is one of off, builtin or external
+	Mode string `json:"mode"`
+	// CA is the PEM encoded certificate of the built-in CA
+	CA       string          `json:"ca,omitempty"`
+	Subject  string          `json:"subject,omitempty"`
+	NotAfter time.Time       `json:"not-after,omitempty"`
+	Nodes    []TLSNodeStatus `json:"nodes"`
+}
@@ -0,0 +1,80 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// Kinds of the nodes of the topology of the cluster
+const (
+	TopologyPeer      = "peer"
+	TopologyDevice    = "device"
+	TopologyBrick     = "brick"
+	TopologySubvolume = "subvolume"
+	TopologyVolume    = "volume"
+	TopologyDaemon    = "daemon"
+)
+
+// TopologyNode is a peer, device, brick, subvolume, volume or daemon of the
+// cluster
+type TopologyNode struct {
+	ID    string            `json:"id"`
+	Kind  string            `json:"kind"`
+	Name  string            `json:"name"`
+	Attrs map[string]string `json:"attrs,omitempty"`
+}
+
+// TopologyEdge links two nodes of the topology. The edges go from the peers
+// to their devices and daemons, from the devices to the bricks created on
+// them, or from the peers for the bricks on no device, from the bricks to
+// their subvolumes, and from the subvolumes to their volumes.
+type TopologyEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// ClusterTopologyResp is the response sent for a request of the topology of
+// the cluster, as a graph
+type ClusterTopologyResp struct {
+	Nodes []TopologyNode `json:"nodes"`
+	Edges []TopologyEdge `json:"edges"`
+}
+
+// topologyShapes are the shapes of the kinds of nodes in DOT
+var topologyShapes = map[string]string{
+	TopologyPeer:      "box3d",
+	TopologyDevice:    "cylinder",
+	TopologyBrick:     "folder",
+	TopologySubvolume: "ellipse",
+	TopologyVolume:    "doubleoctagon",
+	TopologyDaemon:    "component",
+}
+
+// DOT returns the topology in the DOT language of Graphviz
+func (t *ClusterTopologyResp) DOT() string {
+	var b bytes.Buffer
+	b.WriteString("digraph cluster {\n\trankdir=LR;\n")
+	for _, n := range t.Nodes {
+		label := n.Kind + "\n" + n.Name
+		keys := make([]string, 0, len(n.Attrs))
+		for k := range n.Attrs {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			label += "\n" + k + ": " + n.Attrs[k]
+		}
+		shape := topologyShapes[n.Kind]
+		if shape == "" {
+			shape = "box"
+		}
+		fmt.Fprintf(&b, "\t%s [label=%s, shape=%s];\n", strconv.Quote(n.ID), strconv.Quote(label), shape)
+	}
+	for _, e := range t.Edges {
+		fmt.Fprintf(&b, "\t%s -> %s;\n", strconv.Quote(e.From), strconv.Quote(e.To))
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
@@ -0,0 +1,68 @@
+package api
+
+import (
+	"time"
+
+	"github.com/pborman/uuid"
+)
+
+// States of a rolling upgrade
+const (
+	UpgradeRunning   = "running"
+	UpgradeCompleted = "completed"
+	UpgradeFailed    = "failed"
+	UpgradeAborted   = "aborted"
+)
+
+// States of a peer during a rolling upgrade
+const (
+	// PeerUpgradePending is the state of the peers waiting for their turn
+	PeerUpgradePending = "pending"
+	// PeerUpgradeMaintenance is the state of the peer whose bricks are
+	// stopped, until glusterd2 is restarted on it after being upgraded
+	PeerUpgradeMaintenance = "maintenance"
+	// PeerUpgradeHealing is the state of the upgraded peer until the
+	// entries pending heal on the volumes having bricks on it are healed
+	PeerUpgradeHealing = "healing"
+	PeerUpgradeDone    = "done"
+	// PeerUpgradeSkipped is the state of the peers already running the
+	// target version
+	PeerUpgradeSkipped = "skipped"
+)
+
+// UpgradeReq represents a request to start a rolling upgrade of the peers
+type UpgradeReq struct {
+	// TargetVersion is the glusterd2 version the peers are upgraded to.
+	// If set, peers already running it are skipped, and a peer is taken
+	// to be upgraded once it runs it. Otherwise a peer is taken to be
+	// upgraded once glusterd2 restarts on it.
+	TargetVersion string `json:"target-version,omitempty"`
+	// Force starts the upgrade even if taking down a peer makes volumes
+	// lose quorum or become unavailable
+	Force bool `json:"force,omitempty"`
+}
+
+// PeerUpgradeStatus is the progress of the upgrade of a peer
+type PeerUpgradeStatus struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	State       string    `json:"state"`
+	FromVersion string    `json:"from-version,omitempty"`
+	Version     string    `json:"version,omitempty"`
+	// PID is the pid of glusterd2 on the peer when it was put in
+	// maintenance, a different one telling that it was restarted
+	PID       int       `json:"pid,omitempty"`
+	Message   string    `json:"message,omitempty"`
+	UpdatedAt time.Time `json:"updated-at"`
+}
+
+// UpgradeStatus is the progress of the rolling upgrade of the cluster. The
+// peers are upgraded one by one, in the order they are listed in.
+type UpgradeStatus struct {
+	State         string              `json:"state"`
+	TargetVersion string              `json:"target-version,omitempty"`
+	Message       string              `json:"message,omitempty"`
+	StartedAt     time.Time           `json:"started-at"`
+	UpdatedAt     time.Time           `json:"updated-at"`
+	Peers         []PeerUpgradeStatus `json:"peers"`
+}
@@ -0,0 +1,53 @@
+package api
+
+import (
+	"github.com/pborman/uuid"
+)
+
+// MdCacheStats are the counters of the md-cache xlators of the clients of a
+// volume, summed over the clients
+type MdCacheStats struct {
+	// Clients is the number of clients whose statedumps were read
+	Clients         int    `json:"clients"`
+	StatHits        uint64 `json:"stat-hits"`
+	StatMisses      uint64 `json:"stat-misses"`
+	XattrHits       uint64 `json:"xattr-hits"`
+	XattrMisses     uint64 `json:"xattr-misses"`
+	NegativeLookups uint64 `json:"negative-lookups"`
+	// Invalidations are the cache invalidations received by the clients
+	Invalidations uint64 `json:"invalidations"`
+}
+
+// BrickInodeStats is the state of the inode table of a brick
+type BrickInodeStats struct {
+	Peer  uuid.UUID `json:"peer-id"`
+	Brick string    `json:"brick"`
+	// LruLimit is the number of inodes not in use the brick keeps cached
+	LruLimit uint64 `json:"lru-limit"`
+	// LruSize is the number of inodes not in use cached by the brick
+	LruSize uint64 `json:"lru-size"`
+	// ActiveSize is the number of inodes in use
+	ActiveSize uint64 `json:"active-size"`
+}
+
+// VolumeRecommendation is an advice of the volume advisor
+type VolumeRecommendation struct {
+	Reason string `json:"reason"`
+	// Group is the option group to set to follow the recommendation
+	Group string `json:"group,omitempty"`
+	// Options are the volume options to set to follow the recommendation
+	Options map[string]string `json:"options,omitempty"`
+}
+
+// VolumeAdvisorResp is the response sent for a volume advisor request
+type VolumeAdvisorResp struct {
+	// SmallFileMode tells if the options of the small-file option group
+	// are set on the volume
+	SmallFileMode bool         `json:"small-file-mode"`
+	MdCache       MdCacheStats `json:"md-cache"`
+	// MdCacheHitRate is the percentage of the stat lookups served by the
+	// md-cache of the clients, -1 when no lookup was counted
+	MdCacheHitRate  float64                `json:"md-cache-hit-rate"`
+	Bricks          []BrickInodeStats      `json:"bricks"`
+	Recommendations []VolumeRecommendation `json:"recommendations"`
+}
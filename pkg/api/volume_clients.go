@@ -0,0 +1,65 @@
+package api
+
+import (
+	"time"
+
+	"github.com/pborman/uuid"
+)
+
+// Layers a client of a volume is seen connected at
+const (
+	// ClientOfBrick is a client connected to a brick of the volume
+	ClientOfBrick = "brick"
+	// ClientOfGlusterd is a client connected to glusterd2, to fetch the
+	// volfiles of the volume and be notified of their changes
+	ClientOfGlusterd = "glusterd"
+)
+
+// VolumeClient is a client connection to a brick of a volume, or to
+// glusterd2 for a volume
+type VolumeClient struct {
+	// Peer is the peer serving the brick or running glusterd2 the
+	// client is connected to
+	Peer uuid.UUID `json:"peer-id"`
+	// Layer is ClientOfBrick or ClientOfGlusterd
+	Layer string `json:"layer"`
+	// Brick is the path of the brick, for the clients of bricks
+	Brick string `json:"brick,omitempty"`
+	// Address is the address of the client, with its port
+	Address string `json:"address"`
+	// Pid is the PID of the client process, when known
+	Pid int `json:"pid,omitempty"`
+	// OpVersion is the highest op-version the client supports, and
+	// MinOpVersion the lowest, when advertised by the client
+	OpVersion    int `json:"op-version,omitempty"`
+	MinOpVersion int `json:"min-op-version,omitempty"`
+	// BelowMinOpVersion is set for the clients supporting an op-version
+	// lower than the client-compat.min-op-version of the volume, which
+	// are refused new mounts of the volume
+	BelowMinOpVersion bool   `json:"below-min-op-version,omitempty"`
+	Name              string `json:"name,omitempty"`
+	// ConnectedSince is set for the clients of glusterd
+	ConnectedSince time.Time `json:"connected-since,omitempty"`
+	BytesRead      uint64    `json:"bytes-read,omitempty"`
+	BytesWritten   uint64    `json:"bytes-written,omitempty"`
+}
+
+// VolumeClientsResp is the response sent for a volume clients request
+type VolumeClientsResp []VolumeClient
+
+// ClientDisconnectReq represents a request to disconnect a client of a
+// volume
+type ClientDisconnectReq struct {
+	// Host is the hostname or IP address of the client
+	Host string `json:"host"`
+	// Pid restricts the disconnection to the client process with that
+	// PID, all the clients on the host being disconnected if 0
+	Pid int `json:"pid,omitempty"`
+}
+
+// ClientDisconnectResp is the response sent for a client disconnect
+// request
+type ClientDisconnectResp struct {
+	// Disconnected is the number of connections closed per peer
+	Disconnected map[string]int `json:"disconnected"`
+}
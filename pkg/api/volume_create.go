@@ -0,0 +1,36 @@
+package api
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// AddThinArbiter adds the thin arbiter option to the volume create request
+func (req *VolCreateReq) AddThinArbiter(thinArbiter string) error {
+
+	s := strings.Split(thinArbiter, ":")
+	if len(s) != 2 && len(s) != 3 {
+		return errors.New("thin arbiter brick must be of the form <host>:<brick> or <host>:<brick>:<port>")
+	}
+
+	// TODO: If required, handle this in a generic way, just like other
+	// volume set options that we're going to allow to be set during
+	// volume create.
+	if req.Options == nil {
+		req.Options = make(map[string]string)
+	}
+	req.Options["replicate.thin-arbiter"] = thinArbiter
+	req.AllowAdvanced = true
+	return nil
+}
+
+// AddShard adds the shard options to the volume create request
+func (req *VolCreateReq) AddShard(shardSize uint64) {
+	if req.Options == nil {
+		req.Options = make(map[string]string)
+	}
+	req.Options["features/shard"] = "on"
+	req.Options["features/shard.shard-block-size"] = strconv.FormatUint(shardSize, 10)
+	req.AllowAdvanced = true
+}
@@ -0,0 +1,25 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddThinArbiter(t *testing.T) {
+	var req VolCreateReq
+	assert.Error(t, req.AddThinArbiter("host"))
+	assert.False(t, req.AllowAdvanced)
+
+	assert.NoError(t, req.AddThinArbiter("host:/bricks/ta:24007"))
+	assert.Equal(t, "host:/bricks/ta:24007", req.Options["replicate.thin-arbiter"])
+	assert.True(t, req.AllowAdvanced)
+}
+
+func TestAddShard(t *testing.T) {
+	var req VolCreateReq
+	req.AddShard(67108864)
+	assert.Equal(t, "on", req.Options["features/shard"])
+	assert.Equal(t, "67108864", req.Options["features/shard.shard-block-size"])
+	assert.True(t, req.AllowAdvanced)
+}
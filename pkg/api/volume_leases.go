@@ -0,0 +1,25 @@
+package api
+
+import (
+	"github.com/pborman/uuid"
+)
+
+// BrickLeases is the state of the leases granted by a brick of a volume
+type BrickLeases struct {
+	// Peer is the peer serving the brick
+	Peer  uuid.UUID `json:"peer-id"`
+	Brick string    `json:"brick"`
+	// Stats are the counters of the leases xlator of the brick, such as
+	// the leases granted and recalled
+	Stats map[string]string `json:"stats,omitempty"`
+	// Error is set when the state of the leases could not be fetched
+	// from the brick, for example when it is down
+	Error string `json:"error,omitempty"`
+}
+
+// VolumeLeasesResp is the response sent for a volume leases request
+type VolumeLeasesResp struct {
+	// Enabled tells if the bricks of the volume grant leases
+	Enabled bool          `json:"enabled"`
+	Bricks  []BrickLeases `json:"bricks"`
+}
@@ -18,6 +18,7 @@ type BrickReq struct {
 	DevicePath     string `json:"device-path,omitempty"`
 	MntOpts        string `json:"mnt-opts,omitempty"`
 	FsType         string `json:"fs-type,omitempty"`
+	Encrypted      bool   `json:"encrypted,omitempty"`
 }
 
 // SubvolReq represents Sub volume Request
@@ -38,6 +39,9 @@ type SubvolReq struct {
 "allow-root-dir" : allow root directory to create brick
 "allow-mount-as-brick" : reuse if its already mountpoint
 "create-brick-dir" : if brick dir is not present, create it
+Force skips the checks of the flags above, and cleans the gluster xattrs left
+on the bricks by previous volumes. The bricks nested in each other are always
+refused.
 */
 type VolCreateReq struct {
 	Name                    string            `json:"name"`
@@ -64,6 +68,13 @@ type VolCreateReq struct {
 	SubvolZonesOverlap      bool              `json:"subvolume-zones-overlap,omitempty"`
 	SubvolType              string            `json:"subvolume-type,omitempty"`
 	ProvisionerType         string            `json:"provisioner"`
+	Encrypted               bool              `json:"encrypted,omitempty"`
+	BrickUser               string            `json:"brick-user,omitempty"`
+	// Namespace is the namespace the volume is created in. It is the
+	// namespace of the token authenticating the request if not set.
+	Namespace string `json:"namespace,omitempty"`
+	// Labels identify the volume, and select it in the volume lists
+	Labels map[string]string `json:"labels,omitempty"`
 	VolOptionReq
 }
 
@@ -94,6 +105,9 @@ type VolOptionResetReq struct {
 "allow-root-dir" : allow root directory to create brick
 "allow-mount-as-brick" : reuse if its already mountpoint
 "create-brick-dir" : if brick dir is not present, create it
+Force skips the checks of the flags above, and cleans the gluster xattrs left
+on the bricks by previous volumes. The bricks nested in each other are always
+refused.
 */
 type VolExpandReq struct {
 	ReplicaCount    int             `json:"replica,omitempty"`
@@ -110,11 +124,15 @@ type VolumeOption struct {
 	OnValue string `json:"onvalue"`
 }
 
-// OptionGroup represents a group of options
+// OptionGroup represents a group of options. Builtin and Overridden are set
+// by glusterd2 in its responses: the builtin groups are shipped with
+// glusterd2, and are overridden when the site replaced their options.
 type OptionGroup struct {
 	Name        string         `json:"name"`
 	Options     []VolumeOption `json:"options"`
 	Description string         `json:"description"`
+	Builtin     bool           `json:"builtin,omitempty"`
+	Overridden  bool           `json:"overridden,omitempty"`
 }
 
 // OptionGroupReq represents a request to create a new option group
@@ -144,6 +162,31 @@ type VolEditReq struct {
 	DeleteMetadata bool              `json:"delete-metadata"`
 }
 
+// VolPatchReq represents a request patching a volume. The labels are
+// merged into the labels of the volume, the labels set to null are removed.
+type VolPatchReq struct {
+	Labels map[string]*string `json:"labels"`
+}
+
+// VolOptionsBatchReq represents a request setting options on the volumes
+// selected by a label selector, one volume after the other
+type VolOptionsBatchReq struct {
+	// Selector is the label selector of the volumes
+	Selector string `json:"selector,omitempty"`
+	// All selects all the volumes, the selector must then be empty
+	All bool `json:"all,omitempty"`
+	// StopOnError skips the remaining volumes once the options fail to
+	// be set on a volume
+	StopOnError bool `json:"stop-on-error,omitempty"`
+	VolOptionReq
+}
+
+// BrickEnableReq represents a request to re-enable a degraded brick
+type BrickEnableReq struct {
+	PeerID string `json:"peerid"`
+	Path   string `json:"path"`
+}
+
 // ReplaceBrickReq represents replace brick request
 type ReplaceBrickReq struct {
 	SrcPeerID          string          `json:"src-peerid"`
@@ -160,6 +203,93 @@ type ReplaceBrickReq struct {
 // VolumeStartReq represents a request to start volume
 type VolumeStartReq struct {
 	ForceStartBricks bool `json:"force-start-bricks,omitempty"`
+	// ResetVolumeID sets the volume ID of the brick paths to the volume
+	// before starting the bricks, taking over brick paths which belong to
+	// another volume or hold data without a volume ID
+	ResetVolumeID bool `json:"reset-volume-id,omitempty"`
+}
+
+// VolumeStopReq represents a request to stop a volume
+type VolumeStopReq struct {
+	// Force stops the volume even if clients are connected to it, which
+	// are disconnected first
+	Force bool `json:"force,omitempty"`
+}
+
+// What is done with the data of the bricks given by the admin when a volume
+// is purged
+const (
+	// BrickDataKeep leaves the bricks as they are
+	BrickDataKeep = "keep"
+	// BrickDataMetadata removes the metadata kept by glusterfs on the
+	// bricks, leaving the data of the files
+	BrickDataMetadata = "metadata"
+	// BrickDataWipe removes all the contents of the bricks
+	BrickDataWipe = "wipe"
+)
+
+// VolumeDeleteReq represents a request to delete a volume
+type VolumeDeleteReq struct {
+	// Purge deletes the volume for good at once, instead of retaining it
+	// in the trash for the grace period set by the cluster option
+	// cluster.volume-trash-retention
+	Purge bool `json:"purge,omitempty"`
+	// BrickData is what is done with the data of the bricks when the
+	// volume is purged: BrickDataKeep (the default), BrickDataMetadata or
+	// BrickDataWipe. The bricks provisioned by glusterd2 are always
+	// removed.
+	BrickData string `json:"brick-data,omitempty"`
+}
+
+// SubdirExportReq represents a request to export a subdirectory of a volume
+// to a set of clients. Exporting "/" restricts the clients which can mount
+// the whole volume, which is open to all the clients otherwise.
+type SubdirExportReq struct {
+	Path string `json:"path"`
+	// AuthAllow is the list of addresses or hostnames, possibly with
+	// wildcards, of the clients allowed to mount the subdirectory. All the
+	// clients are allowed if empty.
+	AuthAllow []string `json:"auth-allow,omitempty"`
+}
+
+// VolumeBarrierReq represents a request to enable the barrier on the bricks
+// of a volume
+type VolumeBarrierReq struct {
+	// Timeout is the number of seconds after which the barrier is
+	// released if not disabled before. It defaults to 120.
+	Timeout int `json:"timeout,omitempty"`
+}
+
+// VolumeMode is the mode of a volume, restricting the changes to its files
+type VolumeMode string
+
+// Modes of a volume
+const (
+	// VolumeModeReadWrite allows all the changes to the files
+	VolumeModeReadWrite VolumeMode = "read-write"
+	// VolumeModeReadOnly allows no change to the files
+	VolumeModeReadOnly VolumeMode = "read-only"
+	// VolumeModeWORM allows the files to be written once, after which
+	// they can no longer be changed or deleted
+	VolumeModeWORM VolumeMode = "worm"
+	// VolumeModeWORMFileLevel retains each file, which cannot be changed
+	// or deleted, for a retention period once it is committed
+	VolumeModeWORMFileLevel VolumeMode = "worm-file-level"
+)
+
+// VolumeModeReq represents a request to set the mode of a volume. The
+// retention options apply only to the worm-file-level mode.
+type VolumeModeReq struct {
+	Mode VolumeMode `json:"mode"`
+	// RetentionPeriod is the number of seconds a file is retained for
+	// once committed
+	RetentionPeriod int `json:"retention-period,omitempty"`
+	// AutoCommitPeriod is the number of seconds after which a file not
+	// modified is committed
+	AutoCommitPeriod int `json:"auto-commit-period,omitempty"`
+	// RetentionMode is "relax", the default, which allows the retention
+	// period of a file to be shortened, or "enterprise", which does not
+	RetentionMode string `json:"retention-mode,omitempty"`
 }
 
 // MetadataSize returns the size of the volume metadata in VolCreateReq
@@ -1,6 +1,10 @@
 package api
 
-import "github.com/pborman/uuid"
+import (
+	"time"
+
+	"github.com/pborman/uuid"
+)
 
 const (
 	// ProvisionerTypeLoop represents loop device based provisioner
@@ -19,6 +23,7 @@ type BrickInfo struct {
 	PeerID     uuid.UUID `json:"peer-id"`
 	Hostname   string    `json:"host"`
 	Type       BrickType `json:"type"`
+	Encrypted  bool      `json:"encrypted,omitempty"`
 }
 
 // Subvol contains static information about sub volume
@@ -53,6 +58,26 @@ type BrickStatus struct {
 	MountOpts string    `json:"mount-opts"`
 	Device    string    `json:"device"`
 	Size      SizeInfo  `json:"size"`
+	// Degraded is set when the posix health-check of the brick failed,
+	// or when it exceeded its I/O error budget
+	Degraded         bool   `json:"degraded,omitempty"`
+	HealthCheckError string `json:"health-check-error,omitempty"`
+	IOErrors         int    `json:"io-errors,omitempty"`
+	// Resources is the resource usage of the cgroup of the brick process,
+	// when glusterd2 runs the brick processes in cgroups
+	Resources *ResourceUsage `json:"resources,omitempty"`
+}
+
+// ResourceUsage is the resource usage and limits of the cgroup of a process
+type ResourceUsage struct {
+	Cgroup      string  `json:"cgroup"`
+	CPUSeconds  float64 `json:"cpu-seconds"`
+	MemoryBytes uint64  `json:"memory-bytes"`
+	// CPULimitPercent is the CPU limit, in percent of one CPU, 0 for no
+	// limit
+	CPULimitPercent int `json:"cpu-limit-percent,omitempty"`
+	// MemoryLimitBytes is the memory limit, 0 for no limit
+	MemoryLimitBytes uint64 `json:"memory-limit-bytes,omitempty"`
 }
 
 // BricksStatusResp contains statuses of bricks belonging to one
@@ -78,6 +103,25 @@ type VolumeInfo struct {
 	Metadata                map[string]string `json:"metadata"`
 	SnapList                []string          `json:"snap-list"`
 	Capacity                uint64            `json:"capacity,omitempty"`
+	Mode                    VolumeMode        `json:"mode,omitempty"`
+	Encrypted               bool              `json:"encrypted,omitempty"`
+	BrickUser               string            `json:"brick-user,omitempty"`
+	Namespace               string            `json:"namespace,omitempty"`
+	Labels                  map[string]string `json:"labels,omitempty"`
+}
+
+// UsageInfo represents the space and inode utilization of a volume as last
+// collected from its bricks.
+type UsageInfo struct {
+	Capacity         uint64    `json:"capacity"`
+	Used             uint64    `json:"used"`
+	Free             uint64    `json:"free"`
+	Utilization      float64   `json:"utilization"`
+	Inodes           uint64    `json:"inodes"`
+	InodesUsed       uint64    `json:"inodes-used"`
+	InodesFree       uint64    `json:"inodes-free"`
+	InodeUtilization float64   `json:"inode-utilization"`
+	UpdatedAt        time.Time `json:"updated-at"`
 }
 
 // VolumeStatusResp response contains the statuses of all bricks of the volume.
@@ -85,13 +129,37 @@ type VolumeStatusResp struct {
 	Info   VolumeInfo `json:"info"`
 	Online bool       `json:"online"`
 	Size   SizeInfo   `json:"size"`
+	Usage  *UsageInfo `json:"usage,omitempty"`
+}
+
+// VolumeSizeResp is the size of a volume, aggregated from the statfs of its
+// bricks. Bricks which did not answer in time are not accounted for.
+type VolumeSizeResp struct {
+	UsageInfo
+	Bricks         int `json:"bricks"`
+	BricksReported int `json:"bricks-reported"`
 }
 
-// VolumeOptionGetResp is the response sent for a volume option get request
+// Sources of the effective value of a volume option
+const (
+	// OptionSourceVolume is the source of the options set on the volume
+	OptionSourceVolume = "volume"
+	// OptionSourceCluster is the source of the default volume options set
+	// at the cluster level, which the volume does not set
+	OptionSourceCluster = "cluster"
+	// OptionSourceDefault is the source of the options set neither on the
+	// volume nor at the cluster level
+	OptionSourceDefault = "default"
+)
+
+// VolumeOptionGetResp is the response sent for a volume option get request.
+// Value is the effective value of the option for the volume, and Source
+// tells where it comes from.
 type VolumeOptionGetResp struct {
 	OptName      string `json:"name"`
 	Value        string `json:"value"`
 	Modified     bool   `json:"modified"`
+	Source       string `json:"source"`
 	DefaultValue string `json:"default-value"`
 	OptionLevel  string `json:"option-level"`
 }
@@ -118,15 +186,74 @@ type ReplaceBrickResp VolumeInfo
 // VolumeExpandResp is the response sent for a volume expand request.
 type VolumeExpandResp VolumeInfo
 
+// BrickStartTime is the time taken to start a brick of a volume
+type BrickStartTime struct {
+	PeerID string `json:"peer-id"`
+	Path   string `json:"path"`
+	// Duration is the time taken to start the brick and for it to sign in
+	// with the port mapper, in milliseconds
+	Duration int64 `json:"duration-ms"`
+	// SignedIn is false if the brick did not sign in with the port mapper
+	// in time, and may not be serving the clients yet
+	SignedIn    bool `json:"signed-in"`
+	Multiplexed bool `json:"multiplexed,omitempty"`
+}
+
 // VolumeStartResp is the response sent for a volume start request.
-type VolumeStartResp VolumeInfo
+type VolumeStartResp struct {
+	VolumeInfo
+	// BrickStarts are the times taken to start the bricks
+	BrickStarts []BrickStartTime `json:"brick-starts,omitempty"`
+}
 
 // VolumeStopResp is the response sent for a volume stop request.
 type VolumeStopResp VolumeInfo
 
+// VolumeUndeleteResp is the response sent for a volume undelete request.
+type VolumeUndeleteResp VolumeInfo
+
+// TrashedVolume is a deleted volume retained in the trash, which can be
+// undeleted until it is purged
+type TrashedVolume struct {
+	Volume    VolumeInfo `json:"volume"`
+	DeletedAt time.Time  `json:"deleted-at"`
+	PurgeAt   time.Time  `json:"purge-at"`
+	// BrickData is what is done with the data of the bricks when the
+	// volume is purged
+	BrickData string `json:"brick-data"`
+}
+
+// VolumeTrashListResp is the response sent for a volume trash list request.
+type VolumeTrashListResp []TrashedVolume
+
 // VolumeOptionResp is the response sent for a volume option request.
 type VolumeOptionResp VolumeInfo
 
+// Outcomes of the volumes of a batch option request
+const (
+	BatchVolumeSet     = "set"
+	BatchVolumeFailed  = "failed"
+	BatchVolumeSkipped = "skipped"
+)
+
+// VolumeBatchResult is the outcome of a batch option request on a volume
+type VolumeBatchResult struct {
+	Volume string `json:"volume"`
+	// Status is one of BatchVolumeSet, BatchVolumeFailed or
+	// BatchVolumeSkipped
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// VolumeOptionsBatchResp is the response sent for a batch option request,
+// with the outcome of each volume selected, in the order of their names
+type VolumeOptionsBatchResp struct {
+	Results []VolumeBatchResult `json:"results"`
+	Set     int                 `json:"set"`
+	Failed  int                 `json:"failed"`
+	Skipped int                 `json:"skipped"`
+}
+
 // VolumeListResp is the response sent for a volume list request.
 /*VolumeListResp can also be filtered based on query parameters
 sent along with volume list/info api.
@@ -139,11 +266,48 @@ Note - Cannot use query parameters if volname is also supplied.
 */
 type VolumeListResp []VolumeGetResp
 
+// VolumeWatchResp is the response sent for a volume list request with
+// watch=true. It has the volumes changed and the names of the volumes
+// deleted since the revision of the request, and the revision of the store
+// they are at, to be sent as since by the next request.
+type VolumeWatchResp struct {
+	Revision int64           `json:"revision"`
+	Volumes  []VolumeGetResp `json:"volumes"`
+	Deleted  []string        `json:"deleted,omitempty"`
+}
+
 // OptionGroupListResp is the response sent for a group list request.
 type OptionGroupListResp []OptionGroup
 
+// OptionGroupGetResp is the response sent for a group get request.
+type OptionGroupGetResp OptionGroup
+
 // VolumeEditResp is the response sent for a edit volume request
 type VolumeEditResp VolumeInfo
 
+// VolumeModeResp is the response sent for a request to set the mode of a
+// volume
+type VolumeModeResp VolumeInfo
+
 // VolumeOptionsGetResp is the response sent for a volume get request for all options
 type VolumeOptionsGetResp []VolumeOptionGetResp
+
+// SubdirExport is an exported subdirectory of a volume. Clients mount it as
+// `mount -t glusterfs host:/<volume>/<path> /mnt`.
+type SubdirExport struct {
+	Path      string   `json:"path"`
+	AuthAllow []string `json:"auth-allow"`
+}
+
+// SubdirExportListResp is the response sent for a request to list the
+// exported subdirectories of a volume
+type SubdirExportListResp []SubdirExport
+
+// VolumeBarrierResp is the response sent for a request to enable or disable
+// the barrier on the bricks of a volume
+type VolumeBarrierResp struct {
+	Enabled bool `json:"enabled"`
+	// Timeout is the number of seconds after which the barrier is
+	// released
+	Timeout int `json:"timeout,omitempty"`
+}
@@ -0,0 +1,44 @@
+package cryptutils
+
+import (
+	"os"
+	"path"
+
+	"github.com/gluster/glusterd2/pkg/utils"
+)
+
+// MapperDir is where the opened encrypted devices are mapped
+const MapperDir = "/dev/mapper"
+
+// MapperPath returns the path of the device mapped under the name
+func MapperPath(name string) string {
+	return path.Join(MapperDir, name)
+}
+
+// Format formats the device with LUKS, encrypted with the key
+func Format(dev string, key []byte) error {
+	return utils.ExecuteCommandRunInput(key, "cryptsetup",
+		"luksFormat", "--batch-mode", "--type", "luks2", "--key-file", "-", dev)
+}
+
+// Open unlocks the LUKS device with the key, mapping it under the name
+func Open(dev, name string, key []byte) error {
+	return utils.ExecuteCommandRunInput(key, "cryptsetup",
+		"open", "--type", "luks", "--allow-discards", "--key-file", "-", dev, name)
+}
+
+// Close removes the mapping of the opened device
+func Close(name string) error {
+	return utils.ExecuteCommandRun("cryptsetup", "close", name)
+}
+
+// Resize resizes the mapping of the opened device to its underlying device
+func Resize(name string, key []byte) error {
+	return utils.ExecuteCommandRunInput(key, "cryptsetup", "resize", "--key-file", "-", name)
+}
+
+// IsOpen tells if the device mapped under the name is opened
+func IsOpen(name string) bool {
+	_, err := os.Stat(MapperPath(name))
+	return err == nil
+}
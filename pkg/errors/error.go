@@ -38,6 +38,8 @@ var (
 	ErrBitrotNotEnabled                = errors.New("bitrot is not enabled")
 	ErrQuotadNotRunning                = errors.New("quotad is not running")
 	ErrQuotadNotEnabled                = errors.New("quotad is not enabled")
+	ErrQuotaInvalidLimit               = errors.New("invalid quota limit")
+	ErrQuotaLimitNotFound              = errors.New("no quota limit set on the directory")
 	ErrUnknownValue                    = errors.New("unknown value specified")
 	ErrGetFailed                       = errors.New("failed to get value from the store")
 	ErrUnmarshallFailed                = errors.New("failed to unmarshall from json")
@@ -58,6 +60,8 @@ var (
 	ErrInvalidClusterOption            = errors.New("invalid cluster option key")
 	ErrInvalidVolFileTmplNamespace     = errors.New("invalid template namespace")
 	ErrInvalidVolFileTmplName          = errors.New("invalid template name")
+	ErrVolFileTmplVersionNotFound      = errors.New("template version not found")
+	ErrCustomXlatorNotFound            = errors.New("custom xlator not found")
 	ErrDeviceNameNotFound              = errors.New("device name not found")
 	ErrInvalidSplitBrainOp             = errors.New("invalid split-brain operation specified")
 	ErrInvalidHostName                 = errors.New("hostname doesn't exist")
@@ -74,5 +78,71 @@ var (
 	ErrConnectingHost                  = errors.New("could not connect to host. Make sure host address is valid, network connection is active and gd2 is up and running")
 	ErrBlockVolNotFound                = errors.New("block volume not found")
 	ErrBlockHostVolNotFound            = errors.New("block hosting volume not found")
+	ErrBlockVolExists                  = errors.New("block volume already exists")
+	ErrBlockVolResizeNotSupported      = errors.New("block provider does not support resizing block volumes")
+	ErrBlockVolShrinkNotSupported      = errors.New("block volumes can only be grown")
 	ErrSnapNotSupported                = errors.New("snapshot not supported")
+	ErrInsufficientDeviceSpace         = errors.New("space not sufficient on device")
+	ErrSubdirNotExported               = errors.New("subdirectory is not exported")
+	ErrSubdirExportExists              = errors.New("subdirectory is already exported")
+	ErrInvalidSubdirPath               = errors.New("invalid subdirectory path")
+	ErrInvalidSubdirAuthAllow          = errors.New("invalid client in subdirectory auth-allow list")
+	ErrOpVersionTooLow                 = errors.New("feature is not supported by the current cluster op-version")
+	ErrPeerOpVersionTooLow             = errors.New("feature is not supported by the op-version of this peer")
+	ErrInvalidOpVersion                = errors.New("invalid op-version")
+	ErrUpgradeInProgress               = errors.New("an upgrade is already in progress")
+	ErrUpgradeNotFound                 = errors.New("no upgrade in progress")
+	ErrUpgradeLosesQuorum              = errors.New("upgrading the peers one by one makes volumes lose quorum")
+	ErrInvalidGD1State                 = errors.New("invalid glusterd1 state")
+	ErrPluginNotFound                  = errors.New("plugin not found")
+	ErrPluginDisabled                  = errors.New("plugin is disabled")
+	ErrQuorumNotMet                    = errors.New("server-quorum is not met")
+	ErrInvalidQuorumRatio              = errors.New("server-quorum ratio must be a percentage between 0 and 100")
+	ErrInvalidBarrierTimeout           = errors.New("barrier timeout must not be negative")
+	ErrHaloNotReplicate                = errors.New("halo replication applies only to replicate volumes")
+	ErrVolumeWORM                      = errors.New("operation not permitted on a WORM volume")
+	ErrEncryptionNotSupported          = errors.New("encryption is supported only for bricks provisioned on LVM by glusterd2")
+	ErrSnapshotEncrypted               = errors.New("snapshots of volumes with encrypted bricks are not supported")
+	ErrTLSNotEnabled                   = errors.New("TLS is not enabled on the volume")
+	ErrWORMEnterpriseRetained          = errors.New("retention cannot be lifted or shortened on a volume in enterprise retention mode")
+	ErrInvalidCAMode                   = errors.New("cluster.ca must be one of off, builtin or external")
+	ErrCADisabled                      = errors.New("no CA is set for the cluster")
+	ErrCASignCommandNotSet             = errors.New("no CA sign command is set on the peer for the external CA")
+	ErrInvalidJoinToken                = errors.New("join token is invalid, expired or already used")
+	ErrConfigSnapshotNotFound          = errors.New("configuration snapshot not found")
+	ErrConfigSnapshotConflict          = errors.New("failed to take configuration snapshot, other snapshots were taken meanwhile")
+	ErrEvacuationInProgress            = errors.New("an evacuation of the peer is already in progress")
+	ErrEvacuationNotFound              = errors.New("no evacuation of the peer")
+	ErrOptionGroupNotFound             = errors.New("option group not found")
+	ErrBuiltinOptionGroup              = errors.New("cannot delete builtin groups")
+	ErrEmptyOptionGroupName            = errors.New("option group name is empty")
+	ErrBrickPathWasUsed                = errors.New("brick path was part of a volume, it has gluster xattrs")
+	ErrBrickPathNested                 = errors.New("brick path is inside another brick, or contains one")
+	ErrHookNotFound                    = errors.New("hook not found")
+	ErrHookExists                      = errors.New("a hook with the name already exists")
+	ErrMountNotFound                   = errors.New("mount not found")
+	ErrMountExists                     = errors.New("a mount with the path already exists on the peer")
+	ErrVolClientsConnected             = errors.New("clients are connected to the volume, stop it with force to disconnect them")
+	ErrTrashedVolNotFound              = errors.New("deleted volume not found in the trash")
+	ErrInvalidBrickData                = errors.New("invalid brick-data, must be one of keep, metadata or wipe")
+	ErrBrickVolumeIDMismatch           = errors.New("brick path belongs to another volume, start the volume with reset-volume-id to take it over")
+	ErrBrickVolumeIDMissing            = errors.New("brick path is not empty and has no volume ID, start the volume with reset-volume-id to take it over")
+	ErrNamespaceNotFound               = errors.New("namespace not found")
+	ErrNamespaceExists                 = errors.New("a namespace with the name already exists")
+	ErrNamespaceNotEmpty               = errors.New("namespace still has volumes")
+	ErrNamespaceQuotaExceeded          = errors.New("quota of the namespace exceeded")
+	ErrNamespaceTokenNotFound          = errors.New("namespace token not found")
+	ErrSupportBundleNotFound           = errors.New("support bundle not found")
+	ErrSupportBundleRunning            = errors.New("a support bundle is already being created on the peer")
+	ErrSupportBundleNotReady           = errors.New("support bundle is not completed")
+	ErrShardedData                     = errors.New("sharding cannot be disabled on a volume which may hold sharded files")
+	ErrServiceNotFound                 = errors.New("singleton service not found")
+	ErrNotLeader                       = errors.New("peer is not the leader of the service")
+	ErrNoLeader                        = errors.New("no peer leads the service")
+	ErrShuttingDown                    = errors.New("glusterd2 is shutting down")
+	ErrRevisionMismatch                = errors.New("the resource is not at the revision given in If-Match")
+	ErrIdempotencyKeyInUse             = errors.New("a request with the same Idempotency-Key is in progress")
+	ErrIdempotencyKeyReused            = errors.New("the Idempotency-Key was used for another request")
+	ErrRevisionCompacted               = errors.New("the revision was compacted, list the volumes again")
+	ErrBrickNotFound                   = errors.New("brick not found in the volume")
 )
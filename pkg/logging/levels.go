@@ -0,0 +1,193 @@
+package logging
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// subsystemPackages maps the subsystems which can be logged at a level
+// different from the global log level to the source packages that belong to
+// them. Packages are matched by prefix relative to the repository root.
+var subsystemPackages = map[string][]string{
+	"sunrpc":      {"glusterd2/servers/sunrpc", "pkg/sunrpc"},
+	"transaction": {"glusterd2/transaction"},
+	"store":       {"glusterd2/store", "pkg/elasticetcd"},
+	"volgen":      {"glusterd2/volgen"},
+}
+
+var levels = struct {
+	sync.RWMutex
+	global     log.Level
+	subsystems map[string]log.Level
+}{
+	global:     log.InfoLevel,
+	subsystems: make(map[string]log.Level),
+}
+
+// Subsystems returns the names of the subsystems for which log level can be
+// set separately
+func Subsystems() []string {
+	names := make([]string, 0, len(subsystemPackages))
+	for name := range subsystemPackages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// parseLevels parses the global level and the levels of the subsystems. A
+// nil level in the returned map denotes that the subsystem is to be logged
+// at the global level.
+func parseLevels(global string, subsystems map[string]string) (*log.Level, map[string]*log.Level, error) {
+	var gl *log.Level
+	if global != "" {
+		l, err := log.ParseLevel(strings.ToLower(global))
+		if err != nil {
+			return nil, nil, err
+		}
+		gl = &l
+	}
+
+	parsed := make(map[string]*log.Level, len(subsystems))
+	for name, level := range subsystems {
+		if _, ok := subsystemPackages[name]; !ok {
+			return nil, nil, fmt.Errorf("unknown subsystem %s", name)
+		}
+		if level == "" {
+			parsed[name] = nil
+			continue
+		}
+		l, err := log.ParseLevel(strings.ToLower(level))
+		if err != nil {
+			return nil, nil, err
+		}
+		parsed[name] = &l
+	}
+	return gl, parsed, nil
+}
+
+// ValidateLevels checks if the levels passed to SetLevels are valid
+func ValidateLevels(global string, subsystems map[string]string) error {
+	_, _, err := parseLevels(global, subsystems)
+	return err
+}
+
+// SetLevels sets the global log level and the log levels of the given
+// subsystems. The global level is left unchanged if it is empty. A subsystem
+// whose level is empty will be logged at the global level.
+func SetLevels(global string, subsystems map[string]string) error {
+	gl, parsed, err := parseLevels(global, subsystems)
+	if err != nil {
+		return err
+	}
+
+	levels.Lock()
+	defer levels.Unlock()
+
+	if gl != nil {
+		levels.global = *gl
+	}
+	for name, l := range parsed {
+		if l == nil {
+			delete(levels.subsystems, name)
+		} else {
+			levels.subsystems[name] = *l
+		}
+	}
+	applyLevels()
+	return nil
+}
+
+// Levels returns the global log level and the log levels of the subsystems
+// which are logged at a level different from the global level
+func Levels() (string, map[string]string) {
+	levels.RLock()
+	defer levels.RUnlock()
+
+	subsystems := make(map[string]string, len(levels.subsystems))
+	for name, l := range levels.subsystems {
+		subsystems[name] = l.String()
+	}
+	return levels.global.String(), subsystems
+}
+
+// applyLevels sets the level of the logger to the most verbose of the
+// configured levels so that entries of every subsystem reach the formatter,
+// which then filters them. Must be called with levels locked.
+func applyLevels() {
+	max := levels.global
+	for _, l := range levels.subsystems {
+		if l > max {
+			max = l
+		}
+	}
+	log.SetLevel(max)
+}
+
+func setGlobalLevel(l log.Level) {
+	levels.Lock()
+	defer levels.Unlock()
+	levels.global = l
+	applyLevels()
+}
+
+// subsystemOf returns the subsystem of the code which logged the entry
+func subsystemOf() string {
+	pcs := make([]uintptr, 16)
+	n := runtime.Callers(4, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		i := strings.Index(frame.File, gd2Repo+"/")
+		if i >= 0 && !strings.Contains(frame.File, "vendor") {
+			file := frame.File[i+len(gd2Repo)+1:]
+			if !strings.HasPrefix(file, "pkg/logging/") {
+				for name, pkgs := range subsystemPackages {
+					for _, pkg := range pkgs {
+						if strings.HasPrefix(file, pkg) {
+							return name
+						}
+					}
+				}
+				return ""
+			}
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
+// levelFilter is a formatter which drops the entries which are more verbose
+// than the level of the subsystem logging them
+type levelFilter struct {
+	log.Formatter
+}
+
+// Format formats the entry if it is to be logged, else returns nothing
+func (f levelFilter) Format(e *log.Entry) ([]byte, error) {
+	levels.RLock()
+	filter := len(levels.subsystems) != 0
+	global := levels.global
+	levels.RUnlock()
+
+	if filter {
+		level := global
+		if name := subsystemOf(); name != "" {
+			levels.RLock()
+			if l, ok := levels.subsystems[name]; ok {
+				level = l
+			}
+			levels.RUnlock()
+		}
+		if e.Level > level {
+			return nil, nil
+		}
+	}
+	return f.Formatter.Format(e)
+}
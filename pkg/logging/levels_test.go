@@ -0,0 +1,35 @@
+package logging
+
+import (
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetLevels(t *testing.T) {
+	defer SetLevels("info", map[string]string{"store": "", "sunrpc": ""})
+
+	assert.Nil(t, SetLevels("warning", map[string]string{"store": "debug"}))
+	global, subsystems := Levels()
+	assert.Equal(t, "warning", global)
+	assert.Equal(t, map[string]string{"store": "debug"}, subsystems)
+	// logger must let through the most verbose of the levels
+	assert.Equal(t, log.DebugLevel, log.GetLevel())
+
+	// empty global level leaves it unchanged
+	assert.Nil(t, SetLevels("", map[string]string{"sunrpc": "error"}))
+	global, subsystems = Levels()
+	assert.Equal(t, "warning", global)
+	assert.Equal(t, "error", subsystems["sunrpc"])
+
+	// empty subsystem level resets it to the global level
+	assert.Nil(t, SetLevels("", map[string]string{"store": ""}))
+	_, subsystems = Levels()
+	assert.NotContains(t, subsystems, "store")
+	assert.Equal(t, log.WarnLevel, log.GetLevel())
+
+	assert.NotNil(t, SetLevels("verbose", nil))
+	assert.NotNil(t, SetLevels("", map[string]string{"unknown": "debug"}))
+	assert.NotNil(t, SetLevels("", map[string]string{"store": "verbose"}))
+}
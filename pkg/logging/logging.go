@@ -73,7 +73,11 @@ func Init(logdir string, logFileName string, logLevel string, verboseLogEntry bo
 		log.AddHook(SourceLocationHook{})
 	}
 
+	logFile.Lock()
+	defer logFile.Unlock()
+
 	// Close the previously opened Log file
+	logFile.path = ""
 	if logWriter != nil {
 		logWriter.Close()
 		logWriter = nil
@@ -85,8 +89,8 @@ func Init(logdir string, logFileName string, logLevel string, verboseLogEntry bo
 		log.WithError(err).Debug("Failed to parse log level")
 		return err
 	}
-	log.SetLevel(l)
-	log.SetFormatter(utcFormatter{&log.TextFormatter{FullTimestamp: true, TimestampFormat: timestampFormat}})
+	setGlobalLevel(l)
+	log.SetFormatter(levelFilter{utcFormatter{&log.TextFormatter{FullTimestamp: true, TimestampFormat: timestampFormat}}})
 
 	if strings.ToLower(logFileName) == "stderr" || logFileName == "-" {
 		setLogOutput(os.Stderr)
@@ -94,14 +98,15 @@ func Init(logdir string, logFileName string, logLevel string, verboseLogEntry bo
 		setLogOutput(os.Stdout)
 	} else {
 		logFilePath := path.Join(logdir, logFileName)
-		logFile, err := openLogFile(logFilePath)
+		f, err := openLogFile(logFilePath)
 		if err != nil {
 			setLogOutput(os.Stderr)
 			log.WithError(err).Debug("Failed to open log file ", logFilePath)
 			return err
 		}
-		setLogOutput(logFile)
-		logWriter = logFile
+		setLogOutput(f)
+		logWriter = f
+		logFile.path = logFilePath
 	}
 	return nil
 }
@@ -0,0 +1,198 @@
+package logging
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// RotateOptions controls how a log file is rotated
+type RotateOptions struct {
+	// MaxFiles is the number of rotated files retained. The oldest rotated
+	// file is removed when it is exceeded.
+	MaxFiles int
+	// Compress gzips the rotated files
+	Compress bool
+	// CopyTruncate copies the log file and truncates it instead of
+	// renaming it. It is meant for logs of processes which keep the log
+	// file open and cannot be asked to reopen it.
+	CopyTruncate bool
+}
+
+const gzipSuffix = ".gz"
+
+// logFile is the path of the log file opened by Init, empty if logging to
+// stderr or stdout
+var logFile struct {
+	sync.Mutex
+	path string
+}
+
+// rotatedName returns the name of the nth rotated file of path
+func rotatedName(path string, n int, compressed bool) string {
+	name := fmt.Sprintf("%s.%d", path, n)
+	if compressed {
+		name += gzipSuffix
+	}
+	return name
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// shiftRotated renames path.N to path.N+1, for N from MaxFiles-1 down to 1,
+// removing the rotated files beyond MaxFiles. Rotated files are shifted
+// whether compressed or not, so that toggling compression does not leave
+// stale files behind.
+func shiftRotated(path string, maxFiles int) error {
+	for _, compressed := range []bool{false, true} {
+		name := rotatedName(path, maxFiles, compressed)
+		if err := os.Remove(name); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	for n := maxFiles - 1; n >= 1; n-- {
+		for _, compressed := range []bool{false, true} {
+			name := rotatedName(path, n, compressed)
+			if !exists(name) {
+				continue
+			}
+			if err := os.Rename(name, rotatedName(path, n+1, compressed)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// copyFile copies the contents of src to a new file dst, gzipping them if
+// compress is set
+func copyFile(src, dst string, compress bool) (err error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := out.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			os.Remove(dst)
+		}
+	}()
+
+	var w io.Writer = out
+	if compress {
+		zw := gzip.NewWriter(out)
+		defer func() {
+			if cerr := zw.Close(); err == nil {
+				err = cerr
+			}
+		}()
+		w = zw
+	}
+
+	_, err = io.Copy(w, in)
+	return err
+}
+
+// RotateFile rotates the log file at path. The file is moved to path.1, and
+// the previously rotated files are shifted, retaining at most MaxFiles of
+// them. Rotating a file which does not exist or is empty is a no-op.
+func RotateFile(path string, opts RotateOptions) error {
+	return rotateFile(path, opts, nil)
+}
+
+// rotateFile rotates the log file at path, calling reopen after the file
+// has been moved aside and before it is compressed
+func rotateFile(path string, opts RotateOptions, reopen func() error) error {
+	if opts.MaxFiles < 1 {
+		return errors.New("at least one rotated log file must be retained")
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if info.Size() == 0 {
+		return nil
+	}
+
+	if err := shiftRotated(path, opts.MaxFiles); err != nil {
+		return err
+	}
+
+	if opts.CopyTruncate {
+		if err := copyFile(path, rotatedName(path, 1, opts.Compress), opts.Compress); err != nil {
+			return err
+		}
+		return os.Truncate(path, 0)
+	}
+
+	rotated := rotatedName(path, 1, false)
+	if err := os.Rename(path, rotated); err != nil {
+		return err
+	}
+	if reopen != nil {
+		if err := reopen(); err != nil {
+			return err
+		}
+	}
+	if !opts.Compress {
+		return nil
+	}
+	if err := copyFile(rotated, rotatedName(path, 1, true), true); err != nil {
+		return err
+	}
+	return os.Remove(rotated)
+}
+
+// LogFile returns the path of the log file opened by Init. It returns an
+// empty string if logging to stderr or stdout.
+func LogFile() string {
+	logFile.Lock()
+	defer logFile.Unlock()
+	return logFile.path
+}
+
+// Rotate rotates the log file opened by Init and reopens it. It is a no-op
+// when logging to stderr or stdout.
+func Rotate(opts RotateOptions) error {
+	logFile.Lock()
+	defer logFile.Unlock()
+
+	if logFile.path == "" {
+		return nil
+	}
+
+	// The file is renamed before it is reopened, so the entries logged in
+	// between land in the rotated file and are not lost
+	opts.CopyTruncate = false
+	return rotateFile(logFile.path, opts, func() error {
+		f, err := openLogFile(logFile.path)
+		if err != nil {
+			return err
+		}
+		setLogOutput(f)
+		if logWriter != nil {
+			logWriter.Close()
+		}
+		logWriter = f
+		return nil
+	})
+}
@@ -0,0 +1,63 @@
+package logging
+
+import (
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func readGzip(t *testing.T, path string) string {
+	f, err := os.Open(path)
+	assert.Nil(t, err)
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	assert.Nil(t, err)
+	data, err := ioutil.ReadAll(zr)
+	assert.Nil(t, err)
+	return string(data)
+}
+
+func TestRotateFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rotate")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "test.log")
+	opts := RotateOptions{MaxFiles: 2}
+
+	// missing and empty files are not rotated
+	assert.Nil(t, RotateFile(path, opts))
+	assert.Nil(t, ioutil.WriteFile(path, nil, 0644))
+	assert.Nil(t, RotateFile(path, opts))
+	assert.False(t, exists(path+".1"))
+
+	for _, content := range []string{"one", "two", "three"} {
+		assert.Nil(t, ioutil.WriteFile(path, []byte(content), 0644))
+		assert.Nil(t, RotateFile(path, opts))
+	}
+	assert.False(t, exists(path))
+	data, _ := ioutil.ReadFile(path + ".1")
+	assert.Equal(t, "three", string(data))
+	data, _ = ioutil.ReadFile(path + ".2")
+	assert.Equal(t, "two", string(data))
+	assert.False(t, exists(path+".3"))
+
+	// compressed files are shifted along with uncompressed ones
+	opts.Compress = true
+	opts.CopyTruncate = true
+	assert.Nil(t, ioutil.WriteFile(path, []byte("four"), 0644))
+	assert.Nil(t, RotateFile(path, opts))
+	info, err := os.Stat(path)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), info.Size())
+	assert.Equal(t, "four", readGzip(t, path+".1.gz"))
+	data, _ = ioutil.ReadFile(path + ".2")
+	assert.Equal(t, "three", string(data))
+	assert.False(t, exists(path+".1"))
+
+	assert.NotNil(t, RotateFile(path, RotateOptions{}))
+}
@@ -0,0 +1,318 @@
+// Package openapi builds the OpenAPI document describing the REST API of
+// glusterd2, from its routes and the Go types of their requests and responses
+package openapi
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"path"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Version is the version of the OpenAPI specification of the documents
+const Version = "3.0.0"
+
+// Document is an OpenAPI document
+type Document struct {
+	OpenAPI    string                          `json:"openapi"`
+	Info       Info                            `json:"info"`
+	Paths      map[string]map[string]Operation `json:"paths"`
+	Components Components                      `json:"components"`
+	Security   []map[string][]string           `json:"security,omitempty"`
+}
+
+// Info describes the API
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// Components holds the schemas of the types referred to by the operations
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+// SecurityScheme describes how the requests are authenticated
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+}
+
+// Operation is an endpoint of the API
+type Operation struct {
+	OperationID string              `json:"operationId"`
+	Summary     string              `json:"summary,omitempty"`
+	Parameters  []Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]Response `json:"responses"`
+}
+
+// Parameter is a parameter in the path of an operation
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required"`
+	Schema   *Schema `json:"schema"`
+}
+
+// RequestBody is the body of the requests of an operation
+type RequestBody struct {
+	Content map[string]MediaType `json:"content"`
+}
+
+// Response is a response of an operation
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType gives the schema of a body
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Schema is the JSON schema of a type
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Minimum              *int               `json:"minimum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+}
+
+// Route is what the document needs to know of a route of the REST server
+type Route struct {
+	Name         string
+	Description  string
+	Method       string
+	Path         string
+	RequestType  reflect.Type
+	ResponseType reflect.Type
+}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+	// pathVarRe matches the variables of a mux route pattern, with their
+	// optional regexp
+	pathVarRe = regexp.MustCompile(`\{([^}:]+)(:[^}]*)?\}`)
+	// nonIDRe matches the characters not allowed in an operation ID
+	nonIDRe = regexp.MustCompile(`[^A-Za-z0-9]+`)
+)
+
+// builder builds the schemas of the types of a document
+type builder struct {
+	schemas map[string]*Schema
+	names   map[reflect.Type]string
+}
+
+// New returns the OpenAPI document of the routes, whose error responses have
+// a body of errorType
+func New(info Info, routes []Route, errorType reflect.Type) *Document {
+	b := &builder{
+		schemas: make(map[string]*Schema),
+		names:   make(map[reflect.Type]string),
+	}
+
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    info,
+		Paths:   make(map[string]map[string]Operation),
+		Components: Components{
+			Schemas: b.schemas,
+			SecuritySchemes: map[string]SecurityScheme{
+				"bearer": {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+			},
+		},
+		Security: []map[string][]string{{"bearer": {}}},
+	}
+
+	for _, r := range routes {
+		p, params := pathParams(r.Path)
+		op := Operation{
+			OperationID: OperationID(r.Name),
+			Summary:     r.Description,
+			Parameters:  params,
+			Responses:   map[string]Response{"2XX": {Description: "success"}},
+		}
+		if r.RequestType != nil {
+			op.RequestBody = &RequestBody{Content: jsonContent(b.schema(r.RequestType))}
+		}
+		if r.ResponseType != nil {
+			op.Responses["2XX"] = Response{Description: "success", Content: jsonContent(b.schema(r.ResponseType))}
+		}
+		if errorType != nil {
+			op.Responses["default"] = Response{Description: "error", Content: jsonContent(b.schema(errorType))}
+		}
+
+		if doc.Paths[p] == nil {
+			doc.Paths[p] = make(map[string]Operation)
+		}
+		doc.Paths[p][strings.ToLower(r.Method)] = op
+	}
+
+	return doc
+}
+
+// OperationID returns the ID of the operation of a route, its name in
+// CamelCase, such as VolumeCreate or ListEndpoints
+func OperationID(name string) string {
+	var id string
+	for _, w := range nonIDRe.Split(name, -1) {
+		if w != "" {
+			id += strings.ToUpper(w[:1]) + w[1:]
+		}
+	}
+	return id
+}
+
+func jsonContent(s *Schema) map[string]MediaType {
+	return map[string]MediaType{"application/json": {Schema: s}}
+}
+
+// pathParams returns the path of a mux route pattern without the regexps of
+// its variables, and the parameters of the variables
+func pathParams(pattern string) (string, []Parameter) {
+	var params []Parameter
+	for _, m := range pathVarRe.FindAllStringSubmatch(pattern, -1) {
+		params = append(params, Parameter{
+			Name:     m[1],
+			In:       "path",
+			Required: true,
+			Schema:   &Schema{Type: "string"},
+		})
+	}
+	return pathVarRe.ReplaceAllString(pattern, "{$1}"), params
+}
+
+// schema returns the schema of t. The structs are added to the components
+// of the document, and referred to.
+func (b *builder) schema(t reflect.Type) *Schema {
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t.Kind() != reflect.Struct && (t.Implements(jsonMarshalerType) || t.Implements(textMarshalerType)):
+		// The enums and the UUIDs are marshalled as strings
+		return &Schema{Type: "string"}
+	case t.Kind() == reflect.Struct && t.Implements(jsonMarshalerType):
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if t.Kind() == reflect.Int || t.Kind() == reflect.Int64 {
+			return &Schema{Type: "integer", Format: "int64"}
+		}
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		zero := 0
+		return &Schema{Type: "integer", Format: "int64", Minimum: &zero}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Ptr:
+		return b.schema(t.Elem())
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: b.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: b.schema(t.Elem())}
+	case reflect.Struct:
+		return &Schema{Ref: "#/components/schemas/" + b.structSchema(t)}
+	default:
+		// interfaces, accepting any value
+		return &Schema{}
+	}
+}
+
+// structSchema adds the schema of the struct to the components if needed,
+// and returns its name
+func (b *builder) structSchema(t reflect.Type) string {
+	if name, ok := b.names[t]; ok {
+		return name
+	}
+
+	name := b.structName(t)
+	b.names[t] = name
+
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	b.schemas[name] = s
+	b.addFields(s, t)
+	return name
+}
+
+// structName returns the name of the schema of the struct, as the type is
+// named in the routes. The structs of the api packages of the plugins
+// sharing the name of another struct are prefixed with their plugin.
+func (b *builder) structName(t reflect.Type) string {
+	name := t.String()
+	if t.Name() == "" {
+		name = "struct"
+	}
+	if _, taken := b.schemas[name]; !taken {
+		return name
+	}
+
+	pkg := path.Base(path.Dir(t.PkgPath())) + path.Base(t.PkgPath())
+	name = nonIDRe.ReplaceAllString(pkg, "") + "." + t.Name()
+	for i := 2; ; i++ {
+		if _, taken := b.schemas[name]; !taken {
+			return name
+		}
+		name = fmt.Sprintf("%s.%s%d", nonIDRe.ReplaceAllString(pkg, ""), t.Name(), i)
+	}
+}
+
+// addFields adds the fields of the struct to the properties of s, as they
+// are marshalled by encoding/json. The fields without omitempty are
+// required.
+func (b *builder) addFields(s *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts := tag, ""
+		if i := strings.Index(tag, ","); i >= 0 {
+			name, opts = tag[:i], tag[i+1:]
+		}
+
+		ft := f.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			b.addFields(s, ft)
+			continue
+		}
+		if f.PkgPath != "" {
+			// unexported
+			continue
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+		s.Properties[name] = b.schema(f.Type)
+		if !strings.Contains(opts, "omitempty") {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testState int
+
+func (s testState) MarshalJSON() ([]byte, error) {
+	return json.Marshal("started")
+}
+
+type testBase struct {
+	ID uuid.UUID `json:"id"`
+}
+
+type testReq struct {
+	testBase
+	Name     string            `json:"name"`
+	Size     uint64            `json:"size,omitempty"`
+	Tags     []string          `json:"tags,omitempty"`
+	Options  map[string]string `json:"options,omitempty"`
+	State    testState         `json:"state"`
+	Created  time.Time         `json:"created"`
+	Children []*testReq        `json:"children,omitempty"`
+	Ignored  string            `json:"-"`
+	internal string
+}
+
+type testErr struct {
+	Message string `json:"message"`
+}
+
+func TestNew(t *testing.T) {
+	routes := []Route{
+		{
+			Name:         "Test Create",
+			Method:       "POST",
+			Path:         "/v1/tests/{name}/{path:.*}",
+			RequestType:  reflect.TypeOf(testReq{}),
+			ResponseType: reflect.TypeOf([]testReq{}),
+		},
+		{Name: "test delete", Method: "DELETE", Path: "/v1/tests/{name}/{path:.*}"},
+	}
+	doc := New(Info{Title: "test", Version: "1"}, routes, reflect.TypeOf(testErr{}))
+
+	require.Len(t, doc.Paths, 1)
+	ops := doc.Paths["/v1/tests/{name}/{path}"]
+	require.Len(t, ops, 2)
+
+	op := ops["post"]
+	assert.Equal(t, "TestCreate", op.OperationID)
+	require.Len(t, op.Parameters, 2)
+	assert.Equal(t, "name", op.Parameters[0].Name)
+	assert.Equal(t, "path", op.Parameters[1].Name)
+	assert.Equal(t, "#/components/schemas/openapi.testReq", op.RequestBody.Content["application/json"].Schema.Ref)
+	resp := op.Responses["2XX"].Content["application/json"].Schema
+	assert.Equal(t, "array", resp.Type)
+	assert.Equal(t, "#/components/schemas/openapi.testReq", resp.Items.Ref)
+	assert.Equal(t, "#/components/schemas/openapi.testErr", op.Responses["default"].Content["application/json"].Schema.Ref)
+
+	assert.Equal(t, "TestDelete", ops["delete"].OperationID)
+	assert.Nil(t, ops["delete"].RequestBody)
+	assert.Empty(t, ops["delete"].Responses["2XX"].Content)
+
+	s := doc.Components.Schemas["openapi.testReq"]
+	require.NotNil(t, s)
+	assert.Len(t, s.Properties, 8)
+	assert.Equal(t, "string", s.Properties["id"].Type)
+	assert.Equal(t, "integer", s.Properties["size"].Type)
+	assert.Equal(t, "string", s.Properties["tags"].Items.Type)
+	assert.Equal(t, "string", s.Properties["options"].AdditionalProperties.Type)
+	assert.Equal(t, "string", s.Properties["state"].Type)
+	assert.Equal(t, "date-time", s.Properties["created"].Format)
+	assert.Equal(t, "#/components/schemas/openapi.testReq", s.Properties["children"].Items.Ref)
+	assert.Equal(t, []string{"id", "name", "state", "created"}, s.Required)
+}
@@ -36,3 +36,12 @@ func (c *Client) BlockVolumeDelete(provider string, blockVolname string) error {
 	url := fmt.Sprintf("/v1/blockvolumes/%s/%s", provider, blockVolname)
 	return c.del(url, nil, http.StatusNoContent, nil)
 }
+
+// BlockVolumeResize grows a Gluster Block Volume to size bytes
+func (c *Client) BlockVolumeResize(provider string, blockVolname string, size uint64) (api.BlockVolumeGetResp, error) {
+	var vol api.BlockVolumeGetResp
+	req := api.BlockVolumeResizeReq{Size: size}
+	url := fmt.Sprintf("/v1/blockvolumes/%s/%s/resize", provider, blockVolname)
+	err := c.post(url, req, http.StatusOK, &vol)
+	return vol, err
+}
@@ -2,11 +2,15 @@ package restclient
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/gluster/glusterd2/pkg/api"
@@ -18,6 +22,7 @@ import (
 const (
 	expireSeconds        = 120
 	defaultClientTimeout = 30 // in seconds
+	maxRetryBackoff      = 30 * time.Second
 )
 
 // ClientFunc receives a Client and overrides its members
@@ -80,6 +85,34 @@ func WithTimeOut(timeout time.Duration) ClientFunc {
 	}
 }
 
+// WithSecretFile overrides Client password with the secret read from the
+// file, such as the auth file of glusterd2
+func WithSecretFile(path string) ClientFunc {
+	return func(client *Client) error {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read secret file %s, err: %s", path, err.Error())
+		}
+		client.password = strings.TrimSpace(string(data))
+		return nil
+	}
+}
+
+// WithRetries retries a failed request up to attempts times, waiting backoff
+// before the first retry and twice as long before each next one, up to 30
+// seconds. Only the requests that are safe to send again are retried: the
+// requests which could not be sent as glusterd2 could not be reached, the
+// requests failing because a lock could not be obtained in time, which were
+// not applied, and the GET, PUT and DELETE requests failing with 502, 503 or
+// 504.
+func WithRetries(attempts int, backoff time.Duration) ClientFunc {
+	return func(client *Client) error {
+		client.retries = attempts
+		client.retryBackoff = backoff
+		return nil
+	}
+}
+
 // WithDebugRoundTripper wraps a debug middleware to http Transport.
 func WithDebugRoundTripper() ClientFunc {
 	return func(client *Client) error {
@@ -90,12 +123,17 @@ func WithDebugRoundTripper() ClientFunc {
 
 // Client represents Glusterd2 REST Client
 type Client struct {
-	baseURL     string
-	username    string
-	password    string
-	timeout     time.Duration
-	httpClient  *http.Client
-	lastRespErr *http.Response
+	baseURL      string
+	username     string
+	password     string
+	timeout      time.Duration
+	retries      int
+	retryBackoff time.Duration
+	ctx          context.Context
+	targetPeer   string
+	idemKey      string
+	httpClient   *http.Client
+	lastRespErr  *http.Response
 }
 
 // NewClientWithOpts initializes a default Glusterd2 REST Client.
@@ -146,6 +184,42 @@ func (c *Client) LastErrorResponse() *http.Response {
 	return c.lastRespErr
 }
 
+// WithContext returns a copy of the client whose requests are sent with ctx,
+// and are given up on when ctx is done. The copy shares the connections of
+// the client, but has its own LastErrorResponse.
+// For e.g., `client.WithContext(ctx).VolumeStatus(volname)`
+func (c *Client) WithContext(ctx context.Context) *Client {
+	client := *c
+	client.ctx = ctx
+	client.lastRespErr = nil
+	return &client
+}
+
+// WithTargetPeer returns a copy of the client whose requests for the local
+// state of a peer, such as its statedump, are for the peer named by its ID or
+// name, whichever peer they are sent to. The copy shares the connections of
+// the client, but has its own LastErrorResponse.
+// For e.g., `client.WithTargetPeer("node2").DaemonStatedump()`
+func (c *Client) WithTargetPeer(peer string) *Client {
+	client := *c
+	client.targetPeer = peer
+	client.lastRespErr = nil
+	return &client
+}
+
+// WithIdempotencyKey returns a copy of the client whose requests carry the
+// given Idempotency-Key. A request changing the cluster is then run once by
+// glusterd2, and its retries are answered with its response, so that they
+// are retried on timeouts and 502, 503 and 504 as the GET requests are. The
+// key is to be used for a single change.
+// For e.g., `client.WithIdempotencyKey(uuid.NewRandom().String()).VolumeCreate(req)`
+func (c *Client) WithIdempotencyKey(key string) *Client {
+	client := *c
+	client.idemKey = key
+	client.lastRespErr = nil
+	return &client
+}
+
 // SetTimeout sets the overall client timeout which includes the time taken
 // from setting up TCP connection till client finishes reading the response
 // body.
@@ -181,6 +255,10 @@ func (c *Client) put(url string, data interface{}, expectStatusCode int, output
 	return c.do("PUT", url, data, expectStatusCode, output)
 }
 
+func (c *Client) patch(url string, data interface{}, expectStatusCode int, output interface{}) error {
+	return c.do("PATCH", url, data, expectStatusCode, output)
+}
+
 func (c *Client) get(url string, data interface{}, expectStatusCode int, output interface{}) error {
 	return c.do("GET", url, data, expectStatusCode, output)
 }
@@ -190,10 +268,37 @@ func (c *Client) del(url string, data interface{}, expectStatusCode int, output
 }
 
 func (c *Client) do(method string, url string, input interface{}, expectStatusCode int, output interface{}) error {
+	return c.doWithHeader(method, url, nil, input, output, expectStatusCode)
+}
+
+// doWithHeader sends the request with the additional header, retrying it as
+// configured with WithRetries. Any of expectStatusCodes is a success.
+func (c *Client) doWithHeader(method string, url string, header http.Header, input interface{}, output interface{}, expectStatusCodes ...int) error {
+	backoff := c.retryBackoff
+	for attempt := 0; ; attempt++ {
+		err := c.send(method, url, header, input, output, expectStatusCodes)
+		if err == nil || attempt >= c.retries || !retryable(method, c.idemKey != "", err) {
+			return err
+		}
+
+		if backoff > maxRetryBackoff {
+			backoff = maxRetryBackoff
+		}
+		if err := c.sleep(backoff); err != nil {
+			return err
+		}
+		backoff *= 2
+	}
+}
+
+func (c *Client) send(method string, url string, header http.Header, input interface{}, output interface{}, expectStatusCodes []int) error {
 	req, err := c.buildRequest(method, url, input)
 	if err != nil {
 		return err
 	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -201,7 +306,7 @@ func (c *Client) do(method string, url string, input interface{}, expectStatusCo
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != expectStatusCode {
+	if !expectedStatus(resp.StatusCode, expectStatusCodes) {
 		// FIXME: We should may be rather look for 4xx or 5xx series
 		// to determine that we got an error response instead of
 		// comparing to what's expected ?
@@ -223,6 +328,70 @@ func (c *Client) do(method string, url string, input interface{}, expectStatusCo
 	return nil
 }
 
+func expectedStatus(statusCode int, expectStatusCodes []int) bool {
+	for _, code := range expectStatusCodes {
+		if statusCode == code {
+			return true
+		}
+	}
+	return false
+}
+
+// sleep waits for d, or for the context of the client to be done
+func (c *Client) sleep(d time.Duration) error {
+	if c.ctx == nil {
+		time.Sleep(d)
+		return nil
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-c.ctx.Done():
+		return c.ctx.Err()
+	}
+}
+
+// retryable returns true if the request failed with err can be sent again
+// without being applied twice. The requests with an Idempotency-Key are
+// retried as the idempotent requests, and also while the request they retry
+// is in progress. See WithRetries.
+func retryable(method string, keyed bool, err error) bool {
+	idempotent := keyed || method == http.MethodGet || method == http.MethodPut || method == http.MethodDelete
+
+	var status int
+	switch e := err.(type) {
+	case *APIError:
+		if e.HasReason(api.ReasonLockTimeout) {
+			return true
+		}
+		if e.HasReason(api.ReasonIdempotencyKeyInUse) {
+			return keyed
+		}
+		status = e.Status
+	case *HTTPErrorResponse:
+		status = e.Status
+	case *url.Error:
+		if e.Timeout() {
+			return idempotent
+		}
+		if op, ok := e.Err.(*net.OpError); ok {
+			return op.Op == "dial" || idempotent
+		}
+		return false
+	default:
+		return false
+	}
+
+	switch status {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return idempotent
+	}
+	return false
+}
+
 func (c *Client) buildRequest(method string, url string, input interface{}) (*http.Request, error) {
 	url = fmt.Sprintf("%s%s", c.baseURL, url)
 	var body io.Reader
@@ -238,8 +407,17 @@ func (c *Client) buildRequest(method string, url string, input interface{}) (*ht
 	if err != nil {
 		return nil, err
 	}
+	if c.ctx != nil {
+		req = req.WithContext(c.ctx)
+	}
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("Content-Type", "application/json")
+	if c.targetPeer != "" {
+		req.Header.Set(api.TargetPeerHeader, c.targetPeer)
+	}
+	if c.idemKey != "" {
+		req.Header.Set(api.IdempotencyKeyHeader, c.idemKey)
+	}
 	req.Close = true
 
 	// Set Authorization if username and password is not empty string
@@ -1,6 +1,7 @@
 package restclient
 
 import (
+	"context"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
@@ -9,10 +10,13 @@ import (
 	"io/ioutil"
 	"math/big"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 	"time"
 
+	"github.com/gluster/glusterd2/pkg/api"
+
 	"github.com/stretchr/testify/require"
 )
 
@@ -90,3 +94,90 @@ func generateCert() ([]byte, error) {
 	pub := &priv.PublicKey
 	return x509.CreateCertificate(rand.Reader, ca, ca, pub, priv)
 }
+
+func TestRetries(t *testing.T) {
+	r := require.New(t)
+
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		calls++
+		if calls < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client, err := NewClientWithOpts(WithBaseURL(server.URL), WithRetries(3, time.Millisecond))
+	r.Nil(err)
+
+	// GET requests are retried on 503
+	r.Nil(client.Ping())
+	r.Equal(3, calls)
+
+	// POST requests are not
+	calls = 0
+	err = client.post("/ping", nil, http.StatusOK, nil)
+	r.NotNil(err)
+	r.Equal(1, calls)
+
+	// The requests failing to obtain a lock are
+	calls = 0
+	lockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(`{"errors": [{"code": 1, "message": "timed out", "reason": "LOCK_TIMEOUT"}]}`))
+	}))
+	defer lockServer.Close()
+
+	client, err = NewClientWithOpts(WithBaseURL(lockServer.URL), WithRetries(2, time.Millisecond))
+	r.Nil(err)
+	err = client.post("/ping", nil, http.StatusOK, nil)
+	r.Equal(api.ReasonLockTimeout, ErrorReason(err))
+	r.Equal(3, calls)
+
+	// The requests with an Idempotency-Key are retried on 503, and while
+	// the request they retry is in progress
+	calls = 0
+	var keys []string
+	keyServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		calls++
+		keys = append(keys, req.Header.Get(api.IdempotencyKeyHeader))
+		switch calls {
+		case 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case 2:
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(`{"errors": [{"code": 1, "message": "in progress", "reason": "IDEMPOTENCY_KEY_IN_USE"}]}`))
+		default:
+			w.WriteHeader(http.StatusCreated)
+		}
+	}))
+	defer keyServer.Close()
+
+	client, err = NewClientWithOpts(WithBaseURL(keyServer.URL), WithRetries(3, time.Millisecond))
+	r.Nil(err)
+	r.Nil(client.WithIdempotencyKey("k1").post("/v1/volumes", nil, http.StatusCreated, nil))
+	r.Equal(3, calls)
+	r.Equal([]string{"k1", "k1", "k1"}, keys)
+}
+
+func TestWithContext(t *testing.T) {
+	r := require.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client, err := NewClientWithOpts(WithBaseURL(server.URL), WithRetries(5, time.Hour))
+	r.Nil(err)
+
+	// The retries are given up on when the context is done
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err = client.WithContext(ctx).Ping()
+	r.Equal(context.DeadlineExceeded, err)
+	r.Nil(client.LastErrorResponse())
+}
@@ -0,0 +1,68 @@
+package restclient
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/gluster/glusterd2/pkg/api"
+)
+
+// ConfigSnapshotList lists the configuration snapshots, oldest first
+func (c *Client) ConfigSnapshotList() (api.ConfigSnapshotListResp, error) {
+	var resp api.ConfigSnapshotListResp
+	err := c.get("/v1/cluster/config-snapshots", nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// ConfigSnapshotTake takes a snapshot of the configuration of the cluster
+func (c *Client) ConfigSnapshotTake() (api.ConfigSnapshotResp, error) {
+	var resp api.ConfigSnapshotResp
+	err := c.post("/v1/cluster/config-snapshots", nil, http.StatusCreated, &resp)
+	return resp, err
+}
+
+// ConfigSnapshotGet returns the configuration snapshot with its content
+func (c *Client) ConfigSnapshotGet(id int64) (api.ConfigSnapshotResp, error) {
+	var resp api.ConfigSnapshotResp
+	url := fmt.Sprintf("/v1/cluster/config-snapshots/%d", id)
+	err := c.get(url, nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// ConfigSnapshotDelete deletes the configuration snapshot
+func (c *Client) ConfigSnapshotDelete(id int64) error {
+	url := fmt.Sprintf("/v1/cluster/config-snapshots/%d", id)
+	return c.del(url, nil, http.StatusNoContent, nil)
+}
+
+// ConfigSnapshotDiff compares the configuration snapshot with another one,
+// or with the current configuration if to is 0. Only the paths with the
+// prefix are compared, if given.
+func (c *Client) ConfigSnapshotDiff(id, to int64, prefix string) (api.ConfigDiffResp, error) {
+	params := url.Values{}
+	if to != 0 {
+		params.Set("to", strconv.FormatInt(to, 10))
+	}
+	if prefix != "" {
+		params.Set("prefix", prefix)
+	}
+	path := fmt.Sprintf("/v1/cluster/config-snapshots/%d/diff", id)
+	if len(params) != 0 {
+		path += "?" + params.Encode()
+	}
+
+	var resp api.ConfigDiffResp
+	err := c.get(path, nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// VolumeConfigRestore restores the options of the volume from a
+// configuration snapshot
+func (c *Client) VolumeConfigRestore(volname string, snapshot int64) (api.VolumeOptionResp, error) {
+	var resp api.VolumeOptionResp
+	url := fmt.Sprintf("/v1/volumes/%s/config/restore", volname)
+	err := c.post(url, api.VolumeConfigRestoreReq{Snapshot: snapshot}, http.StatusOK, &resp)
+	return resp, err
+}
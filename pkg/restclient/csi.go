@@ -0,0 +1,55 @@
+package restclient
+
+import (
+	"fmt"
+	"net/http"
+
+	csiapi "github.com/gluster/glusterd2/plugins/csi/api"
+)
+
+// idempotencyKeyHeader is the header carrying the idempotency key of the
+// CSI requests
+const idempotencyKeyHeader = "Idempotency-Key"
+
+func idempotencyHeader(key string) http.Header {
+	if key == "" {
+		return nil
+	}
+	return http.Header{idempotencyKeyHeader: []string{key}}
+}
+
+// CSIVolumeCreate provisions a volume, or returns the existing volume if it
+// satisfies the request. The retries of a request should carry the same
+// idempotencyKey, which may be empty.
+func (c *Client) CSIVolumeCreate(req csiapi.VolumeCreateReq, idempotencyKey string) (csiapi.Volume, error) {
+	var vol csiapi.Volume
+	err := c.doWithHeader("POST", "/v1/csi/volumes", idempotencyHeader(idempotencyKey),
+		req, &vol, http.StatusCreated, http.StatusOK)
+	return vol, err
+}
+
+// CSIVolumeGet returns a volume provisioned with CSIVolumeCreate
+func (c *Client) CSIVolumeGet(volname string) (csiapi.Volume, error) {
+	var vol csiapi.Volume
+	err := c.get("/v1/csi/volumes/"+volname, nil, http.StatusOK, &vol)
+	return vol, err
+}
+
+// CSIVolumeExpand grows a volume provisioned with CSIVolumeCreate by
+// sizeDelta bytes. The retries of a request must carry the same
+// idempotencyKey, or the volume is grown again.
+func (c *Client) CSIVolumeExpand(volname string, sizeDelta uint64, idempotencyKey string) (csiapi.Volume, error) {
+	var vol csiapi.Volume
+	req := csiapi.VolumeExpandReq{SizeDelta: sizeDelta}
+	url := fmt.Sprintf("/v1/csi/volumes/%s/expand", volname)
+	err := c.doWithHeader("POST", url, idempotencyHeader(idempotencyKey), req, &vol, http.StatusOK)
+	return vol, err
+}
+
+// CSIVolumeMountInfo returns what a node needs to mount a volume
+func (c *Client) CSIVolumeMountInfo(volname string) (csiapi.MountInfo, error) {
+	var info csiapi.MountInfo
+	url := fmt.Sprintf("/v1/csi/volumes/%s/mount", volname)
+	err := c.get(url, nil, http.StatusOK, &info)
+	return info, err
+}
@@ -0,0 +1,35 @@
+package restclient
+
+import (
+	"net/http"
+	"path"
+
+	"github.com/gluster/glusterd2/pkg/api"
+)
+
+// CustomXlators returns the custom xlators registered by plugins and users
+func (c *Client) CustomXlators() (api.CustomXlatorListResp, error) {
+	var resp api.CustomXlatorListResp
+	err := c.get("/v1/custom-xlators", nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// CustomXlator returns the custom xlator with the given ID
+func (c *Client) CustomXlator(id string) (api.CustomXlatorResp, error) {
+	var resp api.CustomXlatorResp
+	err := c.get("/v1/custom-xlators/"+id, nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// CustomXlatorSet registers a custom xlator, or updates the one registered
+// with the same ID
+func (c *Client) CustomXlatorSet(req api.CustomXlatorReq) (api.CustomXlatorResp, error) {
+	var resp api.CustomXlatorResp
+	err := c.put("/v1/custom-xlators/"+path.Base(req.Type), req, http.StatusOK, &resp)
+	return resp, err
+}
+
+// CustomXlatorDelete deletes the custom xlator registered by a user
+func (c *Client) CustomXlatorDelete(id string) error {
+	return c.del("/v1/custom-xlators/"+id, nil, http.StatusNoContent, nil)
+}
@@ -0,0 +1,70 @@
+package restclient
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/pkg/api"
+)
+
+// DebugSettings returns the state of the debug endpoints
+func (c *Client) DebugSettings() (api.DebugSettings, error) {
+	var resp api.DebugSettings
+	err := c.get("/debug", nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// DebugSet enables or disables the debug endpoints
+func (c *Client) DebugSet(req api.DebugSettings) (api.DebugSettings, error) {
+	var resp api.DebugSettings
+	err := c.put("/debug", req, http.StatusOK, &resp)
+	return resp, err
+}
+
+// SunRPCClients returns the clients connected to the SunRPC server of the
+// node, along with the results of the notifications sent to them
+func (c *Client) SunRPCClients() (api.SunRPCClientsResp, error) {
+	var resp api.SunRPCClientsResp
+	err := c.get("/debug/sunrpc-clients", nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// DaemonStatedump returns the statedump of glusterd2 on the node, with its
+// internal state
+func (c *Client) DaemonStatedump() (api.DaemonStatedumpResp, error) {
+	var resp api.DaemonStatedumpResp
+	err := c.get("/v1/daemon/statedump", nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// Daemons returns the daemons managed by glusterd2 on the node, with their
+// supervision state
+func (c *Client) Daemons() (api.DaemonListResp, error) {
+	var resp api.DaemonListResp
+	err := c.get("/v1/daemons", nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// Faults returns the faults injected on the node
+func (c *Client) Faults() (api.FaultListResp, error) {
+	var resp api.FaultListResp
+	err := c.get("/debug/faults", nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// FaultAdd injects a fault on the node. The faults of another peer are set
+// with WithTargetPeer.
+func (c *Client) FaultAdd(req api.FaultReq) (api.Fault, error) {
+	var resp api.Fault
+	err := c.post("/debug/faults", req, http.StatusCreated, &resp)
+	return resp, err
+}
+
+// FaultRemove removes the fault of the given ID injected on the node
+func (c *Client) FaultRemove(id string) error {
+	return c.del("/debug/faults/"+id, nil, http.StatusNoContent, nil)
+}
+
+// FaultsClear removes all the faults injected on the node
+func (c *Client) FaultsClear() error {
+	return c.del("/debug/faults", nil, http.StatusNoContent, nil)
+}
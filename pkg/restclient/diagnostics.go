@@ -0,0 +1,15 @@
+package restclient
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/pkg/api"
+)
+
+// Diagnostics runs the diagnostic checks on the cluster and returns the
+// problems found, along with the state of glusterd2 on the peers
+func (c *Client) Diagnostics() (api.DiagnosticsResp, error) {
+	var resp api.DiagnosticsResp
+	err := c.get("/v1/diagnostics", nil, http.StatusOK, &resp)
+	return resp, err
+}
@@ -0,0 +1,23 @@
+package restclient
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/pkg/api"
+)
+
+// Drift lists the drifts of the volumes from their state in the store,
+// detected on the peers
+func (c *Client) Drift() (api.DriftListResp, error) {
+	var resp api.DriftListResp
+	err := c.get("/v1/cluster/drift", nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// DriftReconcile corrects the drifts of the volumes detected on the peers
+// which are up, and returns the drifts corrected
+func (c *Client) DriftReconcile() (api.DriftReconcileResp, error) {
+	var resp api.DriftReconcileResp
+	err := c.post("/v1/cluster/drift/reconcile", nil, http.StatusOK, &resp)
+	return resp, err
+}
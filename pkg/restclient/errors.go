@@ -22,6 +22,38 @@ func (e *HTTPErrorResponse) Error() string {
 	return fmt.Sprintf("Request failed. Status: %d\nResponse: %s", e.Status, e.Body)
 }
 
+// APIError is the error returned for the error responses of glusterd2. The
+// Errors have the machine-readable reasons of the errors, for the callers to
+// tell them apart without parsing the message.
+type APIError struct {
+	Status int
+	Errors []api.HTTPError
+	msg    string
+}
+
+func (e *APIError) Error() string {
+	return e.msg
+}
+
+// HasReason returns true if any of the errors of the response has reason
+func (e *APIError) HasReason(reason api.ErrorReason) bool {
+	for _, apiErr := range e.Errors {
+		if apiErr.Reason == reason {
+			return true
+		}
+	}
+	return false
+}
+
+// ErrorReason returns the reason of the first error of the response if err
+// is an *APIError, or an empty reason otherwise
+func ErrorReason(err error) api.ErrorReason {
+	if e, ok := err.(*APIError); ok && len(e.Errors) > 0 {
+		return e.Errors[0].Reason
+	}
+	return ""
+}
+
 func newHTTPErrorResponse(resp *http.Response) error {
 
 	b, err := ioutil.ReadAll(resp.Body)
@@ -31,7 +63,13 @@ func newHTTPErrorResponse(resp *http.Response) error {
 
 	var errResp api.ErrorResp
 	if err = json.Unmarshal(b, &errResp); err != nil {
-		return err
+		// The response did not come from glusterd2, but from a proxy
+		// in front of it
+		return &HTTPErrorResponse{
+			Status:  resp.StatusCode,
+			Body:    string(b),
+			Headers: resp.Header,
+		}
 	}
 
 	var buffer bytes.Buffer
@@ -47,5 +85,9 @@ func newHTTPErrorResponse(resp *http.Response) error {
 		}
 	}
 
-	return fmt.Errorf("%s", buffer.String())
+	return &APIError{
+		Status: resp.StatusCode,
+		Errors: errResp.Errors,
+		msg:    buffer.String(),
+	}
 }
@@ -0,0 +1,40 @@
+package restclient
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewHTTPErrorResponse(t *testing.T) {
+	r := require.New(t)
+
+	body := `{"errors": [
+		{"code": 1, "message": "volume not found", "reason": "VOLUME_NOT_FOUND"},
+		{"code": 2, "message": "a txn step failed", "reason": "PEER_UNREACHABLE",
+		 "fields": {"step": "vol-start.StartBricks", "peer-id": "peer1", "error": "peer unreachable"}}
+	]}`
+	resp := &http.Response{
+		StatusCode: http.StatusNotFound,
+		Body:       ioutil.NopCloser(bytes.NewBufferString(body)),
+	}
+
+	err := newHTTPErrorResponse(resp)
+	r.Error(err)
+	r.Contains(err.Error(), "volume not found")
+	r.Contains(err.Error(), "Transaction step vol-start.StartBricks failed on peer peer1")
+
+	apiErr, ok := err.(*APIError)
+	r.True(ok)
+	r.Equal(http.StatusNotFound, apiErr.Status)
+	r.True(apiErr.HasReason(api.ReasonPeerUnreachable))
+	r.False(apiErr.HasReason(api.ReasonQuorumLost))
+	r.Equal(api.ReasonVolumeNotFound, ErrorReason(err))
+
+	r.Empty(ErrorReason(http.ErrHandlerTimeout))
+}
@@ -49,3 +49,70 @@ func (c *Client) WebhookTest(url string, token string, secret string) error {
 	}
 	return c.post("/v1/events/webhook/test", req, http.StatusOK, nil)
 }
+
+// SMTPSinkAdd registers a SMTP sink which mails Gluster Events
+func (c *Client) SMTPSinkAdd(req eventsapi.SMTPSink) error {
+	return c.post("/v1/events/smtp", req, http.StatusOK, nil)
+}
+
+// SMTPSinkDelete deletes the SMTP sink
+func (c *Client) SMTPSinkDelete(name string) error {
+	req := &eventsapi.SinkDel{
+		Name: name,
+	}
+	return c.del("/v1/events/smtp", req, http.StatusNoContent, nil)
+}
+
+// SMTPSinks returns the list of SMTP sinks
+func (c *Client) SMTPSinks() (eventsapi.SMTPSinkList, error) {
+	var resp eventsapi.SMTPSinkList
+	err := c.get("/v1/events/smtp", nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// SyslogSinkAdd registers a syslog sink which forwards Gluster Events
+func (c *Client) SyslogSinkAdd(req eventsapi.SyslogSink) error {
+	return c.post("/v1/events/syslog", req, http.StatusOK, nil)
+}
+
+// SyslogSinkDelete deletes the syslog sink
+func (c *Client) SyslogSinkDelete(name string) error {
+	req := &eventsapi.SinkDel{
+		Name: name,
+	}
+	return c.del("/v1/events/syslog", req, http.StatusNoContent, nil)
+}
+
+// SyslogSinks returns the list of syslog sinks
+func (c *Client) SyslogSinks() (eventsapi.SyslogSinkList, error) {
+	var resp eventsapi.SyslogSinkList
+	err := c.get("/v1/events/syslog", nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// ThresholdAdd adds an alerting threshold
+func (c *Client) ThresholdAdd(req eventsapi.Threshold) error {
+	return c.post("/v1/events/thresholds", req, http.StatusOK, nil)
+}
+
+// ThresholdDelete deletes the alerting threshold
+func (c *Client) ThresholdDelete(name string) error {
+	req := &eventsapi.ThresholdDel{
+		Name: name,
+	}
+	return c.del("/v1/events/thresholds", req, http.StatusNoContent, nil)
+}
+
+// Thresholds returns the list of alerting thresholds
+func (c *Client) Thresholds() (eventsapi.ThresholdList, error) {
+	var resp eventsapi.ThresholdList
+	err := c.get("/v1/events/thresholds", nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// Alerts returns the list of currently firing alerts
+func (c *Client) Alerts() (eventsapi.AlertList, error) {
+	var resp eventsapi.AlertList
+	err := c.get("/v1/alerts", nil, http.StatusOK, &resp)
+	return resp, err
+}
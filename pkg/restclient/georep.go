@@ -59,7 +59,9 @@ func (c *Client) GeorepDelete(mastervolid string, slavevolid string, force bool)
 	return err
 }
 
-// GeorepStatus gets status of Geo-replication sessions
+// GeorepStatus gets status of Geo-replication sessions. The status of a
+// session has the rollup of the statuses of its workers, the statuses of the
+// workers themselves are given by GeorepStatusDetail.
 func (c *Client) GeorepStatus(mastervolid string, slavevolid string) (georepapi.GeorepSessionList, error) {
 	url := "/v1/geo-replication"
 	allSessions := false
@@ -82,6 +84,15 @@ func (c *Client) GeorepStatus(mastervolid string, slavevolid string) (georepapi.
 	return sessions, err
 }
 
+// GeorepStatusDetail gets the status of a Geo-replication session along with
+// the status of each of its workers
+func (c *Client) GeorepStatusDetail(mastervolid string, slavevolid string) (georepapi.GeorepSession, error) {
+	url := fmt.Sprintf("/v1/geo-replication/%s/%s?detail=true", mastervolid, slavevolid)
+	var session georepapi.GeorepSession
+	err := c.get(url, nil, http.StatusOK, &session)
+	return session, err
+}
+
 // GeorepSSHKeysGenerate generates SSH keys in all Volume nodes
 func (c *Client) GeorepSSHKeysGenerate(volname string) ([]georepapi.GeorepSSHPublicKey, error) {
 	url := "/v1/ssh-key/" + volname + "/generate"
@@ -0,0 +1,32 @@
+package restclient
+
+import (
+	"fmt"
+	"net/http"
+
+	gfproxyapi "github.com/gluster/glusterd2/plugins/gfproxy/api"
+)
+
+// GfProxyStatus returns the gfproxy state of a volume
+func (c *Client) GfProxyStatus(volname string) (gfproxyapi.GfProxyStatus, error) {
+	var status gfproxyapi.GfProxyStatus
+	url := fmt.Sprintf("/v1/volumes/%s/gfproxy", volname)
+	err := c.get(url, nil, http.StatusOK, &status)
+	return status, err
+}
+
+// GfProxyEnable enables gfproxy on a volume
+func (c *Client) GfProxyEnable(volname string) (gfproxyapi.GfProxyStatus, error) {
+	var status gfproxyapi.GfProxyStatus
+	url := fmt.Sprintf("/v1/volumes/%s/gfproxy/enable", volname)
+	err := c.post(url, nil, http.StatusOK, &status)
+	return status, err
+}
+
+// GfProxyDisable disables gfproxy on a volume
+func (c *Client) GfProxyDisable(volname string) (gfproxyapi.GfProxyStatus, error) {
+	var status gfproxyapi.GfProxyStatus
+	url := fmt.Sprintf("/v1/volumes/%s/gfproxy/disable", volname)
+	err := c.post(url, nil, http.StatusOK, &status)
+	return status, err
+}
@@ -0,0 +1,54 @@
+package restclient
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/pkg/api"
+)
+
+// HookCreate registers a hook
+func (c *Client) HookCreate(req api.HookCreateReq) (api.HookResp, error) {
+	var resp api.HookResp
+	err := c.post("/v1/hooks", req, http.StatusCreated, &resp)
+	return resp, err
+}
+
+// Hooks returns the hooks
+func (c *Client) Hooks() (api.HookListResp, error) {
+	var resp api.HookListResp
+	err := c.get("/v1/hooks", nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// Hook returns the hook with the name
+func (c *Client) Hook(name string) (api.HookResp, error) {
+	var resp api.HookResp
+	err := c.get("/v1/hooks/"+name, nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// HookDelete deletes the hook with the name
+func (c *Client) HookDelete(name string) error {
+	return c.del("/v1/hooks/"+name, nil, http.StatusNoContent, nil)
+}
+
+// HookEnable enables the hook with the name
+func (c *Client) HookEnable(name string) (api.HookResp, error) {
+	var resp api.HookResp
+	err := c.post("/v1/hooks/"+name+"/enable", nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// HookDisable disables the hook with the name
+func (c *Client) HookDisable(name string) (api.HookResp, error) {
+	var resp api.HookResp
+	err := c.post("/v1/hooks/"+name+"/disable", nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// HookHistory returns the runs of the hook with the name, the latest first
+func (c *Client) HookHistory(name string) (api.HookHistoryResp, error) {
+	var resp api.HookHistoryResp
+	err := c.get("/v1/hooks/"+name+"/history", nil, http.StatusOK, &resp)
+	return resp, err
+}
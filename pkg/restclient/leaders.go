@@ -0,0 +1,22 @@
+package restclient
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/pkg/api"
+)
+
+// Leaders returns the peers leading the singleton services of the cluster
+func (c *Client) Leaders() (api.ListLeadersResp, error) {
+	var resp api.ListLeadersResp
+	err := c.get("/v1/cluster/leaders", nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// LeaderFailover makes the leader of the service resign, for another peer to
+// take the service over. It returns the peer which resigned.
+func (c *Client) LeaderFailover(service string) (api.ServiceLeader, error) {
+	var resp api.ServiceLeader
+	err := c.post("/v1/cluster/leaders/"+service+"/failover", nil, http.StatusAccepted, &resp)
+	return resp, err
+}
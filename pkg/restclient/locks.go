@@ -0,0 +1,21 @@
+package restclient
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/pkg/api"
+)
+
+// ClusterLocks lists the cluster wide locks held
+func (c *Client) ClusterLocks() (api.LockListResp, error) {
+	var resp api.LockListResp
+	err := c.get("/v1/cluster/locks", nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// ClusterLockForceRelease releases a stale lock, whichever peer holds it
+func (c *Client) ClusterLockForceRelease(lockID string) (api.LockInfo, error) {
+	var resp api.LockInfo
+	err := c.del("/v1/cluster/locks/"+lockID, nil, http.StatusOK, &resp)
+	return resp, err
+}
@@ -0,0 +1,60 @@
+package restclient
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	logmgmtapi "github.com/gluster/glusterd2/plugins/logmgmt/api"
+)
+
+// LogLevels returns the log levels of glusterd2
+func (c *Client) LogLevels() (logmgmtapi.LogLevelResp, error) {
+	var resp logmgmtapi.LogLevelResp
+	err := c.get("/v1/logging", nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// LogLevelSet sets the log levels of glusterd2 on all the peers
+func (c *Client) LogLevelSet(req logmgmtapi.LogLevelReq) (logmgmtapi.LogLevelResp, error) {
+	var resp logmgmtapi.LogLevelResp
+	err := c.put("/v1/logging", req, http.StatusOK, &resp)
+	return resp, err
+}
+
+// LogRotate rotates the logs of glusterd2 and the daemons managed by it on
+// all the peers
+func (c *Client) LogRotate() error {
+	return c.post("/v1/logging/rotate", nil, http.StatusNoContent, nil)
+}
+
+// Logs returns the log entries of glusterd2 and the daemons managed by it
+// matching the query
+func (c *Client) Logs(q logmgmtapi.LogQuery) (logmgmtapi.LogsResp, error) {
+	params := url.Values{}
+	for k, v := range map[string]string{
+		"daemon": q.Daemon,
+		"volume": q.Volume,
+		"brick":  q.Brick,
+		"level":  q.Level,
+		"grep":   q.Pattern,
+	} {
+		if v != "" {
+			params.Set(k, v)
+		}
+	}
+	if !q.Since.IsZero() {
+		params.Set("since", q.Since.Format(time.RFC3339Nano))
+	}
+	if q.Limit > 0 {
+		params.Set("limit", strconv.Itoa(q.Limit))
+	}
+	if q.Peers {
+		params.Set("peers", "true")
+	}
+
+	var resp logmgmtapi.LogsResp
+	err := c.get("/v1/logs?"+params.Encode(), nil, http.StatusOK, &resp)
+	return resp, err
+}
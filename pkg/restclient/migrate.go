@@ -0,0 +1,18 @@
+package restclient
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/pkg/api"
+)
+
+// GD1Import imports the volumes and snapshots of a glusterd1 installation
+func (c *Client) GD1Import(req api.GD1ImportReq) (api.GD1ImportResp, error) {
+	var resp api.GD1ImportResp
+	expectedStatus := http.StatusCreated
+	if req.DryRun {
+		expectedStatus = http.StatusOK
+	}
+	err := c.post("/v1/cluster/import", req, expectedStatus, &resp)
+	return resp, err
+}
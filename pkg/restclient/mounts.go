@@ -0,0 +1,26 @@
+package restclient
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/pkg/api"
+)
+
+// MountCreate mounts a volume on the peer with the ID
+func (c *Client) MountCreate(peerID string, req api.MountCreateReq) (api.MountResp, error) {
+	var resp api.MountResp
+	err := c.post("/v1/peers/"+peerID+"/mounts", req, http.StatusCreated, &resp)
+	return resp, err
+}
+
+// Mounts returns the managed mounts of the peer with the ID
+func (c *Client) Mounts(peerID string) (api.MountListResp, error) {
+	var resp api.MountListResp
+	err := c.get("/v1/peers/"+peerID+"/mounts", nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// MountDelete unmounts the managed mount of the peer with the ID
+func (c *Client) MountDelete(peerID, mountID string) error {
+	return c.del("/v1/peers/"+peerID+"/mounts/"+mountID, nil, http.StatusNoContent, nil)
+}
@@ -0,0 +1,60 @@
+package restclient
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/pkg/api"
+)
+
+// NamespaceCreate creates a namespace
+func (c *Client) NamespaceCreate(req api.NamespaceCreateReq) (api.NamespaceResp, error) {
+	var resp api.NamespaceResp
+	err := c.post("/v1/namespaces", req, http.StatusCreated, &resp)
+	return resp, err
+}
+
+// Namespaces returns the namespaces and the usage of their quotas
+func (c *Client) Namespaces() (api.NamespaceListResp, error) {
+	var resp api.NamespaceListResp
+	err := c.get("/v1/namespaces", nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// Namespace returns the namespace with the name
+func (c *Client) Namespace(name string) (api.NamespaceResp, error) {
+	var resp api.NamespaceResp
+	err := c.get("/v1/namespaces/"+name, nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// NamespaceEdit changes the quotas of the namespace with the name
+func (c *Client) NamespaceEdit(name string, req api.NamespaceEditReq) (api.NamespaceResp, error) {
+	var resp api.NamespaceResp
+	err := c.post("/v1/namespaces/"+name+"/edit", req, http.StatusOK, &resp)
+	return resp, err
+}
+
+// NamespaceDelete deletes the namespace with the name, and its tokens
+func (c *Client) NamespaceDelete(name string) error {
+	return c.del("/v1/namespaces/"+name, nil, http.StatusNoContent, nil)
+}
+
+// NamespaceTokenCreate creates an API token scoped to the namespace. The
+// secret of the token is only returned here.
+func (c *Client) NamespaceTokenCreate(name string) (api.NamespaceToken, error) {
+	var resp api.NamespaceToken
+	err := c.post("/v1/namespaces/"+name+"/tokens", nil, http.StatusCreated, &resp)
+	return resp, err
+}
+
+// NamespaceTokens returns the tokens of the namespace
+func (c *Client) NamespaceTokens(name string) (api.NamespaceTokenListResp, error) {
+	var resp api.NamespaceTokenListResp
+	err := c.get("/v1/namespaces/"+name+"/tokens", nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// NamespaceTokenDelete revokes the token of the namespace
+func (c *Client) NamespaceTokenDelete(name, id string) error {
+	return c.del("/v1/namespaces/"+name+"/tokens/"+id, nil, http.StatusNoContent, nil)
+}
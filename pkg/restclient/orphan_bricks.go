@@ -0,0 +1,22 @@
+package restclient
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/pkg/api"
+)
+
+// OrphanBricks lists the orphaned brick processes detected on the peers
+func (c *Client) OrphanBricks() (api.OrphanBrickListResp, error) {
+	var resp api.OrphanBrickListResp
+	err := c.get("/v1/cluster/orphan-bricks", nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// OrphanBricksCleanup kills the orphaned brick processes detected on the
+// peers which are up, and returns the processes killed
+func (c *Client) OrphanBricksCleanup() (api.OrphanBrickCleanupResp, error) {
+	var resp api.OrphanBrickCleanupResp
+	err := c.del("/v1/cluster/orphan-bricks", nil, http.StatusOK, &resp)
+	return resp, err
+}
@@ -27,6 +27,14 @@ func (c *Client) GetPeer(peerid string) (api.PeerGetResp, error) {
 	return peer, err
 }
 
+// PeerPorts returns the ports the peer and its bricks listen on, and are
+// expected to listen on
+func (c *Client) PeerPorts(peerid string) (api.PeerPortsResp, error) {
+	var resp api.PeerPortsResp
+	err := c.get("/v1/peers/"+peerid+"/ports", nil, http.StatusOK, &resp)
+	return resp, err
+}
+
 // Peers gets list of Gluster Peers
 func (c *Client) Peers(filterParams ...map[string]string) (api.PeerListResp, error) {
 	var peers api.PeerListResp
@@ -37,3 +45,45 @@ func (c *Client) Peers(filterParams ...map[string]string) (api.PeerListResp, err
 	err := c.get("/v1/peers"+queryString, nil, http.StatusOK, &peers)
 	return peers, err
 }
+
+// PeerTokenCreate creates a one-time token for a peer to join the cluster
+func (c *Client) PeerTokenCreate(req api.PeerTokenReq) (api.PeerTokenResp, error) {
+	var resp api.PeerTokenResp
+	err := c.post("/v1/peers/tokens", req, http.StatusCreated, &resp)
+	return resp, err
+}
+
+// PeerJoin makes the peer join the cluster which created the join token
+func (c *Client) PeerJoin(req api.PeerJoinReq) (api.PeerJoinResp, error) {
+	var resp api.PeerJoinResp
+	err := c.post("/v1/peers/join", req, http.StatusCreated, &resp)
+	return resp, err
+}
+
+// PeerAdmit presents the join token of a peer joining the cluster
+func (c *Client) PeerAdmit(req api.PeerAdmitReq) (api.PeerAdmitResp, error) {
+	var resp api.PeerAdmitResp
+	err := c.post("/v1/peers/admit", req, http.StatusOK, &resp)
+	return resp, err
+}
+
+// PeerEvacuate plans the moves of all the bricks off the peer, and unless
+// it is a dry run, starts moving them
+func (c *Client) PeerEvacuate(peerID string, req api.PeerEvacuateReq) (api.PeerEvacuateStatus, error) {
+	var resp api.PeerEvacuateStatus
+	expectedStatus := http.StatusAccepted
+	if req.DryRun {
+		expectedStatus = http.StatusOK
+	}
+	url := fmt.Sprintf("/v1/peers/%s/evacuate", peerID)
+	err := c.post(url, req, expectedStatus, &resp)
+	return resp, err
+}
+
+// PeerEvacuationStatus returns the progress of the evacuation of the peer
+func (c *Client) PeerEvacuationStatus(peerID string) (api.PeerEvacuateStatus, error) {
+	var resp api.PeerEvacuateStatus
+	url := fmt.Sprintf("/v1/peers/%s/evacuate", peerID)
+	err := c.get(url, nil, http.StatusOK, &resp)
+	return resp, err
+}
@@ -0,0 +1,28 @@
+package restclient
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/pkg/api"
+)
+
+// PluginList lists the plugins of glusterd2 and their state
+func (c *Client) PluginList() (api.PluginListResp, error) {
+	var resp api.PluginListResp
+	err := c.get("/v1/plugins", nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// PluginEnable enables the plugin in the cluster
+func (c *Client) PluginEnable(name string) (api.PluginInfo, error) {
+	var resp api.PluginInfo
+	err := c.post("/v1/plugins/"+name+"/enable", nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// PluginDisable disables the plugin in the cluster
+func (c *Client) PluginDisable(name string) (api.PluginInfo, error) {
+	var resp api.PluginInfo
+	err := c.post("/v1/plugins/"+name+"/disable", nil, http.StatusOK, &resp)
+	return resp, err
+}
@@ -3,6 +3,8 @@ package restclient
 import (
 	"fmt"
 	"net/http"
+
+	quotaapi "github.com/gluster/glusterd2/plugins/quota/api"
 )
 
 // QuotaEnable starts a Gluster Volume
@@ -10,3 +12,24 @@ func (c *Client) QuotaEnable(volname string) error {
 	url := fmt.Sprintf("/v1/quota/%s", volname)
 	return c.post(url, nil, http.StatusOK, nil)
 }
+
+// QuotaList lists the quota limits of a volume with their usage
+func (c *Client) QuotaList(volname string) (quotaapi.ListResp, error) {
+	var resp quotaapi.ListResp
+	url := fmt.Sprintf("/v1/quota/%s/limit", volname)
+	err := c.get(url, nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// QuotaLimit sets the quota limits of a directory of a volume
+func (c *Client) QuotaLimit(volname string, req quotaapi.SetLimitReq) error {
+	url := fmt.Sprintf("/v1/quota/%s/limit", volname)
+	return c.post(url, req, http.StatusOK, nil)
+}
+
+// QuotaRemove removes the quota limits of a directory of a volume
+func (c *Client) QuotaRemove(volname string, path string) error {
+	req := quotaapi.RemoveLimitReq{Path: path}
+	url := fmt.Sprintf("/v1/quota/%s/limit", volname)
+	return c.del(url, req, http.StatusOK, nil)
+}
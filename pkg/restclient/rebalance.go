@@ -0,0 +1,37 @@
+package restclient
+
+import (
+	"fmt"
+	"net/http"
+
+	rebalanceapi "github.com/gluster/glusterd2/plugins/rebalance/api"
+
+	"github.com/pborman/uuid"
+)
+
+// RebalanceStart starts the rebalance of a volume and returns the ID of the
+// rebalance. The option may be "fix-layout", "force" or empty.
+func (c *Client) RebalanceStart(volname string, option string) (uuid.UUID, error) {
+	var id uuid.UUID
+	req := rebalanceapi.StartReq{Option: option}
+	url := fmt.Sprintf("/v1/volumes/%s/rebalance/start", volname)
+	err := c.post(url, req, http.StatusOK, &id)
+	return id, err
+}
+
+// RebalanceStop stops the rebalance of a volume
+func (c *Client) RebalanceStop(volname string) (rebalanceapi.RebalInfo, error) {
+	var info rebalanceapi.RebalInfo
+	url := fmt.Sprintf("/v1/volumes/%s/rebalance/stop", volname)
+	err := c.post(url, nil, http.StatusOK, &info)
+	return info, err
+}
+
+// RebalanceStatus returns the status of the rebalance of a volume on each
+// peer
+func (c *Client) RebalanceStatus(volname string) (rebalanceapi.RebalStatus, error) {
+	var status rebalanceapi.RebalStatus
+	url := fmt.Sprintf("/v1/volumes/%s/rebalance", volname)
+	err := c.get(url, nil, http.StatusOK, &status)
+	return status, err
+}
@@ -0,0 +1,59 @@
+package restclient
+
+import (
+	"net/http"
+
+	sambaapi "github.com/gluster/glusterd2/plugins/samba/api"
+)
+
+// SambaShareCreate exports a directory of a volume as a Samba share
+func (c *Client) SambaShareCreate(req sambaapi.ShareCreateReq) (sambaapi.Share, error) {
+	var share sambaapi.Share
+	err := c.post("/v1/samba/shares", req, http.StatusCreated, &share)
+	return share, err
+}
+
+// SambaShareList lists the Samba shares
+func (c *Client) SambaShareList() (sambaapi.ShareListResp, error) {
+	var shares sambaapi.ShareListResp
+	err := c.get("/v1/samba/shares", nil, http.StatusOK, &shares)
+	return shares, err
+}
+
+// SambaShareGet returns a Samba share
+func (c *Client) SambaShareGet(name string) (sambaapi.Share, error) {
+	var share sambaapi.Share
+	err := c.get("/v1/samba/shares/"+name, nil, http.StatusOK, &share)
+	return share, err
+}
+
+// SambaShareDelete deletes a Samba share
+func (c *Client) SambaShareDelete(name string) error {
+	return c.del("/v1/samba/shares/"+name, nil, http.StatusNoContent, nil)
+}
+
+// SambaCTDBSetup sets the volume used as the CTDB lock volume
+func (c *Client) SambaCTDBSetup(req sambaapi.CTDBSetupReq) (sambaapi.CTDBConfig, error) {
+	var cfg sambaapi.CTDBConfig
+	err := c.put("/v1/samba/ctdb", req, http.StatusOK, &cfg)
+	return cfg, err
+}
+
+// SambaCTDBGet returns the CTDB configuration of the cluster
+func (c *Client) SambaCTDBGet() (sambaapi.CTDBConfig, error) {
+	var cfg sambaapi.CTDBConfig
+	err := c.get("/v1/samba/ctdb", nil, http.StatusOK, &cfg)
+	return cfg, err
+}
+
+// SambaCTDBDelete stops using a CTDB lock volume
+func (c *Client) SambaCTDBDelete() error {
+	return c.del("/v1/samba/ctdb", nil, http.StatusNoContent, nil)
+}
+
+// SambaStatus returns the Samba health of all the peers
+func (c *Client) SambaStatus() (sambaapi.StatusResp, error) {
+	var resp sambaapi.StatusResp
+	err := c.get("/v1/samba/status", nil, http.StatusOK, &resp)
+	return resp, err
+}
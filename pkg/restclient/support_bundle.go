@@ -0,0 +1,60 @@
+package restclient
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/gluster/glusterd2/pkg/api"
+)
+
+// SupportBundleCreate starts the creation of a support bundle, an archive of
+// the logs, statedumps, versions and health of the peers
+func (c *Client) SupportBundleCreate(req api.SupportBundleReq) (api.SupportBundle, error) {
+	var resp api.SupportBundle
+	err := c.post("/v1/cluster/support-bundle", req, http.StatusAccepted, &resp)
+	return resp, err
+}
+
+// SupportBundleList lists the support bundles
+func (c *Client) SupportBundleList() (api.SupportBundleListResp, error) {
+	var resp api.SupportBundleListResp
+	err := c.get("/v1/cluster/support-bundle", nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// SupportBundleStatus returns the support bundle, with the progress of its
+// creation
+func (c *Client) SupportBundleStatus(id string) (api.SupportBundle, error) {
+	var resp api.SupportBundle
+	err := c.get("/v1/cluster/support-bundle/"+id, nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// SupportBundleDownload writes the archive of the completed support bundle
+// to w. The archive is downloaded from the peer which created it, through
+// the peer the request is sent to.
+func (c *Client) SupportBundleDownload(id string, w io.Writer) error {
+	req, err := c.buildRequest("GET", "/v1/cluster/support-bundle/"+id+"/download", nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/gzip")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		c.lastRespErr = resp
+		return newHTTPErrorResponse(resp)
+	}
+	_, err = io.Copy(w, resp.Body)
+	return err
+}
+
+// SupportBundleDelete deletes the support bundle and its archive
+func (c *Client) SupportBundleDelete(id string) error {
+	return c.del("/v1/cluster/support-bundle/"+id, nil, http.StatusNoContent, nil)
+}
@@ -0,0 +1,56 @@
+package restclient
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gluster/glusterd2/pkg/api"
+)
+
+// Templates returns the latest version of each volfile template
+func (c *Client) Templates() (api.VolfileTemplateListResp, error) {
+	var resp api.VolfileTemplateListResp
+	err := c.get("/v1/templates", nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// Template returns the given version of the volfile template, or its latest
+// version if version is negative
+func (c *Client) Template(name string, version int) (api.VolfileTemplateResp, error) {
+	var resp api.VolfileTemplateResp
+	url := "/v1/templates/" + name
+	if version >= 0 {
+		url = fmt.Sprintf("%s?version=%d", url, version)
+	}
+	err := c.get(url, nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// TemplateVersions returns all the versions of the volfile template
+func (c *Client) TemplateVersions(name string) (api.VolfileTemplateVersionsResp, error) {
+	var resp api.VolfileTemplateVersionsResp
+	err := c.get("/v1/templates/"+name+"/versions", nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// TemplateSet saves a new version of the volfile template
+func (c *Client) TemplateSet(req api.VolfileTemplateReq) (api.VolfileTemplateResp, error) {
+	var resp api.VolfileTemplateResp
+	err := c.put("/v1/templates/"+req.Name, req, http.StatusOK, &resp)
+	return resp, err
+}
+
+// VolumeTemplatePin pins the version of the volfile template used by the
+// volume
+func (c *Client) VolumeTemplatePin(volname, name string, version int) (api.VolfileTemplateResp, error) {
+	var resp api.VolfileTemplateResp
+	req := api.VolumeTemplatePinReq{Version: version}
+	err := c.put("/v1/volumes/"+volname+"/templates/"+name, req, http.StatusOK, &resp)
+	return resp, err
+}
+
+// VolumeTemplateUnpin makes the volume use the latest version of the volfile
+// template
+func (c *Client) VolumeTemplateUnpin(volname, name string) error {
+	return c.del("/v1/volumes/"+volname+"/templates/"+name, nil, http.StatusNoContent, nil)
+}
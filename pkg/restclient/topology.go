@@ -0,0 +1,15 @@
+package restclient
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/pkg/api"
+)
+
+// ClusterTopology returns the topology of the cluster, as a graph of the
+// peers, devices, bricks, subvolumes, volumes and daemons
+func (c *Client) ClusterTopology() (api.ClusterTopologyResp, error) {
+	var resp api.ClusterTopologyResp
+	err := c.get("/v1/cluster/topology", nil, http.StatusOK, &resp)
+	return resp, err
+}
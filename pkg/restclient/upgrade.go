@@ -0,0 +1,28 @@
+package restclient
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/pkg/api"
+)
+
+// UpgradeStart starts a rolling upgrade of the peers
+func (c *Client) UpgradeStart(req api.UpgradeReq) (api.UpgradeStatus, error) {
+	var resp api.UpgradeStatus
+	err := c.put("/v1/cluster/upgrade", req, http.StatusAccepted, &resp)
+	return resp, err
+}
+
+// UpgradeStatus returns the progress of the rolling upgrade
+func (c *Client) UpgradeStatus() (api.UpgradeStatus, error) {
+	var resp api.UpgradeStatus
+	err := c.get("/v1/cluster/upgrade", nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// UpgradeAbort stops the rolling upgrade in progress
+func (c *Client) UpgradeAbort() (api.UpgradeStatus, error) {
+	var resp api.UpgradeStatus
+	err := c.del("/v1/cluster/upgrade", nil, http.StatusOK, &resp)
+	return resp, err
+}
@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gluster/glusterd2/pkg/api"
 )
@@ -52,6 +55,13 @@ func getQueryString(filterParam map[string]string) string {
 	case keyAndValue:
 		queryString = fmt.Sprintf("?key=%s&value=%s", url.QueryEscape(filterParam["key"]), url.QueryEscape(filterParam["value"]))
 	}
+	if selector, ok := filterParam["selector"]; ok {
+		sep := "?"
+		if queryString != "" {
+			sep = "&"
+		}
+		queryString += fmt.Sprintf("%sselector=%s", sep, url.QueryEscape(selector))
+	}
 	return queryString
 }
 
@@ -73,6 +83,33 @@ func (c *Client) Volumes(volname string, filterParams ...map[string]string) (api
 	return []api.VolumeGetResp{vol}, err
 }
 
+// VolumeWatch waits for the volumes to change after the store revision since,
+// for at most timeout, and returns the volumes changed and deleted. With since
+// set to 0 all the volumes are returned at once. The Revision of the response
+// is the since of the next call. The timeout of the client must be longer than
+// the timeout of the watch.
+func (c *Client) VolumeWatch(since int64, timeout time.Duration) (api.VolumeWatchResp, error) {
+	url := fmt.Sprintf("/v1/volumes?watch=true&since=%d&timeout=%d", since, int64(timeout/time.Second))
+	var resp api.VolumeWatchResp
+	err := c.get(url, nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// VolumePatch patches the labels of a Gluster volume
+func (c *Client) VolumePatch(volname string, req api.VolPatchReq) (api.VolumeGetResp, error) {
+	var resp api.VolumeGetResp
+	err := c.patch("/v1/volumes/"+volname, req, http.StatusOK, &resp)
+	return resp, err
+}
+
+// BrickEnable re-enables a degraded brick of a Gluster volume, starting it
+// again if it was stopped
+func (c *Client) BrickEnable(volname string, req api.BrickEnableReq) (api.BrickInfo, error) {
+	var resp api.BrickInfo
+	err := c.post("/v1/volumes/"+volname+"/bricks/enable", req, http.StatusOK, &resp)
+	return resp, err
+}
+
 // BricksStatus returns the status of bricks that form a Gluster volume
 func (c *Client) BricksStatus(volname string) (api.BricksStatusResp, error) {
 	url := fmt.Sprintf("/v1/volumes/%s/bricks", volname)
@@ -89,24 +126,174 @@ func (c *Client) VolumeStatus(volname string) (api.VolumeStatusResp, error) {
 	return volStatus, err
 }
 
+// VolumeSize returns the size of a Gluster volume
+func (c *Client) VolumeSize(volname string) (api.VolumeSizeResp, error) {
+	url := fmt.Sprintf("/v1/volumes/%s/size", volname)
+	var resp api.VolumeSizeResp
+	err := c.get(url, nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// VolumeClients lists the clients connected to a Gluster volume
+func (c *Client) VolumeClients(volname string) (api.VolumeClientsResp, error) {
+	url := fmt.Sprintf("/v1/volumes/%s/clients", volname)
+	var resp api.VolumeClientsResp
+	err := c.get(url, nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// VolumeClientDisconnect disconnects a client of a Gluster volume
+func (c *Client) VolumeClientDisconnect(volname string, req api.ClientDisconnectReq) (api.ClientDisconnectResp, error) {
+	url := fmt.Sprintf("/v1/volumes/%s/clients/disconnect", volname)
+	var resp api.ClientDisconnectResp
+	err := c.post(url, req, http.StatusOK, &resp)
+	return resp, err
+}
+
+// VolumeBarrierEnable pauses the disruptive operations on the bricks of a
+// Gluster volume for at most timeout seconds
+func (c *Client) VolumeBarrierEnable(volname string, timeout int) (api.VolumeBarrierResp, error) {
+	req := api.VolumeBarrierReq{
+		Timeout: timeout,
+	}
+	url := fmt.Sprintf("/v1/volumes/%s/barrier/enable", volname)
+	var resp api.VolumeBarrierResp
+	err := c.post(url, req, http.StatusOK, &resp)
+	return resp, err
+}
+
+// VolumeBarrierDisable resumes the operations paused on the bricks of a
+// Gluster volume
+func (c *Client) VolumeBarrierDisable(volname string) (api.VolumeBarrierResp, error) {
+	url := fmt.Sprintf("/v1/volumes/%s/barrier/disable", volname)
+	var resp api.VolumeBarrierResp
+	err := c.post(url, nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// VolumeHalo reports the replicas of a Gluster volume local to the clients
+// of each zone, for halo replication
+func (c *Client) VolumeHalo(volname string) (api.VolumeHaloResp, error) {
+	url := fmt.Sprintf("/v1/volumes/%s/halo", volname)
+	var resp api.VolumeHaloResp
+	err := c.get(url, nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// VolumeLeases reports the state of the leases granted by the bricks of a
+// Gluster volume
+func (c *Client) VolumeLeases(volname string) (api.VolumeLeasesResp, error) {
+	url := fmt.Sprintf("/v1/volumes/%s/leases", volname)
+	var resp api.VolumeLeasesResp
+	err := c.get(url, nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// VolumeAdvisor reports the effectiveness of the caches of a Gluster volume,
+// with the recommended options
+func (c *Client) VolumeAdvisor(volname string) (api.VolumeAdvisorResp, error) {
+	url := fmt.Sprintf("/v1/volumes/%s/advisor", volname)
+	var resp api.VolumeAdvisorResp
+	err := c.get(url, nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// VolumeSetMode sets the volume read-write, read-only or WORM
+func (c *Client) VolumeSetMode(volname string, req api.VolumeModeReq) (api.VolumeModeResp, error) {
+	url := fmt.Sprintf("/v1/volumes/%s/mode", volname)
+	var resp api.VolumeModeResp
+	err := c.post(url, req, http.StatusOK, &resp)
+	return resp, err
+}
+
+// VolumeTLS gets the state of TLS on the data path of a Gluster volume
+func (c *Client) VolumeTLS(volname string) (api.VolumeTLSResp, error) {
+	url := fmt.Sprintf("/v1/volumes/%s/tls", volname)
+	var resp api.VolumeTLSResp
+	err := c.get(url, nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// VolumeTLSEnable enables TLS on the data path of a Gluster volume
+func (c *Client) VolumeTLSEnable(volname string, req api.VolumeTLSReq) (api.VolumeTLSResp, error) {
+	url := fmt.Sprintf("/v1/volumes/%s/tls/enable", volname)
+	var resp api.VolumeTLSResp
+	err := c.post(url, req, http.StatusOK, &resp)
+	return resp, err
+}
+
+// VolumeTLSDisable disables TLS on the data path of a Gluster volume
+func (c *Client) VolumeTLSDisable(volname string) (api.VolumeTLSResp, error) {
+	url := fmt.Sprintf("/v1/volumes/%s/tls/disable", volname)
+	var resp api.VolumeTLSResp
+	err := c.post(url, nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// VolumeTLSRotate restarts the bricks of a Gluster volume, for them to load
+// their renewed certificates
+func (c *Client) VolumeTLSRotate(volname string) (api.VolumeTLSResp, error) {
+	url := fmt.Sprintf("/v1/volumes/%s/tls/rotate", volname)
+	var resp api.VolumeTLSResp
+	err := c.post(url, nil, http.StatusOK, &resp)
+	return resp, err
+}
+
 // VolumeStart starts a Gluster Volume
 func (c *Client) VolumeStart(volname string, force bool) error {
 	req := api.VolumeStartReq{
 		ForceStartBricks: force,
 	}
+	return c.VolumeStartWithReq(volname, req)
+}
+
+// VolumeStartWithReq starts a Gluster Volume with the options of req
+func (c *Client) VolumeStartWithReq(volname string, req api.VolumeStartReq) error {
 	url := fmt.Sprintf("/v1/volumes/%s/start", volname)
 	return c.post(url, req, http.StatusOK, nil)
 }
 
-// VolumeStop stops a Gluster Volume
+// VolumeStop stops a Gluster Volume. Stopping a volume its clients are
+// connected to fails, see VolumeStopForce.
 func (c *Client) VolumeStop(volname string) error {
 	url := fmt.Sprintf("/v1/volumes/%s/stop", volname)
 	return c.post(url, nil, http.StatusOK, nil)
 }
 
-// VolumeDelete deletes a Gluster Volume
-func (c *Client) VolumeDelete(volname string) error {
+// VolumeStopForce stops a Gluster Volume, disconnecting its clients
+func (c *Client) VolumeStopForce(volname string) error {
+	req := api.VolumeStopReq{
+		Force: true,
+	}
+	url := fmt.Sprintf("/v1/volumes/%s/stop", volname)
+	return c.post(url, req, http.StatusOK, nil)
+}
+
+// VolumeDelete deletes a Gluster Volume. Unless purged, the volume is
+// retained in the trash, with its bricks, until the end of its grace period.
+func (c *Client) VolumeDelete(volname string, req api.VolumeDeleteReq) error {
 	url := fmt.Sprintf("/v1/volumes/%s", volname)
+	return c.del(url, req, http.StatusNoContent, nil)
+}
+
+// VolumeTrashList lists the deleted volumes retained in the trash
+func (c *Client) VolumeTrashList() (api.VolumeTrashListResp, error) {
+	var resp api.VolumeTrashListResp
+	err := c.get("/v1/trash/volumes", nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// VolumeUndelete restores a deleted volume from the trash, not started
+func (c *Client) VolumeUndelete(volid string) (api.VolumeUndeleteResp, error) {
+	var resp api.VolumeUndeleteResp
+	url := fmt.Sprintf("/v1/trash/volumes/%s/undelete", volid)
+	err := c.post(url, nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// VolumePurge deletes a volume retained in the trash for good
+func (c *Client) VolumePurge(volid string) error {
+	url := fmt.Sprintf("/v1/trash/volumes/%s", volid)
 	return c.del(url, nil, http.StatusNoContent, nil)
 }
 
@@ -117,10 +304,74 @@ func (c *Client) VolumeSet(volname string, req api.VolOptionReq) error {
 	return err
 }
 
+// VolumeSetBatch sets options on the Gluster volumes selected by a label
+// selector, returning the outcome of each volume
+func (c *Client) VolumeSetBatch(req api.VolOptionsBatchReq) (api.VolumeOptionsBatchResp, error) {
+	var resp api.VolumeOptionsBatchResp
+	err := c.post("/v1/volumes/options:batch", req, http.StatusOK, &resp)
+	return resp, err
+}
+
 // ClusterOptionSet sets cluster level options
 func (c *Client) ClusterOptionSet(req api.ClusterOptionReq) error {
 	url := fmt.Sprintf("/v1/cluster/options")
-	return c.post(url, req, http.StatusOK, nil)
+	return c.put(url, req, http.StatusOK, nil)
+}
+
+// ClusterOptionsGet gets cluster level options
+func (c *Client) ClusterOptionsGet() ([]api.ClusterOptionsResp, error) {
+	var opts []api.ClusterOptionsResp
+	err := c.get("/v1/cluster/options", nil, http.StatusOK, &opts)
+	return opts, err
+}
+
+// OpVersionGet gets the op-version of the cluster and of its peers
+func (c *Client) OpVersionGet() (api.OpVersionResp, error) {
+	var resp api.OpVersionResp
+	err := c.get("/v1/cluster/op-version", nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// OpVersionBump raises the op-version of the cluster
+func (c *Client) OpVersionBump(req api.OpVersionBumpReq) (api.OpVersionResp, error) {
+	var resp api.OpVersionResp
+	err := c.post("/v1/cluster/op-version", req, http.StatusOK, &resp)
+	return resp, err
+}
+
+// QuorumGet gets server-quorum as seen by the peer
+func (c *Client) QuorumGet() (api.QuorumStatus, error) {
+	var resp api.QuorumStatus
+	err := c.get("/v1/cluster/quorum", nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// TLSCAGet gets the CA bundle trusted for TLS on the data path
+func (c *Client) TLSCAGet() (api.TLSCAResp, error) {
+	var resp api.TLSCAResp
+	err := c.get("/v1/cluster/tls/ca", nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// TLSCASet sets the CA bundle trusted for TLS on the data path
+func (c *Client) TLSCASet(req api.TLSCAReq) (api.TLSCAResp, error) {
+	var resp api.TLSCAResp
+	err := c.put("/v1/cluster/tls/ca", req, http.StatusOK, &resp)
+	return resp, err
+}
+
+// CAStatus gets the state of the CA and of the certificates of the peers
+func (c *Client) CAStatus() (api.CAStatusResp, error) {
+	var resp api.CAStatusResp
+	err := c.get("/v1/cluster/ca", nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// CARenew renews the certificates of the peers
+func (c *Client) CARenew(req api.CARenewReq) (api.CAStatusResp, error) {
+	var resp api.CAStatusResp
+	err := c.post("/v1/cluster/ca/renew", req, http.StatusOK, &resp)
+	return resp, err
 }
 
 // VolumeGet gets volume options for a Gluster Volume
@@ -172,7 +423,16 @@ func (c *Client) OptionGroupList() (api.OptionGroupListResp, error) {
 	return l, err
 }
 
-// OptionGroupDelete deletes the specified option group
+// OptionGroupGet returns the specified option group
+func (c *Client) OptionGroupGet(group string) (api.OptionGroupGetResp, error) {
+	var g api.OptionGroupGetResp
+	url := fmt.Sprintf("/v1/volumes/options-group/%s", group)
+	err := c.get(url, nil, http.StatusOK, &g)
+	return g, err
+}
+
+// OptionGroupDelete deletes the specified option group, or reverts the
+// override of a builtin option group
 func (c *Client) OptionGroupDelete(group string) error {
 	url := fmt.Sprintf("/v1/volumes/options-group/%s", group)
 	return c.del(url, nil, http.StatusNoContent, nil)
@@ -199,3 +459,82 @@ func (c *Client) VolumeProfileInfo(volname string, option string) ([]api.BrickPr
 	err := c.get(url, nil, http.StatusOK, &volumeProfileInfo)
 	return volumeProfileInfo, err
 }
+
+// SubdirExportCreate exports a subdirectory of a volume
+func (c *Client) SubdirExportCreate(volname string, req api.SubdirExportReq) (api.SubdirExport, error) {
+	var resp api.SubdirExport
+	url := fmt.Sprintf("/v1/volumes/%s/subdirs", volname)
+	err := c.post(url, req, http.StatusCreated, &resp)
+	return resp, err
+}
+
+// SubdirExportList lists the exported subdirectories of a volume
+func (c *Client) SubdirExportList(volname string) (api.SubdirExportListResp, error) {
+	var resp api.SubdirExportListResp
+	url := fmt.Sprintf("/v1/volumes/%s/subdirs", volname)
+	err := c.get(url, nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// SubdirExportDelete stops exporting a subdirectory of a volume
+func (c *Client) SubdirExportDelete(volname string, subdir string) error {
+	url := fmt.Sprintf("/v1/volumes/%s/subdirs/%s", volname, strings.TrimPrefix(subdir, "/"))
+	return c.del(url, nil, http.StatusNoContent, nil)
+}
+
+// PlacementPreview tells if the volume can be provisioned, and where its
+// bricks would be placed, without creating it
+func (c *Client) PlacementPreview(req api.VolCreateReq) (api.PlacementPreviewResp, error) {
+	params := url.Values{}
+	params.Set("name", req.Name)
+	params.Set("size", strconv.FormatUint(req.Size, 10))
+	if req.ProvisionerType != "" {
+		params.Set("provisioner", req.ProvisionerType)
+	}
+	for k, v := range map[string]uint64{
+		"max-brick-size":    req.MaxBrickSize,
+		"average-file-size": req.AverageFileSize,
+	} {
+		if v != 0 {
+			params.Set(k, strconv.FormatUint(v, 10))
+		}
+	}
+	for k, v := range map[string]int{
+		"distribute":          req.DistributeCount,
+		"replica":             req.ReplicaCount,
+		"arbiter":             req.ArbiterCount,
+		"disperse":            req.DisperseCount,
+		"disperse-data":       req.DisperseDataCount,
+		"disperse-redundancy": req.DisperseRedundancyCount,
+	} {
+		if v != 0 {
+			params.Set(k, strconv.Itoa(v))
+		}
+	}
+	for k, v := range map[string]bool{
+		"snapshot":                req.SnapshotEnabled,
+		"subvolume-zones-overlap": req.SubvolZonesOverlap,
+		"encrypted":               req.Encrypted,
+	} {
+		if v {
+			params.Set(k, "true")
+		}
+	}
+	if req.SnapshotReserveFactor != 0 {
+		params.Set("snapshot-reserve-factor", strconv.FormatFloat(req.SnapshotReserveFactor, 'f', -1, 64))
+	}
+	for k, v := range map[string][]string{
+		"limit-peers":   req.LimitPeers,
+		"limit-zones":   req.LimitZones,
+		"exclude-peers": req.ExcludePeers,
+		"exclude-zones": req.ExcludeZones,
+	} {
+		if len(v) != 0 {
+			params.Set(k, strings.Join(v, ","))
+		}
+	}
+
+	var resp api.PlacementPreviewResp
+	err := c.get("/v1/cluster/placement-preview?"+params.Encode(), nil, http.StatusOK, &resp)
+	return resp, err
+}
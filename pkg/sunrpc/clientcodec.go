@@ -99,7 +99,9 @@ func (c *clientCodec) WriteRequest(req *rpc.Request, param interface{}) error {
 	return nil
 }
 
-func (c *clientCodec) checkReplyForErr(reply *RPCMsg) error {
+// ReplyError returns the error reported by the RPC reply, nil if the call
+// succeeded
+func ReplyError(reply *RPCMsg) error {
 
 	if reply.Type != Reply {
 		return ErrInvalidRPCMessageType
@@ -168,7 +170,7 @@ func (c *clientCodec) ReadResponseHeader(resp *rpc.Response) error {
 	delete(c.pending, resp.Seq)
 	c.mutex.Unlock()
 
-	return c.checkReplyForErr(&reply)
+	return ReplyError(&reply)
 }
 
 func (c *clientCodec) ReadResponseBody(result interface{}) error {
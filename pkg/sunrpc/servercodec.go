@@ -13,9 +13,14 @@ type serverCodec struct {
 	conn         io.ReadWriteCloser
 	closed       bool
 	notifyClose  chan<- io.ReadWriteCloser
+	onReply      ReplyHandler
 	recordReader io.Reader
 }
 
+// ReplyHandler is called with the replies received by the server to the RPC
+// calls it sent to the client over the same connection
+type ReplyHandler func(reply *RPCMsg)
+
 // NewServerCodec returns a new rpc.ServerCodec using Sun RPC on conn.
 // If a non-nil channel is passed as second argument, the conn is sent on
 // that channel when Close() is called on conn.
@@ -23,29 +28,46 @@ func NewServerCodec(conn io.ReadWriteCloser, notifyClose chan<- io.ReadWriteClos
 	return &serverCodec{conn: conn, notifyClose: notifyClose}
 }
 
+// NewServerCodecWithReplies returns a new rpc.ServerCodec using Sun RPC on
+// conn, which passes the replies received on conn to onReply instead of
+// closing the connection.
+func NewServerCodecWithReplies(conn io.ReadWriteCloser, notifyClose chan<- io.ReadWriteCloser, onReply ReplyHandler) rpc.ServerCodec {
+	return &serverCodec{conn: conn, notifyClose: notifyClose, onReply: onReply}
+}
+
 func (c *serverCodec) ReadRequestHeader(req *rpc.Request) error {
 	// NOTE:
 	// Errors returned by this function aren't relayed back to the client
 	// as WriteResponse() isn't called. The net/rpc package will call
 	// c.Close() when this function returns an error.
 
-	// Read entire RPC message from network
-	record, err := ReadFullRecord(c.conn)
-	if err != nil {
-		if err != io.EOF {
-			log.Println(err)
+	var call RPCMsg
+	for {
+		// Read entire RPC message from network
+		record, err := ReadFullRecord(c.conn)
+		if err != nil {
+			if err != io.EOF {
+				log.Println(err)
+			}
+			return err
 		}
-		return err
-	}
 
-	c.recordReader = bytes.NewReader(record)
+		c.recordReader = bytes.NewReader(record)
 
-	// Unmarshall RPC message
-	var call RPCMsg
-	_, err = xdr.Unmarshal(c.recordReader, &call)
-	if err != nil {
-		log.Println(err)
-		return err
+		// Unmarshall RPC message
+		call = RPCMsg{}
+		_, err = xdr.Unmarshal(c.recordReader, &call)
+		if err != nil {
+			log.Println(err)
+			return err
+		}
+
+		// Replies to the calls sent by the server are not requests,
+		// wait for the next message
+		if call.Type != Reply || c.onReply == nil {
+			break
+		}
+		c.onReply(&call)
 	}
 
 	if call.Type != Call {
@@ -0,0 +1,153 @@
+// Package systemd implements the socket activation and notification
+// protocols of systemd, so that a service can receive its listening sockets
+// from systemd and report its state to it
+package systemd
+
+import (
+	"errors"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// listenFdsStart is the first file descriptor passed by systemd
+const listenFdsStart = 3
+
+// ErrNoListener is returned when systemd did not pass a socket of a name
+var ErrNoListener = errors.New("no socket of the name was passed by systemd")
+
+var (
+	listenersOnce sync.Once
+	listeners     map[string]net.Listener
+	listenersErr  error
+)
+
+// parseListenEnv returns the number and the names of the sockets passed by
+// systemd to the process with the pid, from the values of the LISTEN_PID,
+// LISTEN_FDS and LISTEN_FDNAMES environment variables. Sockets without a name
+// are named after their position, starting from 0.
+func parseListenEnv(pid int, listenPid, listenFds, listenFdNames string) ([]string, error) {
+	if listenPid == "" || listenFds == "" {
+		return nil, nil
+	}
+
+	p, err := strconv.Atoi(listenPid)
+	if err != nil {
+		return nil, errors.New("invalid LISTEN_PID " + listenPid)
+	}
+	if p != pid {
+		// the sockets were meant for another process
+		return nil, nil
+	}
+
+	n, err := strconv.Atoi(listenFds)
+	if err != nil || n < 0 {
+		return nil, errors.New("invalid LISTEN_FDS " + listenFds)
+	}
+
+	var fdNames []string
+	if listenFdNames != "" {
+		fdNames = strings.Split(listenFdNames, ":")
+	}
+
+	names := make([]string, n)
+	for i := range names {
+		if i < len(fdNames) && fdNames[i] != "" {
+			names[i] = fdNames[i]
+		} else {
+			names[i] = strconv.Itoa(i)
+		}
+	}
+	return names, nil
+}
+
+// loadListeners takes over the sockets passed by systemd. The environment
+// variables are unset so that the sockets are not passed on to the children
+// of the process.
+func loadListeners() (map[string]net.Listener, error) {
+	defer os.Unsetenv("LISTEN_PID")
+	defer os.Unsetenv("LISTEN_FDS")
+	defer os.Unsetenv("LISTEN_FDNAMES")
+
+	names, err := parseListenEnv(os.Getpid(), os.Getenv("LISTEN_PID"),
+		os.Getenv("LISTEN_FDS"), os.Getenv("LISTEN_FDNAMES"))
+	if err != nil {
+		return nil, err
+	}
+
+	ls := make(map[string]net.Listener, len(names))
+	for i, name := range names {
+		fd := listenFdsStart + i
+		syscall.CloseOnExec(fd)
+
+		f := os.NewFile(uintptr(fd), name)
+		l, err := net.FileListener(f)
+		// net.FileListener dups the file descriptor
+		f.Close()
+		if err != nil {
+			return nil, err
+		}
+		ls[name] = l
+	}
+	return ls, nil
+}
+
+// Listener returns the socket of the name passed by systemd for socket
+// activation. The name is the FileDescriptorName= of the socket unit.
+func Listener(name string) (net.Listener, error) {
+	listenersOnce.Do(func() {
+		listeners, listenersErr = loadListeners()
+	})
+	if listenersErr != nil {
+		return nil, listenersErr
+	}
+
+	l, ok := listeners[name]
+	if !ok {
+		return nil, ErrNoListener
+	}
+	return l, nil
+}
+
+// Notify sends the state to systemd, like sd_notify(3). It returns false if
+// the process was not started by systemd with a notification socket.
+func Notify(state string) (bool, error) {
+	socket := os.Getenv("NOTIFY_SOCKET")
+	if socket == "" {
+		return false, nil
+	}
+	// abstract sockets are given with a leading @
+	if strings.HasPrefix(socket, "@") {
+		socket = "\x00" + socket[1:]
+	}
+
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write([]byte(state)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// WatchdogInterval returns the interval within which systemd expects
+// WATCHDOG=1 notifications from the process, or 0 if the watchdog is not
+// enabled for the process
+func WatchdogInterval() time.Duration {
+	if pid := os.Getenv("WATCHDOG_PID"); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return 0
+	}
+
+	usec, err := strconv.ParseInt(os.Getenv("WATCHDOG_USEC"), 10, 64)
+	if err != nil || usec <= 0 {
+		return 0
+	}
+	return time.Duration(usec) * time.Microsecond
+}
@@ -0,0 +1,76 @@
+package systemd
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseListenEnv(t *testing.T) {
+	names, err := parseListenEnv(100, "", "", "")
+	assert.NoError(t, err)
+	assert.Empty(t, names)
+
+	// sockets of another process are ignored
+	names, err = parseListenEnv(100, "101", "2", "")
+	assert.NoError(t, err)
+	assert.Empty(t, names)
+
+	names, err = parseListenEnv(100, "100", "3", "client::peer")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"client", "1", "peer"}, names)
+
+	_, err = parseListenEnv(100, "100", "two", "")
+	assert.Error(t, err)
+	_, err = parseListenEnv(100, "pid", "2", "")
+	assert.Error(t, err)
+}
+
+func TestNotify(t *testing.T) {
+	os.Unsetenv("NOTIFY_SOCKET")
+	sent, err := Notify("READY=1")
+	assert.NoError(t, err)
+	assert.False(t, sent)
+
+	dir, err := ioutil.TempDir("", "TestNotify")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	socket := path.Join(dir, "notify")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socket, Net: "unixgram"})
+	require.NoError(t, err)
+	defer conn.Close()
+
+	os.Setenv("NOTIFY_SOCKET", socket)
+	defer os.Unsetenv("NOTIFY_SOCKET")
+
+	sent, err = Notify("READY=1")
+	assert.NoError(t, err)
+	assert.True(t, sent)
+
+	buf := make([]byte, 64)
+	n, err := conn.Read(buf)
+	require.NoError(t, err)
+	assert.Equal(t, "READY=1", string(buf[:n]))
+}
+
+func TestWatchdogInterval(t *testing.T) {
+	defer os.Unsetenv("WATCHDOG_USEC")
+	defer os.Unsetenv("WATCHDOG_PID")
+
+	os.Unsetenv("WATCHDOG_USEC")
+	assert.Equal(t, time.Duration(0), WatchdogInterval())
+
+	os.Setenv("WATCHDOG_USEC", "30000000")
+	assert.Equal(t, 30*time.Second, WatchdogInterval())
+
+	os.Setenv("WATCHDOG_PID", strconv.Itoa(os.Getpid()+1))
+	assert.Equal(t, time.Duration(0), WatchdogInterval())
+}
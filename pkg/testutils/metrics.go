@@ -0,0 +1,59 @@
+package testutils
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// collector replaces the Collect of a Prometheus collector
+type collector struct {
+	prometheus.Collector
+	collect func(ch chan<- prometheus.Metric)
+}
+
+func (c collector) Collect(ch chan<- prometheus.Metric) {
+	c.collect(ch)
+}
+
+// CollectMetrics gathers the metrics sent by collect, or by the Collect of
+// c if collect is nil, and returns their values keyed by the metric name
+// and labels, like glusterd2_volume_started{type="Replicate",volume="gv0"}.
+// The metrics must be described by c.
+func CollectMetrics(c prometheus.Collector, collect func(ch chan<- prometheus.Metric)) (map[string]float64, error) {
+	if collect != nil {
+		c = collector{c, collect}
+	}
+	registry := prometheus.NewPedanticRegistry()
+	if err := registry.Register(c); err != nil {
+		return nil, err
+	}
+	families, err := registry.Gather()
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]float64)
+	for _, f := range families {
+		for _, m := range f.GetMetric() {
+			var labels []string
+			for _, l := range m.GetLabel() {
+				labels = append(labels, fmt.Sprintf("%s=%q", l.GetName(), l.GetValue()))
+			}
+			key := f.GetName()
+			if len(labels) > 0 {
+				key += "{" + strings.Join(labels, ",") + "}"
+			}
+			switch {
+			case m.Gauge != nil:
+				values[key] = m.GetGauge().GetValue()
+			case m.Counter != nil:
+				values[key] = m.GetCounter().GetValue()
+			default:
+				values[key] = m.GetUntyped().GetValue()
+			}
+		}
+	}
+	return values, nil
+}
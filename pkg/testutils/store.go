@@ -0,0 +1,65 @@
+package testutils
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/store"
+
+	"github.com/pborman/uuid"
+	config "github.com/spf13/viper"
+)
+
+// freeURL returns the URL of a free local port
+func freeURL(t *testing.T) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	return fmt.Sprintf("http://%s", l.Addr().String())
+}
+
+// InitStore starts an embedded store in a temporary directory, for the tests
+// of the store transactions. The store is destroyed by the function
+// returned.
+func InitStore(t *testing.T) func() {
+	dir, err := ioutil.TempDir("", "gd2store")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logdir := config.Get("logdir")
+	config.Set("logdir", dir)
+	if gdctx.MyUUID == nil {
+		gdctx.MyUUID = uuid.NewRandom()
+	}
+	if gdctx.MyClusterID == nil {
+		gdctx.MyClusterID = uuid.NewRandom()
+	}
+
+	curl := freeURL(t)
+	conf := &store.Config{
+		Endpoints: []string{curl},
+		CURLs:     []string{curl},
+		PURLs:     []string{freeURL(t)},
+		Dir:       filepath.Join(dir, "store"),
+		ConfFile:  filepath.Join(dir, "store.toml"),
+	}
+	if err := store.Init(conf); err != nil {
+		config.Set("logdir", logdir)
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+
+	return func() {
+		store.Destroy(true)
+		config.Set("logdir", logdir)
+		os.RemoveAll(dir)
+	}
+}
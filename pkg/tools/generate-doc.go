@@ -48,6 +48,9 @@ var pluginMap = map[string]string{
 	"Devic": "plugins/device/api",
 	"Event": "plugins/events/api",
 	"GeoRe": "plugins/georeplication/api",
+	"LogLe": "plugins/logmgmt/api",
+	"LogRo": "plugins/logmgmt/api",
+	"LogsG": "plugins/logmgmt/api",
 	"SelfH": "plugins/glustershd/api", // TODO: change package name to selfheal
 	"Quota": "plugins/quota/api",
 	"Rebal": "plugins/rebalance/api",
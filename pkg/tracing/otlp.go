@@ -0,0 +1,273 @@
+package tracing
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+
+	log "github.com/sirupsen/logrus"
+	flag "github.com/spf13/pflag"
+	config "github.com/spf13/viper"
+	"go.opencensus.io/trace"
+)
+
+// Commandline option for the OTLP exporter
+const otlpEndpointOpt = "otlp-endpoint"
+
+const (
+	otlpTracesPath    = "/v1/traces"
+	otlpBatchSize     = 512
+	otlpFlushInterval = 5 * time.Second
+	otlpTimeout       = 10 * time.Second
+
+	// OTLP status codes
+	otlpStatusOk    = 1
+	otlpStatusError = 2
+)
+
+// otlpExporter is an opencensus trace exporter which sends spans to an
+// OpenTelemetry collector using OTLP over HTTP with JSON encoding
+type otlpExporter struct {
+	sync.Mutex
+	endpoint string
+	resource otlpResource
+	client   *http.Client
+	spans    []otlpSpan
+	flushCh  chan struct{}
+}
+
+// The following types are the JSON representation of the OTLP trace
+// protobuf messages. Only the fields used by glusterd2 are defined.
+type otlpKeyValue struct {
+	Key   string                 `json:"key"`
+	Value map[string]interface{} `json:"value"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpEvent struct {
+	TimeUnixNano string         `json:"timeUnixNano"`
+	Name         string         `json:"name"`
+	Attributes   []otlpKeyValue `json:"attributes,omitempty"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Events            []otlpEvent    `json:"events,omitempty"`
+	Status            otlpStatus     `json:"status"`
+}
+
+type otlpScopeSpans struct {
+	Scope map[string]string `json:"scope"`
+	Spans []otlpSpan        `json:"spans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpTraceRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+// OTLP exporter
+var otlpExp *otlpExporter
+
+func otlpAttributes(attrs map[string]interface{}) []otlpKeyValue {
+	kvs := make([]otlpKeyValue, 0, len(attrs))
+	for k, v := range attrs {
+		var value map[string]interface{}
+		switch v := v.(type) {
+		case bool:
+			value = map[string]interface{}{"boolValue": v}
+		case int64:
+			// 64 bit integers are encoded as strings in JSON
+			value = map[string]interface{}{"intValue": strconv.FormatInt(v, 10)}
+		default:
+			value = map[string]interface{}{"stringValue": fmt.Sprint(v)}
+		}
+		kvs = append(kvs, otlpKeyValue{Key: k, Value: value})
+	}
+	return kvs
+}
+
+func unixNano(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
+
+func toOTLPSpan(sd *trace.SpanData) otlpSpan {
+	s := otlpSpan{
+		TraceID:           hex.EncodeToString(sd.TraceID[:]),
+		SpanID:            hex.EncodeToString(sd.SpanID[:]),
+		Name:              sd.Name,
+		StartTimeUnixNano: unixNano(sd.StartTime),
+		EndTimeUnixNano:   unixNano(sd.EndTime),
+		Attributes:        otlpAttributes(sd.Attributes),
+		Status:            otlpStatus{Code: otlpStatusOk},
+	}
+	if sd.ParentSpanID != (trace.SpanID{}) {
+		s.ParentSpanID = hex.EncodeToString(sd.ParentSpanID[:])
+	}
+	if sd.Status.Code != trace.StatusCodeOK {
+		s.Status = otlpStatus{Code: otlpStatusError, Message: sd.Status.Message}
+	}
+	for _, a := range sd.Annotations {
+		s.Events = append(s.Events, otlpEvent{
+			TimeUnixNano: unixNano(a.Time),
+			Name:         a.Message,
+			Attributes:   otlpAttributes(a.Attributes),
+		})
+	}
+	return s
+}
+
+// ExportSpan queues the span to be sent to the collector in the next batch
+func (e *otlpExporter) ExportSpan(sd *trace.SpanData) {
+	e.Lock()
+	e.spans = append(e.spans, toOTLPSpan(sd))
+	full := len(e.spans) >= otlpBatchSize
+	e.Unlock()
+
+	if full {
+		select {
+		case e.flushCh <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (e *otlpExporter) run() {
+	ticker := time.NewTicker(otlpFlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-e.flushCh:
+		}
+		e.flush()
+	}
+}
+
+// flush sends all the queued spans to the collector
+func (e *otlpExporter) flush() {
+	e.Lock()
+	spans := e.spans
+	e.spans = nil
+	e.Unlock()
+
+	if len(spans) == 0 {
+		return
+	}
+
+	req := otlpTraceRequest{
+		ResourceSpans: []otlpResourceSpans{{
+			Resource: e.resource,
+			ScopeSpans: []otlpScopeSpans{{
+				Scope: map[string]string{"name": "glusterd2"},
+				Spans: spans,
+			}},
+		}},
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		log.WithError(err).Warning("tracing: failed to marshal spans")
+		return
+	}
+
+	resp, err := e.client.Post(e.endpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		log.WithError(err).WithField("otlpEndpoint", e.endpoint).Warning("tracing: failed to export spans")
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		log.WithFields(log.Fields{
+			"otlpEndpoint": e.endpoint,
+			"status":       resp.Status,
+			"spans":        len(spans),
+		}).Warning("tracing: OTLP collector rejected spans")
+	}
+}
+
+// otlpTracesURL returns the URL to which traces are to be posted. If the
+// configured endpoint has no path, the default OTLP/HTTP traces path is used.
+func otlpTracesURL(endpoint string) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("unsupported scheme %q in OTLP endpoint", u.Scheme)
+	}
+	if u.Path == "" || u.Path == "/" {
+		u.Path = otlpTracesPath
+	}
+	return u.String(), nil
+}
+
+// InitOTLPExporter initializes an exporter which sends traces to the
+// OpenTelemetry collector configured with the otlp-endpoint option. It
+// returns false if no endpoint is configured or the endpoint is invalid.
+// It is to be called after InitJaegerExporter.
+func InitOTLPExporter() bool {
+	endpoint := config.GetString(otlpEndpointOpt)
+	if endpoint == "" {
+		return false
+	}
+
+	tracesURL, err := otlpTracesURL(endpoint)
+	if err != nil {
+		log.WithError(err).WithField("otlpEndpoint", endpoint).Warning("tracing: invalid OTLP endpoint")
+		return false
+	}
+
+	otlpExp = &otlpExporter{
+		endpoint: tracesURL,
+		resource: otlpResource{
+			Attributes: otlpAttributes(map[string]interface{}{
+				"service.name":        "glusterd2",
+				"service.instance.id": gdctx.MyUUID.String(),
+				"host.name":           gdctx.HostName,
+			}),
+		},
+		client:  &http.Client{Timeout: otlpTimeout},
+		flushCh: make(chan struct{}, 1),
+	}
+	go otlpExp.run()
+	trace.RegisterExporter(otlpExp)
+
+	// The spans are sampled by the sampler shared with the Jaeger exporter.
+	// It is applied here if no Jaeger exporter was initialized.
+	if jaegerExporter == nil {
+		ApplySampler(samplerConfig())
+	}
+
+	log.WithField("otlpEndpoint", tracesURL).Info("tracing: Registered OTLP exporter for traces")
+	return true
+}
+
+func initOTLPFlags() {
+	flag.String(otlpEndpointOpt, "", "OpenTelemetry collector endpoint (OTLP over HTTP) to which traces are exported.")
+}
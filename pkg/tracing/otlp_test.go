@@ -0,0 +1,114 @@
+package tracing
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/trace"
+)
+
+func TestOTLPTracesURL(t *testing.T) {
+	u, err := otlpTracesURL("http://collector:4318")
+	require.NoError(t, err)
+	assert.Equal(t, "http://collector:4318/v1/traces", u)
+
+	u, err = otlpTracesURL("https://collector/otlp/traces")
+	require.NoError(t, err)
+	assert.Equal(t, "https://collector/otlp/traces", u)
+
+	_, err = otlpTracesURL("grpc://collector:4317")
+	assert.NotNil(t, err)
+}
+
+func TestToOTLPSpan(t *testing.T) {
+	start := time.Unix(1500000000, 5)
+	sd := &trace.SpanData{
+		SpanContext: trace.SpanContext{
+			TraceID: trace.TraceID{0x01, 0x02},
+			SpanID:  trace.SpanID{0x0a},
+		},
+		Name:       "txn.Step",
+		StartTime:  start,
+		EndTime:    start.Add(time.Second),
+		Attributes: map[string]interface{}{"node": "n1", "count": int64(3), "local": true},
+		Annotations: []trace.Annotation{
+			{Time: start, Message: "retried", Attributes: map[string]interface{}{"attempt": int64(2)}},
+		},
+	}
+
+	s := toOTLPSpan(sd)
+	assert.Equal(t, "01020000000000000000000000000000", s.TraceID)
+	assert.Equal(t, "0a00000000000000", s.SpanID)
+	// Root spans have no parent
+	assert.Empty(t, s.ParentSpanID)
+	assert.Equal(t, "1500000000000000005", s.StartTimeUnixNano)
+	assert.Equal(t, "1500000001000000005", s.EndTimeUnixNano)
+	assert.Equal(t, otlpStatus{Code: otlpStatusOk}, s.Status)
+	assert.ElementsMatch(t, []otlpKeyValue{
+		{Key: "node", Value: map[string]interface{}{"stringValue": "n1"}},
+		// 64 bit integers are strings in the JSON encoding of OTLP
+		{Key: "count", Value: map[string]interface{}{"intValue": "3"}},
+		{Key: "local", Value: map[string]interface{}{"boolValue": true}},
+	}, s.Attributes)
+	require.Len(t, s.Events, 1)
+	assert.Equal(t, otlpEvent{
+		TimeUnixNano: "1500000000000000005",
+		Name:         "retried",
+		Attributes:   []otlpKeyValue{{Key: "attempt", Value: map[string]interface{}{"intValue": "2"}}},
+	}, s.Events[0])
+
+	sd.ParentSpanID = trace.SpanID{0x0b}
+	sd.Status = trace.Status{Code: trace.StatusCodeUnknown, Message: "step failed"}
+	s = toOTLPSpan(sd)
+	assert.Equal(t, "0b00000000000000", s.ParentSpanID)
+	assert.Equal(t, otlpStatus{Code: otlpStatusError, Message: "step failed"}, s.Status)
+}
+
+func TestOTLPExporterBatch(t *testing.T) {
+	var reqs []otlpTraceRequest
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		var req otlpTraceRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		reqs = append(reqs, req)
+	}))
+	defer collector.Close()
+
+	e := &otlpExporter{
+		endpoint: collector.URL + otlpTracesPath,
+		resource: otlpResource{Attributes: otlpAttributes(map[string]interface{}{"service.name": "glusterd2"})},
+		client:   collector.Client(),
+		flushCh:  make(chan struct{}, 1),
+	}
+
+	// Nothing is sent without spans
+	e.flush()
+	assert.Empty(t, reqs)
+
+	// A full batch asks for the spans to be flushed
+	for i := 0; i < otlpBatchSize-1; i++ {
+		e.ExportSpan(&trace.SpanData{Name: "span"})
+	}
+	assert.Len(t, e.flushCh, 0)
+	e.ExportSpan(&trace.SpanData{Name: "span"})
+	assert.Len(t, e.flushCh, 1)
+
+	// All the queued spans are sent in a single request
+	e.flush()
+	require.Len(t, reqs, 1)
+	require.Len(t, reqs[0].ResourceSpans, 1)
+	rs := reqs[0].ResourceSpans[0]
+	assert.Equal(t, e.resource, rs.Resource)
+	require.Len(t, rs.ScopeSpans, 1)
+	assert.Equal(t, map[string]string{"name": "glusterd2"}, rs.ScopeSpans[0].Scope)
+	assert.Len(t, rs.ScopeSpans[0].Spans, otlpBatchSize)
+	assert.Empty(t, e.spans)
+
+	e.flush()
+	assert.Len(t, reqs, 1)
+}
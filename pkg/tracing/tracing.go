@@ -24,33 +24,21 @@ const (
 	jaegerSampleFractionOpt = "jaeger-sample-fraction"
 )
 
-// JaegerSamplerType to indicate different Jaeger sampler type
-type JaegerSamplerType uint8
+// JaegerSamplerType to indicate different Jaeger sampler type. It is
+// defined along with the tracemgmt API so that clients can use it without
+// importing this package.
+type JaegerSamplerType = tracemgmtapi.JaegerSamplerType
 
 // Sampler types in Jaeger
 const (
-	// 'Never' - Don't sample any trace
-	Never JaegerSamplerType = iota
-	// 'Always' - Sample every trace
-	Always
-	// 'Probabilistic' - Sample based on sample fraction
-	Probabilistic
+	Never         = tracemgmtapi.Never
+	Always        = tracemgmtapi.Always
+	Probabilistic = tracemgmtapi.Probabilistic
 )
 
 // SamplerTypeToString returns string representation of sampler type.
 func SamplerTypeToString(samplerType JaegerSamplerType) string {
-	sampler := ""
-	switch samplerType {
-	case Never:
-		sampler = "Never"
-	case Always:
-		sampler = "Always"
-	case Probabilistic:
-		sampler = "Probabilistic"
-	default:
-		sampler = "Unknown"
-	}
-	return sampler
+	return tracemgmtapi.SamplerTypeToString(samplerType)
 }
 
 // DefaultSampleFraction - Default sample fraction. By default every 1 in 10
@@ -162,11 +150,14 @@ func JaegerExporter() *jaeger.Exporter {
 	return jaegerExporter
 }
 
-// Flush any outstanding spans to the Jaeger endpoint
+// Flush any outstanding spans to the Jaeger and OTLP endpoints
 func Flush() {
 	if jaegerExporter != nil {
 		jaegerExporter.Flush()
 	}
+	if otlpExp != nil {
+		otlpExp.flush()
+	}
 }
 
 // InitFlags initializes the command line options for GD2 tracing endpoints
@@ -175,6 +166,7 @@ func InitFlags() {
 	flag.String(jaegerAgentEndpointOpt, "", "Jaeger agent endpoint that the Jaeger client sends spans to.")
 	flag.String(jaegerSamplerOpt, "", "Jaeger sampler to employ (0 - never or 1 - always or 2 - probabilistic).")
 	flag.String(jaegerSampleFractionOpt, "", "Jaeger sample fraction to use if sampler type is set to probabilistic.")
+	initOTLPFlags()
 }
 
 // ApplySampler sets the desired sampler type
@@ -194,6 +186,40 @@ func ApplySampler(sampler int, sampleFraction float64) {
 	}
 }
 
+// samplerConfig returns the sampler type and the sample fraction set with
+// the command line options. Traces are sampled with the default sample
+// fraction if no sampler is set.
+func samplerConfig() (int, float64) {
+	if config.GetString(jaegerSamplerOpt) == "" {
+		return int(Probabilistic), DefaultSampleFraction
+	}
+
+	// Get the sampler type & sample fraction if required
+	sampler := config.GetInt(jaegerSamplerOpt)
+
+	// Validate sampler. Disable tracing if invalid.
+	if err := ValidateJaegerSampler(sampler); err != nil {
+		sampler = int(Never)
+	}
+
+	// Validate the sample fraction. Implicitly set the sample fraction if the
+	// sampler is either "Never" or "Always". Client is not expected to set
+	// sample fraction for such samplers.
+	sampleFraction := config.GetFloat64(jaegerSampleFractionOpt)
+	switch JaegerSamplerType(sampler) {
+	case Never:
+		sampleFraction = 0.0
+	case Always:
+		sampleFraction = 1.0
+	case Probabilistic:
+		if err := ValidateJaegerProbSampleFraction(sampler, sampleFraction); err != nil {
+			// Set default sample fraction
+			sampleFraction = DefaultSampleFraction
+		}
+	}
+	return sampler, sampleFraction
+}
+
 // InitJaegerExporter initializes the jaeger exporter as the tracing endpoint
 // This should be called early when a process starts.
 // This creates and returns an exporter if successful.
@@ -244,29 +270,7 @@ func InitJaegerExporter() *jaeger.Exporter {
 		sampler = storeTraceConfig.JaegerSampler
 		sampleFraction = storeTraceConfig.JaegerSampleFraction
 	} else {
-		// Get the sampler type & sample fraction if required
-		sampler := config.GetInt(jaegerSamplerOpt)
-
-		// Validate sampler. Disable tracing if invalid.
-		if err := ValidateJaegerSampler(sampler); err != nil {
-			sampler = int(Never)
-		}
-
-		// Validate the sample fraction. Implicitly set the sample fraction if the
-		// sampler is either "Never" or "Always". Client is not expected to set
-		// sample fraction for such samplers.
-		sampleFraction = config.GetFloat64(jaegerSampleFractionOpt)
-		switch JaegerSamplerType(sampler) {
-		case Never:
-			sampleFraction = 0.0
-		case Always:
-			sampleFraction = 1.0
-		case Probabilistic:
-			if err := ValidateJaegerProbSampleFraction(sampler, sampleFraction); err != nil {
-				// Set default sample fraction
-				sampleFraction = DefaultSampleFraction
-			}
-		}
+		sampler, sampleFraction = samplerConfig()
 	}
 
 	// Apply the sample type based on config settings
@@ -0,0 +1,11 @@
+// +build !windows
+
+package utils
+
+import "golang.org/x/sys/unix"
+
+// checkWritable returns an error if the directory does not have write
+// permission
+func checkWritable(path string) error {
+	return unix.Access(path, unix.W_OK)
+}
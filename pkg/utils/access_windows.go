@@ -0,0 +1,17 @@
+package utils
+
+import (
+	"io/ioutil"
+	"os"
+)
+
+// checkWritable returns an error if files can not be created in the
+// directory. Windows has no access(2), so a file is created to check.
+func checkWritable(path string) error {
+	f, err := ioutil.TempFile(path, ".access")
+	if err != nil {
+		return err
+	}
+	f.Close()
+	return os.Remove(f.Name())
+}
@@ -1,10 +1,36 @@
 package utils
 
-import "reflect"
+import (
+	"reflect"
+	"sync"
+)
+
+// types holds the types passed to GetTypeString, by their string
+var types = struct {
+	sync.Mutex
+	m map[string]reflect.Type
+}{m: make(map[string]reflect.Type)}
 
 // GetTypeString returns the type of instance passed, as a string.
 // Go doesn't have type literals. Hence one has to pass (*Type)(nil)
-// as argument to this function.
+// as argument to this function. The type can be looked up by the string
+// with GetType.
 func GetTypeString(i interface{}) string {
-	return reflect.TypeOf(i).Elem().String()
+	t := reflect.TypeOf(i).Elem()
+	s := t.String()
+
+	types.Lock()
+	types.m[s] = t
+	types.Unlock()
+
+	return s
+}
+
+// GetType returns the type whose string was returned by GetTypeString
+func GetType(s string) (reflect.Type, bool) {
+	types.Lock()
+	defer types.Unlock()
+
+	t, ok := types.m[s]
+	return t, ok
 }
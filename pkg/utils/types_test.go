@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/gluster/glusterd2/pkg/api"
@@ -12,3 +13,12 @@ func TestGetTypeString(t *testing.T) {
 	assert.Equal(t, resp, "api.PeerGetResp")
 
 }
+
+func TestGetType(t *testing.T) {
+	typ, ok := GetType(GetTypeString((*api.VolCreateReq)(nil)))
+	assert.True(t, ok)
+	assert.Equal(t, reflect.TypeOf(api.VolCreateReq{}), typ)
+
+	_, ok = GetType("api.NoSuchType")
+	assert.False(t, ok)
+}
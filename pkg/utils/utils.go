@@ -13,8 +13,6 @@ import (
 	"strings"
 	"syscall"
 
-	"golang.org/x/sys/unix"
-
 	"github.com/gluster/glusterd2/pkg/errors"
 	log "github.com/sirupsen/logrus"
 )
@@ -102,7 +100,7 @@ func InitDir(path string) error {
 		return err
 	}
 
-	if err := unix.Access(path, unix.W_OK); err != nil {
+	if err := checkWritable(path); err != nil {
 		log.WithError(err).WithField("path", path).Debug(
 			"directory does not have write permission")
 		return err
@@ -236,6 +234,34 @@ func ExecuteCommandRun(cmdName string, arg ...string) error {
 	return execStderrCombined(cmd.Run(), &stderr)
 }
 
+// ExecuteCommandRunInput runs the command with the input on its stdin,
+// and adds additional error information
+func ExecuteCommandRunInput(input []byte, cmdName string, arg ...string) error {
+	cmd := exec.Command(cmdName, arg...)
+	cmd.Stdin = bytes.NewReader(input)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	return execStderrCombined(cmd.Run(), &stderr)
+}
+
+// ExecuteCommandOutputInput runs the command with the input on its stdin,
+// and returns its output with additional error information
+func ExecuteCommandOutputInput(input []byte, cmdName string, arg ...string) ([]byte, error) {
+	cmd := exec.Command(cmdName, arg...)
+	cmd.Stdin = bytes.NewReader(input)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+
+	if err != nil {
+		return out, execStderrCombined(err, &stderr)
+	}
+
+	return out, nil
+}
+
 //GenerateQsh generate the hash string to avoid URL tampering
 func GenerateQsh(r *http.Request) string {
 	// qsh URL tampering prevention.
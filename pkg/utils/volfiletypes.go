@@ -15,8 +15,13 @@ const (
 	ScrubdVolfile = "scrubd"
 	// GfProxyVolfile is a name of gfproxy volfile template
 	GfProxyVolfile = "gfproxy"
+	// GfProxyClientVolfile is a name of the template of the thin clients
+	// using gfproxy
+	GfProxyClientVolfile = "gfproxy-client"
 	// NFSVolfile is a name of nfs volfile template
 	NFSVolfile = "nfs"
+	// QuotadVolfile is a name of quotad volfile template
+	QuotadVolfile = "quotad"
 )
 
 // ValidVolfiles represents list of valid volfile names
@@ -28,5 +33,7 @@ var ValidVolfiles = [...]string{
 	BitdVolfile,
 	ScrubdVolfile,
 	GfProxyVolfile,
+	GfProxyClientVolfile,
 	NFSVolfile,
+	QuotadVolfile,
 }
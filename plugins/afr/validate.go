@@ -2,9 +2,14 @@ package afr
 
 import (
 	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 
+	"github.com/gluster/glusterd2/glusterd2/halo"
 	"github.com/gluster/glusterd2/glusterd2/volume"
 	"github.com/gluster/glusterd2/glusterd2/xlator"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
 )
 
 var names = [...]string{"replicate", "afr"}
@@ -19,7 +24,40 @@ func isVolReplicate(vType volume.VolType) bool {
 	return false
 }
 
+// validateHaloOptions validates the halo options, which apply only to
+// replicate volumes and whose replica counts must be consistent
+func validateHaloOptions(v *volume.Volinfo, key string, value string) error {
+	if v.Type != volume.Replicate && v.Type != volume.DistReplicate {
+		return gderrors.ErrHaloNotReplicate
+	}
+
+	switch key {
+	case halo.OptMinReplicas, halo.OptMaxReplicas:
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			return fmt.Errorf("%s must be a positive integer", key)
+		}
+		min, max := n, n
+		if key == halo.OptMinReplicas {
+			max, _ = strconv.Atoi(halo.Option(v, halo.OptMaxReplicas))
+		} else {
+			min, _ = strconv.Atoi(halo.Option(v, halo.OptMinReplicas))
+		}
+		if min > max {
+			return fmt.Errorf("%s must not be greater than %s", halo.OptMinReplicas, halo.OptMaxReplicas)
+		}
+		if key == halo.OptMinReplicas && n > v.Subvols[0].ReplicaCount {
+			return fmt.Errorf("%s must not be greater than the replica count %d", key, v.Subvols[0].ReplicaCount)
+		}
+	}
+	return nil
+}
+
 func validateOptions(v *volume.Volinfo, key string, value string) error {
+	if strings.HasPrefix(key, "halo-") {
+		return validateHaloOptions(v, key, value)
+	}
+
 	switch key {
 	case "metadata-self-heal":
 		if v.Subvols[0].ReplicaCount == 1 {
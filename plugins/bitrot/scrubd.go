@@ -6,8 +6,10 @@ import (
 	"os"
 	"os/exec"
 	"path"
+	"time"
 
 	"github.com/cespare/xxhash"
+	"github.com/gluster/glusterd2/glusterd2/daemon"
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
 	config "github.com/spf13/viper"
 )
@@ -101,3 +103,16 @@ func newScrubd() (*Scrubd, error) {
 func (s *Scrubd) ID() string {
 	return ""
 }
+
+func init() {
+	// bitd signs the files of the bricks and scrubd verifies them, both
+	// connecting to the bricks of this peer
+	for _, name := range []string{"bitd", "scrubd"} {
+		daemon.RegisterPolicy(name, daemon.Policy{
+			DependsOn:     []string{"glusterfsd"},
+			Restart:       daemon.RestartAlways,
+			MaxRestarts:   5,
+			RestartWindow: 10 * time.Minute,
+		})
+	}
+}
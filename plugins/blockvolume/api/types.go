@@ -41,6 +41,12 @@ type BlockVolumeCreateResp struct {
 	Password string   `json:"password,omitempty"`
 }
 
+// BlockVolumeResizeReq represents req Body for Block vol resize req
+type BlockVolumeResizeReq struct {
+	// Size represents the new Block Volume size in bytes
+	Size uint64 `json:"size"`
+}
+
 // BlockVolumeListResp represents resp body for a Block List req
 type BlockVolumeListResp []BlockVolumeInfo
 
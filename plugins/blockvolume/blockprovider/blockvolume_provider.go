@@ -25,6 +25,12 @@ type Provider interface {
 	ProviderName() string
 }
 
+// Resizer is implemented by the block providers which are able to grow
+// existing block volumes
+type Resizer interface {
+	ResizeBlockVolume(name string, size uint64) (BlockVolume, error)
+}
+
 // BlockVolume is an interface which provides information about a block volume
 type BlockVolume interface {
 	Name() string
@@ -0,0 +1,439 @@
+package glustertcmu
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path"
+	"strconv"
+	"syscall"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/errors"
+	"github.com/gluster/glusterd2/plugins/blockvolume/blockprovider"
+	"github.com/gluster/glusterd2/plugins/blockvolume/hostvol"
+
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+	config "github.com/spf13/viper"
+)
+
+const (
+	providerName = "tcmu"
+	// defaultHaCount is the number of peers exporting a block volume if
+	// neither hosts nor a ha count is requested
+	defaultHaCount = 3
+)
+
+func init() {
+	blockprovider.RegisterBlockProvider(providerName, newGlusterTcmu)
+}
+
+// GlusterTcmu implements block Provider interface. Its block volumes are
+// files on the hosting volume, exported as LUNs by tcmu-runner through an
+// iSCSI target on each of the selected peers. All the targets share the same
+// IQN and LUN serial, so that initiators see the portals as paths of a
+// single multipath device.
+type GlusterTcmu struct{}
+
+func newGlusterTcmu() (blockprovider.Provider, error) {
+	return &GlusterTcmu{}, nil
+}
+
+// withHostVolMount mounts the hosting volume on a temporary directory for
+// the duration of fn
+func withHostVolMount(hostVolume string, fn func(mountpoint string) error) error {
+	tempDir, err := ioutil.TempDir(config.GetString("rundir"), "gd2mount")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tempDir)
+
+	if err := volume.MountVolume(hostVolume, tempDir, ""); err != nil {
+		return err
+	}
+	defer syscall.Unmount(tempDir, syscall.MNT_FORCE)
+
+	return fn(tempDir)
+}
+
+// findPeer looks up a peer by its ID, name or one of its addresses
+func findPeer(host string) (*peer.Peer, error) {
+	if p, err := peer.GetPeerByName(host); err == nil {
+		return p, nil
+	}
+	if p, err := peer.GetPeerByAddr(host); err == nil {
+		return p, nil
+	}
+	if uuid.Parse(host) != nil {
+		return peer.GetPeer(host)
+	}
+	return nil, fmt.Errorf("peer %s not found", host)
+}
+
+// selectTargetPeers returns the peers exporting a new block volume. These
+// are the requested hosts if any, otherwise the first haCount peers hosting
+// bricks of the hosting volume.
+func selectTargetPeers(hostVol *volume.Volinfo, hosts []string, haCount int) ([]*peer.Peer, error) {
+	var peers []*peer.Peer
+
+	if len(hosts) != 0 {
+		for _, host := range hosts {
+			p, err := findPeer(host)
+			if err != nil {
+				return nil, err
+			}
+			peers = append(peers, p)
+		}
+		return peers, nil
+	}
+
+	nodes := hostVol.Nodes()
+	if haCount == 0 {
+		haCount = defaultHaCount
+		if len(nodes) < haCount {
+			haCount = len(nodes)
+		}
+	}
+	if haCount > len(nodes) {
+		return nil, fmt.Errorf("ha count %d is more than the %d peers hosting volume %s", haCount, len(nodes), hostVol.Name)
+	}
+
+	for _, node := range nodes[:haCount] {
+		p, err := peer.GetPeer(node.String())
+		if err != nil {
+			return nil, err
+		}
+		peers = append(peers, p)
+	}
+	return peers, nil
+}
+
+// portalOf returns the iSCSI portal of the peer, on its first peer address
+func portalOf(p *peer.Peer) string {
+	host := p.PeerAddresses[0]
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return net.JoinHostPort(host, iscsiPort)
+}
+
+// reserveHostVolSpace updates the available size of the hosting volume by
+// delta bytes, failing if the hosting volume is short of space
+func reserveHostVolSpace(hostVolume string, delta int64) error {
+	clusterLocks := transaction.Locks{}
+	if err := clusterLocks.Lock(hostVolume); err != nil {
+		return err
+	}
+	defer clusterLocks.UnLock(context.Background())
+
+	volInfo, err := volume.GetVolume(hostVolume)
+	if err != nil {
+		return err
+	}
+
+	available, err := strconv.ParseUint(volInfo.Metadata[volume.BlockHostingAvailableSize], 10, 64)
+	if err != nil {
+		return err
+	}
+	if delta > 0 && available < uint64(delta) {
+		return fmt.Errorf("available size of hosting volume %s is %d, requested %d", hostVolume, available, delta)
+	}
+
+	resizeFunc := func(blockHostingAvailableSize, blockSize uint64) uint64 { return blockHostingAvailableSize - blockSize }
+	if delta < 0 {
+		delta = -delta
+		resizeFunc = func(blockHostingAvailableSize, blockSize uint64) uint64 { return blockHostingAvailableSize + blockSize }
+	}
+	if err := hostvol.UpdateBlockHostingVolumeSize(volInfo, uint64(delta), resizeFunc); err != nil {
+		return err
+	}
+	return volume.AddOrUpdateVolume(volInfo)
+}
+
+func newTxn(name string) (*transaction.Txn, error) {
+	ctx := gdctx.WithReqLogger(context.Background(), log.StandardLogger())
+	return transaction.NewTxnWithLocks(ctx, path.Join(blockVolsPrefix, name))
+}
+
+// CreateBlockVolume will create a block volume with given name and size having `hostVolume` as hosting volume
+func (g *GlusterTcmu) CreateBlockVolume(name string, size uint64, hostVolume string, options ...blockprovider.BlockVolOption) (blockprovider.BlockVolume, error) {
+	blockVolOpts := &blockprovider.BlockVolumeOptions{}
+	blockVolOpts.ApplyOpts(options...)
+	logger := log.WithFields(log.Fields{
+		"block_name":           name,
+		"hostvol":              hostVolume,
+		"requested_block_size": size,
+	})
+
+	txn, err := newTxn(name)
+	if err != nil {
+		return nil, err
+	}
+	defer txn.Done()
+
+	if _, err := getBlockVol(name); err == nil {
+		return nil, errors.ErrBlockVolExists
+	}
+
+	hostVol, err := volume.GetVolume(hostVolume)
+	if err != nil {
+		return nil, err
+	}
+
+	peers, err := selectTargetPeers(hostVol, blockVolOpts.Hosts, blockVolOpts.Ha)
+	if err != nil {
+		logger.WithError(err).Error("failed to select the peers exporting the block volume")
+		return nil, err
+	}
+
+	wwn := uuid.NewRandom().String()
+	rec := &blockVolRecord{
+		Name:       name,
+		HostVolume: hostVolume,
+		Size:       size,
+		WWN:        wwn,
+		IQN:        iqnPrefix + wwn,
+	}
+	for _, p := range peers {
+		rec.Nodes = append(rec.Nodes, p.ID)
+		rec.Portals = append(rec.Portals, portalOf(p))
+	}
+	if blockVolOpts.Auth {
+		rec.Username = wwn
+		rec.Password = uuid.NewRandom().String()
+	}
+
+	if err := reserveHostVolSpace(hostVolume, int64(size)); err != nil {
+		logger.WithError(err).Error("failed to reserve space on the hosting volume")
+		return nil, err
+	}
+
+	err = withHostVolMount(hostVolume, func(mountpoint string) error {
+		if err := os.MkdirAll(path.Join(mountpoint, blockStoreDir), 0755); err != nil {
+			return err
+		}
+		f, err := os.OpenFile(path.Join(mountpoint, backingFile(name)), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		if blockVolOpts.FullPrealloc {
+			return syscall.Fallocate(int(f.Fd()), 0, 0, int64(size))
+		}
+		return f.Truncate(int64(size))
+	})
+	if err != nil {
+		logger.WithError(err).Error("failed to create the file backing the block volume")
+		reserveHostVolSpace(hostVolume, -int64(size))
+		return nil, err
+	}
+
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc:   "block-tcmu.CreateTarget",
+			UndoFunc: "block-tcmu.DeleteTarget",
+			Nodes:    rec.Nodes,
+		},
+	}
+	if err := txn.Ctx.Set("blockvol", rec); err != nil {
+		return nil, err
+	}
+
+	if err = txn.Do(); err == nil {
+		err = putBlockVol(rec)
+	}
+	if err != nil {
+		logger.WithError(err).Error("failed to export the block volume")
+		removeBackingFile(rec, logger)
+		reserveHostVolSpace(hostVolume, -int64(size))
+		return nil, err
+	}
+
+	return newBlockVolume(rec), nil
+}
+
+func removeBackingFile(rec *blockVolRecord, logger log.FieldLogger) {
+	err := withHostVolMount(rec.HostVolume, func(mountpoint string) error {
+		return os.Remove(path.Join(mountpoint, backingFile(rec.Name)))
+	})
+	if err != nil {
+		logger.WithError(err).WithField("block", rec.Name).Error("failed to remove the file backing the block volume")
+	}
+}
+
+// DeleteBlockVolume deletes a block volume of give name
+func (g *GlusterTcmu) DeleteBlockVolume(name string, options ...blockprovider.BlockVolOption) error {
+	logger := log.WithField("block_name", name)
+
+	txn, err := newTxn(name)
+	if err != nil {
+		return err
+	}
+	defer txn.Done()
+
+	rec, err := getBlockVol(name)
+	if err != nil {
+		return err
+	}
+
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc: "block-tcmu.DeleteTarget",
+			Nodes:  rec.Nodes,
+		},
+	}
+	if err := txn.Ctx.Set("blockvol", rec); err != nil {
+		return err
+	}
+
+	if err := txn.Do(); err != nil {
+		logger.WithError(err).Error("failed to remove the iSCSI targets of the block volume")
+		return err
+	}
+
+	if err := deleteBlockVol(name); err != nil {
+		return err
+	}
+
+	removeBackingFile(rec, logger)
+	if err := reserveHostVolSpace(rec.HostVolume, -int64(rec.Size)); err != nil {
+		logger.WithError(err).Error("failed to release space on the hosting volume")
+	}
+	return nil
+}
+
+// ResizeBlockVolume grows a block volume to the given size
+func (g *GlusterTcmu) ResizeBlockVolume(name string, size uint64) (blockprovider.BlockVolume, error) {
+	logger := log.WithFields(log.Fields{
+		"block_name":     name,
+		"requested_size": size,
+	})
+
+	txn, err := newTxn(name)
+	if err != nil {
+		return nil, err
+	}
+	defer txn.Done()
+
+	rec, err := getBlockVol(name)
+	if err != nil {
+		return nil, err
+	}
+	if size < rec.Size {
+		return nil, errors.ErrBlockVolShrinkNotSupported
+	}
+	if size == rec.Size {
+		return newBlockVolume(rec), nil
+	}
+
+	delta := int64(size - rec.Size)
+	if err := reserveHostVolSpace(rec.HostVolume, delta); err != nil {
+		logger.WithError(err).Error("failed to reserve space on the hosting volume")
+		return nil, err
+	}
+
+	err = withHostVolMount(rec.HostVolume, func(mountpoint string) error {
+		return os.Truncate(path.Join(mountpoint, backingFile(name)), int64(size))
+	})
+	if err != nil {
+		logger.WithError(err).Error("failed to grow the file backing the block volume")
+		reserveHostVolSpace(rec.HostVolume, -delta)
+		return nil, err
+	}
+
+	// The file is not shrunk back if the LUNs fail to pick up the new
+	// size, the resize can simply be retried
+	rec.Size = size
+	if err := putBlockVol(rec); err != nil {
+		return nil, err
+	}
+
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc: "block-tcmu.ResizeTarget",
+			Nodes:  rec.Nodes,
+		},
+	}
+	if err := txn.Ctx.Set("blockvol", rec); err != nil {
+		return nil, err
+	}
+
+	if err := txn.Do(); err != nil {
+		logger.WithError(err).Error("failed to resize the LUNs of the block volume")
+		return nil, err
+	}
+
+	return newBlockVolume(rec), nil
+}
+
+// GetBlockVolume gives info about a block volume
+func (g *GlusterTcmu) GetBlockVolume(name string) (blockprovider.BlockVolume, error) {
+	rec, err := getBlockVol(name)
+	if err != nil {
+		return nil, err
+	}
+	return newBlockVolume(rec), nil
+}
+
+// BlockVolumes returns all available block volumes
+func (g *GlusterTcmu) BlockVolumes() []blockprovider.BlockVolume {
+	var blockVolumes = []blockprovider.BlockVolume{}
+
+	recs, err := getBlockVols()
+	if err != nil {
+		return blockVolumes
+	}
+
+	for _, rec := range recs {
+		blockVolumes = append(blockVolumes, newBlockVolume(rec))
+	}
+	return blockVolumes
+}
+
+// ProviderName returns name of block provider
+func (g *GlusterTcmu) ProviderName() string {
+	return providerName
+}
+
+// BlockVolume implements blockprovider.BlockVolume interface.
+// It holds information about a block volume exported by tcmu-runner
+type BlockVolume struct {
+	rec *blockVolRecord
+}
+
+func newBlockVolume(rec *blockVolRecord) *BlockVolume {
+	return &BlockVolume{rec: rec}
+}
+
+// HostAddresses returns the iSCSI portals of the block vol
+func (bv *BlockVolume) HostAddresses() []string { return bv.rec.Portals }
+
+// IQN returns IQN of the block vol
+func (bv *BlockVolume) IQN() string { return bv.rec.IQN }
+
+// Username returns the CHAP username of the block vol
+func (bv *BlockVolume) Username() string { return bv.rec.Username }
+
+// Password returns the CHAP password of the block vol
+func (bv *BlockVolume) Password() string { return bv.rec.Password }
+
+// HostVolume returns host vol name of the block vol
+func (bv *BlockVolume) HostVolume() string { return bv.rec.HostVolume }
+
+// Name returns name of the block vol
+func (bv *BlockVolume) Name() string { return bv.rec.Name }
+
+// Size returns size of the block vol in bytes
+func (bv *BlockVolume) Size() uint64 { return bv.rec.Size }
+
+// ID returns the serial of the LUN of the block vol
+func (bv *BlockVolume) ID() string { return bv.rec.WWN }
+
+// HaCount returns the number of peers exporting the block vol
+func (bv *BlockVolume) HaCount() int { return len(bv.rec.Nodes) }
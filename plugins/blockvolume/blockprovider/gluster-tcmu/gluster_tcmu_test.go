@@ -0,0 +1,40 @@
+package glustertcmu
+
+import (
+	"testing"
+
+	"github.com/gluster/glusterd2/glusterd2/peer"
+
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPortalOf(t *testing.T) {
+	assert.Equal(t, "192.168.1.10:3260", portalOf(&peer.Peer{PeerAddresses: []string{"192.168.1.10:24008"}}))
+	assert.Equal(t, "node1:3260", portalOf(&peer.Peer{PeerAddresses: []string{"node1"}}))
+	assert.Equal(t, "[fe80::1]:3260", portalOf(&peer.Peer{PeerAddresses: []string{"[fe80::1]:24008"}}))
+}
+
+func TestBlockVolume(t *testing.T) {
+	rec := &blockVolRecord{
+		Name:       "block1",
+		HostVolume: "hostvol",
+		Size:       1024,
+		WWN:        "8f1c3a2e-7c1b-4e51-9d5e-0b6a1f2c3d4e",
+		IQN:        iqnPrefix + "8f1c3a2e-7c1b-4e51-9d5e-0b6a1f2c3d4e",
+		Nodes:      []uuid.UUID{uuid.NewRandom(), uuid.NewRandom()},
+		Portals:    []string{"192.168.1.10:3260", "192.168.1.11:3260"},
+		Username:   "user",
+		Password:   "secret",
+	}
+	bv := newBlockVolume(rec)
+	assert.Equal(t, "block1", bv.Name())
+	assert.Equal(t, "hostvol", bv.HostVolume())
+	assert.Equal(t, uint64(1024), bv.Size())
+	assert.Equal(t, rec.WWN, bv.ID())
+	assert.Equal(t, rec.IQN, bv.IQN())
+	assert.Equal(t, rec.Portals, bv.HostAddresses())
+	assert.Equal(t, 2, bv.HaCount())
+	assert.Equal(t, "user", bv.Username())
+	assert.Equal(t, "secret", bv.Password())
+}
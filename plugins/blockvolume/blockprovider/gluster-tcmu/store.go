@@ -0,0 +1,81 @@
+package glustertcmu
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+const blockVolsPrefix = "blockvolumes/tcmu/"
+
+// blockVolRecord is the stored state of a block volume, and is also passed
+// to the peers exporting it through the transaction context
+type blockVolRecord struct {
+	Name       string `json:"name"`
+	HostVolume string `json:"hostvolume"`
+	Size       uint64 `json:"size"`
+	// WWN is the unit serial of the LUN, which is the same on all the
+	// portals so that initiators group them under one multipath device
+	WWN string `json:"wwn"`
+	IQN string `json:"iqn"`
+	// Nodes are the peers exporting the block volume, Portals[i] being
+	// the portal of Nodes[i]
+	Nodes    []uuid.UUID `json:"nodes"`
+	Portals  []string    `json:"portals"`
+	Username string      `json:"username,omitempty"`
+	Password string      `json:"password,omitempty"`
+}
+
+func getBlockVol(name string) (*blockVolRecord, error) {
+	resp, err := store.Get(context.TODO(), blockVolsPrefix+name)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Count != 1 {
+		return nil, errors.ErrBlockVolNotFound
+	}
+
+	var rec blockVolRecord
+	if err := json.Unmarshal(resp.Kvs[0].Value, &rec); err != nil {
+		return nil, err
+	}
+	return &rec, nil
+}
+
+func getBlockVols() ([]*blockVolRecord, error) {
+	resp, err := store.Get(context.TODO(), blockVolsPrefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	recs := make([]*blockVolRecord, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var rec blockVolRecord
+		if err := json.Unmarshal(kv.Value, &rec); err != nil {
+			log.WithError(err).WithField("key", string(kv.Key)).Error("failed to unmarshal block volume")
+			continue
+		}
+		recs = append(recs, &rec)
+	}
+	return recs, nil
+}
+
+func putBlockVol(rec *blockVolRecord) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	_, err = store.Put(context.TODO(), blockVolsPrefix+rec.Name, string(data))
+	return err
+}
+
+func deleteBlockVol(name string) error {
+	_, err := store.Delete(context.TODO(), blockVolsPrefix+name)
+	return err
+}
@@ -0,0 +1,115 @@
+package glustertcmu
+
+import (
+	"fmt"
+	"net"
+	"path"
+	"strconv"
+
+	"github.com/gluster/glusterd2/pkg/utils"
+
+	log "github.com/sirupsen/logrus"
+	config "github.com/spf13/viper"
+)
+
+const (
+	// glfsBackstores is the targetcli path of the backstores handled by
+	// the glfs handler of tcmu-runner
+	glfsBackstores = "/backstores/user:glfs"
+	iscsiTargets   = "/iscsi"
+	iqnPrefix      = "iqn.2016-12.org.gluster-block:"
+	iscsiPort      = "3260"
+	// blockStoreDir is the directory of the hosting volume holding the
+	// files backing the block volumes
+	blockStoreDir = "block-store"
+)
+
+func targetcli(args ...string) error {
+	return utils.ExecuteCommandRun("targetcli", args...)
+}
+
+// volfileServer returns the address tcmu-runner fetches the volfile of the
+// hosting volume from
+func volfileServer() string {
+	host, _, err := net.SplitHostPort(config.GetString("clientaddress"))
+	if err != nil || host == "" {
+		return "127.0.0.1"
+	}
+	return host
+}
+
+func backingFile(name string) string {
+	return path.Join(blockStoreDir, name)
+}
+
+// createTarget configures the LUN backed by the block volume file and the
+// iSCSI target exporting it through the portal of this peer. Whatever was
+// configured is removed if it fails.
+func createTarget(rec *blockVolRecord, portal string, logger log.FieldLogger) (err error) {
+	tpg := path.Join(iscsiTargets, rec.IQN, "tpg1")
+	defer func() {
+		if err != nil {
+			deleteTarget(rec, logger)
+		}
+	}()
+
+	cfgstring := fmt.Sprintf("%s@%s/%s", rec.HostVolume, volfileServer(), backingFile(rec.Name))
+	if err = targetcli(glfsBackstores, "create", "name="+rec.Name,
+		"size="+strconv.FormatUint(rec.Size, 10), "cfgstring="+cfgstring, "wwn="+rec.WWN); err != nil {
+		return err
+	}
+	if err = targetcli(iscsiTargets, "create", rec.IQN); err != nil {
+		return err
+	}
+	if err = targetcli(path.Join(tpg, "luns"), "create", path.Join(glfsBackstores, rec.Name)); err != nil {
+		return err
+	}
+
+	// targetcli listens on all the addresses by default, replace it with
+	// the portal of this peer
+	targetcli(path.Join(tpg, "portals"), "delete", "0.0.0.0", iscsiPort)
+	host, port, err := net.SplitHostPort(portal)
+	if err != nil {
+		return err
+	}
+	if err = targetcli(path.Join(tpg, "portals"), "create", host, port); err != nil {
+		return err
+	}
+
+	if rec.Username != "" {
+		if err = targetcli(tpg, "set", "auth", "userid="+rec.Username, "password="+rec.Password); err != nil {
+			return err
+		}
+		err = targetcli(tpg, "set", "attribute", "authentication=1", "generate_node_acls=1", "demo_mode_write_protect=0")
+	} else {
+		err = targetcli(tpg, "set", "attribute", "authentication=0", "generate_node_acls=1", "demo_mode_write_protect=0")
+	}
+	if err != nil {
+		return err
+	}
+
+	return targetcli("saveconfig")
+}
+
+// deleteTarget removes the iSCSI target and the LUN of the block volume.
+// Failures are logged and ignored so that it can clean up partially
+// configured targets.
+func deleteTarget(rec *blockVolRecord, logger log.FieldLogger) {
+	if err := targetcli(iscsiTargets, "delete", rec.IQN); err != nil {
+		logger.WithError(err).WithField("iqn", rec.IQN).Warn("failed to delete iSCSI target")
+	}
+	if err := targetcli(glfsBackstores, "delete", rec.Name); err != nil {
+		logger.WithError(err).WithField("block", rec.Name).Warn("failed to delete backstore")
+	}
+	if err := targetcli("saveconfig"); err != nil {
+		logger.WithError(err).Warn("failed to save target configuration")
+	}
+}
+
+// resizeTarget makes tcmu-runner pick up the new size of the block volume
+func resizeTarget(rec *blockVolRecord) error {
+	if err := targetcli(path.Join(glfsBackstores, rec.Name), "reconfig", "dev_size", strconv.FormatUint(rec.Size, 10)); err != nil {
+		return err
+	}
+	return targetcli("saveconfig")
+}
@@ -0,0 +1,24 @@
+package glustertcmu
+
+import (
+	"testing"
+
+	config "github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVolfileServer(t *testing.T) {
+	defer config.Set("clientaddress", config.Get("clientaddress"))
+
+	config.Set("clientaddress", "192.168.1.10:24007")
+	assert.Equal(t, "192.168.1.10", volfileServer())
+
+	// tcmu-runner fetches the volfile locally if glusterd2 listens on all
+	// the addresses
+	config.Set("clientaddress", ":24007")
+	assert.Equal(t, "127.0.0.1", volfileServer())
+}
+
+func TestBackingFile(t *testing.T) {
+	assert.Equal(t, "block-store/block1", backingFile("block1"))
+}
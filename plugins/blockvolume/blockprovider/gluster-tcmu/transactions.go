@@ -0,0 +1,78 @@
+package glustertcmu
+
+import (
+	"fmt"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+
+	"github.com/pborman/uuid"
+)
+
+// myPortal returns the portal through which this peer exports the block
+// volume
+func myPortal(rec *blockVolRecord) (string, error) {
+	for i, node := range rec.Nodes {
+		if uuid.Equal(node, gdctx.MyUUID) {
+			return rec.Portals[i], nil
+		}
+	}
+	return "", fmt.Errorf("block volume %s is not exported by this peer", rec.Name)
+}
+
+func txnCreateTarget(c transaction.TxnCtx) error {
+	var rec blockVolRecord
+	if err := c.Get("blockvol", &rec); err != nil {
+		return err
+	}
+
+	portal, err := myPortal(&rec)
+	if err != nil {
+		return err
+	}
+
+	if err := createTarget(&rec, portal, c.Logger()); err != nil {
+		c.Logger().WithError(err).WithField("block", rec.Name).Error("failed to create iSCSI target")
+		return err
+	}
+	return nil
+}
+
+func txnDeleteTarget(c transaction.TxnCtx) error {
+	var rec blockVolRecord
+	if err := c.Get("blockvol", &rec); err != nil {
+		return err
+	}
+
+	deleteTarget(&rec, c.Logger())
+	return nil
+}
+
+func txnResizeTarget(c transaction.TxnCtx) error {
+	var rec blockVolRecord
+	if err := c.Get("blockvol", &rec); err != nil {
+		return err
+	}
+
+	if err := resizeTarget(&rec); err != nil {
+		c.Logger().WithError(err).WithField("block", rec.Name).Error("failed to resize LUN")
+		return err
+	}
+	return nil
+}
+
+// RegisterStepFuncs registers the step functions configuring the iSCSI
+// targets on the peers
+func RegisterStepFuncs() {
+	var sfs = []struct {
+		name string
+		sf   transaction.StepFunc
+	}{
+		{"block-tcmu.CreateTarget", txnCreateTarget},
+		{"block-tcmu.DeleteTarget", txnDeleteTarget},
+		{"block-tcmu.ResizeTarget", txnResizeTarget},
+	}
+	for _, sf := range sfs {
+		transaction.RegisterStepFunc(sf.sf, sf.name)
+	}
+}
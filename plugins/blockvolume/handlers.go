@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/pkg/errors"
 	"github.com/gluster/glusterd2/plugins/blockvolume/api"
 	"github.com/gluster/glusterd2/plugins/blockvolume/blockprovider"
 
@@ -136,3 +137,56 @@ func (b *BlockVolume) GetBlockVolume(w http.ResponseWriter, r *http.Request) {
 
 	utils.SendHTTPResponse(r.Context(), w, http.StatusOK, resp)
 }
+
+// ResizeVolume is a http Handler for growing a block volume
+func (b *BlockVolume) ResizeVolume(w http.ResponseWriter, r *http.Request) {
+	var (
+		req        = &api.BlockVolumeResizeReq{}
+		resp       = &api.BlockVolumeGetResp{}
+		pathParams = mux.Vars(r)
+	)
+
+	if err := utils.UnmarshalRequest(r, req); err != nil {
+		utils.SendHTTPError(r.Context(), w, http.StatusBadRequest, err)
+		return
+	}
+
+	blockProvider, err := blockprovider.GetBlockProvider(pathParams["provider"])
+	if err != nil {
+		utils.SendHTTPError(r.Context(), w, http.StatusInternalServerError, err)
+		return
+	}
+
+	resizer, ok := blockProvider.(blockprovider.Resizer)
+	if !ok {
+		utils.SendHTTPError(r.Context(), w, http.StatusNotImplemented, errors.ErrBlockVolResizeNotSupported)
+		return
+	}
+
+	blockVol, err := resizer.ResizeBlockVolume(pathParams["name"], req.Size)
+	switch err {
+	case nil:
+	case errors.ErrBlockVolNotFound:
+		utils.SendHTTPError(r.Context(), w, http.StatusNotFound, err)
+		return
+	case errors.ErrBlockVolShrinkNotSupported:
+		utils.SendHTTPError(r.Context(), w, http.StatusBadRequest, err)
+		return
+	default:
+		utils.SendHTTPError(r.Context(), w, http.StatusInternalServerError, err)
+		return
+	}
+
+	{
+		resp.BlockVolumeInfo = &api.BlockVolumeInfo{}
+		resp.Name = blockVol.Name()
+		resp.HostingVolume = blockVol.HostVolume()
+		resp.Size = blockVol.Size()
+		resp.Hosts = blockVol.HostAddresses()
+		resp.Password = blockVol.Password()
+		resp.GBID = blockVol.ID()
+		resp.HaCount = blockVol.HaCount()
+	}
+
+	utils.SendHTTPResponse(r.Context(), w, http.StatusOK, resp)
+}
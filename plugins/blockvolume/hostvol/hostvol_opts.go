@@ -5,7 +5,6 @@ import (
 	"strconv"
 
 	"github.com/gluster/glusterd2/glusterd2/options"
-	"github.com/gluster/glusterd2/glusterd2/volume"
 	"github.com/gluster/glusterd2/pkg/api"
 	"github.com/gluster/glusterd2/pkg/size"
 
@@ -62,13 +61,13 @@ func (h *HostingVolumeOptions) PrepareVolumeCreateReq() (*api.VolCreateReq, erro
 			log.WithError(err).Error("failed to prepare host vol create request")
 			return nil, err
 		}
-		if err := volume.AddThinArbiter(req, h.ThinArbPath); err != nil {
+		if err := req.AddThinArbiter(h.ThinArbPath); err != nil {
 			log.WithError(err).Error("failed to add thin arbiter options to host volume")
 			return nil, err
 		}
 	}
 	if h.ShardSize != 0 {
-		volume.AddShard(req, h.ShardSize)
+		req.AddShard(h.ShardSize)
 	}
 
 	return req, nil
@@ -75,7 +75,7 @@ func CreateAndStartHostingVolume(req *api.VolCreateReq) (*volume.Volinfo, error)
 		return nil, err
 	}
 
-	vInfo, _, err := volumecommands.StartVolume(ctx, req.Name, api.VolumeStartReq{})
+	vInfo, _, _, err := volumecommands.StartVolume(ctx, req.Name, api.VolumeStartReq{})
 	if err != nil {
 		log.WithError(err).Error("error in starting auto created block hosting volume")
 		return nil, err
@@ -3,5 +3,6 @@ package blockvolume
 import (
 	// initialise all block providers
 	_ "github.com/gluster/glusterd2/plugins/blockvolume/blockprovider/gluster-block"
+	_ "github.com/gluster/glusterd2/plugins/blockvolume/blockprovider/gluster-tcmu"
 	_ "github.com/gluster/glusterd2/plugins/blockvolume/blockprovider/gluster-virtblock"
 )
@@ -7,6 +7,7 @@ import (
 	"github.com/gluster/glusterd2/glusterd2/servers/rest/route"
 	"github.com/gluster/glusterd2/pkg/utils"
 	"github.com/gluster/glusterd2/plugins/blockvolume/api"
+	glustertcmu "github.com/gluster/glusterd2/plugins/blockvolume/blockprovider/gluster-tcmu"
 	"github.com/gluster/glusterd2/plugins/blockvolume/hostvol"
 )
 
@@ -56,13 +57,21 @@ func (b *BlockVolume) RestRoutes() route.Routes {
 			Version:     1,
 			HandlerFunc: b.GetBlockVolume,
 		},
+		{
+			Name:         "BlockResize",
+			Method:       http.MethodPost,
+			Pattern:      "/blockvolumes/{provider}/{name}/resize",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.BlockVolumeResizeReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.BlockVolumeGetResp)(nil)),
+			HandlerFunc:  b.ResizeVolume,
+		},
 	}
 }
 
 // RegisterStepFuncs registers all step functions
-// Here it is a no-op method
 func (*BlockVolume) RegisterStepFuncs() {
-
+	glustertcmu.RegisterStepFuncs()
 }
 
 // Init will initialize the underlying HostVolume manager only once.
@@ -0,0 +1,37 @@
+package api
+
+// Access modes of a volume, as defined by the CSI specification
+const (
+	SingleNodeWriter      = "single-node-writer"
+	SingleNodeReaderOnly  = "single-node-reader-only"
+	MultiNodeReaderOnly   = "multi-node-reader-only"
+	MultiNodeSingleWriter = "multi-node-single-writer"
+	MultiNodeMultiWriter  = "multi-node-multi-writer"
+)
+
+// VolumeCreateReq is the request to provision a volume of the given capacity.
+// The layout of the volume and the devices of its bricks are picked by
+// glusterd2. A request for an existing volume succeeds if the volume is
+// compatible with it, so that it can be retried.
+type VolumeCreateReq struct {
+	Name string `json:"name"`
+	// Capacity is the minimum size of the volume in bytes
+	Capacity uint64 `json:"capacity"`
+	// LimitBytes is the maximum size of the volume in bytes. It is not
+	// enforced if zero.
+	LimitBytes  uint64   `json:"limit-bytes,omitempty"`
+	AccessModes []string `json:"access-modes"`
+	// ReplicaCount overrides the replica count picked by glusterd2
+	ReplicaCount int `json:"replica,omitempty"`
+	// LimitZones restricts the bricks to the peers in these zones, to
+	// honour the topology requirements of the orchestrator
+	LimitZones []string          `json:"limit-zones,omitempty"`
+	Options    map[string]string `json:"options,omitempty"`
+}
+
+// VolumeExpandReq is the request to grow a volume by SizeDelta bytes. Unlike
+// CSI, the request is relative to the current size, so retries must carry
+// the idempotency key of the original request.
+type VolumeExpandReq struct {
+	SizeDelta uint64 `json:"size-delta"`
+}
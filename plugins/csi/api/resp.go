@@ -0,0 +1,32 @@
+package api
+
+import (
+	"github.com/gluster/glusterd2/pkg/api"
+)
+
+// Credentials are the credentials of the trusted clients of a volume,
+// available to volfile templates as volume.auth.username and
+// volume.auth.password
+type Credentials struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// MountInfo is what a node needs to mount a volume, as in
+// `mount -t glusterfs -o <mount-options> <volfile-servers[0]>:/<volfile-id> <path>`
+type MountInfo struct {
+	VolfileServers []string     `json:"volfile-servers"`
+	VolfileID      string       `json:"volfile-id"`
+	MountOptions   []string     `json:"mount-options,omitempty"`
+	Credentials    *Credentials `json:"credentials,omitempty"`
+}
+
+// Volume is a volume provisioned for a container orchestrator
+type Volume struct {
+	ID          string       `json:"id"`
+	Name        string       `json:"name"`
+	Capacity    uint64       `json:"capacity"`
+	AccessModes []string     `json:"access-modes"`
+	State       api.VolState `json:"state"`
+	Mount       MountInfo    `json:"mount"`
+}
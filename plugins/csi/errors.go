@@ -0,0 +1,21 @@
+package csi
+
+import (
+	"errors"
+)
+
+var (
+	// ErrInvalidCapacity : the requested capacity is zero or above the limit
+	ErrInvalidCapacity = errors.New("invalid capacity")
+	// ErrInvalidAccessMode : the access mode is not one defined by CSI
+	ErrInvalidAccessMode = errors.New("invalid access mode")
+	// ErrVolumeIncompatible : a volume with the same name exists but does
+	// not satisfy the request
+	ErrVolumeIncompatible = errors.New("volume exists and is incompatible with the request")
+	// ErrVolumeNotProvisioned : the volume was not provisioned through the
+	// CSI API
+	ErrVolumeNotProvisioned = errors.New("volume was not provisioned by capacity")
+	// ErrIdempotencyKeyReused : the idempotency key was used for a different
+	// request
+	ErrIdempotencyKeyReused = errors.New("idempotency key was used for a different request")
+)
@@ -0,0 +1,125 @@
+package csi
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+
+	"github.com/coreos/etcd/clientv3"
+)
+
+const (
+	// IdempotencyKeyHeader is the header carrying the idempotency key of a
+	// request. The response to the first successful request with a key is
+	// replayed to the requests retrying it.
+	IdempotencyKeyHeader = "Idempotency-Key"
+
+	requestsPrefix = "csi/requests/"
+	// requestTTL is the time in seconds for which completed requests are
+	// remembered
+	requestTTL = 24 * 60 * 60
+)
+
+// requestRecord is a completed request, stored under its idempotency key
+type requestRecord struct {
+	// Digest identifies the operation and its parameters
+	Digest   string
+	Status   int
+	Response json.RawMessage
+}
+
+// requestDigest returns the digest identifying the operation on the volume
+// with the given parameters
+func requestDigest(op, volname string, req interface{}) (string, error) {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(append([]byte(op+"/"+volname+"/"), data...))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func getRequestRecord(key string) (*requestRecord, error) {
+	resp, err := store.Get(context.TODO(), requestsPrefix+key)
+	if err != nil || resp.Count != 1 {
+		return nil, err
+	}
+
+	var rec requestRecord
+	if err := json.Unmarshal(resp.Kvs[0].Value, &rec); err != nil {
+		return nil, err
+	}
+	return &rec, nil
+}
+
+func putRequestRecord(key string, rec *requestRecord) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+
+	l, err := store.Store.Grant(store.Store.Ctx(), requestTTL)
+	if err != nil {
+		return err
+	}
+	_, err = store.Put(context.TODO(), requestsPrefix+key, string(data), clientv3.WithLease(l.ID))
+	return err
+}
+
+// opFunc performs an operation, returning the HTTP status and the response,
+// or the error
+type opFunc func() (int, interface{}, error)
+
+// idempotent performs the operation unless the request carries the
+// idempotency key of a request which completed successfully, whose response
+// is returned instead. Failed requests are not remembered, as they leave the
+// volume unchanged and can simply be performed again.
+func idempotent(ctx context.Context, r *http.Request, digest string, op opFunc) (int, interface{}, error) {
+	key := r.Header.Get(IdempotencyKeyHeader)
+	if key == "" {
+		return op()
+	}
+
+	// Serialize the requests with the same key, so that a retry racing
+	// with the original request waits for it
+	txn, err := transaction.NewTxnWithLocks(ctx, "csi-request."+key)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		return status, nil, err
+	}
+	defer txn.Done()
+
+	rec, err := getRequestRecord(key)
+	if err != nil {
+		return http.StatusInternalServerError, nil, err
+	}
+	if rec != nil {
+		if rec.Digest != digest {
+			return http.StatusUnprocessableEntity, nil, ErrIdempotencyKeyReused
+		}
+		return rec.Status, rec.Response, nil
+	}
+
+	status, resp, err := op()
+	if err != nil {
+		return status, nil, err
+	}
+
+	// The operation succeeded, failing to remember it only makes the
+	// retries unsafe
+	data, err := json.Marshal(resp)
+	if err == nil {
+		err = putRequestRecord(key, &requestRecord{Digest: digest, Status: status, Response: data})
+	}
+	if err != nil {
+		gdctx.GetReqLogger(ctx).WithError(err).WithField("key", key).Warn("failed to record idempotent request")
+	}
+	return status, resp, nil
+}
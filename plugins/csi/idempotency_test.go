@@ -0,0 +1,55 @@
+package csi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	csiapi "github.com/gluster/glusterd2/plugins/csi/api"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestDigest(t *testing.T) {
+	req := &csiapi.VolumeExpandReq{SizeDelta: 1024}
+	digest, err := requestDigest("expand", "pvc-1", req)
+	require.NoError(t, err)
+
+	again, err := requestDigest("expand", "pvc-1", &csiapi.VolumeExpandReq{SizeDelta: 1024})
+	require.NoError(t, err)
+	assert.Equal(t, digest, again)
+
+	// The operation, the volume and the parameters are all part of the
+	// digest
+	for _, d := range []struct {
+		op, volname string
+		delta       uint64
+	}{
+		{"create", "pvc-1", 1024},
+		{"expand", "pvc-2", 1024},
+		{"expand", "pvc-1", 2048},
+	} {
+		other, err := requestDigest(d.op, d.volname, &csiapi.VolumeExpandReq{SizeDelta: d.delta})
+		require.NoError(t, err)
+		assert.NotEqual(t, digest, other)
+	}
+}
+
+func TestIdempotentWithoutKey(t *testing.T) {
+	// The requests without an idempotency key are simply performed
+	r := httptest.NewRequest("POST", "/v1/csi/volumes", nil)
+	calls := 0
+	op := func() (int, interface{}, error) {
+		calls++
+		return http.StatusCreated, "created", nil
+	}
+	for i := 0; i < 2; i++ {
+		status, resp, err := idempotent(context.Background(), r, "digest", op)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, status)
+		assert.Equal(t, "created", resp)
+	}
+	assert.Equal(t, 2, calls)
+}
@@ -0,0 +1,57 @@
+package csi
+
+import (
+	"github.com/gluster/glusterd2/glusterd2/servers/rest/route"
+	"github.com/gluster/glusterd2/pkg/utils"
+	csiapi "github.com/gluster/glusterd2/plugins/csi/api"
+)
+
+// Plugin is a structure which implements GlusterdPlugin interface
+type Plugin struct {
+}
+
+// Name returns name of plugin
+func (p *Plugin) Name() string {
+	return "csi"
+}
+
+// RestRoutes returns list of REST API routes to register with Glusterd
+func (p *Plugin) RestRoutes() route.Routes {
+	return route.Routes{
+		route.Route{
+			Name:         "CSIVolumeCreate",
+			Method:       "POST",
+			Pattern:      "/csi/volumes",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*csiapi.VolumeCreateReq)(nil)),
+			ResponseType: utils.GetTypeString((*csiapi.Volume)(nil)),
+			HandlerFunc:  volumeCreateHandler},
+		route.Route{
+			Name:         "CSIVolumeGet",
+			Method:       "GET",
+			Pattern:      "/csi/volumes/{volname}",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*csiapi.Volume)(nil)),
+			HandlerFunc:  volumeGetHandler},
+		route.Route{
+			Name:         "CSIVolumeExpand",
+			Method:       "POST",
+			Pattern:      "/csi/volumes/{volname}/expand",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*csiapi.VolumeExpandReq)(nil)),
+			ResponseType: utils.GetTypeString((*csiapi.Volume)(nil)),
+			HandlerFunc:  volumeExpandHandler},
+		route.Route{
+			Name:         "CSIVolumeMountInfo",
+			Method:       "GET",
+			Pattern:      "/csi/volumes/{volname}/mount",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*csiapi.MountInfo)(nil)),
+			HandlerFunc:  mountInfoHandler},
+	}
+}
+
+// RegisterStepFuncs registers transaction step functions with
+// Glusterd Transaction framework
+func (p *Plugin) RegisterStepFuncs() {
+}
@@ -0,0 +1,230 @@
+package csi
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	volumecommands "github.com/gluster/glusterd2/glusterd2/commands/volumes"
+	"github.com/gluster/glusterd2/glusterd2/events"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+	"github.com/gluster/glusterd2/pkg/utils"
+	csiapi "github.com/gluster/glusterd2/plugins/csi/api"
+
+	"github.com/gorilla/mux"
+)
+
+func validateCreateReq(req *csiapi.VolumeCreateReq) error {
+	if !volume.IsValidName(req.Name) {
+		return gderrors.ErrInvalidVolName
+	}
+	if req.Capacity == 0 || (req.LimitBytes != 0 && req.LimitBytes < req.Capacity) {
+		return ErrInvalidCapacity
+	}
+	if len(req.AccessModes) == 0 {
+		return ErrInvalidAccessMode
+	}
+	for _, m := range req.AccessModes {
+		if !utils.StringInSlice(m, validAccessModes) {
+			return ErrInvalidAccessMode
+		}
+	}
+	return nil
+}
+
+func volumeCreateHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req csiapi.VolumeCreateReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
+		return
+	}
+	if err := validateCreateReq(&req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	digest, err := requestDigest("create", req.Name, &req)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	status, resp, err := idempotent(ctx, r, digest, func() (int, interface{}, error) {
+		return createVolume(ctx, &req)
+	})
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	restutils.SendHTTPResponse(ctx, w, status, resp)
+}
+
+// createVolume provisions the volume, or returns the existing volume with the
+// same name if it satisfies the request
+func createVolume(ctx context.Context, req *csiapi.VolumeCreateReq) (int, interface{}, error) {
+	v, err := volume.GetVolume(req.Name)
+	if err == nil {
+		return existingVolume(ctx, v, req)
+	} else if err != gderrors.ErrVolNotFound {
+		return http.StatusInternalServerError, nil, err
+	}
+
+	replica := req.ReplicaCount
+	if replica == 0 {
+		if replica, err = pickReplicaCount(); err != nil {
+			return http.StatusInternalServerError, nil, err
+		}
+	}
+
+	creq := api.VolCreateReq{
+		Name:         req.Name,
+		Size:         req.Capacity,
+		ReplicaCount: replica,
+		LimitZones:   req.LimitZones,
+		Options:      req.Options,
+		Metadata: map[string]string{
+			metadataAccessModes: strings.Join(req.AccessModes, ","),
+		},
+	}
+	if status, err := volumecommands.CreateVolume(ctx, creq); err != nil {
+		return status, nil, err
+	}
+
+	v, err = volume.GetVolume(req.Name)
+	if err != nil {
+		return http.StatusInternalServerError, nil, err
+	}
+	events.Broadcast(volume.NewEvent(volume.EventVolumeCreated, v))
+
+	return startVolume(ctx, v, http.StatusCreated)
+}
+
+// existingVolume returns the volume if it satisfies the request, starting it
+// if a previous attempt failed to
+func existingVolume(ctx context.Context, v *volume.Volinfo, req *csiapi.VolumeCreateReq) (int, interface{}, error) {
+	modes, ok := accessModes(v)
+	if !ok || !sameModes(modes, req.AccessModes) ||
+		v.Capacity < req.Capacity || (req.LimitBytes != 0 && v.Capacity > req.LimitBytes) {
+		return http.StatusConflict, nil, ErrVolumeIncompatible
+	}
+	return startVolume(ctx, v, http.StatusOK)
+}
+
+func startVolume(ctx context.Context, v *volume.Volinfo, status int) (int, interface{}, error) {
+	if v.State != volume.VolStarted {
+		var err error
+		v, _, status, err = volumecommands.StartVolume(ctx, v.Name, api.VolumeStartReq{})
+		if err != nil {
+			return status, nil, err
+		}
+		events.Broadcast(volume.NewEvent(volume.EventVolumeStarted, v))
+	}
+
+	resp, err := createVolumeResp(v)
+	if err != nil {
+		return http.StatusInternalServerError, nil, err
+	}
+	return status, resp, nil
+}
+
+func volumeExpandHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	volname := mux.Vars(r)["volname"]
+
+	var req csiapi.VolumeExpandReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
+		return
+	}
+	if req.SizeDelta == 0 {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, ErrInvalidCapacity)
+		return
+	}
+
+	digest, err := requestDigest("expand", volname, &req)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	status, resp, err := idempotent(ctx, r, digest, func() (int, interface{}, error) {
+		return expandVolume(ctx, volname, req.SizeDelta)
+	})
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	restutils.SendHTTPResponse(ctx, w, status, resp)
+}
+
+// expandVolume grows the bricks of the volume so that its capacity grows by
+// delta
+func expandVolume(ctx context.Context, volname string, delta uint64) (int, interface{}, error) {
+	v, err := volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		return status, nil, err
+	}
+	if _, ok := accessModes(v); !ok || v.Capacity == 0 {
+		return http.StatusBadRequest, nil, ErrVolumeNotProvisioned
+	}
+
+	// Keeping the distribute count resizes the existing bricks instead
+	// of adding new ones
+	ereq := api.VolExpandReq{
+		Size:            delta,
+		DistributeCount: len(v.Subvols),
+	}
+	v, status, err := volumecommands.ExpandVolume(ctx, volname, ereq)
+	if err != nil {
+		return status, nil, err
+	}
+	events.Broadcast(volume.NewEvent(volume.EventVolumeExpanded, v))
+
+	resp, err := createVolumeResp(v)
+	if err != nil {
+		return http.StatusInternalServerError, nil, err
+	}
+	return http.StatusOK, resp, nil
+}
+
+func volumeGetHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	v, err := volume.GetVolume(mux.Vars(r)["volname"])
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	resp, err := createVolumeResp(v)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+func mountInfoHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	v, err := volume.GetVolume(mux.Vars(r)["volname"])
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	info, err := createMountInfo(v)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, info)
+}
@@ -0,0 +1,71 @@
+package csi
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+	csiapi "github.com/gluster/glusterd2/plugins/csi/api"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateCreateReq(t *testing.T) {
+	req := csiapi.VolumeCreateReq{
+		Name:        "pvc-1",
+		Capacity:    1024,
+		AccessModes: []string{csiapi.SingleNodeWriter},
+	}
+	assert.NoError(t, validateCreateReq(&req))
+
+	bad := req
+	bad.Name = "pvc/1"
+	assert.Equal(t, gderrors.ErrInvalidVolName, validateCreateReq(&bad))
+
+	bad = req
+	bad.Capacity = 0
+	assert.Equal(t, ErrInvalidCapacity, validateCreateReq(&bad))
+	bad = req
+	bad.LimitBytes = 512
+	assert.Equal(t, ErrInvalidCapacity, validateCreateReq(&bad))
+
+	bad = req
+	bad.AccessModes = nil
+	assert.Equal(t, ErrInvalidAccessMode, validateCreateReq(&bad))
+	bad = req
+	bad.AccessModes = []string{"read-write-many"}
+	assert.Equal(t, ErrInvalidAccessMode, validateCreateReq(&bad))
+}
+
+func TestExistingVolumeIncompatible(t *testing.T) {
+	v := &volume.Volinfo{
+		Name:     "pvc-1",
+		Capacity: 2048,
+		Metadata: map[string]string{metadataAccessModes: csiapi.SingleNodeWriter},
+	}
+	req := csiapi.VolumeCreateReq{
+		Name:        "pvc-1",
+		Capacity:    1024,
+		AccessModes: []string{csiapi.SingleNodeWriter},
+	}
+
+	for _, r := range []csiapi.VolumeCreateReq{
+		// different access modes
+		{Name: req.Name, Capacity: req.Capacity, AccessModes: []string{csiapi.MultiNodeMultiWriter}},
+		// too small
+		{Name: req.Name, Capacity: 4096, AccessModes: req.AccessModes},
+		// too large
+		{Name: req.Name, Capacity: req.Capacity, LimitBytes: 1024, AccessModes: req.AccessModes},
+	} {
+		status, _, err := existingVolume(context.Background(), v, &r)
+		assert.Equal(t, http.StatusConflict, status)
+		assert.Equal(t, ErrVolumeIncompatible, err)
+	}
+
+	// A volume not provisioned through the CSI API is never compatible
+	status, _, err := existingVolume(context.Background(), &volume.Volinfo{Name: "pvc-1", Capacity: 2048}, &req)
+	assert.Equal(t, http.StatusConflict, status)
+	assert.Equal(t, ErrVolumeIncompatible, err)
+}
@@ -0,0 +1,149 @@
+package csi
+
+import (
+	"net"
+	"strings"
+
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/utils"
+	csiapi "github.com/gluster/glusterd2/plugins/csi/api"
+)
+
+const (
+	// metadataAccessModes is the volume metadata recording the access
+	// modes of the volumes provisioned through the CSI API
+	metadataAccessModes = "_csi.access-modes"
+
+	defaultReplicaCount = 3
+)
+
+var validAccessModes = []string{
+	csiapi.SingleNodeWriter,
+	csiapi.SingleNodeReaderOnly,
+	csiapi.MultiNodeReaderOnly,
+	csiapi.MultiNodeSingleWriter,
+	csiapi.MultiNodeMultiWriter,
+}
+
+// accessModes returns the access modes of a volume provisioned through the
+// CSI API, and false for other volumes
+func accessModes(v *volume.Volinfo) ([]string, bool) {
+	modes, ok := v.Metadata[metadataAccessModes]
+	if !ok {
+		return nil, false
+	}
+	return strings.Split(modes, ","), true
+}
+
+// readOnly returns true if all the access modes are read-only
+func readOnly(modes []string) bool {
+	for _, m := range modes {
+		if m != csiapi.SingleNodeReaderOnly && m != csiapi.MultiNodeReaderOnly {
+			return false
+		}
+	}
+	return len(modes) > 0
+}
+
+// sameModes returns true if both the lists contain the same access modes
+func sameModes(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, m := range a {
+		if !utils.StringInSlice(m, b) {
+			return false
+		}
+	}
+	return true
+}
+
+// pickReplicaCount returns the replica count of the volumes provisioned
+// without an explicit one. Volumes are replicated thrice if the cluster is
+// large enough, there is no point in replicating them on fewer peers.
+func pickReplicaCount() (int, error) {
+	peers, err := peer.GetPeers()
+	if err != nil {
+		return 0, err
+	}
+	if len(peers) < defaultReplicaCount {
+		return 1, nil
+	}
+	return defaultReplicaCount, nil
+}
+
+// volfileServers returns the hosts serving the volfile of the volume. The
+// peers hosting its bricks come first, as they are the most likely to be up
+// when the volume is usable.
+func volfileServers(v *volume.Volinfo) ([]string, error) {
+	peers, err := peer.GetPeers()
+	if err != nil {
+		return nil, err
+	}
+
+	hosting := make(map[string]bool)
+	for _, id := range v.Nodes() {
+		hosting[id.String()] = true
+	}
+
+	var first, rest []string
+	for _, p := range peers {
+		if len(p.ClientAddresses) == 0 {
+			continue
+		}
+		host, _, err := net.SplitHostPort(p.ClientAddresses[0])
+		if err != nil {
+			host = p.ClientAddresses[0]
+		}
+		if hosting[p.ID.String()] {
+			first = append(first, host)
+		} else {
+			rest = append(rest, host)
+		}
+	}
+	return append(first, rest...), nil
+}
+
+func createMountInfo(v *volume.Volinfo) (*csiapi.MountInfo, error) {
+	servers, err := volfileServers(v)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &csiapi.MountInfo{
+		VolfileServers: servers,
+		VolfileID:      v.VolfileID,
+	}
+	if modes, ok := accessModes(v); ok && readOnly(modes) {
+		info.MountOptions = append(info.MountOptions, "ro")
+	}
+	if len(servers) > 1 {
+		info.MountOptions = append(info.MountOptions, "backup-volfile-servers="+strings.Join(servers[1:], ":"))
+	}
+	if v.Auth.Username != "" {
+		info.Credentials = &csiapi.Credentials{
+			Username: v.Auth.Username,
+			Password: v.Auth.Password,
+		}
+	}
+	return info, nil
+}
+
+func createVolumeResp(v *volume.Volinfo) (*csiapi.Volume, error) {
+	info, err := createMountInfo(v)
+	if err != nil {
+		return nil, err
+	}
+
+	modes, _ := accessModes(v)
+	return &csiapi.Volume{
+		ID:          v.ID.String(),
+		Name:        v.Name,
+		Capacity:    v.Capacity,
+		AccessModes: modes,
+		State:       api.VolState(v.State),
+		Mount:       *info,
+	}, nil
+}
@@ -0,0 +1,38 @@
+package csi
+
+import (
+	"testing"
+
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	csiapi "github.com/gluster/glusterd2/plugins/csi/api"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccessModes(t *testing.T) {
+	_, ok := accessModes(&volume.Volinfo{Metadata: map[string]string{}})
+	assert.False(t, ok)
+
+	v := &volume.Volinfo{Metadata: map[string]string{
+		metadataAccessModes: csiapi.SingleNodeWriter + "," + csiapi.MultiNodeReaderOnly,
+	}}
+	modes, ok := accessModes(v)
+	assert.True(t, ok)
+	assert.Equal(t, []string{csiapi.SingleNodeWriter, csiapi.MultiNodeReaderOnly}, modes)
+}
+
+func TestReadOnly(t *testing.T) {
+	assert.True(t, readOnly([]string{csiapi.SingleNodeReaderOnly}))
+	assert.True(t, readOnly([]string{csiapi.SingleNodeReaderOnly, csiapi.MultiNodeReaderOnly}))
+	assert.False(t, readOnly([]string{csiapi.MultiNodeReaderOnly, csiapi.MultiNodeSingleWriter}))
+	assert.False(t, readOnly(nil))
+}
+
+func TestSameModes(t *testing.T) {
+	assert.True(t, sameModes(nil, nil))
+	assert.True(t, sameModes(
+		[]string{csiapi.SingleNodeWriter, csiapi.MultiNodeReaderOnly},
+		[]string{csiapi.MultiNodeReaderOnly, csiapi.SingleNodeWriter}))
+	assert.False(t, sameModes([]string{csiapi.SingleNodeWriter}, []string{csiapi.MultiNodeMultiWriter}))
+	assert.False(t, sameModes([]string{csiapi.SingleNodeWriter}, []string{csiapi.SingleNodeWriter, csiapi.MultiNodeReaderOnly}))
+}
@@ -3,6 +3,7 @@ package deviceutils
 import (
 	"context"
 	"encoding/json"
+	"sort"
 
 	"github.com/gluster/glusterd2/glusterd2/brick"
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
@@ -82,6 +83,11 @@ func SetDeviceState(peerID, deviceName, deviceState string) error {
 	return AddOrUpdateDevice(*dev)
 }
 
+// deviceKey returns the key of the device in the store
+func deviceKey(device deviceapi.Info) string {
+	return devicePrefix + device.PeerID.String() + "/" + device.Device
+}
+
 // AddOrUpdateDevice adds device to peerinfo
 func AddOrUpdateDevice(device deviceapi.Info) error {
 	json, err := json.Marshal(device)
@@ -89,9 +95,7 @@ func AddOrUpdateDevice(device deviceapi.Info) error {
 		return err
 	}
 
-	storeKey := device.PeerID.String() + "/" + device.Device
-
-	if _, err := store.Put(context.TODO(), devicePrefix+storeKey, string(json)); err != nil {
+	if _, err := store.Put(context.TODO(), deviceKey(device), string(json)); err != nil {
 		return err
 	}
 
@@ -117,38 +121,76 @@ func UpdateDeviceFreeSize(peerID, device string) error {
 
 // AddDeviceFreeSize updates device available size
 func AddDeviceFreeSize(peerID, device string, size uint64) error {
-	clusterLocks := transaction.Locks{}
-	if err := clusterLocks.Lock(peerID + device); err != nil {
-		return err
-	}
-	defer clusterLocks.UnLock(context.Background())
-
-	dev, err := GetDevice(peerID, device)
-	if err != nil {
-		return err
-	}
-
-	dev.AvailableSize = dev.AvailableSize + size
-	dev.UsedSize = dev.TotalSize - dev.AvailableSize
-	return AddOrUpdateDevice(*dev)
+	return AddDevicesFreeSize(peerID, map[string]uint64{device: size})
 }
 
 // ReduceDeviceFreeSize updates device available size
 func ReduceDeviceFreeSize(peerID, device string, size uint64) error {
+	return ReduceDevicesFreeSize(peerID, map[string]uint64{device: size})
+}
+
+// AddDevicesFreeSize adds the sizes to the available sizes of the devices of
+// the peer, given by device, in a single store transaction
+func AddDevicesFreeSize(peerID string, sizes map[string]uint64) error {
+	return updateDevicesFreeSize(peerID, sizes, true)
+}
+
+// ReduceDevicesFreeSize removes the sizes from the available sizes of the
+// devices of the peer, given by device, in a single store transaction
+func ReduceDevicesFreeSize(peerID string, sizes map[string]uint64) error {
+	return updateDevicesFreeSize(peerID, sizes, false)
+}
+
+// updateDevicesFreeSize updates the available sizes of the devices of the
+// peer. The devices are locked, in order so that two updates of the same
+// devices do not deadlock, and either all of them or none are updated. A
+// size larger than the available size of its device is not removed.
+func updateDevicesFreeSize(peerID string, sizes map[string]uint64, add bool) error {
+	if len(sizes) == 0 {
+		return nil
+	}
+
+	devices := make([]string, 0, len(sizes))
+	for device := range sizes {
+		devices = append(devices, device)
+	}
+	sort.Strings(devices)
+	lockIDs := make([]string, len(devices))
+	for i, device := range devices {
+		lockIDs[i] = peerID + device
+	}
+
 	clusterLocks := transaction.Locks{}
-	if err := clusterLocks.Lock(peerID + device); err != nil {
+	defer clusterLocks.UnLock(context.Background())
+	if err := clusterLocks.Lock(lockIDs[0], lockIDs[1:]...); err != nil {
 		return err
 	}
-	defer clusterLocks.UnLock(context.Background())
 
-	dev, err := GetDevice(peerID, device)
-	if err != nil {
-		return err
+	ops := make([]clientv3.Op, 0, len(devices))
+	for _, device := range devices {
+		dev, err := GetDevice(peerID, device)
+		if err != nil {
+			return err
+		}
+
+		if add {
+			dev.AvailableSize = dev.AvailableSize + sizes[device]
+		} else {
+			if dev.AvailableSize < sizes[device] {
+				return gderrors.ErrInsufficientDeviceSpace
+			}
+			dev.AvailableSize = dev.AvailableSize - sizes[device]
+		}
+		dev.UsedSize = dev.TotalSize - dev.AvailableSize
+		data, err := json.Marshal(dev)
+		if err != nil {
+			return err
+		}
+		ops = append(ops, clientv3.OpPut(deviceKey(*dev), string(data)))
 	}
 
-	dev.AvailableSize = dev.AvailableSize - size
-	dev.UsedSize = dev.TotalSize - dev.AvailableSize
-	return AddOrUpdateDevice(*dev)
+	_, err := store.Txn(context.TODO()).Then(ops...).Commit()
+	return err
 }
 
 // UpdateDeviceFreeSizeByVg updates the actual available size of VG
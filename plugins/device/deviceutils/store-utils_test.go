@@ -0,0 +1,56 @@
+package deviceutils
+
+import (
+	"testing"
+
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+	"github.com/gluster/glusterd2/pkg/testutils"
+	deviceapi "github.com/gluster/glusterd2/plugins/device/api"
+
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestUpdateDevicesFreeSize validates that the available sizes of the
+// devices are updated all together or not at all, and that a size larger
+// than the available size of its device is refused rather than wrapping
+// around
+func TestUpdateDevicesFreeSize(t *testing.T) {
+	defer testutils.InitStore(t)()
+
+	peerID := uuid.NewRandom()
+	for device, available := range map[string]uint64{"/dev/sdb": 50, "/dev/sdc": 10} {
+		require.Nil(t, AddOrUpdateDevice(deviceapi.Info{
+			Device:        device,
+			PeerID:        peerID,
+			TotalSize:     100,
+			AvailableSize: available,
+			UsedSize:      100 - available,
+		}))
+	}
+	assertSizes := func(sdb, sdc uint64) {
+		for device, available := range map[string]uint64{"/dev/sdb": sdb, "/dev/sdc": sdc} {
+			dev, err := GetDevice(peerID.String(), device)
+			require.Nil(t, err)
+			assert.Equal(t, available, dev.AvailableSize, device)
+			assert.Equal(t, 100-available, dev.UsedSize, device)
+		}
+	}
+
+	// /dev/sdc has not 30 left, so /dev/sdb is not updated either
+	err := ReduceDevicesFreeSize(peerID.String(), map[string]uint64{"/dev/sdb": 20, "/dev/sdc": 30})
+	assert.Equal(t, gderrors.ErrInsufficientDeviceSpace, err)
+	assertSizes(50, 10)
+
+	// nor when a device is not found
+	err = ReduceDevicesFreeSize(peerID.String(), map[string]uint64{"/dev/sdb": 20, "/dev/sdd": 1})
+	assert.Equal(t, gderrors.ErrDeviceNotFound, err)
+	assertSizes(50, 10)
+
+	require.Nil(t, ReduceDevicesFreeSize(peerID.String(), map[string]uint64{"/dev/sdb": 20, "/dev/sdc": 10}))
+	assertSizes(30, 0)
+
+	require.Nil(t, AddDevicesFreeSize(peerID.String(), map[string]uint64{"/dev/sdb": 20, "/dev/sdc": 10}))
+	assertSizes(50, 10)
+}
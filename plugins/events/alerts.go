@@ -0,0 +1,224 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	gd2events "github.com/gluster/glusterd2/glusterd2/events"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/pkg/api"
+	eventsapi "github.com/gluster/glusterd2/plugins/events/api"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	thresholdPrefix = "config/events/thresholds/"
+	alertsPrefix    = "alerts/"
+
+	eventAlertFiring   = "alert.firing"
+	eventAlertResolved = "alert.resolved"
+)
+
+var thresholdOperators = map[string]func(a, b float64) bool{
+	">":  func(a, b float64) bool { return a > b },
+	">=": func(a, b float64) bool { return a >= b },
+	"<":  func(a, b float64) bool { return a < b },
+	"<=": func(a, b float64) bool { return a <= b },
+	"==": func(a, b float64) bool { return a == b },
+	"!=": func(a, b float64) bool { return a != b },
+}
+
+func validateThreshold(t *eventsapi.Threshold) error {
+	if t.Name == "" || t.Event == "" {
+		return fmt.Errorf("threshold name and event are required fields")
+	}
+	if strings.Contains(t.Name, "/") {
+		return fmt.Errorf("threshold name cannot contain '/'")
+	}
+	if t.Key != "" {
+		if _, ok := thresholdOperators[t.Operator]; !ok {
+			return fmt.Errorf("unsupported threshold operator %q", t.Operator)
+		}
+	}
+	if !gd2events.ValidSeverity(t.Severity) {
+		return fmt.Errorf("unknown severity %q", t.Severity)
+	}
+	return nil
+}
+
+// evaluate checks the event against the threshold. matched is false if
+// the event has no bearing on the threshold, otherwise firing tells if
+// the alert condition holds.
+func evaluate(t *eventsapi.Threshold, e *api.Event) (firing, matched bool) {
+	switch e.Name {
+	case strings.ToLower(t.Event):
+		if t.Key == "" {
+			return true, true
+		}
+		v, err := strconv.ParseFloat(e.Data[t.Key], 64)
+		if err != nil {
+			return false, false
+		}
+		return thresholdOperators[t.Operator](v, t.Value), true
+	case strings.ToLower(t.ClearEvent):
+		return false, true
+	}
+	return false, false
+}
+
+func alertKey(t *eventsapi.Threshold, e *api.Event) (string, string) {
+	var scope string
+	if t.Scope != "" {
+		scope = e.Data[t.Scope]
+	}
+	return alertsPrefix + t.Name + "/" + strings.Replace(scope, "/", "|", -1), scope
+}
+
+// alertEvaluator evaluates the configured thresholds against every event
+// originating on this node and maintains the list of firing alerts
+type alertEvaluator struct{}
+
+func (a *alertEvaluator) Handle(e *api.Event) {
+	// evaluate events only on originator node
+	if !uuid.Equal(e.Origin, gdctx.MyUUID) {
+		return
+	}
+	if e.Name == eventAlertFiring || e.Name == eventAlertResolved {
+		return
+	}
+
+	thresholds, err := GetThresholdList()
+	if err != nil {
+		log.WithError(err).Error("error retriving threshold list from etcd")
+		return
+	}
+
+	for _, t := range thresholds {
+		firing, matched := evaluate(t, e)
+		if !matched {
+			continue
+		}
+		key, scope := alertKey(t, e)
+		if firing {
+			fireAlert(key, scope, t, e)
+		} else {
+			resolveAlert(key, scope, t)
+		}
+	}
+}
+
+func (a *alertEvaluator) Events() []string {
+	return []string{}
+}
+
+func fireAlert(key, scope string, t *eventsapi.Threshold, e *api.Event) {
+	resp, err := store.Get(context.TODO(), key)
+	if err != nil {
+		log.WithError(err).WithField("alert", key).Error("failed to get alert from store")
+		return
+	}
+	// Alert is already firing, retain the time since when it is firing
+	if resp.Count == 1 {
+		return
+	}
+
+	alert := eventsapi.Alert{
+		Threshold: t.Name,
+		Scope:     scope,
+		Severity:  t.Severity,
+		Event:     e.Name,
+		Data:      e.Data,
+		Origin:    e.Origin,
+		Since:     e.Timestamp,
+	}
+	if t.Key != "" {
+		alert.Value = e.Data[t.Key]
+	}
+	v, err := json.Marshal(alert)
+	if err != nil {
+		log.WithError(err).WithField("alert", key).Error("failed to marshal alert")
+		return
+	}
+	if _, err := store.Put(context.TODO(), key, string(v)); err != nil {
+		log.WithError(err).WithField("alert", key).Error("failed to add alert to store")
+		return
+	}
+
+	data := map[string]string{
+		"threshold": t.Name,
+		"scope":     scope,
+		"event":     e.Name,
+		"value":     alert.Value,
+	}
+	gd2events.Broadcast(gd2events.NewWithSeverity(eventAlertFiring, t.Severity, data, true))
+}
+
+func resolveAlert(key, scope string, t *eventsapi.Threshold) {
+	resp, err := store.Delete(context.TODO(), key)
+	if err != nil {
+		log.WithError(err).WithField("alert", key).Error("failed to delete alert from store")
+		return
+	}
+	if resp.Deleted == 0 {
+		return
+	}
+
+	data := map[string]string{
+		"threshold": t.Name,
+		"scope":     scope,
+	}
+	gd2events.Broadcast(gd2events.NewWithSeverity(eventAlertResolved, api.SeverityInfo, data, true))
+}
+
+// GetThresholdList returns list of all alerting thresholds
+func GetThresholdList() ([]*eventsapi.Threshold, error) {
+	resp, e := store.Get(context.TODO(), thresholdPrefix, clientv3.WithPrefix())
+	if e != nil {
+		return nil, e
+	}
+
+	thresholds := make([]*eventsapi.Threshold, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var t eventsapi.Threshold
+		if err := json.Unmarshal(kv.Value, &t); err != nil {
+			log.WithError(err).WithField("threshold", string(kv.Key)).Error("Failed to unmarshal threshold")
+			continue
+		}
+		thresholds = append(thresholds, &t)
+	}
+
+	return thresholds, nil
+}
+
+// GetAlertList returns list of currently firing alerts
+func GetAlertList() (eventsapi.AlertList, error) {
+	resp, e := store.Get(context.TODO(), alertsPrefix, clientv3.WithPrefix())
+	if e != nil {
+		return nil, e
+	}
+
+	alerts := make(eventsapi.AlertList, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var a eventsapi.Alert
+		if err := json.Unmarshal(kv.Value, &a); err != nil {
+			log.WithError(err).WithField("alert", string(kv.Key)).Error("Failed to unmarshal alert")
+			continue
+		}
+		alerts = append(alerts, a)
+	}
+
+	return alerts, nil
+}
+
+// deleteAlerts removes all the alerts raised by the given threshold
+func deleteAlerts(threshold string) error {
+	_, e := store.Delete(context.TODO(), alertsPrefix+threshold+"/", clientv3.WithPrefix())
+	return e
+}
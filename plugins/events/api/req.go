@@ -1,5 +1,9 @@
 package api
 
+import (
+	"github.com/gluster/glusterd2/pkg/api"
+)
+
 // Webhook is Structure to represent a webhook that will be used
 // for posting events
 type Webhook struct {
@@ -13,3 +17,70 @@ type Webhook struct {
 type WebhookDel struct {
 	URL string `json:"url"`
 }
+
+// SMTPSink is Structure to represent an SMTP notifier which mails
+// events to the configured recipients
+type SMTPSink struct {
+	Name     string `json:"name"`
+	Server   string `json:"server"`
+	Username string `json:"username,omitempty"`
+	// PasswordFile is the path of the file holding the SMTP password on
+	// the peers. The password itself is not sent to or stored by glusterd2.
+	PasswordFile string   `json:"password-file,omitempty"`
+	From         string   `json:"from"`
+	To           []string `json:"to"`
+	// Subject and Template are text/template strings executed against
+	// the event. Defaults are used if they are not set.
+	Subject  string `json:"subject,omitempty"`
+	Template string `json:"template,omitempty"`
+	// Events is the list of event names to be mailed. All events are
+	// mailed if the list is empty.
+	Events []string `json:"events,omitempty"`
+	// Severities is the list of event severities to be mailed. Events of
+	// all severities are mailed if the list is empty.
+	Severities []api.EventSeverity `json:"severities,omitempty"`
+}
+
+// SyslogSink is Structure to represent a syslog forwarder. Events are
+// sent to the local syslog (and hence journald) if Address is empty.
+type SyslogSink struct {
+	Name     string   `json:"name"`
+	Network  string   `json:"network,omitempty"`
+	Address  string   `json:"address,omitempty"`
+	Tag      string   `json:"tag,omitempty"`
+	Facility string   `json:"facility,omitempty"`
+	Events   []string `json:"events,omitempty"`
+	// Severities is the list of event severities to be forwarded. Events
+	// of all severities are forwarded if the list is empty.
+	Severities []api.EventSeverity `json:"severities,omitempty"`
+}
+
+// SinkDel is Structure to represent a SMTP or syslog sink that will be
+// used for deleting the sink
+type SinkDel struct {
+	Name string `json:"name"`
+}
+
+// Threshold is Structure to represent an alerting condition. An alert
+// fires when an event named Event is seen and, if Key is set, the numeric
+// value of Data[Key] satisfies Operator and Value. The alert is resolved
+// when the condition is no longer satisfied or ClearEvent is seen.
+type Threshold struct {
+	Name     string  `json:"name"`
+	Event    string  `json:"event"`
+	Key      string  `json:"key,omitempty"`
+	Operator string  `json:"operator,omitempty"`
+	Value    float64 `json:"value,omitempty"`
+	// ClearEvent is the name of an event which resolves the alert
+	ClearEvent string `json:"clear-event,omitempty"`
+	// Scope is an event data key, such as "volume.name", used to track a
+	// separate alert for each of its values
+	Scope    string            `json:"scope,omitempty"`
+	Severity api.EventSeverity `json:"severity"`
+}
+
+// ThresholdDel is Structure to represent a threshold that will be used
+// for deleting the threshold
+type ThresholdDel struct {
+	Name string `json:"name"`
+}
@@ -1,7 +1,11 @@
 package api
 
 import (
+	"time"
+
 	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/pborman/uuid"
 )
 
 // WebhookList holds list of webhooks containing just its URL
@@ -9,3 +13,27 @@ type WebhookList []string
 
 // EventList holds list of events happened in last 10 mins(configurable)
 type EventList []api.Event
+
+// SMTPSinkList holds list of SMTP sinks
+type SMTPSinkList []SMTPSink
+
+// SyslogSinkList holds list of syslog sinks
+type SyslogSinkList []SyslogSink
+
+// ThresholdList holds list of alerting thresholds
+type ThresholdList []Threshold
+
+// Alert represents a currently firing alert
+type Alert struct {
+	Threshold string            `json:"threshold"`
+	Scope     string            `json:"scope,omitempty"`
+	Severity  api.EventSeverity `json:"severity"`
+	Event     string            `json:"event"`
+	Value     string            `json:"value,omitempty"`
+	Data      map[string]string `json:"data,omitempty"`
+	Origin    uuid.UUID         `json:"origin"`
+	Since     time.Time         `json:"since"`
+}
+
+// AlertList holds list of currently firing alerts
+type AlertList []Alert
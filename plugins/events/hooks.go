@@ -1,6 +1,8 @@
 package events
 
 import (
+	"strings"
+
 	gd2events "github.com/gluster/glusterd2/glusterd2/events"
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
 	"github.com/gluster/glusterd2/pkg/api"
@@ -39,7 +41,91 @@ func (w *webhooksNotifier) Events() []string {
 	return []string{}
 }
 
+// wanted returns true if the event is in the list of event names and its
+// severity is in the list of severities. An empty list matches everything.
+func wanted(e *api.Event, events []string, severities []api.EventSeverity) bool {
+	nameOK := len(events) == 0
+	for _, name := range events {
+		if strings.ToLower(name) == e.Name {
+			nameOK = true
+			break
+		}
+	}
+	if !nameOK {
+		return false
+	}
+
+	if len(severities) == 0 {
+		return true
+	}
+	for _, s := range severities {
+		if s == e.Severity {
+			return true
+		}
+	}
+	return false
+}
+
+type smtpNotifier struct{}
+
+func (s *smtpNotifier) Handle(e *api.Event) {
+	//send events only from originator node
+	if !uuid.Equal(e.Origin, gdctx.MyUUID) {
+		return
+	}
+	sinks, err := GetSMTPSinkList()
+	if err != nil {
+		log.WithError(err).Error("error retriving SMTP sink list from etcd")
+		return
+	}
+
+	for _, sink := range sinks {
+		if !wanted(e, sink.Events, sink.Severities) {
+			continue
+		}
+		go func(e *api.Event, sink *eventsapi.SMTPSink) {
+			if err := gd2events.SMTPPublish(sink, e); err != nil {
+				log.WithError(err).WithField("sink", sink.Name).Error("error in mailing event")
+			}
+		}(e, sink)
+	}
+}
+
+func (s *smtpNotifier) Events() []string {
+	return []string{}
+}
+
+type syslogNotifier struct{}
+
+func (s *syslogNotifier) Handle(e *api.Event) {
+	//send events only from originator node
+	if !uuid.Equal(e.Origin, gdctx.MyUUID) {
+		return
+	}
+	sinks, err := GetSyslogSinkList()
+	if err != nil {
+		log.WithError(err).Error("error retriving syslog sink list from etcd")
+		return
+	}
+
+	for _, sink := range sinks {
+		if !wanted(e, sink.Events, sink.Severities) {
+			continue
+		}
+		if err := gd2events.SyslogPublish(sink, e); err != nil {
+			log.WithError(err).WithField("sink", sink.Name).Error("error in forwarding event to syslog")
+		}
+	}
+}
+
+func (s *syslogNotifier) Events() []string {
+	return []string{}
+}
+
 func init() {
 	w := new(webhooksNotifier)
 	gd2events.Register(w)
+	gd2events.Register(new(smtpNotifier))
+	gd2events.Register(new(syslogNotifier))
+	gd2events.Register(new(alertEvaluator))
 }
@@ -55,6 +55,76 @@ func (p *Plugin) RestRoutes() route.Routes {
 			// FIXME: This type is not in 'eventsapi'
 			ResponseType: utils.GetTypeString((*api.Event)(nil)),
 			HandlerFunc:  eventsListHandler},
+		route.Route{
+			Name:        "EventsSMTPSinkAdd",
+			Method:      "POST",
+			Pattern:     "/events/smtp",
+			Version:     1,
+			RequestType: utils.GetTypeString((*eventsapi.SMTPSink)(nil)),
+			HandlerFunc: smtpSinkAddHandler},
+		route.Route{
+			Name:        "EventsSMTPSinkDelete",
+			Method:      "DELETE",
+			Pattern:     "/events/smtp",
+			Version:     1,
+			RequestType: utils.GetTypeString((*eventsapi.SinkDel)(nil)),
+			HandlerFunc: sinkDeleteHandler(smtpPrefix)},
+		route.Route{
+			Name:         "EventsSMTPSinkList",
+			Method:       "GET",
+			Pattern:      "/events/smtp",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*eventsapi.SMTPSinkList)(nil)),
+			HandlerFunc:  smtpSinkListHandler},
+		route.Route{
+			Name:        "EventsSyslogSinkAdd",
+			Method:      "POST",
+			Pattern:     "/events/syslog",
+			Version:     1,
+			RequestType: utils.GetTypeString((*eventsapi.SyslogSink)(nil)),
+			HandlerFunc: syslogSinkAddHandler},
+		route.Route{
+			Name:        "EventsSyslogSinkDelete",
+			Method:      "DELETE",
+			Pattern:     "/events/syslog",
+			Version:     1,
+			RequestType: utils.GetTypeString((*eventsapi.SinkDel)(nil)),
+			HandlerFunc: sinkDeleteHandler(syslogPrefix)},
+		route.Route{
+			Name:         "EventsSyslogSinkList",
+			Method:       "GET",
+			Pattern:      "/events/syslog",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*eventsapi.SyslogSinkList)(nil)),
+			HandlerFunc:  syslogSinkListHandler},
+		route.Route{
+			Name:        "EventsThresholdAdd",
+			Method:      "POST",
+			Pattern:     "/events/thresholds",
+			Version:     1,
+			RequestType: utils.GetTypeString((*eventsapi.Threshold)(nil)),
+			HandlerFunc: thresholdAddHandler},
+		route.Route{
+			Name:        "EventsThresholdDelete",
+			Method:      "DELETE",
+			Pattern:     "/events/thresholds",
+			Version:     1,
+			RequestType: utils.GetTypeString((*eventsapi.ThresholdDel)(nil)),
+			HandlerFunc: thresholdDeleteHandler},
+		route.Route{
+			Name:         "EventsThresholdList",
+			Method:       "GET",
+			Pattern:      "/events/thresholds",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*eventsapi.ThresholdList)(nil)),
+			HandlerFunc:  thresholdListHandler},
+		route.Route{
+			Name:         "AlertsList",
+			Method:       "GET",
+			Pattern:      "/alerts",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*eventsapi.AlertList)(nil)),
+			HandlerFunc:  alertsListHandler},
 	}
 }
 
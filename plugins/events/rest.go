@@ -194,3 +194,233 @@ func webhookTestHandler(w http.ResponseWriter, r *http.Request) {
 
 	restutils.SendHTTPResponse(ctx, w, http.StatusOK, nil)
 }
+
+func smtpSinkAddHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req eventsapi.SMTPSink
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrJSONParsingFailed)
+		return
+	}
+
+	if err := gd2events.ValidateSMTPSink(&req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	exists, err := configExists(smtpPrefix, req.Name)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError,
+			"Could not check if SMTP sink already exists")
+		return
+	}
+	if exists {
+		restutils.SendHTTPError(ctx, w, http.StatusConflict, "SMTP sink already exists")
+		return
+	}
+
+	if err := addConfig(smtpPrefix, req.Name, req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, "Could not add SMTP sink")
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, nil)
+}
+
+func smtpSinkListHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	sinks, err := GetSMTPSinkList()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, "Could not retrive SMTP sink list")
+		return
+	}
+
+	resp := make(eventsapi.SMTPSinkList, 0, len(sinks))
+	for _, s := range sinks {
+		resp = append(resp, *s)
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+func syslogSinkAddHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req eventsapi.SyslogSink
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrJSONParsingFailed)
+		return
+	}
+
+	if err := gd2events.ValidateSyslogSink(&req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	exists, err := configExists(syslogPrefix, req.Name)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError,
+			"Could not check if syslog sink already exists")
+		return
+	}
+	if exists {
+		restutils.SendHTTPError(ctx, w, http.StatusConflict, "Syslog sink already exists")
+		return
+	}
+
+	if err := addConfig(syslogPrefix, req.Name, req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, "Could not add syslog sink")
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, nil)
+}
+
+func syslogSinkListHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	sinks, err := GetSyslogSinkList()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, "Could not retrive syslog sink list")
+		return
+	}
+
+	resp := make(eventsapi.SyslogSinkList, 0, len(sinks))
+	for _, s := range sinks {
+		resp = append(resp, *s)
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+// sinkDeleteHandler returns a handler which deletes the named sink stored
+// under the given prefix
+func sinkDeleteHandler(prefix string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		var req eventsapi.SinkDel
+		if err := restutils.UnmarshalRequest(r, &req); err != nil {
+			restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrJSONParsingFailed)
+			return
+		}
+
+		if req.Name == "" {
+			restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "sink name is required field")
+			return
+		}
+
+		exists, err := configExists(prefix, req.Name)
+		if err != nil {
+			restutils.SendHTTPError(ctx, w, http.StatusInternalServerError,
+				"Could not check if sink exists")
+			return
+		}
+		if !exists {
+			restutils.SendHTTPError(ctx, w, http.StatusNotFound, "Sink does not exist")
+			return
+		}
+
+		if err := deleteConfig(prefix, req.Name); err != nil {
+			restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, "Could not delete sink")
+			return
+		}
+		restutils.SendHTTPResponse(ctx, w, http.StatusNoContent, nil)
+	}
+}
+
+func thresholdAddHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req eventsapi.Threshold
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrJSONParsingFailed)
+		return
+	}
+
+	if err := validateThreshold(&req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	exists, err := configExists(thresholdPrefix, req.Name)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError,
+			"Could not check if threshold already exists")
+		return
+	}
+	if exists {
+		restutils.SendHTTPError(ctx, w, http.StatusConflict, "Threshold already exists")
+		return
+	}
+
+	if err := addConfig(thresholdPrefix, req.Name, req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, "Could not add threshold")
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, nil)
+}
+
+func thresholdDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req eventsapi.ThresholdDel
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrJSONParsingFailed)
+		return
+	}
+
+	exists, err := configExists(thresholdPrefix, req.Name)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError,
+			"Could not check if threshold exists")
+		return
+	}
+	if req.Name == "" || !exists {
+		restutils.SendHTTPError(ctx, w, http.StatusNotFound, "Threshold does not exist")
+		return
+	}
+
+	if err := deleteConfig(thresholdPrefix, req.Name); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, "Could not delete threshold")
+		return
+	}
+	// Alerts raised by a deleted threshold can never be resolved
+	if err := deleteAlerts(req.Name); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, "Could not delete alerts of threshold")
+		return
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusNoContent, nil)
+}
+
+func thresholdListHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	thresholds, err := GetThresholdList()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, "Could not retrive threshold list")
+		return
+	}
+
+	resp := make(eventsapi.ThresholdList, 0, len(thresholds))
+	for _, t := range thresholds {
+		resp = append(resp, *t)
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+func alertsListHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	alerts, err := GetAlertList()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, "Could not retrive alerts list")
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, alerts)
+}
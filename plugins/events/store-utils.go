@@ -16,6 +16,8 @@ import (
 
 const (
 	webhookPrefix string = "config/events/webhooks/"
+	smtpPrefix           = "config/events/smtp/"
+	syslogPrefix         = "config/events/syslog/"
 	eventsPrefix         = "events/"
 )
 
@@ -99,3 +101,71 @@ func GetEventsList() ([]*api.Event, error) {
 
 	return events, nil
 }
+
+func configExists(prefix, name string) (bool, error) {
+	resp, e := store.Get(context.TODO(), prefix+name)
+	if e != nil {
+		log.WithError(e).WithField("name", name).Error("Couldn't retrive config from store")
+		return false, e
+	}
+	return resp.Count == 1, nil
+}
+
+func addConfig(prefix, name string, value interface{}) error {
+	v, e := json.Marshal(value)
+	if e != nil {
+		log.WithError(e).Error("Failed to marshal the config object")
+		return e
+	}
+
+	if _, err := store.Put(context.TODO(), prefix+name, string(v)); err != nil {
+		log.WithError(err).WithField("name", name).Error("Couldn't add config to store")
+		return err
+	}
+	return nil
+}
+
+func deleteConfig(prefix, name string) error {
+	_, e := store.Delete(context.TODO(), prefix+name)
+	return e
+}
+
+// GetSMTPSinkList returns list of all SMTP sinks registered to glusterd
+func GetSMTPSinkList() ([]*eventsapi.SMTPSink, error) {
+	resp, e := store.Get(context.TODO(), smtpPrefix, clientv3.WithPrefix())
+	if e != nil {
+		return nil, e
+	}
+
+	sinks := make([]*eventsapi.SMTPSink, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var s eventsapi.SMTPSink
+		if err := json.Unmarshal(kv.Value, &s); err != nil {
+			log.WithError(err).WithField("sink", string(kv.Key)).Error("Failed to unmarshal SMTP sink")
+			continue
+		}
+		sinks = append(sinks, &s)
+	}
+
+	return sinks, nil
+}
+
+// GetSyslogSinkList returns list of all syslog sinks registered to glusterd
+func GetSyslogSinkList() ([]*eventsapi.SyslogSink, error) {
+	resp, e := store.Get(context.TODO(), syslogPrefix, clientv3.WithPrefix())
+	if e != nil {
+		return nil, e
+	}
+
+	sinks := make([]*eventsapi.SyslogSink, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var s eventsapi.SyslogSink
+		if err := json.Unmarshal(kv.Value, &s); err != nil {
+			log.WithError(err).WithField("sink", string(kv.Key)).Error("Failed to unmarshal syslog sink")
+			continue
+		}
+		sinks = append(sinks, &s)
+	}
+
+	return sinks, nil
+}
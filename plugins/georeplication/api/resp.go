@@ -62,6 +62,20 @@ type GeorepWorker struct {
 	CrawlStatus                string `json:"crawl_status"`
 }
 
+// GeorepSummary represents the rollup of the statuses of the workers of a
+// Geo-replication session
+type GeorepSummary struct {
+	Workers      int `json:"workers"`
+	Active       int `json:"active"`
+	Passive      int `json:"passive"`
+	Faulty       int `json:"faulty"`
+	Initializing int `json:"initializing"`
+	Unknown      int `json:"unknown"`
+	// MaxLagSeconds is the time since the oldest last synced time of the
+	// active workers
+	MaxLagSeconds int64 `json:"max_lag_seconds"`
+}
+
 // GeorepSSHPublicKey represents one nodes SSH Public key
 type GeorepSSHPublicKey struct {
 	PeerID    uuid.UUID `json:"peerid"`
@@ -79,6 +93,7 @@ type GeorepSession struct {
 	RemoteVol   string             `json:"remote_volume"`
 	Status      string             `json:"monitor_status"`
 	Workers     []GeorepWorker     `json:"workers"`
+	Summary     *GeorepSummary     `json:"summary,omitempty"`
 	Options     map[string]string  `json:"options"`
 }
 
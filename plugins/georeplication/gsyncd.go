@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"path"
 	"strings"
+	"time"
 
+	"github.com/gluster/glusterd2/glusterd2/daemon"
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
 	"github.com/gluster/glusterd2/pkg/utils"
 	georepapi "github.com/gluster/glusterd2/plugins/georeplication/api"
@@ -122,3 +124,13 @@ func (g *Gsyncd) statusArgs(localPath string) []string {
 		localPath,
 		"--json"}
 }
+
+func init() {
+	// gsyncd has no socket to check, and restarts its workers itself
+	daemon.RegisterPolicy("gsyncd", daemon.Policy{
+		DependsOn:     []string{"glusterfsd"},
+		Restart:       daemon.RestartAlways,
+		MaxRestarts:   3,
+		RestartWindow: 10 * time.Minute,
+	})
+}
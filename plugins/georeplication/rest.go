@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/gluster/glusterd2/glusterd2/events"
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
@@ -515,6 +516,10 @@ func georepStatusHandler(w http.ResponseWriter, r *http.Request) {
 			Nodes:  txn.Nodes,
 		},
 	}
+	// The workers of the peers which are down are reported with an
+	// unknown status
+	txn.DontCheckAlive = true
+	txn.DisableRollback = true
 
 	if err = txn.Ctx.Set("mastervolid", masterid.String()); err != nil {
 		logger.WithError(err).Error("failed to set mastervolid in transaction context")
@@ -582,7 +587,10 @@ func georepStatusHandler(w http.ResponseWriter, r *http.Request) {
 	// assignment. So that order of the workers will be maintained similar
 	// to order of bricks in Master Volume
 	for idx, w := range geoSession.Workers {
-		statusData := (*result)[w.MasterPeerID+":"+w.MasterBrickPath]
+		statusData, ok := (*result)[w.MasterPeerID+":"+w.MasterBrickPath]
+		if !ok {
+			continue
+		}
 		geoSession.Workers[idx].Status = statusData.Status
 		geoSession.Workers[idx].LastSyncedTime = statusData.LastSyncedTime
 		geoSession.Workers[idx].LastSyncedTimeUTC = statusData.LastSyncedTimeUTC
@@ -600,6 +608,13 @@ func georepStatusHandler(w http.ResponseWriter, r *http.Request) {
 		geoSession.Workers[idx].CrawlStatus = statusData.CrawlStatus
 	}
 
+	// The statuses of the workers are only sent when asked for, as there
+	// is one per brick of the master volume
+	geoSession.Summary = summarize(geoSession.Workers, time.Now().UTC())
+	if r.URL.Query().Get("detail") != "true" {
+		geoSession.Workers = nil
+	}
+
 	// Send aggregated result back to the client
 	restutils.SendHTTPResponse(ctx, w, http.StatusOK, geoSession)
 }
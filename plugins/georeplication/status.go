@@ -0,0 +1,154 @@
+package georeplication
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/pkg/utils"
+
+	georepapi "github.com/gluster/glusterd2/plugins/georeplication/api"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// statusConcurrency is the number of workers of a peer whose status
+	// is queried at once
+	statusConcurrency = 16
+
+	// statusCacheTTL is the time the status of a worker is reused for,
+	// so that the status requests of many clients do not each run gsyncd
+	// for every worker
+	statusCacheTTL = 5 * time.Second
+
+	// lastSyncedFormat is the format of the last synced times of gsyncd
+	lastSyncedFormat = "2006-01-02 15:04:05"
+)
+
+type cachedWorkerStatus struct {
+	worker georepapi.GeorepWorker
+	at     time.Time
+}
+
+// statusCache has the recent statuses of the local workers, by session and
+// brick path
+var statusCache = struct {
+	sync.Mutex
+	workers map[string]cachedWorkerStatus
+}{
+	workers: make(map[string]cachedWorkerStatus),
+}
+
+// workerStatus returns the status of the worker of the session syncing the
+// local brick, from the cache if it is recent enough
+func workerStatus(g *Gsyncd, b brick.Brickinfo) (georepapi.GeorepWorker, error) {
+	key := g.ID() + ":" + b.Path
+
+	statusCache.Lock()
+	cached, ok := statusCache.workers[key]
+	statusCache.Unlock()
+	if ok && time.Since(cached.at) < statusCacheTTL {
+		return cached.worker, nil
+	}
+
+	out, err := utils.ExecuteCommandOutput(getGsyncdCommand(), g.statusArgs(b.Path)...)
+	if err != nil {
+		return georepapi.GeorepWorker{}, err
+	}
+	var worker georepapi.GeorepWorker
+	if err := json.Unmarshal(out, &worker); err != nil {
+		return georepapi.GeorepWorker{}, err
+	}
+
+	statusCache.Lock()
+	statusCache.workers[key] = cachedWorkerStatus{worker: worker, at: time.Now()}
+	statusCache.Unlock()
+	return worker, nil
+}
+
+// pruneStatusCache forgets the statuses which are no longer recent enough to
+// be reused, of the workers of deleted sessions and bricks among others
+func pruneStatusCache() {
+	statusCache.Lock()
+	defer statusCache.Unlock()
+	for key, cached := range statusCache.workers {
+		if time.Since(cached.at) >= statusCacheTTL {
+			delete(statusCache.workers, key)
+		}
+	}
+}
+
+// localWorkersStatus queries the statuses of the workers of the session
+// syncing the given local bricks, statusConcurrency at a time, by brick path.
+// The workers whose status could not be had are left out, and reported with
+// an unknown status.
+func localWorkersStatus(sessioninfo georepapi.GeorepSession, bricks []brick.Brickinfo, logger log.FieldLogger) (map[string]georepapi.GeorepWorker, error) {
+	gsyncd, err := newGsyncd(sessioninfo)
+	if err != nil {
+		return nil, err
+	}
+	pruneStatusCache()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		statuses = make(map[string]georepapi.GeorepWorker, len(bricks))
+		sem      = make(chan struct{}, statusConcurrency)
+	)
+	for _, b := range bricks {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(b brick.Brickinfo) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			worker, err := workerStatus(gsyncd, b)
+			if err != nil {
+				logger.WithError(err).WithField("brick", b.Path).Error("failed to get the status of geo-replication worker")
+				return
+			}
+			mu.Lock()
+			statuses[b.Path] = worker
+			mu.Unlock()
+		}(b)
+	}
+	wg.Wait()
+
+	return statuses, nil
+}
+
+// summarize returns the rollup of the statuses of the workers of a session.
+// The lag is the time since the oldest last synced time of the active
+// workers, as of now.
+func summarize(workers []georepapi.GeorepWorker, now time.Time) *georepapi.GeorepSummary {
+	s := &georepapi.GeorepSummary{Workers: len(workers)}
+	var oldest time.Time
+	for _, w := range workers {
+		switch w.Status {
+		case georepapi.GeorepStatusActive:
+			s.Active++
+			synced, err := time.Parse(lastSyncedFormat, w.LastSyncedTimeUTC)
+			if err != nil {
+				continue
+			}
+			if oldest.IsZero() || synced.Before(oldest) {
+				oldest = synced
+			}
+		case georepapi.GeorepStatusPassive:
+			s.Passive++
+		case georepapi.GeorepStatusFaulty:
+			s.Faulty++
+		case georepapi.GeorepStatusInitializing:
+			s.Initializing++
+		default:
+			s.Unknown++
+		}
+	}
+
+	if !oldest.IsZero() && now.After(oldest) {
+		s.MaxLagSeconds = int64(now.Sub(oldest) / time.Second)
+	}
+	return s
+}
@@ -0,0 +1,35 @@
+package georeplication
+
+import (
+	"testing"
+	"time"
+
+	georepapi "github.com/gluster/glusterd2/plugins/georeplication/api"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSummarize(t *testing.T) {
+	now := time.Date(2018, 7, 21, 16, 10, 0, 0, time.UTC)
+	workers := []georepapi.GeorepWorker{
+		{Status: georepapi.GeorepStatusActive, LastSyncedTimeUTC: "2018-07-21 16:03:51"},
+		{Status: georepapi.GeorepStatusActive, LastSyncedTimeUTC: "2018-07-21 16:01:40"},
+		{Status: georepapi.GeorepStatusActive, LastSyncedTimeUTC: "N/A"},
+		{Status: georepapi.GeorepStatusPassive, LastSyncedTimeUTC: "2018-07-21 15:00:00"},
+		{Status: georepapi.GeorepStatusFaulty},
+		{Status: georepapi.GeorepStatusInitializing},
+		{Status: georepapi.GeorepStatusUnknown},
+	}
+
+	s := summarize(workers, now)
+	assert.Equal(t, 7, s.Workers)
+	assert.Equal(t, 3, s.Active)
+	assert.Equal(t, 1, s.Passive)
+	assert.Equal(t, 1, s.Faulty)
+	assert.Equal(t, 1, s.Initializing)
+	assert.Equal(t, 1, s.Unknown)
+	// The lag of the passive workers is not counted
+	assert.Equal(t, int64(500), s.MaxLagSeconds)
+
+	assert.Equal(t, int64(0), summarize(nil, now).MaxLagSeconds)
+}
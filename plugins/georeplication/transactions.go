@@ -1,8 +1,6 @@
 package georeplication
 
 import (
-	"encoding/json"
-	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -157,28 +155,15 @@ func txnGeorepStatus(c transaction.TxnCtx) error {
 		return err
 	}
 
-	var workersStatuses = make(map[string]georepapi.GeorepWorker)
-
-	for _, w := range volinfo.GetLocalBricks() {
-		gsyncd, err := newGsyncd(*sessioninfo)
-		if err != nil {
-			return err
-		}
-		args := gsyncd.statusArgs(w.Path)
-
-		out, err := utils.ExecuteCommandOutput(getGsyncdCommand(), args...)
-		if err != nil {
-			return err
-		}
-
-		var worker georepapi.GeorepWorker
-		if err = json.Unmarshal(out, &worker); err != nil {
-			return err
-		}
+	statuses, err := localWorkersStatus(*sessioninfo, volinfo.GetLocalBricks(), c.Logger())
+	if err != nil {
+		return err
+	}
 
-		// Unique key for master brick UUID:BRICK_PATH
-		key := gdctx.MyUUID.String() + ":" + w.Path
-		workersStatuses[key] = worker
+	// Unique key for master brick UUID:BRICK_PATH
+	var workersStatuses = make(map[string]georepapi.GeorepWorker, len(statuses))
+	for path, worker := range statuses {
+		workersStatuses[gdctx.MyUUID.String()+":"+path] = worker
 	}
 
 	c.SetNodeResult(gdctx.MyUUID, gsyncdStatusTxnKey, workersStatuses)
@@ -190,11 +175,14 @@ func aggregateGsyncdStatus(ctx transaction.TxnCtx, nodes []uuid.UUID) (*map[stri
 
 	// Loop over each node on which txn was run.
 	// Fetch brick statuses stored by each node in transaction context.
+	// The nodes which could not be reached have no results, their workers
+	// are reported with an unknown status.
 	for _, node := range nodes {
 		var tmp = make(map[string]georepapi.GeorepWorker)
 		err := ctx.GetNodeResult(node, gsyncdStatusTxnKey, &tmp)
 		if err != nil {
-			return nil, errors.New("aggregateGsyncdStatus: Could not fetch results from transaction context")
+			ctx.Logger().WithError(err).WithField("peer", node.String()).Warn("no geo-replication status from peer")
+			continue
 		}
 
 		// Single final Hashmap
@@ -0,0 +1,72 @@
+package gfproxy
+
+import (
+	"github.com/gluster/glusterd2/glusterd2/daemon"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/glusterd2/xlator"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// startGfproxyd starts the gfproxy daemon of the volume on this node
+func startGfproxyd(v *volume.Volinfo, logger log.FieldLogger) error {
+	d, err := newGfproxyd(v.Name)
+	if err != nil {
+		return err
+	}
+	err = daemon.Start(d, true, logger)
+	if err != nil && err != gderrors.ErrProcessAlreadyRunning {
+		return err
+	}
+	return nil
+}
+
+// stopGfproxyd stops the gfproxy daemon of the volume on this node
+func stopGfproxyd(v *volume.Volinfo, logger log.FieldLogger) error {
+	d, err := newGfproxyd(v.Name)
+	if err != nil {
+		return err
+	}
+	err = daemon.Stop(d, true, logger)
+	if err != nil && err != gderrors.ErrPidFileNotFound {
+		return err
+	}
+	return nil
+}
+
+// gfproxyActor starts and stops the gfproxy daemons along with the volumes
+// which have gfproxy enabled
+type gfproxyActor struct{}
+
+func (actor *gfproxyActor) Do(v *volume.Volinfo, key string, value string, volOp xlator.VolumeOpType, logger log.FieldLogger) error {
+	if !IsEnabled(v) {
+		return nil
+	}
+
+	switch volOp {
+	case xlator.VolumeStart:
+		return startGfproxyd(v, logger)
+	case xlator.VolumeStop:
+		return stopGfproxyd(v, logger)
+	}
+	return nil
+}
+
+func (actor *gfproxyActor) Undo(v *volume.Volinfo, key string, value string, volOp xlator.VolumeOpType, logger log.FieldLogger) error {
+	if !IsEnabled(v) {
+		return nil
+	}
+
+	switch volOp {
+	case xlator.VolumeStart:
+		return stopGfproxyd(v, logger)
+	case xlator.VolumeStop:
+		return startGfproxyd(v, logger)
+	}
+	return nil
+}
+
+func init() {
+	xlator.RegisterOptionActor("gfproxy", &gfproxyActor{})
+}
@@ -0,0 +1,9 @@
+package api
+
+// GfProxyStatus represents the gfproxy state of a volume. Thin clients mount
+// the volume with the volfile ID ClientVolfileID, for example
+// `mount -t glusterfs -o volfile-id=gfproxy-client/testvol host:/testvol /mnt`.
+type GfProxyStatus struct {
+	Enabled         bool   `json:"enabled"`
+	ClientVolfileID string `json:"client-volfile-id,omitempty"`
+}
@@ -0,0 +1,14 @@
+package gfproxy
+
+import (
+	"errors"
+)
+
+var (
+	// ErrGfProxyAlreadyEnabled : gfproxy is already enabled on the volume
+	ErrGfProxyAlreadyEnabled = errors.New("gfproxy is already enabled")
+	// ErrGfProxyAlreadyDisabled : gfproxy is already disabled on the volume
+	ErrGfProxyAlreadyDisabled = errors.New("gfproxy is already disabled")
+	// ErrGfProxyNotEnabled : volfiles of gfproxy requested for a volume not using it
+	ErrGfProxyNotEnabled = errors.New("gfproxy is not enabled")
+)
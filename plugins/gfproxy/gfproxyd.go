@@ -0,0 +1,112 @@
+package gfproxy
+
+import (
+	"fmt"
+	"net"
+	"os/exec"
+	"path"
+	"time"
+
+	"github.com/cespare/xxhash"
+	"github.com/gluster/glusterd2/glusterd2/daemon"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+
+	config "github.com/spf13/viper"
+)
+
+const (
+	gfproxydBin = "glusterfsd"
+)
+
+// Gfproxyd type represents information about the gfproxy daemon of a volume
+type Gfproxyd struct {
+	binarypath     string
+	args           []string
+	socketfilepath string
+	pidfilepath    string
+	volname        string
+}
+
+// Name returns human-friendly name of the gfproxyd process. This is used for
+// logging
+func (g *Gfproxyd) Name() string {
+	return "gfproxyd"
+}
+
+// Path returns absolute path of the binary of gfproxyd process
+func (g *Gfproxyd) Path() string {
+	return g.binarypath
+}
+
+// Args returns arguments to be passed to gfproxyd process during spawn.
+// gfproxyd signs in with the portmapper like a brick, with the name of the
+// subvolume it exports, so that the thin clients can find its port.
+func (g *Gfproxyd) Args() []string {
+	if g.args != nil {
+		return g.args
+	}
+
+	shost, sport, _ := net.SplitHostPort(config.GetString("clientaddress"))
+	if shost == "" {
+		shost = "127.0.0.1"
+	}
+
+	logFile := path.Join(config.GetString("logdir"), "glusterfs", "gfproxyd-"+g.volname+".log")
+
+	g.args = []string{}
+	g.args = append(g.args, "--volfile-server", shost)
+	g.args = append(g.args, "--volfile-server-port", sport)
+	g.args = append(g.args, "--volfile-id", VolfileID(g.volname))
+	g.args = append(g.args, "-p", g.PidFile())
+	g.args = append(g.args, "-S", g.SocketFile())
+	g.args = append(g.args, "--brick-name", Subvolume(g.volname))
+	g.args = append(g.args, "-l", logFile)
+
+	return g.args
+}
+
+// SocketFile returns path to the socket file used for IPC.
+func (g *Gfproxyd) SocketFile() string {
+	if g.socketfilepath != "" {
+		return g.socketfilepath
+	}
+	g.socketfilepath = fmt.Sprintf("%s/gfproxyd-%x.socket", config.GetString("rundir"),
+		xxhash.Sum64String(gdctx.MyUUID.String()+g.volname))
+
+	return g.socketfilepath
+}
+
+// PidFile returns path to the pid file of the gfproxyd process.
+func (g *Gfproxyd) PidFile() string {
+	if g.pidfilepath != "" {
+		return g.pidfilepath
+	}
+	g.pidfilepath = path.Join(config.GetString("rundir"), "gfproxyd-"+g.volname+".pid")
+
+	return g.pidfilepath
+}
+
+// ID returns the unique identifier of the daemon. One gfproxyd runs per
+// volume on a node.
+func (g *Gfproxyd) ID() string {
+	return "gfproxyd-" + g.volname
+}
+
+// newGfproxyd returns a new instance of Gfproxyd type which implements the
+// daemon interface
+func newGfproxyd(volname string) (*Gfproxyd, error) {
+	path, e := exec.LookPath(gfproxydBin)
+	if e != nil {
+		return nil, e
+	}
+	return &Gfproxyd{binarypath: path, volname: volname}, nil
+}
+
+func init() {
+	daemon.RegisterPolicy("gfproxyd", daemon.Policy{
+		DependsOn:     []string{"glusterfsd"},
+		Restart:       daemon.RestartAlways,
+		MaxRestarts:   5,
+		RestartWindow: 10 * time.Minute,
+	})
+}
@@ -0,0 +1,28 @@
+package gfproxy
+
+import (
+	"testing"
+
+	config "github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGfproxydArgs(t *testing.T) {
+	defer config.Reset()
+	config.Set("clientaddress", ":24007")
+	config.Set("rundir", "/var/run/glusterd2")
+	config.Set("logdir", "/var/log/glusterd2")
+
+	g := &Gfproxyd{volname: "vol1"}
+	assert.Equal(t, "gfproxyd-vol1", g.ID())
+	assert.Equal(t, "/var/run/glusterd2/gfproxyd-vol1.pid", g.PidFile())
+	assert.Equal(t, []string{
+		"--volfile-server", "127.0.0.1",
+		"--volfile-server-port", "24007",
+		"--volfile-id", "gfproxyd/vol1",
+		"-p", "/var/run/glusterd2/gfproxyd-vol1.pid",
+		"-S", g.SocketFile(),
+		"--brick-name", "vol1-gfproxyd",
+		"-l", "/var/log/glusterd2/glusterfs/gfproxyd-vol1.log",
+	}, g.Args())
+}
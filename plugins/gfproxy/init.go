@@ -0,0 +1,55 @@
+package gfproxy
+
+import (
+	"github.com/gluster/glusterd2/glusterd2/servers/rest/route"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/pkg/utils"
+	gfproxyapi "github.com/gluster/glusterd2/plugins/gfproxy/api"
+)
+
+const name = "gfproxy"
+
+// Plugin is a structure which implements GlusterdPlugin interface
+type Plugin struct {
+}
+
+// Name returns name of plugin
+func (p *Plugin) Name() string {
+	return name
+}
+
+// RestRoutes returns list of REST API routes to register with Glusterd
+func (p *Plugin) RestRoutes() route.Routes {
+	return route.Routes{
+		route.Route{
+			Name:         "GfProxyStatus",
+			Method:       "GET",
+			Pattern:      "/volumes/{volname}/gfproxy",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*gfproxyapi.GfProxyStatus)(nil)),
+			HandlerFunc:  gfproxyStatusHandler},
+		route.Route{
+			Name:         "GfProxyEnable",
+			Method:       "POST",
+			Pattern:      "/volumes/{volname}/gfproxy/enable",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*gfproxyapi.GfProxyStatus)(nil)),
+			HandlerFunc:  gfproxyEnableHandler},
+		route.Route{
+			Name:         "GfProxyDisable",
+			Method:       "POST",
+			Pattern:      "/volumes/{volname}/gfproxy/disable",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*gfproxyapi.GfProxyStatus)(nil)),
+			HandlerFunc:  gfproxyDisableHandler},
+	}
+}
+
+// RegisterStepFuncs registers transaction step functions with
+// Glusterd Transaction framework
+func (p *Plugin) RegisterStepFuncs() {
+	transaction.RegisterStepFunc(txnGfproxyEnable, "gfproxy-enable.Commit")
+	transaction.RegisterStepFunc(txnGfproxyDisable, "gfproxy-enable.Undo")
+	transaction.RegisterStepFunc(txnGfproxyDisable, "gfproxy-disable.Commit")
+	transaction.RegisterStepFunc(txnGfproxyEnable, "gfproxy-disable.Undo")
+}
@@ -0,0 +1,121 @@
+package gfproxy
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	gfproxyapi "github.com/gluster/glusterd2/plugins/gfproxy/api"
+
+	"github.com/gorilla/mux"
+	"github.com/pborman/uuid"
+)
+
+func createStatusResp(v *volume.Volinfo) gfproxyapi.GfProxyStatus {
+	resp := gfproxyapi.GfProxyStatus{Enabled: IsEnabled(v)}
+	if resp.Enabled {
+		resp.ClientVolfileID = ClientVolfileID(v.Name)
+	}
+	return resp
+}
+
+func gfproxyStatusHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	volname := mux.Vars(r)["volname"]
+
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, createStatusResp(volinfo))
+}
+
+func gfproxyEnableHandler(w http.ResponseWriter, r *http.Request) {
+	gfproxyEnableDisable(w, r, true)
+}
+
+func gfproxyDisableHandler(w http.ResponseWriter, r *http.Request) {
+	gfproxyEnableDisable(w, r, false)
+}
+
+func gfproxyEnableDisable(w http.ResponseWriter, r *http.Request, enable bool) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+	volname := mux.Vars(r)["volname"]
+
+	txn, err := transaction.NewTxnWithLocks(ctx, volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	defer txn.Done()
+
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	if enable && IsEnabled(volinfo) {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, ErrGfProxyAlreadyEnabled)
+		return
+	}
+	if !enable && !IsEnabled(volinfo) {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, ErrGfProxyAlreadyDisabled)
+		return
+	}
+
+	// save volume information for transaction failure scenario
+	if err := txn.Ctx.Set("oldvolinfo", volinfo); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if volinfo.Metadata == nil {
+		volinfo.Metadata = make(map[string]string)
+	}
+	action := "enable"
+	if enable {
+		volinfo.Metadata[metadataKey] = "on"
+	} else {
+		delete(volinfo.Metadata, metadataKey)
+		action = "disable"
+	}
+
+	if err := txn.Ctx.Set("volinfo", volinfo); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	txn.Nodes = volinfo.Nodes()
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc:   "vol-option.UpdateVolinfo",
+			UndoFunc: "vol-option.UpdateVolinfo.Undo",
+			Nodes:    []uuid.UUID{gdctx.MyUUID},
+			// Volinfo needs to be updated before the gfproxy
+			// daemons fetch their volfiles
+			Sync: true,
+		},
+		{
+			DoFunc:   "gfproxy-" + action + ".Commit",
+			UndoFunc: "gfproxy-" + action + ".Undo",
+			Nodes:    txn.Nodes,
+		},
+	}
+
+	if err := txn.Do(); err != nil {
+		logger.WithError(err).WithField("volume", volname).Error("failed to " + action + " gfproxy")
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, createStatusResp(volinfo))
+}
@@ -0,0 +1,30 @@
+package gfproxy
+
+import (
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+)
+
+// txnGfproxyEnable starts the gfproxy daemon of the volume on this node, if
+// the volume is started
+func txnGfproxyEnable(c transaction.TxnCtx) error {
+	var volinfo volume.Volinfo
+	if err := c.Get("volinfo", &volinfo); err != nil {
+		return err
+	}
+
+	if volinfo.State != volume.VolStarted {
+		return nil
+	}
+	return startGfproxyd(&volinfo, c.Logger())
+}
+
+// txnGfproxyDisable stops the gfproxy daemon of the volume on this node
+func txnGfproxyDisable(c transaction.TxnCtx) error {
+	var volinfo volume.Volinfo
+	if err := c.Get("volinfo", &volinfo); err != nil {
+		return err
+	}
+
+	return stopGfproxyd(&volinfo, c.Logger())
+}
@@ -0,0 +1,96 @@
+package gfproxy
+
+import (
+	"strings"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/volgen"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/utils"
+
+	"github.com/pborman/uuid"
+)
+
+const (
+	// metadataKey is the Volinfo Metadata key set to "on" when gfproxy is
+	// enabled on the volume
+	metadataKey = "_gfproxy"
+
+	// volfileIDPrefix prefixes the volume name in the volfile ID of the
+	// gfproxy daemon
+	volfileIDPrefix = "gfproxyd/"
+	// clientVolfileIDPrefix prefixes the volume name in the volfile ID
+	// requested by the thin clients, for example
+	// `mount -t glusterfs -o volfile-id=gfproxy-client/testvol`
+	clientVolfileIDPrefix = "gfproxy-client/"
+)
+
+// IsEnabled returns true if gfproxy is enabled on the volume
+func IsEnabled(v *volume.Volinfo) bool {
+	return v.Metadata[metadataKey] == "on"
+}
+
+// VolfileID returns the volfile ID of the gfproxy daemon of the volume
+func VolfileID(volname string) string {
+	return volfileIDPrefix + volname
+}
+
+// ClientVolfileID returns the volfile ID of the thin clients of the volume
+func ClientVolfileID(volname string) string {
+	return clientVolfileIDPrefix + volname
+}
+
+// Subvolume returns the name of the subvolume exported by the gfproxy daemon
+// of the volume
+func Subvolume(volname string) string {
+	return volname + "-gfproxyd"
+}
+
+// VolumeOfVolfileID returns the name of the volume if the volfile ID is of
+// the gfproxy daemon or of the thin clients
+func VolumeOfVolfileID(volfileID string) (string, bool) {
+	for _, prefix := range []string{volfileIDPrefix, clientVolfileIDPrefix} {
+		if strings.HasPrefix(volfileID, prefix) {
+			return strings.TrimPrefix(volfileID, prefix), true
+		}
+	}
+	return "", false
+}
+
+// proxyHost returns the host of the gfproxy daemon the thin clients fetching
+// their volfile from this node connect to. It is this node if it runs a
+// gfproxy daemon of the volume, or else the first node hosting the bricks of
+// the volume.
+func proxyHost(v *volume.Volinfo) string {
+	bricks := v.GetBricks()
+	for _, b := range bricks {
+		if uuid.Equal(b.PeerID, gdctx.MyUUID) {
+			return b.Hostname
+		}
+	}
+	if len(bricks) > 0 {
+		return bricks[0].Hostname
+	}
+	return gdctx.HostName
+}
+
+// Volfile generates the volfile of the gfproxy daemon or of the thin clients
+// of the volume, as requested by the volfile ID
+func Volfile(v *volume.Volinfo, volfileID string) (string, error) {
+	if !IsEnabled(v) {
+		return "", ErrGfProxyNotEnabled
+	}
+
+	tmplName := utils.GfProxyClientVolfile
+	host := proxyHost(v)
+	if strings.HasPrefix(volfileID, volfileIDPrefix) {
+		tmplName = utils.GfProxyVolfile
+		host = gdctx.HostName
+	}
+
+	tmpl, err := volgen.GetTemplateFromVolinfo(v, tmplName)
+	if err != nil {
+		return "", err
+	}
+	return volgen.GfProxyVolfile(tmpl, v, host, Subvolume(v.Name))
+}
@@ -0,0 +1,55 @@
+package gfproxy
+
+import (
+	"testing"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVolumeOfVolfileID(t *testing.T) {
+	for _, volfileID := range []string{VolfileID("vol1"), ClientVolfileID("vol1")} {
+		volname, ok := VolumeOfVolfileID(volfileID)
+		assert.True(t, ok)
+		assert.Equal(t, "vol1", volname)
+	}
+
+	// The volfiles of the bricks and the clients are not of gfproxy
+	for _, volfileID := range []string{"vol1", "vol1.node1.bricks-b1", "gluster/glustershd", "snaps/snap1"} {
+		_, ok := VolumeOfVolfileID(volfileID)
+		assert.False(t, ok)
+	}
+}
+
+func TestProxyHost(t *testing.T) {
+	defer func(id uuid.UUID, host string) {
+		gdctx.MyUUID, gdctx.HostName = id, host
+	}(gdctx.MyUUID, gdctx.HostName)
+	gdctx.MyUUID = uuid.NewRandom()
+	gdctx.HostName = "local"
+
+	remote := brick.Brickinfo{PeerID: uuid.NewRandom(), Hostname: "remote", Path: "/bricks/b1"}
+	local := brick.Brickinfo{PeerID: gdctx.MyUUID, Hostname: "local-ip", Path: "/bricks/b2"}
+
+	// The thin clients connect to the gfproxy daemon of this node if it
+	// hosts a brick of the volume
+	v := &volume.Volinfo{Subvols: []volume.Subvol{{Bricks: []brick.Brickinfo{remote, local}}}}
+	assert.Equal(t, "local-ip", proxyHost(v))
+
+	// or else to the first node hosting the bricks
+	v = &volume.Volinfo{Subvols: []volume.Subvol{{Bricks: []brick.Brickinfo{remote}}}}
+	assert.Equal(t, "remote", proxyHost(v))
+
+	assert.Equal(t, "local", proxyHost(&volume.Volinfo{}))
+}
+
+func TestVolfileNotEnabled(t *testing.T) {
+	v := &volume.Volinfo{Name: "vol1", Metadata: map[string]string{}}
+	assert.False(t, IsEnabled(v))
+	_, err := Volfile(v, ClientVolfileID("vol1"))
+	assert.Equal(t, ErrGfProxyNotEnabled, err)
+}
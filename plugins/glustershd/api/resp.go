@@ -2,6 +2,7 @@ package api
 
 import (
 	"encoding/xml"
+	"time"
 )
 
 // FileGfID represents the file details on a volume
@@ -26,6 +27,38 @@ type BrickHealInfo struct {
 	EntriesPossiblyHealing    *int64     `json:"entries-possibly-healing,omitempty"`
 	Entries                   *int64     `json:"entries,omitempty"`
 	Files                     []FileGfID `xml:"file" json:"file-gfid,omitempty"`
+	Trend                     *HealTrend `xml:"-" json:"trend,omitempty"`
+}
+
+// States of the heal backlog of a brick
+const (
+	// HealTrendHealed is the state of a brick with no entry to heal
+	HealTrendHealed = "healed"
+	// HealTrendConverging is the state of a brick whose backlog shrinks
+	HealTrendConverging = "converging"
+	// HealTrendGrowing is the state of a brick whose backlog grows
+	HealTrendGrowing = "growing"
+	// HealTrendStalled is the state of a brick whose backlog does not
+	// change
+	HealTrendStalled = "stalled"
+	// HealTrendUnknown is the state of a brick without enough recent
+	// samples of its backlog
+	HealTrendUnknown = "unknown"
+)
+
+// HealTrend represents the trend of the heal backlog of a brick, computed
+// from the recent samples of its heal info
+type HealTrend struct {
+	State   string `json:"state"`
+	Backlog int64  `json:"backlog"`
+	// RatePerMinute is the change of the backlog, negative when the
+	// backlog shrinks
+	RatePerMinute float64 `json:"rate-per-minute"`
+	// ETASeconds is the estimated time for the backlog to be healed, set
+	// only when the backlog shrinks
+	ETASeconds int64      `json:"eta-seconds,omitempty"`
+	ETA        *time.Time `json:"eta,omitempty"`
+	Samples    int        `json:"samples"`
 }
 
 // HealInfo represents structure of stdout while running glfsheal binary
@@ -5,8 +5,10 @@ import (
 	"net"
 	"os/exec"
 	"path"
+	"time"
 
 	"github.com/cespare/xxhash"
+	"github.com/gluster/glusterd2/glusterd2/daemon"
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
 
 	config "github.com/spf13/viper"
@@ -97,3 +99,16 @@ func newGlustershd() (*Glustershd, error) {
 func (shd *Glustershd) ID() string {
 	return "glustershd"
 }
+
+func init() {
+	// the self-heal daemon heals the bricks of this peer, which are to be
+	// running for it to be of any use
+	daemon.RegisterPolicy("glustershd", daemon.Policy{
+		DependsOn:           []string{"glusterfsd"},
+		Restart:             daemon.RestartAlways,
+		MaxRestarts:         5,
+		RestartWindow:       10 * time.Minute,
+		HealthCheck:         daemon.SocketHealthCheck,
+		HealthCheckFailures: 3,
+	})
+}
@@ -49,6 +49,17 @@ func (p *Plugin) RestRoutes() route.Routes {
 	}
 }
 
+// Start starts sampling the heal backlog of the bricks, for the trend of
+// their heal
+func (p *Plugin) Start() {
+	startHealTrend()
+}
+
+// Stop stops sampling the heal backlog of the bricks
+func (p *Plugin) Stop() {
+	stopHealTrend()
+}
+
 // RegisterStepFuncs registers transaction step functions with
 // Glusterd Transaction framework
 func (p *Plugin) RegisterStepFuncs() {
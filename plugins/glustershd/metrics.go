@@ -0,0 +1,137 @@
+package glustershd
+
+import (
+	"context"
+	"encoding/xml"
+	"sync"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/metrics"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	glustershdapi "github.com/gluster/glusterd2/plugins/glustershd/api"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+)
+
+// healInfoInterval is the minimum interval between two runs of glfsheal
+// for metrics, as heal info is expensive to gather
+const healInfoInterval = time.Minute
+
+// healCollector exports heal pending counts of the bricks local to this
+// node, so that each brick is reported by exactly one node
+type healCollector struct {
+	pending    *prometheus.Desc
+	splitBrain *prometheus.Desc
+	healing    *prometheus.Desc
+	rate       *prometheus.Desc
+	eta        *prometheus.Desc
+
+	mu      sync.Mutex
+	updated time.Time
+	cache   []prometheus.Metric
+}
+
+func newHealCollector() *healCollector {
+	labels := []string{"volume", "brick"}
+	return &healCollector{
+		pending:    metrics.NewDesc("heal", "pending_entries", "Number of entries pending heal on the brick", labels...),
+		splitBrain: metrics.NewDesc("heal", "split_brain_entries", "Number of entries in split-brain on the brick", labels...),
+		healing:    metrics.NewDesc("heal", "possibly_healing_entries", "Number of entries possibly being healed on the brick", labels...),
+		rate:       metrics.NewDesc("heal", "backlog_rate_per_minute", "Change of the heal backlog of the brick per minute over the last hour, negative while it shrinks", labels...),
+		eta:        metrics.NewDesc("heal", "eta_seconds", "Estimated time for the heal backlog of the brick to be healed, while it shrinks", labels...),
+	}
+}
+
+func (c *healCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.pending
+	ch <- c.splitBrain
+	ch <- c.healing
+	ch <- c.rate
+	ch <- c.eta
+}
+
+func (c *healCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if time.Since(c.updated) > healInfoInterval {
+		c.cache = c.gather()
+		c.updated = time.Now()
+	}
+	for _, m := range c.cache {
+		ch <- m
+	}
+}
+
+func (c *healCollector) gather() []prometheus.Metric {
+	var ms []prometheus.Metric
+
+	volumes, err := volume.GetVolumes(context.TODO())
+	if err != nil {
+		log.WithError(err).Error("metrics: failed to get volumes")
+		return nil
+	}
+
+	for _, v := range volumes {
+		if !isVolReplicate(v.Type) || v.State != volume.VolStarted || len(v.GetLocalBricks()) == 0 {
+			continue
+		}
+
+		out, err := getHealInfo(v.Name, "info-summary")
+		if err != nil {
+			log.WithError(err).WithField("volume", v.Name).Debug("metrics: heal info failed")
+			continue
+		}
+		var info glustershdapi.HealInfo
+		if err := xml.Unmarshal([]byte(out), &info); err != nil {
+			continue
+		}
+		if info, err = filterHealInfo(info); err != nil {
+			continue
+		}
+		samples, err := getHealSamples(v.Name)
+		if err != nil {
+			log.WithError(err).WithField("volume", v.Name).Debug("metrics: failed to get heal backlog samples")
+		}
+		ms = append(ms, c.brickMetrics(v.Name, info, samples, time.Now())...)
+	}
+	return ms
+}
+
+// brickMetrics returns the heal counts, and the trend of the heal backlog
+// from the samples, of the bricks of the volume local to this node
+func (c *healCollector) brickMetrics(volname string, info glustershdapi.HealInfo, samples []healSample, now time.Time) []prometheus.Metric {
+	var ms []prometheus.Metric
+	for _, b := range info.Bricks {
+		if b.HostID != gdctx.MyUUID.String() {
+			continue
+		}
+		if len(samples) > 0 {
+			trend := healTrend(samples, b.Name, now)
+			if trend.Samples >= 2 {
+				ms = append(ms, prometheus.MustNewConstMetric(c.rate, prometheus.GaugeValue, trend.RatePerMinute, volname, b.Name))
+			}
+			if trend.State == glustershdapi.HealTrendConverging {
+				ms = append(ms, prometheus.MustNewConstMetric(c.eta, prometheus.GaugeValue, float64(trend.ETASeconds), volname, b.Name))
+			}
+		}
+		for desc, val := range map[*prometheus.Desc]*int64{
+			c.pending:    b.EntriesInHealPending,
+			c.splitBrain: b.EntriesInSplitBrain,
+			c.healing:    b.EntriesPossiblyHealing,
+		} {
+			// Counts are -1 if the brick is not reachable
+			if val == nil || *val < 0 {
+				continue
+			}
+			ms = append(ms, prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, float64(*val), volname, b.Name))
+		}
+	}
+	return ms
+}
+
+func init() {
+	metrics.Register(newHealCollector())
+}
@@ -0,0 +1,63 @@
+package glustershd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/pkg/testutils"
+	glustershdapi "github.com/gluster/glusterd2/plugins/glustershd/api"
+
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealCollector(t *testing.T) {
+	defer testutils.Patch(&gdctx.MyUUID, uuid.NewRandom()).Restore()
+
+	count := func(n int64) *int64 { return &n }
+	info := glustershdapi.HealInfo{Bricks: []glustershdapi.BrickHealInfo{
+		{
+			HostID:                 gdctx.MyUUID.String(),
+			Name:                   "host1:/bricks/b1",
+			EntriesInHealPending:   count(4),
+			EntriesInSplitBrain:    count(1),
+			EntriesPossiblyHealing: count(0),
+		},
+		// The counts of an unreachable brick are -1
+		{
+			HostID:               gdctx.MyUUID.String(),
+			Name:                 "host1:/bricks/b2",
+			EntriesInHealPending: count(-1),
+		},
+		// The bricks of other nodes are reported by them
+		{
+			HostID:               uuid.NewRandom().String(),
+			Name:                 "host2:/bricks/b1",
+			EntriesInHealPending: count(7),
+		},
+	}}
+
+	// One entry of b1 is healed every 5 minutes
+	now := time.Now()
+	samples := []healSample{
+		{At: now.Add(-10 * time.Minute), Backlog: map[string]int64{"host1:/bricks/b1": 6}},
+		{At: now.Add(-5 * time.Minute), Backlog: map[string]int64{"host1:/bricks/b1": 5}},
+		{At: now, Backlog: map[string]int64{"host1:/bricks/b1": 4}},
+	}
+
+	c := newHealCollector()
+	c.cache = c.brickMetrics("gv0", info, samples, now)
+	// Heal info is gathered again only once the cache is stale
+	c.updated = now
+
+	values, err := testutils.CollectMetrics(c, nil)
+	require.NoError(t, err)
+	require.Len(t, values, 5)
+	assert.Equal(t, 4.0, values[`glusterd2_heal_pending_entries{brick="host1:/bricks/b1",volume="gv0"}`])
+	assert.Equal(t, 1.0, values[`glusterd2_heal_split_brain_entries{brick="host1:/bricks/b1",volume="gv0"}`])
+	assert.Equal(t, 0.0, values[`glusterd2_heal_possibly_healing_entries{brick="host1:/bricks/b1",volume="gv0"}`])
+	assert.InDelta(t, -0.2, values[`glusterd2_heal_backlog_rate_per_minute{brick="host1:/bricks/b1",volume="gv0"}`], 0.001)
+	assert.InDelta(t, 1200, values[`glusterd2_heal_eta_seconds{brick="host1:/bricks/b1",volume="gv0"}`], 1)
+}
@@ -9,6 +9,7 @@ import (
 	"path"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
 	"github.com/gluster/glusterd2/glusterd2/peer"
@@ -191,6 +192,17 @@ func selfhealInfoHandler(w http.ResponseWriter, r *http.Request) {
 		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
 		return
 	}
+
+	// The trend of the backlog of the bricks is added when their backlog
+	// was sampled
+	if samples, err := getHealSamples(volname); err != nil {
+		logger.WithError(err).WithField("volname", volname).Warn("failed to get the heal backlog samples")
+	} else if len(samples) > 0 {
+		now := time.Now()
+		for i := range info.Bricks {
+			info.Bricks[i].Trend = healTrend(samples, info.Bricks[i].Name, now)
+		}
+	}
 	restutils.SendHTTPResponse(ctx, w, http.StatusOK, &info.Bricks)
 
 }
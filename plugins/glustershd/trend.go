@@ -0,0 +1,253 @@
+package glustershd
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/glusterd2/transactionv2"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	glustershdapi "github.com/gluster/glusterd2/plugins/glustershd/api"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// healTrendPrefix is where the samples of the heal backlog of the
+	// bricks are saved in the store, by volume
+	healTrendPrefix = "healtrend/"
+
+	// healSampleInterval is the interval at which the heal backlog of the
+	// bricks is sampled
+	healSampleInterval = 5 * time.Minute
+
+	// healSamplesKept is the number of samples kept for each volume
+	healSamplesKept = 48
+
+	// healTrendWindow is the time over which the trend of the backlog is
+	// computed, from the samples taken within it
+	healTrendWindow = time.Hour
+)
+
+// healSample is the heal backlog of the bricks of a volume at a time, by
+// brick name. The bricks which could not be reached are left out.
+type healSample struct {
+	At      time.Time        `json:"at"`
+	Backlog map[string]int64 `json:"backlog"`
+}
+
+var (
+	trendMu       sync.Mutex
+	trendStopChan chan struct{}
+)
+
+// startHealTrend starts sampling the heal backlog of the bricks periodically
+func startHealTrend() {
+	trendMu.Lock()
+	defer trendMu.Unlock()
+	if trendStopChan != nil {
+		return
+	}
+	trendStopChan = make(chan struct{})
+	go transaction.UntilStop(sampleHealBacklog, healSampleInterval, trendStopChan)
+}
+
+// stopHealTrend stops sampling the heal backlog
+func stopHealTrend() {
+	trendMu.Lock()
+	defer trendMu.Unlock()
+	if trendStopChan == nil {
+		return
+	}
+	close(trendStopChan)
+	trendStopChan = nil
+}
+
+// isSampler returns true if this node is the first online node among the
+// nodes hosting the bricks of the volume, so that the heal info of a volume
+// is gathered by one node only
+func isSampler(v *volume.Volinfo) bool {
+	for _, node := range v.Nodes() {
+		if uuid.Equal(node, gdctx.MyUUID) {
+			return true
+		}
+		if _, alive := store.Store.IsNodeAlive(node); alive {
+			return false
+		}
+	}
+	return false
+}
+
+// brickBacklog returns the number of entries the brick has to heal, false if
+// the brick could not be reached
+func brickBacklog(b glustershdapi.BrickHealInfo) (int64, bool) {
+	val := b.TotalEntries
+	if val == nil {
+		val = b.EntriesInHealPending
+	}
+	if val == nil || *val < 0 {
+		return 0, false
+	}
+	return *val, true
+}
+
+func sampleHealBacklog() {
+	volumes, err := volume.GetVolumes(context.TODO())
+	if err != nil {
+		log.WithError(err).Error("heal trend: failed to get volumes")
+		return
+	}
+
+	existing := make(map[string]bool, len(volumes))
+	for _, v := range volumes {
+		existing[v.Name] = true
+		if !isVolReplicate(v.Type) || v.State != volume.VolStarted || !isSampler(v) {
+			continue
+		}
+
+		out, err := getHealInfo(v.Name, "info-summary")
+		if err != nil {
+			log.WithError(err).WithField("volume", v.Name).Debug("heal trend: heal info failed")
+			continue
+		}
+		var info glustershdapi.HealInfo
+		if err := xml.Unmarshal([]byte(out), &info); err != nil {
+			continue
+		}
+		if info, err = filterHealInfo(info); err != nil {
+			continue
+		}
+
+		sample := healSample{At: time.Now(), Backlog: make(map[string]int64)}
+		for _, b := range info.Bricks {
+			if backlog, ok := brickBacklog(b); ok {
+				sample.Backlog[b.Name] = backlog
+			}
+		}
+		if err := addHealSample(v.Name, sample); err != nil {
+			log.WithError(err).WithField("volume", v.Name).Error("heal trend: failed to save heal backlog")
+		}
+	}
+
+	// The samples of the deleted volumes are removed
+	resp, err := store.Get(context.TODO(), healTrendPrefix, clientv3.WithPrefix(), clientv3.WithKeysOnly())
+	if err != nil {
+		return
+	}
+	for _, kv := range resp.Kvs {
+		volname := strings.TrimPrefix(string(kv.Key), healTrendPrefix)
+		if existing[volname] {
+			continue
+		}
+		if _, err := store.Delete(context.TODO(), string(kv.Key)); err != nil {
+			log.WithError(err).WithField("volume", volname).Debug("heal trend: failed to delete heal backlog")
+		}
+	}
+}
+
+// getHealSamples returns the samples of the heal backlog of the volume, the
+// oldest first
+func getHealSamples(volname string) ([]healSample, error) {
+	resp, err := store.Get(context.TODO(), healTrendPrefix+volname)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Count != 1 {
+		return nil, nil
+	}
+	var samples []healSample
+	if err := json.Unmarshal(resp.Kvs[0].Value, &samples); err != nil {
+		return nil, err
+	}
+	return samples, nil
+}
+
+// addHealSample saves the sample of the heal backlog of the volume, along
+// with the last healSamplesKept samples
+func addHealSample(volname string, sample healSample) error {
+	samples, err := getHealSamples(volname)
+	if err != nil {
+		return err
+	}
+	samples = append(samples, sample)
+	if len(samples) > healSamplesKept {
+		samples = samples[len(samples)-healSamplesKept:]
+	}
+
+	data, err := json.Marshal(samples)
+	if err != nil {
+		return err
+	}
+	_, err = store.Put(context.TODO(), healTrendPrefix+volname, string(data))
+	return err
+}
+
+// healTrend returns the trend of the heal backlog of the brick from the
+// samples taken within healTrendWindow before now. The rate is the slope of
+// the least squares line through the samples.
+func healTrend(samples []healSample, brick string, now time.Time) *glustershdapi.HealTrend {
+	var ts, backlogs []float64
+	var last healSample
+	for _, s := range samples {
+		if now.Sub(s.At) > healTrendWindow {
+			continue
+		}
+		backlog, ok := s.Backlog[brick]
+		if !ok {
+			continue
+		}
+		ts = append(ts, s.At.Sub(now).Seconds())
+		backlogs = append(backlogs, float64(backlog))
+		last = s
+	}
+
+	trend := &glustershdapi.HealTrend{State: glustershdapi.HealTrendUnknown, Samples: len(ts)}
+	if len(ts) == 0 {
+		return trend
+	}
+	trend.Backlog = last.Backlog[brick]
+	if trend.Backlog == 0 {
+		trend.State = glustershdapi.HealTrendHealed
+		return trend
+	}
+	if len(ts) < 2 {
+		return trend
+	}
+
+	n := float64(len(ts))
+	var sumT, sumB, sumTT, sumTB float64
+	for i := range ts {
+		sumT += ts[i]
+		sumB += backlogs[i]
+		sumTT += ts[i] * ts[i]
+		sumTB += ts[i] * backlogs[i]
+	}
+	den := n*sumTT - sumT*sumT
+	if den == 0 {
+		return trend
+	}
+	slope := (n*sumTB - sumT*sumB) / den // entries per second
+	trend.RatePerMinute = slope * 60
+
+	switch {
+	case slope < 0:
+		trend.State = glustershdapi.HealTrendConverging
+		at := last.At.Add(time.Duration(float64(trend.Backlog) / -slope * float64(time.Second)))
+		trend.ETA = &at
+		if at.After(now) {
+			trend.ETASeconds = int64(at.Sub(now) / time.Second)
+		}
+	case slope > 0:
+		trend.State = glustershdapi.HealTrendGrowing
+	default:
+		trend.State = glustershdapi.HealTrendStalled
+	}
+	return trend
+}
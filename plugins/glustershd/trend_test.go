@@ -0,0 +1,56 @@
+package glustershd
+
+import (
+	"testing"
+	"time"
+
+	glustershdapi "github.com/gluster/glusterd2/plugins/glustershd/api"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealTrend(t *testing.T) {
+	now := time.Date(2018, 7, 21, 16, 0, 0, 0, time.UTC)
+	samples := func(brick string, backlogs ...int64) []healSample {
+		var s []healSample
+		for i, b := range backlogs {
+			at := now.Add(time.Duration(i-len(backlogs)+1) * healSampleInterval)
+			s = append(s, healSample{At: at, Backlog: map[string]int64{brick: b}})
+		}
+		return s
+	}
+
+	// 100 entries healed every 5 minutes, 500 left
+	trend := healTrend(samples("b1", 800, 700, 600, 500), "b1", now)
+	assert.Equal(t, glustershdapi.HealTrendConverging, trend.State)
+	assert.Equal(t, int64(500), trend.Backlog)
+	assert.Equal(t, 4, trend.Samples)
+	assert.InDelta(t, -20, trend.RatePerMinute, 0.001)
+	require.NotNil(t, trend.ETA)
+	assert.Equal(t, now.Add(25*time.Minute), *trend.ETA)
+	assert.Equal(t, int64(25*60), trend.ETASeconds)
+
+	trend = healTrend(samples("b1", 500, 600, 700), "b1", now)
+	assert.Equal(t, glustershdapi.HealTrendGrowing, trend.State)
+	assert.Nil(t, trend.ETA)
+
+	trend = healTrend(samples("b1", 500, 500, 500), "b1", now)
+	assert.Equal(t, glustershdapi.HealTrendStalled, trend.State)
+
+	trend = healTrend(samples("b1", 500, 0), "b1", now)
+	assert.Equal(t, glustershdapi.HealTrendHealed, trend.State)
+
+	// A single sample does not tell the trend
+	trend = healTrend(samples("b1", 500), "b1", now)
+	assert.Equal(t, glustershdapi.HealTrendUnknown, trend.State)
+	assert.Equal(t, int64(500), trend.Backlog)
+
+	// The samples of other bricks, and the samples older than the window,
+	// are left out
+	trend = healTrend(samples("b1", 800, 700), "b2", now)
+	assert.Equal(t, glustershdapi.HealTrendUnknown, trend.State)
+	assert.Equal(t, 0, trend.Samples)
+	trend = healTrend(samples("b1", 800, 700), "b1", now.Add(2*healTrendWindow))
+	assert.Equal(t, 0, trend.Samples)
+}
@@ -0,0 +1,39 @@
+package api
+
+import (
+	"time"
+)
+
+// LogLevelReq is the request to change the log levels of glusterd2 on all
+// the peers. Levels are one of panic, fatal, error, warning, info or debug.
+type LogLevelReq struct {
+	// Global is the log level of all the subsystems which are not set
+	// explicitly. It is left unchanged if empty.
+	Global string `json:"global,omitempty"`
+	// Subsystems maps subsystem names to their log levels. A subsystem
+	// whose level is empty is logged at the global log level.
+	Subsystems map[string]string `json:"subsystems,omitempty"`
+}
+
+// LogQuery is the query for the log entries of glusterd2 and the daemons it
+// manages. Empty fields do not filter the entries.
+type LogQuery struct {
+	// Daemon is one of glusterd2, brick, glustershd, gsyncd, rebalance or
+	// the name of the log file of any other daemon without the extension
+	Daemon string `json:"daemon,omitempty"`
+	// Volume restricts the entries to the logs of the bricks and daemons
+	// of the volume, and to the entries of other logs mentioning it
+	Volume string `json:"volume,omitempty"`
+	// Brick is the path of the brick whose log is to be queried
+	Brick string `json:"brick,omitempty"`
+	// Since drops the entries logged before it
+	Since time.Time `json:"since,omitempty"`
+	// Level drops the entries less severe than it
+	Level string `json:"level,omitempty"`
+	// Pattern is a regular expression which the entries must match
+	Pattern string `json:"pattern,omitempty"`
+	// Limit is the maximum number of most recent entries returned
+	Limit int `json:"limit,omitempty"`
+	// Peers queries the logs on all the peers instead of the local node
+	Peers bool `json:"peers,omitempty"`
+}
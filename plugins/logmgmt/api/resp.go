@@ -0,0 +1,35 @@
+package api
+
+import (
+	"time"
+
+	"github.com/pborman/uuid"
+)
+
+// LogLevelResp is the response containing the log levels of glusterd2
+type LogLevelResp struct {
+	Global     string            `json:"global"`
+	Subsystems map[string]string `json:"subsystems"`
+	// Available is the list of subsystems whose log level can be set
+	Available []string `json:"available-subsystems"`
+}
+
+// LogEntry is an entry in the log of glusterd2 or a daemon managed by it
+type LogEntry struct {
+	PeerID   uuid.UUID `json:"peer-id"`
+	Hostname string    `json:"hostname"`
+	Daemon   string    `json:"daemon"`
+	File     string    `json:"file"`
+	Time     time.Time `json:"time"`
+	Level    string    `json:"level"`
+	Message  string    `json:"message"`
+}
+
+// LogsResp is the response containing the log entries matching a query,
+// oldest first
+type LogsResp struct {
+	Entries []LogEntry `json:"entries"`
+	// Truncated is set if the query limit was reached, in which case older
+	// entries matching the query may have been left out
+	Truncated bool `json:"truncated"`
+}
@@ -0,0 +1,58 @@
+package logmgmt
+
+import (
+	"github.com/gluster/glusterd2/glusterd2/servers/rest/route"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/pkg/utils"
+	logmgmtapi "github.com/gluster/glusterd2/plugins/logmgmt/api"
+)
+
+// Plugin is a structure which implements GlusterdPlugin interface
+type Plugin struct {
+}
+
+// Name returns name of plugin
+func (p *Plugin) Name() string {
+	return "logmgmt"
+}
+
+// RestRoutes returns a list of REST API routes to register with Glusterd
+func (p *Plugin) RestRoutes() route.Routes {
+	return route.Routes{
+		route.Route{
+			Name:         "LogLevelGet",
+			Method:       "GET",
+			Pattern:      "/logging",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*logmgmtapi.LogLevelResp)(nil)),
+			HandlerFunc:  logLevelGetHandler},
+		route.Route{
+			Name:         "LogLevelSet",
+			Method:       "PUT",
+			Pattern:      "/logging",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*logmgmtapi.LogLevelReq)(nil)),
+			ResponseType: utils.GetTypeString((*logmgmtapi.LogLevelResp)(nil)),
+			HandlerFunc:  logLevelSetHandler},
+		route.Route{
+			Name:         "LogsGet",
+			Method:       "GET",
+			Pattern:      "/logs",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*logmgmtapi.LogsResp)(nil)),
+			HandlerFunc:  logsGetHandler},
+		route.Route{
+			Name:        "LogRotate",
+			Method:      "POST",
+			Pattern:     "/logging/rotate",
+			Version:     1,
+			HandlerFunc: logRotateHandler},
+	}
+}
+
+// RegisterStepFuncs registers transaction step functions with Glusterd transaction framework
+func (p *Plugin) RegisterStepFuncs() {
+	transaction.RegisterStepFunc(txnSetLogLevels, "log-mgmt.SetLogLevels")
+	transaction.RegisterStepFunc(txnRotateLogs, "log-mgmt.RotateLogs")
+	transaction.RegisterStepFunc(txnQueryLogs, "log-mgmt.QueryLogs")
+}
@@ -0,0 +1,331 @@
+package logmgmt
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/logging"
+	logmgmtapi "github.com/gluster/glusterd2/plugins/logmgmt/api"
+
+	log "github.com/sirupsen/logrus"
+	config "github.com/spf13/viper"
+)
+
+const (
+	defaultLogQueryLimit = 100
+	maxLogQueryLimit     = 1000
+
+	// logs are read backwards in chunks of this size
+	logChunkSize = 64 * 1024
+
+	// timestamp format of glusterd2 and glusterfs log entries, in UTC
+	logTimestampFormat = "2006-01-02 15:04:05.000000"
+)
+
+var (
+	// time="2018-09-10 10:20:30.123456" level=info msg="..." key=value
+	gd2LogLine = regexp.MustCompile(`^time="([^"]+)" level=(\w+) (.*)$`)
+	// [2018-09-10 10:20:30.123456] I [MSGID: 106487] [file.c:12:fn] 0-xl: msg
+	glusterfsLogLine = regexp.MustCompile(`^\[(\d{4}-\d\d-\d\d \d\d:\d\d:\d\d\.\d+)\] ([TDINWECA]) (.*)$`)
+
+	// glusterfsLevels maps the log levels of glusterfs to those of glusterd2
+	glusterfsLevels = map[string]log.Level{
+		"T": log.DebugLevel,
+		"D": log.DebugLevel,
+		"I": log.InfoLevel,
+		"N": log.InfoLevel,
+		"W": log.WarnLevel,
+		"E": log.ErrorLevel,
+		"C": log.FatalLevel,
+		"A": log.FatalLevel,
+	}
+)
+
+// logFile is a log of glusterd2 or a daemon managed by it
+type logFile struct {
+	path   string
+	daemon string
+	// volume is set if the log belongs to a daemon of a single volume
+	volume string
+}
+
+// logFilter is the parsed form of a LogQuery
+type logFilter struct {
+	logmgmtapi.LogQuery
+	level   *log.Level
+	pattern *regexp.Regexp
+	// volume matches the entries mentioning the volume of the query
+	volume *regexp.Regexp
+}
+
+// volumePattern returns a regular expression matching the mentions of the
+// volume, either as a field, like volume=gv0, or as the volume part of the
+// name of a glusterfs translator, like 0-gv0-client-1. The names of other
+// volumes starting with the name, like gv01, are not matched.
+func volumePattern(name string) *regexp.Regexp {
+	return regexp.MustCompile(`(^|[^\w-])(\d+-)?` + regexp.QuoteMeta(name) + `(-[a-z-]+(-\d+)?)?($|[^\w-])`)
+}
+
+func newLogFilter(q logmgmtapi.LogQuery) (*logFilter, error) {
+	f := &logFilter{LogQuery: q}
+	if q.Level != "" {
+		l, err := log.ParseLevel(strings.ToLower(q.Level))
+		if err != nil {
+			return nil, err
+		}
+		f.level = &l
+	}
+	if q.Pattern != "" {
+		re, err := regexp.Compile(q.Pattern)
+		if err != nil {
+			return nil, err
+		}
+		f.pattern = re
+	}
+	if q.Volume != "" {
+		f.volume = volumePattern(q.Volume)
+	}
+	if f.Limit <= 0 {
+		f.Limit = defaultLogQueryLimit
+	} else if f.Limit > maxLogQueryLimit {
+		f.Limit = maxLogQueryLimit
+	}
+	return f, nil
+}
+
+// brickPathOf returns the path of a brick given either as path or host:path
+func brickPathOf(b string) string {
+	if !strings.HasPrefix(b, "/") {
+		if i := strings.Index(b, ":"); i >= 0 {
+			return b[i+1:]
+		}
+	}
+	return b
+}
+
+// selectFile returns true if the entries of the log can match the filter
+func (f *logFilter) selectFile(lf logFile) bool {
+	if f.Brick != "" {
+		return lf.path == brick.LogFile(brickPathOf(f.Brick))
+	}
+	if f.Daemon != "" && f.Daemon != lf.daemon {
+		return false
+	}
+	return f.Volume == "" || lf.volume == "" || f.Volume == lf.volume
+}
+
+// match returns true if the entry of the log matches the filter
+func (f *logFilter) match(lf logFile, e *logmgmtapi.LogEntry, level *log.Level) bool {
+	if f.level != nil && (level == nil || *level > *f.level) {
+		return false
+	}
+	// entries of logs not belonging to a single volume have to mention the
+	// volume
+	if f.volume != nil && lf.volume == "" && !f.volume.MatchString(e.Message) {
+		return false
+	}
+	return f.pattern == nil || f.pattern.MatchString(e.Message)
+}
+
+// localLogFiles returns the logs of glusterd2 and the daemons on this node
+func localLogFiles() []logFile {
+	var files []logFile
+	if p := logging.LogFile(); p != "" {
+		files = append(files, logFile{path: p, daemon: "glusterd2"})
+	}
+
+	brickVolumes := make(map[string]string)
+	if volumes, err := volume.GetVolumes(context.TODO()); err == nil {
+		for _, v := range volumes {
+			for _, b := range v.GetLocalBricks() {
+				brickVolumes[brick.LogFile(b.Path)] = v.Name
+			}
+		}
+	} else {
+		log.WithError(err).Error("failed to get volumes")
+	}
+
+	dir := filepath.Join(config.GetString("logdir"), "glusterfs")
+	filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil || !info.Mode().IsRegular() || !strings.HasSuffix(p, ".log") {
+			return nil
+		}
+		rel, _ := filepath.Rel(dir, p)
+		name := strings.TrimSuffix(filepath.Base(p), ".log")
+		switch parts := strings.Split(rel, string(filepath.Separator)); {
+		case parts[0] == "bricks":
+			files = append(files, logFile{path: p, daemon: "brick", volume: brickVolumes[p]})
+		case parts[0] == "geo-replication" && len(parts) > 2:
+			// session directories are named mastervol_remotehost_remotevol
+			files = append(files, logFile{path: p, daemon: "gsyncd", volume: strings.SplitN(parts[1], "_", 2)[0]})
+		case len(parts) == 1 && strings.HasSuffix(name, "-rebalance"):
+			files = append(files, logFile{path: p, daemon: "rebalance", volume: strings.TrimSuffix(name, "-rebalance")})
+		case len(parts) == 1:
+			files = append(files, logFile{path: p, daemon: name})
+		}
+		return nil
+	})
+	return files
+}
+
+// parseLogLine parses the header line of a glusterd2 or glusterfs log entry.
+// It returns false for the lines which continue the previous entry.
+func parseLogLine(line string) (*logmgmtapi.LogEntry, *log.Level, bool) {
+	var (
+		ts, msg string
+		level   *log.Level
+	)
+	if m := gd2LogLine.FindStringSubmatch(line); m != nil {
+		ts, msg = m[1], m[3]
+		if l, err := log.ParseLevel(m[2]); err == nil {
+			level = &l
+		}
+	} else if m := glusterfsLogLine.FindStringSubmatch(line); m != nil {
+		ts, msg = m[1], m[3]
+		if l, ok := glusterfsLevels[m[2]]; ok {
+			level = &l
+		}
+	} else {
+		return nil, nil, false
+	}
+
+	t, err := time.ParseInLocation(logTimestampFormat, ts, time.UTC)
+	if err != nil {
+		return nil, nil, false
+	}
+	e := &logmgmtapi.LogEntry{Time: t, Message: msg}
+	if level != nil {
+		e.Level = level.String()
+	}
+	return e, level, true
+}
+
+// scanBackwards calls fn with the lines of the file from the last to the
+// first, until fn returns false
+func scanBackwards(f *os.File, fn func(line string) bool) error {
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	var (
+		offset  = info.Size()
+		partial []byte
+		buf     = make([]byte, logChunkSize)
+	)
+	for offset > 0 {
+		n := int64(logChunkSize)
+		if offset < n {
+			n = offset
+		}
+		offset -= n
+		if _, err := f.ReadAt(buf[:n], offset); err != nil && err != io.EOF {
+			return err
+		}
+
+		lines := bytes.Split(append(buf[:n:n], partial...), []byte("\n"))
+		// the first line may continue in the previous chunk
+		partial = append([]byte(nil), lines[0]...)
+		for i := len(lines) - 1; i > 0; i-- {
+			if len(lines[i]) == 0 {
+				continue
+			}
+			if !fn(string(lines[i])) {
+				return nil
+			}
+		}
+	}
+	if len(partial) > 0 {
+		fn(string(partial))
+	}
+	return nil
+}
+
+// queryLogFile returns up to Limit most recent entries of the log matching
+// the filter, oldest first
+func queryLogFile(lf logFile, f *logFilter) ([]logmgmtapi.LogEntry, error) {
+	file, err := os.Open(lf.path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var (
+		entries      []logmgmtapi.LogEntry
+		continuation []string
+	)
+	err = scanBackwards(file, func(line string) bool {
+		e, level, ok := parseLogLine(line)
+		if !ok {
+			continuation = append(continuation, line)
+			return true
+		}
+		// continuation lines were read last line first
+		for i := len(continuation) - 1; i >= 0; i-- {
+			e.Message += "\n" + continuation[i]
+		}
+		continuation = nil
+
+		if !f.Since.IsZero() && e.Time.Before(f.Since) {
+			return false
+		}
+		if !f.match(lf, e, level) {
+			return true
+		}
+		e.PeerID = gdctx.MyUUID
+		e.Hostname = gdctx.HostName
+		e.Daemon = lf.daemon
+		e.File = lf.path
+		entries = append(entries, *e)
+		return len(entries) < f.Limit
+	})
+
+	for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+		entries[i], entries[j] = entries[j], entries[i]
+	}
+	return entries, err
+}
+
+// mergeLogEntries sorts the entries by time and returns the most recent
+// limit of them, and whether any were dropped
+func mergeLogEntries(entries []logmgmtapi.LogEntry, limit int) ([]logmgmtapi.LogEntry, bool) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Time.Before(entries[j].Time)
+	})
+	if len(entries) > limit {
+		return entries[len(entries)-limit:], true
+	}
+	return entries, false
+}
+
+// queryLocalLogs returns the entries of the logs on this node matching the
+// filter, oldest first, and whether the limit was reached
+func queryLocalLogs(f *logFilter) ([]logmgmtapi.LogEntry, bool) {
+	var (
+		entries []logmgmtapi.LogEntry
+		limited bool
+	)
+	for _, lf := range localLogFiles() {
+		if !f.selectFile(lf) {
+			continue
+		}
+		fileEntries, err := queryLogFile(lf, f)
+		if err != nil {
+			log.WithError(err).WithField("log", lf.path).Error("failed to query log")
+		}
+		limited = limited || len(fileEntries) >= f.Limit
+		entries = append(entries, fileEntries...)
+	}
+	entries, truncated := mergeLogEntries(entries, f.Limit)
+	return entries, limited || truncated
+}
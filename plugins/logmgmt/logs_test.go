@@ -0,0 +1,242 @@
+package logmgmt
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	logmgmtapi "github.com/gluster/glusterd2/plugins/logmgmt/api"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func tempLog(t *testing.T, content string) string {
+	f, err := ioutil.TempFile("", "logmgmt")
+	require.NoError(t, err)
+	defer f.Close()
+	_, err = f.WriteString(content)
+	require.NoError(t, err)
+	return f.Name()
+}
+
+func TestParseLogLine(t *testing.T) {
+	at := time.Date(2018, 9, 10, 10, 20, 30, 123456000, time.UTC)
+	tests := []struct {
+		line    string
+		ok      bool
+		level   string
+		message string
+	}{
+		{`time="2018-09-10 10:20:30.123456" level=warning msg="brick down" volume=gv0`, true, "warning", `msg="brick down" volume=gv0`},
+		{`[2018-09-10 10:20:30.123456] E [MSGID: 114058] [client-handshake.c:1447:fn] 0-gv0-client-1: failed`, true, "error", `[MSGID: 114058] [client-handshake.c:1447:fn] 0-gv0-client-1: failed`},
+		{`[2018-09-10 10:20:30.123456] N [fuse-bridge.c:10:fn] 0-fuse: mounted`, true, "info", `[fuse-bridge.c:10:fn] 0-fuse: mounted`},
+		// continuation of the previous entry
+		{`  goroutine 1 [running]:`, false, "", ""},
+		{`time="yesterday" level=info msg="x"`, false, "", ""},
+		{``, false, "", ""},
+	}
+	for _, tt := range tests {
+		e, level, ok := parseLogLine(tt.line)
+		require.Equal(t, tt.ok, ok, tt.line)
+		if !ok {
+			continue
+		}
+		assert.Equal(t, at, e.Time, tt.line)
+		assert.Equal(t, tt.level, e.Level, tt.line)
+		require.NotNil(t, level, tt.line)
+		assert.Equal(t, tt.level, level.String(), tt.line)
+		assert.Equal(t, tt.message, e.Message, tt.line)
+	}
+}
+
+func TestScanBackwards(t *testing.T) {
+	// Enough lines for the file to be read in several chunks
+	var long []string
+	for i := 0; i < 3*logChunkSize/40; i++ {
+		long = append(long, fmt.Sprintf("line %d %s", i, strings.Repeat("x", i%50)))
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		lines   []string
+	}{
+		{"empty", "", nil},
+		{"no newline at end", "a\nb", []string{"a", "b"}},
+		{"empty lines", "a\n\nb\n", []string{"a", "b"}},
+		{"chunks", strings.Join(long, "\n") + "\n", long},
+	}
+	for _, tt := range tests {
+		path := tempLog(t, tt.content)
+		defer os.Remove(path)
+		f, err := os.Open(path)
+		require.NoError(t, err)
+		defer f.Close()
+
+		var lines []string
+		require.NoError(t, scanBackwards(f, func(line string) bool {
+			lines = append([]string{line}, lines...)
+			return true
+		}), tt.name)
+		assert.Equal(t, tt.lines, lines, tt.name)
+	}
+
+	// Scanning stops once the function returns false
+	path := tempLog(t, "a\nb\nc\n")
+	defer os.Remove(path)
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	var lines []string
+	require.NoError(t, scanBackwards(f, func(line string) bool {
+		lines = append(lines, line)
+		return len(lines) < 2
+	}))
+	assert.Equal(t, []string{"c", "b"}, lines)
+}
+
+func TestQueryLogFile(t *testing.T) {
+	path := tempLog(t, `time="2018-09-10 10:00:00.000000" level=info msg="volume started" volume=gv0
+time="2018-09-10 10:01:00.000000" level=error msg="volume start failed" volume=gv01
+[2018-09-10 10:02:00.000000] W [MSGID: 1] [afr.c:1:fn] 0-gv0-replicate-0: subvolume down
+time="2018-09-10 10:03:00.000000" level=error msg="step failed" volume=gv0
+panic: step failed
+  goroutine 1
+time="2018-09-10 10:04:00.000000" level=debug msg="peer connected"
+`)
+	defer os.Remove(path)
+	lf := logFile{path: path, daemon: "glusterd2"}
+
+	tests := []struct {
+		name     string
+		query    logmgmtapi.LogQuery
+		messages []string
+	}{
+		{
+			name:  "limit",
+			query: logmgmtapi.LogQuery{Limit: 2},
+			messages: []string{
+				"msg=\"step failed\" volume=gv0\npanic: step failed\n  goroutine 1",
+				`msg="peer connected"`,
+			},
+		},
+		{
+			name:  "level",
+			query: logmgmtapi.LogQuery{Level: "warning"},
+			messages: []string{
+				`msg="volume start failed" volume=gv01`,
+				`[MSGID: 1] [afr.c:1:fn] 0-gv0-replicate-0: subvolume down`,
+				"msg=\"step failed\" volume=gv0\npanic: step failed\n  goroutine 1",
+			},
+		},
+		{
+			name:  "since",
+			query: logmgmtapi.LogQuery{Since: time.Date(2018, 9, 10, 10, 3, 0, 0, time.UTC), Level: "info"},
+			messages: []string{
+				"msg=\"step failed\" volume=gv0\npanic: step failed\n  goroutine 1",
+			},
+		},
+		{
+			// gv01 is another volume
+			name:  "volume",
+			query: logmgmtapi.LogQuery{Volume: "gv0"},
+			messages: []string{
+				`msg="volume started" volume=gv0`,
+				`[MSGID: 1] [afr.c:1:fn] 0-gv0-replicate-0: subvolume down`,
+				"msg=\"step failed\" volume=gv0\npanic: step failed\n  goroutine 1",
+			},
+		},
+		{
+			name:     "pattern",
+			query:    logmgmtapi.LogQuery{Pattern: "goroutine \\d"},
+			messages: []string{"msg=\"step failed\" volume=gv0\npanic: step failed\n  goroutine 1"},
+		},
+	}
+	for _, tt := range tests {
+		f, err := newLogFilter(tt.query)
+		require.NoError(t, err, tt.name)
+		entries, err := queryLogFile(lf, f)
+		require.NoError(t, err, tt.name)
+
+		var messages []string
+		for _, e := range entries {
+			assert.Equal(t, "glusterd2", e.Daemon, tt.name)
+			assert.Equal(t, path, e.File, tt.name)
+			messages = append(messages, e.Message)
+		}
+		assert.Equal(t, tt.messages, messages, tt.name)
+	}
+
+	// The entries of the logs of a volume need not mention it
+	f, err := newLogFilter(logmgmtapi.LogQuery{Volume: "gv2", Level: "error"})
+	require.NoError(t, err)
+	entries, err := queryLogFile(logFile{path: path, daemon: "brick", volume: "gv2"}, f)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+}
+
+func TestVolumePattern(t *testing.T) {
+	tests := []struct {
+		message string
+		match   bool
+	}{
+		{"volume=gv0", true},
+		{`msg="deleted gv0"`, true},
+		{"0-gv0-client-1: connected", true},
+		{"0-gv0-dht: layout fixed", true},
+		{"volume=gv01", false},
+		{"volume=agv0", false},
+		{"0-gv01-client-1: connected", false},
+		{"0-gv0-1-client-1: connected", false},
+		{"volume=gv0_old", false},
+	}
+	re := volumePattern("gv0")
+	for _, tt := range tests {
+		assert.Equal(t, tt.match, re.MatchString(tt.message), tt.message)
+	}
+}
+
+func TestMergeLogEntries(t *testing.T) {
+	at := func(m int) logmgmtapi.LogEntry {
+		return logmgmtapi.LogEntry{Time: time.Date(2018, 9, 10, 10, m, 0, 0, time.UTC), Message: fmt.Sprint(m)}
+	}
+	tests := []struct {
+		name      string
+		entries   []logmgmtapi.LogEntry
+		limit     int
+		merged    []logmgmtapi.LogEntry
+		truncated bool
+	}{
+		{"empty", nil, 10, nil, false},
+		{"sorted by time", []logmgmtapi.LogEntry{at(3), at(1), at(2)}, 10, []logmgmtapi.LogEntry{at(1), at(2), at(3)}, false},
+		{"most recent kept", []logmgmtapi.LogEntry{at(3), at(1), at(4), at(2)}, 2, []logmgmtapi.LogEntry{at(3), at(4)}, true},
+		{"limit reached", []logmgmtapi.LogEntry{at(2), at(1)}, 2, []logmgmtapi.LogEntry{at(1), at(2)}, false},
+	}
+	for _, tt := range tests {
+		merged, truncated := mergeLogEntries(tt.entries, tt.limit)
+		assert.Equal(t, tt.merged, merged, tt.name)
+		assert.Equal(t, tt.truncated, truncated, tt.name)
+	}
+}
+
+func TestNewLogFilter(t *testing.T) {
+	f, err := newLogFilter(logmgmtapi.LogQuery{})
+	require.NoError(t, err)
+	assert.Equal(t, defaultLogQueryLimit, f.Limit)
+	assert.Nil(t, f.level)
+
+	f, err = newLogFilter(logmgmtapi.LogQuery{Level: "WARNING", Limit: 5000})
+	require.NoError(t, err)
+	assert.Equal(t, maxLogQueryLimit, f.Limit)
+	assert.Equal(t, log.WarnLevel, *f.level)
+
+	_, err = newLogFilter(logmgmtapi.LogQuery{Level: "loud"})
+	assert.NotNil(t, err)
+	_, err = newLogFilter(logmgmtapi.LogQuery{Pattern: "("})
+	assert.NotNil(t, err)
+}
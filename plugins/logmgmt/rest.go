@@ -0,0 +1,226 @@
+package logmgmt
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	transactionv2 "github.com/gluster/glusterd2/glusterd2/transactionv2"
+	"github.com/gluster/glusterd2/pkg/errors"
+	"github.com/gluster/glusterd2/pkg/logging"
+	logmgmtapi "github.com/gluster/glusterd2/plugins/logmgmt/api"
+)
+
+func createLogLevelResp() *logmgmtapi.LogLevelResp {
+	global, subsystems := logging.Levels()
+	return &logmgmtapi.LogLevelResp{
+		Global:     global,
+		Subsystems: subsystems,
+		Available:  logging.Subsystems(),
+	}
+}
+
+func logLevelGetHandler(w http.ResponseWriter, r *http.Request) {
+	restutils.SendHTTPResponse(r.Context(), w, http.StatusOK, createLogLevelResp())
+}
+
+func logLevelSetHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+
+	var req logmgmtapi.LogLevelReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		logger.WithError(err).Error("Failed to unmarshal LogLevelReq")
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrJSONParsingFailed)
+		return
+	}
+
+	if err := logging.ValidateLevels(req.Global, req.Subsystems); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	txn, err := transactionv2.NewTxnWithLocks(ctx, gdctx.MyClusterID.String())
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	defer txn.Done()
+
+	if err := txn.Ctx.Set("req", &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	nodes, err := peer.GetPeerIDs()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc: "log-mgmt.SetLogLevels",
+			Nodes:  nodes,
+			Sync:   true,
+		},
+	}
+
+	if err = txn.Do(); err != nil {
+		logger.WithError(err).Error("Failed to set log levels")
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, createLogLevelResp())
+}
+
+func logRotateHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+
+	txn, err := transactionv2.NewTxnWithLocks(ctx, gdctx.MyClusterID.String())
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	defer txn.Done()
+
+	nodes, err := peer.GetPeerIDs()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc: "log-mgmt.RotateLogs",
+			Nodes:  nodes,
+			Sync:   true,
+		},
+	}
+
+	if err = txn.Do(); err != nil {
+		logger.WithError(err).Error("Failed to rotate logs")
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusNoContent, nil)
+}
+
+// parseLogQuery parses the query parameters of a request for log entries
+func parseLogQuery(r *http.Request) (logmgmtapi.LogQuery, error) {
+	params := r.URL.Query()
+	q := logmgmtapi.LogQuery{
+		Daemon:  params.Get("daemon"),
+		Volume:  params.Get("volume"),
+		Brick:   params.Get("brick"),
+		Level:   params.Get("level"),
+		Pattern: params.Get("grep"),
+	}
+
+	// since is either a timestamp or a duration relative to now, resolved
+	// here so that all the peers use the same instant
+	if since := params.Get("since"); since != "" {
+		if d, err := time.ParseDuration(since); err == nil {
+			q.Since = time.Now().Add(-d)
+		} else if t, err := time.Parse(time.RFC3339, since); err == nil {
+			q.Since = t
+		} else {
+			return q, fmt.Errorf("invalid since %q, expected a duration or RFC3339 timestamp", since)
+		}
+	}
+
+	if limit := params.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil {
+			return q, fmt.Errorf("invalid limit %q", limit)
+		}
+		q.Limit = n
+	}
+
+	if peers := params.Get("peers"); peers != "" {
+		all, err := strconv.ParseBool(peers)
+		if err != nil {
+			return q, fmt.Errorf("invalid peers %q", peers)
+		}
+		q.Peers = all
+	}
+	return q, nil
+}
+
+func logsGetHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+
+	q, err := parseLogQuery(r)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+	filter, err := newLogFilter(q)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	var resp logmgmtapi.LogsResp
+	if !q.Peers {
+		resp.Entries, resp.Truncated = queryLocalLogs(filter)
+		restutils.SendHTTPResponse(ctx, w, http.StatusOK, &resp)
+		return
+	}
+
+	nodes, err := peer.GetPeerIDs()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	txn := transaction.NewTxn(ctx)
+	defer txn.Done()
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc: "log-mgmt.QueryLogs",
+			Nodes:  nodes,
+		},
+	}
+	if err := txn.Ctx.Set("query", &q); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	// Some nodes may not be up, which is okay.
+	txn.DontCheckAlive = true
+	txn.DisableRollback = true
+
+	if err := txn.Do(); err != nil {
+		logger.WithError(err).Error("Failed to query logs")
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	var entries []logmgmtapi.LogEntry
+	for _, node := range nodes {
+		var result logmgmtapi.LogsResp
+		if err := txn.Ctx.GetNodeResult(node, logQueryTxnKey, &result); err != nil {
+			// skip if we do not have information
+			continue
+		}
+		entries = append(entries, result.Entries...)
+		resp.Truncated = resp.Truncated || result.Truncated
+	}
+
+	var truncated bool
+	resp.Entries, truncated = mergeLogEntries(entries, filter.Limit)
+	resp.Truncated = resp.Truncated || truncated
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, &resp)
+}
@@ -0,0 +1,59 @@
+package logmgmt
+
+import (
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/logrotate"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/pkg/logging"
+	logmgmtapi "github.com/gluster/glusterd2/plugins/logmgmt/api"
+)
+
+const logQueryTxnKey = "logentries"
+
+// Transaction step that applies the log levels on the node
+func txnSetLogLevels(c transaction.TxnCtx) error {
+	var req logmgmtapi.LogLevelReq
+	if err := c.Get("req", &req); err != nil {
+		return err
+	}
+
+	if err := logging.SetLevels(req.Global, req.Subsystems); err != nil {
+		c.Logger().WithError(err).Error("failed to set log levels")
+		return err
+	}
+
+	global, subsystems := logging.Levels()
+	c.Logger().WithField("global", global).WithField("subsystems", subsystems).Info("log levels changed")
+	return nil
+}
+
+// Transaction step that rotates the logs of glusterd2 and the daemons on the
+// node irrespective of their size
+func txnRotateLogs(c transaction.TxnCtx) error {
+	rotated, err := logrotate.Rotate(true)
+	if err != nil {
+		c.Logger().WithError(err).Error("failed to rotate logs")
+		return err
+	}
+
+	c.Logger().WithField("logs", rotated).Info("logs rotated on demand")
+	return nil
+}
+
+// Transaction step that queries the logs on the node. The entries are stored
+// in the transaction context to be merged by the node which initiated it.
+func txnQueryLogs(c transaction.TxnCtx) error {
+	var q logmgmtapi.LogQuery
+	if err := c.Get("query", &q); err != nil {
+		return err
+	}
+
+	filter, err := newLogFilter(q)
+	if err != nil {
+		return err
+	}
+
+	var result logmgmtapi.LogsResp
+	result.Entries, result.Truncated = queryLocalLogs(filter)
+	return c.SetNodeResult(gdctx.MyUUID, logQueryTxnKey, &result)
+}
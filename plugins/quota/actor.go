@@ -6,7 +6,6 @@ import (
 	"path"
 
 	"github.com/gluster/glusterd2/glusterd2/daemon"
-	"github.com/gluster/glusterd2/glusterd2/volgen"
 	"github.com/gluster/glusterd2/glusterd2/volume"
 	"github.com/gluster/glusterd2/glusterd2/xlator"
 	"github.com/gluster/glusterd2/pkg/errors"
@@ -40,9 +39,27 @@ func isQuotadStopRequired(volumes []*volume.Volinfo) bool {
 }
 
 func (actor *quotadActor) Do(v *volume.Volinfo, key, value string, volOp xlator.VolumeOpType, logger log.FieldLogger) error {
-	if key != quotaDaemonKey {
-		return nil
+	// Besides quota being enabled or disabled, the volumes quotad is
+	// serving change when a volume having quota enabled is started or
+	// stopped
+	volinfo := *v
+	switch volOp {
+	case xlator.VolumeStart:
+		if !isQuotaEnabled(v) {
+			return nil
+		}
+		volinfo.State = volume.VolStarted
+	case xlator.VolumeStop:
+		if !isQuotaEnabled(v) {
+			return nil
+		}
+		volinfo.State = volume.VolStopped
+	default:
+		if key != quotaDaemonKey {
+			return nil
+		}
 	}
+
 	quotadDaemon, err := NewQuotad()
 	if err != nil {
 		return err
@@ -64,15 +81,23 @@ func (actor *quotadActor) Do(v *volume.Volinfo, key, value string, volOp xlator.
 		logger.WithError(err).Error("failed to get volumes")
 		return err
 	}
+	for idx, vol := range volumes {
+		if vol.Name == volinfo.Name {
+			volumes[idx] = &volinfo
+		}
+	}
 
-	if value == "off" && isQuotadStopRequired(volumes) {
+	if isQuotadStopRequired(volumes) {
 		// This condition is for disabling quotad
-		if err = daemon.Stop(quotadDaemon, true, logger); err != nil {
+		err = daemon.Stop(quotadDaemon, true, logger)
+		if err == errors.ErrPidFileNotFound {
+			err = nil
+		} else if err != nil {
 			logger.Error("quotad stop failed")
 		}
 	} else {
-		// Quotad must be restarted whenever quota is enabled or disabled
-		// for a volume.
+		// Quotad must be restarted whenever the volumes it serves
+		// change.
 		err = daemon.Stop(quotadDaemon, true, logger)
 		if err == errors.ErrPidFileNotFound {
 			logger.Info("quotad stop failed as pidfile missing")
@@ -81,7 +106,7 @@ func (actor *quotadActor) Do(v *volume.Volinfo, key, value string, volOp xlator.
 		} else {
 			logger.Info("quotad stopped for restart")
 		}
-		err = volgen.ClusterVolfileToFile(v, quotadDaemon.VolfileID, "quotad")
+		err = generateQuotadVolfile(volumes, quotadDaemon.VolfileID)
 		if err != nil {
 			return err
 		}
@@ -5,6 +5,10 @@ type SetLimitReq struct {
 	Path             string `json:"path"`
 	SizeUsageLimit   int    `json:"size-usage-limit,omitempty"`
 	ObjectCountLimit int    `json:"object-count-limit,omitempty"`
+	// SoftLimit is the percentage of the limits above which the usage
+	// is reported as exceeding the soft limit. The quota.default-soft-limit
+	// volume option applies if not set.
+	SoftLimit int `json:"soft-limit,omitempty"`
 }
 
 // RemoveLimitReq represents REST API request to Remove Usage/objects of a directory
@@ -1,11 +1,25 @@
 package api
 
+import (
+	"time"
+)
+
 type crawlInfo struct {
 	CrawlPid int `json:"crawl-pid"`
 	MountPid int `json:"crawl-mount-pid"`
 }
 
-type list struct {
+// Types of quota limits
+const (
+	// LimitTypeUsage limits the space used by a directory
+	LimitTypeUsage int32 = iota
+	// LimitTypeObjects limits the number of files and directories in a
+	// directory
+	LimitTypeObjects
+)
+
+// Limit represents a quota limit of a directory along with its usage
+type Limit struct {
 	Path      string `json:"path"`
 	HardLimit int64  `json:"hard-limit"`
 	SoftLimit int64  `json:"soft-limit"`
@@ -21,11 +35,16 @@ type list struct {
 	LimitType int32 `json:"limit-type"`
 }
 
-//ListResp is an array of structs representing individual limits.
-type ListResp []list
+// ListResp represents the quota limits of a volume
+type ListResp struct {
+	Limits []Limit `json:"limits"`
+	// UpdatedAt is the time at which the usage was aggregated. The
+	// usage is cached and can be up to the cache TTL old.
+	UpdatedAt time.Time `json:"updated-at"`
+}
 
-//DisableResp gives the information of disable crawler on success
+// DisableResp gives the information of disable crawler on success
 type DisableResp crawlInfo
 
-//EnableResp gives the information of enable crawler on success
+// EnableResp gives the information of enable crawler on success
 type EnableResp crawlInfo
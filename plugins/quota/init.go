@@ -2,6 +2,8 @@ package quota
 
 import (
 	"github.com/gluster/glusterd2/glusterd2/servers/rest/route"
+	"github.com/gluster/glusterd2/pkg/utils"
+	quotaapi "github.com/gluster/glusterd2/plugins/quota/api"
 )
 
 const name = "quota"
@@ -19,22 +21,25 @@ func (p *Plugin) Name() string {
 func (p *Plugin) RestRoutes() route.Routes {
 	return route.Routes{
 		route.Route{
-			Name:        "QuotaList",
-			Method:      "GET",
-			Pattern:     "/quota/{volname}/limit",
-			Version:     1,
-			HandlerFunc: quotaListHandler},
+			Name:         "QuotaList",
+			Method:       "GET",
+			Pattern:      "/quota/{volname}/limit",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*quotaapi.ListResp)(nil)),
+			HandlerFunc:  quotaListHandler},
 		route.Route{
 			Name:        "QuotaLimit",
 			Method:      "POST",
 			Pattern:     "/quota/{volname}/limit",
 			Version:     1,
+			RequestType: utils.GetTypeString((*quotaapi.SetLimitReq)(nil)),
 			HandlerFunc: quotaLimitHandler},
 		route.Route{
 			Name:        "QuotaRemove",
 			Method:      "DELETE",
 			Pattern:     "/quota/{volname}/limit",
 			Version:     1,
+			RequestType: utils.GetTypeString((*quotaapi.RemoveLimitReq)(nil)),
 			HandlerFunc: quotaRemoveHandler},
 	}
 }
@@ -5,9 +5,15 @@ import (
 	"net"
 	"os/exec"
 	"path"
+	"time"
 
-	"github.com/cespare/xxhash"
+	"github.com/gluster/glusterd2/glusterd2/daemon"
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/volgen"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/utils"
+
+	"github.com/cespare/xxhash"
 	config "github.com/spf13/viper"
 )
 
@@ -100,3 +106,51 @@ func NewQuotad() (*Quotad, error) {
 func (q *Quotad) ID() string {
 	return "quotad"
 }
+
+// generateQuotadVolfile generates the volfile of quotad, which has the
+// started volumes having quota enabled as children
+func generateQuotadVolfile(volumes []*volume.Volinfo, volfileID string) error {
+	var quotaVolumes []*volume.Volinfo
+	for _, v := range volumes {
+		if v.State == volume.VolStarted && isQuotaEnabled(v) {
+			quotaVolumes = append(quotaVolumes, v)
+		}
+	}
+
+	tmpl, err := volgen.GetTemplateFromVolinfo(nil, utils.QuotadVolfile)
+	if err != nil {
+		return err
+	}
+
+	// quotad looks up its children by the volume-id options. The
+	// template is copied so as not to modify the shared one.
+	quotadTmpl := *tmpl
+	quotadTmpl.Xlators = make([]volgen.Xlator, len(tmpl.Xlators))
+	copy(quotadTmpl.Xlators, tmpl.Xlators)
+	quotadXl := &quotadTmpl.Xlators[0]
+	opts := make(map[string]string)
+	for k, v := range quotadXl.Options {
+		opts[k] = v
+	}
+	for _, v := range quotaVolumes {
+		opts[v.Name+".volume-id"] = v.Name
+	}
+	quotadXl.Options = opts
+
+	volfile, err := volgen.ClusterLevelVolfile(&quotadTmpl, quotaVolumes)
+	if err != nil {
+		return err
+	}
+
+	filename := path.Join(config.GetString("localstatedir"), "volfiles", volfileID+".vol")
+	return volgen.SaveToFile(filename, volfile)
+}
+
+func init() {
+	daemon.RegisterPolicy("quotad", daemon.Policy{
+		DependsOn:     []string{"glusterfsd"},
+		Restart:       daemon.RestartAlways,
+		MaxRestarts:   5,
+		RestartWindow: 10 * time.Minute,
+	})
+}
@@ -1,25 +1,318 @@
 package quota
 
 import (
+	"context"
 	"net/http"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
 	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/errors"
+	quotaapi "github.com/gluster/glusterd2/plugins/quota/api"
+
+	"github.com/gorilla/mux"
+)
+
+const (
+	defaultSoftLimitKey = "quota.default-soft-limit"
+	defaultSoftLimit    = 80
 )
 
+// defaultSoftLimitOf returns the soft limit percentage applying to the
+// limits of the volume without an explicit one
+func defaultSoftLimitOf(v *volume.Volinfo) int64 {
+	val, ok := v.Options[defaultSoftLimitKey]
+	if !ok {
+		return defaultSoftLimit
+	}
+	pct, err := strconv.ParseInt(strings.TrimSuffix(val, "%"), 10, 64)
+	if err != nil {
+		return defaultSoftLimit
+	}
+	return pct
+}
+
+func newLimit(dir string, limitType int32, hardLimit int64, softLimitPct int64, used int64) quotaapi.Limit {
+	l := quotaapi.Limit{
+		Path:      dir,
+		HardLimit: hardLimit,
+		SoftLimit: hardLimit * softLimitPct / 100,
+		Used:      used,
+		LimitType: limitType,
+	}
+	if used < hardLimit {
+		l.Available = hardLimit - used
+	}
+	l.SoftLimitExceeded = used > l.SoftLimit
+	l.HardLimitExceeded = used > hardLimit
+	return l
+}
+
+// aggregateUsage returns the limits of the volume along with the usage of
+// the limited directories, as aggregated over the bricks by the client
+func aggregateUsage(v *volume.Volinfo) (*quotaapi.ListResp, error) {
+	list := &quotaapi.ListResp{
+		Limits:    []quotaapi.Limit{},
+		UpdatedAt: time.Now(),
+	}
+
+	limits, err := getLimits(v.Name)
+	if err != nil || len(limits) == 0 {
+		return list, err
+	}
+
+	dirs := make([]string, 0, len(limits))
+	for dir := range limits {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+
+	defaultPct := defaultSoftLimitOf(v)
+	err = withQuotaMount(v.Name, func(mountpoint string) error {
+		for _, dir := range dirs {
+			u, err := getUsage(path.Join(mountpoint, dir))
+			if err != nil {
+				return err
+			}
+
+			rec := limits[dir]
+			pct := defaultPct
+			if rec.SoftLimit != 0 {
+				pct = int64(rec.SoftLimit)
+			}
+			if rec.HardLimit != 0 {
+				list.Limits = append(list.Limits, newLimit(dir, quotaapi.LimitTypeUsage, rec.HardLimit, pct, u.Size))
+			}
+			if rec.ObjectLimit != 0 {
+				list.Limits = append(list.Limits, newLimit(dir, quotaapi.LimitTypeObjects, rec.ObjectLimit, pct, u.FileCount+u.DirCount))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
 func quotaListHandler(w http.ResponseWriter, r *http.Request) {
-	// implement the help logic and send response back as below
 	ctx := r.Context()
-	restutils.SendHTTPResponse(ctx, w, http.StatusOK, "todo: quota list")
+	logger := gdctx.GetReqLogger(ctx)
+	volname := mux.Vars(r)["volname"]
+	refresh := r.URL.Query().Get("refresh") == "true"
+
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	if !isQuotaEnabled(volinfo) {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrQuotadNotEnabled)
+		return
+	}
+
+	// Aggregating the usage requires a mount of the volume, serve it
+	// from the cache unless asked not to
+	if !refresh {
+		list, err := getCachedList(volname)
+		if err != nil {
+			logger.WithError(err).WithField("volume", volname).Warn("failed to get cached quota usage")
+		} else if list != nil {
+			restutils.SendHTTPResponse(ctx, w, http.StatusOK, list)
+			return
+		}
+	}
+
+	if volinfo.State != volume.VolStarted {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrVolNotStarted)
+		return
+	}
+
+	list, err := aggregateUsage(volinfo)
+	if err != nil {
+		logger.WithError(err).WithField("volume", volname).Error("failed to aggregate quota usage")
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if err := putCachedList(volname, list); err != nil {
+		logger.WithError(err).WithField("volume", volname).Warn("failed to cache quota usage")
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, list)
+}
+
+// sendLimitError sends the error of an operation on the limit of a directory
+func sendLimitError(ctx context.Context, w http.ResponseWriter, err error) {
+	if os.IsNotExist(err) {
+		restutils.SendHTTPError(ctx, w, http.StatusNotFound, err)
+		return
+	}
+	restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
 }
 
 func quotaLimitHandler(w http.ResponseWriter, r *http.Request) {
-	// implement the help logic and send response back as below
 	ctx := r.Context()
-	restutils.SendHTTPResponse(ctx, w, http.StatusOK, "Todo: limitusage")
+	logger := gdctx.GetReqLogger(ctx)
+	volname := mux.Vars(r)["volname"]
+
+	var req quotaapi.SetLimitReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrJSONParsingFailed)
+		return
+	}
+
+	if req.Path == "" || req.SizeUsageLimit < 0 || req.ObjectCountLimit < 0 ||
+		(req.SizeUsageLimit == 0 && req.ObjectCountLimit == 0) ||
+		req.SoftLimit < 0 || req.SoftLimit > 100 {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrQuotaInvalidLimit)
+		return
+	}
+	dir := path.Clean("/" + req.Path)
+
+	txn, err := transaction.NewTxnWithLocks(ctx, volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	defer txn.Done()
+
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	if volinfo.State != volume.VolStarted {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrVolNotStarted)
+		return
+	}
+	if !isQuotaEnabled(volinfo) {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrQuotadNotEnabled)
+		return
+	}
+
+	limits, err := getLimits(volname)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	rec := limitRecord{
+		HardLimit:   int64(req.SizeUsageLimit),
+		ObjectLimit: int64(req.ObjectCountLimit),
+		SoftLimit:   req.SoftLimit,
+	}
+	// The bricks take -1 as the default soft limit
+	softLimit := int64(-1)
+	if req.SoftLimit != 0 {
+		softLimit = int64(req.SoftLimit)
+	}
+
+	err = withQuotaMount(volname, func(mountpoint string) error {
+		target := path.Join(mountpoint, dir)
+		if _, err := os.Stat(target); err != nil {
+			return err
+		}
+		if rec.HardLimit != 0 {
+			if err := setLimitXattr(target, xattrLimitSet, rec.HardLimit, softLimit); err != nil {
+				return err
+			}
+		} else if err := removeLimitXattr(target, xattrLimitSet); err != nil {
+			return err
+		}
+		if rec.ObjectLimit != 0 {
+			return setLimitXattr(target, xattrLimitObjects, rec.ObjectLimit, softLimit)
+		}
+		return removeLimitXattr(target, xattrLimitObjects)
+	})
+	if err != nil {
+		logger.WithError(err).WithField("path", dir).Error("failed to set quota limit")
+		sendLimitError(ctx, w, err)
+		return
+	}
+
+	limits[dir] = rec
+	if err := putLimits(volname, limits); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, nil)
 }
 
 func quotaRemoveHandler(w http.ResponseWriter, r *http.Request) {
-	// implement the help logic and send response back as below
 	ctx := r.Context()
-	restutils.SendHTTPResponse(ctx, w, http.StatusOK, "Todo: quota Remove")
+	logger := gdctx.GetReqLogger(ctx)
+	volname := mux.Vars(r)["volname"]
+
+	var req quotaapi.RemoveLimitReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrJSONParsingFailed)
+		return
+	}
+	dir := path.Clean("/" + req.Path)
+
+	txn, err := transaction.NewTxnWithLocks(ctx, volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	defer txn.Done()
+
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	limits, err := getLimits(volname)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	if _, ok := limits[dir]; !ok {
+		restutils.SendHTTPError(ctx, w, http.StatusNotFound, errors.ErrQuotaLimitNotFound)
+		return
+	}
+
+	if volinfo.State != volume.VolStarted {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrVolNotStarted)
+		return
+	}
+
+	err = withQuotaMount(volname, func(mountpoint string) error {
+		target := path.Join(mountpoint, dir)
+		if err := removeLimitXattr(target, xattrLimitSet); err != nil {
+			return err
+		}
+		return removeLimitXattr(target, xattrLimitObjects)
+	})
+	// The limit is forgotten even if the directory is gone
+	if err != nil && !os.IsNotExist(err) {
+		logger.WithError(err).WithField("path", dir).Error("failed to remove quota limit")
+		sendLimitError(ctx, w, err)
+		return
+	}
+
+	delete(limits, dir)
+	if err := putLimits(volname, limits); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, nil)
 }
@@ -0,0 +1,43 @@
+package quota
+
+import (
+	"testing"
+
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	quotaapi "github.com/gluster/glusterd2/plugins/quota/api"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultSoftLimitOf(t *testing.T) {
+	v := &volume.Volinfo{Options: map[string]string{}}
+	assert.Equal(t, int64(defaultSoftLimit), defaultSoftLimitOf(v))
+
+	for val, pct := range map[string]int64{"90": 90, "70%": 70, "invalid": defaultSoftLimit} {
+		v.Options[defaultSoftLimitKey] = val
+		assert.Equal(t, pct, defaultSoftLimitOf(v), val)
+	}
+}
+
+func TestNewLimit(t *testing.T) {
+	l := newLimit("/dir", quotaapi.LimitTypeUsage, 1000, 80, 500)
+	assert.Equal(t, quotaapi.Limit{
+		Path:      "/dir",
+		HardLimit: 1000,
+		SoftLimit: 800,
+		Used:      500,
+		Available: 500,
+		LimitType: quotaapi.LimitTypeUsage,
+	}, l)
+
+	l = newLimit("/dir", quotaapi.LimitTypeObjects, 1000, 80, 900)
+	assert.True(t, l.SoftLimitExceeded)
+	assert.False(t, l.HardLimitExceeded)
+	assert.Equal(t, int64(100), l.Available)
+
+	// Nothing is available once the hard limit is exceeded
+	l = newLimit("/dir", quotaapi.LimitTypeUsage, 1000, 80, 1200)
+	assert.True(t, l.SoftLimitExceeded)
+	assert.True(t, l.HardLimitExceeded)
+	assert.Equal(t, int64(0), l.Available)
+}
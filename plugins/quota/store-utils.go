@@ -0,0 +1,109 @@
+package quota
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/configsnap"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	quotaapi "github.com/gluster/glusterd2/plugins/quota/api"
+
+	"github.com/coreos/etcd/clientv3"
+)
+
+const (
+	limitsPrefix = "quota/limits/"
+	usagePrefix  = "quota/usage/"
+
+	// usageCacheTTL is the time for which the aggregated usage of the
+	// limited directories is served from the store
+	usageCacheTTL = time.Minute
+)
+
+func init() {
+	// The limits are part of the configuration of the volumes
+	configsnap.RegisterPrefix(limitsPrefix)
+}
+
+// limitRecord is a stored quota limit of a directory
+type limitRecord struct {
+	// HardLimit is the size limit in bytes, 0 if not limited
+	HardLimit int64 `json:"hard-limit,omitempty"`
+	// ObjectLimit is the limit of files and directories, 0 if not
+	// limited
+	ObjectLimit int64 `json:"object-limit,omitempty"`
+	// SoftLimit is the soft limit percentage, 0 for the default
+	SoftLimit int `json:"soft-limit,omitempty"`
+}
+
+// getLimits returns the limits of the volume, mapped by directory
+func getLimits(volname string) (map[string]limitRecord, error) {
+	limits := make(map[string]limitRecord)
+
+	resp, err := store.Get(context.TODO(), limitsPrefix+volname)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Count != 1 {
+		return limits, nil
+	}
+
+	if err := json.Unmarshal(resp.Kvs[0].Value, &limits); err != nil {
+		return nil, err
+	}
+	return limits, nil
+}
+
+// putLimits stores the limits of the volume and drops its cached usage
+func putLimits(volname string, limits map[string]limitRecord) error {
+	var err error
+	if len(limits) == 0 {
+		_, err = store.Delete(context.TODO(), limitsPrefix+volname)
+	} else {
+		var data []byte
+		if data, err = json.Marshal(limits); err != nil {
+			return err
+		}
+		_, err = store.Put(context.TODO(), limitsPrefix+volname, string(data))
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = store.Delete(context.TODO(), usagePrefix+volname)
+	return err
+}
+
+// getCachedList returns the cached quota list of the volume, or nil if it
+// has expired
+func getCachedList(volname string) (*quotaapi.ListResp, error) {
+	resp, err := store.Get(context.TODO(), usagePrefix+volname)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Count != 1 {
+		return nil, nil
+	}
+
+	var list quotaapi.ListResp
+	if err := json.Unmarshal(resp.Kvs[0].Value, &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// putCachedList caches the quota list of the volume for usageCacheTTL
+func putCachedList(volname string, list *quotaapi.ListResp) error {
+	data, err := json.Marshal(list)
+	if err != nil {
+		return err
+	}
+
+	l, err := store.Store.Grant(store.Store.Ctx(), int64(usageCacheTTL/time.Second))
+	if err != nil {
+		return err
+	}
+	_, err = store.Put(context.TODO(), usagePrefix+volname, string(data), clientv3.WithLease(l.ID))
+	return err
+}
@@ -0,0 +1,89 @@
+package quota
+
+import (
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"syscall"
+
+	"github.com/gluster/glusterd2/glusterd2/volume"
+
+	config "github.com/spf13/viper"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	xattrLimitSet     = "trusted.glusterfs.quota.limit-set"
+	xattrLimitObjects = "trusted.glusterfs.quota.limit-objects"
+	xattrSize         = "trusted.glusterfs.quota.size"
+
+	// quotaMountClientPid identifies the auxiliary mount of quota to the
+	// bricks, which only let it set the limits
+	quotaMountClientPid = "-5"
+)
+
+// usage is the aggregated usage of a directory, as accounted by the marker
+// on the bricks
+type usage struct {
+	Size      int64
+	FileCount int64
+	DirCount  int64
+}
+
+// withQuotaMount mounts the volume on a temporary directory with the client
+// pid of the quota auxiliary mount, for the duration of fn
+func withQuotaMount(volname string, fn func(mountpoint string) error) error {
+	tempDir, err := ioutil.TempDir(config.GetString("rundir"), "gd2mount")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tempDir)
+
+	if err := volume.MountVolume(volname, tempDir, " --client-pid="+quotaMountClientPid); err != nil {
+		return err
+	}
+	defer syscall.Unmount(tempDir, syscall.MNT_FORCE)
+
+	return fn(tempDir)
+}
+
+// setLimitXattr sets a limit on the directory. The value is the hard limit
+// followed by the soft limit percentage, in network byte order.
+func setLimitXattr(dir string, key string, hardLimit int64, softLimit int64) error {
+	value := make([]byte, 16)
+	binary.BigEndian.PutUint64(value[0:8], uint64(hardLimit))
+	binary.BigEndian.PutUint64(value[8:16], uint64(softLimit))
+	return unix.Setxattr(dir, key, value, 0)
+}
+
+// removeLimitXattr removes a limit from the directory, if set
+func removeLimitXattr(dir string, key string) error {
+	err := unix.Removexattr(dir, key)
+	if err == unix.ENODATA {
+		return nil
+	}
+	return err
+}
+
+// getUsage returns the aggregated usage of the directory. Older bricks only
+// account the size.
+func getUsage(dir string) (*usage, error) {
+	value := make([]byte, 24)
+	sz, err := unix.Getxattr(dir, xattrSize, value)
+	if err == unix.ENODATA {
+		return &usage{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	u := &usage{}
+	if sz >= 8 {
+		u.Size = int64(binary.BigEndian.Uint64(value[0:8]))
+	}
+	if sz >= 24 {
+		u.FileCount = int64(binary.BigEndian.Uint64(value[8:16]))
+		u.DirCount = int64(binary.BigEndian.Uint64(value[16:24]))
+	}
+	return u, nil
+}
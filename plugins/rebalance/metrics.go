@@ -0,0 +1,111 @@
+package rebalance
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+
+	"github.com/gluster/glusterd2/glusterd2/metrics"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	rebalanceapi "github.com/gluster/glusterd2/plugins/rebalance/api"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+)
+
+var rebalanceStates = map[rebalanceapi.Status]string{
+	rebalanceapi.NotStarted: "not started",
+	rebalanceapi.Started:    "started",
+	rebalanceapi.Stopped:    "stopped",
+	rebalanceapi.Complete:   "complete",
+	rebalanceapi.Failed:     "failed",
+}
+
+// rebalanceCollector exports the progress of rebalance of all volumes
+type rebalanceCollector struct {
+	state          *prometheus.Desc
+	nodesCompleted *prometheus.Desc
+	files          *prometheus.Desc
+	size           *prometheus.Desc
+	lookedup       *prometheus.Desc
+	skipped        *prometheus.Desc
+	failures       *prometheus.Desc
+}
+
+func newRebalanceCollector() *rebalanceCollector {
+	return &rebalanceCollector{
+		state:          metrics.NewDesc("rebalance", "state", "Rebalance state of the volume, the value is 1 for the current state", "volume", "state"),
+		nodesCompleted: metrics.NewDesc("rebalance", "nodes_completed", "Number of nodes which have completed rebalance", "volume"),
+		files:          metrics.NewDesc("rebalance", "files", "Number of files rebalanced", "volume"),
+		size:           metrics.NewDesc("rebalance", "size_bytes", "Size of data rebalanced", "volume"),
+		lookedup:       metrics.NewDesc("rebalance", "lookedup_files", "Number of files looked up", "volume"),
+		skipped:        metrics.NewDesc("rebalance", "skipped_files", "Number of files skipped", "volume"),
+		failures:       metrics.NewDesc("rebalance", "failures", "Number of failures during rebalance", "volume"),
+	}
+}
+
+func (c *rebalanceCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.state
+	ch <- c.nodesCompleted
+	ch <- c.files
+	ch <- c.size
+	ch <- c.lookedup
+	ch <- c.skipped
+	ch <- c.failures
+}
+
+func sumStat(stats []rebalanceapi.RebalNodeStatus, field func(rebalanceapi.RebalNodeStatus) string) float64 {
+	var sum float64
+	for _, s := range stats {
+		if v, err := strconv.ParseFloat(field(s), 64); err == nil {
+			sum += v
+		}
+	}
+	return sum
+}
+
+func (c *rebalanceCollector) Collect(ch chan<- prometheus.Metric) {
+	resp, err := store.Get(context.TODO(), rebalancePrefix, clientv3.WithPrefix())
+	if err != nil {
+		log.WithError(err).Error("metrics: failed to get rebalance info from store")
+		return
+	}
+
+	for _, kv := range resp.Kvs {
+		var info rebalanceapi.RebalInfo
+		if err := json.Unmarshal(kv.Value, &info); err != nil {
+			continue
+		}
+		c.collectVolume(ch, &info)
+	}
+}
+
+// collectVolume exports the rebalance state and statistics of a volume
+func (c *rebalanceCollector) collectVolume(ch chan<- prometheus.Metric, info *rebalanceapi.RebalInfo) {
+	v := info.Volname
+	for state, name := range rebalanceStates {
+		val := 0.0
+		if state == info.State {
+			val = 1
+		}
+		ch <- prometheus.MustNewConstMetric(c.state, prometheus.GaugeValue, val, v, name)
+	}
+
+	stats := info.RebalStats
+	ch <- prometheus.MustNewConstMetric(c.nodesCompleted, prometheus.GaugeValue, float64(len(stats)), v)
+	ch <- prometheus.MustNewConstMetric(c.files, prometheus.GaugeValue,
+		sumStat(stats, func(s rebalanceapi.RebalNodeStatus) string { return s.RebalancedFiles }), v)
+	ch <- prometheus.MustNewConstMetric(c.size, prometheus.GaugeValue,
+		sumStat(stats, func(s rebalanceapi.RebalNodeStatus) string { return s.RebalancedSize }), v)
+	ch <- prometheus.MustNewConstMetric(c.lookedup, prometheus.GaugeValue,
+		sumStat(stats, func(s rebalanceapi.RebalNodeStatus) string { return s.LookedupFiles }), v)
+	ch <- prometheus.MustNewConstMetric(c.skipped, prometheus.GaugeValue,
+		sumStat(stats, func(s rebalanceapi.RebalNodeStatus) string { return s.SkippedFiles }), v)
+	ch <- prometheus.MustNewConstMetric(c.failures, prometheus.GaugeValue,
+		sumStat(stats, func(s rebalanceapi.RebalNodeStatus) string { return s.RebalanceFailures }), v)
+}
+
+func init() {
+	metrics.Register(newRebalanceCollector())
+}
@@ -0,0 +1,39 @@
+package rebalance
+
+import (
+	"testing"
+
+	"github.com/gluster/glusterd2/pkg/testutils"
+	rebalanceapi "github.com/gluster/glusterd2/plugins/rebalance/api"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRebalanceCollector(t *testing.T) {
+	info := &rebalanceapi.RebalInfo{
+		Volname: "gv0",
+		State:   rebalanceapi.Started,
+		RebalStats: []rebalanceapi.RebalNodeStatus{
+			{RebalancedFiles: "10", RebalancedSize: "4096", LookedupFiles: "20", SkippedFiles: "1", RebalanceFailures: "0"},
+			// Statistics which cannot be parsed are not counted
+			{RebalancedFiles: "5", RebalancedSize: "", LookedupFiles: "8", SkippedFiles: "x", RebalanceFailures: "2"},
+		},
+	}
+
+	c := newRebalanceCollector()
+	values, err := testutils.CollectMetrics(c, func(ch chan<- prometheus.Metric) {
+		c.collectVolume(ch, info)
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 1.0, values[`glusterd2_rebalance_state{state="started",volume="gv0"}`])
+	assert.Equal(t, 0.0, values[`glusterd2_rebalance_state{state="complete",volume="gv0"}`])
+	assert.Equal(t, 2.0, values[`glusterd2_rebalance_nodes_completed{volume="gv0"}`])
+	assert.Equal(t, 15.0, values[`glusterd2_rebalance_files{volume="gv0"}`])
+	assert.Equal(t, 4096.0, values[`glusterd2_rebalance_size_bytes{volume="gv0"}`])
+	assert.Equal(t, 28.0, values[`glusterd2_rebalance_lookedup_files{volume="gv0"}`])
+	assert.Equal(t, 1.0, values[`glusterd2_rebalance_skipped_files{volume="gv0"}`])
+	assert.Equal(t, 2.0, values[`glusterd2_rebalance_failures{volume="gv0"}`])
+}
@@ -1,12 +1,12 @@
 package rebalance
 
 import (
-	//        "bytes"
 	"fmt"
 	"net"
 	"os/exec"
 	"path"
 
+	"github.com/gluster/glusterd2/glusterd2/daemon"
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
 	rebalanceapi "github.com/gluster/glusterd2/plugins/rebalance/api"
 
@@ -112,3 +112,11 @@ func NewRebalanceProcess(rinfo rebalanceapi.RebalInfo) (*Process, error) {
 func (r *Process) ID() string {
 	return r.rInfo.Volname + "-rebalance"
 }
+
+func init() {
+	// the rebalance process exits once done, it is not to be restarted
+	daemon.RegisterPolicy("rebalance", daemon.Policy{
+		DependsOn: []string{"glusterfsd"},
+		Restart:   daemon.RestartNever,
+	})
+}
@@ -82,6 +82,13 @@ func rebalanceStartHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Migrating the data would break the retention of the files, only
+	// the layout may be fixed
+	if vol.IsWORM() && rebalinfo.Cmd != rebalanceapi.CmdFixLayoutStart {
+		restutils.SendHTTPError(ctx, w, http.StatusForbidden, errors.ErrVolumeWORM)
+		return
+	}
+
 	// TODO: Check for remove-brick
 
 	// Start the rebalance process on all nodes
@@ -265,6 +272,7 @@ func rebalanceStatusHandler(w http.ResponseWriter, r *http.Request) {
 		{
 			DoFunc: "rebalance-status",
 			Nodes:  txn.Nodes,
+			Stream: true,
 		},
 	}
 
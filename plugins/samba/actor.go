@@ -0,0 +1,100 @@
+package samba
+
+import (
+	"github.com/gluster/glusterd2/glusterd2/options"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/glusterd2/xlator"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// smbActor regenerates the smb.conf fragment when the automatic share of a
+// volume appears or goes away, ie. when user.smb is set or reset on a started
+// volume or when a volume with user.smb enabled is started or stopped.
+type smbActor struct{}
+
+// volumeAfter returns the volume as it would be after the operation. The
+// volume passed to the actors may not reflect the operation yet.
+func volumeAfter(v *volume.Volinfo, value string, volOp xlator.VolumeOpType) *volume.Volinfo {
+	after := *v
+	after.Options = make(map[string]string, len(v.Options))
+	for k, val := range v.Options {
+		after.Options[k] = val
+	}
+
+	switch volOp {
+	case xlator.VolumeSet, xlator.VolumeReset:
+		after.Options[optSMB] = value
+	case xlator.VolumeStart:
+		after.State = volume.VolStarted
+	case xlator.VolumeStop:
+		after.State = volume.VolStopped
+	}
+	return &after
+}
+
+// volumeBefore returns the volume as it was before the operation, used to
+// undo it. The previous value of user.smb is not known to the actor, a failed
+// set or reset is assumed to have changed it.
+func volumeBefore(v *volume.Volinfo, value string, volOp xlator.VolumeOpType) *volume.Volinfo {
+	before := *v
+	switch volOp {
+	case xlator.VolumeSet, xlator.VolumeReset:
+		before.Options = make(map[string]string, len(v.Options))
+		for k, val := range v.Options {
+			before.Options[k] = val
+		}
+		if value == "enable" {
+			before.Options[optSMB] = "disable"
+		} else {
+			before.Options[optSMB] = "enable"
+		}
+	case xlator.VolumeStart:
+		before.State = volume.VolStopped
+	case xlator.VolumeStop:
+		before.State = volume.VolStarted
+	}
+	return &before
+}
+
+func (actor *smbActor) Do(v *volume.Volinfo, key string, value string, volOp xlator.VolumeOpType, logger log.FieldLogger) error {
+	switch volOp {
+	case xlator.VolumeStart, xlator.VolumeStop:
+		// Only volumes exported by user.smb are of interest
+		if v.Options[optSMB] != "enable" {
+			return nil
+		}
+	}
+	return refreshConf(volumeAfter(v, value, volOp), logger)
+}
+
+func (actor *smbActor) Undo(v *volume.Volinfo, key string, value string, volOp xlator.VolumeOpType, logger log.FieldLogger) error {
+	switch volOp {
+	case xlator.VolumeStart, xlator.VolumeStop:
+		if v.Options[optSMB] != "enable" {
+			return nil
+		}
+	}
+	return refreshConf(volumeBefore(v, value, volOp), logger)
+}
+
+func init() {
+	actor := &smbActor{}
+
+	// user.smb is not an option of any xlator, it is consumed only by this
+	// plugin
+	xlator.RegisterPseudoXlator(&xlator.Xlator{
+		ID: "user",
+		Options: []*options.Option{{
+			Key:          []string{"smb"},
+			Type:         options.OptionTypeStr,
+			Value:        []string{"enable", "disable"},
+			DefaultValue: "disable",
+			Description:  "Export the volume as the Samba share gluster-<volname> on all the peers",
+			Flags:        options.OptionFlagSettable,
+			Level:        options.OptionStatusBasic,
+		}},
+		Actor: actor,
+	})
+	xlator.RegisterOptionActor("samba", actor)
+}
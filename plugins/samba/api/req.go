@@ -0,0 +1,31 @@
+package api
+
+// ShareCreateReq is the request to export a directory of a volume as a Samba
+// share on all the peers
+type ShareCreateReq struct {
+	Name   string `json:"name"`
+	Volume string `json:"volume"`
+	// Path is the directory of the volume exported by the share, relative
+	// to the root of the volume. The whole volume is exported if empty.
+	Path    string `json:"path,omitempty"`
+	Comment string `json:"comment,omitempty"`
+	// ReadOnly exports the share read-only
+	ReadOnly bool `json:"read-only,omitempty"`
+	// Hidden excludes the share from the list of browseable shares
+	Hidden bool `json:"hidden,omitempty"`
+	// ValidUsers restricts access to the share to the listed users and
+	// @groups. Everyone authenticated by smbd may connect if empty.
+	ValidUsers []string `json:"valid-users,omitempty"`
+	// Options are additional smb.conf parameters of the share
+	Options map[string]string `json:"options,omitempty"`
+}
+
+// CTDBSetupReq is the request to use a volume as the CTDB lock volume of the
+// cluster. The volume is mounted on all the peers and the CTDB recovery lock
+// is placed in it.
+type CTDBSetupReq struct {
+	Volume string `json:"volume"`
+	// MountPath is where the lock volume is mounted on every peer. It
+	// defaults to /gluster/lock.
+	MountPath string `json:"mount-path,omitempty"`
+}
@@ -0,0 +1,49 @@
+package api
+
+import (
+	"github.com/pborman/uuid"
+)
+
+// Share is a Samba share backed by a gluster volume
+type Share struct {
+	Name       string            `json:"name"`
+	Volume     string            `json:"volume"`
+	Path       string            `json:"path"`
+	Comment    string            `json:"comment,omitempty"`
+	ReadOnly   bool              `json:"read-only"`
+	Hidden     bool              `json:"hidden"`
+	ValidUsers []string          `json:"valid-users,omitempty"`
+	Options    map[string]string `json:"options,omitempty"`
+	// Automatic is true for the shares exported because of the user.smb
+	// option of the volume. They cannot be deleted, reset the option
+	// instead.
+	Automatic bool `json:"automatic"`
+}
+
+// ShareListResp is the response containing all the Samba shares
+type ShareListResp []Share
+
+// CTDBConfig is the CTDB configuration of the cluster
+type CTDBConfig struct {
+	Volume    string `json:"volume"`
+	MountPath string `json:"mount-path"`
+	// RecoveryLock is the path of the CTDB recovery lock file
+	RecoveryLock string `json:"recovery-lock"`
+}
+
+// NodeStatus is the Samba health of a peer
+type NodeStatus struct {
+	PeerID      uuid.UUID `json:"peer-id"`
+	SmbdRunning bool      `json:"smbd-running"`
+	CTDBRunning bool      `json:"ctdbd-running"`
+	Shares      int       `json:"shares"`
+	LockMounted bool      `json:"lock-volume-mounted"`
+	ConfigError string    `json:"config-error,omitempty"`
+}
+
+// StatusResp is the response containing the Samba health of all the peers
+type StatusResp struct {
+	Nodes []NodeStatus `json:"nodes"`
+	// Unreachable lists the peers which did not report their status
+	Unreachable []uuid.UUID `json:"unreachable,omitempty"`
+}
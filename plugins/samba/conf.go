@@ -0,0 +1,207 @@
+package samba
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gluster/glusterd2/glusterd2/daemon"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/utils"
+	sambaapi "github.com/gluster/glusterd2/plugins/samba/api"
+
+	log "github.com/sirupsen/logrus"
+	config "github.com/spf13/viper"
+)
+
+const (
+	sharesConfFile = "shares.conf"
+	smbcontrolBin  = "smbcontrol"
+)
+
+// smbdPidFiles are the locations of the pid file of smbd used by the
+// distributions
+var smbdPidFiles = []string{
+	"/run/samba/smbd.pid",
+	"/run/smbd.pid",
+	"/var/run/samba/smbd.pid",
+}
+
+var (
+	// confMu serializes the regeneration of smb.conf fragment on this node
+	confMu sync.Mutex
+	// confErr is the error of the last regeneration of the fragment, if
+	// any
+	confErr error
+)
+
+// confDir returns the directory holding the configuration fragments which
+// smb.conf and ctdb.conf of the node are expected to include
+func confDir() string {
+	return path.Join(config.GetString("localstatedir"), "samba")
+}
+
+// SharesConfFile returns the path of the smb.conf fragment defining the
+// shares. smb.conf must include it, as in `include = <path>`.
+func SharesConfFile() string {
+	return path.Join(confDir(), sharesConfFile)
+}
+
+// yesNo formats a boolean smb.conf parameter
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+// shareSection returns the smb.conf section of the share. The share accesses
+// the volume through libgfapi using vfs_glusterfs, so the path is relative
+// to the root of the volume.
+func shareSection(share *sambaapi.Share) string {
+	var buf bytes.Buffer
+	params := map[string]string{
+		"path":                     share.Path,
+		"read only":                yesNo(share.ReadOnly),
+		"browseable":               yesNo(!share.Hidden),
+		"vfs objects":              "glusterfs",
+		"glusterfs:volume":         share.Volume,
+		"glusterfs:volfile_server": "localhost",
+		"glusterfs:logfile":        path.Join(config.GetString("logdir"), "glusterfs", "samba-"+share.Volume+".log"),
+		"kernel share modes":       "no",
+	}
+	if share.Comment != "" {
+		params["comment"] = share.Comment
+	}
+	if len(share.ValidUsers) > 0 {
+		params["valid users"] = strings.Join(share.ValidUsers, " ")
+	}
+	// Additional parameters may override the defaults above
+	for k, v := range share.Options {
+		params[k] = v
+	}
+
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	fmt.Fprintf(&buf, "[%s]\n", share.Name)
+	for _, k := range keys {
+		fmt.Fprintf(&buf, "\t%s = %s\n", k, params[k])
+	}
+	return buf.String()
+}
+
+// generateSharesConf returns the smb.conf fragment defining the shares
+func generateSharesConf(shares []sambaapi.Share) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("# Generated by glusterd2. Do not edit, changes will be overwritten.\n")
+	for i := range shares {
+		buf.WriteString("\n")
+		buf.WriteString(shareSection(&shares[i]))
+	}
+	return buf.Bytes()
+}
+
+// refreshConf regenerates the smb.conf fragment of this node from the store.
+// override is passed on to allShares.
+func refreshConf(override *volume.Volinfo, logger log.FieldLogger) error {
+	shares, err := allShares(override)
+	return updateConf(shares, err, logger)
+}
+
+// updateConf writes the smb.conf fragment defining the shares and makes smbd
+// reload its configuration if the fragment changed. err is the error in
+// listing the shares, if any, which is recorded for the status of the node.
+func updateConf(shares []sambaapi.Share, err error, logger log.FieldLogger) error {
+	confMu.Lock()
+	defer confMu.Unlock()
+
+	if err == nil {
+		err = writeSharesConf(generateSharesConf(shares), logger)
+	}
+	confErr = err
+	return err
+}
+
+func writeSharesConf(content []byte, logger log.FieldLogger) error {
+	file := SharesConfFile()
+	if old, err := ioutil.ReadFile(file); err == nil && bytes.Equal(old, content) {
+		return nil
+	}
+
+	if err := os.MkdirAll(confDir(), os.ModeDir|os.ModePerm); err != nil {
+		return err
+	}
+	tmp := file + ".tmp"
+	if err := ioutil.WriteFile(tmp, content, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, file); err != nil {
+		return err
+	}
+	logger.WithField("file", file).Info("updated samba shares configuration")
+
+	return reloadSmbd(logger)
+}
+
+// reloadSmbd makes smbd reload its configuration, if it is running
+func reloadSmbd(logger log.FieldLogger) error {
+	if !smbdRunning() {
+		return nil
+	}
+	if _, err := exec.LookPath(smbcontrolBin); err != nil {
+		logger.WithError(err).Warn("cannot reload smbd configuration")
+		return nil
+	}
+	return utils.ExecuteCommandRun(smbcontrolBin, "smbd", "reload-config")
+}
+
+// processRunning returns true if the process whose pid is in any of the pid
+// files is running
+func processRunning(pidFiles ...string) bool {
+	for _, file := range pidFiles {
+		pid, err := daemon.ReadPidFromFile(file)
+		if err != nil {
+			continue
+		}
+		if _, err := daemon.GetProcess(pid); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+func smbdRunning() bool {
+	return processRunning(smbdPidFiles...)
+}
+
+// confStatus returns the number of shares in the smb.conf fragment of this
+// node and the error of the last regeneration of the fragment, if any
+func confStatus() (int, error) {
+	confMu.Lock()
+	defer confMu.Unlock()
+
+	content, err := ioutil.ReadFile(SharesConfFile())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, confErr
+		}
+		return 0, err
+	}
+	var n int
+	for _, line := range strings.Split(string(content), "\n") {
+		if strings.HasPrefix(line, "[") {
+			n++
+		}
+	}
+	return n, confErr
+}
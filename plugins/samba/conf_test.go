@@ -0,0 +1,84 @@
+package samba
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	sambaapi "github.com/gluster/glusterd2/plugins/samba/api"
+
+	log "github.com/sirupsen/logrus"
+	config "github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShareSection(t *testing.T) {
+	defer config.Set("logdir", config.Get("logdir"))
+	config.Set("logdir", "/var/log/glusterd2")
+
+	share := &sambaapi.Share{
+		Name:       "share1",
+		Volume:     "vol1",
+		Path:       "/dir",
+		Comment:    "a share",
+		ReadOnly:   true,
+		ValidUsers: []string{"alice", "@staff"},
+		// The additional parameters override the defaults
+		Options: map[string]string{"kernel share modes": "yes"},
+	}
+	assert.Equal(t, "[share1]\n"+
+		"\tbrowseable = yes\n"+
+		"\tcomment = a share\n"+
+		"\tglusterfs:logfile = /var/log/glusterd2/glusterfs/samba-vol1.log\n"+
+		"\tglusterfs:volfile_server = localhost\n"+
+		"\tglusterfs:volume = vol1\n"+
+		"\tkernel share modes = yes\n"+
+		"\tpath = /dir\n"+
+		"\tread only = yes\n"+
+		"\tvalid users = alice @staff\n"+
+		"\tvfs objects = glusterfs\n", shareSection(share))
+
+	share = &sambaapi.Share{Name: "share2", Volume: "vol1", Path: "/", Hidden: true}
+	section := shareSection(share)
+	assert.Contains(t, section, "\tbrowseable = no\n")
+	assert.Contains(t, section, "\tread only = no\n")
+	assert.NotContains(t, section, "comment")
+	assert.NotContains(t, section, "valid users")
+}
+
+func TestWriteSharesConf(t *testing.T) {
+	dir, err := ioutil.TempDir("", "samba")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	defer config.Set("localstatedir", config.Get("localstatedir"))
+	config.Set("localstatedir", dir)
+
+	n, err := confStatus()
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+
+	shares := []sambaapi.Share{
+		{Name: "share1", Volume: "vol1", Path: "/"},
+		{Name: "share2", Volume: "vol2", Path: "/dir"},
+	}
+	require.NoError(t, updateConf(shares, nil, log.StandardLogger()))
+	content, err := ioutil.ReadFile(SharesConfFile())
+	require.NoError(t, err)
+	assert.Equal(t, generateSharesConf(shares), content)
+	n, err = confStatus()
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	// The error in listing the shares is reported in the status, and the
+	// fragment is left as it is
+	require.Error(t, updateConf(nil, ErrShareNotFound, log.StandardLogger()))
+	n, err = confStatus()
+	assert.Equal(t, ErrShareNotFound, err)
+	assert.Equal(t, 2, n)
+
+	require.NoError(t, updateConf(shares[:1], nil, log.StandardLogger()))
+	n, err = confStatus()
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+}
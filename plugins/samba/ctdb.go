@@ -0,0 +1,143 @@
+package samba
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path"
+	"sort"
+	"syscall"
+
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	sambaapi "github.com/gluster/glusterd2/plugins/samba/api"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	defaultLockMountPath = "/gluster/lock"
+	recoveryLockFile     = ".CTDB-lockfile"
+	ctdbConfFile         = "ctdb.conf"
+	ctdbNodesFile        = "ctdb-nodes"
+)
+
+// ctdbPidFiles are the locations of the pid file of ctdbd used by the
+// distributions
+var ctdbPidFiles = []string{
+	"/run/ctdb/ctdbd.pid",
+	"/var/run/ctdb/ctdbd.pid",
+}
+
+// CTDBConfFile returns the path of the ctdb.conf fragment setting the
+// recovery lock
+func CTDBConfFile() string {
+	return path.Join(confDir(), ctdbConfFile)
+}
+
+// CTDBNodesFile returns the path of the CTDB nodes file listing the peers
+func CTDBNodesFile() string {
+	return path.Join(confDir(), ctdbNodesFile)
+}
+
+func ctdbdRunning() bool {
+	return processRunning(ctdbPidFiles...)
+}
+
+// lockVolumeMounted returns true if the lock volume is mounted on this node
+func lockVolumeMounted(cfg *sambaapi.CTDBConfig) bool {
+	mounts, err := volume.GetMounts()
+	if err != nil {
+		return false
+	}
+	for _, m := range mounts {
+		if m.MntDir == cfg.MountPath && m.MntType == "fuse.glusterfs" {
+			return true
+		}
+	}
+	return false
+}
+
+// ctdbNodes returns the CTDB nodes file listing the address of every peer.
+// The order is stable so that all the peers generate the same file, as
+// CTDB requires.
+func ctdbNodes() ([]byte, error) {
+	peers, err := peer.GetPeers()
+	if err != nil {
+		return nil, err
+	}
+
+	var addrs []string
+	for _, p := range peers {
+		if len(p.PeerAddresses) == 0 {
+			continue
+		}
+		host, _, err := net.SplitHostPort(p.PeerAddresses[0])
+		if err != nil {
+			host = p.PeerAddresses[0]
+		}
+		addrs = append(addrs, host)
+	}
+	sort.Strings(addrs)
+
+	var buf bytes.Buffer
+	for _, addr := range addrs {
+		fmt.Fprintln(&buf, addr)
+	}
+	return buf.Bytes(), nil
+}
+
+// setupCTDB mounts the lock volume on this node and writes the ctdb.conf
+// fragment and the nodes file
+func setupCTDB(cfg *sambaapi.CTDBConfig, logger log.FieldLogger) error {
+	if err := os.MkdirAll(cfg.MountPath, os.ModeDir|os.ModePerm); err != nil {
+		return err
+	}
+	if !lockVolumeMounted(cfg) {
+		if err := volume.MountVolume(cfg.Volume, cfg.MountPath, ""); err != nil {
+			return err
+		}
+		logger.WithFields(log.Fields{
+			"volume": cfg.Volume,
+			"path":   cfg.MountPath,
+		}).Info("mounted CTDB lock volume")
+	}
+
+	if err := os.MkdirAll(confDir(), os.ModeDir|os.ModePerm); err != nil {
+		return err
+	}
+	conf := fmt.Sprintf("# Generated by glusterd2. Do not edit, changes will be overwritten.\n[cluster]\n\trecovery lock = %s\n", cfg.RecoveryLock)
+	if err := ioutil.WriteFile(CTDBConfFile(), []byte(conf), 0644); err != nil {
+		return err
+	}
+
+	nodes, err := ctdbNodes()
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(CTDBNodesFile(), nodes, 0644)
+}
+
+// teardownCTDB unmounts the lock volume on this node and removes the CTDB
+// configuration written by setupCTDB
+func teardownCTDB(cfg *sambaapi.CTDBConfig, logger log.FieldLogger) error {
+	for _, file := range []string{CTDBConfFile(), CTDBNodesFile()} {
+		if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	if !lockVolumeMounted(cfg) {
+		return nil
+	}
+	if err := syscall.Unmount(cfg.MountPath, 0); err != nil {
+		return err
+	}
+	logger.WithFields(log.Fields{
+		"volume": cfg.Volume,
+		"path":   cfg.MountPath,
+	}).Info("unmounted CTDB lock volume")
+	return nil
+}
@@ -0,0 +1,19 @@
+package samba
+
+import (
+	"errors"
+)
+
+var (
+	// ErrShareNotFound : the share does not exist
+	ErrShareNotFound = errors.New("share not found")
+	// ErrShareExists : a share with the same name exists
+	ErrShareExists = errors.New("share already exists")
+	// ErrShareAutomatic : the share is exported because of the user.smb
+	// option of the volume
+	ErrShareAutomatic = errors.New("share is exported by the user.smb volume option, reset the option instead")
+	// ErrInvalidShareName : the share name is not accepted by smbd
+	ErrInvalidShareName = errors.New("invalid share name")
+	// ErrCTDBNotConfigured : no CTDB lock volume is set up
+	ErrCTDBNotConfigured = errors.New("CTDB lock volume is not configured")
+)
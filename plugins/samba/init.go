@@ -0,0 +1,96 @@
+package samba
+
+import (
+	"github.com/gluster/glusterd2/glusterd2/servers/rest/route"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/pkg/utils"
+	sambaapi "github.com/gluster/glusterd2/plugins/samba/api"
+)
+
+const name = "samba"
+
+// Plugin is a structure which implements GlusterdPlugin interface
+type Plugin struct {
+}
+
+// Name returns name of plugin
+func (p *Plugin) Name() string {
+	return name
+}
+
+// RestRoutes returns list of REST API routes to register with Glusterd
+func (p *Plugin) RestRoutes() route.Routes {
+	return route.Routes{
+		route.Route{
+			Name:         "SambaShareCreate",
+			Method:       "POST",
+			Pattern:      "/samba/shares",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*sambaapi.ShareCreateReq)(nil)),
+			ResponseType: utils.GetTypeString((*sambaapi.Share)(nil)),
+			HandlerFunc:  shareCreateHandler},
+		route.Route{
+			Name:         "SambaShareList",
+			Method:       "GET",
+			Pattern:      "/samba/shares",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*sambaapi.ShareListResp)(nil)),
+			HandlerFunc:  shareListHandler},
+		route.Route{
+			Name:         "SambaShareGet",
+			Method:       "GET",
+			Pattern:      "/samba/shares/{name}",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*sambaapi.Share)(nil)),
+			HandlerFunc:  shareGetHandler},
+		route.Route{
+			Name:        "SambaShareDelete",
+			Method:      "DELETE",
+			Pattern:     "/samba/shares/{name}",
+			Version:     1,
+			HandlerFunc: shareDeleteHandler},
+		route.Route{
+			Name:         "SambaCTDBSetup",
+			Method:       "PUT",
+			Pattern:      "/samba/ctdb",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*sambaapi.CTDBSetupReq)(nil)),
+			ResponseType: utils.GetTypeString((*sambaapi.CTDBConfig)(nil)),
+			HandlerFunc:  ctdbSetupHandler},
+		route.Route{
+			Name:         "SambaCTDBGet",
+			Method:       "GET",
+			Pattern:      "/samba/ctdb",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*sambaapi.CTDBConfig)(nil)),
+			HandlerFunc:  ctdbGetHandler},
+		route.Route{
+			Name:        "SambaCTDBDelete",
+			Method:      "DELETE",
+			Pattern:     "/samba/ctdb",
+			Version:     1,
+			HandlerFunc: ctdbDeleteHandler},
+		route.Route{
+			Name:         "SambaStatus",
+			Method:       "GET",
+			Pattern:      "/samba/status",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*sambaapi.StatusResp)(nil)),
+			HandlerFunc:  statusHandler},
+	}
+}
+
+// RegisterStepFuncs registers transaction step functions with
+// Glusterd Transaction framework
+func (p *Plugin) RegisterStepFuncs() {
+	transaction.RegisterStepFunc(txnShareCreate, "samba-share-create.Commit")
+	transaction.RegisterStepFunc(txnShareDelete, "samba-share-delete.Commit")
+	transaction.RegisterStepFunc(txnRefreshConf, "samba.RefreshConf")
+	transaction.RegisterStepFunc(txnShareStore, "samba-share.Store")
+	transaction.RegisterStepFunc(txnShareDeleteStore, "samba-share.DeleteStore")
+	transaction.RegisterStepFunc(txnCTDBSetup, "samba-ctdb.Setup")
+	transaction.RegisterStepFunc(txnCTDBTeardown, "samba-ctdb.Teardown")
+	transaction.RegisterStepFunc(txnCTDBStore, "samba-ctdb.Store")
+	transaction.RegisterStepFunc(txnCTDBDeleteStore, "samba-ctdb.DeleteStore")
+	transaction.RegisterStepFunc(txnStatus, "samba-status.Commit")
+}
@@ -0,0 +1,412 @@
+package samba
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+	sambaapi "github.com/gluster/glusterd2/plugins/samba/api"
+
+	"github.com/gorilla/mux"
+	"github.com/pborman/uuid"
+)
+
+const (
+	shareLockPrefix = "samba-share."
+	ctdbLock        = "samba-ctdb"
+
+	// maxShareNameLen is the longest share name accepted by Windows
+	// clients
+	maxShareNameLen = 80
+)
+
+// validateShareName checks that the share name can be used as an smb.conf
+// section name
+func validateShareName(name string) error {
+	switch {
+	case name == "", len(name) > maxShareNameLen:
+		return ErrInvalidShareName
+	case strings.ContainsAny(name, "[]\"/\\:;|<>+=,*?%\n"):
+		return fmt.Errorf("%s: %q contains a reserved character", ErrInvalidShareName, name)
+	case strings.EqualFold(name, "global"), strings.EqualFold(name, "homes"), strings.EqualFold(name, "printers"):
+		return fmt.Errorf("%s: %q is reserved by smbd", ErrInvalidShareName, name)
+	case strings.HasPrefix(name, automaticSharePrefix):
+		return fmt.Errorf("%s: prefix %q is reserved for the shares exported by %s", ErrInvalidShareName, automaticSharePrefix, optSMB)
+	}
+	return nil
+}
+
+// findShare returns the stored or automatic share with the given name
+func findShare(name string) (*sambaapi.Share, error) {
+	share, err := getShare(name)
+	if err != ErrShareNotFound {
+		return share, err
+	}
+
+	if !strings.HasPrefix(name, automaticSharePrefix) {
+		return nil, ErrShareNotFound
+	}
+	v, err := volume.GetVolume(strings.TrimPrefix(name, automaticSharePrefix))
+	if err != nil || !smbEnabled(v) {
+		return nil, ErrShareNotFound
+	}
+	s := automaticShare(v)
+	return &s, nil
+}
+
+func shareListHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	shares, err := allShares(nil)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, sambaapi.ShareListResp(shares))
+}
+
+func shareGetHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	share, err := findShare(mux.Vars(r)["name"])
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err == ErrShareNotFound {
+			status = http.StatusNotFound
+		}
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, share)
+}
+
+func shareCreateHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+
+	var req sambaapi.ShareCreateReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
+		return
+	}
+	if err := validateShareName(req.Name); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	// The volume is locked so that it is not deleted while being exported
+	txn, err := transaction.NewTxnWithLocks(ctx, shareLockPrefix+req.Name, req.Volume)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	defer txn.Done()
+
+	if _, err := volume.GetVolume(req.Volume); err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	if _, err := getShare(req.Name); err != ErrShareNotFound {
+		if err == nil {
+			err = ErrShareExists
+		}
+		restutils.SendHTTPError(ctx, w, http.StatusConflict, err)
+		return
+	}
+
+	share := sambaapi.Share{
+		Name:       req.Name,
+		Volume:     req.Volume,
+		Path:       path.Clean("/" + req.Path),
+		Comment:    req.Comment,
+		ReadOnly:   req.ReadOnly,
+		Hidden:     req.Hidden,
+		ValidUsers: req.ValidUsers,
+		Options:    req.Options,
+	}
+	if err := shareTxn(txn, &share, "create"); err != nil {
+		logger.WithError(err).WithField("share", share.Name).Error("failed to create samba share")
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusCreated, share)
+}
+
+func shareDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+	name := mux.Vars(r)["name"]
+
+	txn, err := transaction.NewTxnWithLocks(ctx, shareLockPrefix+name)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	defer txn.Done()
+
+	share, err := getShare(name)
+	if err == ErrShareNotFound {
+		if _, err := findShare(name); err == nil {
+			restutils.SendHTTPError(ctx, w, http.StatusConflict, ErrShareAutomatic)
+			return
+		}
+		restutils.SendHTTPError(ctx, w, http.StatusNotFound, err)
+		return
+	} else if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if err := shareTxn(txn, share, "delete"); err != nil {
+		logger.WithError(err).WithField("share", share.Name).Error("failed to delete samba share")
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusNoContent, nil)
+}
+
+// shareTxn configures the creation or deletion of the share on all the peers
+// and then records it in the store
+func shareTxn(txn *transaction.Txn, share *sambaapi.Share, action string) error {
+	nodes, err := peer.GetPeerIDs()
+	if err != nil {
+		return err
+	}
+
+	storeFunc := "samba-share.Store"
+	if action == "delete" {
+		storeFunc = "samba-share.DeleteStore"
+	}
+
+	txn.Nodes = nodes
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc:   "samba-share-" + action + ".Commit",
+			UndoFunc: "samba.RefreshConf",
+			Nodes:    nodes,
+		},
+		{
+			DoFunc: storeFunc,
+			Nodes:  []uuid.UUID{gdctx.MyUUID},
+		},
+	}
+	if err := txn.Ctx.Set("share", share); err != nil {
+		return err
+	}
+	return txn.Do()
+}
+
+func ctdbGetHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	cfg, err := getCTDBConfig()
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err == ErrCTDBNotConfigured {
+			status = http.StatusNotFound
+		}
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, cfg)
+}
+
+func ctdbSetupHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+
+	var req sambaapi.CTDBSetupReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
+		return
+	}
+	if req.MountPath == "" {
+		req.MountPath = defaultLockMountPath
+	}
+	if !path.IsAbs(req.MountPath) {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "mount-path must be an absolute path")
+		return
+	}
+
+	txn, err := transaction.NewTxnWithLocks(ctx, ctdbLock, req.Volume)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	defer txn.Done()
+
+	v, err := volume.GetVolume(req.Volume)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	if v.State != volume.VolStarted {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrVolNotStarted)
+		return
+	}
+
+	// Setting up the same lock volume again refreshes the nodes file, for
+	// example after peers were added. Moving the lock needs the current
+	// configuration to be deleted first.
+	if cur, err := getCTDBConfig(); err == nil {
+		if cur.Volume != req.Volume || cur.MountPath != path.Clean(req.MountPath) {
+			restutils.SendHTTPError(ctx, w, http.StatusConflict,
+				fmt.Sprintf("CTDB lock volume %s is already configured", cur.Volume))
+			return
+		}
+	} else if err != ErrCTDBNotConfigured {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	nodes, err := peer.GetPeerIDs()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	mountPath := path.Clean(req.MountPath)
+	cfg := sambaapi.CTDBConfig{
+		Volume:       req.Volume,
+		MountPath:    mountPath,
+		RecoveryLock: path.Join(mountPath, recoveryLockFile),
+	}
+
+	txn.Nodes = nodes
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc:   "samba-ctdb.Setup",
+			UndoFunc: "samba-ctdb.Teardown",
+			Nodes:    nodes,
+		},
+		{
+			DoFunc: "samba-ctdb.Store",
+			Nodes:  []uuid.UUID{gdctx.MyUUID},
+		},
+	}
+	if err := txn.Ctx.Set("ctdb", &cfg); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if err := txn.Do(); err != nil {
+		logger.WithError(err).WithField("volume", req.Volume).Error("failed to set up CTDB lock volume")
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, cfg)
+}
+
+func ctdbDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+
+	txn, err := transaction.NewTxnWithLocks(ctx, ctdbLock)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	defer txn.Done()
+
+	cfg, err := getCTDBConfig()
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err == ErrCTDBNotConfigured {
+			status = http.StatusNotFound
+		}
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	nodes, err := peer.GetPeerIDs()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	txn.Nodes = nodes
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc:   "samba-ctdb.Teardown",
+			UndoFunc: "samba-ctdb.Setup",
+			Nodes:    nodes,
+		},
+		{
+			DoFunc: "samba-ctdb.DeleteStore",
+			Nodes:  []uuid.UUID{gdctx.MyUUID},
+		},
+	}
+	if err := txn.Ctx.Set("ctdb", cfg); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if err := txn.Do(); err != nil {
+		logger.WithError(err).WithField("volume", cfg.Volume).Error("failed to remove CTDB lock volume")
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusNoContent, nil)
+}
+
+func statusHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+
+	nodes, err := peer.GetPeerIDs()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	txn := transaction.NewTxn(ctx)
+	defer txn.Done()
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc: "samba-status.Commit",
+			Nodes:  nodes,
+		},
+	}
+
+	// Some nodes may not be up, which is okay.
+	txn.DontCheckAlive = true
+	txn.DisableRollback = true
+
+	if err := txn.Do(); err != nil {
+		logger.WithError(err).Error("failed to get samba status")
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	var resp sambaapi.StatusResp
+	for _, node := range nodes {
+		var status sambaapi.NodeStatus
+		if err := txn.Ctx.GetNodeResult(node, statusTxnKey, &status); err != nil {
+			resp.Unreachable = append(resp.Unreachable, node)
+			continue
+		}
+		resp.Nodes = append(resp.Nodes, status)
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, &resp)
+}
@@ -0,0 +1,18 @@
+package samba
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateShareName(t *testing.T) {
+	for _, name := range []string{"share1", "my share", "data-2018"} {
+		assert.NoError(t, validateShareName(name), name)
+	}
+	for _, name := range []string{"", strings.Repeat("a", maxShareNameLen+1), "a/b", "a[b]", "a;b",
+		"global", "Homes", "PRINTERS", automaticSharePrefix + "vol1"} {
+		assert.Error(t, validateShareName(name), name)
+	}
+}
@@ -0,0 +1,151 @@
+package samba
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	sambaapi "github.com/gluster/glusterd2/plugins/samba/api"
+
+	"github.com/coreos/etcd/clientv3"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	sharesPrefix = "samba/shares/"
+	ctdbKey      = "samba/ctdb"
+
+	// optSMB is the volume option exporting the whole volume as a share
+	optSMB = "user.smb"
+	// automaticSharePrefix prefixes the name of the volume in the name of
+	// the share exported because of user.smb
+	automaticSharePrefix = "gluster-"
+)
+
+// getShare returns the share with the given name. Automatic shares are not
+// stored and are not returned.
+func getShare(name string) (*sambaapi.Share, error) {
+	resp, err := store.Get(context.TODO(), sharesPrefix+name)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Count != 1 {
+		return nil, ErrShareNotFound
+	}
+
+	var share sambaapi.Share
+	if err := json.Unmarshal(resp.Kvs[0].Value, &share); err != nil {
+		return nil, err
+	}
+	return &share, nil
+}
+
+// getShares returns all the stored shares
+func getShares() ([]sambaapi.Share, error) {
+	resp, err := store.Get(context.TODO(), sharesPrefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	shares := make([]sambaapi.Share, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var share sambaapi.Share
+		if err := json.Unmarshal(kv.Value, &share); err != nil {
+			log.WithError(err).WithField("share", string(kv.Key)).Error("Failed to unmarshal share")
+			continue
+		}
+		shares = append(shares, share)
+	}
+	return shares, nil
+}
+
+func addShare(share *sambaapi.Share) error {
+	data, err := json.Marshal(share)
+	if err != nil {
+		return err
+	}
+	_, err = store.Put(context.TODO(), sharesPrefix+share.Name, string(data))
+	return err
+}
+
+func deleteShare(name string) error {
+	_, err := store.Delete(context.TODO(), sharesPrefix+name)
+	return err
+}
+
+// smbEnabled returns true if the volume is exported by the user.smb option
+func smbEnabled(v *volume.Volinfo) bool {
+	return v.State == volume.VolStarted && v.Options[optSMB] == "enable"
+}
+
+// automaticShare returns the share exporting the whole volume because of the
+// user.smb option
+func automaticShare(v *volume.Volinfo) sambaapi.Share {
+	return sambaapi.Share{
+		Name:      automaticSharePrefix + v.Name,
+		Volume:    v.Name,
+		Path:      "/",
+		Automatic: true,
+	}
+}
+
+// allShares returns the stored shares along with the automatic shares of the
+// volumes. override, if not nil, replaces the stored volume with the same
+// name, so that option actors can act upon volume states which are not in
+// the store yet.
+func allShares(override *volume.Volinfo) ([]sambaapi.Share, error) {
+	shares, err := getShares()
+	if err != nil {
+		return nil, err
+	}
+
+	volumes, err := volume.GetVolumes(context.TODO())
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range volumes {
+		if override != nil && v.Name == override.Name {
+			v = override
+		}
+		if smbEnabled(v) {
+			shares = append(shares, automaticShare(v))
+		}
+	}
+
+	sort.Slice(shares, func(i, j int) bool {
+		return shares[i].Name < shares[j].Name
+	})
+	return shares, nil
+}
+
+func getCTDBConfig() (*sambaapi.CTDBConfig, error) {
+	resp, err := store.Get(context.TODO(), ctdbKey)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Count != 1 {
+		return nil, ErrCTDBNotConfigured
+	}
+
+	var cfg sambaapi.CTDBConfig
+	if err := json.Unmarshal(resp.Kvs[0].Value, &cfg); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+func putCTDBConfig(cfg *sambaapi.CTDBConfig) error {
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+	_, err = store.Put(context.TODO(), ctdbKey, string(data))
+	return err
+}
+
+func deleteCTDBConfig() error {
+	_, err := store.Delete(context.TODO(), ctdbKey)
+	return err
+}
@@ -0,0 +1,25 @@
+package samba
+
+import (
+	"testing"
+
+	"github.com/gluster/glusterd2/glusterd2/volume"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAutomaticShare(t *testing.T) {
+	v := &volume.Volinfo{Name: "vol1", State: volume.VolStarted, Options: map[string]string{optSMB: "enable"}}
+	assert.True(t, smbEnabled(v))
+	share := automaticShare(v)
+	assert.Equal(t, automaticSharePrefix+"vol1", share.Name)
+	assert.Equal(t, "/", share.Path)
+	assert.True(t, share.Automatic)
+	// The automatic shares cannot be created by the users
+	assert.Error(t, validateShareName(share.Name))
+
+	v.State = volume.VolStopped
+	assert.False(t, smbEnabled(v))
+	v = &volume.Volinfo{Name: "vol1", State: volume.VolStarted, Options: map[string]string{}}
+	assert.False(t, smbEnabled(v))
+}
@@ -0,0 +1,127 @@
+package samba
+
+import (
+	"sort"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	sambaapi "github.com/gluster/glusterd2/plugins/samba/api"
+)
+
+const statusTxnKey = "samba-status"
+
+// txnShareCreate adds the share in the transaction context to the smb.conf
+// fragment of this node. The share is stored only after all the peers have
+// configured it.
+func txnShareCreate(c transaction.TxnCtx) error {
+	var share sambaapi.Share
+	if err := c.Get("share", &share); err != nil {
+		return err
+	}
+
+	shares, err := allShares(nil)
+	if err == nil {
+		shares = append(shares, share)
+		sort.Slice(shares, func(i, j int) bool {
+			return shares[i].Name < shares[j].Name
+		})
+	}
+	return updateConf(shares, err, c.Logger())
+}
+
+// txnShareDelete removes the share in the transaction context from the
+// smb.conf fragment of this node
+func txnShareDelete(c transaction.TxnCtx) error {
+	var share sambaapi.Share
+	if err := c.Get("share", &share); err != nil {
+		return err
+	}
+
+	shares, err := allShares(nil)
+	if err == nil {
+		for i := range shares {
+			if shares[i].Name == share.Name {
+				shares = append(shares[:i], shares[i+1:]...)
+				break
+			}
+		}
+	}
+	return updateConf(shares, err, c.Logger())
+}
+
+// txnRefreshConf regenerates the smb.conf fragment of this node from the
+// store. It undoes txnShareCreate and txnShareDelete, as the store is updated
+// last.
+func txnRefreshConf(c transaction.TxnCtx) error {
+	return refreshConf(nil, c.Logger())
+}
+
+func txnShareStore(c transaction.TxnCtx) error {
+	var share sambaapi.Share
+	if err := c.Get("share", &share); err != nil {
+		return err
+	}
+	return addShare(&share)
+}
+
+func txnShareDeleteStore(c transaction.TxnCtx) error {
+	var share sambaapi.Share
+	if err := c.Get("share", &share); err != nil {
+		return err
+	}
+	return deleteShare(share.Name)
+}
+
+// txnCTDBSetup mounts the lock volume and writes the CTDB configuration on
+// this node
+func txnCTDBSetup(c transaction.TxnCtx) error {
+	var cfg sambaapi.CTDBConfig
+	if err := c.Get("ctdb", &cfg); err != nil {
+		return err
+	}
+	return setupCTDB(&cfg, c.Logger())
+}
+
+// txnCTDBTeardown unmounts the lock volume and removes the CTDB
+// configuration on this node
+func txnCTDBTeardown(c transaction.TxnCtx) error {
+	var cfg sambaapi.CTDBConfig
+	if err := c.Get("ctdb", &cfg); err != nil {
+		return err
+	}
+	return teardownCTDB(&cfg, c.Logger())
+}
+
+func txnCTDBStore(c transaction.TxnCtx) error {
+	var cfg sambaapi.CTDBConfig
+	if err := c.Get("ctdb", &cfg); err != nil {
+		return err
+	}
+	return putCTDBConfig(&cfg)
+}
+
+func txnCTDBDeleteStore(c transaction.TxnCtx) error {
+	return deleteCTDBConfig()
+}
+
+// txnStatus collects the Samba health of this node. The result is stored in
+// the transaction context to be aggregated by the node which initiated it.
+func txnStatus(c transaction.TxnCtx) error {
+	status := sambaapi.NodeStatus{
+		PeerID:      gdctx.MyUUID,
+		SmbdRunning: smbdRunning(),
+		CTDBRunning: ctdbdRunning(),
+	}
+
+	shares, err := confStatus()
+	status.Shares = shares
+	if err != nil {
+		status.ConfigError = err.Error()
+	}
+
+	if cfg, err := getCTDBConfig(); err == nil {
+		status.LockMounted = lockVolumeMounted(cfg)
+	}
+
+	return c.SetNodeResult(gdctx.MyUUID, statusTxnKey, &status)
+}
@@ -0,0 +1,25 @@
+package shard
+
+import (
+	"github.com/gluster/glusterd2/glusterd2/options"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/glusterd2/xlator"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+)
+
+// validateOptions refuses disabling sharding on a volume which may hold
+// sharded files, whose shards past the first block would no longer be
+// reachable by the clients
+func validateOptions(v *volume.Volinfo, key, value string) error {
+	if key != "shard" || !v.HasShardedData() {
+		return nil
+	}
+	if on, err := options.StringToBoolean(value); err != nil || !on {
+		return gderrors.ErrShardedData
+	}
+	return nil
+}
+
+func init() {
+	xlator.RegisterValidationFunc("shard", validateOptions)
+}
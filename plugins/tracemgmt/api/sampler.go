@@ -0,0 +1,30 @@
+package api
+
+// JaegerSamplerType to indicate different Jaeger sampler type
+type JaegerSamplerType uint8
+
+// Sampler types in Jaeger
+const (
+	// 'Never' - Don't sample any trace
+	Never JaegerSamplerType = iota
+	// 'Always' - Sample every trace
+	Always
+	// 'Probabilistic' - Sample based on sample fraction
+	Probabilistic
+)
+
+// SamplerTypeToString returns string representation of sampler type.
+func SamplerTypeToString(samplerType JaegerSamplerType) string {
+	sampler := ""
+	switch samplerType {
+	case Never:
+		sampler = "Never"
+	case Always:
+		sampler = "Always"
+	case Probabilistic:
+		sampler = "Probabilistic"
+	default:
+		sampler = "Unknown"
+	}
+	return sampler
+}
@@ -0,0 +1,51 @@
+package worm
+
+import (
+	"strconv"
+
+	"github.com/gluster/glusterd2/glusterd2/options"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/glusterd2/xlator"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+)
+
+// retentionPeriod returns the default retention period of the volume, in
+// seconds
+func retentionPeriod(v *volume.Volinfo) int {
+	value, ok := v.Options[volume.WORMRetentionPeriodKey]
+	if !ok {
+		if o, err := xlator.FindOption(volume.WORMRetentionPeriodKey); err == nil {
+			value = o.DefaultValue
+		}
+	}
+	period, _ := strconv.Atoi(value)
+	return period
+}
+
+// validateOptions refuses the options lifting or shortening the retention of
+// the files of a volume in enterprise retention mode
+func validateOptions(v *volume.Volinfo, key, value string) error {
+	if !v.IsEnterpriseRetained() {
+		return nil
+	}
+
+	switch key {
+	case "worm-file-level":
+		if on, err := options.StringToBoolean(value); err != nil || !on {
+			return gderrors.ErrWORMEnterpriseRetained
+		}
+	case "retention-mode":
+		if value != "enterprise" {
+			return gderrors.ErrWORMEnterpriseRetained
+		}
+	case "default-retention-period":
+		if period, err := strconv.Atoi(value); err != nil || period < retentionPeriod(v) {
+			return gderrors.ErrWORMEnterpriseRetained
+		}
+	}
+	return nil
+}
+
+func init() {
+	xlator.RegisterValidationFunc("worm", validateOptions)
+}
@@ -25,6 +25,10 @@ mkdir -p "$OUTDIR"
 REPO_PATH="github.com/gluster/glusterd2"
 GOPKG="${REPO_PATH}/${PACKAGE}"
 BIN=$(basename "$PACKAGE")
+OUTBIN=$BIN
+if [ "$GOOS" == "windows" ]; then
+    OUTBIN+=".exe"
+fi
 
 VERSION=$("$(dirname "$0")/pkg-version" --full)
 [[ -f VERSION ]] && source VERSION
@@ -59,6 +63,6 @@ fi
 
 echo "Building $BIN $VERSION"
 
-go build $INSTALLFLAG -ldflags "${LDFLAGS}" -o "$OUTDIR/$BIN" -tags "$GOBUILD_TAGS" "$GOPKG" || exit 1
+go build $INSTALLFLAG -ldflags "${LDFLAGS}" -o "$OUTDIR/$OUTBIN" -tags "$GOBUILD_TAGS" "$GOPKG" || exit 1
 
-echo "Built $PACKAGE $VERSION at $OUTDIR/$BIN"
+echo "Built $PACKAGE $VERSION at $OUTDIR/$OUTBIN"
@@ -0,0 +1,14 @@
+#!/bin/bash
+
+# Tests the generator and the runtime of the Python client
+
+if ! command -v python3 >/dev/null 2>&1; then
+	echo "warning: could not find python3 ... will skip python client tests" >&2
+	exit 0
+fi
+
+SCRIPT_DIR="$(cd "$(dirname "${0}")" && pwd)"
+BASE_DIR="$(cd "${SCRIPT_DIR}/.." && pwd)"
+
+cd "${BASE_DIR}/extras/python-client" || exit 2
+exec python3 -m unittest discover -s tests
@@ -0,0 +1,11 @@
+#!/bin/bash
+
+# Checks that glustercli and the Go client build on the platforms the
+# administrators manage clusters from, glusterd2 itself being Linux only
+
+CLIPACKAGES="./glustercli/... ./pkg/restclient/..."
+
+for os in darwin windows; do
+	# shellcheck disable=SC2086
+	GOOS=${os} go build ${CLIPACKAGES} || exit 1
+done
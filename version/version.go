@@ -12,8 +12,10 @@ var (
 	expVer = expvar.NewString("version")
 )
 
-// MaxOpVersion and APIVersion supported
+// MaxOpVersion and APIVersion supported. MinOpVersion is the op-version
+// supported by all the releases of glusterd2.
 const (
+	MinOpVersion = 50000
 	MaxOpVersion = 50000
 	APIVersion   = 1
 )